@@ -10,32 +10,49 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/audit"
+	"github.com/ethersphere/bee/pkg/feeds/validation"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/node"
+	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology/driver"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 const (
 	optionNameDataDir                    = "data-dir"
+	optionNameProfile                    = "profile"
 	optionNameCacheCapacity              = "cache-capacity"
+	optionNameCacheMinFreeDisk           = "cache-min-free-disk"
+	optionNameUploadCapacity             = "upload-capacity"
+	optionNameColdDataDir                = "cold-data-dir"
+	optionNameColdAge                    = "cold-age"
+	optionNameDBBackend                  = "db-backend"
 	optionNameDBOpenFilesLimit           = "db-open-files-limit"
 	optionNameDBBlockCacheCapacity       = "db-block-cache-capacity"
 	optionNameDBWriteBufferSize          = "db-write-buffer-size"
 	optionNameDBDisableSeeksCompaction   = "db-disable-seeks-compaction"
+	optionNameDBGroupCommitLatency       = "db-group-commit-latency"
 	optionNamePassword                   = "password"
 	optionNamePasswordFile               = "password-file"
 	optionNameAPIAddr                    = "api-addr"
 	optionNameP2PAddr                    = "p2p-addr"
 	optionNameNATAddr                    = "nat-addr"
 	optionNameP2PWSEnable                = "p2p-ws-enable"
+	optionNameP2PRelayEnable             = "p2p-relay-enable"
+	optionNameP2PRelayServiceEnable      = "p2p-relay-service-enable"
+	optionNameP2PStaticRelays            = "p2p-static-relays"
 	optionNameDebugAPIEnable             = "debug-api-enable"
 	optionNameDebugAPIAddr               = "debug-api-addr"
 	optionNameBootnodes                  = "bootnode"
+	optionNameBootnodeDomains            = "bootnode-dns"
 	optionNameNetworkID                  = "network-id"
 	optionWelcomeMessage                 = "welcome-message"
 	optionCORSAllowedOrigins             = "cors-allowed-origins"
@@ -45,6 +62,7 @@ const (
 	optionNameTracingPort                = "tracing-port"
 	optionNameTracingServiceName         = "tracing-service-name"
 	optionNameVerbosity                  = "verbosity"
+	optionNameLogSinks                   = "log-sinks"
 	optionNamePaymentThreshold           = "payment-threshold"
 	optionNamePaymentTolerance           = "payment-tolerance-percent"
 	optionNamePaymentEarly               = "payment-early-percent"
@@ -61,6 +79,11 @@ const (
 	optionNameSwapEnable                 = "swap-enable"
 	optionNameChequebookEnable           = "chequebook-enable"
 	optionNameSwapDeploymentGasPrice     = "swap-deployment-gas-price"
+	optionNameSwapBounceThreshold        = "swap-bounce-threshold"
+	optionNameSwapBounceBlocklist        = "swap-bounce-blocklist-duration"
+	optionNameWithdrawalAddress          = "withdrawal-address"
+	optionNameWithdrawalThreshold        = "withdrawal-threshold"
+	optionNameWithdrawalInterval         = "withdrawal-interval"
 	optionNameFullNode                   = "full-node"
 	optionNamePostageContractAddress     = "postage-stamp-address"
 	optionNamePostageContractStartBlock  = "postage-stamp-start-block"
@@ -83,6 +106,47 @@ const (
 	optionNameAdminPasswordHash          = "admin-password"
 	optionNameUsePostageSnapshot         = "use-postage-snapshot"
 	optionNameStorageIncentivesEnable    = "storage-incentives-enable"
+	optionNameMaxCollectionFileSize      = "max-collection-file-size"
+	optionNameAuditLogFile               = "audit-log-file"
+	optionNameAuditLogMaxSize            = "audit-log-max-size"
+	optionNameAuditLogMaxBackups         = "audit-log-max-backups"
+	optionNamePushSyncTrace              = "pushsync-trace"
+	optionNamePushSyncBatchWindow        = "pushsync-batch-window"
+	optionNameDynamicPricing             = "dynamic-pricing"
+	optionNameCompressibleContentTypes   = "api-compressible-content-types"
+	optionNameTenantsFile                = "api-tenants-file"
+	optionNameWebDAV                     = "api-webdav"
+	optionNameWebDAVPostageBatch         = "api-webdav-postage-batch"
+	optionNameS3Addr                     = "s3-addr"
+	optionNameS3PostageBatch             = "s3-postage-batch"
+	optionNamePssGRPCAddr                = "pss-grpc-addr"
+	optionNameAccountingSnapshotInterval = "accounting-snapshot-interval"
+	optionNameIPFSGateway                = "ipfs-gateway"
+	optionNameContentStats               = "content-stats"
+	optionNameGateway                    = "gateway"
+	optionNameSourceURLMaxSize           = "source-url-max-size"
+	optionNameSourceURLSchemes           = "source-url-schemes"
+	optionNameSourceURLAllowPrivate      = "source-url-allow-private"
+	optionNameTopologyDriver             = "topology-driver"
+	optionNameWebhookURLs                = "webhook-url"
+	optionNameWebhookSecret              = "webhook-secret"
+	optionNameWebhookEvents              = "webhook-events"
+	optionNameWebhookChequebookMin       = "webhook-chequebook-min-balance"
+	optionNamePyroscopeAddr              = "pyroscope-addr"
+	optionNamePyroscopeAppName           = "pyroscope-app-name"
+	optionNameHandoffAllowedPeers        = "handoff-allowed-peers"
+	optionNameFeedValidationHooks        = "feed-validation-hook"
+	optionNameFeedValidationTimeout      = "feed-validation-timeout"
+	optionNameChain                      = "chain"
+	optionNameStaticBatchesFile          = "static-batches-file"
+	optionNameStaticBatchesSigner        = "static-batches-signer"
+	optionNamePostageExpiryGracePeriod   = "postage-expiry-grace-period"
+	optionNamePostageExpiryWarning       = "postage-expiry-warning"
+	optionNameKeystore                   = "keystore"
+	optionNameKeystoreKMSURL             = "keystore-kms-url"
+	optionNameKeystoreKMSKey             = "keystore-kms-key"
+	optionNameKeystoreKMSToken           = "keystore-kms-token"
+	optionNameStateStoreEncryption       = "statestore-encryption"
 )
 
 // nolint:gochecknoinits
@@ -152,8 +216,15 @@ func newCommand(opts ...option) (c *command, err error) {
 		return nil, err
 	}
 
+	if err := c.initMigrateCmd(); err != nil {
+		return nil, err
+	}
+
 	c.initVersionCmd()
 	c.initDBCmd()
+	c.initMountCmd()
+	c.initSelfTestCmd()
+	c.initVerifyArchiveCmd()
 
 	if err := c.initConfigurateOptionsCmd(); err != nil {
 		return nil, err
@@ -223,6 +294,47 @@ func (c *command) initConfig() (err error) {
 	return nil
 }
 
+// profileNameRegexp matches the valid names of the node profiles.
+var profileNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// profile returns the name of the selected node profile,
+// which is empty for the default node identity.
+func (c *command) profile() (string, error) {
+	profile := c.config.GetString(optionNameProfile)
+	if profile != "" && !profileNameRegexp.MatchString(profile) {
+		return "", fmt.Errorf("invalid profile name %q", profile)
+	}
+	return profile, nil
+}
+
+// profileDir returns the directory of the data of the selected profile
+// under the given data directory, which is isolated from the data of the
+// other profiles.
+func (c *command) profileDir(dir string) (string, error) {
+	profile, err := c.profile()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" || dir == "" {
+		return dir, nil
+	}
+	return filepath.Join(dir, "profiles", profile), nil
+}
+
+// dataDir returns the data directory of the selected profile.
+func (c *command) dataDir() (string, error) {
+	return c.profileDir(c.config.GetString(optionNameDataDir))
+}
+
+// profileKeyName returns the name of the key of the profile in the
+// keystore, the keys of the profiles are kept in their subdirectories.
+func profileKeyName(profile, name string) string {
+	if profile == "" {
+		return name
+	}
+	return profile + "/" + name
+}
+
 func (c *command) setHomeDir() (err error) {
 	if c.homeDir != "" {
 		return
@@ -237,18 +349,34 @@ func (c *command) setHomeDir() (err error) {
 
 func (c *command) setAllFlags(cmd *cobra.Command) {
 	cmd.Flags().String(optionNameDataDir, filepath.Join(c.homeDir, ".bee"), "data directory")
+	cmd.Flags().String(optionNameProfile, "", "name of the node identity, its keys are kept in the keystore of the data directory and its data in the profiles/<name> subdirectory")
 	cmd.Flags().Uint64(optionNameCacheCapacity, 1000000, fmt.Sprintf("cache capacity in chunks, multiply by %d to get approximate capacity in bytes", swarm.ChunkSize))
+	cmd.Flags().Float64(optionNameCacheMinFreeDisk, 0, "ratio of the disk space kept free by lowering the cache capacity, e.g. 0.1 keeps at least 10% free, the capacity is fixed if zero")
+	cmd.Flags().Uint64(optionNameUploadCapacity, 0, "maximal number of the uploaded chunks which are not synced yet, the uploads over it are refused, not limited if zero")
+	cmd.Flags().String(optionNameColdDataDir, "", "secondary data directory where the cold cache chunks are moved, disabled if empty")
+	cmd.Flags().Duration(optionNameColdAge, 24*time.Hour, "time after which the unaccessed cache chunk is moved to the cold data directory")
+	cmd.Flags().String(optionNameDBBackend, shed.DefaultBackend, fmt.Sprintf("storage backend of the localstore indexes, one of the compiled in: %s", strings.Join(shed.Backends(), ", ")))
 	cmd.Flags().Uint64(optionNameDBOpenFilesLimit, 200, "number of open files allowed by database")
 	cmd.Flags().Uint64(optionNameDBBlockCacheCapacity, 32*1024*1024, "size of block cache of the database in bytes")
 	cmd.Flags().Uint64(optionNameDBWriteBufferSize, 32*1024*1024, "size of the database write buffer in bytes")
 	cmd.Flags().Bool(optionNameDBDisableSeeksCompaction, false, "disables db compactions triggered by seeks")
+	cmd.Flags().Duration(optionNameDBGroupCommitLatency, 0, "longest time a database write waits to be synced together with the concurrent writes, disabled if zero")
 	cmd.Flags().String(optionNamePassword, "", "password for decrypting keys")
 	cmd.Flags().String(optionNamePasswordFile, "", "path to a file that contains password for decrypting keys")
+	cmd.Flags().String(optionNameKeystore, keystoreFile, fmt.Sprintf("keystore of the keys: %q in the data directory, %q of the operating system or %q with the keys in the data directory encrypted by the key management service", keystoreFile, keystoreKeyring, keystoreKMS))
+	cmd.Flags().String(optionNameKeystoreKMSURL, "", "address of the HashiCorp Vault whose transit secrets engine encrypts the keys of the kms keystore")
+	cmd.Flags().String(optionNameKeystoreKMSKey, "", "name of the transit encryption key of the kms keystore")
+	cmd.Flags().String(optionNameKeystoreKMSToken, "", "token of the HashiCorp Vault of the kms keystore")
+	cmd.Flags().Bool(optionNameStateStoreEncryption, false, "encrypt the payment channel state in the statestore with a key from the keystore, can not be disabled once enabled")
 	cmd.Flags().String(optionNameAPIAddr, ":1633", "HTTP API listen address")
 	cmd.Flags().String(optionNameP2PAddr, ":1634", "P2P listen address")
 	cmd.Flags().String(optionNameNATAddr, "", "NAT exposed address")
 	cmd.Flags().Bool(optionNameP2PWSEnable, false, "enable P2P WebSocket transport")
+	cmd.Flags().Bool(optionNameP2PRelayEnable, false, "advertise circuit relay addresses and punch holes when the node is not directly reachable")
+	cmd.Flags().Bool(optionNameP2PRelayServiceEnable, false, "relay the connections of the nodes which are not directly reachable")
+	cmd.Flags().StringSlice(optionNameP2PStaticRelays, nil, "underlays of the circuit relays used instead of the connected peers")
 	cmd.Flags().StringSlice(optionNameBootnodes, []string{""}, "initial nodes to connect to")
+	cmd.Flags().StringSlice(optionNameBootnodeDomains, []string{}, "domains whose TXT records list the underlay addresses of the initial nodes to connect to")
 	cmd.Flags().Bool(optionNameDebugAPIEnable, false, "enable debug HTTP API")
 	cmd.Flags().String(optionNameDebugAPIAddr, ":1635", "debug HTTP API listen address")
 	cmd.Flags().Uint64(optionNameNetworkID, 1, "ID of the Swarm network")
@@ -259,6 +387,7 @@ func (c *command) setAllFlags(cmd *cobra.Command) {
 	cmd.Flags().String(optionNameTracingPort, "", "port to send tracing data")
 	cmd.Flags().String(optionNameTracingServiceName, "bee", "service name identifier for tracing")
 	cmd.Flags().String(optionNameVerbosity, "info", "log verbosity level 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=trace")
+	cmd.Flags().StringSlice(optionNameLogSinks, []string{}, "additional log sinks, can be repeated, format file:///path[?max-size=bytes&max-backups=n], syslog://[host:port][?tag=bee], syslog+tcp://host:port or tcp://host:port, with optional format=json|text")
 	cmd.Flags().String(optionWelcomeMessage, "", "send a welcome message string during handshakes")
 	cmd.Flags().String(optionNamePaymentThreshold, "13500000", "threshold in BZZ where you expect to get paid from your peers")
 	cmd.Flags().Int64(optionNamePaymentTolerance, 25, "excess debt above payment threshold in percentages where you disconnect from your peer")
@@ -283,6 +412,11 @@ func (c *command) setAllFlags(cmd *cobra.Command) {
 	cmd.Flags().String(optionNameStakingAddress, "", "staking contract address")
 	cmd.Flags().Uint64(optionNameBlockTime, 15, "chain block time")
 	cmd.Flags().String(optionNameSwapDeploymentGasPrice, "", "gas price in wei to use for deployment and funding")
+	cmd.Flags().Int(optionNameSwapBounceThreshold, 3, "number of bounced cheques after which a peer is blocklisted, 0 disables blocklisting")
+	cmd.Flags().Duration(optionNameSwapBounceBlocklist, 24*time.Hour, "duration for which peers with bounced cheques are blocklisted")
+	cmd.Flags().String(optionNameWithdrawalAddress, "", "address the chequebook balance above the withdrawal threshold is periodically transferred to, empty disables the withdrawals")
+	cmd.Flags().String(optionNameWithdrawalThreshold, "0", "chequebook balance kept when withdrawing to the withdrawal address")
+	cmd.Flags().Duration(optionNameWithdrawalInterval, 24*time.Hour, "interval between the withdrawals to the withdrawal address")
 	cmd.Flags().Duration(optionWarmUpTime, time.Minute*5, "time to warmup the node before some major protocols can be kicked off.")
 	cmd.Flags().Bool(optionNameMainNet, true, "triggers connect to main net bootnodes.")
 	cmd.Flags().Bool(optionNameRetrievalCaching, true, "enable forwarded content caching")
@@ -296,9 +430,45 @@ func (c *command) setAllFlags(cmd *cobra.Command) {
 	cmd.Flags().String(optionNameAdminPasswordHash, "", "bcrypt hash of the admin password to get the security token")
 	cmd.Flags().Bool(optionNameUsePostageSnapshot, false, "bootstrap node using postage snapshot from the network")
 	cmd.Flags().Bool(optionNameStorageIncentivesEnable, true, "enable storage incentives feature")
+	cmd.Flags().Int64(optionNameMaxCollectionFileSize, api.DefaultMaxDirUploadFileSize, "maximum size in bytes of a single file in a collection upload")
+	cmd.Flags().String(optionNameAuditLogFile, "", "file to log state-changing api calls to, disabled if empty")
+	cmd.Flags().Int64(optionNameAuditLogMaxSize, audit.DefaultMaxSize, "size in bytes after which the audit log file is rotated")
+	cmd.Flags().Int(optionNameAuditLogMaxBackups, audit.DefaultMaxBackups, "number of rotated audit log files to keep")
+	cmd.Flags().Bool(optionNamePushSyncTrace, false, "request the forwarding path in push sync receipts of uploaded chunks, for debugging")
+	cmd.Flags().Duration(optionNamePushSyncBatchWindow, 10*time.Millisecond, "time within which the push sync deliveries to the same peer are batched in a stream, not batched if zero")
+	cmd.Flags().Bool(optionNameDynamicPricing, false, "raise the chunk price with the disk usage and the reserve utilization, announcing it to the peers")
+	cmd.Flags().StringSlice(optionNameCompressibleContentTypes, api.DefaultCompressibleContentTypes, "content types compressed on download with the encoding accepted by the client, type/* matches all subtypes, all downloads are gzip compressed if empty")
+	cmd.Flags().String(optionNameTenantsFile, "", "JSON file with the tenants sharing the restricted api, with their batches and pin quotas")
+	cmd.Flags().Bool(optionNameWebDAV, false, "serve the manifests as read-only WebDAV file systems on /webdav")
+	cmd.Flags().String(optionNameWebDAVPostageBatch, "", "postage batch stamping the changes made over WebDAV to the feed manifests owned by the node, which are published as feed updates")
+	cmd.Flags().String(optionNameS3Addr, "", "S3 compatible API listen address, the requests are authenticated with the security token only in the restricted mode so otherwise it should be reachable only by trusted clients")
+	cmd.Flags().String(optionNameS3PostageBatch, "", "postage batch stamping the objects stored over the S3 compatible API, the buckets are read-only if not set")
+	cmd.Flags().String(optionNamePssGRPCAddr, "", "pss gRPC stream listen address, the messages are sent and the topics subscribed to with the delivery statuses streamed back")
+	cmd.Flags().Duration(optionNameAccountingSnapshotInterval, 0, "interval of the snapshots of the accounting balances and the settlements of the peers exported for billing on the debug api, disabled if zero")
+	cmd.Flags().String(optionNameIPFSGateway, "", "URL of the IPFS HTTP gateway the content is imported from on /import/ipfs, the import is disabled if not set")
+	cmd.Flags().Bool(optionNameContentStats, false, "keep the aggregated access statistics of the downloaded content on /stats/content, without logging the individual requests")
+	cmd.Flags().String(optionNameTopologyDriver, driver.DefaultName, fmt.Sprintf("topology driver connecting to the peers, one of the compiled in: %s", strings.Join(driver.Names(), ", ")))
+	cmd.Flags().Bool(optionNameGateway, false, "serve a read-only public gateway: forbid the mutating API endpoints, rate limit the clients, apply the deny-list and cache the hash-addressed responses as immutable")
+	cmd.Flags().Int64(optionNameSourceURLMaxSize, 0, "maximal size in bytes of the resource fetched by the node from the source-url of the bzz upload, the upload from a source url is disabled if zero")
+	cmd.Flags().StringSlice(optionNameSourceURLSchemes, []string{"https"}, "URL schemes of the source-url of the bzz upload the node fetches")
+	cmd.Flags().Bool(optionNameSourceURLAllowPrivate, false, "allow the node to fetch the source-url of the bzz upload from the loopback and the private network addresses")
+	cmd.Flags().StringSlice(optionNameWebhookURLs, nil, "URLs of the webhooks the critical events are posted to, can be repeated")
+	cmd.Flags().String(optionNameWebhookSecret, "", "secret of the HMAC-SHA256 signatures of the webhook requests, the requests are not signed if empty")
+	cmd.Flags().StringSlice(optionNameWebhookEvents, nil, "events posted to the webhooks, one of chequebook_low_balance, batch_expiring, reserve_full, chain_disconnected and blocklisted_by_peers, all if empty")
+	cmd.Flags().String(optionNameWebhookChequebookMin, "", "available chequebook balance in PLUR below which the webhooks are notified, not watched if empty")
+	cmd.Flags().String(optionNamePyroscopeAddr, "", "URL of the pyroscope compatible server the CPU and heap profiles are continuously uploaded to, disabled if empty")
+	cmd.Flags().String(optionNamePyroscopeAppName, "bee", "application name of the profiles uploaded to the pyroscope server")
+	cmd.Flags().StringSlice(optionNameHandoffAllowedPeers, nil, "overlay addresses of the peers whose content handoffs are accepted and pinned, none if empty")
+	cmd.Flags().StringSlice(optionNameFeedValidationHooks, nil, "URLs of the hooks validating the uploaded feed updates before they are stamped, can be repeated")
+	cmd.Flags().Duration(optionNameFeedValidationTimeout, validation.DefaultTimeout, "time the feed validation hook is waited to respond")
+	cmd.Flags().String(optionNameChain, "on", "chain mode, on or off; with off the batches are loaded from the static batches file instead of the blockchain")
+	cmd.Flags().String(optionNameStaticBatchesFile, "", "JSON file with the table of the valid batches, used with the chain off")
+	cmd.Flags().String(optionNameStaticBatchesSigner, "", "ethereum address which must have signed the static batches file, the file may be unsigned if empty")
+	cmd.Flags().Duration(optionNamePostageExpiryGracePeriod, 0, "time for which the chunks of the expired batches stay readable before they are evicted")
+	cmd.Flags().Duration(optionNamePostageExpiryWarning, 24*time.Hour, "time before the expiry of a batch at which the expiry warning is published on the batch events")
 }
 
-func newLogger(cmd *cobra.Command, verbosity string) (log.Logger, error) {
+func newLogger(cmd *cobra.Command, verbosity string, opts ...log.Option) (log.Logger, error) {
 	var (
 		sink   = cmd.OutOrStdout()
 		vLevel = log.VerbosityNone
@@ -328,8 +498,10 @@ func newLogger(cmd *cobra.Command, verbosity string) (log.Logger, error) {
 
 	return log.NewLogger(
 		node.LoggerName,
-		log.WithSink(sink),
-		log.WithVerbosity(vLevel),
+		append([]log.Option{
+			log.WithSink(sink),
+			log.WithVerbosity(vLevel),
+		}, opts...)...,
 	).Register(), nil
 }
 
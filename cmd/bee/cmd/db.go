@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/localstore/indexexport"
 	"github.com/ethersphere/bee/pkg/statestore/leveldb"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +22,8 @@ import (
 const (
 	optionNameForgetOverlay = "forget-overlay"
 	optionNameForgetStamps  = "forget-stamps"
+	optionNameExportFormat  = "format"
+	optionNameDryRun        = "dry-run"
 )
 
 func (c *command) initDBCmd() {
@@ -30,9 +33,11 @@ func (c *command) initDBCmd() {
 	}
 
 	dbExportCmd(cmd)
+	dbExportIndexCmd(cmd)
 	dbImportCmd(cmd)
 	dbNukeCmd(cmd)
 	dbIndicesCmd(cmd)
+	dbMigrateCmd(cmd)
 
 	c.root.AddCommand(cmd)
 }
@@ -89,6 +94,61 @@ func dbIndicesCmd(cmd *cobra.Command) {
 	cmd.AddCommand(c)
 }
 
+func dbMigrateCmd(cmd *cobra.Command) {
+	c := &cobra.Command{
+		Use:   "migrate",
+		Short: "Run the pending DB schema migrations, or estimate their duration and space requirements with --dry-run",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			start := time.Now()
+			v, err := cmd.Flags().GetString(optionNameVerbosity)
+			if err != nil {
+				return fmt.Errorf("get verbosity: %w", err)
+			}
+			v = strings.ToLower(v)
+			logger, err := newLogger(cmd, v)
+			if err != nil {
+				return fmt.Errorf("new logger: %w", err)
+			}
+
+			dryRun, err := cmd.Flags().GetBool(optionNameDryRun)
+			if err != nil {
+				return fmt.Errorf("get dry-run: %w", err)
+			}
+
+			dataDir, err := cmd.Flags().GetString(optionNameDataDir)
+			if err != nil {
+				return fmt.Errorf("get data-dir: %w", err)
+			}
+			if dataDir == "" {
+				return errors.New("no data-dir provided")
+			}
+
+			logger.Info("migrating db with data-dir", "path", dataDir, "dry_run", dryRun)
+
+			path := filepath.Join(dataDir, "localstore")
+
+			storer, err := localstore.New(path, nil, nil, &localstore.Options{MigrationDryRun: dryRun}, logger)
+			if errors.Is(err, localstore.ErrMigrationDryRun) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("localstore: %w", err)
+			}
+			if err := storer.Close(); err != nil {
+				return fmt.Errorf("close localstore: %w", err)
+			}
+
+			logger.Info("db schema is up to date", "elapsed", time.Since(start))
+
+			return nil
+		},
+	}
+	c.Flags().String(optionNameDataDir, "", "data directory")
+	c.Flags().String(optionNameVerbosity, "info", "verbosity level")
+	c.Flags().Bool(optionNameDryRun, false, "only estimate the duration and space requirements of the pending migrations")
+	cmd.AddCommand(c)
+}
+
 func dbExportCmd(cmd *cobra.Command) {
 	c := &cobra.Command{
 		Use:   "export <filename>",
@@ -150,6 +210,66 @@ func dbExportCmd(cmd *cobra.Command) {
 	cmd.AddCommand(c)
 }
 
+func dbExportIndexCmd(cmd *cobra.Command) {
+	c := &cobra.Command{
+		Use:   "export-index <filename>",
+		Short: "Export the retrieval, pull, postage and pin indexes to a file for offline analytics",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if (len(args)) != 1 {
+				return cmd.Help()
+			}
+			v, err := cmd.Flags().GetString(optionNameVerbosity)
+			if err != nil {
+				return fmt.Errorf("get verbosity: %w", err)
+			}
+			v = strings.ToLower(v)
+			logger, err := newLogger(cmd, v)
+			if err != nil {
+				return fmt.Errorf("new logger: %w", err)
+			}
+
+			format, err := cmd.Flags().GetString(optionNameExportFormat)
+			if err != nil {
+				return fmt.Errorf("get format: %w", err)
+			}
+			if format != "sqlite" {
+				return fmt.Errorf("unsupported export format %q", format)
+			}
+
+			dataDir, err := cmd.Flags().GetString(optionNameDataDir)
+			if err != nil {
+				return fmt.Errorf("get data-dir: %w", err)
+			}
+			if dataDir == "" {
+				return errors.New("no data-dir provided")
+			}
+
+			logger.Info("starting index export with data-dir", "path", dataDir)
+
+			path := filepath.Join(dataDir, "localstore")
+
+			storer, err := localstore.New(path, nil, nil, nil, logger)
+			if err != nil {
+				return fmt.Errorf("localstore: %w", err)
+			}
+			defer storer.Close()
+
+			counts, err := indexexport.SQLite(storer, args[0])
+			if err != nil {
+				return fmt.Errorf("error exporting indexes: %w", err)
+			}
+
+			logger.Info("indexes exported successfully", "retrieval", counts.Retrieval, "pull", counts.Pull, "postage", counts.Postage, "pin", counts.Pin)
+
+			return nil
+		},
+	}
+	c.Flags().String(optionNameDataDir, "", "data directory")
+	c.Flags().String(optionNameVerbosity, "info", "verbosity level")
+	c.Flags().String(optionNameExportFormat, "sqlite", "export format, only sqlite is supported")
+	cmd.AddCommand(c)
+}
+
 func dbImportCmd(cmd *cobra.Command) {
 	c := &cobra.Command{
 		Use:   "import <filename>",
@@ -30,7 +30,10 @@ func (c *command) initDeployCmd() error {
 				return fmt.Errorf("new logger: %w", err)
 			}
 
-			dataDir := c.config.GetString(optionNameDataDir)
+			dataDir, err := c.dataDir()
+			if err != nil {
+				return err
+			}
 			factoryAddress := c.config.GetString(optionNameSwapFactoryAddress)
 			swapInitialDeposit := c.config.GetString(optionNameSwapInitialDeposit)
 			swapEndpoint := c.config.GetString(optionNameSwapEndpoint)
@@ -39,18 +42,18 @@ func (c *command) initDeployCmd() error {
 			if swapEndpoint != "" {
 				blockchainRpcEndpoint = swapEndpoint
 			}
-			stateStore, err := node.InitStateStore(logger, dataDir)
+			signerConfig, err := c.configureSigner(cmd, logger)
 			if err != nil {
 				return err
 			}
+			signer := signerConfig.signer
 
-			defer stateStore.Close()
-
-			signerConfig, err := c.configureSigner(cmd, logger)
+			stateStore, err := node.InitStateStore(logger, dataDir, signerConfig.stateStoreKey)
 			if err != nil {
 				return err
 			}
-			signer := signerConfig.signer
+
+			defer stateStore.Close()
 
 			ctx := cmd.Context()
 
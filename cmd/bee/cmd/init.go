@@ -26,13 +26,16 @@ func (c *command) initInitCmd() (err error) {
 			if err != nil {
 				return fmt.Errorf("new logger: %w", err)
 			}
-			_, err = c.configureSigner(cmd, logger)
+			signerConfig, err := c.configureSigner(cmd, logger)
 			if err != nil {
 				return err
 			}
 
-			dataDir := c.config.GetString(optionNameDataDir)
-			stateStore, err := node.InitStateStore(logger, dataDir)
+			dataDir, err := c.dataDir()
+			if err != nil {
+				return err
+			}
+			stateStore, err := node.InitStateStore(logger, dataDir, signerConfig.stateStoreKey)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,72 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+)
+
+func TestInitCmdProfiles(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+
+	initProfile := func(t *testing.T, profile string) error {
+		t.Helper()
+
+		args := []string{"init", "--data-dir", dataDir, "--password", "secret", "--verbosity", "0"}
+		if profile != "" {
+			args = append(args, "--profile", profile)
+		}
+		return newCommand(t,
+			cmd.WithArgs(args...),
+			cmd.WithOutput(io.Discard),
+			cmd.WithErrorOutput(io.Discard),
+		).Execute()
+	}
+
+	readKey := func(t *testing.T, path ...string) []byte {
+		t.Helper()
+
+		data, err := os.ReadFile(filepath.Join(append([]string{dataDir, "keys"}, path...)...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	for _, profile := range []string{"", "testnet", "mainnet"} {
+		if err := initProfile(t, profile); err != nil {
+			t.Fatalf("profile %q: %v", profile, err)
+		}
+	}
+
+	defaultKey := readKey(t, "swarm.key")
+	testnetKey := readKey(t, "testnet", "swarm.key")
+	mainnetKey := readKey(t, "mainnet", "swarm.key")
+	if bytes.Equal(defaultKey, testnetKey) || bytes.Equal(testnetKey, mainnetKey) {
+		t.Fatal("profiles share the same key")
+	}
+
+	for _, dir := range []string{
+		filepath.Join(dataDir, "statestore"),
+		filepath.Join(dataDir, "profiles", "testnet", "statestore"),
+		filepath.Join(dataDir, "profiles", "mainnet", "statestore"),
+	} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := initProfile(t, "../default"); err == nil {
+		t.Fatal("expected error for invalid profile name")
+	}
+}
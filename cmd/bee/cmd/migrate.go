@@ -0,0 +1,424 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/node"
+	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/spf13/cobra"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	ldbstorage "github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+const (
+	optionNameMigrateTo = "to"
+
+	// migratedFile is the name of the file written to the data directory
+	// which was migrated, the node refuses to start from it afterwards.
+	migratedFile = "MIGRATED"
+)
+
+// migratedNote is the content of the migratedFile.
+type migratedNote struct {
+	To   string    `json:"to"`
+	Time time.Time `json:"time"`
+}
+
+// checkMigrated returns the error if the data directory was migrated, so that
+// two nodes with the same keys do not run from the old and new directories.
+func checkMigrated(dataDir string) error {
+	if dataDir == "" {
+		return nil
+	}
+	b, err := os.ReadFile(filepath.Join(dataDir, migratedFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read migration note: %w", err)
+	}
+	var note migratedNote
+	if err := json.Unmarshal(b, &note); err != nil {
+		return fmt.Errorf("data directory %s was migrated", dataDir)
+	}
+	return fmt.Errorf("data directory %s was migrated to %s at %s, start the node with the new data directory", dataDir, note.To, note.Time.Format(time.RFC3339))
+}
+
+func (c *command) initMigrateCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the node to a new data directory",
+		Long: `Migrate the keys, the statestore and the localstore of the node, with all
+its profiles, to a new data directory. The node must be stopped. The keys are
+unlocked and the chequebook is checked to be issued by the node key on the
+chain, if the blockchain rpc endpoint is given. Every copied file is verified
+against its source, and the entries of the copied statestores are compared
+with the original ones, including the chequebook and stamp issuer state.
+The old data directory is kept but marked as migrated, and the node refuses
+to start from it, so that no two nodes run with the same keys.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) > 0 {
+				return cmd.Help()
+			}
+
+			v := strings.ToLower(c.config.GetString(optionNameVerbosity))
+			logger, err := newLogger(cmd, v)
+			if err != nil {
+				return fmt.Errorf("new logger: %w", err)
+			}
+
+			from, to, err := migrationDirs(c.config.GetString(optionNameDataDir), c.config.GetString(optionNameMigrateTo))
+			if err != nil {
+				return err
+			}
+			if err := checkMigrated(from); err != nil {
+				return err
+			}
+
+			if err := c.checkMigrationKeys(cmd, logger); err != nil {
+				return err
+			}
+
+			locks, err := lockStores(from)
+			if err != nil {
+				return fmt.Errorf("lock the data directory, the node must be stopped: %w", err)
+			}
+			files, err := copyDir(from, to)
+			if err != nil {
+				unlockStores(locks)
+				return fmt.Errorf("copy data directory: %w", err)
+			}
+			// the note is written before the stores are unlocked,
+			// so that the node is not started from the old directory
+			if err := writeMigratedNote(from, to); err != nil {
+				unlockStores(locks)
+				return err
+			}
+			unlockStores(locks)
+
+			if err := verifyStores(from, to); err != nil {
+				_ = os.Remove(filepath.Join(from, migratedFile))
+				return fmt.Errorf("verify migrated data directory %s, it is incomplete: %w", to, err)
+			}
+
+			logger.Info("data directory migrated", "from", from, "to", to, "files", files)
+			logger.Info("the old data directory can be removed once the node runs from the new one", "path", from)
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.config.BindPFlags(cmd.Flags())
+		},
+	}
+
+	c.setAllFlags(cmd)
+	cmd.Flags().String(optionNameMigrateTo, "", "new data directory, it must not exist or be empty")
+	c.root.AddCommand(cmd)
+	return nil
+}
+
+// migrationDirs returns the absolute paths of the migrated data
+// directory and the empty, or not existing, new data directory.
+func migrationDirs(from, to string) (string, string, error) {
+	if from == "" {
+		return "", "", errors.New("no data-dir provided")
+	}
+	if to == "" {
+		return "", "", errors.New("no new data directory provided")
+	}
+	from, err := filepath.Abs(from)
+	if err != nil {
+		return "", "", err
+	}
+	to, err = filepath.Abs(to)
+	if err != nil {
+		return "", "", err
+	}
+	if from == to || strings.HasPrefix(to, from+string(filepath.Separator)) || strings.HasPrefix(from, to+string(filepath.Separator)) {
+		return "", "", errors.New("the data directories must not contain each other")
+	}
+	if _, err := os.Stat(from); err != nil {
+		return "", "", fmt.Errorf("data directory: %w", err)
+	}
+	entries, err := os.ReadDir(to)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", "", fmt.Errorf("new data directory: %w", err)
+	}
+	if len(entries) > 0 {
+		return "", "", fmt.Errorf("new data directory %s is not empty", to)
+	}
+	return from, to, nil
+}
+
+// checkMigrationKeys unlocks the keys of the selected profile and, if the
+// chequebook is deployed and the blockchain rpc endpoint is given, checks
+// that the chequebook is issued by the node key, so that the migrated node
+// can sign its cheques.
+func (c *command) checkMigrationKeys(cmd *cobra.Command, logger log.Logger) error {
+	profile, err := c.profile()
+	if err != nil {
+		return err
+	}
+	ks, err := c.keystore(logger)
+	if err != nil {
+		return err
+	}
+	// the keys must not be created by the configuration of the signer
+	if !c.config.GetBool(optionNameClefSignerEnable) {
+		exists, err := ks.Exists(profileKeyName(profile, "swarm"))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("no node keys in the data directory")
+		}
+	}
+
+	signerConfig, err := c.configureSigner(cmd, logger)
+	if err != nil {
+		return err
+	}
+	dataDir, err := c.dataDir()
+	if err != nil {
+		return err
+	}
+	stateStore, err := node.InitStateStore(logger, dataDir, signerConfig.stateStoreKey)
+	if err != nil {
+		return fmt.Errorf("open statestore, the node must be stopped: %w", err)
+	}
+	defer stateStore.Close()
+
+	chequebookAddress, err := chequebook.StoredAddress(stateStore)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("chequebook address: %w", err)
+	}
+	endpoint := c.config.GetString(optionNameBlockchainRpcEndpoint)
+	if swapEndpoint := c.config.GetString(optionNameSwapEndpoint); swapEndpoint != "" {
+		endpoint = swapEndpoint
+	}
+	if endpoint == "" {
+		logger.Warning("no blockchain rpc endpoint, the chequebook ownership is not checked", "chequebook_address", chequebookAddress)
+		return nil
+	}
+
+	backend, overlayEthAddress, _, monitor, transactionService, err := node.InitChain(cmd.Context(), logger, stateStore, endpoint, 0, signerConfig.signer, blocktime, true)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+	defer monitor.Close()
+
+	issuer, err := chequebook.Issuer(cmd.Context(), transactionService, chequebookAddress)
+	if err != nil {
+		return fmt.Errorf("chequebook issuer: %w", err)
+	}
+	if issuer != overlayEthAddress {
+		return fmt.Errorf("chequebook %s is issued by %s, not by the node key %s", chequebookAddress, issuer, overlayEthAddress)
+	}
+	logger.Info("chequebook is issued by the node key", "chequebook_address", chequebookAddress, "issuer", issuer)
+	return nil
+}
+
+// storeDirs returns the directories of the leveldb stores under the data
+// directory, the statestores and the localstores of all the profiles.
+func storeDirs(dir string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == "CURRENT" {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// lockStores locks all the leveldb stores of the data directory, which
+// fails if any of them is open by the running node.
+func lockStores(dir string) ([]ldbstorage.Storage, error) {
+	dirs, err := storeDirs(dir)
+	if err != nil {
+		return nil, err
+	}
+	var locks []ldbstorage.Storage
+	for _, d := range dirs {
+		s, err := ldbstorage.OpenFile(d, false)
+		if err != nil {
+			unlockStores(locks)
+			return nil, fmt.Errorf("%s: %w", d, err)
+		}
+		locks = append(locks, s)
+	}
+	return locks, nil
+}
+
+func unlockStores(locks []ldbstorage.Storage) {
+	for _, s := range locks {
+		_ = s.Close()
+	}
+}
+
+// copyDir copies all the files of the from directory to the to directory and
+// verifies the checksum of every copied file. It returns the number of files.
+func copyDir(from, to string) (int, error) {
+	files := 0
+	err := filepath.WalkDir(from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode().IsRegular():
+			files++
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+	})
+	return files, err
+}
+
+func copyFile(from, to string, perm fs.FileMode) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(dst, h), src); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("copy %s: %w", from, err)
+	}
+	if err := dst.Sync(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	// the copy is read back, so that the written data is verified
+	f, err := os.Open(to)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	hc := sha256.New()
+	if _, err := io.Copy(hc, f); err != nil {
+		return fmt.Errorf("read %s: %w", to, err)
+	}
+	if !bytes.Equal(h.Sum(nil), hc.Sum(nil)) {
+		return fmt.Errorf("checksum of %s does not match %s", to, from)
+	}
+	return nil
+}
+
+func writeMigratedNote(from, to string) error {
+	b, err := json.Marshal(migratedNote{To: to, Time: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(from, migratedFile), b, 0600); err != nil {
+		return fmt.Errorf("write migration note: %w", err)
+	}
+	return nil
+}
+
+// verifyStores opens all the copied leveldb stores read-only and compares
+// the entries of the copied statestores with the original ones.
+func verifyStores(from, to string) error {
+	dirs, err := storeDirs(from)
+	if err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		rel, err := filepath.Rel(from, d)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if filepath.Base(d) != "statestore" {
+			db, err := leveldb.OpenFile(target, &opt.Options{ReadOnly: true})
+			if err != nil {
+				return fmt.Errorf("open %s: %w", target, err)
+			}
+			if err := db.Close(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := compareStores(d, target); err != nil {
+			return fmt.Errorf("statestore %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+func compareStores(from, to string) error {
+	src, err := leveldb.OpenFile(from, &opt.Options{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := leveldb.OpenFile(to, &opt.Options{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	si := src.NewIterator(nil, nil)
+	defer si.Release()
+	di := dst.NewIterator(nil, nil)
+	defer di.Release()
+	for si.Next() {
+		if !di.Next() {
+			return fmt.Errorf("entry %q is missing", si.Key())
+		}
+		if !bytes.Equal(si.Key(), di.Key()) || !bytes.Equal(si.Value(), di.Value()) {
+			return fmt.Errorf("entry %q does not match", si.Key())
+		}
+	}
+	if di.Next() {
+		return fmt.Errorf("unexpected entry %q", di.Key())
+	}
+	if err := si.Error(); err != nil {
+		return err
+	}
+	return di.Error()
+}
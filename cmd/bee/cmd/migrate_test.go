@@ -0,0 +1,86 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+)
+
+func TestMigrateCmd(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dataDir = t.TempDir()
+		newDir  = filepath.Join(t.TempDir(), "bee")
+	)
+
+	run := func(t *testing.T, args ...string) error {
+		t.Helper()
+
+		return newCommand(t,
+			cmd.WithArgs(append(args, "--password", "secret", "--verbosity", "0")...),
+			cmd.WithOutput(io.Discard),
+			cmd.WithErrorOutput(io.Discard),
+		).Execute()
+	}
+
+	if err := run(t, "migrate", "--data-dir", dataDir, "--to", newDir); err == nil {
+		t.Fatal("expected error for the data directory without keys")
+	}
+
+	if err := run(t, "init", "--data-dir", dataDir); err != nil {
+		t.Fatal(err)
+	}
+
+	notEmpty := t.TempDir()
+	if err := os.WriteFile(filepath.Join(notEmpty, "file"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(t, "migrate", "--data-dir", dataDir, "--to", notEmpty); err == nil {
+		t.Fatal("expected error for the not empty new data directory")
+	}
+
+	if err := run(t, "migrate", "--data-dir", dataDir, "--to", newDir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"swarm.key", "libp2p_v2.key", "pss.key"} {
+		want, err := os.ReadFile(filepath.Join(dataDir, "keys", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(newDir, "keys", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("key %s does not match", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(newDir, "statestore", "CURRENT")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(newDir, "MIGRATED")); err == nil {
+		t.Fatal("the new data directory is marked as migrated")
+	}
+
+	// the old data directory is not used any more
+	err := run(t, "migrate", "--data-dir", dataDir, "--to", filepath.Join(t.TempDir(), "bee"))
+	if err == nil || !strings.Contains(err.Error(), "was migrated to "+newDir) {
+		t.Fatalf("got error %v, want the data directory to be migrated", err)
+	}
+
+	// the node keys work in the new data directory
+	if err := run(t, "init", "--data-dir", newDir); err != nil {
+		t.Fatal(err)
+	}
+}
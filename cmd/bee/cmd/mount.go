@@ -0,0 +1,116 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ethersphere/bee/pkg/mount"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	optionNameMountPrefetch  = "prefetch"
+	optionNameMountCacheSize = "cache-size"
+)
+
+func (c *command) initMountCmd() {
+	cmd := &cobra.Command{
+		Use:   "mount <reference> <path>",
+		Short: "Mount the collection as a read-only file system",
+		Long: `Mount the collection as a read-only FUSE file system on the path.
+The files are retrieved through the HTTP API of the running node as they are read,
+so the pinned collections are read from the local store of the node.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 2 {
+				return cmd.Help()
+			}
+			v, err := cmd.Flags().GetString(optionNameVerbosity)
+			if err != nil {
+				return fmt.Errorf("get verbosity: %w", err)
+			}
+			v = strings.ToLower(v)
+			logger, err := newLogger(cmd, v)
+			if err != nil {
+				return fmt.Errorf("new logger: %w", err)
+			}
+
+			reference, err := swarm.ParseHexAddress(args[0])
+			if err != nil {
+				return fmt.Errorf("parse reference: %w", err)
+			}
+			apiAddr, err := cmd.Flags().GetString(optionNameAPIAddr)
+			if err != nil {
+				return fmt.Errorf("get api-addr: %w", err)
+			}
+			endpoint, err := url.Parse(apiAddr)
+			if err != nil {
+				return fmt.Errorf("parse api-addr: %w", err)
+			}
+			prefetch, err := cmd.Flags().GetInt64(optionNameMountPrefetch)
+			if err != nil {
+				return fmt.Errorf("get prefetch: %w", err)
+			}
+			cacheSize, err := cmd.Flags().GetInt(optionNameMountCacheSize)
+			if err != nil {
+				return fmt.Errorf("get cache-size: %w", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			api := mount.NewAPIGetter(http.DefaultClient, endpoint)
+			pinned, err := api.IsPinned(ctx, reference)
+			if err != nil {
+				return fmt.Errorf("check pin: %w", err)
+			}
+			if !pinned {
+				logger.Warning("reference is not pinned, the files are retrieved from the network", "reference", reference)
+			}
+
+			getter, err := mount.NewCache(api, cacheSize)
+			if err != nil {
+				return fmt.Errorf("cache: %w", err)
+			}
+			server, err := mount.Mount(ctx, args[1], getter, reference, mount.Options{
+				Prefetch: prefetch,
+				Debug:    v == "5" || v == "trace",
+			})
+			if err != nil {
+				return fmt.Errorf("mount: %w", err)
+			}
+			logger.Info("collection mounted", "reference", reference, "path", args[1])
+
+			sysInterruptChannel := make(chan os.Signal, 1)
+			signal.Notify(sysInterruptChannel, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-sysInterruptChannel
+				logger.Info("received interrupt signal")
+				if err := server.Unmount(); err != nil {
+					logger.Error(err, "unmount failed")
+				}
+			}()
+
+			server.Wait()
+			logger.Info("collection unmounted", "path", args[1])
+
+			return nil
+		},
+	}
+	cmd.Flags().String(optionNameAPIAddr, "http://localhost:1633", "HTTP API URL of the node")
+	cmd.Flags().Int64(optionNameMountPrefetch, mount.DefaultPrefetch, "size of the data prefetched ahead of the sequential reads in bytes")
+	cmd.Flags().Int(optionNameMountCacheSize, 4096, "number of the retrieved chunks kept in memory")
+	cmd.Flags().String(optionNameVerbosity, "info", "verbosity level")
+
+	c.root.AddCommand(cmd)
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/selftest"
+	"github.com/spf13/cobra"
+)
+
+const (
+	optionNameSelfTestBatch   = "batch"
+	optionNameSelfTestSize    = "size"
+	optionNameSelfTestTimeout = "timeout"
+)
+
+// errSelfTestFailed is returned if any stage of the self-test failed.
+var errSelfTestFailed = errors.New("self-test failed")
+
+type selfTestReport struct {
+	OK        bool   `json:"ok"`
+	Reference string `json:"reference"`
+	Size      int    `json:"size"`
+	Chunks    int    `json:"chunks"`
+	Receipts  int    `json:"receipts"`
+	Retrieved int    `json:"retrieved"`
+	Stages    []struct {
+		Name     string `json:"name"`
+		Duration int64  `json:"duration"`
+		Error    string `json:"error"`
+	} `json:"stages"`
+}
+
+func (c *command) initSelfTestCmd() {
+	cmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "Check that the running node can upload to and retrieve from the network",
+		Long: `Run the self-test of the running node through its debug HTTP API.
+The node uploads a small random payload stamped with the batch, pushes its chunks
+to the network waiting for the push-sync receipts and retrieves them back from
+the network, skipping its local store. The timings of the stages are printed.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			debugAPIAddr, err := cmd.Flags().GetString(optionNameDebugAPIAddr)
+			if err != nil {
+				return fmt.Errorf("get debug-api-addr: %w", err)
+			}
+			endpoint, err := url.Parse(debugAPIAddr)
+			if err != nil {
+				return fmt.Errorf("parse debug-api-addr: %w", err)
+			}
+			batch, err := cmd.Flags().GetString(optionNameSelfTestBatch)
+			if err != nil {
+				return fmt.Errorf("get batch: %w", err)
+			}
+			if batch == "" {
+				return errors.New("batch not set")
+			}
+			size, err := cmd.Flags().GetInt(optionNameSelfTestSize)
+			if err != nil {
+				return fmt.Errorf("get size: %w", err)
+			}
+			timeout, err := cmd.Flags().GetDuration(optionNameSelfTestTimeout)
+			if err != nil {
+				return fmt.Errorf("get timeout: %w", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			report, err := runSelfTest(ctx, endpoint, batch, size)
+			if err != nil {
+				return err
+			}
+
+			for _, stage := range report.Stages {
+				d := time.Duration(stage.Duration).Round(time.Millisecond)
+				if stage.Error != "" {
+					cmd.Printf("%-10s failed after %s: %s\n", stage.Name, d, stage.Error)
+					continue
+				}
+				cmd.Printf("%-10s ok in %s\n", stage.Name, d)
+			}
+			cmd.Printf("reference %s: %d bytes, %d chunks, %d receipts, %d retrieved\n",
+				report.Reference, report.Size, report.Chunks, report.Receipts, report.Retrieved)
+
+			if !report.OK {
+				return errSelfTestFailed
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(optionNameDebugAPIAddr, "http://localhost:1635", "debug HTTP API URL of the node")
+	cmd.Flags().String(optionNameSelfTestBatch, "", "ID of the postage batch the payload is stamped with")
+	cmd.Flags().Int(optionNameSelfTestSize, selftest.DefaultSize, "size of the random payload in bytes")
+	cmd.Flags().Duration(optionNameSelfTestTimeout, 5*time.Minute, "maximal duration of the self-test")
+
+	c.root.AddCommand(cmd)
+}
+
+// runSelfTest runs the self-test of the node through its debug API.
+func runSelfTest(ctx context.Context, endpoint *url.URL, batch string, size int) (*selfTestReport, error) {
+	u := endpoint.JoinPath("debug", "selftest")
+	u.RawQuery = url.Values{"size": {strconv.Itoa(size)}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Swarm-Postage-Batch-Id", strings.TrimPrefix(batch, "0x"))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("self-test request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var r jsonhttp.StatusResponse
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil || r.Message == "" {
+			return nil, fmt.Errorf("self-test request: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("self-test request: %s: %s", resp.Status, r.Message)
+	}
+
+	report := new(selfTestReport)
+	if err := json.NewDecoder(resp.Body).Decode(report); err != nil {
+		return nil, fmt.Errorf("decode self-test report: %w", err)
+	}
+	return report, nil
+}
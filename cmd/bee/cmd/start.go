@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/ecdsa"
+	"crypto/sha256"
 	_ "embed"
 	"encoding/hex"
 	"errors"
@@ -29,6 +30,8 @@ import (
 	"github.com/ethersphere/bee/pkg/crypto/clef"
 	"github.com/ethersphere/bee/pkg/keystore"
 	filekeystore "github.com/ethersphere/bee/pkg/keystore/file"
+	keyringkeystore "github.com/ethersphere/bee/pkg/keystore/keyring"
+	kmskeystore "github.com/ethersphere/bee/pkg/keystore/kms"
 	memkeystore "github.com/ethersphere/bee/pkg/keystore/mem"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/node"
@@ -64,7 +67,17 @@ func (c *command) initStartCmd() (err error) {
 
 			v := strings.ToLower(c.config.GetString(optionNameVerbosity))
 
-			logger, err := newLogger(cmd, v)
+			var sinkOpts []log.Option
+			for _, s := range c.config.GetStringSlice(optionNameLogSinks) {
+				sink, jsonOutput, err := log.NewSink(s)
+				if err != nil {
+					return fmt.Errorf("log sink %s: %w", s, err)
+				}
+				defer sink.Close()
+				sinkOpts = append(sinkOpts, log.WithExtraSink(sink, jsonOutput))
+			}
+
+			logger, err := newLogger(cmd, v, sinkOpts...)
 			if err != nil {
 				return fmt.Errorf("new logger: %w", err)
 			}
@@ -223,11 +236,24 @@ func buildBeeNode(ctx context.Context, c *command, cmd *cobra.Command, logger lo
 		debugAPIAddr = ""
 	}
 
+	if err := checkMigrated(c.config.GetString(optionNameDataDir)); err != nil {
+		return nil, err
+	}
+
 	signerConfig, err := c.configureSigner(cmd, logger)
 	if err != nil {
 		return nil, err
 	}
 
+	dataDir, err := c.dataDir()
+	if err != nil {
+		return nil, err
+	}
+	coldDataDir, err := c.profileDir(c.config.GetString(optionNameColdDataDir))
+	if err != nil {
+		return nil, err
+	}
+
 	bootNode := c.config.GetBool(optionNameBootnodeMode)
 	fullNode := c.config.GetBool(optionNameFullNode)
 
@@ -292,20 +318,43 @@ func buildBeeNode(ctx context.Context, c *command, cmd *cobra.Command, logger lo
 		blockchainRpcEndpoint = swapEndpoint
 	}
 
+	var chainDisabled bool
+	switch chain := c.config.GetString(optionNameChain); chain {
+	case "on":
+	case "off":
+		chainDisabled = true
+		if c.config.GetString(optionNameStaticBatchesFile) == "" {
+			return nil, errors.New("static batches file is required with the chain off")
+		}
+	default:
+		return nil, fmt.Errorf("invalid chain mode %q, expected on or off", chain)
+	}
+
 	b, err := node.NewBee(ctx, c.config.GetString(optionNameP2PAddr), signerConfig.publicKey, signerConfig.signer, networkID, logger, signerConfig.libp2pPrivateKey, signerConfig.pssPrivateKey, &node.Options{
-		DataDir:                       c.config.GetString(optionNameDataDir),
+		DataDir:                       dataDir,
 		CacheCapacity:                 c.config.GetUint64(optionNameCacheCapacity),
+		CacheMinFreeDisk:              c.config.GetFloat64(optionNameCacheMinFreeDisk),
+		UploadCapacity:                c.config.GetUint64(optionNameUploadCapacity),
+		StateStoreEncryptionKey:       signerConfig.stateStoreKey,
+		ColdDataDir:                   coldDataDir,
+		ColdAge:                       c.config.GetDuration(optionNameColdAge),
+		DBBackend:                     c.config.GetString(optionNameDBBackend),
 		DBOpenFilesLimit:              c.config.GetUint64(optionNameDBOpenFilesLimit),
 		DBBlockCacheCapacity:          c.config.GetUint64(optionNameDBBlockCacheCapacity),
 		DBWriteBufferSize:             c.config.GetUint64(optionNameDBWriteBufferSize),
 		DBDisableSeeksCompaction:      c.config.GetBool(optionNameDBDisableSeeksCompaction),
+		DBGroupCommitLatency:          c.config.GetDuration(optionNameDBGroupCommitLatency),
 		APIAddr:                       c.config.GetString(optionNameAPIAddr),
 		DebugAPIAddr:                  debugAPIAddr,
 		Addr:                          c.config.GetString(optionNameP2PAddr),
 		NATAddr:                       c.config.GetString(optionNameNATAddr),
 		EnableWS:                      c.config.GetBool(optionNameP2PWSEnable),
+		EnableRelay:                   c.config.GetBool(optionNameP2PRelayEnable),
+		EnableRelayService:            c.config.GetBool(optionNameP2PRelayServiceEnable),
+		StaticRelays:                  c.config.GetStringSlice(optionNameP2PStaticRelays),
 		WelcomeMessage:                c.config.GetString(optionWelcomeMessage),
 		Bootnodes:                     networkConfig.bootNodes,
+		BootnodeDomains:               c.config.GetStringSlice(optionNameBootnodeDomains),
 		CORSAllowedOrigins:            c.config.GetStringSlice(optionCORSAllowedOrigins),
 		TracingEnabled:                c.config.GetBool(optionNameTracingEnabled),
 		TracingEndpoint:               tracingEndpoint,
@@ -320,8 +369,8 @@ func buildBeeNode(ctx context.Context, c *command, cmd *cobra.Command, logger lo
 		SwapFactoryAddress:            c.config.GetString(optionNameSwapFactoryAddress),
 		SwapLegacyFactoryAddresses:    c.config.GetStringSlice(optionNameSwapLegacyFactoryAddresses),
 		SwapInitialDeposit:            c.config.GetString(optionNameSwapInitialDeposit),
-		SwapEnable:                    c.config.GetBool(optionNameSwapEnable),
-		ChequebookEnable:              c.config.GetBool(optionNameChequebookEnable),
+		SwapEnable:                    c.config.GetBool(optionNameSwapEnable) && !chainDisabled,
+		ChequebookEnable:              c.config.GetBool(optionNameChequebookEnable) && !chainDisabled,
 		FullNodeMode:                  fullNode,
 		PostageContractAddress:        c.config.GetString(optionNamePostageContractAddress),
 		PostageContractStartBlock:     c.config.GetUint64(optionNamePostageContractStartBlock),
@@ -330,6 +379,11 @@ func buildBeeNode(ctx context.Context, c *command, cmd *cobra.Command, logger lo
 		StakingContractAddress:        c.config.GetString(optionNameStakingAddress),
 		BlockTime:                     networkConfig.blockTime,
 		DeployGasPrice:                c.config.GetString(optionNameSwapDeploymentGasPrice),
+		SwapBounceThreshold:           c.config.GetInt(optionNameSwapBounceThreshold),
+		SwapBounceBlocklistDuration:   c.config.GetDuration(optionNameSwapBounceBlocklist),
+		WithdrawalAddress:             c.config.GetString(optionNameWithdrawalAddress),
+		WithdrawalThreshold:           c.config.GetString(optionNameWithdrawalThreshold),
+		WithdrawalInterval:            c.config.GetDuration(optionNameWithdrawalInterval),
 		WarmupTime:                    c.config.GetDuration(optionWarmUpTime),
 		ChainID:                       networkConfig.chainID,
 		RetrievalCaching:              c.config.GetBool(optionNameRetrievalCaching),
@@ -342,7 +396,43 @@ func buildBeeNode(ctx context.Context, c *command, cmd *cobra.Command, logger lo
 		TokenEncryptionKey:            c.config.GetString(optionNameTokenEncryptionKey),
 		AdminPasswordHash:             c.config.GetString(optionNameAdminPasswordHash),
 		UsePostageSnapshot:            c.config.GetBool(optionNameUsePostageSnapshot),
-		EnableStorageIncentives:       c.config.GetBool(optionNameStorageIncentivesEnable),
+		EnableStorageIncentives:       c.config.GetBool(optionNameStorageIncentivesEnable) && !chainDisabled,
+		MaxDirUploadFileSize:          c.config.GetInt64(optionNameMaxCollectionFileSize),
+		AuditLogPath:                  c.config.GetString(optionNameAuditLogFile),
+		AuditLogMaxSize:               c.config.GetInt64(optionNameAuditLogMaxSize),
+		AuditLogMaxBackups:            c.config.GetInt(optionNameAuditLogMaxBackups),
+		PushSyncTrace:                 c.config.GetBool(optionNamePushSyncTrace),
+		PushSyncBatchWindow:           c.config.GetDuration(optionNamePushSyncBatchWindow),
+		DynamicPricing:                c.config.GetBool(optionNameDynamicPricing),
+		CompressibleContentTypes:      c.config.GetStringSlice(optionNameCompressibleContentTypes),
+		TenantsPath:                   c.config.GetString(optionNameTenantsFile),
+		WebDAV:                        c.config.GetBool(optionNameWebDAV),
+		WebDAVPostageBatch:            c.config.GetString(optionNameWebDAVPostageBatch),
+		S3Addr:                        c.config.GetString(optionNameS3Addr),
+		S3PostageBatch:                c.config.GetString(optionNameS3PostageBatch),
+		PssGRPCAddr:                   c.config.GetString(optionNamePssGRPCAddr),
+		AccountingSnapshotInterval:    c.config.GetDuration(optionNameAccountingSnapshotInterval),
+		IPFSGateway:                   c.config.GetString(optionNameIPFSGateway),
+		ContentStats:                  c.config.GetBool(optionNameContentStats),
+		Gateway:                       c.config.GetBool(optionNameGateway),
+		SourceURLMaxSize:              c.config.GetInt64(optionNameSourceURLMaxSize),
+		SourceURLSchemes:              c.config.GetStringSlice(optionNameSourceURLSchemes),
+		SourceURLAllowPrivate:         c.config.GetBool(optionNameSourceURLAllowPrivate),
+		TopologyDriver:                c.config.GetString(optionNameTopologyDriver),
+		WebhookURLs:                   c.config.GetStringSlice(optionNameWebhookURLs),
+		WebhookSecret:                 c.config.GetString(optionNameWebhookSecret),
+		WebhookEvents:                 c.config.GetStringSlice(optionNameWebhookEvents),
+		WebhookChequebookMinBalance:   c.config.GetString(optionNameWebhookChequebookMin),
+		PyroscopeAddr:                 c.config.GetString(optionNamePyroscopeAddr),
+		PyroscopeAppName:              c.config.GetString(optionNamePyroscopeAppName),
+		HandoffAllowedPeers:           c.config.GetStringSlice(optionNameHandoffAllowedPeers),
+		FeedValidationHooks:           c.config.GetStringSlice(optionNameFeedValidationHooks),
+		FeedValidationTimeout:         c.config.GetDuration(optionNameFeedValidationTimeout),
+		ChainDisabled:                 chainDisabled,
+		StaticBatchesPath:             c.config.GetString(optionNameStaticBatchesFile),
+		StaticBatchesSigner:           c.config.GetString(optionNameStaticBatchesSigner),
+		PostageExpiryGracePeriod:      c.config.GetDuration(optionNamePostageExpiryGracePeriod),
+		PostageExpiryWarning:          c.config.GetDuration(optionNamePostageExpiryWarning),
 	})
 
 	return b, err
@@ -369,6 +459,7 @@ type signerConfig struct {
 	publicKey        *ecdsa.PublicKey
 	libp2pPrivateKey *ecdsa.PrivateKey
 	pssPrivateKey    *ecdsa.PrivateKey
+	stateStoreKey    []byte // nil if the statestore is not encrypted
 }
 
 func waitForClef(logger log.Logger, maxRetries uint64, endpoint string) (externalSigner *external.ExternalSigner, err error) {
@@ -387,13 +478,51 @@ func waitForClef(logger log.Logger, maxRetries uint64, endpoint string) (externa
 	return
 }
 
+const (
+	keystoreFile    = "file"
+	keystoreKeyring = "keyring"
+	keystoreKMS     = "kms"
+)
+
+// keystore returns the keystore selected by the keystore option.
+func (c *command) keystore(logger log.Logger) (keystore.Service, error) {
+	dataDir := c.config.GetString(optionNameDataDir)
+	switch ks := c.config.GetString(optionNameKeystore); ks {
+	case keystoreFile:
+		if dataDir == "" {
+			logger.Warning("data directory not provided, keys are not persisted")
+			return memkeystore.New(), nil
+		}
+		return filekeystore.New(filepath.Join(dataDir, "keys")), nil
+	case keystoreKeyring:
+		return keyringkeystore.New(keyringkeystore.DefaultServiceName), nil
+	case keystoreKMS:
+		if dataDir == "" {
+			return nil, errors.New("kms keystore requires the data directory")
+		}
+		vault, err := kmskeystore.NewVaultTransit(
+			c.config.GetString(optionNameKeystoreKMSURL),
+			c.config.GetString(optionNameKeystoreKMSKey),
+			c.config.GetString(optionNameKeystoreKMSToken),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("kms keystore: %w", err)
+		}
+		return kmskeystore.New(filepath.Join(dataDir, "keys"), vault), nil
+	default:
+		return nil, fmt.Errorf("unknown keystore %q", ks)
+	}
+}
+
 func (c *command) configureSigner(cmd *cobra.Command, logger log.Logger) (config *signerConfig, err error) {
-	var keystore keystore.Service
-	if c.config.GetString(optionNameDataDir) == "" {
-		keystore = memkeystore.New()
-		logger.Warning("data directory not provided, keys are not persisted")
-	} else {
-		keystore = filekeystore.New(filepath.Join(c.config.GetString(optionNameDataDir), "keys"))
+	profile, err := c.profile()
+	if err != nil {
+		return nil, err
+	}
+
+	keystore, err := c.keystore(logger)
+	if err != nil {
+		return nil, err
 	}
 
 	var signer crypto.Signer
@@ -411,7 +540,7 @@ func (c *command) configureSigner(cmd *cobra.Command, logger log.Logger) (config
 		// if libp2p key exists we can assume all required keys exist
 		// so prompt for a password to unlock them
 		// otherwise prompt for new password with confirmation to create them
-		exists, err := keystore.Exists("libp2p")
+		exists, err := keystore.Exists(profileKeyName(profile, "libp2p"))
 		if err != nil {
 			return nil, err
 		}
@@ -466,7 +595,7 @@ func (c *command) configureSigner(cmd *cobra.Command, logger log.Logger) (config
 		}
 	} else {
 		logger.Warning("clef is not enabled; portability and security of your keys is sub optimal")
-		swarmPrivateKey, _, err := keystore.Key("swarm", password, crypto.EDGSecp256_K1)
+		swarmPrivateKey, _, err := keystore.Key(profileKeyName(profile, "swarm"), password, crypto.EDGSecp256_K1)
 		if err != nil {
 			return nil, fmt.Errorf("swarm key: %w", err)
 		}
@@ -476,7 +605,7 @@ func (c *command) configureSigner(cmd *cobra.Command, logger log.Logger) (config
 
 	logger.Info("swarm public key", "public_key", hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(publicKey)))
 
-	libp2pPrivateKey, created, err := keystore.Key("libp2p_v2", password, crypto.EDGSecp256_R1)
+	libp2pPrivateKey, created, err := keystore.Key(profileKeyName(profile, "libp2p_v2"), password, crypto.EDGSecp256_R1)
 	if err != nil {
 		return nil, fmt.Errorf("libp2p v2 key: %w", err)
 	}
@@ -486,7 +615,7 @@ func (c *command) configureSigner(cmd *cobra.Command, logger log.Logger) (config
 		logger.Debug("using existing libp2p key")
 	}
 
-	pssPrivateKey, created, err := keystore.Key("pss", password, crypto.EDGSecp256_K1)
+	pssPrivateKey, created, err := keystore.Key(profileKeyName(profile, "pss"), password, crypto.EDGSecp256_K1)
 	if err != nil {
 		return nil, fmt.Errorf("pss key: %w", err)
 	}
@@ -498,6 +627,23 @@ func (c *command) configureSigner(cmd *cobra.Command, logger log.Logger) (config
 
 	logger.Info("pss public key", "public_key", hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(&pssPrivateKey.PublicKey)))
 
+	var stateStoreKey []byte
+	if c.config.GetBool(optionNameStateStoreEncryption) {
+		stateStorePrivateKey, created, err := keystore.Key(profileKeyName(profile, "statestore"), password, crypto.EDGSecp256_K1)
+		if err != nil {
+			return nil, fmt.Errorf("statestore key: %w", err)
+		}
+		if created {
+			logger.Debug("new statestore key created")
+		} else {
+			logger.Debug("using existing statestore key")
+		}
+		// the encryption key is derived from the private key
+		// so that it is kept and protected as the other keys
+		k := sha256.Sum256(append([]byte("statestore encryption"), stateStorePrivateKey.D.FillBytes(make([]byte, 32))...))
+		stateStoreKey = k[:]
+	}
+
 	// postinst and post scripts inside packaging/{deb,rpm} depend and parse on this log output
 	overlayEthAddress, err := signer.EthereumAddress()
 	if err != nil {
@@ -510,6 +656,7 @@ func (c *command) configureSigner(cmd *cobra.Command, logger log.Logger) (config
 		publicKey:        publicKey,
 		libp2pPrivateKey: libp2pPrivateKey,
 		pssPrivateKey:    pssPrivateKey,
+		stateStoreKey:    stateStoreKey,
 	}, nil
 }
 
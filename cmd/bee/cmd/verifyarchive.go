@@ -0,0 +1,69 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethersphere/bee/pkg/localstore/exportverify"
+	"github.com/spf13/cobra"
+)
+
+// errVerifyArchiveFailed is returned if any chunk of the archive failed the verification.
+var errVerifyArchiveFailed = errors.New("archive verification failed")
+
+func (c *command) initVerifyArchiveCmd() {
+	cmd := &cobra.Command{
+		Use:   "verify-archive <filename>",
+		Short: "Verify the exported content archive offline. Use \"-\" as filename in order to read from STDIN",
+		Long: `Verify the archive written by the db export command without a running node.
+The content of every chunk must hash to its address, the postage stamps of each
+batch must be signed by the same owner and be in the collision bucket of their
+chunks, and all the chunks of the manifests in the archive must be in the archive.
+The owners of the batches are printed, so that they can be checked on the chain.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return cmd.Help()
+			}
+
+			var in io.Reader
+			if args[0] == "-" {
+				in = os.Stdin
+			} else {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return fmt.Errorf("open archive: %w", err)
+				}
+				defer f.Close()
+				in = f
+			}
+
+			report, err := exportverify.Verify(context.Background(), in)
+			if err != nil {
+				return fmt.Errorf("verify archive: %w", err)
+			}
+
+			for _, f := range report.Failures {
+				cmd.Printf("%-8s %s: %s\n", f.Check, f.Address, f.Reason)
+			}
+			for _, b := range report.Batches {
+				cmd.Printf("batch %x: owner %x, %d chunks\n", b.ID, b.Owner, b.Chunks)
+			}
+			cmd.Printf("export version %s: %d chunks, %d manifests, %d ignored files, %d failures\n",
+				report.Version, report.Chunks, report.Manifests, report.Ignored, len(report.Failures))
+
+			if !report.OK() {
+				return errVerifyArchiveFailed
+			}
+			return nil
+		},
+	}
+
+	c.root.AddCommand(cmd)
+}
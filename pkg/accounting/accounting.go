@@ -1375,7 +1375,7 @@ func (d *debitAction) Apply() error {
 		if err != nil {
 			disconnectFor = 10
 		}
-		return p2p.NewBlockPeerError(time.Duration(disconnectFor)*time.Second, ErrDisconnectThresholdExceeded)
+		return p2p.NewBlockPeerError(time.Duration(disconnectFor)*time.Second, p2p.OffenseAccountingViolation, ErrDisconnectThresholdExceeded)
 
 	}
 
@@ -1425,10 +1425,10 @@ func (a *Accounting) blocklistUntil(peer swarm.Address, multiplier int64) (int64
 func (a *Accounting) blocklist(peer swarm.Address, multiplier int64, reason string) error {
 	disconnectFor, err := a.blocklistUntil(peer, multiplier)
 	if err != nil {
-		return a.p2p.Blocklist(peer, 1*time.Minute, reason)
+		return a.p2p.Blocklist(peer, 1*time.Minute, p2p.OffenseAccountingViolation, reason)
 	}
 
-	return a.p2p.Blocklist(peer, time.Duration(disconnectFor)*time.Second, reason)
+	return a.p2p.Blocklist(peer, time.Duration(disconnectFor)*time.Second, p2p.OffenseAccountingViolation, reason)
 }
 
 func (a *Accounting) Connect(peer swarm.Address, fullNode bool) {
@@ -1531,7 +1531,7 @@ func (a *Accounting) Disconnect(peer swarm.Address) {
 			disconnectFor = int64(10)
 		}
 		accountingPeer.connected = false
-		_ = a.p2p.Blocklist(peer, time.Duration(disconnectFor)*time.Second, "accounting disconnect")
+		_ = a.p2p.Blocklist(peer, time.Duration(disconnectFor)*time.Second, p2p.OffenseAccountingViolation, "accounting disconnect")
 		a.metrics.AccountingDisconnectsReconnectCount.Inc()
 	}
 }
@@ -1593,7 +1593,7 @@ func TestAccountingGhostOverdraft(t *testing.T) {
 
 	paymentThresholdInRefreshmentSeconds := new(big.Int).Div(testPaymentThreshold, big.NewInt(testRefreshRate)).Uint64()
 
-	f := func(s swarm.Address, t time.Duration, reason string) error {
+	f := func(s swarm.Address, t time.Duration, _ p2p.Offense, reason string) error {
 		if reason != "ghost overdraw" {
 			return errInvalidReason
 		}
@@ -1671,7 +1671,7 @@ func TestAccountingReconnectBeforeAllowed(t *testing.T) {
 
 	paymentThresholdInRefreshmentSeconds := new(big.Int).Div(testPaymentThreshold, big.NewInt(testRefreshRate)).Uint64()
 
-	f := func(s swarm.Address, t time.Duration, reason string) error {
+	f := func(s swarm.Address, t time.Duration, _ p2p.Offense, reason string) error {
 		if reason != "accounting disconnect" {
 			return errInvalidReason
 		}
@@ -1745,7 +1745,7 @@ func TestAccountingResetBalanceAfterReconnect(t *testing.T) {
 
 	paymentThresholdInRefreshmentSeconds := new(big.Int).Div(testPaymentThreshold, big.NewInt(testRefreshRate)).Uint64()
 
-	f := func(s swarm.Address, t time.Duration, reason string) error {
+	f := func(s swarm.Address, t time.Duration, _ p2p.Offense, reason string) error {
 		if reason != "accounting disconnect" {
 			return errInvalidReason
 		}
@@ -5,16 +5,53 @@
 package api
 
 import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math/big"
 	"net/http"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/billing"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/profitability"
+	"github.com/ethersphere/bee/pkg/retrieval"
+	"github.com/ethersphere/bee/pkg/swarm"
 )
 
 const (
 	httpErrGetAccountingInfo = "Cannot get accounting info"
+	httpErrGetProfitability  = "Cannot get profitability"
+	httpErrGetSnapshots      = "Cannot get accounting snapshots"
 )
 
+// defaultProfitabilityDays is the number of the reported days if not given.
+const defaultProfitabilityDays = 30
+
+// defaultEarningChunks is the number of the reported chunks if not given.
+const defaultEarningChunks = 100
+
+// defaultSnapshotDays is the number of the days of the
+// reported snapshots if the beginning of the range is not given.
+const defaultSnapshotDays = 30
+
+// snapshotDateLayout is the layout of the dates of the snapshot ranges,
+// the times of the ranges may be given in RFC 3339 format as well.
+const snapshotDateLayout = "2006-01-02"
+
+// BillingSnapshotter returns the periodic snapshots of
+// the accounting balances and the settlements of the peers.
+type BillingSnapshotter interface {
+	Snapshots(from, to time.Time) ([]billing.Snapshot, error)
+}
+
+// EarningsReporter reports the earnings of the chunks served
+// from the local store to the retrieval requests of the peers.
+type EarningsReporter interface {
+	Earnings(limit int) (*big.Int, []retrieval.ChunkEarnings)
+}
+
 type peerData struct {
 	InfoResponse map[string]peerDataResponse `json:"peerData"`
 }
@@ -61,3 +98,288 @@ func (s *Service) accountingInfoHandler(w http.ResponseWriter, _ *http.Request)
 
 	jsonhttp.OK(w, peerData{InfoResponse: infoResponses})
 }
+
+type profitabilityDayResponse struct {
+	Date        string                    `json:"date"`
+	Costs       map[string]*bigint.BigInt `json:"costs"`
+	Income      map[string]*bigint.BigInt `json:"income"`
+	TotalCosts  *bigint.BigInt            `json:"totalCosts"`
+	TotalIncome *bigint.BigInt            `json:"totalIncome"`
+	Net         *bigint.BigInt            `json:"net,omitempty"`
+}
+
+type profitabilityResponse struct {
+	TotalCosts  *bigint.BigInt             `json:"totalCosts"`
+	TotalIncome *bigint.BigInt             `json:"totalIncome"`
+	Net         *bigint.BigInt             `json:"net,omitempty"`
+	Days        []profitabilityDayResponse `json:"days"`
+}
+
+// sumAmounts wraps the amounts and returns them with their sum.
+func sumAmounts(amounts map[string]*big.Int) (map[string]*bigint.BigInt, *big.Int) {
+	wrapped := make(map[string]*bigint.BigInt, len(amounts))
+	total := new(big.Int)
+	for category, amount := range amounts {
+		wrapped[category] = bigint.Wrap(amount)
+		total.Add(total, amount)
+	}
+	return wrapped, total
+}
+
+// profitabilityHandler reports the daily transaction fees, in wei, and the
+// income, in PLUR, of the node. If the price of a BZZ token in wei is given
+// by the rate query parameter, the net result in wei is reported as well.
+func (s *Service) profitabilityHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_accounting_profitability").Build()
+
+	queries := struct {
+		Days int      `map:"days" validate:"min=0,max=365"`
+		Rate *big.Int `map:"rate"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	if queries.Days == 0 {
+		queries.Days = defaultProfitabilityDays
+	}
+
+	days, err := s.profitability.Days(queries.Days)
+	if err != nil {
+		logger.Debug("get profitability failed", "error", err)
+		logger.Error(nil, "get profitability failed")
+		jsonhttp.InternalServerError(w, httpErrGetProfitability)
+		return
+	}
+
+	var (
+		totalCosts  = new(big.Int)
+		totalIncome = new(big.Int)
+		res         = profitabilityResponse{Days: make([]profitabilityDayResponse, 0, len(days))}
+	)
+	for _, day := range days {
+		costs, dayCosts := sumAmounts(day.Costs)
+		income, dayIncome := sumAmounts(day.Income)
+		dayRes := profitabilityDayResponse{
+			Date:        day.Date,
+			Costs:       costs,
+			Income:      income,
+			TotalCosts:  bigint.Wrap(dayCosts),
+			TotalIncome: bigint.Wrap(dayIncome),
+		}
+		if queries.Rate != nil {
+			dayRes.Net = bigint.Wrap(new(big.Int).Sub(profitability.IncomeInWei(dayIncome, queries.Rate), dayCosts))
+		}
+		res.Days = append(res.Days, dayRes)
+		totalCosts.Add(totalCosts, dayCosts)
+		totalIncome.Add(totalIncome, dayIncome)
+	}
+	res.TotalCosts = bigint.Wrap(totalCosts)
+	res.TotalIncome = bigint.Wrap(totalIncome)
+	if queries.Rate != nil {
+		res.Net = bigint.Wrap(new(big.Int).Sub(profitability.IncomeInWei(totalIncome, queries.Rate), totalCosts))
+	}
+
+	jsonhttp.OK(w, res)
+}
+
+type chunkEarningsResponse struct {
+	Address    swarm.Address  `json:"address"`
+	Earned     *bigint.BigInt `json:"earned"`
+	Retrievals uint64         `json:"retrievals"`
+	LastServed time.Time      `json:"lastServed"`
+	Proximity  uint8          `json:"proximity"`
+}
+
+type earningsResponse struct {
+	TotalEarned *bigint.BigInt          `json:"totalEarned"`
+	Chunks      []chunkEarningsResponse `json:"chunks"`
+}
+
+// earningsHandler ranks the recently served chunks of the local store, the
+// cached ones and the ones of the reserve, by the amounts, in PLUR, the peers
+// were debited for retrieving them, so that the operators can tell how the
+// size of the cache and the garbage collection affect the revenue.
+func (s *Service) earningsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_accounting_earnings").Build()
+
+	queries := struct {
+		Limit int `map:"limit" validate:"min=0,max=10000"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	if queries.Limit == 0 {
+		queries.Limit = defaultEarningChunks
+	}
+
+	total, chunks := s.earnings.Earnings(queries.Limit)
+	res := earningsResponse{
+		TotalEarned: bigint.Wrap(total),
+		Chunks:      make([]chunkEarningsResponse, 0, len(chunks)),
+	}
+	for _, c := range chunks {
+		res.Chunks = append(res.Chunks, chunkEarningsResponse{
+			Address:    c.Address,
+			Earned:     bigint.Wrap(c.Earned),
+			Retrievals: c.Retrievals,
+			LastServed: c.LastServed,
+			Proximity:  c.Proximity,
+		})
+	}
+
+	jsonhttp.OK(w, res)
+}
+
+type snapshotPeerResponse struct {
+	Peer                    string         `json:"peer"`
+	Balance                 *bigint.BigInt `json:"balance"`
+	ConsumedBalance         *bigint.BigInt `json:"consumedBalance"`
+	SettlementsReceived     *bigint.BigInt `json:"settlementsReceived"`
+	SettlementsSent         *bigint.BigInt `json:"settlementsSent"`
+	TimeSettlementsReceived *bigint.BigInt `json:"timeSettlementsReceived"`
+	TimeSettlementsSent     *bigint.BigInt `json:"timeSettlementsSent"`
+}
+
+type snapshotResponse struct {
+	Time  time.Time              `json:"time"`
+	Peers []snapshotPeerResponse `json:"peers"`
+}
+
+type snapshotsResponse struct {
+	From      time.Time          `json:"from"`
+	To        time.Time          `json:"to"`
+	Snapshots []snapshotResponse `json:"snapshots"`
+}
+
+// snapshotCSVHeader is the header of the csv export, each
+// of its rows is the state of a single peer in a snapshot.
+var snapshotCSVHeader = []string{
+	"time",
+	"peer",
+	"balance",
+	"consumedBalance",
+	"settlementsReceived",
+	"settlementsSent",
+	"timeSettlementsReceived",
+	"timeSettlementsSent",
+}
+
+// parseSnapshotTime parses the bound of the snapshot range given as a date
+// or as a time in RFC 3339 format. The end of the range given as a date
+// includes the whole day.
+func parseSnapshotTime(value string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(snapshotDateLayout, value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid date")
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// snapshotsHandler exports the periodic snapshots of the accounting balances
+// and of the cumulative settlements of the peers, in PLUR, taken in the
+// [from, to) range, as json or as csv, so that the operators who resell the
+// capacity of their gateway can generate the billing data from the node.
+func (s *Service) snapshotsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_accounting_snapshots").Build()
+
+	queries := struct {
+		From   string `map:"from"`
+		To     string `map:"to"`
+		Format string `map:"format" validate:"omitempty,oneof=json csv"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	to := time.Now().UTC()
+	if queries.To != "" {
+		t, err := parseSnapshotTime(queries.To, true)
+		if err != nil {
+			logger.Debug("invalid end of the range", "to", queries.To, "error", err)
+			jsonhttp.BadRequest(w, "invalid to")
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -defaultSnapshotDays)
+	if queries.From != "" {
+		t, err := parseSnapshotTime(queries.From, false)
+		if err != nil {
+			logger.Debug("invalid beginning of the range", "from", queries.From, "error", err)
+			jsonhttp.BadRequest(w, "invalid from")
+			return
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		jsonhttp.BadRequest(w, "empty time range")
+		return
+	}
+
+	snapshots, err := s.billing.Snapshots(from, to)
+	if err != nil {
+		logger.Debug("get accounting snapshots failed", "error", err)
+		logger.Error(nil, "get accounting snapshots failed")
+		jsonhttp.InternalServerError(w, httpErrGetSnapshots)
+		return
+	}
+
+	if queries.Format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"accounting-%s-%s.csv\"", from.Format(snapshotDateLayout), to.Format(snapshotDateLayout)))
+		cw := csv.NewWriter(w)
+		_ = cw.Write(snapshotCSVHeader)
+		for _, snapshot := range snapshots {
+			t := snapshot.Time.Format(time.RFC3339)
+			for _, p := range snapshot.Peers {
+				_ = cw.Write([]string{
+					t,
+					p.Peer,
+					p.Balance.String(),
+					p.ConsumedBalance.String(),
+					p.SettlementsReceived.String(),
+					p.SettlementsSent.String(),
+					p.TimeSettlementsReceived.String(),
+					p.TimeSettlementsSent.String(),
+				})
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			logger.Debug("write accounting snapshots failed", "error", err)
+		}
+		return
+	}
+
+	res := snapshotsResponse{
+		From:      from,
+		To:        to,
+		Snapshots: make([]snapshotResponse, 0, len(snapshots)),
+	}
+	for _, snapshot := range snapshots {
+		peers := make([]snapshotPeerResponse, 0, len(snapshot.Peers))
+		for _, p := range snapshot.Peers {
+			peers = append(peers, snapshotPeerResponse{
+				Peer:                    p.Peer,
+				Balance:                 bigint.Wrap(p.Balance),
+				ConsumedBalance:         bigint.Wrap(p.ConsumedBalance),
+				SettlementsReceived:     bigint.Wrap(p.SettlementsReceived),
+				SettlementsSent:         bigint.Wrap(p.SettlementsSent),
+				TimeSettlementsReceived: bigint.Wrap(p.TimeSettlementsReceived),
+				TimeSettlementsSent:     bigint.Wrap(p.TimeSettlementsSent),
+			})
+		}
+		res.Snapshots = append(res.Snapshots, snapshotResponse{Time: snapshot.Time, Peers: peers})
+	}
+
+	jsonhttp.OK(w, res)
+}
@@ -10,13 +10,20 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/accounting"
 	"github.com/ethersphere/bee/pkg/accounting/mock"
 	"github.com/ethersphere/bee/pkg/api"
 	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/billing"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/profitability"
+	"github.com/ethersphere/bee/pkg/retrieval"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
 )
 
 func TestAccountingInfo(t *testing.T) {
@@ -123,3 +130,196 @@ func TestAccountingInfoError(t *testing.T) {
 		}),
 	)
 }
+
+func TestProfitability(t *testing.T) {
+	t.Parallel()
+
+	ledger := profitability.New(statestore.NewStateStore())
+	if err := ledger.AddCost(profitability.CostCashout, common.HexToHash("0x1"), big.NewInt(3000)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.AddIncome(profitability.IncomeSettlements, big.NewInt(10_000_000_000_000)); err != nil {
+		t.Fatal(err)
+	}
+	testServer, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:      true,
+		Profitability: ledger,
+	})
+
+	t.Run("report", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.ProfitabilityResponse
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/accounting/profitability?days=2&rate=5000", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+
+		if len(res.Days) != 2 {
+			t.Fatalf("got %d days, want 2", len(res.Days))
+		}
+		if res.Days[0].TotalCosts.Sign() != 0 || res.Days[0].TotalIncome.Sign() != 0 {
+			t.Fatalf("got yesterday %+v, want no records", res.Days[0])
+		}
+		today := res.Days[1]
+		if today.Costs[profitability.CostCashout].Int64() != 3000 {
+			t.Fatalf("got costs %v, want cashout cost 3000", today.Costs)
+		}
+		if today.Income[profitability.IncomeSettlements].Int64() != 10_000_000_000_000 {
+			t.Fatalf("got income %v, want settlements income", today.Income)
+		}
+		// the income is worth 5 wei at the rate of 5000 wei per BZZ
+		if res.TotalCosts.Int64() != 3000 || res.Net.Int64() != -2995 {
+			t.Fatalf("got total costs %v and net %v, want 3000 and -2995", res.TotalCosts, res.Net)
+		}
+	})
+
+	t.Run("without rate", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.ProfitabilityResponse
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/accounting/profitability", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+
+		if len(res.Days) != 30 {
+			t.Fatalf("got %d days, want 30", len(res.Days))
+		}
+		if res.Net != nil {
+			t.Fatalf("got net %v, want none", res.Net)
+		}
+	})
+
+	t.Run("invalid days", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/accounting/profitability?days=1000", http.StatusBadRequest)
+	})
+}
+
+type earningsReporterMock func(limit int) (*big.Int, []retrieval.ChunkEarnings)
+
+func (m earningsReporterMock) Earnings(limit int) (*big.Int, []retrieval.ChunkEarnings) {
+	return m(limit)
+}
+
+func TestEarnings(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr       = swarm.MustParseHexAddress("0034")
+		lastServed = time.Unix(1700000000, 0).UTC()
+		gotLimit   = make(chan int, 1)
+	)
+	testServer, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		Earnings: earningsReporterMock(func(limit int) (*big.Int, []retrieval.ChunkEarnings) {
+			gotLimit <- limit
+			return big.NewInt(30), []retrieval.ChunkEarnings{{
+				Address:    addr,
+				Earned:     big.NewInt(20),
+				Retrievals: 2,
+				LastServed: lastServed,
+				Proximity:  3,
+			}}
+		}),
+	})
+
+	jsonhttptest.Request(t, testServer, http.MethodGet, "/accounting/earnings", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.EarningsResponse{
+			TotalEarned: bigint.Wrap(big.NewInt(30)),
+			Chunks: []api.ChunkEarningsResponse{{
+				Address:    addr,
+				Earned:     bigint.Wrap(big.NewInt(20)),
+				Retrievals: 2,
+				LastServed: lastServed,
+				Proximity:  3,
+			}},
+		}),
+	)
+	if limit := <-gotLimit; limit != 100 {
+		t.Fatalf("got limit %d, want the default 100", limit)
+	}
+
+	jsonhttptest.Request(t, testServer, http.MethodGet, "/accounting/earnings?limit=5", http.StatusOK)
+	if limit := <-gotLimit; limit != 5 {
+		t.Fatalf("got limit %d, want 5", limit)
+	}
+
+	jsonhttptest.Request(t, testServer, http.MethodGet, "/accounting/earnings?limit=10001", http.StatusBadRequest)
+}
+
+type billingSnapshotterMock func(from, to time.Time) ([]billing.Snapshot, error)
+
+func (m billingSnapshotterMock) Snapshots(from, to time.Time) ([]billing.Snapshot, error) {
+	return m(from, to)
+}
+
+func TestAccountingSnapshots(t *testing.T) {
+	t.Parallel()
+
+	type timeRange struct{ from, to time.Time }
+	var (
+		taken    = time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC)
+		gotRange = make(chan timeRange, 1)
+	)
+	testServer, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		Billing: billingSnapshotterMock(func(from, to time.Time) ([]billing.Snapshot, error) {
+			gotRange <- timeRange{from, to}
+			return []billing.Snapshot{{
+				Time: taken,
+				Peers: []billing.Peer{{
+					Peer:                    "a",
+					Balance:                 big.NewInt(-10),
+					ConsumedBalance:         big.NewInt(5),
+					SettlementsReceived:     big.NewInt(100),
+					SettlementsSent:         big.NewInt(0),
+					TimeSettlementsReceived: big.NewInt(3),
+					TimeSettlementsSent:     big.NewInt(1),
+				}},
+			}}, nil
+		}),
+	})
+
+	from := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	jsonhttptest.Request(t, testServer, http.MethodGet, "/accounting/snapshots?from=2023-05-01&to=2023-05-31", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.SnapshotsResponse{
+			From: from,
+			To:   to,
+			Snapshots: []api.SnapshotResponse{{
+				Time: taken,
+				Peers: []api.SnapshotPeerResponse{{
+					Peer:                    "a",
+					Balance:                 bigint.Wrap(big.NewInt(-10)),
+					ConsumedBalance:         bigint.Wrap(big.NewInt(5)),
+					SettlementsReceived:     bigint.Wrap(big.NewInt(100)),
+					SettlementsSent:         bigint.Wrap(big.NewInt(0)),
+					TimeSettlementsReceived: bigint.Wrap(big.NewInt(3)),
+					TimeSettlementsSent:     bigint.Wrap(big.NewInt(1)),
+				}},
+			}},
+		}),
+	)
+	if r := <-gotRange; !r.from.Equal(from) || !r.to.Equal(to) {
+		t.Fatalf("got range %v - %v, want %v - %v", r.from, r.to, from, to)
+	}
+
+	jsonhttptest.Request(t, testServer, http.MethodGet, "/accounting/snapshots?from=2023-05-01T00:00:00Z&to=2023-06-01T00:00:00Z&format=csv", http.StatusOK,
+		jsonhttptest.WithExpectedResponse([]byte("time,peer,balance,consumedBalance,settlementsReceived,settlementsSent,timeSettlementsReceived,timeSettlementsSent\n"+
+			"2023-05-02T12:00:00Z,a,-10,5,100,0,3,1\n")),
+	)
+	if r := <-gotRange; !r.from.Equal(from) || !r.to.Equal(to) {
+		t.Fatalf("got range %v - %v, want %v - %v", r.from, r.to, from, to)
+	}
+
+	for _, query := range []string{
+		"from=yesterday",
+		"to=2023-13-01",
+		"from=2023-05-02&to=2023-05-01",
+		"format=xml",
+	} {
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/accounting/snapshots?"+query, http.StatusBadRequest)
+	}
+}
@@ -28,11 +28,21 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/accounting"
+	"github.com/ethersphere/bee/pkg/audit"
 	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/availability"
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/contentstats"
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/denylist"
 	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/validation"
+	"github.com/ethersphere/bee/pkg/file/padding"
 	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/file/pipeline/encryption"
+	"github.com/ethersphere/bee/pkg/handoff"
+	"github.com/ethersphere/bee/pkg/ipfs"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/p2p"
@@ -40,11 +50,16 @@ import (
 	"github.com/ethersphere/bee/pkg/pinning"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
+	"github.com/ethersphere/bee/pkg/prewarm"
+	"github.com/ethersphere/bee/pkg/profitability"
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/pusher"
+	"github.com/ethersphere/bee/pkg/ratelimit"
+	"github.com/ethersphere/bee/pkg/receipts"
 	"github.com/ethersphere/bee/pkg/resolver"
 	"github.com/ethersphere/bee/pkg/resolver/client/ens"
 	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/selftest"
 	"github.com/ethersphere/bee/pkg/settlement"
 	"github.com/ethersphere/bee/pkg/settlement/swap"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
@@ -60,10 +75,13 @@ import (
 	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/ethersphere/bee/pkg/transaction"
 	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/webhook"
+	"github.com/ethersphere/bee/pkg/workingset"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/go-multierror"
 	"github.com/prometheus/client_golang/prometheus"
+	dav "golang.org/x/net/webdav"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 )
@@ -79,9 +97,37 @@ const (
 	SwarmErrorDocumentHeader  = "Swarm-Error-Document"
 	SwarmFeedIndexHeader      = "Swarm-Feed-Index"
 	SwarmFeedIndexNextHeader  = "Swarm-Feed-Index-Next"
+	IfFeedIndexHeader         = "If-Feed-Index"
 	SwarmCollectionHeader     = "Swarm-Collection"
 	SwarmPostageBatchIdHeader = "Swarm-Postage-Batch-Id"
 	SwarmDeferredUploadHeader = "Swarm-Deferred-Upload"
+	SwarmSocOwnerHeader       = "Swarm-Soc-Owner"
+	SwarmSocIdHeader          = "Swarm-Soc-Id"
+	SwarmTraceHeader          = "Swarm-Trace"
+	SwarmTraceIdHeader        = "Swarm-Trace-Id"
+
+	// SwarmPostageFallbackBatchIdHeader is the batch used for the chunks
+	// whose bucket is full in the batch of SwarmPostageBatchIdHeader.
+	SwarmPostageFallbackBatchIdHeader = "Swarm-Postage-Fallback-Batch-Id"
+
+	// SwarmEncryptPaddingHeader is the block size in bytes the encrypted
+	// content is padded to, so that its reference does not leak its length.
+	SwarmEncryptPaddingHeader = "Swarm-Encrypt-Padding"
+	// SwarmUnpaddedLengthHeader is the length of the padded content without
+	// the padding, which is returned on the upload to /bytes. The padding is
+	// not recorded in the content, it is stripped on the download from /bytes
+	// only if the request has the header set.
+	SwarmUnpaddedLengthHeader = "Swarm-Unpadded-Length"
+
+	// SwarmContentSha256Header is the hex encoded SHA-256 hash of the
+	// body of the upload, which is rejected if the body does not match.
+	SwarmContentSha256Header = "Swarm-Content-Sha256"
+
+	// SwarmRootNeighbourhoodHeader places the root chunks of the encrypted
+	// upload within the neighbourhood stored by the node. Warning: mining the
+	// keys of the chunks slows down the upload and the reference reveals the
+	// neighbourhood, and so the uploading node, to anyone who sees it.
+	SwarmRootNeighbourhoodHeader = "Swarm-Root-Neighbourhood"
 )
 
 // The size of buffer used for prefetching content with Langos.
@@ -127,6 +173,9 @@ type Service struct {
 	traversal       traversal.Traverser
 	pinning         pinning.Interface
 	steward         steward.Interface
+	handoff         handoff.Interface
+	selfTest        selftest.Interface
+	feedValidator   validation.Validator
 	logger          log.Logger
 	loggerV1        log.Logger
 	tracer          *tracing.Tracer
@@ -139,6 +188,38 @@ type Service struct {
 	metricsRegistry *prometheus.Registry
 	stakingContract staking.Contract
 	indexDebugger   StorageIndexDebugger
+	reserve         ReserveReporter
+	reserveChecker  ReserveChecker
+	reserveRepairer ReserveRepairer
+	syncer          SyncReporter
+	statusService   StatusService
+	auditLog        *audit.Log
+	batchEvents     *postage.BatchEventFeed
+	stateStore      storage.StateStorer
+	tenants         map[string]*tenant
+	receipts        *receipts.Store
+	denylist        *denylist.List
+	gatewayLimiter  *ratelimit.Limiter
+	sourceClient    *http.Client
+	profitability   *profitability.Ledger
+	earnings        EarningsReporter
+	billing         BillingSnapshotter
+	prewarm         *prewarm.Service
+	workingSet      *workingset.Service
+	availability    *availability.Service
+	clockSkew       *clockskew.Detector
+	ipfs            ipfs.Fetcher
+	webhooks        *webhook.Service
+	contentStats    *contentstats.Service
+
+	idempotencyMu       sync.Mutex
+	webdavMu            sync.Mutex
+	publishMu           sync.Mutex // serializes the feed updates of the published collections
+	chunkTraces         *chunkTraces
+	webdavLocks         dav.LockSystem
+	s3Mu                sync.Mutex
+	collectionsMu       sync.Mutex          // serializes the feed updates of the collections
+	idempotencyInflight map[string]struct{} // idempotency keys of the uploads in progress
 	Options
 
 	http.Handler
@@ -161,6 +242,7 @@ type Service struct {
 	p2p            p2p.DebugService
 	accounting     accounting.Interface
 	chequebook     chequebook.Service
+	withdrawal     WithdrawalPlanner
 	pseudosettle   settlement.Interface
 	pingpong       pingpong.Interface
 
@@ -206,9 +288,37 @@ func (s *Service) SetRedistributionAgent(redistributionAgent *storageincentives.
 }
 
 type Options struct {
-	CORSAllowedOrigins []string
-	WsPingPeriod       time.Duration
-	Restricted         bool
+	CORSAllowedOrigins   []string
+	WsPingPeriod         time.Duration
+	Restricted           bool
+	MaxDirUploadFileSize int64
+	// CompressibleContentTypes are the content types which are compressed
+	// on download if the client accepts it, no content is compressed if empty.
+	CompressibleContentTypes []string
+	// Tenants are the applications the node is shared by, in the restricted mode.
+	Tenants []Tenant
+	// WebDAV enables the WebDAV access to the manifests on /webdav.
+	WebDAV bool
+	// WebDAVPostageBatch stamps the changes of the feed manifests owned by
+	// the node made over WebDAV, all manifests are read-only if it is empty.
+	WebDAVPostageBatch []byte
+	// S3PostageBatch stamps the objects stored over the S3 API,
+	// all buckets are read-only if it is empty.
+	S3PostageBatch []byte
+	// Gateway enables the read-only public gateway profile, which forbids
+	// the mutating endpoints and rate limits the clients to the sustained
+	// GatewayRateLimit requests per second in the bursts of the
+	// GatewayRateLimitBurst requests, the defaults are used if zero.
+	Gateway               bool
+	GatewayRateLimit      int
+	GatewayRateLimitBurst int
+	// SourceURLMaxSize is the maximal size of the resource the node fetches
+	// from the source URL of the bzz upload, which is disabled if zero.
+	// Only the SourceURLSchemes are fetched, https if empty, and the private
+	// addresses are refused unless SourceURLAllowPrivate is set.
+	SourceURLMaxSize      int64
+	SourceURLSchemes      []string
+	SourceURLAllowPrivate bool
 }
 
 type ExtraOptions struct {
@@ -219,6 +329,7 @@ type ExtraOptions struct {
 	Pseudosettle     settlement.Interface
 	Swap             swap.Interface
 	Chequebook       chequebook.Service
+	Withdrawal       WithdrawalPlanner
 	BlockTime        time.Duration
 	Tags             *tags.Tags
 	Storer           storage.Storer
@@ -231,13 +342,39 @@ type ExtraOptions struct {
 	PostageContract  postagecontract.Interface
 	Staking          staking.Contract
 	Steward          steward.Interface
+	Handoff          handoff.Interface
+	SelfTest         selftest.Interface
+	FeedValidator    validation.Validator
 	SyncStatus       func() (bool, error)
 	IndexDebugger    StorageIndexDebugger
+	Reserve          ReserveReporter
+	ReserveChecker   ReserveChecker
+	ReserveRepairer  ReserveRepairer
+	Syncer           SyncReporter
+	Status           StatusService
+	AuditLog         *audit.Log
+	BatchEvents      *postage.BatchEventFeed
+	StateStorer      storage.StateStorer
+	Receipts         *receipts.Store
+	Denylist         *denylist.List
+	Profitability    *profitability.Ledger
+	Earnings         EarningsReporter
+	Billing          BillingSnapshotter
+	Prewarm          *prewarm.Service
+	WorkingSet       *workingset.Service
+	Availability     *availability.Service
+	ClockSkew        *clockskew.Detector
+	IPFS             ipfs.Fetcher
+	Webhooks         *webhook.Service
+	ContentStats     *contentstats.Service
 }
 
 func New(publicKey, pssPublicKey ecdsa.PublicKey, ethereumAddress common.Address, logger log.Logger, transaction transaction.Service, batchStore postage.Storer, beeMode BeeNodeMode, chequebookEnabled, swapEnabled bool, chainBackend transaction.Backend, cors []string) *Service {
 	s := new(Service)
 
+	s.idempotencyInflight = make(map[string]struct{})
+	s.chunkTraces = newChunkTraces()
+	s.webdavLocks = dav.NewMemLS()
 	s.CORSAllowedOrigins = cors
 	s.beeMode = beeMode
 	s.logger = logger.WithName(loggerName).Register()
@@ -293,13 +430,53 @@ func (s *Service) Configure(signer crypto.Signer, auth auth.Authenticator, trace
 	s.post = e.Post
 	s.postageContract = e.PostageContract
 	s.steward = e.Steward
+	s.handoff = e.Handoff
+	s.selfTest = e.SelfTest
+	s.feedValidator = e.FeedValidator
 	s.stakingContract = e.Staking
 	s.indexDebugger = e.IndexDebugger
+	s.reserve = e.Reserve
+	s.reserveChecker = e.ReserveChecker
+	s.reserveRepairer = e.ReserveRepairer
+	s.syncer = e.Syncer
+	s.statusService = e.Status
+	s.auditLog = e.AuditLog
+	s.batchEvents = e.BatchEvents
+	s.stateStore = e.StateStorer
+	s.receipts = e.Receipts
+	s.denylist = e.Denylist
+	s.profitability = e.Profitability
+	s.earnings = e.Earnings
+	s.billing = e.Billing
+	s.prewarm = e.Prewarm
+	s.workingSet = e.WorkingSet
+	s.availability = e.Availability
+	s.clockSkew = e.ClockSkew
+	s.ipfs = e.IPFS
+	s.webhooks = e.Webhooks
+	s.contentStats = e.ContentStats
+
+	if o.Gateway {
+		s.gatewayLimiter = newGatewayLimiter(o.GatewayRateLimit, o.GatewayRateLimitBurst)
+	}
+
+	if o.SourceURLMaxSize > 0 {
+		if len(o.SourceURLSchemes) == 0 {
+			s.SourceURLSchemes = []string{"https"}
+		}
+		s.sourceClient = newSourceClient(s.SourceURLSchemes, o.SourceURLAllowPrivate)
+	}
+
+	if len(o.Tenants) > 0 {
+		s.tenants = newTenants(o.Tenants)
+		s.pinning = &tenantPinning{Interface: e.Pinning, store: e.StateStorer, tenants: s.tenants}
+	}
 
 	s.pingpong = e.Pingpong
 	s.topologyDriver = e.TopologyDriver
 	s.accounting = e.Accounting
 	s.chequebook = e.Chequebook
+	s.withdrawal = e.Withdrawal
 	s.swap = e.Swap
 	s.lightNodes = e.LightNodes
 	s.pseudosettle = e.Pseudosettle
@@ -353,25 +530,25 @@ func (s *Service) Close() error {
 
 // getOrCreateTag attempts to get the tag if an id is supplied, and returns an error if it does not exist.
 // If no id is supplied, it will attempt to create a new tag with a generated name and return it.
-func (s *Service) getOrCreateTag(tagUid string) (*tags.Tag, bool, error) {
+func (s *Service) getOrCreateTag(ctx context.Context, tagUid string) (*tags.Tag, bool, error) {
 	// if tag ID is not supplied, create a new tag
 	if tagUid == "" {
-		tag, err := s.tags.Create(0)
+		tag, err := s.createTag(ctx)
 		if err != nil {
 			return nil, false, fmt.Errorf("cannot create tag: %w", err)
 		}
 		return tag, true, nil
 	}
-	t, err := s.getTag(tagUid)
+	t, err := s.getTag(ctx, tagUid)
 	return t, false, err
 }
 
-func (s *Service) getTag(tagUid string) (*tags.Tag, error) {
+func (s *Service) getTag(ctx context.Context, tagUid string) (*tags.Tag, error) {
 	uid, err := strconv.Atoi(tagUid)
 	if err != nil {
 		return nil, fmt.Errorf("cannot mapStructure taguid: %w", err)
 	}
-	return s.tags.Get(uint32(uid))
+	return s.lookupTag(ctx, uint32(uid))
 }
 
 func (s *Service) resolveNameOrAddress(str string) (swarm.Address, error) {
@@ -414,6 +591,29 @@ func requestEncrypt(r *http.Request) bool {
 	return strings.ToLower(r.Header.Get(SwarmEncryptHeader)) == boolHeaderSetValue
 }
 
+// requestRootNeighbourhood reports whether the root chunks
+// of the upload are placed within the neighbourhood of the node.
+func requestRootNeighbourhood(r *http.Request) bool {
+	return strings.ToLower(r.Header.Get(SwarmRootNeighbourhoodHeader)) == boolHeaderSetValue
+}
+
+// requestEncryptPadding returns the block size the encrypted
+// content is padded to or zero if the content is not padded.
+func requestEncryptPadding(r *http.Request) (int64, error) {
+	h := r.Header.Get(SwarmEncryptPaddingHeader)
+	if h == "" || !requestEncrypt(r) {
+		return 0, nil
+	}
+	blockSize, err := strconv.ParseInt(h, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if err := padding.ValidateBlockSize(blockSize); err != nil {
+		return 0, err
+	}
+	return blockSize, nil
+}
+
 func requestDeferred(r *http.Request) (bool, error) {
 	if h := strings.ToLower(r.Header.Get(SwarmDeferredUploadHeader)); h != "" {
 		return strconv.ParseBool(h)
@@ -422,7 +622,11 @@ func requestDeferred(r *http.Request) (bool, error) {
 }
 
 func requestPostageBatchId(r *http.Request) ([]byte, error) {
-	if h := strings.ToLower(r.Header.Get(SwarmPostageBatchIdHeader)); h != "" {
+	return parsePostageBatchIdHeader(r, SwarmPostageBatchIdHeader)
+}
+
+func parsePostageBatchIdHeader(r *http.Request, header string) ([]byte, error) {
+	if h := strings.ToLower(r.Header.Get(header)); h != "" {
 		if len(h) != 64 {
 			return nil, errInvalidPostageBatch
 		}
@@ -442,6 +646,7 @@ type securityTokenRsp struct {
 
 type securityTokenReq struct {
 	Role   string `json:"role"`
+	Tenant string `json:"tenant,omitempty"`
 	Expiry int    `json:"expiry"` // duration in seconds
 }
 
@@ -478,7 +683,14 @@ func (s *Service) authHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	key, err := s.auth.GenerateKey(payload.Role, time.Duration(payload.Expiry)*time.Second)
+	if _, ok := s.tenants[payload.Tenant]; payload.Tenant != "" && !ok {
+		s.logger.Debug("auth handler: unknown tenant", "tenant", payload.Tenant)
+		s.logger.Error(nil, "auth handler: unknown tenant")
+		jsonhttp.BadRequest(w, "Unknown tenant")
+		return
+	}
+
+	key, err := s.auth.GenerateKey(payload.Role, payload.Tenant, time.Duration(payload.Expiry)*time.Second)
 	if errors.Is(err, auth.ErrExpiry) {
 		s.logger.Debug("auth handler: generate key failed", "error", err)
 		s.logger.Error(nil, "auth handler: generate key failed")
@@ -561,6 +773,10 @@ func (s *Service) newTracingHandler(spanName string) func(h http.Handler) http.H
 			span, _, ctx := s.tracer.StartSpanFromContext(ctx, spanName, s.logger)
 			defer span.Finish()
 
+			if traceID, ok := r.Context().Value(traceIDKey{}).(*string); ok {
+				*traceID = tracing.TraceID(ctx)
+			}
+
 			err = s.tracer.AddContextHTTPHeader(ctx, r.Header)
 			if err != nil {
 				s.logger.Debug("inject tracing context failed", "span_name", spanName, "error", err)
@@ -642,7 +858,7 @@ func (s *Service) corsHandler(h http.Handler) http.Handler {
 		if o := r.Header.Get("Origin"); o != "" && s.checkOrigin(r) {
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 			w.Header().Set("Access-Control-Allow-Origin", o)
-			w.Header().Set("Access-Control-Allow-Headers", "User-Agent, Origin, Accept, Authorization, Content-Type, X-Requested-With, Decompressed-Content-Length, Access-Control-Request-Headers, Access-Control-Request-Method, Swarm-Tag, Swarm-Pin, Swarm-Encrypt, Swarm-Index-Document, Swarm-Error-Document, Swarm-Collection, Swarm-Postage-Batch-Id, Swarm-Deferred-Upload, Gas-Price, Range, Accept-Ranges, Content-Encoding")
+			w.Header().Set("Access-Control-Allow-Headers", "User-Agent, Origin, Accept, Authorization, Content-Type, X-Requested-With, Decompressed-Content-Length, Access-Control-Request-Headers, Access-Control-Request-Method, Swarm-Tag, Swarm-Pin, Swarm-Encrypt, Swarm-Encrypt-Padding, Swarm-Unpadded-Length, Swarm-Index-Document, Swarm-Error-Document, Swarm-Collection, Swarm-Postage-Batch-Id, Swarm-Deferred-Upload, Gas-Price, Range, Accept-Ranges, Content-Encoding, Idempotency-Key, Swarm-Api-Version, Swarm-Trace, If-Feed-Index")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS, POST, PUT, DELETE")
 			w.Header().Set("Access-Control-Max-Age", "3600")
 		}
@@ -787,6 +1003,9 @@ func (s *Service) newStamperPutter(r *http.Request) (storage.Storer, func() erro
 	if err != nil {
 		return nil, noopWaitFn, fmt.Errorf("postage batch id: %w", err)
 	}
+	if !requestTenant(r.Context()).allowsBatch(batch) {
+		return nil, noopWaitFn, errBatchNotAllowed
+	}
 
 	deferred, err := requestDeferred(r) // TODO: extrapolate the headers parsing to the handler level!
 	if err != nil {
@@ -796,25 +1015,43 @@ func (s *Service) newStamperPutter(r *http.Request) (storage.Storer, func() erro
 	if !deferred && s.beeMode == DevMode {
 		return nil, noopWaitFn, errUnsupportedDevNodeOperation
 	}
-	exists, err := s.batchStore.Exists(batch)
-	if err != nil {
-		return nil, noopWaitFn, fmt.Errorf("batch exists: %w", err)
+
+	// the stamps timestamped by the skewed clock are rejected by the other nodes
+	if s.clockSkew != nil {
+		if err := s.clockSkew.Check(); err != nil {
+			return nil, noopWaitFn, err
+		}
 	}
 
-	issuer, save, err := s.post.GetStampIssuer(batch)
+	stamper, save, err := s.batchStamper(batch)
 	if err != nil {
-		return nil, noopWaitFn, fmt.Errorf("stamp issuer: %w", err)
+		return nil, noopWaitFn, err
 	}
 
-	if usable := exists && s.post.IssuerUsable(issuer); !usable {
-		return nil, noopWaitFn, errBatchUnusable
+	if r.Header.Get(SwarmPostageFallbackBatchIdHeader) != "" {
+		fallbackBatch, err := parsePostageBatchIdHeader(r, SwarmPostageFallbackBatchIdHeader)
+		if err != nil {
+			return nil, noopWaitFn, fmt.Errorf("fallback postage batch id: %w", err)
+		}
+		if !requestTenant(r.Context()).allowsBatch(fallbackBatch) {
+			return nil, noopWaitFn, fmt.Errorf("fallback postage batch id: %w", errBatchNotAllowed)
+		}
+		fallback, fallbackSave, err := s.batchStamper(fallbackBatch)
+		if err != nil {
+			return nil, noopWaitFn, fmt.Errorf("fallback batch: %w", err)
+		}
+		stamper = postage.NewFallbackStamper(stamper, fallback)
+		primarySave := save
+		save = func() error {
+			return multierror.Append(primarySave(), fallbackSave()).ErrorOrNil()
+		}
 	}
 
 	if deferred {
-		p := newStoringStamperPutter(s.storer, issuer, s.signer)
+		p := newStoringStamperPutter(s.storer, stamper)
 		return p, save, nil
 	}
-	p := newPushStamperPutter(s.storer, issuer, s.signer, s.chunkPushC)
+	p := newPushStamperPutter(s.storer, stamper, s.chunkPushC)
 
 	wait := func() error {
 		if err := save(); err != nil {
@@ -826,6 +1063,58 @@ func (s *Service) newStamperPutter(r *http.Request) (storage.Storer, func() erro
 	return p, wait, err
 }
 
+// bucketFullResponse is returned when a chunk cannot be
+// stamped because its collision bucket of the batch is full.
+type bucketFullResponse struct {
+	Code             int     `json:"code"`
+	Message          string  `json:"message"`
+	BatchID          hexByte `json:"batchID,omitempty"`
+	Bucket           *uint32 `json:"bucket,omitempty"`
+	Depth            uint8   `json:"depth,omitempty"`
+	BucketDepth      uint8   `json:"bucketDepth,omitempty"`
+	Utilization      uint32  `json:"utilization,omitempty"`
+	BucketUpperBound uint32  `json:"bucketUpperBound,omitempty"`
+	SuggestedDepth   uint8   `json:"suggestedDepth,omitempty"`
+}
+
+func newBucketFullResponse(err error) bucketFullResponse {
+	resp := bucketFullResponse{
+		Code:    http.StatusPaymentRequired,
+		Message: "batch is overissued",
+	}
+	var bfe *postage.BucketFullError
+	if errors.As(err, &bfe) {
+		resp.BatchID = bfe.BatchID
+		resp.Bucket = &bfe.Bucket
+		resp.Depth = bfe.Depth
+		resp.BucketDepth = bfe.BucketDepth
+		resp.Utilization = bfe.Utilization
+		resp.BucketUpperBound = bfe.UpperBound
+		resp.SuggestedDepth = bfe.SuggestedDepth()
+	}
+	return resp
+}
+
+// batchStamper returns the stamper issuing the stamps from the usable batch
+// and the function which saves the state of its stamp issuer.
+func (s *Service) batchStamper(batch []byte) (postage.Stamper, func() error, error) {
+	exists, err := s.batchStore.Exists(batch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("batch exists: %w", err)
+	}
+
+	issuer, save, err := s.post.GetStampIssuer(batch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("stamp issuer: %w", err)
+	}
+
+	if usable := exists && s.post.IssuerUsable(issuer); !usable {
+		return nil, nil, errBatchUnusable
+	}
+
+	return postage.NewStamper(issuer, s.signer), save, nil
+}
+
 type pushStamperPutter struct {
 	storage.Storer
 	stamper postage.Stamper
@@ -834,8 +1123,7 @@ type pushStamperPutter struct {
 	sem     chan struct{}
 }
 
-func newPushStamperPutter(s storage.Storer, i *postage.StampIssuer, signer crypto.Signer, cc chan *pusher.Op) *pushStamperPutter {
-	stamper := postage.NewStamper(i, signer)
+func newPushStamperPutter(s storage.Storer, stamper postage.Stamper, cc chan *pusher.Op) *pushStamperPutter {
 	return &pushStamperPutter{Storer: s, stamper: stamper, c: cc, sem: make(chan struct{}, uploadSem)}
 }
 
@@ -843,6 +1131,11 @@ func (p *pushStamperPutter) Wait() error {
 	return p.eg.Wait()
 }
 
+// BucketUtilization implements the postage.BucketUtilizer.
+func (p *pushStamperPutter) BucketUtilization(addr swarm.Address) postage.BucketUtilization {
+	return stamperBucketUtilization(p.stamper, addr)
+}
+
 func (p *pushStamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) (exists []bool, err error) {
 	exists = make([]bool, len(chs))
 
@@ -900,11 +1193,24 @@ type stamperPutter struct {
 	stamper postage.Stamper
 }
 
-func newStoringStamperPutter(s storage.Storer, i *postage.StampIssuer, signer crypto.Signer) *stamperPutter {
-	stamper := postage.NewStamper(i, signer)
+func newStoringStamperPutter(s storage.Storer, stamper postage.Stamper) *stamperPutter {
 	return &stamperPutter{Storer: s, stamper: stamper}
 }
 
+// BucketUtilization implements the postage.BucketUtilizer.
+func (p *stamperPutter) BucketUtilization(addr swarm.Address) postage.BucketUtilization {
+	return stamperBucketUtilization(p.stamper, addr)
+}
+
+// stamperBucketUtilization returns the utilization of the bucket of the
+// address reported by the stamper, the zero value if it is not reported.
+func stamperBucketUtilization(stamper postage.Stamper, addr swarm.Address) postage.BucketUtilization {
+	if u, ok := stamper.(postage.BucketUtilizer); ok {
+		return u.BucketUtilization(addr)
+	}
+	return postage.BucketUtilization{}
+}
+
 func (p *stamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) (exists []bool, err error) {
 	var (
 		ctp = make([]swarm.Chunk, 0, len(chs))
@@ -940,23 +1246,106 @@ func (p *stamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...sw
 	return exists, nil
 }
 
-type pipelineFunc func(context.Context, io.Reader) (swarm.Address, error)
+// pipelineFunc stores the content and returns its reference and the length
+// of the content without the padding, which must be recorded out of band,
+// or -1 if the content is not padded.
+type pipelineFunc func(context.Context, io.Reader) (swarm.Address, int64, error)
 
 func requestPipelineFn(s storage.Putter, r *http.Request) pipelineFunc {
 	mode, encrypt := requestModePut(r), requestEncrypt(r)
-	return func(ctx context.Context, r io.Reader) (swarm.Address, error) {
-		pipe := builder.NewPipelineBuilder(ctx, s, mode, encrypt)
-		return builder.FeedPipeline(ctx, pipe, r)
+	blockSize, paddingErr := requestEncryptPadding(r)
+	upcoming := requestCalculateNumberOfChunks(r)
+	return func(ctx context.Context, r io.Reader) (swarm.Address, int64, error) {
+		if paddingErr != nil {
+			return swarm.ZeroAddress, 0, paddingErr
+		}
+		pipe := newPipeline(ctx, s, mode, encrypt, upcoming)
+		if blockSize == 0 {
+			address, err := builder.FeedPipeline(ctx, pipe, r)
+			return address, -1, err
+		}
+		pipe, err := padding.NewWriter(pipe, blockSize)
+		if err != nil {
+			return swarm.ZeroAddress, 0, err
+		}
+		cr := &countingReader{r: r}
+		address, err := builder.FeedPipeline(ctx, pipe, cr)
+		return address, cr.n, err
 	}
 }
 
+// countingReader counts the bytes read.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
 func requestPipelineFactory(ctx context.Context, s storage.Putter, r *http.Request) func() pipeline.Interface {
 	mode, encrypt := requestModePut(r), requestEncrypt(r)
 	return func() pipeline.Interface {
-		return builder.NewPipelineBuilder(ctx, s, mode, encrypt)
+		return newPipeline(ctx, s, mode, encrypt, 0)
 	}
 }
 
+// newPipeline returns the pipeline of the upload, whose encrypted chunks are
+// balanced across the stamp buckets if the putter reports their utilization,
+// given the number of the upcoming chunks, zero if not known. The encrypted
+// root chunks are placed within the neighbourhood of the context, if any.
+func newPipeline(ctx context.Context, s storage.Putter, mode storage.ModePut, encrypt bool, upcoming int64) pipeline.Interface {
+	u, balanced := s.(postage.BucketUtilizer)
+	if n, ok := ctx.Value(rootNeighbourhoodContextKey{}).(rootNeighbourhood); ok && encrypt {
+		var b *encryption.Balancer
+		if balanced {
+			b = encryption.NewBalancer(u, upcoming)
+		}
+		return builder.NewNeighbourhoodEncryptionPipelineBuilder(ctx, s, mode, encryption.NewNeighbourhood(n.overlay, n.depth), b)
+	}
+	if balanced && encrypt {
+		return builder.NewBalancedEncryptionPipelineBuilder(ctx, s, mode, encryption.NewBalancer(u, upcoming))
+	}
+	return builder.NewPipelineBuilder(ctx, s, mode, encrypt)
+}
+
+type rootNeighbourhoodContextKey struct{}
+
+// rootNeighbourhood is the neighbourhood stored by the node.
+type rootNeighbourhood struct {
+	overlay swarm.Address
+	depth   uint8
+}
+
+// rootNeighbourhoodHandler places the root chunks of the encrypted uploads
+// requesting it within the neighbourhood of the storage radius of the node,
+// so that the node itself keeps the chunks needed to retrieve the content.
+func (s *Service) rootNeighbourhoodHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !requestRootNeighbourhood(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if !requestEncrypt(r) {
+			jsonhttp.BadRequest(w, "root neighbourhood requires encryption")
+			return
+		}
+		if s.beeMode != FullMode || s.overlay == nil {
+			jsonhttp.BadRequest(w, errOperationSupportedOnlyInFullMode)
+			return
+		}
+		n := rootNeighbourhood{
+			overlay: *s.overlay,
+			depth:   s.batchStore.GetReserveState().StorageRadius,
+		}
+		s.logger.Debug("placing root chunks within the neighbourhood", "path", r.URL.Path, "depth", n.depth)
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), rootNeighbourhoodContextKey{}, n)))
+	})
+}
+
 // calculateNumberOfChunks calculates the number of chunks in an arbitrary
 // content length.
 func calculateNumberOfChunks(contentLength int64, isEncrypted bool) int64 {
@@ -28,16 +28,25 @@ import (
 	mock2 "github.com/ethersphere/bee/pkg/storageincentives/staking/mock"
 	"github.com/ethersphere/bee/pkg/transaction"
 	"github.com/ethersphere/bee/pkg/util/testutil"
+	"github.com/ethersphere/bee/pkg/webhook"
 
 	"github.com/ethereum/go-ethereum/common"
 	accountingmock "github.com/ethersphere/bee/pkg/accounting/mock"
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/audit"
 	"github.com/ethersphere/bee/pkg/auth"
 	mockauth "github.com/ethersphere/bee/pkg/auth/mock"
+	"github.com/ethersphere/bee/pkg/availability"
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/contentstats"
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/denylist"
 	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/validation"
 	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/handoff"
+	"github.com/ethersphere/bee/pkg/ipfs"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
 	p2pmock "github.com/ethersphere/bee/pkg/p2p/mock"
@@ -47,10 +56,14 @@ import (
 	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
+	"github.com/ethersphere/bee/pkg/prewarm"
+	"github.com/ethersphere/bee/pkg/profitability"
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/pusher"
+	"github.com/ethersphere/bee/pkg/receipts"
 	"github.com/ethersphere/bee/pkg/resolver"
 	resolverMock "github.com/ethersphere/bee/pkg/resolver/mock"
+	"github.com/ethersphere/bee/pkg/selftest"
 	"github.com/ethersphere/bee/pkg/settlement/pseudosettle"
 	chequebookmock "github.com/ethersphere/bee/pkg/settlement/swap/chequebook/mock"
 	erc20mock "github.com/ethersphere/bee/pkg/settlement/swap/erc20/mock"
@@ -69,6 +82,7 @@ import (
 	"github.com/ethersphere/bee/pkg/transaction/backendmock"
 	transactionmock "github.com/ethersphere/bee/pkg/transaction/mock"
 	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/workingset"
 	"github.com/gorilla/websocket"
 	"resenje.org/web"
 )
@@ -90,6 +104,18 @@ func init() {
 type testServerOptions struct {
 	Storer             storage.Storer
 	StateStorer        storage.StateStorer
+	Receipts           *receipts.Store
+	Denylist           *denylist.List
+	Profitability      *profitability.Ledger
+	Earnings           api.EarningsReporter
+	Billing            api.BillingSnapshotter
+	Prewarm            *prewarm.Service
+	WorkingSet         *workingset.Service
+	Availability       *availability.Service
+	ClockSkew          *clockskew.Detector
+	IPFS               ipfs.Fetcher
+	Webhooks           *webhook.Service
+	ContentStats       *contentstats.Service
 	Resolver           resolver.Interface
 	Pss                pss.Interface
 	Traversal          traversal.Traverser
@@ -105,6 +131,9 @@ type testServerOptions struct {
 	StakingContract    staking.Contract
 	Post               postage.Service
 	Steward            steward.Interface
+	Handoff            handoff.Interface
+	SelfTest           selftest.Interface
+	FeedValidator      validation.Validator
 	WsHeaders          http.Header
 	Authenticator      auth.Authenticator
 	DebugAPI           bool
@@ -112,6 +141,30 @@ type testServerOptions struct {
 	DirectUpload       bool
 	Probe              *api.Probe
 	IndexDebugger      api.StorageIndexDebugger
+	Reserve            api.ReserveReporter
+	ReserveChecker     api.ReserveChecker
+	ReserveRepairer    api.ReserveRepairer
+	Syncer             api.SyncReporter
+	Status             api.StatusService
+	Withdrawal         api.WithdrawalPlanner
+	AuditLog           *audit.Log
+	BatchEvents        *postage.BatchEventFeed
+
+	MaxDirUploadFileSize     int64
+	CompressibleContentTypes []string
+	Tenants                  []api.Tenant
+	WebDAV                   bool
+	WebDAVPostageBatch       []byte
+	S3                       bool
+	S3PostageBatch           []byte
+	PssGRPC                  bool
+	Gateway                  bool
+	GatewayRateLimit         int
+	GatewayRateLimitBurst    int
+	SourceURLMaxSize         int64
+	SourceURLSchemes         []string
+	SourceURLAllowPrivate    bool
+	Signer                   crypto.Signer
 
 	Overlay         swarm.Address
 	PublicKey       ecdsa.PublicKey
@@ -138,8 +191,10 @@ type testServerOptions struct {
 
 func newTestServer(t *testing.T, o testServerOptions) (*http.Client, *websocket.Conn, string, *chanStorer) {
 	t.Helper()
-	pk, _ := crypto.GenerateSecp256k1Key()
-	signer := crypto.NewDefaultSigner(pk)
+	if o.Signer == nil {
+		pk, _ := crypto.GenerateSecp256k1Key()
+		o.Signer = crypto.NewDefaultSigner(pk)
+	}
 
 	if o.Logger == nil {
 		o.Logger = log.Noop
@@ -204,9 +259,33 @@ func newTestServer(t *testing.T, o testServerOptions) (*http.Client, *websocket.
 		Post:             o.Post,
 		PostageContract:  o.PostageContract,
 		Steward:          o.Steward,
+		Handoff:          o.Handoff,
+		SelfTest:         o.SelfTest,
+		FeedValidator:    o.FeedValidator,
 		SyncStatus:       o.SyncStatus,
 		Staking:          o.StakingContract,
 		IndexDebugger:    o.IndexDebugger,
+		Reserve:          o.Reserve,
+		ReserveChecker:   o.ReserveChecker,
+		ReserveRepairer:  o.ReserveRepairer,
+		Syncer:           o.Syncer,
+		Status:           o.Status,
+		Withdrawal:       o.Withdrawal,
+		AuditLog:         o.AuditLog,
+		BatchEvents:      o.BatchEvents,
+		StateStorer:      o.StateStorer,
+		Receipts:         o.Receipts,
+		Denylist:         o.Denylist,
+		Profitability:    o.Profitability,
+		Earnings:         o.Earnings,
+		Billing:          o.Billing,
+		Prewarm:          o.Prewarm,
+		WorkingSet:       o.WorkingSet,
+		Availability:     o.Availability,
+		ClockSkew:        o.ClockSkew,
+		IPFS:             o.IPFS,
+		Webhooks:         o.Webhooks,
+		ContentStats:     o.ContentStats,
 	}
 
 	// By default bee mode is set to full mode.
@@ -233,15 +312,27 @@ func newTestServer(t *testing.T, o testServerOptions) (*http.Client, *websocket.
 	})
 	testutil.CleanupCloser(t, tracerCloser)
 
-	chC := s.Configure(signer, o.Authenticator, noOpTracer, api.Options{
-		CORSAllowedOrigins: o.CORSAllowedOrigins,
-		WsPingPeriod:       o.WsPingPeriod,
-		Restricted:         o.Restricted,
+	chC := s.Configure(o.Signer, o.Authenticator, noOpTracer, api.Options{
+		CORSAllowedOrigins:       o.CORSAllowedOrigins,
+		WsPingPeriod:             o.WsPingPeriod,
+		Restricted:               o.Restricted,
+		MaxDirUploadFileSize:     o.MaxDirUploadFileSize,
+		CompressibleContentTypes: o.CompressibleContentTypes,
+		Tenants:                  o.Tenants,
+		WebDAV:                   o.WebDAV,
+		WebDAVPostageBatch:       o.WebDAVPostageBatch,
+		S3PostageBatch:           o.S3PostageBatch,
+		Gateway:                  o.Gateway,
+		GatewayRateLimit:         o.GatewayRateLimit,
+		GatewayRateLimitBurst:    o.GatewayRateLimitBurst,
+		SourceURLMaxSize:         o.SourceURLMaxSize,
+		SourceURLSchemes:         o.SourceURLSchemes,
+		SourceURLAllowPrivate:    o.SourceURLAllowPrivate,
 	}, extraOpts, 1, erc20)
 
 	if o.DebugAPI {
-		s.MountTechnicalDebug()
-		s.MountDebug(false)
+		s.MountTechnicalDebug(o.Restricted)
+		s.MountDebug(o.Restricted)
 	} else {
 		s.MountAPI()
 	}
@@ -251,9 +342,24 @@ func newTestServer(t *testing.T, o testServerOptions) (*http.Client, *websocket.
 		t.Cleanup(chanStore.stop)
 	}
 
-	ts := httptest.NewServer(s)
+	var handler http.Handler = s
+	if o.S3 {
+		handler = s.S3Handler()
+	}
+	ts := httptest.NewServer(handler)
 	t.Cleanup(ts.Close)
 
+	if o.PssGRPC {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv := s.PssGRPCServer()
+		go func() { _ = srv.Serve(l) }()
+		t.Cleanup(srv.Stop)
+		return nil, nil, l.Addr().String(), nil
+	}
+
 	var (
 		httpClient = &http.Client{
 			Transport: web.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
@@ -466,12 +572,11 @@ func TestPostageHeaderError(t *testing.T) {
 
 	var (
 		mockStorer      = mock.NewStorer()
-		mockStatestore  = statestore.NewStateStore()
 		logger          = log.Noop
 		mp              = mockpost.New(mockpost.WithIssuer(postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)))
 		client, _, _, _ = newTestServer(t, testServerOptions{
 			Storer: mockStorer,
-			Tags:   tags.NewTags(mockStatestore, logger),
+			Tags:   tags.NewTags(nil, logger),
 			Logger: logger,
 			Post:   mp,
 		})
@@ -577,7 +682,7 @@ func TestPostageDirectAndDeferred_FLAKY(t *testing.T) {
 
 	options := testServerOptions{
 		Storer: mock.NewStorer(),
-		Tags:   tags.NewTags(statestore.NewStateStore(), log.Noop),
+		Tags:   tags.NewTags(nil, log.Noop),
 		Logger: log.Noop,
 		Post: mockpost.New(mockpost.WithIssuer(postage.NewStampIssuer(
 			"",
@@ -744,7 +849,7 @@ func createRedistributionAgentService(t *testing.T, addr swarm.Address, storer s
 	}))
 	contract := &mockContract{}
 
-	return storageincentives.New(addr, common.Address{}, backend, log.Noop, &mockMonitor{}, contract, postageContract, stakingContract, mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{StorageRadius: 0})), &mockSampler{t: t}, time.Millisecond*10, blocksPerRound, blocksPerPhase, storer, erc20Service, tranService)
+	return storageincentives.New(addr, common.Address{}, backend, log.Noop, &mockMonitor{}, contract, postageContract, stakingContract, mockbatchstore.New(mockbatchstore.WithReserveState(&postage.ReserveState{StorageRadius: 0})), &mockSampler{t: t}, time.Millisecond*10, blocksPerRound, blocksPerPhase, storer, erc20Service, tranService, nil)
 }
 
 type contractCall int
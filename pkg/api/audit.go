@@ -0,0 +1,137 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/audit"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+// auditHandler records the state-changing API calls in the audit log.
+func (s *Service) auditHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.auditLog == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		wrapper := newResponseWriter(w)
+		h.ServeHTTP(wrapper, r)
+
+		err := s.auditLog.Write(audit.Record{
+			Time:       start,
+			Caller:     auditCaller(r),
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			ParamsHash: auditParamsHash(r),
+			Status:     wrapper.statusCode,
+			Duration:   time.Since(start),
+		})
+		if err != nil {
+			s.logger.Debug("audit log write failed", "error", err)
+			s.logger.Error(nil, "audit log write failed")
+		}
+	})
+}
+
+// auditCaller identifies the caller by its remote host and,
+// if present, by the fingerprint of its security token.
+func auditCaller(r *http.Request) string {
+	caller := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		caller = host
+	}
+	if token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); token != "" {
+		sum := sha256.Sum256([]byte(token))
+		caller += "/" + hex.EncodeToString(sum[:8])
+	}
+	return caller
+}
+
+// auditParamsHash hashes the parameters of the call, that are
+// the path, the query and the swarm headers, leaving out the body.
+func auditParamsHash(r *http.Request) string {
+	var keys []string
+	for k := range r.Header {
+		if strings.HasPrefix(k, "Swarm-") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	_, _ = h.Write([]byte(r.Method + "\n" + r.URL.Path + "\n" + r.URL.RawQuery + "\n"))
+	for _, k := range keys {
+		_, _ = h.Write([]byte(k + ":" + strings.Join(r.Header.Values(k), ",") + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type auditResponse struct {
+	Records []audit.Record `json:"records"`
+}
+
+func (s *Service) auditGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_audit").Build()
+
+	queries := struct {
+		Method string `map:"method"`
+		Path   string `map:"path"`
+		Caller string `map:"caller"`
+		Since  int64  `map:"since"`
+		Until  int64  `map:"until"`
+		Limit  int    `map:"limit" validate:"min=0"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	if s.auditLog == nil {
+		jsonhttp.NotImplemented(w, "audit log not enabled")
+		return
+	}
+
+	filter := audit.Filter{
+		Method:     queries.Method,
+		PathPrefix: queries.Path,
+		Caller:     queries.Caller,
+		Limit:      queries.Limit,
+	}
+	if queries.Since > 0 {
+		filter.Since = time.Unix(queries.Since, 0)
+	}
+	if queries.Until > 0 {
+		filter.Until = time.Unix(queries.Until, 0)
+	}
+
+	records, err := s.auditLog.Query(filter)
+	if err != nil {
+		logger.Debug("audit log query failed", "error", err)
+		logger.Error(nil, "audit log query failed")
+		jsonhttp.InternalServerError(w, "audit log query failed")
+		return
+	}
+	if records == nil {
+		records = []audit.Record{}
+	}
+
+	jsonhttp.OK(w, auditResponse{Records: records})
+}
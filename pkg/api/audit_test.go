@@ -0,0 +1,88 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/audit"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+)
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records state-changing calls", func(t *testing.T) {
+		t.Parallel()
+
+		auditLog, err := audit.New(filepath.Join(t.TempDir(), "audit.log"), audit.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = auditLog.Close() })
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			AuditLog: auditLog,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodPost, "/pingpong/invalid", http.StatusBadRequest)
+		jsonhttptest.Request(t, ts, http.MethodGet, "/reservestate", http.StatusOK)
+		jsonhttptest.Request(t, ts, http.MethodDelete, "/peers/invalid", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader("Authorization", "Bearer token"),
+		)
+
+		var resp api.AuditResponse
+		jsonhttptest.Request(t, ts, http.MethodGet, "/audit", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		if len(resp.Records) != 2 {
+			t.Fatalf("got %d records, want 2", len(resp.Records))
+		}
+		for i, want := range []struct {
+			method string
+			path   string
+		}{
+			{http.MethodPost, "/pingpong/invalid"},
+			{http.MethodDelete, "/peers/invalid"},
+		} {
+			r := resp.Records[i]
+			if r.Method != want.method || r.Path != want.path || r.Status != http.StatusBadRequest {
+				t.Fatalf("record %d: got %+v, want %s %s", i, r, want.method, want.path)
+			}
+			if r.ParamsHash == "" {
+				t.Fatalf("record %d: missing parameters hash", i)
+			}
+		}
+		if resp.Records[0].Caller == resp.Records[1].Caller {
+			t.Fatalf("expected the security token to identify the caller, got %q", resp.Records[1].Caller)
+		}
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/audit?method=delete", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.AuditResponse{
+				Records: resp.Records[1:],
+			}),
+		)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/audit", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotImplemented,
+				Message: "audit log not enabled",
+			}),
+		)
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/availability"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+// defaultAvailabilityHours is the default number of the
+// hours the availability statistics are returned for.
+const defaultAvailabilityHours = 24
+
+type availabilityNeighbourhood struct {
+	Prefix  string `json:"prefix"`
+	Success uint64 `json:"success"`
+	Failure uint64 `json:"failure"`
+}
+
+type availabilityWindow struct {
+	Start          time.Time                   `json:"start"`
+	Neighbourhoods []availabilityNeighbourhood `json:"neighbourhoods"`
+}
+
+type availabilityResponse struct {
+	Depth    int                  `json:"depth"`
+	Interval int64                `json:"interval"` // duration of the windows in seconds
+	Windows  []availabilityWindow `json:"windows"`
+}
+
+// availabilityGetHandler returns the heatmap of the outcomes of the
+// retrievals of the chunks requested by this node by the neighbourhood
+// of the chunk, in the hourly windows.
+func (s *Service) availabilityGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_availability").Build()
+
+	queries := struct {
+		Hours *int `map:"hours" validate:"omitempty,min=1,max=168"`
+		Depth *int `map:"depth" validate:"omitempty,min=1,max=8"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	hours, depth := defaultAvailabilityHours, availability.MaxDepth
+	if queries.Hours != nil {
+		hours = *queries.Hours
+	}
+	if queries.Depth != nil {
+		depth = *queries.Depth
+	}
+
+	windows, err := s.availability.Windows(time.Now().Add(-time.Duration(hours-1) * availability.Interval))
+	if err != nil {
+		logger.Debug("get availability windows failed", "error", err)
+		logger.Error(nil, "get availability windows failed")
+		jsonhttp.InternalServerError(w, "get availability failed")
+		return
+	}
+
+	res := availabilityResponse{
+		Depth:    depth,
+		Interval: int64(availability.Interval / time.Second),
+		Windows:  make([]availabilityWindow, 0, len(windows)),
+	}
+	for _, win := range windows {
+		// the neighbourhoods are merged into the ones of the requested depth
+		merged := make(map[uint8]availability.Counts)
+		for n, c := range win.Neighbourhoods {
+			n >>= availability.MaxDepth - depth
+			v := merged[n]
+			v.Success += c.Success
+			v.Failure += c.Failure
+			merged[n] = v
+		}
+		neighbourhoods := make([]availabilityNeighbourhood, 0, len(merged))
+		for n, c := range merged {
+			neighbourhoods = append(neighbourhoods, availabilityNeighbourhood{
+				Prefix:  fmt.Sprintf("%0*b", depth, n),
+				Success: c.Success,
+				Failure: c.Failure,
+			})
+		}
+		sort.Slice(neighbourhoods, func(i, j int) bool {
+			return neighbourhoods[i].Prefix < neighbourhoods[j].Prefix
+		})
+		res.Windows = append(res.Windows, availabilityWindow{Start: win.Start, Neighbourhoods: neighbourhoods})
+	}
+	jsonhttp.OK(w, res)
+}
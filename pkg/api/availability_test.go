@@ -0,0 +1,85 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/availability"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestAvailability(t *testing.T) {
+	t.Parallel()
+
+	service := availability.New(statestore.NewStateStore(), log.Noop)
+	t.Cleanup(func() { _ = service.Close() })
+	for _, r := range []struct {
+		addr    string
+		success bool
+	}{
+		{"0a00000000000000000000000000000000000000000000000000000000000000", true},
+		{"0b00000000000000000000000000000000000000000000000000000000000000", false},
+		{"ff00000000000000000000000000000000000000000000000000000000000000", false},
+	} {
+		service.Record(swarm.MustParseHexAddress(r.addr), r.success)
+	}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:     true,
+		Availability: service,
+	})
+
+	t.Run("neighbourhoods", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.AvailabilityResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/availability", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Depth != 8 || res.Interval != int64(time.Hour/time.Second) || len(res.Windows) != 1 {
+			t.Fatalf("unexpected response %+v", res)
+		}
+		want := []api.AvailabilityNeighbourhood{
+			{Prefix: "00001010", Success: 1},
+			{Prefix: "00001011", Failure: 1},
+			{Prefix: "11111111", Failure: 1},
+		}
+		if got := res.Windows[0].Neighbourhoods; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+			t.Fatalf("got neighbourhoods %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("merged neighbourhoods", func(t *testing.T) {
+		t.Parallel()
+
+		var res api.AvailabilityResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/availability?depth=1", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		want := []api.AvailabilityNeighbourhood{
+			{Prefix: "0", Success: 1, Failure: 1},
+			{Prefix: "1", Failure: 1},
+		}
+		if len(res.Windows) != 1 {
+			t.Fatalf("got %d windows, want 1", len(res.Windows))
+		}
+		if got := res.Windows[0].Neighbourhoods; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Fatalf("got neighbourhoods %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("invalid depth", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/availability?depth=9", http.StatusBadRequest)
+	})
+}
@@ -9,11 +9,15 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/file/pipeline/hashtrie"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/sctx"
@@ -29,6 +33,20 @@ type bytesPostResponse struct {
 	Reference swarm.Address `json:"reference"`
 }
 
+// errResumeOffset is returned if the body of the resumed
+// upload is shorter than the offset of the checkpoint.
+var errResumeOffset = errors.New("content shorter than the checkpoint offset")
+
+// uploadCheckpoint is the persisted checkpoint of the upload of the tag.
+type uploadCheckpoint struct {
+	Encrypt bool `json:"encrypt"`
+	hashtrie.Checkpoint
+}
+
+func uploadCheckpointKey(uid uint32) string {
+	return fmt.Sprintf("upload_checkpoint_%d", uid)
+}
+
 // bytesUploadHandler handles upload of raw binary data of arbitrary length.
 func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_bytes").Build())
@@ -42,11 +60,48 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	queries := struct {
+		Resume uint32 `map:"resume"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	blockSize, err := requestEncryptPadding(r)
+	if err != nil {
+		logger.Debug("invalid encrypt padding", "error", err)
+		logger.Error(nil, "invalid encrypt padding")
+		jsonhttp.BadRequest(w, "invalid encrypt padding")
+		return
+	}
+	if queries.Resume != 0 && blockSize > 0 {
+		jsonhttp.BadRequest(w, "padded upload cannot be resumed")
+		return
+	}
+	rootNeighbourhood := requestEncrypt(r) && requestRootNeighbourhood(r)
+	if queries.Resume != 0 && rootNeighbourhood {
+		jsonhttp.BadRequest(w, "root neighbourhood upload cannot be resumed")
+		return
+	}
+	if queries.Resume != 0 {
+		headers.SwarmTag = fmt.Sprint(queries.Resume)
+	}
+
+	if err := requestContentChecksum(r); err != nil {
+		logger.Debug("invalid content checksum", "error", err)
+		logger.Error(nil, "invalid content checksum")
+		jsonhttp.BadRequest(w, errInvalidContentChecksum)
+		return
+	}
+
 	putter, wait, err := s.newStamperPutter(r)
 	if err != nil {
 		logger.Debug("get putter failed", "error", err)
 		logger.Error(nil, "get putter failed")
 		switch {
+		case errors.Is(err, errBatchNotAllowed):
+			jsonhttp.Forbidden(w, "batch not allowed")
 		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
 			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
 		case errors.Is(err, postage.ErrNotFound):
@@ -55,13 +110,15 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 			jsonhttp.BadRequest(w, "invalid batch id")
 		case errors.Is(err, errUnsupportedDevNodeOperation):
 			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		case errors.Is(err, clockskew.ErrClockSkewed):
+			jsonhttp.ServiceUnavailable(w, err.Error())
 		default:
 			jsonhttp.BadRequest(w, nil)
 		}
 		return
 	}
 
-	tag, created, err := s.getOrCreateTag(headers.SwarmTag)
+	tag, created, err := s.getOrCreateTag(r.Context(), headers.SwarmTag)
 	if err != nil {
 		logger.Debug("get or create tag failed", "error", err)
 		logger.Error(nil, "get or create tag failed")
@@ -89,7 +146,6 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Add the tag to the context
 	ctx := sctx.SetTag(r.Context(), tag)
-	p := requestPipelineFn(putter, r)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	pr := ioutil.TimeoutReader(ctx, r.Body, time.Minute, func(n uint64) {
@@ -97,21 +153,39 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 		logger.Debug("idle read timeout exceeded", "bytes_read", n)
 		cancel()
 	})
-	address, err := p(ctx, pr)
+	var (
+		address        swarm.Address
+		unpaddedLength int64 = -1
+	)
+	// the uploads with the tag supplied by the client are checkpointed,
+	// so that they can be resumed with the same tag if interrupted
+	if !created && blockSize == 0 && !rootNeighbourhood {
+		address, err = s.checkpointUpload(ctx, putter, r, tag.Uid, queries.Resume != 0, pr)
+	} else {
+		address, unpaddedLength, err = requestPipelineFn(putter, r)(ctx, pr)
+	}
 	if err != nil {
 		logger.Debug("split write all failed", "error", err)
 		logger.Error(nil, "split write all failed")
+		failUploadTag(logger, tag, tags.PhaseSplit, err)
 		switch {
 		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
+		case errors.Is(err, errResumeOffset):
+			jsonhttp.BadRequest(w, errResumeOffset)
+		case errors.Is(err, errContentChecksum):
+			jsonhttp.BadRequest(w, errContentChecksum)
 		default:
 			jsonhttp.InternalServerError(w, "split write all failed")
 		}
 		return
 	}
+	s.watchReceipts(logger, address)
+
 	if err = wait(); err != nil {
 		logger.Debug("sync chunks failed", "error", err)
 		logger.Error(nil, "sync chunks failed")
+		failUploadTag(logger, tag, tags.PhasePush, err)
 		jsonhttp.InternalServerError(w, "sync chunks failed")
 		return
 	}
@@ -130,6 +204,10 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 		if err := s.pinning.CreatePin(ctx, address, false); err != nil {
 			logger.Debug("pin creation failed", "address", address, "error", err)
 			logger.Error(nil, "pin creation failed")
+			if errors.Is(err, errPinQuotaExceeded) {
+				jsonhttp.Forbidden(w, "pin quota exceeded")
+				return
+			}
 			jsonhttp.InternalServerError(w, "create ping failed")
 			return
 		}
@@ -137,11 +215,59 @@ func (s *Service) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
 	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
+	if unpaddedLength >= 0 {
+		w.Header().Set(SwarmUnpaddedLengthHeader, strconv.FormatInt(unpaddedLength, 10))
+		w.Header().Add("Access-Control-Expose-Headers", SwarmUnpaddedLengthHeader)
+	}
 	jsonhttp.Created(w, bytesPostResponse{
 		Reference: address,
 	})
 }
 
+// checkpointUpload splits the content with the pipeline which persists its
+// checkpoints under the tag. If resume is set, the splitting continues from
+// the persisted checkpoint and the content preceding its offset is skipped.
+// The checkpoint is removed once the content is split.
+func (s *Service) checkpointUpload(ctx context.Context, putter storage.Putter, r *http.Request, uid uint32, resume bool, rd io.Reader) (swarm.Address, error) {
+	encrypt := requestEncrypt(r)
+	key := uploadCheckpointKey(uid)
+
+	var from *hashtrie.Checkpoint
+	if resume {
+		var c uploadCheckpoint
+		switch err := s.stateStore.Get(key, &c); {
+		case errors.Is(err, storage.ErrNotFound):
+		case err != nil:
+			return swarm.ZeroAddress, fmt.Errorf("get checkpoint: %w", err)
+		case c.Encrypt == encrypt:
+			from = &c.Checkpoint
+		}
+	}
+	if from != nil {
+		if _, err := io.CopyN(io.Discard, rd, from.Offset); err != nil {
+			if errors.Is(err, io.EOF) {
+				return swarm.ZeroAddress, errResumeOffset
+			}
+			return swarm.ZeroAddress, err
+		}
+	}
+
+	pipe, err := builder.NewCheckpointPipelineBuilder(ctx, putter, requestModePut(r), encrypt, from, func(c hashtrie.Checkpoint) error {
+		return s.stateStore.Put(key, uploadCheckpoint{Encrypt: encrypt, Checkpoint: c})
+	})
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	address, err := builder.FeedPipeline(ctx, pipe, rd)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	if err := s.stateStore.Delete(key); err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("delete checkpoint: %w", err)
+	}
+	return address, nil
+}
+
 // bytesGetHandler handles retrieval of raw binary data of arbitrary length.
 func (s *Service) bytesGetHandler(w http.ResponseWriter, r *http.Request) {
 	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("get_bytes_by_address").Build())
@@ -154,11 +280,25 @@ func (s *Service) bytesGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	headers := struct {
+		UnpaddedLength *uint64 `map:"Swarm-Unpadded-Length"`
+	}{}
+	if response := s.mapStructure(r.Header, &headers); response != nil {
+		response("invalid header params", logger, w)
+		return
+	}
+	size := int64(-1)
+	if headers.UnpaddedLength != nil {
+		size = int64(*headers.UnpaddedLength)
+	}
+
+	setContentStatsRoot(r.Context(), paths.Address)
+
 	additionalHeaders := http.Header{
 		"Content-Type": {"application/octet-stream"},
 	}
 
-	s.downloadHandler(logger, w, r, paths.Address, additionalHeaders, true)
+	s.downloadHandler(logger, w, r, paths.Address, size, additionalHeaders, true)
 }
 
 func (s *Service) bytesHeadHandler(w http.ResponseWriter, r *http.Request) {
@@ -172,6 +312,10 @@ func (s *Service) bytesHeadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.denied(logger, w, paths.Address) {
+		return
+	}
+
 	ch, err := s.storer.Get(r.Context(), storage.ModeGetRequest, paths.Address)
 	if err != nil {
 		logger.Debug("get root chunk failed", "chunk_address", paths.Address, "error", err)
@@ -8,22 +8,30 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/file/pipeline/hashtrie"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
 	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
+	"github.com/ethersphere/bee/pkg/postage"
 	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
 	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/util/testutil"
 	"gitlab.com/nolash/go-mockbytes"
 )
 
@@ -42,7 +50,7 @@ func TestBytes(t *testing.T) {
 		logger          = log.Noop
 		client, _, _, _ = newTestServer(t, testServerOptions{
 			Storer:  storerMock,
-			Tags:    tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Tags:    tags.NewTags(nil, log.Noop),
 			Pinning: pinningMock,
 			Logger:  logger,
 			Post:    mockpost.New(mockpost.WithAcceptAll()),
@@ -145,6 +153,50 @@ func TestBytes(t *testing.T) {
 		}
 	})
 
+	t.Run("upload-encrypted-padded", func(t *testing.T) {
+		const blockSize = 8 * swarm.ChunkSize
+
+		var res api.BytesPostResponse
+		header := jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmEncryptHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmEncryptPaddingHeader, strconv.Itoa(blockSize)),
+			jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if got, want := header.Get(api.SwarmUnpaddedLengthHeader), strconv.Itoa(len(content)); got != want {
+			t.Fatalf("got unpadded length %q, want %q", got, want)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/"+res.Reference.String(), http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmUnpaddedLengthHeader, strconv.Itoa(len(content))),
+			jsonhttptest.WithExpectedResponse(content),
+		)
+		// the padding is not stripped without the unpadded length
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/"+res.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(append(append([]byte{}, content...), make([]byte, blockSize-len(content))...)),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/"+res.Reference.String(), http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmUnpaddedLengthHeader, strconv.Itoa(blockSize+1)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid unpadded length",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmEncryptHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmEncryptPaddingHeader, "1000"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid encrypt padding",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
 	t.Run("internal error", func(t *testing.T) {
 		jsonhttptest.Request(t, client, http.MethodGet, resource+"/abcd", http.StatusInternalServerError,
 			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
@@ -155,6 +207,59 @@ func TestBytes(t *testing.T) {
 	})
 }
 
+// TestBytesRootNeighbourhood tests that the root chunk of the encrypted upload
+// is placed within the neighbourhood of the storage radius of the node.
+func TestBytesRootNeighbourhood(t *testing.T) {
+	t.Parallel()
+
+	const radius = 4
+	var (
+		overlay         = swarm.RandAddress(t)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:     mock.NewStorer(),
+			Tags:       tags.NewTags(nil, log.Noop),
+			Logger:     log.Noop,
+			Post:       mockpost.New(mockpost.WithAcceptAll()),
+			Overlay:    overlay,
+			BatchStore: mockbatchstore.New(mockbatchstore.WithAcceptAllExistsFunc(), mockbatchstore.WithReserveState(&postage.ReserveState{StorageRadius: radius})),
+		})
+		content = testutil.RandBytes(t, 3*swarm.ChunkSize+10)
+	)
+
+	var res api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmEncryptHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmRootNeighbourhoodHeader, "true"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&res),
+	)
+	if po := swarm.Proximity(overlay.Bytes(), res.Reference.Bytes()[:swarm.HashSize]); po < radius {
+		t.Fatalf("got root proximity %d, want at least %d", po, radius)
+	}
+
+	resp := request(t, client, http.MethodGet, "/bytes/"+res.Reference.String(), nil, http.StatusOK)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Fatal("data mismatch")
+	}
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusBadRequest,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmRootNeighbourhoodHeader, "true"),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "root neighbourhood requires encryption",
+			Code:    http.StatusBadRequest,
+		}),
+	)
+}
+
 // nolint:paralleltest
 func TestBytesInvalidStamp(t *testing.T) {
 	const (
@@ -182,7 +287,7 @@ func TestBytesInvalidStamp(t *testing.T) {
 	t.Run("upload, batch not found", func(t *testing.T) {
 		clientBatchNotExists, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:     storerMock,
-			Tags:       tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Tags:       tags.NewTags(nil, log.Noop),
 			Pinning:    pinningMock,
 			Logger:     logger,
 			Post:       mockpost.New(),
@@ -219,7 +324,7 @@ func TestBytesInvalidStamp(t *testing.T) {
 	t.Run("upload, batch exists error", func(t *testing.T) {
 		client, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:     storerMock,
-			Tags:       tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Tags:       tags.NewTags(nil, log.Noop),
 			Pinning:    pinningMock,
 			Logger:     logger,
 			Post:       mockpost.New(mockpost.WithAcceptAll()),
@@ -245,7 +350,7 @@ func TestBytesInvalidStamp(t *testing.T) {
 	t.Run("upload, batch unusable", func(t *testing.T) {
 		clientBatchUnusable, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:     storerMock,
-			Tags:       tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Tags:       tags.NewTags(nil, log.Noop),
 			Pinning:    pinningMock,
 			Logger:     logger,
 			Post:       mockpost.New(mockpost.WithAcceptAll()),
@@ -276,7 +381,7 @@ func TestBytesInvalidStamp(t *testing.T) {
 	})
 
 	t.Run("upload, tag not found", func(t *testing.T) {
-		tag := tags.NewTags(statestore.NewStateStore(), log.Noop)
+		tag := tags.NewTags(nil, log.Noop)
 		clientTagExists, _, _, _ := newTestServer(t, testServerOptions{
 			Tags:    tag,
 			Storer:  storerMock,
@@ -295,6 +400,111 @@ func TestBytesInvalidStamp(t *testing.T) {
 
 }
 
+func TestBytesClockSkewed(t *testing.T) {
+	t.Parallel()
+
+	clockSkew := clockskew.New(log.Noop)
+	for i := byte(0); i < 3; i++ {
+		clockSkew.Record(swarm.NewAddress(append(make([]byte, swarm.HashSize-1), i)), time.Now().Add(time.Hour))
+	}
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:    mock.NewStorer(),
+		Tags:      tags.NewTags(nil, log.Noop),
+		Logger:    log.Noop,
+		Post:      mockpost.New(mockpost.WithAcceptAll()),
+		ClockSkew: clockSkew,
+	})
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusServiceUnavailable,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader([]byte("data"))),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "local clock is skewed: 1h0m0s behind the peers",
+			Code:    http.StatusServiceUnavailable,
+		}),
+	)
+}
+
+func TestBytesResume(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stateStore      = statestore.NewStateStore()
+		tagsStore       = tags.NewTags(nil, log.Noop)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:      mock.NewStorer(),
+			Tags:        tagsStore,
+			StateStorer: stateStore,
+			Logger:      log.Noop,
+			Post:        mockpost.New(mockpost.WithAcceptAll()),
+		})
+		ctx = context.Background()
+	)
+
+	content := testutil.RandBytes(t, 2*swarm.Branches*swarm.ChunkSize+1000)
+	exp, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, mock.NewStorer(), storage.ModePutUpload, false), bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// checkpoint of the upload interrupted after the first branch
+	var checkpoint hashtrie.Checkpoint
+	pipe, err := builder.NewCheckpointPipelineBuilder(ctx, mock.NewStorer(), storage.ModePutUpload, false, nil, func(c hashtrie.Checkpoint) error {
+		checkpoint = c
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pipe.Write(content[:swarm.Branches*swarm.ChunkSize+100]); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := tagsStore.Create(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := api.UploadCheckpointKey(tag.Uid)
+	if err := stateStore.Put(key, api.UploadCheckpoint{Checkpoint: checkpoint}); err != nil {
+		t.Fatal(err)
+	}
+	resource := fmt.Sprintf("/bytes?resume=%d", tag.Uid)
+
+	t.Run("content shorter than checkpoint", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(content[:100])),
+		)
+	})
+
+	t.Run("tag not found", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, fmt.Sprintf("/bytes?resume=%d", tag.Uid+1), http.StatusNotFound,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "tag not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+			jsonhttptest.WithExpectedJSONResponse(api.BytesPostResponse{
+				Reference: exp,
+			}),
+		)
+
+		var c api.UploadCheckpoint
+		if err := stateStore.Get(key, &c); !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+		}
+	})
+}
+
 func Test_bytesUploadHandler_invalidInputs(t *testing.T) {
 	t.Parallel()
 
@@ -396,7 +606,7 @@ func TestDirectUploadBytes(t *testing.T) {
 		logger          = log.Noop
 		client, _, _, _ = newTestServer(t, testServerOptions{
 			Storer:  storerMock,
-			Tags:    tags.NewTags(statestore.NewStateStore(), log.Noop),
+			Tags:    tags.NewTags(nil, log.Noop),
 			Pinning: pinningMock,
 			Logger:  logger,
 			Post:    mockpost.New(mockpost.WithAcceptAll()),
@@ -20,6 +20,7 @@ import (
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/gorilla/mux"
 
+	"github.com/ethersphere/bee/pkg/clockskew"
 	"github.com/ethersphere/bee/pkg/feeds"
 	"github.com/ethersphere/bee/pkg/file/joiner"
 	"github.com/ethersphere/bee/pkg/file/loadsave"
@@ -37,6 +38,11 @@ import (
 func (s *Service) bzzUploadHandler(w http.ResponseWriter, r *http.Request) {
 	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_bzz").Build())
 
+	if sourceURL := r.URL.Query().Get(SwarmSourceURLQuery); sourceURL != "" {
+		s.bzzSourceUploadHandler(logger, w, r, sourceURL)
+		return
+	}
+
 	headers := struct {
 		ContentType string `map:"Content-Type,mimeMediaType" validate:"required"`
 	}{}
@@ -45,11 +51,27 @@ func (s *Service) bzzUploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, err := requestEncryptPadding(r); err != nil {
+		logger.Debug("invalid encrypt padding", "error", err)
+		logger.Error(nil, "invalid encrypt padding")
+		jsonhttp.BadRequest(w, "invalid encrypt padding")
+		return
+	}
+
+	if err := requestContentChecksum(r); err != nil {
+		logger.Debug("invalid content checksum", "error", err)
+		logger.Error(nil, "invalid content checksum")
+		jsonhttp.BadRequest(w, errInvalidContentChecksum)
+		return
+	}
+
 	putter, wait, err := s.newStamperPutter(r)
 	if err != nil {
 		logger.Debug("putter failed", "error", err)
 		logger.Error(nil, "putter failed")
 		switch {
+		case errors.Is(err, errBatchNotAllowed):
+			jsonhttp.Forbidden(w, "batch not allowed")
 		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
 			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
 		case errors.Is(err, postage.ErrNotFound):
@@ -58,6 +80,8 @@ func (s *Service) bzzUploadHandler(w http.ResponseWriter, r *http.Request) {
 			jsonhttp.BadRequest(w, "invalid batch id")
 		case errors.Is(err, errUnsupportedDevNodeOperation):
 			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		case errors.Is(err, clockskew.ErrClockSkewed):
+			jsonhttp.ServiceUnavailable(w, err.Error())
 		default:
 			jsonhttp.BadRequest(w, nil)
 		}
@@ -88,7 +112,7 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 		return
 	}
 
-	tag, created, err := s.getOrCreateTag(r.Header.Get(SwarmTagHeader))
+	tag, created, err := s.getOrCreateTag(r.Context(), r.Header.Get(SwarmTagHeader))
 	if err != nil {
 		logger.Debug("get or create tag failed", "error", err)
 		logger.Error(nil, "get or create tag failed")
@@ -119,13 +143,20 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 	p := requestPipelineFn(storer, r)
 
 	// first store the file and get its reference
-	fr, err := p(ctx, r.Body)
+	fr, unpaddedLength, err := p(ctx, r.Body)
 	if err != nil {
 		logger.Debug("file store failed", "file_name", queries.FileName, "error", err)
 		logger.Error(nil, "file store failed", "file_name", queries.FileName)
+		failUploadTag(logger, tag, tags.PhaseSplit, err)
 		switch {
 		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
+		case errors.Is(err, errContentChecksum):
+			jsonhttp.BadRequest(w, errContentChecksum)
+		case errors.Is(err, errSourceTooLarge):
+			jsonhttp.RequestEntityTooLarge(w, errSourceTooLarge.Error())
+		case errors.Is(err, errSourceUnavailable):
+			jsonhttp.BadGateway(w, "fetch source failed")
 		default:
 			jsonhttp.InternalServerError(w, errFileStore)
 		}
@@ -187,6 +218,9 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 		manifest.EntryMetadataContentTypeKey: r.Header.Get(contentTypeHeader), // Content-Type has already been validated.
 		manifest.EntryMetadataFilenameKey:    queries.FileName,
 	}
+	if unpaddedLength >= 0 {
+		fileMtdt[manifest.EntryMetadataUnpaddedLengthKey] = strconv.FormatInt(unpaddedLength, 10)
+	}
 
 	err = m.Add(ctx, queries.FileName, manifest.NewEntry(fr, fileMtdt))
 	if err != nil {
@@ -217,15 +251,17 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 	if err != nil {
 		logger.Debug("manifest store failed", "file_name", queries.FileName, "error", err)
 		logger.Error(nil, "manifest store failed", "file_name", queries.FileName)
+		failUploadTag(logger, tag, tags.PhaseSplit, err)
 		switch {
 		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
 		default:
 			jsonhttp.InternalServerError(w, "manifest store failed")
 		}
 		return
 	}
 	logger.Debug("store", "manifest_reference", manifestReference)
+	s.watchReceipts(logger, manifestReference)
 
 	if created {
 		_, err = tag.DoneSplit(manifestReference)
@@ -241,6 +277,10 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 		if err := s.pinning.CreatePin(ctx, manifestReference, false); err != nil {
 			logger.Debug("pin creation failed", "manifest_reference", manifestReference, "error", err)
 			logger.Error(nil, "pin creation failed")
+			if errors.Is(err, errPinQuotaExceeded) {
+				jsonhttp.Forbidden(w, "pin quota exceeded")
+				return
+			}
 			jsonhttp.InternalServerError(w, "create pin failed")
 			return
 		}
@@ -249,6 +289,7 @@ func (s *Service) fileUploadHandler(logger log.Logger, w http.ResponseWriter, r
 	if err = waitFn(); err != nil {
 		logger.Debug("sync chunks failed", "error", err)
 		logger.Error(nil, "sync chunks failed")
+		failUploadTag(logger, tag, tags.PhasePush, err)
 		jsonhttp.InternalServerError(w, "sync chunks failed")
 		return
 	}
@@ -277,6 +318,7 @@ func (s *Service) bzzDownloadHandler(w http.ResponseWriter, r *http.Request) {
 		paths.Path = strings.TrimRight(paths.Path, "/") + "/" // NOTE: leave one slash if there was some.
 	}
 
+	setContentStatsRoot(r.Context(), paths.Address)
 	s.serveReference(logger, paths.Address, paths.Path, w, r)
 }
 
@@ -290,6 +332,10 @@ func (s *Service) serveReference(logger log.Logger, address swarm.Address, pathV
 	ctx := r.Context()
 
 FETCH:
+	if s.denied(logger, w, address) {
+		return
+	}
+
 	// read manifest entry
 	m, err := manifest.NewDefaultManifestReference(
 		address,
@@ -349,6 +395,13 @@ FETCH:
 		}
 	}
 
+	// the directories are listed for the clients which accept json
+	if (pathVar == "" || strings.HasSuffix(pathVar, "/")) && requestAcceptsJSON(r) {
+		if s.serveDirListing(logger, w, r, m, pathVar) {
+			return
+		}
+	}
+
 	if pathVar == "" {
 		loggerV1.Debug("bzz download: handle empty path", "address", address)
 
@@ -451,14 +504,37 @@ func (s *Service) serveManifestEntry(
 	if mimeType, ok := mtdt[manifest.EntryMetadataContentTypeKey]; ok {
 		additionalHeaders["Content-Type"] = []string{mimeType}
 	}
+	size := int64(-1)
+	if v, ok := mtdt[manifest.EntryMetadataUnpaddedLengthKey]; ok {
+		length, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || length < 0 {
+			logger.Debug("bzz download: invalid unpadded length", "address", manifestEntry.Reference(), "value", v)
+			logger.Error(nil, "bzz download: invalid unpadded length")
+			jsonhttp.InternalServerError(w, "invalid unpadded length")
+			return
+		}
+		size = length
+	}
 
-	s.downloadHandler(logger, w, r, manifestEntry.Reference(), additionalHeaders, etag)
+	s.downloadHandler(logger, w, r, manifestEntry.Reference(), size, additionalHeaders, etag)
 }
 
-// downloadHandler contains common logic for dowloading Swarm file from API
-func (s *Service) downloadHandler(logger log.Logger, w http.ResponseWriter, r *http.Request, reference swarm.Address, additionalHeaders http.Header, etag bool) {
-	reader, l, err := joiner.New(r.Context(), s.storer, reference)
+// downloadHandler contains common logic for dowloading Swarm file from API.
+// Only the first size bytes of the content are served, which strips the
+// padding of the padded content, unless the size is negative.
+func (s *Service) downloadHandler(logger log.Logger, w http.ResponseWriter, r *http.Request, reference swarm.Address, size int64, additionalHeaders http.Header, etag bool) {
+	if s.denied(logger, w, reference) {
+		return
+	}
+
+	reader, l, err := joiner.NewSized(r.Context(), s.storer, reference, size)
 	if err != nil {
+		if errors.Is(err, joiner.ErrInvalidSize) {
+			logger.Debug("api download: invalid unpadded length", "address", reference, "size", size)
+			logger.Error(nil, "api download: invalid unpadded length")
+			jsonhttp.BadRequest(w, "invalid unpadded length")
+			return
+		}
 		if errors.Is(err, storage.ErrNotFound) {
 			logger.Debug("api download: not found ", "address", reference, "error", err)
 			logger.Error(nil, "not found")
@@ -481,6 +557,22 @@ func (s *Service) downloadHandler(logger log.Logger, w http.ResponseWriter, r *h
 	w.Header().Set("Content-Length", strconv.FormatInt(l, 10))
 	w.Header().Set("Decompressed-Content-Length", strconv.FormatInt(l, 10))
 	w.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
+
+	// the content is compressed only when it is served as a whole,
+	// as the ranges refer to the offsets of the uncompressed content
+	if s.compressibleContentType(w.Header().Get("Content-Type")) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if encoding := negotiateEncoding(r); encoding != "" && r.Method == http.MethodGet && r.Header.Get("Range") == "" {
+			cw := newCompressResponseWriter(w, encoding)
+			defer func() {
+				if err := cw.Close(); err != nil {
+					logger.Debug("api download: compression failed", "address", reference, "encoding", encoding, "error", err)
+				}
+			}()
+			w = cw
+		}
+	}
+
 	http.ServeContent(w, r, "", time.Now(), langos.NewBufferedLangos(reader, lookaheadBufferSize(l)))
 }
 
@@ -509,36 +601,56 @@ func (s *Service) manifestFeed(
 	ctx context.Context,
 	m manifest.Interface,
 ) (feeds.Lookup, error) {
+	f, t, period, err := manifestFeedInfo(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	return s.feedFactory.NewLookupWithPeriod(t, f, period)
+}
+
+// manifestFeedInfo returns the feed, its type and the hint of the seconds
+// between its updates from the root metadata of the feed manifest.
+// The period is zero if the manifest has no hint.
+func manifestFeedInfo(
+	ctx context.Context,
+	m manifest.Interface,
+) (*feeds.Feed, feeds.Type, int64, error) {
 	e, err := m.Lookup(ctx, "/")
 	if err != nil {
-		return nil, fmt.Errorf("node lookup: %w", err)
+		return nil, 0, 0, fmt.Errorf("node lookup: %w", err)
 	}
 	var (
 		owner, topic []byte
 		t            = new(feeds.Type)
+		period       int64
 	)
 	meta := e.Metadata()
 	if e := meta[feedMetadataEntryOwner]; e != "" {
 		owner, err = hex.DecodeString(e)
 		if err != nil {
-			return nil, err
+			return nil, 0, 0, err
 		}
 	}
 	if e := meta[feedMetadataEntryTopic]; e != "" {
 		topic, err = hex.DecodeString(e)
 		if err != nil {
-			return nil, err
+			return nil, 0, 0, err
 		}
 	}
 	if e := meta[feedMetadataEntryType]; e != "" {
 		err := t.FromString(e)
 		if err != nil {
-			return nil, err
+			return nil, 0, 0, err
+		}
+	}
+	if e := meta[feedMetadataEntryPeriod]; e != "" {
+		period, err = strconv.ParseInt(e, 10, 64)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("feed period: %w", err)
 		}
 	}
 	if len(owner) == 0 || len(topic) == 0 {
-		return nil, fmt.Errorf("node lookup: %s", "feed metadata absent")
+		return nil, 0, 0, fmt.Errorf("node lookup: %s", "feed metadata absent")
 	}
-	f := feeds.New(topic, common.BytesToAddress(owner))
-	return s.feedFactory.NewLookup(*t, f)
+	return feeds.New(topic, common.BytesToAddress(owner)), *t, period, nil
 }
@@ -0,0 +1,141 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	bzzListingTypeFile      = "file"
+	bzzListingTypeDirectory = "directory"
+)
+
+type bzzListingEntry struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Reference   *swarm.Address    `json:"reference,omitempty"`
+	Size        int64             `json:"size,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+type bzzListingResponse struct {
+	Path    string            `json:"path"`
+	Entries []bzzListingEntry `json:"entries"`
+}
+
+// requestAcceptsJSON reports whether the Accept header
+// of the request allows the application/json media type.
+func requestAcceptsJSON(r *http.Request) bool {
+	for _, v := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(v, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			if !strings.EqualFold(strings.TrimSpace(name), "application/json") {
+				continue
+			}
+			if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				if q, err := strconv.ParseFloat(v, 64); err != nil || q == 0 {
+					continue
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// serveDirListing responds with the entries of the manifest directory dir.
+// The directories are derived from the paths of the files. It reports
+// whether the response was written, which is not the case if there are
+// no files under the directory.
+func (s *Service) serveDirListing(logger log.Logger, w http.ResponseWriter, r *http.Request, m manifest.Interface, dir string) bool {
+	ctx := r.Context()
+
+	walker, ok := m.(manifest.Walker)
+	if !ok {
+		return false
+	}
+
+	var (
+		files []string
+		dirs  = make(map[string]struct{})
+	)
+	err := walker.Walk(ctx, func(p string, isDir bool) error {
+		if isDir || !strings.HasPrefix(p, dir) {
+			return nil
+		}
+		name := strings.TrimPrefix(p, dir)
+		if name == "" || name == manifest.RootPath {
+			return nil
+		}
+		if i := strings.Index(name, "/"); i >= 0 {
+			dirs[name[:i]] = struct{}{}
+			return nil
+		}
+		files = append(files, name)
+		return nil
+	})
+	if err != nil {
+		logger.Debug("bzz download: walk manifest failed", "path", dir, "error", err)
+		logger.Error(nil, "bzz download: walk manifest failed")
+		jsonhttp.InternalServerError(w, "list directory")
+		return true
+	}
+	if len(files) == 0 && len(dirs) == 0 {
+		return false
+	}
+
+	entries := make([]bzzListingEntry, 0, len(files)+len(dirs))
+	for name := range dirs {
+		entries = append(entries, bzzListingEntry{Name: name, Type: bzzListingTypeDirectory})
+	}
+	for _, name := range files {
+		me, err := m.Lookup(ctx, dir+name)
+		if err != nil {
+			logger.Debug("bzz download: lookup listed entry failed", "path", dir+name, "error", err)
+			logger.Error(nil, "bzz download: lookup listed entry failed")
+			jsonhttp.InternalServerError(w, "list directory")
+			return true
+		}
+		ref := me.Reference()
+		entry := bzzListingEntry{
+			Name:        name,
+			Type:        bzzListingTypeFile,
+			Reference:   &ref,
+			ContentType: me.Metadata()[manifest.EntryMetadataContentTypeKey],
+			Metadata:    me.Metadata(),
+		}
+		if v, ok := me.Metadata()[manifest.EntryMetadataUnpaddedLengthKey]; ok {
+			if entry.Size, err = strconv.ParseInt(v, 10, 64); err != nil {
+				logger.Debug("bzz download: invalid unpadded length of listed entry", "path", dir+name, "error", err)
+			}
+		} else if _, size, err := joiner.New(ctx, s.storer, ref); err == nil {
+			entry.Size = size
+		} else {
+			logger.Debug("bzz download: size of listed entry unknown", "path", dir+name, "error", err)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	w.Header().Add("Vary", "Accept")
+	jsonhttp.OK(w, bzzListingResponse{
+		Path:    dir,
+		Entries: entries,
+	})
+	return true
+}
@@ -26,7 +26,6 @@ import (
 	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
 	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage"
 	smock "github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -40,13 +39,12 @@ func TestBzzFiles(t *testing.T) {
 		fileDownloadResource = func(addr string) string { return "/bzz/" + addr }
 		simpleData           = []byte("this is a simple text")
 		storerMock           = smock.NewStorer()
-		statestoreMock       = statestore.NewStateStore()
 		pinningMock          = pinning.NewServiceMock()
 		logger               = log.Noop
 		client, _, _, _      = newTestServer(t, testServerOptions{
 			Storer:  storerMock,
 			Pinning: pinningMock,
-			Tags:    tags.NewTags(statestoreMock, logger),
+			Tags:    tags.NewTags(nil, logger),
 			Logger:  logger,
 			Post:    mockpost.New(mockpost.WithAcceptAll()),
 		})
@@ -204,6 +202,29 @@ func TestBzzFiles(t *testing.T) {
 		}
 	})
 
+	t.Run("encrypt-decrypt-padded", func(t *testing.T) {
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost,
+			fileUploadResource+"?name=padded.txt", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(simpleData)),
+			jsonhttptest.WithRequestHeader(api.SwarmEncryptHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmEncryptPaddingHeader, strconv.Itoa(4*swarm.ChunkSize)),
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		// the unpadded length is recorded in the manifest entry
+		rcvdHeader := jsonhttptest.Request(t, client, http.MethodGet,
+			fileDownloadResource(resp.Reference.String()), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(simpleData),
+		)
+		if got, want := rcvdHeader.Get("Decompressed-Content-Length"), strconv.Itoa(len(simpleData)); got != want {
+			t.Fatalf("got content length %q, want %q", got, want)
+		}
+	})
+
 	t.Run("filter out filename path", func(t *testing.T) {
 		fileName := "my-pictures.jpeg"
 		fileNameWithPath := "../../" + fileName
@@ -434,11 +455,10 @@ func TestBzzFilesRangeRequests(t *testing.T) {
 		t.Run(upload.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockStatestore := statestore.NewStateStore()
 			logger := log.Noop
 			client, _, _, _ := newTestServer(t, testServerOptions{
 				Storer: smock.NewStorer(),
-				Tags:   tags.NewTags(mockStatestore, logger),
+				Tags:   tags.NewTags(nil, logger),
 				Logger: logger,
 				Post:   mockpost.New(mockpost.WithAcceptAll()),
 			})
@@ -553,12 +573,11 @@ func TestFeedIndirection(t *testing.T) {
 	// first, "upload" some content for the update
 	var (
 		updateData      = []byte("<h1>Swarm Feeds Hello World!</h1>")
-		mockStatestore  = statestore.NewStateStore()
 		logger          = log.Noop
 		storer          = smock.NewStorer()
 		client, _, _, _ = newTestServer(t, testServerOptions{
 			Storer: storer,
-			Tags:   tags.NewTags(mockStatestore, logger),
+			Tags:   tags.NewTags(nil, logger),
 			Logger: logger,
 			Post:   mockpost.New(mockpost.WithAcceptAll()),
 		})
@@ -608,7 +627,7 @@ func TestFeedIndirection(t *testing.T) {
 	)
 	client, _, _, _ = newTestServer(t, testServerOptions{
 		Storer: storer,
-		Tags:   tags.NewTags(mockStatestore, logger),
+		Tags:   tags.NewTags(nil, logger),
 		Logger: logger,
 		Feeds:  factory,
 	})
@@ -625,9 +644,10 @@ func TestFeedIndirection(t *testing.T) {
 	}
 	emptyAddr := make([]byte, 32)
 	err = m.Add(ctx, manifest.RootPath, manifest.NewEntry(swarm.NewAddress(emptyAddr), map[string]string{
-		api.FeedMetadataEntryOwner: "8d3766440f0d7b949a5e32995d09619a7f86e632",
-		api.FeedMetadataEntryTopic: "abcc",
-		api.FeedMetadataEntryType:  "epoch",
+		api.FeedMetadataEntryOwner:  "8d3766440f0d7b949a5e32995d09619a7f86e632",
+		api.FeedMetadataEntryTopic:  "abcc",
+		api.FeedMetadataEntryType:   "epoch",
+		api.FeedMetadataEntryPeriod: "60",
 	}))
 	if err != nil {
 		t.Fatal(err)
@@ -640,6 +660,9 @@ func TestFeedIndirection(t *testing.T) {
 	jsonhttptest.Request(t, client, http.MethodGet, bzzDownloadResource(manifRef.String(), ""), http.StatusOK,
 		jsonhttptest.WithExpectedResponse(updateData),
 	)
+	if !factory.epochCalled || factory.period != 60 {
+		t.Fatalf("got epoch lookup %t with period %d, want epoch lookup with period 60", factory.epochCalled, factory.period)
+	}
 }
 
 func Test_bzzDownloadHandler_invalidInputs(t *testing.T) {
@@ -697,7 +720,6 @@ func TestInvalidBzzParams(t *testing.T) {
 	var (
 		fileUploadResource = "/bzz"
 		storerMock         = smock.NewStorer()
-		statestoreMock     = statestore.NewStateStore()
 		pinningMock        = pinning.NewServiceMock()
 		logger             = log.Noop
 		existsFn           = func(id []byte) (bool, error) {
@@ -721,7 +743,7 @@ func TestInvalidBzzParams(t *testing.T) {
 		clientBatchUnusable, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:     storerMock,
 			Pinning:    pinningMock,
-			Tags:       tags.NewTags(statestoreMock, logger),
+			Tags:       tags.NewTags(nil, logger),
 			Logger:     logger,
 			Post:       mockpost.New(mockpost.WithAcceptAll()),
 			BatchStore: mockbatchstore.New(),
@@ -751,7 +773,7 @@ func TestInvalidBzzParams(t *testing.T) {
 		clientBatchExists, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:     storerMock,
 			Pinning:    pinningMock,
-			Tags:       tags.NewTags(statestoreMock, logger),
+			Tags:       tags.NewTags(nil, logger),
 			Logger:     logger,
 			Post:       mockpost.New(mockpost.WithAcceptAll()),
 			BatchStore: mockbatchstore.New(mockbatchstore.WithExistsFunc(existsFn)),
@@ -781,7 +803,7 @@ func TestInvalidBzzParams(t *testing.T) {
 		clientBatchExists, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:  storerMock,
 			Pinning: pinningMock,
-			Tags:    tags.NewTags(statestoreMock, logger),
+			Tags:    tags.NewTags(nil, logger),
 			Logger:  logger,
 			Post:    mockpost.New(),
 		})
@@ -833,7 +855,7 @@ func TestInvalidBzzParams(t *testing.T) {
 				},
 			},
 		})
-		tag := tags.NewTags(statestore.NewStateStore(), log.Noop)
+		tag := tags.NewTags(nil, log.Noop)
 		clientTagExists, _, _, _ := newTestServer(t, testServerOptions{
 			Tags:   tag,
 			Storer: storerMock,
@@ -855,7 +877,7 @@ func TestInvalidBzzParams(t *testing.T) {
 		client, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:  storerMock,
 			Pinning: pinningMock,
-			Tags:    tags.NewTags(statestoreMock, logger),
+			Tags:    tags.NewTags(nil, logger),
 			Logger:  logger,
 			Post:    mockpost.New(mockpost.WithAcceptAll()),
 		})
@@ -866,6 +888,121 @@ func TestInvalidBzzParams(t *testing.T) {
 
 }
 
+func TestBzzDirListing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storerMock      = smock.NewStorer()
+		logger          = log.Noop
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storerMock,
+			Tags:   tags.NewTags(nil, logger),
+			Logger: logger,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+	)
+
+	tr := tarFiles(t, []f{
+		{
+			data: []byte("<h1>index</h1>"),
+			name: "index.html",
+			header: http.Header{
+				"Content-Type": {"text/html; charset=utf-8"},
+			},
+		},
+		{
+			data: []byte("image 1"),
+			name: "1.png",
+			dir:  "img",
+			header: http.Header{
+				"Content-Type": {"image/png"},
+			},
+		},
+		{
+			data: []byte("image 22"),
+			name: "2.png",
+			dir:  "img",
+			header: http.Header{
+				"Content-Type": {"image/png"},
+			},
+		},
+	})
+	var upload api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithRequestBody(tr),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+
+	list := func(t *testing.T, dir string) api.BzzListingResponse {
+		t.Helper()
+
+		var res api.BzzListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+upload.Reference.String()+"/"+dir, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept", "application/json"),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		return res
+	}
+
+	t.Run("root", func(t *testing.T) {
+		t.Parallel()
+
+		res := list(t, "")
+		if len(res.Entries) != 2 {
+			t.Fatalf("got %d entries, want 2", len(res.Entries))
+		}
+		if e := res.Entries[0]; e.Name != "img" || e.Type != "directory" || e.Reference != nil {
+			t.Fatalf("got entry %+v, want directory img", e)
+		}
+		if e := res.Entries[1]; e.Name != "index.html" || e.Type != "file" || e.Reference == nil || e.Size != 14 || e.ContentType != "text/html; charset=utf-8" {
+			t.Fatalf("got entry %+v, want file index.html", e)
+		}
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		t.Parallel()
+
+		res := list(t, "img/")
+		if res.Path != "img/" {
+			t.Fatalf("got path %q, want %q", res.Path, "img/")
+		}
+		if len(res.Entries) != 2 {
+			t.Fatalf("got %d entries, want 2", len(res.Entries))
+		}
+		for i, want := range []struct {
+			name string
+			size int64
+		}{{"1.png", 7}, {"2.png", 8}} {
+			e := res.Entries[i]
+			if e.Name != want.name || e.Type != "file" || e.Size != want.size || e.ContentType != "image/png" {
+				t.Fatalf("got entry %+v, want file %s", e, want.name)
+			}
+		}
+	})
+
+	t.Run("index document without json", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+upload.Reference.String()+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("<h1>index</h1>")),
+		)
+	})
+
+	t.Run("file with json", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+upload.Reference.String()+"/img/1.png", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept", "application/json"),
+			jsonhttptest.WithExpectedResponse([]byte("image 1")),
+		)
+	})
+}
+
 // TestDirectUploadBzz tests that the direct upload endpoint give correct error message in dev mode
 func TestDirectUploadBzz(t *testing.T) {
 	t.Parallel()
@@ -873,7 +1010,6 @@ func TestDirectUploadBzz(t *testing.T) {
 	var (
 		fileUploadResource = "/bzz"
 		storerMock         = smock.NewStorer()
-		statestoreMock     = statestore.NewStateStore()
 		pinningMock        = pinning.NewServiceMock()
 		logger             = log.Noop
 	)
@@ -891,7 +1027,7 @@ func TestDirectUploadBzz(t *testing.T) {
 	clientBatchUnusable, _, _, _ := newTestServer(t, testServerOptions{
 		Storer:     storerMock,
 		Pinning:    pinningMock,
-		Tags:       tags.NewTags(statestoreMock, logger),
+		Tags:       tags.NewTags(nil, logger),
 		Logger:     logger,
 		Post:       mockpost.New(mockpost.WithAcceptAll()),
 		BatchStore: mockbatchstore.New(),
@@ -0,0 +1,68 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+)
+
+var (
+	// errContentChecksum is returned if the body of the upload
+	// does not match the checksum of the SwarmContentSha256Header.
+	errContentChecksum = errors.New("content checksum mismatch")
+	// errInvalidContentChecksum is returned if the
+	// SwarmContentSha256Header is not a hex encoded SHA-256 hash.
+	errInvalidContentChecksum = errors.New("invalid content checksum")
+)
+
+// checksumBody is the request body which fails with errContentChecksum
+// instead of io.EOF if the read content does not match the checksum.
+type checksumBody struct {
+	io.ReadCloser
+	hash hash.Hash
+	want []byte
+}
+
+func (b *checksumBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	_, _ = b.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) && !bytes.Equal(b.hash.Sum(nil), b.want) {
+		return n, errContentChecksum
+	}
+	return n, err
+}
+
+// requestContentChecksum replaces the body of the request with the one
+// verifying the checksum of the SwarmContentSha256Header, if it is set.
+func requestContentChecksum(r *http.Request) error {
+	h := r.Header.Get(SwarmContentSha256Header)
+	if h == "" {
+		return nil
+	}
+	want, err := hex.DecodeString(h)
+	if err != nil || len(want) != sha256.Size {
+		return errInvalidContentChecksum
+	}
+	r.Body = &checksumBody{ReadCloser: r.Body, hash: sha256.New(), want: want}
+	return nil
+}
+
+// verifyContentChecksum reads the rest of the body of the request and
+// verifies the checksum of the whole body, if it is set. It is needed by
+// the uploads whose readers may stop before the end of the body.
+func verifyContentChecksum(r *http.Request) error {
+	b, ok := r.Body.(*checksumBody)
+	if !ok {
+		return nil
+	}
+	_, err := io.Copy(io.Discard, b)
+	return err
+}
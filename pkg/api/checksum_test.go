@@ -0,0 +1,143 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestContentChecksum(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+		Tags:   tags.NewTags(nil, log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+
+	checksum := func(b []byte) string {
+		h := sha256.Sum256(b)
+		return hex.EncodeToString(h[:])
+	}
+
+	content := bytes.Repeat([]byte("checksum"), swarm.ChunkSize)
+	chunk := append([]byte{8, 0, 0, 0, 0, 0, 0, 0}, []byte("checksum")...)
+	tr := tarFiles(t, []f{{
+		data:   []byte("robots text"),
+		name:   "robots.txt",
+		header: http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+	}}).Bytes()
+
+	for _, tc := range []struct {
+		name        string
+		resource    string
+		contentType string
+		body        []byte
+		checksum    string
+		status      int
+		message     string
+	}{
+		{
+			name:     "bytes",
+			resource: "/bytes",
+			body:     content,
+			checksum: checksum(content),
+			status:   http.StatusCreated,
+		},
+		{
+			name:     "bytes mismatch",
+			resource: "/bytes",
+			body:     content,
+			checksum: checksum(content[1:]),
+			status:   http.StatusBadRequest,
+			message:  "content checksum mismatch",
+		},
+		{
+			name:     "bytes invalid",
+			resource: "/bytes",
+			body:     content,
+			checksum: "abcd",
+			status:   http.StatusBadRequest,
+			message:  "invalid content checksum",
+		},
+		{
+			name:        "file mismatch",
+			resource:    "/bzz?name=file.txt",
+			contentType: "text/plain",
+			body:        content,
+			checksum:    checksum(content[1:]),
+			status:      http.StatusBadRequest,
+			message:     "content checksum mismatch",
+		},
+		{
+			name:        "dir",
+			resource:    "/bzz",
+			contentType: api.ContentTypeTar,
+			body:        tr,
+			checksum:    checksum(tr),
+			status:      http.StatusCreated,
+		},
+		{
+			// the trailing bytes are not read by the tar reader
+			name:        "dir mismatch",
+			resource:    "/bzz",
+			contentType: api.ContentTypeTar,
+			body:        append(append([]byte(nil), tr...), make([]byte, 1024)...),
+			checksum:    checksum(tr),
+			status:      http.StatusBadRequest,
+			message:     "content checksum mismatch",
+		},
+		{
+			name:     "chunk",
+			resource: "/chunks",
+			body:     chunk,
+			checksum: checksum(chunk),
+			status:   http.StatusCreated,
+		},
+		{
+			name:     "chunk mismatch",
+			resource: "/chunks",
+			body:     chunk,
+			checksum: checksum(chunk[1:]),
+			status:   http.StatusBadRequest,
+			message:  "content checksum mismatch",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := []jsonhttptest.Option{
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithRequestHeader(api.SwarmContentSha256Header, tc.checksum),
+				jsonhttptest.WithRequestBody(bytes.NewReader(tc.body)),
+			}
+			if tc.contentType != "" {
+				opts = append(opts, jsonhttptest.WithRequestHeader(api.ContentTypeHeader, tc.contentType))
+			}
+			if tc.message != "" {
+				opts = append(opts, jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+					Message: tc.message,
+					Code:    tc.status,
+				}))
+			}
+			jsonhttptest.Request(t, client, http.MethodPost, tc.resource, tc.status, opts...)
+		})
+	}
+}
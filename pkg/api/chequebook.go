@@ -5,9 +5,11 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"net/http"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/bigint"
@@ -30,10 +32,18 @@ const (
 	errCantLastCheque              = "cannot get last cheque for all peers"
 	errCannotCash                  = "cannot cash cheque"
 	errCannotCashStatus            = "cannot get cashout status"
+	errCantBouncedCheques          = "cannot get bounced cheques"
 	errNoCashout                   = "no prior cashout"
 	errNoCheque                    = "no prior cheque"
+	errChequebookNoWithdrawal      = "scheduled withdrawals not configured"
+	errChequebookWithdrawalPlan    = "cannot plan withdrawal"
 )
 
+// WithdrawalPlanner plans the scheduled withdrawals of the chequebook balance.
+type WithdrawalPlanner interface {
+	Plan(ctx context.Context) (*chequebook.WithdrawalPlan, error)
+}
+
 type chequebookBalanceResponse struct {
 	TotalBalance     *bigint.BigInt `json:"totalBalance"`
 	AvailableBalance *bigint.BigInt `json:"availableBalance"`
@@ -337,6 +347,90 @@ func (s *Service) swapCashoutStatusHandler(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+type swapBouncedChequeResponse struct {
+	Peer             swarm.Address  `json:"peer"`
+	Chequebook       common.Address `json:"chequebook"`
+	TransactionHash  common.Hash    `json:"transactionHash"`
+	CumulativePayout *bigint.BigInt `json:"cumulativePayout"`
+	TotalPayout      *bigint.BigInt `json:"totalPayout"`
+	Timestamp        int64          `json:"timestamp"`
+}
+
+type swapBouncedChequesResponse struct {
+	Bounced []swapBouncedChequeResponse `json:"bounced"`
+}
+
+func newSwapBouncedChequeResponse(b swap.BouncedCheque) swapBouncedChequeResponse {
+	return swapBouncedChequeResponse{
+		Peer:             b.Peer,
+		Chequebook:       b.Chequebook,
+		TransactionHash:  b.TxHash,
+		CumulativePayout: bigint.Wrap(b.CumulativePayout),
+		TotalPayout:      bigint.Wrap(b.TotalPayout),
+		Timestamp:        b.Timestamp,
+	}
+}
+
+func (s *Service) swapBouncedPeerHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_chequebook_bounced_by_peer").Build()
+
+	paths := struct {
+		Peer swarm.Address `map:"peer" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	bounced, err := s.swap.BouncedCheques(paths.Peer)
+	if errors.Is(err, postagecontract.ErrChainDisabled) {
+		logger.Debug("get bounced cheques failed", "peer_address", paths.Peer, "error", err)
+		logger.Error(nil, "get bounced cheques failed", "peer_address", paths.Peer)
+		jsonhttp.MethodNotAllowed(w, err)
+		return
+	}
+	if err != nil {
+		logger.Debug("get bounced cheques failed", "peer_address", paths.Peer, "error", err)
+		logger.Error(nil, "get bounced cheques failed", "peer_address", paths.Peer)
+		jsonhttp.InternalServerError(w, errCantBouncedCheques)
+		return
+	}
+
+	response := swapBouncedChequesResponse{Bounced: make([]swapBouncedChequeResponse, 0, len(bounced))}
+	for _, b := range bounced {
+		response.Bounced = append(response.Bounced, newSwapBouncedChequeResponse(b))
+	}
+
+	jsonhttp.OK(w, response)
+}
+
+func (s *Service) swapBouncedAllHandler(w http.ResponseWriter, _ *http.Request) {
+	logger := s.logger.WithName("get_chequebook_bounced").Build()
+
+	bounced, err := s.swap.AllBouncedCheques()
+	if errors.Is(err, postagecontract.ErrChainDisabled) {
+		logger.Debug("get all bounced cheques failed", "error", err)
+		logger.Error(nil, "get all bounced cheques failed")
+		jsonhttp.MethodNotAllowed(w, err)
+		return
+	}
+	if err != nil {
+		logger.Debug("get all bounced cheques failed", "error", err)
+		logger.Error(nil, "get all bounced cheques failed")
+		jsonhttp.InternalServerError(w, errCantBouncedCheques)
+		return
+	}
+
+	response := swapBouncedChequesResponse{Bounced: make([]swapBouncedChequeResponse, 0)}
+	for _, peerBounced := range bounced {
+		for _, b := range peerBounced {
+			response.Bounced = append(response.Bounced, newSwapBouncedChequeResponse(b))
+		}
+	}
+
+	jsonhttp.OK(w, response)
+}
+
 type chequebookTxResponse struct {
 	TransactionHash common.Hash `json:"transactionHash"`
 }
@@ -396,3 +490,42 @@ func (s *Service) chequebookDepositHandler(w http.ResponseWriter, r *http.Reques
 
 	jsonhttp.OK(w, chequebookTxResponse{TransactionHash: txHash})
 }
+
+type chequebookWithdrawalResponse struct {
+	AvailableBalance *bigint.BigInt `json:"availableBalance"`
+	Threshold        *bigint.BigInt `json:"threshold"`
+	Amount           *bigint.BigInt `json:"amount"`
+	Chequebook       common.Address `json:"chequebook"`
+	Wallet           common.Address `json:"wallet"`
+	Recipient        common.Address `json:"recipient"`
+	Next             time.Time      `json:"next"`
+}
+
+// chequebookWithdrawalHandler returns the transactions of the next scheduled
+// withdrawal without sending them.
+func (s *Service) chequebookWithdrawalHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_chequebook_withdrawal").Build()
+
+	if s.withdrawal == nil {
+		jsonhttp.NotFound(w, errChequebookNoWithdrawal)
+		return
+	}
+
+	plan, err := s.withdrawal.Plan(r.Context())
+	if err != nil {
+		logger.Debug("plan withdrawal failed", "error", err)
+		logger.Error(nil, "plan withdrawal failed")
+		jsonhttp.InternalServerError(w, errChequebookWithdrawalPlan)
+		return
+	}
+
+	jsonhttp.OK(w, chequebookWithdrawalResponse{
+		AvailableBalance: bigint.Wrap(plan.AvailableBalance),
+		Threshold:        bigint.Wrap(plan.Threshold),
+		Amount:           bigint.Wrap(plan.Amount),
+		Chequebook:       plan.Chequebook,
+		Wallet:           plan.Wallet,
+		Recipient:        plan.Recipient,
+		Next:             plan.Next,
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/api"
@@ -18,6 +19,7 @@ import (
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/settlement/swap"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook/mock"
 	swapmock "github.com/ethersphere/bee/pkg/settlement/swap/mock"
@@ -267,6 +269,82 @@ func TestChequebookDeposit(t *testing.T) {
 	})
 }
 
+type withdrawalPlannerFunc func(ctx context.Context) (*chequebook.WithdrawalPlan, error)
+
+func (f withdrawalPlannerFunc) Plan(ctx context.Context) (*chequebook.WithdrawalPlan, error) {
+	return f(ctx)
+}
+
+func TestChequebookWithdrawal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		plan := &chequebook.WithdrawalPlan{
+			AvailableBalance: big.NewInt(1000),
+			Threshold:        big.NewInt(300),
+			Amount:           big.NewInt(700),
+			Chequebook:       common.HexToAddress("0xabcd"),
+			Wallet:           common.HexToAddress("0xbeef"),
+			Recipient:        common.HexToAddress("0xc01d"),
+			Next:             time.Unix(1700000000, 0).UTC(),
+		}
+
+		testServer, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			Withdrawal: withdrawalPlannerFunc(func(context.Context) (*chequebook.WithdrawalPlan, error) {
+				return plan, nil
+			}),
+		})
+
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/chequebook/withdrawal", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ChequebookWithdrawalResponse{
+				AvailableBalance: bigint.Wrap(plan.AvailableBalance),
+				Threshold:        bigint.Wrap(plan.Threshold),
+				Amount:           bigint.Wrap(plan.Amount),
+				Chequebook:       plan.Chequebook,
+				Wallet:           plan.Wallet,
+				Recipient:        plan.Recipient,
+				Next:             plan.Next,
+			}),
+		)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/chequebook/withdrawal", http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "scheduled withdrawals not configured",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			Withdrawal: withdrawalPlannerFunc(func(context.Context) (*chequebook.WithdrawalPlan, error) {
+				return nil, errors.New("some error")
+			}),
+		})
+
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/chequebook/withdrawal", http.StatusInternalServerError,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "cannot plan withdrawal",
+				Code:    http.StatusInternalServerError,
+			}),
+		)
+	})
+}
+
 func TestChequebookLastCheques(t *testing.T) {
 	t.Parallel()
 
@@ -501,6 +579,72 @@ func TestChequebookLastChequesPeer(t *testing.T) {
 
 }
 
+func TestChequebookBounced(t *testing.T) {
+	t.Parallel()
+
+	addr := swarm.MustParseHexAddress("1000000000000000000000000000000000000000000000000000000000000000")
+	bounced := swap.BouncedCheque{
+		Peer:             addr,
+		Chequebook:       common.HexToAddress("0xeee1"),
+		TxHash:           common.HexToHash("0xffff"),
+		CumulativePayout: big.NewInt(700),
+		TotalPayout:      big.NewInt(300),
+		Timestamp:        1000,
+	}
+
+	testServer, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		SwapOpts: []swapmock.Option{
+			swapmock.WithBouncedChequesFunc(func(peer swarm.Address) ([]swap.BouncedCheque, error) {
+				if !peer.Equal(addr) {
+					return nil, nil
+				}
+				return []swap.BouncedCheque{bounced}, nil
+			}),
+			swapmock.WithAllBouncedChequesFunc(func() (map[string][]swap.BouncedCheque, error) {
+				return map[string][]swap.BouncedCheque{addr.String(): {bounced}}, nil
+			}),
+		},
+	})
+
+	expected := &api.SwapBouncedChequesResponse{
+		Bounced: []api.SwapBouncedChequeResponse{{
+			Peer:             addr,
+			Chequebook:       bounced.Chequebook,
+			TransactionHash:  bounced.TxHash,
+			CumulativePayout: bigint.Wrap(bounced.CumulativePayout),
+			TotalPayout:      bigint.Wrap(bounced.TotalPayout),
+			Timestamp:        bounced.Timestamp,
+		}},
+	}
+
+	t.Run("peer", func(t *testing.T) {
+		t.Parallel()
+
+		var got *api.SwapBouncedChequesResponse
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/chequebook/bounced/"+addr.String(), http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Got: \n %+v \n\n Expected: \n %+v \n\n", got, expected)
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		var got *api.SwapBouncedChequesResponse
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/chequebook/bounced", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Got: \n %+v \n\n Expected: \n %+v \n\n", got, expected)
+		}
+	})
+}
+
 func TestChequebookCashout(t *testing.T) {
 	t.Parallel()
 
@@ -15,6 +15,7 @@ import (
 	"github.com/ethersphere/bee/pkg/cac"
 	"github.com/ethersphere/bee/pkg/log"
 
+	"github.com/ethersphere/bee/pkg/clockskew"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/sctx"
@@ -33,7 +34,7 @@ func (s *Service) processUploadRequest(
 ) (ctx context.Context, tag *tags.Tag, putter storage.Putter, waitFn func() error, err error) {
 
 	if str := r.Header.Get(SwarmTagHeader); str != "" {
-		tag, err = s.getTag(str)
+		tag, err = s.getTag(r.Context(), str)
 		if err != nil {
 			logger.Debug("get tag failed", "string", str, "error", err)
 			logger.Error(nil, "get tag failed", "string", str)
@@ -59,11 +60,20 @@ func (s *Service) processUploadRequest(
 func (s *Service) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("post_chunk").Build()
 
+	if err := requestContentChecksum(r); err != nil {
+		logger.Debug("chunk upload: invalid content checksum", "error", err)
+		logger.Error(nil, "chunk upload: invalid content checksum")
+		jsonhttp.BadRequest(w, errInvalidContentChecksum)
+		return
+	}
+
 	ctx, tag, putter, wait, err := s.processUploadRequest(logger, r)
 	if err != nil {
 		switch {
 		case errors.Is(err, tags.ErrNotFound):
 			jsonhttp.NotFound(w, "tag not found")
+		case errors.Is(err, errBatchNotAllowed):
+			jsonhttp.Forbidden(w, "batch not allowed")
 		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
 			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
 		case errors.Is(err, postage.ErrNotFound):
@@ -72,6 +82,8 @@ func (s *Service) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 			jsonhttp.BadRequest(w, "invalid batch id")
 		case errors.Is(err, errUnsupportedDevNodeOperation):
 			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		case errors.Is(err, clockskew.ErrClockSkewed):
+			jsonhttp.ServiceUnavailable(w, err.Error())
 		default:
 			jsonhttp.BadRequest(w, nil)
 		}
@@ -93,6 +105,10 @@ func (s *Service) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 		if jsonhttp.HandleBodyReadError(err, w) {
 			return
 		}
+		if errors.Is(err, errContentChecksum) {
+			jsonhttp.BadRequest(w, errContentChecksum)
+			return
+		}
 		s.logger.Debug("chunk upload: read chunk data failed", "error", err)
 		s.logger.Error(nil, "chunk upload: read chunk data failed")
 		jsonhttp.InternalServerError(w, "cannot read chunk data")
@@ -120,7 +136,7 @@ func (s *Service) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 		s.logger.Error(nil, "chunk upload: write chunk failed")
 		switch {
 		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
 		default:
 			jsonhttp.InternalServerError(w, "chunk write error")
 		}
@@ -156,11 +172,17 @@ func (s *Service) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 				s.logger.Debug("chunk upload: pin deletion failed", "chunk_address", chunk.Address(), "error", err)
 				s.logger.Error(nil, "chunk upload: pin deletion failed")
 			}
+			if errors.Is(err, errPinQuotaExceeded) {
+				jsonhttp.Forbidden(w, "pin quota exceeded")
+				return
+			}
 			jsonhttp.InternalServerError(w, "creation of pin failed")
 			return
 		}
 	}
 
+	s.watchReceipts(logger, chunk.Address())
+
 	if err = wait(); err != nil {
 		s.logger.Debug("chunk upload: sync chunk failed", "error", err)
 		switch {
@@ -5,6 +5,8 @@
 package api
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
@@ -38,6 +40,72 @@ func (s *Service) hasChunkHandler(w http.ResponseWriter, r *http.Request) {
 	jsonhttp.OK(w, nil)
 }
 
+// maxChunksHasReferences is the maximal number
+// of the references checked in a single request.
+const maxChunksHasReferences = 10000
+
+type chunksHasRequest struct {
+	References []swarm.Address `json:"references"`
+}
+
+type chunkHasData struct {
+	Reference swarm.Address `json:"reference"`
+	Has       bool          `json:"has"`
+}
+
+type chunksHasResponse struct {
+	Chunks []chunkHasData `json:"chunks"`
+}
+
+// hasChunksHandler reports which of the chunks are stored locally, checking
+// all of them at once, so that the large sets of the references can be
+// diffed against the node without a request per chunk.
+func (s *Service) hasChunksHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_chunks_has").Build()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+	var req chunksHasRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Debug("unmarshal request body failed", "error", err)
+		logger.Error(nil, "unmarshal request body failed")
+		jsonhttp.BadRequest(w, "invalid request")
+		return
+	}
+	if len(req.References) > maxChunksHasReferences {
+		jsonhttp.BadRequest(w, "too many references")
+		return
+	}
+	for _, ref := range req.References {
+		if ref.IsZero() {
+			jsonhttp.BadRequest(w, "invalid reference")
+			return
+		}
+	}
+
+	res := chunksHasResponse{Chunks: make([]chunkHasData, 0, len(req.References))}
+	if len(req.References) == 0 {
+		jsonhttp.OK(w, res)
+		return
+	}
+	have, err := s.storer.HasMulti(r.Context(), req.References...)
+	if err != nil {
+		logger.Debug("has chunks failed", "error", err)
+		logger.Error(nil, "has chunks failed")
+		jsonhttp.InternalServerError(w, "has chunks failed")
+		return
+	}
+	for i, ref := range req.References {
+		res.Chunks = append(res.Chunks, chunkHasData{Reference: ref, Has: have[i]})
+	}
+	jsonhttp.OK(w, res)
+}
+
 func (s *Service) removeChunk(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("delete_chunk").Build()
 
@@ -0,0 +1,169 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/binary"
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+const (
+	chunkTypeContentAddressed = "content-addressed"
+	chunkTypeSingleOwner      = "single-owner"
+	chunkTypeUnknown          = "unknown"
+)
+
+type debugChunkResponse struct {
+	Address swarm.Address            `json:"address"`
+	Data    hexByte                  `json:"data"`
+	Type    string                   `json:"type"`
+	Span    *uint64                  `json:"span,omitempty"`
+	SOC     *debugChunkSOCResponse   `json:"soc,omitempty"`
+	Stamp   *debugChunkStampResponse `json:"stamp,omitempty"`
+	Batch   *postageBatchResponse    `json:"batch,omitempty"`
+	Indices []string                 `json:"indices"`
+}
+
+// debugChunkSOCResponse is the interpretation of the chunk data as
+// a single-owner chunk, present even if the SOC address does not match.
+type debugChunkSOCResponse struct {
+	ID        hexByte `json:"id"`
+	Owner     hexByte `json:"owner"`
+	Signature hexByte `json:"signature"`
+	Span      uint64  `json:"span"`
+	Valid     bool    `json:"valid"`
+}
+
+type debugChunkStampResponse struct {
+	BatchID   hexByte `json:"batchID"`
+	Bucket    uint32  `json:"bucket"`
+	Index     uint32  `json:"index"`
+	Timestamp uint64  `json:"timestamp"`
+	Signature hexByte `json:"signature"`
+	Valid     bool    `json:"valid"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func (s *Service) debugChunkHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_debug_chunk").Build()
+
+	paths := struct {
+		Address swarm.Address `map:"address" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	if s.indexDebugger == nil {
+		jsonhttp.NotImplemented(w, "storage indices not available")
+		logger.Error(nil, "debug chunk not implemented")
+		return
+	}
+
+	ch, indices, err := s.indexDebugger.DebugChunk(r.Context(), paths.Address)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			jsonhttp.NotFound(w, "chunk not found")
+			return
+		}
+		logger.Debug("debug chunk failed", "chunk_address", paths.Address, "error", err)
+		logger.Error(nil, "debug chunk failed")
+		jsonhttp.InternalServerError(w, "cannot get chunk")
+		return
+	}
+
+	res := debugChunkResponse{
+		Address: paths.Address,
+		Data:    ch.Data(),
+		Type:    chunkTypeUnknown,
+		Indices: indices,
+	}
+
+	if cac.Valid(ch) {
+		res.Type = chunkTypeContentAddressed
+		span := binary.LittleEndian.Uint64(ch.Data()[:swarm.SpanSize])
+		res.Span = &span
+	}
+	if sch, err := soc.FromChunk(ch); err == nil {
+		res.SOC = &debugChunkSOCResponse{
+			ID:        sch.ID(),
+			Owner:     sch.OwnerAddress(),
+			Signature: sch.Signature(),
+			Span:      binary.LittleEndian.Uint64(sch.WrappedChunk().Data()[:swarm.SpanSize]),
+			Valid:     soc.Valid(ch),
+		}
+		if res.SOC.Valid {
+			res.Type = chunkTypeSingleOwner
+		}
+	}
+
+	if stamp, ok := ch.Stamp().(*postage.Stamp); ok && len(stamp.BatchID()) > 0 {
+		res.Stamp, res.Batch, err = s.debugChunkStamp(paths.Address, stamp)
+		if err != nil {
+			logger.Debug("debug chunk stamp failed", "chunk_address", paths.Address, "error", err)
+			logger.Error(nil, "debug chunk stamp failed")
+			jsonhttp.InternalServerError(w, "cannot get chunk batch")
+			return
+		}
+	}
+
+	jsonhttp.OK(w, res)
+}
+
+// debugChunkStamp interprets the stamp of the chunk and validates it against
+// its batch. The batch is nil if it is not known to the node.
+func (s *Service) debugChunkStamp(addr swarm.Address, stamp *postage.Stamp) (*debugChunkStampResponse, *postageBatchResponse, error) {
+	bucket, index := stamp.BucketIndex()
+	res := &debugChunkStampResponse{
+		BatchID:   stamp.BatchID(),
+		Bucket:    bucket,
+		Index:     index,
+		Timestamp: binary.BigEndian.Uint64(stamp.Timestamp()),
+		Signature: stamp.Sig(),
+	}
+
+	b, err := s.batchStore.Get(stamp.BatchID())
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			res.Error = postage.ErrNotFound.Error()
+			return res, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	if err := stamp.Valid(addr, b.Owner, b.Depth, b.BucketDepth, b.Immutable); err != nil {
+		res.Error = err.Error()
+	} else {
+		res.Valid = true
+	}
+
+	batchTTL, err := s.estimateBatchTTL(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, &postageBatchResponse{
+		BatchID:       b.ID,
+		Value:         bigint.Wrap(b.Value),
+		Start:         b.Start,
+		Owner:         b.Owner,
+		Depth:         b.Depth,
+		BucketDepth:   b.BucketDepth,
+		Immutable:     b.Immutable,
+		StorageRadius: b.StorageRadius,
+		BatchTTL:      batchTTL,
+	}, nil
+}
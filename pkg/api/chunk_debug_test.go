@@ -0,0 +1,129 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	soctesting "github.com/ethersphere/bee/pkg/soc/testing"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestDebugChunk(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		batch   = postagetesting.MustNewBatch(postagetesting.WithOwner(owner.Bytes()))
+		issuer  = postage.NewStampIssuer("", "", batch.ID, big.NewInt(3), batch.Depth, batch.BucketDepth, 1000, true)
+		mockSOC = soctesting.GenerateMockSOC(t, []byte("data"))
+		sch     = mockSOC.Chunk()
+		indices = []string{"retrievalDataIndex", "pullIndex"}
+	)
+	stamp, err := postage.NewStamper(issuer, signer).Stamp(sch.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := map[string]swarm.Chunk{
+		sch.Address().ByteString(): sch.WithStamp(stamp),
+	}
+	testServer, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		BatchStore: mockbatchstore.New(
+			mockbatchstore.WithBatch(batch),
+			mockbatchstore.WithChainState(postagetesting.NewChainState()),
+		),
+		IndexDebugger: &testIndexDebugger{
+			chunkFunc: func(addr swarm.Address) (swarm.Chunk, []string, error) {
+				ch, ok := chunks[addr.ByteString()]
+				if !ok {
+					return nil, nil, storage.ErrNotFound
+				}
+				return ch, indices, nil
+			},
+		},
+	})
+
+	t.Run("single owner chunk", func(t *testing.T) {
+		t.Parallel()
+
+		var res struct {
+			Address swarm.Address `json:"address"`
+			Data    string        `json:"data"`
+			Type    string        `json:"type"`
+			Span    *uint64       `json:"span"`
+			SOC     struct {
+				Owner string `json:"owner"`
+				Span  uint64 `json:"span"`
+				Valid bool   `json:"valid"`
+			} `json:"soc"`
+			Stamp struct {
+				BatchID string `json:"batchID"`
+				Valid   bool   `json:"valid"`
+				Error   string `json:"error"`
+			} `json:"stamp"`
+			Batch struct {
+				Owner string `json:"owner"`
+			} `json:"batch"`
+			Indices []string `json:"indices"`
+		}
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/debug/chunks/"+sch.Address().String(), http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+
+		if !res.Address.Equal(sch.Address()) || res.Data != hex.EncodeToString(sch.Data()) {
+			t.Fatalf("got chunk %s, want %s", res.Address, sch.Address())
+		}
+		if res.Type != "single-owner" || res.Span != nil {
+			t.Fatalf("got type %q, want single-owner", res.Type)
+		}
+		if res.SOC.Owner != hex.EncodeToString(mockSOC.Owner) || !res.SOC.Valid {
+			t.Fatalf("got soc %+v, want valid soc", res.SOC)
+		}
+		if res.SOC.Span != 4 {
+			t.Fatalf("got soc span %d, want 4", res.SOC.Span)
+		}
+		if !res.Stamp.Valid || res.Stamp.Error != "" || res.Stamp.BatchID != hex.EncodeToString(batch.ID) {
+			t.Fatalf("got stamp %+v, want valid stamp of batch %x", res.Stamp, batch.ID)
+		}
+		if res.Batch.Owner != hex.EncodeToString(owner.Bytes()) {
+			t.Fatalf("got batch owner %s, want %s", res.Batch.Owner, owner)
+		}
+		if len(res.Indices) != len(indices) {
+			t.Fatalf("got indexes %v, want %v", res.Indices, indices)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/debug/chunks/"+swarm.RandAddress(t).String(), http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "chunk not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+}
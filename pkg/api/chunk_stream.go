@@ -48,6 +48,9 @@ func (s *Service) chunkUploadStreamHandler(w http.ResponseWriter, r *http.Reques
 	}
 
 	cctx := context.Background()
+	if t := requestTenant(r.Context()); t != nil {
+		cctx = context.WithValue(cctx, tenantContextKey{}, t)
+	}
 	if tag != nil {
 		cctx = sctx.SetTag(cctx, tag)
 	}
@@ -15,7 +15,6 @@ import (
 	"github.com/ethersphere/bee/pkg/log"
 	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	testingc "github.com/ethersphere/bee/pkg/storage/testing"
@@ -32,9 +31,8 @@ func TestChunkUploadStream(t *testing.T) {
 	wsHeaders.Set("Swarm-Postage-Batch-Id", batchOkStr)
 
 	var (
-		statestoreMock  = statestore.NewStateStore()
 		logger          = log.Noop
-		tag             = tags.NewTags(statestoreMock, logger)
+		tag             = tags.NewTags(nil, logger)
 		storerMock      = mock.NewStorer()
 		pinningMock     = pinning.NewServiceMock()
 		_, wsConn, _, _ = newTestServer(t, testServerOptions{
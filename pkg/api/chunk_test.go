@@ -7,16 +7,19 @@ package api_test
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"io"
+	"math/big"
 	"net/http"
 	"testing"
 
+	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/log"
 	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
+	"github.com/ethersphere/bee/pkg/postage"
 	mockbatchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 
 	"github.com/ethersphere/bee/pkg/tags"
 
@@ -37,9 +40,8 @@ func TestChunkUploadDownload(t *testing.T) {
 		chunksEndpoint  = "/chunks"
 		chunksResource  = func(a swarm.Address) string { return "/chunks/" + a.String() }
 		chunk           = testingc.GenerateTestRandomChunk()
-		statestoreMock  = statestore.NewStateStore()
 		logger          = log.Noop
-		tag             = tags.NewTags(statestoreMock, logger)
+		tag             = tags.NewTags(nil, logger)
 		storerMock      = mock.NewStorer()
 		pinningMock     = pinning.NewServiceMock()
 		client, _, _, _ = newTestServer(t, testServerOptions{
@@ -211,6 +213,60 @@ func TestHasChunkHandler(t *testing.T) {
 	})
 }
 
+func TestHasChunksHandler(t *testing.T) {
+	t.Parallel()
+
+	mockStorer := mock.NewStorer()
+	testServer, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mockStorer,
+	})
+
+	stored := testingc.GenerateTestRandomChunk()
+	if _, err := mockStorer.Put(context.Background(), storage.ModePutUpload, stored); err != nil {
+		t.Fatal(err)
+	}
+	missing := testingc.GenerateTestRandomChunk().Address()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, testServer, http.MethodPost, "/chunks/has", http.StatusOK,
+			jsonhttptest.WithJSONRequestBody(api.ChunksHasRequest{
+				References: []swarm.Address{stored.Address(), missing},
+			}),
+			jsonhttptest.WithExpectedJSONResponse(api.ChunksHasResponse{
+				Chunks: []api.ChunkHasData{
+					{Reference: stored.Address(), Has: true},
+					{Reference: missing, Has: false},
+				},
+			}),
+		)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, testServer, http.MethodPost, "/chunks/has", http.StatusOK,
+			jsonhttptest.WithJSONRequestBody(api.ChunksHasRequest{}),
+			jsonhttptest.WithExpectedJSONResponse(api.ChunksHasResponse{
+				Chunks: []api.ChunkHasData{},
+			}),
+		)
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, testServer, http.MethodPost, "/chunks/has", http.StatusBadRequest,
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte(`{"references":["abcd1100zz"]}`))),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid request",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
 func Test_chunkHandlers_invalidInputs(t *testing.T) {
 	t.Parallel()
 
@@ -270,7 +326,6 @@ func TestInvalidChunkParams(t *testing.T) {
 		chunksEndpoint = "/chunks"
 		chunk          = testingc.GenerateTestRandomChunk()
 		storerMock     = mock.NewStorer()
-		statestoreMock = statestore.NewStateStore()
 		pinningMock    = pinning.NewServiceMock()
 		logger         = log.Noop
 		existsFn       = func(id []byte) (bool, error) {
@@ -284,7 +339,7 @@ func TestInvalidChunkParams(t *testing.T) {
 		clientBatchUnusable, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:     storerMock,
 			Pinning:    pinningMock,
-			Tags:       tags.NewTags(statestoreMock, logger),
+			Tags:       tags.NewTags(nil, logger),
 			Logger:     logger,
 			Post:       mockpost.New(mockpost.WithAcceptAll()),
 			BatchStore: mockbatchstore.New(),
@@ -302,7 +357,7 @@ func TestInvalidChunkParams(t *testing.T) {
 		clientBatchExists, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:     storerMock,
 			Pinning:    pinningMock,
-			Tags:       tags.NewTags(statestoreMock, logger),
+			Tags:       tags.NewTags(nil, logger),
 			Logger:     logger,
 			Post:       mockpost.New(mockpost.WithAcceptAll()),
 			BatchStore: mockbatchstore.New(mockbatchstore.WithExistsFunc(existsFn)),
@@ -320,7 +375,7 @@ func TestInvalidChunkParams(t *testing.T) {
 		clientBatchNotFound, _, _, _ := newTestServer(t, testServerOptions{
 			Storer:  storerMock,
 			Pinning: pinningMock,
-			Tags:    tags.NewTags(statestoreMock, logger),
+			Tags:    tags.NewTags(nil, logger),
 			Logger:  logger,
 			Post:    mockpost.New(),
 		})
@@ -338,9 +393,8 @@ func TestDirectChunkUpload(t *testing.T) {
 	var (
 		chunksEndpoint  = "/chunks"
 		chunk           = testingc.GenerateTestRandomChunk()
-		statestoreMock  = statestore.NewStateStore()
 		logger          = log.Noop
-		tag             = tags.NewTags(statestoreMock, logger)
+		tag             = tags.NewTags(nil, logger)
 		storerMock      = mock.NewStorer()
 		pinningMock     = pinning.NewServiceMock()
 		client, _, _, _ = newTestServer(t, testServerOptions{
@@ -362,3 +416,69 @@ func TestDirectChunkUpload(t *testing.T) {
 		}),
 	)
 }
+
+func TestChunkUploadFallbackBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chunk         = testingc.GenerateTestRandomChunk()
+		fallbackBatch = bytes.Repeat([]byte{1}, 32)
+		// batch depth equal to the bucket depth allows a single chunk per bucket
+		primary  = postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 8, 8, 1000, true)
+		fallback = postage.NewStampIssuer("", "", fallbackBatch, big.NewInt(3), 8, 8, 1000, true)
+		bucket   = uint32(chunk.Address().Bytes()[0])
+	)
+
+	// fill the bucket of the chunk in the primary batch
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := postage.NewStamper(primary, crypto.NewDefaultSigner(pk)).Stamp(chunk.Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+		Tags:   tags.NewTags(nil, log.Noop),
+		Post:   mockpost.New(mockpost.WithIssuer(primary), mockpost.WithIssuer(fallback)),
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/chunks", http.StatusPaymentRequired,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(chunk.Data())),
+			jsonhttptest.WithExpectedJSONResponse(api.BucketFullResponse{
+				Code:             http.StatusPaymentRequired,
+				Message:          "batch is overissued",
+				BatchID:          batchOk,
+				Bucket:           &bucket,
+				Depth:            8,
+				BucketDepth:      8,
+				Utilization:      1,
+				BucketUpperBound: 1,
+				SuggestedDepth:   9,
+			}),
+		)
+	})
+
+	t.Run("invalid fallback", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/chunks", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageFallbackBatchIdHeader, "abcd"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(chunk.Data())),
+		)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/chunks", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageFallbackBatchIdHeader, hex.EncodeToString(fallbackBatch)),
+			jsonhttptest.WithRequestBody(bytes.NewReader(chunk.Data())),
+			jsonhttptest.WithExpectedJSONResponse(api.ChunkAddressResponse{Reference: chunk.Address()}),
+		)
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/chunktrace"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+// maxChunkTraces is the number of the latest traced
+// requests whose chunk traces are kept.
+const maxChunkTraces = 100
+
+type chunkTrace struct {
+	path     string
+	trace    *chunktrace.Trace
+	duration time.Duration // zero until the request is done
+}
+
+// chunkTraces keeps the chunk traces of the latest traced requests.
+type chunkTraces struct {
+	mu     sync.Mutex
+	ids    []string // in the order the requests were traced
+	traces map[string]*chunkTrace
+}
+
+func newChunkTraces() *chunkTraces {
+	return &chunkTraces{traces: make(map[string]*chunkTrace)}
+}
+
+func (c *chunkTraces) add(id string, t *chunkTrace) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.ids) == maxChunkTraces {
+		delete(c.traces, c.ids[0])
+		c.ids = c.ids[1:]
+	}
+	c.ids = append(c.ids, id)
+	c.traces[id] = t
+}
+
+func (c *chunkTraces) done(id string, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t, ok := c.traces[id]; ok {
+		t.duration = d
+	}
+}
+
+func (c *chunkTraces) get(id string) (chunkTrace, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.traces[id]
+	if !ok {
+		return chunkTrace{}, false
+	}
+	return *t, true
+}
+
+// chunkTraceHandler records how every chunk of the request is served if
+// the request has the SwarmTraceHeader set. The trace is returned by the
+// /traces/{id} endpoint under the id from the SwarmTraceIdHeader.
+func (s *Service) chunkTraceHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if traced, _ := strconv.ParseBool(r.Header.Get(SwarmTraceHeader)); !traced {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			s.logger.Debug("chunk trace id failed", "error", err)
+			h.ServeHTTP(w, r)
+			return
+		}
+		id := hex.EncodeToString(b)
+
+		t := chunktrace.New()
+		s.chunkTraces.add(id, &chunkTrace{path: r.URL.Path, trace: t})
+		w.Header().Add("Access-Control-Expose-Headers", SwarmTraceIdHeader)
+		w.Header().Set(SwarmTraceIdHeader, id)
+
+		h.ServeHTTP(w, r.WithContext(chunktrace.WithTrace(r.Context(), t)))
+		s.chunkTraces.done(id, time.Since(t.Started()))
+	})
+}
+
+type chunkTraceRecord struct {
+	Address swarm.Address `json:"address"`
+	Local   bool          `json:"local"`
+	Latency int64         `json:"latency"` // in nanoseconds
+	Peers   int           `json:"peers"`
+	Error   string        `json:"error,omitempty"`
+}
+
+type chunkTraceResponse struct {
+	ID               string             `json:"id"`
+	Path             string             `json:"path"`
+	StartedAt        time.Time          `json:"startedAt"`
+	Done             bool               `json:"done"`
+	Duration         int64              `json:"duration"` // in nanoseconds
+	Chunks           int                `json:"chunks"`
+	LocalHits        int                `json:"localHits"`
+	Retrieved        int                `json:"retrieved"`
+	Failed           int                `json:"failed"`
+	RetrievalLatency int64              `json:"retrievalLatency"` // sum of the latencies of the retrieved chunks in nanoseconds
+	Records          []chunkTraceRecord `json:"records"`
+}
+
+// chunkTraceGetHandler returns the per chunk timing summary of the traced request.
+func (s *Service) chunkTraceGetHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	t, ok := s.chunkTraces.get(id)
+	if !ok {
+		jsonhttp.NotFound(w, "trace not found")
+		return
+	}
+
+	res := chunkTraceResponse{
+		ID:        id,
+		Path:      t.path,
+		StartedAt: t.trace.Started(),
+		Done:      t.duration > 0,
+		Duration:  int64(t.duration),
+		Records:   make([]chunkTraceRecord, 0),
+	}
+	for _, rec := range t.trace.Records() {
+		cr := chunkTraceRecord{
+			Address: rec.Address,
+			Local:   rec.Local,
+			Latency: int64(rec.Latency),
+			Peers:   rec.Peers(),
+		}
+		switch {
+		case rec.Err != nil:
+			cr.Error = rec.Err.Error()
+			res.Failed++
+		case rec.Local:
+			res.LocalHits++
+		default:
+			res.Retrieved++
+			res.RetrievalLatency += int64(rec.Latency)
+		}
+		res.Records = append(res.Records, cr)
+	}
+	res.Chunks = len(res.Records)
+	jsonhttp.OK(w, res)
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/chunktrace"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/netstore"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+// networkRetrieval retrieves the chunks from the storer of the network.
+type networkRetrieval struct {
+	storer storage.Storer
+}
+
+func (n networkRetrieval) RetrieveChunk(ctx context.Context, addr, _ swarm.Address) (swarm.Chunk, error) {
+	chunktrace.AddPeer(ctx)
+	ch, err := n.storer.Get(ctx, storage.ModeGetRequest, addr)
+	if err != nil {
+		return nil, err
+	}
+	return ch.WithStamp(postagetesting.MustNewStamp()), nil
+}
+
+// nolint:paralleltest
+func TestChunkTrace(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		network = mock.NewStorer()
+		ns      = netstore.New(mock.NewStorer(), func(c swarm.Chunk, _ []byte) (swarm.Chunk, error) { return c, nil }, networkRetrieval{storer: network}, log.Noop)
+		data    = bytes.Repeat([]byte{1, 2, 3}, swarm.ChunkSize)
+	)
+	testutil.CleanupCloser(t, ns)
+
+	ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, network, storage.ModePutUpload, false), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: ns,
+		Logger: log.Noop,
+	})
+
+	t.Run("not traced", func(t *testing.T) {
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+ref.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+		if id := header.Get(api.SwarmTraceIdHeader); id != "" {
+			t.Fatalf("got trace id %s, want none", id)
+		}
+	})
+
+	t.Run("traced", func(t *testing.T) {
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+ref.String(), http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmTraceHeader, "true"),
+			jsonhttptest.WithExpectedResponse(data),
+		)
+		id := header.Get(api.SwarmTraceIdHeader)
+		if id == "" {
+			t.Fatal("no trace id")
+		}
+
+		var res api.ChunkTraceResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/traces/"+id, http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.ID != id || res.Path != "/bytes/"+ref.String() || !res.Done || res.Duration <= 0 {
+			t.Fatalf("unexpected trace %+v", res)
+		}
+		// the root and the leaves, some of them may be served locally
+		// if the previous request already stored them
+		if res.Chunks < 2 || res.Chunks != len(res.Records) || res.Chunks != res.LocalHits+res.Retrieved || res.Failed != 0 {
+			t.Fatalf("unexpected trace summary %+v", res)
+		}
+		for _, r := range res.Records {
+			if r.Local != (r.Peers == 0) || r.Error != "" {
+				t.Fatalf("unexpected record %+v", r)
+			}
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/traces/abcd", http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "trace not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+}
@@ -0,0 +1,456 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/collection"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/manifest/feedwriter"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+// collectionTopicPrefix separates the topics of the collections
+// from the topics of the other feeds of the node.
+const collectionTopicPrefix = "collections/"
+
+// maxCollectionDocumentSize is the size limit of the stored documents.
+const maxCollectionDocumentSize = 1024 * 1024
+
+const errCollectionNotFound = "collection not found"
+
+type collectionRequest struct {
+	Indexes []string `json:"indexes"`
+}
+
+type collectionResponse struct {
+	Name      string        `json:"name"`
+	Reference swarm.Address `json:"reference"`
+	Indexes   []string      `json:"indexes"`
+}
+
+type collectionDocumentResponse struct {
+	ID        string          `json:"id"`
+	Reference swarm.Address   `json:"reference"`
+	Document  json.RawMessage `json:"document,omitempty"`
+}
+
+type collectionDocumentsResponse struct {
+	Documents []collectionDocumentResponse `json:"documents"`
+}
+
+// collectionRoot resolves the root manifest of the collection from the
+// latest update of its feed. The returned index is the index of the next
+// update of the feed. The root is zero if the collection does not exist.
+func (s *Service) collectionRoot(ctx context.Context, name string) (feed *feeds.Feed, root swarm.Address, next feeds.Index, err error) {
+	if s.signer == nil {
+		return nil, root, nil, errors.New("no signer")
+	}
+	owner, err := s.signer.EthereumAddress()
+	if err != nil {
+		return nil, root, nil, err
+	}
+	topic, err := crypto.LegacyKeccak256([]byte(collectionTopicPrefix + name))
+	if err != nil {
+		return nil, root, nil, err
+	}
+	feed = feeds.New(topic, owner)
+
+	l, err := s.feedFactory.NewLookup(feeds.Sequence, feed)
+	if err != nil {
+		return nil, root, nil, err
+	}
+	ch, _, next, err := l.At(ctx, time.Now().Unix(), 0)
+	if err != nil {
+		return nil, root, nil, err
+	}
+	if ch == nil {
+		return feed, swarm.ZeroAddress, next, nil
+	}
+	root, _, err = parseFeedUpdate(ch)
+	if err != nil {
+		return nil, root, nil, err
+	}
+	return feed, root, next, nil
+}
+
+// collectionReadonly returns the collection, responding with the error
+// if it can not be resolved or it does not exist.
+func (s *Service) collectionReadonly(w http.ResponseWriter, r *http.Request, logger log.Logger, name string) (*collection.Collection, bool) {
+	_, root, _, err := s.collectionRoot(r.Context(), name)
+	if err != nil {
+		logger.Debug("resolve collection failed", "name", name, "error", err)
+		logger.Error(nil, "resolve collection failed")
+		jsonhttp.InternalServerError(w, "resolve collection failed")
+		return nil, false
+	}
+	if root.IsZero() {
+		jsonhttp.NotFound(w, errCollectionNotFound)
+		return nil, false
+	}
+	return collection.New(s.storer, root, collection.Options{}), true
+}
+
+// collectionWritable returns the collection which publishes every change
+// as the new update of its feed, responding with the error if it can not
+// be resolved or, unless create is set, it does not exist. The returned
+// function waits for the changed chunks to be synced.
+func (s *Service) collectionWritable(w http.ResponseWriter, r *http.Request, logger log.Logger, name string, create bool) (*collection.Collection, func() error, bool) {
+	feed, root, next, err := s.collectionRoot(r.Context(), name)
+	if err != nil {
+		logger.Debug("resolve collection failed", "name", name, "error", err)
+		logger.Error(nil, "resolve collection failed")
+		jsonhttp.InternalServerError(w, "resolve collection failed")
+		return nil, nil, false
+	}
+	if root.IsZero() && !create {
+		jsonhttp.NotFound(w, errCollectionNotFound)
+		return nil, nil, false
+	}
+
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debug("putter failed", "error", err)
+		logger.Error(nil, "putter failed")
+		switch {
+		case errors.Is(err, errBatchNotAllowed):
+			jsonhttp.Forbidden(w, "batch not allowed")
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, errInvalidPostageBatch):
+			jsonhttp.BadRequest(w, "invalid batch id")
+		case errors.Is(err, errUnsupportedDevNodeOperation):
+			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		case errors.Is(err, clockskew.ErrClockSkewed):
+			jsonhttp.ServiceUnavailable(w, err.Error())
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return nil, nil, false
+	}
+	commit, err := feedwriter.FeedCommit(putter, s.signer, feed.Topic, next)
+	if err != nil {
+		logger.Debug("feed putter failed", "error", err)
+		logger.Error(nil, "feed putter failed")
+		jsonhttp.InternalServerError(w, "feed putter failed")
+		return nil, nil, false
+	}
+
+	return collection.New(s.storer, root, collection.Options{
+		Storer: putter,
+		Mode:   requestModePut(r),
+		Commit: commit,
+	}), wait, true
+}
+
+// collectionError responds with the error of the failed operation on the collection.
+func collectionError(w http.ResponseWriter, logger log.Logger, msg string, err error) {
+	logger.Debug(msg, "error", err)
+	switch {
+	case errors.Is(err, collection.ErrNotFound):
+		jsonhttp.NotFound(w, "document not found")
+	case errors.Is(err, collection.ErrInvalidID):
+		jsonhttp.BadRequest(w, "invalid document id")
+	case errors.Is(err, collection.ErrInvalidDocument):
+		jsonhttp.BadRequest(w, "invalid document")
+	case errors.Is(err, collection.ErrInvalidField):
+		jsonhttp.BadRequest(w, "invalid field name")
+	case errors.Is(err, collection.ErrNotIndexed):
+		jsonhttp.BadRequest(w, err.Error())
+	case errors.Is(err, postage.ErrBucketFull):
+		jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
+	default:
+		logger.Error(nil, msg)
+		jsonhttp.InternalServerError(w, msg)
+	}
+}
+
+// collectionGetHandler returns the root manifest and the indexed fields of the collection.
+func (s *Service) collectionGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("get_collection").Build())
+
+	paths := struct {
+		Name string `map:"name" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	c, ok := s.collectionReadonly(w, r, logger, paths.Name)
+	if !ok {
+		return
+	}
+	indexes, err := c.Indexes(r.Context())
+	if err != nil {
+		collectionError(w, logger, "get indexes failed", err)
+		return
+	}
+	if indexes == nil {
+		indexes = []string{}
+	}
+	jsonhttp.OK(w, collectionResponse{
+		Name:      paths.Name,
+		Reference: c.Root(),
+		Indexes:   indexes,
+	})
+}
+
+// collectionPutHandler creates the collection or changes its indexed fields,
+// the indexes of the stored documents are rebuilt.
+func (s *Service) collectionPutHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("put_collection").Build())
+
+	paths := struct {
+		Name string `map:"name" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+	var req collectionRequest
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			logger.Debug("unmarshal request body failed", "error", err)
+			logger.Error(nil, "unmarshal request body failed")
+			jsonhttp.BadRequest(w, "invalid request")
+			return
+		}
+	}
+
+	// the changes of the collection are serialized,
+	// as every change depends on the previous update
+	s.collectionsMu.Lock()
+	defer s.collectionsMu.Unlock()
+
+	c, wait, ok := s.collectionWritable(w, r, logger, paths.Name, true)
+	if !ok {
+		return
+	}
+	if err := c.SetIndexes(r.Context(), req.Indexes); err != nil {
+		collectionError(w, logger, "set indexes failed", err)
+		return
+	}
+	if err := wait(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		jsonhttp.InternalServerError(w, "sync failed")
+		return
+	}
+	s.watchReceipts(logger, c.Root())
+
+	indexes, err := c.Indexes(r.Context())
+	if err != nil {
+		collectionError(w, logger, "get indexes failed", err)
+		return
+	}
+	if indexes == nil {
+		indexes = []string{}
+	}
+	jsonhttp.OK(w, collectionResponse{
+		Name:      paths.Name,
+		Reference: c.Root(),
+		Indexes:   indexes,
+	})
+}
+
+// collectionDocumentsGetHandler returns the documents of the collection
+// with the values of the indexed fields given by the query parameters.
+func (s *Service) collectionDocumentsGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("get_collection_documents").Build())
+
+	paths := struct {
+		Name string `map:"name" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	filters := make(map[string]string)
+	for field, values := range r.URL.Query() {
+		if len(values) != 1 {
+			jsonhttp.BadRequest(w, "repeated query param "+field)
+			return
+		}
+		filters[field] = values[0]
+	}
+
+	c, ok := s.collectionReadonly(w, r, logger, paths.Name)
+	if !ok {
+		return
+	}
+	docs, err := c.Find(r.Context(), filters)
+	if err != nil {
+		collectionError(w, logger, "find documents failed", err)
+		return
+	}
+
+	res := collectionDocumentsResponse{Documents: make([]collectionDocumentResponse, 0, len(docs))}
+	for _, d := range docs {
+		res.Documents = append(res.Documents, collectionDocumentResponse{
+			ID:        d.ID,
+			Reference: d.Reference,
+			Document:  d.Data,
+		})
+	}
+	jsonhttp.OK(w, res)
+}
+
+// collectionDocumentPostHandler stores the document under the generated id.
+func (s *Service) collectionDocumentPostHandler(w http.ResponseWriter, r *http.Request) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		s.logger.Debug("generate document id failed", "error", err)
+		s.logger.Error(nil, "generate document id failed")
+		jsonhttp.InternalServerError(w, "generate document id failed")
+		return
+	}
+	s.collectionDocumentStore(w, r, "post_collection_document", hex.EncodeToString(b), http.StatusCreated)
+}
+
+// collectionDocumentPutHandler stores the document under the id,
+// replacing the document with the same id.
+func (s *Service) collectionDocumentPutHandler(w http.ResponseWriter, r *http.Request) {
+	s.collectionDocumentStore(w, r, "put_collection_document", mux.Vars(r)["id"], http.StatusOK)
+}
+
+func (s *Service) collectionDocumentStore(w http.ResponseWriter, r *http.Request, name, id string, status int) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName(name).Build())
+
+	paths := struct {
+		Name string `map:"name" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+	if !collection.ValidID(id) {
+		jsonhttp.BadRequest(w, "invalid document id")
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+
+	s.collectionsMu.Lock()
+	defer s.collectionsMu.Unlock()
+
+	c, wait, ok := s.collectionWritable(w, r, logger, paths.Name, false)
+	if !ok {
+		return
+	}
+	doc, err := c.Put(r.Context(), id, data)
+	if err != nil {
+		collectionError(w, logger, "store document failed", err)
+		return
+	}
+	if err := wait(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		jsonhttp.InternalServerError(w, "sync failed")
+		return
+	}
+	s.watchReceipts(logger, c.Root())
+
+	jsonhttp.Respond(w, status, collectionDocumentResponse{
+		ID:        doc.ID,
+		Reference: doc.Reference,
+	})
+}
+
+// collectionDocumentGetHandler returns the document with the id.
+func (s *Service) collectionDocumentGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("get_collection_document").Build())
+
+	paths := struct {
+		Name string `map:"name" validate:"required"`
+		ID   string `map:"id" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	c, ok := s.collectionReadonly(w, r, logger, paths.Name)
+	if !ok {
+		return
+	}
+	doc, err := c.Get(r.Context(), paths.ID)
+	if err != nil {
+		collectionError(w, logger, "get document failed", err)
+		return
+	}
+	// the stored document is written as is, jsonhttp would wrap
+	// the json.RawMessage stringer in the status response
+	w.Header().Set(contentTypeHeader, "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(doc.Data)
+}
+
+// collectionDocumentDeleteHandler removes the document with the id.
+func (s *Service) collectionDocumentDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("delete_collection_document").Build())
+
+	paths := struct {
+		Name string `map:"name" validate:"required"`
+		ID   string `map:"id" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	s.collectionsMu.Lock()
+	defer s.collectionsMu.Unlock()
+
+	c, wait, ok := s.collectionWritable(w, r, logger, paths.Name, false)
+	if !ok {
+		return
+	}
+	if err := c.Delete(r.Context(), paths.ID); err != nil {
+		collectionError(w, logger, "delete document failed", err)
+		return
+	}
+	if err := wait(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		jsonhttp.InternalServerError(w, "sync failed")
+		return
+	}
+	s.watchReceipts(logger, c.Root())
+
+	jsonhttp.OK(w, nil)
+}
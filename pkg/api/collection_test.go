@@ -0,0 +1,172 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+)
+
+func TestCollections(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storer          = mock.NewStorer()
+		pk, _           = crypto.GenerateSecp256k1Key()
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storer,
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+			Feeds:  factory.New(storer),
+			Signer: crypto.NewDefaultSigner(pk),
+		})
+		batch = jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr)
+	)
+
+	find := func(t *testing.T, query string) []string {
+		t.Helper()
+
+		var res api.CollectionDocumentsResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/collections/users/documents"+query, http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		ids := []string{}
+		for _, d := range res.Documents {
+			ids = append(ids, d.ID)
+		}
+		return ids
+	}
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/collections/users", http.StatusNotFound,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "collection not found",
+			Code:    http.StatusNotFound,
+		}),
+	)
+	jsonhttptest.Request(t, client, http.MethodPut, "/collections/users/documents/alice", http.StatusNotFound,
+		batch,
+		jsonhttptest.WithRequestBody(strings.NewReader(`{"age":30}`)),
+	)
+
+	var created api.CollectionResponse
+	jsonhttptest.Request(t, client, http.MethodPut, "/collections/users", http.StatusOK,
+		batch,
+		jsonhttptest.WithJSONRequestBody(api.CollectionRequest{Indexes: []string{"age"}}),
+		jsonhttptest.WithUnmarshalJSONResponse(&created),
+	)
+	if created.Name != "users" || created.Reference.IsZero() || len(created.Indexes) != 1 || created.Indexes[0] != "age" {
+		t.Fatalf("got collection %+v", created)
+	}
+
+	for id, doc := range map[string]string{
+		"alice": `{"name":"alice","age":30}`,
+		"bob":   `{"name":"bob","age":25}`,
+	} {
+		jsonhttptest.Request(t, client, http.MethodPut, "/collections/users/documents/"+id, http.StatusOK,
+			batch,
+			jsonhttptest.WithRequestBody(strings.NewReader(doc)),
+		)
+	}
+	var posted api.CollectionDocumentResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/collections/users/documents", http.StatusCreated,
+		batch,
+		jsonhttptest.WithRequestBody(strings.NewReader(`{"name":"carol","age":30}`)),
+		jsonhttptest.WithUnmarshalJSONResponse(&posted),
+	)
+
+	t.Run("get", func(t *testing.T) {
+		t.Parallel()
+
+		var doc map[string]interface{}
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/collections/users/documents/bob", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&doc),
+		)
+		if doc["name"] != "bob" {
+			t.Fatalf("got document %v", doc)
+		}
+		if got := header.Get("Content-Type"); got != "application/json" {
+			t.Fatalf("got content type %q, want %q", got, "application/json")
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/collections/users/documents/dave", http.StatusNotFound)
+	})
+
+	t.Run("find", func(t *testing.T) {
+		t.Parallel()
+
+		if got := find(t, ""); len(got) != 3 {
+			t.Fatalf("got documents %v, want 3", got)
+		}
+		want := []string{"alice", posted.ID}
+		sort.Strings(want)
+		if got := find(t, "?age=30"); !reflect.DeepEqual(got, want) {
+			t.Fatalf("got documents %v, want %v", got, want)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/collections/users/documents?name=bob", http.StatusBadRequest)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPut, "/collections/users/documents/a.b", http.StatusBadRequest,
+			batch,
+			jsonhttptest.WithRequestBody(strings.NewReader(`{}`)),
+		)
+		jsonhttptest.Request(t, client, http.MethodPut, "/collections/users/documents/x", http.StatusBadRequest,
+			batch,
+			jsonhttptest.WithRequestBody(strings.NewReader(`[1, 2]`)),
+		)
+		jsonhttptest.Request(t, client, http.MethodPut, "/collections/users/documents/x", http.StatusBadRequest,
+			jsonhttptest.WithRequestBody(strings.NewReader(`{}`)),
+		)
+	})
+}
+
+func TestCollectionDelete(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storer          = mock.NewStorer()
+		pk, _           = crypto.GenerateSecp256k1Key()
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storer,
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+			Feeds:  factory.New(storer),
+			Signer: crypto.NewDefaultSigner(pk),
+		})
+		batch = jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr)
+	)
+
+	jsonhttptest.Request(t, client, http.MethodPut, "/collections/users", http.StatusOK, batch)
+	jsonhttptest.Request(t, client, http.MethodPut, "/collections/users/documents/alice", http.StatusOK,
+		batch,
+		jsonhttptest.WithRequestBody(strings.NewReader(`{"name":"alice"}`)),
+	)
+	jsonhttptest.Request(t, client, http.MethodDelete, "/collections/users/documents/alice", http.StatusOK, batch)
+	jsonhttptest.Request(t, client, http.MethodGet, "/collections/users/documents/alice", http.StatusNotFound)
+	jsonhttptest.Request(t, client, http.MethodDelete, "/collections/users/documents/alice", http.StatusNotFound, batch)
+
+	var res api.CollectionDocumentsResponse
+	jsonhttptest.Request(t, client, http.MethodGet, "/collections/users/documents", http.StatusOK,
+		jsonhttptest.WithUnmarshalJSONResponse(&res),
+	)
+	if len(res.Documents) != 0 {
+		b, _ := json.Marshal(res)
+		t.Fatalf("got documents %s, want none", b)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gorilla/handlers"
+)
+
+// DefaultCompressibleContentTypes are the content types which are compressed
+// on download if the client accepts the compressed content encoding. The
+// entries ending with "/*" match all of the subtypes of the type.
+var DefaultCompressibleContentTypes = []string{
+	"text/*",
+	"application/javascript",
+	"application/json",
+	"application/xml",
+	"application/wasm",
+	"image/svg+xml",
+}
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// compressHandler compresses the responses with gzip, except for the responses
+// to the HEAD requests and, if the compressible content types are configured,
+// the content downloads, which negotiate the content encoding on their own.
+func (s *Service) compressHandler(h http.Handler) http.Handler {
+	compress := handlers.CompressHandler(h)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead || (len(s.CompressibleContentTypes) > 0 && isDownloadRequest(r)) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		compress.ServeHTTP(w, r)
+	})
+}
+
+// isDownloadRequest reports whether the request is
+// the download of the content from /bzz or /bytes.
+func isDownloadRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	path := r.URL.Path
+	if version, ok := pathAPIVersion(path); ok {
+		path = strings.TrimPrefix(path, "/"+version)
+	}
+	return strings.HasPrefix(path, "/bzz/") || strings.HasPrefix(path, "/bytes/")
+}
+
+// compressibleContentType reports whether the content of the given
+// type is on the allow-list of the compressible content types.
+func (s *Service) compressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range s.CompressibleContentTypes {
+		t = strings.ToLower(t)
+		if prefix := strings.TrimSuffix(t, "*"); prefix != t {
+			if strings.HasPrefix(mediaType, prefix) {
+				return true
+			}
+			continue
+		}
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding returns the most preferred content encoding out
+// of the supported ones which is acceptable for the client, or an
+// empty string if the content should be sent as it is.
+func negotiateEncoding(r *http.Request) string {
+	var (
+		encoding string
+		quality  float64
+	)
+	for _, v := range r.Header.Values("Accept-Encoding") {
+		for _, part := range strings.Split(v, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != encodingBrotli && name != encodingGzip {
+				continue
+			}
+			q := 1.0
+			if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				var err error
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			// brotli is preferred on equal quality as it compresses better
+			if q > quality || (q == quality && name == encodingBrotli) {
+				encoding, quality = name, q
+			}
+		}
+	}
+	return encoding
+}
+
+// compressResponseWriter compresses the body of the successful response
+// with the negotiated encoding while streaming it to the client.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding    string
+	writer      io.WriteCloser
+	wroteHeader bool
+}
+
+func newCompressResponseWriter(w http.ResponseWriter, encoding string) *compressResponseWriter {
+	return &compressResponseWriter{ResponseWriter: w, encoding: encoding}
+}
+
+func (w *compressResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if code == http.StatusOK {
+		w.Header().Del("Content-Length")
+		w.Header().Set("Content-Encoding", w.encoding)
+		switch w.encoding {
+		case encodingBrotli:
+			w.writer = brotli.NewWriter(w.ResponseWriter)
+		case encodingGzip:
+			w.writer = gzip.NewWriter(w.ResponseWriter)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.writer == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.writer.Write(b)
+}
+
+// Close flushes the remaining compressed data to the client.
+func (w *compressResponseWriter) Close() error {
+	if w.writer == nil {
+		return nil
+	}
+	return w.writer.Close()
+}
@@ -0,0 +1,145 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestDownloadCompression(t *testing.T) {
+	t.Parallel()
+
+	var (
+		data            = []byte(strings.Repeat("<p>compressible text</p>", 1000))
+		logger          = log.Noop
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:                   smock.NewStorer(),
+			Tags:                     tags.NewTags(nil, logger),
+			Logger:                   logger,
+			Post:                     mockpost.New(mockpost.WithAcceptAll()),
+			CompressibleContentTypes: []string{"text/*"},
+		})
+	)
+
+	upload := func(t *testing.T, contentType string) string {
+		t.Helper()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=index.html", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader("Content-Type", contentType),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		return resp.Reference.String()
+	}
+
+	html := upload(t, "text/html; charset=utf-8")
+	binary := upload(t, "application/octet-stream")
+
+	for _, tc := range []struct {
+		name           string
+		acceptEncoding string
+		wantEncoding   string
+		decompress     func(io.Reader) (io.Reader, error)
+	}{{
+		name:           "gzip",
+		acceptEncoding: "gzip",
+		wantEncoding:   "gzip",
+		decompress:     func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+	}, {
+		name:           "brotli",
+		acceptEncoding: "gzip, deflate, br",
+		wantEncoding:   "br",
+		decompress:     func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	}, {
+		name:           "quality",
+		acceptEncoding: "br;q=0.5, gzip;q=0.8",
+		wantEncoding:   "gzip",
+		decompress:     func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+	}} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var body []byte
+			header := jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+html, http.StatusOK,
+				jsonhttptest.WithRequestHeader("Accept-Encoding", tc.acceptEncoding),
+				jsonhttptest.WithPutResponseBody(&body),
+			)
+			if got := header.Get("Content-Encoding"); got != tc.wantEncoding {
+				t.Fatalf("got content encoding %q, want %q", got, tc.wantEncoding)
+			}
+			if got := header.Get("Vary"); got != "Accept-Encoding" {
+				t.Fatalf("got vary %q, want %q", got, "Accept-Encoding")
+			}
+			if len(body) >= len(data) {
+				t.Fatalf("compressed body of %d bytes is not smaller than the content of %d bytes", len(body), len(data))
+			}
+			r, err := tc.decompress(bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatal("decompressed content does not match the uploaded one")
+			}
+		})
+	}
+
+	t.Run("not accepted", func(t *testing.T) {
+		t.Parallel()
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+html, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "identity"),
+			jsonhttptest.WithExpectedResponse(data),
+		)
+		if got := header.Get("Content-Encoding"); got != "" {
+			t.Fatalf("got content encoding %q, want none", got)
+		}
+	})
+
+	t.Run("range", func(t *testing.T) {
+		t.Parallel()
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+html, http.StatusPartialContent,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "gzip"),
+			jsonhttptest.WithRequestHeader("Range", "bytes=0-9"),
+			jsonhttptest.WithExpectedResponse(data[:10]),
+		)
+		if got := header.Get("Content-Encoding"); got != "" {
+			t.Fatalf("got content encoding %q, want none", got)
+		}
+	})
+
+	t.Run("not compressible", func(t *testing.T) {
+		t.Parallel()
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+binary, http.StatusOK,
+			jsonhttptest.WithRequestHeader("Accept-Encoding", "gzip"),
+			jsonhttptest.WithExpectedResponse(data),
+		)
+		if got := header.Get("Content-Encoding"); got != "" {
+			t.Fatalf("got content encoding %q, want none", got)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/gorilla/mux"
+)
+
+// localStorer retrieves the chunks only from the local store,
+// so that the missing chunks are not retrieved from the network.
+type localStorer struct {
+	storage.Storer
+}
+
+// Get implements the storage.Getter interface.
+func (s localStorer) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	has, err := s.Has(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, storage.ErrNotFound
+	}
+	return s.Storer.Get(ctx, mode, addr)
+}
+
+// contentDeleteHandler unpins the content of the reference and removes
+// its chunks from the local store, except for the chunks the node is
+// responsible for in the reserve. The pending push sync of the chunks
+// is cancelled.
+func (s *Service) contentDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("delete_content").Build()
+
+	paths := struct {
+		Reference swarm.Address `map:"reference" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	has, err := s.hasPin(r.Context(), paths.Reference)
+	if err != nil {
+		logger.Debug("delete content: has pin failed", "reference", paths.Reference, "error", err)
+		logger.Error(nil, "delete content: has pin failed")
+		jsonhttp.InternalServerError(w, "delete content: checking of tracking pin")
+		return
+	}
+	if has {
+		if err := s.pinning.DeletePin(r.Context(), paths.Reference); err != nil {
+			logger.Debug("delete content: delete pin failed", "reference", paths.Reference, "error", err)
+			logger.Error(nil, "delete content: delete pin failed")
+			jsonhttp.InternalServerError(w, "delete content: deletion of pin failed")
+			return
+		}
+	}
+
+	var (
+		seen  = make(map[string]struct{})
+		addrs []swarm.Address
+	)
+	err = traversal.New(localStorer{s.storer}).Traverse(r.Context(), paths.Reference, func(addr swarm.Address) error {
+		if _, ok := seen[addr.ByteString()]; !ok {
+			seen[addr.ByteString()] = struct{}{}
+			addrs = append(addrs, addr)
+		}
+		return nil
+	})
+	// the chunks which are already missing are skipped
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		logger.Debug("delete content: traversal failed", "reference", paths.Reference, "error", err)
+		logger.Error(nil, "delete content: traversal failed")
+		jsonhttp.InternalServerError(w, "delete content: traversal failed")
+		return
+	}
+	if len(addrs) == 0 && !has {
+		jsonhttp.NotFound(w, nil)
+		return
+	}
+
+	if err := s.storer.Set(r.Context(), storage.ModeSetPurge, addrs...); err != nil {
+		logger.Debug("delete content: purge failed", "reference", paths.Reference, "error", err)
+		logger.Error(nil, "delete content: purge failed")
+		jsonhttp.InternalServerError(w, "delete content: purge failed")
+		return
+	}
+
+	jsonhttp.OK(w, nil)
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/pinning"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+func TestContentDelete(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger          = log.Noop
+		storerMock      = mock.NewStorer()
+		traverser       = traversal.New(storerMock)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:    storerMock,
+			Traversal: traverser,
+			Tags:      tags.NewTags(nil, logger),
+			Pinning:   pinning.NewService(storerMock, statestore.NewStateStore(), traverser),
+			Logger:    logger,
+			Post:      mockpost.New(mockpost.WithAcceptAll()),
+		})
+	)
+
+	upload := func(t *testing.T, data []byte, pin bool) swarm.Address {
+		t.Helper()
+
+		var res api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmPinHeader, "false"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if pin {
+			jsonhttptest.Request(t, client, http.MethodPost, "/pins/"+res.Reference.String(), http.StatusCreated)
+		}
+		return res.Reference
+	}
+
+	chunksOf := func(t *testing.T, ref swarm.Address) []swarm.Address {
+		t.Helper()
+
+		var addrs []swarm.Address
+		err := traverser.Traverse(context.Background(), ref, func(addr swarm.Address) error {
+			addrs = append(addrs, addr)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addrs
+	}
+
+	t.Run("pinned", func(t *testing.T) {
+		t.Parallel()
+
+		ref := upload(t, testutil.RandBytes(t, 3*swarm.ChunkSize), true)
+		chunks := chunksOf(t, ref)
+
+		jsonhttptest.Request(t, client, http.MethodDelete, "/content/"+ref.String(), http.StatusOK)
+
+		for _, addr := range chunks {
+			if has, _ := storerMock.Has(context.Background(), addr); has {
+				t.Fatalf("chunk %s not purged", addr)
+			}
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/pins/"+ref.String(), http.StatusNotFound)
+		jsonhttptest.Request(t, client, http.MethodDelete, "/content/"+ref.String(), http.StatusNotFound)
+	})
+
+	t.Run("chunks pinned by other content are kept", func(t *testing.T) {
+		t.Parallel()
+
+		data := testutil.RandBytes(t, 2*swarm.ChunkSize)
+		ref := upload(t, data, false)
+		other := upload(t, data[:swarm.ChunkSize], true)
+
+		jsonhttptest.Request(t, client, http.MethodDelete, "/content/"+ref.String(), http.StatusOK)
+
+		if has, _ := storerMock.Has(context.Background(), ref); has {
+			t.Fatal("root chunk not purged")
+		}
+		if has, _ := storerMock.Has(context.Background(), other); !has {
+			t.Fatal("chunk pinned by other content purged")
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodDelete, "/content/"+swarm.RandAddress(t).String(), http.StatusNotFound)
+	})
+}
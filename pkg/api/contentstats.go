@@ -0,0 +1,116 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/contentstats"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+type contentStatsRootContextKey struct{}
+
+// setContentStatsRoot sets the root reference of the content
+// the request is recorded under by the contentStatsHandler.
+func setContentStatsRoot(ctx context.Context, root swarm.Address) {
+	if p, ok := ctx.Value(contentStatsRootContextKey{}).(*swarm.Address); ok {
+		*p = root
+	}
+}
+
+// contentStatsHandler records the access of the client to the content if
+// the content stats are enabled and the handler sets the root reference of
+// the content. Only the successful responses are recorded.
+func (s *Service) contentStatsHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.contentStats == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		root := new(swarm.Address)
+		cw := &countingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(cw, r.WithContext(context.WithValue(r.Context(), contentStatsRootContextKey{}, root)))
+		if root.IsZero() || cw.status != http.StatusOK && cw.status != http.StatusPartialContent {
+			return
+		}
+
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		if err := s.contentStats.Record(*root, client, cw.written, cw.status == http.StatusOK); err != nil {
+			s.logger.Debug("record content stats failed", "reference", root, "error", err)
+		}
+	})
+}
+
+// countingResponseWriter counts the bytes of the response body.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	written     uint64
+	wroteHeader bool
+}
+
+func (w *countingResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *countingResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.written += uint64(n)
+	return n, err
+}
+
+type contentStatsResponse struct {
+	Reference     swarm.Address `json:"reference"`
+	Downloads     uint64        `json:"downloads"`
+	BytesServed   uint64        `json:"bytesServed"`
+	UniqueClients uint64        `json:"uniqueClients"`
+}
+
+// contentStatsGetHandler returns the aggregated access statistics
+// of the content under the root reference.
+func (s *Service) contentStatsGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_stats_content").Build()
+
+	paths := struct {
+		Reference swarm.Address `map:"reference" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	stats, err := s.contentStats.Get(paths.Reference)
+	switch {
+	case errors.Is(err, contentstats.ErrNotFound):
+		jsonhttp.NotFound(w, "content stats not found")
+		return
+	case err != nil:
+		logger.Debug("get content stats failed", "reference", paths.Reference, "error", err)
+		logger.Error(nil, "get content stats failed")
+		jsonhttp.InternalServerError(w, "get content stats failed")
+		return
+	}
+	jsonhttp.OK(w, contentStatsResponse{
+		Reference:     paths.Reference,
+		Downloads:     stats.Downloads,
+		BytesServed:   stats.BytesServed,
+		UniqueClients: stats.UniqueClients,
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/contentstats"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+func TestContentStats(t *testing.T) {
+	t.Parallel()
+
+	logger := log.Noop
+	service, err := contentstats.New(statestore.NewStateStore(), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, service)
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:       mock.NewStorer(),
+		Tags:         tags.NewTags(nil, logger),
+		Logger:       logger,
+		Post:         mockpost.New(mockpost.WithAcceptAll()),
+		ContentStats: service,
+	})
+
+	content := []byte("content stats")
+	var upload api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+	ref := upload.Reference.String()
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/stats/content/"+ref, http.StatusNotFound)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+ref, http.StatusOK,
+		jsonhttptest.WithExpectedResponse(content),
+	)
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+ref, http.StatusPartialContent,
+		jsonhttptest.WithRequestHeader("Range", "bytes=0-6"),
+		jsonhttptest.WithExpectedResponse(content[:7]),
+	)
+	// the failed requests are not recorded
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+ref, http.StatusRequestedRangeNotSatisfiable,
+		jsonhttptest.WithRequestHeader("Range", "bytes=100-200"),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/stats/content/"+ref, http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.ContentStatsResponse{
+			Reference:     upload.Reference,
+			Downloads:     1,
+			BytesServed:   uint64(len(content) + 7),
+			UniqueClients: 1,
+		}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/stats/content/invalid", http.StatusBadRequest)
+}
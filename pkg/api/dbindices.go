@@ -5,13 +5,16 @@
 package api
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
 )
 
 type StorageIndexDebugger interface {
 	DebugIndices() (map[string]int, error)
+	DebugChunk(ctx context.Context, addr swarm.Address) (swarm.Chunk, []string, error)
 }
 
 func (s *Service) dbIndicesHandler(w http.ResponseWriter, _ *http.Request) {
@@ -5,6 +5,7 @@
 package api_test
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
@@ -12,10 +13,12 @@ import (
 	"github.com/ethersphere/bee/pkg/api"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/swarm"
 )
 
 type testIndexDebugger struct {
 	indicesFunc func() (map[string]int, error)
+	chunkFunc   func(swarm.Address) (swarm.Chunk, []string, error)
 }
 
 var _ api.StorageIndexDebugger = (*testIndexDebugger)(nil)
@@ -24,6 +27,10 @@ func (t *testIndexDebugger) DebugIndices() (map[string]int, error) {
 	return t.indicesFunc()
 }
 
+func (t *testIndexDebugger) DebugChunk(_ context.Context, addr swarm.Address) (swarm.Chunk, []string, error) {
+	return t.chunkFunc(addr)
+}
+
 func TestDBIndices(t *testing.T) {
 	t.Parallel()
 
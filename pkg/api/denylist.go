@@ -0,0 +1,131 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/denylist"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+const errDenied = "content unavailable for legal reasons"
+
+type denylistRequest struct {
+	Reason string `json:"reason"`
+}
+
+type denylistEntryResponse struct {
+	Reference swarm.Address `json:"reference"`
+	Reason    string        `json:"reason"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+type denylistResponse struct {
+	Entries []denylistEntryResponse `json:"entries"`
+}
+
+func newDenylistEntryResponse(e denylist.Entry) denylistEntryResponse {
+	return denylistEntryResponse{
+		Reference: e.Address,
+		Reason:    e.Reason,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// denied responds with 451 Unavailable For Legal Reasons
+// and returns true if the reference is on the deny-list.
+func (s *Service) denied(logger log.Logger, w http.ResponseWriter, reference swarm.Address) bool {
+	if s.denylist == nil || !s.denylist.Denied(reference) {
+		return false
+	}
+	logger.Debug("denied reference requested", "reference", reference)
+	jsonhttp.UnavailableForLegalReasons(w, errDenied)
+	return true
+}
+
+func (s *Service) denylistGetHandler(w http.ResponseWriter, _ *http.Request) {
+	entries := s.denylist.Entries()
+	res := denylistResponse{Entries: make([]denylistEntryResponse, 0, len(entries))}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, newDenylistEntryResponse(e))
+	}
+	jsonhttp.OK(w, res)
+}
+
+func (s *Service) denylistPutHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("put_denylist").Build()
+
+	paths := struct {
+		Reference swarm.Address `map:"reference" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if jsonhttp.HandleBodyReadError(err, w) {
+			return
+		}
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+
+	req := denylistRequest{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			logger.Debug("unmarshal request body failed", "error", err)
+			logger.Error(nil, "unmarshal request body failed")
+			jsonhttp.BadRequest(w, "invalid request body")
+			return
+		}
+	}
+
+	e, err := s.denylist.Add(paths.Reference, req.Reason)
+	if err != nil {
+		logger.Debug("add to deny-list failed", "reference", paths.Reference, "error", err)
+		logger.Error(nil, "add to deny-list failed")
+		jsonhttp.InternalServerError(w, "add to deny-list failed")
+		return
+	}
+
+	jsonhttp.OK(w, newDenylistEntryResponse(e))
+}
+
+func (s *Service) denylistDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("delete_denylist").Build()
+
+	paths := struct {
+		Reference swarm.Address `map:"reference" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	if err := s.denylist.Remove(paths.Reference); err != nil {
+		logger.Debug("remove from deny-list failed", "reference", paths.Reference, "error", err)
+		logger.Error(nil, "remove from deny-list failed")
+		if errors.Is(err, denylist.ErrNotFound) {
+			jsonhttp.NotFound(w, "reference not denied")
+			return
+		}
+		jsonhttp.InternalServerError(w, "remove from deny-list failed")
+		return
+	}
+
+	jsonhttp.OK(w, nil)
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/denylist"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestDenylist(t *testing.T) {
+	t.Parallel()
+
+	list, err := denylist.New(statestore.NewStateStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		logger          = log.Noop
+		storer          = mock.NewStorer()
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:   storer,
+			Tags:     tags.NewTags(nil, logger),
+			Logger:   logger,
+			Post:     mockpost.New(mockpost.WithAcceptAll()),
+			Denylist: list,
+		})
+		debugClient, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:   storer,
+			DebugAPI: true,
+			Denylist: list,
+		})
+	)
+
+	var upload api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader([]byte("content"))),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+	ref := upload.Reference.String()
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+ref, http.StatusOK,
+		jsonhttptest.WithExpectedResponse([]byte("content")),
+	)
+
+	jsonhttptest.Request(t, debugClient, http.MethodPut, "/denylist/"+ref, http.StatusOK,
+		jsonhttptest.WithJSONRequestBody(api.DenylistRequest{Reason: "copyright"}),
+	)
+
+	var res api.DenylistResponse
+	jsonhttptest.Request(t, debugClient, http.MethodGet, "/denylist", http.StatusOK,
+		jsonhttptest.WithUnmarshalJSONResponse(&res),
+	)
+	if len(res.Entries) != 1 || !res.Entries[0].Reference.Equal(upload.Reference) || res.Entries[0].Reason != "copyright" {
+		t.Fatalf("got entries %+v, want %s denied for copyright", res.Entries, ref)
+	}
+
+	denied := jsonhttp.StatusResponse{
+		Message: "content unavailable for legal reasons",
+		Code:    http.StatusUnavailableForLegalReasons,
+	}
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+ref, http.StatusUnavailableForLegalReasons,
+		jsonhttptest.WithExpectedJSONResponse(denied),
+	)
+	jsonhttptest.Request(t, client, http.MethodHead, "/bytes/"+ref, http.StatusUnavailableForLegalReasons)
+	jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+ref, http.StatusUnavailableForLegalReasons,
+		jsonhttptest.WithExpectedJSONResponse(denied),
+	)
+
+	jsonhttptest.Request(t, debugClient, http.MethodDelete, "/denylist/"+ref, http.StatusOK)
+	jsonhttptest.Request(t, debugClient, http.MethodDelete, "/denylist/"+ref, http.StatusNotFound,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "reference not denied",
+			Code:    http.StatusNotFound,
+		}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+ref, http.StatusOK,
+		jsonhttptest.WithExpectedResponse([]byte("content")),
+	)
+}
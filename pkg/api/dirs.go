@@ -31,7 +31,14 @@ import (
 	"github.com/ethersphere/bee/pkg/tracing"
 )
 
-var errEmptyDir = errors.New("no files in root directory")
+// DefaultMaxDirUploadFileSize is the maximum size of a single file in a
+// directory upload if no other limit is configured.
+const DefaultMaxDirUploadFileSize int64 = 32 * 1024 * 1024 * 1024
+
+var (
+	errEmptyDir     = errors.New("no files in root directory")
+	errFileTooLarge = errors.New("file exceeds maximum size")
+)
 
 // dirUploadHandler uploads a directory supplied as a tar in an HTTP request
 func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *http.Request, storer storage.Storer, waitFn func() error) {
@@ -57,7 +64,7 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 	}
 	defer r.Body.Close()
 
-	tag, created, err := s.getOrCreateTag(r.Header.Get(SwarmTagHeader))
+	tag, created, err := s.getOrCreateTag(r.Context(), r.Header.Get(SwarmTagHeader))
 	if err != nil {
 		logger.Debug("get or create tag failed", "error", err)
 		logger.Error(nil, "get or create tag failed")
@@ -67,6 +74,12 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 		default:
 			jsonhttp.InternalServerError(w, "cannot get or create tag")
 		}
+		return
+	}
+
+	maxFileSize := s.MaxDirUploadFileSize
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultMaxDirUploadFileSize
 	}
 
 	// Add the tag to the context
@@ -83,22 +96,42 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 		r.Header.Get(SwarmErrorDocumentHeader),
 		tag,
 		created,
+		maxFileSize,
 	)
 	if err != nil {
 		logger.Debug("store dir failed", "error", err)
 		logger.Error(nil, "store dir failed")
+		failUploadTag(logger, tag, tags.PhaseSplit, err)
 		switch {
 		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
 		case errors.Is(err, errEmptyDir):
 			jsonhttp.BadRequest(w, errEmptyDir)
+		case errors.Is(err, errFileTooLarge):
+			jsonhttp.RequestEntityTooLarge(w, errFileTooLarge)
 		case errors.Is(err, tar.ErrHeader):
 			jsonhttp.BadRequest(w, "invalid filename in tar archive")
+		case errors.Is(err, errContentChecksum):
+			jsonhttp.BadRequest(w, errContentChecksum)
 		default:
 			jsonhttp.InternalServerError(w, errDirectoryStore)
 		}
 		return
 	}
+	// the archive may end before the end of the body
+	if err := verifyContentChecksum(r); err != nil {
+		logger.Debug("verify content checksum failed", "error", err)
+		logger.Error(nil, "verify content checksum failed")
+		failUploadTag(logger, tag, tags.PhaseSplit, err)
+		if errors.Is(err, errContentChecksum) {
+			jsonhttp.BadRequest(w, errContentChecksum)
+			return
+		}
+		jsonhttp.InternalServerError(w, errDirectoryStore)
+		return
+	}
+	s.watchReceipts(logger, reference)
+
 	if created {
 		_, err = tag.DoneSplit(reference)
 		if err != nil {
@@ -113,6 +146,10 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 		if err := s.pinning.CreatePin(r.Context(), reference, false); err != nil {
 			logger.Debug("pin creation failed", "address", reference, "error", err)
 			logger.Error(nil, "pin creation failed")
+			if errors.Is(err, errPinQuotaExceeded) {
+				jsonhttp.Forbidden(w, "pin quota exceeded")
+				return
+			}
 			jsonhttp.InternalServerError(w, "create pin failed")
 			return
 		}
@@ -121,6 +158,7 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 	if err = waitFn(); err != nil {
 		logger.Debug("sync chunks failed", "error", err)
 		logger.Error(nil, "sync chunks failed")
+		failUploadTag(logger, tag, tags.PhasePush, err)
 		jsonhttp.InternalServerError(w, "sync chunks failed")
 		return
 	}
@@ -133,7 +171,9 @@ func (s *Service) dirUploadHandler(logger log.Logger, w http.ResponseWriter, r *
 }
 
 // storeDir stores all files recursively contained in the directory given as a tar/multipart
-// it returns the hash for the uploaded manifest corresponding to the uploaded dir
+// it returns the hash for the uploaded manifest corresponding to the uploaded dir.
+// Files are streamed one by one through the pipeline so the memory used does not
+// depend on the size of the archive; files larger than maxFileSize are rejected.
 func storeDir(
 	ctx context.Context,
 	encrypt bool,
@@ -145,6 +185,7 @@ func storeDir(
 	errorFilename string,
 	tag *tags.Tag,
 	tagCreated bool,
+	maxFileSize int64,
 ) (swarm.Address, error) {
 	logger := tracing.NewLoggerWithTraceID(ctx, log)
 	loggerV1 := logger.V(1).Build()
@@ -169,6 +210,10 @@ func storeDir(
 			return swarm.ZeroAddress, fmt.Errorf("read tar stream: %w", err)
 		}
 
+		if fileInfo.Size > maxFileSize {
+			return swarm.ZeroAddress, fmt.Errorf("file %s: %w", fileInfo.Path, errFileTooLarge)
+		}
+
 		if !tagCreated {
 			// only in the case when tag is sent via header (i.e. not created by this request)
 			// for each file
@@ -180,7 +225,8 @@ func storeDir(
 			}
 		}
 
-		fileReference, err := p(ctx, fileInfo.Reader)
+		// the declared size is not trusted for multipart entries
+		fileReference, unpaddedLength, err := p(ctx, &maxSizeReader{r: fileInfo.Reader, n: maxFileSize})
 		if err != nil {
 			return swarm.ZeroAddress, fmt.Errorf("store dir file: %w", err)
 		}
@@ -190,6 +236,9 @@ func storeDir(
 			manifest.EntryMetadataContentTypeKey: fileInfo.ContentType,
 			manifest.EntryMetadataFilenameKey:    fileInfo.Name,
 		}
+		if unpaddedLength >= 0 {
+			fileMtdt[manifest.EntryMetadataUnpaddedLengthKey] = strconv.FormatInt(unpaddedLength, 10)
+		}
 		// add file entry to dir manifest
 		err = dirManifest.Add(ctx, fileInfo.Path, manifest.NewEntry(fileReference, fileMtdt))
 		if err != nil {
@@ -253,6 +302,25 @@ type FileInfo struct {
 	Reader      io.Reader
 }
 
+// maxSizeReader reads from r and fails with errFileTooLarge
+// once more than n bytes were read.
+type maxSizeReader struct {
+	r io.Reader
+	n int64
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > m.n+1 {
+		p = p[:m.n+1]
+	}
+	n, err := m.r.Read(p)
+	m.n -= int64(n)
+	if m.n < 0 {
+		return n, errFileTooLarge
+	}
+	return n, err
+}
+
 type dirReader interface {
 	Next() (*FileInfo, error)
 }
@@ -24,7 +24,6 @@ import (
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/manifest"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
@@ -37,11 +36,10 @@ func TestDirs(t *testing.T) {
 		bzzDownloadResource = func(addr, path string) string { return "/bzz/" + addr + "/" + path }
 		ctx                 = context.Background()
 		storer              = mock.NewStorer()
-		mockStatestore      = statestore.NewStateStore()
 		logger              = log.Noop
 		client, _, _, _     = newTestServer(t, testServerOptions{
 			Storer:          storer,
-			Tags:            tags.NewTags(mockStatestore, logger),
+			Tags:            tags.NewTags(nil, logger),
 			Logger:          logger,
 			PreventRedirect: true,
 			Post:            mockpost.New(mockpost.WithAcceptAll()),
@@ -488,7 +486,7 @@ func TestDirs(t *testing.T) {
 				},
 			},
 		})
-		tag := tags.NewTags(statestore.NewStateStore(), log.Noop)
+		tag := tags.NewTags(nil, log.Noop)
 		clientTagExists, _, _, _ := newTestServer(t, testServerOptions{
 			Tags:   tag,
 			Storer: storer,
@@ -511,11 +509,10 @@ func TestEmtpyDir(t *testing.T) {
 	var (
 		dirUploadResource = "/bzz"
 		storer            = mock.NewStorer()
-		mockStatestore    = statestore.NewStateStore()
 		logger            = log.Noop
 		client, _, _, _   = newTestServer(t, testServerOptions{
 			Storer:          storer,
-			Tags:            tags.NewTags(mockStatestore, logger),
+			Tags:            tags.NewTags(nil, logger),
 			Logger:          logger,
 			PreventRedirect: true,
 			Post:            mockpost.New(mockpost.WithAcceptAll()),
@@ -537,6 +534,74 @@ func TestEmtpyDir(t *testing.T) {
 	)
 }
 
+func TestDirUploadMaxFileSize(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dirUploadResource = "/bzz"
+		logger            = log.Noop
+		client, _, _, _   = newTestServer(t, testServerOptions{
+			Storer:               mock.NewStorer(),
+			Tags:                 tags.NewTags(nil, logger),
+			Logger:               logger,
+			PreventRedirect:      true,
+			Post:                 mockpost.New(mockpost.WithAcceptAll()),
+			MaxDirUploadFileSize: 8,
+		})
+		tooLarge = jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: api.FileTooLarge.Error(),
+			Code:    http.StatusRequestEntityTooLarge,
+		})
+	)
+
+	t.Run("tar", func(t *testing.T) {
+		t.Parallel()
+
+		tarReader := tarFiles(t, []f{
+			{data: []byte("small"), name: "small.txt"},
+			{data: []byte("larger than the limit"), name: "large.txt"},
+		})
+
+		jsonhttptest.Request(t, client, http.MethodPost, dirUploadResource,
+			http.StatusRequestEntityTooLarge,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(tarReader),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			tooLarge,
+		)
+	})
+
+	t.Run("multipart with understated length", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		hdr := make(textproto.MIMEHeader)
+		hdr.Set("Content-Disposition", `form-data; name="large.txt"`)
+		hdr.Set("Content-Type", "text/plain")
+		hdr.Set("Content-Length", "4")
+		part, err := mw.CreatePart(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write([]byte("larger than the limit")); err != nil {
+			t.Fatal(err)
+		}
+		if err := mw.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodPost, dirUploadResource,
+			http.StatusRequestEntityTooLarge,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(&buf),
+			jsonhttptest.WithRequestHeader("Content-Type", fmt.Sprintf("multipart/form-data; boundary=%q", mw.Boundary())),
+			tooLarge,
+		)
+	})
+}
+
 // tarFiles receives an array of test case files and creates a new tar with those files as a collection
 // it returns a bytes.Buffer which can be used to read the created tar
 func tarFiles(t *testing.T, files []f) *bytes.Buffer {
@@ -5,22 +5,60 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/log/httpaccess"
+	m "github.com/ethersphere/bee/pkg/metrics"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"resenje.org/web"
 )
 
 type (
-	BytesPostResponse     = bytesPostResponse
-	ChunkAddressResponse  = chunkAddressResponse
-	SocPostResponse       = socPostResponse
-	FeedReferenceResponse = feedReferenceResponse
-	BzzUploadResponse     = bzzUploadResponse
-	DebugTagResponse      = debugTagResponse
-	TagRequest            = tagRequest
-	ListTagsResponse      = listTagsResponse
-	IsRetrievableResponse = isRetrievableResponse
-	SecurityTokenResponse = securityTokenRsp
-	SecurityTokenRequest  = securityTokenReq
+	BytesPostResponse           = bytesPostResponse
+	ReserveRepairResponse       = reserveRepairResponse
+	WebhookDeliveriesResponse   = webhookDeliveriesResponse
+	HandoffResponse             = handoffResponse
+	SelfTestResponse            = selfTestResponse
+	SelfTestStageResponse       = selfTestStageResponse
+	UploadCheckpoint            = uploadCheckpoint
+	ChunkAddressResponse        = chunkAddressResponse
+	ChunksHasRequest            = chunksHasRequest
+	ChunksHasResponse           = chunksHasResponse
+	ChunkHasData                = chunkHasData
+	ReceiptsResponse            = receiptsResponse
+	ReceiptResponse             = receiptResponse
+	DenylistRequest             = denylistRequest
+	DenylistResponse            = denylistResponse
+	PrewarmResponse             = prewarmResponse
+	AvailabilityResponse        = availabilityResponse
+	AvailabilityWindow          = availabilityWindow
+	AvailabilityNeighbourhood   = availabilityNeighbourhood
+	WorkingSetLeaseResponse     = workingSetLeaseResponse
+	WorkingSetLeasesResponse    = workingSetLeasesResponse
+	ContentStatsResponse        = contentStatsResponse
+	SocPostResponse             = socPostResponse
+	FeedReferenceResponse       = feedReferenceResponse
+	PublishResponse             = publishResponse
+	CollectionRequest           = collectionRequest
+	CollectionResponse          = collectionResponse
+	CollectionDocumentResponse  = collectionDocumentResponse
+	CollectionDocumentsResponse = collectionDocumentsResponse
+	IPFSImportResponse          = ipfsImportResponse
+	IPFSImportFile              = ipfsImportFile
+	ChunkTraceResponse          = chunkTraceResponse
+	FeedSnapshotResponse        = feedSnapshotResponse
+	BzzUploadResponse           = bzzUploadResponse
+	BzzListingResponse          = bzzListingResponse
+	BzzListingEntry             = bzzListingEntry
+	DebugTagResponse            = debugTagResponse
+	TagRequest                  = tagRequest
+	ListTagsResponse            = listTagsResponse
+	IsRetrievableResponse       = isRetrievableResponse
+	SecurityTokenResponse       = securityTokenRsp
+	SecurityTokenRequest        = securityTokenReq
 )
 
 var (
@@ -28,6 +66,7 @@ var (
 	InvalidRequest      = errInvalidRequest
 	DirectoryStoreError = errDirectoryStore
 	EmptyDir            = errEmptyDir
+	FileTooLarge        = errFileTooLarge
 )
 
 var (
@@ -43,9 +82,10 @@ var (
 )
 
 var (
-	FeedMetadataEntryOwner = feedMetadataEntryOwner
-	FeedMetadataEntryTopic = feedMetadataEntryTopic
-	FeedMetadataEntryType  = feedMetadataEntryType
+	FeedMetadataEntryOwner  = feedMetadataEntryOwner
+	FeedMetadataEntryTopic  = feedMetadataEntryTopic
+	FeedMetadataEntryType   = feedMetadataEntryType
+	FeedMetadataEntryPeriod = feedMetadataEntryPeriod
 
 	SuccessWsMsg = successWsMsg
 )
@@ -53,8 +93,26 @@ var (
 var (
 	FileSizeBucketsKBytes = fileSizeBucketsKBytes
 	ToFileSizeBucket      = toFileSizeBucket
+	UploadCheckpointKey   = uploadCheckpointKey
+)
+
+var (
+	NewSourceClient         = newSourceClient
+	ErrSourceAddressRefused = errSourceAddressRefused
 )
 
+// NewRouteMetricsHandler instruments the router with the route metrics and
+// returns the instrumented handler together with the metrics collectors.
+func NewRouteMetricsHandler(router *mux.Router) (http.Handler, []prometheus.Collector) {
+	metrics := newMetrics()
+	router.Use(routeLabelHandler)
+	return web.ChainHandlers(
+		httpaccess.NewHTTPAccessLogHandler(log.Noop, nil, "api access"),
+		newRouteMetricsHandler(metrics),
+		web.FinalHandler(router),
+	), m.PrometheusCollectorsFromFields(metrics)
+}
+
 func (s *Service) ResolveNameOrAddress(str string) (swarm.Address, error) {
 	return s.resolveNameOrAddress(str)
 }
@@ -69,12 +127,21 @@ type (
 	PingpongResponse                  = pingpongResponse
 	PeerConnectResponse               = peerConnectResponse
 	PeersResponse                     = peersResponse
+	PeerTrafficResponse               = peerTrafficResponse
+	TrafficResponse                   = trafficResponse
 	AddressesResponse                 = addressesResponse
+	NATStatusResponse                 = natStatusResponse
 	WelcomeMessageRequest             = welcomeMessageRequest
 	WelcomeMessageResponse            = welcomeMessageResponse
 	BalancesResponse                  = balancesResponse
 	PeerDataResponse                  = peerDataResponse
 	PeerData                          = peerData
+	ProfitabilityResponse             = profitabilityResponse
+	EarningsResponse                  = earningsResponse
+	ChunkEarningsResponse             = chunkEarningsResponse
+	SnapshotsResponse                 = snapshotsResponse
+	SnapshotResponse                  = snapshotResponse
+	SnapshotPeerResponse              = snapshotPeerResponse
 	BalanceResponse                   = balanceResponse
 	SettlementResponse                = settlementResponse
 	SettlementsResponse               = settlementsResponse
@@ -84,14 +151,33 @@ type (
 	ChequebookLastChequesResponse     = chequebookLastChequesResponse
 	ChequebookLastChequesPeerResponse = chequebookLastChequesPeerResponse
 	ChequebookTxResponse              = chequebookTxResponse
+	ChequebookWithdrawalResponse      = chequebookWithdrawalResponse
 	SwapCashoutResponse               = swapCashoutResponse
 	SwapCashoutStatusResponse         = swapCashoutStatusResponse
 	SwapCashoutStatusResult           = swapCashoutStatusResult
+	SwapBouncedChequeResponse         = swapBouncedChequeResponse
+	SwapBouncedChequesResponse        = swapBouncedChequesResponse
 	TransactionInfo                   = transactionInfo
 	TransactionPendingList            = transactionPendingList
 	TransactionHashResponse           = transactionHashResponse
+	NonceStatusResponse               = nonceStatusResponse
+	StuckNonceResponse                = stuckNonceResponse
 	TagResponse                       = tagResponse
+	TagTraceResponse                  = tagTraceResponse
+	TagErrorResponse                  = tagErrorResponse
+	BucketFullResponse                = bucketFullResponse
 	ReserveStateResponse              = reserveStateResponse
+	ReserveForecastResponse           = reserveForecastResponse
+	StatusSnapshotResponse            = statusSnapshotResponse
+	StatusPeersResponse               = statusPeersResponse
+	AuditResponse                     = auditResponse
+	TopologyLatencyResponse           = topologyLatencyResponse
+	BatchEventResponse                = batchEventResponse
+	RestampResponse                   = restampResponse
+	BinLatencyResponse                = binLatencyResponse
+	LatencyGraph                      = latencyGraph
+	LatencyNode                       = latencyNode
+	LatencyEdge                       = latencyEdge
 	ChainStateResponse                = chainStateResponse
 	PostageCreateResponse             = postageCreateResponse
 	PostageStampResponse              = postageStampResponse
@@ -99,10 +185,13 @@ type (
 	PostageBatchResponse              = postageBatchResponse
 	PostageStampBucketsResponse       = postageStampBucketsResponse
 	BucketData                        = bucketData
+	PostageStampCapacityResponse      = postageStampCapacityResponse
+	BucketCapacityData                = bucketCapacityData
 	WalletResponse                    = walletResponse
 	GetStakeResponse                  = getStakeResponse
 	WithdrawAllStakeResponse          = withdrawAllStakeResponse
 	NodeStatusResponse                = nodeStatusResponse
+	ReserveProofResponse              = reserveProofResponse
 )
 
 var (
@@ -117,6 +206,8 @@ var (
 	ErrUnknownTransaction    = errUnknownTransaction
 	ErrCantGetTransaction    = errCantGetTransaction
 	ErrCantResendTransaction = errCantResendTransaction
+	ErrCantGetNonceStatus    = errCantGetNonceStatus
+	ErrNoStuckNonce          = errNoStuckNonce
 	ErrAlreadyImported       = errAlreadyImported
 )
 
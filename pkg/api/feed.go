@@ -5,6 +5,7 @@
 package api
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
@@ -13,7 +14,9 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/clockskew"
 	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
 	"github.com/ethersphere/bee/pkg/file/loadsave"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/manifest"
@@ -21,6 +24,7 @@ import (
 	"github.com/ethersphere/bee/pkg/manifest/simple"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/gorilla/mux"
 )
@@ -29,9 +33,15 @@ const (
 	feedMetadataEntryOwner = "swarm-feed-owner"
 	feedMetadataEntryTopic = "swarm-feed-topic"
 	feedMetadataEntryType  = "swarm-feed-type"
+	// feedMetadataEntryPeriod is the optional hint of the seconds
+	// between the updates, used to narrow the epoch feed lookups.
+	feedMetadataEntryPeriod = "swarm-feed-period"
 )
 
-var errInvalidFeedUpdate = errors.New("invalid feed update")
+var (
+	errInvalidFeedUpdate = errors.New("invalid feed update")
+	errFeedIndexTaken    = errors.New("feed index already taken")
+)
 
 type feedReferenceResponse struct {
 	Reference swarm.Address `json:"reference"`
@@ -121,6 +131,128 @@ func (s *Service) feedGetHandler(w http.ResponseWriter, r *http.Request) {
 	jsonhttp.OK(w, feedReferenceResponse{Reference: ref})
 }
 
+type feedSnapshotResponse struct {
+	Reference  swarm.Address `json:"reference"`
+	FeedUpdate swarm.Address `json:"feedUpdate"`
+	Index      string        `json:"index"`
+	Timestamp  int64         `json:"timestamp"`
+	Pinned     bool          `json:"pinned"`
+}
+
+// feedSnapshotHandler resolves the feed at the given index or, if the index
+// is not given, at the given time and returns the immutable reference the
+// update points to, which can be used as a permalink of the feed content at
+// that version. The referenced content is pinned if requested.
+func (s *Service) feedSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_feed_snapshot").Build()
+
+	paths := struct {
+		Owner common.Address `map:"owner" validate:"required"`
+		Topic []byte         `map:"topic" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	queries := struct {
+		Index []byte `map:"index" validate:"omitempty,len=8"`
+		At    int64  `map:"at"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	if queries.At == 0 {
+		queries.At = time.Now().Unix()
+	}
+
+	var (
+		ctx = r.Context()
+		f   = feeds.New(paths.Topic, paths.Owner)
+		ch  swarm.Chunk
+		cur feeds.Index
+	)
+	if queries.Index != nil {
+		cur = sequence.NewIndex(binary.BigEndian.Uint64(queries.Index))
+		var err error
+		ch, err = feeds.NewGetter(s.storer, f).Get(ctx, cur)
+		if err != nil {
+			logger.Debug("get feed update failed", "owner", paths.Owner, "index", cur, "error", err)
+			logger.Error(nil, "get feed update failed")
+			jsonhttp.NotFound(w, "no update found")
+			return
+		}
+	} else {
+		lookup, err := s.feedFactory.NewLookup(feeds.Sequence, f)
+		if err != nil {
+			logger.Debug("new lookup failed", "owner", paths.Owner, "error", err)
+			logger.Error(nil, "new lookup failed")
+			switch {
+			case errors.Is(err, feeds.ErrFeedTypeNotFound):
+				jsonhttp.NotFound(w, "feed type not found")
+			default:
+				jsonhttp.InternalServerError(w, "new lookup failed")
+			}
+			return
+		}
+		ch, cur, _, err = lookup.At(ctx, queries.At, 0)
+		if err != nil {
+			logger.Debug("lookup at failed", "at", queries.At, "error", err)
+			logger.Error(nil, "lookup at failed")
+			jsonhttp.NotFound(w, "lookup at failed")
+			return
+		}
+		// KLUDGE: if a feed was never updated, the chunk will be nil
+		if ch == nil {
+			logger.Debug("no update found")
+			logger.Error(nil, "no update found")
+			jsonhttp.NotFound(w, "no update found")
+			return
+		}
+	}
+
+	ref, ts, err := parseFeedUpdate(ch)
+	if err != nil {
+		logger.Debug("parse feed update failed", "error", err)
+		logger.Error(nil, "parse feed update failed")
+		jsonhttp.InternalServerError(w, "parse feed update failed")
+		return
+	}
+
+	curBytes, err := cur.MarshalBinary()
+	if err != nil {
+		logger.Debug("marshal current index failed", "error", err)
+		logger.Error(nil, "marshal current index failed")
+		jsonhttp.InternalServerError(w, "marshal current index failed")
+		return
+	}
+
+	pinned := requestPin(r)
+	if pinned {
+		if err := s.pinning.CreatePin(ctx, ref, true); err != nil {
+			logger.Debug("pin creation failed", "reference", ref, "error", err)
+			logger.Error(nil, "pin creation failed")
+			if errors.Is(err, errPinQuotaExceeded) {
+				jsonhttp.Forbidden(w, "pin quota exceeded")
+				return
+			}
+			jsonhttp.InternalServerError(w, "pin creation failed")
+			return
+		}
+	}
+
+	w.Header().Set(SwarmFeedIndexHeader, hex.EncodeToString(curBytes))
+	w.Header().Set("Access-Control-Expose-Headers", SwarmFeedIndexHeader)
+	jsonhttp.OK(w, feedSnapshotResponse{
+		Reference:  ref,
+		FeedUpdate: ch.Address(),
+		Index:      hex.EncodeToString(curBytes),
+		Timestamp:  ts,
+		Pinned:     pinned,
+	})
+}
+
 func (s *Service) feedPostHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("post_feed").Build()
 
@@ -133,11 +265,21 @@ func (s *Service) feedPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	headers := struct {
+		IfFeedIndex []byte `map:"If-Feed-Index" validate:"omitempty,len=8"`
+	}{}
+	if response := s.mapStructure(r.Header, &headers); response != nil {
+		response("invalid header params", logger, w)
+		return
+	}
+
 	putter, wait, err := s.newStamperPutter(r)
 	if err != nil {
 		logger.Debug("putter failed", "error", err)
 		logger.Error(nil, "putter failed")
 		switch {
+		case errors.Is(err, errBatchNotAllowed):
+			jsonhttp.Forbidden(w, "batch not allowed")
 		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
 			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
 		case errors.Is(err, postage.ErrNotFound):
@@ -146,12 +288,25 @@ func (s *Service) feedPostHandler(w http.ResponseWriter, r *http.Request) {
 			jsonhttp.BadRequest(w, "invalid batch id")
 		case errors.Is(err, errUnsupportedDevNodeOperation):
 			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		case errors.Is(err, clockskew.ErrClockSkewed):
+			jsonhttp.ServiceUnavailable(w, err.Error())
 		default:
 			jsonhttp.BadRequest(w, nil)
 		}
 		return
 	}
 
+	if err := s.checkFeedIndex(r.Context(), feeds.New(paths.Topic, paths.Owner), headers.IfFeedIndex); err != nil {
+		logger.Debug("feed index precondition failed", "owner", paths.Owner, "index", hex.EncodeToString(headers.IfFeedIndex), "error", err)
+		if errors.Is(err, errFeedIndexTaken) {
+			jsonhttp.PreconditionFailed(w, errFeedIndexTaken)
+			return
+		}
+		logger.Error(nil, "feed index precondition failed")
+		jsonhttp.InternalServerError(w, "check feed index failed")
+		return
+	}
+
 	l := loadsave.New(putter, requestPipelineFactory(r.Context(), putter, r))
 	feedManifest, err := manifest.NewDefaultManifest(l, false)
 	if err != nil {
@@ -194,7 +349,7 @@ func (s *Service) feedPostHandler(w http.ResponseWriter, r *http.Request) {
 		logger.Error(nil, "store manifest failed")
 		switch {
 		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
 		default:
 			jsonhttp.InternalServerError(w, "store manifest failed")
 		}
@@ -205,6 +360,10 @@ func (s *Service) feedPostHandler(w http.ResponseWriter, r *http.Request) {
 		if err := s.pinning.CreatePin(r.Context(), ref, false); err != nil {
 			logger.Debug("pin creation failed: %v", "address", ref, "error", err)
 			logger.Error(nil, "pin creation failed")
+			if errors.Is(err, errPinQuotaExceeded) {
+				jsonhttp.Forbidden(w, "pin quota exceeded")
+				return
+			}
 			jsonhttp.InternalServerError(w, "creation of pin failed")
 			return
 		}
@@ -220,6 +379,25 @@ func (s *Service) feedPostHandler(w http.ResponseWriter, r *http.Request) {
 	jsonhttp.Created(w, feedReferenceResponse{Reference: ref})
 }
 
+// checkFeedIndex evaluates the If-Feed-Index precondition of the feed
+// updates: it returns errFeedIndexTaken if the feed already has an update
+// at the index, so that the concurrent publishers of the feed fail instead
+// of silently diverging it. Nothing is checked if the index is nil.
+func (s *Service) checkFeedIndex(ctx context.Context, f *feeds.Feed, index []byte) error {
+	if index == nil {
+		return nil
+	}
+	_, err := feeds.NewGetter(s.storer, f).Get(ctx, sequence.NewIndex(binary.BigEndian.Uint64(index)))
+	switch {
+	case err == nil:
+		return errFeedIndexTaken
+	case errors.Is(err, storage.ErrNotFound):
+		return nil
+	default:
+		return fmt.Errorf("get feed update: %w", err)
+	}
+}
+
 func parseFeedUpdate(ch swarm.Chunk) (swarm.Address, int64, error) {
 	s, err := soc.FromChunk(ch)
 	if err != nil {
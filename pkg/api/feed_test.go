@@ -16,16 +16,19 @@ import (
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
 	"github.com/ethersphere/bee/pkg/file/loadsave"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/manifest"
+	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
 	"github.com/ethersphere/bee/pkg/postage"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
 	testingsoc "github.com/ethersphere/bee/pkg/soc/testing"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
@@ -45,10 +48,9 @@ func TestFeed_Get(t *testing.T) {
 			}
 			return fmt.Sprintf("/feeds/%s/%s", owner, topic)
 		}
-		mockStatestore = statestore.NewStateStore()
-		logger         = log.Noop
-		tag            = tags.NewTags(mockStatestore, logger)
-		mockStorer     = mock.NewStorer()
+		logger     = log.Noop
+		tag        = tags.NewTags(nil, logger)
+		mockStorer = mock.NewStorer()
 	)
 
 	t.Run("with at", func(t *testing.T) {
@@ -119,15 +121,111 @@ func TestFeed_Get(t *testing.T) {
 	})
 }
 
+func TestFeed_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger      = log.Noop
+		mockStorer  = mock.NewStorer()
+		pinningMock = pinning.NewServiceMock()
+		topic       = []byte{0xaa, 0xbb, 0xcc}
+		refs        = []swarm.Address{swarm.RandAddress(t), swarm.RandAddress(t)}
+	)
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	updater, err := sequence.NewUpdater(mockStorer, signer, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ref := range refs {
+		if err := updater.Update(context.Background(), int64(i+1), ref.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:  mockStorer,
+		Tags:    tags.NewTags(nil, logger),
+		Logger:  logger,
+		Feeds:   factory.New(mockStorer),
+		Pinning: pinningMock,
+	})
+	snapshotResource := func(query string) string {
+		return fmt.Sprintf("/feeds/%x/%x/snapshot?%s", owner, topic, query)
+	}
+	updateAddress := func(i uint64) swarm.Address {
+		addr, err := feeds.New(topic, owner).Update(sequence.NewIndex(i)).Address()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr
+	}
+
+	t.Run("at index", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, snapshotResource("index=0000000000000000"), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.FeedSnapshotResponse{
+				Reference:  refs[0],
+				FeedUpdate: updateAddress(0),
+				Index:      "0000000000000000",
+				Timestamp:  1,
+			}),
+		)
+	})
+
+	t.Run("at time with pin", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, snapshotResource("at=2"), http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmPinHeader, "true"),
+			jsonhttptest.WithExpectedJSONResponse(api.FeedSnapshotResponse{
+				Reference:  refs[1],
+				FeedUpdate: updateAddress(1),
+				Index:      "0000000000000001",
+				Timestamp:  2,
+				Pinned:     true,
+			}),
+		)
+		if has, _ := pinningMock.HasPin(refs[1]); !has {
+			t.Fatalf("reference %s is not pinned", refs[1])
+		}
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, snapshotResource("index=0000000000000005"), http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotFound,
+				Message: "no update found",
+			}),
+		)
+	})
+
+	t.Run("invalid index", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, snapshotResource("index=00"), http.StatusBadRequest)
+	})
+}
+
 // nolint:paralleltest
 func TestFeed_Post(t *testing.T) {
 	// post to owner, tpoic, then expect a reference
 	// get the reference from the store, unmarshal to a
 	// manifest entry and make sure all metadata correct
 	var (
-		mockStatestore  = statestore.NewStateStore()
 		logger          = log.Noop
-		tag             = tags.NewTags(mockStatestore, logger)
+		tag             = tags.NewTags(nil, logger)
 		topic           = "aabbcc"
 		mp              = mockpost.New(mockpost.WithIssuer(postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)))
 		mockStorer      = mock.NewStorer()
@@ -197,13 +295,77 @@ func TestFeed_Post(t *testing.T) {
 
 }
 
+func TestFeed_PostIfFeedIndex(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mockStorer = mock.NewStorer()
+		topic      = []byte{0xaa, 0xbb, 0xcc}
+	)
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	updater, err := sequence.NewUpdater(mockStorer, signer, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := updater.Update(context.Background(), 1, swarm.RandAddress(t).Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer: mockStorer,
+		Tags:   tags.NewTags(nil, log.Noop),
+		Logger: log.Noop,
+		Post:   mockpost.New(mockpost.WithAcceptAll()),
+	})
+	url := fmt.Sprintf("/feeds/%x/%x", owner, topic)
+
+	t.Run("taken", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, url, http.StatusPreconditionFailed,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.IfFeedIndexHeader, "0000000000000000"),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "feed index already taken",
+				Code:    http.StatusPreconditionFailed,
+			}),
+		)
+	})
+
+	t.Run("free", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, url, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.IfFeedIndexHeader, "0000000000000001"),
+		)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, url, http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.IfFeedIndexHeader, "00"),
+		)
+	})
+}
+
 // TestDirectUploadFeed tests that the direct upload endpoint give correct error message in dev mode
 func TestDirectUploadFeed(t *testing.T) {
 	t.Parallel()
 	var (
-		mockStatestore  = statestore.NewStateStore()
 		logger          = log.Noop
-		tag             = tags.NewTags(mockStatestore, logger)
+		tag             = tags.NewTags(nil, logger)
 		topic           = "aabbcc"
 		mp              = mockpost.New(mockpost.WithIssuer(postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)))
 		mockStorer      = mock.NewStorer()
@@ -229,6 +391,7 @@ func TestDirectUploadFeed(t *testing.T) {
 type factoryMock struct {
 	sequenceCalled bool
 	epochCalled    bool
+	period         int64
 	feed           *feeds.Feed
 	lookup         feeds.Lookup
 }
@@ -248,6 +411,11 @@ func (f *factoryMock) NewLookup(t feeds.Type, feed *feeds.Feed) (feeds.Lookup, e
 	return f.lookup, nil
 }
 
+func (f *factoryMock) NewLookupWithPeriod(t feeds.Type, feed *feeds.Feed, period int64) (feeds.Lookup, error) {
+	f.period = period
+	return f.NewLookup(t, feed)
+}
+
 type mockLookup struct {
 	at, after int64
 	chunk     swarm.Chunk
@@ -0,0 +1,161 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/ratelimit"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+// The gateway profile hardens the api of the node serving the public
+// read-only gateway. The mutating endpoints are forbidden, the requests of
+// each client are rate limited, the deny-list applies to all hash-addressed
+// endpoints and their responses are cached by the clients and the proxies as
+// immutable, as the content of a swarm hash does not change.
+
+const (
+	defaultGatewayRateLimit      = 10  // requests per second of a client
+	defaultGatewayRateLimitBurst = 100 // requests of a client in a burst
+
+	// immutableCacheControl is the Cache-Control of the hash-addressed responses.
+	immutableCacheControl = "public, max-age=31536000, immutable"
+)
+
+const (
+	errGatewayReadOnly    = "read-only gateway"
+	errGatewayRateLimited = "too many requests"
+)
+
+// gatewayReadOnlyPosts are the routes which only read despite the POST method.
+var gatewayReadOnlyPosts = map[string]bool{
+	"/chunks/has": true,
+}
+
+// gatewayMutatingGets are the routes which mutate despite the GET method,
+// as the websocket upgrade.
+var gatewayMutatingGets = map[string]bool{
+	"/chunks/stream": true,
+}
+
+// gatewayHashRoutes are the hash-addressed routes whose content is immutable.
+var gatewayHashRoutes = map[string]bool{
+	"/bytes/{address}":         true,
+	"/chunks/{address}":        true,
+	"/bzz/{address}/{path:.*}": true,
+}
+
+// newGatewayLimiter returns the rate limiter of the clients of the gateway.
+func newGatewayLimiter(rate, burst int) *ratelimit.Limiter {
+	if rate <= 0 {
+		rate = defaultGatewayRateLimit
+	}
+	if burst <= 0 {
+		burst = defaultGatewayRateLimitBurst
+	}
+	return ratelimit.New(time.Second/time.Duration(rate), burst)
+}
+
+// gatewayHandler applies the gateway profile to the routed requests.
+func (s *Service) gatewayHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.WithName("gateway").Build()
+
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		if !s.gatewayLimiter.Allow(client, 1) {
+			logger.Debug("gateway request rate limited", "client", client)
+			jsonhttp.TooManyRequests(w, errGatewayRateLimited)
+			return
+		}
+
+		route := gatewayRoute(r)
+		if gatewayMutating(r.Method, route) {
+			logger.Debug("gateway mutating request forbidden", "method", r.Method, "route", route)
+			jsonhttp.Forbidden(w, errGatewayReadOnly)
+			return
+		}
+
+		if gatewayHashRoutes[route] && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+			address, err := swarm.ParseHexAddress(mux.Vars(r)["address"])
+			if err == nil {
+				if s.denied(logger, w, address) {
+					return
+				}
+				w = &immutableResponseWriter{ResponseWriter: w}
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// gatewayRoute returns the path template of the route
+// of the request, without the api version prefix.
+func gatewayRoute(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	for _, prefix := range []string{rootPath, rootPathV2} {
+		if strings.HasPrefix(tpl, prefix+"/") {
+			return strings.TrimPrefix(tpl, prefix)
+		}
+	}
+	return tpl
+}
+
+// gatewayMutating reports whether the request to the route mutates the node.
+func gatewayMutating(method, route string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return gatewayMutatingGets[route]
+	case http.MethodPost:
+		return !gatewayReadOnlyPosts[route]
+	}
+	return true
+}
+
+// immutableResponseWriter sets the immutable Cache-Control on the successful
+// responses, except on those of the dereferenced feeds which change.
+type immutableResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *immutableResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if (code == http.StatusOK || code == http.StatusPartialContent) && w.Header().Get(SwarmFeedIndexHeader) == "" {
+			w.Header().Set("Cache-Control", immutableCacheControl)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *immutableResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *immutableResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
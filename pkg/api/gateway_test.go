@@ -0,0 +1,141 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/denylist"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestGateway(t *testing.T) {
+	t.Parallel()
+
+	list, err := denylist.New(statestore.NewStateStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		logger          = log.Noop
+		storer          = mock.NewStorer()
+		chunk           = testingc.GenerateTestRandomChunk()
+		denied          = testingc.GenerateTestRandomChunk()
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:   storer,
+			Tags:     tags.NewTags(nil, logger),
+			Logger:   logger,
+			Post:     mockpost.New(mockpost.WithAcceptAll()),
+			Denylist: list,
+			Gateway:  true,
+		})
+	)
+	if _, err := storer.Put(context.Background(), storage.ModePutUpload, chunk, denied); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := list.Add(denied.Address(), "copyright"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("mutating forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		forbidden := jsonhttp.StatusResponse{
+			Message: "read-only gateway",
+			Code:    http.StatusForbidden,
+		}
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusForbidden,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader([]byte("content"))),
+			jsonhttptest.WithExpectedJSONResponse(forbidden),
+		)
+		jsonhttptest.Request(t, client, http.MethodPost, "/v1/chunks", http.StatusForbidden,
+			jsonhttptest.WithRequestBody(bytes.NewReader(chunk.Data())),
+			jsonhttptest.WithExpectedJSONResponse(forbidden),
+		)
+		jsonhttptest.Request(t, client, http.MethodDelete, "/chunks/"+chunk.Address().String(), http.StatusForbidden,
+			jsonhttptest.WithExpectedJSONResponse(forbidden),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/chunks/stream", http.StatusForbidden,
+			jsonhttptest.WithExpectedJSONResponse(forbidden),
+		)
+	})
+
+	t.Run("read-only post allowed", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/chunks/has", http.StatusOK,
+			jsonhttptest.WithJSONRequestBody(api.ChunksHasRequest{References: []swarm.Address{chunk.Address()}}),
+		)
+	})
+
+	t.Run("immutable cache", func(t *testing.T) {
+		t.Parallel()
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+chunk.Address().String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(chunk.Data()),
+		)
+		if got, want := header.Get("Cache-Control"), "public, max-age=31536000, immutable"; got != want {
+			t.Fatalf("got Cache-Control %q, want %q", got, want)
+		}
+
+		header = jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+testingc.GenerateTestRandomChunk().Address().String(), http.StatusNotFound)
+		if got := header.Get("Cache-Control"); got != "" {
+			t.Fatalf("got Cache-Control %q on the missing chunk, want none", got)
+		}
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+denied.Address().String(), http.StatusUnavailableForLegalReasons,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "content unavailable for legal reasons",
+				Code:    http.StatusUnavailableForLegalReasons,
+			}),
+		)
+	})
+}
+
+func TestGatewayRateLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storer          = mock.NewStorer()
+		chunk           = testingc.GenerateTestRandomChunk()
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:                storer,
+			Gateway:               true,
+			GatewayRateLimit:      1,
+			GatewayRateLimitBurst: 2,
+		})
+	)
+	if _, err := storer.Put(context.Background(), storage.ModePutUpload, chunk); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+chunk.Address().String(), http.StatusOK)
+	}
+	jsonhttptest.Request(t, client, http.MethodGet, "/chunks/"+chunk.Address().String(), http.StatusTooManyRequests,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "too many requests",
+			Code:    http.StatusTooManyRequests,
+		}),
+	)
+}
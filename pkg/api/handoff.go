@@ -0,0 +1,69 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/handoff"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+type handoffResponse struct {
+	Chunks uint64 `json:"chunks"`
+	Stored uint64 `json:"stored"`
+}
+
+// handoffPostHandler pushes the chunks of the reference directly to the
+// peer, which must be connected and must consent to the handoff.
+func (s *Service) handoffPostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_handoff").Build())
+
+	paths := struct {
+		Address swarm.Address `map:"address,resolve" validate:"required"`
+		Peer    swarm.Address `map:"peer" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	res, err := s.handoff.Handoff(r.Context(), paths.Peer, paths.Address)
+	switch {
+	case errors.Is(err, p2p.ErrPeerNotFound):
+		logger.Debug("handoff failed", "chunk_address", paths.Address, "peer_address", paths.Peer, "error", err)
+		jsonhttp.NotFound(w, "peer not found")
+		return
+	case errors.Is(err, storage.ErrNotFound):
+		logger.Debug("handoff failed", "chunk_address", paths.Address, "peer_address", paths.Peer, "error", err)
+		jsonhttp.NotFound(w, "content not found")
+		return
+	case errors.Is(err, handoff.ErrRejected):
+		logger.Debug("handoff failed", "chunk_address", paths.Address, "peer_address", paths.Peer, "error", err)
+		jsonhttp.Forbidden(w, err.Error())
+		return
+	case errors.Is(err, handoff.ErrIncomplete):
+		logger.Debug("handoff failed", "chunk_address", paths.Address, "peer_address", paths.Peer, "error", err)
+		logger.Error(nil, "handoff incomplete", "stored", res.Stored, "chunks", res.Chunks)
+		jsonhttp.BadGateway(w, err.Error())
+		return
+	case err != nil:
+		logger.Debug("handoff failed", "chunk_address", paths.Address, "peer_address", paths.Peer, "error", err)
+		logger.Error(nil, "handoff failed")
+		jsonhttp.InternalServerError(w, "handoff failed")
+		return
+	}
+
+	jsonhttp.OK(w, handoffResponse{
+		Chunks: res.Chunks,
+		Stored: res.Stored,
+	})
+}
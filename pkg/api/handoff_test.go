@@ -0,0 +1,91 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/handoff"
+	handoffmock "github.com/ethersphere/bee/pkg/handoff/mock"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestHandoff(t *testing.T) {
+	t.Parallel()
+
+	var (
+		reference = swarm.RandAddress(t)
+		peer      = swarm.RandAddress(t)
+		rejecting = swarm.RandAddress(t)
+		failing   = swarm.RandAddress(t)
+	)
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Handoff: handoffmock.Handoff(func(_ context.Context, p, ref swarm.Address) (handoff.Result, error) {
+			switch {
+			case !ref.Equal(reference):
+				return handoff.Result{}, fmt.Errorf("unexpected reference %s", ref)
+			case p.Equal(peer):
+				return handoff.Result{Chunks: 5, Stored: 5}, nil
+			case p.Equal(rejecting):
+				return handoff.Result{}, fmt.Errorf("%w: peer not allowed", handoff.ErrRejected)
+			case p.Equal(failing):
+				return handoff.Result{Chunks: 5, Stored: 2}, fmt.Errorf("%w: invalid stamp", handoff.ErrIncomplete)
+			}
+			return handoff.Result{}, p2p.ErrPeerNotFound
+		}),
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/handoff/"+reference.String()+"/"+peer.String(), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.HandoffResponse{
+				Chunks: 5,
+				Stored: 5,
+			}),
+		)
+	})
+
+	t.Run("peer not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/handoff/"+reference.String()+"/"+swarm.RandAddress(t).String(), http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "peer not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/handoff/"+reference.String()+"/"+rejecting.String(), http.StatusForbidden,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "handoff rejected: peer not allowed",
+				Code:    http.StatusForbidden,
+			}),
+		)
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/handoff/"+reference.String()+"/"+failing.String(), http.StatusBadGateway,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "handoff incomplete: invalid stamp",
+				Code:    http.StatusBadGateway,
+			}),
+		)
+	})
+}
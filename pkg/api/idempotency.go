@@ -0,0 +1,165 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+const (
+	// IdempotencyKeyHeader is the client provided key of the upload.
+	// The retries of the upload with the same key get the response
+	// of the first successful upload, without stamping the chunks again.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on the replayed responses.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyPrefix    = "idempotency-"
+	idempotencyKeyMaxLength = 255
+	idempotencyTTL          = 24 * time.Hour
+)
+
+// idempotentHeaders are the response headers replayed with the response.
+var idempotentHeaders = []string{
+	"Content-Type",
+	"ETag",
+	SwarmTagHeader,
+	"Access-Control-Expose-Headers",
+}
+
+// idempotentResponse is the recorded response to the upload.
+type idempotentResponse struct {
+	Method    string      `json:"method"`
+	Path      string      `json:"path"`
+	Status    int         `json:"status"`
+	Header    http.Header `json:"header"`
+	Body      []byte      `json:"body"`
+	CreatedAt int64       `json:"createdAt"`
+}
+
+// idempotencyKey returns the statestore key of the client provided key,
+// scoped to the tenant of the request, so that the tenants can not
+// replay the responses to the uploads of each other.
+func idempotencyKey(r *http.Request, key string) string {
+	if t := requestTenant(r.Context()); t != nil {
+		return idempotencyKeyPrefix + t.name + "-" + key
+	}
+	return idempotencyKeyPrefix + "-" + key
+}
+
+// idempotencyHandler replays the recorded response to the upload if the
+// request carries the idempotency key of an upload which already succeeded.
+// Otherwise the successful response of the upload is recorded under the key.
+func (s *Service) idempotencyHandler() func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := s.logger.WithName("idempotency").Build()
+
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || s.stateStore == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > idempotencyKeyMaxLength {
+				jsonhttp.BadRequest(w, "idempotency key too long")
+				return
+			}
+			storeKey := idempotencyKey(r, key)
+
+			s.idempotencyMu.Lock()
+			if _, ok := s.idempotencyInflight[storeKey]; ok {
+				s.idempotencyMu.Unlock()
+				jsonhttp.Conflict(w, "request with the idempotency key is in progress")
+				return
+			}
+			s.idempotencyInflight[storeKey] = struct{}{}
+			s.idempotencyMu.Unlock()
+
+			defer func() {
+				s.idempotencyMu.Lock()
+				delete(s.idempotencyInflight, storeKey)
+				s.idempotencyMu.Unlock()
+			}()
+
+			var recorded idempotentResponse
+			switch err := s.stateStore.Get(storeKey, &recorded); {
+			case errors.Is(err, storage.ErrNotFound):
+			case err != nil:
+				logger.Debug("get idempotent response failed", "key", key, "error", err)
+				logger.Error(nil, "get idempotent response failed")
+				jsonhttp.InternalServerError(w, "get idempotent response failed")
+				return
+			case time.Since(time.Unix(0, recorded.CreatedAt)) > idempotencyTTL:
+				// the key expired and may be used for a new upload
+			case recorded.Method != r.Method || recorded.Path != r.URL.Path:
+				jsonhttp.UnprocessableEntity(w, "idempotency key used for a different request")
+				return
+			default:
+				for k, v := range recorded.Header {
+					w.Header()[k] = v
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.Header().Set("Content-Length", strconv.Itoa(len(recorded.Body)))
+				w.WriteHeader(recorded.Status)
+				_, _ = w.Write(recorded.Body)
+				return
+			}
+
+			rw := &recordingResponseWriter{ResponseWriter: w}
+			h.ServeHTTP(rw, r)
+			if rw.status < http.StatusOK || rw.status >= http.StatusMultipleChoices {
+				return
+			}
+
+			resp := idempotentResponse{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    rw.status,
+				Header:    make(http.Header),
+				Body:      rw.body.Bytes(),
+				CreatedAt: time.Now().UnixNano(),
+			}
+			for _, k := range idempotentHeaders {
+				if v := w.Header().Values(k); len(v) > 0 {
+					resp.Header[k] = v
+				}
+			}
+			if err := s.stateStore.Put(storeKey, resp); err != nil {
+				logger.Debug("put idempotent response failed", "key", key, "error", err)
+				logger.Error(nil, "put idempotent response failed")
+			}
+		})
+	}
+}
+
+// recordingResponseWriter records the status and
+// the body of the response while writing it.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger          = log.Noop
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:      mock.NewStorer(),
+			Tags:        tags.NewTags(nil, logger),
+			Logger:      logger,
+			Post:        mockpost.New(mockpost.WithAcceptAll()),
+			StateStorer: statestore.NewStateStore(),
+		})
+		upload = func(t *testing.T, key, data string, opts ...jsonhttptest.Option) http.Header {
+			t.Helper()
+
+			var resp api.BytesPostResponse
+			header := jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated, append([]jsonhttptest.Option{
+				jsonhttptest.WithRequestHeader(api.IdempotencyKeyHeader, key),
+				jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithRequestBody(strings.NewReader(data)),
+				jsonhttptest.WithUnmarshalJSONResponse(&resp),
+			}, opts...)...)
+			header.Set("Reference", resp.Reference.String())
+			return header
+		}
+	)
+
+	first := upload(t, "key", "first")
+	if first.Get(api.IdempotentReplayedHeader) != "" {
+		t.Fatal("first upload replayed")
+	}
+
+	// the retry gets the original response, even if the body differs
+	retry := upload(t, "key", "retried")
+	if retry.Get(api.IdempotentReplayedHeader) != "true" {
+		t.Fatal("retried upload not replayed")
+	}
+	if retry.Get("Reference") != first.Get("Reference") || retry.Get(api.SwarmTagHeader) != first.Get(api.SwarmTagHeader) {
+		t.Fatalf("got replayed reference %s and tag %s, want %s and %s", retry.Get("Reference"), retry.Get(api.SwarmTagHeader), first.Get("Reference"), first.Get(api.SwarmTagHeader))
+	}
+
+	other := upload(t, "other-key", "first")
+	if other.Get(api.IdempotentReplayedHeader) != "" || other.Get(api.SwarmTagHeader) == first.Get(api.SwarmTagHeader) {
+		t.Fatal("upload with another key replayed")
+	}
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusUnprocessableEntity,
+		jsonhttptest.WithRequestHeader(api.IdempotencyKeyHeader, "key"),
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+		jsonhttptest.WithRequestBody(strings.NewReader("first")),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "idempotency key used for a different request",
+			Code:    http.StatusUnprocessableEntity,
+		}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusBadRequest,
+		jsonhttptest.WithRequestHeader(api.IdempotencyKeyHeader, strings.Repeat("k", 256)),
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(strings.NewReader("first")),
+	)
+}
@@ -0,0 +1,222 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/ipfs"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+	"github.com/ipfs/go-cid"
+)
+
+type ipfsImportFile struct {
+	Path      string        `json:"path"`
+	Reference swarm.Address `json:"reference"`
+	Size      int64         `json:"size"`
+}
+
+type ipfsImportResponse struct {
+	CID       string           `json:"cid"`
+	Reference swarm.Address    `json:"reference"`
+	Files     []ipfsImportFile `json:"files"`
+}
+
+// ipfsDirReader reads the files of the tar archive of the IPFS DAG and
+// records the mapping of the imported files. The paths are relative to
+// the root directory of the DAG, which is named by the CID in the archive.
+type ipfsDirReader struct {
+	dirReader
+	root  string
+	files []ipfsImportFile
+}
+
+func (r *ipfsDirReader) Next() (*FileInfo, error) {
+	fi, err := r.dirReader.Next()
+	if err != nil {
+		return nil, err
+	}
+	// a single file DAG is stored under the name of the CID
+	fi.Path = strings.TrimPrefix(fi.Path, r.root+"/")
+	r.files = append(r.files, ipfsImportFile{Path: fi.Path, Size: fi.Size})
+	return fi, nil
+}
+
+// pipeline records the reference of the file being stored.
+func (r *ipfsDirReader) pipeline(p pipelineFunc) pipelineFunc {
+	return func(ctx context.Context, rd io.Reader) (swarm.Address, int64, error) {
+		ref, length, err := p(ctx, rd)
+		if err == nil && len(r.files) > 0 {
+			r.files[len(r.files)-1].Reference = ref
+		}
+		return ref, length, err
+	}
+}
+
+// ipfsImportHandler fetches the UnixFS DAG of the CID from the configured
+// IPFS gateway, stores its files under the manifest with the same paths
+// and returns the reference of the manifest with the mapping of the files.
+func (s *Service) ipfsImportHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_import_ipfs").Build())
+
+	paths := struct {
+		CID string `map:"cid" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+	c, err := cid.Decode(paths.CID)
+	if err != nil {
+		logger.Debug("decode cid failed", "cid", paths.CID, "error", err)
+		jsonhttp.BadRequest(w, "invalid cid")
+		return
+	}
+
+	if s.ipfs == nil {
+		jsonhttp.NotImplemented(w, "ipfs import not configured")
+		return
+	}
+
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debug("putter failed", "error", err)
+		logger.Error(nil, "putter failed")
+		switch {
+		case errors.Is(err, errBatchNotAllowed):
+			jsonhttp.Forbidden(w, "batch not allowed")
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, errInvalidPostageBatch):
+			jsonhttp.BadRequest(w, "invalid batch id")
+		case errors.Is(err, errUnsupportedDevNodeOperation):
+			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		case errors.Is(err, clockskew.ErrClockSkewed):
+			jsonhttp.ServiceUnavailable(w, err.Error())
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return
+	}
+
+	body, err := s.ipfs.FetchTar(r.Context(), c)
+	if err != nil {
+		logger.Debug("fetch ipfs content failed", "cid", c, "error", err)
+		logger.Error(nil, "fetch ipfs content failed")
+		switch {
+		case errors.Is(err, ipfs.ErrNotFound):
+			jsonhttp.NotFound(w, "ipfs content not found")
+		case errors.Is(err, context.DeadlineExceeded):
+			jsonhttp.GatewayTimeout(w, "ipfs gateway timed out")
+		default:
+			jsonhttp.BadGateway(w, "fetch ipfs content failed")
+		}
+		return
+	}
+	defer body.Close()
+
+	tag, err := s.createTag(r.Context())
+	if err != nil {
+		logger.Debug("create tag failed", "error", err)
+		logger.Error(nil, "create tag failed")
+		jsonhttp.InternalServerError(w, "cannot create tag")
+		return
+	}
+
+	maxFileSize := s.MaxDirUploadFileSize
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultMaxDirUploadFileSize
+	}
+
+	dReader := &ipfsDirReader{
+		dirReader: &tarReader{r: tar.NewReader(body), logger: s.logger},
+		root:      c.String(),
+	}
+	ctx := sctx.SetTag(r.Context(), tag)
+	reference, err := storeDir(
+		ctx,
+		requestEncrypt(r),
+		dReader,
+		s.logger,
+		dReader.pipeline(requestPipelineFn(putter, r)),
+		loadsave.New(putter, requestPipelineFactory(ctx, putter, r)),
+		r.Header.Get(SwarmIndexDocumentHeader),
+		r.Header.Get(SwarmErrorDocumentHeader),
+		tag,
+		true,
+		maxFileSize,
+	)
+	if err != nil {
+		logger.Debug("store dir failed", "cid", c, "error", err)
+		logger.Error(nil, "store dir failed")
+		failUploadTag(logger, tag, tags.PhaseSplit, err)
+		switch {
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
+		case errors.Is(err, errEmptyDir):
+			jsonhttp.BadRequest(w, "no files in ipfs content")
+		case errors.Is(err, errFileTooLarge):
+			jsonhttp.RequestEntityTooLarge(w, errFileTooLarge)
+		case errors.Is(err, tar.ErrHeader):
+			jsonhttp.BadGateway(w, "invalid tar archive from ipfs gateway")
+		default:
+			jsonhttp.InternalServerError(w, errDirectoryStore)
+		}
+		return
+	}
+	s.watchReceipts(logger, reference)
+
+	if _, err := tag.DoneSplit(reference); err != nil {
+		logger.Debug("done split failed", "error", err)
+		logger.Error(nil, "done split failed")
+		jsonhttp.InternalServerError(w, "done split failed")
+		return
+	}
+
+	if requestPin(r) {
+		if err := s.pinning.CreatePin(r.Context(), reference, false); err != nil {
+			logger.Debug("pin creation failed", "address", reference, "error", err)
+			logger.Error(nil, "pin creation failed")
+			if errors.Is(err, errPinQuotaExceeded) {
+				jsonhttp.Forbidden(w, "pin quota exceeded")
+				return
+			}
+			jsonhttp.InternalServerError(w, "create pin failed")
+			return
+		}
+	}
+
+	if err := wait(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		failUploadTag(logger, tag, tags.PhasePush, err)
+		jsonhttp.InternalServerError(w, "sync chunks failed")
+		return
+	}
+
+	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
+	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
+	jsonhttp.Created(w, ipfsImportResponse{
+		CID:       c.String(),
+		Reference: reference,
+		Files:     dReader.files,
+	})
+}
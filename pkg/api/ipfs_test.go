@@ -0,0 +1,141 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/ipfs"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ipfs/go-cid"
+)
+
+type ipfsFetcher map[string][]byte
+
+func (f ipfsFetcher) FetchTar(_ context.Context, c cid.Cid) (io.ReadCloser, error) {
+	data, ok := f[c.String()]
+	if !ok {
+		return nil, ipfs.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// nolint:paralleltest
+func TestIPFSImport(t *testing.T) {
+	const (
+		dirCID     = "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+		fileCID    = "bafkreifjjcie6lypi6ny7amxnfftagclbuxndqonfipmb64f2km2devei4"
+		missingCID = "bafkreidgvpkjawlxz6sffxzwgooowe5yt7i6wsyg236mfoks77nywkptdq"
+	)
+
+	var (
+		storer  = mock.NewStorer()
+		fetcher = ipfsFetcher{
+			dirCID: tarFiles(t, []f{
+				{data: []byte("<h1>hello</h1>"), dir: dirCID, name: "index.html"},
+				{data: []byte("body {}"), dir: dirCID + "/css", name: "style.css"},
+			}).Bytes(),
+			fileCID: tarFiles(t, []f{
+				{data: []byte("hello"), name: fileCID},
+			}).Bytes(),
+		}
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storer,
+			Tags:   tags.NewTags(nil, log.Noop),
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+			IPFS:   fetcher,
+		})
+	)
+
+	t.Run("directory", func(t *testing.T) {
+		var res api.IPFSImportResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/import/ipfs/"+dirCID, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.CID != dirCID {
+			t.Fatalf("got cid %s, want %s", res.CID, dirCID)
+		}
+		if len(res.Files) != 2 {
+			t.Fatalf("got %d files, want 2", len(res.Files))
+		}
+		for i, want := range []struct {
+			path string
+			size int64
+		}{{"index.html", 14}, {"css/style.css", 7}} {
+			if got := res.Files[i]; got.Path != want.path || got.Size != want.size || got.Reference.IsZero() {
+				t.Fatalf("got file %+v, want path %s and size %d", got, want.path, want.size)
+			}
+		}
+
+		ref := res.Reference.String()
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+ref+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("<h1>hello</h1>")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+ref+"/css/style.css", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("body {}")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+res.Files[0].Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("<h1>hello</h1>")),
+		)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		var res api.IPFSImportResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/import/ipfs/"+fileCID, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if len(res.Files) != 1 || res.Files[0].Path != fileCID {
+			t.Fatalf("got files %+v, want the file named by the cid", res.Files)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+res.Reference.String()+"/"+fileCID, http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("hello")),
+		)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/import/ipfs/"+missingCID, http.StatusNotFound,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "ipfs content not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("invalid cid", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/import/ipfs/not-a-cid", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid cid",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			Storer: storer,
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+		jsonhttptest.Request(t, client, http.MethodPost, "/import/ipfs/"+dirCID, http.StatusNotImplemented,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		)
+	})
+}
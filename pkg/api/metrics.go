@@ -5,12 +5,14 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/ethersphere/bee"
 	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 )
@@ -23,10 +25,9 @@ type metrics struct {
 	// all metrics fields must be exported
 	// to be able to return them by Metrics()
 	// using reflection
-	RequestCount       prometheus.Counter
-	ResponseDuration   prometheus.Histogram
-	PingRequestCount   prometheus.Counter
-	ResponseCodeCounts *prometheus.CounterVec
+	RequestCount     *prometheus.CounterVec
+	ResponseDuration *prometheus.HistogramVec
+	PingRequestCount prometheus.Counter
 
 	ContentApiDuration prometheus.HistogramVec
 }
@@ -35,27 +36,24 @@ func newMetrics() metrics {
 	subsystem := "api"
 
 	return metrics{
-		RequestCount: prometheus.NewCounter(prometheus.CounterOpts{
-			Namespace: m.Namespace,
-			Subsystem: subsystem,
-			Name:      "request_count",
-			Help:      "Number of API requests.",
-		}),
-		ResponseDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
-			Namespace: m.Namespace,
-			Subsystem: subsystem,
-			Name:      "response_duration_seconds",
-			Help:      "Histogram of API response durations.",
-			Buckets:   []float64{0.01, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
-		}),
-		ResponseCodeCounts: prometheus.NewCounterVec(
+		RequestCount: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: m.Namespace,
 				Subsystem: subsystem,
-				Name:      "response_code_count",
-				Help:      "Response count grouped by status code",
+				Name:      "request_count",
+				Help:      "Number of API requests grouped by route, method and status code.",
+			},
+			[]string{"route", "method", "code"},
+		),
+		ResponseDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "response_duration_seconds",
+				Help:      "Histogram of API response durations grouped by route, method and status code.",
+				Buckets:   []float64{0.01, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
 			},
-			[]string{"code", "method"},
+			[]string{"route", "method", "code"},
 		),
 		ContentApiDuration: *prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: m.Namespace,
@@ -82,24 +80,68 @@ func (s *Service) Metrics() []prometheus.Collector {
 	return m.PrometheusCollectorsFromFields(s.metrics)
 }
 
-func (s *Service) pageviewMetricsHandler(h http.Handler) http.Handler {
+// unmatchedRoute is the route label of the requests not matched by any route.
+const unmatchedRoute = "unmatched"
+
+// routeLabelKey is the context key of the route label of the request.
+type routeLabelKey struct{}
+
+// traceIDKey is the context key of the trace ID of the request, it is
+// recorded by the tracing handler as the exemplar of the response duration.
+type traceIDKey struct{}
+
+// routeLabelHandler records the path template of the matched route as the
+// route label of the request. It must be used as the middleware of the router.
+func routeLabelHandler(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		s.metrics.RequestCount.Inc()
+		if label, ok := r.Context().Value(routeLabelKey{}).(*string); ok {
+			if route := mux.CurrentRoute(r); route != nil {
+				if tpl, err := route.GetPathTemplate(); err == nil {
+					*label = tpl
+				}
+			}
+		}
 		h.ServeHTTP(w, r)
-		s.metrics.ResponseDuration.Observe(time.Since(start).Seconds())
 	})
 }
 
-func (s *Service) responseCodeMetricsHandler(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		wrapper := newResponseWriter(w)
-		h.ServeHTTP(wrapper, r)
-		s.metrics.ResponseCodeCounts.WithLabelValues(
-			strconv.Itoa(wrapper.statusCode),
-			r.Method,
-		).Inc()
-	})
+// metricsMethod returns the method label of the request,
+// the non-standard methods are grouped to bound the label values.
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "OTHER"
+}
+
+// newRouteMetricsHandler counts the requests and measures their durations
+// by the route, the method and the status code of the response. The route
+// is the path template recorded by the routeLabelHandler of the router. The
+// durations of the traced requests are observed with the trace ID exemplar.
+func newRouteMetricsHandler(metrics metrics) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			route := unmatchedRoute
+			traceID := ""
+			ctx := context.WithValue(r.Context(), routeLabelKey{}, &route)
+			ctx = context.WithValue(ctx, traceIDKey{}, &traceID)
+			wrapper := newResponseWriter(w)
+			h.ServeHTTP(wrapper, r.WithContext(ctx))
+
+			labels := []string{route, metricsMethod(r.Method), strconv.Itoa(wrapper.statusCode)}
+			metrics.RequestCount.WithLabelValues(labels...).Inc()
+
+			duration := metrics.ResponseDuration.WithLabelValues(labels...)
+			if eo, ok := duration.(prometheus.ExemplarObserver); ok && traceID != "" {
+				eo.ObserveWithExemplar(time.Since(start).Seconds(), prometheus.Labels{"trace_id": traceID})
+			} else {
+				duration.Observe(time.Since(start).Seconds())
+			}
+		})
+	}
 }
 
 // UpgradedResponseWriter adds more functionality on top of ResponseWriter
@@ -5,9 +5,16 @@
 package api_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func TestToFileSizeBucket(t *testing.T) {
@@ -31,3 +38,83 @@ func TestToFileSizeBucket(t *testing.T) {
 		t.Fatalf("bucket should be the last bucket")
 	}
 }
+
+func TestRouteMetrics(t *testing.T) {
+	t.Parallel()
+
+	router := mux.NewRouter()
+	router.Handle("/bytes/{address}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if mux.Vars(r)["address"] == "missing" {
+				jsonhttp.NotFound(w, nil)
+				return
+			}
+			jsonhttp.OK(w, nil)
+		}),
+	})
+	handler, collectors := api.NewRouteMetricsHandler(router)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors...)
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	for _, r := range []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/bytes/aa"},
+		{http.MethodGet, "/bytes/bb"},
+		{http.MethodGet, "/bytes/missing"},
+		{http.MethodPost, "/bytes/aa"},
+		{http.MethodGet, "/unknown"},
+		{"PROPFIND", "/unknown"},
+	} {
+		req, err := http.NewRequest(r.method, server.URL+r.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]uint64)
+	for _, f := range families {
+		for _, metric := range f.GetMetric() {
+			labels := make([]string, 0, len(metric.GetLabel()))
+			for _, l := range metric.GetLabel() {
+				labels = append(labels, l.GetName()+"="+l.GetValue())
+			}
+			key := strings.TrimPrefix(f.GetName(), "bee_api_") + "{" + strings.Join(labels, ",") + "}"
+			switch {
+			case metric.GetCounter() != nil:
+				got[key] = uint64(metric.GetCounter().GetValue())
+			case metric.GetHistogram() != nil:
+				got[key] = metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	want := map[string]uint64{
+		"request_count{code=200,method=GET,route=/bytes/{address}}":              2,
+		"request_count{code=404,method=GET,route=/bytes/{address}}":              1,
+		"request_count{code=405,method=POST,route=/bytes/{address}}":             1,
+		"request_count{code=404,method=GET,route=unmatched}":                     1,
+		"request_count{code=404,method=OTHER,route=unmatched}":                   1,
+		"response_duration_seconds{code=200,method=GET,route=/bytes/{address}}":  2,
+		"response_duration_seconds{code=404,method=GET,route=/bytes/{address}}":  1,
+		"response_duration_seconds{code=405,method=POST,route=/bytes/{address}}": 1,
+		"response_duration_seconds{code=404,method=GET,route=unmatched}":         1,
+		"response_duration_seconds{code=404,method=OTHER,route=unmatched}":       1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got metrics %v, want %v", got, want)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/multiformats/go-multiaddr"
 )
@@ -21,6 +22,14 @@ type addressesResponse struct {
 	Ethereum     common.Address        `json:"ethereum"`
 	PublicKey    string                `json:"publicKey"`
 	PSSPublicKey string                `json:"pssPublicKey"`
+	NAT          *natStatusResponse    `json:"nat,omitempty"`
+}
+
+type natStatusResponse struct {
+	Reachability   string                `json:"reachability"`
+	Relay          bool                  `json:"relay"`
+	RelayService   bool                  `json:"relayService"`
+	RelayAddresses []multiaddr.Multiaddr `json:"relayAddresses"`
 }
 
 func (s *Service) addressesHandler(w http.ResponseWriter, _ *http.Request) {
@@ -28,6 +37,7 @@ func (s *Service) addressesHandler(w http.ResponseWriter, _ *http.Request) {
 
 	// initialize variable to json encode as [] instead null if p2p is nil
 	underlay := make([]multiaddr.Multiaddr, 0)
+	var nat *natStatusResponse
 	// addresses endpoint is exposed before p2p service is configured
 	// to provide information about other addresses.
 	if s.p2p != nil {
@@ -38,6 +48,16 @@ func (s *Service) addressesHandler(w http.ResponseWriter, _ *http.Request) {
 			return
 		}
 		underlay = u
+
+		if n, ok := s.p2p.(p2p.NATStatuser); ok {
+			status := n.NATStatus()
+			nat = &natStatusResponse{
+				Reachability:   status.Reachability.String(),
+				Relay:          status.Relay,
+				RelayService:   status.RelayService,
+				RelayAddresses: status.RelayAddresses,
+			}
+		}
 	}
 	jsonhttp.OK(w, addressesResponse{
 		Overlay:      s.overlay,
@@ -45,5 +65,6 @@ func (s *Service) addressesHandler(w http.ResponseWriter, _ *http.Request) {
 		Ethereum:     s.ethereumAddress,
 		PublicKey:    hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(&s.publicKey)),
 		PSSPublicKey: hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(&s.pssPublicKey)),
+		NAT:          nat,
 	})
 }
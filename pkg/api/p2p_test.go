@@ -15,6 +15,7 @@ import (
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/p2p/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/multiformats/go-multiaddr"
@@ -60,6 +61,10 @@ func TestAddresses(t *testing.T) {
 				Ethereum:     ethereumAddress,
 				PublicKey:    hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(&privateKey.PublicKey)),
 				PSSPublicKey: hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(&pssPrivateKey.PublicKey)),
+				NAT: &api.NATStatusResponse{
+					Reachability:   p2p.ReachabilityStatusUnknown.String(),
+					RelayAddresses: make([]multiaddr.Multiaddr, 0),
+				},
 			}),
 		)
 	})
@@ -73,6 +78,56 @@ func TestAddresses(t *testing.T) {
 	)
 }
 
+func TestAddresses_nat(t *testing.T) {
+	t.Parallel()
+
+	overlay := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	addresses := []multiaddr.Multiaddr{
+		mustMultiaddr(t, "/ip4/192.168.0.101/tcp/7071/p2p/16Uiu2HAmTBuJT9LvNmBiQiNoTsxE5mtNy6YG3paw79m94CRa9sRb"),
+	}
+	relayAddresses := []multiaddr.Multiaddr{
+		mustMultiaddr(t, "/ip4/203.0.113.7/tcp/1634/p2p/16Uiu2HAm3gQkzqBe5FPPRdnZ1CsBHApvmqkp4D4cMZvrHXa2GMfx/p2p-circuit/p2p/16Uiu2HAmTBuJT9LvNmBiQiNoTsxE5mtNy6YG3paw79m94CRa9sRb"),
+	}
+
+	privateKey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testServer, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:     true,
+		PublicKey:    privateKey.PublicKey,
+		PSSPublicKey: privateKey.PublicKey,
+		Overlay:      overlay,
+		P2P: mock.New(
+			mock.WithAddressesFunc(func() ([]multiaddr.Multiaddr, error) {
+				return addresses, nil
+			}),
+			mock.WithNATStatusFunc(func() p2p.NATStatus {
+				return p2p.NATStatus{
+					Reachability:   p2p.ReachabilityStatusPrivate,
+					Relay:          true,
+					RelayAddresses: relayAddresses,
+				}
+			}),
+		),
+	})
+
+	jsonhttptest.Request(t, testServer, http.MethodGet, "/addresses", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.AddressesResponse{
+			Overlay:      &overlay,
+			Underlay:     addresses,
+			PublicKey:    hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(&privateKey.PublicKey)),
+			PSSPublicKey: hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(&privateKey.PublicKey)),
+			NAT: &api.NATStatusResponse{
+				Reachability:   p2p.ReachabilityStatusPrivate.String(),
+				Relay:          true,
+				RelayAddresses: relayAddresses,
+			},
+		}),
+	)
+}
+
 func TestAddresses_error(t *testing.T) {
 	t.Parallel()
 
@@ -77,6 +77,56 @@ func (s *Service) peerDisconnectHandler(w http.ResponseWriter, r *http.Request)
 	jsonhttp.OK(w, nil)
 }
 
+type peerTrafficResponse struct {
+	Address   swarm.Address              `json:"address"`
+	Protocols map[string]trafficResponse `json:"protocols"`
+}
+
+type trafficResponse struct {
+	BytesIn     uint64 `json:"bytesIn"`
+	BytesOut    uint64 `json:"bytesOut"`
+	MessagesIn  uint64 `json:"messagesIn"`
+	MessagesOut uint64 `json:"messagesOut"`
+}
+
+func (s *Service) peerTrafficHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithValues("get_peer_traffic").Build()
+
+	paths := struct {
+		Address swarm.Address `map:"address" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	reporter, ok := s.p2p.(p2p.TrafficReporter)
+	if !ok {
+		jsonhttp.NotImplemented(w, "peer traffic not supported")
+		return
+	}
+	traffic, ok := reporter.PeerTraffic(paths.Address)
+	if !ok {
+		logger.Debug("peer traffic: peer not found", "peer_address", paths.Address)
+		jsonhttp.NotFound(w, "peer not found")
+		return
+	}
+
+	protocols := make(map[string]trafficResponse, len(traffic))
+	for name, t := range traffic {
+		protocols[name] = trafficResponse{
+			BytesIn:     t.BytesIn,
+			BytesOut:    t.BytesOut,
+			MessagesIn:  t.MessagesIn,
+			MessagesOut: t.MessagesOut,
+		}
+	}
+	jsonhttp.OK(w, peerTrafficResponse{
+		Address:   paths.Address,
+		Protocols: protocols,
+	})
+}
+
 // Peer holds information about a Peer.
 type Peer struct {
 	Address  swarm.Address `json:"address"`
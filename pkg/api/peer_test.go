@@ -183,6 +183,48 @@ func TestPeer(t *testing.T) {
 	})
 }
 
+func TestPeerTraffic(t *testing.T) {
+	t.Parallel()
+
+	overlay := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	unknown := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59a")
+	testServer, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		P2P: mock.New(mock.WithPeerTrafficFunc(func(addr swarm.Address) (map[string]p2p.Traffic, bool) {
+			if !addr.Equal(overlay) {
+				return nil, false
+			}
+			return map[string]p2p.Traffic{
+				"pushsync": {BytesIn: 4100, BytesOut: 120, MessagesIn: 1, MessagesOut: 1},
+			}, true
+		})),
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/debug/peers/"+overlay.String()+"/traffic", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.PeerTrafficResponse{
+				Address: overlay,
+				Protocols: map[string]api.TrafficResponse{
+					"pushsync": {BytesIn: 4100, BytesOut: 120, MessagesIn: 1, MessagesOut: 1},
+				},
+			}),
+		)
+	})
+
+	t.Run("peer not found", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/debug/peers/"+unknown.String()+"/traffic", http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotFound,
+				Message: "peer not found",
+			}),
+		)
+	})
+}
+
 func TestBlocklistedPeers(t *testing.T) {
 	t.Parallel()
 
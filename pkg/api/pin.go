@@ -7,10 +7,14 @@ package api
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/pinning"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
 	"github.com/gorilla/mux"
 )
 
@@ -26,7 +30,19 @@ func (s *Service) pinRootHash(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	has, err := s.pinning.HasPin(paths.Reference)
+	queries := struct {
+		Path string `map:"path"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	if path := strings.TrimPrefix(queries.Path, "/"); path != "" {
+		s.pinPath(w, r, logger, paths.Reference, path)
+		return
+	}
+
+	has, err := s.hasPin(r.Context(), paths.Reference)
 	if err != nil {
 		logger.Debug("pin root hash: has pin failed", "chunk_address", paths.Reference, "error", err)
 		logger.Error(nil, "pin root hash: has pin failed")
@@ -42,6 +58,11 @@ func (s *Service) pinRootHash(w http.ResponseWriter, r *http.Request) {
 	case errors.Is(err, storage.ErrNotFound):
 		jsonhttp.NotFound(w, nil)
 		return
+	case errors.Is(err, errPinQuotaExceeded):
+		logger.Debug("pin root hash: pin quota exceeded", "chunk_address", paths.Reference)
+		logger.Error(nil, "pin root hash: pin quota exceeded")
+		jsonhttp.Forbidden(w, "pin quota exceeded")
+		return
 	case err != nil:
 		logger.Debug("pin root hash: create pin failed", "chunk_address", paths.Reference, "error", err)
 		logger.Error(nil, "pin root hash: create pin failed")
@@ -64,7 +85,19 @@ func (s *Service) unpinRootHash(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	has, err := s.pinning.HasPin(paths.Reference)
+	queries := struct {
+		Path string `map:"path"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	if path := strings.TrimPrefix(queries.Path, "/"); path != "" {
+		s.unpinPath(w, r, logger, paths.Reference, path)
+		return
+	}
+
+	has, err := s.hasPin(r.Context(), paths.Reference)
 	if err != nil {
 		logger.Debug("unpin root hash: has pin failed", "chunk_address", paths.Reference, "error", err)
 		logger.Error(nil, "unpin root hash: has pin failed")
@@ -98,7 +131,19 @@ func (s *Service) getPinnedRootHash(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	has, err := s.pinning.HasPin(paths.Reference)
+	queries := struct {
+		Path string `map:"path"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	if path := strings.TrimPrefix(queries.Path, "/"); path != "" {
+		s.getPinnedPath(w, r, logger, paths.Reference, path)
+		return
+	}
+
+	has, err := s.hasPin(r.Context(), paths.Reference)
 	if err != nil {
 		logger.Debug("pinned root hash: has pin failed", "chunk_address", paths.Reference, "error", err)
 		logger.Error(nil, "pinned root hash: has pin failed")
@@ -122,17 +167,110 @@ func (s *Service) getPinnedRootHash(w http.ResponseWriter, r *http.Request) {
 func (s *Service) listPinnedRootHashes(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("get_pins").Build()
 
-	pinned, err := s.pinning.Pins()
+	pinned, err := s.listPins(r.Context())
 	if err != nil {
 		logger.Debug("list pinned root references: unable to list references", "error", err)
 		logger.Error(nil, "list pinned root references: unable to list references")
 		jsonhttp.InternalServerError(w, "list pinned root references failed")
 		return
 	}
+	pinnedPaths, err := s.listPathPins(r.Context())
+	if err != nil {
+		logger.Debug("list pinned root references: unable to list paths", "error", err)
+		logger.Error(nil, "list pinned root references: unable to list paths")
+		jsonhttp.InternalServerError(w, "list pinned root references failed")
+		return
+	}
 
 	jsonhttp.OK(w, struct {
-		References []swarm.Address `json:"references"`
+		References []swarm.Address   `json:"references"`
+		Paths      []pinning.PathPin `json:"paths,omitempty"`
 	}{
 		References: pinned,
+		Paths:      pinnedPaths,
+	})
+}
+
+// pinPath pins the entries of the manifest under the path. This method is idempotent.
+func (s *Service) pinPath(w http.ResponseWriter, r *http.Request, logger log.Logger, reference swarm.Address, path string) {
+	has, err := s.hasPathPin(r.Context(), reference, path)
+	if err != nil {
+		logger.Debug("pin path: has pin failed", "chunk_address", reference, "path", path, "error", err)
+		logger.Error(nil, "pin path: has pin failed")
+		jsonhttp.InternalServerError(w, "pin path: checking of tracking pin failed")
+		return
+	}
+	if has {
+		jsonhttp.OK(w, nil)
+		return
+	}
+
+	switch err = s.pinning.CreatePathPin(r.Context(), reference, path); {
+	case errors.Is(err, storage.ErrNotFound):
+		jsonhttp.NotFound(w, nil)
+		return
+	case errors.Is(err, traversal.ErrPrefixNotFound):
+		jsonhttp.NotFound(w, "path not found")
+		return
+	case errors.Is(err, traversal.ErrNotManifest):
+		jsonhttp.BadRequest(w, "reference is not a manifest")
+		return
+	case errors.Is(err, errPinQuotaExceeded):
+		logger.Debug("pin path: pin quota exceeded", "chunk_address", reference, "path", path)
+		logger.Error(nil, "pin path: pin quota exceeded")
+		jsonhttp.Forbidden(w, "pin quota exceeded")
+		return
+	case err != nil:
+		logger.Debug("pin path: create pin failed", "chunk_address", reference, "path", path, "error", err)
+		logger.Error(nil, "pin path: create pin failed")
+		jsonhttp.InternalServerError(w, "pin path: creation of tracking pin failed")
+		return
+	}
+
+	jsonhttp.Created(w, nil)
+}
+
+// unpinPath unpins an already pinned manifest path. This method is idempotent.
+func (s *Service) unpinPath(w http.ResponseWriter, r *http.Request, logger log.Logger, reference swarm.Address, path string) {
+	has, err := s.hasPathPin(r.Context(), reference, path)
+	if err != nil {
+		logger.Debug("unpin path: has pin failed", "chunk_address", reference, "path", path, "error", err)
+		logger.Error(nil, "unpin path: has pin failed")
+		jsonhttp.InternalServerError(w, "unpin path: checking of tracking pin failed")
+		return
+	}
+	if !has {
+		jsonhttp.NotFound(w, nil)
+		return
+	}
+
+	if err := s.pinning.DeletePathPin(r.Context(), reference, path); err != nil {
+		logger.Debug("unpin path: delete pin failed", "chunk_address", reference, "path", path, "error", err)
+		logger.Error(nil, "unpin path: delete pin failed")
+		jsonhttp.InternalServerError(w, "unpin path: deletion of pin failed")
+		return
+	}
+
+	jsonhttp.OK(w, nil)
+}
+
+// getPinnedPath returns back the given reference and path if the manifest path is pinned.
+func (s *Service) getPinnedPath(w http.ResponseWriter, r *http.Request, logger log.Logger, reference swarm.Address, path string) {
+	has, err := s.hasPathPin(r.Context(), reference, path)
+	if err != nil {
+		logger.Debug("pinned path: has pin failed", "chunk_address", reference, "path", path, "error", err)
+		logger.Error(nil, "pinned path: has pin failed")
+		jsonhttp.InternalServerError(w, "pinned path: check reference failed")
+		return
+	}
+
+	if !has {
+		jsonhttp.NotFound(w, nil)
+		return
+	}
+
+	jsonhttp.OK(w, pinning.PathPin{
+		Reference: reference,
+		Path:      path,
 	})
 }
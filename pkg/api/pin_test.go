@@ -14,6 +14,7 @@ import (
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
+	pinningsvc "github.com/ethersphere/bee/pkg/pinning"
 	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
 	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
@@ -87,7 +88,7 @@ func TestPinHandlers(t *testing.T) {
 		client, _, _, _ = newTestServer(t, testServerOptions{
 			Storer:    storerMock,
 			Traversal: traversal.New(storerMock),
-			Tags:      tags.NewTags(statestore.NewStateStore(), logger),
+			Tags:      tags.NewTags(nil, logger),
 			Pinning:   pinning.NewServiceMock(),
 			Logger:    logger,
 			Post:      mockpost.New(mockpost.WithAcceptAll()),
@@ -157,6 +158,89 @@ func TestPinHandlers(t *testing.T) {
 	})
 }
 
+// nolint:paralleltest
+func TestPinPathHandlers(t *testing.T) {
+	var (
+		logger          = log.Noop
+		storerMock      = mock.NewStorer()
+		traverser       = traversal.New(storerMock)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:    storerMock,
+			Traversal: traverser,
+			Tags:      tags.NewTags(nil, logger),
+			Pinning:   pinningsvc.NewService(storerMock, statestore.NewStateStore(), traverser),
+			Logger:    logger,
+			Post:      mockpost.New(mockpost.WithAcceptAll()),
+		})
+	)
+
+	var upload api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestBody(tarFiles(t, []f{
+			{data: []byte("<h1>Swarm"), name: "index.html"},
+			{data: []byte("png"), name: "a.png", dir: "images"},
+		})),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+
+	var (
+		pinsReferencePath = "/pins/" + upload.Reference.String()
+		pinsImagesPath    = pinsReferencePath + "?path=/images/"
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, pinsImagesPath, http.StatusNotFound)
+	jsonhttptest.Request(t, client, http.MethodPost, pinsReferencePath+"?path=/docs/", http.StatusNotFound,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "path not found",
+			Code:    http.StatusNotFound,
+		}),
+	)
+	jsonhttptest.Request(t, client, http.MethodPost, pinsImagesPath, http.StatusCreated)
+	jsonhttptest.Request(t, client, http.MethodPost, pinsImagesPath, http.StatusOK)
+
+	jsonhttptest.Request(t, client, http.MethodGet, pinsImagesPath, http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(pinningsvc.PathPin{
+			Reference: upload.Reference,
+			Path:      "images/",
+		}),
+	)
+	// the path pin is tracked separately from the pin of the whole manifest
+	jsonhttptest.Request(t, client, http.MethodGet, pinsReferencePath, http.StatusNotFound)
+	jsonhttptest.Request(t, client, http.MethodGet, "/pins", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(struct {
+			References []swarm.Address      `json:"references"`
+			Paths      []pinningsvc.PathPin `json:"paths"`
+		}{
+			References: []swarm.Address{},
+			Paths:      []pinningsvc.PathPin{{Reference: upload.Reference, Path: "images/"}},
+		}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodDelete, pinsImagesPath, http.StatusOK)
+	jsonhttptest.Request(t, client, http.MethodGet, pinsImagesPath, http.StatusNotFound)
+	jsonhttptest.Request(t, client, http.MethodDelete, pinsImagesPath, http.StatusNotFound)
+
+	t.Run("not a manifest", func(t *testing.T) {
+		var ref api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(strings.NewReader("this is a simple text")),
+			jsonhttptest.WithUnmarshalJSONResponse(&ref),
+		)
+		jsonhttptest.Request(t, client, http.MethodPost, "/pins/"+ref.Reference.String()+"?path=/images/", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "reference is not a manifest",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
 func Test_pinHandlers_invalidInputs(t *testing.T) {
 	t.Parallel()
 
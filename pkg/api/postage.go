@@ -66,6 +66,19 @@ func (b hexByte) MarshalJSON() ([]byte, error) {
 	return json.Marshal(hex.EncodeToString(b))
 }
 
+func (b *hexByte) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
 type postageCreateResponse struct {
 	BatchID hexByte `json:"batchID"`
 	TxHash  string  `json:"txHash"`
@@ -142,6 +155,7 @@ type postageStampResponse struct {
 	Exists        bool           `json:"exists"`
 	BatchTTL      int64          `json:"batchTTL"`
 	Expired       bool           `json:"expired"`
+	ExpiresIn     int64          `json:"expiresIn"`
 }
 
 type postageStampsResponse struct {
@@ -172,6 +186,21 @@ type bucketData struct {
 	Collisions uint32 `json:"collisions"`
 }
 
+type postageStampCapacityResponse struct {
+	BatchID          hexByte              `json:"batchID"`
+	Depth            uint8                `json:"depth"`
+	BucketDepth      uint8                `json:"bucketDepth"`
+	BucketUpperBound uint32               `json:"bucketUpperBound"`
+	ImmutableFlag    bool                 `json:"immutableFlag"`
+	Remaining        uint64               `json:"remaining"`
+	Buckets          []bucketCapacityData `json:"buckets"`
+}
+
+type bucketCapacityData struct {
+	BucketID  uint32 `json:"bucketID"`
+	Remaining uint32 `json:"remaining"`
+}
+
 func (s *Service) postageGetStampsHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("get_stamps").Build()
 
@@ -201,6 +230,13 @@ func (s *Service) postageGetStampsHandler(w http.ResponseWriter, r *http.Request
 			jsonhttp.InternalServerError(w, "unable to estimate batch expiration")
 			return
 		}
+		expiresIn, err := s.estimateBatchExpiresIn(v, exists, batchTTL)
+		if err != nil {
+			logger.Debug("get stamp issuer: estimate batch eviction failed", "batch_id", hex.EncodeToString(v.ID()), "error", err)
+			logger.Error(nil, "get stamp issuer: estimate batch eviction failed")
+			jsonhttp.InternalServerError(w, "unable to estimate batch eviction")
+			return
+		}
 		if queries.All || exists {
 			resp.Stamps = append(resp.Stamps, postageStampResponse{
 				BatchID:       v.ID(),
@@ -215,6 +251,7 @@ func (s *Service) postageGetStampsHandler(w http.ResponseWriter, r *http.Request
 				Exists:        exists,
 				BatchTTL:      batchTTL,
 				Expired:       v.Expired(),
+				ExpiresIn:     expiresIn,
 			})
 		}
 	}
@@ -308,6 +345,63 @@ func (s *Service) postageGetStampBucketsHandler(w http.ResponseWriter, r *http.R
 	jsonhttp.OK(w, resp)
 }
 
+// postageGetStampCapacityHandler returns the number of chunks which can still
+// be stamped in each collision bucket of the batch before it is full.
+func (s *Service) postageGetStampCapacityHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_stamp_capacity").Build()
+
+	paths := struct {
+		BatchID []byte `map:"batch_id" validate:"required,len=32"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+	hexBatchID := hex.EncodeToString(paths.BatchID)
+
+	issuer, save, err := s.post.GetStampIssuer(paths.BatchID)
+	if err != nil {
+		logger.Debug("get stamp issuer: get issuer failed", "batch_id", hexBatchID, "error", err)
+		logger.Error(nil, "get stamp issuer: get issuer failed")
+		switch {
+		case errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.BadRequest(w, "batch not usable")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "cannot get batch")
+		default:
+			jsonhttp.InternalServerError(w, "get issuer failed")
+		}
+		return
+	}
+	defer func() {
+		if err := save(); err != nil {
+			s.logger.Debug("stamp issuer save", "error", err)
+		}
+	}()
+
+	b := issuer.Buckets()
+	upperBound := issuer.BucketUpperBound()
+	resp := postageStampCapacityResponse{
+		BatchID:          paths.BatchID,
+		Depth:            issuer.Depth(),
+		BucketDepth:      issuer.BucketDepth(),
+		BucketUpperBound: upperBound,
+		ImmutableFlag:    issuer.ImmutableFlag(),
+		Buckets:          make([]bucketCapacityData, len(b)),
+	}
+
+	for i, v := range b {
+		var remaining uint32
+		if v < upperBound {
+			remaining = upperBound - v
+		}
+		resp.Buckets[i] = bucketCapacityData{BucketID: uint32(i), Remaining: remaining}
+		resp.Remaining += uint64(remaining)
+	}
+
+	jsonhttp.OK(w, resp)
+}
+
 func (s *Service) postageGetStampHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("get_stamp").Build()
 
@@ -348,6 +442,13 @@ func (s *Service) postageGetStampHandler(w http.ResponseWriter, r *http.Request)
 		jsonhttp.InternalServerError(w, "unable to estimate batch expiration")
 		return
 	}
+	expiresIn, err := s.estimateBatchExpiresIn(issuer, exists, batchTTL)
+	if err != nil {
+		logger.Debug("estimate batch eviction failed", "batch_id", hexBatchID, "error", err)
+		logger.Error(nil, "estimate batch eviction failed")
+		jsonhttp.InternalServerError(w, "unable to estimate batch eviction")
+		return
+	}
 
 	jsonhttp.OK(w, &postageStampResponse{
 		BatchID:       paths.BatchID,
@@ -362,6 +463,7 @@ func (s *Service) postageGetStampHandler(w http.ResponseWriter, r *http.Request)
 		Amount:        bigint.Wrap(issuer.Amount()),
 		BlockNumber:   issuer.BlockNumber(),
 		Expired:       issuer.Expired(),
+		ExpiresIn:     expiresIn,
 	})
 }
 
@@ -382,11 +484,8 @@ func (s *Service) reserveStateHandler(w http.ResponseWriter, _ *http.Request) {
 	logger := s.logger.WithName("get_reservestate").Build()
 
 	state := s.batchStore.GetReserveState()
-	commitment := int64(0)
-	if err := s.batchStore.Iterate(func(b *postage.Batch) (bool, error) {
-		commitment += int64(math.Pow(2.0, float64(b.Depth)))
-		return false, nil
-	}); err != nil {
+	commitment, err := s.batchCommitment()
+	if err != nil {
 		logger.Debug("batch store iteration failed", "error", err)
 		logger.Error(nil, "batch store iteration failed")
 
@@ -401,6 +500,16 @@ func (s *Service) reserveStateHandler(w http.ResponseWriter, _ *http.Request) {
 	})
 }
 
+// batchCommitment returns the total number of chunks the batches can stamp.
+func (s *Service) batchCommitment() (int64, error) {
+	commitment := int64(0)
+	err := s.batchStore.Iterate(func(b *postage.Batch) (bool, error) {
+		commitment += int64(math.Pow(2.0, float64(b.Depth)))
+		return false, nil
+	})
+	return commitment, err
+}
+
 // chainStateHandler returns the current chain state.
 func (s *Service) chainStateHandler(w http.ResponseWriter, r *http.Request) {
 	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("get_chainstate").Build())
@@ -455,6 +564,36 @@ func (s *Service) estimateBatchTTL(batch *postage.Batch) (int64, error) {
 	return ttl.Int64(), nil
 }
 
+// estimateBatchExpiresIn estimates the time remaining until the chunks of the
+// batch are evicted, which is the batch TTL extended by the grace period of
+// the expired batches. The -1 signals that the batch never expires.
+func (s *Service) estimateBatchExpiresIn(issuer *postage.StampIssuer, exists bool, batchTTL int64) (int64, error) {
+	blockTime := int64(s.blockTime / time.Second)
+	if exists {
+		if batchTTL < 0 {
+			return -1, nil
+		}
+		return batchTTL + int64(s.batchStore.ExpiryGracePeriod())*blockTime, nil
+	}
+
+	block, err := s.batchStore.EvictionBlock(issuer.ID())
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		if issuer.Expired() {
+			return 0, nil
+		}
+		return -1, nil
+	case err != nil:
+		return 0, err
+	}
+
+	current := s.batchStore.GetChainState().Block
+	if block <= current {
+		return 0, nil
+	}
+	return int64(block-current) * blockTime, nil
+}
+
 func (s *Service) postageTopUpHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("patch_stamp_topup").Build()
 
@@ -19,6 +19,7 @@ import (
 
 	"github.com/ethersphere/bee/pkg/api"
 	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/postage"
@@ -28,6 +29,7 @@ import (
 	contractMock "github.com/ethersphere/bee/pkg/postage/postagecontract/mock"
 	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
 	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/transaction/backendmock"
 )
 
@@ -286,6 +288,7 @@ func TestPostageGetStamps(t *testing.T) {
 						Exists:        true,
 						BatchTTL:      15, // ((value-totalAmount)/pricePerBlock)*blockTime=((20-5)/2)*2.
 						Expired:       false,
+						ExpiresIn:     15,
 					},
 				},
 			}),
@@ -317,6 +320,7 @@ func TestPostageGetStamps(t *testing.T) {
 						ImmutableFlag: si.ImmutableFlag(),
 						Exists:        false,
 						BatchTTL:      -1,
+						ExpiresIn:     -1,
 					},
 				},
 			}),
@@ -386,6 +390,60 @@ func TestPostageGetStamps(t *testing.T) {
 			}),
 		)
 	})
+
+	t.Run("grace period", func(t *testing.T) {
+		t.Parallel()
+
+		eb := postagetesting.MustNewBatch(postagetesting.WithValue(20))
+
+		esi := postage.NewStampIssuer("", "", eb.ID, big.NewInt(3), 11, 10, 1000, true)
+		emp := mockpost.New(mockpost.WithIssuer(esi), mockpost.WithIssuer(si))
+		emp.HandleStampExpiry(eb.ID)
+		bs := mock.New(
+			mock.WithChainState(cs),
+			mock.WithBatch(b),
+			mock.WithExpiryGracePeriod(5),
+			mock.WithEvictionBlock(eb.ID, 40),
+		)
+		ts, _, _, _ := newTestServer(t, testServerOptions{DebugAPI: true, Post: emp, BatchStore: bs, BlockTime: 2 * time.Second})
+
+		// the chunks of the expired batch are evicted at the end of the grace period
+		jsonhttptest.Request(t, ts, http.MethodGet, "/stamps/"+hex.EncodeToString(eb.ID), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(&api.PostageStampResponse{
+				BatchID:       eb.ID,
+				Utilization:   esi.Utilization(),
+				Usable:        false,
+				Label:         esi.Label(),
+				Depth:         esi.Depth(),
+				Amount:        bigint.Wrap(esi.Amount()),
+				BucketDepth:   esi.BucketDepth(),
+				BlockNumber:   esi.BlockNumber(),
+				ImmutableFlag: esi.ImmutableFlag(),
+				Exists:        false,
+				BatchTTL:      -1,
+				Expired:       true,
+				ExpiresIn:     60, // (evictionBlock-block)*blockTime=(40-10)*2.
+			}),
+		)
+
+		// the grace period extends the time to live of the batch
+		jsonhttptest.Request(t, ts, http.MethodGet, "/stamps/"+hex.EncodeToString(b.ID), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(&api.PostageStampResponse{
+				BatchID:       b.ID,
+				Utilization:   si.Utilization(),
+				Usable:        true,
+				Label:         si.Label(),
+				Depth:         si.Depth(),
+				Amount:        bigint.Wrap(si.Amount()),
+				BucketDepth:   si.BucketDepth(),
+				BlockNumber:   si.BlockNumber(),
+				ImmutableFlag: si.ImmutableFlag(),
+				Exists:        true,
+				BatchTTL:      15,
+				ExpiresIn:     25, // batchTTL+gracePeriod*blockTime=15+5*2.
+			}),
+		)
+	})
 }
 
 // TestGetAllBatches tests that the endpoint that returns all living
@@ -455,6 +513,7 @@ func TestPostageGetStamp(t *testing.T) {
 				ImmutableFlag: si.ImmutableFlag(),
 				Exists:        true,
 				BatchTTL:      15, // ((value-totalAmount)/pricePerBlock)*blockTime=((20-5)/2)*2.
+				ExpiresIn:     15,
 			}),
 		)
 	})
@@ -495,6 +554,53 @@ func TestPostageGetBuckets(t *testing.T) {
 
 }
 
+func TestPostageGetCapacity(t *testing.T) {
+	t.Parallel()
+
+	si := postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := swarm.MustParseHexAddress("0100000000000000000000000000000000000000000000000000000000000000")
+	if _, err := postage.NewStamper(si, crypto.NewDefaultSigner(pk)).Stamp(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	mp := mockpost.New(mockpost.WithIssuer(si))
+	ts, _, _, _ := newTestServer(t, testServerOptions{Post: mp, DebugAPI: true})
+	buckets := make([]api.BucketCapacityData, 1024)
+	for i := range buckets {
+		buckets[i] = api.BucketCapacityData{BucketID: uint32(i), Remaining: 2}
+	}
+	// the address falls into the bucket 4 at the bucket depth 10
+	buckets[4].Remaining = 1
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/stamps/"+batchOkStr+"/capacity", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(&api.PostageStampCapacityResponse{
+				BatchID:          batchOk,
+				Depth:            si.Depth(),
+				BucketDepth:      si.BucketDepth(),
+				BucketUpperBound: si.BucketUpperBound(),
+				ImmutableFlag:    true,
+				Remaining:        2047,
+				Buckets:          buckets,
+			}),
+		)
+	})
+
+	t.Run("batch not found", func(t *testing.T) {
+		t.Parallel()
+
+		tsNotFound, _, _, _ := newTestServer(t, testServerOptions{Post: mockpost.New(), DebugAPI: true})
+
+		jsonhttptest.Request(t, tsNotFound, http.MethodGet, "/stamps/"+batchOkStr+"/capacity", http.StatusNotFound)
+	})
+}
+
 func TestReserveState(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,150 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/gorilla/websocket"
+)
+
+type batchEventResponse struct {
+	Type        postage.BatchEventType `json:"type"`
+	BatchID     hexByte                `json:"batchID,omitempty"`
+	Owner       hexByte                `json:"owner,omitempty"`
+	Value       *bigint.BigInt         `json:"value,omitempty"`
+	Depth       uint8                  `json:"depth,omitempty"`
+	BucketDepth uint8                  `json:"bucketDepth,omitempty"`
+	Immutable   bool                   `json:"immutable,omitempty"`
+	Price       *bigint.BigInt         `json:"price,omitempty"`
+	TxHash      string                 `json:"txHash"`
+	ExpiresIn   int64                  `json:"expiresIn,omitempty"`
+}
+
+func newBatchEventResponse(e postage.BatchEvent, blockTime time.Duration) batchEventResponse {
+	r := batchEventResponse{
+		Type:        e.Type,
+		BatchID:     e.BatchID,
+		Owner:       e.Owner,
+		Depth:       e.Depth,
+		BucketDepth: e.BucketDepth,
+		Immutable:   e.Immutable,
+		TxHash:      e.TxHash.String(),
+		ExpiresIn:   int64(e.Blocks) * int64(blockTime/time.Second),
+	}
+	if e.Value != nil {
+		r.Value = bigint.Wrap(e.Value)
+	}
+	if e.Price != nil {
+		r.Price = bigint.Wrap(e.Price)
+	}
+	return r
+}
+
+// postageEventsWsHandler streams the batch events observed on the chain.
+func (s *Service) postageEventsWsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_batches_ws").Build()
+
+	if s.batchEvents == nil {
+		jsonhttp.NotImplemented(w, "batch events not available")
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     s.checkOrigin,
+	}
+
+	// subscribe before the upgrade so that no event is missed
+	// once the client receives the upgrade response
+	events, unsubscribe := s.batchEvents.Subscribe()
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		unsubscribe()
+		logger.Debug("upgrade failed", "error", err)
+		logger.Error(nil, "upgrade failed")
+		jsonhttp.InternalServerError(w, "upgrade failed")
+		return
+	}
+
+	s.wsWg.Add(1)
+	go s.pumpBatchEvents(conn, events, unsubscribe)
+}
+
+func (s *Service) pumpBatchEvents(conn *websocket.Conn, events <-chan postage.BatchEvent, unsubscribe func()) {
+	defer s.wsWg.Done()
+
+	var (
+		gone   = make(chan struct{})
+		ticker = time.NewTicker(s.WsPingPeriod)
+		err    error
+	)
+	defer func() {
+		unsubscribe()
+		ticker.Stop()
+		_ = conn.Close()
+	}()
+
+	// the client is not expected to send any messages,
+	// reading only detects that the client is gone
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				s.logger.Debug("batches ws: client gone", "error", err)
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case e := <-events:
+			err = conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+			if err != nil {
+				s.logger.Debug("batches ws: set write deadline failed", "error", err)
+				return
+			}
+
+			err = conn.WriteJSON(newBatchEventResponse(e, s.blockTime))
+			if err != nil {
+				s.logger.Debug("batches ws: write message failed", "error", err)
+				return
+			}
+
+		case <-s.quit:
+			// shutdown
+			err = conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+			if err != nil {
+				s.logger.Debug("batches ws: set write deadline failed", "error", err)
+				return
+			}
+			err = conn.WriteMessage(websocket.CloseMessage, []byte{})
+			if err != nil {
+				s.logger.Debug("batches ws: write close message failed", "error", err)
+			}
+			return
+		case <-gone:
+			// client gone
+			return
+		case <-ticker.C:
+			err = conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+			if err != nil {
+				s.logger.Debug("batches ws: set write deadline failed", "error", err)
+				return
+			}
+			if err = conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				// error encountered while pinging client. client probably gone
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/postage"
+)
+
+func TestPostageEventsWs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stream", func(t *testing.T) {
+		t.Parallel()
+
+		feed := postage.NewBatchEventFeed()
+		_, conn, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:    true,
+			BatchEvents: feed,
+			WsPath:      "/batches/ws",
+		})
+
+		txHash := common.HexToHash("0x01")
+		events := []postage.BatchEvent{
+			{Type: postage.BatchCreated, BatchID: []byte{1}, Owner: []byte{2}, Value: big.NewInt(10), Depth: 20, BucketDepth: 16, TxHash: txHash},
+			{Type: postage.BatchExpired, BatchID: []byte{1}},
+			{Type: postage.PriceUpdated, Price: big.NewInt(5), TxHash: txHash},
+		}
+		for _, e := range events {
+			feed.Publish(e)
+		}
+
+		want := []api.BatchEventResponse{
+			{Type: postage.BatchCreated, BatchID: []byte{1}, Owner: []byte{2}, Value: bigint.Wrap(big.NewInt(10)), Depth: 20, BucketDepth: 16, TxHash: txHash.String()},
+			{Type: postage.BatchExpired, BatchID: []byte{1}, TxHash: common.Hash{}.String()},
+			{Type: postage.PriceUpdated, Price: bigint.Wrap(big.NewInt(5)), TxHash: txHash.String()},
+		}
+		for i, w := range want {
+			if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+				t.Fatal(err)
+			}
+			_, got, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			exp, err := json.Marshal(w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(bytes.TrimSpace(got), exp) {
+				t.Fatalf("event %d: got %s, want %s", i, got, exp)
+			}
+		}
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+		jsonhttptest.Request(t, ts, http.MethodGet, "/batches/ws", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotImplemented,
+				Message: "batch events not available",
+			}),
+		)
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/prewarm"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+type prewarmResponse struct {
+	Reference  swarm.Address `json:"reference"`
+	Chunks     int           `json:"chunks"`
+	Fetched    int           `json:"fetched"`
+	Failed     int           `json:"failed"`
+	Done       bool          `json:"done"`
+	Error      string        `json:"error,omitempty"`
+	StartedAt  time.Time     `json:"startedAt"`
+	FinishedAt *time.Time    `json:"finishedAt,omitempty"`
+}
+
+func newPrewarmResponse(p prewarm.Progress) prewarmResponse {
+	res := prewarmResponse{
+		Reference: p.Reference,
+		Chunks:    p.Chunks,
+		Fetched:   p.Fetched,
+		Failed:    p.Failed,
+		Done:      p.Done,
+		StartedAt: p.StartedAt,
+	}
+	if p.Err != nil {
+		res.Error = p.Err.Error()
+	}
+	if p.Done {
+		res.FinishedAt = &p.FinishedAt
+	}
+	return res
+}
+
+// prewarmPostHandler starts fetching all chunks of the content into the
+// local store in the background. The progress is reported by the GET method.
+func (s *Service) prewarmPostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_prewarm").Build()
+
+	paths := struct {
+		Address swarm.Address `map:"address,resolve" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	queries := struct {
+		Concurrency *int `map:"concurrency" validate:"omitempty,min=1,max=64"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	concurrency := prewarm.DefaultConcurrency
+	if queries.Concurrency != nil {
+		concurrency = *queries.Concurrency
+	}
+
+	p, err := s.prewarm.Start(paths.Address, concurrency)
+	if err != nil {
+		logger.Debug("start prewarm failed", "reference", paths.Address, "error", err)
+		logger.Error(nil, "start prewarm failed")
+		jsonhttp.InternalServerError(w, "start prewarm failed")
+		return
+	}
+	jsonhttp.Accepted(w, newPrewarmResponse(p))
+}
+
+// prewarmGetHandler reports the progress of the latest prewarming of the content.
+func (s *Service) prewarmGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_prewarm").Build()
+
+	paths := struct {
+		Address swarm.Address `map:"address,resolve" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	p, ok := s.prewarm.Progress(paths.Address)
+	if !ok {
+		jsonhttp.NotFound(w, "prewarm not found")
+		return
+	}
+	jsonhttp.OK(w, newPrewarmResponse(p))
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/prewarm"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/traversal"
+)
+
+func TestPrewarm(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger          = log.Noop
+		storer          = mock.NewStorer()
+		service         = prewarm.New(storer, traversal.New(storer))
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storer,
+			Tags:   tags.NewTags(nil, logger),
+			Logger: logger,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+		debugClient, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:   storer,
+			DebugAPI: true,
+			Prewarm:  service,
+		})
+	)
+	t.Cleanup(func() { _ = service.Close() })
+
+	var upload api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(bytes.Repeat([]byte{1}, 3*swarm.ChunkSize))),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+	ref := upload.Reference.String()
+
+	jsonhttptest.Request(t, debugClient, http.MethodGet, "/prewarm/"+ref, http.StatusNotFound,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "prewarm not found",
+			Code:    http.StatusNotFound,
+		}),
+	)
+	jsonhttptest.Request(t, debugClient, http.MethodPost, "/prewarm/"+ref+"?concurrency=0", http.StatusBadRequest)
+
+	var res api.PrewarmResponse
+	jsonhttptest.Request(t, debugClient, http.MethodPost, "/prewarm/"+ref+"?concurrency=4", http.StatusAccepted,
+		jsonhttptest.WithUnmarshalJSONResponse(&res),
+	)
+	if !res.Reference.Equal(upload.Reference) {
+		t.Fatalf("got reference %s, want %s", res.Reference, ref)
+	}
+
+	for i := 0; !res.Done; i++ {
+		if i == 100 {
+			t.Fatal("prewarming not done")
+		}
+		time.Sleep(10 * time.Millisecond)
+		jsonhttptest.Request(t, debugClient, http.MethodGet, "/prewarm/"+ref, http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+	}
+	// the identical leaves and the root
+	if res.Chunks != 2 || res.Fetched != 2 || res.Failed != 0 || res.Error != "" || res.FinishedAt == nil {
+		t.Fatalf("got response %+v, want 2 fetched chunks", res)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/profiling"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+// profileGetHandler captures the profile of the kind of the path and returns
+// it in the pprof format. The CPU profile is captured for the seconds of the
+// query, the other profiles are the snapshots of the runtime.
+func (s *Service) profileGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("get_profile").Build())
+
+	paths := struct {
+		Kind string `map:"kind" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	queries := struct {
+		Seconds int `map:"seconds" validate:"min=0"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	duration := profiling.DefaultCPUDuration
+	if queries.Seconds > 0 {
+		duration = time.Duration(queries.Seconds) * time.Second
+	}
+
+	buf := new(bytes.Buffer)
+	err := profiling.Capture(r.Context(), buf, profiling.Kind(paths.Kind), duration)
+	switch {
+	case errors.Is(err, profiling.ErrUnknownKind):
+		logger.Debug("capture profile failed", "kind", paths.Kind, "error", err)
+		jsonhttp.BadRequest(w, "unknown profile kind")
+		return
+	case errors.Is(err, profiling.ErrInvalidDuration):
+		logger.Debug("capture profile failed", "kind", paths.Kind, "error", err)
+		jsonhttp.BadRequest(w, "invalid profile duration")
+		return
+	case errors.Is(err, profiling.ErrCaptureInProgress):
+		logger.Debug("capture profile failed", "kind", paths.Kind, "error", err)
+		jsonhttp.Conflict(w, "profile capture in progress")
+		return
+	case err != nil:
+		logger.Debug("capture profile failed", "kind", paths.Kind, "error", err)
+		logger.Error(nil, "capture profile failed")
+		jsonhttp.InternalServerError(w, "capture profile failed")
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", paths.Kind+".pprof"))
+	_, _ = buf.WriteTo(w)
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	mockauth "github.com/ethersphere/bee/pkg/auth/mock"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+	})
+
+	t.Run("heap", func(t *testing.T) {
+		t.Parallel()
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/profiles/heap", http.StatusOK)
+		if got := header.Get("Content-Disposition"); got != `attachment; filename="heap.pprof"` {
+			t.Fatalf("got content disposition %q", got)
+		}
+	})
+
+	t.Run("cpu", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/profiles/cpu?seconds=1", http.StatusOK)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/profiles/threadcreate", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "unknown profile kind",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/profiles/cpu?seconds=3600", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid profile duration",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
+func TestProfilingRestricted(t *testing.T) {
+	t.Parallel()
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:   true,
+		Restricted: true,
+		Authenticator: &mockauth.Auth{
+			EnforceFunc: func(key, _, _ string) (bool, error) {
+				return key == "maintainer", nil
+			},
+		},
+	})
+
+	for _, path := range []string{"/debug/pprof/cmdline", "/debug/pprof/heap", "/debug/vars"} {
+		path := path
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusForbidden)
+			jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusForbidden,
+				jsonhttptest.WithRequestHeader("Authorization", "Bearer consumer"),
+			)
+			jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusOK,
+				jsonhttptest.WithRequestHeader("Authorization", "Bearer maintainer"),
+			)
+		})
+	}
+
+	t.Run("unrestricted", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+		jsonhttptest.Request(t, client, http.MethodGet, "/debug/pprof/cmdline", http.StatusOK)
+	})
+}
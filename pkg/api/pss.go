@@ -55,6 +55,7 @@ func (s *Service) pssPostHandler(w http.ResponseWriter, r *http.Request) {
 
 	queries := struct {
 		Recipient *ecdsa.PublicKey `map:"recipient,omitempty"`
+		Expiry    int64            `map:"expiry" validate:"min=0"`
 	}{}
 	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
 		response("invalid query params", logger, w)
@@ -78,6 +79,12 @@ func (s *Service) pssPostHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.BadRequest(w, "invalid postage batch id")
 		return
 	}
+	if !requestTenant(r.Context()).allowsBatch(batch) {
+		logger.Debug("batch not allowed", "batch_id", hex.EncodeToString(batch))
+		logger.Error(nil, "batch not allowed")
+		jsonhttp.Forbidden(w, "batch not allowed")
+		return
+	}
 	i, save, err := s.post.GetStampIssuer(batch)
 	if err != nil {
 		logger.Debug("get postage batch issuer failed", "batch_id", hex.EncodeToString(batch), "error", err)
@@ -100,13 +107,22 @@ func (s *Service) pssPostHandler(w http.ResponseWriter, r *http.Request) {
 
 	stamper := postage.NewStamper(i, s.signer)
 
-	err = s.pss.Send(r.Context(), topic, payload, stamper, queries.Recipient, targets)
+	ctx := r.Context()
+	if queries.Expiry > 0 {
+		// the message is queued for at most the expiry if there are no connected peers
+		ctx = pss.WithExpiry(ctx, time.Duration(queries.Expiry)*time.Second)
+	}
+	err = s.pss.Send(ctx, topic, payload, stamper, queries.Recipient, targets)
+	if errors.Is(err, pss.ErrQueued) {
+		jsonhttp.Accepted(w, nil)
+		return
+	}
 	if err != nil {
 		logger.Debug("send payload failed", "topic", paths.Topic, "error", err)
 		logger.Error(nil, "send payload failed")
 		switch {
 		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
 		default:
 			jsonhttp.InternalServerError(w, "pss send failed")
 		}
@@ -30,6 +30,7 @@ import (
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/pushsync"
 	"github.com/ethersphere/bee/pkg/spinlock"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/util/testutil"
@@ -275,6 +276,32 @@ func TestPssSend(t *testing.T) {
 	})
 }
 
+func TestPssSendQueued(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mp = mockpost.New(mockpost.WithIssuer(postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)))
+		p  = newMockPss(func(ctx context.Context, _ pss.Targets, _ swarm.Chunk) error {
+			return pss.ErrQueued
+		})
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Pss:    p,
+			Storer: mock.NewStorer(),
+			Logger: log.Noop,
+			Post:   mp,
+		})
+	)
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/pss/send/testtopic/12?expiry=3600", http.StatusAccepted,
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "Accepted",
+			Code:    http.StatusAccepted,
+		}),
+	)
+}
+
 // TestPssPingPong tests that the websocket api adheres to the websocket standard
 // and sends ping-pong messages to keep the connection alive.
 // The test opens a websocket, keeps it alive for 500ms, then receives a pss message.
@@ -476,6 +503,10 @@ func (m *mpss) SetPushSyncer(pushSyncer pushsync.PushSyncer) {
 	panic("not implemented") // TODO: Implement
 }
 
+func (m *mpss) SetOutbox(_ storage.StateStorer, _ pss.TopologySubscriber) {
+	panic("not implemented") // TODO: Implement
+}
+
 func (m *mpss) Close() error {
 	panic("not implemented") // TODO: Implement
 }
@@ -0,0 +1,393 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pss/pb"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// pssStreamMaxSends is the maximum number of the messages
+	// of a stream which are stamped and sent concurrently.
+	pssStreamMaxSends = 16
+	// pssStreamBuffer is the number of the responses buffered
+	// before the stream is written to.
+	pssStreamBuffer = 64
+	// pssStreamMinPingInterval is the minimal interval of the
+	// keepalive pings of the clients.
+	pssStreamMinPingInterval = 10 * time.Second
+)
+
+// PssGRPCServer returns the gRPC server of the bidirectional pss stream, on
+// which the messages are sent and the topics are subscribed to with their
+// delivery statuses streamed back. The connections are kept alive by the
+// pings of the HTTP/2 transport instead of the websocket pings.
+func (s *Service) PssGRPCServer() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    s.WsPingPeriod,
+			Timeout: writeDeadline,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             pssStreamMinPingInterval,
+			PermitWithoutStream: true,
+		}),
+	)
+	pb.RegisterPssServer(srv, &pssGRPCServer{s: s})
+	return srv
+}
+
+type pssGRPCServer struct {
+	s *Service
+}
+
+// pssStream is a single pss stream of a client.
+type pssStream struct {
+	s      *Service
+	logger log.Logger
+	apiKey string  // security token of the stream in the restricted mode
+	tenant *tenant // tenant the stream is scoped to, nil if none
+
+	out           chan *pb.Response
+	sem           chan struct{}
+	wg            sync.WaitGroup // waits for the messages being sent
+	mu            sync.Mutex
+	subscriptions map[string]func()
+}
+
+// Stream serves the requests of the stream until the client closes it.
+func (g *pssGRPCServer) Stream(stream pb.Pss_StreamServer) error {
+	st := &pssStream{
+		s:             g.s,
+		logger:        g.s.logger.WithName("pss_stream").Build(),
+		out:           make(chan *pb.Response, pssStreamBuffer),
+		sem:           make(chan struct{}, pssStreamMaxSends),
+		subscriptions: make(map[string]func()),
+	}
+	if err := st.authenticate(stream.Context()); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	stop := make(chan struct{})
+	writeErrC := make(chan error, 1)
+	go func() {
+		writeErrC <- st.write(ctx, stream, stop)
+		cancel()
+	}()
+	defer st.unsubscribeAll()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			cancel()
+			st.wg.Wait()
+			return err
+		}
+		st.handle(ctx, req)
+	}
+
+	// the statuses of the messages being sent are written
+	// before the stream is closed
+	st.wg.Wait()
+	st.unsubscribeAll()
+	close(stop)
+	return <-writeErrC
+}
+
+// authenticate checks the security token of the stream in the restricted
+// mode and scopes the stream to the tenant the token is issued for.
+func (st *pssStream) authenticate(ctx context.Context) error {
+	if !st.s.Restricted {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if key, ok := strings.CutPrefix(v, "Bearer "); ok && strings.TrimSpace(key) != "" {
+			st.apiKey = key
+		}
+	}
+	if st.apiKey == "" {
+		return status.Error(codes.Unauthenticated, "missing security token")
+	}
+	if len(st.s.tenants) == 0 {
+		return nil
+	}
+
+	name, err := st.s.auth.Tenant(st.apiKey)
+	if errors.Is(err, auth.ErrTokenExpired) {
+		return status.Error(codes.Unauthenticated, "token expired")
+	}
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid security token")
+	}
+	if name == "" {
+		return nil
+	}
+	t, ok := st.s.tenants[name]
+	if !ok {
+		return status.Error(codes.PermissionDenied, "unknown tenant")
+	}
+	st.tenant = t
+	return nil
+}
+
+// allowed checks the permission of the stream for the pss api path
+// of the equivalent http request in the restricted mode.
+func (st *pssStream) allowed(path, method string) error {
+	if !st.s.Restricted {
+		return nil
+	}
+	allowed, err := st.s.auth.Enforce(st.apiKey, path, method)
+	if errors.Is(err, auth.ErrTokenExpired) {
+		return errors.New("token expired")
+	}
+	if err != nil {
+		st.logger.Debug("validate security token failed", "error", err)
+		return errors.New("validate security token failed")
+	}
+	if !allowed {
+		return errors.New("security token does not grant access")
+	}
+	return nil
+}
+
+// write writes the responses to the stream until the
+// stream is stopped and all the responses are written.
+func (st *pssStream) write(ctx context.Context, stream pb.Pss_StreamServer, stop <-chan struct{}) error {
+	for {
+		select {
+		case res := <-st.out:
+			if err := stream.Send(res); err != nil {
+				st.logger.Debug("pss stream: write failed", "error", err)
+				return err
+			}
+		case <-stop:
+			for {
+				select {
+				case res := <-st.out:
+					if err := stream.Send(res); err != nil {
+						return err
+					}
+				default:
+					return nil
+				}
+			}
+		case <-st.s.quit:
+			return status.Error(codes.Unavailable, "shutting down")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (st *pssStream) respond(ctx context.Context, res *pb.Response) {
+	select {
+	case st.out <- res:
+	case <-ctx.Done():
+	}
+}
+
+func (st *pssStream) status(ctx context.Context, id uint64, state pb.State, err error) {
+	s := &pb.Status{ID: id, State: state}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	st.respond(ctx, &pb.Response{Response: &pb.Response_Status{Status: s}})
+}
+
+func (st *pssStream) handle(ctx context.Context, req *pb.Request) {
+	switch r := req.Request.(type) {
+	case *pb.Request_Send:
+		st.send(ctx, req.ID, r.Send)
+	case *pb.Request_Subscribe:
+		if err := st.subscribe(ctx, r.Subscribe.Topic); err != nil {
+			st.status(ctx, req.ID, pb.State_Failed, err)
+			return
+		}
+		st.status(ctx, req.ID, pb.State_Accepted, nil)
+	case *pb.Request_Unsubscribe:
+		st.unsubscribe(r.Unsubscribe.Topic)
+		st.status(ctx, req.ID, pb.State_Accepted, nil)
+	default:
+		st.status(ctx, req.ID, pb.State_Failed, errors.New("unknown request"))
+	}
+}
+
+func (st *pssStream) subscribe(ctx context.Context, topic string) error {
+	if topic == "" {
+		return errors.New("missing topic")
+	}
+	if err := st.allowed("/pss/subscribe/"+topic, "GET"); err != nil {
+		return err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, ok := st.subscriptions[topic]; ok {
+		return nil
+	}
+	st.subscriptions[topic] = st.s.pss.Register(pss.NewTopic(topic), func(hctx context.Context, m []byte) {
+		res := &pb.Response{Response: &pb.Response_Message{Message: &pb.Message{Topic: topic, Payload: m}}}
+		select {
+		case st.out <- res:
+		case <-hctx.Done():
+		case <-ctx.Done():
+		}
+	})
+	return nil
+}
+
+func (st *pssStream) unsubscribe(topic string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if cleanup, ok := st.subscriptions[topic]; ok {
+		cleanup()
+		delete(st.subscriptions, topic)
+	}
+}
+
+func (st *pssStream) unsubscribeAll() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for topic, cleanup := range st.subscriptions {
+		cleanup()
+		delete(st.subscriptions, topic)
+	}
+}
+
+// send validates the message and reports it accepted, the message is then
+// stamped and sent in the background and its delivery status is reported.
+func (st *pssStream) send(ctx context.Context, id uint64, m *pb.Send) {
+	recipient, err := st.validateSend(m)
+	if err != nil {
+		st.status(ctx, id, pb.State_Failed, err)
+		return
+	}
+	st.status(ctx, id, pb.State_Accepted, nil)
+
+	select {
+	case st.sem <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	st.wg.Add(1)
+	go func() {
+		defer st.wg.Done()
+		defer func() { <-st.sem }()
+
+		err := st.sendMessage(ctx, m, recipient)
+		switch {
+		case errors.Is(err, pss.ErrQueued):
+			st.status(ctx, id, pb.State_Queued, nil)
+		case err != nil:
+			st.status(ctx, id, pb.State_Failed, err)
+		default:
+			st.status(ctx, id, pb.State_Sent, nil)
+		}
+	}()
+}
+
+// validateSend validates the message and returns its recipient.
+func (st *pssStream) validateSend(m *pb.Send) (*ecdsa.PublicKey, error) {
+	if m.Topic == "" {
+		return nil, errors.New("missing topic")
+	}
+	if len(m.Targets) == 0 {
+		return nil, errors.New("missing targets")
+	}
+	targets := make([]string, 0, len(m.Targets))
+	for _, t := range m.Targets {
+		if len(t) == 0 || len(t) > targetMaxLength {
+			return nil, errors.New("invalid target")
+		}
+		targets = append(targets, hex.EncodeToString(t))
+	}
+	if len(m.BatchID) != swarm.HashSize {
+		return nil, errors.New("invalid postage batch id")
+	}
+	if !st.tenant.allowsBatch(m.BatchID) {
+		return nil, errors.New("batch not allowed")
+	}
+	if err := st.allowed("/pss/send/"+m.Topic+"/"+strings.Join(targets, ","), "POST"); err != nil {
+		return nil, err
+	}
+
+	if len(m.Recipient) == 0 {
+		topic := pss.NewTopic(m.Topic)
+		return &(crypto.Secp256k1PrivateKeyFromBytes(topic[:])).PublicKey, nil
+	}
+	recipient, err := pss.ParseRecipient(hex.EncodeToString(m.Recipient))
+	if err != nil {
+		return nil, errors.New("invalid recipient")
+	}
+	return recipient, nil
+}
+
+func (st *pssStream) sendMessage(ctx context.Context, m *pb.Send, recipient *ecdsa.PublicKey) error {
+	i, save, err := st.s.post.GetStampIssuer(m.BatchID)
+	if err != nil {
+		st.logger.Debug("get postage batch issuer failed", "batch_id", hex.EncodeToString(m.BatchID), "error", err)
+		switch {
+		case errors.Is(err, postage.ErrNotFound):
+			return errors.New("batch not found")
+		case errors.Is(err, postage.ErrNotUsable):
+			return errors.New("batch not usable yet")
+		default:
+			return errors.New("postage stamp issuer")
+		}
+	}
+	defer func() {
+		if err := save(); err != nil {
+			st.logger.Debug("stamp issuer save", "error", err)
+		}
+	}()
+
+	if m.Expiry > 0 {
+		// the message is queued for at most the expiry if there are no connected peers
+		ctx = pss.WithExpiry(ctx, time.Duration(m.Expiry)*time.Second)
+	}
+	targets := make(pss.Targets, 0, len(m.Targets))
+	for _, t := range m.Targets {
+		targets = append(targets, pss.Target(t))
+	}
+	err = st.s.pss.Send(ctx, pss.NewTopic(m.Topic), m.Payload, postage.NewStamper(i, st.s.signer), recipient, targets)
+	if err != nil && !errors.Is(err, pss.ErrQueued) {
+		st.logger.Debug("send payload failed", "topic", m.Topic, "error", err)
+		if errors.Is(err, postage.ErrBucketFull) {
+			return err
+		}
+		return errors.New("pss send failed")
+	}
+	return err
+}
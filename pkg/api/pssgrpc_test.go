@@ -0,0 +1,204 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pss/pb"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func newPssStream(t *testing.T, addr string) pb.Pss_StreamClient {
+	t.Helper()
+
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), longTimeout)
+	t.Cleanup(cancel)
+
+	stream, err := pb.NewPssClient(conn).Stream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stream
+}
+
+func expectStatus(t *testing.T, stream pb.Pss_StreamClient, id uint64, state pb.State) *pb.Status {
+	t.Helper()
+
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := res.GetStatus()
+	if s == nil {
+		t.Fatalf("got %v, want status", res)
+	}
+	if s.ID != id || s.State != state {
+		t.Fatalf("got status %d %v (%s), want %d %v", s.ID, s.State, s.Error, id, state)
+	}
+	return s
+}
+
+func TestPssGRPCSend(t *testing.T) {
+	t.Parallel()
+
+	sendErr := errors.New("no peers")
+	mp := mockpost.New(mockpost.WithIssuer(postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)))
+
+	for _, tc := range []struct {
+		name   string
+		sendFn pssSendFn
+		want   pb.State
+	}{{
+		name:   "sent",
+		sendFn: func(context.Context, pss.Targets, swarm.Chunk) error { return nil },
+		want:   pb.State_Sent,
+	}, {
+		name:   "queued",
+		sendFn: func(context.Context, pss.Targets, swarm.Chunk) error { return pss.ErrQueued },
+		want:   pb.State_Queued,
+	}, {
+		name:   "failed",
+		sendFn: func(context.Context, pss.Targets, swarm.Chunk) error { return sendErr },
+		want:   pb.State_Failed,
+	}} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, addr, _ := newTestServer(t, testServerOptions{
+				Pss:     newMockPss(tc.sendFn),
+				Storer:  mock.NewStorer(),
+				Logger:  log.Noop,
+				Post:    mp,
+				PssGRPC: true,
+			})
+			stream := newPssStream(t, addr)
+
+			err := stream.Send(&pb.Request{ID: 1, Request: &pb.Request_Send{Send: &pb.Send{
+				Topic:   "testtopic",
+				Targets: [][]byte{{0x12}},
+				BatchID: batchOk,
+				Payload: payload,
+			}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectStatus(t, stream, 1, pb.State_Accepted)
+			expectStatus(t, stream, 1, tc.want)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, addr, _ := newTestServer(t, testServerOptions{
+			Pss:     newMockPss(nil),
+			Storer:  mock.NewStorer(),
+			Logger:  log.Noop,
+			Post:    mp,
+			PssGRPC: true,
+		})
+		stream := newPssStream(t, addr)
+
+		for i, m := range []*pb.Send{
+			{Targets: [][]byte{{0x12}}, BatchID: batchOk},
+			{Topic: "testtopic", BatchID: batchOk},
+			{Topic: "testtopic", Targets: [][]byte{{0x12}}, BatchID: batchInvalid},
+		} {
+			id := uint64(i + 1)
+			if err := stream.Send(&pb.Request{ID: id, Request: &pb.Request_Send{Send: m}}); err != nil {
+				t.Fatal(err)
+			}
+			expectStatus(t, stream, id, pb.State_Failed)
+		}
+	})
+}
+
+func TestPssGRPCSubscribe(t *testing.T) {
+	t.Parallel()
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pss.New(privkey, log.Noop)
+	testutil.CleanupCloser(t, p)
+
+	_, _, addr, _ := newTestServer(t, testServerOptions{
+		Pss:     p,
+		Storer:  mock.NewStorer(),
+		Logger:  log.Noop,
+		PssGRPC: true,
+	})
+	stream := newPssStream(t, addr)
+
+	err = stream.Send(&pb.Request{ID: 1, Request: &pb.Request_Subscribe{Subscribe: &pb.Subscribe{Topic: "testtopic"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStatus(t, stream, 1, pb.State_Accepted)
+
+	tc, err := pss.Wrap(context.Background(), topic, payload, &privkey.PublicKey, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.TryUnwrap(tc)
+
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := res.GetMessage()
+	if m == nil {
+		t.Fatalf("got %v, want message", res)
+	}
+	if m.Topic != "testtopic" || !bytes.Equal(m.Payload, payload) {
+		t.Fatalf("got message %q %q, want %q %q", m.Topic, m.Payload, "testtopic", payload)
+	}
+
+	err = stream.Send(&pb.Request{ID: 2, Request: &pb.Request_Unsubscribe{Unsubscribe: &pb.Unsubscribe{Topic: "testtopic"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStatus(t, stream, 2, pb.State_Accepted)
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected the stream to be closed")
+		}
+	case <-time.After(mTimeout):
+		t.Fatal("stream not closed")
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+// defaultPublishSyncTimeout is the default time the
+// chunks of the published collection are waited to sync.
+const defaultPublishSyncTimeout = 30 * time.Minute
+
+type publishResponse struct {
+	Reference swarm.Address `json:"reference"`
+	Feed      swarm.Address `json:"feed"`
+	Owner     string        `json:"owner"`
+	Topic     string        `json:"topic"`
+}
+
+// publishHandler uploads the collection, waits until all of its chunks are
+// synced and only then updates the feed of the node named by the name to
+// the new manifest root. The visitors resolving the feed manifest never get
+// the collection which is not fully synced.
+func (s *Service) publishHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_publish").Build())
+
+	paths := struct {
+		Name string `map:"name" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	headers := struct {
+		ContentType string `map:"Content-Type,mimeMediaType" validate:"required"`
+		IfFeedIndex []byte `map:"If-Feed-Index" validate:"omitempty,len=8"`
+	}{}
+	if response := s.mapStructure(r.Header, &headers); response != nil {
+		response("invalid header params", logger, w)
+		return
+	}
+
+	queries := struct {
+		Timeout *int `map:"timeout" validate:"omitempty,min=1"` // in seconds
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	timeout := defaultPublishSyncTimeout
+	if queries.Timeout != nil {
+		timeout = time.Duration(*queries.Timeout) * time.Second
+	}
+
+	if s.signer == nil {
+		jsonhttp.InternalServerError(w, "no signer")
+		return
+	}
+	owner, err := s.signer.EthereumAddress()
+	if err != nil {
+		logger.Debug("signer address failed", "error", err)
+		logger.Error(nil, "signer address failed")
+		jsonhttp.InternalServerError(w, "signer address failed")
+		return
+	}
+	topic, err := crypto.LegacyKeccak256([]byte(paths.Name))
+	if err != nil {
+		logger.Debug("topic failed", "name", paths.Name, "error", err)
+		logger.Error(nil, "topic failed")
+		jsonhttp.InternalServerError(w, "topic failed")
+		return
+	}
+	feed := feeds.New(topic, owner)
+
+	// fail early, the precondition is checked again when the feed is updated
+	if err := s.checkFeedIndex(r.Context(), feed, headers.IfFeedIndex); err != nil {
+		logger.Debug("feed index precondition failed", "name", paths.Name, "error", err)
+		if errors.Is(err, errFeedIndexTaken) {
+			jsonhttp.PreconditionFailed(w, errFeedIndexTaken)
+			return
+		}
+		logger.Error(nil, "feed index precondition failed")
+		jsonhttp.InternalServerError(w, "check feed index failed")
+		return
+	}
+
+	var dReader dirReader
+	mediaType, params, _ := mime.ParseMediaType(headers.ContentType)
+	switch mediaType {
+	case contentTypeTar:
+		dReader = &tarReader{r: tar.NewReader(r.Body), logger: s.logger}
+	case multiPartFormData:
+		dReader = &multipartReader{r: multipart.NewReader(r.Body, params["boundary"])}
+	default:
+		jsonhttp.BadRequest(w, errInvalidContentType)
+		return
+	}
+	defer r.Body.Close()
+
+	deferred, err := requestDeferred(r)
+	if err != nil {
+		jsonhttp.BadRequest(w, "invalid deferred upload header")
+		return
+	}
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debug("putter failed", "error", err)
+		logger.Error(nil, "putter failed")
+		switch {
+		case errors.Is(err, errBatchNotAllowed):
+			jsonhttp.Forbidden(w, "batch not allowed")
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, errInvalidPostageBatch):
+			jsonhttp.BadRequest(w, "invalid batch id")
+		case errors.Is(err, errUnsupportedDevNodeOperation):
+			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		case errors.Is(err, clockskew.ErrClockSkewed):
+			jsonhttp.ServiceUnavailable(w, err.Error())
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return
+	}
+
+	// the tag is always created, as the upload is complete
+	// once the collection is stored and its total is known
+	tag, err := s.createTag(r.Context())
+	if err != nil {
+		logger.Debug("create tag failed", "error", err)
+		logger.Error(nil, "create tag failed")
+		jsonhttp.InternalServerError(w, "cannot create tag")
+		return
+	}
+	w.Header().Set("Access-Control-Expose-Headers", fmt.Sprintf("%s, %s", SwarmTagHeader, SwarmFeedIndexHeader))
+	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
+
+	maxFileSize := s.MaxDirUploadFileSize
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultMaxDirUploadFileSize
+	}
+
+	ctx := sctx.SetTag(r.Context(), tag)
+	ls := loadsave.New(putter, requestPipelineFactory(ctx, putter, r))
+	reference, err := storeDir(
+		ctx,
+		requestEncrypt(r),
+		dReader,
+		s.logger,
+		requestPipelineFn(putter, r),
+		ls,
+		r.Header.Get(SwarmIndexDocumentHeader),
+		r.Header.Get(SwarmErrorDocumentHeader),
+		tag,
+		true,
+		maxFileSize,
+	)
+	if err != nil {
+		logger.Debug("store dir failed", "error", err)
+		logger.Error(nil, "store dir failed")
+		failUploadTag(logger, tag, tags.PhaseSplit, err)
+		switch {
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
+		case errors.Is(err, errEmptyDir):
+			jsonhttp.BadRequest(w, errEmptyDir)
+		case errors.Is(err, errFileTooLarge):
+			jsonhttp.RequestEntityTooLarge(w, errFileTooLarge)
+		case errors.Is(err, tar.ErrHeader):
+			jsonhttp.BadRequest(w, "invalid filename in tar archive")
+		default:
+			jsonhttp.InternalServerError(w, errDirectoryStore)
+		}
+		return
+	}
+	s.watchReceipts(logger, reference)
+
+	feedManifest, err := storeFeedManifest(ctx, ls, feed)
+	if err != nil {
+		logger.Debug("store feed manifest failed", "error", err)
+		logger.Error(nil, "store feed manifest failed")
+		failUploadTag(logger, tag, tags.PhaseSplit, err)
+		switch {
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
+		default:
+			jsonhttp.InternalServerError(w, "store feed manifest failed")
+		}
+		return
+	}
+	// the total of the tag includes the chunks of the feed manifest
+	if _, err := tag.DoneSplit(reference); err != nil {
+		logger.Debug("done split failed", "error", err)
+		logger.Error(nil, "done split failed")
+		jsonhttp.InternalServerError(w, "done split failed")
+		return
+	}
+
+	// the direct uploads are synced once the putter is done,
+	// the deferred ones once the pusher synced all chunks of the tag
+	if err := wait(); err != nil {
+		logger.Debug("sync chunks failed", "error", err)
+		logger.Error(nil, "sync chunks failed")
+		failUploadTag(logger, tag, tags.PhasePush, err)
+		jsonhttp.InternalServerError(w, "sync chunks failed")
+		return
+	}
+	if deferred {
+		syncCtx, cancel := context.WithTimeout(r.Context(), timeout)
+		err := tag.WaitTillDone(syncCtx, tags.StateSynced)
+		cancel()
+		if err != nil {
+			logger.Debug("wait for sync failed", "tag", tag.Uid, "error", err)
+			logger.Error(nil, "wait for sync failed")
+			if errors.Is(err, context.DeadlineExceeded) {
+				jsonhttp.GatewayTimeout(w, "sync timed out")
+				return
+			}
+			jsonhttp.InternalServerError(w, "sync chunks failed")
+			return
+		}
+	}
+
+	index, err := s.publishFeedUpdate(r.Context(), putter, feed, reference, headers.IfFeedIndex)
+	if err != nil {
+		logger.Debug("feed update failed", "name", paths.Name, "error", err)
+		logger.Error(nil, "feed update failed")
+		switch {
+		case errors.Is(err, errFeedIndexTaken):
+			jsonhttp.PreconditionFailed(w, errFeedIndexTaken)
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
+		default:
+			jsonhttp.InternalServerError(w, "feed update failed")
+		}
+		return
+	}
+	if err := wait(); err != nil {
+		logger.Debug("sync feed update failed", "error", err)
+		logger.Error(nil, "sync feed update failed")
+		jsonhttp.InternalServerError(w, "sync feed update failed")
+		return
+	}
+
+	w.Header().Set(SwarmFeedIndexHeader, hex.EncodeToString(index))
+	jsonhttp.Created(w, publishResponse{
+		Reference: reference,
+		Feed:      feedManifest,
+		Owner:     hex.EncodeToString(owner.Bytes()),
+		Topic:     hex.EncodeToString(topic),
+	})
+}
+
+// publishFeedUpdate updates the sequence feed of the node to the reference
+// and returns the index of the update. The updates are serialized, as every
+// update depends on the previous one. The update fails with errFeedIndexTaken
+// if the feed already has an update at the ifIndex, if it is not nil.
+func (s *Service) publishFeedUpdate(ctx context.Context, putter storage.Putter, feed *feeds.Feed, reference swarm.Address, ifIndex []byte) ([]byte, error) {
+	s.publishMu.Lock()
+	defer s.publishMu.Unlock()
+
+	if err := s.checkFeedIndex(ctx, feed, ifIndex); err != nil {
+		return nil, err
+	}
+
+	l, err := s.feedFactory.NewLookup(feeds.Sequence, feed)
+	if err != nil {
+		return nil, fmt.Errorf("feed lookup: %w", err)
+	}
+	_, _, next, err := l.At(ctx, time.Now().Unix(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("next feed index: %w", err)
+	}
+	updater, err := feeds.NewPutter(putter, s.signer, feed.Topic)
+	if err != nil {
+		return nil, fmt.Errorf("feed putter: %w", err)
+	}
+	if err := updater.Put(ctx, next, time.Now().Unix(), reference.Bytes()); err != nil {
+		return nil, fmt.Errorf("put feed update: %w", err)
+	}
+	return next.MarshalBinary()
+}
+
+// storeFeedManifest stores the manifest resolving to the latest update of the feed.
+func storeFeedManifest(ctx context.Context, ls file.LoadSaver, feed *feeds.Feed) (swarm.Address, error) {
+	m, err := manifest.NewDefaultManifest(ls, false)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("create manifest: %w", err)
+	}
+	meta := map[string]string{
+		feedMetadataEntryOwner: hex.EncodeToString(feed.Owner.Bytes()),
+		feedMetadataEntryTopic: hex.EncodeToString(feed.Topic),
+		feedMetadataEntryType:  feeds.Sequence.String(),
+	}
+	// a feed manifest stores the metadata at the root "/" path
+	if err := m.Add(ctx, "/", manifest.NewEntry(swarm.NewAddress(make([]byte, 32)), meta)); err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("add manifest entry: %w", err)
+	}
+	return m.Store(ctx)
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// nolint:paralleltest
+func TestPublish(t *testing.T) {
+	var (
+		storer          = mock.NewStorer()
+		tagsService     = tags.NewTags(nil, log.Noop)
+		pk, _           = crypto.GenerateSecp256k1Key()
+		signer          = crypto.NewDefaultSigner(pk)
+		owner, _        = signer.EthereumAddress()
+		topic, _        = crypto.LegacyKeccak256([]byte("site"))
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storer,
+			Tags:   tagsService,
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+			Feeds:  factory.New(storer),
+			Signer: signer,
+		})
+		syncing atomic.Bool
+	)
+
+	// the pusher syncing all chunks of the tags is simulated
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+			if !syncing.Load() {
+				continue
+			}
+			for _, tag := range tagsService.All() {
+				synced, total, err := tag.Status(tags.StateSynced)
+				if err == nil && synced < total {
+					_ = tag.IncN(tags.StateSynced, total-synced)
+				}
+			}
+		}
+	}()
+
+	publish := func(t *testing.T, content string, query string, status int, opts ...jsonhttptest.Option) api.PublishResponse {
+		t.Helper()
+
+		var res api.PublishResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/publish/site"+query, status, append(opts,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+			jsonhttptest.WithRequestHeader(api.ContentTypeHeader, api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, []f{{
+				data: []byte(content),
+				name: "index.html",
+				header: http.Header{
+					api.ContentTypeHeader: {"text/html; charset=utf-8"},
+				},
+			}})),
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)...)
+		return res
+	}
+	feedURL := "/feeds/" + hex.EncodeToString(owner.Bytes()) + "/" + hex.EncodeToString(topic)
+
+	t.Run("not synced", func(t *testing.T) {
+		publish(t, "<h1>v0</h1>", "?timeout=1", http.StatusGatewayTimeout)
+		// the feed is not updated to the collection which is not synced
+		jsonhttptest.Request(t, client, http.MethodGet, feedURL, http.StatusNotFound)
+	})
+
+	syncing.Store(true)
+
+	var feed string
+	for i, content := range []string{"<h1>v1</h1>", "<h1>v2</h1>"} {
+		res := publish(t, content, "", http.StatusCreated)
+		if res.Owner != hex.EncodeToString(owner.Bytes()) || res.Topic != hex.EncodeToString(topic) {
+			t.Fatalf("got feed %s/%s, want %x/%x", res.Owner, res.Topic, owner, topic)
+		}
+		if feed != "" && res.Feed.String() != feed {
+			t.Fatalf("got feed manifest %s, want %s", res.Feed, feed)
+		}
+		feed = res.Feed.String()
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, feedURL, http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.FeedReferenceResponse{Reference: res.Reference}),
+		)
+		if got, want := header.Get(api.SwarmFeedIndexHeader), hex.EncodeToString([]byte{0, 0, 0, 0, 0, 0, 0, byte(i)}); got != want {
+			t.Fatalf("got feed index %s, want %s", got, want)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+feed+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte(content)),
+		)
+	}
+
+	t.Run("feed index taken", func(t *testing.T) {
+		publish(t, "<h1>v3</h1>", "", http.StatusPreconditionFailed,
+			jsonhttptest.WithRequestHeader(api.IfFeedIndexHeader, "0000000000000001"),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+feed+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("<h1>v2</h1>")),
+		)
+		publish(t, "<h1>v3</h1>", "", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.IfFeedIndexHeader, "0000000000000002"),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+feed+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("<h1>v3</h1>")),
+		)
+	})
+}
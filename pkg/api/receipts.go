@@ -0,0 +1,87 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/receipts"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+type receiptResponse struct {
+	Storer    swarm.Address `json:"storer"`
+	Signature string        `json:"signature"`
+	Nonce     string        `json:"nonce"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+type receiptsResponse struct {
+	Reference swarm.Address     `json:"reference"`
+	Receipts  []receiptResponse `json:"receipts"`
+}
+
+// receiptsGetHandler returns the signed receipts of the storer nodes
+// which accepted the root chunk of the content uploaded to this node.
+func (s *Service) receiptsGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_receipts").Build()
+
+	paths := struct {
+		Reference swarm.Address `map:"reference" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	if s.receipts == nil {
+		jsonhttp.NotFound(w, "receipts not found")
+		return
+	}
+
+	rs, err := s.receipts.Get(paths.Reference)
+	if err != nil {
+		logger.Debug("get receipts failed", "reference", paths.Reference, "error", err)
+		logger.Error(nil, "get receipts failed")
+		if errors.Is(err, receipts.ErrNotFound) {
+			jsonhttp.NotFound(w, "receipts not found")
+			return
+		}
+		jsonhttp.InternalServerError(w, "get receipts failed")
+		return
+	}
+
+	resp := receiptsResponse{
+		Reference: paths.Reference,
+		Receipts:  make([]receiptResponse, 0, len(rs)),
+	}
+	for _, rc := range rs {
+		resp.Receipts = append(resp.Receipts, receiptResponse{
+			Storer:    rc.Storer,
+			Signature: hex.EncodeToString(rc.Signature),
+			Nonce:     hex.EncodeToString(rc.Nonce),
+			Timestamp: rc.Timestamp,
+		})
+	}
+	jsonhttp.OK(w, resp)
+}
+
+// watchReceipts starts keeping the receipts of the uploaded content.
+// Failing to do so does not fail the upload, as the receipts are optional.
+func (s *Service) watchReceipts(logger log.Logger, reference swarm.Address) {
+	if s.receipts == nil {
+		return
+	}
+	if err := s.receipts.Watch(reference); err != nil {
+		logger.Debug("watch receipts failed", "reference", reference, "error", err)
+		logger.Error(nil, "watch receipts failed")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/receipts"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestReceipts(t *testing.T) {
+	t.Parallel()
+
+	receiptStore, err := receipts.New(statestore.NewStateStore(), receipts.DefaultRecentCapacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		logger          = log.Noop
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:   mock.NewStorer(),
+			Tags:     tags.NewTags(nil, logger),
+			Logger:   logger,
+			Post:     mockpost.New(mockpost.WithAcceptAll()),
+			Receipts: receiptStore,
+		})
+	)
+
+	var upload api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader([]byte("content"))),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+
+	// the content is watched, but its root chunk is not pushed yet
+	jsonhttptest.Request(t, client, http.MethodGet, "/receipts/"+upload.Reference.String(), http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.ReceiptsResponse{
+			Reference: upload.Reference,
+			Receipts:  []api.ReceiptResponse{},
+		}),
+	)
+
+	receipt := receipts.Receipt{
+		Address:   upload.Reference,
+		Storer:    swarm.RandAddress(t),
+		Signature: []byte{1, 2, 3},
+		Nonce:     []byte{4, 5, 6},
+		Timestamp: time.Unix(1672531200, 0).UTC(),
+	}
+	if err := receiptStore.Put(receipt); err != nil {
+		t.Fatal(err)
+	}
+	jsonhttptest.Request(t, client, http.MethodGet, "/receipts/"+upload.Reference.String(), http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.ReceiptsResponse{
+			Reference: upload.Reference,
+			Receipts: []api.ReceiptResponse{{
+				Storer:    receipt.Storer,
+				Signature: hex.EncodeToString(receipt.Signature),
+				Nonce:     hex.EncodeToString(receipt.Nonce),
+				Timestamp: receipt.Timestamp,
+			}},
+		}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/receipts/"+swarm.RandAddress(t).String(), http.StatusNotFound,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "receipts not found",
+			Code:    http.StatusNotFound,
+		}),
+	)
+}
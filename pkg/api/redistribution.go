@@ -6,10 +6,14 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/bigint"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/storageincentives"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
 )
 
 type nodeStatusResponse struct {
@@ -64,3 +68,185 @@ func (s *Service) redistributionStatusHandler(w http.ResponseWriter, r *http.Req
 		Fees:               bigint.Wrap(status.Fees),
 	})
 }
+
+// defaultRedistributionRoundsLimit is the default number of the returned rounds.
+const defaultRedistributionRoundsLimit = 100
+
+type redistributionPhaseResponse struct {
+	Done  bool   `json:"done"`
+	Error string `json:"error,omitempty"`
+}
+
+type redistributionRoundResponse struct {
+	Round      uint64                      `json:"round"`
+	Selected   bool                        `json:"selected"`
+	SampleHash hexByte                     `json:"sampleHash,omitempty"`
+	Sample     redistributionPhaseResponse `json:"sample"`
+	Commit     redistributionPhaseResponse `json:"commit"`
+	Reveal     redistributionPhaseResponse `json:"reveal"`
+	Claim      redistributionPhaseResponse `json:"claim"`
+	Winner     bool                        `json:"winner"`
+	Reward     *bigint.BigInt              `json:"reward"`
+}
+
+type redistributionRoundsResponse struct {
+	Rounds []redistributionRoundResponse `json:"rounds"`
+}
+
+func newRedistributionPhaseResponse(o storageincentives.PhaseOutcome) redistributionPhaseResponse {
+	return redistributionPhaseResponse{Done: o.Done, Error: o.Error}
+}
+
+// redistributionRoundsHandler returns the data of the latest rounds
+// the node took part in, starting with the latest one.
+func (s *Service) redistributionRoundsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("get_redistribution_rounds").Build())
+
+	queries := struct {
+		Limit *int `map:"limit" validate:"omitempty,min=1"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	limit := defaultRedistributionRoundsLimit
+	if queries.Limit != nil {
+		limit = *queries.Limit
+	}
+
+	if s.beeMode != FullMode {
+		jsonhttp.BadRequest(w, errOperationSupportedOnlyInFullMode)
+		return
+	}
+
+	rounds, err := s.redistributionAgent.Rounds(limit)
+	if err != nil {
+		logger.Debug("get redistribution rounds", "error", err)
+		logger.Error(nil, "get redistribution rounds")
+		jsonhttp.InternalServerError(w, "failed to get redistribution rounds")
+		return
+	}
+
+	res := redistributionRoundsResponse{Rounds: make([]redistributionRoundResponse, 0, len(rounds))}
+	for _, d := range rounds {
+		res.Rounds = append(res.Rounds, redistributionRoundResponse{
+			Round:      d.Round,
+			Selected:   d.Selected,
+			SampleHash: d.SampleHash,
+			Sample:     newRedistributionPhaseResponse(d.Sample),
+			Commit:     newRedistributionPhaseResponse(d.Commit),
+			Reveal:     newRedistributionPhaseResponse(d.Reveal),
+			Claim:      newRedistributionPhaseResponse(d.Claim),
+			Winner:     d.Winner,
+			Reward:     bigint.Wrap(d.Reward),
+		})
+	}
+	jsonhttp.OK(w, res)
+}
+
+type inclusionProofResponse struct {
+	Section hexByte   `json:"section"`
+	Sisters []hexByte `json:"sisters"`
+	Span    hexByte   `json:"span"`
+}
+
+type reserveProofItemResponse struct {
+	TransformedAddress swarm.Address          `json:"transformedAddress"`
+	InclusionProof     inclusionProofResponse `json:"inclusionProof"`
+	ChunkAddress       swarm.Address          `json:"chunkAddress"`
+	ChunkData          hexByte                `json:"chunkData"`
+	Stamp              hexByte                `json:"stamp"`
+}
+
+type reserveProofResponse struct {
+	Overlay       swarm.Address              `json:"overlay"`
+	Anchor        hexByte                    `json:"anchor"`
+	Depth         uint8                      `json:"depth"`
+	ConsensusTime uint64                     `json:"consensusTime"`
+	SampleHash    swarm.Address              `json:"sampleHash"`
+	Items         []reserveProofItemResponse `json:"items"`
+}
+
+// redistributionProofHandler returns the proof that the node stores its reserve
+// of the depth, made of the sample of the reserve with the anchor and of the
+// inclusion proofs of its items in the reserve commitment hash. The sample of
+// a played round can be reproduced with the consensus time of the round.
+func (s *Service) redistributionProofHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("get_redistribution_proof").Build())
+
+	paths := struct {
+		Depth  uint8  `map:"depth"`
+		Anchor []byte `map:"anchor" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	queries := struct {
+		Time uint64 `map:"time"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+	if queries.Time == 0 {
+		queries.Time = uint64(time.Now().UnixNano())
+	}
+
+	if s.beeMode != FullMode {
+		jsonhttp.BadRequest(w, errOperationSupportedOnlyInFullMode)
+		return
+	}
+
+	sample, err := s.storer.ReserveSample(r.Context(), paths.Anchor, paths.Depth, queries.Time)
+	if err != nil {
+		logger.Debug("reserve sample failed", "error", err)
+		logger.Error(nil, "reserve sample failed")
+		jsonhttp.InternalServerError(w, "failed generating sample")
+		return
+	}
+	proof, err := storageincentives.NewReserveProof(*s.overlay, paths.Anchor, paths.Depth, sample)
+	if err != nil {
+		logger.Debug("reserve proof failed", "error", err)
+		logger.Error(nil, "reserve proof failed")
+		jsonhttp.InternalServerError(w, "failed generating proof")
+		return
+	}
+
+	res := reserveProofResponse{
+		Overlay:       proof.Overlay,
+		Anchor:        proof.Anchor,
+		Depth:         proof.Depth,
+		ConsensusTime: queries.Time,
+		SampleHash:    proof.SampleHash,
+		Items:         make([]reserveProofItemResponse, 0, len(proof.Items)),
+	}
+	for _, item := range proof.Items {
+		var stamp []byte
+		if st := item.Chunk.Stamp(); st != nil {
+			if stamp, err = st.MarshalBinary(); err != nil {
+				logger.Debug("marshal stamp failed", "chunk_address", item.Chunk.Address(), "error", err)
+				logger.Error(nil, "marshal stamp failed")
+				jsonhttp.InternalServerError(w, "failed generating proof")
+				return
+			}
+		}
+		sisters := make([]hexByte, len(item.Inclusion.Sisters))
+		for i, sister := range item.Inclusion.Sisters {
+			sisters[i] = sister
+		}
+		res.Items = append(res.Items, reserveProofItemResponse{
+			TransformedAddress: item.TransformedAddress,
+			InclusionProof: inclusionProofResponse{
+				Section: item.Inclusion.Section,
+				Sisters: sisters,
+				Span:    item.Inclusion.Span,
+			},
+			ChunkAddress: item.Chunk.Address(),
+			ChunkData:    item.Chunk.Data(),
+			Stamp:        stamp,
+		})
+	}
+	jsonhttp.OK(w, res)
+}
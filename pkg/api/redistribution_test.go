@@ -5,19 +5,31 @@
 package api_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
 	"math/big"
 	"net/http"
+	"sort"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/bmt"
+	"github.com/ethersphere/bee/pkg/bmtpool"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	mockstorer "github.com/ethersphere/bee/pkg/storage/mock"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
 	"github.com/ethersphere/bee/pkg/storageincentives"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/transaction/backendmock"
 	"github.com/ethersphere/bee/pkg/transaction/mock"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestRedistributionStatus(t *testing.T) {
@@ -78,3 +90,209 @@ func TestRedistributionStatus(t *testing.T) {
 		)
 	})
 }
+
+func TestRedistributionRounds(t *testing.T) {
+	t.Parallel()
+
+	store := statestore.NewStateStore()
+	for _, d := range []storageincentives.RoundData{
+		{
+			Round:    4,
+			Selected: true,
+			Sample:   storageincentives.PhaseOutcome{Error: "sample failed"},
+			Reward:   big.NewInt(0),
+		},
+		{
+			Round:      7,
+			Selected:   true,
+			SampleHash: []byte{0xaa, 0xbb},
+			Sample:     storageincentives.PhaseOutcome{Done: true},
+			Commit:     storageincentives.PhaseOutcome{Done: true},
+			Reveal:     storageincentives.PhaseOutcome{Done: true},
+			Claim:      storageincentives.PhaseOutcome{Done: true},
+			Winner:     true,
+			Reward:     big.NewInt(500),
+		},
+	} {
+		if err := store.Put(fmt.Sprintf("redistribution_round_%020d", d.Round), d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type phase struct {
+		Done  bool   `json:"done"`
+		Error string `json:"error"`
+	}
+	type round struct {
+		Round      uint64 `json:"round"`
+		Selected   bool   `json:"selected"`
+		SampleHash string `json:"sampleHash"`
+		Sample     phase  `json:"sample"`
+		Commit     phase  `json:"commit"`
+		Reveal     phase  `json:"reveal"`
+		Claim      phase  `json:"claim"`
+		Winner     bool   `json:"winner"`
+		Reward     string `json:"reward"`
+	}
+	type response struct {
+		Rounds []round `json:"rounds"`
+	}
+	won := round{
+		Round:      7,
+		Selected:   true,
+		SampleHash: "aabb",
+		Sample:     phase{Done: true},
+		Commit:     phase{Done: true},
+		Reveal:     phase{Done: true},
+		Claim:      phase{Done: true},
+		Winner:     true,
+		Reward:     "500",
+	}
+	failed := round{
+		Round:    4,
+		Selected: true,
+		Sample:   phase{Error: "sample failed"},
+		Reward:   "0",
+	}
+
+	srv, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:    true,
+		StateStorer: store,
+	})
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		var got response
+		jsonhttptest.Request(t, srv, http.MethodGet, "/redistribution/rounds", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if diff := cmp.Diff(response{Rounds: []round{won, failed}}, got); diff != "" {
+			t.Errorf("result mismatch (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		t.Parallel()
+
+		var got response
+		jsonhttptest.Request(t, srv, http.MethodGet, "/redistribution/rounds?limit=1", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&got),
+		)
+		if diff := cmp.Diff(response{Rounds: []round{won}}, got); diff != "" {
+			t.Errorf("result mismatch (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, srv, http.MethodGet, "/redistribution/rounds?limit=0", http.StatusBadRequest)
+	})
+}
+
+// samplerStorer returns the sample made of its chunks.
+type samplerStorer struct {
+	storage.Storer
+	chunks []swarm.Chunk
+}
+
+func (s *samplerStorer) ReserveSample(_ context.Context, anchor []byte, _ uint8, _ uint64) (storage.Sample, error) {
+	var sample storage.Sample
+	for _, ch := range s.chunks {
+		mac := hmac.New(swarm.NewHasher, anchor)
+		_, _ = mac.Write(ch.Data())
+		sample.Items = append(sample.Items, swarm.NewAddress(mac.Sum(nil)))
+		sample.Chunks = append(sample.Chunks, ch)
+	}
+	sort.Sort(byTransformedAddress(sample))
+
+	hasher := bmtpool.Get()
+	defer bmtpool.Put(hasher)
+	for _, item := range sample.Items {
+		if _, err := hasher.Write(item.Bytes()); err != nil {
+			return storage.Sample{}, err
+		}
+	}
+	sample.Hash = swarm.NewAddress(hasher.Sum(nil))
+	return sample, nil
+}
+
+type byTransformedAddress storage.Sample
+
+func (s byTransformedAddress) Len() int { return len(s.Items) }
+func (s byTransformedAddress) Less(i, j int) bool {
+	return bytes.Compare(s.Items[i].Bytes(), s.Items[j].Bytes()) < 0
+}
+func (s byTransformedAddress) Swap(i, j int) {
+	s.Items[i], s.Items[j] = s.Items[j], s.Items[i]
+	s.Chunks[i], s.Chunks[j] = s.Chunks[j], s.Chunks[i]
+}
+
+func TestRedistributionProof(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		overlay := swarm.RandAddress(t)
+		storer := &samplerStorer{Storer: mockstorer.NewStorer()}
+		for i := 0; i < 8; i++ {
+			storer.chunks = append(storer.chunks, testingc.GenerateTestRandomChunk())
+		}
+		srv, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			Storer:   storer,
+			Overlay:  overlay,
+		})
+
+		var res api.ReserveProofResponse
+		jsonhttptest.Request(t, srv, http.MethodGet, "/redistribution/proof/0/abcd?time=10", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if !res.Overlay.Equal(overlay) || hex.EncodeToString(res.Anchor) != "abcd" || res.ConsensusTime != 10 || len(res.Items) != 8 {
+			t.Fatalf("unexpected proof %+v", res)
+		}
+
+		// the proof of the response verifies
+		proof := storageincentives.ReserveProof{
+			Overlay:    res.Overlay,
+			Anchor:     res.Anchor,
+			Depth:      res.Depth,
+			SampleHash: res.SampleHash,
+		}
+		for _, item := range res.Items {
+			sisters := make([][]byte, len(item.InclusionProof.Sisters))
+			for i, sister := range item.InclusionProof.Sisters {
+				sisters[i] = sister
+			}
+			proof.Items = append(proof.Items, storageincentives.ReserveProofItem{
+				TransformedAddress: item.TransformedAddress,
+				Inclusion: bmt.Proof{
+					Section: item.InclusionProof.Section,
+					Sisters: sisters,
+					Span:    item.InclusionProof.Span,
+				},
+				Chunk: swarm.NewChunk(item.ChunkAddress, item.ChunkData),
+			})
+		}
+		if err := storageincentives.VerifyReserveProof(proof); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		t.Parallel()
+
+		srv, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			BeeMode:  api.LightMode,
+		})
+		jsonhttptest.Request(t, srv, http.MethodGet, "/redistribution/proof/0/abcd", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: api.ErrOperationSupportedOnlyInFullMode.Error(),
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+// ReserveReporter reports the size and the configured capacity of the reserve.
+type ReserveReporter interface {
+	ComputeReserveSize(uint8) (uint64, error)
+	ReserveCapacity() uint64
+}
+
+// ReserveChecker finds the chunks missing in the reserve, removes them
+// and returns the proximity order bins in which they were found.
+type ReserveChecker interface {
+	CheckReserve(context.Context) ([]uint8, error)
+}
+
+// ReserveRepairer syncs the bins of the reserve again.
+type ReserveRepairer interface {
+	Repair(bins ...uint8)
+}
+
+// SyncReporter reports the rate of the chunks synced by the node in chunks per second.
+type SyncReporter interface {
+	Rate() float64
+}
+
+type reserveForecastResponse struct {
+	Radius            uint8   `json:"radius"`
+	StorageRadius     uint8   `json:"storageRadius"`
+	Commitment        int64   `json:"commitment"`
+	CommittedSize     int64   `json:"committedSize"`
+	ReserveSize       uint64  `json:"reserveSize"`
+	ReserveCapacity   uint64  `json:"reserveCapacity"`
+	SyncRate          float64 `json:"syncRate"`
+	SecondsToCapacity int64   `json:"secondsToCapacity"`
+}
+
+// reserveForecastHandler forecasts when the reserve reaches its capacity.
+// The reserve is expected to fill up only if the commitment of the batches
+// falling within the storage radius exceeds the capacity, in which case the
+// remaining capacity is divided by the observed rate of the synced chunks.
+// A negative number of seconds means the capacity is not expected to be
+// reached with the current commitment and sync rate.
+func (s *Service) reserveForecastHandler(w http.ResponseWriter, _ *http.Request) {
+	logger := s.logger.WithName("get_reserve_forecast").Build()
+
+	if s.reserve == nil {
+		jsonhttp.NotImplemented(w, "reserve not available")
+		logger.Error(nil, "reserve forecast not implemented")
+		return
+	}
+
+	state := s.batchStore.GetReserveState()
+	commitment, err := s.batchCommitment()
+	if err != nil {
+		logger.Debug("batch store iteration failed", "error", err)
+		logger.Error(nil, "batch store iteration failed")
+		jsonhttp.InternalServerError(w, "unable to iterate all batches")
+		return
+	}
+
+	size, err := s.reserve.ComputeReserveSize(state.StorageRadius)
+	if err != nil {
+		logger.Debug("compute reserve size failed", "error", err)
+		logger.Error(nil, "compute reserve size failed")
+		jsonhttp.InternalServerError(w, "unable to compute reserve size")
+		return
+	}
+
+	var rate float64
+	if s.syncer != nil {
+		rate = s.syncer.Rate()
+	}
+
+	capacity := s.reserve.ReserveCapacity()
+	committed := commitment >> state.StorageRadius
+
+	eta := int64(-1)
+	switch {
+	case size >= capacity:
+		eta = 0
+	case rate > 0 && committed >= int64(capacity):
+		eta = int64(float64(capacity-size) / rate)
+	}
+
+	jsonhttp.OK(w, reserveForecastResponse{
+		Radius:            state.Radius,
+		StorageRadius:     state.StorageRadius,
+		Commitment:        commitment,
+		CommittedSize:     committed,
+		ReserveSize:       size,
+		ReserveCapacity:   capacity,
+		SyncRate:          rate,
+		SecondsToCapacity: eta,
+	})
+}
+
+type reserveRepairResponse struct {
+	Bins []int `json:"bins"`
+}
+
+// reserveRepairHandler checks the reserve for the missing chunks and
+// schedules the resync of the bins in which they were found.
+func (s *Service) reserveRepairHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_reserve_repair").Build()
+
+	if s.reserveChecker == nil || s.reserveRepairer == nil {
+		jsonhttp.NotImplemented(w, "reserve repair not available")
+		logger.Error(nil, "reserve repair not implemented")
+		return
+	}
+
+	bins, err := s.reserveChecker.CheckReserve(r.Context())
+	if err != nil {
+		logger.Debug("check reserve failed", "error", err)
+		logger.Error(nil, "check reserve failed")
+		jsonhttp.InternalServerError(w, "unable to check reserve")
+		return
+	}
+	if len(bins) > 0 {
+		s.reserveRepairer.Repair(bins...)
+	}
+
+	resp := reserveRepairResponse{Bins: make([]int, 0, len(bins))}
+	for _, bin := range bins {
+		resp.Bins = append(resp.Bins, int(bin))
+	}
+	jsonhttp.OK(w, resp)
+}
@@ -0,0 +1,190 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+)
+
+type testReserve struct {
+	size     uint64
+	capacity uint64
+}
+
+var _ api.ReserveReporter = (*testReserve)(nil)
+
+func (r *testReserve) ComputeReserveSize(uint8) (uint64, error) { return r.size, nil }
+func (r *testReserve) ReserveCapacity() uint64                  { return r.capacity }
+
+type testSyncer float64
+
+func (s testSyncer) Rate() float64 { return float64(s) }
+
+func TestReserveForecast(t *testing.T) {
+	t.Parallel()
+
+	batch := postagetesting.MustNewBatch(postagetesting.WithDepth(20))
+
+	for _, tc := range []struct {
+		name     string
+		reserve  *testReserve
+		rate     float64
+		radius   uint8
+		expected int64
+	}{
+		{
+			name:     "filling up",
+			reserve:  &testReserve{size: 1000, capacity: 10000},
+			rate:     10,
+			radius:   4,
+			expected: 900,
+		},
+		{
+			name:     "commitment below capacity",
+			reserve:  &testReserve{size: 1000, capacity: 10000},
+			rate:     10,
+			radius:   8,
+			expected: -1,
+		},
+		{
+			name:     "not syncing",
+			reserve:  &testReserve{size: 1000, capacity: 10000},
+			radius:   4,
+			expected: -1,
+		},
+		{
+			name:     "full",
+			reserve:  &testReserve{size: 10000, capacity: 10000},
+			rate:     10,
+			radius:   4,
+			expected: 0,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts, _, _, _ := newTestServer(t, testServerOptions{
+				DebugAPI: true,
+				BatchStore: mock.New(
+					mock.WithReserveState(&postage.ReserveState{Radius: 6, StorageRadius: tc.radius}),
+					mock.WithBatch(batch),
+				),
+				Reserve: tc.reserve,
+				Syncer:  testSyncer(tc.rate),
+			})
+			jsonhttptest.Request(t, ts, http.MethodGet, "/reserve/forecast", http.StatusOK,
+				jsonhttptest.WithExpectedJSONResponse(api.ReserveForecastResponse{
+					Radius:            6,
+					StorageRadius:     tc.radius,
+					Commitment:        1 << 20,
+					CommittedSize:     1 << (20 - tc.radius),
+					ReserveSize:       tc.reserve.size,
+					ReserveCapacity:   tc.reserve.capacity,
+					SyncRate:          tc.rate,
+					SecondsToCapacity: tc.expected,
+				}),
+			)
+		})
+	}
+
+	t.Run("not implemented", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:   true,
+			BatchStore: mock.New(),
+		})
+		jsonhttptest.Request(t, ts, http.MethodGet, "/reserve/forecast", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotImplemented,
+				Message: "reserve not available",
+			}),
+		)
+	})
+}
+
+type testReserveChecker []uint8
+
+func (c testReserveChecker) CheckReserve(context.Context) ([]uint8, error) { return c, nil }
+
+type testReserveRepairer struct {
+	mu   sync.Mutex
+	bins []uint8
+}
+
+func (r *testReserveRepairer) Repair(bins ...uint8) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bins = append(r.bins, bins...)
+}
+
+func TestReserveRepair(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing chunks", func(t *testing.T) {
+		t.Parallel()
+
+		repairer := new(testReserveRepairer)
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:        true,
+			BatchStore:      mock.New(),
+			ReserveChecker:  testReserveChecker{2, 5},
+			ReserveRepairer: repairer,
+		})
+		jsonhttptest.Request(t, ts, http.MethodPost, "/reserve/repair", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ReserveRepairResponse{Bins: []int{2, 5}}),
+		)
+		repairer.mu.Lock()
+		defer repairer.mu.Unlock()
+		if want := []uint8{2, 5}; !reflect.DeepEqual(repairer.bins, want) {
+			t.Fatalf("got repaired bins %v, want %v", repairer.bins, want)
+		}
+	})
+
+	t.Run("intact", func(t *testing.T) {
+		t.Parallel()
+
+		repairer := new(testReserveRepairer)
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:        true,
+			BatchStore:      mock.New(),
+			ReserveChecker:  testReserveChecker{},
+			ReserveRepairer: repairer,
+		})
+		jsonhttptest.Request(t, ts, http.MethodPost, "/reserve/repair", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ReserveRepairResponse{Bins: []int{}}),
+		)
+		if len(repairer.bins) != 0 {
+			t.Fatalf("got repaired bins %v, want none", repairer.bins)
+		}
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI:   true,
+			BatchStore: mock.New(),
+		})
+		jsonhttptest.Request(t, ts, http.MethodPost, "/reserve/repair", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotImplemented,
+				Message: "reserve repair not available",
+			}),
+		)
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+type restampRequest struct {
+	Reference string `json:"reference"`
+	BatchID   string `json:"batchID"`
+}
+
+type restampResponse struct {
+	Reference swarm.Address `json:"reference"`
+	BatchID   hexByte       `json:"batchID"`
+	Restamped int           `json:"restamped"`
+	Missing   int           `json:"missing"`
+}
+
+// restampHandler traverses the content under the reference and stamps
+// every locally stored chunk with the given batch before pushing it to
+// the network again. Chunks which are not stored locally are skipped and
+// reported as missing, as they cannot be restamped without re-uploading.
+func (s *Service) restampHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_stamps_restamp").Build()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+	var req restampRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Debug("unmarshal request body failed", "error", err)
+		logger.Error(nil, "unmarshal request body failed")
+		jsonhttp.BadRequest(w, "invalid request")
+		return
+	}
+	reference, err := swarm.ParseHexAddress(req.Reference)
+	if err != nil || reference.IsZero() {
+		logger.Debug("invalid reference", "reference", req.Reference, "error", err)
+		logger.Error(nil, "invalid reference")
+		jsonhttp.BadRequest(w, "invalid reference")
+		return
+	}
+	batchID, err := hex.DecodeString(req.BatchID)
+	if err != nil || len(batchID) != 32 {
+		logger.Debug("invalid batch id", "batch_id", req.BatchID, "error", err)
+		logger.Error(nil, "invalid batch id")
+		jsonhttp.BadRequest(w, "invalid batch id")
+		return
+	}
+
+	if !requestTenant(r.Context()).allowsBatch(batchID) {
+		logger.Debug("batch not allowed", "batch_id", req.BatchID)
+		logger.Error(nil, "batch not allowed")
+		jsonhttp.Forbidden(w, "batch not allowed")
+		return
+	}
+
+	if s.beeMode == DevMode {
+		jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		return
+	}
+
+	exists, err := s.batchStore.Exists(batchID)
+	if err != nil {
+		logger.Debug("batch exists check failed", "batch_id", req.BatchID, "error", err)
+		logger.Error(nil, "batch exists check failed")
+		jsonhttp.InternalServerError(w, "batch exists check failed")
+		return
+	}
+	issuer, save, err := s.post.GetStampIssuer(batchID)
+	if err != nil {
+		logger.Debug("get stamp issuer failed", "batch_id", req.BatchID, "error", err)
+		logger.Error(nil, "get stamp issuer failed")
+		switch {
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		default:
+			jsonhttp.InternalServerError(w, "get stamp issuer failed")
+		}
+		return
+	}
+	if usable := exists && s.post.IssuerUsable(issuer); !usable {
+		jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		return
+	}
+
+	var (
+		ctx     = r.Context()
+		putter  = newPushStamperPutter(s.storer, postage.NewStamper(issuer, s.signer), s.chunkPushC)
+		seen    = make(map[string]struct{})
+		missing int
+	)
+	err = s.traversal.Traverse(ctx, reference, func(addr swarm.Address) error {
+		if _, ok := seen[addr.ByteString()]; ok {
+			return nil
+		}
+		seen[addr.ByteString()] = struct{}{}
+
+		has, err := s.storer.Has(ctx, addr)
+		if err != nil {
+			return err
+		}
+		if !has {
+			missing++
+			return nil
+		}
+		ch, err := s.storer.Get(ctx, storage.ModeGetRequest, addr)
+		if err != nil {
+			return err
+		}
+		stamp, err := putter.stamper.Stamp(addr)
+		if err != nil {
+			return err
+		}
+		putter.putChunk(ctx, ch.WithStamp(stamp))
+		return nil
+	})
+	if waitErr := putter.Wait(); err == nil {
+		err = waitErr
+	}
+	if saveErr := save(); err == nil {
+		err = saveErr
+	}
+	if err != nil {
+		logger.Debug("restamp failed", "reference", reference, "batch_id", req.BatchID, "error", err)
+		logger.Error(nil, "restamp failed")
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			jsonhttp.NotFound(w, "content not found")
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
+		default:
+			jsonhttp.InternalServerError(w, "restamp failed")
+		}
+		return
+	}
+
+	jsonhttp.OK(w, restampResponse{
+		Reference: reference,
+		BatchID:   batchID,
+		Restamped: len(seen) - missing,
+		Missing:   missing,
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"gitlab.com/nolash/go-mockbytes"
+)
+
+func TestRestamp(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storerMock               = mock.NewStorer()
+		client, _, _, chanStorer = newTestServer(t, testServerOptions{
+			Storer:       storerMock,
+			Traversal:    traversal.New(storerMock),
+			Post:         mockpost.New(mockpost.WithAcceptAll()),
+			DebugAPI:     true,
+			DirectUpload: true,
+		})
+		upload, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storerMock,
+			Tags:   tags.NewTags(nil, log.Noop),
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+	)
+
+	content, err := mockbytes.New(0, mockbytes.MockTypeStandard).WithModulus(255).SequentialBytes(swarm.ChunkSize * 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp api.BytesPostResponse
+	jsonhttptest.Request(t, upload, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+
+	request := func(reference, batchID string) *strings.Reader {
+		return strings.NewReader(fmt.Sprintf(`{"reference":%q,"batchID":%q}`, reference, batchID))
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/stamps/restamp", http.StatusOK,
+			jsonhttptest.WithRequestBody(request(resp.Reference.String(), batchOkStr)),
+			jsonhttptest.WithExpectedJSONResponse(api.RestampResponse{
+				Reference: resp.Reference,
+				BatchID:   batchOk,
+				Restamped: 3,
+			}),
+		)
+
+		has, err := chanStorer.Has(context.Background(), resp.Reference)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !has {
+			t.Fatal("restamped root chunk not pushed")
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/stamps/restamp", http.StatusNotFound,
+			jsonhttptest.WithRequestBody(request(swarm.RandAddress(t).String(), batchOkStr)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotFound,
+				Message: "content not found",
+			}),
+		)
+	})
+
+	t.Run("invalid batch", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/stamps/restamp", http.StatusBadRequest,
+			jsonhttptest.WithRequestBody(request(resp.Reference.String(), "abcd")),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid batch id",
+			}),
+		)
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/stamps/restamp", http.StatusBadRequest,
+			jsonhttptest.WithRequestBody(request("xyz", batchOkStr)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid reference",
+			}),
+		)
+	})
+}
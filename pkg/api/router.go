@@ -21,16 +21,19 @@ import (
 )
 
 const (
-	apiVersion = "v1" // Only one api version exists, this should be configurable with more.
+	apiVersion = apiVersionV1 // The api version served on the unversioned paths by default.
 	rootPath   = "/" + apiVersion
 )
 
-func (s *Service) MountTechnicalDebug() {
+// MountTechnicalDebug mounts the technical debug endpoints. In the restricted
+// mode the runtime profiling endpoints are not mounted, they are mounted by
+// the MountDebug behind the permission check instead.
+func (s *Service) MountTechnicalDebug(restricted bool) {
 	router := mux.NewRouter()
 	router.NotFoundHandler = http.HandlerFunc(jsonhttp.NotFoundHandler)
 	s.router = router
 
-	s.mountTechnicalDebug()
+	s.mountTechnicalDebug(restricted)
 
 	s.Handler = web.ChainHandlers(
 		httpaccess.NewHTTPAccessLogHandler(s.logger, s.tracer, "debug api access"),
@@ -47,7 +50,10 @@ func (s *Service) MountDebug(restricted bool) {
 	s.Handler = web.ChainHandlers(
 		httpaccess.NewHTTPAccessLogHandler(s.logger, s.tracer, "debug api access"),
 		handlers.CompressHandler,
+		s.auditHandler,
 		s.corsHandler,
+		s.tenantHandler,
+		s.apiVersionHandler,
 		web.NoCacheHeadersHandler,
 		web.FinalHandler(s.router),
 	)
@@ -59,31 +65,26 @@ func (s *Service) MountAPI() {
 		s.router.NotFoundHandler = http.HandlerFunc(jsonhttp.NotFoundHandler)
 	}
 
-	s.mountAPI()
-
-	skipHeadHandler := func(fn func(http.Handler) http.Handler) func(h http.Handler) http.Handler {
-		return func(h http.Handler) http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				if r.Method == http.MethodHead {
-					h.ServeHTTP(w, r)
-				} else {
-					fn(h).ServeHTTP(w, r)
-				}
-			})
-		}
+	s.router.Use(routeLabelHandler)
+	if s.Gateway {
+		s.router.Use(s.gatewayHandler)
 	}
+	s.mountAPI()
 
 	s.Handler = web.ChainHandlers(
 		httpaccess.NewHTTPAccessLogHandler(s.logger, s.tracer, "api access"),
-		skipHeadHandler(handlers.CompressHandler),
-		s.responseCodeMetricsHandler,
-		s.pageviewMetricsHandler,
+		s.compressHandler,
+		newRouteMetricsHandler(s.metrics),
+		s.auditHandler,
 		s.corsHandler,
+		s.tenantHandler,
+		s.rootNeighbourhoodHandler,
+		s.apiVersionHandler,
 		web.FinalHandler(s.router),
 	)
 }
 
-func (s *Service) mountTechnicalDebug() {
+func (s *Service) mountTechnicalDebug(restricted bool) {
 	s.router.Handle("/node", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.nodeGetHandler),
 	})
@@ -100,22 +101,13 @@ func (s *Service) mountTechnicalDebug() {
 		httpaccess.NewHTTPAccessSuppressLogHandler(),
 		web.FinalHandler(promhttp.InstrumentMetricHandler(
 			s.metricsRegistry,
-			promhttp.HandlerFor(s.metricsRegistry, promhttp.HandlerOpts{}),
+			promhttp.HandlerFor(s.metricsRegistry, promhttp.HandlerOpts{EnableOpenMetrics: true}),
 		)),
 	))
 
-	s.router.Handle("/debug/pprof", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		u := r.URL
-		u.Path += "/"
-		http.Redirect(w, r, u.String(), http.StatusPermanentRedirect)
-	}))
-	s.router.Handle("/debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
-	s.router.Handle("/debug/pprof/profile", http.HandlerFunc(pprof.Profile))
-	s.router.Handle("/debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
-	s.router.Handle("/debug/pprof/trace", http.HandlerFunc(pprof.Trace))
-	s.router.PathPrefix("/debug/pprof/").Handler(http.HandlerFunc(pprof.Index))
-
-	s.router.Handle("/debug/vars", expvar.Handler())
+	if !restricted {
+		s.mountProfiling(nil)
+	}
 
 	s.router.Handle("/loggers", jsonhttp.MethodHandler{
 		"GET": web.ChainHandlers(
@@ -173,14 +165,14 @@ func (s *Service) mountAPI() {
 
 	// handle is a helper closure which simplifies the router setup.
 	handle := func(path string, handler http.Handler) {
-		s.router.Handle(path, handler)
-		s.router.Handle(rootPath+path, handler)
+		s.handleVersions(path, path, handler)
 	}
 
 	handle("/bytes", jsonhttp.MethodHandler{
 		"POST": web.ChainHandlers(
 			s.contentLengthMetricMiddleware(),
 			s.newTracingHandler("bytes-upload"),
+			s.idempotencyHandler(),
 			web.FinalHandlerFunc(s.bytesUploadHandler),
 		),
 	})
@@ -189,6 +181,8 @@ func (s *Service) mountAPI() {
 		"GET": web.ChainHandlers(
 			s.contentLengthMetricMiddleware(),
 			s.newTracingHandler("bytes-download"),
+			s.chunkTraceHandler,
+			s.contentStatsHandler,
 			web.FinalHandlerFunc(s.bytesGetHandler),
 		),
 		"HEAD": web.ChainHandlers(
@@ -197,6 +191,10 @@ func (s *Service) mountAPI() {
 		),
 	})
 
+	handle("/traces/{id}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.chunkTraceGetHandler),
+	})
+
 	handle("/chunks", jsonhttp.MethodHandler{
 		"POST": web.ChainHandlers(
 			jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkWithSpanSize),
@@ -209,6 +207,10 @@ func (s *Service) mountAPI() {
 		web.FinalHandlerFunc(s.chunkUploadStreamHandler),
 	))
 
+	handle("/chunks/has", jsonhttp.MethodHandler{
+		"POST": http.HandlerFunc(s.hasChunksHandler),
+	})
+
 	handle("/chunks/{address}", jsonhttp.MethodHandler{
 		"GET":    http.HandlerFunc(s.chunkGetHandler),
 		"HEAD":   http.HandlerFunc(s.hasChunkHandler),
@@ -216,8 +218,10 @@ func (s *Service) mountAPI() {
 	})
 
 	handle("/soc/{owner}/{id}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.socGetHandler),
 		"POST": web.ChainHandlers(
 			jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkWithSpanSize),
+			s.idempotencyHandler(),
 			web.FinalHandlerFunc(s.socUploadHandler),
 		),
 	})
@@ -230,14 +234,59 @@ func (s *Service) mountAPI() {
 		),
 	})
 
+	handle("/feeds/{owner}/{topic}/snapshot", jsonhttp.MethodHandler{
+		"POST": http.HandlerFunc(s.feedSnapshotHandler),
+	})
+
+	handle("/collections/{name}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.collectionGetHandler),
+		"PUT": web.ChainHandlers(
+			jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkSize),
+			web.FinalHandlerFunc(s.collectionPutHandler),
+		),
+	})
+
+	handle("/collections/{name}/documents", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.collectionDocumentsGetHandler),
+		"POST": web.ChainHandlers(
+			jsonhttp.NewMaxBodyBytesHandler(maxCollectionDocumentSize),
+			web.FinalHandlerFunc(s.collectionDocumentPostHandler),
+		),
+	})
+
+	handle("/collections/{name}/documents/{id}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.collectionDocumentGetHandler),
+		"PUT": web.ChainHandlers(
+			jsonhttp.NewMaxBodyBytesHandler(maxCollectionDocumentSize),
+			web.FinalHandlerFunc(s.collectionDocumentPutHandler),
+		),
+		"DELETE": http.HandlerFunc(s.collectionDocumentDeleteHandler),
+	})
+
 	handle("/bzz", jsonhttp.MethodHandler{
 		"POST": web.ChainHandlers(
 			s.contentLengthMetricMiddleware(),
 			s.newTracingHandler("bzz-upload"),
+			s.idempotencyHandler(),
 			web.FinalHandlerFunc(s.bzzUploadHandler),
 		),
 	})
 
+	handle("/publish/{name}", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.contentLengthMetricMiddleware(),
+			s.newTracingHandler("publish"),
+			web.FinalHandlerFunc(s.publishHandler),
+		),
+	})
+
+	handle("/import/ipfs/{cid}", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.newTracingHandler("import-ipfs"),
+			web.FinalHandlerFunc(s.ipfsImportHandler),
+		),
+	})
+
 	handle("/bzz/{address}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		u := r.URL
 		u.Path += "/"
@@ -248,10 +297,21 @@ func (s *Service) mountAPI() {
 		"GET": web.ChainHandlers(
 			s.contentLengthMetricMiddleware(),
 			s.newTracingHandler("bzz-download"),
+			s.chunkTraceHandler,
+			s.contentStatsHandler,
 			web.FinalHandlerFunc(s.bzzDownloadHandler),
 		),
 	})
 
+	if s.WebDAV {
+		webdavHandler := web.ChainHandlers(
+			s.newTracingHandler("webdav"),
+			web.FinalHandlerFunc(s.webdavHandler),
+		)
+		handle("/webdav/{address}", webdavHandler)
+		handle("/webdav/{address}/{path:.*}", webdavHandler)
+	}
+
 	handle("/pss/send/{topic}/{targets}", web.ChainHandlers(
 		web.FinalHandler(jsonhttp.MethodHandler{
 			"POST": web.ChainHandlers(
@@ -300,6 +360,56 @@ func (s *Service) mountAPI() {
 		})),
 	)
 
+	if s.workingSet != nil {
+		handle("/cache/working-set", web.ChainHandlers(
+			web.FinalHandler(jsonhttp.MethodHandler{
+				"GET": http.HandlerFunc(s.workingSetListHandler),
+				"POST": web.ChainHandlers(
+					jsonhttp.NewMaxBodyBytesHandler(1024*1024),
+					web.FinalHandlerFunc(s.workingSetPostHandler),
+				),
+			})),
+		)
+
+		handle("/cache/working-set/{id}", web.ChainHandlers(
+			web.FinalHandler(jsonhttp.MethodHandler{
+				"GET": http.HandlerFunc(s.workingSetGetHandler),
+				"PUT": web.ChainHandlers(
+					jsonhttp.NewMaxBodyBytesHandler(1024),
+					web.FinalHandlerFunc(s.workingSetPutHandler),
+				),
+				"DELETE": http.HandlerFunc(s.workingSetDeleteHandler),
+			})),
+		)
+	}
+
+	if s.contentStats != nil {
+		handle("/stats/content/{reference}", web.ChainHandlers(
+			web.FinalHandler(jsonhttp.MethodHandler{
+				"GET": http.HandlerFunc(s.contentStatsGetHandler),
+			})),
+		)
+	}
+
+	handle("/content/{reference}", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"DELETE": http.HandlerFunc(s.contentDeleteHandler),
+		})),
+	)
+
+	handle("/receipts/{reference}", web.ChainHandlers(
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.receiptsGetHandler),
+		})),
+	)
+
+	handle("/handoff/{address}/{peer}", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.newTracingHandler("handoff"),
+			web.FinalHandlerFunc(s.handoffPostHandler),
+		),
+	})
+
 	handle("/stewardship/{address}", jsonhttp.MethodHandler{
 		"GET": web.ChainHandlers(
 			web.FinalHandlerFunc(s.stewardshipGetHandler),
@@ -343,19 +453,53 @@ func (s *Service) mountAPI() {
 	}
 }
 
+// mountProfiling mounts the runtime profiling endpoints and the exported
+// variables, which expose the command line of the node, with the handlers
+// wrapped by the middleware if it is set.
+func (s *Service) mountProfiling(middleware func(http.Handler) http.Handler) {
+	handle := func(h http.Handler) http.Handler {
+		if middleware == nil {
+			return h
+		}
+		return middleware(h)
+	}
+
+	s.router.Handle("/debug/pprof", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := r.URL
+		u.Path += "/"
+		http.Redirect(w, r, u.String(), http.StatusPermanentRedirect)
+	}))
+	s.router.Handle("/debug/pprof/cmdline", handle(http.HandlerFunc(pprof.Cmdline)))
+	s.router.Handle("/debug/pprof/profile", handle(http.HandlerFunc(pprof.Profile)))
+	s.router.Handle("/debug/pprof/symbol", handle(http.HandlerFunc(pprof.Symbol)))
+	s.router.Handle("/debug/pprof/trace", handle(http.HandlerFunc(pprof.Trace)))
+	s.router.PathPrefix("/debug/pprof/").Handler(handle(http.HandlerFunc(pprof.Index)))
+
+	s.router.Handle("/debug/vars", handle(expvar.Handler()))
+}
+
 func (s *Service) mountBusinessDebug(restricted bool) {
-	handle := func(path string, handler http.Handler) {
+	// handleVersions registers a route whose path differs between the api versions.
+	handleVersions := func(v1Path, v2Path string, handler http.Handler) {
 		if restricted {
 			handler = web.ChainHandlers(auth.PermissionCheckHandler(s.auth), web.FinalHandler(handler))
 		}
-		s.router.Handle(path, handler)
-		s.router.Handle(rootPath+path, handler)
+		s.handleVersions(v1Path, v2Path, handler)
+	}
+	handle := func(path string, handler http.Handler) {
+		handleVersions(path, path, handler)
 	}
 
 	if s.transaction != nil {
 		handle("/transactions", jsonhttp.MethodHandler{
 			"GET": http.HandlerFunc(s.transactionListHandler),
 		})
+		handle("/transactions/nonce", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.transactionNonceStatusHandler),
+		})
+		handle("/transactions/nonce/resolve", jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.transactionNonceResolveHandler),
+		})
 		handle("/transactions/{hash}", jsonhttp.MethodHandler{
 			"GET":    http.HandlerFunc(s.transactionDetailHandler),
 			"POST":   http.HandlerFunc(s.transactionResendHandler),
@@ -371,10 +515,60 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		"POST": http.HandlerFunc(s.pingpongHandler),
 	})
 
-	handle("/reservestate", jsonhttp.MethodHandler{
+	handleVersions("/reservestate", "/reserve/state", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.reserveStateHandler),
 	})
 
+	handle("/reserve/forecast", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.reserveForecastHandler),
+	})
+
+	handle("/reserve/repair", jsonhttp.MethodHandler{
+		"POST": http.HandlerFunc(s.reserveRepairHandler),
+	})
+
+	handle("/status", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.statusGetHandler),
+	})
+
+	handle("/status/peers", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.statusGetPeersHandler),
+	})
+
+	if s.availability != nil {
+		handle("/availability", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.availabilityGetHandler),
+		})
+	}
+
+	if s.webhooks != nil {
+		handle("/webhooks/deliveries", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.webhookDeliveriesHandler),
+		})
+	}
+
+	if s.prewarm != nil {
+		handle("/prewarm/{address}", jsonhttp.MethodHandler{
+			"GET":  http.HandlerFunc(s.prewarmGetHandler),
+			"POST": http.HandlerFunc(s.prewarmPostHandler),
+		})
+	}
+
+	if s.denylist != nil {
+		handle("/denylist", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.denylistGetHandler),
+		})
+
+		handle("/denylist/{reference}", jsonhttp.MethodHandler{
+			"PUT":    http.HandlerFunc(s.denylistPutHandler),
+			"DELETE": http.HandlerFunc(s.denylistDeleteHandler),
+		})
+	}
+
+	handle("/audit", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.auditGetHandler),
+	})
+
 	handle("/connect/{multi-address:.+}", jsonhttp.MethodHandler{
 		"POST": http.HandlerFunc(s.peerConnectHandler),
 	})
@@ -387,15 +581,27 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		"DELETE": http.HandlerFunc(s.peerDisconnectHandler),
 	})
 
+	handle("/debug/peers/{address}/traffic", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.peerTrafficHandler),
+	})
+
 	handle("/chunks/{address}", jsonhttp.MethodHandler{
 		"GET":    http.HandlerFunc(s.hasChunkHandler),
 		"DELETE": http.HandlerFunc(s.removeChunk),
 	})
 
+	handle("/debug/chunks/{address}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.debugChunkHandler),
+	})
+
 	handle("/topology", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.topologyHandler),
 	})
 
+	handle("/topology/latency", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.topologyLatencyHandler),
+	})
+
 	handle("/welcome-message", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.getWelcomeMessageHandler),
 		"POST": web.ChainHandlers(
@@ -448,6 +654,14 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 				web.FinalHandlerFunc(s.swapCashoutHandler),
 			),
 		})
+
+		handle("/chequebook/bounced/{peer}", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.swapBouncedPeerHandler),
+		})
+
+		handle("/chequebook/bounced", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.swapBouncedAllHandler),
+		})
 	}
 
 	if s.chequebookEnabled {
@@ -473,6 +687,10 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 			),
 		})
 
+		handle("/chequebook/withdrawal", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.chequebookWithdrawalHandler),
+		})
+
 		if s.swapEnabled {
 			handle("/wallet", jsonhttp.MethodHandler{
 				"GET": http.HandlerFunc(s.walletHandler),
@@ -487,6 +705,13 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		})),
 	)
 
+	handle("/stamps/restamp", web.ChainHandlers(
+		s.postageSyncStatusCheckHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.restampHandler),
+		})),
+	)
+
 	handle("/stamps/{batch_id}", web.ChainHandlers(
 		s.postageSyncStatusCheckHandler,
 		web.FinalHandler(jsonhttp.MethodHandler{
@@ -501,6 +726,13 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		})),
 	)
 
+	handle("/stamps/{batch_id}/capacity", web.ChainHandlers(
+		s.postageSyncStatusCheckHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.postageGetStampCapacityHandler),
+		})),
+	)
+
 	handle("/stamps/{amount}/{depth}", web.ChainHandlers(
 		s.postageAccessHandler,
 		s.postageSyncStatusCheckHandler,
@@ -534,6 +766,10 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		})),
 	)
 
+	handle("/batches/ws", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.postageEventsWsHandler),
+	})
+
 	handle("/tags/{id}", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.getDebugTagHandler),
 	})
@@ -542,6 +778,24 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 		"GET": http.HandlerFunc(s.accountingInfoHandler),
 	})
 
+	if s.profitability != nil {
+		handle("/accounting/profitability", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.profitabilityHandler),
+		})
+	}
+
+	if s.earnings != nil {
+		handle("/accounting/earnings", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.earningsHandler),
+		})
+	}
+
+	if s.billing != nil {
+		handle("/accounting/snapshots", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.snapshotsHandler),
+		})
+	}
+
 	handle("/readiness", web.ChainHandlers(
 		httpaccess.NewHTTPAccessSuppressLogHandler(),
 		web.FinalHandlerFunc(s.readinessHandler),
@@ -568,8 +822,28 @@ func (s *Service) mountBusinessDebug(restricted bool) {
 			"DELETE": http.HandlerFunc(s.withdrawAllStakeHandler),
 		})),
 	)
-	handle("/redistributionstate", jsonhttp.MethodHandler{
+	handleVersions("/redistributionstate", "/redistribution/state", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.redistributionStatusHandler),
 	},
 	)
+
+	handle("/redistribution/rounds", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.redistributionRoundsHandler),
+	})
+
+	handle("/redistribution/proof/{depth}/{anchor}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.redistributionProofHandler),
+	})
+
+	handle("/profiles/{kind}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.profileGetHandler),
+	})
+
+	if restricted {
+		s.mountProfiling(auth.PermissionCheckHandler(s.auth))
+	}
+
+	handle("/debug/selftest", jsonhttp.MethodHandler{
+		"POST": http.HandlerFunc(s.selfTestPostHandler),
+	})
 }
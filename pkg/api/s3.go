@@ -0,0 +1,576 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/log/httpaccess"
+	"github.com/ethersphere/bee/pkg/manifest/feedwriter"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/s3"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+	"resenje.org/web"
+)
+
+const (
+	s3Namespace    = "http://s3.amazonaws.com/doc/2006-03-01/"
+	s3TimeLayout   = "2006-01-02T15:04:05.000Z"
+	s3StorageClass = "STANDARD"
+)
+
+// s3BucketNameRegexp matches the bucket names allowed by S3.
+var s3BucketNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// s3Error is the error response of the S3 API.
+type s3Error struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource,omitempty"`
+
+	status int
+}
+
+var (
+	s3ErrNoSuchKey         = s3Error{Code: "NoSuchKey", Message: "The specified key does not exist.", status: http.StatusNotFound}
+	s3ErrInvalidBucketName = s3Error{Code: "InvalidBucketName", Message: "The specified bucket is not valid.", status: http.StatusBadRequest}
+	s3ErrInvalidArgument   = s3Error{Code: "InvalidArgument", Message: "Invalid argument.", status: http.StatusBadRequest}
+	s3ErrAccessDenied      = s3Error{Code: "AccessDenied", Message: "Access Denied.", status: http.StatusForbidden}
+	s3ErrNotImplemented    = s3Error{Code: "NotImplemented", Message: "A header or query you provided implies functionality that is not implemented.", status: http.StatusNotImplemented}
+	s3ErrMethodNotAllowed  = s3Error{Code: "MethodNotAllowed", Message: "The specified method is not allowed against this resource.", status: http.StatusMethodNotAllowed}
+	s3ErrInternalError     = s3Error{Code: "InternalError", Message: "We encountered an internal error. Please try again.", status: http.StatusInternalServerError}
+)
+
+// writeS3Error writes the S3 error response for the requested resource.
+func writeS3Error(w http.ResponseWriter, r *http.Request, e s3Error) {
+	e.Resource = r.URL.Path
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(e.status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(e)
+}
+
+// writeS3Response writes the XML encoded S3 response.
+func writeS3Response(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+type s3Object struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type s3CommonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// s3ListBucketResult is the response of both versions of ListObjects,
+// the fields specific to one of the versions are omitted in the other.
+type s3ListBucketResult struct {
+	XMLName               xml.Name         `xml:"ListBucketResult"`
+	Xmlns                 string           `xml:"xmlns,attr"`
+	Name                  string           `xml:"Name"`
+	Prefix                string           `xml:"Prefix"`
+	Marker                *string          `xml:"Marker,omitempty"`
+	NextMarker            string           `xml:"NextMarker,omitempty"`
+	StartAfter            string           `xml:"StartAfter,omitempty"`
+	ContinuationToken     string           `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string           `xml:"NextContinuationToken,omitempty"`
+	KeyCount              *int             `xml:"KeyCount,omitempty"`
+	MaxKeys               int              `xml:"MaxKeys"`
+	Delimiter             string           `xml:"Delimiter,omitempty"`
+	EncodingType          string           `xml:"EncodingType,omitempty"`
+	IsTruncated           bool             `xml:"IsTruncated"`
+	Contents              []s3Object       `xml:"Contents"`
+	CommonPrefixes        []s3CommonPrefix `xml:"CommonPrefixes"`
+}
+
+type s3LocationConstraint struct {
+	XMLName xml.Name `xml:"LocationConstraint"`
+	Xmlns   string   `xml:"xmlns,attr"`
+}
+
+// s3MethodHandler routes the request by its method,
+// responding with the S3 error to the other methods.
+type s3MethodHandler map[string]http.Handler
+
+func (h s3MethodHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, ok := h[r.Method]
+	if !ok {
+		writeS3Error(w, r, s3ErrMethodNotAllowed)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// S3Handler returns the handler of the S3 compatible API, which is served
+// on its own listener as the S3 clients address the buckets from the root
+// path. Only the path-style requests are supported. The requests are
+// authenticated only in the restricted mode, with the bearer security token
+// checked for the path of the request under /s3, otherwise the listener is
+// meant to be reachable only by the trusted clients. Every bucket is the sequence feed of the node with the topic of
+// the bucket name, its updates are the manifests with the objects. The
+// buckets are read-only unless the S3 postage batch is set.
+func (s *Service) S3Handler() http.Handler {
+	router := mux.NewRouter()
+	router.SkipClean(true)
+	router.UseEncodedPath()
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeS3Error(w, r, s3ErrNotImplemented)
+	})
+
+	router.Handle("/{bucket}", s3MethodHandler{
+		http.MethodGet:  http.HandlerFunc(s.s3ListObjectsHandler),
+		http.MethodHead: http.HandlerFunc(s.s3BucketHandler),
+		http.MethodPut:  http.HandlerFunc(s.s3BucketHandler),
+	})
+	router.Handle("/{bucket}/", s3MethodHandler{
+		http.MethodGet:  http.HandlerFunc(s.s3ListObjectsHandler),
+		http.MethodHead: http.HandlerFunc(s.s3BucketHandler),
+		http.MethodPut:  http.HandlerFunc(s.s3BucketHandler),
+	})
+	router.Handle("/{bucket}/{key:.+}", s3MethodHandler{
+		http.MethodGet:    http.HandlerFunc(s.s3GetObjectHandler),
+		http.MethodHead:   http.HandlerFunc(s.s3GetObjectHandler),
+		http.MethodPut:    http.HandlerFunc(s.s3PutObjectHandler),
+		http.MethodDelete: http.HandlerFunc(s.s3DeleteObjectHandler),
+	})
+
+	return web.ChainHandlers(
+		httpaccess.NewHTTPAccessLogHandler(s.logger, s.tracer, "s3 access"),
+		s.newTracingHandler("s3"),
+		s.s3AuthHandler,
+		web.FinalHandler(router),
+	)
+}
+
+// s3AuthHandler checks the security token of the request in the
+// restricted mode, responding with the S3 error if access is not granted.
+func (s *Service) s3AuthHandler(h http.Handler) http.Handler {
+	if !s.Restricted {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(apiKey) == "" {
+			writeS3Error(w, r, s3ErrAccessDenied)
+			return
+		}
+		allowed, err := s.auth.Enforce(strings.TrimSpace(apiKey), "/s3"+r.URL.Path, r.Method)
+		if err != nil {
+			s.logger.Debug("s3: validate security token failed", "error", err)
+			writeS3Error(w, r, s3ErrAccessDenied)
+			return
+		}
+		if !allowed {
+			writeS3Error(w, r, s3ErrAccessDenied)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// s3Request returns the bucket name and the object key of the request.
+func s3Request(r *http.Request) (bucket, key string, err error) {
+	vars := mux.Vars(r)
+	if bucket, err = url.PathUnescape(vars["bucket"]); err != nil {
+		return "", "", err
+	}
+	if !s3BucketNameRegexp.MatchString(bucket) {
+		return "", "", fmt.Errorf("invalid bucket name %q", bucket)
+	}
+	if key, err = url.PathUnescape(vars["key"]); err != nil {
+		return "", "", err
+	}
+	return bucket, key, nil
+}
+
+// s3Unsupported reports whether the request asks for
+// the subresource of the bucket or the object not served.
+func s3Unsupported(r *http.Request, supported ...string) bool {
+	query := r.URL.Query()
+	for _, name := range supported {
+		query.Del(name)
+	}
+	return len(query) > 0
+}
+
+// s3EscapeKey escapes the key listed with the url encoding type,
+// keeping the path separators.
+func s3EscapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// s3Bucket resolves the bucket from the latest update of its feed.
+// The returned index is the index of the next update of the feed.
+func (s *Service) s3Bucket(ctx context.Context, name string) (feed *feeds.Feed, root swarm.Address, next feeds.Index, err error) {
+	if s.signer == nil {
+		return nil, root, nil, errors.New("no signer")
+	}
+	owner, err := s.signer.EthereumAddress()
+	if err != nil {
+		return nil, root, nil, err
+	}
+	topic, err := crypto.LegacyKeccak256([]byte(name))
+	if err != nil {
+		return nil, root, nil, err
+	}
+	feed = feeds.New(topic, owner)
+
+	l, err := s.feedFactory.NewLookup(feeds.Sequence, feed)
+	if err != nil {
+		return nil, root, nil, err
+	}
+	ch, _, next, err := l.At(ctx, time.Now().Unix(), 0)
+	if err != nil {
+		return nil, root, nil, err
+	}
+	if ch == nil {
+		// the bucket has no objects yet
+		return feed, swarm.ZeroAddress, next, nil
+	}
+	root, _, err = parseFeedUpdate(ch)
+	if err != nil {
+		return nil, root, nil, err
+	}
+	return feed, root, next, nil
+}
+
+// s3WritableBucket resolves the bucket which publishes every change
+// as the new update of its feed. The returned function waits for the
+// changed chunks to be synced.
+func (s *Service) s3WritableBucket(r *http.Request, name string) (*s3.Bucket, func() error, error) {
+	if len(s.S3PostageBatch) == 0 {
+		return nil, noopWaitFn, s3.ErrReadOnly
+	}
+	ctx := r.Context()
+	feed, root, next, err := s.s3Bucket(ctx, name)
+	if err != nil {
+		return nil, noopWaitFn, fmt.Errorf("resolve bucket: %w", err)
+	}
+
+	r.Header.Set(SwarmPostageBatchIdHeader, hex.EncodeToString(s.S3PostageBatch))
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		return nil, noopWaitFn, fmt.Errorf("putter: %w", err)
+	}
+	commit, err := feedwriter.FeedCommit(putter, s.signer, feed.Topic, next)
+	if err != nil {
+		return nil, noopWaitFn, err
+	}
+
+	return s3.New(s.storer, root, s3.Options{
+		Storer: putter,
+		Mode:   requestModePut(r),
+		Commit: commit,
+	}), wait, nil
+}
+
+// s3WriteError writes the S3 error of the failed change of the bucket.
+func s3WriteError(w http.ResponseWriter, r *http.Request, logger log.Logger, err error) {
+	switch {
+	case errors.Is(err, s3.ErrReadOnly), errors.Is(err, errBatchNotAllowed):
+		writeS3Error(w, r, s3ErrAccessDenied)
+	case errors.Is(err, errBatchUnusable), errors.Is(err, postage.ErrNotUsable), errors.Is(err, postage.ErrNotFound):
+		logger.Error(nil, "s3: postage batch not usable")
+		writeS3Error(w, r, s3ErrAccessDenied)
+	case errors.Is(err, postage.ErrBucketFull):
+		logger.Error(nil, "s3: postage batch is overissued")
+		writeS3Error(w, r, s3ErrAccessDenied)
+	default:
+		logger.Error(nil, "s3: change bucket failed")
+		writeS3Error(w, r, s3ErrInternalError)
+	}
+}
+
+// s3BucketHandler serves HeadBucket and CreateBucket. The buckets exist
+// implicitly, so every bucket with the valid name is reported to exist.
+func (s *Service) s3BucketHandler(w http.ResponseWriter, r *http.Request) {
+	if _, _, err := s3Request(r); err != nil {
+		writeS3Error(w, r, s3ErrInvalidBucketName)
+		return
+	}
+	if r.Method == http.MethodPut && len(s.S3PostageBatch) == 0 {
+		writeS3Error(w, r, s3ErrAccessDenied)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// s3ListObjectsHandler serves both versions of ListObjects
+// and GetBucketLocation.
+func (s *Service) s3ListObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("s3").Build())
+
+	name, _, err := s3Request(r)
+	if err != nil {
+		writeS3Error(w, r, s3ErrInvalidBucketName)
+		return
+	}
+	query := r.URL.Query()
+	if _, ok := query["location"]; ok {
+		writeS3Response(w, s3LocationConstraint{Xmlns: s3Namespace})
+		return
+	}
+	if s3Unsupported(r, "list-type", "prefix", "delimiter", "marker", "start-after", "continuation-token", "max-keys", "encoding-type", "fetch-owner") {
+		writeS3Error(w, r, s3ErrNotImplemented)
+		return
+	}
+
+	v2 := query.Get("list-type") == "2"
+	opts := s3.ListOptions{
+		Prefix:     query.Get("prefix"),
+		Delimiter:  query.Get("delimiter"),
+		StartAfter: query.Get("marker"),
+		MaxKeys:    s3.DefaultMaxKeys,
+	}
+	if v2 {
+		opts.StartAfter = query.Get("start-after")
+		if token := query.Get("continuation-token"); token != "" {
+			marker, err := base64.URLEncoding.DecodeString(token)
+			if err != nil {
+				writeS3Error(w, r, s3ErrInvalidArgument)
+				return
+			}
+			opts.StartAfter = string(marker)
+		}
+	}
+	if v := query.Get("max-keys"); v != "" {
+		maxKeys, err := strconv.Atoi(v)
+		if err != nil || maxKeys < 0 {
+			writeS3Error(w, r, s3ErrInvalidArgument)
+			return
+		}
+		if maxKeys < opts.MaxKeys {
+			opts.MaxKeys = maxKeys
+		}
+	}
+	encode := func(s string) string { return s }
+	if query.Get("encoding-type") == "url" {
+		encode = s3EscapeKey
+	}
+
+	res := &s3.ListResult{}
+	if opts.MaxKeys > 0 {
+		_, root, _, err := s.s3Bucket(r.Context(), name)
+		if err != nil {
+			logger.Debug("s3: resolve bucket failed", "bucket", name, "error", err)
+			logger.Error(nil, "s3: resolve bucket failed")
+			writeS3Error(w, r, s3ErrInternalError)
+			return
+		}
+		res, err = s3.New(s.storer, root, s3.Options{}).List(r.Context(), opts)
+		if err != nil {
+			logger.Debug("s3: list objects failed", "bucket", name, "error", err)
+			logger.Error(nil, "s3: list objects failed")
+			writeS3Error(w, r, s3ErrInternalError)
+			return
+		}
+	}
+
+	out := s3ListBucketResult{
+		Xmlns:       s3Namespace,
+		Name:        name,
+		Prefix:      encode(opts.Prefix),
+		MaxKeys:     opts.MaxKeys,
+		Delimiter:   encode(opts.Delimiter),
+		IsTruncated: res.IsTruncated,
+	}
+	if query.Get("encoding-type") == "url" {
+		out.EncodingType = "url"
+	}
+	for _, o := range res.Objects {
+		out.Contents = append(out.Contents, s3Object{
+			Key:          encode(o.Key),
+			LastModified: o.LastModified.Format(s3TimeLayout),
+			ETag:         strconv.Quote(o.ETag),
+			Size:         o.Size,
+			StorageClass: s3StorageClass,
+		})
+	}
+	for _, p := range res.CommonPrefixes {
+		out.CommonPrefixes = append(out.CommonPrefixes, s3CommonPrefix{Prefix: encode(p)})
+	}
+	if v2 {
+		keyCount := len(res.Objects) + len(res.CommonPrefixes)
+		out.KeyCount = &keyCount
+		out.StartAfter = encode(query.Get("start-after"))
+		out.ContinuationToken = query.Get("continuation-token")
+		if res.IsTruncated {
+			out.NextContinuationToken = base64.URLEncoding.EncodeToString([]byte(res.NextMarker))
+		}
+	} else {
+		marker := encode(query.Get("marker"))
+		out.Marker = &marker
+		if res.IsTruncated {
+			out.NextMarker = encode(res.NextMarker)
+		}
+	}
+	writeS3Response(w, out)
+}
+
+// s3GetObjectHandler serves GetObject and HeadObject.
+func (s *Service) s3GetObjectHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("s3").Build())
+
+	name, key, err := s3Request(r)
+	if err != nil {
+		writeS3Error(w, r, s3ErrInvalidBucketName)
+		return
+	}
+	if s3Unsupported(r, "versionId", "x-id") {
+		writeS3Error(w, r, s3ErrNotImplemented)
+		return
+	}
+
+	_, root, _, err := s.s3Bucket(r.Context(), name)
+	if err != nil {
+		logger.Debug("s3: resolve bucket failed", "bucket", name, "error", err)
+		logger.Error(nil, "s3: resolve bucket failed")
+		writeS3Error(w, r, s3ErrInternalError)
+		return
+	}
+	o, reader, err := s3.New(s.storer, root, s3.Options{}).Get(r.Context(), key)
+	if errors.Is(err, s3.ErrNoSuchKey) {
+		writeS3Error(w, r, s3ErrNoSuchKey)
+		return
+	}
+	if err != nil {
+		logger.Debug("s3: get object failed", "bucket", name, "key", key, "error", err)
+		logger.Error(nil, "s3: get object failed")
+		writeS3Error(w, r, s3ErrInternalError)
+		return
+	}
+
+	w.Header().Set("ETag", strconv.Quote(o.ETag))
+	if o.ContentType != "" {
+		w.Header().Set("Content-Type", o.ContentType)
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	http.ServeContent(w, r, "", o.LastModified, io.NewSectionReader(reader, 0, o.Size))
+}
+
+// s3PutObjectHandler serves PutObject, publishing
+// the changed manifest as the new update of the bucket.
+func (s *Service) s3PutObjectHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("s3").Build())
+
+	name, key, err := s3Request(r)
+	if err != nil {
+		writeS3Error(w, r, s3ErrInvalidBucketName)
+		return
+	}
+	if s3Unsupported(r, "x-id") || r.Header.Get("x-amz-copy-source") != "" {
+		writeS3Error(w, r, s3ErrNotImplemented)
+		return
+	}
+
+	var body io.Reader = r.Body
+	if s3.IsChunked(r.Header.Get("Content-Encoding"), r.Header.Get("x-amz-content-sha256")) {
+		body = s3.NewChunkedReader(r.Body)
+	}
+
+	// the changes of the bucket are serialized,
+	// as every change depends on the previous update
+	s.s3Mu.Lock()
+	defer s.s3Mu.Unlock()
+
+	b, wait, err := s.s3WritableBucket(r, name)
+	if err != nil {
+		logger.Debug("s3: put object failed", "bucket", name, "key", key, "error", err)
+		s3WriteError(w, r, logger, err)
+		return
+	}
+	o, err := b.Put(r.Context(), key, body, r.Header.Get("Content-Type"))
+	if err != nil {
+		logger.Debug("s3: put object failed", "bucket", name, "key", key, "error", err)
+		s3WriteError(w, r, logger, err)
+		return
+	}
+	if err := wait(); err != nil {
+		logger.Debug("s3: sync chunks failed", "error", err)
+		logger.Error(nil, "s3: sync chunks failed")
+		writeS3Error(w, r, s3ErrInternalError)
+		return
+	}
+	s.watchReceipts(logger, b.Root())
+
+	w.Header().Set("ETag", strconv.Quote(o.ETag))
+	w.WriteHeader(http.StatusOK)
+}
+
+// s3DeleteObjectHandler serves DeleteObject, publishing
+// the changed manifest as the new update of the bucket.
+func (s *Service) s3DeleteObjectHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("s3").Build())
+
+	name, key, err := s3Request(r)
+	if err != nil {
+		writeS3Error(w, r, s3ErrInvalidBucketName)
+		return
+	}
+	if s3Unsupported(r, "x-id") {
+		writeS3Error(w, r, s3ErrNotImplemented)
+		return
+	}
+
+	s.s3Mu.Lock()
+	defer s.s3Mu.Unlock()
+
+	b, wait, err := s.s3WritableBucket(r, name)
+	if err != nil {
+		logger.Debug("s3: delete object failed", "bucket", name, "key", key, "error", err)
+		s3WriteError(w, r, logger, err)
+		return
+	}
+	root := b.Root()
+	if err := b.Delete(r.Context(), key); err != nil {
+		logger.Debug("s3: delete object failed", "bucket", name, "key", key, "error", err)
+		s3WriteError(w, r, logger, err)
+		return
+	}
+	if err := wait(); err != nil {
+		logger.Debug("s3: sync chunks failed", "error", err)
+		logger.Error(nil, "s3: sync chunks failed")
+		writeS3Error(w, r, s3ErrInternalError)
+		return
+	}
+	if !b.Root().Equal(root) {
+		s.watchReceipts(logger, b.Root())
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
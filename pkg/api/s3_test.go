@@ -0,0 +1,256 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	mockauth "github.com/ethersphere/bee/pkg/auth/mock"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+)
+
+type s3ListResult struct {
+	Contents []struct {
+		Key  string
+		ETag string
+		Size int64
+	}
+	CommonPrefixes []struct {
+		Prefix string
+	}
+	IsTruncated           bool
+	KeyCount              int
+	NextContinuationToken string
+}
+
+func (r s3ListResult) keys() []string {
+	var keys []string
+	for _, c := range r.Contents {
+		keys = append(keys, c.Key)
+	}
+	for _, p := range r.CommonPrefixes {
+		keys = append(keys, p.Prefix)
+	}
+	return keys
+}
+
+// nolint:paralleltest
+func TestS3(t *testing.T) {
+	var (
+		storer  = mock.NewStorer()
+		pk, _   = crypto.GenerateSecp256k1Key()
+		options = testServerOptions{
+			Storer: storer,
+			Logger: log.Noop,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+			Feeds:  factory.New(storer),
+			Signer: crypto.NewDefaultSigner(pk),
+			S3:     true,
+		}
+		readOnly, _, _, _ = newTestServer(t, options)
+	)
+	options.S3PostageBatch = batchOk
+	client, _, _, _ := newTestServer(t, options)
+
+	list := func(t *testing.T, query string) s3ListResult {
+		t.Helper()
+
+		var (
+			body []byte
+			res  s3ListResult
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup?"+query, http.StatusOK,
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		if err := xml.Unmarshal(body, &res); err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	t.Run("invalid bucket", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/Not_Valid/a.txt", http.StatusBadRequest)
+	})
+
+	t.Run("empty bucket", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPut, "/backup", http.StatusOK)
+		jsonhttptest.Request(t, client, http.MethodHead, "/backup", http.StatusOK)
+		if keys := list(t, "").keys(); len(keys) != 0 {
+			t.Fatalf("got keys %v, want none", keys)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup/a.txt", http.StatusNotFound)
+	})
+
+	t.Run("put object", func(t *testing.T) {
+		header := jsonhttptest.Request(t, client, http.MethodPut, "/backup/a.txt", http.StatusOK,
+			jsonhttptest.WithRequestBody(strings.NewReader("alpha")),
+		)
+		// md5 of the content
+		if got, want := header.Get("ETag"), `"2c1743a391305fbf367df8e4f069f9f9"`; got != want {
+			t.Fatalf("got etag %s, want %s", got, want)
+		}
+		jsonhttptest.Request(t, client, http.MethodPut, "/backup/docs/b%20c.txt", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Content-Type", "text/plain"),
+			jsonhttptest.WithRequestBody(strings.NewReader("beta")),
+		)
+		jsonhttptest.Request(t, client, http.MethodPut, "/backup/docs", http.StatusOK,
+			jsonhttptest.WithRequestBody(strings.NewReader("gamma")),
+		)
+	})
+
+	t.Run("chunked object", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPut, "/backup/chunked", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Content-Encoding", "aws-chunked"),
+			jsonhttptest.WithRequestHeader("x-amz-content-sha256", "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"),
+			jsonhttptest.WithRequestBody(strings.NewReader("3;chunk-signature=aa\r\ndel\r\n2;chunk-signature=bb\r\nta\r\n0;chunk-signature=cc\r\n\r\n")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup/chunked", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("delta")),
+		)
+	})
+
+	t.Run("get object", func(t *testing.T) {
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/backup/docs/b%20c.txt", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("beta")),
+		)
+		if got := header.Get("Content-Type"); got != "text/plain" {
+			t.Fatalf("got content type %s, want text/plain", got)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup/docs", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("gamma")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup/a.txt", http.StatusPartialContent,
+			jsonhttptest.WithRequestHeader("Range", "bytes=1-3"),
+			jsonhttptest.WithExpectedResponse([]byte("lph")),
+		)
+		jsonhttptest.Request(t, readOnly, http.MethodHead, "/backup/a.txt", http.StatusOK)
+	})
+
+	t.Run("list objects", func(t *testing.T) {
+		got := list(t, "").keys()
+		want := []string{"a.txt", "chunked", "docs", "docs/b c.txt"}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got keys %v, want %v", got, want)
+		}
+
+		got = list(t, "delimiter=/").keys()
+		want = []string{"a.txt", "chunked", "docs", "docs/"}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got keys %v, want %v", got, want)
+		}
+
+		got = list(t, "prefix=docs/&encoding-type=url").keys()
+		want = []string{"docs/b%20c.txt"}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got keys %v, want %v", got, want)
+		}
+
+		var pages []string
+		res := list(t, "list-type=2&max-keys=3")
+		for {
+			if res.KeyCount != len(res.keys()) {
+				t.Fatalf("got key count %d, want %d", res.KeyCount, len(res.keys()))
+			}
+			pages = append(pages, strings.Join(res.keys(), ","))
+			if !res.IsTruncated {
+				break
+			}
+			res = list(t, "list-type=2&max-keys=3&continuation-token="+res.NextContinuationToken)
+		}
+		if want := []string{"a.txt,chunked,docs", "docs/b c.txt"}; !reflect.DeepEqual(pages, want) {
+			t.Fatalf("got pages %v, want %v", pages, want)
+		}
+	})
+
+	t.Run("delete object", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodDelete, "/backup/docs", http.StatusNoContent)
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup/docs", http.StatusNotFound)
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup/docs/b%20c.txt", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("beta")),
+		)
+		jsonhttptest.Request(t, client, http.MethodDelete, "/backup/missing", http.StatusNoContent)
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		jsonhttptest.Request(t, readOnly, http.MethodPut, "/backup/e.txt", http.StatusForbidden,
+			jsonhttptest.WithRequestBody(strings.NewReader("epsilon")),
+		)
+		jsonhttptest.Request(t, readOnly, http.MethodDelete, "/backup/a.txt", http.StatusForbidden)
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/backup/a.txt?uploads", http.StatusMethodNotAllowed)
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup?versioning", http.StatusNotImplemented)
+		jsonhttptest.Request(t, client, http.MethodPut, "/backup/f.txt", http.StatusNotImplemented,
+			jsonhttptest.WithRequestHeader("x-amz-copy-source", "/backup/a.txt"),
+		)
+	})
+}
+
+// nolint:paralleltest
+func TestS3Restricted(t *testing.T) {
+	const token = "creator-token"
+
+	var (
+		storer = mock.NewStorer()
+		pk, _  = crypto.GenerateSecp256k1Key()
+		paths  []string
+	)
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:         storer,
+		Logger:         log.Noop,
+		Post:           mockpost.New(mockpost.WithAcceptAll()),
+		Feeds:          factory.New(storer),
+		Signer:         crypto.NewDefaultSigner(pk),
+		S3:             true,
+		S3PostageBatch: batchOk,
+		Restricted:     true,
+		Authenticator: &mockauth.Auth{
+			EnforceFunc: func(apiKey, path, _ string) (bool, error) {
+				paths = append(paths, path)
+				return apiKey == token, nil
+			},
+		},
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPut, "/backup/a.txt", http.StatusForbidden,
+			jsonhttptest.WithRequestBody(strings.NewReader("alpha")),
+		)
+		jsonhttptest.Request(t, client, http.MethodDelete, "/backup/a.txt", http.StatusForbidden)
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup/a.txt", http.StatusForbidden)
+	})
+
+	t.Run("not allowed", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPut, "/backup/a.txt", http.StatusForbidden,
+			jsonhttptest.WithRequestHeader("Authorization", "Bearer other-token"),
+			jsonhttptest.WithRequestBody(strings.NewReader("alpha")),
+		)
+	})
+
+	t.Run("authenticated", func(t *testing.T) {
+		paths = nil
+		jsonhttptest.Request(t, client, http.MethodPut, "/backup/a.txt", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Authorization", "Bearer "+token),
+			jsonhttptest.WithRequestBody(strings.NewReader("alpha")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/backup/a.txt", http.StatusOK,
+			jsonhttptest.WithRequestHeader("Authorization", "Bearer "+token),
+			jsonhttptest.WithExpectedResponse([]byte("alpha")),
+		)
+		if want := []string{"/s3/backup/a.txt", "/s3/backup/a.txt"}; !reflect.DeepEqual(paths, want) {
+			t.Fatalf("got enforced paths %v, want %v", paths, want)
+		}
+	})
+}
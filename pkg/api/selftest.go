@@ -0,0 +1,111 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/selftest"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+)
+
+type selfTestStageResponse struct {
+	Name     string `json:"name"`
+	Duration int64  `json:"duration"` // in nanoseconds
+	Error    string `json:"error,omitempty"`
+}
+
+type selfTestResponse struct {
+	OK        bool                    `json:"ok"`
+	Reference swarm.Address           `json:"reference"`
+	Size      int                     `json:"size"`
+	Chunks    int                     `json:"chunks"`
+	Receipts  int                     `json:"receipts"`
+	Retrieved int                     `json:"retrieved"`
+	Stages    []selfTestStageResponse `json:"stages"`
+}
+
+// selfTestPostHandler uploads the random payload stamped by the batch of the
+// request, pushes it to the network and retrieves it back, and reports the
+// timings of the stages. The failed stage is reported in the response.
+func (s *Service) selfTestPostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("post_selftest").Build())
+
+	queries := struct {
+		Size int `map:"size" validate:"omitempty,min=1"`
+	}{
+		Size: selftest.DefaultSize,
+	}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	if s.beeMode == DevMode {
+		jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		return
+	}
+
+	batch, err := requestPostageBatchId(r)
+	if err != nil {
+		logger.Debug("self-test: postage batch id", "error", err)
+		jsonhttp.BadRequest(w, "invalid batch id")
+		return
+	}
+	stamper, save, err := s.batchStamper(batch)
+	if err != nil {
+		logger.Debug("self-test: batch stamper", "batch_id", swarm.NewAddress(batch), "error", err)
+		switch {
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		default:
+			logger.Error(nil, "self-test: batch stamper failed")
+			jsonhttp.InternalServerError(w, "self-test failed")
+		}
+		return
+	}
+
+	report, err := s.selfTest.Run(r.Context(), stamper, queries.Size)
+	if err != nil {
+		logger.Debug("self-test failed", "size", queries.Size, "error", err)
+		if errors.Is(err, selftest.ErrInvalidSize) {
+			jsonhttp.BadRequest(w, "invalid payload size")
+			return
+		}
+		logger.Error(nil, "self-test failed")
+		jsonhttp.InternalServerError(w, "self-test failed")
+		return
+	}
+	if err := save(); err != nil {
+		logger.Debug("self-test: save stamp issuer", "batch_id", swarm.NewAddress(batch), "error", err)
+		logger.Error(nil, "self-test: save stamp issuer failed")
+	}
+
+	stages := make([]selfTestStageResponse, len(report.Stages))
+	for i, stage := range report.Stages {
+		stages[i] = selfTestStageResponse{
+			Name:     stage.Name,
+			Duration: stage.Duration.Nanoseconds(),
+		}
+		if stage.Err != nil {
+			stages[i].Error = stage.Err.Error()
+		}
+	}
+	jsonhttp.OK(w, selfTestResponse{
+		OK:        !report.Failed(),
+		Reference: report.Reference,
+		Size:      report.Size,
+		Chunks:    report.Chunks,
+		Receipts:  report.Receipts,
+		Retrieved: report.Retrieved,
+		Stages:    stages,
+	})
+}
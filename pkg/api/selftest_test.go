@@ -0,0 +1,94 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/selftest"
+	"github.com/ethersphere/bee/pkg/selftest/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestSelfTest(t *testing.T) {
+	t.Parallel()
+
+	reference := swarm.RandAddress(t)
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		Post:     mockpost.New(mockpost.WithAcceptAll()),
+		SelfTest: mock.SelfTest(func(_ context.Context, stamper postage.Stamper, size int) (*selftest.Report, error) {
+			if size > selftest.MaxSize {
+				return nil, selftest.ErrInvalidSize
+			}
+			if _, err := stamper.Stamp(reference); err != nil {
+				return nil, err
+			}
+			return &selftest.Report{
+				Reference: reference,
+				Size:      size,
+				Chunks:    2,
+				Receipts:  2,
+				Stages: []selftest.Stage{
+					{Name: selftest.StageUpload, Duration: time.Millisecond},
+					{Name: selftest.StagePushSync, Duration: time.Second},
+					{Name: selftest.StageRetrieval, Duration: time.Second, Err: errors.New("retrieval failed")},
+				},
+			}, nil
+		}),
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/debug/selftest?size=5000", http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(api.SelfTestResponse{
+				OK:        false,
+				Reference: reference,
+				Size:      5000,
+				Chunks:    2,
+				Receipts:  2,
+				Stages: []api.SelfTestStageResponse{
+					{Name: selftest.StageUpload, Duration: time.Millisecond.Nanoseconds()},
+					{Name: selftest.StagePushSync, Duration: time.Second.Nanoseconds()},
+					{Name: selftest.StageRetrieval, Duration: time.Second.Nanoseconds(), Error: "retrieval failed"},
+				},
+			}),
+		)
+	})
+
+	t.Run("invalid batch", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/debug/selftest", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid batch id",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/debug/selftest?size=100000000", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid payload size",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
@@ -5,15 +5,20 @@
 package api
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/feeds/validation"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/gorilla/mux"
 )
@@ -112,6 +117,28 @@ func (s *Service) socUploadHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.Conflict(w, "chunk already exists")
 		return
 	}
+
+	// the feed update is validated before it is stamped,
+	// so that no stamp is spent on the malformed update
+	if s.feedValidator != nil {
+		err := s.feedValidator.Validate(ctx, validation.Update{
+			Owner:   paths.Owner,
+			ID:      paths.ID,
+			Span:    binary.LittleEndian.Uint64(data[:swarm.SpanSize]),
+			Payload: data[swarm.SpanSize:],
+		})
+		if err != nil {
+			logger.Debug("feed update validation failed", "owner", hex.EncodeToString(paths.Owner), "id", hex.EncodeToString(paths.ID), "error", err)
+			if errors.Is(err, validation.ErrRejected) {
+				jsonhttp.UnprocessableEntity(w, err.Error())
+				return
+			}
+			logger.Error(nil, "feed update validation failed")
+			jsonhttp.BadGateway(w, "feed update validation failed")
+			return
+		}
+	}
+
 	batch, err := requestPostageBatchId(r)
 	if err != nil {
 		logger.Debug("mapStructure postage batch id failed", "error", err)
@@ -119,6 +146,12 @@ func (s *Service) socUploadHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.BadRequest(w, "invalid postage batch id")
 		return
 	}
+	if !requestTenant(ctx).allowsBatch(batch) {
+		logger.Debug("batch not allowed", "batch_id", hex.EncodeToString(batch))
+		logger.Error(nil, "batch not allowed")
+		jsonhttp.Forbidden(w, "batch not allowed")
+		return
+	}
 
 	i, save, err := s.post.GetStampIssuer(batch)
 	if err != nil {
@@ -147,7 +180,7 @@ func (s *Service) socUploadHandler(w http.ResponseWriter, r *http.Request) {
 		logger.Error(nil, "stamp failed")
 		switch {
 		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
+			jsonhttp.PaymentRequired(w, newBucketFullResponse(err))
 		default:
 			jsonhttp.InternalServerError(w, "stamp error")
 		}
@@ -166,6 +199,10 @@ func (s *Service) socUploadHandler(w http.ResponseWriter, r *http.Request) {
 		if err := s.pinning.CreatePin(ctx, sch.Address(), false); err != nil {
 			logger.Debug("create pin failed", "chunk_address", sch.Address(), "error", err)
 			logger.Error(nil, "create pin failed")
+			if errors.Is(err, errPinQuotaExceeded) {
+				jsonhttp.Forbidden(w, "pin quota exceeded")
+				return
+			}
 			jsonhttp.InternalServerError(w, "creation of pin failed")
 			return
 		}
@@ -173,3 +210,63 @@ func (s *Service) socUploadHandler(w http.ResponseWriter, r *http.Request) {
 
 	jsonhttp.Created(w, chunkAddressResponse{Reference: sch.Address()})
 }
+
+// socGetHandler derives the address of the single owner chunk from its owner
+// and identifier, verifies the signature of the retrieved chunk and returns
+// the payload of the wrapped chunk.
+func (s *Service) socGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_soc").Build()
+	loggerV1 := logger.V(1).Build()
+
+	paths := struct {
+		Owner []byte `map:"owner" validate:"required,len=20"`
+		ID    []byte `map:"id" validate:"required,len=32"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	address, err := soc.CreateAddress(paths.ID, paths.Owner)
+	if err != nil {
+		logger.Debug("create soc address failed", "id", paths.ID, "owner", paths.Owner, "error", err)
+		logger.Error(nil, "create soc address failed")
+		jsonhttp.InternalServerError(w, "create soc address failed")
+		return
+	}
+
+	ch, err := s.storer.Get(r.Context(), storage.ModeGetRequest, address)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			loggerV1.Debug("soc not found", "address", address)
+			jsonhttp.NotFound(w, "soc not found")
+			return
+		}
+		logger.Debug("read soc failed", "address", address, "error", err)
+		logger.Error(nil, "read soc failed")
+		jsonhttp.InternalServerError(w, "read soc failed")
+		return
+	}
+
+	// the address is derived from the requested owner and identifier,
+	// so a valid signature also proves the ownership of the chunk
+	if !soc.Valid(ch) {
+		logger.Debug("invalid soc", "address", address)
+		logger.Error(nil, "invalid soc")
+		jsonhttp.InternalServerError(w, "invalid soc")
+		return
+	}
+	sch, err := soc.FromChunk(ch)
+	if err != nil {
+		logger.Debug("parse soc failed", "address", address, "error", err)
+		logger.Error(nil, "parse soc failed")
+		jsonhttp.InternalServerError(w, "invalid soc")
+		return
+	}
+
+	w.Header().Set(SwarmSocOwnerHeader, hex.EncodeToString(paths.Owner))
+	w.Header().Set(SwarmSocIdHeader, hex.EncodeToString(paths.ID))
+	w.Header().Set("Access-Control-Expose-Headers", fmt.Sprintf("%s, %s", SwarmSocOwnerHeader, SwarmSocIdHeader))
+	w.Header().Set("Content-Type", "binary/octet-stream")
+	_, _ = io.Copy(w, bytes.NewReader(sch.WrappedChunk().Data()[swarm.SpanSize:]))
+}
@@ -6,7 +6,9 @@ package api_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -14,13 +16,16 @@ import (
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/feeds/validation"
+	mockvalidation "github.com/ethersphere/bee/pkg/feeds/validation/mock"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/soc"
 	testingsoc "github.com/ethersphere/bee/pkg/soc/testing"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
@@ -31,9 +36,8 @@ func TestSOC(t *testing.T) {
 	var (
 		testData        = []byte("foo")
 		socResource     = func(owner, id, sig string) string { return fmt.Sprintf("/soc/%s/%s?sig=%s", owner, id, sig) }
-		mockStatestore  = statestore.NewStateStore()
 		logger          = log.Noop
-		tag             = tags.NewTags(mockStatestore, logger)
+		tag             = tags.NewTags(nil, logger)
 		mp              = mockpost.New(mockpost.WithIssuer(postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)))
 		mockStorer      = mock.NewStorer()
 		client, _, _, _ = newTestServer(t, testServerOptions{
@@ -149,3 +153,144 @@ func TestSOC(t *testing.T) {
 		})
 	})
 }
+
+func TestSOCFeedValidation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		testData    = []byte("foo")
+		socResource = func(owner, id, sig string) string { return fmt.Sprintf("/soc/%s/%s?sig=%s", owner, id, sig) }
+		errHook     = errors.New("hook error")
+	)
+
+	for _, tc := range []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		{
+			name:       "accepted",
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "rejected",
+			err:        fmt.Errorf("%w: missing field", validation.ErrRejected),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantMsg:    "feed update rejected: missing field",
+		},
+		{
+			name:       "hook failed",
+			err:        fmt.Errorf("%w: %v", validation.ErrUnavailable, errHook),
+			wantStatus: http.StatusBadGateway,
+			wantMsg:    "feed update validation failed",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := testingsoc.GenerateMockSOC(t, testData)
+			var got validation.Update
+			client, _, _, _ := newTestServer(t, testServerOptions{
+				Storer: mock.NewStorer(),
+				Tags:   tags.NewTags(nil, log.Noop),
+				Post:   mockpost.New(mockpost.WithIssuer(postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true))),
+				FeedValidator: mockvalidation.Validator(func(_ context.Context, u validation.Update) error {
+					got = u
+					return tc.err
+				}),
+			})
+
+			opts := []jsonhttptest.Option{
+				jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithRequestBody(bytes.NewReader(s.WrappedChunk.Data())),
+			}
+			if tc.wantMsg != "" {
+				opts = append(opts, jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+					Message: tc.wantMsg,
+					Code:    tc.wantStatus,
+				}))
+			}
+			jsonhttptest.Request(t, client, http.MethodPost, socResource(hex.EncodeToString(s.Owner), hex.EncodeToString(s.ID), hex.EncodeToString(s.Signature)), tc.wantStatus, opts...)
+
+			if !bytes.Equal(got.Owner, s.Owner) || !bytes.Equal(got.ID, s.ID) {
+				t.Fatalf("got update of owner %x and id %x, want %x and %x", got.Owner, got.ID, s.Owner, s.ID)
+			}
+			if got.Span != uint64(len(testData)) || !bytes.Equal(got.Payload, testData) {
+				t.Fatalf("got update of span %d and payload %q, want %d and %q", got.Span, got.Payload, len(testData), testData)
+			}
+		})
+	}
+}
+
+func TestSOCGet(t *testing.T) {
+	t.Parallel()
+
+	var (
+		testData        = []byte("foo")
+		socResource     = func(owner, id string) string { return fmt.Sprintf("/soc/%s/%s", owner, id) }
+		mockStorer      = mock.NewStorer()
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: mockStorer,
+		})
+	)
+
+	s := testingsoc.GenerateMockSOC(t, testData)
+	if _, err := mockStorer.Put(context.Background(), storage.ModePutUpload, s.Chunk()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, socResource(hex.EncodeToString(s.Owner), hex.EncodeToString(s.ID)), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(testData),
+		)
+		if got := header.Get(api.SwarmSocOwnerHeader); got != hex.EncodeToString(s.Owner) {
+			t.Fatalf("got owner header %q, want %q", got, hex.EncodeToString(s.Owner))
+		}
+		if got := header.Get(api.SwarmSocIdHeader); got != hex.EncodeToString(s.ID) {
+			t.Fatalf("got id header %q, want %q", got, hex.EncodeToString(s.ID))
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		other := testingsoc.GenerateMockSOC(t, testData)
+		jsonhttptest.Request(t, client, http.MethodGet, socResource(hex.EncodeToString(other.Owner), hex.EncodeToString(other.ID)), http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "soc not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("invalid soc", func(t *testing.T) {
+		t.Parallel()
+
+		// a chunk stored under the address which is not signed by the owner
+		id := bytes.Repeat([]byte{1}, swarm.HashSize)
+		address, err := soc.CreateAddress(id, s.Owner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := mockStorer.Put(context.Background(), storage.ModePutUpload, swarm.NewChunk(address, s.Chunk().Data())); err != nil {
+			t.Fatal(err)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, socResource(hex.EncodeToString(s.Owner), hex.EncodeToString(id)), http.StatusInternalServerError,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid soc",
+				Code:    http.StatusInternalServerError,
+			}),
+		)
+	})
+
+	t.Run("bad owner", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, client, http.MethodGet, socResource("abcd", hex.EncodeToString(s.ID)), http.StatusBadRequest)
+	})
+}
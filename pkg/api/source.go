@@ -0,0 +1,257 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+)
+
+// The file of the bzz upload can be fetched by the node from the source URL
+// instead of being sent in the request body, so that the large media hosted
+// elsewhere is not transferred through the client. The fetched resource is
+// capped by the configured maximal size, only the allowed schemes are fetched
+// and the addresses of the private networks are refused, so that the node can
+// not be used to reach the services which are not public.
+
+// SwarmSourceURLQuery is the query parameter of the source URL of the bzz upload.
+const SwarmSourceURLQuery = "source-url"
+
+const (
+	sourceDialTimeout   = 30 * time.Second
+	sourceHeaderTimeout = 30 * time.Second
+	maxSourceRedirects  = 10
+)
+
+var (
+	errSourceURLDisabled    = errors.New("upload from source url not enabled")
+	errSourceURLScheme      = errors.New("source url scheme not allowed")
+	errSourceAddressRefused = errors.New("source address refused")
+	errSourceNotFound       = errors.New("source not found")
+	errSourceUnavailable    = errors.New("source unavailable")
+	errSourceTooLarge       = errors.New("source too large")
+)
+
+// newSourceClient returns the client fetching the sources,
+// refusing the private addresses unless allowPrivate is set.
+// The sources are never fetched through the proxy of the environment,
+// as only the address of the proxy would be checked by the dialer.
+func newSourceClient(schemes []string, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: sourceDialTimeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s", errSourceAddressRefused, host)
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ResponseHeaderTimeout: sourceHeaderTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxSourceRedirects {
+				return errors.New("too many redirects")
+			}
+			if !allowedScheme(req.URL.Scheme, schemes) {
+				return fmt.Errorf("%w: %s", errSourceURLScheme, req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// publicIP reports whether the ip is a public unicast address.
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
+
+func allowedScheme(scheme string, schemes []string) bool {
+	for _, s := range schemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// source is the resource fetched from the source URL.
+type source struct {
+	body        io.ReadCloser
+	contentType string
+	size        int64 // -1 if unknown
+	name        string
+}
+
+// fetchSource fetches the resource of the source URL.
+func (s *Service) fetchSource(ctx context.Context, rawURL string) (*source, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q", errSourceURLScheme, rawURL)
+	}
+	if !allowedScheme(u.Scheme, s.SourceURLSchemes) {
+		return nil, fmt.Errorf("%w: %s", errSourceURLScheme, u.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.sourceClient.Do(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errSourceAddressRefused), errors.Is(err, errSourceURLScheme):
+			return nil, err
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", errSourceUnavailable, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errSourceNotFound
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", errSourceUnavailable, resp.Status)
+	case resp.ContentLength > s.SourceURLMaxSize:
+		resp.Body.Close()
+		return nil, errSourceTooLarge
+	}
+
+	contentType := resp.Header.Get(contentTypeHeader)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	name := path.Base(resp.Request.URL.Path)
+	if name == "/" || name == "." {
+		name = ""
+	}
+	return &source{
+		body:        &sourceReader{r: resp.Body, remaining: s.SourceURLMaxSize},
+		contentType: contentType,
+		size:        resp.ContentLength,
+		name:        name,
+	}, nil
+}
+
+// sourceReader fails with errSourceTooLarge after the maximal
+// size is read and marks the read failures of the source.
+type sourceReader struct {
+	r         io.ReadCloser
+	remaining int64
+}
+
+func (r *sourceReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, errSourceTooLarge
+	}
+	// read a byte over the limit to detect the larger source
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.r.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, errSourceTooLarge
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = fmt.Errorf("%w: %v", errSourceUnavailable, err)
+	}
+	return n, err
+}
+
+func (r *sourceReader) Close() error {
+	return r.r.Close()
+}
+
+// bzzSourceUploadHandler uploads the file fetched from the source URL
+// as if it was sent in the body of the request.
+func (s *Service) bzzSourceUploadHandler(logger log.Logger, w http.ResponseWriter, r *http.Request, sourceURL string) {
+	if s.sourceClient == nil {
+		logger.Debug("upload from source url not enabled", "source_url", sourceURL)
+		jsonhttp.NotImplemented(w, errSourceURLDisabled.Error())
+		return
+	}
+
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debug("putter failed", "error", err)
+		logger.Error(nil, "putter failed")
+		switch {
+		case errors.Is(err, errBatchNotAllowed):
+			jsonhttp.Forbidden(w, "batch not allowed")
+		case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.NotFound(w, "batch with id not found")
+		case errors.Is(err, errInvalidPostageBatch):
+			jsonhttp.BadRequest(w, "invalid batch id")
+		case errors.Is(err, errUnsupportedDevNodeOperation):
+			jsonhttp.BadRequest(w, errUnsupportedDevNodeOperation)
+		case errors.Is(err, clockskew.ErrClockSkewed):
+			jsonhttp.ServiceUnavailable(w, err.Error())
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return
+	}
+
+	src, err := s.fetchSource(r.Context(), sourceURL)
+	if err != nil {
+		logger.Debug("fetch source failed", "source_url", sourceURL, "error", err)
+		logger.Error(nil, "fetch source failed")
+		switch {
+		case errors.Is(err, errSourceURLScheme):
+			jsonhttp.BadRequest(w, errSourceURLScheme.Error())
+		case errors.Is(err, errSourceAddressRefused):
+			jsonhttp.Forbidden(w, errSourceAddressRefused.Error())
+		case errors.Is(err, errSourceNotFound):
+			jsonhttp.NotFound(w, errSourceNotFound.Error())
+		case errors.Is(err, errSourceTooLarge):
+			jsonhttp.RequestEntityTooLarge(w, errSourceTooLarge.Error())
+		case errors.Is(err, context.DeadlineExceeded):
+			jsonhttp.GatewayTimeout(w, "source timed out")
+		default:
+			jsonhttp.BadGateway(w, "fetch source failed")
+		}
+		return
+	}
+	defer src.body.Close()
+
+	q := r.URL.Query()
+	if q.Get("name") == "" && src.name != "" {
+		q.Set("name", src.name)
+		r.URL.RawQuery = q.Encode()
+	}
+	r.Body = src.body
+	r.ContentLength = src.size
+	r.Header.Set(contentTypeHeader, src.contentType)
+	r.Header.Del(SwarmCollectionHeader)
+
+	s.fileUploadHandler(logger, w, r, putter, wait)
+}
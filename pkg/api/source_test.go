@@ -0,0 +1,203 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// nolint:paralleltest
+func TestBzzSourceURL(t *testing.T) {
+	const maxSize = 1024
+
+	var (
+		content = bytes.Repeat([]byte("swarm"), 100)
+		large   = bytes.Repeat([]byte("s"), maxSize+1)
+	)
+
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/media/video.mp4":
+			w.Header().Set("Content-Type", "video/mp4")
+			_, _ = w.Write(content)
+		case "/large":
+			_, _ = w.Write(large)
+		case "/large/streamed":
+			// the size is not known before the body is read
+			for _, b := range large {
+				_, _ = w.Write([]byte{b})
+				w.(http.Flusher).Flush()
+			}
+		case "/failing":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(source.Close)
+
+	newClient := func(t *testing.T, o testServerOptions) *http.Client {
+		t.Helper()
+		o.Storer = mock.NewStorer()
+		o.Tags = tags.NewTags(nil, log.Noop)
+		o.Logger = log.Noop
+		o.Post = mockpost.New(mockpost.WithAcceptAll())
+		client, _, _, _ := newTestServer(t, o)
+		return client
+	}
+
+	client := newClient(t, testServerOptions{
+		SourceURLMaxSize:      maxSize,
+		SourceURLSchemes:      []string{"http"},
+		SourceURLAllowPrivate: true,
+	})
+
+	upload := func(t *testing.T, client *http.Client, sourceURL string, code int, opts ...jsonhttptest.Option) {
+		t.Helper()
+		opts = append(opts, jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr))
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?"+api.SwarmSourceURLQuery+"="+url.QueryEscape(sourceURL), code, opts...)
+	}
+
+	t.Run("upload", func(t *testing.T) {
+		var res api.BzzUploadResponse
+		upload(t, client, source.URL+"/media/video.mp4", http.StatusCreated,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+
+		header := jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+res.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(content),
+		)
+		if got := header.Get("Content-Type"); got != "video/mp4" {
+			t.Fatalf("got content type %q, want %q", got, "video/mp4")
+		}
+		if got := header.Get("Content-Disposition"); !strings.Contains(got, "video.mp4") {
+			t.Fatalf("got content disposition %q, want the source name", got)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		upload(t, client, source.URL+"/large", http.StatusRequestEntityTooLarge,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "source too large",
+				Code:    http.StatusRequestEntityTooLarge,
+			}),
+		)
+	})
+
+	t.Run("too large streamed", func(t *testing.T) {
+		upload(t, client, source.URL+"/large/streamed", http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		upload(t, client, source.URL+"/missing", http.StatusNotFound)
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		upload(t, client, source.URL+"/failing", http.StatusBadGateway)
+	})
+
+	t.Run("scheme not allowed", func(t *testing.T) {
+		upload(t, client, "ftp://example.com/file", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "source url scheme not allowed",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("private address refused", func(t *testing.T) {
+		client := newClient(t, testServerOptions{
+			SourceURLMaxSize: maxSize,
+			SourceURLSchemes: []string{"http"},
+		})
+		upload(t, client, source.URL+"/media/video.mp4", http.StatusForbidden,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "source address refused",
+				Code:    http.StatusForbidden,
+			}),
+		)
+	})
+
+	t.Run("private address refused through proxy", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(content)
+		}))
+		t.Cleanup(proxy.Close)
+		t.Setenv("HTTP_PROXY", proxy.URL)
+		t.Setenv("http_proxy", proxy.URL)
+
+		client := newClient(t, testServerOptions{
+			SourceURLMaxSize: maxSize,
+			SourceURLSchemes: []string{"http"},
+		})
+		for _, sourceURL := range []string{
+			source.URL + "/media/video.mp4",
+			"http://169.254.169.254/latest/meta-data",
+		} {
+			upload(t, client, sourceURL, http.StatusForbidden,
+				jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+					Message: "source address refused",
+					Code:    http.StatusForbidden,
+				}),
+			)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		client := newClient(t, testServerOptions{})
+		upload(t, client, source.URL+"/media/video.mp4", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "upload from source url not enabled",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+}
+
+// nolint:paralleltest
+func TestSourceClientProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("source %s fetched through the proxy", r.URL)
+	}))
+	t.Cleanup(proxy.Close)
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("http_proxy", proxy.URL)
+
+	client := api.NewSourceClient([]string{"http"}, false)
+	for _, tc := range []struct {
+		url  string
+		host string
+	}{
+		{url: "http://127.0.0.1:1633/health", host: "127.0.0.1"},
+		{url: "http://169.254.169.254/latest/meta-data", host: "169.254.169.254"},
+	} {
+		resp, err := client.Get(tc.url)
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("%s: fetched, want refused", tc.url)
+		}
+		if !errors.Is(err, api.ErrSourceAddressRefused) {
+			t.Fatalf("%s: got error %v, want %v", tc.url, err, api.ErrSourceAddressRefused)
+		}
+		// the target must be refused, not the address of the proxy
+		if !strings.Contains(err.Error(), api.ErrSourceAddressRefused.Error()+": "+tc.host) {
+			t.Fatalf("%s: got error %v, want the target refused", tc.url, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/status"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// statusPeerRequestTimeout is the timeout of the status request to a single peer.
+const statusPeerRequestTimeout = 10 * time.Second
+
+// StatusService reports the status of the node and of its peers.
+type StatusService interface {
+	LocalSnapshot() (*status.Snapshot, error)
+	PeerSnapshot(ctx context.Context, peer swarm.Address) (*status.Snapshot, error)
+}
+
+type statusSnapshotResponse struct {
+	Peer            string  `json:"peer"`
+	Proximity       uint8   `json:"proximity"`
+	ReserveSize     uint64  `json:"reserveSize"`
+	StorageRadius   uint8   `json:"storageRadius"`
+	ConnectedPeers  uint64  `json:"connectedPeers"`
+	PullsyncRate    float64 `json:"pullsyncRate"`
+	BatchCommitment uint64  `json:"batchCommitment"`
+	IsHealthy       bool    `json:"isHealthy"`
+	RequestFailed   bool    `json:"requestFailed,omitempty"`
+}
+
+type statusPeersResponse struct {
+	Snapshots []statusSnapshotResponse `json:"snapshots"`
+}
+
+func newStatusSnapshotResponse(peer swarm.Address, po uint8, ss *status.Snapshot) statusSnapshotResponse {
+	return statusSnapshotResponse{
+		Peer:            peer.String(),
+		Proximity:       po,
+		ReserveSize:     ss.ReserveSize,
+		StorageRadius:   ss.StorageRadius,
+		ConnectedPeers:  ss.ConnectedPeers,
+		PullsyncRate:    ss.PullsyncRate,
+		BatchCommitment: ss.BatchCommitment,
+		IsHealthy:       ss.IsHealthy,
+	}
+}
+
+// statusGetHandler returns the status of the node.
+func (s *Service) statusGetHandler(w http.ResponseWriter, _ *http.Request) {
+	logger := s.logger.WithName("get_status").Build()
+
+	if s.statusService == nil {
+		jsonhttp.NotImplemented(w, "status not available")
+		logger.Error(nil, "status not implemented")
+		return
+	}
+
+	ss, err := s.statusService.LocalSnapshot()
+	if err != nil {
+		logger.Debug("status snapshot failed", "error", err)
+		logger.Error(nil, "status snapshot failed")
+		jsonhttp.InternalServerError(w, "status snapshot failed")
+		return
+	}
+
+	var overlay swarm.Address
+	if s.overlay != nil {
+		overlay = *s.overlay
+	}
+	jsonhttp.OK(w, newStatusSnapshotResponse(overlay, 0, ss))
+}
+
+// statusGetPeersHandler requests the status of all the connected peers
+// concurrently. The snapshots are ordered from the closest peers, so that
+// the neighbourhood comes first. The peers which fail to respond are
+// reported with the requestFailed flag set.
+func (s *Service) statusGetPeersHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_status_peers").Build()
+
+	if s.statusService == nil {
+		jsonhttp.NotImplemented(w, "status not available")
+		logger.Error(nil, "status not implemented")
+		return
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		snapshots = make([]statusSnapshotResponse, 0)
+	)
+
+	err := s.topologyDriver.EachPeer(func(peer swarm.Address, po uint8) (bool, bool, error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(r.Context(), statusPeerRequestTimeout)
+			defer cancel()
+
+			snapshot := statusSnapshotResponse{Peer: peer.String(), Proximity: po, RequestFailed: true}
+			ss, err := s.statusService.PeerSnapshot(ctx, peer)
+			if err != nil {
+				logger.Debug("unable to get status snapshot for peer", "peer_address", peer, "error", err)
+			} else {
+				snapshot = newStatusSnapshotResponse(peer, po, ss)
+			}
+
+			mu.Lock()
+			snapshots = append(snapshots, snapshot)
+			mu.Unlock()
+		}()
+		return false, false, nil
+	}, topology.Filter{})
+	wg.Wait()
+	if err != nil {
+		logger.Debug("status snapshot", "error", err)
+		logger.Error(nil, "status snapshot")
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Proximity != snapshots[j].Proximity {
+			return snapshots[i].Proximity > snapshots[j].Proximity
+		}
+		return snapshots[i].Peer < snapshots[j].Peer
+	})
+
+	jsonhttp.OK(w, statusPeersResponse{Snapshots: snapshots})
+}
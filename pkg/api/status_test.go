@@ -0,0 +1,123 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/status"
+	"github.com/ethersphere/bee/pkg/swarm"
+	topologymock "github.com/ethersphere/bee/pkg/topology/mock"
+)
+
+type testStatus struct {
+	local *status.Snapshot
+	peers map[string]*status.Snapshot
+}
+
+var _ api.StatusService = (*testStatus)(nil)
+
+func (s *testStatus) LocalSnapshot() (*status.Snapshot, error) {
+	return s.local, nil
+}
+
+func (s *testStatus) PeerSnapshot(_ context.Context, peer swarm.Address) (*status.Snapshot, error) {
+	ss, ok := s.peers[peer.ByteString()]
+	if !ok {
+		return nil, errors.New("no response")
+	}
+	return ss, nil
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		overlay  = swarm.RandAddress(t)
+		peer1    = swarm.RandAddress(t)
+		peer2    = swarm.RandAddress(t)
+		snapshot = &status.Snapshot{
+			ReserveSize:     1000,
+			StorageRadius:   8,
+			ConnectedPeers:  120,
+			PullsyncRate:    2.5,
+			BatchCommitment: 1 << 20,
+			IsHealthy:       true,
+		}
+	)
+
+	ts, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		Overlay:  overlay,
+		// the mock topology reports the index of the peer as its proximity
+		TopologyOpts: []topologymock.Option{topologymock.WithPeers(peer1, peer2)},
+		Status: &testStatus{
+			local: snapshot,
+			peers: map[string]*status.Snapshot{peer1.ByteString(): snapshot},
+		},
+	})
+
+	t.Run("local", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/status", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.StatusSnapshotResponse{
+				Peer:            overlay.String(),
+				ReserveSize:     1000,
+				StorageRadius:   8,
+				ConnectedPeers:  120,
+				PullsyncRate:    2.5,
+				BatchCommitment: 1 << 20,
+				IsHealthy:       true,
+			}),
+		)
+	})
+
+	t.Run("peers", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/status/peers", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.StatusPeersResponse{
+				Snapshots: []api.StatusSnapshotResponse{
+					{
+						Peer:          peer2.String(),
+						Proximity:     1,
+						RequestFailed: true,
+					},
+					{
+						Peer:            peer1.String(),
+						Proximity:       0,
+						ReserveSize:     1000,
+						StorageRadius:   8,
+						ConnectedPeers:  120,
+						PullsyncRate:    2.5,
+						BatchCommitment: 1 << 20,
+						IsHealthy:       true,
+					},
+				},
+			}),
+		)
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		t.Parallel()
+
+		ts, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+		})
+		jsonhttptest.Request(t, ts, http.MethodGet, "/status/peers", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusNotImplemented,
+				Message: "status not available",
+			}),
+		)
+	})
+}
@@ -36,10 +36,15 @@ func (s *Service) stewardshipPutHandler(w http.ResponseWriter, r *http.Request)
 }
 
 type isRetrievableResponse struct {
-	IsRetrievable bool `json:"isRetrievable"`
+	IsRetrievable bool            `json:"isRetrievable"`
+	Chunks        int             `json:"chunks,omitempty"`
+	Checked       int             `json:"checked,omitempty"`
+	Unretrievable []swarm.Address `json:"unretrievable,omitempty"`
 }
 
 // stewardshipGetHandler checks whether the content on the given address is retrievable.
+// If the sample ratio or the detail is requested, the retrievability of the sampled
+// chunks is checked one by one and the unretrievable ones are optionally listed.
 func (s *Service) stewardshipGetHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("get_stewardship").Build()
 
@@ -51,14 +56,47 @@ func (s *Service) stewardshipGetHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	res, err := s.steward.IsRetrievable(r.Context(), paths.Address)
+	queries := struct {
+		Sample *float64 `map:"sample" validate:"omitempty,gt=0,lte=1"`
+		Detail bool     `map:"detail"`
+	}{}
+	if response := s.mapStructure(r.URL.Query(), &queries); response != nil {
+		response("invalid query params", logger, w)
+		return
+	}
+
+	if queries.Sample == nil && !queries.Detail {
+		res, err := s.steward.IsRetrievable(r.Context(), paths.Address)
+		if err != nil {
+			logger.Debug("is retrievable check failed", "chunk_address", paths.Address, "error", err)
+			logger.Error(nil, "is retrievable")
+			jsonhttp.InternalServerError(w, "is retrievable check failed")
+			return
+		}
+		jsonhttp.OK(w, isRetrievableResponse{
+			IsRetrievable: res,
+		})
+		return
+	}
+
+	sample := 1.0
+	if queries.Sample != nil {
+		sample = *queries.Sample
+	}
+	report, err := s.steward.CheckRetrievable(r.Context(), paths.Address, sample)
 	if err != nil {
-		logger.Debug("is retrievable check failed", "chunk_address", paths.Address, "error", err)
+		logger.Debug("is retrievable check failed", "chunk_address", paths.Address, "sample", sample, "error", err)
 		logger.Error(nil, "is retrievable")
 		jsonhttp.InternalServerError(w, "is retrievable check failed")
 		return
 	}
-	jsonhttp.OK(w, isRetrievableResponse{
-		IsRetrievable: res,
-	})
+	res := isRetrievableResponse{
+		IsRetrievable: len(report.Unretrievable) == 0,
+		Chunks:        report.Chunks,
+		Checked:       report.Checked,
+	}
+	if queries.Detail {
+		res.Unretrievable = report.Unretrievable
+	}
+	jsonhttp.OK(w, res)
 }
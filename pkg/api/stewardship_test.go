@@ -13,7 +13,6 @@ import (
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/steward/mock"
 	smock "github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -23,15 +22,14 @@ import (
 // nolint:paralleltest
 func TestStewardship(t *testing.T) {
 	var (
-		logger         = log.Noop
-		statestoreMock = statestore.NewStateStore()
-		stewardMock    = &mock.Steward{}
-		storer         = smock.NewStorer()
-		addr           = swarm.NewAddress([]byte{31: 128})
+		logger      = log.Noop
+		stewardMock = &mock.Steward{}
+		storer      = smock.NewStorer()
+		addr        = swarm.NewAddress([]byte{31: 128})
 	)
 	client, _, _, _ := newTestServer(t, testServerOptions{
 		Storer:  storer,
-		Tags:    tags.NewTags(statestoreMock, logger),
+		Tags:    tags.NewTags(nil, logger),
 		Logger:  logger,
 		Steward: stewardMock,
 	})
@@ -52,6 +50,9 @@ func TestStewardship(t *testing.T) {
 		jsonhttptest.Request(t, client, http.MethodGet, "/v1/stewardship/"+addr.String(), http.StatusOK,
 			jsonhttptest.WithExpectedJSONResponse(api.IsRetrievableResponse{IsRetrievable: true}),
 		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/v1/stewardship/"+addr.String()+"?sample=0.5&detail=true", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.IsRetrievableResponse{IsRetrievable: true, Chunks: 1, Checked: 1}),
+		)
 		jsonhttptest.Request(t, client, http.MethodGet, "/v1/stewardship/"+hex.EncodeToString([]byte{}), http.StatusNotFound,
 			jsonhttptest.WithExpectedJSONResponse(&jsonhttp.StatusResponse{
 				Code:    http.StatusNotFound,
@@ -61,6 +62,51 @@ func TestStewardship(t *testing.T) {
 	})
 }
 
+func TestStewardshipUnretrievable(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger      = log.Noop
+		stewardMock = &mock.Steward{}
+		storer      = smock.NewStorer()
+		addr        = swarm.NewAddress([]byte{31: 128})
+	)
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Storer:  storer,
+		Tags:    tags.NewTags(nil, logger),
+		Logger:  logger,
+		Steward: stewardMock,
+	})
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/v1/stewardship/"+addr.String()+"?detail=true", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.IsRetrievableResponse{
+			IsRetrievable: false,
+			Chunks:        1,
+			Checked:       1,
+			Unretrievable: []swarm.Address{addr},
+		}),
+	)
+	jsonhttptest.Request(t, client, http.MethodGet, "/v1/stewardship/"+addr.String()+"?sample=0.1", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.IsRetrievableResponse{
+			IsRetrievable: false,
+			Chunks:        1,
+			Checked:       1,
+		}),
+	)
+	jsonhttptest.Request(t, client, http.MethodGet, "/v1/stewardship/"+addr.String()+"?sample=2", http.StatusBadRequest,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid query params",
+			Reasons: []jsonhttp.Reason{
+				{
+					Field: "sample",
+					Error: "want lte:1",
+				},
+			},
+		}),
+	)
+}
+
 func Test_stewardshipHandlers_invalidInputs(t *testing.T) {
 	t.Parallel()
 
@@ -15,7 +15,6 @@ import (
 	"github.com/ethersphere/bee/pkg/log"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
 	resolverMock "github.com/ethersphere/bee/pkg/resolver/mock"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
@@ -97,11 +96,10 @@ func TestSubdomains(t *testing.T) {
 			var (
 				dirUploadResource = "/bzz"
 				storer            = mock.NewStorer()
-				mockStatestore    = statestore.NewStateStore()
 				logger            = log.Noop
 				client, _, _, _   = newTestServer(t, testServerOptions{
 					Storer:          storer,
-					Tags:            tags.NewTags(mockStatestore, logger),
+					Tags:            tags.NewTags(nil, logger),
 					Logger:          logger,
 					PreventRedirect: true,
 					Post:            mockpost.New(mockpost.WithAcceptAll()),
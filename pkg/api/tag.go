@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
 	"github.com/gorilla/mux"
@@ -19,6 +21,10 @@ import (
 
 type tagRequest struct {
 	Address swarm.Address `json:"address,omitempty"`
+
+	// BandwidthLimit caps the push sync bandwidth of the tag
+	// chunks in bytes per second, zero removes the cap.
+	BandwidthLimit *int64 `json:"bandwidthLimit,omitempty"`
 }
 
 type tagResponse struct {
@@ -27,6 +33,30 @@ type tagResponse struct {
 	Total     int64     `json:"total"`
 	Processed int64     `json:"processed"`
 	Synced    int64     `json:"synced"`
+
+	BandwidthLimit int64 `json:"bandwidthLimit,omitempty"`
+
+	Trace *tagTraceResponse `json:"trace,omitempty"`
+
+	// Failed is set once an upload phase of the tag failed,
+	// the tag is then never done.
+	Failed bool               `json:"failed,omitempty"`
+	Errors []tagErrorResponse `json:"errors,omitempty"`
+}
+
+// tagErrorResponse is the error of a failed upload phase.
+type tagErrorResponse struct {
+	Phase   string `json:"phase"`
+	Message string `json:"message"`
+}
+
+// tagTraceResponse summarises the forwarding paths of the tag
+// chunks, present only if push sync receipt tracing is enabled.
+type tagTraceResponse struct {
+	Receipts       int64            `json:"receipts"`
+	MaxHops        int              `json:"maxHops"`
+	AvgHops        float64          `json:"avgHops"`
+	Neighbourhoods map[string]int64 `json:"neighbourhoods"`
 }
 
 type listTagsResponse struct {
@@ -40,6 +70,49 @@ func newTagResponse(tag *tags.Tag) tagResponse {
 		Total:     tag.Total,
 		Processed: tag.Stored,
 		Synced:    tag.Seen + tag.Synced,
+
+		BandwidthLimit: tag.BandwidthLimit(),
+
+		Trace: newTagTraceResponse(tag),
+
+		Failed: tag.Failed(),
+		Errors: newTagErrorsResponse(tag),
+	}
+}
+
+func newTagErrorsResponse(tag *tags.Tag) []tagErrorResponse {
+	var res []tagErrorResponse
+	for _, e := range tag.Errors() {
+		res = append(res, tagErrorResponse{
+			Phase:   tags.PhaseName(e.Phase),
+			Message: e.Message,
+		})
+	}
+	return res
+}
+
+// failUploadTag records the failed upload phase in the tag, so that the tag
+// reaches its terminal failed state instead of never becoming done. The
+// failures caused by a full bucket of the postage batch fail the stamp phase.
+func failUploadTag(logger log.Logger, tag *tags.Tag, phase tags.Phase, err error) {
+	if errors.Is(err, postage.ErrBucketFull) {
+		phase = tags.PhaseStamp
+	}
+	if err := tag.Fail(phase, err); err != nil {
+		logger.Debug("fail tag failed", "tag_uid", tag.Uid, "error", err)
+	}
+}
+
+func newTagTraceResponse(tag *tags.Tag) *tagTraceResponse {
+	trace, ok := tag.Trace()
+	if !ok {
+		return nil
+	}
+	return &tagTraceResponse{
+		Receipts:       trace.Receipts,
+		MaxHops:        trace.MaxHops,
+		AvgHops:        float64(trace.TotalHops) / float64(trace.Receipts),
+		Neighbourhoods: trace.Neighbourhoods,
 	}
 }
 
@@ -68,13 +141,28 @@ func (s *Service) createTagHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	tag, err := s.tags.Create(0)
+	if tagr.BandwidthLimit != nil && *tagr.BandwidthLimit < 0 {
+		logger.Debug("invalid bandwidth limit", "bandwidth_limit", *tagr.BandwidthLimit)
+		logger.Error(nil, "invalid bandwidth limit")
+		jsonhttp.BadRequest(w, "invalid bandwidth limit")
+		return
+	}
+
+	tag, err := s.createTag(r.Context())
 	if err != nil {
 		logger.Debug("create tag failed", "error", err)
 		logger.Error(nil, "create tag failed")
 		jsonhttp.InternalServerError(w, "cannot create tag")
 		return
 	}
+	if tagr.BandwidthLimit != nil {
+		if err := tag.SetBandwidthLimit(*tagr.BandwidthLimit); err != nil {
+			logger.Debug("set bandwidth limit failed", "tag_id", tag.Uid, "error", err)
+			logger.Error(nil, "set bandwidth limit failed", "tag_id", tag.Uid)
+			jsonhttp.InternalServerError(w, "cannot set bandwidth limit")
+			return
+		}
+	}
 	w.Header().Set("Cache-Control", "no-cache, private, max-age=0")
 	jsonhttp.Created(w, newTagResponse(tag))
 }
@@ -90,7 +178,7 @@ func (s *Service) getTagHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tag, err := s.tags.Get(paths.TagID)
+	tag, err := s.lookupTag(r.Context(), paths.TagID)
 	if err != nil {
 		if errors.Is(err, tags.ErrNotFound) {
 			logger.Debug("tag not found", "tag_id", paths.TagID)
@@ -119,7 +207,7 @@ func (s *Service) deleteTagHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tag, err := s.tags.Get(paths.TagID)
+	tag, err := s.lookupTag(r.Context(), paths.TagID)
 	if err != nil {
 		if errors.Is(err, tags.ErrNotFound) {
 			logger.Debug("tag not found", "tag_id", paths.TagID)
@@ -133,7 +221,12 @@ func (s *Service) deleteTagHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.tags.Delete(tag.Uid)
+	if err := s.deleteTag(tag.Uid); err != nil {
+		logger.Debug("delete tag failed", "tag_id", paths.TagID, "error", err)
+		logger.Error(nil, "delete tag failed", "tag_id", paths.TagID)
+		jsonhttp.InternalServerError(w, "cannot delete tag")
+		return
+	}
 	jsonhttp.NoContent(w)
 }
 
@@ -170,7 +263,7 @@ func (s *Service) doneSplitHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	tag, err := s.tags.Get(paths.TagID)
+	tag, err := s.lookupTag(r.Context(), paths.TagID)
 	if err != nil {
 		if errors.Is(err, tags.ErrNotFound) {
 			logger.Debug("tag not found", "tag_id", paths.TagID)
@@ -184,6 +277,24 @@ func (s *Service) doneSplitHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if tagr.BandwidthLimit != nil {
+		if err := tag.SetBandwidthLimit(*tagr.BandwidthLimit); err != nil {
+			logger.Debug("set bandwidth limit failed", "tag_id", paths.TagID, "error", err)
+			logger.Error(nil, "set bandwidth limit failed", "tag_id", paths.TagID)
+			if errors.Is(err, tags.ErrInvalidBandwidthLimit) {
+				jsonhttp.BadRequest(w, "invalid bandwidth limit")
+				return
+			}
+			jsonhttp.InternalServerError(w, "cannot set bandwidth limit")
+			return
+		}
+		// only the bandwidth limit is changed while the upload is in progress
+		if tagr.Address.IsZero() {
+			jsonhttp.OK(w, "ok")
+			return
+		}
+	}
+
 	_, err = tag.DoneSplit(tagr.Address)
 	if err != nil {
 		logger.Debug("done split failed", "address", tagr.Address, "error", err)
@@ -191,6 +302,8 @@ func (s *Service) doneSplitHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.InternalServerError(w, "done split: failed")
 		return
 	}
+	s.watchReceipts(logger, tagr.Address)
+
 	jsonhttp.OK(w, "ok")
 }
 
@@ -198,8 +311,14 @@ func (s *Service) listTagsHandler(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.WithName("get_tags").Build()
 
 	queries := struct {
-		Offset int `map:"offset"`
-		Limit  int `map:"limit"`
+		Offset          int     `map:"offset"`
+		Limit           int     `map:"limit"`
+		State           string  `map:"state" validate:"omitempty,oneof=incomplete complete failed"`
+		Sort            string  `map:"sort" validate:"omitempty,oneof=uid age"`
+		MinAge          int64   `map:"minAge" validate:"min=0"`
+		MaxAge          int64   `map:"maxAge" validate:"min=0"`
+		MinCompleteness float64 `map:"minCompleteness" validate:"min=0,max=1"`
+		MaxCompleteness float64 `map:"maxCompleteness" validate:"min=0,max=1"`
 	}{
 		Limit: 100, // Default limit.
 	}
@@ -208,9 +327,19 @@ func (s *Service) listTagsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tagList, err := s.tags.ListAll(r.Context(), queries.Offset, queries.Limit)
+	query := tags.Query{
+		Progress:        tags.Progress(queries.State),
+		MinAge:          time.Duration(queries.MinAge) * time.Second,
+		MaxAge:          time.Duration(queries.MaxAge) * time.Second,
+		MinCompleteness: queries.MinCompleteness,
+		MaxCompleteness: queries.MaxCompleteness,
+		Sort:            tags.Sort(queries.Sort),
+		Offset:          queries.Offset,
+		Limit:           queries.Limit,
+	}
+	tagList, err := s.listTags(r.Context(), query)
 	if err != nil {
-		logger.Debug("listing failed", "offset", queries.Offset, "limit", queries.Limit, "error", err)
+		logger.Debug("listing failed", "query", query, "error", err)
 		logger.Error(nil, "listing failed")
 		jsonhttp.InternalServerError(w, err)
 		return
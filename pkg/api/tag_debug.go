@@ -25,6 +25,11 @@ type debugTagResponse struct {
 	Uid       uint32        `json:"uid"`
 	Address   swarm.Address `json:"address"`
 	StartedAt time.Time     `json:"startedAt"`
+
+	Trace *tagTraceResponse `json:"trace,omitempty"`
+
+	Failed bool               `json:"failed,omitempty"`
+	Errors []tagErrorResponse `json:"errors,omitempty"`
 }
 
 func newDebugTagResponse(tag *tags.Tag) debugTagResponse {
@@ -38,6 +43,9 @@ func newDebugTagResponse(tag *tags.Tag) debugTagResponse {
 		Uid:       tag.Uid,
 		Address:   tag.Address,
 		StartedAt: tag.StartedAt,
+		Trace:     newTagTraceResponse(tag),
+		Failed:    tag.Failed(),
+		Errors:    newTagErrorsResponse(tag),
 	}
 }
 
@@ -16,7 +16,6 @@ import (
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/log"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	testingc "github.com/ethersphere/bee/pkg/storage/testing"
@@ -31,8 +30,7 @@ func TestDebugTags(t *testing.T) {
 		logger          = log.Noop
 		chunk           = testingc.GenerateTestRandomChunk()
 		mockStorer      = mock.NewStorer()
-		mockStatestore  = statestore.NewStateStore()
-		tagsStore       = tags.NewTags(mockStatestore, logger)
+		tagsStore       = tags.NewTags(nil, logger)
 		client, _, _, _ = newTestServer(t, testServerOptions{
 			Storer:   mock.NewStorer(),
 			Tags:     tagsStore,
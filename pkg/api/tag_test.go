@@ -6,17 +6,19 @@ package api_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"sort"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/util/testutil"
 
 	"github.com/ethersphere/bee/pkg/api"
@@ -45,9 +47,8 @@ func TestTags(t *testing.T) {
 		chunksResource           = "/chunks"
 		tagsResource             = "/tags"
 		chunk                    = testingc.GenerateTestRandomChunk()
-		mockStatestore           = statestore.NewStateStore()
 		logger                   = log.Noop
-		tag                      = tags.NewTags(mockStatestore, logger)
+		tag                      = tags.NewTags(nil, logger)
 		client, _, listenAddr, _ = newTestServer(t, testServerOptions{
 			Storer: mock.NewStorer(),
 			Tags:   tag,
@@ -73,6 +74,44 @@ func TestTags(t *testing.T) {
 		)
 	})
 
+	t.Run("bandwidth limit", func(t *testing.T) {
+		limit := int64(swarm.ChunkWithSpanSize)
+		tr := api.TagResponse{}
+		jsonhttptest.Request(t, client, http.MethodPost, tagsResource, http.StatusCreated,
+			jsonhttptest.WithJSONRequestBody(api.TagRequest{BandwidthLimit: &limit}),
+			jsonhttptest.WithUnmarshalJSONResponse(&tr),
+		)
+		if tr.BandwidthLimit != limit {
+			t.Fatalf("got bandwidth limit %d, want %d", tr.BandwidthLimit, limit)
+		}
+
+		limit = 10 * swarm.ChunkWithSpanSize
+		jsonhttptest.Request(t, client, http.MethodPatch, tagsWithIdResource(tr.Uid), http.StatusOK,
+			jsonhttptest.WithJSONRequestBody(api.TagRequest{BandwidthLimit: &limit}),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, tagsWithIdResource(tr.Uid), http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&tr),
+		)
+		if tr.BandwidthLimit != limit {
+			t.Fatalf("got bandwidth limit %d, want %d", tr.BandwidthLimit, limit)
+		}
+		if tr.Synced != 0 {
+			t.Fatal("changing the bandwidth limit must not finish the split")
+		}
+
+		limit = -1
+		jsonhttptest.Request(t, client, http.MethodPatch, tagsWithIdResource(tr.Uid), http.StatusBadRequest,
+			jsonhttptest.WithJSONRequestBody(api.TagRequest{BandwidthLimit: &limit}),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid bandwidth limit",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+		jsonhttptest.Request(t, client, http.MethodPost, tagsResource, http.StatusBadRequest,
+			jsonhttptest.WithJSONRequestBody(api.TagRequest{BandwidthLimit: &limit}),
+		)
+	})
+
 	t.Run("create tag with invalid id", func(t *testing.T) {
 		jsonhttptest.Request(t, client, http.MethodPost, chunksResource, http.StatusBadRequest,
 			jsonhttptest.WithRequestBody(bytes.NewReader(chunk.Data())),
@@ -386,6 +425,57 @@ func TestTags(t *testing.T) {
 		}
 		tagValueTest(t, id, 3, 3, 1, 0, 0, 3, swarm.ZeroAddress, client)
 	})
+
+	t.Run("tag trace", func(t *testing.T) {
+		ta, err := tag.Create(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ta.AddTrace(3, "0111")
+		ta.AddTrace(1, "0111")
+
+		tr := api.TagResponse{}
+		jsonhttptest.Request(t, client, http.MethodGet, tagsWithIdResource(ta.Uid), http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&tr),
+		)
+
+		want := &api.TagTraceResponse{
+			Receipts:       2,
+			MaxHops:        3,
+			AvgHops:        2,
+			Neighbourhoods: map[string]int64{"0111": 2},
+		}
+		if !reflect.DeepEqual(tr.Trace, want) {
+			t.Fatalf("got trace %+v, want %+v", tr.Trace, want)
+		}
+	})
+
+	t.Run("tag errors", func(t *testing.T) {
+		ta, err := tag.Create(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tr := api.TagResponse{}
+		jsonhttptest.Request(t, client, http.MethodGet, tagsWithIdResource(ta.Uid), http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&tr),
+		)
+		if tr.Failed || len(tr.Errors) != 0 {
+			t.Fatalf("got failed %t with errors %v, want none", tr.Failed, tr.Errors)
+		}
+
+		if err := ta.Fail(tags.PhaseStamp, postage.ErrBucketFull); err != nil {
+			t.Fatal(err)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, tagsWithIdResource(ta.Uid), http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&tr),
+		)
+
+		want := []api.TagErrorResponse{{Phase: "stamp", Message: postage.ErrBucketFull.Error()}}
+		if !tr.Failed || !reflect.DeepEqual(tr.Errors, want) {
+			t.Fatalf("got failed %t with errors %v, want errors %v", tr.Failed, tr.Errors, want)
+		}
+	})
 }
 
 func Test_tagHandlers_invalidInputs(t *testing.T) {
@@ -449,6 +539,82 @@ func isTagFoundInResponse(t *testing.T, headers http.Header, tr *api.TagResponse
 	return id
 }
 
+func TestTagsQuery(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tagsResource    = "/tags"
+		tag             = tags.NewTags(nil, log.Noop)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Tags: tag,
+		})
+		now = time.Now()
+	)
+
+	// the tags of 10 chunks from the oldest to the newest
+	for i, synced := range []int64{2, 10, 0, 5} {
+		ta, err := tag.Create(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ta.Stored = 10
+		ta.Synced = synced
+		ta.StartedAt = now.Add(time.Duration(i-4) * time.Hour)
+	}
+	all, err := tag.Query(context.Background(), tags.Query{Sort: tags.SortAge})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  []*tags.Tag
+	}{
+		{
+			query: "?state=incomplete&sort=age",
+			want:  []*tags.Tag{all[0], all[2], all[3]},
+		},
+		{
+			query: "?state=complete",
+			want:  []*tags.Tag{all[1]},
+		},
+		{
+			query: "?sort=age&minAge=5400&maxAge=12600",
+			want:  []*tags.Tag{all[1], all[2]},
+		},
+		{
+			query: "?sort=age&minCompleteness=0.1&maxCompleteness=0.5",
+			want:  []*tags.Tag{all[0], all[3]},
+		},
+	} {
+		var resp api.ListTagsResponse
+		jsonhttptest.Request(t, client, http.MethodGet, tagsResource+tc.query, http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		if len(resp.Tags) != len(tc.want) {
+			t.Fatalf("%s: got %d tags, want %d", tc.query, len(resp.Tags), len(tc.want))
+		}
+		for i, ta := range tc.want {
+			if resp.Tags[i].Uid != ta.Uid {
+				t.Fatalf("%s: got tag %d at %d, want %d", tc.query, resp.Tags[i].Uid, i, ta.Uid)
+			}
+		}
+	}
+
+	jsonhttptest.Request(t, client, http.MethodGet, tagsResource+"?state=unknown", http.StatusBadRequest,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid query params",
+			Reasons: []jsonhttp.Reason{
+				{
+					Field: "state",
+					Error: "want oneof:incomplete complete failed",
+				},
+			},
+		}),
+	)
+}
+
 func tagValueTest(t *testing.T, id uint32, split, stored, seen, sent, synced, total int64, address swarm.Address, client *http.Client) {
 	t.Helper()
 	tag := api.TagResponse{}
@@ -0,0 +1,505 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/pinning"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+const (
+	tenantPinKeyPrefix     = "tenant-pin-"
+	tenantPathPinKeyPrefix = "tenant-path-pin-"
+	tenantTagKeyPrefix     = "tenant-tag-"
+)
+
+var (
+	errBatchNotAllowed  = errors.New("batch not allowed for the tenant")
+	errPinQuotaExceeded = errors.New("pin quota exceeded")
+
+	tenantNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+)
+
+// Tenant is an application sharing the node with the other applications.
+// The requests with a security token scoped to the tenant may stamp the
+// chunks only with the batches of the tenant, may pin no more references
+// than allowed by the pin quota and see only the tags created by the tenant.
+type Tenant struct {
+	Name     string
+	Batches  [][]byte
+	PinQuota int // maximum number of pinned references, unlimited if zero
+}
+
+// ParseTenants parses the JSON encoded list of the tenants, in the form of:
+// [{"name": "app", "batches": ["<hex batch id>"], "pinQuota": 100}]
+func ParseTenants(data []byte) ([]Tenant, error) {
+	var entries []struct {
+		Name     string   `json:"name"`
+		Batches  []string `json:"batches"`
+		PinQuota int      `json:"pinQuota"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	tenants := make([]Tenant, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		if !tenantNameRegexp.MatchString(e.Name) {
+			return nil, fmt.Errorf("invalid tenant name %q", e.Name)
+		}
+		if _, ok := seen[e.Name]; ok {
+			return nil, fmt.Errorf("duplicate tenant %q", e.Name)
+		}
+		seen[e.Name] = struct{}{}
+		if e.PinQuota < 0 {
+			return nil, fmt.Errorf("negative pin quota of tenant %q", e.Name)
+		}
+
+		t := Tenant{Name: e.Name, PinQuota: e.PinQuota}
+		for _, b := range e.Batches {
+			id, err := hex.DecodeString(b)
+			if err != nil || len(id) != 32 {
+				return nil, fmt.Errorf("invalid batch id %q of tenant %q", b, e.Name)
+			}
+			t.Batches = append(t.Batches, id)
+		}
+		tenants = append(tenants, t)
+	}
+	return tenants, nil
+}
+
+// tenant is the resolved configuration of the Tenant.
+type tenant struct {
+	name     string
+	batches  map[string]struct{}
+	pinQuota int
+}
+
+func newTenants(ts []Tenant) map[string]*tenant {
+	tenants := make(map[string]*tenant, len(ts))
+	for _, t := range ts {
+		batches := make(map[string]struct{}, len(t.Batches))
+		for _, b := range t.Batches {
+			batches[string(b)] = struct{}{}
+		}
+		tenants[t.Name] = &tenant{name: t.Name, batches: batches, pinQuota: t.PinQuota}
+	}
+	return tenants
+}
+
+// allowsBatch reports whether the chunks may be stamped with the batch.
+// Requests which are not scoped to any tenant may use all batches.
+func (t *tenant) allowsBatch(batchID []byte) bool {
+	if t == nil {
+		return true
+	}
+	_, ok := t.batches[string(batchID)]
+	return ok
+}
+
+type tenantContextKey struct{}
+
+// requestTenant returns the tenant the request is scoped to, nil if none.
+func requestTenant(ctx context.Context) *tenant {
+	t, _ := ctx.Value(tenantContextKey{}).(*tenant)
+	return t
+}
+
+// tenantHandler scopes the requests with the security
+// token issued for a tenant to the configuration of the tenant.
+func (s *Service) tenantHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.tenants) == 0 {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		apiKey, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(apiKey) == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		name, err := s.auth.Tenant(apiKey)
+		if errors.Is(err, auth.ErrTokenExpired) {
+			jsonhttp.Unauthorized(w, "Token expired")
+			return
+		}
+		if err != nil {
+			jsonhttp.Unauthorized(w, "Invalid security token")
+			return
+		}
+		if name == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		t, ok := s.tenants[name]
+		if !ok {
+			jsonhttp.Forbidden(w, "unknown tenant")
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantContextKey{}, t)))
+	})
+}
+
+func tenantPinKey(name string, addr swarm.Address) string {
+	return fmt.Sprintf("%s%s-%s", tenantPinKeyPrefix, name, addr)
+}
+
+func tenantPathPinKey(name string, addr swarm.Address, path string) string {
+	return fmt.Sprintf("%s%s-%s-%s", tenantPathPinKeyPrefix, name, addr, path)
+}
+
+func tenantTagKey(name string, uid uint32) string {
+	return fmt.Sprintf("%s%s-%d", tenantTagKeyPrefix, name, uid)
+}
+
+// tenantPinning keeps track of the references pinned by the tenants
+// and enforces their pin quotas. A reference stays pinned until it
+// is unpinned by all of the tenants which have pinned it.
+type tenantPinning struct {
+	pinning.Interface
+	mu      sync.Mutex
+	store   storage.StateStorer
+	tenants map[string]*tenant
+}
+
+// CreatePin implements the pinning.Interface.
+func (p *tenantPinning) CreatePin(ctx context.Context, addr swarm.Address, traverse bool) error {
+	t := requestTenant(ctx)
+	if t == nil {
+		return p.Interface.CreatePin(ctx, addr, traverse)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	has, err := p.hasPin(t, addr)
+	if err != nil || has {
+		return err
+	}
+	if err := p.checkQuota(t); err != nil {
+		return err
+	}
+	if err := p.Interface.CreatePin(ctx, addr, traverse); err != nil {
+		return err
+	}
+	return p.store.Put(tenantPinKey(t.name, addr), addr)
+}
+
+// CreatePathPin implements the pinning.Interface.
+func (p *tenantPinning) CreatePathPin(ctx context.Context, addr swarm.Address, path string) error {
+	t := requestTenant(ctx)
+	if t == nil {
+		return p.Interface.CreatePathPin(ctx, addr, path)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	has, err := p.hasPathPin(t, addr, path)
+	if err != nil || has {
+		return err
+	}
+	if err := p.checkQuota(t); err != nil {
+		return err
+	}
+	if err := p.Interface.CreatePathPin(ctx, addr, path); err != nil {
+		return err
+	}
+	return p.store.Put(tenantPathPinKey(t.name, addr, path), pinning.PathPin{Reference: addr, Path: path})
+}
+
+// checkQuota returns errPinQuotaExceeded if the tenant may not pin more
+// references, the path pins count towards the pin quota as well.
+func (p *tenantPinning) checkQuota(t *tenant) error {
+	if t.pinQuota == 0 {
+		return nil
+	}
+	pins, err := p.pins(t)
+	if err != nil {
+		return err
+	}
+	pathPins, err := p.pathPins(t)
+	if err != nil {
+		return err
+	}
+	if len(pins)+len(pathPins) >= t.pinQuota {
+		return errPinQuotaExceeded
+	}
+	return nil
+}
+
+// DeletePin implements the pinning.Interface.
+func (p *tenantPinning) DeletePin(ctx context.Context, addr swarm.Address) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := requestTenant(ctx)
+	if t == nil {
+		for name := range p.tenants {
+			if err := p.store.Delete(tenantPinKey(name, addr)); err != nil {
+				return err
+			}
+		}
+		return p.Interface.DeletePin(ctx, addr)
+	}
+
+	has, err := p.hasPin(t, addr)
+	if err != nil || !has {
+		return err
+	}
+	if err := p.store.Delete(tenantPinKey(t.name, addr)); err != nil {
+		return err
+	}
+	for _, other := range p.tenants {
+		if has, err := p.hasPin(other, addr); err != nil || has {
+			return err
+		}
+	}
+	return p.Interface.DeletePin(ctx, addr)
+}
+
+// DeletePathPin implements the pinning.Interface.
+func (p *tenantPinning) DeletePathPin(ctx context.Context, addr swarm.Address, path string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := requestTenant(ctx)
+	if t == nil {
+		for name := range p.tenants {
+			if err := p.store.Delete(tenantPathPinKey(name, addr, path)); err != nil {
+				return err
+			}
+		}
+		return p.Interface.DeletePathPin(ctx, addr, path)
+	}
+
+	has, err := p.hasPathPin(t, addr, path)
+	if err != nil || !has {
+		return err
+	}
+	if err := p.store.Delete(tenantPathPinKey(t.name, addr, path)); err != nil {
+		return err
+	}
+	for _, other := range p.tenants {
+		if has, err := p.hasPathPin(other, addr, path); err != nil || has {
+			return err
+		}
+	}
+	return p.Interface.DeletePathPin(ctx, addr, path)
+}
+
+func (p *tenantPinning) hasPin(t *tenant, addr swarm.Address) (bool, error) {
+	var ref swarm.Address
+	switch err := p.store.Get(tenantPinKey(t.name, addr), &ref); {
+	case errors.Is(err, storage.ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *tenantPinning) pins(t *tenant) ([]swarm.Address, error) {
+	var pins []swarm.Address
+	err := p.store.Iterate(tenantPinKeyPrefix+t.name+"-", func(_, value []byte) (bool, error) {
+		var ref swarm.Address
+		if err := json.Unmarshal(value, &ref); err != nil {
+			return true, err
+		}
+		pins = append(pins, ref)
+		return false, nil
+	})
+	return pins, err
+}
+
+func (p *tenantPinning) hasPathPin(t *tenant, addr swarm.Address, path string) (bool, error) {
+	var pin pinning.PathPin
+	switch err := p.store.Get(tenantPathPinKey(t.name, addr, path), &pin); {
+	case errors.Is(err, storage.ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *tenantPinning) pathPins(t *tenant) ([]pinning.PathPin, error) {
+	var pins []pinning.PathPin
+	err := p.store.Iterate(tenantPathPinKeyPrefix+t.name+"-", func(_, value []byte) (bool, error) {
+		var pin pinning.PathPin
+		if err := json.Unmarshal(value, &pin); err != nil {
+			return true, err
+		}
+		pins = append(pins, pin)
+		return false, nil
+	})
+	return pins, err
+}
+
+// hasPin returns true if the reference is pinned, from the
+// perspective of the tenant the context is scoped to.
+func (s *Service) hasPin(ctx context.Context, addr swarm.Address) (bool, error) {
+	if p, ok := s.pinning.(*tenantPinning); ok {
+		if t := requestTenant(ctx); t != nil {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return p.hasPin(t, addr)
+		}
+	}
+	return s.pinning.HasPin(addr)
+}
+
+// listPins returns the pinned references, from the
+// perspective of the tenant the context is scoped to.
+func (s *Service) listPins(ctx context.Context) ([]swarm.Address, error) {
+	if p, ok := s.pinning.(*tenantPinning); ok {
+		if t := requestTenant(ctx); t != nil {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return p.pins(t)
+		}
+	}
+	return s.pinning.Pins()
+}
+
+// hasPathPin returns true if the manifest path is pinned, from
+// the perspective of the tenant the context is scoped to.
+func (s *Service) hasPathPin(ctx context.Context, addr swarm.Address, path string) (bool, error) {
+	if p, ok := s.pinning.(*tenantPinning); ok {
+		if t := requestTenant(ctx); t != nil {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return p.hasPathPin(t, addr, path)
+		}
+	}
+	return s.pinning.HasPathPin(addr, path)
+}
+
+// listPathPins returns the pinned manifest paths, from the
+// perspective of the tenant the context is scoped to.
+func (s *Service) listPathPins(ctx context.Context) ([]pinning.PathPin, error) {
+	if p, ok := s.pinning.(*tenantPinning); ok {
+		if t := requestTenant(ctx); t != nil {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return p.pathPins(t)
+		}
+	}
+	return s.pinning.PathPins()
+}
+
+// createTag creates a new tag in the namespace
+// of the tenant the context is scoped to.
+func (s *Service) createTag(ctx context.Context) (*tags.Tag, error) {
+	tag, err := s.tags.Create(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := requestTenant(ctx); t != nil {
+		if err := s.stateStore.Put(tenantTagKey(t.name, tag.Uid), tag.Uid); err != nil {
+			s.tags.Delete(tag.Uid)
+			return nil, err
+		}
+	}
+	return tag, nil
+}
+
+// lookupTag returns the tag if it is in the namespace
+// of the tenant the context is scoped to.
+func (s *Service) lookupTag(ctx context.Context, uid uint32) (*tags.Tag, error) {
+	if t := requestTenant(ctx); t != nil {
+		var v uint32
+		switch err := s.stateStore.Get(tenantTagKey(t.name, uid), &v); {
+		case errors.Is(err, storage.ErrNotFound):
+			return nil, tags.ErrNotFound
+		case err != nil:
+			return nil, err
+		}
+	}
+	return s.tags.Get(uid)
+}
+
+// deleteTag deletes the tag and removes it from the tenant namespaces.
+func (s *Service) deleteTag(uid uint32) error {
+	s.tags.Delete(uid)
+	for name := range s.tenants {
+		if err := s.stateStore.Delete(tenantTagKey(name, uid)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listTags lists the tags of the query in the namespace of the tenant
+// the context is scoped to, in the order of the query as the tags.Query does.
+func (s *Service) listTags(ctx context.Context, q tags.Query) ([]*tags.Tag, error) {
+	t := requestTenant(ctx)
+	if t == nil {
+		return s.tags.Query(ctx, q)
+	}
+
+	prefix := tenantTagKeyPrefix + t.name + "-"
+	var uids []uint32
+	err := s.stateStore.Iterate(prefix, func(key, _ []byte) (bool, error) {
+		uid, err := strconv.ParseUint(strings.TrimPrefix(string(key), prefix), 10, 32)
+		if err != nil {
+			return true, err
+		}
+		uids = append(uids, uint32(uid))
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		list []*tags.Tag
+		now  = time.Now()
+	)
+	for _, uid := range uids {
+		tag, err := s.tags.Get(uid)
+		if errors.Is(err, tags.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if q.Match(tag, now) {
+			list = append(list, tag)
+		}
+	}
+	q.SortTags(list)
+
+	if q.Offset > 0 {
+		if q.Offset >= len(list) {
+			return nil, nil
+		}
+		list = list[q.Offset:]
+	}
+	if q.Limit > 0 && q.Limit < len(list) {
+		list = list[:q.Limit]
+	}
+	return list, nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	mockauth "github.com/ethersphere/bee/pkg/auth/mock"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestParseTenants(t *testing.T) {
+	t.Parallel()
+
+	tenants, err := api.ParseTenants([]byte(fmt.Sprintf(`[{"name": "app", "batches": [%q], "pinQuota": 2}, {"name": "other"}]`, batchOkStr)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tenants) != 2 {
+		t.Fatalf("got %d tenants, want 2", len(tenants))
+	}
+	if tenants[0].Name != "app" || tenants[0].PinQuota != 2 || len(tenants[0].Batches) != 1 || hex.EncodeToString(tenants[0].Batches[0]) != batchOkStr {
+		t.Fatalf("got tenant %+v", tenants[0])
+	}
+
+	for _, data := range []string{
+		`[{"name": "a-b"}]`,
+		`[{"name": "app"}, {"name": "app"}]`,
+		`[{"name": "app", "batches": ["ff"]}]`,
+		`[{"name": "app", "pinQuota": -1}]`,
+	} {
+		if _, err := api.ParseTenants([]byte(data)); err == nil {
+			t.Fatalf("expected error parsing %s", data)
+		}
+	}
+}
+
+// nolint:paralleltest
+func TestTenants(t *testing.T) {
+	var (
+		logger        = log.Noop
+		otherBatchStr = strings.Repeat("ab", 32)
+		otherBatch, _ = hex.DecodeString(otherBatchStr)
+		authenticator = &mockauth.Auth{
+			EnforceFunc: func(_, _, _ string) (bool, error) { return true, nil },
+			TenantFunc: func(key string) (string, error) {
+				switch key {
+				case "admin":
+					return "", nil
+				case "expired":
+					return "", errors.New("token expired")
+				}
+				return key, nil
+			},
+		}
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:        mock.NewStorer(),
+			Tags:          tags.NewTags(nil, logger),
+			Pinning:       pinning.NewServiceMock(),
+			Logger:        logger,
+			Post:          mockpost.New(mockpost.WithAcceptAll()),
+			StateStorer:   statestore.NewStateStore(),
+			Restricted:    true,
+			Authenticator: authenticator,
+			Tenants: []api.Tenant{
+				{Name: "app", Batches: [][]byte{batchOk}, PinQuota: 1},
+				{Name: "other", Batches: [][]byte{otherBatch}},
+			},
+		})
+		bearer = func(token string) jsonhttptest.Option {
+			return jsonhttptest.WithRequestHeader("Authorization", "Bearer "+token)
+		}
+		upload = func(t *testing.T, token, batch, data string) swarm.Address {
+			t.Helper()
+
+			var resp api.BytesPostResponse
+			jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+				bearer(token),
+				jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batch),
+				jsonhttptest.WithRequestBody(strings.NewReader(data)),
+				jsonhttptest.WithUnmarshalJSONResponse(&resp),
+			)
+			return resp.Reference
+		}
+	)
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/pins", http.StatusForbidden,
+			bearer("unknown"),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "unknown tenant",
+				Code:    http.StatusForbidden,
+			}),
+		)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/pins", http.StatusUnauthorized,
+			bearer("expired"),
+		)
+	})
+
+	t.Run("batches", func(t *testing.T) {
+		upload(t, "app", batchOkStr, "app data")
+		upload(t, "other", otherBatchStr, "other data")
+		upload(t, "admin", batchOkStr, "admin data")
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusForbidden,
+			bearer("other"),
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(strings.NewReader("stolen stamps")),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "batch not allowed",
+				Code:    http.StatusForbidden,
+			}),
+		)
+	})
+
+	t.Run("pins", func(t *testing.T) {
+		var (
+			first  = upload(t, "app", batchOkStr, "first")
+			second = upload(t, "app", batchOkStr, "second")
+		)
+		type pinsResponse struct {
+			References []swarm.Address `json:"references"`
+		}
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/pins/"+first.String(), http.StatusCreated, bearer("app"))
+		jsonhttptest.Request(t, client, http.MethodPost, "/pins/"+second.String(), http.StatusForbidden,
+			bearer("app"),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "pin quota exceeded",
+				Code:    http.StatusForbidden,
+			}),
+		)
+		jsonhttptest.Request(t, client, http.MethodPost, "/pins/"+first.String(), http.StatusCreated, bearer("other"))
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/pins", http.StatusOK,
+			bearer("app"),
+			jsonhttptest.WithExpectedJSONResponse(pinsResponse{References: []swarm.Address{first}}),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, "/pins/"+second.String(), http.StatusNotFound, bearer("app"))
+
+		// the reference stays pinned until all of the tenants unpin it
+		jsonhttptest.Request(t, client, http.MethodDelete, "/pins/"+first.String(), http.StatusOK, bearer("app"))
+		jsonhttptest.Request(t, client, http.MethodGet, "/pins/"+first.String(), http.StatusNotFound, bearer("app"))
+		jsonhttptest.Request(t, client, http.MethodGet, "/pins/"+first.String(), http.StatusOK, bearer("admin"))
+		jsonhttptest.Request(t, client, http.MethodDelete, "/pins/"+first.String(), http.StatusOK, bearer("other"))
+		jsonhttptest.Request(t, client, http.MethodGet, "/pins/"+first.String(), http.StatusNotFound, bearer("admin"))
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/pins/"+second.String(), http.StatusCreated, bearer("app"))
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		var tag api.TagResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/tags", http.StatusCreated,
+			bearer("app"),
+			jsonhttptest.WithUnmarshalJSONResponse(&tag),
+		)
+		path := fmt.Sprintf("/tags/%d", tag.Uid)
+
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusOK, bearer("app"))
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusOK, bearer("admin"))
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusNotFound, bearer("other"))
+		jsonhttptest.Request(t, client, http.MethodDelete, path, http.StatusNotFound, bearer("other"))
+
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusNotFound,
+			bearer("other"),
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, otherBatchStr),
+			jsonhttptest.WithRequestHeader(api.SwarmTagHeader, fmt.Sprint(tag.Uid)),
+			jsonhttptest.WithRequestBody(strings.NewReader("foreign tag")),
+		)
+
+		var list api.ListTagsResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/tags", http.StatusOK,
+			bearer("other"),
+			jsonhttptest.WithUnmarshalJSONResponse(&list),
+		)
+		for _, tr := range list.Tags {
+			if tr.Uid == tag.Uid {
+				t.Fatalf("tag %d of the tenant app listed for the tenant other", tag.Uid)
+			}
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/tags", http.StatusOK,
+			bearer("app"),
+			jsonhttptest.WithUnmarshalJSONResponse(&list),
+		)
+		var found bool
+		for _, tr := range list.Tags {
+			found = found || tr.Uid == tag.Uid
+		}
+		if !found {
+			t.Fatalf("tag %d not listed for the tenant app", tag.Uid)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodDelete, path, http.StatusNoContent, bearer("app"))
+		jsonhttptest.Request(t, client, http.MethodGet, path, http.StatusNotFound, bearer("app"))
+	})
+}
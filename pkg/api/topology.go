@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"sort"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 )
@@ -29,3 +30,100 @@ func (s *Service) topologyHandler(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", jsonhttp.DefaultContentTypeHeader)
 	_, _ = io.Copy(w, bytes.NewBuffer(b))
 }
+
+type binLatencyResponse struct {
+	Bin        uint8 `json:"bin"`
+	Peers      int   `json:"peers"`
+	Unmeasured int   `json:"unmeasured"`
+	Min        int64 `json:"min"`
+	P50        int64 `json:"p50"`
+	P90        int64 `json:"p90"`
+	P99        int64 `json:"p99"`
+	Max        int64 `json:"max"`
+}
+
+type latencyNode struct {
+	ID  string `json:"id"`
+	Bin *uint8 `json:"bin,omitempty"`
+}
+
+type latencyEdge struct {
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Latency int64  `json:"latency"`
+}
+
+type latencyGraph struct {
+	Nodes []latencyNode `json:"nodes"`
+	Edges []latencyEdge `json:"edges"`
+}
+
+type topologyLatencyResponse struct {
+	Base  string               `json:"baseAddr"`
+	Depth uint8                `json:"depth"`
+	Bins  []binLatencyResponse `json:"bins"`
+	Graph latencyGraph         `json:"graph"`
+}
+
+// topologyLatencyHandler returns the percentiles of the latencies of the
+// connected peers per bin, along with a graph of the connections weighted
+// by their latency. The latencies are the moving averages of the round trip
+// times measured periodically by pinging the peers, given in milliseconds.
+// Peers without a measurement yet are counted as unmeasured and left out.
+func (s *Service) topologyLatencyHandler(w http.ResponseWriter, _ *http.Request) {
+	params := s.topologyDriver.Snapshot()
+
+	resp := topologyLatencyResponse{
+		Base:  params.Base,
+		Depth: params.Depth,
+		Bins:  []binLatencyResponse{},
+		Graph: latencyGraph{
+			Nodes: []latencyNode{{ID: params.Base}},
+			Edges: []latencyEdge{},
+		},
+	}
+
+	for i, bin := range params.Bins.List() {
+		if len(bin.ConnectedPeers) == 0 {
+			continue
+		}
+		po := uint8(i)
+		binLatency := binLatencyResponse{Bin: po, Peers: len(bin.ConnectedPeers)}
+
+		var latencies []int64
+		for _, peer := range bin.ConnectedPeers {
+			if peer.Metrics == nil || peer.Metrics.LatencyEWMA <= 0 {
+				binLatency.Unmeasured++
+				continue
+			}
+			latencies = append(latencies, peer.Metrics.LatencyEWMA)
+			resp.Graph.Nodes = append(resp.Graph.Nodes, latencyNode{ID: peer.Address.String(), Bin: &po})
+			resp.Graph.Edges = append(resp.Graph.Edges, latencyEdge{
+				Source:  params.Base,
+				Target:  peer.Address.String(),
+				Latency: peer.Metrics.LatencyEWMA,
+			})
+		}
+
+		if len(latencies) > 0 {
+			sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+			binLatency.Min = latencies[0]
+			binLatency.P50 = percentile(latencies, 50)
+			binLatency.P90 = percentile(latencies, 90)
+			binLatency.P99 = percentile(latencies, 99)
+			binLatency.Max = latencies[len(latencies)-1]
+		}
+		resp.Bins = append(resp.Bins, binLatency)
+	}
+
+	jsonhttp.OK(w, resp)
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []int64, p int) int64 {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
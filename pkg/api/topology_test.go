@@ -8,7 +8,11 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/ethersphere/bee/pkg/api"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+	topologymock "github.com/ethersphere/bee/pkg/topology/mock"
 )
 
 func TestTopologyOK(t *testing.T) {
@@ -24,3 +28,59 @@ func TestTopologyOK(t *testing.T) {
 		t.Error("empty response")
 	}
 }
+
+func TestTopologyLatency(t *testing.T) {
+	t.Parallel()
+
+	base := "ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c"
+	peer := func(addr string, latency int64) *topology.PeerInfo {
+		return &topology.PeerInfo{
+			Address: swarm.MustParseHexAddress(addr),
+			Metrics: &topology.MetricSnapshotView{LatencyEWMA: latency},
+		}
+	}
+
+	params := &topology.KadParams{Base: base, Depth: 2}
+	params.Bins.Bin0.ConnectedPeers = []*topology.PeerInfo{
+		peer("0000000000000000000000000000000000000000000000000000000000000001", 40),
+		peer("0000000000000000000000000000000000000000000000000000000000000002", 10),
+		peer("0000000000000000000000000000000000000000000000000000000000000003", 20),
+		peer("0000000000000000000000000000000000000000000000000000000000000004", 0),
+	}
+	params.Bins.Bin3.ConnectedPeers = []*topology.PeerInfo{
+		{Address: swarm.MustParseHexAddress("ca00000000000000000000000000000000000000000000000000000000000000")},
+	}
+
+	testServer, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:     true,
+		TopologyOpts: []topologymock.Option{topologymock.WithSnapshot(params)},
+	})
+
+	bin0, bin3 := uint8(0), uint8(3)
+	edge := func(target string, latency int64) api.LatencyEdge {
+		return api.LatencyEdge{Source: base, Target: target, Latency: latency}
+	}
+	jsonhttptest.Request(t, testServer, http.MethodGet, "/topology/latency", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.TopologyLatencyResponse{
+			Base:  base,
+			Depth: 2,
+			Bins: []api.BinLatencyResponse{
+				{Bin: bin0, Peers: 4, Unmeasured: 1, Min: 10, P50: 20, P90: 40, P99: 40, Max: 40},
+				{Bin: bin3, Peers: 1, Unmeasured: 1},
+			},
+			Graph: api.LatencyGraph{
+				Nodes: []api.LatencyNode{
+					{ID: base},
+					{ID: "0000000000000000000000000000000000000000000000000000000000000001", Bin: &bin0},
+					{ID: "0000000000000000000000000000000000000000000000000000000000000002", Bin: &bin0},
+					{ID: "0000000000000000000000000000000000000000000000000000000000000003", Bin: &bin0},
+				},
+				Edges: []api.LatencyEdge{
+					edge("0000000000000000000000000000000000000000000000000000000000000001", 40),
+					edge("0000000000000000000000000000000000000000000000000000000000000002", 10),
+					edge("0000000000000000000000000000000000000000000000000000000000000003", 20),
+				},
+			},
+		}),
+	)
+}
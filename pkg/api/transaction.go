@@ -24,6 +24,9 @@ const (
 	errUnknownTransaction    = "unknown transaction"
 	errAlreadyImported       = "already imported"
 	errCantResendTransaction = "can't resend transaction"
+	errCantGetNonceStatus    = "cannot get nonce status"
+	errCantResolveNonce      = "cannot resolve stuck nonce"
+	errNoStuckNonce          = "no stuck nonce"
 )
 
 type transactionInfo struct {
@@ -199,3 +202,72 @@ func (s *Service) transactionCancelHandler(w http.ResponseWriter, r *http.Reques
 		TransactionHash: txHash,
 	})
 }
+
+type stuckNonceResponse struct {
+	Nonce           uint64       `json:"nonce"`
+	TransactionHash *common.Hash `json:"transactionHash,omitempty"`
+	Since           *time.Time   `json:"since,omitempty"`
+}
+
+type nonceStatusResponse struct {
+	Confirmed uint64              `json:"confirmed"`
+	Pending   uint64              `json:"pending"`
+	Next      uint64              `json:"next"`
+	Stuck     *stuckNonceResponse `json:"stuck"`
+}
+
+func (s *Service) transactionNonceStatusHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_transactions_nonce").Build()
+
+	status, err := s.transaction.NonceStatus(r.Context())
+	if err != nil {
+		logger.Debug("get nonce status failed", "error", err)
+		logger.Error(nil, "get nonce status failed")
+		jsonhttp.InternalServerError(w, errCantGetNonceStatus)
+		return
+	}
+
+	resp := nonceStatusResponse{
+		Confirmed: status.Confirmed,
+		Pending:   status.Pending,
+		Next:      status.Next,
+	}
+	if status.Stuck != nil {
+		resp.Stuck = &stuckNonceResponse{Nonce: status.Stuck.Nonce}
+		if status.Stuck.TxHash != (common.Hash{}) {
+			resp.Stuck.TransactionHash = &status.Stuck.TxHash
+			resp.Stuck.Since = &status.Stuck.Since
+		}
+	}
+
+	jsonhttp.OK(w, resp)
+}
+
+func (s *Service) transactionNonceResolveHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_transactions_nonce_resolve").Build()
+
+	headers := struct {
+		GasPrice *big.Int `map:"Gas-Price"`
+	}{}
+	if response := s.mapStructure(r.Header, &headers); response != nil {
+		response("invalid header params", logger, w)
+		return
+	}
+	ctx := sctx.SetGasPrice(r.Context(), headers.GasPrice)
+
+	txHash, err := s.transaction.ResolveStuckNonce(ctx)
+	if err != nil {
+		logger.Debug("resolve stuck nonce failed", "error", err)
+		logger.Error(nil, "resolve stuck nonce failed")
+		if errors.Is(err, transaction.ErrNoStuckNonce) {
+			jsonhttp.BadRequest(w, errNoStuckNonce)
+		} else {
+			jsonhttp.InternalServerError(w, errCantResolveNonce)
+		}
+		return
+	}
+
+	jsonhttp.OK(w, transactionHashResponse{
+		TransactionHash: txHash,
+	})
+}
@@ -336,3 +336,96 @@ func TestTransactionResend(t *testing.T) {
 		)
 	})
 }
+
+func TestTransactionNonce(t *testing.T) {
+	t.Parallel()
+
+	txHash := common.HexToHash("0xabcd")
+	since := time.Unix(1616451040, 0)
+
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			TransactionOpts: []mock.Option{
+				mock.WithNonceStatusFunc(func(ctx context.Context) (*transaction.NonceStatus, error) {
+					return &transaction.NonceStatus{
+						Confirmed: 5,
+						Pending:   6,
+						Next:      7,
+						Stuck:     &transaction.StuckNonce{Nonce: 5, TxHash: txHash, Since: since},
+					}, nil
+				}),
+			},
+		})
+
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/transactions/nonce", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.NonceStatusResponse{
+				Confirmed: 5,
+				Pending:   6,
+				Next:      7,
+				Stuck:     &api.StuckNonceResponse{Nonce: 5, TransactionHash: &txHash, Since: &since},
+			}),
+		)
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			TransactionOpts: []mock.Option{
+				mock.WithNonceStatusFunc(func(ctx context.Context) (*transaction.NonceStatus, error) {
+					return nil, errors.New("err")
+				}),
+			},
+		})
+
+		jsonhttptest.Request(t, testServer, http.MethodGet, "/transactions/nonce", http.StatusInternalServerError,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusInternalServerError,
+				Message: api.ErrCantGetNonceStatus,
+			}),
+		)
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			TransactionOpts: []mock.Option{
+				mock.WithResolveStuckNonceFunc(func(ctx context.Context) (common.Hash, error) {
+					return txHash, nil
+				}),
+			},
+		})
+
+		jsonhttptest.Request(t, testServer, http.MethodPost, "/transactions/nonce/resolve", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.TransactionHashResponse{
+				TransactionHash: txHash,
+			}),
+		)
+	})
+
+	t.Run("nothing to resolve", func(t *testing.T) {
+		t.Parallel()
+
+		testServer, _, _, _ := newTestServer(t, testServerOptions{
+			DebugAPI: true,
+			TransactionOpts: []mock.Option{
+				mock.WithResolveStuckNonceFunc(func(ctx context.Context) (common.Hash, error) {
+					return common.Hash{}, transaction.ErrNoStuckNonce
+				}),
+			},
+		})
+
+		jsonhttptest.Request(t, testServer, http.MethodPost, "/transactions/nonce/resolve", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Code:    http.StatusBadRequest,
+				Message: api.ErrNoStuckNonce,
+			}),
+		)
+	})
+}
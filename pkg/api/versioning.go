@@ -0,0 +1,103 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+const (
+	// SwarmAPIVersionHeader selects the api version of the requests to the
+	// unversioned paths, and reports the api version serving the request.
+	SwarmAPIVersionHeader = "Swarm-Api-Version"
+
+	// DeprecationHeader marks the responses of the routes which are
+	// replaced or removed in a later api version.
+	DeprecationHeader = "Deprecation"
+)
+
+const (
+	apiVersionV1 = "v1"
+	apiVersionV2 = "v2"
+
+	rootPathV2 = "/" + apiVersionV2
+)
+
+const errUnsupportedAPIVersion = "unsupported api version"
+
+// pathAPIVersion returns the api version of the path prefix, if any.
+func pathAPIVersion(path string) (string, bool) {
+	for _, v := range []string{apiVersionV1, apiVersionV2} {
+		if path == "/"+v || strings.HasPrefix(path, "/"+v+"/") {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// apiVersionHandler negotiates the api version of the request. The version
+// of a versioned path is given by its prefix. The version of an unversioned
+// path is selected by the SwarmAPIVersionHeader, defaulting to v1, and the
+// request is routed to the path of the selected version.
+func (s *Service) apiVersionHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, ok := pathAPIVersion(r.URL.Path)
+		if !ok {
+			version = apiVersion
+			if v := r.Header.Get(SwarmAPIVersionHeader); v != "" {
+				version = strings.ToLower(v)
+			}
+
+			switch version {
+			case apiVersionV1:
+			case apiVersionV2:
+				r.URL.Path = rootPathV2 + r.URL.Path
+				if r.URL.RawPath != "" {
+					r.URL.RawPath = rootPathV2 + r.URL.RawPath
+				}
+			default:
+				s.logger.Debug("api version negotiation failed", "version", version)
+				jsonhttp.BadRequest(w, errUnsupportedAPIVersion)
+				return
+			}
+		}
+
+		w.Header().Set(SwarmAPIVersionHeader, version)
+		h.ServeHTTP(w, r)
+	})
+}
+
+// deprecatedHandler marks the responses of the handler as deprecated and
+// links to the successor path in v2, if any.
+func deprecatedHandler(successor string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(DeprecationHeader, "true")
+		if successor != "" {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", rootPathV2, successor))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// handleVersions registers the handler on the v1 path, both with and
+// without the version prefix, and on the v2 path. A route which has a
+// different or an empty v2 path is deprecated in v1, and an empty v2
+// path removes the route from v2.
+func (s *Service) handleVersions(v1Path, v2Path string, handler http.Handler) {
+	v1Handler := handler
+	if v1Path != v2Path {
+		v1Handler = deprecatedHandler(v2Path, handler)
+	}
+	s.router.Handle(v1Path, v1Handler)
+	s.router.Handle(rootPath+v1Path, v1Handler)
+
+	if v2Path != "" {
+		s.router.Handle(rootPathV2+v2Path, handler)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+)
+
+func TestAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	ts, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI:   true,
+		BatchStore: mock.New(mock.WithReserveState(&postage.ReserveState{Radius: 5})),
+	})
+	expected := jsonhttptest.WithExpectedJSONResponse(&api.ReserveStateResponse{Radius: 5})
+
+	for _, tc := range []struct {
+		name       string
+		path       string
+		header     string
+		version    string
+		deprecated bool
+	}{
+		{name: "unversioned", path: "/reservestate", version: "v1", deprecated: true},
+		{name: "v1 prefix", path: "/v1/reservestate", version: "v1", deprecated: true},
+		{name: "v1 header", path: "/reservestate", header: "v1", version: "v1", deprecated: true},
+		{name: "v2 prefix", path: "/v2/reserve/state", version: "v2"},
+		{name: "v2 header", path: "/reserve/state", header: "V2", version: "v2"},
+		{name: "prefix over header", path: "/v2/reserve/state", header: "v1", version: "v2"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := []jsonhttptest.Option{expected}
+			if tc.header != "" {
+				opts = append(opts, jsonhttptest.WithRequestHeader(api.SwarmAPIVersionHeader, tc.header))
+			}
+			header := jsonhttptest.Request(t, ts, http.MethodGet, tc.path, http.StatusOK, opts...)
+
+			if got := header.Get(api.SwarmAPIVersionHeader); got != tc.version {
+				t.Fatalf("got api version %q, want %q", got, tc.version)
+			}
+			if got := header.Get(api.DeprecationHeader) == "true"; got != tc.deprecated {
+				t.Fatalf("got deprecated %t, want %t", got, tc.deprecated)
+			}
+			if tc.deprecated {
+				if got, want := header.Get("Link"), `</v2/reserve/state>; rel="successor-version"`; got != want {
+					t.Fatalf("got link %q, want %q", got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("removed in v2", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/v2/reservestate", http.StatusNotFound)
+		jsonhttptest.Request(t, ts, http.MethodGet, "/reservestate", http.StatusNotFound,
+			jsonhttptest.WithRequestHeader(api.SwarmAPIVersionHeader, "v2"),
+		)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		jsonhttptest.Request(t, ts, http.MethodGet, "/reservestate", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmAPIVersionHeader, "v3"),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "unsupported api version",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
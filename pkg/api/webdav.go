@@ -0,0 +1,173 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/manifest/feedwriter"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/ethersphere/bee/pkg/webdav"
+	"github.com/gorilla/mux"
+	dav "golang.org/x/net/webdav"
+)
+
+const webdavPathPrefix = "/webdav/"
+
+// isWebDAVReadMethod reports whether the WebDAV method does not change the resources.
+func isWebDAVReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND":
+		return true
+	}
+	return false
+}
+
+// webdavHandler serves the manifest as the WebDAV file system. The feed
+// manifests resolve to the latest update, and the feed manifests of the
+// feeds owned by the node are writable if the WebDAV postage batch is set,
+// every change being published as the new update of the feed.
+func (s *Service) webdavHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger.WithName("webdav").Build())
+
+	paths := struct {
+		Address swarm.Address `map:"address,resolve" validate:"required"`
+	}{}
+	if response := s.mapStructure(mux.Vars(r), &paths); response != nil {
+		response("invalid path params", logger, w)
+		return
+	}
+
+	i := strings.Index(r.URL.Path, webdavPathPrefix) + len(webdavPathPrefix)
+	prefix := r.URL.Path[:i] + strings.SplitN(r.URL.Path[i:], "/", 2)[0]
+
+	read := isWebDAVReadMethod(r.Method)
+	if !read {
+		// the changes of the feed manifests are serialized,
+		// as every change depends on the previous update
+		s.webdavMu.Lock()
+		defer s.webdavMu.Unlock()
+	}
+
+	ctx := r.Context()
+	root, feed, next, modTime, err := s.webdavRoot(ctx, paths.Address)
+	if err != nil {
+		logger.Debug("webdav: resolve root failed", "address", paths.Address, "error", err)
+		logger.Error(nil, "webdav: resolve root failed")
+		jsonhttp.NotFound(w, "manifest not found")
+		return
+	}
+
+	opts := webdav.Options{ModTime: modTime}
+	wait := noopWaitFn
+	if !read && next != nil && s.webdavWritable(feed) {
+		if r.Header.Get(SwarmPostageBatchIdHeader) == "" {
+			r.Header.Set(SwarmPostageBatchIdHeader, hex.EncodeToString(s.WebDAVPostageBatch))
+		}
+		putter, waitFn, err := s.newStamperPutter(r)
+		if err != nil {
+			logger.Debug("webdav: putter failed", "error", err)
+			logger.Error(nil, "webdav: putter failed")
+			switch {
+			case errors.Is(err, errBatchNotAllowed):
+				jsonhttp.Forbidden(w, "batch not allowed")
+			case errors.Is(err, errBatchUnusable) || errors.Is(err, postage.ErrNotUsable):
+				jsonhttp.UnprocessableEntity(w, "batch not usable yet or does not exist")
+			case errors.Is(err, postage.ErrNotFound):
+				jsonhttp.NotFound(w, "batch with id not found")
+			case errors.Is(err, clockskew.ErrClockSkewed):
+				jsonhttp.ServiceUnavailable(w, err.Error())
+			default:
+				jsonhttp.BadRequest(w, nil)
+			}
+			return
+		}
+		commit, err := feedwriter.FeedCommit(putter, s.signer, feed.Topic, next)
+		if err != nil {
+			logger.Debug("webdav: feed putter failed", "error", err)
+			logger.Error(nil, "webdav: feed putter failed")
+			jsonhttp.InternalServerError(w, "feed putter failed")
+			return
+		}
+		wait = waitFn
+		opts.Storer = putter
+		opts.Mode = requestModePut(r)
+		opts.Commit = commit
+	}
+
+	fs := webdav.New(s.storer, root, opts)
+	h := &dav.Handler{
+		Prefix:     prefix,
+		FileSystem: fs,
+		LockSystem: s.webdavLocks,
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				logger.Debug("webdav: request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+			}
+		},
+	}
+	h.ServeHTTP(w, r)
+
+	if err := wait(); err != nil {
+		logger.Debug("webdav: sync chunks failed", "error", err)
+		logger.Error(nil, "webdav: sync chunks failed")
+	}
+	if !fs.Root().Equal(root) {
+		logger.Debug("webdav: feed updated", "address", paths.Address, "root", fs.Root())
+		s.watchReceipts(logger, fs.Root())
+	}
+}
+
+// webdavRoot returns the root manifest of the WebDAV file system. If the
+// address is the feed manifest, the root is its latest update and the feed
+// is returned with the index of its next update and the time of the update.
+func (s *Service) webdavRoot(ctx context.Context, address swarm.Address) (root swarm.Address, feed *feeds.Feed, next feeds.Index, modTime time.Time, err error) {
+	m, err := manifest.NewDefaultManifestReference(address, loadsave.NewReadonly(s.storer))
+	if err != nil {
+		return root, nil, nil, modTime, err
+	}
+	f, t, period, err := manifestFeedInfo(ctx, m)
+	if err != nil {
+		// not a feed manifest
+		return address, nil, nil, modTime, nil
+	}
+	l, err := s.feedFactory.NewLookupWithPeriod(t, f, period)
+	if err != nil {
+		return root, nil, nil, modTime, err
+	}
+	ch, _, next, err := l.At(ctx, time.Now().Unix(), 0)
+	if err != nil {
+		return root, nil, nil, modTime, err
+	}
+	if ch == nil {
+		return root, nil, nil, modTime, errors.New("no feed update found")
+	}
+	root, at, err := parseFeedUpdate(ch)
+	if err != nil {
+		return root, nil, nil, modTime, err
+	}
+	return root, f, next, time.Unix(at, 0), nil
+}
+
+// webdavWritable reports whether the changes of the feed can be published.
+func (s *Service) webdavWritable(feed *feeds.Feed) bool {
+	if feed == nil || len(s.WebDAVPostageBatch) == 0 || s.signer == nil {
+		return false
+	}
+	owner, err := s.signer.EthereumAddress()
+	return err == nil && owner == feed.Owner
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// nolint:paralleltest
+func TestWebDAV(t *testing.T) {
+	var (
+		logger  = log.Noop
+		storer  = mock.NewStorer()
+		topic   = []byte{0xaa, 0xbb}
+		pk, _   = crypto.GenerateSecp256k1Key()
+		signer  = crypto.NewDefaultSigner(pk)
+		owner   = func() string { o, _ := signer.EthereumAddress(); return fmt.Sprintf("%x", o) }()
+		options = testServerOptions{
+			Storer:             storer,
+			Tags:               tags.NewTags(nil, logger),
+			Logger:             logger,
+			Post:               mockpost.New(mockpost.WithAcceptAll()),
+			Feeds:              factory.New(storer),
+			Signer:             signer,
+			WebDAV:             true,
+			WebDAVPostageBatch: batchOk,
+		}
+		client, _, _, _ = newTestServer(t, options)
+	)
+
+	var upload api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithRequestBody(tarFiles(t, []f{
+			{data: []byte("alpha"), name: "a.txt"},
+			{data: []byte("beta"), name: "b.txt", dir: "docs"},
+		})),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+
+	propfind := func(t *testing.T, resource string, names ...string) {
+		t.Helper()
+
+		var body []byte
+		jsonhttptest.Request(t, client, "PROPFIND", resource, http.StatusMultiStatus,
+			jsonhttptest.WithRequestHeader("Depth", "1"),
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		for _, name := range names {
+			if !bytes.Contains(body, []byte(name)) {
+				t.Fatalf("listing of %s does not contain %s: %s", resource, name, body)
+			}
+		}
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		resource := "/webdav/" + upload.Reference.String()
+
+		propfind(t, resource+"/", resource+"/a.txt", resource+"/docs/")
+		propfind(t, resource+"/docs/", resource+"/docs/b.txt")
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/docs/b.txt", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("beta")),
+		)
+		jsonhttptest.Request(t, client, http.MethodPut, resource+"/c.txt", http.StatusNotFound,
+			jsonhttptest.WithRequestBody(strings.NewReader("gamma")),
+		)
+		jsonhttptest.Request(t, client, http.MethodDelete, resource+"/a.txt", http.StatusMethodNotAllowed)
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		updater, err := sequence.NewUpdater(storer, signer, topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := updater.Update(context.Background(), 1, upload.Reference.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+
+		var feed api.FeedReferenceResponse
+		jsonhttptest.Request(t, client, http.MethodPost, fmt.Sprintf("/feeds/%s/%x", owner, topic), http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithUnmarshalJSONResponse(&feed),
+		)
+		resource := "/webdav/" + feed.Reference.String()
+
+		jsonhttptest.Request(t, client, http.MethodPut, resource+"/c.txt", http.StatusCreated,
+			jsonhttptest.WithRequestBody(strings.NewReader("gamma")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/c.txt", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("gamma")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/a.txt", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("alpha")),
+		)
+
+		jsonhttptest.Request(t, client, "MOVE", resource+"/docs", http.StatusCreated,
+			jsonhttptest.WithRequestHeader("Destination", resource+"/notes"),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/notes/b.txt", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("beta")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/docs/b.txt", http.StatusNotFound)
+
+		jsonhttptest.Request(t, client, "MKCOL", resource+"/empty", http.StatusCreated)
+		propfind(t, resource+"/", resource+"/empty/", resource+"/notes/", resource+"/c.txt")
+
+		jsonhttptest.Request(t, client, http.MethodDelete, resource+"/c.txt", http.StatusNoContent)
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/c.txt", http.StatusNotFound)
+
+		// the original collection is left intact
+		jsonhttptest.Request(t, client, http.MethodGet, "/webdav/"+upload.Reference.String()+"/docs/b.txt", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("beta")),
+		)
+	})
+}
@@ -0,0 +1,24 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/webhook"
+)
+
+type webhookDeliveriesResponse struct {
+	Deliveries []webhook.Delivery `json:"deliveries"`
+}
+
+// webhookDeliveriesHandler returns the status of the latest
+// deliveries of the critical events to the webhooks.
+func (s *Service) webhookDeliveriesHandler(w http.ResponseWriter, _ *http.Request) {
+	jsonhttp.OK(w, webhookDeliveriesResponse{
+		Deliveries: s.webhooks.Deliveries(),
+	})
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/spinlock"
+	"github.com/ethersphere/bee/pkg/webhook"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	t.Parallel()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(receiver.Close)
+
+	service, err := webhook.New(webhook.Options{URLs: []string{receiver.URL}}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = service.Close() })
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		DebugAPI: true,
+		Webhooks: service,
+	})
+
+	service.Notify(webhook.ReserveFull, nil)
+
+	var res api.WebhookDeliveriesResponse
+	err = spinlock.Wait(5*time.Second, func() bool {
+		jsonhttptest.Request(t, client, http.MethodGet, "/webhooks/deliveries", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		return len(res.Deliveries) == 1 && res.Deliveries[0].Status == webhook.DeliveryDelivered
+	})
+	if err != nil {
+		t.Fatalf("got deliveries %+v, want one delivered", res.Deliveries)
+	}
+	d := res.Deliveries[0]
+	if d.Event.Type != webhook.ReserveFull || d.URL != receiver.URL || d.Attempts != 1 || d.StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected delivery %+v", d)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/workingset"
+	"github.com/gorilla/mux"
+)
+
+type workingSetLeaseRequest struct {
+	References []swarm.Address `json:"references"`
+	TTL        int64           `json:"ttl"` // time to live of the lease in seconds
+}
+
+type workingSetRenewRequest struct {
+	TTL int64 `json:"ttl"`
+}
+
+type workingSetLeaseResponse struct {
+	ID         string          `json:"id"`
+	References []swarm.Address `json:"references"`
+	ExpiresAt  time.Time       `json:"expiresAt"`
+}
+
+type workingSetLeasesResponse struct {
+	Leases []workingSetLeaseResponse `json:"leases"`
+}
+
+func newWorkingSetLeaseResponse(l workingset.Lease) workingSetLeaseResponse {
+	return workingSetLeaseResponse{
+		ID:         l.ID,
+		References: l.References,
+		ExpiresAt:  l.ExpiresAt,
+	}
+}
+
+// workingSetPostHandler exempts the chunks of the content from the garbage
+// collection until the lease expires.
+func (s *Service) workingSetPostHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("post_cache_working_set").Build()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+	var req workingSetLeaseRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Debug("unmarshal request body failed", "error", err)
+		logger.Error(nil, "unmarshal request body failed")
+		jsonhttp.BadRequest(w, "invalid request")
+		return
+	}
+	for _, ref := range req.References {
+		if ref.IsZero() {
+			jsonhttp.BadRequest(w, "invalid reference")
+			return
+		}
+	}
+
+	lease, err := s.workingSet.Lease(r.Context(), req.References, time.Duration(req.TTL)*time.Second)
+	switch {
+	case errors.Is(err, workingset.ErrNoReferences):
+		jsonhttp.BadRequest(w, "no references")
+		return
+	case errors.Is(err, workingset.ErrInvalidTTL):
+		jsonhttp.BadRequest(w, "invalid ttl")
+		return
+	case errors.Is(err, storage.ErrNotFound):
+		jsonhttp.NotFound(w, "content not found")
+		return
+	case err != nil:
+		logger.Debug("lease working set failed", "error", err)
+		logger.Error(nil, "lease working set failed")
+		jsonhttp.InternalServerError(w, "lease working set failed")
+		return
+	}
+	jsonhttp.Created(w, newWorkingSetLeaseResponse(lease))
+}
+
+// workingSetListHandler lists the active leases.
+func (s *Service) workingSetListHandler(w http.ResponseWriter, _ *http.Request) {
+	logger := s.logger.WithName("get_cache_working_set").Build()
+
+	leases, err := s.workingSet.Leases()
+	if err != nil {
+		logger.Debug("list leases failed", "error", err)
+		logger.Error(nil, "list leases failed")
+		jsonhttp.InternalServerError(w, "list leases failed")
+		return
+	}
+	res := workingSetLeasesResponse{Leases: make([]workingSetLeaseResponse, 0, len(leases))}
+	for _, l := range leases {
+		res.Leases = append(res.Leases, newWorkingSetLeaseResponse(l))
+	}
+	jsonhttp.OK(w, res)
+}
+
+// workingSetGetHandler returns the active lease.
+func (s *Service) workingSetGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("get_cache_working_set_by_id").Build()
+
+	id := mux.Vars(r)["id"]
+	lease, err := s.workingSet.Get(id)
+	switch {
+	case errors.Is(err, workingset.ErrNotFound):
+		jsonhttp.NotFound(w, "lease not found")
+		return
+	case err != nil:
+		logger.Debug("get lease failed", "id", id, "error", err)
+		logger.Error(nil, "get lease failed")
+		jsonhttp.InternalServerError(w, "get lease failed")
+		return
+	}
+	jsonhttp.OK(w, newWorkingSetLeaseResponse(lease))
+}
+
+// workingSetPutHandler renews the lease to expire after the ttl from now.
+func (s *Service) workingSetPutHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("put_cache_working_set").Build()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Debug("read request body failed", "error", err)
+		logger.Error(nil, "read request body failed")
+		jsonhttp.InternalServerError(w, "cannot read request")
+		return
+	}
+	var req workingSetRenewRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Debug("unmarshal request body failed", "error", err)
+		logger.Error(nil, "unmarshal request body failed")
+		jsonhttp.BadRequest(w, "invalid request")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	lease, err := s.workingSet.Renew(id, time.Duration(req.TTL)*time.Second)
+	switch {
+	case errors.Is(err, workingset.ErrInvalidTTL):
+		jsonhttp.BadRequest(w, "invalid ttl")
+		return
+	case errors.Is(err, workingset.ErrNotFound):
+		jsonhttp.NotFound(w, "lease not found")
+		return
+	case err != nil:
+		logger.Debug("renew lease failed", "id", id, "error", err)
+		logger.Error(nil, "renew lease failed")
+		jsonhttp.InternalServerError(w, "renew lease failed")
+		return
+	}
+	jsonhttp.OK(w, newWorkingSetLeaseResponse(lease))
+}
+
+// workingSetDeleteHandler releases the lease before it expires.
+func (s *Service) workingSetDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.WithName("delete_cache_working_set").Build()
+
+	id := mux.Vars(r)["id"]
+	switch err := s.workingSet.Release(r.Context(), id); {
+	case errors.Is(err, workingset.ErrNotFound):
+		jsonhttp.NotFound(w, "lease not found")
+		return
+	case err != nil:
+		logger.Debug("release lease failed", "id", id, "error", err)
+		logger.Error(nil, "release lease failed")
+		jsonhttp.InternalServerError(w, "release lease failed")
+		return
+	}
+	jsonhttp.OK(w, nil)
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/log"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/workingset"
+)
+
+func TestWorkingSet(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger          = log.Noop
+		storer          = mock.NewStorer()
+		service         = workingset.New(storer, statestore.NewStateStore(), traversal.New(storer), logger)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:     storer,
+			Tags:       tags.NewTags(nil, logger),
+			Logger:     logger,
+			Post:       mockpost.New(mockpost.WithAcceptAll()),
+			WorkingSet: service,
+		})
+	)
+	t.Cleanup(func() { _ = service.Close() })
+
+	var upload api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader([]byte("working set"))),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+	ref := upload.Reference.String()
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/cache/working-set", http.StatusBadRequest,
+		jsonhttptest.WithJSONRequestBody(map[string]interface{}{"references": []string{ref}, "ttl": 0}),
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "invalid ttl",
+			Code:    http.StatusBadRequest,
+		}),
+	)
+	jsonhttptest.Request(t, client, http.MethodPost, "/cache/working-set", http.StatusNotFound,
+		jsonhttptest.WithJSONRequestBody(map[string]interface{}{
+			"references": []string{"aabbcc00000000000000000000000000000000000000000000000000000000ff"},
+			"ttl":        3600,
+		}),
+	)
+
+	var lease api.WorkingSetLeaseResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/cache/working-set", http.StatusCreated,
+		jsonhttptest.WithJSONRequestBody(map[string]interface{}{"references": []string{ref}, "ttl": 3600}),
+		jsonhttptest.WithUnmarshalJSONResponse(&lease),
+	)
+	if len(lease.References) != 1 || !lease.References[0].Equal(upload.Reference) {
+		t.Fatalf("got references %v, want %s", lease.References, ref)
+	}
+	if d := time.Until(lease.ExpiresAt); d <= 0 || d > time.Hour {
+		t.Fatalf("got expiry in %v, want in an hour", d)
+	}
+
+	var leases api.WorkingSetLeasesResponse
+	jsonhttptest.Request(t, client, http.MethodGet, "/cache/working-set", http.StatusOK,
+		jsonhttptest.WithUnmarshalJSONResponse(&leases),
+	)
+	if len(leases.Leases) != 1 || leases.Leases[0].ID != lease.ID {
+		t.Fatalf("got leases %v, want %v", leases.Leases, lease)
+	}
+
+	var renewed api.WorkingSetLeaseResponse
+	jsonhttptest.Request(t, client, http.MethodPut, "/cache/working-set/"+lease.ID, http.StatusOK,
+		jsonhttptest.WithJSONRequestBody(map[string]interface{}{"ttl": 7200}),
+		jsonhttptest.WithUnmarshalJSONResponse(&renewed),
+	)
+	if !renewed.ExpiresAt.After(lease.ExpiresAt) {
+		t.Fatalf("got expiry %v, want after %v", renewed.ExpiresAt, lease.ExpiresAt)
+	}
+	jsonhttptest.Request(t, client, http.MethodGet, "/cache/working-set/"+lease.ID, http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(renewed),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodDelete, "/cache/working-set/"+lease.ID, http.StatusOK)
+	jsonhttptest.Request(t, client, http.MethodGet, "/cache/working-set/"+lease.ID, http.StatusNotFound,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "lease not found",
+			Code:    http.StatusNotFound,
+		}),
+	)
+	jsonhttptest.Request(t, client, http.MethodPut, "/cache/working-set/"+lease.ID, http.StatusNotFound,
+		jsonhttptest.WithJSONRequestBody(map[string]interface{}{"ttl": 7200}),
+	)
+}
@@ -0,0 +1,226 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package audit provides a structured log of the state-changing
+// calls made to the node, written to a size-rotated file which is
+// kept separately from the standard logs.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxSize is the default size in bytes
+	// after which the audit file is rotated.
+	DefaultMaxSize = 100 * 1024 * 1024
+	// DefaultMaxBackups is the default number of
+	// the rotated audit files which are kept.
+	DefaultMaxBackups = 3
+)
+
+// Record is a single entry of the audit log.
+type Record struct {
+	Time       time.Time     `json:"time"`
+	Caller     string        `json:"caller"`
+	Method     string        `json:"method"`
+	Path       string        `json:"path"`
+	ParamsHash string        `json:"paramsHash"`
+	Status     int           `json:"status"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Filter selects the records returned by a query.
+// Zero values of the fields match all records.
+type Filter struct {
+	Method     string
+	PathPrefix string
+	Caller     string
+	Since      time.Time
+	Until      time.Time
+	Limit      int // maximum number of the most recent records
+}
+
+func (f Filter) match(r *Record) bool {
+	switch {
+	case f.Method != "" && !strings.EqualFold(f.Method, r.Method):
+		return false
+	case f.PathPrefix != "" && !strings.HasPrefix(r.Path, f.PathPrefix):
+		return false
+	case f.Caller != "" && f.Caller != r.Caller:
+		return false
+	case !f.Since.IsZero() && r.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && r.Time.After(f.Until):
+		return false
+	}
+	return true
+}
+
+// Options configure the rotation of the audit file.
+type Options struct {
+	MaxSize    int64 // size in bytes after which the file is rotated
+	MaxBackups int   // number of the rotated files which are kept
+}
+
+// Log writes the audit records as JSON lines to a file.
+// Once the file grows over the maximum size, it is renamed
+// to <path>.1, shifting the older backups by one.
+type Log struct {
+	mu   sync.Mutex
+	path string
+	opts Options
+	file *os.File
+	size int64
+}
+
+// New opens or creates the audit file at the given path.
+func New(path string, o Options) (*Log, error) {
+	if o.MaxSize <= 0 {
+		o.MaxSize = DefaultMaxSize
+	}
+	if o.MaxBackups < 0 {
+		o.MaxBackups = 0
+	}
+	l := &Log{path: path, opts: o}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("open audit file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat audit file: %w", err)
+	}
+	l.file = f
+	l.size = info.Size()
+	return nil
+}
+
+// Write appends the record to the audit file.
+func (l *Log) Write(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return os.ErrClosed
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.opts.MaxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+func (l *Log) backup(i int) string {
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
+
+// rotate must be called with the lock held.
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+
+	if l.opts.MaxBackups == 0 {
+		if err := os.Remove(l.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return l.open()
+	}
+
+	for i := l.opts.MaxBackups - 1; i > 0; i-- {
+		if err := os.Rename(l.backup(i), l.backup(i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(l.path, l.backup(1)); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+// Query returns the records matching the filter, ordered from the oldest
+// to the most recent, reading through the rotated files as well.
+func (l *Log) Query(f Filter) ([]Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var records []Record
+	for i := l.opts.MaxBackups; i >= 0; i-- {
+		path := l.path
+		if i > 0 {
+			path = l.backup(i)
+		}
+		if err := readRecords(path, f, &records); err != nil {
+			return nil, err
+		}
+	}
+	if f.Limit > 0 && len(records) > f.Limit {
+		records = records[len(records)-f.Limit:]
+	}
+	return records, nil
+}
+
+func readRecords(path string, f Filter, records *[]Record) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return fmt.Errorf("decode audit record in %s: %w", path, err)
+		}
+		if f.match(&r) {
+			*records = append(*records, r)
+		}
+	}
+	return scanner.Err()
+}
+
+// Close closes the audit file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
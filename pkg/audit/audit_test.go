@@ -0,0 +1,117 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/audit"
+)
+
+func TestLogQuery(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := audit.New(path, audit.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	start := time.Unix(1000, 0)
+	records := []audit.Record{
+		{Time: start, Caller: "a", Method: http.MethodPost, Path: "/bytes", Status: http.StatusCreated},
+		{Time: start.Add(time.Second), Caller: "b", Method: http.MethodDelete, Path: "/pins/abcd", Status: http.StatusOK},
+		{Time: start.Add(2 * time.Second), Caller: "a", Method: http.MethodPost, Path: "/pins/abcd", Status: http.StatusNotFound},
+	}
+	for _, r := range records {
+		if err := l.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter audit.Filter
+		want   []int
+	}{
+		{name: "all", want: []int{0, 1, 2}},
+		{name: "method", filter: audit.Filter{Method: "post"}, want: []int{0, 2}},
+		{name: "path prefix", filter: audit.Filter{PathPrefix: "/pins"}, want: []int{1, 2}},
+		{name: "caller", filter: audit.Filter{Caller: "b"}, want: []int{1}},
+		{name: "since", filter: audit.Filter{Since: start.Add(time.Second)}, want: []int{1, 2}},
+		{name: "until", filter: audit.Filter{Until: start}, want: []int{0}},
+		{name: "limit", filter: audit.Filter{Limit: 2}, want: []int{1, 2}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := l.Query(tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d records, want %d", len(got), len(tc.want))
+			}
+			for i, j := range tc.want {
+				if !got[i].Time.Equal(records[j].Time) || got[i].Path != records[j].Path {
+					t.Fatalf("record %d: got %+v, want %+v", i, got[i], records[j])
+				}
+			}
+		})
+	}
+}
+
+func TestLogRotate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := audit.New(path, audit.Options{MaxSize: 200, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	const count = 20
+	for i := 0; i < count; i++ {
+		if err := l.Write(audit.Record{Time: time.Unix(int64(i), 0), Method: http.MethodPost, Path: "/bytes"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 200 {
+			t.Fatalf("file %s size %d exceeds max size", p, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected no third backup, got %v", err)
+	}
+
+	got, err := l.Query(audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || len(got) >= count {
+		t.Fatalf("got %d records, want less than %d", len(got), count)
+	}
+	for i := 1; i < len(got); i++ {
+		if !got[i-1].Time.Before(got[i].Time) {
+			t.Fatalf("records not ordered: %v, %v", got[i-1].Time, got[i].Time)
+		}
+	}
+	if last := got[len(got)-1].Time; last.Unix() != count-1 {
+		t.Fatalf("got last record at %v, want %d", last, count-1)
+	}
+}
@@ -27,13 +27,15 @@ const loggerName = "auth"
 
 type Authenticator interface {
 	Authorize(string) bool
-	GenerateKey(string, time.Duration) (string, error)
+	GenerateKey(string, string, time.Duration) (string, error)
 	RefreshKey(string, time.Duration) (string, error)
 	Enforce(string, string, string) (bool, error)
+	Tenant(string) (string, error)
 }
 
 type authRecord struct {
 	Role   string    `json:"r"`
+	Tenant string    `json:"t,omitempty"`
 	Expiry time.Time `json:"e"`
 }
 
@@ -59,7 +61,7 @@ func New(encryptionKey, passwordHash string, logger log.Logger) (*authenticator,
 	e = some(where (p.eft == allow))
 
 	[matchers]
-	m = (g(r.sub, p.sub) || r.sub == p.sub) && (keyMatch(r.obj, p.obj) || keyMatch(r.obj, '/v1'+p.obj)) && regexMatch(r.act, p.act)`)
+	m = (g(r.sub, p.sub) || r.sub == p.sub) && (keyMatch(r.obj, p.obj) || keyMatch(r.obj, '/v1'+p.obj) || keyMatch(r.obj, '/v2'+p.obj)) && regexMatch(r.act, p.act)`)
 
 	if err != nil {
 		return nil, err
@@ -95,13 +97,16 @@ func (a *authenticator) Authorize(password string) bool {
 
 var ErrExpiry = errors.New("expiry duration must be a positive number")
 
-func (a *authenticator) GenerateKey(role string, expiryDuration time.Duration) (string, error) {
+// GenerateKey returns the security token granting the role. The token is
+// scoped to the tenant, unless the tenant is empty.
+func (a *authenticator) GenerateKey(role, tenant string, expiryDuration time.Duration) (string, error) {
 	if expiryDuration == 0 {
 		return "", ErrExpiry
 	}
 
 	ar := authRecord{
 		Role:   role,
+		Tenant: tenant,
 		Expiry: time.Now().Add(expiryDuration),
 	}
 
@@ -196,6 +201,31 @@ func (a *authenticator) Enforce(apiKey, obj, act string) (bool, error) {
 	return allow, nil
 }
 
+// Tenant returns the tenant the security token is scoped to,
+// or an empty string if the token is not scoped to any tenant.
+func (a *authenticator) Tenant(apiKey string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(apiKey)
+	if err != nil {
+		return "", err
+	}
+
+	decryptedBytes, err := a.ciph.decrypt(decoded)
+	if err != nil {
+		return "", err
+	}
+
+	var ar authRecord
+	if err := json.Unmarshal(decryptedBytes, &ar); err != nil {
+		return "", err
+	}
+
+	if time.Now().After(ar.Expiry) {
+		return "", ErrTokenExpired
+	}
+
+	return ar.Tenant, nil
+}
+
 type encrypter struct {
 	gcm cipher.AEAD
 }
@@ -243,28 +273,50 @@ func (e encrypter) decrypt(data []byte) ([]byte, error) {
 func applyPolicies(e *casbin.Enforcer) error {
 	_, err := e.AddPolicies([][]string{
 		{"consumer", "/bytes/*", "GET"},
+		{"consumer", "/traces/*", "GET"},
 		{"creator", "/bytes", "POST"},
 		{"consumer", "/chunks/*", "GET"},
 		{"creator", "/chunks", "POST"},
+		{"consumer", "/chunks/has", "POST"},
 		{"consumer", "/bzz/*", "GET"},
 		{"creator", "/bzz/*", "PATCH"},
 		{"creator", "/bzz", "POST"},
 		{"creator", "/bzz?*", "POST"},
+		{"creator", "/publish/*", "POST"},
+		{"creator", "/import/ipfs/*", "POST"},
 		{"consumer", "/bzz/*/*", "GET"},
+		{"consumer", "/webdav/*", "(GET)|(HEAD)|(OPTIONS)|(PROPFIND)"},
+		{"creator", "/webdav/*", "(PUT)|(DELETE)|(MKCOL)|(COPY)|(MOVE)|(PROPPATCH)|(LOCK)|(UNLOCK)"},
 		{"creator", "/tags", "GET"},
 		{"creator", "/tags?*", "GET"},
 		{"creator", "/tags", "POST"},
 		{"creator", "/tags/*", "(GET)|(DELETE)|(PATCH)"},
 		{"creator", "/pins/*", "(GET)|(DELETE)|(POST)"},
+		{"creator", "/content/*", "DELETE"},
+		{"creator", "/stats/content/*", "GET"},
 		{"maintainer", "/pins", "GET"},
+		{"creator", "/cache/working-set", "(GET)|(POST)"},
+		{"creator", "/cache/working-set/*", "(GET)|(PUT)|(DELETE)"},
+		{"creator", "/receipts/*", "GET"},
 		{"creator", "/pss/send/*", "POST"},
 		{"consumer", "/pss/subscribe/*", "GET"},
 		{"creator", "/soc/*/*", "POST"},
+		{"consumer", "/soc/*/*", "GET"},
 		{"creator", "/feeds/*/*", "POST"},
 		{"consumer", "/feeds/*/*", "GET"},
+		{"consumer", "/collections/*", "GET"},
+		{"creator", "/collections/*", "PUT"},
+		{"consumer", "/collections/*/documents", "GET"},
+		{"consumer", "/collections/*/documents?*", "GET"},
+		{"creator", "/collections/*/documents", "POST"},
+		{"consumer", "/collections/*/documents/*", "GET"},
+		{"creator", "/collections/*/documents/*", "(PUT)|(DELETE)"},
+		{"consumer", "/s3/*", "(GET)|(HEAD)"},
+		{"creator", "/s3/*", "(PUT)|(DELETE)"},
 		{"maintainer", "/stamps", "GET"},
 		{"maintainer", "/stamps/*", "GET"},
 		{"maintainer", "/stamps/*/*", "POST"},
+		{"maintainer", "/stamps/restamp", "POST"},
 		{"maintainer", "/stamps/topup/*/*", "PATCH"},
 		{"maintainer", "/stamps/dilute/*/*", "PATCH"},
 		{"maintainer", "/stake", "(GET)|(DELETE)"},
@@ -276,23 +328,44 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"maintainer", "/peers/*", "DELETE"},
 		{"maintainer", "/pingpong/*", "POST"},
 		{"maintainer", "/topology", "GET"},
+		{"maintainer", "/topology/latency", "GET"},
 		{"maintainer", "/welcome-message", "(GET)|(POST)"},
 		{"maintainer", "/balances", "GET"},
 		{"maintainer", "/balances/*", "GET"},
 		{"maintainer", "/accounting", "GET"},
+		{"maintainer", "/accounting/profitability", "GET"},
+		{"maintainer", "/accounting/earnings", "GET"},
+		{"maintainer", "/accounting/snapshots", "GET"},
 		{"maintainer", "/chequebook/cashout/*", "GET"},
 		{"accountant", "/chequebook/cashout/*", "POST"},
 		{"accountant", "/chequebook/withdraw", "POST"},
 		{"accountant", "/chequebook/withdraw?*", "POST"},
+		{"accountant", "/chequebook/withdrawal", "GET"},
 		{"accountant", "/chequebook/deposit", "POST"},
 		{"accountant", "/chequebook/deposit?*", "POST"},
 		{"maintainer", "/chequebook/cheque/*", "GET"},
 		{"maintainer", "/chequebook/cheque", "GET"},
+		{"maintainer", "/chequebook/bounced/*", "GET"},
+		{"maintainer", "/chequebook/bounced", "GET"},
 		{"maintainer", "/chequebook/address", "GET"},
 		{"maintainer", "/chequebook/balance", "GET"},
 		{"maintainer", "/wallet", "GET"},
 		{"maintainer", "/chunks/*", "(GET)|(DELETE)"},
+		{"maintainer", "/debug/chunks/*", "GET"},
+		{"maintainer", "/debug/peers/*", "GET"},
 		{"maintainer", "/reservestate", "GET"},
+		{"maintainer", "/reserve/state", "GET"},
+		{"maintainer", "/reserve/forecast", "GET"},
+		{"maintainer", "/reserve/repair", "POST"},
+		{"maintainer", "/status", "GET"},
+		{"maintainer", "/status/peers", "GET"},
+		{"maintainer", "/availability", "GET"},
+		{"maintainer", "/webhooks/deliveries", "GET"},
+		{"maintainer", "/prewarm/*", "(GET)|(POST)"},
+		{"maintainer", "/denylist", "GET"},
+		{"maintainer", "/denylist/*", "(PUT)|(DELETE)"},
+		{"maintainer", "/audit", "GET"},
+		{"maintainer", "/batches/ws", "GET"},
 		{"maintainer", "/chainstate", "GET"},
 		{"maintainer", "/settlements/*", "GET"},
 		{"maintainer", "/settlements", "GET"},
@@ -304,7 +377,15 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"consumer", "/chunks/stream", "GET"},
 		{"creator", "/stewardship/*", "GET"},
 		{"consumer", "/stewardship/*", "PUT"},
+		{"creator", "/handoff/*/*", "POST"},
 		{"maintainer", "/redistributionstate", "GET"},
+		{"maintainer", "/redistribution/state", "GET"},
+		{"maintainer", "/redistribution/rounds", "GET"},
+		{"maintainer", "/redistribution/proof/*", "GET"},
+		{"maintainer", "/profiles/*", "GET"},
+		{"maintainer", "/debug/pprof/*", "(GET)|(POST)"},
+		{"maintainer", "/debug/vars", "GET"},
+		{"maintainer", "/debug/selftest", "POST"},
 	})
 
 	if err != nil {
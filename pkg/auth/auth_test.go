@@ -64,7 +64,7 @@ func TestExpiry(t *testing.T) {
 		t.Error(err)
 	}
 
-	key, err := a.GenerateKey("consumer", expiryDuration)
+	key, err := a.GenerateKey("consumer", "", expiryDuration)
 	if err != nil {
 		t.Errorf("expected no error, got: %v", err)
 	}
@@ -134,7 +134,7 @@ func TestEnforce(t *testing.T) {
 		t.Run(tC.desc, func(t *testing.T) {
 			t.Parallel()
 
-			apiKey, err := a.GenerateKey(tC.role, expiryDuration)
+			apiKey, err := a.GenerateKey(tC.role, "", expiryDuration)
 
 			if err != nil {
 				t.Errorf("expected no error, got: %v", err)
@@ -152,3 +152,49 @@ func TestEnforce(t *testing.T) {
 		})
 	}
 }
+
+func TestTenant(t *testing.T) {
+	t.Parallel()
+
+	a, err := auth.New(encryptionKey, passwordHash, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := a.GenerateKey("creator", "app", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tenant, err := a.Tenant(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant != "app" {
+		t.Fatalf("got tenant %q, want %q", tenant, "app")
+	}
+
+	refreshed, err := a.RefreshKey(key, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenant, err = a.Tenant(refreshed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant != "app" {
+		t.Fatalf("got tenant %q of the refreshed token, want %q", tenant, "app")
+	}
+
+	key, err = a.GenerateKey("creator", "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenant, err = a.Tenant(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant != "" {
+		t.Fatalf("got tenant %q, want none", tenant)
+	}
+}
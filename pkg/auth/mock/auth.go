@@ -10,6 +10,7 @@ type Auth struct {
 	AuthorizeFunc   func(string) bool
 	GenerateKeyFunc func(string) (string, error)
 	EnforceFunc     func(string, string, string) (bool, error)
+	TenantFunc      func(string) (string, error)
 }
 
 func (ma *Auth) Authorize(u string) bool {
@@ -18,7 +19,7 @@ func (ma *Auth) Authorize(u string) bool {
 	}
 	return ma.AuthorizeFunc(u)
 }
-func (ma *Auth) GenerateKey(k string, _ time.Duration) (string, error) {
+func (ma *Auth) GenerateKey(k, _ string, _ time.Duration) (string, error) {
 	if ma.GenerateKeyFunc == nil {
 		return "", nil
 	}
@@ -33,3 +34,9 @@ func (ma *Auth) RefreshKey(k string, _ time.Duration) (string, error) {
 func (ma *Auth) Enforce(a1 string, a2 string, a3 string) (bool, error) {
 	return ma.EnforceFunc(a1, a2, a3)
 }
+func (ma *Auth) Tenant(k string) (string, error) {
+	if ma.TenantFunc == nil {
+		return "", nil
+	}
+	return ma.TenantFunc(k)
+}
@@ -0,0 +1,257 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package availability aggregates the outcomes of the retrievals of the
+// chunks requested by this node by the neighbourhood of the chunk over
+// time, which helps discovering the neighbourhoods where the content is
+// no longer available.
+package availability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/retrieval"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "availability"
+
+const (
+	// MaxDepth is the number of the leading bits of the chunk address
+	// which identify the neighbourhood the statistics are aggregated by.
+	MaxDepth = 8
+	// Interval is the duration of a window of the statistics.
+	Interval = time.Hour
+	// Retention is the duration the statistics are kept for.
+	Retention = 7 * 24 * time.Hour
+
+	flushInterval = time.Minute
+	keyPrefix     = "availability-"
+)
+
+// Counts is the number of the successful and failed retrievals.
+type Counts struct {
+	Success uint64 `json:"success"`
+	Failure uint64 `json:"failure"`
+}
+
+// Window holds the retrieval statistics of the neighbourhoods,
+// keyed by the MaxDepth leading bits of the chunk addresses,
+// for the Interval starting at Start.
+type Window struct {
+	Start          time.Time        `json:"start"`
+	Neighbourhoods map[uint8]Counts `json:"neighbourhoods"`
+}
+
+func (w *Window) add(o Window) {
+	if w.Neighbourhoods == nil {
+		w.Neighbourhoods = make(map[uint8]Counts)
+	}
+	for n, c := range o.Neighbourhoods {
+		v := w.Neighbourhoods[n]
+		v.Success += c.Success
+		v.Failure += c.Failure
+		w.Neighbourhoods[n] = v
+	}
+}
+
+func windowKey(start time.Time) string {
+	// zero padded so that the windows are iterated in the chronological order
+	return fmt.Sprintf("%s%020d", keyPrefix, start.Unix())
+}
+
+// Service records the outcomes of the retrievals and
+// periodically persists them in the state store.
+type Service struct {
+	stateStore storage.StateStorer
+	logger     log.Logger
+	now        func() time.Time
+
+	mu      sync.Mutex       // guards pending and serializes the flushes
+	pending map[int64]Window // recorded but not yet persisted windows by the start
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New returns a new Service which persists the statistics in the background.
+func New(stateStore storage.StateStorer, logger log.Logger) *Service {
+	s := &Service{
+		stateStore: stateStore,
+		logger:     logger.WithName(loggerName).Register(),
+		now:        time.Now,
+		pending:    make(map[int64]Window),
+		quit:       make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.flushLoop()
+
+	return s
+}
+
+// Record counts the outcome of the retrieval of the chunk.
+func (s *Service) Record(addr swarm.Address, success bool) {
+	n := addr.Bytes()[0] >> (8 - MaxDepth)
+	start := s.now().Truncate(Interval)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.pending[start.Unix()]
+	if !ok {
+		w = Window{Start: start, Neighbourhoods: make(map[uint8]Counts)}
+		s.pending[start.Unix()] = w
+	}
+	c := w.Neighbourhoods[n]
+	if success {
+		c.Success++
+	} else {
+		c.Failure++
+	}
+	w.Neighbourhoods[n] = c
+}
+
+// Windows returns the statistics of the windows overlapping
+// the time since the given time, in the chronological order.
+func (s *Service) Windows(since time.Time) ([]Window, error) {
+	since = since.Truncate(Interval)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	windows := make(map[int64]Window)
+	if err := s.stateStore.Iterate(keyPrefix, func(key, value []byte) (bool, error) {
+		start, err := strconv.ParseInt(strings.TrimPrefix(string(key), keyPrefix), 10, 64)
+		if err != nil {
+			return true, fmt.Errorf("invalid window key %q: %w", key, err)
+		}
+		if start < since.Unix() {
+			return false, nil
+		}
+		var w Window
+		if err := json.Unmarshal(value, &w); err != nil {
+			return true, fmt.Errorf("invalid window: %w", err)
+		}
+		windows[start] = w
+		return false, nil
+	}); err != nil {
+		return nil, err
+	}
+	for start, p := range s.pending {
+		if start < since.Unix() {
+			continue
+		}
+		w, ok := windows[start]
+		if !ok {
+			w = Window{Start: p.Start, Neighbourhoods: make(map[uint8]Counts)}
+		}
+		w.add(p)
+		windows[start] = w
+	}
+
+	res := make([]Window, 0, len(windows))
+	for _, w := range windows {
+		res = append(res, w)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Start.Before(res[j].Start)
+	})
+	return res, nil
+}
+
+// flush persists the pending windows and deletes the windows
+// older than the retention.
+func (s *Service) flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for start, p := range s.pending {
+		key := windowKey(p.Start)
+		w := Window{Start: p.Start, Neighbourhoods: make(map[uint8]Counts)}
+		switch err := s.stateStore.Get(key, &w); {
+		case errors.Is(err, storage.ErrNotFound):
+		case err != nil:
+			return fmt.Errorf("get window: %w", err)
+		}
+		w.add(p)
+		if err := s.stateStore.Put(key, w); err != nil {
+			return fmt.Errorf("put window: %w", err)
+		}
+		delete(s.pending, start)
+	}
+
+	expired := windowKey(s.now().Add(-Retention).Truncate(Interval))
+	var keys []string
+	if err := s.stateStore.Iterate(keyPrefix, func(key, _ []byte) (bool, error) {
+		if string(key) < expired {
+			keys = append(keys, string(key))
+		}
+		return false, nil
+	}); err != nil {
+		return fmt.Errorf("iterate windows: %w", err)
+	}
+	for _, key := range keys {
+		if err := s.stateStore.Delete(key); err != nil {
+			return fmt.Errorf("delete window: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) flushLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.flush(); err != nil {
+				s.logger.Error(err, "persist retrieval statistics failed")
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// Close stops the background persisting and persists the pending statistics.
+func (s *Service) Close() error {
+	close(s.quit)
+	s.wg.Wait()
+	return s.flush()
+}
+
+// Retrieval returns the retrieval which records the outcomes of the
+// retrievals of the chunks requested by this node in the statistics.
+func (s *Service) Retrieval(r retrieval.Interface) retrieval.Interface {
+	return &recordingRetrieval{Interface: r, service: s}
+}
+
+type recordingRetrieval struct {
+	retrieval.Interface
+	service *Service
+}
+
+func (r *recordingRetrieval) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.Address) (swarm.Chunk, error) {
+	ch, err := r.Interface.RetrieveChunk(ctx, addr, sourcePeerAddr)
+	// only the requests of this node are recorded and the
+	// requests canceled by the caller do not tell anything
+	if sourcePeerAddr.IsZero() && !errors.Is(err, context.Canceled) {
+		r.service.Record(addr, err == nil)
+	}
+	return ch, err
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package availability_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/availability"
+	"github.com/ethersphere/bee/pkg/log"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+type retrievalFunc func(context.Context, swarm.Address, swarm.Address) (swarm.Chunk, error)
+
+func (f retrievalFunc) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.Address) (swarm.Chunk, error) {
+	return f(ctx, addr, sourcePeerAddr)
+}
+
+func TestAvailability(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store   = statestore.NewStateStore()
+		now     = time.Unix(1700000000, 0).Truncate(availability.Interval)
+		service = availability.New(store, log.Noop)
+		errFail = errors.New("fail")
+		a       = swarm.MustParseHexAddress("0a00000000000000000000000000000000000000000000000000000000000000")
+		b       = swarm.MustParseHexAddress("ff00000000000000000000000000000000000000000000000000000000000000")
+		peer    = swarm.MustParseHexAddress("0100000000000000000000000000000000000000000000000000000000000000")
+	)
+	service.SetNow(func() time.Time { return now })
+
+	retrieval := service.Retrieval(retrievalFunc(func(_ context.Context, addr, _ swarm.Address) (swarm.Chunk, error) {
+		if addr.Equal(b) {
+			return nil, errFail
+		}
+		return swarm.NewChunk(addr, nil), nil
+	}))
+	retrieve := func(t *testing.T, addr, source swarm.Address) {
+		t.Helper()
+
+		_, _ = retrieval.RetrieveChunk(context.Background(), addr, source)
+	}
+
+	retrieve(t, a, swarm.ZeroAddress)
+	retrieve(t, a, swarm.ZeroAddress)
+	retrieve(t, b, swarm.ZeroAddress)
+	// forwarded requests are not recorded
+	retrieve(t, b, peer)
+
+	if err := service.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(availability.Interval + time.Minute)
+	service.SetNow(func() time.Time { return now })
+	retrieve(t, b, swarm.ZeroAddress)
+
+	want := []availability.Window{{
+		Start: now.Add(-availability.Interval - time.Minute),
+		Neighbourhoods: map[uint8]availability.Counts{
+			0x0a: {Success: 2},
+			0xff: {Failure: 1},
+		},
+	}, {
+		Start: now.Truncate(availability.Interval),
+		Neighbourhoods: map[uint8]availability.Counts{
+			0xff: {Failure: 1},
+		},
+	}}
+
+	assertWindows := func(t *testing.T, s *availability.Service, since time.Time, want []availability.Window) {
+		t.Helper()
+
+		got, err := s.Windows(since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("got %d windows, want %d", len(got), len(want))
+		}
+		for i := range want {
+			if !got[i].Start.Equal(want[i].Start) || !reflect.DeepEqual(got[i].Neighbourhoods, want[i].Neighbourhoods) {
+				t.Fatalf("got window %d %+v, want %+v", i, got[i], want[i])
+			}
+		}
+	}
+
+	assertWindows(t, service, now.Add(-24*time.Hour), want)
+	assertWindows(t, service, now, want[1:])
+
+	// the statistics survive the restart
+	if err := service.Close(); err != nil {
+		t.Fatal(err)
+	}
+	restarted := availability.New(store, log.Noop)
+	t.Cleanup(func() { _ = restarted.Close() })
+	assertWindows(t, restarted, now.Add(-24*time.Hour), want)
+
+	// the windows older than the retention are deleted
+	restarted.SetNow(func() time.Time { return now.Add(availability.Retention) })
+	if err := restarted.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	assertWindows(t, restarted, now.Add(-24*time.Hour), want[1:])
+}
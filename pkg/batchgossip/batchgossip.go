@@ -0,0 +1,314 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package batchgossip exposes the protocol with which the neighbours gossip
+// the IDs of the recently expired batches. The nodes lagging behind on the
+// chain sync expire the batches reported by enough neighbours, so that they
+// evict the chunks of the expired batches and stop accepting their stamps
+// sooner, which shortens the window in which the reserve is polluted.
+package batchgossip
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/batchgossip/pb"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/protobuf"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "batchgossip"
+
+const (
+	protocolName    = "batchgossip"
+	protocolVersion = "1.0.0"
+	streamName      = "expired"
+)
+
+const (
+	// DefaultQuorum is the default number of the neighbours which
+	// must report the expiry of a batch before the node expires it.
+	DefaultQuorum = 2
+	// DefaultMaxLag is the default number of blocks in which the batch
+	// must expire according to the local chain state to be expired on the
+	// reports of the neighbours, which bounds the effect of false reports.
+	DefaultMaxLag = 720
+
+	maxBatchIDs    = 256 // maximum number of the batch IDs in a message
+	flushInterval  = 5 * time.Second
+	reportTTL      = time.Hour
+	messageTimeout = 10 * time.Second
+)
+
+var errTooManyBatchIDs = errors.New("too many batch ids")
+
+// Options are the options of the Service.
+type Options struct {
+	Quorum int    // neighbours which must report the expiry of a batch
+	MaxLag uint64 // blocks in which the reported batch must expire locally
+}
+
+// BatchEventSubscriber delivers the batch events.
+type BatchEventSubscriber interface {
+	Subscribe() (c <-chan postage.BatchEvent, unsubscribe func())
+}
+
+type topologyDriver interface {
+	topology.EachNeighbor
+	topology.NeighborhoodDepther
+}
+
+// report collects the neighbours which reported the expiry of a batch.
+type report struct {
+	peers   map[string]struct{}
+	created time.Time
+}
+
+type Service struct {
+	streamer   p2p.Streamer
+	batchStore postage.Storer
+	topology   topologyDriver
+	base       swarm.Address
+	logger     log.Logger
+	metrics    metrics
+	quorum     int
+	maxLag     uint64
+
+	mu      sync.Mutex
+	pending [][]byte           // expired batches to be gossiped
+	reports map[string]*report // expiries reported by the neighbours
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New returns the Service which gossips the batches which expire
+// according to the events of the subscriber to the neighbours.
+func New(streamer p2p.Streamer, batchStore postage.Storer, topology topologyDriver, base swarm.Address, events BatchEventSubscriber, logger log.Logger, o Options) *Service {
+	if o.Quorum <= 0 {
+		o.Quorum = DefaultQuorum
+	}
+	if o.MaxLag == 0 {
+		o.MaxLag = DefaultMaxLag
+	}
+
+	s := &Service{
+		streamer:   streamer,
+		batchStore: batchStore,
+		topology:   topology,
+		base:       base,
+		logger:     logger.WithName(loggerName).Register(),
+		metrics:    newMetrics(),
+		quorum:     o.Quorum,
+		maxLag:     o.MaxLag,
+		reports:    make(map[string]*report),
+		quit:       make(chan struct{}),
+	}
+
+	c, unsubscribe := events.Subscribe()
+	s.wg.Add(1)
+	go s.worker(c, unsubscribe)
+
+	return s
+}
+
+func (s *Service) Protocol() p2p.ProtocolSpec {
+	return p2p.ProtocolSpec{
+		Name:    protocolName,
+		Version: protocolVersion,
+		StreamSpecs: []p2p.StreamSpec{
+			{
+				Name:    streamName,
+				Handler: s.handler,
+			},
+		},
+	}
+}
+
+func (s *Service) Close() error {
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Service) worker(events <-chan postage.BatchEvent, unsubscribe func()) {
+	defer s.wg.Done()
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.quit
+		cancel()
+	}()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.quit:
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if e.Type == postage.BatchExpired {
+				s.mu.Lock()
+				s.pending = append(s.pending, e.BatchID)
+				s.mu.Unlock()
+			}
+		case <-ticker.C:
+			s.broadcast(ctx)
+		}
+	}
+}
+
+// broadcast sends the pending expired batches to the neighbours.
+func (s *Service) broadcast(ctx context.Context) {
+	s.mu.Lock()
+	ids := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > maxBatchIDs {
+			n = maxBatchIDs
+		}
+		batch := ids[:n]
+		ids = ids[n:]
+
+		_ = s.topology.EachNeighbor(func(peer swarm.Address, _ uint8) (bool, bool, error) {
+			if err := s.sendExpired(ctx, peer, batch); err != nil {
+				s.logger.Debug("gossip expired batches failed", "peer_address", peer, "error", err)
+			}
+			return ctx.Err() != nil, false, nil
+		})
+	}
+}
+
+func (s *Service) sendExpired(ctx context.Context, peer swarm.Address, ids [][]byte) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, messageTimeout)
+	defer cancel()
+
+	stream, err := s.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
+	if err != nil {
+		return fmt.Errorf("new stream: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = stream.Reset()
+		} else {
+			go stream.FullClose()
+		}
+	}()
+
+	w := protobuf.NewWriter(stream)
+	if err := w.WriteMsgWithContext(ctx, &pb.Expired{BatchIDs: ids}); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	s.metrics.SentBatchIDs.Add(float64(len(ids)))
+	return nil
+}
+
+func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) error {
+	ctx, cancel := context.WithTimeout(ctx, messageTimeout)
+	defer cancel()
+
+	r := protobuf.NewReader(stream)
+	var msg pb.Expired
+	if err := r.ReadMsgWithContext(ctx, &msg); err != nil {
+		_ = stream.Reset()
+		return fmt.Errorf("read message: %w", err)
+	}
+	go stream.FullClose()
+
+	if len(msg.BatchIDs) > maxBatchIDs {
+		return errTooManyBatchIDs
+	}
+	// only the neighbours are trusted to follow the same reserve
+	if swarm.Proximity(s.base.Bytes(), p.Address.Bytes()) < s.topology.NeighborhoodDepth() {
+		return nil
+	}
+
+	s.metrics.ReceivedBatchIDs.Add(float64(len(msg.BatchIDs)))
+	for _, id := range msg.BatchIDs {
+		if len(id) != swarm.HashSize {
+			continue
+		}
+		if err := s.report(p.Address, id); err != nil {
+			s.logger.Debug("expire reported batch failed", "batch_id", hex.EncodeToString(id), "error", err)
+		}
+	}
+	return nil
+}
+
+// report records the expiry of the batch reported by the peer and expires
+// the batch once it is reported by the quorum of the neighbours.
+func (s *Service) report(peer swarm.Address, id []byte) error {
+	b, err := s.batchStore.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil // already expired or unknown
+		}
+		return err
+	}
+	if !s.expiresSoon(b) {
+		s.metrics.RejectedBatchIDs.Inc()
+		return nil
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	for k, r := range s.reports {
+		if now.Sub(r.created) > reportTTL {
+			delete(s.reports, k)
+		}
+	}
+	r, ok := s.reports[string(id)]
+	if !ok {
+		r = &report{peers: make(map[string]struct{}), created: now}
+		s.reports[string(id)] = r
+	}
+	r.peers[peer.ByteString()] = struct{}{}
+	reached := len(r.peers) >= s.quorum
+	if reached {
+		delete(s.reports, string(id))
+	}
+	s.mu.Unlock()
+
+	if !reached {
+		return nil
+	}
+	if err := s.batchStore.Expire(id); err != nil {
+		return err
+	}
+	s.metrics.ExpiredBatches.Inc()
+	s.logger.Debug("batch expired on the reports of the neighbours", "batch_id", hex.EncodeToString(id))
+	return nil
+}
+
+// expiresSoon reports whether the batch expires within
+// the maximal lag according to the local chain state.
+func (s *Service) expiresSoon(b *postage.Batch) bool {
+	cs := s.batchStore.GetChainState()
+	if cs == nil || cs.TotalAmount == nil || cs.CurrentPrice == nil || b.Value == nil {
+		return false
+	}
+	threshold := new(big.Int).Mul(cs.CurrentPrice, new(big.Int).SetUint64(s.maxLag))
+	threshold.Add(threshold, cs.TotalAmount)
+	return b.Value.Cmp(threshold) <= 0
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package batchgossip_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/batchgossip"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/postage"
+	batchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+type neighbourhood uint8
+
+func (n neighbourhood) NeighborhoodDepth() uint8                  { return uint8(n) }
+func (neighbourhood) EachNeighbor(topology.EachPeerFunc) error    { return nil }
+func (neighbourhood) EachNeighborRev(topology.EachPeerFunc) error { return nil }
+
+func TestExpiredGossip(t *testing.T) {
+	t.Parallel()
+
+	chainState := &postage.ChainState{
+		Block:        100,
+		TotalAmount:  big.NewInt(1000),
+		CurrentPrice: big.NewInt(10),
+	}
+
+	for _, tc := range []struct {
+		name    string
+		value   int64
+		expired bool
+	}{
+		{
+			name:    "expires soon",
+			value:   1000 + 10*batchgossip.DefaultMaxLag,
+			expired: true,
+		},
+		{
+			name:    "expires late",
+			value:   1000 + 10*batchgossip.DefaultMaxLag + 1,
+			expired: false,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			batch := postagetesting.MustNewBatch(postagetesting.WithValue(tc.value))
+			bs := batchstore.New(batchstore.WithChainState(chainState), batchstore.WithBatch(batch))
+
+			base := swarm.RandAddress(t)
+			server := batchgossip.New(nil, bs, neighbourhood(0), base, postage.NewBatchEventFeed(), log.Noop, batchgossip.Options{})
+			t.Cleanup(func() { _ = server.Close() })
+
+			// the batch is expired only once reported by the quorum of the neighbours
+			for i := 0; i < batchgossip.DefaultQuorum; i++ {
+				if got := bs.Expired(); len(got) != 0 {
+					t.Fatalf("batch expired after %d reports, want %d", i, batchgossip.DefaultQuorum)
+				}
+
+				recorder := streamtest.New(
+					streamtest.WithProtocols(server.Protocol()),
+					streamtest.WithBaseAddr(swarm.RandAddress(t)),
+				)
+				client := batchgossip.New(recorder, batchstore.New(), neighbourhood(0), swarm.RandAddress(t), postage.NewBatchEventFeed(), log.Noop, batchgossip.Options{})
+				t.Cleanup(func() { _ = client.Close() })
+
+				if err := batchgossip.SendExpired(client, context.Background(), base, [][]byte{batch.ID}); err != nil {
+					t.Fatal(err)
+				}
+				records, err := recorder.Records(base, batchgossip.ProtocolName, batchgossip.ProtocolVersion, batchgossip.StreamName)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := records[0].Err(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := bs.Expired()
+			if !tc.expired {
+				if len(got) != 0 {
+					t.Fatalf("got %d expired batches, want none", len(got))
+				}
+				return
+			}
+			if len(got) != 1 || !bytes.Equal(got[0], batch.ID) {
+				t.Fatalf("got expired batches %x, want %x", got, batch.ID)
+			}
+		})
+	}
+}
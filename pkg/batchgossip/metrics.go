@@ -0,0 +1,52 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package batchgossip
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	SentBatchIDs     prometheus.Counter
+	ReceivedBatchIDs prometheus.Counter
+	RejectedBatchIDs prometheus.Counter
+	ExpiredBatches   prometheus.Counter
+}
+
+func newMetrics() metrics {
+	subsystem := "batchgossip"
+
+	return metrics{
+		SentBatchIDs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "sent_batch_ids",
+			Help:      "Number of the expired batch IDs sent to the neighbours.",
+		}),
+		ReceivedBatchIDs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "received_batch_ids",
+			Help:      "Number of the expired batch IDs received from the neighbours.",
+		}),
+		RejectedBatchIDs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "rejected_batch_ids",
+			Help:      "Number of the reported batches which do not expire soon according to the local chain state.",
+		}),
+		ExpiredBatches: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "expired_batches",
+			Help:      "Number of the batches expired on the reports of the neighbours.",
+		}),
+	}
+}
+
+func (s *Service) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
@@ -0,0 +1,260 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package billing takes the periodic snapshots of the accounting balances
+// and of the settlements of the peers, so that the operators who resell the
+// capacity of their gateway can export the billing data of a date range
+// directly from the node. The amounts are in PLUR, the settlements are the
+// cumulative totals at the time of the snapshot.
+package billing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/settlement"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "billing"
+
+const (
+	// retention is the age of the snapshots after which they are pruned.
+	retention         = 400 * 24 * time.Hour
+	snapshotKeyPrefix = "billing_snapshot_"
+)
+
+// Balancer reports the balances of the peers.
+type Balancer interface {
+	// CompensatedBalances returns the balances of the peers with
+	// the surplus balances and the time settlements accounted.
+	CompensatedBalances() (map[string]*big.Int, error)
+	// Balances returns the consumed balances of the peers.
+	Balances() (map[string]*big.Int, error)
+}
+
+// Peer is the accounting state of a single peer at the time of the snapshot.
+type Peer struct {
+	Peer                    string   `json:"peer"`
+	Balance                 *big.Int `json:"balance"`
+	ConsumedBalance         *big.Int `json:"consumedBalance"`
+	SettlementsReceived     *big.Int `json:"settlementsReceived"`
+	SettlementsSent         *big.Int `json:"settlementsSent"`
+	TimeSettlementsReceived *big.Int `json:"timeSettlementsReceived"`
+	TimeSettlementsSent     *big.Int `json:"timeSettlementsSent"`
+}
+
+// Snapshot is the accounting state of the peers at the given time,
+// the peers are sorted by their overlay address.
+type Snapshot struct {
+	Time  time.Time `json:"time"`
+	Peers []Peer    `json:"peers"`
+}
+
+// snapshotKey orders the keys of the snapshots by their time.
+func snapshotKey(t time.Time) string {
+	return fmt.Sprintf("%s%020d", snapshotKeyPrefix, t.UnixNano())
+}
+
+func snapshotTime(key string) (time.Time, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(key, snapshotKeyPrefix), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid snapshot key %q: %w", key, err)
+	}
+	return time.Unix(0, n).UTC(), nil
+}
+
+// Service takes the snapshots periodically in the background.
+type Service struct {
+	logger          log.Logger
+	store           storage.StateStorer
+	balances        Balancer
+	settlements     settlement.Interface // nil if the swap is disabled
+	timeSettlements settlement.Interface
+	interval        time.Duration
+	now             func() time.Time
+
+	mu   sync.Mutex // serializes the snapshots and their pruning
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New returns a new Service which takes a snapshot every interval.
+func New(logger log.Logger, store storage.StateStorer, balances Balancer, settlements, timeSettlements settlement.Interface, interval time.Duration) *Service {
+	s := &Service{
+		logger:          logger.WithName(loggerName).Register(),
+		store:           store,
+		balances:        balances,
+		settlements:     settlements,
+		timeSettlements: timeSettlements,
+		interval:        interval,
+		now:             time.Now,
+		quit:            make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.snapshotLoop()
+
+	return s
+}
+
+func (s *Service) snapshotLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+		if err := s.snapshot(); err != nil {
+			s.logger.Error(err, "accounting snapshot failed")
+		}
+	}
+}
+
+// snapshot stores the current accounting state of the
+// peers and prunes the snapshots older than the retention.
+func (s *Service) snapshot() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	peers := make(map[string]*Peer)
+	peer := func(address string) *Peer {
+		p, ok := peers[address]
+		if !ok {
+			p = &Peer{
+				Peer:                    address,
+				Balance:                 new(big.Int),
+				ConsumedBalance:         new(big.Int),
+				SettlementsReceived:     new(big.Int),
+				SettlementsSent:         new(big.Int),
+				TimeSettlementsReceived: new(big.Int),
+				TimeSettlementsSent:     new(big.Int),
+			}
+			peers[address] = p
+		}
+		return p
+	}
+	collect := func(name string, amounts func() (map[string]*big.Int, error), field func(*Peer) *big.Int) error {
+		m, err := amounts()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for address, amount := range m {
+			field(peer(address)).Set(amount)
+		}
+		return nil
+	}
+
+	if err := collect("balances", s.balances.CompensatedBalances, func(p *Peer) *big.Int { return p.Balance }); err != nil {
+		return err
+	}
+	if err := collect("consumed balances", s.balances.Balances, func(p *Peer) *big.Int { return p.ConsumedBalance }); err != nil {
+		return err
+	}
+	if s.settlements != nil {
+		if err := collect("settlements received", s.settlements.SettlementsReceived, func(p *Peer) *big.Int { return p.SettlementsReceived }); err != nil {
+			return err
+		}
+		if err := collect("settlements sent", s.settlements.SettlementsSent, func(p *Peer) *big.Int { return p.SettlementsSent }); err != nil {
+			return err
+		}
+	}
+	if err := collect("time settlements received", s.timeSettlements.SettlementsReceived, func(p *Peer) *big.Int { return p.TimeSettlementsReceived }); err != nil {
+		return err
+	}
+	if err := collect("time settlements sent", s.timeSettlements.SettlementsSent, func(p *Peer) *big.Int { return p.TimeSettlementsSent }); err != nil {
+		return err
+	}
+
+	snapshot := Snapshot{Time: now, Peers: make([]Peer, 0, len(peers))}
+	for _, p := range peers {
+		snapshot.Peers = append(snapshot.Peers, *p)
+	}
+	sort.Slice(snapshot.Peers, func(i, j int) bool {
+		return snapshot.Peers[i].Peer < snapshot.Peers[j].Peer
+	})
+	if err := s.store.Put(snapshotKey(now), snapshot); err != nil {
+		return fmt.Errorf("store snapshot: %w", err)
+	}
+
+	return s.prune(now.Add(-retention))
+}
+
+// prune deletes the snapshots taken before the given time.
+func (s *Service) prune(before time.Time) error {
+	var keys []string
+	if err := s.store.Iterate(snapshotKeyPrefix, func(key, _ []byte) (bool, error) {
+		t, err := snapshotTime(string(key))
+		if err != nil {
+			return true, err
+		}
+		if t.Before(before) {
+			keys = append(keys, string(key))
+		}
+		return false, nil
+	}); err != nil {
+		return fmt.Errorf("iterate snapshots: %w", err)
+	}
+
+	for _, key := range keys {
+		if err := s.store.Delete(key); err != nil {
+			return fmt.Errorf("delete snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// Snapshots returns the snapshots taken in the [from, to)
+// time range, starting with the oldest one.
+func (s *Service) Snapshots(from, to time.Time) ([]Snapshot, error) {
+	if !from.Before(to) {
+		return nil, errors.New("empty time range")
+	}
+
+	var snapshots []Snapshot
+	if err := s.store.Iterate(snapshotKeyPrefix, func(key, value []byte) (bool, error) {
+		t, err := snapshotTime(string(key))
+		if err != nil {
+			return true, err
+		}
+		if t.Before(from) || !t.Before(to) {
+			return false, nil
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(value, &snapshot); err != nil {
+			return true, fmt.Errorf("invalid snapshot %q: %w", key, err)
+		}
+		snapshots = append(snapshots, snapshot)
+		return false, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+	return snapshots, nil
+}
+
+// Close stops taking the snapshots.
+func (s *Service) Close() error {
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
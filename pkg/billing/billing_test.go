@@ -0,0 +1,135 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package billing_test
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/accounting/mock"
+	"github.com/ethersphere/bee/pkg/billing"
+	"github.com/ethersphere/bee/pkg/log"
+	swapmock "github.com/ethersphere/bee/pkg/settlement/swap/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+func amounts(kv ...interface{}) func() (map[string]*big.Int, error) {
+	return func() (map[string]*big.Int, error) {
+		m := make(map[string]*big.Int)
+		for i := 0; i < len(kv); i += 2 {
+			m[kv[i].(string)] = big.NewInt(int64(kv[i+1].(int)))
+		}
+		return m, nil
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	t.Parallel()
+
+	var (
+		balance  = 10
+		balances = mock.NewAccounting(
+			mock.WithCompensatedBalancesFunc(func() (map[string]*big.Int, error) {
+				return amounts("a", balance, "b", -5)()
+			}),
+			mock.WithBalancesFunc(amounts("a", 7)),
+		)
+		settlements     = swapmock.New(swapmock.WithSettlementsRecvFunc(amounts("a", 100)), swapmock.WithSettlementsSentFunc(amounts("c", 20)))
+		timeSettlements = swapmock.New(swapmock.WithSettlementsRecvFunc(amounts("b", 3)), swapmock.WithSettlementsSentFunc(amounts()))
+		service         = billing.New(log.Noop, statestore.NewStateStore(), balances, settlements, timeSettlements, time.Hour)
+	)
+	testutil.CleanupCloser(t, service)
+
+	start := time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		now := start.Add(time.Duration(i) * time.Hour)
+		service.SetNow(func() time.Time { return now })
+		if err := service.Snapshot(); err != nil {
+			t.Fatal(err)
+		}
+		balance += 10
+	}
+
+	snapshots, err := service.Snapshots(start.Add(time.Hour), start.Add(3*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(snapshots))
+	}
+	if !snapshots[0].Time.Equal(start.Add(time.Hour)) || !snapshots[1].Time.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("got snapshots at %v and %v", snapshots[0].Time, snapshots[1].Time)
+	}
+
+	want := []billing.Peer{{
+		Peer:                    "a",
+		Balance:                 big.NewInt(30),
+		ConsumedBalance:         big.NewInt(7),
+		SettlementsReceived:     big.NewInt(100),
+		SettlementsSent:         big.NewInt(0),
+		TimeSettlementsReceived: big.NewInt(0),
+		TimeSettlementsSent:     big.NewInt(0),
+	}, {
+		Peer:                    "b",
+		Balance:                 big.NewInt(-5),
+		ConsumedBalance:         big.NewInt(0),
+		SettlementsReceived:     big.NewInt(0),
+		SettlementsSent:         big.NewInt(0),
+		TimeSettlementsReceived: big.NewInt(3),
+		TimeSettlementsSent:     big.NewInt(0),
+	}, {
+		Peer:                    "c",
+		Balance:                 big.NewInt(0),
+		ConsumedBalance:         big.NewInt(0),
+		SettlementsReceived:     big.NewInt(0),
+		SettlementsSent:         big.NewInt(20),
+		TimeSettlementsReceived: big.NewInt(0),
+		TimeSettlementsSent:     big.NewInt(0),
+	}}
+	if !reflect.DeepEqual(snapshots[1].Peers, want) {
+		t.Fatalf("got peers %+v, want %+v", snapshots[1].Peers, want)
+	}
+
+	if _, err := service.Snapshots(start, start); err == nil {
+		t.Fatal("expected error on empty time range")
+	}
+}
+
+func TestSnapshotsPruned(t *testing.T) {
+	t.Parallel()
+
+	var (
+		balances = mock.NewAccounting(
+			mock.WithCompensatedBalancesFunc(amounts()),
+			mock.WithBalancesFunc(amounts()),
+		)
+		timeSettlements = swapmock.New(swapmock.WithSettlementsRecvFunc(amounts()), swapmock.WithSettlementsSentFunc(amounts()))
+		service         = billing.New(log.Noop, statestore.NewStateStore(), balances, nil, timeSettlements, time.Hour)
+	)
+	testutil.CleanupCloser(t, service)
+
+	start := time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC)
+	for _, now := range []time.Time{start, start.AddDate(1, 0, 0), start.AddDate(1, 2, 0)} {
+		now := now
+		service.SetNow(func() time.Time { return now })
+		if err := service.Snapshot(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshots, err := service.Snapshots(start, start.AddDate(2, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(snapshots))
+	}
+	if !snapshots[0].Time.Equal(start.AddDate(1, 0, 0)) {
+		t.Fatalf("got the oldest snapshot at %v, want %v", snapshots[0].Time, start.AddDate(1, 0, 0))
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package billing
+
+import "time"
+
+func (s *Service) SetNow(f func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = f
+}
+
+func (s *Service) Snapshot() error {
+	return s.snapshot()
+}
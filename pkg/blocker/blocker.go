@@ -104,7 +104,7 @@ func (b *Blocker) block() {
 		}
 
 		if 0 < peer.blockAfter && peer.blockAfter < b.sequence.Load() {
-			if err := b.blocklister.Blocklist(peer.address, b.blockDuration, "blocker: flag timeout"); err != nil {
+			if err := b.blocklister.Blocklist(peer.address, b.blockDuration, p2p.OffenseProtocolError, "blocker: flag timeout"); err != nil {
 				b.logger.Warning("blocking peer failed", "peer_address", peer.address, "error", err)
 			}
 			if b.blocklistCallback != nil {
@@ -41,7 +41,7 @@ func TestBlocksAfterFlagTimeout(t *testing.T) {
 	addr := swarm.RandAddress(t)
 	blockedC := make(chan swarm.Address, 10)
 
-	mock := mockBlockLister(func(a swarm.Address, d time.Duration, r string) error {
+	mock := mockBlockLister(func(a swarm.Address, d time.Duration, _ p2p.Offense, r string) error {
 		blockedC <- a
 
 		if d != blockTime {
@@ -82,7 +82,7 @@ func TestUnflagBeforeBlock(t *testing.T) {
 	t.Parallel()
 
 	addr := swarm.RandAddress(t)
-	mock := mockBlockLister(func(a swarm.Address, d time.Duration, r string) error {
+	mock := mockBlockLister(func(a swarm.Address, d time.Duration, _ p2p.Offense, r string) error {
 		t.Fatalf("address should not be blocked")
 
 		return nil
@@ -108,7 +108,7 @@ func TestPruneBeforeBlock(t *testing.T) {
 	t.Parallel()
 
 	addr := swarm.RandAddress(t)
-	mock := mockBlockLister(func(a swarm.Address, d time.Duration, r string) error {
+	mock := mockBlockLister(func(a swarm.Address, d time.Duration, _ p2p.Offense, r string) error {
 		t.Fatalf("address should not be blocked")
 
 		return nil
@@ -131,17 +131,17 @@ func TestPruneBeforeBlock(t *testing.T) {
 }
 
 type blocklister struct {
-	blocklistFunc func(swarm.Address, time.Duration, string) error
+	blocklistFunc func(swarm.Address, time.Duration, p2p.Offense, string) error
 }
 
-func mockBlockLister(f func(swarm.Address, time.Duration, string) error) *blocklister {
+func mockBlockLister(f func(swarm.Address, time.Duration, p2p.Offense, string) error) *blocklister {
 	return &blocklister{
 		blocklistFunc: f,
 	}
 }
 
-func (b *blocklister) Blocklist(addr swarm.Address, t time.Duration, r string) error {
-	return b.blocklistFunc(addr, t, r)
+func (b *blocklister) Blocklist(addr swarm.Address, t time.Duration, o p2p.Offense, r string) error {
+	return b.blocklistFunc(addr, t, o, r)
 }
 
 // NetworkStatus implements p2p.NetworkStatuser interface.
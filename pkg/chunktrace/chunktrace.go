@@ -0,0 +1,99 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package chunktrace records how every chunk of a request was served,
+// from the local store or retrieved from the network, and how long it
+// took, so that the slow downloads can be debugged chunk by chunk.
+package chunktrace
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+type (
+	traceKey  struct{}
+	recordKey struct{}
+)
+
+// Record is the trace of a single chunk.
+type Record struct {
+	Address swarm.Address
+	Local   bool          // served from the local store
+	Latency time.Duration // time to get the chunk
+	Err     error
+	peers   int32
+}
+
+// Peers returns the number of the peers the chunk was requested from.
+func (r *Record) Peers() int {
+	return int(atomic.LoadInt32(&r.peers))
+}
+
+// Trace collects the records of the chunks of a request.
+type Trace struct {
+	started time.Time
+
+	mu      sync.Mutex
+	records []*Record
+}
+
+// New returns a new Trace started now.
+func New() *Trace {
+	return &Trace{started: time.Now()}
+}
+
+// Started returns the time the trace was started.
+func (t *Trace) Started() time.Time {
+	return t.started
+}
+
+// Add adds the record of the chunk to the trace.
+func (t *Trace) Add(r *Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.records = append(t.records, r)
+}
+
+// Records returns the records of the chunks in the order they were added.
+func (t *Trace) Records() []*Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]*Record(nil), t.records...)
+}
+
+// WithTrace returns the context with the trace the chunks are recorded in.
+func WithTrace(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+// FromContext returns the trace of the context or nil if it is not traced.
+func FromContext(ctx context.Context) *Trace {
+	t, _ := ctx.Value(traceKey{}).(*Trace)
+	return t
+}
+
+// WithRecord returns the context with the record of the chunk being retrieved.
+func WithRecord(ctx context.Context, r *Record) context.Context {
+	return context.WithValue(ctx, recordKey{}, r)
+}
+
+// RecordFromContext returns the record of the context or nil if it has none.
+func RecordFromContext(ctx context.Context) *Record {
+	r, _ := ctx.Value(recordKey{}).(*Record)
+	return r
+}
+
+// AddPeer counts the peer the chunk is requested from in the record of the context.
+func AddPeer(ctx context.Context) {
+	if r := RecordFromContext(ctx); r != nil {
+		atomic.AddInt32(&r.peers, 1)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package clockskew estimates the skew of the local clock from the times
+// reported by the peers. The stamps are timestamped with the local clock
+// and the nodes storing the chunks reject the stamps older than the ones
+// they already have, so the uploads of a node with the skewed clock fail
+// on the other nodes with the errors which are hard to trace back to it.
+package clockskew
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "clockskew"
+
+const (
+	// MaxSkew is the maximal tolerated skew of the local clock.
+	MaxSkew = 10 * time.Second
+
+	// minPeers is the minimal number of the peers whose
+	// times are needed for the skew to be estimated.
+	minPeers = 3
+	// sampleTTL is the duration the time of the peer is used for.
+	sampleTTL = 30 * time.Minute
+)
+
+// ErrClockSkewed is returned when the local clock is skewed by more than the MaxSkew.
+var ErrClockSkewed = errors.New("local clock is skewed")
+
+type sample struct {
+	offset   time.Duration // local time minus the time of the peer
+	recorded time.Time
+}
+
+// Detector estimates the skew of the local clock
+// as the median of the offsets to the times of the peers.
+type Detector struct {
+	logger  log.Logger
+	metrics metrics
+	now     func() time.Time
+
+	mu      sync.Mutex
+	samples map[string]sample // by the peer overlay
+	skewed  bool
+}
+
+// New returns a new Detector.
+func New(logger log.Logger) *Detector {
+	return &Detector{
+		logger:  logger.WithName(loggerName).Register(),
+		metrics: newMetrics(),
+		now:     time.Now,
+		samples: make(map[string]sample),
+	}
+}
+
+// Record records the time reported by the peer. Only the latest time of
+// every peer is used, so that a single peer cannot outweigh the others.
+func (d *Detector) Record(peer swarm.Address, peerTime time.Time) {
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.samples[peer.ByteString()] = sample{offset: now.Sub(peerTime), recorded: now}
+
+	skew, ok := d.skew(now)
+	if !ok {
+		return
+	}
+	d.metrics.Skew.Set(skew.Seconds())
+
+	skewed := skew > MaxSkew || skew < -MaxSkew
+	switch {
+	case skewed && !d.skewed:
+		d.logger.Warning("local clock is skewed, uploads are refused until the system clock is corrected", "skew", skew)
+	case !skewed && d.skewed:
+		d.logger.Info("local clock is no longer skewed", "skew", skew)
+	}
+	d.skewed = skewed
+}
+
+// Skew returns the estimated skew of the local clock, positive if the
+// local clock is ahead of the peers. It returns false if there are
+// not enough times of the peers to estimate it.
+func (d *Detector) Skew() (time.Duration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.skew(d.now())
+}
+
+// Check returns an error wrapping the ErrClockSkewed
+// if the local clock is skewed by more than the MaxSkew.
+func (d *Detector) Check() error {
+	skew, ok := d.Skew()
+	if !ok {
+		return nil
+	}
+	switch {
+	case skew > MaxSkew:
+		return fmt.Errorf("%w: %v ahead of the peers", ErrClockSkewed, skew.Round(time.Second))
+	case skew < -MaxSkew:
+		return fmt.Errorf("%w: %v behind the peers", ErrClockSkewed, (-skew).Round(time.Second))
+	}
+	return nil
+}
+
+// skew must be called with the mutex held.
+func (d *Detector) skew(now time.Time) (time.Duration, bool) {
+	offsets := make([]time.Duration, 0, len(d.samples))
+	for peer, s := range d.samples {
+		if now.Sub(s.recorded) > sampleTTL {
+			delete(d.samples, peer)
+			continue
+		}
+		offsets = append(offsets, s.offset)
+	}
+	if len(offsets) < minPeers {
+		return 0, false
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	m := len(offsets) / 2
+	if len(offsets)%2 == 0 {
+		return (offsets[m-1] + offsets[m]) / 2, true
+	}
+	return offsets[m], true
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package clockskew_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/clockskew"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestDetector(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	d := clockskew.New(log.Noop)
+	d.SetNow(func() time.Time { return now })
+
+	peer := func(b byte) swarm.Address {
+		return swarm.NewAddress(append(make([]byte, swarm.HashSize-1), b))
+	}
+
+	// the local clock is a minute behind
+	d.Record(peer(1), now.Add(time.Minute))
+	d.Record(peer(2), now.Add(time.Minute+time.Second))
+	if _, ok := d.Skew(); ok {
+		t.Fatal("skew estimated from too few peers")
+	}
+	if err := d.Check(); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+
+	// a single peer does not outweigh the others
+	d.Record(peer(3), now.Add(-time.Hour))
+	d.Record(peer(4), now.Add(time.Minute-time.Second))
+	d.Record(peer(5), now.Add(time.Minute))
+	skew, ok := d.Skew()
+	if !ok {
+		t.Fatal("skew not estimated")
+	}
+	if want := -time.Minute; skew != want {
+		t.Fatalf("got skew %v, want %v", skew, want)
+	}
+	if err := d.Check(); !errors.Is(err, clockskew.ErrClockSkewed) {
+		t.Fatalf("got error %v, want %v", err, clockskew.ErrClockSkewed)
+	}
+
+	// the latest time of the peer replaces the previous one
+	for b := byte(1); b <= 5; b++ {
+		d.Record(peer(b), now.Add(time.Second))
+	}
+	skew, _ = d.Skew()
+	if want := -time.Second; skew != want {
+		t.Fatalf("got skew %v, want %v", skew, want)
+	}
+	if err := d.Check(); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+
+	// the old times are not used
+	now = now.Add(time.Hour)
+	if _, ok := d.Skew(); ok {
+		t.Fatal("skew estimated from expired times")
+	}
+}
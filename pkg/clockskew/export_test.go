@@ -0,0 +1,11 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package clockskew
+
+import "time"
+
+func (d *Detector) SetNow(now func() time.Time) {
+	d.now = now
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package clockskew
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	// all metrics fields must be exported
+	// to be able to return them by Metrics()
+	// using reflection
+	Skew prometheus.Gauge
+}
+
+func newMetrics() metrics {
+	subsystem := "clockskew"
+
+	return metrics{
+		Skew: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "skew_seconds",
+			Help:      "Estimated skew of the local clock to the peers.",
+		}),
+	}
+}
+
+func (d *Detector) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(d.metrics)
+}
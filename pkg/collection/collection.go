@@ -0,0 +1,503 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package collection stores the JSON documents in the mantaray manifest
+// and maintains the secondary indexes of their fields in the same manifest,
+// so that the documents can be queried by the values of the indexed fields
+// without building the manifests by hand. Every change produces a new
+// version of the manifest.
+//
+// The document with the id is the entry documents/<id>.json. The indexed
+// fields are listed in the metadata of the root entry of the manifest and
+// the subtree indexes/<field>/<value>/ of the manifest is the index of the
+// documents with the value of the field, its entries <id>.json reference
+// the documents. Only the string, number and boolean values of the top
+// level fields of the documents are indexed, they are compared in their
+// JSON text form without the quotes of the strings.
+package collection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/manifest/feedwriter"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	// ContentType is the content type of the documents.
+	ContentType = "application/json"
+
+	documentsPrefix    = "documents/"
+	indexesPrefix      = "indexes/"
+	documentSuffix     = ".json"
+	metadataIndexesKey = "Collection-Indexes"
+)
+
+var (
+	// ErrNotFound is returned when the collection has no document with the id.
+	ErrNotFound = errors.New("document not found")
+	// ErrReadOnly is returned when the collection can not be changed.
+	ErrReadOnly = errors.New("collection is read-only")
+	// ErrInvalidID is returned for the document id which is not valid.
+	ErrInvalidID = errors.New("invalid document id")
+	// ErrInvalidDocument is returned for the document which is not a JSON object.
+	ErrInvalidDocument = errors.New("document is not a json object")
+	// ErrInvalidField is returned for the empty name of the indexed field.
+	ErrInvalidField = errors.New("invalid field name")
+	// ErrNotIndexed is returned when the documents are queried by the field
+	// which is not indexed.
+	ErrNotIndexed = errors.New("field is not indexed")
+)
+
+// idRegexp matches the valid document ids. The ids can not contain the dot,
+// so that no path of a document entry is the prefix of another one.
+var idRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// ValidID reports whether the id is a valid document id.
+func ValidID(id string) bool {
+	return idRegexp.MatchString(id)
+}
+
+// CommitFunc stores the root manifest of the changed collection.
+type CommitFunc = feedwriter.CommitFunc
+
+// Options are the options of the Collection. The collection is read-only
+// if the Storer of the written documents is nil.
+type Options = feedwriter.Options
+
+// Document is the JSON document stored in the collection.
+type Document struct {
+	ID        string
+	Reference swarm.Address
+	Data      json.RawMessage
+}
+
+// Collection is the collection of the documents kept in the manifest.
+// The collection of the zero root reference is empty.
+type Collection struct {
+	storer storage.Storer
+	w      *feedwriter.Writer
+}
+
+// New returns the collection of the manifest with the root reference.
+func New(storer storage.Storer, root swarm.Address, o Options) *Collection {
+	return &Collection{
+		storer: storer,
+		w:      feedwriter.New(storer, root, o),
+	}
+}
+
+// Root returns the reference of the current root manifest.
+func (c *Collection) Root() swarm.Address {
+	return c.w.Root()
+}
+
+func documentPath(id string) string {
+	return documentsPrefix + id + documentSuffix
+}
+
+func indexPrefix(field, value string) string {
+	return indexesPrefix + url.PathEscape(field) + "/" + url.PathEscape(value) + "/"
+}
+
+// Indexes returns the sorted names of the indexed fields.
+func (c *Collection) Indexes(ctx context.Context) ([]string, error) {
+	if c.w.Root().IsZero() {
+		return nil, nil
+	}
+	m, err := c.w.Manifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexes(ctx, m)
+}
+
+func indexes(ctx context.Context, m manifest.Interface) ([]string, error) {
+	e, err := m.Lookup(ctx, manifest.RootPath)
+	if errors.Is(err, manifest.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	v, ok := e.Metadata()[metadataIndexesKey]
+	if !ok {
+		return nil, nil
+	}
+	var fields []string
+	if err := json.Unmarshal([]byte(v), &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal indexes: %w", err)
+	}
+	return fields, nil
+}
+
+// SetIndexes sets the indexed fields of the collection and rebuilds
+// the indexes of the stored documents. It creates the empty collection
+// if the collection has no root manifest yet.
+func (c *Collection) SetIndexes(ctx context.Context, fields []string) error {
+	if !c.w.Writable() {
+		return ErrReadOnly
+	}
+	fields = append([]string(nil), fields...)
+	for _, f := range fields {
+		if f == "" {
+			return ErrInvalidField
+		}
+	}
+	sort.Strings(fields)
+	fields = dedup(fields)
+
+	return c.w.Update(ctx, func(m manifest.Interface) error {
+		keys, err := keys(ctx, m, indexesPrefix)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := m.Remove(ctx, k); err != nil {
+				return err
+			}
+		}
+
+		v, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		// the entry of the root carries only the metadata, but
+		// it must be of the size of the references of the manifest
+		metadata := map[string]string{metadataIndexesKey: string(v)}
+		emptyAddr := swarm.NewAddress(make([]byte, swarm.HashSize))
+		if err := m.Add(ctx, manifest.RootPath, manifest.NewEntry(emptyAddr, metadata)); err != nil {
+			return err
+		}
+
+		ids, err := documentIDs(ctx, m, documentsPrefix)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			doc, err := c.document(ctx, m, id)
+			if err != nil {
+				return err
+			}
+			if err := addIndexEntries(ctx, m, fields, doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Get returns the document with the id.
+func (c *Collection) Get(ctx context.Context, id string) (*Document, error) {
+	if !ValidID(id) {
+		return nil, ErrInvalidID
+	}
+	if c.w.Root().IsZero() {
+		return nil, ErrNotFound
+	}
+	m, err := c.w.Manifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.document(ctx, m, id)
+}
+
+func (c *Collection) document(ctx context.Context, m manifest.Interface, id string) (*Document, error) {
+	e, err := m.Lookup(ctx, documentPath(id))
+	if errors.Is(err, manifest.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := joiner.New(ctx, c.storer, e.Reference())
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{ID: id, Reference: e.Reference(), Data: data}, nil
+}
+
+// Put stores the document with the id, replacing the document with the
+// same id, and updates the indexes of its fields.
+func (c *Collection) Put(ctx context.Context, id string, data []byte) (*Document, error) {
+	if !c.w.Writable() {
+		return nil, ErrReadOnly
+	}
+	if !ValidID(id) {
+		return nil, ErrInvalidID
+	}
+	if _, err := decode(data); err != nil {
+		return nil, err
+	}
+
+	w := builder.NewWriter(ctx, c.w.Storer(), builder.Options{Mode: c.w.Mode()})
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	doc := &Document{ID: id, Reference: w.Reference(), Data: data}
+
+	err := c.w.Update(ctx, func(m manifest.Interface) error {
+		fields, err := indexes(ctx, m)
+		if err != nil {
+			return err
+		}
+		switch old, err := c.document(ctx, m, id); {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return err
+		default:
+			if err := removeIndexEntries(ctx, m, fields, old); err != nil {
+				return err
+			}
+		}
+
+		metadata := map[string]string{
+			manifest.EntryMetadataFilenameKey:    id + documentSuffix,
+			manifest.EntryMetadataContentTypeKey: ContentType,
+		}
+		if err := m.Add(ctx, documentPath(id), manifest.NewEntry(doc.Reference, metadata)); err != nil {
+			return err
+		}
+		return addIndexEntries(ctx, m, fields, doc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Delete removes the document with the id and its index entries.
+func (c *Collection) Delete(ctx context.Context, id string) error {
+	if !c.w.Writable() {
+		return ErrReadOnly
+	}
+	if !ValidID(id) {
+		return ErrInvalidID
+	}
+	if c.w.Root().IsZero() {
+		return ErrNotFound
+	}
+
+	return c.w.Update(ctx, func(m manifest.Interface) error {
+		doc, err := c.document(ctx, m, id)
+		if err != nil {
+			return err
+		}
+		fields, err := indexes(ctx, m)
+		if err != nil {
+			return err
+		}
+		if err := removeIndexEntries(ctx, m, fields, doc); err != nil {
+			return err
+		}
+		return m.Remove(ctx, documentPath(id))
+	})
+}
+
+// Find returns the documents with all the filtered fields equal to the
+// values, sorted by their ids. All the documents are returned without
+// the filters. The filtered fields must be indexed.
+func (c *Collection) Find(ctx context.Context, filters map[string]string) ([]*Document, error) {
+	if c.w.Root().IsZero() {
+		if len(filters) > 0 {
+			return nil, ErrNotIndexed
+		}
+		return nil, nil
+	}
+	m, err := c.w.Manifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := documentIDs(ctx, m, documentsPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(filters) > 0 {
+		fields, err := indexes(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		for field, value := range filters {
+			if i := sort.SearchStrings(fields, field); i == len(fields) || fields[i] != field {
+				return nil, fmt.Errorf("%w: %s", ErrNotIndexed, field)
+			}
+			matched, err := documentIDs(ctx, m, indexPrefix(field, value))
+			if err != nil {
+				return nil, err
+			}
+			ids = intersect(ids, matched)
+		}
+	}
+
+	docs := make([]*Document, 0, len(ids))
+	for _, id := range ids {
+		doc, err := c.document(ctx, m, id)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// decode decodes the top level fields of the document.
+func decode(data []byte) (map[string]interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var fields map[string]interface{}
+	if err := d.Decode(&fields); err != nil || fields == nil {
+		return nil, ErrInvalidDocument
+	}
+	if d.More() {
+		return nil, ErrInvalidDocument
+	}
+	return fields, nil
+}
+
+// indexValue returns the indexed value of the field,
+// false if the value of the field is not indexed.
+func indexValue(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		if v {
+			return "true", true
+		}
+		return "false", true
+	default:
+		return "", false
+	}
+}
+
+// indexPaths returns the paths of the index entries of the document.
+func indexPaths(fields []string, doc *Document) ([]string, error) {
+	values, err := decode(doc.Data)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, f := range fields {
+		if v, ok := indexValue(values[f]); ok {
+			paths = append(paths, indexPrefix(f, v)+doc.ID+documentSuffix)
+		}
+	}
+	return paths, nil
+}
+
+func addIndexEntries(ctx context.Context, m manifest.Interface, fields []string, doc *Document) error {
+	paths, err := indexPaths(fields, doc)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if err := m.Add(ctx, p, manifest.NewEntry(doc.Reference, nil)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeIndexEntries(ctx context.Context, m manifest.Interface, fields []string, doc *Document) error {
+	paths, err := indexPaths(fields, doc)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if err := m.Remove(ctx, p); err != nil && !errors.Is(err, manifest.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// keys returns the sorted paths of the entries starting with the prefix.
+func keys(ctx context.Context, m manifest.Interface, prefix string) ([]string, error) {
+	w, ok := m.(manifest.Walker)
+	if !ok {
+		return nil, fmt.Errorf("manifest type %s can not be listed", m.Type())
+	}
+
+	var keys []string
+	err := w.Walk(ctx, func(p string, isDir bool) error {
+		if !isDir && strings.HasPrefix(p, prefix) {
+			keys = append(keys, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// documentIDs returns the sorted ids of the document entries directly
+// under the prefix.
+func documentIDs(ctx context.Context, m manifest.Interface, prefix string) ([]string, error) {
+	keys, err := keys(ctx, m, prefix)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(keys))
+	for _, k := range keys {
+		id := strings.TrimSuffix(k[len(prefix):], documentSuffix)
+		if ValidID(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// intersect returns the strings of the sorted a which are in the sorted b.
+func intersect(a, b []string) []string {
+	var res []string
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// dedup removes the repeated strings of the sorted s.
+func dedup(s []string) []string {
+	res := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/collection"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func ids(docs []*collection.Document) []string {
+	var ids []string
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
+func TestCollection(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		storer  = mock.NewStorer()
+		commits int
+		root    swarm.Address
+		c       = collection.New(storer, swarm.ZeroAddress, collection.Options{
+			Storer: storer,
+			Mode:   storage.ModePutUpload,
+			Commit: func(_ context.Context, r swarm.Address) error {
+				commits++
+				root = r
+				return nil
+			},
+		})
+	)
+
+	for id, doc := range map[string]string{
+		"alice": `{"name":"alice","age":30,"admin":true}`,
+		"bob":   `{"name":"bob","age":25,"admin":false}`,
+		"carol": `{"name":"carol","age":30,"tags":["a"]}`,
+	} {
+		if _, err := c.Put(ctx, id, []byte(doc)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the indexes of the stored documents are built
+	if err := c.SetIndexes(ctx, []string{"age", "admin", "age"}); err != nil {
+		t.Fatal(err)
+	}
+	if commits != 4 || !c.Root().Equal(root) {
+		t.Fatalf("got %d commits of root %s, want 4 of root %s", commits, root, c.Root())
+	}
+
+	find := func(t *testing.T, c *collection.Collection, filters map[string]string) []string {
+		t.Helper()
+
+		docs, err := c.Find(ctx, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ids(docs)
+	}
+
+	t.Run("get", func(t *testing.T) {
+		t.Parallel()
+
+		c := collection.New(storer, root, collection.Options{})
+		doc, err := c.Get(ctx, "bob")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(doc.Data) != `{"name":"bob","age":25,"admin":false}` {
+			t.Fatalf("got document %s", doc.Data)
+		}
+		if _, err := c.Get(ctx, "dave"); !errors.Is(err, collection.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, collection.ErrNotFound)
+		}
+		indexes, err := c.Indexes(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"admin", "age"}; !reflect.DeepEqual(indexes, want) {
+			t.Fatalf("got indexes %v, want %v", indexes, want)
+		}
+	})
+
+	t.Run("find", func(t *testing.T) {
+		t.Parallel()
+
+		c := collection.New(storer, root, collection.Options{})
+		for _, tc := range []struct {
+			filters map[string]string
+			want    []string
+		}{
+			{nil, []string{"alice", "bob", "carol"}},
+			{map[string]string{"age": "30"}, []string{"alice", "carol"}},
+			{map[string]string{"age": "30", "admin": "true"}, []string{"alice"}},
+			{map[string]string{"age": "31"}, nil},
+		} {
+			if got := find(t, c, tc.filters); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v for %v, want %v", got, tc.filters, tc.want)
+			}
+		}
+		if _, err := c.Find(ctx, map[string]string{"name": "bob"}); !errors.Is(err, collection.ErrNotIndexed) {
+			t.Fatalf("got error %v, want %v", err, collection.ErrNotIndexed)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		t.Parallel()
+
+		c := collection.New(storer, root, collection.Options{
+			Storer: storer,
+			Mode:   storage.ModePutUpload,
+			Commit: func(context.Context, swarm.Address) error { return nil },
+		})
+		if _, err := c.Put(ctx, "alice", []byte(`{"name":"alice","age":31}`)); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := find(t, c, map[string]string{"age": "30"}), []string{"carol"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := find(t, c, map[string]string{"age": "31"}), []string{"alice"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got := find(t, c, map[string]string{"admin": "true"}); got != nil {
+			t.Fatalf("got %v, want none", got)
+		}
+
+		if err := c.Delete(ctx, "carol"); err != nil {
+			t.Fatal(err)
+		}
+		if got := find(t, c, map[string]string{"age": "30"}); got != nil {
+			t.Fatalf("got %v, want none", got)
+		}
+		if err := c.Delete(ctx, "carol"); !errors.Is(err, collection.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, collection.ErrNotFound)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		c := collection.New(storer, root, collection.Options{
+			Storer: storer,
+			Mode:   storage.ModePutUpload,
+			Commit: func(context.Context, swarm.Address) error { return nil },
+		})
+		if _, err := c.Put(ctx, "a.b", []byte(`{}`)); !errors.Is(err, collection.ErrInvalidID) {
+			t.Fatalf("got error %v, want %v", err, collection.ErrInvalidID)
+		}
+		for _, doc := range []string{`[1]`, `null`, `{"a":1} {}`, `{`} {
+			if _, err := c.Put(ctx, "x", []byte(doc)); !errors.Is(err, collection.ErrInvalidDocument) {
+				t.Fatalf("got error %v for %s, want %v", err, doc, collection.ErrInvalidDocument)
+			}
+		}
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		t.Parallel()
+
+		c := collection.New(storer, root, collection.Options{})
+		if _, err := c.Put(ctx, "x", []byte(`{}`)); !errors.Is(err, collection.ErrReadOnly) {
+			t.Fatalf("got error %v, want %v", err, collection.ErrReadOnly)
+		}
+		if err := c.Delete(ctx, "bob"); !errors.Is(err, collection.ErrReadOnly) {
+			t.Fatalf("got error %v, want %v", err, collection.ErrReadOnly)
+		}
+	})
+}
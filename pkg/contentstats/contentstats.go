@@ -0,0 +1,211 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package contentstats keeps the aggregated access statistics of the content
+// served by the node under its root reference, so that the publishers get the
+// analytics of their content without the individual requests being logged.
+// The clients are only counted: their addresses are hashed with the secret
+// salt of the node and the reference, and the hashes are added to the
+// HyperLogLog which estimates their number without keeping them.
+package contentstats
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "contentstats"
+
+const (
+	// flushInterval is the period of the writes of the changed statistics.
+	flushInterval  = time.Minute
+	statsKeyPrefix = "contentstats_"
+	saltKey        = "contentstats-salt"
+	saltSize       = 32
+)
+
+// ErrNotFound is returned when the content has not been accessed.
+var ErrNotFound = errors.New("content stats not found")
+
+// Stats are the aggregated access statistics of the content.
+type Stats struct {
+	// Downloads is the number of the responses with the whole content.
+	Downloads uint64
+	// BytesServed is the number of the bytes of all the responses,
+	// including those with the ranges of the content.
+	BytesServed uint64
+	// UniqueClients is the estimated number of the distinct clients.
+	UniqueClients uint64
+}
+
+// record is the stored state of the statistics of the content.
+type record struct {
+	Downloads   uint64      `json:"downloads"`
+	BytesServed uint64      `json:"bytesServed"`
+	Clients     hyperLogLog `json:"clients"`
+}
+
+func statsKey(ref swarm.Address) string {
+	return statsKeyPrefix + ref.String()
+}
+
+// Service records the accesses of the content and keeps the changed
+// statistics in memory until they are written in the background.
+type Service struct {
+	store  storage.StateStorer
+	logger log.Logger
+	salt   []byte
+
+	mu      sync.Mutex         // guards the records
+	records map[string]*record // the statistics changed since the last flush
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New returns a new Service which writes the changed statistics to the
+// store periodically. The salt of the client hashes is created on the
+// first start and kept in the store, so that the estimates of the unique
+// clients persist over the restarts of the node.
+func New(store storage.StateStorer, logger log.Logger) (*Service, error) {
+	var salt []byte
+	switch err := store.Get(saltKey, &salt); {
+	case errors.Is(err, storage.ErrNotFound):
+		salt = make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("salt: %w", err)
+		}
+		if err := store.Put(saltKey, salt); err != nil {
+			return nil, fmt.Errorf("store salt: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("get salt: %w", err)
+	}
+
+	s := &Service{
+		store:   store,
+		logger:  logger.WithName(loggerName).Register(),
+		salt:    salt,
+		records: make(map[string]*record),
+		quit:    make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.flushLoop()
+
+	return s, nil
+}
+
+func (s *Service) flushLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+		if err := s.flush(); err != nil {
+			s.logger.Error(err, "content stats flush failed")
+		}
+	}
+}
+
+// flush writes the changed statistics to the store.
+func (s *Service) flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, r := range s.records {
+		if err := s.store.Put(key, r); err != nil {
+			return fmt.Errorf("store content stats: %w", err)
+		}
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// get returns the record of the content, the changed one if it is in
+// memory or the stored one. It must be called with the lock held.
+func (s *Service) get(key string) (*record, error) {
+	if r, ok := s.records[key]; ok {
+		return r, nil
+	}
+	r := new(record)
+	if err := s.store.Get(key, r); err != nil {
+		return nil, err
+	}
+	if len(r.Clients) != hllRegisters {
+		return nil, fmt.Errorf("invalid content stats %q", key)
+	}
+	return r, nil
+}
+
+// Record records the access of the client to the content under the root
+// reference, which served the bytes of the whole content or of its range.
+func (s *Service) Record(ref swarm.Address, client string, bytes uint64, whole bool) error {
+	h := hmac.New(sha256.New, s.salt)
+	_, _ = h.Write(ref.Bytes())
+	_, _ = h.Write([]byte(client))
+	sum := h.Sum(nil)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := statsKey(ref)
+	r, err := s.get(key)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		r = &record{Clients: newHyperLogLog()}
+	case err != nil:
+		return err
+	}
+
+	if whole {
+		r.Downloads++
+	}
+	r.BytesServed += bytes
+	r.Clients.add(binary.BigEndian.Uint64(sum))
+	s.records[key] = r
+	return nil
+}
+
+// Get returns the statistics of the content under the root reference.
+func (s *Service) Get(ref swarm.Address) (Stats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, err := s.get(statsKey(ref))
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return Stats{}, ErrNotFound
+	case err != nil:
+		return Stats{}, err
+	}
+	return Stats{
+		Downloads:     r.Downloads,
+		BytesServed:   r.BytesServed,
+		UniqueClients: r.Clients.estimate(),
+	}, nil
+}
+
+// Close stops the background writes and writes the changed statistics.
+func (s *Service) Close() error {
+	close(s.quit)
+	s.wg.Wait()
+	return s.flush()
+}
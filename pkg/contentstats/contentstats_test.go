@@ -0,0 +1,109 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contentstats_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/contentstats"
+	"github.com/ethersphere/bee/pkg/log"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	service, err := contentstats.New(statestore.NewStateStore(), log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, service)
+
+	ref := swarm.RandAddress(t)
+	if _, err := service.Get(ref); !errors.Is(err, contentstats.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, contentstats.ErrNotFound)
+	}
+
+	const clients = 2000
+	for i := 0; i < clients; i++ {
+		client := fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+		if err := service.Record(ref, client, 100, true); err != nil {
+			t.Fatal(err)
+		}
+		// the range of the content of the same client
+		if err := service.Record(ref, client, 10, false); err != nil {
+			t.Fatal(err)
+		}
+		if i == clients/2 {
+			if err := service.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	stats, err := service.Get(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Downloads != clients {
+		t.Fatalf("got %d downloads, want %d", stats.Downloads, clients)
+	}
+	if stats.BytesServed != clients*110 {
+		t.Fatalf("got %d bytes served, want %d", stats.BytesServed, clients*110)
+	}
+	// within three times the standard error of the estimate
+	if min, max := uint64(clients*0.9), uint64(clients*1.1); stats.UniqueClients < min || stats.UniqueClients > max {
+		t.Fatalf("got %d unique clients, want within [%d, %d]", stats.UniqueClients, min, max)
+	}
+
+	if _, err := service.Get(swarm.RandAddress(t)); !errors.Is(err, contentstats.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, contentstats.ErrNotFound)
+	}
+}
+
+func TestStatsPersisted(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store = statestore.NewStateStore()
+		ref   = swarm.RandAddress(t)
+	)
+
+	service, err := contentstats.New(store, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, client := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		if err := service.Record(ref, client, 100, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := service.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	service, err = contentstats.New(store, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, service)
+
+	// the client is known after the restart
+	if err := service.Record(ref, "10.0.0.1", 100, true); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := service.Get(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := contentstats.Stats{Downloads: 4, BytesServed: 400, UniqueClients: 3}
+	if stats != want {
+		t.Fatalf("got stats %+v, want %+v", stats, want)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contentstats
+
+import (
+	"math"
+	"math/bits"
+)
+
+const (
+	// hllPrecision is the number of the bits of the hash selecting the
+	// register, the standard error of the estimate is 1.04/sqrt(2^precision),
+	// about 3%, for the registers taking a kilobyte per reference.
+	hllPrecision = 10
+	hllRegisters = 1 << hllPrecision
+)
+
+// hyperLogLog estimates the number of the distinct hashes added to it.
+// The registers keep only the longest run of the leading zeros of the hashes
+// of each bucket, so the hashes added can not be recovered from them.
+type hyperLogLog []uint8
+
+func newHyperLogLog() hyperLogLog {
+	return make(hyperLogLog, hllRegisters)
+}
+
+// add adds the uniformly distributed hash.
+func (h hyperLogLog) add(x uint64) {
+	i := x >> (64 - hllPrecision)
+	// the guard bit bounds the run of the zeros of the remaining bits
+	w := x<<hllPrecision | 1<<(hllPrecision-1)
+	if rho := uint8(bits.LeadingZeros64(w)) + 1; rho > h[i] {
+		h[i] = rho
+	}
+}
+
+// estimate returns the estimated number of the distinct hashes added.
+func (h hyperLogLog) estimate() uint64 {
+	const m = float64(hllRegisters)
+
+	var (
+		sum   float64
+		zeros int
+	)
+	for _, r := range h {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	e := 0.7213 / (1 + 1.079/m) * m * m / sum
+	// the linear counting is more accurate for the small cardinalities,
+	// the large range correction is not needed for the 64-bit hashes
+	if e <= 2.5*m && zeros > 0 {
+		e = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(e))
+}
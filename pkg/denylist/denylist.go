@@ -0,0 +1,131 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package denylist keeps the references which the gateway operator does
+// not want to serve. The denied references are persisted in the state
+// store and kept in memory, so that checking a reference is cheap enough
+// to be done on every download.
+package denylist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const storePrefix = "denylist-"
+
+// ErrNotFound is returned when the reference is not denied.
+var ErrNotFound = errors.New("reference not denied")
+
+// Entry is a denied reference.
+type Entry struct {
+	Address   swarm.Address `json:"address"`
+	Reason    string        `json:"reason"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+func entryKey(addr swarm.Address) string {
+	return storePrefix + addr.String()
+}
+
+// List is the list of the denied references.
+type List struct {
+	mu      sync.RWMutex
+	store   storage.StateStorer
+	entries map[string]Entry
+}
+
+// New creates a new List with the denied references persisted in the store.
+func New(store storage.StateStorer) (*List, error) {
+	l := &List{
+		store:   store,
+		entries: make(map[string]Entry),
+	}
+
+	err := store.Iterate(storePrefix, func(_, value []byte) (bool, error) {
+		var e Entry
+		if err := json.Unmarshal(value, &e); err != nil {
+			return true, fmt.Errorf("unmarshal entry: %w", err)
+		}
+		l.entries[e.Address.ByteString()] = e
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Add denies the reference for the given reason. Adding a reference
+// which is already denied replaces its reason.
+func (l *List) Add(addr swarm.Address, reason string) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := Entry{Address: addr, Reason: reason, CreatedAt: time.Now()}
+	if err := l.store.Put(entryKey(addr), e); err != nil {
+		return Entry{}, err
+	}
+	l.entries[addr.ByteString()] = e
+	return e, nil
+}
+
+// Remove allows the denied reference to be served again.
+func (l *List) Remove(addr swarm.Address) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.entries[addr.ByteString()]; !ok {
+		return ErrNotFound
+	}
+	if err := l.store.Delete(entryKey(addr)); err != nil {
+		return err
+	}
+	delete(l.entries, addr.ByteString())
+	return nil
+}
+
+// Denied reports whether the reference is denied.
+func (l *List) Denied(addr swarm.Address) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	_, ok := l.entries[addr.ByteString()]
+	return ok
+}
+
+// Get returns the entry of the denied reference.
+func (l *List) Get(addr swarm.Address) (Entry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	e, ok := l.entries[addr.ByteString()]
+	if !ok {
+		return Entry{}, ErrNotFound
+	}
+	return e, nil
+}
+
+// Entries returns the denied references ordered by their address.
+func (l *List) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]Entry, 0, len(l.entries))
+	for _, e := range l.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Address.String() < entries[j].Address.String()
+	})
+	return entries
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package denylist_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/denylist"
+	"github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	store := mock.NewStateStore()
+	list, err := denylist.New(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		denied  = swarm.MustParseHexAddress("aa")
+		other   = swarm.MustParseHexAddress("bb")
+		allowed = swarm.MustParseHexAddress("cc")
+	)
+
+	if _, err := list.Add(denied, "copyright"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := list.Add(other, "abuse"); err != nil {
+		t.Fatal(err)
+	}
+	// adding again replaces the reason
+	if _, err := list.Add(other, "malware"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !list.Denied(denied) || !list.Denied(other) {
+		t.Fatal("reference not denied")
+	}
+	if list.Denied(allowed) {
+		t.Fatal("reference denied")
+	}
+
+	// the entries survive the restart of the node
+	list, err = denylist.New(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := list.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if !entries[0].Address.Equal(denied) || entries[0].Reason != "copyright" {
+		t.Fatalf("got entry %+v, want %s denied for copyright", entries[0], denied)
+	}
+	if !entries[1].Address.Equal(other) || entries[1].Reason != "malware" {
+		t.Fatalf("got entry %+v, want %s denied for malware", entries[1], other)
+	}
+
+	if err := list.Remove(denied); err != nil {
+		t.Fatal(err)
+	}
+	if list.Denied(denied) {
+		t.Fatal("removed reference denied")
+	}
+	if err := list.Remove(denied); !errors.Is(err, denylist.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, denylist.ErrNotFound)
+	}
+	if _, err := list.Get(denied); !errors.Is(err, denylist.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, denylist.ErrNotFound)
+	}
+
+	list, err = denylist.New(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Denied(denied) || !list.Denied(other) {
+		t.Fatal("removal not persisted")
+	}
+}
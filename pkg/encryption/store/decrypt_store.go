@@ -18,7 +18,19 @@ type decryptingStore struct {
 	storage.Getter
 }
 
+// decryptingMultiStore is the decryptingStore
+// of the getter which gets many chunks at once.
+type decryptingMultiStore struct {
+	decryptingStore
+	multi storage.MultiGetter
+}
+
+// New returns the getter decrypting the chunks of the encrypted references.
+// It is also a storage.MultiGetter if the wrapped getter is one.
 func New(s storage.Getter) storage.Getter {
+	if m, ok := s.(storage.MultiGetter); ok {
+		return &decryptingMultiStore{decryptingStore{s}, m}
+	}
 	return &decryptingStore{s}
 }
 
@@ -48,6 +60,37 @@ func (s *decryptingStore) Get(ctx context.Context, mode storage.ModeGet, addr sw
 	}
 }
 
+func (s *decryptingMultiStore) GetMulti(ctx context.Context, mode storage.ModeGet, addrs ...swarm.Address) ([]swarm.Chunk, error) {
+	addresses := make([]swarm.Address, len(addrs))
+	for i, addr := range addrs {
+		switch l := len(addr.Bytes()); l {
+		case swarm.HashSize:
+			addresses[i] = addr
+		case encryption.ReferenceSize:
+			addresses[i] = swarm.NewAddress(addr.Bytes()[:swarm.HashSize])
+		default:
+			return nil, storage.ErrReferenceLength
+		}
+	}
+
+	chs, err := s.multi.GetMulti(ctx, mode, addresses...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, addr := range addrs {
+		if len(addr.Bytes()) != encryption.ReferenceSize {
+			continue
+		}
+		d, err := decryptChunkData(chs[i].Data(), addr.Bytes()[swarm.HashSize:])
+		if err != nil {
+			return nil, err
+		}
+		chs[i] = swarm.NewChunk(addresses[i], d)
+	}
+	return chs, nil
+}
+
 func decryptChunkData(chunkData []byte, encryptionKey encryption.Key) ([]byte, error) {
 	decryptedSpan, decryptedData, err := decrypt(chunkData, encryptionKey)
 	if err != nil {
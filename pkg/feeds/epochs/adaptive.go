@@ -0,0 +1,188 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package epochs
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var _ feeds.Lookup = (*adaptiveFinder)(nil)
+
+// levelSpread is the number of levels above and below the hinted level
+// which are probed by the adaptive finder.
+const levelSpread = 2
+
+// adaptiveFinder uses a hint on the update frequency of a feed to probe
+// concurrently the epochs on the levels where updates are most likely placed,
+// and descends only from the lowest of them holding an update valid at the
+// requested time instead of retrieving the epochs of all levels.
+// If none of them holds such an update, it falls back to the concurrent
+// lookup through all levels.
+// The hint is adjusted to the level of the last update found.
+type adaptiveFinder struct {
+	getter   *feeds.Getter
+	fallback *asyncFinder
+
+	mu    sync.Mutex
+	level uint8 // level at which updates are expected
+}
+
+// NewAdaptiveFinder constructs an adaptive finder for a feed which
+// is expected to be updated about every period seconds.
+// A non-positive period means the update frequency is unknown.
+func NewAdaptiveFinder(getter storage.Getter, feed *feeds.Feed, period int64) feeds.Lookup {
+	g := feeds.NewGetter(getter, feed)
+	return &adaptiveFinder{
+		getter:   g,
+		fallback: &asyncFinder{g},
+		level:    periodLevel(period),
+	}
+}
+
+// periodLevel returns the level of the epochs spanning the given period.
+func periodLevel(period int64) uint8 {
+	if period <= 0 {
+		return maxLevel
+	}
+	if l := bits.Len64(uint64(period)); l < maxLevel {
+		return uint8(l)
+	}
+	return maxLevel
+}
+
+// At looks up the version valid at time `at`
+// after is a unix time hint of the latest known update
+func (f *adaptiveFinder) At(ctx context.Context, at, after int64) (swarm.Chunk, feeds.Index, feeds.Index, error) {
+	f.mu.Lock()
+	level := f.level
+	f.mu.Unlock()
+
+	var (
+		ch  swarm.Chunk
+		e   *epoch
+		err error
+	)
+	if level < maxLevel {
+		e, ch, err = f.probe(ctx, at, level)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if ch != nil && e.level > 0 {
+		// every later update valid at `at` is placed under the found epoch
+		ch, e, err = f.at(ctx, uint64(at), e.childAt(uint64(at)), ch, e)
+	} else if ch == nil {
+		ch, e, err = f.fallback.asyncAt(ctx, at, after)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if e != nil {
+		f.mu.Lock()
+		f.level = e.level
+		f.mu.Unlock()
+	}
+	return ch, nil, nil, nil
+}
+
+// probe concurrently retrieves the epochs containing `at` on the levels
+// around the hinted level and returns the lowest one that holds an update
+// valid at `at`, or a nil chunk if there is no such epoch.
+func (f *adaptiveFinder) probe(ctx context.Context, at int64, level uint8) (*epoch, swarm.Chunk, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lo, hi := 0, int(level)+levelSpread
+	if int(level) > levelSpread {
+		lo = int(level) - levelSpread
+	}
+	if hi > maxLevel {
+		hi = maxLevel
+	}
+
+	epochs := make([]*epoch, hi-lo+1)
+	e := &epoch{0, maxLevel}
+	for {
+		if int(e.level) <= hi {
+			epochs[int(e.level)-lo] = e
+		}
+		if int(e.level) == lo {
+			break
+		}
+		e = e.childAt(uint64(at))
+	}
+
+	c := make(chan *result, len(epochs))
+	for _, e := range epochs {
+		go func(e *epoch) {
+			ch, err := f.fallback.get(ctx, at, e)
+			c <- &result{chunk: ch, err: err, epoch: e}
+		}(e)
+	}
+
+	// results are indexed by level, an epoch can be chosen
+	// once all the epochs below it reported no valid update
+	results := make([]*result, len(epochs))
+	for range epochs {
+		var r *result
+		select {
+		case r = <-c:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		// an epoch which failed to be retrieved is taken as one
+		// without an update, the fallback lookup retries it
+		if r.err != nil {
+			r.chunk = nil
+		}
+		results[int(r.level)-lo] = r
+		for _, r := range results {
+			if r == nil {
+				break
+			}
+			if r.chunk != nil {
+				return r.epoch, r.chunk, nil
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// at is the recursive descent of the sequential finder which also
+// returns the epoch at which the returned update chunk was found
+func (f *adaptiveFinder) at(ctx context.Context, at uint64, e *epoch, ch swarm.Chunk, che *epoch) (swarm.Chunk, *epoch, error) {
+	uch, err := f.getter.Get(ctx, e)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, err
+		}
+		if e.isLeft() {
+			return ch, che, nil
+		}
+		return f.at(ctx, e.start-1, e.left(), ch, che)
+	}
+	ts, err := feeds.UpdatedAt(uch)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ts > at {
+		if e.isLeft() {
+			return ch, che, nil
+		}
+		return f.at(ctx, e.start-1, e.left(), ch, che)
+	}
+	if e.level == 0 {
+		return uch, e, nil
+	}
+	return f.at(ctx, at, e.childAt(at), uch, e)
+}
@@ -98,11 +98,12 @@ func (f *finder) at(ctx context.Context, at uint64, e *epoch, ch swarm.Chunk) (s
 type result struct {
 	path  *path
 	chunk swarm.Chunk
+	err   error
 	*epoch
 }
 
 // asyncFinder encapsulates a chunk store getter and a feed and provides
-// non-concurrent lookup methods
+// concurrent lookup methods
 type asyncFinder struct {
 	getter *feeds.Getter
 }
@@ -143,21 +144,22 @@ func (f *asyncFinder) get(ctx context.Context, at int64, e *epoch) (swarm.Chunk,
 }
 
 // at attempts to retrieve all epoch chunks on the path for `at` concurrently
+// probes still in flight are abandoned once the path or the context is cancelled
 func (f *asyncFinder) at(ctx context.Context, at int64, p *path, e *epoch, c chan<- *result) {
 	for ; ; e = e.childAt(uint64(at)) {
 		select {
 		case <-p.cancel:
 			return
+		case <-ctx.Done():
+			return
 		default:
 		}
 		go func(e *epoch) {
 			uch, err := f.get(ctx, at, e)
-			if err != nil {
-				return
-			}
 			select {
-			case c <- &result{p, uch, e}:
+			case c <- &result{p, uch, err, e}:
 			case <-p.cancel:
+			case <-ctx.Done():
 			}
 		}(e)
 		if e.level == 0 {
@@ -165,40 +167,61 @@ func (f *asyncFinder) at(ctx context.Context, at int64, p *path, e *epoch, c cha
 		}
 	}
 }
+
 func (f *asyncFinder) At(ctx context.Context, at, after int64) (swarm.Chunk, feeds.Index, feeds.Index, error) {
 	// TODO: current and next index return values need to be implemented
-	ch, err := f.asyncAt(ctx, at, after)
+	ch, _, err := f.asyncAt(ctx, at, after)
 	return ch, nil, nil, err
 }
 
-// At looks up the version valid at time `at`
+// asyncAt looks up the version valid at time `at` and the epoch it was found at
 // after is a unix time hint of the latest known update
-func (f *asyncFinder) asyncAt(ctx context.Context, at, after int64) (swarm.Chunk, error) {
+// an epoch which failed to be retrieved is taken as one without an update,
+// the error is only returned if no update is found
+func (f *asyncFinder) asyncAt(ctx context.Context, at, after int64) (swarm.Chunk, *epoch, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var probeErr error
 	c := make(chan *result)
 	go f.at(ctx, at, newPath(at), &epoch{0, maxLevel}, c)
-LOOP:
-	for r := range c {
+	for {
+		var r *result
+		select {
+		case r = <-c:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		if r.err != nil {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+			if probeErr == nil {
+				probeErr = r.err
+			}
+			r.chunk = nil
+		}
 		p := r.path
 		// ignore result from paths already  cancelled
 		select {
 		case <-p.cancel:
-			continue LOOP
+			continue
 		default:
 		}
 		if r.chunk != nil { // update chunk for epoch found
 			if r.level == 0 { // return if deepest level epoch
-				return r.chunk, nil
+				return r.chunk, r.epoch, nil
 			}
 			// ignore if higher level than the deepest epoch found
 			if p.top != nil && p.top.level < r.level {
-				continue LOOP
+				continue
 			}
 			p.top = r
 		} else { // update chunk for epoch not found
 			// if top level than return with no update found
-			if r.level == 32 {
+			if r.level == maxLevel {
 				close(p.cancel)
-				return nil, nil
+				return nil, nil, probeErr
 			}
 			// if topmost epoch not found, then set bottom
 			if p.bottom == nil || p.bottom.level < r.level {
@@ -211,13 +234,12 @@ LOOP:
 			// cancel path
 			close(p.cancel)
 			if p.bottom.isLeft() {
-				return p.top.chunk, nil
+				return p.top.chunk, p.top.epoch, nil
 			}
 			// recursive call on new path through left sister
 			np := newPath(at)
-			np.top = &result{np, p.top.chunk, p.top.epoch}
+			np.top = &result{np, p.top.chunk, nil, p.top.epoch}
 			go f.at(ctx, int64(p.bottom.start-1), np, p.bottom.left(), c)
 		}
 	}
-	return nil, nil
 }
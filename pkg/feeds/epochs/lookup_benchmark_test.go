@@ -55,8 +55,9 @@ func BenchmarkFinder(b *testing.B) {
 				for k, finder := range []feeds.Lookup{
 					epochs.NewFinder(storer, updater.Feed()),
 					epochs.NewAsyncFinder(storer, updater.Feed()),
+					epochs.NewAdaptiveFinder(storer, updater.Feed(), 1<<i),
 				} {
-					names := []string{"sync", "async"}
+					names := []string{"sync", "async", "adaptive"}
 					b.Run(fmt.Sprintf("%s:prefill=%d, latest=%d, now=%d", names[k], prefill, latest, now), func(b *testing.B) {
 						for n := 0; n < b.N; n++ {
 							_, _, _, err := finder.At(ctx, now, after)
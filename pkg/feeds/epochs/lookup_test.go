@@ -5,6 +5,9 @@
 package epochs_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/crypto"
@@ -12,6 +15,8 @@ import (
 	"github.com/ethersphere/bee/pkg/feeds/epochs"
 	feedstesting "github.com/ethersphere/bee/pkg/feeds/testing"
 	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
 )
 
 func TestFinder_FLAKY(t *testing.T) {
@@ -43,4 +48,109 @@ func TestFinder_FLAKY(t *testing.T) {
 		t.Parallel()
 		testf(t, epochs.NewAsyncFinder, epochs.NewUpdater)
 	})
+	for _, period := range []int64{0, 1, 1 << 9} {
+		period := period
+		t.Run(fmt.Sprintf("adaptive period %d", period), func(t *testing.T) {
+			t.Parallel()
+			testf(t, func(getter storage.Getter, feed *feeds.Feed) feeds.Lookup {
+				return epochs.NewAdaptiveFinder(getter, feed, period)
+			}, epochs.NewUpdater)
+		})
+	}
+}
+
+func TestFinderCancel(t *testing.T) {
+	t.Parallel()
+
+	storer := &feedstesting.Timeout{Storer: mock.NewStorer()}
+	topic, err := crypto.LegacyKeccak256([]byte("testtopic"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, _ := crypto.GenerateSecp256k1Key()
+	updater, err := epochs.NewUpdater(storer, crypto.NewDefaultSigner(pk), topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := updater.Update(context.Background(), 100, []byte("payload")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for name, finder := range map[string]feeds.Lookup{
+		"async":    epochs.NewAsyncFinder(storer, updater.Feed()),
+		"adaptive": epochs.NewAdaptiveFinder(storer, updater.Feed(), 1<<6),
+	} {
+		if _, _, _, err := finder.At(ctx, 200, 0); !errors.Is(err, context.Canceled) {
+			t.Fatalf("%s: got error %v, want %v", name, err, context.Canceled)
+		}
+	}
+}
+
+// failingStorer fails the retrievals of the chunks which are not stored.
+type failingStorer struct {
+	storage.Storer
+}
+
+var errUnreachable = errors.New("unreachable")
+
+func (s failingStorer) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	ch, err := s.Storer.Get(ctx, mode, addr)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, errUnreachable
+	}
+	return ch, err
+}
+
+func TestFinderProbeErrors(t *testing.T) {
+	t.Parallel()
+
+	storer := failingStorer{mock.NewStorer()}
+	topic, err := crypto.LegacyKeccak256([]byte("testtopic"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, _ := crypto.GenerateSecp256k1Key()
+	updater, err := epochs.NewUpdater(storer, crypto.NewDefaultSigner(pk), topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	finders := func() map[string]feeds.Lookup {
+		return map[string]feeds.Lookup{
+			"async":    epochs.NewAsyncFinder(storer, updater.Feed()),
+			"adaptive": epochs.NewAdaptiveFinder(storer, updater.Feed(), 1<<6),
+		}
+	}
+
+	// the lookup fails only if no update is found
+	for name, finder := range finders() {
+		if _, _, _, err := finder.At(context.Background(), 200, 0); !errors.Is(err, errUnreachable) {
+			t.Fatalf("%s: got error %v, want %v", name, err, errUnreachable)
+		}
+	}
+
+	for _, at := range []int64{100, 150} {
+		if err := updater.Update(context.Background(), at, []byte("payload")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, finder := range finders() {
+		ch, _, _, err := finder.At(context.Background(), 200, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if ch == nil {
+			t.Fatalf("%s: no update found", name)
+		}
+		ts, err := feeds.UpdatedAt(ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ts != 150 {
+			t.Fatalf("%s: got update at %d, want 150", name, ts)
+		}
+	}
 }
@@ -20,11 +20,15 @@ func New(getter storage.Getter) feeds.Factory {
 }
 
 func (f *factory) NewLookup(t feeds.Type, feed *feeds.Feed) (feeds.Lookup, error) {
+	return f.NewLookupWithPeriod(t, feed, 0)
+}
+
+func (f *factory) NewLookupWithPeriod(t feeds.Type, feed *feeds.Feed, period int64) (feeds.Lookup, error) {
 	switch t {
 	case feeds.Sequence:
 		return sequence.NewAsyncFinder(f.Getter, feed), nil
 	case feeds.Epoch:
-		return epochs.NewAsyncFinder(f.Getter, feed), nil
+		return epochs.NewAdaptiveFinder(f.Getter, feed, period), nil
 	}
 
 	return nil, feeds.ErrFeedTypeNotFound
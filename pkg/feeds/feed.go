@@ -26,6 +26,10 @@ var ErrFeedTypeNotFound = errors.New("no such feed type")
 // Factory creates feed lookups for different types of feeds.
 type Factory interface {
 	NewLookup(Type, *Feed) (Lookup, error)
+	// NewLookupWithPeriod creates the lookup for the feed which is expected
+	// to be updated about every period seconds. The epoch lookup uses the
+	// hint to narrow the search for the update.
+	NewLookupWithPeriod(Type, *Feed, int64) (Lookup, error)
 }
 
 // Type enumerates the time-based feed types
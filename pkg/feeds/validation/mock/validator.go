@@ -0,0 +1,19 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mock
+
+import (
+	"context"
+
+	"github.com/ethersphere/bee/pkg/feeds/validation"
+)
+
+// Validator is the validation.Validator mock calling the function.
+type Validator func(ctx context.Context, u validation.Update) error
+
+// Validate implements validation.Validator Validate method.
+func (f Validator) Validate(ctx context.Context, u validation.Update) error {
+	return f(ctx, u)
+}
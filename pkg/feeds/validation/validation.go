@@ -0,0 +1,181 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package validation lets the operator enforce the application-level schemas
+// of the feed updates at the node boundary. The single owner chunks uploaded
+// to the node, which carry the feed updates, are posted to the configured
+// validation hooks before they are stamped, and the updates rejected by any
+// hook are not stamped nor stored, so that the teams sharing a feed-based
+// protocol do not pay for and spread the malformed data.
+//
+// The hooks are external HTTP services, the WASM modules are not supported
+// as the node does not embed a WASM runtime.
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "feedvalidation"
+
+const (
+	// DefaultTimeout is the default time the hook is waited to respond.
+	DefaultTimeout = 5 * time.Second
+
+	maxReasonSize = 1024 // maximal size of the rejection reason read from the response
+)
+
+var (
+	// ErrRejected is returned if the hook rejected the update.
+	ErrRejected = errors.New("feed update rejected")
+	// ErrUnavailable is returned if the hook could not validate the update.
+	ErrUnavailable = errors.New("feed validation hook unavailable")
+)
+
+// Update is the feed update, the single owner chunk, being validated.
+type Update struct {
+	Owner   []byte // ethereum address of the owner
+	ID      []byte // identifier of the single owner chunk
+	Span    uint64 // span of the wrapped chunk
+	Payload []byte // payload of the wrapped chunk, without the span
+}
+
+// Validator validates the feed updates.
+type Validator interface {
+	// Validate returns an error wrapping ErrRejected if the update is
+	// malformed, or any other error if it could not be validated.
+	Validate(ctx context.Context, u Update) error
+}
+
+// Request is the body posted to the hooks.
+type Request struct {
+	Owner   string `json:"owner"` // hex encoded
+	ID      string `json:"id"`    // hex encoded
+	Span    uint64 `json:"span"`
+	Payload []byte `json:"payload"` // base64 encoded
+}
+
+// Rejection is the optional body of the response of the hook rejecting
+// the update. The raw body is the reason if it is not a Rejection.
+type Rejection struct {
+	Reason string `json:"reason"`
+}
+
+// Options are the options of the Service.
+type Options struct {
+	URLs    []string      // hooks the updates are posted to, in order
+	Timeout time.Duration // time the hook is waited to respond
+	Client  *http.Client
+}
+
+// Service posts the feed updates to the hooks, which accept them with the
+// 2xx status code and reject them with the 400 or 422 status code. Any other
+// response, or no response within the timeout, fails the validation, so that
+// no update passes unvalidated.
+type Service struct {
+	urls    []string
+	timeout time.Duration
+	client  *http.Client
+	logger  log.Logger
+}
+
+// New returns the Service posting the feed updates to the hooks of the options.
+func New(o Options, logger log.Logger) (*Service, error) {
+	for _, u := range o.URLs {
+		pu, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("parse feed validation hook url: %w", err)
+		}
+		if pu.Scheme != "http" && pu.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported feed validation hook url scheme: %q", pu.Scheme)
+		}
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Client == nil {
+		o.Client = new(http.Client)
+	}
+	return &Service{
+		urls:    o.URLs,
+		timeout: o.Timeout,
+		client:  o.Client,
+		logger:  logger.WithName(loggerName).Register(),
+	}, nil
+}
+
+// Validate implements the Validator. The update is posted to the hooks in
+// order and the first rejection or failure is returned.
+func (s *Service) Validate(ctx context.Context, u Update) error {
+	body, err := json.Marshal(Request{
+		Owner:   hex.EncodeToString(u.Owner),
+		ID:      hex.EncodeToString(u.ID),
+		Span:    u.Span,
+		Payload: u.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal feed update: %w", err)
+	}
+	for _, hook := range s.urls {
+		if err := s.post(ctx, hook, body); err != nil {
+			s.logger.Debug("feed update not validated", "owner", hex.EncodeToString(u.Owner), "id", hex.EncodeToString(u.ID), "hook", hook, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// post posts the body to the hook and interprets its response.
+func (s *Service) post(ctx context.Context, hook string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxReasonSize))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return nil
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity:
+		if reason := rejectionReason(data); reason != "" {
+			return fmt.Errorf("%w: %s", ErrRejected, reason)
+		}
+		return ErrRejected
+	default:
+		return fmt.Errorf("%w: hook responded with %s", ErrUnavailable, resp.Status)
+	}
+}
+
+// rejectionReason returns the reason of the Rejection,
+// or the trimmed raw body if it is not a Rejection.
+func rejectionReason(data []byte) string {
+	var r Rejection
+	if err := json.Unmarshal(data, &r); err == nil && r.Reason != "" {
+		return r.Reason
+	}
+	return strings.TrimSpace(string(data))
+}
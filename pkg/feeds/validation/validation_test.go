@@ -0,0 +1,140 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validation_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/feeds/validation"
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+// hook responds with the status code and the body to the updates,
+// after it checks the request against the update.
+func hook(t *testing.T, want validation.Update, code int, body string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req validation.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		if req.Owner != "aa" || req.ID != "bb" || req.Span != want.Span || !bytes.Equal(req.Payload, want.Payload) {
+			t.Errorf("got request %+v, want %+v", req, want)
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	update := validation.Update{
+		Owner:   []byte{0xaa},
+		ID:      []byte{0xbb},
+		Span:    3,
+		Payload: []byte("foo"),
+	}
+
+	for _, tc := range []struct {
+		name    string
+		codes   []int
+		bodies  []string
+		wantErr error
+		reason  string
+	}{
+		{
+			name:  "accepted",
+			codes: []int{http.StatusOK, http.StatusNoContent},
+		},
+		{
+			name:    "rejected with reason",
+			codes:   []int{http.StatusOK, http.StatusUnprocessableEntity},
+			bodies:  []string{"", `{"reason":"missing field"}`},
+			wantErr: validation.ErrRejected,
+			reason:  "missing field",
+		},
+		{
+			name:    "rejected with raw body",
+			codes:   []int{http.StatusBadRequest, http.StatusOK},
+			bodies:  []string{"bad schema\n", ""},
+			wantErr: validation.ErrRejected,
+			reason:  "bad schema",
+		},
+		{
+			name:    "hook failed",
+			codes:   []int{http.StatusInternalServerError},
+			wantErr: validation.ErrUnavailable,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var urls []string
+			for i, code := range tc.codes {
+				var body string
+				if i < len(tc.bodies) {
+					body = tc.bodies[i]
+				}
+				urls = append(urls, hook(t, update, code, body).URL)
+			}
+			v, err := validation.New(validation.Options{URLs: urls}, log.Noop)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = v.Validate(context.Background(), update)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+			if err != nil && !strings.HasSuffix(err.Error(), tc.reason) {
+				t.Fatalf("got error %q, want reason %q", err, tc.reason)
+			}
+		})
+	}
+}
+
+func TestValidateTimeout(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	v, err := validation.New(validation.Options{URLs: []string{srv.URL}, Timeout: 50 * time.Millisecond}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Validate(context.Background(), validation.Update{}); !errors.Is(err, validation.ErrUnavailable) {
+		t.Fatalf("got error %v, want %v", err, validation.ErrUnavailable)
+	}
+}
+
+func TestNewInvalidURL(t *testing.T) {
+	t.Parallel()
+
+	if _, err := validation.New(validation.Options{URLs: []string{"ftp://example.com"}}, log.Noop); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -22,7 +22,8 @@ type Joiner interface {
 	Reader
 	// IterateChunkAddresses is used to iterate over chunks addresses of some root hash.
 	IterateChunkAddresses(swarm.AddressIterFunc) error
-	// Size returns the span of the hash trie represented by the joiner's root hash.
+	// Size returns the length of the content represented by the joiner's root hash,
+	// which excludes the padding of the padded encrypted content.
 	Size() int64
 }
 
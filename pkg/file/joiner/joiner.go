@@ -25,6 +25,7 @@ type joiner struct {
 	addr      swarm.Address
 	rootData  []byte
 	span      int64
+	size      int64 // length of the content without the padding
 	off       int64
 	refLength int
 
@@ -32,8 +33,20 @@ type joiner struct {
 	getter storage.Getter
 }
 
+// ErrInvalidSize is returned when the size of the content
+// is larger than the span of its root chunk.
+var ErrInvalidSize = errors.New("content size exceeds the span")
+
 // New creates a new Joiner. A Joiner provides Read, Seek and Size functionalities.
 func New(ctx context.Context, getter storage.Getter, address swarm.Address) (file.Joiner, int64, error) {
+	return NewSized(ctx, getter, address, -1)
+}
+
+// NewSized creates a new Joiner of the first size bytes of the content, which
+// strips the padding of the padded content whose length without the padding
+// is recorded out of band, see package padding. The whole content is joined
+// if the size is negative.
+func NewSized(ctx context.Context, getter storage.Getter, address swarm.Address, size int64) (file.Joiner, int64, error) {
 	getter = store.New(getter)
 	// retrieve the root chunk to read the total data length the be retrieved
 	rootChunk, err := getter.Get(ctx, storage.ModeGetRequest, address)
@@ -44,6 +57,12 @@ func New(ctx context.Context, getter storage.Getter, address swarm.Address) (fil
 	var chunkData = rootChunk.Data()
 
 	span := int64(binary.LittleEndian.Uint64(chunkData[:swarm.SpanSize]))
+	if size > span {
+		return nil, 0, ErrInvalidSize
+	}
+	if size < 0 {
+		size = span
+	}
 
 	j := &joiner{
 		addr:      rootChunk.Address(),
@@ -51,10 +70,11 @@ func New(ctx context.Context, getter storage.Getter, address swarm.Address) (fil
 		ctx:       ctx,
 		getter:    getter,
 		span:      span,
+		size:      size,
 		rootData:  chunkData[swarm.SpanSize:],
 	}
 
-	return j, span, nil
+	return j, size, nil
 }
 
 // Read is called by the consumer to retrieve the joined data.
@@ -71,13 +91,13 @@ func (j *joiner) Read(b []byte) (n int, err error) {
 
 func (j *joiner) ReadAt(buffer []byte, off int64) (read int, err error) {
 	// since offset is int64 and swarm spans are uint64 it means we cannot seek beyond int64 max value
-	if off >= j.span {
+	if off >= j.size {
 		return 0, io.EOF
 	}
 
 	readLen := int64(cap(buffer))
-	if readLen > j.span-off {
-		readLen = j.span - off
+	if readLen > j.size-off {
+		readLen = j.size - off
 	}
 	var bytesRead int64
 	var eg errgroup.Group
@@ -93,6 +113,13 @@ func (j *joiner) ReadAt(buffer []byte, off int64) (read int, err error) {
 
 var ErrMalformedTrie = errors.New("malformed tree")
 
+// subtrieRead is the read of the part of a subtrie into the buffer.
+type subtrieRead struct {
+	address                             swarm.Address
+	cur, off, bufferOffset, bytesToRead int64
+	spanLimit                           int64
+}
+
 func (j *joiner) readAtOffset(b, data []byte, cur, subTrieSize, off, bufferOffset, bytesToRead int64, bytesRead *int64, eg *errgroup.Group) {
 	// we are at a leaf data chunk
 	if subTrieSize <= int64(len(data)) {
@@ -109,6 +136,7 @@ func (j *joiner) readAtOffset(b, data []byte, cur, subTrieSize, off, bufferOffse
 		return
 	}
 
+	var reads []subtrieRead
 	for cursor := 0; cursor < len(data); cursor += j.refLength {
 		if bytesToRead == 0 {
 			break
@@ -137,30 +165,74 @@ func (j *joiner) readAtOffset(b, data []byte, cur, subTrieSize, off, bufferOffse
 			currentReadSize = subtrieSpan
 		}
 
-		func(address swarm.Address, b []byte, cur, subTrieSize, off, bufferOffset, bytesToRead, subtrieSpanLimit int64) {
-			eg.Go(func() error {
-				ch, err := j.getter.Get(j.ctx, storage.ModeGetRequest, address)
-				if err != nil {
-					return err
-				}
-
-				chunkData := ch.Data()[8:]
-				subtrieSpan := int64(chunkToSpan(ch.Data()))
-
-				if subtrieSpan > subtrieSpanLimit {
-					return ErrMalformedTrie
-				}
-
-				j.readAtOffset(b, chunkData, cur, subtrieSpan, off, bufferOffset, currentReadSize, bytesRead, eg)
-				return nil
-			})
-		}(address, b, cur, subtrieSpan, off, bufferOffset, currentReadSize, subtrieSpanLimit)
+		reads = append(reads, subtrieRead{
+			address:      address,
+			cur:          cur,
+			off:          off,
+			bufferOffset: bufferOffset,
+			bytesToRead:  currentReadSize,
+			spanLimit:    subtrieSpanLimit,
+		})
 
 		bufferOffset += currentReadSize
 		bytesToRead -= currentReadSize
 		cur += subtrieSpan
 		off = cur
 	}
+
+	// the chunks of the subtries are got at once if the getter supports
+	// it, which reads the locally stored chunks concurrently, otherwise
+	// or if any of them is missing locally, they are got one by one
+	mg, ok := j.getter.(storage.MultiGetter)
+	if !ok || len(reads) < 2 {
+		for _, r := range reads {
+			j.getSubtrie(b, r, bytesRead, eg)
+		}
+		return
+	}
+	eg.Go(func() error {
+		addrs := make([]swarm.Address, len(reads))
+		for i, r := range reads {
+			addrs[i] = r.address
+		}
+		chs, err := mg.GetMulti(j.ctx, storage.ModeGetRequest, addrs...)
+		if err != nil {
+			for _, r := range reads {
+				j.getSubtrie(b, r, bytesRead, eg)
+			}
+			return nil
+		}
+		for i, r := range reads {
+			if err := j.readSubtrie(b, chs[i], r, bytesRead, eg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// getSubtrie gets the chunk of the subtrie and reads the subtrie.
+func (j *joiner) getSubtrie(b []byte, r subtrieRead, bytesRead *int64, eg *errgroup.Group) {
+	eg.Go(func() error {
+		ch, err := j.getter.Get(j.ctx, storage.ModeGetRequest, r.address)
+		if err != nil {
+			return err
+		}
+		return j.readSubtrie(b, ch, r, bytesRead, eg)
+	})
+}
+
+// readSubtrie reads the subtrie of the chunk.
+func (j *joiner) readSubtrie(b []byte, ch swarm.Chunk, r subtrieRead, bytesRead *int64, eg *errgroup.Group) error {
+	chunkData := ch.Data()[8:]
+	subtrieSpan := int64(chunkToSpan(ch.Data()))
+
+	if subtrieSpan > r.spanLimit {
+		return ErrMalformedTrie
+	}
+
+	j.readAtOffset(b, chunkData, r.cur, subtrieSpan, r.off, r.bufferOffset, r.bytesToRead, bytesRead, eg)
+	return nil
 }
 
 // brute-forces the subtrie size for each of the sections in this intermediate chunk
@@ -203,7 +275,7 @@ func (j *joiner) Seek(offset int64, whence int) (int64, error) {
 		offset += j.off
 	case 2:
 
-		offset = j.span - offset
+		offset = j.size - offset
 		if offset < 0 {
 			return 0, io.EOF
 		}
@@ -214,7 +286,7 @@ func (j *joiner) Seek(offset int64, whence int) (int64, error) {
 	if offset < 0 {
 		return 0, errOffset
 	}
-	if offset > j.span {
+	if offset > j.size {
 		return 0, io.EOF
 	}
 	j.off = offset
@@ -292,7 +364,7 @@ func (j *joiner) processChunkAddresses(ctx context.Context, fn swarm.AddressIter
 }
 
 func (j *joiner) Size() int64 {
-	return j.span
+	return j.size
 }
 
 func chunkToSpan(data []byte) uint64 {
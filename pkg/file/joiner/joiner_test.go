@@ -13,12 +13,14 @@ import (
 	"io"
 	mrand "math/rand"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/cac"
 	"github.com/ethersphere/bee/pkg/encryption/store"
 	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/padding"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
 	"github.com/ethersphere/bee/pkg/file/splitter"
 	filetest "github.com/ethersphere/bee/pkg/file/testing"
@@ -283,6 +285,79 @@ func TestEncryptDecrypt(t *testing.T) {
 	}
 }
 
+func TestEncryptDecryptPadded(t *testing.T) {
+	t.Parallel()
+
+	const blockSize = 16 * swarm.ChunkSize
+
+	for _, length := range []int{10, 4096, 100000, blockSize} {
+		length := length
+		t.Run(fmt.Sprintf("Encrypt %d bytes", length), func(t *testing.T) {
+			t.Parallel()
+
+			store := mock.NewStorer()
+
+			g := mockbytes.New(0, mockbytes.MockTypeStandard).WithModulus(255)
+			testData, err := g.SequentialBytes(length)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			pipe, err := padding.NewWriter(builder.NewPipelineBuilder(ctx, store, storage.ModePutUpload, true), blockSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resultAddress, err := builder.FeedPipeline(ctx, pipe, bytes.NewReader(testData))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reader, l, err := joiner.NewSized(ctx, store, resultAddress, int64(length))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if l != int64(length) {
+				t.Fatalf("expected join data length %d, got %d", length, l)
+			}
+			if reader.Size() != int64(length) {
+				t.Fatalf("expected size %d, got %d", length, reader.Size())
+			}
+
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(testData, got) {
+				t.Fatal("input data and output data does not match")
+			}
+
+			// all of the padding chunks are part of the trie
+			var chunks int
+			if err := reader.IterateChunkAddresses(func(swarm.Address) error {
+				chunks++
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if want := int(padding.Size(int64(length), blockSize)/swarm.ChunkSize) + 1; chunks != want {
+				t.Fatalf("got %d chunks, want %d", chunks, want)
+			}
+
+			// the padding is not stripped without the size
+			_, l, err = joiner.New(ctx, store, resultAddress)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := padding.Size(int64(length), blockSize); l != want {
+				t.Fatalf("expected join data length %d, got %d", want, l)
+			}
+			if _, _, err := joiner.NewSized(ctx, store, resultAddress, l+1); !errors.Is(err, joiner.ErrInvalidSize) {
+				t.Fatalf("got error %v, want %v", err, joiner.ErrInvalidSize)
+			}
+		})
+	}
+}
+
 func TestSeek(t *testing.T) {
 	t.Parallel()
 
@@ -961,3 +1036,67 @@ func TestJoinerIterateChunkAddresses_Encrypted(t *testing.T) {
 		}
 	}
 }
+
+// multiGetter counts the GetMulti calls of the storer, which fail if fail is set.
+type multiGetter struct {
+	storage.Storer
+	fail  bool
+	calls int32
+}
+
+func (m *multiGetter) GetMulti(ctx context.Context, mode storage.ModeGet, addrs ...swarm.Address) ([]swarm.Chunk, error) {
+	atomic.AddInt32(&m.calls, 1)
+	if m.fail {
+		return nil, storage.ErrNotFound
+	}
+	return m.Storer.GetMulti(ctx, mode, addrs...)
+}
+
+func TestJoinerGetMulti(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		encrypt bool
+		fail    bool
+	}{
+		{name: "plain"},
+		{name: "encrypted", encrypt: true},
+		{name: "fallback", fail: true},
+		{name: "encrypted fallback", encrypt: true, fail: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &multiGetter{Storer: mock.NewStorer(), fail: tc.fail}
+
+			testData := make([]byte, 200*swarm.ChunkSize+100)
+			if _, err := mrand.New(mrand.NewSource(1)).Read(testData); err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			pipe := builder.NewPipelineBuilder(ctx, store, storage.ModePutUpload, tc.encrypt)
+			addr, err := builder.FeedPipeline(ctx, pipe, bytes.NewReader(testData))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			j, l, err := joiner.New(ctx, store, addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]byte, l)
+			n, err := j.ReadAt(got, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != len(testData) || !bytes.Equal(got, testData) {
+				t.Fatal("input data and output data does not match")
+			}
+			if atomic.LoadInt32(&store.calls) == 0 {
+				t.Fatal("chunks not got at once")
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package padding pads the content to a multiple of a block size, so that
+// the size of the uploaded content, which can be observed through the number
+// of its chunks, does not reveal the exact length of the plaintext.
+//
+// The padding is zero bytes appended to the content and it is not marked
+// within the content, which is never reinterpreted on download. The length
+// of the content without the padding is recorded out of band instead: in the
+// metadata of its manifest entry, or by the client, which gets it on upload
+// and passes it on download.
+package padding
+
+import (
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// ErrInvalidBlockSize is returned for block sizes
+// which are not a positive multiple of the chunk size.
+var ErrInvalidBlockSize = fmt.Errorf("block size must be a positive multiple of %d", swarm.ChunkSize)
+
+// ValidateBlockSize checks whether the content can be padded to the block size.
+// As the encrypted chunks are always padded to the chunk size, smaller
+// blocks would not hide anything.
+func ValidateBlockSize(blockSize int64) error {
+	if blockSize <= 0 || blockSize%swarm.ChunkSize != 0 {
+		return ErrInvalidBlockSize
+	}
+	return nil
+}
+
+// Size returns the size of the content of the given length
+// after it has been padded to a multiple of the block size.
+// The empty content is padded to a single block.
+func Size(length, blockSize int64) int64 {
+	blocks := (length + blockSize - 1) / blockSize
+	if blocks == 0 {
+		blocks = 1
+	}
+	return blocks * blockSize
+}
+
+type writer struct {
+	next      pipeline.Interface
+	blockSize int64
+	length    int64
+}
+
+// NewWriter returns a pipeline which pads the content written
+// to the next pipeline to a multiple of the block size on Sum.
+func NewWriter(next pipeline.Interface, blockSize int64) (pipeline.Interface, error) {
+	if err := ValidateBlockSize(blockSize); err != nil {
+		return nil, err
+	}
+	return &writer{next: next, blockSize: blockSize}, nil
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	n, err := w.next.Write(p)
+	w.length += int64(n)
+	return n, err
+}
+
+// Sum writes the padding before summing the next pipeline.
+func (w *writer) Sum() ([]byte, error) {
+	zeros := make([]byte, swarm.ChunkSize)
+	for pad := Size(w.length, w.blockSize) - w.length; pad > 0; {
+		n := int64(len(zeros))
+		if n > pad {
+			n = pad
+		}
+		if _, err := w.next.Write(zeros[:n]); err != nil {
+			return nil, err
+		}
+		pad -= n
+	}
+
+	return w.next.Sum()
+}
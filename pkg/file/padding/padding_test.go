@@ -0,0 +1,69 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package padding_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/file/padding"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// bufferPipeline collects the written data.
+type bufferPipeline struct {
+	bytes.Buffer
+}
+
+func (b *bufferPipeline) Sum() ([]byte, error) {
+	return nil, nil
+}
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	const blockSize = 4 * swarm.ChunkSize
+
+	for _, length := range []int{0, 1, blockSize, blockSize + 1, 3*blockSize + 5} {
+		data := bytes.Repeat([]byte{0xff}, length)
+
+		var buf bufferPipeline
+		w, err := padding.NewWriter(&buf, blockSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Sum(); err != nil {
+			t.Fatal(err)
+		}
+
+		padded := buf.Bytes()
+		if want := padding.Size(int64(length), blockSize); int64(len(padded)) != want {
+			t.Fatalf("length %d: got padded size %d, want %d", length, len(padded), want)
+		}
+		if len(padded)%blockSize != 0 {
+			t.Fatalf("length %d: padded size %d is not a multiple of the block size", length, len(padded))
+		}
+		if !bytes.Equal(padded[:length], data) {
+			t.Fatalf("length %d: content changed", length)
+		}
+		if !bytes.Equal(padded[length:], make([]byte, len(padded)-length)) {
+			t.Fatalf("length %d: padding not zero", length)
+		}
+	}
+}
+
+func TestNewWriterBlockSize(t *testing.T) {
+	t.Parallel()
+
+	for _, blockSize := range []int64{0, -swarm.ChunkSize, swarm.ChunkSize + 1} {
+		if _, err := padding.NewWriter(new(bufferPipeline), blockSize); !errors.Is(err, padding.ErrInvalidBlockSize) {
+			t.Fatalf("block size %d: got error %v, want %v", blockSize, err, padding.ErrInvalidBlockSize)
+		}
+	}
+}
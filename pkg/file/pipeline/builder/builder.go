@@ -29,6 +29,33 @@ func NewPipelineBuilder(ctx context.Context, s storage.Putter, mode storage.Mode
 	return newPipeline(ctx, s, mode)
 }
 
+// NewCheckpointPipelineBuilder returns the pipeline which reports the checkpoints
+// of its hash trie to the function. If from is not nil, the pipeline continues
+// from the checkpoint and only the content following its offset must be written.
+func NewCheckpointPipelineBuilder(ctx context.Context, s storage.Putter, mode storage.ModePut, encrypt bool, from *hashtrie.Checkpoint, fn hashtrie.CheckpointFunc) (pipeline.Interface, error) {
+	var (
+		chain pipeline.ChainWriter
+		err   error
+	)
+	if encrypt {
+		chain, err = hashtrie.NewCheckpointHashTrieWriter(swarm.ChunkSize, 64, swarm.HashSize+encryption.KeyLength, newShortEncryptionPipelineFunc(ctx, s, mode), from, fn)
+		if err != nil {
+			return nil, err
+		}
+		chain = store.NewStoreWriter(ctx, s, mode, chain)
+		chain = bmt.NewBmtWriter(chain)
+		chain = enc.NewEncryptionWriter(encryption.NewChunkEncrypter(), chain)
+	} else {
+		chain, err = hashtrie.NewCheckpointHashTrieWriter(swarm.ChunkSize, swarm.Branches, swarm.HashSize, newShortPipelineFunc(ctx, s, mode), from, fn)
+		if err != nil {
+			return nil, err
+		}
+		chain = store.NewStoreWriter(ctx, s, mode, chain)
+		chain = bmt.NewBmtWriter(chain)
+	}
+	return feeder.NewChunkFeederWriter(swarm.ChunkSize, chain), nil
+}
+
 // newPipeline creates a standard pipeline that only hashes content with BMT to create
 // a merkle-tree of hashes that represent the given arbitrary size byte stream. Partial
 // writes are supported. The pipeline flow is: Data -> Feeder -> BMT -> Storage -> HashTrie.
@@ -71,6 +98,45 @@ func newShortEncryptionPipelineFunc(ctx context.Context, s storage.Putter, mode
 	}
 }
 
+// NewBalancedEncryptionPipelineBuilder returns the encryption pipeline which
+// chooses the keys of the chunks so that they are placed into the collision
+// buckets of the balancer with room. The pipeline flow is: Data -> Feeder ->
+// Balancing Encryption and BMT -> Storage -> HashTrie.
+func NewBalancedEncryptionPipelineBuilder(ctx context.Context, s storage.Putter, mode storage.ModePut, b *enc.Balancer) pipeline.Interface {
+	shortPipeline := func() pipeline.ChainWriter {
+		lsw := store.NewStoreWriter(ctx, s, mode, nil)
+		return enc.NewBalancingEncryptionWriter(encryption.NewChunkEncrypter(), b, lsw)
+	}
+	tw := hashtrie.NewHashTrieWriter(swarm.ChunkSize, 64, swarm.HashSize+encryption.KeyLength, shortPipeline)
+	lsw := store.NewStoreWriter(ctx, s, mode, tw)
+	w := enc.NewBalancingEncryptionWriter(encryption.NewChunkEncrypter(), b, lsw)
+	return feeder.NewChunkFeederWriter(swarm.ChunkSize, w)
+}
+
+// NewNeighbourhoodEncryptionPipelineBuilder returns the encryption pipeline
+// which chooses the keys of the chunks which may be the root of the content
+// so that they are placed within the neighbourhood. The other chunks are
+// placed into the collision buckets of the balancer with room, if it is not
+// nil. The pipeline flow is: Data -> Feeder -> Placing Encryption and BMT ->
+// Storage -> HashTrie.
+func NewNeighbourhoodEncryptionPipelineBuilder(ctx context.Context, s storage.Putter, mode storage.ModePut, n *enc.Neighbourhood, b *enc.Balancer) pipeline.Interface {
+	placingWriter := func(mineFirst bool, next pipeline.ChainWriter) pipeline.ChainWriter {
+		var unplaced pipeline.ChainWriter
+		if b != nil {
+			unplaced = enc.NewBalancingEncryptionWriter(encryption.NewChunkEncrypter(), b, next)
+		} else {
+			unplaced = enc.NewEncryptionWriter(encryption.NewChunkEncrypter(), bmt.NewBmtWriter(next))
+		}
+		return enc.NewNeighbourhoodEncryptionWriter(encryption.NewChunkEncrypter(), n, b, mineFirst, unplaced, next)
+	}
+	shortPipeline := func() pipeline.ChainWriter {
+		return placingWriter(false, store.NewStoreWriter(ctx, s, mode, nil))
+	}
+	tw := hashtrie.NewHashTrieWriter(swarm.ChunkSize, 64, swarm.HashSize+encryption.KeyLength, shortPipeline)
+	lsw := store.NewStoreWriter(ctx, s, mode, tw)
+	return n.Pipeline(feeder.NewChunkFeederWriter(swarm.ChunkSize, placingWriter(true, lsw)))
+}
+
 // FeedPipeline feeds the pipeline with the given reader until EOF is reached.
 // It returns the cryptographic root hash of the content.
 func FeedPipeline(ctx context.Context, pipeline pipeline.Interface, r io.Reader) (addr swarm.Address, err error) {
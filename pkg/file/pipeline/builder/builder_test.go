@@ -9,10 +9,14 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"strconv"
 	"testing"
 
+	"github.com/ethersphere/bee/pkg/file/joiner"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/file/pipeline/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline/hashtrie"
 	test "github.com/ethersphere/bee/pkg/file/testing"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
@@ -60,6 +64,100 @@ func TestHelloWorld(t *testing.T) {
 	}
 }
 
+// TestCheckpointResume tests that the content written from the
+// checkpoint of an interrupted pipeline results in the same hash.
+func TestCheckpointResume(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := mock.NewStorer()
+	data := testutil.RandBytes(t, 2*swarm.Branches*swarm.ChunkSize+1000)
+
+	p := builder.NewPipelineBuilder(ctx, m, storage.ModePutUpload, false)
+	exp, err := builder.FeedPipeline(ctx, p, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var checkpoints []hashtrie.Checkpoint
+	p, err = builder.NewCheckpointPipelineBuilder(ctx, m, storage.ModePutUpload, false, nil, func(c hashtrie.Checkpoint) error {
+		checkpoints = append(checkpoints, c)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// interrupted after the first branch
+	if _, err := p.Write(data[:swarm.Branches*swarm.ChunkSize+100]); err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoints) != 1 {
+		t.Fatalf("got %d checkpoints, want 1", len(checkpoints))
+	}
+	from := checkpoints[0]
+	if from.Offset != swarm.Branches*swarm.ChunkSize {
+		t.Fatalf("got checkpoint offset %d, want %d", from.Offset, swarm.Branches*swarm.ChunkSize)
+	}
+
+	p, err = builder.NewCheckpointPipelineBuilder(ctx, m, storage.ModePutUpload, false, &from, func(hashtrie.Checkpoint) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := builder.FeedPipeline(ctx, p, bytes.NewReader(data[from.Offset:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(exp) {
+		t.Fatalf("got resumed hash %s, want %s", got, exp)
+	}
+}
+
+// TestNeighbourhoodEncryption tests that the root chunk of the encrypted
+// content is placed within the neighbourhood and the content is joined.
+func TestNeighbourhoodEncryption(t *testing.T) {
+	t.Parallel()
+
+	const depth = 4
+	overlay := swarm.RandAddress(t)
+
+	for _, size := range []int{
+		100,
+		swarm.ChunkSize,
+		3*swarm.ChunkSize + 10,
+		swarm.EncryptedBranches*swarm.ChunkSize + 5,
+	} {
+		size := size
+		t.Run(strconv.Itoa(size), func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			m := mock.NewStorer()
+			data := testutil.RandBytes(t, size)
+
+			p := builder.NewNeighbourhoodEncryptionPipelineBuilder(ctx, m, storage.ModePutUpload, encryption.NewNeighbourhood(overlay, depth), nil)
+			ref, err := builder.FeedPipeline(ctx, p, bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if po := swarm.Proximity(overlay.Bytes(), ref.Bytes()[:swarm.HashSize]); po < depth {
+				t.Fatalf("got root proximity %d, want at least %d", po, depth)
+			}
+
+			j, _, err := joiner.New(ctx, m, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(j)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatal("joined content mismatch")
+			}
+		})
+	}
+}
+
 // TestEmpty tests that a hash is generated for an empty file.
 func TestEmpty(t *testing.T) {
 	t.Parallel()
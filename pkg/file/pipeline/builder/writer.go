@@ -0,0 +1,118 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var (
+	// ErrWriterClosed is returned when the content is written to the closed Writer.
+	ErrWriterClosed = errors.New("pipeline writer closed")
+	// ErrChunkAddress is returned when the ChunkFunc changes the chunk address.
+	ErrChunkAddress = errors.New("chunk func changed the chunk address")
+)
+
+// ChunkFunc is called with every chunk of the content, the data chunks as
+// well as the intermediate chunks, before it is stored. The returned chunk
+// is stored instead of the given one, so that the chunk can be stamped for
+// example, it must have the same address as the reference to it is already
+// computed. An error aborts the write.
+type ChunkFunc func(ctx context.Context, ch swarm.Chunk) (swarm.Chunk, error)
+
+// Options are the options of the Writer.
+type Options struct {
+	Mode      storage.ModePut
+	Encrypt   bool
+	ChunkFunc ChunkFunc
+}
+
+var _ io.WriteCloser = (*Writer)(nil)
+
+// Writer streams the content of arbitrary length into the storage
+// through the splitter pipeline, without holding the whole content
+// in memory. The reference of the content is known once the Writer
+// is closed.
+type Writer struct {
+	ctx       context.Context
+	pipeline  pipeline.Interface
+	reference swarm.Address
+	closed    bool
+}
+
+// NewWriter returns a Writer which stores the chunks of the content to s.
+func NewWriter(ctx context.Context, s storage.Putter, o Options) *Writer {
+	if o.ChunkFunc != nil {
+		s = &chunkFuncPutter{Putter: s, chunkFunc: o.ChunkFunc}
+	}
+	return &Writer{
+		ctx:      ctx,
+		pipeline: NewPipelineBuilder(ctx, s, o.Mode, o.Encrypt),
+	}
+}
+
+// Write implements the io.Writer interface.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.pipeline.Write(p)
+}
+
+// Close flushes the remaining content and computes its reference.
+// It implements the io.Closer interface.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+	w.closed = true
+
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	sum, err := w.pipeline.Sum()
+	if err != nil {
+		return err
+	}
+	w.reference = swarm.NewAddress(sum)
+	return nil
+}
+
+// Reference returns the reference of the content, it is
+// the zero address until the Writer is successfully closed.
+func (w *Writer) Reference() swarm.Address {
+	return w.reference
+}
+
+// chunkFuncPutter calls the ChunkFunc on the chunks before storing them.
+type chunkFuncPutter struct {
+	storage.Putter
+	chunkFunc ChunkFunc
+}
+
+func (p *chunkFuncPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	res := make([]swarm.Chunk, len(chs))
+	for i, ch := range chs {
+		c, err := p.chunkFunc(ctx, ch)
+		if err != nil {
+			return nil, err
+		}
+		if !c.Address().Equal(ch.Address()) {
+			return nil, fmt.Errorf("%w: got %s, want %s", ErrChunkAddress, c.Address(), ch.Address())
+		}
+		res[i] = c
+	}
+	return p.Putter.Put(ctx, mode, res...)
+}
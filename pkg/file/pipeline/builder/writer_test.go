@@ -0,0 +1,118 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	test "github.com/ethersphere/bee/pkg/file/testing"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	for i := 1; i <= 20; i++ {
+		data, expect := test.GetVector(t, i)
+		m := mock.NewStorer()
+		var chunks int
+		w := builder.NewWriter(context.Background(), m, builder.Options{
+			Mode: storage.ModePutUpload,
+			ChunkFunc: func(_ context.Context, ch swarm.Chunk) (swarm.Chunk, error) {
+				chunks++
+				return ch, nil
+			},
+		})
+
+		// stream the data in uneven pieces
+		if _, err := io.CopyBuffer(w, bytes.NewReader(data), make([]byte, 1000)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !w.Reference().Equal(expect) {
+			t.Fatalf("vector %d: got reference %s, want %s", i, w.Reference(), expect)
+		}
+		if chunks == 0 {
+			t.Fatalf("vector %d: chunk func not called", i)
+		}
+		if _, err := m.Get(context.Background(), storage.ModeGetRequest, expect); err != nil {
+			t.Fatalf("vector %d: root chunk not stored: %v", i, err)
+		}
+	}
+
+	t.Run("chunk func error", func(t *testing.T) {
+		t.Parallel()
+
+		errChunk := errors.New("chunk func error")
+		w := builder.NewWriter(context.Background(), mock.NewStorer(), builder.Options{
+			Mode: storage.ModePutUpload,
+			ChunkFunc: func(context.Context, swarm.Chunk) (swarm.Chunk, error) {
+				return nil, errChunk
+			},
+		})
+		if _, err := w.Write([]byte("hello world")); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); !errors.Is(err, errChunk) {
+			t.Fatalf("got error %v, want %v", err, errChunk)
+		}
+		if !w.Reference().IsZero() {
+			t.Fatalf("got reference %s, want zero address", w.Reference())
+		}
+	})
+
+	t.Run("chunk func address", func(t *testing.T) {
+		t.Parallel()
+
+		w := builder.NewWriter(context.Background(), mock.NewStorer(), builder.Options{
+			Mode: storage.ModePutUpload,
+			ChunkFunc: func(_ context.Context, ch swarm.Chunk) (swarm.Chunk, error) {
+				return swarm.NewChunk(swarm.MustParseHexAddress("aabbcc0000000000000000000000000000000000000000000000000000000000"), ch.Data()), nil
+			},
+		})
+		if _, err := w.Write([]byte("hello world")); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); !errors.Is(err, builder.ErrChunkAddress) {
+			t.Fatalf("got error %v, want %v", err, builder.ErrChunkAddress)
+		}
+		if !w.Reference().IsZero() {
+			t.Fatalf("got reference %s, want zero address", w.Reference())
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		t.Parallel()
+
+		w := builder.NewWriter(context.Background(), mock.NewStorer(), builder.Options{Mode: storage.ModePutUpload})
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte("hello world")); !errors.Is(err, builder.ErrWriterClosed) {
+			t.Fatalf("got error %v, want %v", err, builder.ErrWriterClosed)
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		w := builder.NewWriter(ctx, mock.NewStorer(), builder.Options{Mode: storage.ModePutUpload})
+		if _, err := w.Write([]byte("hello world")); !errors.Is(err, context.Canceled) {
+			t.Fatalf("got error %v, want %v", err, context.Canceled)
+		}
+	})
+}
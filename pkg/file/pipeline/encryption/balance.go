@@ -0,0 +1,140 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption
+
+import (
+	"sync/atomic"
+
+	"github.com/ethersphere/bee/pkg/bmtpool"
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// The address of an encrypted chunk depends on its random key, so the
+// collision bucket of the batch the chunk is stamped in can be chosen by
+// trying another key. With the number of the chunks still to be written known
+// in advance, each bucket is expected to receive its share of them. A key is
+// kept as soon as the bucket of the chunk has room for its share, otherwise
+// up to maxBalanceTries keys are tried and the one of the least utilized
+// bucket is kept. This prevents the premature overflow of the buckets of the
+// immutable batches with the content of a skewed address distribution, while
+// the chunks stamped into the batches with room are encrypted only once.
+
+// maxBalanceTries is the maximal number of the keys tried for a chunk.
+var maxBalanceTries = 16
+
+// Balancer tracks the chunks still to be written by the balancing
+// encryption writers of a pipeline.
+type Balancer struct {
+	utilizer postage.BucketUtilizer
+	upcoming atomic.Int64
+}
+
+// NewBalancer returns the Balancer of the buckets of the utilizer
+// for the upcoming number of chunks, zero if not known.
+func NewBalancer(utilizer postage.BucketUtilizer, upcoming int64) *Balancer {
+	b := &Balancer{utilizer: utilizer}
+	b.upcoming.Store(upcoming)
+	return b
+}
+
+// share returns the number of the upcoming chunks,
+// including the next one, expected in each of the buckets.
+func (b *Balancer) share(buckets uint32) uint32 {
+	upcoming := b.upcoming.Add(-1) + 1
+	if upcoming <= 0 || buckets == 0 {
+		return 1
+	}
+	return uint32((upcoming + int64(buckets) - 1) / int64(buckets))
+}
+
+type balancingWriter struct {
+	next     pipeline.ChainWriter
+	enc      encryption.ChunkEncrypter
+	balancer *Balancer
+}
+
+// NewBalancingEncryptionWriter returns the writer which encrypts the chunk
+// with the key placing it into the bucket of the balancer with room, and
+// hashes it, so it replaces both the encryption and the bmt writers.
+func NewBalancingEncryptionWriter(encrypter encryption.ChunkEncrypter, balancer *Balancer, next pipeline.ChainWriter) pipeline.ChainWriter {
+	return &balancingWriter{
+		next:     next,
+		enc:      encrypter,
+		balancer: balancer,
+	}
+}
+
+// ChainWrite assumes that the span is prepended to the actual data before the write !
+func (w *balancingWriter) ChainWrite(p *pipeline.PipeWriteArgs) error {
+	var (
+		best  *candidate
+		share uint32 // the upcoming chunks expected in each bucket
+	)
+	for try := 0; try < maxBalanceTries; try++ {
+		c, err := encrypt(w.enc, p.Data)
+		if err != nil {
+			return err
+		}
+		u := w.balancer.utilizer.BucketUtilization(swarm.NewAddress(c.ref))
+		if try == 0 {
+			share = w.balancer.share(u.Buckets)
+		}
+		c.count = u.Count
+		if best == nil || c.count < best.count {
+			best = c
+		}
+		// the utilization is not known or the bucket has room for its share
+		if u.UpperBound == 0 || u.Count+share <= u.UpperBound {
+			best = c
+			break
+		}
+	}
+	p.Data = best.data // replace the verbatim data with the encrypted data
+	p.Key = best.key
+	p.Ref = best.ref
+	return w.next.ChainWrite(p)
+}
+
+// candidate is the chunk encrypted with one of the tried keys.
+type candidate struct {
+	key   encryption.Key
+	data  []byte
+	ref   []byte
+	count uint32 // chunks stamped in the bucket of the ref
+}
+
+// encrypt encrypts the data with a random key and hashes it.
+func encrypt(enc encryption.ChunkEncrypter, data []byte) (*candidate, error) {
+	key, encryptedSpan, encryptedData, err := enc.EncryptChunk(data)
+	if err != nil {
+		return nil, err
+	}
+	c := make([]byte, len(encryptedSpan)+len(encryptedData))
+	copy(c[:swarm.SpanSize], encryptedSpan)
+	copy(c[swarm.SpanSize:], encryptedData)
+	ref, err := hash(c)
+	if err != nil {
+		return nil, err
+	}
+	return &candidate{key: key, data: c, ref: ref}, nil
+}
+
+func (w *balancingWriter) Sum() ([]byte, error) {
+	return w.next.Sum()
+}
+
+// hash returns the bmt hash of the data prepended with the span.
+func hash(data []byte) ([]byte, error) {
+	hasher := bmtpool.Get()
+	defer bmtpool.Put(hasher)
+	hasher.SetHeader(data[:swarm.SpanSize])
+	if _, err := hasher.Write(data[swarm.SpanSize:]); err != nil {
+		return nil, err
+	}
+	return hasher.Hash(nil)
+}
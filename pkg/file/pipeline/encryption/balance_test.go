@@ -0,0 +1,131 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/bmtpool"
+	enc "github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/encryption"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// twoBuckets is the utilizer of a batch with two buckets, by the first
+// bit of the address, whose utilization is set by the test.
+type twoBuckets [2]uint32
+
+func (b *twoBuckets) BucketUtilization(addr swarm.Address) postage.BucketUtilization {
+	return postage.BucketUtilization{
+		Count:      b[addr.Bytes()[0]>>7],
+		UpperBound: 16,
+		Buckets:    2,
+	}
+}
+
+// countingEncrypter counts the encrypted chunks.
+type countingEncrypter struct {
+	enc.ChunkEncrypter
+	calls int
+}
+
+func (e *countingEncrypter) EncryptChunk(data []byte) (enc.Key, []byte, []byte, error) {
+	e.calls++
+	return e.ChunkEncrypter.EncryptChunk(data)
+}
+
+// refsWriter records the written chunks.
+type refsWriter struct {
+	args []pipeline.PipeWriteArgs
+}
+
+func (w *refsWriter) ChainWrite(p *pipeline.PipeWriteArgs) error {
+	w.args = append(w.args, *p)
+	return nil
+}
+
+func (w *refsWriter) Sum() ([]byte, error) {
+	return nil, nil
+}
+
+// nolint:paralleltest
+func TestBalancingEncryption(t *testing.T) {
+	defer func(n int) { *encryption.MaxBalanceTries = n }(*encryption.MaxBalanceTries)
+	*encryption.MaxBalanceTries = 64
+
+	chunkData := func() []byte {
+		data := make([]byte, swarm.SpanSize+swarm.ChunkSize)
+		binary.LittleEndian.PutUint64(data, swarm.ChunkSize)
+		copy(data[swarm.SpanSize:], "balanced")
+		return data
+	}
+
+	t.Run("room for the share", func(t *testing.T) {
+		buckets := &twoBuckets{0, 0}
+		encrypter := &countingEncrypter{ChunkEncrypter: enc.NewChunkEncrypter()}
+		next := &refsWriter{}
+		w := encryption.NewBalancingEncryptionWriter(encrypter, encryption.NewBalancer(buckets, 10), next)
+
+		for i := 0; i < 10; i++ {
+			if err := w.ChainWrite(&pipeline.PipeWriteArgs{Data: chunkData()}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if encrypter.calls != 10 {
+			t.Fatalf("got %d encryptions, want 10", encrypter.calls)
+		}
+		for _, p := range next.args {
+			hasher := bmtpool.Get()
+			hasher.SetHeader(p.Data[:swarm.SpanSize])
+			_, _ = hasher.Write(p.Data[swarm.SpanSize:])
+			ref, err := hasher.Hash(nil)
+			bmtpool.Put(hasher)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ref, p.Ref) {
+				t.Fatalf("got ref %x, want %x", p.Ref, ref)
+			}
+			if len(p.Key) != enc.KeyLength {
+				t.Fatalf("got key length %d, want %d", len(p.Key), enc.KeyLength)
+			}
+		}
+	})
+
+	t.Run("full bucket avoided", func(t *testing.T) {
+		buckets := &twoBuckets{16, 0}
+		next := &refsWriter{}
+		w := encryption.NewBalancingEncryptionWriter(enc.NewChunkEncrypter(), encryption.NewBalancer(buckets, 0), next)
+
+		for i := 0; i < 10; i++ {
+			if err := w.ChainWrite(&pipeline.PipeWriteArgs{Data: chunkData()}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, p := range next.args {
+			if p.Ref[0]>>7 != 1 {
+				t.Fatalf("chunk %x placed into the full bucket", p.Ref)
+			}
+		}
+	})
+
+	t.Run("no room for the upcoming share", func(t *testing.T) {
+		// the first bucket has room for the chunk but not for its share
+		buckets := &twoBuckets{12, 0}
+		next := &refsWriter{}
+		w := encryption.NewBalancingEncryptionWriter(enc.NewChunkEncrypter(), encryption.NewBalancer(buckets, 20), next)
+
+		if err := w.ChainWrite(&pipeline.PipeWriteArgs{Data: chunkData()}); err != nil {
+			t.Fatal(err)
+		}
+		if p := next.args[0]; p.Ref[0]>>7 != 1 {
+			t.Fatalf("chunk %x placed into the bucket without room", p.Ref)
+		}
+	})
+}
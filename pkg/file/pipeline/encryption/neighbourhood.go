@@ -0,0 +1,134 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption
+
+import (
+	"sync/atomic"
+
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// The root chunk of the content is written when the pipeline is summed, or it
+// is the first chunk of the content if the content fits into a single chunk.
+// The keys of these chunks are mined so that their addresses fall within the
+// neighbourhood of the overlay, which leaves the rest of the chunks, the bulk
+// of the content, encrypted only once. The expected number of the keys tried
+// for a chunk is two to the power of the depth of the neighbourhood.
+
+// maxNeighbourhoodTries is the maximal number of the keys tried for a chunk,
+// the key placing the chunk closest to the overlay is kept if none of them
+// places it within the neighbourhood.
+var maxNeighbourhoodTries = 1 << 16
+
+// Neighbourhood is the neighbourhood the root chunks of a pipeline are placed
+// within, the chunks of the addresses sharing at least depth bits with the
+// overlay.
+type Neighbourhood struct {
+	overlay swarm.Address
+	depth   uint8
+	summing atomic.Bool // the pipeline is being summed
+}
+
+// NewNeighbourhood returns the Neighbourhood of the overlay of the given depth.
+func NewNeighbourhood(overlay swarm.Address, depth uint8) *Neighbourhood {
+	return &Neighbourhood{
+		overlay: overlay,
+		depth:   depth,
+	}
+}
+
+// Pipeline returns the pipeline which marks the chunks written when
+// it is summed to be placed within the neighbourhood.
+func (n *Neighbourhood) Pipeline(p pipeline.Interface) pipeline.Interface {
+	return &neighbourhoodPipeline{Interface: p, n: n}
+}
+
+type neighbourhoodPipeline struct {
+	pipeline.Interface
+	n *Neighbourhood
+}
+
+func (p *neighbourhoodPipeline) Sum() ([]byte, error) {
+	p.n.summing.Store(true)
+	return p.Interface.Sum()
+}
+
+type neighbourhoodWriter struct {
+	next      pipeline.ChainWriter
+	unplaced  pipeline.ChainWriter
+	enc       encryption.ChunkEncrypter
+	n         *Neighbourhood
+	balancer  *Balancer // nil if the buckets are not balanced
+	mineFirst bool      // the first chunk written may be the root
+}
+
+// NewNeighbourhoodEncryptionWriter returns the writer which encrypts the
+// chunks which may be the root with the key placing them within the
+// neighbourhood, and hashes them, so it replaces both the encryption and
+// the bmt writers. The other chunks are written to unplaced, which must
+// encrypt and hash them before they are written to next. If the balancer
+// is not nil, the placed chunks are kept only in the buckets with room.
+func NewNeighbourhoodEncryptionWriter(encrypter encryption.ChunkEncrypter, n *Neighbourhood, balancer *Balancer, mineFirst bool, unplaced, next pipeline.ChainWriter) pipeline.ChainWriter {
+	return &neighbourhoodWriter{
+		next:      next,
+		unplaced:  unplaced,
+		enc:       encrypter,
+		n:         n,
+		balancer:  balancer,
+		mineFirst: mineFirst,
+	}
+}
+
+// ChainWrite assumes that the span is prepended to the actual data before the write !
+func (w *neighbourhoodWriter) ChainWrite(p *pipeline.PipeWriteArgs) error {
+	first := w.mineFirst
+	w.mineFirst = false
+	if !first && !w.n.summing.Load() {
+		return w.unplaced.ChainWrite(p)
+	}
+
+	var (
+		best   *candidate
+		bestPO uint8
+	)
+	for try := 0; try < maxNeighbourhoodTries; try++ {
+		c, err := encrypt(w.enc, p.Data)
+		if err != nil {
+			return err
+		}
+		if w.balancer != nil {
+			u := w.balancer.utilizer.BucketUtilization(swarm.NewAddress(c.ref))
+			if try == 0 {
+				w.balancer.share(u.Buckets)
+			}
+			// the bucket is full
+			if u.UpperBound != 0 && u.Count >= u.UpperBound {
+				continue
+			}
+		}
+		po := swarm.Proximity(w.n.overlay.Bytes(), c.ref)
+		if best == nil || po > bestPO {
+			best, bestPO = c, po
+		}
+		if po >= w.n.depth {
+			break
+		}
+	}
+	if best == nil {
+		// the buckets of all the tried keys are full, the
+		// stamper reports the overissued batch if it is still so
+		return w.unplaced.ChainWrite(p)
+	}
+	p.Data = best.data // replace the verbatim data with the encrypted data
+	p.Key = best.key
+	p.Ref = best.ref
+	return w.next.ChainWrite(p)
+}
+
+func (w *neighbourhoodWriter) Sum() ([]byte, error) {
+	return w.next.Sum()
+}
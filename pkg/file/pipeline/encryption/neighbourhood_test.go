@@ -0,0 +1,89 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	enc "github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/encryption"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// summedPipeline is the pipeline which only reports it is summed.
+type summedPipeline struct {
+	summed bool
+}
+
+func (p *summedPipeline) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (p *summedPipeline) Sum() ([]byte, error) {
+	p.summed = true
+	return nil, nil
+}
+
+func TestNeighbourhoodEncryption(t *testing.T) {
+	t.Parallel()
+
+	const depth = 6
+	var (
+		overlay   = swarm.RandAddress(t)
+		n         = encryption.NewNeighbourhood(overlay, depth)
+		encrypter = &countingEncrypter{ChunkEncrypter: enc.NewChunkEncrypter()}
+		unplaced  = &refsWriter{}
+		next      = &refsWriter{}
+		w         = encryption.NewNeighbourhoodEncryptionWriter(encrypter, n, nil, true, unplaced, next)
+	)
+	chunkData := func() []byte {
+		data := make([]byte, swarm.SpanSize+swarm.ChunkSize)
+		binary.LittleEndian.PutUint64(data, swarm.ChunkSize)
+		copy(data[swarm.SpanSize:], "neighbourhood")
+		return data
+	}
+
+	// the first chunk may be the root
+	if err := w.ChainWrite(&pipeline.PipeWriteArgs{Data: chunkData()}); err != nil {
+		t.Fatal(err)
+	}
+	// the chunk written before the pipeline is summed is not placed
+	if err := w.ChainWrite(&pipeline.PipeWriteArgs{Data: chunkData()}); err != nil {
+		t.Fatal(err)
+	}
+	if len(next.args) != 1 || len(unplaced.args) != 1 {
+		t.Fatalf("got %d placed and %d unplaced chunks, want 1 and 1", len(next.args), len(unplaced.args))
+	}
+	calls := encrypter.calls
+
+	p := &summedPipeline{}
+	if _, err := n.Pipeline(p).Sum(); err != nil {
+		t.Fatal(err)
+	}
+	if !p.summed {
+		t.Fatal("pipeline not summed")
+	}
+	// the chunks written when the pipeline is summed are placed
+	if err := w.ChainWrite(&pipeline.PipeWriteArgs{Data: chunkData()}); err != nil {
+		t.Fatal(err)
+	}
+	if len(next.args) != 2 || len(unplaced.args) != 1 {
+		t.Fatalf("got %d placed and %d unplaced chunks, want 2 and 1", len(next.args), len(unplaced.args))
+	}
+	if encrypter.calls == calls {
+		t.Fatal("placed chunk not encrypted")
+	}
+
+	for _, a := range next.args {
+		if po := swarm.Proximity(overlay.Bytes(), a.Ref); po < depth {
+			t.Fatalf("got proximity %d, want at least %d", po, depth)
+		}
+		if len(a.Key) != enc.KeyLength {
+			t.Fatalf("got key length %d, want %d", len(a.Key), enc.KeyLength)
+		}
+	}
+}
@@ -13,12 +13,26 @@ import (
 )
 
 var (
-	errInconsistentRefs = errors.New("inconsistent references")
-	errTrieFull         = errors.New("trie full")
+	errInconsistentRefs  = errors.New("inconsistent references")
+	errTrieFull          = errors.New("trie full")
+	errInvalidCheckpoint = errors.New("invalid checkpoint")
 )
 
 const maxLevel = 8
 
+// Checkpoint is the state of the trie after a completed branch
+// of the first level, the writing of the content can be resumed
+// from it with the content following the offset.
+type Checkpoint struct {
+	Offset  int64  `json:"offset"`  // length of the content hashed in the trie
+	Cursors []int  `json:"cursors"` // level cursors
+	Buffer  []byte `json:"buffer"`  // data of the levels
+}
+
+// CheckpointFunc is called with the checkpoint of the trie
+// every time a branch of the first level is completed.
+type CheckpointFunc func(Checkpoint) error
+
 type hashTrieWriter struct {
 	branching  int
 	chunkSize  int
@@ -28,9 +42,32 @@ type hashTrieWriter struct {
 	buffer     []byte // keeps all level data
 	full       bool   // indicates whether the trie is full. currently we support (128^7)*4096 = 2305843009213693952 bytes
 	pipelineFn pipeline.PipelineFunc
+	offset     int64 // length of the content written to the first level
+	checkpoint CheckpointFunc
 }
 
 func NewHashTrieWriter(chunkSize, branching, refLen int, pipelineFn pipeline.PipelineFunc) pipeline.ChainWriter {
+	return newHashTrieWriter(chunkSize, branching, refLen, pipelineFn)
+}
+
+// NewCheckpointHashTrieWriter returns the hash trie writer which reports
+// its checkpoints to the function. If from is not nil, the trie continues
+// from the checkpoint, so only the content following its offset is written.
+func NewCheckpointHashTrieWriter(chunkSize, branching, refLen int, pipelineFn pipeline.PipelineFunc, from *Checkpoint, fn CheckpointFunc) (pipeline.ChainWriter, error) {
+	h := newHashTrieWriter(chunkSize, branching, refLen, pipelineFn)
+	h.checkpoint = fn
+	if from != nil {
+		if len(from.Cursors) != len(h.cursors) || len(from.Buffer) != from.Cursors[1] || len(from.Buffer) > len(h.buffer) {
+			return nil, errInvalidCheckpoint
+		}
+		copy(h.cursors, from.Cursors)
+		copy(h.buffer, from.Buffer)
+		h.offset = from.Offset
+	}
+	return h, nil
+}
+
+func newHashTrieWriter(chunkSize, branching, refLen int, pipelineFn pipeline.PipelineFunc) *hashTrieWriter {
 	return &hashTrieWriter{
 		cursors:    make([]int, 9),
 		buffer:     make([]byte, swarm.ChunkWithSpanSize*9*2), // double size as temp workaround for weak calculation of needed buffer space
@@ -53,7 +90,18 @@ func (h *hashTrieWriter) ChainWrite(p *pipeline.PipeWriteArgs) error {
 	if h.full {
 		return errTrieFull
 	}
-	return h.writeToLevel(1, p.Span, p.Ref, p.Key)
+	if err := h.writeToLevel(1, p.Span, p.Ref, p.Key); err != nil {
+		return err
+	}
+	h.offset += int64(binary.LittleEndian.Uint64(p.Span))
+	if h.checkpoint != nil && h.levelSize(1) == 0 {
+		return h.checkpoint(Checkpoint{
+			Offset:  h.offset,
+			Cursors: append([]int(nil), h.cursors...),
+			Buffer:  append([]byte(nil), h.buffer[:h.cursors[1]]...),
+		})
+	}
+	return nil
 }
 
 func (h *hashTrieWriter) writeToLevel(level int, span, ref, key []byte) error {
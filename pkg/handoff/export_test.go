@@ -0,0 +1,16 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handoff
+
+const (
+	ProtocolName    = protocolName
+	ProtocolVersion = protocolVersion
+	StreamName      = streamName
+)
+
+var (
+	MessageTimeout = &messageTimeout
+	PinTimeout     = &pinTimeout
+)
@@ -0,0 +1,302 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package handoff exposes the protocol with which the node hands the chunks
+// of a reference over directly to one peer, bypassing the routing to the
+// closest peers. It is meant for pre-seeding a known mirror or migrating the
+// content between the owned nodes. The receiving peer consents to the offered
+// handoff only if the sender is one of its allowed peers, and it pins the
+// reference once all the chunks are stored, so that they are not evicted.
+package handoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/handoff/pb"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/protobuf"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "handoff"
+
+const (
+	protocolName    = "handoff"
+	protocolVersion = "1.0.0"
+	streamName      = "handoff"
+)
+
+const (
+	// DefaultMaxChunks is the default maximal number
+	// of the chunks accepted in a single handoff.
+	DefaultMaxChunks = 1 << 20
+)
+
+var (
+	messageTimeout = 30 * time.Second
+	pinTimeout     = 10 * time.Minute
+)
+
+var (
+	// ErrRejected is returned if the peer does not consent to the handoff.
+	ErrRejected = errors.New("handoff rejected")
+	// ErrIncomplete is returned if the peer did not store all the chunks.
+	ErrIncomplete = errors.New("handoff incomplete")
+)
+
+// Interface hands the chunks of a reference over to a peer.
+type Interface interface {
+	// Handoff pushes all the chunks of the reference to the peer
+	// and returns the numbers of the sent and the stored chunks.
+	Handoff(ctx context.Context, peer, reference swarm.Address) (Result, error)
+}
+
+// Result is the result of the handoff.
+type Result struct {
+	Chunks uint64 // number of the chunks sent to the peer
+	Stored uint64 // number of the chunks stored by the peer
+}
+
+// Pinner pins the handed over references.
+type Pinner interface {
+	CreatePin(ctx context.Context, ref swarm.Address, traverse bool) error
+}
+
+// Options are the options of the Service.
+type Options struct {
+	AllowedPeers []swarm.Address // peers whose handoffs are accepted
+	MaxChunks    uint64          // maximal number of the chunks of a handoff
+}
+
+type Service struct {
+	streamer   p2p.Streamer
+	storer     storage.Storer
+	traverser  traversal.Traverser
+	pinner     Pinner
+	validStamp postage.ValidStampFn
+	allowed    map[string]struct{}
+	maxChunks  uint64
+	logger     log.Logger
+	metrics    metrics
+}
+
+// New returns the Service sending the chunks of the storer and storing the
+// chunks received from the allowed peers of the options.
+func New(streamer p2p.Streamer, storer storage.Storer, traverser traversal.Traverser, pinner Pinner, validStamp postage.ValidStampFn, logger log.Logger, o Options) *Service {
+	if o.MaxChunks == 0 {
+		o.MaxChunks = DefaultMaxChunks
+	}
+	allowed := make(map[string]struct{}, len(o.AllowedPeers))
+	for _, p := range o.AllowedPeers {
+		allowed[p.ByteString()] = struct{}{}
+	}
+
+	return &Service{
+		streamer:   streamer,
+		storer:     storer,
+		traverser:  traverser,
+		pinner:     pinner,
+		validStamp: validStamp,
+		allowed:    allowed,
+		maxChunks:  o.MaxChunks,
+		logger:     logger.WithName(loggerName).Register(),
+		metrics:    newMetrics(),
+	}
+}
+
+func (s *Service) Protocol() p2p.ProtocolSpec {
+	return p2p.ProtocolSpec{
+		Name:    protocolName,
+		Version: protocolVersion,
+		StreamSpecs: []p2p.StreamSpec{
+			{
+				Name:    streamName,
+				Handler: s.handler,
+			},
+		},
+	}
+}
+
+// Handoff implements the Interface.
+func (s *Service) Handoff(ctx context.Context, peer, reference swarm.Address) (res Result, err error) {
+	var (
+		seen  = make(map[string]struct{})
+		addrs []swarm.Address
+	)
+	err = s.traverser.Traverse(ctx, reference, func(addr swarm.Address) error {
+		if _, ok := seen[addr.ByteString()]; !ok {
+			seen[addr.ByteString()] = struct{}{}
+			addrs = append(addrs, addr)
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("traversal of %s failed: %w", reference, err)
+	}
+
+	stream, err := s.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
+	if err != nil {
+		return Result{}, fmt.Errorf("new stream: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = stream.Reset()
+		} else {
+			go stream.FullClose()
+		}
+	}()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+
+	if err := writeMsg(ctx, w, &pb.Offer{Reference: reference.Bytes(), Chunks: uint64(len(addrs))}); err != nil {
+		return Result{}, fmt.Errorf("write offer: %w", err)
+	}
+	var consent pb.Consent
+	if err := readMsg(ctx, r, &consent); err != nil {
+		return Result{}, fmt.Errorf("read consent: %w", err)
+	}
+	if !consent.Accepted {
+		return Result{}, fmt.Errorf("%w: %s", ErrRejected, consent.Reason)
+	}
+
+	for _, addr := range addrs {
+		ch, err := s.storer.Get(ctx, storage.ModeGetSync, addr)
+		if err != nil {
+			return Result{}, fmt.Errorf("get chunk %s: %w", addr, err)
+		}
+		if ch.Stamp() == nil {
+			return Result{}, fmt.Errorf("chunk %s without stamp", addr)
+		}
+		stamp, err := ch.Stamp().MarshalBinary()
+		if err != nil {
+			return Result{}, fmt.Errorf("marshal stamp of chunk %s: %w", addr, err)
+		}
+		if err := writeMsg(ctx, w, &pb.Delivery{Address: addr.Bytes(), Data: ch.Data(), Stamp: stamp}); err != nil {
+			return Result{}, fmt.Errorf("write delivery: %w", err)
+		}
+		s.metrics.SentChunks.Inc()
+	}
+
+	// the receipt is sent by the peer only after the reference is pinned
+	var receipt pb.Receipt
+	receiptCtx, cancel := context.WithTimeout(ctx, pinTimeout+messageTimeout)
+	err = r.ReadMsgWithContext(receiptCtx, &receipt)
+	cancel()
+	if err != nil {
+		return Result{}, fmt.Errorf("read receipt: %w", err)
+	}
+	res = Result{Chunks: uint64(len(addrs)), Stored: receipt.Stored}
+	if receipt.Err != "" {
+		return res, fmt.Errorf("%w: %s", ErrIncomplete, receipt.Err)
+	}
+	return res, nil
+}
+
+func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (err error) {
+	defer func() {
+		if err != nil {
+			_ = stream.Reset()
+		} else {
+			go stream.FullClose()
+		}
+	}()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+
+	var offer pb.Offer
+	if err := readMsg(ctx, r, &offer); err != nil {
+		return fmt.Errorf("read offer: %w", err)
+	}
+	reference := swarm.NewAddress(offer.Reference)
+
+	consent := pb.Consent{Accepted: true}
+	if _, ok := s.allowed[p.Address.ByteString()]; !ok {
+		consent = pb.Consent{Reason: "peer not allowed"}
+	} else if offer.Chunks > s.maxChunks {
+		consent = pb.Consent{Reason: fmt.Sprintf("too many chunks, at most %d accepted", s.maxChunks)}
+	}
+	if err := writeMsg(ctx, w, &consent); err != nil {
+		return fmt.Errorf("write consent: %w", err)
+	}
+	if !consent.Accepted {
+		s.metrics.RejectedOffers.Inc()
+		s.logger.Debug("handoff rejected", "peer_address", p.Address, "reference", reference, "reason", consent.Reason)
+		return nil
+	}
+
+	// the deliveries after the first failed one are read but not stored,
+	// so that the sender gets the receipt with the cause of the failure
+	var receipt pb.Receipt
+	for i := uint64(0); i < offer.Chunks; i++ {
+		var delivery pb.Delivery
+		if err := readMsg(ctx, r, &delivery); err != nil {
+			return fmt.Errorf("read delivery: %w", err)
+		}
+		if receipt.Err != "" {
+			continue
+		}
+		if err := s.store(ctx, &delivery); err != nil {
+			receipt.Err = err.Error()
+			continue
+		}
+		receipt.Stored++
+		s.metrics.ReceivedChunks.Inc()
+	}
+
+	if receipt.Err == "" {
+		pinCtx, cancel := context.WithTimeout(ctx, pinTimeout)
+		err := s.pinner.CreatePin(pinCtx, reference, true)
+		cancel()
+		if err != nil {
+			receipt.Err = fmt.Sprintf("pin reference: %v", err)
+		}
+	}
+	if receipt.Err != "" {
+		s.logger.Debug("handoff incomplete", "peer_address", p.Address, "reference", reference, "stored", receipt.Stored, "error", receipt.Err)
+	}
+
+	if err := writeMsg(ctx, w, &receipt); err != nil {
+		return fmt.Errorf("write receipt: %w", err)
+	}
+	return nil
+}
+
+// store validates the delivered chunk with its stamp and stores it.
+func (s *Service) store(ctx context.Context, d *pb.Delivery) error {
+	addr := swarm.NewAddress(d.Address)
+	ch := swarm.NewChunk(addr, d.Data)
+	if !cac.Valid(ch) && !soc.Valid(ch) {
+		return fmt.Errorf("chunk %s: %w", addr, swarm.ErrInvalidChunk)
+	}
+	ch, err := s.validStamp(ch, d.Stamp)
+	if err != nil {
+		return fmt.Errorf("chunk %s: %w", addr, err)
+	}
+	if _, err := s.storer.Put(ctx, storage.ModePutSync, ch); err != nil {
+		return fmt.Errorf("store chunk %s: %w", addr, err)
+	}
+	return nil
+}
+
+func writeMsg(ctx context.Context, w protobuf.Writer, msg protobuf.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, messageTimeout)
+	defer cancel()
+	return w.WriteMsgWithContext(ctx, msg)
+}
+
+func readMsg(ctx context.Context, r protobuf.Reader, msg protobuf.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, messageTimeout)
+	defer cancel()
+	return r.ReadMsgWithContext(ctx, msg)
+}
@@ -0,0 +1,226 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handoff_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/handoff"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/postage"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+// stampingStorer stamps the chunks before storing them.
+type stampingStorer struct {
+	storage.Storer
+}
+
+func (s stampingStorer) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	for _, ch := range chs {
+		ch.WithStamp(postagetesting.MustNewStamp())
+	}
+	return s.Storer.Put(ctx, mode, chs...)
+}
+
+// pinner records the pinned references.
+type pinner struct {
+	mu   sync.Mutex
+	refs []swarm.Address
+}
+
+func (p *pinner) CreatePin(_ context.Context, ref swarm.Address, _ bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refs = append(p.refs, ref)
+	return nil
+}
+
+// slowPinner pins the references after the delay.
+type slowPinner struct {
+	pinner
+	delay time.Duration
+}
+
+func (p *slowPinner) CreatePin(ctx context.Context, ref swarm.Address, traverse bool) error {
+	select {
+	case <-time.After(p.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return p.pinner.CreatePin(ctx, ref, traverse)
+}
+
+func validStamp(ch swarm.Chunk, b []byte) (swarm.Chunk, error) {
+	stamp := new(postage.Stamp)
+	if err := stamp.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return ch.WithStamp(stamp), nil
+}
+
+func invalidStamp(swarm.Chunk, []byte) (swarm.Chunk, error) {
+	return nil, postage.ErrNotFound
+}
+
+func TestHandoff(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	senderStore := mock.NewStorer()
+	pipe := builder.NewPipelineBuilder(ctx, stampingStorer{senderStore}, storage.ModePutUpload, false)
+	ref, err := builder.FeedPipeline(ctx, pipe, bytes.NewReader(testutil.RandBytes(t, 10*swarm.ChunkSize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		senderAddr   = swarm.RandAddress(t)
+		receiverAddr = swarm.RandAddress(t)
+	)
+
+	for _, tc := range []struct {
+		name       string
+		options    handoff.Options
+		validStamp postage.ValidStampFn
+		stored     uint64
+		pinned     bool
+		err        error
+	}{
+		{
+			name:       "accepted",
+			options:    handoff.Options{AllowedPeers: []swarm.Address{senderAddr}},
+			validStamp: validStamp,
+			stored:     11,
+			pinned:     true,
+		},
+		{
+			name:       "peer not allowed",
+			options:    handoff.Options{AllowedPeers: []swarm.Address{swarm.RandAddress(t)}},
+			validStamp: validStamp,
+			err:        handoff.ErrRejected,
+		},
+		{
+			name:       "too many chunks",
+			options:    handoff.Options{AllowedPeers: []swarm.Address{senderAddr}, MaxChunks: 10},
+			validStamp: validStamp,
+			err:        handoff.ErrRejected,
+		},
+		{
+			name:       "invalid stamps",
+			options:    handoff.Options{AllowedPeers: []swarm.Address{senderAddr}},
+			validStamp: invalidStamp,
+			err:        handoff.ErrIncomplete,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			receiverStore := mock.NewStorer()
+			receiverPinner := new(pinner)
+			receiver := handoff.New(nil, receiverStore, traversal.New(receiverStore), receiverPinner, tc.validStamp, log.Noop, tc.options)
+
+			recorder := streamtest.New(
+				streamtest.WithProtocols(receiver.Protocol()),
+				streamtest.WithBaseAddr(senderAddr),
+			)
+			sender := handoff.New(recorder, senderStore, traversal.New(senderStore), new(pinner), validStamp, log.Noop, handoff.Options{})
+
+			res, err := sender.Handoff(ctx, receiverAddr, ref)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			if res.Stored != tc.stored {
+				t.Fatalf("got %d stored chunks, want %d", res.Stored, tc.stored)
+			}
+			if tc.err == nil && res.Chunks != tc.stored {
+				t.Fatalf("got %d sent chunks, want %d", res.Chunks, tc.stored)
+			}
+
+			records, err := recorder.Records(receiverAddr, handoff.ProtocolName, handoff.ProtocolVersion, handoff.StreamName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := records[0].Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			if !tc.pinned {
+				if len(receiverPinner.refs) != 0 {
+					t.Fatalf("got pinned references %v, want none", receiverPinner.refs)
+				}
+				return
+			}
+			if len(receiverPinner.refs) != 1 || !receiverPinner.refs[0].Equal(ref) {
+				t.Fatalf("got pinned references %v, want %s", receiverPinner.refs, ref)
+			}
+			if err := traversal.New(receiverStore).Traverse(ctx, ref, func(addr swarm.Address) error {
+				_, err := receiverStore.Get(ctx, storage.ModeGetRequest, addr)
+				return err
+			}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// nolint:paralleltest
+func TestHandoffSlowPin(t *testing.T) {
+	messageTimeout, pinTimeout := *handoff.MessageTimeout, *handoff.PinTimeout
+	*handoff.MessageTimeout, *handoff.PinTimeout = 50*time.Millisecond, 5*time.Second
+	t.Cleanup(func() {
+		*handoff.MessageTimeout, *handoff.PinTimeout = messageTimeout, pinTimeout
+	})
+
+	ctx := context.Background()
+
+	senderStore := mock.NewStorer()
+	pipe := builder.NewPipelineBuilder(ctx, stampingStorer{senderStore}, storage.ModePutUpload, false)
+	ref, err := builder.FeedPipeline(ctx, pipe, bytes.NewReader(testutil.RandBytes(t, 10*swarm.ChunkSize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		senderAddr   = swarm.RandAddress(t)
+		receiverAddr = swarm.RandAddress(t)
+	)
+
+	// the pinning takes longer than the timeout of a single message
+	receiverStore := mock.NewStorer()
+	receiverPinner := &slowPinner{delay: 200 * time.Millisecond}
+	receiver := handoff.New(nil, receiverStore, traversal.New(receiverStore), receiverPinner, validStamp, log.Noop, handoff.Options{AllowedPeers: []swarm.Address{senderAddr}})
+
+	recorder := streamtest.New(
+		streamtest.WithProtocols(receiver.Protocol()),
+		streamtest.WithBaseAddr(senderAddr),
+	)
+	sender := handoff.New(recorder, senderStore, traversal.New(senderStore), new(pinner), validStamp, log.Noop, handoff.Options{})
+
+	res, err := sender.Handoff(ctx, receiverAddr, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Stored != 11 {
+		t.Fatalf("got %d stored chunks, want %d", res.Stored, 11)
+	}
+	if len(receiverPinner.refs) != 1 || !receiverPinner.refs[0].Equal(ref) {
+		t.Fatalf("got pinned references %v, want %s", receiverPinner.refs, ref)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package handoff
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	SentChunks     prometheus.Counter
+	ReceivedChunks prometheus.Counter
+	RejectedOffers prometheus.Counter
+}
+
+func newMetrics() metrics {
+	subsystem := "handoff"
+
+	return metrics{
+		SentChunks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "sent_chunks",
+			Help:      "Number of the chunks handed over to the peers.",
+		}),
+		ReceivedChunks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "received_chunks",
+			Help:      "Number of the chunks handed over by the peers and stored.",
+		}),
+		RejectedOffers: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "rejected_offers",
+			Help:      "Number of the handoffs offered by the peers which were not consented to.",
+		}),
+	}
+}
+
+func (s *Service) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
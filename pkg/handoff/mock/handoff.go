@@ -0,0 +1,20 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mock
+
+import (
+	"context"
+
+	"github.com/ethersphere/bee/pkg/handoff"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Handoff is the handoff.Interface mock calling the function.
+type Handoff func(ctx context.Context, peer, reference swarm.Address) (handoff.Result, error)
+
+// Handoff implements handoff.Interface Handoff method.
+func (f Handoff) Handoff(ctx context.Context, peer, reference swarm.Address) (handoff.Result, error) {
+	return f(ctx, peer, reference)
+}
@@ -11,6 +11,7 @@
 package hive
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"errors"
@@ -308,20 +309,34 @@ func (s *Service) checkAndAddPeers(ctx context.Context, peers pb.Peers) {
 	for _, p := range peers.Peers {
 
 		overlay := swarm.NewAddress(p.Overlay)
-		cacheOverlay := overlay.ByteString()[:cachePrefix]
+		// the underlay is a part of the key, so that the changed
+		// underlays of the known peers are not skipped
+		cacheOverlay := overlay.ByteString()[:cachePrefix] + string(p.Underlay)
 
 		// cached peer, skip
 		if _, ok := s.lru.Get(cacheOverlay); ok {
 			continue
 		}
 
-		// if peer exists already in the addressBook, skip
-		if _, err := s.addressBook.Get(overlay); err == nil {
+		// if peer exists already in the addressBook with the same underlay, skip
+		known, err := s.addressBook.Get(overlay)
+		if err == nil && bytes.Equal(known.Underlay.Bytes(), p.Underlay) {
 			_ = s.lru.Add(cacheOverlay, nil)
 			continue
 		}
 
-		err := s.sem.Acquire(ctx, 1)
+		// the changed underlay of a known peer replaces the stored one
+		// only if it is signed by the peer
+		if err == nil {
+			if _, err := bzz.ParseAddress(p.Underlay, p.Overlay, p.Signature, p.Transaction, true, s.networkID); err != nil {
+				s.metrics.PeerUnderlayErr.Inc()
+				s.logger.Debug("invalid changed underlay", "peer_address", overlay, "error", err)
+				_ = s.lru.Add(cacheOverlay, nil)
+				continue
+			}
+		}
+
+		err = s.sem.Acquire(ctx, 1)
 		if err != nil {
 			return
 		}
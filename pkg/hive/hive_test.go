@@ -297,6 +297,99 @@ func TestBroadcastPeers_FLAKY(t *testing.T) {
 	}
 }
 
+func TestBroadcastChangedUnderlay(t *testing.T) {
+	t.Parallel()
+
+	logger := log.Noop
+	networkID := uint64(1)
+
+	newAddress := func(t *testing.T, signer crypto.Signer, overlay swarm.Address, underlay string) *bzz.Address {
+		t.Helper()
+
+		u, err := ma.NewMultiaddr(underlay)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bzzAddr, err := bzz.NewAddress(signer, u, overlay, networkID, tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bzzAddr
+	}
+	newPeer := func(t *testing.T) (crypto.Signer, swarm.Address) {
+		t.Helper()
+
+		pk, err := crypto.GenerateSecp256k1Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		overlay, err := crypto.NewOverlayAddress(pk.PublicKey, networkID, tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return crypto.NewDefaultSigner(pk), overlay
+	}
+
+	forger, _ := newPeer(t)
+	signer1, overlay1 := newPeer(t)
+	signer2, overlay2 := newPeer(t)
+	old1 := newAddress(t, signer1, overlay1, "/ip4/127.0.0.1/udp/1")
+	old2 := newAddress(t, signer2, overlay2, "/ip4/127.0.0.1/udp/2")
+
+	// the server knows both peers with their old underlays
+	addressbookServer := ab.New(mock.NewStateStore())
+	for _, a := range []*bzz.Address{old1, old2} {
+		if err := addressbookServer.Put(a.Overlay, *a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the client gossips a changed underlay signed by the peer and
+	// a changed underlay signed by somebody else
+	forged := newAddress(t, forger, overlay1, "/ip4/127.0.0.1/udp/11")
+	changed := newAddress(t, signer2, overlay2, "/ip4/127.0.0.1/udp/12")
+	addressbookClient := ab.New(mock.NewStateStore())
+	for _, a := range []*bzz.Address{forged, changed} {
+		if err := addressbookClient.Put(a.Overlay, *a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server, _ := hive.New(streamtest.New(), addressbookServer, networkID, false, true, logger)
+	testutil.CleanupCloser(t, server)
+
+	recorder := streamtest.New(
+		streamtest.WithProtocols(server.Protocol()),
+	)
+
+	client, _ := hive.New(recorder, addressbookClient, networkID, false, true, logger)
+	testutil.CleanupCloser(t, client)
+
+	addressee := swarm.RandAddress(t)
+	if err := client.BroadcastPeers(context.Background(), addressee, overlay1, overlay2); err != nil {
+		t.Fatal(err)
+	}
+
+	err := spinlock.Wait(spinTimeout, func() bool {
+		got, err := addressbookServer.Get(overlay2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got.Underlay.Equal(changed.Underlay)
+	})
+	if err != nil {
+		t.Fatal("timed out waiting for the changed underlay")
+	}
+
+	got, err := addressbookServer.Get(overlay1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Underlay.Equal(old1.Underlay) {
+		t.Fatalf("got underlay %s, want %s", got.Underlay, old1.Underlay)
+	}
+}
+
 func expectOverlaysEventually(t *testing.T, exporter ab.Interface, wantOverlays []swarm.Address) {
 	t.Helper()
 
@@ -0,0 +1,93 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ipfs fetches the content from an IPFS gateway, so that the
+// existing IPFS content can be imported to Swarm.
+package ipfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ipfs/go-cid"
+)
+
+// responseHeaderTimeout is the time the gateway has to start responding,
+// the transfer of the content itself is bounded only by the context.
+const responseHeaderTimeout = 5 * time.Minute
+
+var (
+	// ErrNotFound is returned if the gateway does not find the content.
+	ErrNotFound = errors.New("ipfs content not found")
+)
+
+// Fetcher fetches the content of the IPFS DAGs.
+type Fetcher interface {
+	// FetchTar returns the UnixFS DAG of the CID as a tar archive. The files
+	// of a directory are under the directory named by the CID, a single file
+	// is named by the CID.
+	FetchTar(ctx context.Context, c cid.Cid) (io.ReadCloser, error)
+}
+
+var _ Fetcher = (*Gateway)(nil)
+
+// Gateway fetches the content from the HTTP gateway of an IPFS node,
+// for example the gateway of a local Kubo node.
+type Gateway struct {
+	client *http.Client
+	url    string
+}
+
+// NewGateway returns the Gateway which fetches the content
+// from the IPFS HTTP gateway at the url.
+func NewGateway(gatewayURL string) (*Gateway, error) {
+	u, err := url.Parse(gatewayURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse ipfs gateway url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported ipfs gateway url scheme: %q", u.Scheme)
+	}
+	return &Gateway{
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				ResponseHeaderTimeout: responseHeaderTimeout,
+			},
+		},
+		url: strings.TrimSuffix(gatewayURL, "/"),
+	}, nil
+}
+
+// FetchTar requests the tar archive of the DAG with the format
+// query parameter of the trustless gateway specification.
+func (g *Gateway) FetchTar(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/ipfs/%s?format=tar", g.url, c), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/x-tar")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ipfs gateway: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("ipfs gateway: %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipfs_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/ipfs"
+	"github.com/ipfs/go-cid"
+)
+
+func TestGateway(t *testing.T) {
+	t.Parallel()
+
+	found := cid.MustParse("bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi")
+	failing := cid.MustParse("bafkreifjjcie6lypi6ny7amxnfftagclbuxndqonfipmb64f2km2devei4")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") != "tar" || r.Header.Get("Accept") != "application/x-tar" {
+			http.Error(w, "unsupported format", http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/ipfs/" + found.String():
+			_, _ = w.Write([]byte("tar"))
+		case "/ipfs/" + failing.String():
+			http.Error(w, "no providers", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	g, err := ipfs.NewGateway(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := g.FetchTar(context.Background(), found)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "tar" {
+		t.Fatalf("got %q, want %q", data, "tar")
+	}
+
+	if _, err := g.FetchTar(context.Background(), failing); err == nil || errors.Is(err, ipfs.ErrNotFound) {
+		t.Fatalf("got error %v, want the gateway error", err)
+	}
+
+	missing := cid.NewCidV1(cid.Raw, found.Hash())
+	if _, err := g.FetchTar(context.Background(), missing); !errors.Is(err, ipfs.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, ipfs.ErrNotFound)
+	}
+
+	if _, err := ipfs.NewGateway("ftp://localhost"); err == nil {
+		t.Fatal("expected the unsupported scheme error")
+	}
+}
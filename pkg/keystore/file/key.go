@@ -65,7 +65,10 @@ type kdfParams struct {
 	Salt  string `json:"salt"`
 }
 
-func encryptKey(k *ecdsa.PrivateKey, password string, edg keystore.EDG) ([]byte, error) {
+// EncryptKey encrypts the private key with the password into the JSON v3
+// key file format, so that the other keystores can store the keys in the
+// same format as the key files.
+func EncryptKey(k *ecdsa.PrivateKey, password string, edg keystore.EDG) ([]byte, error) {
 	data, err := edg.Encode(k)
 	if err != nil {
 		return nil, err
@@ -95,7 +98,9 @@ func encryptKey(k *ecdsa.PrivateKey, password string, edg keystore.EDG) ([]byte,
 	})
 }
 
-func decryptKey(data []byte, password string, edg keystore.EDG) (*ecdsa.PrivateKey, error) {
+// DecryptKey decrypts the private key encrypted by the EncryptKey. It
+// returns the keystore.ErrInvalidPassword if the password is not valid.
+func DecryptKey(data []byte, password string, edg keystore.EDG) (*ecdsa.PrivateKey, error) {
 	var k encryptedKey
 	if err := json.Unmarshal(data, &k); err != nil {
 		return nil, err
@@ -46,7 +46,7 @@ func (s *Service) SetKey(name, password string, edg keystore.EDG) (*ecdsa.Privat
 		return nil, fmt.Errorf("generate key: %w", err)
 	}
 
-	d, err := encryptKey(pk, password, edg)
+	d, err := EncryptKey(pk, password, edg)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +76,7 @@ func (s *Service) Key(name, password string, edg keystore.EDG) (pk *ecdsa.Privat
 		return pk, true, err
 	}
 
-	pk, err = decryptKey(data, password, edg)
+	pk, err = DecryptKey(data, password, edg)
 	if err != nil {
 		return nil, false, err
 	}
@@ -0,0 +1,37 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package keyring
+
+import "sync"
+
+// NewWithMemorySecrets returns the Service which stores
+// the secrets in memory instead of the keyring.
+func NewWithMemorySecrets(service string) *Service {
+	return &Service{service: service, secrets: &memorySecretStore{secrets: make(map[string][]byte)}}
+}
+
+type memorySecretStore struct {
+	mu      sync.Mutex
+	secrets map[string][]byte
+}
+
+func (m *memorySecretStore) Get(service, account string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.secrets[service+"/"+account]
+	if !ok {
+		return nil, errNotFound
+	}
+	return s, nil
+}
+
+func (m *memorySecretStore) Set(service, account string, secret []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.secrets[service+"/"+account] = secret
+	return nil
+}
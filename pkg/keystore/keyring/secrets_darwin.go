@@ -0,0 +1,59 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin
+
+package keyring
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+const (
+	securityCommand = "/usr/bin/security"
+	// securityNotFoundExitCode is the exit code of the
+	// security command if the item is not in the keychain.
+	securityNotFoundExitCode = 44
+)
+
+// keychainSecretStore stores the secrets in the login keychain with the
+// security command. The secrets are passed to the command on the standard
+// input, so that they are not visible in the arguments of the process.
+type keychainSecretStore struct{}
+
+func newSecretStore() secretStore {
+	return keychainSecretStore{}
+}
+
+func (keychainSecretStore) Get(service, account string) ([]byte, error) {
+	out, err := exec.Command(securityCommand, "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == securityNotFoundExitCode {
+			return nil, errNotFound
+		}
+		return nil, fmt.Errorf("find generic password: %w", err)
+	}
+	// the secrets are stored hex encoded
+	secret, err := hex.DecodeString(string(bytes.TrimSpace(out)))
+	if err != nil {
+		return nil, fmt.Errorf("decode secret: %w", err)
+	}
+	return secret, nil
+}
+
+func (keychainSecretStore) Set(service, account string, secret []byte) error {
+	cmd := exec.Command(securityCommand, "-i")
+	cmd.Stdin = bytes.NewBufferString(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		strconv.Quote(service), strconv.Quote(account), hex.EncodeToString(secret)))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("add generic password: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	secretServiceName     = "org.freedesktop.secrets"
+	secretServicePath     = "/org/freedesktop/secrets"
+	secretServiceIface    = "org.freedesktop.Secret.Service"
+	secretCollectionIface = "org.freedesktop.Secret.Collection"
+	secretItemIface       = "org.freedesktop.Secret.Item"
+	secretSessionIface    = "org.freedesktop.Secret.Session"
+	secretPromptIface     = "org.freedesktop.Secret.Prompt"
+
+	// noPrompt is the path returned when no prompt is needed.
+	noPrompt = dbus.ObjectPath("/")
+	// promptTimeout is the time the user has to answer the prompt to unlock the keyring.
+	promptTimeout = 2 * time.Minute
+)
+
+var errPromptDismissed = errors.New("keyring unlock prompt dismissed")
+
+// secret is the secret as defined by the Secret Service API.
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// secretServiceStore stores the secrets in the default collection
+// of the Secret Service, like the GNOME Keyring or KWallet.
+type secretServiceStore struct{}
+
+func newSecretStore() secretStore {
+	return secretServiceStore{}
+}
+
+func (secretServiceStore) Get(service, account string) ([]byte, error) {
+	s, err := openSecretSession()
+	if err != nil {
+		return nil, err
+	}
+	defer s.close()
+
+	var unlocked, locked []dbus.ObjectPath
+	if err := s.service.Call(secretServiceIface+".SearchItems", 0, attributes(service, account)).Store(&unlocked, &locked); err != nil {
+		return nil, fmt.Errorf("search items: %w", err)
+	}
+	var item dbus.ObjectPath
+	switch {
+	case len(unlocked) > 0:
+		item = unlocked[0]
+	case len(locked) > 0:
+		item = locked[0]
+		if err := s.unlock(item); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errNotFound
+	}
+
+	var sec secret
+	if err := s.conn.Object(secretServiceName, item).Call(secretItemIface+".GetSecret", 0, s.path).Store(&sec); err != nil {
+		return nil, fmt.Errorf("get secret: %w", err)
+	}
+	return sec.Value, nil
+}
+
+func (secretServiceStore) Set(service, account string, value []byte) error {
+	s, err := openSecretSession()
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	var collection dbus.ObjectPath
+	if err := s.service.Call(secretServiceIface+".ReadAlias", 0, "default").Store(&collection); err != nil {
+		return fmt.Errorf("read default collection: %w", err)
+	}
+	if collection == noPrompt {
+		return errors.New("keyring has no default collection")
+	}
+	if err := s.unlock(collection); err != nil {
+		return err
+	}
+
+	properties := map[string]dbus.Variant{
+		secretItemIface + ".Label":      dbus.MakeVariant(fmt.Sprintf("%s %s key", service, account)),
+		secretItemIface + ".Attributes": dbus.MakeVariant(attributes(service, account)),
+	}
+	sec := secret{
+		Session:     s.path,
+		Value:       value,
+		ContentType: "application/json",
+	}
+	var item, prompt dbus.ObjectPath
+	if err := s.conn.Object(secretServiceName, collection).Call(secretCollectionIface+".CreateItem", 0, properties, sec, true).Store(&item, &prompt); err != nil {
+		return fmt.Errorf("create item: %w", err)
+	}
+	return s.prompt(prompt)
+}
+
+func attributes(service, account string) map[string]string {
+	return map[string]string{
+		"service":  service,
+		"username": account,
+	}
+}
+
+// secretSession is the session with the Secret Service
+// in which the secrets are transferred in plain.
+type secretSession struct {
+	conn    *dbus.Conn
+	service dbus.BusObject
+	path    dbus.ObjectPath
+}
+
+func openSecretSession() (*secretSession, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("connect session bus: %w", err)
+	}
+	s := &secretSession{
+		conn:    conn,
+		service: conn.Object(secretServiceName, secretServicePath),
+	}
+	var output dbus.Variant
+	if err := s.service.Call(secretServiceIface+".OpenSession", 0, "plain", dbus.MakeVariant("")).Store(&output, &s.path); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("open secret service session: %w", err)
+	}
+	return s, nil
+}
+
+func (s *secretSession) close() {
+	_ = s.conn.Object(secretServiceName, s.path).Call(secretSessionIface+".Close", 0).Err
+	s.conn.Close()
+}
+
+// unlock unlocks the item or the collection, prompting the user if needed.
+func (s *secretSession) unlock(path dbus.ObjectPath) error {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	if err := s.service.Call(secretServiceIface+".Unlock", 0, []dbus.ObjectPath{path}).Store(&unlocked, &prompt); err != nil {
+		return fmt.Errorf("unlock: %w", err)
+	}
+	return s.prompt(prompt)
+}
+
+// prompt shows the prompt and waits until the user completes it.
+func (s *secretSession) prompt(prompt dbus.ObjectPath) error {
+	if prompt == noPrompt {
+		return nil
+	}
+
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(prompt),
+		dbus.WithMatchInterface(secretPromptIface),
+		dbus.WithMatchMember("Completed"),
+	}
+	if err := s.conn.AddMatchSignal(match...); err != nil {
+		return fmt.Errorf("add prompt signal match: %w", err)
+	}
+	defer func() { _ = s.conn.RemoveMatchSignal(match...) }()
+
+	signals := make(chan *dbus.Signal, 1)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+
+	if err := s.conn.Object(secretServiceName, prompt).Call(secretPromptIface+".Prompt", 0, "").Err; err != nil {
+		return fmt.Errorf("prompt: %w", err)
+	}
+
+	timeout := time.After(promptTimeout)
+	for {
+		select {
+		case sig := <-signals:
+			if sig.Path != prompt || sig.Name != secretPromptIface+".Completed" {
+				continue
+			}
+			if len(sig.Body) > 0 {
+				if dismissed, _ := sig.Body[0].(bool); dismissed {
+					return errPromptDismissed
+				}
+			}
+			return nil
+		case <-timeout:
+			return errors.New("keyring unlock prompt timed out")
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux && !darwin
+
+package keyring
+
+type unsupportedSecretStore struct{}
+
+func newSecretStore() secretStore {
+	return unsupportedSecretStore{}
+}
+
+func (unsupportedSecretStore) Get(_, _ string) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+func (unsupportedSecretStore) Set(_, _ string, _ []byte) error {
+	return ErrUnsupported
+}
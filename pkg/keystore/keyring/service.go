@@ -0,0 +1,99 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package keyring implements the keystore.Service
+// which stores the keys in the keyring of the operating system.
+package keyring
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/keystore"
+	"github.com/ethersphere/bee/pkg/keystore/file"
+)
+
+var _ keystore.Service = (*Service)(nil)
+
+// DefaultServiceName is the name of the service the keys are stored under in the keyring.
+const DefaultServiceName = "bee"
+
+var (
+	// ErrUnsupported is returned when the keyring of the
+	// operating system is not supported.
+	ErrUnsupported = errors.New("keyring is not supported on this operating system")
+
+	errNotFound = errors.New("secret not found")
+)
+
+// secretStore stores the secrets in the keyring of the operating system.
+type secretStore interface {
+	// Get returns the secret of the account, or the errNotFound.
+	Get(service, account string) ([]byte, error)
+	// Set creates or replaces the secret of the account.
+	Set(service, account string, secret []byte) error
+}
+
+// Service is the keyring-based keystore.Service implementation.
+//
+// Every private key is stored as a secret of the account with the name of
+// the key under the service name, encrypted with the password in the same
+// format as the key files of the file-based keystore.
+type Service struct {
+	service string
+	secrets secretStore
+}
+
+// New creates new keyring-based keystore.Service implementation
+// which stores the keys under the service name.
+func New(service string) *Service {
+	return &Service{service: service, secrets: newSecretStore()}
+}
+
+func (s *Service) Exists(name string) (bool, error) {
+	_, err := s.secrets.Get(s.service, name)
+	switch {
+	case errors.Is(err, errNotFound):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("read private key: %w", err)
+	}
+	return true, nil
+}
+
+func (s *Service) SetKey(name, password string, edg keystore.EDG) (*ecdsa.PrivateKey, error) {
+	pk, err := edg.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	d, err := file.EncryptKey(pk, password, edg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.secrets.Set(s.service, name, d); err != nil {
+		return nil, fmt.Errorf("write private key: %w", err)
+	}
+
+	return pk, nil
+}
+
+func (s *Service) Key(name, password string, edg keystore.EDG) (pk *ecdsa.PrivateKey, created bool, err error) {
+	data, err := s.secrets.Get(s.service, name)
+	switch {
+	case errors.Is(err, errNotFound):
+		pk, err := s.SetKey(name, password, edg)
+		return pk, true, err
+	case err != nil:
+		return nil, false, fmt.Errorf("read private key: %w", err)
+	}
+
+	pk, err = file.DecryptKey(data, password, edg)
+	if err != nil {
+		return nil, false, err
+	}
+	return pk, false, nil
+}
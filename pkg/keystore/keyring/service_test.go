@@ -0,0 +1,18 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package keyring_test
+
+import (
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/keystore/keyring"
+	"github.com/ethersphere/bee/pkg/keystore/test"
+)
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	test.Service(t, keyring.NewWithMemorySecrets(keyring.DefaultServiceName))
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kms implements the keystore.Service which protects the
+// keys with the envelope encryption by the key management service.
+package kms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ethersphere/bee/pkg/keystore"
+	"github.com/ethersphere/bee/pkg/keystore/file"
+)
+
+var _ keystore.Service = (*Service)(nil)
+
+const (
+	envelopeVersion = 1
+	dataKeySize     = 32 // AES-256
+)
+
+// KeyWrapper wraps and unwraps the data keys with the
+// master key which never leaves the key management service.
+type KeyWrapper interface {
+	Wrap(dataKey []byte) ([]byte, error)
+	Unwrap(wrapped []byte) ([]byte, error)
+}
+
+// envelope is the private key encrypted with the data key,
+// which is stored wrapped by the key management service.
+type envelope struct {
+	Version    int    `json:"version"`
+	DataKey    string `json:"dataKey"`
+	Nonce      string `json:"nonce"`
+	CipherText string `json:"ciphertext"`
+}
+
+// Service is the envelope encryption keystore.Service implementation.
+//
+// Keys are stored in directory where each private key is stored in a file.
+// The private key is encrypted with the password in the same format as the
+// key files of the file-based keystore and the result is encrypted with a
+// new data key, which is stored in the file wrapped by the key management
+// service. The key cannot be decrypted without the access to the key
+// management service, even if the file and the password are disclosed.
+type Service struct {
+	dir     string
+	wrapper KeyWrapper
+}
+
+// New creates new envelope encryption keystore.Service implementation.
+func New(dir string, wrapper KeyWrapper) *Service {
+	return &Service{dir: dir, wrapper: wrapper}
+}
+
+func (s *Service) Exists(name string) (bool, error) {
+	data, err := os.ReadFile(s.keyFilename(name))
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("read private key: %w", err)
+	}
+	return len(data) > 0, nil
+}
+
+func (s *Service) SetKey(name, password string, edg keystore.EDG) (*ecdsa.PrivateKey, error) {
+	pk, err := edg.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	d, err := file.EncryptKey(pk, password, edg)
+	if err != nil {
+		return nil, err
+	}
+	d, err = s.seal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := s.keyFilename(name)
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filename, d, 0600); err != nil {
+		return nil, err
+	}
+
+	return pk, nil
+}
+
+func (s *Service) Key(name, password string, edg keystore.EDG) (pk *ecdsa.PrivateKey, created bool, err error) {
+	data, err := os.ReadFile(s.keyFilename(name))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, false, fmt.Errorf("read private key: %w", err)
+	}
+	if len(data) == 0 {
+		pk, err := s.SetKey(name, password, edg)
+		return pk, true, err
+	}
+
+	data, err = s.open(data)
+	if err != nil {
+		return nil, false, err
+	}
+	pk, err = file.DecryptKey(data, password, edg)
+	if err != nil {
+		return nil, false, err
+	}
+	return pk, false, nil
+}
+
+// seal encrypts the data with a new data key into the envelope.
+func (s *Service) seal(data []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	wrapped, err := s.wrapper.Wrap(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key: %w", err)
+	}
+	return json.Marshal(envelope{
+		Version:    envelopeVersion,
+		DataKey:    base64.StdEncoding.EncodeToString(wrapped),
+		Nonce:      hex.EncodeToString(nonce),
+		CipherText: hex.EncodeToString(gcm.Seal(nil, nonce, data, nil)),
+	})
+}
+
+// open decrypts the data from the envelope.
+func (s *Service) open(data []byte) ([]byte, error) {
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	if e.Version != envelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version: %v", e.Version)
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(e.DataKey)
+	if err != nil {
+		return nil, fmt.Errorf("base64 decode data key: %w", err)
+	}
+	nonce, err := hex.DecodeString(e.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("hex decode nonce: %w", err)
+	}
+	cipherText, err := hex.DecodeString(e.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("hex decode cipher text: %w", err)
+	}
+	dataKey, err := s.wrapper.Unwrap(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size: %d", len(nonce))
+	}
+	data, err = gcm.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return data, nil
+}
+
+func (s *Service) keyFilename(name string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.kms.json", name))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
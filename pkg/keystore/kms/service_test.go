@@ -0,0 +1,107 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kms_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/keystore/kms"
+	"github.com/ethersphere/bee/pkg/keystore/test"
+)
+
+const (
+	vaultToken = "s.token"
+	vaultKey   = "bee"
+)
+
+// newVault returns the test server which implements the encrypt and decrypt
+// endpoints of the transit secrets engine by reversing the plaintext.
+func newVault(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	reverse := func(s string) string {
+		b := []byte(s)
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+		return string(b)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != vaultToken {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var data map[string]string
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/" + vaultKey:
+			data = map[string]string{"ciphertext": "vault:v1:" + reverse(req["plaintext"])}
+		case "/v1/transit/decrypt/" + vaultKey:
+			data = map[string]string{"plaintext": reverse(strings.TrimPrefix(req["ciphertext"], "vault:v1:"))}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	vault, err := kms.NewVaultTransit(newVault(t).URL, vaultKey, vaultToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.Service(t, kms.New(t.TempDir(), vault))
+}
+
+func TestServiceNoAccess(t *testing.T) {
+	t.Parallel()
+
+	srv := newVault(t)
+	dir := t.TempDir()
+
+	vault, err := kms.NewVaultTransit(srv.URL, vaultKey, vaultToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := kms.New(dir, vault).Key("swarm", "pass123456", crypto.EDGSecp256_K1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the key cannot be decrypted without the access to the vault, even with the password
+	denied, err := kms.NewVaultTransit(srv.URL, vaultKey, "invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := kms.New(dir, denied).Key("swarm", "pass123456", crypto.EDGSecp256_K1); err == nil || !strings.Contains(err.Error(), "unwrap data key") {
+		t.Fatalf("got error %v, want unwrap data key error", err)
+	}
+}
+
+func TestNewVaultTransit(t *testing.T) {
+	t.Parallel()
+
+	if _, err := kms.NewVaultTransit("unix:///vault", vaultKey, vaultToken); err == nil {
+		t.Fatal("expected unsupported scheme error")
+	}
+	if _, err := kms.NewVaultTransit("https://vault:8200", "", vaultToken); err == nil {
+		t.Fatal("expected missing key error")
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kms
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// vaultTimeout is the timeout of the requests to the Vault.
+const vaultTimeout = 30 * time.Second
+
+var _ KeyWrapper = (*VaultTransit)(nil)
+
+// VaultTransit wraps the data keys with the named encryption key
+// of the transit secrets engine of the HashiCorp Vault.
+type VaultTransit struct {
+	client *http.Client
+	addr   string
+	key    string
+	token  string
+}
+
+// NewVaultTransit returns the VaultTransit which wraps the data keys
+// with the encryption key of the transit secrets engine mounted at the
+// transit path of the Vault at the address, authenticating with the token.
+func NewVaultTransit(addr, key, token string) (*VaultTransit, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("parse vault address: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported vault address scheme: %q", u.Scheme)
+	}
+	if key == "" {
+		return nil, fmt.Errorf("vault transit key not provided")
+	}
+	return &VaultTransit{
+		client: &http.Client{Timeout: vaultTimeout},
+		addr:   strings.TrimSuffix(addr, "/"),
+		key:    key,
+		token:  token,
+	}, nil
+}
+
+func (v *VaultTransit) Wrap(dataKey []byte) ([]byte, error) {
+	var res struct {
+		Data struct {
+			CipherText string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	if err := v.call("encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	}, &res); err != nil {
+		return nil, err
+	}
+	return []byte(res.Data.CipherText), nil
+}
+
+func (v *VaultTransit) Unwrap(wrapped []byte) ([]byte, error) {
+	var res struct {
+		Data struct {
+			PlainText string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := v.call("decrypt", map[string]string{
+		"ciphertext": string(wrapped),
+	}, &res); err != nil {
+		return nil, err
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(res.Data.PlainText)
+	if err != nil {
+		return nil, fmt.Errorf("base64 decode plaintext: %w", err)
+	}
+	return dataKey, nil
+}
+
+func (v *VaultTransit) call(operation string, body, res interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v1/transit/%s/%s", v.addr, operation, url.PathEscape(v.key)), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault %s: %w", operation, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("vault %s: read response: %w", operation, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault %s: %s: %s", operation, resp.Status, bytes.TrimSpace(data))
+	}
+	if err := json.Unmarshal(data, res); err != nil {
+		return fmt.Errorf("vault %s: unmarshal response: %w", operation, err)
+	}
+	return nil
+}
@@ -33,11 +33,11 @@ import (
 )
 
 const (
-	// filename in tar archive that holds the information
-	// about exported data format version
-	exportVersionFilename = ".swarm-export-version"
-	// current export format version
-	currentExportVersion = "3"
+	// ExportVersionFilename is the filename in tar archive that holds
+	// the information about exported data format version.
+	ExportVersionFilename = ".swarm-export-version"
+	// CurrentExportVersion is the current export format version.
+	CurrentExportVersion = "3"
 )
 
 // Export writes a tar structured data to the writer of
@@ -48,13 +48,13 @@ func (db *DB) Export(w io.Writer) (count int64, err error) {
 	defer tw.Close()
 
 	if err := tw.WriteHeader(&tar.Header{
-		Name: exportVersionFilename,
+		Name: ExportVersionFilename,
 		Mode: 0644,
-		Size: int64(len(currentExportVersion)),
+		Size: int64(len(CurrentExportVersion)),
 	}); err != nil {
 		return 0, err
 	}
-	if _, err := tw.Write([]byte(currentExportVersion)); err != nil {
+	if _, err := tw.Write([]byte(CurrentExportVersion)); err != nil {
 		return 0, err
 	}
 
@@ -116,9 +116,9 @@ func (db *DB) Import(ctx context.Context, r io.Reader) (count int64, err error)
 		var (
 			firstFile = true
 
-			// if exportVersionFilename file is not present
+			// if ExportVersionFilename file is not present
 			// assume current version
-			version = currentExportVersion
+			version = CurrentExportVersion
 		)
 		for {
 			hdr, err := tr.Next()
@@ -133,7 +133,7 @@ func (db *DB) Import(ctx context.Context, r io.Reader) (count int64, err error)
 			}
 			if firstFile {
 				firstFile = false
-				if hdr.Name == exportVersionFilename {
+				if hdr.Name == ExportVersionFilename {
 					data, err := io.ReadAll(tr)
 					if err != nil {
 						select {
@@ -177,7 +177,7 @@ func (db *DB) Import(ctx context.Context, r io.Reader) (count int64, err error)
 
 			var ch swarm.Chunk
 			switch version {
-			case currentExportVersion:
+			case CurrentExportVersion:
 				ch = swarm.NewChunk(key, data).WithStamp(stamp)
 			default:
 				select {
@@ -0,0 +1,111 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// RetrievalRecord is an entry of the retrieval indexes.
+type RetrievalRecord struct {
+	Address         swarm.Address
+	BatchID         []byte
+	BatchIndex      []byte
+	BinID           uint64
+	StoreTimestamp  int64
+	AccessTimestamp int64 // zero if the chunk was never accessed
+}
+
+// PullRecord is an entry of the pull index.
+type PullRecord struct {
+	PO      uint8
+	BinID   uint64
+	Address swarm.Address
+	BatchID []byte
+}
+
+// PostageRecord is an entry of the postage index index,
+// the chunk stamped with the batch at the batch index.
+type PostageRecord struct {
+	BatchID    []byte
+	BatchIndex []byte
+	Address    swarm.Address
+	Timestamp  int64 // timestamp of the stamp
+}
+
+// PinRecord is an entry of the pin index.
+type PinRecord struct {
+	Address    swarm.Address
+	PinCounter uint64
+}
+
+// IndexExporter receives the entries of the indexes exported by the DB.
+type IndexExporter interface {
+	Retrieval(RetrievalRecord) error
+	Pull(PullRecord) error
+	Postage(PostageRecord) error
+	Pin(PinRecord) error
+}
+
+// ExportIndexes passes all entries of the retrieval, pull, postage and
+// pin indexes to the exporter, for the offline analytics of the store.
+func (db *DB) ExportIndexes(e IndexExporter) error {
+	err := db.retrievalDataIndex.Iterate(func(item shed.Item) (bool, error) {
+		r := RetrievalRecord{
+			Address:        swarm.NewAddress(item.Address),
+			BatchID:        item.BatchID,
+			BatchIndex:     item.Index,
+			BinID:          item.BinID,
+			StoreTimestamp: item.StoreTimestamp,
+		}
+		access, err := db.retrievalAccessIndex.Get(item)
+		switch {
+		case err == nil:
+			r.AccessTimestamp = access.AccessTimestamp
+		case !errors.Is(err, leveldb.ErrNotFound):
+			return true, err
+		}
+		return false, e.Retrieval(r)
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	err = db.pullIndex.Iterate(func(item shed.Item) (bool, error) {
+		return false, e.Pull(PullRecord{
+			PO:      db.po(swarm.NewAddress(item.Address)),
+			BinID:   item.BinID,
+			Address: swarm.NewAddress(item.Address),
+			BatchID: item.BatchID,
+		})
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	err = db.postageIndexIndex.Iterate(func(item shed.Item) (bool, error) {
+		return false, e.Postage(PostageRecord{
+			BatchID:    item.BatchID,
+			BatchIndex: item.Index,
+			Address:    swarm.NewAddress(item.Address),
+			Timestamp:  int64(binary.BigEndian.Uint64(item.Timestamp)),
+		})
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return db.pinIndex.Iterate(func(item shed.Item) (bool, error) {
+		return false, e.Pin(PinRecord{
+			Address:    swarm.NewAddress(item.Address),
+			PinCounter: item.PinCounter,
+		})
+	}, nil)
+}
@@ -0,0 +1,223 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package exportverify verifies the archives written by the localstore
+// export offline, so that the exports kept in the cold storage can be
+// checked for the archival and compliance purposes without a running node.
+package exportverify
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/manifest/mantaray"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+)
+
+// Check is the name of the check a chunk of the archive failed.
+type Check string
+
+const (
+	// CheckHash fails for the chunks whose content does not hash to their address.
+	CheckHash Check = "hash"
+	// CheckStamp fails for the chunks whose postage stamp is not valid.
+	CheckStamp Check = "stamp"
+	// CheckManifest fails for the manifests whose entries are not all in the archive.
+	CheckManifest Check = "manifest"
+)
+
+// Failure is the failed check of a chunk.
+type Failure struct {
+	Check   Check
+	Address swarm.Address
+	Reason  string
+}
+
+// Batch summarizes the stamps of a postage batch found in the archive.
+type Batch struct {
+	ID     []byte
+	Owner  []byte // the signer of the first stamp of the batch
+	Chunks int
+}
+
+// Report is the result of the verification of the archive.
+type Report struct {
+	Version   string
+	Chunks    int
+	Ignored   int // the files of the archive which are not chunks
+	Manifests int // the manifests traversed
+	Batches   []*Batch
+	Failures  []Failure
+}
+
+// OK reports whether all the chunks of the archive passed all the checks.
+func (r *Report) OK() bool {
+	return len(r.Failures) == 0
+}
+
+func (r *Report) fail(check Check, addr swarm.Address, format string, args ...interface{}) {
+	r.Failures = append(r.Failures, Failure{
+		Check:   check,
+		Address: addr,
+		Reason:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Verify reads the export archive and verifies that the content of every chunk
+// hashes to its address, that the postage stamps are signed by the same owner
+// for each batch and are in the collision bucket of the chunk, and that all the
+// chunks of the manifests found in the archive are in the archive as well. The
+// owners of the batches can not be checked offline, they are reported instead.
+// The chunks are held in memory while the manifests are traversed.
+func Verify(ctx context.Context, r io.Reader) (*Report, error) {
+	var (
+		tr        = tar.NewReader(r)
+		report    = &Report{Version: localstore.CurrentExportVersion}
+		store     = make(chunkStore)
+		batches   = make(map[string]*Batch)
+		manifests []swarm.Address
+	)
+	for first := true; ; first = false {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		if first && hdr.Name == localstore.ExportVersionFilename {
+			version, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("read export version: %w", err)
+			}
+			if string(version) != localstore.CurrentExportVersion {
+				return nil, fmt.Errorf("unsupported export data version %q", version)
+			}
+			continue
+		}
+
+		addrBytes, err := hex.DecodeString(hdr.Name)
+		if err != nil || len(addrBytes) != swarm.HashSize {
+			report.Ignored++
+			continue
+		}
+		addr := swarm.NewAddress(addrBytes)
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("read chunk %s: %w", addr, err)
+		}
+		report.Chunks++
+
+		if len(data) < postage.StampSize+swarm.SpanSize {
+			report.fail(CheckHash, addr, "truncated to %d bytes", len(data))
+			continue
+		}
+		stamp := new(postage.Stamp)
+		if err := stamp.UnmarshalBinary(data[:postage.StampSize]); err != nil {
+			report.fail(CheckStamp, addr, "%v", err)
+			continue
+		}
+		ch := swarm.NewChunk(addr, data[postage.StampSize:]).WithStamp(stamp)
+
+		isCAC := cac.Valid(ch)
+		if !isCAC && !soc.Valid(ch) {
+			report.fail(CheckHash, addr, "content does not hash to the address")
+			continue
+		}
+		store[addr.ByteString()] = ch
+
+		if err := verifyStamp(batches, report, addr, stamp); err != nil {
+			report.fail(CheckStamp, addr, "%v", err)
+		}
+
+		if isCAC && isManifest(addr, ch.Data()[swarm.SpanSize:]) {
+			manifests = append(manifests, addr)
+		}
+	}
+
+	// the manifests reached from the traversed ones are not traversed again
+	var (
+		traverser = traversal.New(store)
+		seen      = make(map[string]struct{})
+	)
+	for _, addr := range manifests {
+		if _, ok := seen[addr.ByteString()]; ok {
+			continue
+		}
+		report.Manifests++
+		// the leaf chunks are iterated by the traversal without being read
+		err := traverser.Traverse(ctx, addr, func(ref swarm.Address) error {
+			if _, ok := store[ref.ByteString()]; !ok {
+				return fmt.Errorf("chunk %s: %w", ref, storage.ErrNotFound)
+			}
+			seen[ref.ByteString()] = struct{}{}
+			return nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			report.fail(CheckManifest, addr, "%v", err)
+		}
+	}
+
+	return report, nil
+}
+
+// verifyStamp checks the stamp of the chunk with the address and
+// accounts it to its batch.
+func verifyStamp(batches map[string]*Batch, report *Report, addr swarm.Address, stamp *postage.Stamp) error {
+	signer, err := stamp.RecoverSigner(addr)
+	if err != nil {
+		return fmt.Errorf("recover signer: %w", err)
+	}
+	if err := stamp.ValidBucket(addr, postage.BucketDepth); err != nil {
+		return err
+	}
+
+	id := string(stamp.BatchID())
+	b, ok := batches[id]
+	if !ok {
+		b = &Batch{ID: stamp.BatchID(), Owner: signer}
+		batches[id] = b
+		report.Batches = append(report.Batches, b)
+	}
+	b.Chunks++
+	if !bytes.Equal(b.Owner, signer) {
+		return fmt.Errorf("%w: signed by %x, batch %x signed by %x", postage.ErrOwnerMismatch, signer, b.ID, b.Owner)
+	}
+	return nil
+}
+
+// isManifest reports whether the payload of the chunk is a manifest
+// node. Only the nodes which fit into a single chunk are recognized.
+func isManifest(addr swarm.Address, payload []byte) bool {
+	return mantaray.NewNodeRef(addr.Bytes()).UnmarshalBinary(payload) == nil
+}
+
+// chunkStore holds the verified chunks of the archive for the traversal.
+type chunkStore map[string]swarm.Chunk
+
+func (s chunkStore) Get(_ context.Context, _ storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	ch, ok := s[addr.ByteString()]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return ch, nil
+}
+
+func (s chunkStore) Put(_ context.Context, _ storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	return nil, errors.New("read-only store")
+}
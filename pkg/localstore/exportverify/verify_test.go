@@ -0,0 +1,234 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package exportverify_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/localstore/exportverify"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+)
+
+// entry is the file of the chunk in the export archive.
+type entry struct {
+	addr swarm.Address
+	data []byte // the stamp followed by the chunk data
+}
+
+// newArchiveEntries uploads a file with its manifest and returns the
+// stamped chunks of the manifest, the manifest chunk first.
+func newArchiveEntries(t *testing.T, stamper postage.Stamper) []entry {
+	t.Helper()
+
+	ctx := context.Background()
+	store := mock.NewStorer()
+	pipelineFn := func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, store, storage.ModePutUpload, false)
+	}
+
+	data := make([]byte, 3*swarm.ChunkSize)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	file, err := builder.FeedPipeline(ctx, pipelineFn(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := manifest.NewDefaultManifest(loadsave.New(store, pipelineFn), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(ctx, "data.bin", manifest.NewEntry(file, nil)); err != nil {
+		t.Fatal(err)
+	}
+	root, err := m.Store(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []entry
+	err = traversal.New(store).Traverse(ctx, root, func(addr swarm.Address) error {
+		ch, err := store.Get(ctx, storage.ModeGetRequest, addr)
+		if err != nil {
+			return err
+		}
+		stamp, err := stamper.Stamp(addr)
+		if err != nil {
+			return err
+		}
+		b, err := stamp.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{addr: addr, data: append(b, ch.Data()...)})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entries[0].addr.Equal(root) {
+		t.Fatalf("got first chunk %s, want manifest %s", entries[0].addr, root)
+	}
+	return entries
+}
+
+// writeArchive writes the entries in the format of the localstore export.
+func writeArchive(t *testing.T, version string, entries []entry) *bytes.Buffer {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	write := func(name string, data []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(localstore.ExportVersionFilename, []byte(version))
+	for _, e := range entries {
+		write(hex.EncodeToString(e.addr.Bytes()), e.data)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+func newTestStamper(t *testing.T, batchID []byte) (postage.Stamper, []byte) {
+	t.Helper()
+
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := crypto.NewEthereumAddress(key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := postage.NewStampIssuer("", "", batchID, big.NewInt(3), 20, postage.BucketDepth, 1000, true)
+	return postage.NewStamper(issuer, crypto.NewDefaultSigner(key)), owner
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	batchID := bytes.Repeat([]byte{1}, 32)
+	stamper, owner := newTestStamper(t, batchID)
+	entries := newArchiveEntries(t, stamper)
+
+	verify := func(t *testing.T, entries []entry) *exportverify.Report {
+		t.Helper()
+		report, err := exportverify.Verify(context.Background(), writeArchive(t, localstore.CurrentExportVersion, entries))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return report
+	}
+	wantFailure := func(t *testing.T, report *exportverify.Report, check exportverify.Check, addr swarm.Address) {
+		t.Helper()
+		for _, f := range report.Failures {
+			if f.Check == check && f.Address.Equal(addr) {
+				return
+			}
+		}
+		t.Fatalf("got failures %+v, want %s failure of %s", report.Failures, check, addr)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		report := verify(t, entries)
+		if !report.OK() {
+			t.Fatalf("got failures %+v", report.Failures)
+		}
+		if report.Chunks != len(entries) {
+			t.Fatalf("got %d chunks, want %d", report.Chunks, len(entries))
+		}
+		if report.Manifests != 1 {
+			t.Fatalf("got %d manifests, want 1", report.Manifests)
+		}
+		if len(report.Batches) != 1 {
+			t.Fatalf("got %d batches, want 1", len(report.Batches))
+		}
+		b := report.Batches[0]
+		if !bytes.Equal(b.ID, batchID) || !bytes.Equal(b.Owner, owner) || b.Chunks != len(entries) {
+			t.Fatalf("got batch %x owned by %x with %d chunks, want %x owned by %x with %d chunks",
+				b.ID, b.Owner, b.Chunks, batchID, owner, len(entries))
+		}
+	})
+
+	t.Run("corrupted chunk", func(t *testing.T) {
+		t.Parallel()
+
+		corrupted := append([]entry(nil), entries...)
+		last := len(corrupted) - 1
+		data := append([]byte(nil), corrupted[last].data...)
+		data[len(data)-1] ^= 0xff
+		corrupted[last].data = data
+
+		report := verify(t, corrupted)
+		wantFailure(t, report, exportverify.CheckHash, corrupted[last].addr)
+		wantFailure(t, report, exportverify.CheckManifest, corrupted[0].addr)
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		t.Parallel()
+
+		report := verify(t, entries[:len(entries)-1])
+		if len(report.Failures) != 1 {
+			t.Fatalf("got failures %+v, want one", report.Failures)
+		}
+		wantFailure(t, report, exportverify.CheckManifest, entries[0].addr)
+	})
+
+	t.Run("foreign stamp", func(t *testing.T) {
+		t.Parallel()
+
+		foreign, _ := newTestStamper(t, batchID)
+		restamped := append([]entry(nil), entries...)
+		last := len(restamped) - 1
+		stamp, err := foreign.Stamp(restamped[last].addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := stamp.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		restamped[last].data = append(b, restamped[last].data[postage.StampSize:]...)
+
+		report := verify(t, restamped)
+		if len(report.Failures) != 1 {
+			t.Fatalf("got failures %+v, want one", report.Failures)
+		}
+		wantFailure(t, report, exportverify.CheckStamp, restamped[last].addr)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := exportverify.Verify(context.Background(), writeArchive(t, "1", entries)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
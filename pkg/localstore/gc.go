@@ -17,14 +17,17 @@
 package localstore
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"sort"
+	"sync"
 	"time"
 
-	"github.com/ethersphere/bee/pkg/sharky"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/syndtr/goleveldb/leveldb"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -44,6 +47,15 @@ var (
 	reserveEvictionBatch uint64 = 200
 )
 
+const (
+	// gcPartitionBits is the number of the leading address bits by which
+	// the gcIndex is partitioned.
+	gcPartitionBits = 2
+	// gcPartitions is the number of the gcIndex partitions, which are
+	// iterated and committed concurrently by the garbage collection.
+	gcPartitions = 1 << gcPartitionBits
+)
+
 // collectGarbageWorker is a long running function that waits for
 // collectGarbageTrigger channel to signal a garbage collection
 // run. GC run iterates on gcIndex and removes older items
@@ -80,6 +92,8 @@ func (db *DB) collectGarbageWorker() {
 // This function returns the number of removed chunks. If done
 // is false, another call to this function is needed to collect
 // the rest of the garbage as the batch size limit is reached.
+// The candidates are collected from all of the gcIndex partitions
+// and each partition is evicted in a separate batch.
 // This function is called in collectGarbageWorker.
 // collected count should reflect how many
 func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
@@ -90,7 +104,6 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 		}
 		totalTimeMetric(db.metrics.TotalTimeCollectGarbage, start)
 	}(time.Now())
-	batch := new(leveldb.Batch)
 	target := db.gcTarget()
 
 	// tell the localstore to start logging dirty addresses
@@ -114,25 +127,7 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 	}
 	db.metrics.GCSize.Set(float64(gcSize))
 
-	first := true
-	start := time.Now()
-
-	candidates := make([]shed.Item, 0, gcBatchSize)
-
-	err = db.gcIndex.Iterate(func(item shed.Item) (stop bool, err error) {
-		if first {
-			totalTimeMetric(db.metrics.TotalTimeGCFirstItem, start)
-			first = false
-		}
-
-		if len(candidates) == cap(candidates) {
-			return true, nil
-		}
-
-		candidates = append(candidates, item)
-
-		return false, nil
-	}, nil)
+	candidates, err := db.gcCandidates()
 	if err != nil {
 		return 0, false, err
 	}
@@ -141,22 +136,20 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 		testHookGCIteratorDone()
 	}
 
-	// protect database from changing idexes and gcSize
-	db.lock.Lock(lockKeyGC)
-	defer totalTimeMetric(db.metrics.TotalTimeGCLock, time.Now())
-	defer db.lock.Unlock(lockKeyGC)
-
 	// refresh gcSize value, since it might have
-	// changed in the meanwhile
+	// changed in the meanwhile, and pick the candidates
+	// to be evicted from each of the partitions
+	db.lock.Lock(lockKeyGC)
 	gcSize, err = db.gcSize.Get()
 	if err != nil {
+		db.lock.Unlock(lockKeyGC)
 		return 0, false, err
 	}
 
-	var totalChunksEvicted uint64
-	locations := make([]sharky.Location, 0, len(candidates))
-
-	// get rid of dirty entries
+	var (
+		selected [gcPartitions][]shed.Item
+		count    uint64
+	)
 	for _, item := range candidates {
 		if swarm.NewAddress(item.Address).MemberOf(db.dirtyAddresses) {
 			continue
@@ -170,22 +163,159 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 		// last iteration of the gc eviction, it gets around the edge case of the last iteration never reaching
 		// the target since the gc size always is bound to change even if to a minor degree in the time between
 		// candidate collection and the mutex acquisition.
-		if gcSize-totalChunksEvicted <= target {
+		if gcSize-count <= target {
 			done = true
 			break
 		}
 
+		count++
+		p := gcPartition(item.Address)
+		selected[p] = append(selected[p], item)
+	}
+	db.lock.Unlock(lockKeyGC)
+
+	// the partitions are committed separately, each in its own smaller
+	// batch, so that the writers waiting on the gc lock are not stalled
+	// for the duration of the whole run.
+	var (
+		g         errgroup.Group
+		committed [gcPartitions]uint64
+	)
+	for p := range selected {
+		p := p
+		if len(selected[p]) == 0 {
+			continue
+		}
+		g.Go(func() (err error) {
+			committed[p], err = db.evictGarbage(selected[p])
+			return err
+		})
+	}
+	err = g.Wait()
+
+	var totalChunksEvicted uint64
+	for _, n := range committed {
+		totalChunksEvicted += n
+	}
+	if err != nil {
+		return totalChunksEvicted, false, err
+	}
+	// some of the selected chunks were accessed in the
+	// meanwhile, another run is needed to reach the target
+	if totalChunksEvicted < count {
+		done = false
+	}
+
+	return totalChunksEvicted, done, nil
+}
+
+// gcCandidates iterates over the gcIndex partitions concurrently and returns
+// at most gcBatchSize of the least recently accessed items across all of them,
+// ordered by the access time.
+func (db *DB) gcCandidates() ([]shed.Item, error) {
+	var (
+		g          errgroup.Group
+		first      sync.Once
+		start      = time.Now()
+		partitions [gcPartitions][]shed.Item
+	)
+	for p := range partitions {
+		p := p
+		g.Go(func() error {
+			return db.gcIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+				first.Do(func() {
+					totalTimeMetric(db.metrics.TotalTimeGCFirstItem, start)
+				})
+
+				if uint64(len(partitions[p])) == gcBatchSize {
+					return true, nil
+				}
+
+				partitions[p] = append(partitions[p], item)
+
+				return false, nil
+			}, &shed.IterateOptions{Prefix: []byte{byte(p)}})
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var candidates []shed.Item
+	for _, items := range partitions {
+		candidates = append(candidates, items...)
+	}
+	sortByAccess(candidates)
+	if uint64(len(candidates)) > gcBatchSize {
+		candidates = candidates[:gcBatchSize]
+	}
+	return candidates, nil
+}
+
+// sortByAccess orders the gcIndex items by the access time,
+// as they are ordered within a partition.
+func sortByAccess(items []shed.Item) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.AccessTimestamp != b.AccessTimestamp {
+			return a.AccessTimestamp < b.AccessTimestamp
+		}
+		if a.BinID != b.BinID {
+			return a.BinID < b.BinID
+		}
+		return bytes.Compare(a.Address, b.Address) < 0
+	})
+}
+
+// evictGarbage removes the items of a single gcIndex partition from
+// retrieval and other indexes in one batch and returns the number
+// of removed chunks. The items which became dirty since they were
+// selected are skipped.
+func (db *DB) evictGarbage(items []shed.Item) (uint64, error) {
+	batch := new(leveldb.Batch)
+
+	// protect database from changing idexes and gcSize
+	db.lock.Lock(lockKeyGC)
+	defer totalTimeMetric(db.metrics.TotalTimeGCLock, time.Now())
+	defer db.lock.Unlock(lockKeyGC)
+
+	gcSize, err := db.gcSize.Get()
+	if err != nil {
+		return 0, err
+	}
+
+	var totalChunksEvicted uint64
+	locations := make([]shed.Item, 0, len(items))
+
+	for _, item := range items {
+		if swarm.NewAddress(item.Address).MemberOf(db.dirtyAddresses) {
+			continue
+		}
+
 		totalChunksEvicted++
 
 		storedItem, err := db.retrievalDataIndex.Get(item)
 		if err != nil {
 			if errors.Is(err, leveldb.ErrNotFound) {
 				if err = db.gcIndex.DeleteInBatch(batch, item); err != nil {
-					return 0, false, err
+					return 0, err
 				}
 				continue
 			}
-			return 0, false, err
+			return 0, err
+		}
+
+		// the uploads which are not synced yet are only removed from
+		// the cache, the entry could be added before they were isolated
+		pending, err := db.uploadPending(storedItem)
+		if err != nil {
+			return 0, err
+		}
+		if pending {
+			if err = db.gcIndex.DeleteInBatch(batch, item); err != nil {
+				return 0, err
+			}
+			continue
 		}
 
 		db.metrics.GCStoreTimeStamps.Set(float64(storedItem.StoreTimestamp))
@@ -194,62 +324,70 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 		// delete from retrieve, pull, gc
 		err = db.retrievalDataIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return 0, false, err
+			return 0, err
 		}
 		err = db.retrievalAccessIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return 0, false, err
-		}
-		err = db.pushIndex.DeleteInBatch(batch, storedItem)
-		if err != nil {
-			return 0, false, err
+			return 0, err
 		}
 		err = db.pullIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return 0, false, err
+			return 0, err
 		}
 		err = db.gcIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return 0, false, err
+			return 0, err
 		}
 		err = db.postageIndexIndex.DeleteInBatch(batch, storedItem)
 		if err != nil {
-			return 0, false, err
+			return 0, err
 		}
 		err = db.postageChunksIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return 0, false, err
+			return 0, err
 		}
-		loc, err := sharky.LocationFromBinary(storedItem.Location)
+		err = db.coldIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return 0, false, err
+			return 0, err
 		}
-		locations = append(locations, loc)
+		locations = append(locations, storedItem)
 	}
 
 	db.metrics.GCCommittedCounter.Add(float64(totalChunksEvicted))
 	db.gcSize.PutInBatch(batch, gcSize-totalChunksEvicted)
 
+	err = db.logIntentsInBatch(batch, locations)
+	if err != nil {
+		return 0, err
+	}
+
 	err = db.shed.WriteBatch(batch)
 	if err != nil {
 		db.metrics.GCErrorCounter.Inc()
-		return 0, false, err
+		return 0, err
 	}
 
-	for _, loc := range locations {
-		err = db.sharky.Release(context.Background(), loc)
-		if err != nil {
-			db.logger.Warning("failed releasing sharky location", "location", loc)
-		}
+	err = db.releaseIntents(context.Background(), locations)
+	if err != nil {
+		db.logger.Warning("failed releasing sharky locations", "error", err)
 	}
 
-	return totalChunksEvicted, done, nil
+	return totalChunksEvicted, nil
+}
+
+// gcPartition returns the gcIndex partition of the chunk address,
+// which is determined by its leading gcPartitionBits bits.
+func gcPartition(addr []byte) byte {
+	if len(addr) == 0 {
+		return 0
+	}
+	return addr[0] >> (8 - gcPartitionBits)
 }
 
 // gcTarget retruns the absolute value for garbage collection
-// target value, calculated from db.capacity and gcTargetRatio.
+// target value, calculated from db.gcCapacity and gcTargetRatio.
 func (db *DB) gcTarget() (target uint64) {
-	return uint64(float64(db.cacheCapacity) * gcTargetRatio)
+	return uint64(float64(db.gcCapacity.Load()) * gcTargetRatio)
 }
 
 // triggerGarbageCollection signals collectGarbageWorker
@@ -291,7 +429,7 @@ func (db *DB) incGCSizeInBatch(batch *leveldb.Batch, change int64) (err error) {
 	db.metrics.GCSize.Set(float64(newSize))
 
 	// trigger garbage collection if we reached the capacity
-	if newSize >= db.cacheCapacity {
+	if newSize >= db.gcCapacity.Load() {
 		db.triggerGarbageCollection()
 	}
 	return nil
@@ -153,6 +153,50 @@ func testDBCollectGarbageWorker(t *testing.T) {
 	})
 }
 
+// TestDB_gcCandidates tests that the garbage collection candidates are
+// collected from all of the gcIndex partitions in the order of the access.
+func TestDB_gcCandidates(t *testing.T) {
+	defer func(s uint64) { gcBatchSize = s }(gcBatchSize)
+	gcBatchSize = 20
+
+	t.Cleanup(setWithinRadiusFunc(func(_ *DB, _ shed.Item) bool { return false }))
+	db := newTestDB(t, nil)
+
+	chunkCount := 50
+	addrs := make([]swarm.Address, chunkCount)
+	partitions := make(map[byte]struct{})
+	for i := 0; i < chunkCount; i++ {
+		ch := generateTestRandomChunk()
+		unreserveChunkBatch(t, db, 0, ch)
+		_, err := db.Put(context.Background(), storage.ModePutUpload, ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.Set(context.Background(), storage.ModeSetSync, ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs[i] = ch.Address()
+		partitions[gcPartition(ch.Address().Bytes())] = struct{}{}
+	}
+	if len(partitions) < 2 {
+		t.Skip("chunks fell into a single partition")
+	}
+
+	candidates, err := db.gcCandidates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint64(len(candidates)) != gcBatchSize {
+		t.Fatalf("got %d candidates, want %d", len(candidates), gcBatchSize)
+	}
+	for i, item := range candidates {
+		if !bytes.Equal(item.Address, addrs[i].Bytes()) {
+			t.Fatalf("got candidate %x at position %d, want %s", item.Address, i, addrs[i])
+		}
+	}
+}
+
 // Pin a file, upload chunks to go past the gc limit to trigger GC,
 // check if the pinned files are still around and removed from gcIndex
 func TestPinGC(t *testing.T) {
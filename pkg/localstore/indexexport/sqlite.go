@@ -0,0 +1,148 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package indexexport writes the indexes of the localstore into
+// formats suitable for the offline analytics, so that the operators
+// can query them without touching the leveldb of the node.
+package indexexport
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethersphere/bee/pkg/localstore"
+	_ "modernc.org/sqlite" // registers the sqlite driver
+)
+
+const schema = `
+CREATE TABLE retrieval (
+	address TEXT NOT NULL PRIMARY KEY,
+	batch_id TEXT NOT NULL,
+	batch_index TEXT NOT NULL,
+	bin_id INTEGER NOT NULL,
+	store_timestamp INTEGER NOT NULL,
+	access_timestamp INTEGER NOT NULL
+);
+CREATE TABLE pull (
+	po INTEGER NOT NULL,
+	bin_id INTEGER NOT NULL,
+	address TEXT NOT NULL,
+	batch_id TEXT NOT NULL,
+	PRIMARY KEY (po, bin_id)
+);
+CREATE TABLE postage (
+	batch_id TEXT NOT NULL,
+	batch_index TEXT NOT NULL,
+	address TEXT NOT NULL,
+	timestamp INTEGER NOT NULL,
+	PRIMARY KEY (batch_id, batch_index)
+);
+CREATE TABLE pin (
+	address TEXT NOT NULL PRIMARY KEY,
+	pin_counter INTEGER NOT NULL
+);
+`
+
+// Counts holds the number of the exported entries per index.
+type Counts struct {
+	Retrieval int
+	Pull      int
+	Postage   int
+	Pin       int
+}
+
+var _ localstore.IndexExporter = (*sqliteExporter)(nil)
+
+type sqliteExporter struct {
+	retrieval *sql.Stmt
+	pull      *sql.Stmt
+	postage   *sql.Stmt
+	pin       *sql.Stmt
+	counts    Counts
+}
+
+// SQLite exports the indexes of the store into a new SQLite database
+// file at the path, with one table per index. The addresses and batch
+// ids are stored as hex strings and the timestamps as unix nanoseconds.
+func SQLite(store *localstore.DB, path string) (counts Counts, err error) {
+	if _, err := os.Stat(path); err == nil {
+		return counts, fmt.Errorf("file %s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return counts, err
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return counts, err
+	}
+	defer func() {
+		if e := db.Close(); err == nil {
+			err = e
+		}
+	}()
+
+	if _, err := db.Exec(schema); err != nil {
+		return counts, fmt.Errorf("create schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return counts, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	e := new(sqliteExporter)
+	for _, s := range []struct {
+		stmt  **sql.Stmt
+		query string
+	}{
+		{&e.retrieval, "INSERT INTO retrieval VALUES (?, ?, ?, ?, ?, ?)"},
+		{&e.pull, "INSERT INTO pull VALUES (?, ?, ?, ?)"},
+		{&e.postage, "INSERT INTO postage VALUES (?, ?, ?, ?)"},
+		{&e.pin, "INSERT INTO pin VALUES (?, ?)"},
+	} {
+		if *s.stmt, err = tx.Prepare(s.query); err != nil {
+			return counts, fmt.Errorf("prepare statement: %w", err)
+		}
+	}
+
+	if err := store.ExportIndexes(e); err != nil {
+		return counts, err
+	}
+	if err := tx.Commit(); err != nil {
+		return counts, err
+	}
+	return e.counts, nil
+}
+
+func (e *sqliteExporter) Retrieval(r localstore.RetrievalRecord) error {
+	e.counts.Retrieval++
+	_, err := e.retrieval.Exec(r.Address.String(), hex.EncodeToString(r.BatchID), hex.EncodeToString(r.BatchIndex), int64(r.BinID), r.StoreTimestamp, r.AccessTimestamp)
+	return err
+}
+
+func (e *sqliteExporter) Pull(r localstore.PullRecord) error {
+	e.counts.Pull++
+	_, err := e.pull.Exec(r.PO, int64(r.BinID), r.Address.String(), hex.EncodeToString(r.BatchID))
+	return err
+}
+
+func (e *sqliteExporter) Postage(r localstore.PostageRecord) error {
+	e.counts.Postage++
+	_, err := e.postage.Exec(hex.EncodeToString(r.BatchID), hex.EncodeToString(r.BatchIndex), r.Address.String(), r.Timestamp)
+	return err
+}
+
+func (e *sqliteExporter) Pin(r localstore.PinRecord) error {
+	e.counts.Pin++
+	_, err := e.pin.Exec(r.Address.String(), int64(r.PinCounter))
+	return err
+}
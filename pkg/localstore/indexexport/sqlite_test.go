@@ -0,0 +1,83 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package indexexport_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/localstore/indexexport"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	chunktesting "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+
+	store, err := localstore.NewInmem(make([]byte, 32), nil, &localstore.Options{
+		UnreserveFunc: func(postage.UnreserveIteratorFn) error { return nil },
+		ValidStamp:    func(ch swarm.Chunk, _ []byte) (swarm.Chunk, error) { return ch, nil },
+	}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	chunks := chunktesting.GenerateTestRandomChunks(3)
+	if _, err := store.Put(context.Background(), storage.ModePutSync, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(context.Background(), storage.ModeSetPin, chunks[0].Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.sqlite")
+	counts, err := indexexport.SQLite(store, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the chunks in the reserve are pinned as well
+	if want := (indexexport.Counts{Retrieval: 3, Pull: 3, Postage: 3, Pin: 3}); counts != want {
+		t.Fatalf("got counts %+v, want %+v", counts, want)
+	}
+
+	if _, err := indexexport.SQLite(store, path); err == nil {
+		t.Fatal("expected error exporting into an existing file")
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	var (
+		batchID string
+		pins    int
+	)
+	err = db.QueryRow("SELECT r.batch_id, p.pin_counter FROM retrieval r JOIN pin p ON p.address = r.address WHERE r.address = ?", chunks[0].Address().String()).Scan(&batchID, &pins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := hex.EncodeToString(chunks[0].Stamp().BatchID()); batchID != want || pins != 2 {
+		t.Fatalf("got batch %s with %d pins, want batch %s with 2 pins", batchID, pins, want)
+	}
+
+	var n int
+	err = db.QueryRow("SELECT COUNT(*) FROM pull p JOIN postage s ON s.address = p.address").Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("got %d joined pull and postage entries, want 3", n)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/sharky"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var errBlobNotFound = errors.New("blob not found")
+
+// blobStore stores the chunk data at the locations
+// which are kept in the retrieval data index.
+type blobStore interface {
+	Read(ctx context.Context, loc sharky.Location, buf []byte) error
+	Write(ctx context.Context, data []byte) (sharky.Location, error)
+	Release(ctx context.Context, loc sharky.Location) error
+	Metrics() []prometheus.Collector
+	io.Closer
+}
+
+var (
+	_ blobStore = (*sharky.Store)(nil)
+	_ blobStore = (*inmemBlobStore)(nil)
+)
+
+// NewInmem returns a new DB which keeps all of its indexes and the chunk
+// data in memory. Nothing is persisted and the contents are lost on Close,
+// which makes it suitable for the dev mode and for the integration tests.
+func NewInmem(baseKey []byte, ss storage.StateStorer, o *Options, logger log.Logger) (*DB, error) {
+	return New("", baseKey, ss, o, logger)
+}
+
+// inmemBlobStore is the blobStore which keeps the data in memory.
+// Released slots are reused by the subsequent writes, as in sharky.
+type inmemBlobStore struct {
+	mu     sync.RWMutex
+	blobs  map[uint32][]byte
+	free   []uint32
+	next   uint32
+	closed bool
+}
+
+func newInmemBlobStore() *inmemBlobStore {
+	return &inmemBlobStore{blobs: make(map[uint32][]byte)}
+}
+
+// Read copies the blob found at the location into buf.
+func (s *inmemBlobStore) Read(ctx context.Context, loc sharky.Location, buf []byte) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return sharky.ErrQuitting
+	}
+	blob, ok := s.blobs[loc.Slot]
+	if !ok {
+		return errBlobNotFound
+	}
+	copy(buf[:loc.Length], blob)
+	return nil
+}
+
+// Write stores a copy of data and returns its location.
+func (s *inmemBlobStore) Write(ctx context.Context, data []byte) (sharky.Location, error) {
+	if len(data) > swarm.SocMaxChunkSize {
+		return sharky.Location{}, sharky.ErrTooLong
+	}
+	if err := ctx.Err(); err != nil {
+		return sharky.Location{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return sharky.Location{}, sharky.ErrQuitting
+	}
+	var slot uint32
+	if n := len(s.free); n > 0 {
+		slot, s.free = s.free[n-1], s.free[:n-1]
+	} else {
+		slot = s.next
+		s.next++
+	}
+	s.blobs[slot] = append([]byte(nil), data...)
+	return sharky.Location{Slot: slot, Length: uint16(len(data))}, nil
+}
+
+// Release frees the slot of the location for reuse.
+func (s *inmemBlobStore) Release(_ context.Context, loc sharky.Location) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return sharky.ErrQuitting
+	}
+	if _, ok := s.blobs[loc.Slot]; ok {
+		delete(s.blobs, loc.Slot)
+		s.free = append(s.free, loc.Slot)
+	}
+	return nil
+}
+
+func (s *inmemBlobStore) Metrics() []prometheus.Collector {
+	return nil
+}
+
+// Close drops all of the stored blobs.
+func (s *inmemBlobStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	s.blobs = nil
+	s.free = nil
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/sharky"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestInmemBlobStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := newInmemBlobStore()
+
+	data := []byte("some data")
+	loc, err := s.Write(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(loc.Length) != len(data) {
+		t.Fatalf("got length %d, want %d", loc.Length, len(data))
+	}
+
+	buf := make([]byte, loc.Length)
+	if err := s.Read(ctx, loc, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, data) {
+		t.Fatalf("got %q, want %q", buf, data)
+	}
+
+	// the stored blob must not alias the written slice
+	data[0] = 'x'
+	if err := s.Read(ctx, loc, buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf[0] != 's' {
+		t.Fatal("stored blob modified through the written slice")
+	}
+
+	if err := s.Release(ctx, loc); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Read(ctx, loc, buf); !errors.Is(err, errBlobNotFound) {
+		t.Fatalf("got error %v, want %v", err, errBlobNotFound)
+	}
+
+	reused, err := s.Write(ctx, []byte("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reused.Slot != loc.Slot {
+		t.Fatalf("got slot %d, want released slot %d", reused.Slot, loc.Slot)
+	}
+
+	if _, err := s.Write(ctx, make([]byte, swarm.SocMaxChunkSize+1)); !errors.Is(err, sharky.ErrTooLong) {
+		t.Fatalf("got error %v, want %v", err, sharky.ErrTooLong)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write(ctx, data); !errors.Is(err, sharky.ErrQuitting) {
+		t.Fatalf("got error %v, want %v", err, sharky.ErrQuitting)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/sharky"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// CheckReserve checks the data of the chunks indexed for the pull syncing
+// and removes the chunks whose data is missing or corrupted, so that they
+// are not reported as present and can be synced again. It returns the
+// proximity order bins in which the missing chunks were found.
+func (db *DB) CheckReserve(ctx context.Context) ([]uint8, error) {
+	var (
+		missing []swarm.Address
+		stale   []shed.Item
+		bins    = make(map[uint8]struct{})
+	)
+	err := db.pullIndex.Iterate(func(item shed.Item) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return true, err
+		}
+		addr := swarm.NewAddress(item.Address)
+		ok, err := db.intact(ctx, item)
+		switch {
+		case errors.Is(err, leveldb.ErrNotFound):
+			// the pull index entry of the removed chunk
+			stale = append(stale, item)
+		case err != nil:
+			return true, err
+		case ok:
+			return false, nil
+		default:
+			missing = append(missing, addr)
+		}
+		bins[db.po(addr)] = struct{}{}
+		return false, nil
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("iterate pull index: %w", err)
+	}
+
+	for _, item := range stale {
+		if err := db.pullIndex.Delete(item); err != nil {
+			return nil, fmt.Errorf("delete stale pull index entry: %w", err)
+		}
+	}
+	if len(missing) > 0 {
+		db.logger.Warning("reserve chunks missing", "count", len(missing))
+		if err := db.Set(ctx, storage.ModeSetRemove, missing...); err != nil {
+			return nil, fmt.Errorf("remove missing chunks: %w", err)
+		}
+	}
+
+	result := make([]uint8, 0, len(bins))
+	for bin := range bins {
+		result = append(result, bin)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+// intact reports whether the data of the chunk is stored and valid.
+func (db *DB) intact(ctx context.Context, item shed.Item) (bool, error) {
+	stored, err := db.retrievalDataIndex.Get(item)
+	if err != nil {
+		return false, err
+	}
+	loc, err := sharky.LocationFromBinary(stored.Location)
+	if err != nil {
+		return false, nil
+	}
+	data := make([]byte, loc.Length)
+	if err := db.sharky.Read(ctx, loc, data); err != nil {
+		return false, ctx.Err()
+	}
+	ch := swarm.NewChunk(swarm.NewAddress(item.Address), data)
+	return cac.Valid(ch) || soc.Valid(ch), nil
+}
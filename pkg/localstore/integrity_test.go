@@ -0,0 +1,76 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/sharky"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestCheckReserve(t *testing.T) {
+	db := newTestDB(t, nil)
+	ctx := context.Background()
+
+	chunks := make([]swarm.Chunk, 10)
+	for i := range chunks {
+		chunks[i] = generateTestRandomChunk()
+	}
+	if _, err := db.Put(ctx, storage.ModePutSync, chunks...); err != nil {
+		t.Fatal(err)
+	}
+
+	bins, err := db.CheckReserve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bins) != 0 {
+		t.Fatalf("got bins %v, want none", bins)
+	}
+
+	// the data of the chunk is lost
+	broken := chunks[0]
+	item, err := db.retrievalDataIndex.Get(addressToItem(broken.Address()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := sharky.LocationFromBinary(item.Location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.sharky.Release(ctx, loc); err != nil {
+		t.Fatal(err)
+	}
+
+	bins, err = db.CheckReserve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint8{db.po(broken.Address())}; !reflect.DeepEqual(bins, want) {
+		t.Fatalf("got bins %v, want %v", bins, want)
+	}
+
+	// the broken chunk is removed to be synced again
+	has, err := db.Has(ctx, broken.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has {
+		t.Fatal("broken chunk not removed")
+	}
+	for _, ch := range chunks[1:] {
+		got, err := db.Get(ctx, storage.ModeGetLookup, ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Address().Equal(ch.Address()) {
+			t.Fatalf("got chunk %s, want %s", got.Address(), ch.Address())
+		}
+	}
+}
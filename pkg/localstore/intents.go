@@ -0,0 +1,178 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/sharky"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/hashicorp/go-multierror"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// intentKeySize is the size of the shard and the slot of the binary sharky location.
+const intentKeySize = 5
+
+// The intent log is a write-ahead log of the sharky locations whose fate
+// depends on a leveldb batch which is not yet committed or whose release
+// is not yet done. The batch operations spanning both sharky and leveldb
+// log the locations to be released in the batch which removes their
+// references, and clear the intents once sharky releases them. The locations
+// written by a put are not logged: they are released if the put fails, and
+// after a crash the sharky recovery frees the slots which are not referenced
+// by the retrieval index, so the put needs no write before its own batch.
+//
+// The intents left behind by a crash or by a failed release are resolved
+// on startup: the location is kept if the retrieval index still references
+// it, otherwise it is released. As the log is keyed by the location, a new
+// write to a reused location replaces any stale intent for it.
+
+// newIntentIndex creates the index of the intents. The key is the shard and
+// the slot of the sharky location, so that the intents do not depend on the
+// length of the data stored in the slot.
+func (db *DB) newIntentIndex() (shed.Index, error) {
+	return db.shed.NewIndex("Shard|Slot->Location|Address", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			if len(fields.Location) != sharky.LocationSize {
+				return nil, fmt.Errorf("invalid location length %d", len(fields.Location))
+			}
+			return fields.Location[:intentKeySize], nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			value = make([]byte, sharky.LocationSize+len(fields.Address))
+			copy(value, fields.Location)
+			copy(value[sharky.LocationSize:], fields.Address)
+			return value, nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			e.Location = value[:sharky.LocationSize]
+			e.Address = value[sharky.LocationSize:]
+			return e, nil
+		},
+	})
+}
+
+// logIntents persists the intents for the locations of the items which
+// are written to sharky but not yet referenced by a committed batch.
+func (db *DB) logIntents(items []shed.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	batch := new(leveldb.Batch)
+	if err := db.logIntentsInBatch(batch, items); err != nil {
+		return err
+	}
+	return db.shed.WriteBatch(batch)
+}
+
+// logIntentsInBatch adds the intents for the locations of the items to the batch.
+func (db *DB) logIntentsInBatch(batch *leveldb.Batch, items []shed.Item) error {
+	for _, item := range items {
+		if err := db.intentIndex.PutInBatch(batch, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearIntentsInBatch removes the intents for the locations of the items in the batch.
+func (db *DB) clearIntentsInBatch(batch *leveldb.Batch, items []shed.Item) error {
+	for _, item := range items {
+		if err := db.intentIndex.DeleteInBatch(batch, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseIntents releases the sharky locations of the items and clears
+// their intents. The intents of the locations which fail to be released
+// are kept, so that they are resolved on the next startup.
+func (db *DB) releaseIntents(ctx context.Context, items []shed.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var (
+		batch    = new(leveldb.Batch)
+		released []shed.Item
+		mErr     *multierror.Error
+	)
+	for _, item := range items {
+		loc, err := sharky.LocationFromBinary(item.Location)
+		if err != nil {
+			mErr = multierror.Append(mErr, err)
+			continue
+		}
+		if err := db.sharky.Release(ctx, loc); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("release location %v: %w", loc, err))
+			continue
+		}
+		released = append(released, item)
+	}
+
+	if err := db.clearIntentsInBatch(batch, released); err != nil {
+		return multierror.Append(mErr, err).ErrorOrNil()
+	}
+	if err := db.shed.WriteBatch(batch); err != nil {
+		return multierror.Append(mErr, err).ErrorOrNil()
+	}
+	return mErr.ErrorOrNil()
+}
+
+// resolveIntents rolls the intents left behind by an interrupted batch
+// operation forward or back. The location of an intent is kept if the
+// chunk is committed to the retrieval index at that location, otherwise
+// the location is released.
+func (db *DB) resolveIntents() error {
+	var intents []shed.Item
+	err := db.intentIndex.Iterate(func(item shed.Item) (bool, error) {
+		intents = append(intents, item)
+		return false, nil
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("iterate intents: %w", err)
+	}
+	if len(intents) == 0 {
+		return nil
+	}
+
+	var (
+		kept    []shed.Item
+		release []shed.Item
+	)
+	for _, item := range intents {
+		stored, err := db.retrievalDataIndex.Get(item)
+		switch {
+		case err == nil && bytes.Equal(stored.Location, item.Location):
+			kept = append(kept, item)
+		case err == nil || errors.Is(err, leveldb.ErrNotFound):
+			release = append(release, item)
+		default:
+			return fmt.Errorf("get retrieval index: %w", err)
+		}
+	}
+
+	batch := new(leveldb.Batch)
+	if err := db.clearIntentsInBatch(batch, kept); err != nil {
+		return err
+	}
+	if err := db.shed.WriteBatch(batch); err != nil {
+		return err
+	}
+	if err := db.releaseIntents(context.Background(), release); err != nil {
+		return fmt.Errorf("release intents: %w", err)
+	}
+
+	db.logger.Info("localstore intents resolved", "kept", len(kept), "released", len(release))
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/sharky"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// releaseRecorder records the locations released in the wrapped blobStore.
+type releaseRecorder struct {
+	blobStore
+
+	mu       sync.Mutex
+	released []sharky.Location
+}
+
+func (r *releaseRecorder) Release(ctx context.Context, loc sharky.Location) error {
+	r.mu.Lock()
+	r.released = append(r.released, loc)
+	r.mu.Unlock()
+	return r.blobStore.Release(ctx, loc)
+}
+
+func TestIntentsCleared(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, nil)
+
+	chunks := generateTestRandomChunks(10)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("after put", newItemsCountTest(db.intentIndex, 0))
+
+	if err := db.Set(context.Background(), storage.ModeSetRemove, chunks[0].Address()); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("after remove", newItemsCountTest(db.intentIndex, 0))
+}
+
+// TestIntentsFailedPut tests that the locations written by a failed put are
+// released without the put logging any intents.
+func TestIntentsFailedPut(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, &Options{
+		UploadCapacity: 1,
+	})
+	recorder := &releaseRecorder{blobStore: db.sharky}
+	db.sharky = recorder
+
+	chunks := generateTestRandomChunks(2)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, chunks...); !errors.Is(err, ErrUploadStoreFull) {
+		t.Fatalf("got error %v, want %v", err, ErrUploadStoreFull)
+	}
+	t.Run("intents", newItemsCountTest(db.intentIndex, 0))
+	t.Run("retrieval", newItemsCountTest(db.retrievalDataIndex, 0))
+
+	if len(recorder.released) != len(chunks) {
+		t.Fatalf("got %d released locations, want %d", len(recorder.released), len(chunks))
+	}
+}
+
+func TestResolveIntents(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, nil)
+	recorder := &releaseRecorder{blobStore: db.sharky}
+	db.sharky = recorder
+
+	committed := generateTestRandomChunk()
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, committed); err != nil {
+		t.Fatal(err)
+	}
+	committedItem, err := db.retrievalDataIndex.Get(addressToItem(committed.Address()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a chunk written to sharky whose batch was never committed
+	uncommitted := generateTestRandomChunk()
+	loc, err := db.sharky.Write(context.Background(), uncommitted.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncommittedLoc, err := loc.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.logIntents([]shed.Item{
+		{Address: committed.Address().Bytes(), Location: committedItem.Location},
+		{Address: uncommitted.Address().Bytes(), Location: uncommittedLoc},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("logged", newItemsCountTest(db.intentIndex, 2))
+
+	if err := db.resolveIntents(); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("resolved", newItemsCountTest(db.intentIndex, 0))
+
+	if len(recorder.released) != 1 || recorder.released[0] != loc {
+		t.Fatalf("got released locations %v, want %v", recorder.released, []sharky.Location{loc})
+	}
+
+	if _, err := db.Get(context.Background(), storage.ModeGetLookup, committed.Address()); err != nil {
+		t.Fatalf("committed chunk: %v", err)
+	}
+	if _, err := db.Get(context.Background(), storage.ModeGetLookup, uncommitted.Address()); err == nil {
+		t.Fatal("uncommitted chunk found")
+	}
+
+	// a stale intent is replaced when its slot is reused
+	reused := generateTestRandomChunk()
+	if err := db.logIntents([]shed.Item{{Address: reused.Address().Bytes(), Location: uncommittedLoc}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, reused); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("reused", newItemsCountTest(db.intentIndex, 0))
+}
@@ -17,6 +17,7 @@
 package localstore
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
@@ -26,6 +27,7 @@ import (
 	"path/filepath"
 	"runtime/pprof"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/log"
@@ -39,7 +41,6 @@ import (
 	"github.com/ethersphere/bee/pkg/tags"
 	"github.com/hashicorp/go-multierror"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/spf13/afero"
 	"github.com/syndtr/goleveldb/leveldb"
 	"resenje.org/multex"
 )
@@ -96,8 +97,8 @@ const (
 // database related objects.
 type DB struct {
 	shed *shed.DB
-	// sharky instance
-	sharky       *sharky.Store
+	// chunk data store, sharky or in-memory
+	sharky       blobStore
 	fdirtyCloser func() error
 
 	tags *tags.Tags
@@ -107,6 +108,10 @@ type DB struct {
 
 	// schema name of loaded data
 	schemaName shed.StringField
+	// progress of the running migration
+	migrationCheckpoint shed.StructField
+	// the pending migrations are only estimated
+	migrationDryRun bool
 
 	// retrieval indexes
 	retrievalDataIndex   shed.Index
@@ -142,16 +147,40 @@ type DB struct {
 	// postage index index
 	postageIndexIndex shed.Index
 
+	// intent log of the sharky locations pending a batch commit or release
+	intentIndex shed.Index
+
+	// chunks whose data is in the cold tier
+	coldIndex shed.Index
+
+	// the unaccessed cache chunks are moved to the
+	// cold tier after coldAge, if it is configured
+	coldAge time.Duration
+
 	// field that stores number of items in gc index
 	gcSize shed.Uint64Field
 
 	// field that stores the size of the reserve
 	reserveSize shed.Uint64Field
 
+	// the number of the chunks in the upload store, the pushIndex,
+	// which is limited by the uploadCapacity, unless it is zero
+	uploadSize     atomic.Int64
+	uploadCapacity uint64
+
 	// garbage collection is triggered when gcSize exceeds
-	// the cacheCapacity value
+	// the gcCapacity value
 	cacheCapacity uint64
 
+	// the capacity of the cache lowered by the free disk space
+	// watermark, it equals to the cacheCapacity without it
+	gcCapacity atomic.Uint64
+
+	// the ratio of the filesystem of the diskPath
+	// kept free, the watermark is disabled if zero
+	minFreeDisk float64
+	diskPath    string
+
 	// the size of the reserve in chunks
 	reserveCapacity uint64
 
@@ -199,6 +228,8 @@ type DB struct {
 	// are done
 	collectGarbageWorkerDone  chan struct{}
 	reserveEvictionWorkerDone chan struct{}
+	tieringWorkerDone         chan struct{}
+	diskWatermarkWorkerDone   chan struct{}
 
 	// wait for all subscriptions to finish before closing
 	// underlaying leveldb to prevent possible panics from
@@ -223,6 +254,10 @@ type Options struct {
 	// UnreserveFunc is an iterator needed to facilitate reserve
 	// eviction once ReserveCapacity is reached.
 	UnreserveFunc func(postage.UnreserveIteratorFn) error
+	// Backend is the name of the storage backend of the indexes, one of
+	// the shed.Backends, and is passed on to shed. The shed.DefaultBackend
+	// is used if it is empty.
+	Backend string
 	// OpenFilesLimit defines the upper bound of open files that the
 	// the localstore should maintain at any point of time. It is
 	// passed on to the shed constructor.
@@ -235,19 +270,32 @@ type Options struct {
 	// DisableSeeksCompaction toggles the seek driven compactions feature on leveldb
 	// and is passed on to shed.
 	DisableSeeksCompaction bool
+	// GroupCommitLatency is the latency budget of the group commit of the
+	// concurrent writes and is passed on to shed. Zero disables it.
+	GroupCommitLatency time.Duration
 	// Stamp validator for reserve sampler
 	ValidStamp postage.ValidStampFn
 	// MetricsPrefix defines a prefix for metrics names.
 	MetricsPrefix string
 	Tags          *tags.Tags
-}
-
-type memFS struct {
-	afero.Fs
-}
-
-func (m *memFS) Open(path string) (fs.File, error) {
-	return m.Fs.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	// ColdPath is the path of the secondary storage of the chunk data.
+	// The cache chunks which are not accessed for ColdAge are moved there
+	// by the tiering job. The tiering is disabled if the path is empty.
+	ColdPath string
+	// ColdAge is the time after which the unaccessed cache chunk is cold.
+	ColdAge time.Duration
+	// MinFreeDisk is the ratio of the filesystem of the store, in range
+	// [0,1), which is kept free by lowering the capacity of the cache below
+	// the Capacity. The capacity is not adjusted if it is zero.
+	MinFreeDisk float64
+	// UploadCapacity is the maximal number of the uploaded chunks which
+	// are not synced yet, the uploads over it are refused with the
+	// ErrUploadStoreFull. The upload store is not limited if it is zero.
+	UploadCapacity uint64
+	// MigrationDryRun makes New only log the estimated duration and space
+	// requirements of the pending schema migrations and return
+	// ErrMigrationDryRun instead of running them.
+	MigrationDryRun bool
 }
 
 type dirFS struct {
@@ -260,6 +308,7 @@ func (d *dirFS) Open(path string) (fs.File, error) {
 
 // New returns a new DB.  All fields and indexes are initialized
 // and possible conflicts with schema from existing database is checked.
+// If the path is empty, the DB is kept in memory, see NewInmem.
 // One goroutine for writing batches is created.
 func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger log.Logger) (db *DB, err error) {
 	if o == nil {
@@ -270,12 +319,17 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 		}
 	}
 
+	if o.MinFreeDisk < 0 || o.MinFreeDisk >= 1 {
+		return nil, fmt.Errorf("invalid free disk space watermark %v", o.MinFreeDisk)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	db = &DB{
 		stateStore:      ss,
 		cacheCapacity:   o.Capacity,
 		reserveCapacity: o.ReserveCapacity,
+		uploadCapacity:  o.UploadCapacity,
 		unreserveFunc:   o.UnreserveFunc,
 		baseKey:         baseKey,
 		tags:            o.Tags,
@@ -290,14 +344,24 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 		close:                     make(chan struct{}),
 		collectGarbageWorkerDone:  make(chan struct{}),
 		reserveEvictionWorkerDone: make(chan struct{}),
+		tieringWorkerDone:         make(chan struct{}),
+		diskWatermarkWorkerDone:   make(chan struct{}),
 		metrics:                   newMetrics(),
 		logger:                    logger.WithName(loggerName).Register(),
 		validStamp:                o.ValidStamp,
 		lock:                      multex.New(),
+		coldAge:                   o.ColdAge,
+		minFreeDisk:               o.MinFreeDisk,
+		diskPath:                  path,
+		migrationDryRun:           o.MigrationDryRun,
+	}
+	if db.coldAge == 0 {
+		db.coldAge = defaultColdAge
 	}
 	if db.cacheCapacity == 0 {
 		db.cacheCapacity = defaultCacheCapacity
 	}
+	db.gcCapacity.Store(db.cacheCapacity)
 
 	capacityMB := float64((db.cacheCapacity+uint64(batchstore.Capacity))*swarm.ChunkSize) * 9.5367431640625e-7
 
@@ -312,10 +376,12 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 	}
 
 	shedOpts := &shed.Options{
+		Backend:                o.Backend,
 		OpenFilesLimit:         o.OpenFilesLimit,
 		BlockCacheCapacity:     o.BlockCacheCapacity,
 		WriteBufferSize:        o.WriteBufferSize,
 		DisableSeeksCompaction: o.DisableSeeksCompaction,
+		GroupCommitLatency:     o.GroupCommitLatency,
 	}
 
 	if withinRadiusFn == nil {
@@ -331,11 +397,10 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 		return nil, err
 	}
 
-	// instantiate sharky instance
-	var sharkyBase fs.FS
+	// instantiate the chunk data store
 	if path == "" {
-		// No need for recovery for in-mem sharky
-		sharkyBase = &memFS{Fs: afero.NewMemMapFs()}
+		// no need for recovery for the in-memory store
+		db.sharky = newInmemBlobStore()
 	} else {
 		sharkyBasePath := filepath.Join(path, "sharky")
 		if _, err := os.Stat(sharkyBasePath); os.IsNotExist(err) {
@@ -344,18 +409,33 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 				return nil, err
 			}
 		}
-		sharkyBase = &dirFS{basedir: sharkyBasePath}
 
-		err = db.safeInit(path, sharkyBasePath)
+		var coldBasePath string
+		if o.ColdPath != "" {
+			coldBasePath = filepath.Join(o.ColdPath, "sharky")
+			if err := os.MkdirAll(coldBasePath, 0775); err != nil {
+				return nil, err
+			}
+		}
+
+		err = db.safeInit(path, sharkyBasePath, coldBasePath)
 		if err != nil {
 			return nil, fmt.Errorf("safe sharky initialization failed: %w", err)
 		}
 		db.fdirtyCloser = func() error { return os.Remove(filepath.Join(path, sharkyDirtyFileName)) }
-	}
 
-	db.sharky, err = sharky.New(sharkyBase, sharkyNoOfShards, swarm.SocMaxChunkSize)
-	if err != nil {
-		return nil, err
+		db.sharky, err = sharky.New(&dirFS{basedir: sharkyBasePath}, sharkyNoOfShards, swarm.SocMaxChunkSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if coldBasePath != "" {
+			cold, err := sharky.New(&dirFS{basedir: coldBasePath}, sharkyNoOfShards, swarm.SocMaxChunkSize)
+			if err != nil {
+				return nil, multierror.Append(err, db.sharky.Close())
+			}
+			db.sharky = &tieredBlobStore{hot: db.sharky, cold: cold}
+		}
 	}
 
 	// Identify current storage schema by arbitrary name.
@@ -367,6 +447,10 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
 		return nil, err
 	}
+	db.migrationCheckpoint, err = db.shed.NewStructField("migration-checkpoint")
+	if err != nil {
+		return nil, err
+	}
 	if schemaName == "" {
 		// initial new localstore run
 		err := db.schemaName.Put(DBSchemaCurrent)
@@ -519,19 +603,21 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 	}
 	// create a push syncing triggers used by SubscribePush function
 	db.pushTriggers = make([]chan<- struct{}, 0)
-	// gc index for removable chunk ordered by ascending last access time
-	db.gcIndex, err = db.shed.NewIndex("AccessTimestamp|BinID|Hash->BatchID|BatchIndex", shed.IndexFuncs{
+	// gc index for removable chunk partitioned by the address prefix
+	// and ordered by ascending last access time within the partition
+	db.gcIndex, err = db.shed.NewIndex("Partition|AccessTimestamp|BinID|Hash->BatchID|BatchIndex", shed.IndexFuncs{
 		EncodeKey: func(fields shed.Item) (key []byte, err error) {
-			b := make([]byte, 16, 16+len(fields.Address))
-			binary.BigEndian.PutUint64(b[:8], uint64(fields.AccessTimestamp))
-			binary.BigEndian.PutUint64(b[8:16], fields.BinID)
+			b := make([]byte, 17, 17+len(fields.Address))
+			b[0] = gcPartition(fields.Address)
+			binary.BigEndian.PutUint64(b[1:9], uint64(fields.AccessTimestamp))
+			binary.BigEndian.PutUint64(b[9:17], fields.BinID)
 			key = append(b, fields.Address...)
 			return key, nil
 		},
 		DecodeKey: func(key []byte) (e shed.Item, err error) {
-			e.AccessTimestamp = int64(binary.BigEndian.Uint64(key[:8]))
-			e.BinID = binary.BigEndian.Uint64(key[8:16])
-			e.Address = key[16:]
+			e.AccessTimestamp = int64(binary.BigEndian.Uint64(key[1:9]))
+			e.BinID = binary.BigEndian.Uint64(key[9:17])
+			e.Address = key[17:]
 			return e, nil
 		},
 		EncodeValue: func(fields shed.Item) (value []byte, err error) {
@@ -649,13 +735,45 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 		return nil, err
 	}
 
+	db.intentIndex, err = db.newIntentIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	db.coldIndex, err = db.newColdIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.resolveIntents(); err != nil {
+		return nil, fmt.Errorf("resolve intents: %w", err)
+	}
+
+	if err := db.checkColdTier(); err != nil {
+		return nil, multierror.Append(err, db.sharky.Close(), db.shed.Close(), db.fdirtyCloser())
+	}
+
+	if err := db.initUploadSize(); err != nil {
+		return nil, multierror.Append(err, db.sharky.Close(), db.shed.Close(), db.fdirtyCloser())
+	}
+
 	// start garbage collection worker
 	go db.collectGarbageWorker()
 	go db.reserveEvictionWorker()
+	if _, ok := db.sharky.(*tieredBlobStore); ok {
+		go db.tieringWorker()
+	} else {
+		close(db.tieringWorkerDone)
+	}
+	if db.minFreeDisk > 0 && path != "" {
+		go db.diskWatermarkWorker()
+	} else {
+		close(db.diskWatermarkWorkerDone)
+	}
 	return db, nil
 }
 
-func (db *DB) safeInit(rootPath, sharkyBasePath string) error {
+func (db *DB) safeInit(rootPath, sharkyBasePath, coldBasePath string) error {
 	// create if needed
 	path := filepath.Join(rootPath, sharkyDirtyFileName)
 	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
@@ -678,26 +796,46 @@ func (db *DB) safeInit(rootPath, sharkyBasePath string) error {
 	if err != nil {
 		return err
 	}
+	recoveries := []*sharky.Recovery{recoverySharky}
+
+	var recoveryCold *sharky.Recovery
+	if coldBasePath != "" {
+		recoveryCold, err = sharky.NewRecovery(coldBasePath, sharkyNoOfShards, swarm.SocMaxChunkSize)
+		if err != nil {
+			return err
+		}
+		recoveries = append(recoveries, recoveryCold)
+	}
 
 	for l := range locOrErr {
 		if l.err != nil {
 			return l.err
 		}
 
-		err = recoverySharky.Add(l.loc)
+		if isColdLocation(l.loc) {
+			if recoveryCold == nil {
+				return fmt.Errorf("chunk stored in the cold tier: %w", errColdTierNotConfigured)
+			}
+			l.loc.Shard &^= coldTierFlag
+			err = recoveryCold.Add(l.loc)
+		} else {
+			err = recoverySharky.Add(l.loc)
+		}
 		if err != nil {
 			return err
 		}
 	}
 
-	err = recoverySharky.Save()
-	if err != nil {
-		return err
-	}
+	for _, r := range recoveries {
+		err = r.Save()
+		if err != nil {
+			return err
+		}
 
-	err = recoverySharky.Close()
-	if err != nil {
-		return err
+		err = r.Close()
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -716,6 +854,8 @@ func (db *DB) Close() error {
 		// return before closing the shed
 		<-db.collectGarbageWorkerDone
 		<-db.reserveEvictionWorkerDone
+		<-db.tieringWorkerDone
+		<-db.diskWatermarkWorkerDone
 		close(done)
 	}()
 
@@ -759,6 +899,8 @@ func (db *DB) DebugIndices() (indexInfo map[string]int, err error) {
 		"postageChunksIndex":   db.postageChunksIndex,
 		"postageRadiusIndex":   db.postageRadiusIndex,
 		"postageIndexIndex":    db.postageIndexIndex,
+		"intentIndex":          db.intentIndex,
+		"coldIndex":            db.coldIndex,
 	} {
 		indexSize, err := v.Count()
 		if err != nil {
@@ -777,10 +919,79 @@ func (db *DB) DebugIndices() (indexInfo map[string]int, err error) {
 		return indexInfo, err
 	}
 	indexInfo["reserveSize"] = int(val)
+	indexInfo["uploadSize"] = int(db.UploadSize())
 
 	return indexInfo, err
 }
 
+// DebugChunk returns the locally stored chunk with its postage stamp, without
+// updating any index, and the names of the indexes which contain the chunk.
+// If the chunk is not stored, storage.ErrNotFound is returned.
+func (db *DB) DebugChunk(ctx context.Context, addr swarm.Address) (swarm.Chunk, []string, error) {
+	item, err := db.get(ctx, storage.ModeGetLookup, addr)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil, storage.ErrNotFound
+		}
+		return nil, nil, err
+	}
+	ch := swarm.NewChunk(addr, item.Data).
+		WithStamp(postage.NewStamp(item.BatchID, item.Index, item.Timestamp, item.Sig))
+
+	indices := []string{"retrievalDataIndex"}
+
+	access, err := db.retrievalAccessIndex.Get(item)
+	switch {
+	case err == nil:
+		item.AccessTimestamp = access.AccessTimestamp
+		indices = append(indices, "retrievalAccessIndex")
+	case !errors.Is(err, leveldb.ErrNotFound):
+		return nil, nil, err
+	}
+
+	for _, i := range []struct {
+		name  string
+		index shed.Index
+	}{
+		{"pushIndex", db.pushIndex},
+		{"pullIndex", db.pullIndex},
+		{"gcIndex", db.gcIndex},
+		{"pinIndex", db.pinIndex},
+		{"postageChunksIndex", db.postageChunksIndex},
+		{"coldIndex", db.coldIndex},
+	} {
+		has, err := i.index.Has(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		if has {
+			indices = append(indices, i.name)
+		}
+	}
+
+	// the keys of the postage index and the intent index are shared
+	// with other chunks, so the stored address has to match as well
+	for _, i := range []struct {
+		name  string
+		index shed.Index
+	}{
+		{"postageIndexIndex", db.postageIndexIndex},
+		{"intentIndex", db.intentIndex},
+	} {
+		v, err := i.index.Get(item)
+		switch {
+		case err == nil:
+			if bytes.Equal(v.Address, item.Address) {
+				indices = append(indices, i.name)
+			}
+		case !errors.Is(err, leveldb.ErrNotFound):
+			return nil, nil, err
+		}
+	}
+
+	return ch, indices, nil
+}
+
 // stateStoreHasPins returns true if the state-store
 // contains any pins, otherwise false is returned.
 func (db *DB) stateStoreHasPins() (bool, error) {
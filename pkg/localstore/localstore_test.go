@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
 	"runtime"
 	"sort"
 	"sync"
@@ -248,7 +249,7 @@ func newTestDB(tb testing.TB, o *Options) *DB {
 		}
 	}
 	logger := log.Noop
-	db, err := New("", baseKey, nil, o, logger)
+	db, err := NewInmem(baseKey, nil, o, logger)
 	if err != nil {
 		tb.Fatal(err)
 	}
@@ -721,3 +722,38 @@ func TestDBDebugIndexes(t *testing.T) {
 
 	testIndexCounts(t, 1, 1, 0, 1, 1, 0, indexCounts)
 }
+
+// TestDBDebugChunk tests that the chunk is returned with the
+// indexes which contain it.
+func TestDBDebugChunk(t *testing.T) {
+	db := newTestDB(t, nil)
+	ctx := context.Background()
+
+	ch := generateTestRandomChunk()
+
+	if _, _, err := db.DebugChunk(ctx, ch.Address()); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+
+	if _, err := db.Put(ctx, storage.ModePutUpload, ch); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Set(ctx, storage.ModeSetPin, ch.Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, indices, err := db.DebugChunk(ctx, ch.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Data(), ch.Data()) {
+		t.Fatal("chunk data mismatch")
+	}
+	if !bytes.Equal(got.Stamp().BatchID(), ch.Stamp().BatchID()) {
+		t.Fatal("chunk stamp mismatch")
+	}
+	want := []string{"retrievalDataIndex", "pushIndex", "pinIndex", "postageChunksIndex", "postageIndexIndex"}
+	if !reflect.DeepEqual(indices, want) {
+		t.Fatalf("got indexes %v, want %v", indices, want)
+	}
+}
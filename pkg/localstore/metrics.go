@@ -34,6 +34,9 @@ type metrics struct {
 	GCUpdate                 prometheus.Counter
 	GCUpdateError            prometheus.Counter
 
+	TieringColdCounter prometheus.Counter
+	TieringHotCounter  prometheus.Counter
+
 	ModeGet                       prometheus.Counter
 	ModeGetFailure                prometheus.Counter
 	ModeGetMulti                  prometheus.Counter
@@ -58,9 +61,12 @@ type metrics struct {
 	SubscribePushIterationFailure prometheus.Counter
 
 	GCSize                  prometheus.Gauge
+	GCCapacity              prometheus.Gauge
 	GCStoreTimeStamps       prometheus.Gauge
 	GCStoreAccessTimeStamps prometheus.Gauge
 
+	UploadSize prometheus.Gauge
+
 	ReserveSize                  prometheus.Gauge
 	EvictReserveCounter          prometheus.Counter
 	EvictReserveErrorCounter     prometheus.Counter
@@ -177,6 +183,18 @@ func newMetrics() metrics {
 			Name:      "gc_collected_count",
 			Help:      "Number of times the GC_COLLECTED operation is done.",
 		}),
+		TieringColdCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "tiering_cold_count",
+			Help:      "Number of chunks moved to the cold tier.",
+		}),
+		TieringHotCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "tiering_hot_count",
+			Help:      "Number of chunks moved back to the hot tier.",
+		}),
 		GCCommittedCounter: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
@@ -347,12 +365,24 @@ func newMetrics() metrics {
 			Help:      "Number of times SUBSCRIBE_PUSH_ITERATION_FAILURE is invoked.",
 		}),
 
+		UploadSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "upload_size",
+			Help:      "Number of the uploaded chunks not synced yet.",
+		}),
 		GCSize: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
 			Name:      "gc_size",
 			Help:      "Number of elements in Garbage collection index.",
 		}),
+		GCCapacity: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "gc_capacity",
+			Help:      "Capacity of the cache adjusted by the free disk space watermark.",
+		}),
 		GCStoreTimeStamps: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
@@ -21,7 +21,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/syndtr/goleveldb/leveldb"
 )
@@ -29,11 +31,36 @@ import (
 var errMissingCurrentSchema = errors.New("could not find current db schema")
 var errMissingTargetSchema = errors.New("could not find target db schema")
 
+// ErrMigrationDryRun is returned by New in the migration dry run mode
+// after the pending migrations are estimated.
+var ErrMigrationDryRun = errors.New("localstore migration dry run")
+
+// migrationProgressInterval is the minimal interval
+// between the progress reports of a running migration.
+var migrationProgressInterval = 30 * time.Second
+
 type migration struct {
 	schemaName string
 	// The migration function that needs to be performed
 	// in order to get to the current schema name.
 	fn func(db *DB) error
+	// The optional estimate of the work of the migration
+	// which is reported by the dry run.
+	estimate func(db *DB) (migrationEstimate, error)
+}
+
+// migrationEstimate is the estimated work of a migration.
+type migrationEstimate struct {
+	items    int           // number of the entries to migrate
+	space    int64         // additional disk space in bytes
+	duration time.Duration // expected duration on the average hardware
+}
+
+// migrationCheckpoint is the persisted progress of the running migration,
+// with it the interrupted migration continues where it has stopped.
+type migrationCheckpoint struct {
+	Schema string `json:"schema"`
+	Done   int    `json:"done"`
 }
 
 // schemaMigrations contains an ordered list of the database schemes, that is
@@ -43,10 +70,11 @@ var schemaMigrations = []migration{
 	{schemaName: DBSchemaYuj, fn: migrateYuj},
 	{schemaName: DBSchemaBatchIndex, fn: migrateBatchIndex},
 	{schemaName: DBSchemaDeadPush, fn: migrateDeadPush},
-	{schemaName: DBSchemaSharky, fn: migrateSharky},
+	{schemaName: DBSchemaSharky, fn: migrateSharky, estimate: estimateSharky},
 	{schemaName: DBSchemaCatharsis, fn: migrateCatharsis},
 	{schemaName: DBSchemaDeadPostageIndex, fn: migrateDeadPostageIndex},
 	{schemaName: DBSchemaResidue, fn: migrateResidue},
+	{schemaName: DBSchemaGCPartition, fn: migrateGCPartition},
 }
 
 func (db *DB) migrate(schemaName string) error {
@@ -59,6 +87,10 @@ func (db *DB) migrate(schemaName string) error {
 		return nil
 	}
 
+	if db.migrationDryRun {
+		return db.estimateMigrations(migrations)
+	}
+
 	db.logger.Info("localstore migration: need to run data migrations on localstore", "total", len(migrations), "schema", schemaName)
 	db.logger.Info("localstore migration: warning: if one of the migration fails it wouldn't be possible to downgrade back to the old version")
 	for i, migration := range migrations {
@@ -68,6 +100,9 @@ func (db *DB) migrate(schemaName string) error {
 		if err = db.schemaName.Put(migration.schemaName); err != nil {
 			return err
 		}
+		if err = db.migrationCheckpoint.Put(migrationCheckpoint{}); err != nil {
+			return err
+		}
 		if schemaName, err = db.schemaName.Get(); err != nil {
 			return err
 		}
@@ -76,6 +111,94 @@ func (db *DB) migrate(schemaName string) error {
 	return nil
 }
 
+// estimateMigrations logs the estimated work of the migrations
+// and returns ErrMigrationDryRun without running them.
+func (db *DB) estimateMigrations(migrations []migration) error {
+	var total migrationEstimate
+	for _, m := range migrations {
+		if m.estimate == nil {
+			db.logger.Info("localstore migration: dry run: no estimate", "schema", m.schemaName)
+			continue
+		}
+		e, err := m.estimate(db)
+		if err != nil {
+			return fmt.Errorf("estimate migration %q: %w", m.schemaName, err)
+		}
+		done, err := db.migrationDone(m.schemaName)
+		if err != nil {
+			return err
+		}
+		db.logger.Info("localstore migration: dry run", "schema", m.schemaName, "items", e.items, "already_migrated", done, "space_mb", e.space>>20, "duration", e.duration)
+		total.items += e.items
+		total.space += e.space
+		total.duration += e.duration
+	}
+	db.logger.Info("localstore migration: dry run: migrations not run", "total", len(migrations), "items", total.items, "space_mb", total.space>>20, "duration", total.duration)
+	return ErrMigrationDryRun
+}
+
+// migrationDone returns the number of the entries migrated by
+// the interrupted previous run of the migration to the schema.
+func (db *DB) migrationDone(schemaName string) (int, error) {
+	var c migrationCheckpoint
+	if err := db.migrationCheckpoint.Get(&c); err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("get migration checkpoint: %w", err)
+	}
+	if c.Schema != schemaName {
+		return 0, nil
+	}
+	return c.Done, nil
+}
+
+// migrationProgress reports the progress of a long running migration
+// at most once per migrationProgressInterval.
+type migrationProgress struct {
+	logger     log.Logger
+	schemaName string
+	total      int
+	resumed    int
+	start      time.Time
+	last       time.Time
+}
+
+func newMigrationProgress(logger log.Logger, schemaName string, done, total int) *migrationProgress {
+	now := time.Now()
+	return &migrationProgress{
+		logger:     logger,
+		schemaName: schemaName,
+		total:      total,
+		resumed:    done,
+		start:      now,
+		last:       now,
+	}
+}
+
+// update logs the number of the migrated entries
+// with the estimated remaining time.
+func (p *migrationProgress) update(done int) {
+	now := time.Now()
+	if now.Sub(p.last) < migrationProgressInterval {
+		return
+	}
+	p.last = now
+
+	var (
+		elapsed   = now.Sub(p.start)
+		percent   = 100.0
+		remaining time.Duration
+	)
+	if p.total > 0 {
+		percent = float64(done) * 100 / float64(p.total)
+	}
+	if n := done - p.resumed; n > 0 && done < p.total {
+		remaining = time.Duration(float64(elapsed) / float64(n) * float64(p.total-done))
+	}
+	p.logger.Info("localstore migration: progress", "schema", p.schemaName, "done", done, "total", p.total, "percent", fmt.Sprintf("%.1f", percent), "elapsed", elapsed.Round(time.Second), "remaining", remaining.Round(time.Second))
+}
+
 // getMigrations returns an ordered list of migrations that need be executed
 // with no errors in order to bring the localstore to the most up-to-date
 // schema definition
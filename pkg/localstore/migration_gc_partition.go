@@ -0,0 +1,97 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// DBSchemaGCPartition is the bee schema identifier for the partitioned gcIndex.
+const DBSchemaGCPartition = "gc-partition"
+
+// migrateGCPartition moves the gcIndex entries to the index
+// which is partitioned by the address prefix.
+func migrateGCPartition(db *DB) error {
+	db.logger.Info("starting gc partition migration")
+	start := time.Now()
+
+	gcIndexFuncs := func(partitioned bool) shed.IndexFuncs {
+		headerSize := 16
+		if partitioned {
+			headerSize = 17
+		}
+		return shed.IndexFuncs{
+			EncodeKey: func(fields shed.Item) (key []byte, err error) {
+				b := make([]byte, headerSize, headerSize+len(fields.Address))
+				if partitioned {
+					b[0] = gcPartition(fields.Address)
+				}
+				binary.BigEndian.PutUint64(b[headerSize-16:headerSize-8], uint64(fields.AccessTimestamp))
+				binary.BigEndian.PutUint64(b[headerSize-8:headerSize], fields.BinID)
+				key = append(b, fields.Address...)
+				return key, nil
+			},
+			DecodeKey: func(key []byte) (e shed.Item, err error) {
+				e.AccessTimestamp = int64(binary.BigEndian.Uint64(key[headerSize-16 : headerSize-8]))
+				e.BinID = binary.BigEndian.Uint64(key[headerSize-8 : headerSize])
+				e.Address = key[headerSize:]
+				return e, nil
+			},
+			EncodeValue: func(fields shed.Item) (value []byte, err error) {
+				value = make([]byte, 40)
+				copy(value, fields.BatchID)
+				copy(value[32:], fields.Index)
+				return value, nil
+			},
+			DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+				e.BatchID = make([]byte, 32)
+				copy(e.BatchID, value[:32])
+				e.Index = make([]byte, postage.IndexSize)
+				copy(e.Index, value[32:])
+				return e, nil
+			},
+		}
+	}
+
+	gcIndex, err := db.shed.NewIndex("AccessTimestamp|BinID|Hash->BatchID|BatchIndex", gcIndexFuncs(false))
+	if err != nil {
+		return fmt.Errorf("failed to instantiate gcIndex: %w", err)
+	}
+	partitionedGCIndex, err := db.shed.NewIndex("Partition|AccessTimestamp|BinID|Hash->BatchID|BatchIndex", gcIndexFuncs(true))
+	if err != nil {
+		return fmt.Errorf("failed to instantiate partitioned gcIndex: %w", err)
+	}
+
+	batch := new(leveldb.Batch)
+	count := 0
+
+	err = gcIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+		if err = partitionedGCIndex.PutInBatch(batch, item); err != nil {
+			return true, err
+		}
+		if err = gcIndex.DeleteInBatch(batch, item); err != nil {
+			return true, err
+		}
+		count++
+		return false, nil
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("iterate index: %w", err)
+	}
+
+	err = db.shed.WriteBatch(batch)
+	if err != nil {
+		return fmt.Errorf("write batch: %w", err)
+	}
+
+	db.logger.Info("gc partition migration done", "elapsed", time.Since(start), "moved", count)
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/sharky"
 	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/hashicorp/go-multierror"
 	"github.com/syndtr/goleveldb/leveldb"
 )
@@ -20,28 +21,15 @@ import (
 // DBSchemaSharky is the bee schema identifier for sharky.
 const DBSchemaSharky = "sharky"
 
-// migrateSharky writes the new retrievalDataIndex format by storing chunk data in sharky
-func migrateSharky(db *DB) error {
-	db.logger.Info("starting sharky migration; have patience, this might take a while...")
-	var (
-		start          = time.Now()
-		batch          = new(leveldb.Batch)
-		batchSize      = 10000
-		batchesCount   = 0
-		headerSize     = 16 + postage.StampSize
-		compactionRate = 100
-		compactionSize = batchSize * compactionRate
-	)
-
-	compaction := func(start, end []byte) (time.Duration, error) {
-		compactStart := time.Now()
-		if err := db.shed.Compact(start, end); err != nil {
-			return 0, fmt.Errorf("leveldb compaction failed: %w", err)
-		}
-		return time.Since(compactStart), nil
-	}
+// sharkyMigrationRate is the approximate number of
+// the chunks moved to sharky per second.
+const sharkyMigrationRate = 2000
 
-	retrievalDataIndex, err := db.shed.NewIndex("Address->StoreTimestamp|BinID|BatchID|BatchIndex|Sig|Data", shed.IndexFuncs{
+// sharkyLegacyRetrievalDataIndex returns the retrievalDataIndex
+// which stores the chunk data in leveldb.
+func sharkyLegacyRetrievalDataIndex(db *DB) (shed.Index, error) {
+	headerSize := 16 + postage.StampSize
+	return db.shed.NewIndex("Address->StoreTimestamp|BinID|BatchID|BatchIndex|Sig|Data", shed.IndexFuncs{
 		EncodeKey: func(fields shed.Item) (key []byte, err error) {
 			return fields.Address, nil
 		},
@@ -76,6 +64,52 @@ func migrateSharky(db *DB) error {
 			return e, nil
 		},
 	})
+}
+
+// estimateSharky estimates the work of the sharky migration
+// from the number of the chunks which are still stored in leveldb.
+func estimateSharky(db *DB) (migrationEstimate, error) {
+	retrievalDataIndex, err := sharkyLegacyRetrievalDataIndex(db)
+	if err != nil {
+		return migrationEstimate{}, err
+	}
+	n, err := retrievalDataIndex.Count()
+	if err != nil {
+		return migrationEstimate{}, err
+	}
+	return migrationEstimate{
+		items: n,
+		// the chunk data of the leveldb is freed only
+		// by the compaction after the data is moved
+		space:    int64(n) * swarm.SocMaxChunkSize,
+		duration: time.Duration(n) * time.Second / sharkyMigrationRate,
+	}, nil
+}
+
+// migrateSharky writes the new retrievalDataIndex format by storing chunk data in sharky.
+// The progress is checkpointed with every written batch, so that the interrupted
+// migration continues with the chunks which are not moved yet.
+func migrateSharky(db *DB) error {
+	db.logger.Info("starting sharky migration; have patience, this might take a while...")
+	var (
+		start          = time.Now()
+		batch          = new(leveldb.Batch)
+		batchSize      = 10000
+		batchesCount   = 0
+		headerSize     = 16 + postage.StampSize
+		compactionRate = 100
+		compactionSize = batchSize * compactionRate
+	)
+
+	compaction := func(start, end []byte) (time.Duration, error) {
+		compactStart := time.Now()
+		if err := db.shed.Compact(start, end); err != nil {
+			return 0, fmt.Errorf("leveldb compaction failed: %w", err)
+		}
+		return time.Since(compactStart), nil
+	}
+
+	retrievalDataIndex, err := sharkyLegacyRetrievalDataIndex(db)
 	if err != nil {
 		return err
 	}
@@ -135,6 +169,19 @@ func migrateSharky(db *DB) error {
 		compactStart, compactEnd *shed.Item
 	)
 
+	batchesCount, err = db.migrationDone(DBSchemaSharky)
+	if err != nil {
+		return err
+	}
+	if batchesCount > 0 {
+		db.logger.Info("resuming the interrupted sharky migration", "migrated_chunks", batchesCount)
+	}
+	remaining, err := retrievalDataIndex.Count()
+	if err != nil {
+		return fmt.Errorf("count index: %w", err)
+	}
+	progress := newMigrationProgress(db.logger, DBSchemaSharky, batchesCount, batchesCount+remaining)
+
 	db.logger.Debug("starting to move entries", "batch_size", batchSize)
 	for {
 		isBatchEmpty := true
@@ -175,6 +222,10 @@ func migrateSharky(db *DB) error {
 			break
 		}
 
+		err = db.migrationCheckpoint.PutInBatch(batch, migrationCheckpoint{Schema: DBSchemaSharky, Done: batchesCount})
+		if err != nil {
+			return fmt.Errorf("checkpoint: %w", err)
+		}
 		if err := db.shed.WriteBatch(batch); err != nil {
 			for _, loc := range dirtyLocations {
 				err = multierror.Append(err, db.sharky.Release(context.TODO(), loc))
@@ -184,6 +235,7 @@ func migrateSharky(db *DB) error {
 		dirtyLocations = nil
 		db.logger.Debug("flush ok; batch of chunks migrated", "count", batchesCount)
 		batch.Reset()
+		progress.update(batchesCount)
 
 		if batchesCount%compactionSize == 0 {
 			db.logger.Debug("starting intermediate compaction")
@@ -18,6 +18,7 @@
 package localstore
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -281,3 +282,133 @@ func TestMigrationErrorTo(t *testing.T) {
 		t.Error("migration ran but shouldnt have")
 	}
 }
+
+// TestMigrationDryRun checks that the dry run only estimates the pending migrations.
+func TestMigrationDryRun(t *testing.T) {
+	defer func(v []migration, s string) {
+		schemaMigrations = v
+		DBSchemaCurrent = s
+	}(schemaMigrations, DBSchemaCurrent)
+
+	DBSchemaCurrent = DBSchemaCode
+	dbSchemaNext := "dbSchemaNext"
+
+	ran, estimated := false, false
+	schemaMigrations = []migration{
+		{schemaName: DBSchemaCode, fn: func(db *DB) error { return nil }},
+		{schemaName: dbSchemaNext, fn: func(db *DB) error {
+			ran = true
+			return nil
+		}, estimate: func(db *DB) (migrationEstimate, error) {
+			estimated = true
+			return migrationEstimate{items: 10}, nil
+		}},
+	}
+
+	dir := t.TempDir()
+	baseKey := testutil.RandBytes(t, 32)
+	logger := log.Noop
+
+	db, err := New(dir, baseKey, nil, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	DBSchemaCurrent = dbSchemaNext
+
+	_, err = New(dir, baseKey, nil, &Options{MigrationDryRun: true}, logger)
+	if !errors.Is(err, ErrMigrationDryRun) {
+		t.Fatalf("got error %v, want %v", err, ErrMigrationDryRun)
+	}
+	if !estimated {
+		t.Error("migration was not estimated")
+	}
+	if ran {
+		t.Error("migration ran in the dry run")
+	}
+
+	db, err = New(dir, baseKey, nil, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ran {
+		t.Error("expected migration did not run")
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestMigrationCheckpoint checks that the interrupted migration
+// gets the checkpoint of its previous run.
+func TestMigrationCheckpoint(t *testing.T) {
+	defer func(v []migration, s string) {
+		schemaMigrations = v
+		DBSchemaCurrent = s
+	}(schemaMigrations, DBSchemaCurrent)
+
+	DBSchemaCurrent = DBSchemaCode
+	dbSchemaNext := "dbSchemaNext"
+
+	var (
+		runs []int
+		fail = true
+	)
+	schemaMigrations = []migration{
+		{schemaName: DBSchemaCode, fn: func(db *DB) error { return nil }},
+		{schemaName: dbSchemaNext, fn: func(db *DB) error {
+			done, err := db.migrationDone(dbSchemaNext)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, done)
+			if fail {
+				if err := db.migrationCheckpoint.Put(migrationCheckpoint{Schema: dbSchemaNext, Done: 42}); err != nil {
+					return err
+				}
+				return errors.New("interrupted")
+			}
+			return nil
+		}},
+	}
+
+	dir := t.TempDir()
+	baseKey := testutil.RandBytes(t, 32)
+	logger := log.Noop
+
+	db, err := New(dir, baseKey, nil, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	DBSchemaCurrent = dbSchemaNext
+
+	if _, err := New(dir, baseKey, nil, nil, logger); err == nil {
+		t.Fatal("expected the interrupted migration to fail")
+	}
+
+	fail = false
+	db, err = New(dir, baseKey, nil, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 42}; len(runs) != len(want) || runs[0] != want[0] || runs[1] != want[1] {
+		t.Errorf("got migration runs from %v, want %v", runs, want)
+	}
+	done, err := db.migrationDone(dbSchemaNext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done != 0 {
+		t.Errorf("got checkpoint %d after the migration, want 0", done)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
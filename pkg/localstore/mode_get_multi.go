@@ -28,8 +28,13 @@ import (
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/syndtr/goleveldb/leveldb"
+	"golang.org/x/sync/errgroup"
 )
 
+// maxParallelSharkyReads is the maximum number of the concurrent
+// sharky reads of the chunks requested by a single GetMulti.
+const maxParallelSharkyReads = 16
+
 // GetMulti returns chunks from the database. If one of the chunks is not found
 // storage.ErrNotFound will be returned. All required indexes will be updated
 // required by the Getter Mode. GetMulti is required to implement chunk.Store
@@ -73,17 +78,22 @@ func (db *DB) getMulti(ctx context.Context, mode storage.ModeGet, addrs ...swarm
 		return nil, err
 	}
 
-	for i, item := range out {
-		l, err := sharky.LocationFromBinary(item.Location)
-		if err != nil {
-			return nil, err
-		}
-
-		out[i].Data = make([]byte, l.Length)
-		err = db.sharky.Read(ctx, l, out[i].Data)
-		if err != nil {
-			return nil, err
-		}
+	// the locations are independent, so they are read concurrently
+	eg, ectx := errgroup.WithContext(ctx)
+	eg.SetLimit(maxParallelSharkyReads)
+	for i := range out {
+		i := i
+		eg.Go(func() error {
+			l, err := sharky.LocationFromBinary(out[i].Location)
+			if err != nil {
+				return err
+			}
+			out[i].Data = make([]byte, l.Length)
+			return db.sharky.Read(ectx, l, out[i].Data)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
 	}
 
 	switch mode {
@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/ethersphere/bee/pkg/sharky"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -53,10 +52,12 @@ func (db *DB) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 	return exist, err
 }
 
-type releaseLocations []sharky.Location
+// releaseLocations holds the items whose sharky locations are released
+// once the batch removing them is committed.
+type releaseLocations []shed.Item
 
-func (r *releaseLocations) add(loc sharky.Location) {
-	*r = append(*r, loc)
+func (r *releaseLocations) add(item shed.Item) {
+	*r = append(*r, item)
 }
 
 // put stores Chunks to database and updates other indexes. It acquires batchMu
@@ -81,7 +82,8 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 	// variables that provide information for operations
 	// to be done after write batch function successfully executes
 	var (
-		gcSizeChange int64 // number to add or subtract from gcSize
+		gcSizeChange     int64 // number to add or subtract from gcSize
+		uploadSizeChange int64 // number to add or subtract from uploadSize
 	)
 	var triggerPushFeed bool                    // signal push feed subscriptions to iterate
 	triggerPullFeed := make(map[uint8]struct{}) // signal pull feed subscriptions to iterate
@@ -100,7 +102,7 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 		releaseLocs = new(releaseLocations)
 		// this is the list of locations that need to be released if the batch is NOT
 		// successfully committed as they have already been committed to sharky
-		committedLocations []sharky.Location
+		committedLocations []shed.Item
 	)
 
 	putChunk := func(ch swarm.Chunk, index int, putOp func(shed.Item, bool) (int64, error)) (bool, int64, error) {
@@ -115,7 +117,7 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 		}
 		if errors.Is(err, leveldb.ErrNotFound) {
 			// This is a new chunk so add to sharky. Also check for double issuance.
-			gcChange, err := db.checkAndRemoveStampIndex(item, batch, releaseLocs)
+			gcChange, uploadChange, err := db.checkAndRemoveStampIndex(item, batch, releaseLocs)
 			if err != nil {
 				if errors.Is(err, ErrOverwrite) && mode == storage.ModePutSync {
 					// if the chunk is overwriting a newer valid chunk for the
@@ -125,12 +127,14 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 				}
 				return false, 0, err
 			}
+			uploadSizeChange += uploadChange
 			l, err := db.sharky.Write(ctx, item.Data)
 			if err != nil {
 				return false, 0, fmt.Errorf("failed writing to sharky: %w", err)
 			}
-			committedLocations = append(committedLocations, l)
+			// the location is recorded for the release before anything can fail
 			item.Location, err = l.MarshalBinary()
+			committedLocations = append(committedLocations, item)
 			if err != nil {
 				return false, 0, fmt.Errorf("failed serializing sharky location: %w", err)
 			}
@@ -157,13 +161,11 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 	// the chunks that have been committed to sharky
 	defer func() {
 		if retErr != nil {
-			for _, l := range committedLocations {
-				// the passed in context could be expired or cancelled, causing a leak by not relesing the
-				// already committed chunks, so we use an empty context
-				err := db.sharky.Release(context.Background(), l)
-				if err != nil {
-					db.logger.Warning("failed releasing sharky location on error", "error", err)
-				}
+			// the passed in context could be expired or cancelled, causing a leak by not relesing the
+			// already committed chunks, so we use an empty context
+			err := db.releaseIntents(context.Background(), committedLocations)
+			if err != nil {
+				db.logger.Warning("failed releasing sharky location on error", "error", err)
 			}
 		}
 	}()
@@ -193,6 +195,16 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 		for i, ch := range chs {
 			pin := mode == storage.ModePutUploadPin
 			exists, c, err := putChunk(ch, i, func(item shed.Item, exists bool) (int64, error) {
+				pending := false
+				if exists {
+					var err error
+					if pending, err = db.uploadPending(item); err != nil {
+						return 0, err
+					}
+				}
+				if !pending {
+					uploadSizeChange++
+				}
 				return db.putUpload(batch, binIDs, item, pin, exists)
 			})
 			if err != nil {
@@ -206,6 +218,9 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 			}
 			gcSizeChange += c
 		}
+		if err := db.checkUploadCapacity(uploadSizeChange); err != nil {
+			return nil, err
+		}
 
 	case storage.ModePutSync:
 		db.lock.Lock(lockKeyGC)
@@ -240,16 +255,28 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 		return nil, fmt.Errorf("inc gc: %w", err)
 	}
 
+	// the written locations need no intents: they are released on error, and
+	// after a crash before the batch is committed the sharky recovery frees
+	// the slots which are not referenced by the retrieval index. A stale
+	// intent for a reused slot is cleared by the batch.
+	err = db.clearIntentsInBatch(batch, committedLocations)
+	if err != nil {
+		return nil, fmt.Errorf("clear intents: %w", err)
+	}
+	err = db.logIntentsInBatch(batch, *releaseLocs)
+	if err != nil {
+		return nil, fmt.Errorf("log intents: %w", err)
+	}
+
 	err = db.shed.WriteBatch(batch)
 	if err != nil {
 		return nil, fmt.Errorf("write batch: %w", err)
 	}
+	db.incUploadSize(uploadSizeChange)
 
-	for _, v := range *releaseLocs {
-		err = db.sharky.Release(ctx, v)
-		if err != nil {
-			db.logger.Warning("failed releasing sharky location", "location", v)
-		}
+	err = db.releaseIntents(ctx, *releaseLocs)
+	if err != nil {
+		db.logger.Warning("failed releasing sharky locations", "error", err)
 	}
 
 	for po := range triggerPullFeed {
@@ -266,27 +293,27 @@ func (db *DB) put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 // return error, if the batch is not immutable we replace the index to point to the
 // new chunk if the timestamp of the new chunk is later.
 // If the index is not taken, we do nothing. This is done to guard against
-// overissuance of batches.
+// overissuance of batches. The upload of the replaced chunk is aborted.
 func (db *DB) checkAndRemoveStampIndex(
 	item shed.Item,
 	batch *leveldb.Batch,
 	loc *releaseLocations,
-) (int64, error) {
+) (gcSizeChange, uploadSizeChange int64, err error) {
 	previous, err := db.postageIndexIndex.Get(item)
 	if errors.Is(err, leveldb.ErrNotFound) {
-		return 0, nil
+		return 0, 0, nil
 	}
 	if err != nil {
-		return 0, fmt.Errorf("failed reading postageIndexIndex: %w", err)
+		return 0, 0, fmt.Errorf("failed reading postageIndexIndex: %w", err)
 	}
 	if item.Immutable {
-		return 0, ErrOverwriteImmutable
+		return 0, 0, ErrOverwriteImmutable
 	}
 	// if a chunk is found with the same postage stamp index,
 	// replace it with the new one only if timestamp is later
 	if prev, cur := timestamps(previous, item); prev >= cur {
 		db.logger.Warning("postage stamp index exists", "prev", prev, "cur", cur, "chunk_address", hex.EncodeToString(item.Address))
-		return 0, ErrOverwrite
+		return 0, 0, ErrOverwrite
 	}
 
 	// remove older chunk
@@ -297,23 +324,23 @@ func (db *DB) checkAndRemoveStampIndex(
 			// due to a bug found recently. This error is mainly ignored as the
 			// chunk is already gone and the index is overwritten.
 			db.logger.Debug("old postage stamp index missing", "Address", swarm.NewAddress(previous.Address))
-			return 0, nil
+			return 0, 0, nil
 		}
-		return 0, fmt.Errorf("could not fetch previous item: %w", err)
+		return 0, 0, fmt.Errorf("could not fetch previous item: %w", err)
 	}
 
-	gcSizeChange, err := db.setRemove(batch, previousIdx, true)
+	uploadSizeChange, err = db.removeUpload(batch, previousIdx)
 	if err != nil {
-		return 0, fmt.Errorf("setRemove on double issuance: %w", err)
+		return 0, 0, fmt.Errorf("remove upload on double issuance: %w", err)
 	}
-
-	l, err := sharky.LocationFromBinary(previousIdx.Location)
+	gcSizeChange, err = db.setRemove(batch, previousIdx, true)
 	if err != nil {
-		return 0, fmt.Errorf("failed getting location: %w", err)
+		return 0, 0, fmt.Errorf("setRemove on double issuance: %w", err)
 	}
-	loc.add(l)
 
-	return gcSizeChange, nil
+	loc.add(previousIdx)
+
+	return gcSizeChange, uploadSizeChange, nil
 }
 
 // putRequest adds an Item to the batch by updating required indexes:
@@ -491,9 +518,29 @@ func (db *DB) putSync(
 	return db.setPin(batch, item)
 }
 
+// addToCache adds the chunk to the cache,
+// unless it is pinned or its upload is pending.
 func (db *DB) addToCache(
 	batch *leveldb.Batch,
 	item shed.Item,
+) (int64, error) {
+	// the uploads are not garbage collected until they are synced
+	pending, err := db.uploadPending(item)
+	if err != nil {
+		return 0, fmt.Errorf("failed checking pushIndex: %w", err)
+	}
+	if pending {
+		return 0, nil
+	}
+	return db.addSyncedToCache(batch, item)
+}
+
+// addSyncedToCache adds the chunk which is not in the upload store,
+// or which is removed from it by the batch, to the cache, unless it
+// is pinned.
+func (db *DB) addSyncedToCache(
+	batch *leveldb.Batch,
+	item shed.Item,
 ) (int64, error) {
 	// add new entry to gc index ONLY if it is not present in pinIndex
 	ok, err := db.pinIndex.Has(item)
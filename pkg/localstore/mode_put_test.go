@@ -756,8 +756,12 @@ func TestPutDuplicateChunks(t *testing.T) {
 func TestReleaseLocations(t *testing.T) {
 	locs := new(releaseLocations)
 
+	loc, err := (&sharky.Location{Shard: 0, Slot: 100, Length: 100}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
 	for i := 0; i < 5; i++ {
-		locs.add(sharky.Location{Shard: 0, Slot: 100, Length: 100})
+		locs.add(shed.Item{Location: loc})
 	}
 
 	if len(*locs) != 5 {
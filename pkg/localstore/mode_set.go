@@ -22,12 +22,10 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/ethersphere/bee/pkg/sharky"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
-	"github.com/hashicorp/go-multierror"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -56,12 +54,13 @@ func (db *DB) set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Addr
 	db.lock.Unlock(lockKeyGC)
 
 	batch := new(leveldb.Batch)
-	var committedLocations []sharky.Location
+	var committedLocations []shed.Item
 
 	// variables that provide information for operations
 	// to be done after write batch function successfully executes
 	var (
-		gcSizeChange int64 // number to add or subtract from gcSize
+		gcSizeChange     int64 // number to add or subtract from gcSize
+		uploadSizeChange int64 // number to add or subtract from uploadSize
 	)
 	triggerPullFeed := make(map[uint8]struct{}) // signal pull feed subscriptions to iterate
 
@@ -72,11 +71,12 @@ func (db *DB) set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Addr
 		defer db.lock.Unlock(lockKeyGC)
 
 		for _, addr := range addrs {
-			c, err := db.setSync(batch, addr)
+			c, u, err := db.setSync(batch, addr)
 			if err != nil {
 				return err
 			}
 			gcSizeChange += c
+			uploadSizeChange += u
 		}
 	case storage.ModeSetRemove:
 		db.lock.Lock(lockKeyGC)
@@ -88,15 +88,16 @@ func (db *DB) set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Addr
 			if err != nil {
 				return err
 			}
-			c, err := db.setRemove(batch, storedItem, true)
+			u, err := db.removeUpload(batch, storedItem)
 			if err != nil {
 				return err
 			}
-			l, err := sharky.LocationFromBinary(storedItem.Location)
+			uploadSizeChange += u
+			c, err := db.setRemove(batch, storedItem, true)
 			if err != nil {
 				return err
 			}
-			committedLocations = append(committedLocations, l)
+			committedLocations = append(committedLocations, storedItem)
 			gcSizeChange += c
 		}
 	case storage.ModeSetPin:
@@ -122,6 +123,25 @@ func (db *DB) set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Addr
 			}
 			gcSizeChange += c
 		}
+	case storage.ModeSetPurge:
+		db.lock.Lock(lockKeyGC)
+		defer db.lock.Unlock(lockKeyGC)
+
+		for _, addr := range addrs {
+			u, err := db.removeUpload(batch, addressToItem(addr))
+			if err != nil {
+				return err
+			}
+			uploadSizeChange += u
+			c, item, err := db.setPurge(batch, addr)
+			if err != nil {
+				return err
+			}
+			if item != nil {
+				committedLocations = append(committedLocations, *item)
+			}
+			gcSizeChange += c
+		}
 	default:
 		return ErrInvalidMode
 	}
@@ -131,17 +151,20 @@ func (db *DB) set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Addr
 		return err
 	}
 
-	err = db.shed.WriteBatch(batch)
+	err = db.logIntentsInBatch(batch, committedLocations)
 	if err != nil {
 		return err
 	}
 
-	sharkyErr := new(multierror.Error)
-	for _, l := range committedLocations {
-		sharkyErr = multierror.Append(sharkyErr, db.sharky.Release(ctx, l))
+	err = db.shed.WriteBatch(batch)
+	if err != nil {
+		return err
 	}
-	if sharkyErr.ErrorOrNil() != nil {
-		return sharkyErr.ErrorOrNil()
+	db.incUploadSize(uploadSizeChange)
+
+	err = db.releaseIntents(ctx, committedLocations)
+	if err != nil {
+		return err
 	}
 
 	for po := range triggerPullFeed {
@@ -152,11 +175,11 @@ func (db *DB) set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Addr
 
 // setSync adds the chunk to the garbage collection after syncing by updating indexes
 //   - ModeSetSync - the corresponding tag is incremented, then item is removed
-//     from push sync index
+//     from push sync index, the upload store
 //   - update to gc index happens given item does not exist in pin index
 //
 // Provided batch is updated.
-func (db *DB) setSync(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange int64, err error) {
+func (db *DB) setSync(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange, uploadSizeChange int64, err error) {
 	item := addressToItem(addr)
 
 	// need to get access timestamp here as it is not
@@ -172,11 +195,11 @@ func (db *DB) setSync(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange in
 			// if it is there
 			err = db.pushIndex.DeleteInBatch(batch, item)
 			if err != nil {
-				return 0, err
+				return 0, 0, err
 			}
-			return 0, nil
+			return 0, 0, nil
 		}
-		return 0, err
+		return 0, 0, err
 	}
 	item.StoreTimestamp = i.StoreTimestamp
 	item.BinID = i.BinID
@@ -192,8 +215,10 @@ func (db *DB) setSync(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange in
 			// but this function is called with ModeSetSync
 			db.logger.Debug("chunk not found in push index", "address", addr)
 		} else {
-			return 0, err
+			return 0, 0, err
 		}
+	} else {
+		uploadSizeChange = -1
 	}
 	if err == nil && db.tags != nil && i.Tag != 0 {
 		t, err := db.tags.Get(i.Tag)
@@ -204,34 +229,40 @@ func (db *DB) setSync(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange in
 		} else {
 			err = t.Inc(tags.StateSynced)
 			if err != nil {
-				return 0, err
+				return 0, 0, err
 			}
 		}
 	}
 
 	err = db.pushIndex.DeleteInBatch(batch, item)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
 	i1, err := db.retrievalAccessIndex.Get(item)
 	if err != nil {
 		if !errors.Is(err, leveldb.ErrNotFound) {
-			return 0, err
+			return 0, 0, err
 		}
 		item.AccessTimestamp = now()
 		err := db.retrievalAccessIndex.PutInBatch(batch, item)
 		if err != nil {
-			return 0, err
+			return 0, 0, err
 		}
 	} else {
 		item.AccessTimestamp = i1.AccessTimestamp
 	}
-	return db.addToCache(batch, item)
+	gcSizeChange, err = db.addSyncedToCache(batch, item)
+	if err != nil {
+		return 0, 0, err
+	}
+	return gcSizeChange, uploadSizeChange, nil
 }
 
 // setRemove removes the chunk by updating indexes:
 //   - delete from retrieve, pull, gc
+//   - the upload store is not updated, the chunk must be removed from
+//     it with removeUpload in the same batch
 //
 // Provided batch is updated.
 func (db *DB) setRemove(batch *leveldb.Batch, item shed.Item, check bool) (gcSizeChange int64, err error) {
@@ -264,10 +295,6 @@ func (db *DB) setRemove(batch *leveldb.Batch, item shed.Item, check bool) (gcSiz
 	if err != nil {
 		return 0, err
 	}
-	err = db.pushIndex.DeleteInBatch(batch, item)
-	if err != nil {
-		return 0, err
-	}
 	err = db.pullIndex.DeleteInBatch(batch, item)
 	if err != nil {
 		return 0, err
@@ -296,6 +323,43 @@ func (db *DB) setRemove(batch *leveldb.Batch, item shed.Item, check bool) (gcSiz
 	return -1, nil
 }
 
+// setPurge removes the chunk uploaded or cached locally by updating indexes:
+//   - the chunk must be removed from the upload store with removeUpload
+//     in the same batch, so that it is not push synced anymore
+//   - delete from retrieve, pull, gc unless the chunk is pinned or it is in
+//     the reserve, as the node is responsible for storing the chunks within
+//     its radius
+//
+// The removed item is returned so that the location of the chunk data
+// is released. Provided batch is updated.
+func (db *DB) setPurge(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange int64, removed *shed.Item, err error) {
+	item, err := db.retrievalDataIndex.Get(addressToItem(addr))
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return 0, nil, nil
+		}
+		return 0, nil, err
+	}
+
+	inReserve, err := db.pullIndex.Has(item)
+	if err != nil {
+		return 0, nil, err
+	}
+	pinned, err := db.pinIndex.Has(item)
+	if err != nil {
+		return 0, nil, err
+	}
+	if inReserve || pinned {
+		return 0, nil, nil
+	}
+
+	c, err := db.setRemove(batch, item, true)
+	if err != nil {
+		return 0, nil, err
+	}
+	return c, &item, nil
+}
+
 // setPin increments pin counter for the chunk by updating
 // pin index and sets the chunk to be excluded from garbage collection.
 // Provided batch is updated.
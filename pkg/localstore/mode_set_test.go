@@ -110,3 +110,54 @@ func TestModeSetRemove_WithSync(t *testing.T) {
 		})
 	}
 }
+
+// TestModeSetPurge validates that ModeSetPurge removes the uploaded chunks
+// from the push index and removes them unless they are pinned or in the reserve.
+func TestModeSetPurge(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, nil)
+
+	uploaded := generateTestRandomChunks(3)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, uploaded...); err != nil {
+		t.Fatal(err)
+	}
+	pinned := generateTestRandomChunk()
+	if _, err := db.Put(context.Background(), storage.ModePutUploadPin, pinned); err != nil {
+		t.Fatal(err)
+	}
+	reserved := generateTestRandomChunkAt(t, swarm.NewAddress(db.baseKey), 2).WithBatch(2, 3, 2, false)
+	if _, err := db.unreserveBatch(reserved.Stamp().BatchID(), 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Put(context.Background(), storage.ModePutSync, reserved); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := append(append(uploaded, pinned), reserved)
+	missing := swarm.RandAddress(t)
+	if err := db.Set(context.Background(), storage.ModeSetPurge, append(chunkAddresses(chunks), missing)...); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("push index count", newItemsCountTest(db.pushIndex, 0))
+	t.Run("retrieve data index count", newItemsCountTest(db.retrievalDataIndex, 2))
+	t.Run("pull index count", newItemsCountTest(db.pullIndex, 1))
+	t.Run("pin index count", newItemsCountTest(db.pinIndex, 2))
+	t.Run("gc size", newIndexGCSizeTest(db))
+
+	for _, ch := range uploaded {
+		has, err := db.Has(context.Background(), ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if has {
+			t.Fatalf("purged chunk %s found", ch.Address())
+		}
+	}
+	for _, ch := range []swarm.Chunk{pinned, reserved} {
+		if _, err := db.Get(context.Background(), storage.ModeGetRequest, ch.Address()); err != nil {
+			t.Fatalf("get chunk %s: %v", ch.Address(), err)
+		}
+	}
+}
@@ -96,7 +96,7 @@ func (db *DB) unreserveBatch(id []byte, radius uint8) (evicted uint64, err error
 	}
 
 	// trigger garbage collection if we reached the capacity
-	if gcSize >= db.cacheCapacity {
+	if gcSize >= db.gcCapacity.Load() {
 		db.triggerGarbageCollection()
 	}
 
@@ -216,3 +216,9 @@ func (db *DB) setReserveSize(size uint64) error {
 	}
 	return nil
 }
+
+// ReserveSize returns the reserve size computed
+// at the last update of the storage depth.
+func (db *DB) ReserveSize() (uint64, error) {
+	return db.reserveSize.Get()
+}
@@ -173,23 +173,28 @@ func (db *DB) ReserveSample(
 	}()
 
 	sampleItems := make([]swarm.Address, 0, sampleSize)
+	sampleChunks := make([]swarm.Chunk, 0, sampleSize)
 	// insert function will insert the new item in its correct place. If the sample
 	// size goes beyond what we need we omit the last item.
-	insert := func(item swarm.Address) {
+	insert := func(item swarm.Address, chunk swarm.Chunk) {
 		added := false
 		for i, sItem := range sampleItems {
 			if le(item.Bytes(), sItem.Bytes()) {
 				sampleItems = append(sampleItems[:i+1], sampleItems[i:]...)
 				sampleItems[i] = item
+				sampleChunks = append(sampleChunks[:i+1], sampleChunks[i:]...)
+				sampleChunks[i] = chunk
 				added = true
 				break
 			}
 		}
 		if len(sampleItems) > sampleSize {
 			sampleItems = sampleItems[:sampleSize]
+			sampleChunks = sampleChunks[:sampleSize]
 		}
 		if len(sampleItems) < sampleSize && !added {
 			sampleItems = append(sampleItems, item)
+			sampleChunks = append(sampleChunks, chunk)
 		}
 	}
 
@@ -225,7 +230,7 @@ func (db *DB) ReserveSample(
 				if !validChunkFn(chunk) {
 					logger.Debug("data invalid for chunk address", "chunk_address", chunk.Address())
 				} else {
-					insert(item.transformedAddress)
+					insert(item.transformedAddress, chunk.WithStamp(stamp))
 				}
 			} else {
 				logger.Debug("invalid stamp for chunk", "chunk_address", chunk.Address(), "error", err)
@@ -256,8 +261,9 @@ func (db *DB) ReserveSample(
 	hash := hasher.Sum(nil)
 
 	sample := storage.Sample{
-		Items: sampleItems,
-		Hash:  swarm.NewAddress(hash),
+		Items:  sampleItems,
+		Hash:   swarm.NewAddress(hash),
+		Chunks: sampleChunks,
 	}
 
 	db.metrics.SamplerSuccessfulRuns.Inc()
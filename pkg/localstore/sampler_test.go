@@ -7,6 +7,7 @@ package localstore
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
 	"errors"
 	"sync"
 	"testing"
@@ -66,6 +67,16 @@ func TestReserveSampler(t *testing.T) {
 				t.Fatalf("incorrect order of samples %+q", sample.Items)
 			}
 		}
+		if len(sample.Chunks) != len(sample.Items) {
+			t.Fatalf("got %d sample chunks, want %d", len(sample.Chunks), len(sample.Items))
+		}
+		for i, ch := range sample.Chunks {
+			mac := hmac.New(swarm.NewHasher, []byte("anchor"))
+			_, _ = mac.Write(ch.Data())
+			if !bytes.Equal(mac.Sum(nil), sample.Items[i].Bytes()) || ch.Stamp() == nil {
+				t.Fatalf("sample chunk %d not of the sample item", i)
+			}
+		}
 
 		sample1 = sample
 	})
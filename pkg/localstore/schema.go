@@ -22,4 +22,4 @@ const DBSchemaCode = "code"
 
 // DBSchemaCurrent represents the DB schema we want to use.
 // The actual/current DB schema might differ until migrations are run.
-var DBSchemaCurrent = DBSchemaResidue
+var DBSchemaCurrent = DBSchemaGCPartition
@@ -0,0 +1,355 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/sharky"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/hashicorp/go-multierror"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// The chunk data is kept in two tiers when the secondary storage path is
+// configured. The new chunks are always written to the hot tier, on the fast
+// storage. The tiering job periodically migrates the cache chunks which were
+// not accessed for the cold age to the cold tier, and brings the chunks which
+// left the cache, by being pinned or by entering the reserve, back to the hot
+// tier. The tier of the chunk data is encoded in the shard of its location,
+// so the reads and the releases are transparent to the rest of the store.
+
+const (
+	// coldTierFlag marks the shard of the locations in the cold tier.
+	coldTierFlag uint8 = 0x80
+
+	// defaultColdAge is the time after which the unaccessed cache chunk is cold.
+	defaultColdAge = 24 * time.Hour
+)
+
+var (
+	// tieringInterval is the interval between the tiering runs.
+	tieringInterval = 10 * time.Minute
+
+	// tieringBatchSize is the maximal number of chunks migrated in a tiering run.
+	tieringBatchSize = 1000
+)
+
+var errColdTierNotConfigured = errors.New("cold tier not configured")
+
+func isColdLocation(loc sharky.Location) bool {
+	return loc.Shard&coldTierFlag != 0
+}
+
+// tieredBlobStore keeps the chunk data in the hot or in the cold blobStore.
+type tieredBlobStore struct {
+	hot  blobStore
+	cold blobStore
+}
+
+var _ blobStore = (*tieredBlobStore)(nil)
+
+func (t *tieredBlobStore) tier(loc sharky.Location) (blobStore, sharky.Location) {
+	if isColdLocation(loc) {
+		loc.Shard &^= coldTierFlag
+		return t.cold, loc
+	}
+	return t.hot, loc
+}
+
+// Read reads the data from the tier of the location.
+func (t *tieredBlobStore) Read(ctx context.Context, loc sharky.Location, buf []byte) error {
+	s, loc := t.tier(loc)
+	return s.Read(ctx, loc, buf)
+}
+
+// Write writes the data to the hot tier.
+func (t *tieredBlobStore) Write(ctx context.Context, data []byte) (sharky.Location, error) {
+	return t.hot.Write(ctx, data)
+}
+
+// writeCold writes the data to the cold tier.
+func (t *tieredBlobStore) writeCold(ctx context.Context, data []byte) (sharky.Location, error) {
+	loc, err := t.cold.Write(ctx, data)
+	if err != nil {
+		return loc, err
+	}
+	loc.Shard |= coldTierFlag
+	return loc, nil
+}
+
+// Release releases the location in its tier.
+func (t *tieredBlobStore) Release(ctx context.Context, loc sharky.Location) error {
+	s, loc := t.tier(loc)
+	return s.Release(ctx, loc)
+}
+
+func (t *tieredBlobStore) Metrics() []prometheus.Collector {
+	return append(t.hot.Metrics(), t.cold.Metrics()...)
+}
+
+func (t *tieredBlobStore) Close() error {
+	return multierror.Append(new(multierror.Error), t.hot.Close(), t.cold.Close()).ErrorOrNil()
+}
+
+// newColdIndex creates the index of the chunks in the cold tier.
+// The index may hold the addresses of the chunks which are already
+// removed, these are cleaned up by the tiering job.
+func (db *DB) newColdIndex() (shed.Index, error) {
+	return db.shed.NewIndex("Hash->nil", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			return fields.Address, nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			e.Address = key
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			return nil, nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			return e, nil
+		},
+	})
+}
+
+// checkColdTier fails if chunks were migrated to the cold tier
+// by the previous run but the cold tier is not configured anymore.
+func (db *DB) checkColdTier() error {
+	if _, ok := db.sharky.(*tieredBlobStore); ok {
+		return nil
+	}
+	var found bool
+	err := db.coldIndex.Iterate(func(item shed.Item) (bool, error) {
+		stored, err := db.retrievalDataIndex.Get(item)
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return true, err
+		}
+		loc, err := sharky.LocationFromBinary(stored.Location)
+		if err != nil {
+			return true, err
+		}
+		found = isColdLocation(loc)
+		return found, nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if found {
+		return fmt.Errorf("chunks stored in the cold tier: %w", errColdTierNotConfigured)
+	}
+	return nil
+}
+
+func (db *DB) tieringWorker() {
+	defer close(db.tieringWorkerDone)
+
+	ticker := time.NewTicker(tieringInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			demoted, promoted, err := db.tier(db.ctx)
+			if err != nil {
+				db.logger.Error(err, "tiering failed")
+			}
+			if demoted > 0 || promoted > 0 {
+				db.logger.Debug("tiering done", "cold", demoted, "hot", promoted)
+			}
+		case <-db.close:
+			return
+		}
+	}
+}
+
+// tier migrates the cold cache chunks to the cold tier and the chunks which
+// are not in the cache anymore back to the hot tier. It returns the number of
+// the chunks moved to the cold and to the hot tier.
+func (db *DB) tier(ctx context.Context) (demoted, promoted int, err error) {
+	tiers, ok := db.sharky.(*tieredBlobStore)
+	if !ok {
+		return 0, 0, errColdTierNotConfigured
+	}
+
+	coldBefore := now() - db.coldAge.Nanoseconds()
+
+	// the gc index is ordered by the access time only within the partitions,
+	// so every partition is iterated up to its first chunk which is not cold,
+	// and the least recently accessed chunks across all of them are migrated,
+	// as the garbage collection selects them
+	var candidates []shed.Item
+	for p := 0; p < gcPartitions; p++ {
+		var selected int
+		err = db.gcIndex.Iterate(func(item shed.Item) (bool, error) {
+			if item.AccessTimestamp >= coldBefore {
+				return true, nil
+			}
+			cold, err := db.coldIndex.Has(item)
+			if err != nil {
+				return true, err
+			}
+			if !cold {
+				candidates = append(candidates, item)
+				selected++
+			}
+			return selected >= tieringBatchSize, nil
+		}, &shed.IterateOptions{Prefix: []byte{byte(p)}})
+		if err != nil {
+			return 0, 0, fmt.Errorf("iterate gc index: %w", err)
+		}
+	}
+	sortByAccess(candidates)
+	if len(candidates) > tieringBatchSize {
+		candidates = candidates[:tieringBatchSize]
+	}
+	for _, item := range candidates {
+		moved, err := db.moveTier(ctx, tiers, item, coldBefore, true)
+		if err != nil {
+			return demoted, promoted, err
+		}
+		if moved {
+			demoted++
+		}
+	}
+
+	candidates = candidates[:0]
+	err = db.coldIndex.Iterate(func(item shed.Item) (bool, error) {
+		_, loc, inCache, err := db.tierOf(item)
+		switch {
+		case errors.Is(err, leveldb.ErrNotFound):
+			// stale entry of the removed chunk
+		case err != nil:
+			return true, err
+		case isColdLocation(loc) && inCache:
+			return false, nil
+		}
+		candidates = append(candidates, item)
+		return len(candidates) >= tieringBatchSize, nil
+	}, nil)
+	if err != nil {
+		return demoted, 0, fmt.Errorf("iterate cold index: %w", err)
+	}
+	for _, item := range candidates {
+		moved, err := db.moveTier(ctx, tiers, item, coldBefore, false)
+		if err != nil {
+			return demoted, promoted, err
+		}
+		if moved {
+			promoted++
+		}
+	}
+
+	db.metrics.TieringColdCounter.Add(float64(demoted))
+	db.metrics.TieringHotCounter.Add(float64(promoted))
+	return demoted, promoted, nil
+}
+
+// tierOf returns the stored item of the chunk with its access timestamp,
+// the location of its data and whether the chunk is in the cache.
+func (db *DB) tierOf(item shed.Item) (stored shed.Item, loc sharky.Location, inCache bool, err error) {
+	stored, err = db.retrievalDataIndex.Get(item)
+	if err != nil {
+		return stored, loc, false, err
+	}
+	loc, err = sharky.LocationFromBinary(stored.Location)
+	if err != nil {
+		return stored, loc, false, err
+	}
+	access, err := db.retrievalAccessIndex.Get(stored)
+	switch {
+	case err == nil:
+		stored.AccessTimestamp = access.AccessTimestamp
+	case !errors.Is(err, leveldb.ErrNotFound):
+		return stored, loc, false, err
+	}
+	inCache, err = db.gcIndex.Has(stored)
+	return stored, loc, inCache, err
+}
+
+// moveTier moves the chunk data to the cold or to the hot tier if the chunk
+// still belongs there. The data is written to the new location which is
+// referenced by the retrieval index before the old location is released,
+// the intents of both locations are logged for the case of a crash.
+func (db *DB) moveTier(ctx context.Context, tiers *tieredBlobStore, item shed.Item, coldBefore int64, toCold bool) (bool, error) {
+	db.lock.Lock(lockKeyGC)
+	defer db.lock.Unlock(lockKeyGC)
+
+	stored, loc, inCache, err := db.tierOf(item)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return false, db.coldIndex.Delete(item)
+	}
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case toCold && (isColdLocation(loc) || !inCache || stored.AccessTimestamp >= coldBefore):
+		// moved or accessed since it was selected
+		return false, nil
+	case !toCold && !isColdLocation(loc):
+		return false, db.coldIndex.Delete(item)
+	case !toCold && inCache:
+		return false, nil
+	}
+
+	stored.Data = make([]byte, loc.Length)
+	if err := db.sharky.Read(ctx, loc, stored.Data); err != nil {
+		return false, fmt.Errorf("read location %v: %w", loc, err)
+	}
+
+	var newLoc sharky.Location
+	if toCold {
+		newLoc, err = tiers.writeCold(ctx, stored.Data)
+	} else {
+		newLoc, err = tiers.Write(ctx, stored.Data)
+	}
+	if err != nil {
+		return false, fmt.Errorf("write data: %w", err)
+	}
+	moved := stored
+	moved.Location, err = newLoc.MarshalBinary()
+	if err != nil {
+		return false, err
+	}
+
+	if err := db.logIntents([]shed.Item{moved}); err != nil {
+		return false, multierror.Append(err, tiers.Release(ctx, newLoc))
+	}
+
+	batch := new(leveldb.Batch)
+	err = db.retrievalDataIndex.PutInBatch(batch, moved)
+	if err == nil {
+		if toCold {
+			err = db.coldIndex.PutInBatch(batch, moved)
+		} else {
+			err = db.coldIndex.DeleteInBatch(batch, moved)
+		}
+	}
+	if err == nil {
+		err = db.clearIntentsInBatch(batch, []shed.Item{moved})
+	}
+	if err == nil {
+		err = db.logIntentsInBatch(batch, []shed.Item{stored})
+	}
+	if err == nil {
+		err = db.shed.WriteBatch(batch)
+	}
+	if err != nil {
+		return false, multierror.Append(err, db.releaseIntents(ctx, []shed.Item{moved}))
+	}
+
+	if err := db.releaseIntents(ctx, []shed.Item{stored}); err != nil {
+		db.logger.Warning("release location of the moved chunk failed", "address", swarm.NewAddress(item.Address), "error", err)
+	}
+	return true, nil
+}
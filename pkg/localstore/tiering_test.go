@@ -0,0 +1,249 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/sharky"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestTiering(t *testing.T) {
+	t.Cleanup(setWithinRadiusFunc(func(_ *DB, _ shed.Item) bool { return false }))
+
+	var (
+		dir     = t.TempDir()
+		coldDir = t.TempDir()
+		baseKey = make([]byte, 32)
+		o       = &Options{
+			Capacity: 100,
+			ColdPath: coldDir,
+			ColdAge:  time.Hour,
+			UnreserveFunc: func(postage.UnreserveIteratorFn) error {
+				return nil
+			},
+		}
+	)
+
+	var timestamp int64 = 1
+	t.Cleanup(setNow(func() int64 {
+		return timestamp
+	}))
+
+	db, err := New(dir, baseKey, nil, o, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := generateTestRandomChunks(10)
+	for _, ch := range chunks {
+		unreserveChunkBatch(t, db, 0, ch)
+	}
+	if _, err := db.Put(context.Background(), storage.ModePutRequest, chunks...); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("hot chunks stay", func(t *testing.T) {
+		demoted, promoted, err := db.tier(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if demoted != 0 || promoted != 0 {
+			t.Fatalf("got %d cold and %d hot chunks, want none", demoted, promoted)
+		}
+	})
+
+	timestamp += 2 * time.Hour.Nanoseconds()
+
+	t.Run("cold chunks demoted", func(t *testing.T) {
+		demoted, promoted, err := db.tier(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if demoted != len(chunks) || promoted != 0 {
+			t.Fatalf("got %d cold and %d hot chunks, want %d cold", demoted, promoted, len(chunks))
+		}
+		newItemsCountTest(db.coldIndex, len(chunks))(t)
+		for _, ch := range chunks {
+			testTier(t, db, ch.Address(), true)
+		}
+
+		// the next run has nothing to move
+		demoted, promoted, err = db.tier(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if demoted != 0 || promoted != 0 {
+			t.Fatalf("got %d cold and %d hot chunks, want none", demoted, promoted)
+		}
+	})
+
+	t.Run("cold chunks readable", func(t *testing.T) {
+		for _, ch := range chunks {
+			got, err := db.Get(context.Background(), storage.ModeGetLookup, ch.Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.Data(), ch.Data()) {
+				t.Fatalf("got data %x, want %x", got.Data(), ch.Data())
+			}
+		}
+	})
+
+	t.Run("pinned chunk promoted", func(t *testing.T) {
+		pinned := chunks[0]
+		if err := db.Set(context.Background(), storage.ModeSetPin, pinned.Address()); err != nil {
+			t.Fatal(err)
+		}
+
+		demoted, promoted, err := db.tier(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if demoted != 0 || promoted != 1 {
+			t.Fatalf("got %d cold and %d hot chunks, want 1 hot", demoted, promoted)
+		}
+		newItemsCountTest(db.coldIndex, len(chunks)-1)(t)
+		testTier(t, db, pinned.Address(), false)
+	})
+
+	t.Run("removed chunk cleaned up", func(t *testing.T) {
+		removed := chunks[1]
+		if err := db.Set(context.Background(), storage.ModeSetRemove, removed.Address()); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := db.tier(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		newItemsCountTest(db.coldIndex, len(chunks)-2)(t)
+	})
+
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("cold tier required", func(t *testing.T) {
+		opts := *o
+		opts.ColdPath = ""
+		_, err := New(dir, baseKey, nil, &opts, log.Noop)
+		if !errors.Is(err, errColdTierNotConfigured) {
+			t.Fatalf("got error %v, want %v", err, errColdTierNotConfigured)
+		}
+	})
+
+	t.Run("reopen", func(t *testing.T) {
+		db, err := New(dir, baseKey, nil, o, log.Noop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		for _, ch := range chunks[2:] {
+			got, err := db.Get(context.Background(), storage.ModeGetLookup, ch.Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.Data(), ch.Data()) {
+				t.Fatalf("got data %x, want %x", got.Data(), ch.Data())
+			}
+		}
+	})
+}
+
+// TestTieringPartitions validates that the least recently accessed cold
+// chunks are migrated first, whichever gc index partition they are in.
+func TestTieringPartitions(t *testing.T) {
+	t.Cleanup(setWithinRadiusFunc(func(_ *DB, _ shed.Item) bool { return false }))
+
+	defer func(s int) { tieringBatchSize = s }(tieringBatchSize)
+	tieringBatchSize = 2
+
+	var timestamp int64 = 1
+	t.Cleanup(setNow(func() int64 {
+		return timestamp
+	}))
+
+	db, err := New(t.TempDir(), make([]byte, 32), nil, &Options{
+		Capacity: 100,
+		ColdPath: t.TempDir(),
+		ColdAge:  time.Hour,
+		UnreserveFunc: func(postage.UnreserveIteratorFn) error {
+			return nil
+		},
+	}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	// the chunks of the first partition are accessed after
+	// the oldest chunk, which is in the last partition
+	var first []swarm.Chunk
+	var oldest swarm.Chunk
+	for len(first) < 2 || oldest == nil {
+		ch := generateTestRandomChunk()
+		switch gcPartition(ch.Address().Bytes()) {
+		case 0:
+			if len(first) < 2 {
+				first = append(first, ch)
+			}
+		case gcPartitions - 1:
+			oldest = ch
+		}
+	}
+	for _, ch := range append([]swarm.Chunk{oldest}, first...) {
+		unreserveChunkBatch(t, db, 0, ch)
+		if _, err := db.Put(context.Background(), storage.ModePutRequest, ch); err != nil {
+			t.Fatal(err)
+		}
+		timestamp++
+	}
+
+	timestamp += 2 * time.Hour.Nanoseconds()
+
+	demoted, _, err := db.tier(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if demoted != tieringBatchSize {
+		t.Fatalf("got %d cold chunks, want %d", demoted, tieringBatchSize)
+	}
+	testTier(t, db, oldest.Address(), true)
+	testTier(t, db, first[0].Address(), true)
+	testTier(t, db, first[1].Address(), false)
+}
+
+// testTier fails the test if the chunk data is not in the expected tier.
+func testTier(t *testing.T, db *DB, addr swarm.Address, cold bool) {
+	t.Helper()
+
+	item, err := db.retrievalDataIndex.Get(addressToItem(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := sharky.LocationFromBinary(item.Location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isColdLocation(loc) != cold {
+		t.Fatalf("chunk %s: got cold %v, want %v", addr, isColdLocation(loc), cold)
+	}
+	has, err := db.coldIndex.Has(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has != cold {
+		t.Fatalf("chunk %s: got in cold index %v, want %v", addr, has, cold)
+	}
+}
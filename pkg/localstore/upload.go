@@ -0,0 +1,102 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// The uploaded chunks which are not synced yet are kept in the upload store,
+// the pushIndex, apart from the cache and the reserve. They are not added to
+// the gcIndex, even if they are requested or synced from the other peers in
+// the meanwhile, so the garbage collection can not evict them before they are
+// pushed. The upload store has its own capacity accounting, and the uploads
+// over the capacity are refused with ErrUploadStoreFull. A chunk leaves the
+// upload store only when it is synced, and then it is added to the cache as
+// usual, or when its upload is aborted by the purge or the removal.
+
+// ErrUploadStoreFull is returned when the uploaded chunks
+// would exceed the capacity of the upload store.
+var ErrUploadStoreFull = errors.New("upload store full")
+
+// initUploadSize counts the chunks in the upload store.
+func (db *DB) initUploadSize() error {
+	n, err := db.pushIndex.Count()
+	if err != nil {
+		return fmt.Errorf("count push index: %w", err)
+	}
+	db.incUploadSize(int64(n))
+	return nil
+}
+
+// UploadSize returns the number of the chunks in the upload store.
+func (db *DB) UploadSize() uint64 {
+	return uint64(db.uploadSize.Load())
+}
+
+// incUploadSize changes the upload store size by change, which can be
+// negative. It must be called only after the batch of the change is written.
+func (db *DB) incUploadSize(change int64) {
+	if change == 0 {
+		return
+	}
+	db.metrics.UploadSize.Set(float64(db.uploadSize.Add(change)))
+}
+
+// checkUploadCapacity returns ErrUploadStoreFull if the upload store
+// would exceed its capacity with the change. It must be called under
+// the lockKeyUpload lock.
+func (db *DB) checkUploadCapacity(change int64) error {
+	if db.uploadCapacity == 0 || change <= 0 {
+		return nil
+	}
+	if uint64(db.uploadSize.Load()+change) > db.uploadCapacity {
+		return ErrUploadStoreFull
+	}
+	return nil
+}
+
+// uploadPending reports whether the chunk is in the upload store.
+func (db *DB) uploadPending(item shed.Item) (bool, error) {
+	if item.StoreTimestamp == 0 {
+		i, err := db.retrievalDataIndex.Get(item)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		item.StoreTimestamp = i.StoreTimestamp
+	}
+	return db.pushIndex.Has(item)
+}
+
+// removeUpload removes the chunk from the upload store, so that it is not
+// push synced anymore, and returns the change of the upload store size.
+// Provided batch is updated.
+func (db *DB) removeUpload(batch *leveldb.Batch, item shed.Item) (uploadSizeChange int64, err error) {
+	if item.StoreTimestamp == 0 {
+		i, err := db.retrievalDataIndex.Get(item)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				return 0, nil
+			}
+			return 0, err
+		}
+		item.StoreTimestamp = i.StoreTimestamp
+	}
+	pending, err := db.pushIndex.Has(item)
+	if err != nil || !pending {
+		return 0, err
+	}
+	if err := db.pushIndex.DeleteInBatch(batch, item); err != nil {
+		return 0, err
+	}
+	return -1, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+func TestUploadStoreCapacity(t *testing.T) {
+	db := newTestDB(t, &Options{
+		UploadCapacity: 3,
+	})
+	ctx := context.Background()
+
+	chunks := generateTestRandomChunks(5)
+	for _, ch := range chunks {
+		unreserveChunkBatch(t, db, 0, ch)
+	}
+
+	if _, err := db.Put(ctx, storage.ModePutUpload, chunks[:3]...); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.UploadSize(); got != 3 {
+		t.Fatalf("got upload size %d, want 3", got)
+	}
+
+	// the pending upload is not counted twice
+	if _, err := db.Put(ctx, storage.ModePutUpload, chunks[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := db.Put(ctx, storage.ModePutUpload, chunks[3])
+	if !errors.Is(err, ErrUploadStoreFull) {
+		t.Fatalf("got error %v, want %v", err, ErrUploadStoreFull)
+	}
+	if _, err := db.Get(ctx, storage.ModeGetRequest, chunks[3].Address()); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+
+	// the synced and the aborted uploads free the capacity
+	if err := db.Set(ctx, storage.ModeSetSync, chunks[0].Address()); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Set(ctx, storage.ModeSetPurge, chunks[1].Address()); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.UploadSize(); got != 1 {
+		t.Fatalf("got upload size %d, want 1", got)
+	}
+	if _, err := db.Put(ctx, storage.ModePutUpload, chunks[3:]...); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("push index count", newItemsCountTest(db.pushIndex, 3))
+
+	// the size is counted from the upload store when the db is opened
+	db.uploadSize.Store(0)
+	if err := db.initUploadSize(); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.UploadSize(); got != 3 {
+		t.Fatalf("got upload size %d, want 3", got)
+	}
+}
+
+func TestUploadStoreIsolation(t *testing.T) {
+	t.Cleanup(setWithinRadiusFunc(func(_ *DB, _ shed.Item) bool { return false }))
+
+	db := newTestDB(t, nil)
+	ctx := context.Background()
+
+	ch := generateTestRandomChunk()
+	unreserveChunkBatch(t, db, 0, ch)
+	if _, err := db.Put(ctx, storage.ModePutUpload, ch); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pending upload is not cached when it is requested or synced
+	if _, err := db.Put(ctx, storage.ModePutRequest, ch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Put(ctx, storage.ModePutSync, ch); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, 0))
+	t.Run("gc size", newIndexGCSizeTest(db))
+
+	item, err := db.retrievalDataIndex.Get(addressToItem(ch.Address()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the cache entry of the pending upload is evicted without the chunk
+	if err := db.gcIndex.Put(item); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.gcSize.Put(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.evictGarbage([]shed.Item{item}); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("gc index count after eviction", newItemsCountTest(db.gcIndex, 0))
+	t.Run("gc size after eviction", newIndexGCSizeTest(db))
+	t.Run("push index count after eviction", newItemsCountTest(db.pushIndex, 1))
+	if _, err := db.Get(ctx, storage.ModeGetRequest, ch.Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the synced upload is cached
+	if err := db.Set(ctx, storage.ModeSetSync, ch.Address()); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("gc index count after sync", newItemsCountTest(db.gcIndex, 1))
+	t.Run("push index count after sync", newItemsCountTest(db.pushIndex, 0))
+	if got := db.UploadSize(); got != 0 {
+		t.Fatalf("got upload size %d, want 0", got)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// The cache capacity is fixed in the number of chunks, which does not account
+// for the other data on the filesystem of the store. With the free disk space
+// watermark configured, the watermark job periodically samples the filesystem
+// and lowers the capacity of the cache, down to none, while less than the
+// watermark ratio of the filesystem is free, and raises it back, up to the
+// configured capacity, as the space is freed. The garbage collection evicts
+// the cache down to the lowered capacity as usual.
+
+// chunkDiskSize is the disk space taken by the data of a chunk,
+// the size of the sharky slot, ignoring the index entries.
+const chunkDiskSize = swarm.SocMaxChunkSize
+
+// diskWatermarkInterval is the interval between the filesystem samples.
+var diskWatermarkInterval = time.Minute
+
+// diskUsage returns the free and the total space of the
+// filesystem the path is located on, in bytes.
+var diskUsage = func(path string) (free, total uint64, err error) {
+	usage, err := disk.Usage(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	return usage.Free, usage.Total, nil
+}
+
+func (db *DB) diskWatermarkWorker() {
+	defer close(db.diskWatermarkWorkerDone)
+
+	ticker := time.NewTicker(diskWatermarkInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := db.adjustCacheCapacity(); err != nil {
+			db.logger.Error(err, "adjust cache capacity failed")
+		}
+		select {
+		case <-ticker.C:
+		case <-db.close:
+			return
+		}
+	}
+}
+
+// adjustCacheCapacity samples the filesystem of the store and sets the
+// capacity of the cache, so that the minFreeDisk ratio of the filesystem
+// is kept free, and triggers the garbage collection if the cache exceeds
+// it. The capacity never exceeds the configured cacheCapacity.
+func (db *DB) adjustCacheCapacity() (uint64, error) {
+	free, total, err := diskUsage(db.diskPath)
+	if err != nil {
+		return 0, fmt.Errorf("disk usage: %w", err)
+	}
+	gcSize, err := db.gcSize.Get()
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return 0, err
+	}
+
+	// the cache may grow by the space above the
+	// watermark and must shrink by the space below it
+	watermark := uint64(db.minFreeDisk * float64(total))
+	var capacity uint64
+	if free >= watermark {
+		capacity = gcSize + (free-watermark)/chunkDiskSize
+		if capacity < gcSize || capacity > db.cacheCapacity {
+			capacity = db.cacheCapacity
+		}
+	} else if below := (watermark - free) / chunkDiskSize; below < gcSize {
+		capacity = gcSize - below
+	}
+
+	if prev := db.gcCapacity.Swap(capacity); prev != capacity {
+		db.logger.Debug("cache capacity adjusted", "capacity", capacity, "previous", prev, "free_bytes", free, "watermark_bytes", watermark)
+	}
+	db.metrics.GCCapacity.Set(float64(capacity))
+
+	if gcSize > 0 && gcSize >= capacity {
+		db.triggerGarbageCollection()
+	}
+	return capacity, nil
+}
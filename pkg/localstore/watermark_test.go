@@ -0,0 +1,120 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+func setDiskUsage(f func(path string) (free, total uint64, err error)) (reset func()) {
+	current := diskUsage
+	reset = func() { diskUsage = current }
+	diskUsage = f
+	return reset
+}
+
+func TestAdjustCacheCapacity(t *testing.T) {
+	t.Cleanup(setWithinRadiusFunc(func(_ *DB, _ shed.Item) bool { return false }))
+
+	const total = 1000 * chunkDiskSize // the watermark is 100 chunks
+	var free uint64
+	t.Cleanup(setDiskUsage(func(string) (uint64, uint64, error) {
+		return free, total, nil
+	}))
+
+	db := newTestDB(t, &Options{
+		Capacity:    100,
+		MinFreeDisk: 0.1,
+	})
+
+	chunks := generateTestRandomChunks(50)
+	for _, ch := range chunks {
+		unreserveChunkBatch(t, db, 0, ch)
+		if _, err := db.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Set(context.Background(), storage.ModeSetSync, ch.Address()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		freeChunks uint64
+		want       uint64
+	}{
+		{
+			name:       "plenty of free space",
+			freeChunks: 900,
+			want:       100,
+		},
+		{
+			name:       "above the watermark",
+			freeChunks: 110,
+			want:       60,
+		},
+		{
+			name:       "at the watermark",
+			freeChunks: 100,
+			want:       50,
+		},
+		{
+			name:       "below the watermark",
+			freeChunks: 80,
+			want:       30,
+		},
+	} {
+		free = tc.freeChunks * chunkDiskSize
+		gcSize, err := db.gcSize.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gcSize != 50 {
+			t.Fatalf("%s: got gc size %d, want 50", tc.name, gcSize)
+		}
+		got, err := db.adjustCacheCapacity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: got capacity %d, want %d", tc.name, got, tc.want)
+		}
+		if got := db.gcCapacity.Load(); got != tc.want {
+			t.Fatalf("%s: got gc capacity %d, want %d", tc.name, got, tc.want)
+		}
+	}
+
+	// the cache is evicted down to the target of the lowered capacity
+	target := db.gcTarget()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		gcSize, err := db.gcSize.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gcSize == target {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got gc size %d, want %d", gcSize, target)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// no cache is kept on the full disk
+	free = 0
+	got, err := db.adjustCacheCapacity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 0 {
+		t.Fatalf("got capacity %d, want 0", got)
+	}
+}
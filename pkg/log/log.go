@@ -197,6 +197,7 @@ type Options struct {
 	levelHooks levelHooks
 	fmtOptions fmtOptions
 	logMetrics *metrics
+	extraSinks []extraSink
 }
 
 // Option represent Options parameters modifier.
@@ -209,6 +210,16 @@ func WithSink(sink io.Writer) Option {
 	return func(opts *Options) { opts.sink = sink }
 }
 
+// WithExtraSink tells the logger to also log to the given sink with
+// the output formatted as JSON if jsonOutput is true, or as text
+// otherwise, regardless of the output format of the main sink.
+// The same concurrency requirements as for WithSink apply.
+func WithExtraSink(sink io.Writer, jsonOutput bool) Option {
+	return func(opts *Options) {
+		opts.extraSinks = append(opts.extraSinks, extraSink{w: sink, jsonOutput: jsonOutput})
+	}
+}
+
 // WithVerbosity tells the logger which verbosity level should be logged by default.
 func WithVerbosity(verbosity Level) Option {
 	return func(opts *Options) { opts.verbosity = verbosity }
@@ -121,7 +121,10 @@ func (b *builder) Build() Logger {
 func (b *builder) Register() Logger {
 	val := b.Build()
 	key := hash(b.namesStr, b.v, b.valuesStr, b.l.sink)
-	res, _ := loggers.LoadOrStore(key, val)
+	res, loaded := loggers.LoadOrStore(key, val)
+	if !loaded {
+		applyOverrides(res.(*logger))
+	}
 	return res.(*logger)
 }
 
@@ -163,6 +166,9 @@ type logger struct {
 
 	// metrics collects basic statistics about logged messages.
 	metrics *metrics
+
+	// extraSinks represents the additional streams where the logs are written.
+	extraSinks []extraSink
 }
 
 // Metrics implements metrics.Collector interface.
@@ -242,12 +248,24 @@ func (l *logger) log(vl Level, mc MessageCategory, err error, msg string, keysAn
 	buf := l.formatter.render(base, keysAndValues)
 
 	var merr *multierror.Error
-	if _, err = l.sink.Write(buf); err != nil {
+	if _, err = write(l.sink, vl, buf); err != nil {
 		merr = multierror.Append(
 			merr,
 			fmt.Errorf("log %s: failed to write message: %w", vl, err),
 		)
 	}
+	for _, s := range l.extraSinks {
+		sbuf := buf
+		if s.formatter != l.formatter {
+			sbuf = s.formatter.render(base, keysAndValues)
+		}
+		if _, err = write(s.w, vl, sbuf); err != nil {
+			merr = multierror.Append(
+				merr,
+				fmt.Errorf("log %s: failed to write message to extra sink: %w", vl, err),
+			)
+		}
+	}
 	if err := l.levelHooks.fire(vl + Level(l.v)); err != nil {
 		merr = multierror.Append(
 			merr,
@@ -63,7 +63,7 @@ func NewLogger(name string, opts ...Option) Logger {
 		modify(&options)
 	}
 
-	if options.sink == io.Discard {
+	if options.sink == io.Discard && len(options.extraSinks) == 0 {
 		return Noop
 	}
 
@@ -72,6 +72,17 @@ func NewLogger(name string, opts ...Option) Logger {
 		formatter = newFormatter(options.fmtOptions)
 	}
 
+	extraSinks := make([]extraSink, 0, len(options.extraSinks))
+	for _, s := range options.extraSinks {
+		s.formatter = formatter
+		if s.jsonOutput != formatter.opts.jsonOutput {
+			opts := formatter.opts
+			opts.jsonOutput = s.jsonOutput
+			s.formatter = newFormatter(opts)
+		}
+		extraSinks = append(extraSinks, s)
+	}
+
 	val, ok := loggers.Load(hash(name, 0, "", options.sink))
 	if ok {
 		return val.(*logger)
@@ -83,6 +94,7 @@ func NewLogger(name string, opts ...Option) Logger {
 		sink:       options.sink,
 		levelHooks: options.levelHooks,
 		metrics:    options.logMetrics,
+		extraSinks: extraSinks,
 	}
 	l.builder = &builder{
 		l:        l,
@@ -107,9 +119,56 @@ func SetVerbosity(l Logger, v Level) error {
 	return nil
 }
 
+// overrides holds the verbosity levels set by expressions, which
+// are also applied to the loggers registered after they were set,
+// so that the level of a subsystem can be changed before
+// its loggers are created.
+var overrides = struct {
+	sync.Mutex
+	levels []override
+}{}
+
+// override is a verbosity level set by an expression.
+type override struct {
+	exp   string
+	rex   *regexp.Regexp
+	level Level
+}
+
+// setOverride records the verbosity level for the expression,
+// replacing the level previously set by the same expression.
+func setOverride(e string, rex *regexp.Regexp, v Level) {
+	overrides.Lock()
+	defer overrides.Unlock()
+
+	for i, o := range overrides.levels {
+		if o.exp == e {
+			overrides.levels = append(overrides.levels[:i], overrides.levels[i+1:]...)
+			break
+		}
+	}
+	overrides.levels = append(overrides.levels, override{exp: e, rex: rex, level: v})
+}
+
+// applyOverrides sets the verbosity of the newly registered
+// logger to the level of the last expression that matches it.
+func applyOverrides(l *logger) {
+	overrides.Lock()
+	defer overrides.Unlock()
+
+	for i := len(overrides.levels) - 1; i >= 0; i-- {
+		if o := overrides.levels[i]; o.rex.MatchString(l.id) {
+			_ = SetVerbosity(l, o.level)
+			return
+		}
+	}
+}
+
 // SetVerbosityByExp sets all loggers to the given
 // verbosity level v that match the given expression
 // e, which can be a logger id or a regular expression.
+// The loggers matching the regular expression, which are
+// registered later, are set to the same verbosity level.
 // An error is returned if e fails to compile.
 func SetVerbosityByExp(e string, v Level) error {
 	val, ok := loggers.Load(e)
@@ -122,6 +181,7 @@ func SetVerbosityByExp(e string, v Level) error {
 	if err != nil {
 		return err
 	}
+	setOverride(e, rex, v)
 
 	var merr *multierror.Error
 	loggers.Range(func(key, val interface{}) bool {
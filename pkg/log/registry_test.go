@@ -214,3 +214,47 @@ func TestRegistryRange(t *testing.T) {
 		t.Fatalf("RegistryIterate(...) instance(s) count mismatch: want: %d; have: %d", want, have)
 	}
 }
+
+func TestSetVerbosityByExpOverrides(t *testing.T) {
+	l, o, v := loggers, defaults.options, overrides.levels
+	t.Cleanup(func() {
+		loggers = l
+		defaults.pin = sync.Once{}
+		defaults.options = o
+		overrides.levels = v
+	})
+
+	loggers = new(sync.Map)
+	defaults.options = new(Options)
+	overrides.levels = nil
+	ModifyDefaults(opts...)
+
+	if err := SetVerbosityByExp("^root/pullsync", VerbosityDebug); err != nil {
+		t.Fatalf("SetVerbosityByExp(...) unexpected error %v", err)
+	}
+
+	// The loggers registered after the level was set inherit it.
+	pullsync := NewLogger("root").WithName("pullsync").Register()
+	pushsync := NewLogger("root").WithName("pushsync").Register()
+	if want, have := VerbosityDebug, pullsync.Verbosity(); want != have {
+		t.Errorf("pullsync want verbosity: %q; have: %q", want, have)
+	}
+	if want, have := Level(1), pushsync.Verbosity(); want != have {
+		t.Errorf("pushsync want verbosity: %q; have: %q", want, have)
+	}
+
+	// The same expression replaces the previous level.
+	if err := SetVerbosityByExp("^root/pullsync", VerbosityWarning); err != nil {
+		t.Fatalf("SetVerbosityByExp(...) unexpected error %v", err)
+	}
+	if want, have := VerbosityWarning, pullsync.Verbosity(); want != have {
+		t.Errorf("pullsync want verbosity: %q; have: %q", want, have)
+	}
+	child := NewLogger("root").WithName("pullsync").WithValues("peer", "a").Register()
+	if want, have := VerbosityWarning, child.Verbosity(); want != have {
+		t.Errorf("child want verbosity: %q; have: %q", want, have)
+	}
+	if want, have := 1, len(overrides.levels); want != have {
+		t.Errorf("want %d overrides; have %d", want, have)
+	}
+}
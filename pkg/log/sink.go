@@ -0,0 +1,297 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrUnsupportedSink is returned by NewSink when
+// the sink is not supported on the platform.
+var ErrUnsupportedSink = errors.New("log: unsupported sink")
+
+// LevelWriter is implemented by the sinks which
+// handle the severity of the messages on their own,
+// e.g. syslog. The loggers call WriteLevel instead
+// of Write on such sinks.
+type LevelWriter interface {
+	WriteLevel(l Level, p []byte) (int, error)
+}
+
+// write writes p to w with the given level.
+func write(w io.Writer, l Level, p []byte) (int, error) {
+	if lw, ok := w.(LevelWriter); ok {
+		return lw.WriteLevel(l, p)
+	}
+	return w.Write(p)
+}
+
+// extraSink is an additional stream where the logs are
+// written with the output format independent of the main sink.
+type extraSink struct {
+	w          io.Writer
+	jsonOutput bool
+	formatter  *formatter
+}
+
+// NewSink opens the sink described by the URL. The supported sinks are:
+//
+//	file:///var/log/bee.log?max-size=104857600&max-backups=5
+//	syslog:///?tag=bee (the local syslog daemon)
+//	syslog://localhost:514?tag=bee (syslog+tcp:// for TCP)
+//	tcp://localhost:5170
+//
+// The output format of the sink can be set with the format=json|text
+// query parameter. The TCP sink writes JSON lines by default; the
+// others write text. The returned jsonOutput reports the format.
+func NewSink(rawURL string) (w io.WriteCloser, jsonOutput bool, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, false, fmt.Errorf("log: parse sink: %w", err)
+	}
+	query := u.Query()
+
+	jsonOutput = u.Scheme == "tcp"
+	switch format := query.Get("format"); format {
+	case "":
+	case "json":
+		jsonOutput = true
+	case "text":
+		jsonOutput = false
+	default:
+		return nil, false, fmt.Errorf("log: unknown sink format %q", format)
+	}
+
+	switch u.Scheme {
+	case "file":
+		path := u.Path
+		if u.Opaque != "" {
+			path = u.Opaque
+		}
+		var (
+			maxSize    int64
+			maxBackups int
+		)
+		if v := query.Get("max-size"); v != "" {
+			if maxSize, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return nil, false, fmt.Errorf("log: parse max-size: %w", err)
+			}
+		}
+		if v := query.Get("max-backups"); v != "" {
+			if maxBackups, err = strconv.Atoi(v); err != nil {
+				return nil, false, fmt.Errorf("log: parse max-backups: %w", err)
+			}
+		}
+		w, err = NewFileSink(path, maxSize, maxBackups)
+	case "syslog":
+		network := ""
+		if u.Host != "" {
+			network = "udp"
+		}
+		w, err = NewSyslogSink(network, u.Host, query.Get("tag"))
+	case "syslog+tcp":
+		w, err = NewSyslogSink("tcp", u.Host, query.Get("tag"))
+	case "tcp":
+		w = NewTCPSink(u.Host, 0)
+	default:
+		return nil, false, fmt.Errorf("%w: %q", ErrUnsupportedSink, u.Scheme)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return w, jsonOutput, nil
+}
+
+// FileSink writes the logs to a file which is rotated once its size
+// exceeds the maximum. The rotated files get a numeric suffix, the
+// most recent one being path.1, and only maxBackups of them are kept.
+// FileSink is safe for concurrent use.
+type FileSink struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewFileSink opens the file sink appending to the file on the path.
+// The file is never rotated if maxSize is not positive.
+func NewFileSink(path string, maxSize int64, maxBackups int) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("log: empty file sink path")
+	}
+	s := &FileSink{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write implements the io.Writer interface.
+func (s *FileSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return 0, os.ErrClosed
+	}
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(p)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+	return n, err
+}
+
+// Reopen closes and reopens the file, so
+// it can be rotated by an external tool.
+func (s *FileSink) Reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			return err
+		}
+	}
+	return s.open()
+}
+
+// Close implements the io.Closer interface.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *FileSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+
+	backup := func(i int) string { return s.path + "." + strconv.Itoa(i) }
+	if s.maxBackups > 0 {
+		for i := s.maxBackups - 1; i > 0; i-- {
+			if err := os.Rename(backup(i), backup(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		if err := os.Rename(s.path, backup(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(s.path); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+// defaultTCPSinkTimeout is the default timeout for
+// connecting to and writing to the TCP sink.
+const defaultTCPSinkTimeout = 5 * time.Second
+
+// TCPSink writes the logs to the TCP connection. The connection is
+// established on the first write and re-established after a failed
+// one. While the remote end is unreachable, the messages are dropped
+// so that logging never blocks on the network for long.
+// TCPSink is safe for concurrent use.
+type TCPSink struct {
+	mu      sync.Mutex
+	addr    string
+	timeout time.Duration
+	conn    net.Conn
+	retryAt time.Time
+}
+
+// NewTCPSink returns the TCP sink writing to the address.
+// The default timeout is used if timeout is not positive.
+func NewTCPSink(addr string, timeout time.Duration) *TCPSink {
+	if timeout <= 0 {
+		timeout = defaultTCPSinkTimeout
+	}
+	return &TCPSink{addr: addr, timeout: timeout}
+}
+
+// Write implements the io.Writer interface.
+func (s *TCPSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if time.Now().Before(s.retryAt) {
+			return len(p), nil // Dropped while backing off.
+		}
+		conn, err := net.DialTimeout("tcp", s.addr, s.timeout)
+		if err != nil {
+			s.retryAt = time.Now().Add(s.timeout)
+			return 0, err
+		}
+		s.conn = conn
+	}
+
+	if err := s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+		return 0, err
+	}
+	n, err := s.conn.Write(p)
+	if err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		s.retryAt = time.Now().Add(s.timeout)
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (s *TCPSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// syslogSink writes the logs to syslog with the
+// priority matching the severity of the message.
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink returns the sink writing to the syslog daemon at the
+// address on the network. The local daemon is used if network is empty.
+func NewSyslogSink(network, raddr, tag string) (io.WriteCloser, error) {
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w}, nil
+}
+
+// Write implements the io.Writer interface.
+func (s *syslogSink) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
+// WriteLevel implements the LevelWriter interface.
+func (s *syslogSink) WriteLevel(l Level, p []byte) (int, error) {
+	var err error
+	switch msg := string(p); l {
+	case VerbosityError:
+		err = s.w.Err(msg)
+	case VerbosityWarning:
+		err = s.w.Warning(msg)
+	case VerbosityInfo:
+		err = s.w.Info(msg)
+	default:
+		err = s.w.Debug(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close implements the io.Closer interface.
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,17 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows || plan9
+
+package log
+
+import (
+	"fmt"
+	"io"
+)
+
+// NewSyslogSink is not supported on this platform.
+func NewSyslogSink(string, string, string) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("%w: syslog", ErrUnsupportedSink)
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// levelSink is a helper type for recording
+// the levels of the messages written to it.
+type levelSink struct {
+	bytes.Buffer
+	levels []Level
+}
+
+// WriteLevel implements LevelWriter.WriteLevel method.
+func (s *levelSink) WriteLevel(l Level, p []byte) (int, error) {
+	s.levels = append(s.levels, l)
+	return s.Write(p)
+}
+
+func TestLoggerExtraSink(t *testing.T) {
+	l, o := loggers, defaults.options
+	t.Cleanup(func() {
+		loggers = l
+		defaults.pin = sync.Once{}
+		defaults.options = o
+	})
+
+	loggers = new(sync.Map)
+	defaults.options = &Options{verbosity: VerbosityDebug}
+	ModifyDefaults()
+
+	var (
+		main   = new(bytes.Buffer)
+		text   = new(levelSink)
+		jsonBB = new(bytes.Buffer)
+	)
+	logger := NewLogger("root",
+		WithSink(main),
+		WithExtraSink(text, false),
+		WithExtraSink(jsonBB, true),
+	).WithName("child").Build()
+
+	logger.Info("msg", "k", 1)
+	logger.Error(nil, "failed")
+
+	if want, have := `"level"="info" "logger"="root/child" "msg"="msg" "k"=1`, strings.Split(main.String(), "\n")[0]; want != have {
+		t.Errorf("main sink mismatch:\nwant: %s\nhave: %s", want, have)
+	}
+	if want, have := main.String(), text.String(); want != have {
+		t.Errorf("text sink mismatch:\nwant: %s\nhave: %s", want, have)
+	}
+	if want, have := []Level{VerbosityInfo, VerbosityError}, text.levels; len(have) != 2 || have[0] != want[0] || have[1] != want[1] {
+		t.Errorf("text sink levels mismatch: want: %v; have: %v", want, have)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.Split(jsonBB.String(), "\n")[0]), &line); err != nil {
+		t.Fatalf("json sink: %v", err)
+	}
+	if line["msg"] != "msg" || line["logger"] != "root/child" || line["k"] != float64(1) {
+		t.Errorf("json sink mismatch: %v", line)
+	}
+
+	// The logger is not discarded if only the main sink is.
+	if NewLogger("discard", WithSink(io.Discard), WithExtraSink(new(bytes.Buffer), false)) == Noop {
+		t.Error("logger with the extra sink must not be discarded")
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "bee.log")
+	s, err := NewFileSink(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for _, msg := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		if _, err := s.Write([]byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, want := range map[string]string{
+		path:        "dddddd\n",
+		path + ".1": "cccccc\n",
+		path + ".2": "bbbbbb\n",
+	} {
+		have, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(have) != want {
+			t.Errorf("%s: want %q; have %q", name, want, have)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("want only 2 backups; stat error: %v", err)
+	}
+
+	// The file moved by an external tool is recreated on reopen.
+	if err := os.Rename(path, path+".old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write([]byte("eeeeee\n")); err != nil {
+		t.Fatal(err)
+	}
+	if have, err := os.ReadFile(path); err != nil || string(have) != "eeeeee\n" {
+		t.Errorf("want %q; have %q (error: %v)", "eeeeee\n", have, err)
+	}
+}
+
+func TestTCPSink(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	w, jsonOutput, err := NewSink("tcp://" + ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if !jsonOutput {
+		t.Error("tcp sink must write json by default")
+	}
+
+	for _, msg := range []string{`{"msg":"a"}`, `{"msg":"b"}`} {
+		if _, err := w.Write([]byte(msg + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case have := <-lines:
+			if have != msg {
+				t.Errorf("want %s; have %s", msg, have)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the message")
+		}
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		url        string
+		jsonOutput bool
+		err        bool
+	}{
+		{url: "file://" + filepath.Join(dir, "a.log"), jsonOutput: false},
+		{url: "file://" + filepath.Join(dir, "b.log") + "?format=json&max-size=1024&max-backups=3", jsonOutput: true},
+		{url: "file://" + filepath.Join(dir, "c.log") + "?max-size=big", err: true},
+		{url: "tcp://127.0.0.1:1?format=text", jsonOutput: false},
+		{url: "tcp://127.0.0.1:1?format=xml", err: true},
+		{url: "ftp://127.0.0.1:1", err: true},
+	} {
+		w, jsonOutput, err := NewSink(tc.url)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: want error", tc.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.url, err)
+			continue
+		}
+		if jsonOutput != tc.jsonOutput {
+			t.Errorf("%s: want json output %t; have %t", tc.url, tc.jsonOutput, jsonOutput)
+		}
+		_ = w.Close()
+	}
+}
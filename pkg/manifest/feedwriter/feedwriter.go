@@ -0,0 +1,127 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package feedwriter keeps the mantaray manifest which is changed in
+// versions: every change stores the new root manifest and commits it,
+// usually as the next update of the feed the manifest is published on.
+// The manifest is read-only unless the changes can be committed.
+package feedwriter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// CommitFunc stores the root manifest of the changed manifest.
+type CommitFunc func(ctx context.Context, root swarm.Address) error
+
+// Options are the options of the Writer.
+type Options struct {
+	// Storer stores the written content and the changed manifests,
+	// the manifest is read-only if it is nil.
+	Storer storage.Storer
+	// Mode is the mode the written chunks are stored with.
+	Mode storage.ModePut
+	// Commit is called with the new root manifest after every change.
+	Commit CommitFunc
+}
+
+// Writer is the manifest with the current root reference.
+// The manifest of the zero root reference is empty.
+type Writer struct {
+	storer storage.Storer
+	root   swarm.Address
+	o      Options
+}
+
+// New returns the writer of the manifest with the root reference.
+func New(storer storage.Storer, root swarm.Address, o Options) *Writer {
+	return &Writer{
+		storer: storer,
+		root:   root,
+		o:      o,
+	}
+}
+
+// Root returns the reference of the current root manifest.
+func (w *Writer) Root() swarm.Address {
+	return w.root
+}
+
+// Writable reports whether the changes of the manifest can be committed.
+func (w *Writer) Writable() bool {
+	return w.o.Storer != nil && w.o.Commit != nil
+}
+
+// Storer returns the storer of the written content,
+// nil if the manifest is read-only.
+func (w *Writer) Storer() storage.Storer {
+	return w.o.Storer
+}
+
+// Mode returns the mode the written chunks are stored with.
+func (w *Writer) Mode() storage.ModePut {
+	return w.o.Mode
+}
+
+// Manifest loads the current root manifest.
+func (w *Writer) Manifest(ctx context.Context) (manifest.Interface, error) {
+	ls := loadsave.NewReadonly(w.storer)
+	if w.Writable() {
+		ls = loadsave.New(w.o.Storer, func() pipeline.Interface {
+			return builder.NewPipelineBuilder(ctx, w.o.Storer, w.o.Mode, false)
+		})
+	}
+	if w.root.IsZero() {
+		return manifest.NewDefaultManifest(ls, false)
+	}
+	return manifest.NewDefaultManifestReference(w.root, ls)
+}
+
+// Update changes the manifest and commits its new root.
+func (w *Writer) Update(ctx context.Context, fn func(manifest.Interface) error) error {
+	m, err := w.Manifest(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(m); err != nil {
+		return err
+	}
+	root, err := m.Store(ctx)
+	if err != nil {
+		return err
+	}
+	if err := w.o.Commit(ctx, root); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	w.root = root
+	return nil
+}
+
+// FeedCommit returns the CommitFunc which publishes every root manifest
+// as the update of the feed with the topic, starting at the next index.
+func FeedCommit(putter storage.Putter, signer crypto.Signer, topic []byte, next feeds.Index) (CommitFunc, error) {
+	updater, err := feeds.NewPutter(putter, signer, topic)
+	if err != nil {
+		return nil, fmt.Errorf("feed putter: %w", err)
+	}
+	return func(ctx context.Context, root swarm.Address) error {
+		at := time.Now().Unix()
+		if err := updater.Put(ctx, next, at, root.Bytes()); err != nil {
+			return err
+		}
+		next = next.Next(at, uint64(at))
+		return nil
+	}, nil
+}
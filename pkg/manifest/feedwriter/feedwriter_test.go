@@ -0,0 +1,115 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feedwriter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/manifest/feedwriter"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		storer = mock.NewStorer()
+		topic  = []byte("topic")
+		entry  = swarm.MustParseHexAddress("aabbcc0000000000000000000000000000000000000000000000000000000000")
+	)
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed := feeds.New(topic, owner)
+
+	commit, err := feedwriter.FeedCommit(storer, signer, topic, sequence.NewIndex(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := feedwriter.New(storer, swarm.ZeroAddress, feedwriter.Options{
+		Storer: storer,
+		Mode:   storage.ModePutUpload,
+		Commit: commit,
+	})
+
+	for _, p := range []string{"a", "b"} {
+		p := p
+		err := w.Update(ctx, func(m manifest.Interface) error {
+			return m.Add(ctx, p, manifest.NewEntry(entry, nil))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ch, _, _, err := sequence.NewFinder(storer, feed).At(ctx, time.Now().Unix(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch == nil {
+		t.Fatal("no feed update")
+	}
+	_, payload, err := feeds.FromChunk(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root := swarm.NewAddress(payload); !root.Equal(w.Root()) {
+		t.Fatalf("got latest update %s, want root %s", root, w.Root())
+	}
+
+	m, err := feedwriter.New(storer, w.Root(), feedwriter.Options{}).Manifest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"a", "b"} {
+		if _, err := m.Lookup(ctx, p); err != nil {
+			t.Fatalf("lookup %s: %v", p, err)
+		}
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		t.Parallel()
+
+		r := feedwriter.New(storer, w.Root(), feedwriter.Options{})
+		if r.Writable() {
+			t.Fatal("writer without the storer is writable")
+		}
+	})
+
+	t.Run("failed commit", func(t *testing.T) {
+		t.Parallel()
+
+		errCommit := errors.New("commit")
+		f := feedwriter.New(storer, w.Root(), feedwriter.Options{
+			Storer: storer,
+			Commit: func(context.Context, swarm.Address) error { return errCommit },
+		})
+		err := f.Update(ctx, func(m manifest.Interface) error {
+			return m.Add(ctx, "c", manifest.NewEntry(entry, nil))
+		})
+		if !errors.Is(err, errCommit) {
+			t.Fatalf("got error %v, want %v", err, errCommit)
+		}
+		if !f.Root().Equal(w.Root()) {
+			t.Fatal("root changed by the failed commit")
+		}
+	})
+}
@@ -22,6 +22,9 @@ const (
 	WebsiteErrorDocumentPathKey   = "website-error-document"
 	EntryMetadataContentTypeKey   = "Content-Type"
 	EntryMetadataFilenameKey      = "Filename"
+	// EntryMetadataUnpaddedLengthKey is the length of the padded
+	// content of the entry without the padding, see package padding.
+	EntryMetadataUnpaddedLengthKey = "Unpadded-Length"
 )
 
 var (
@@ -60,6 +63,25 @@ type Interface interface {
 	IterateAddresses(context.Context, swarm.AddressIterFunc) error
 }
 
+// WalkFunc is called for each path of the manifest visited by Walk,
+// the paths of the directories end with the path separator.
+type WalkFunc func(path string, isDir bool) error
+
+// Walker is implemented by the manifests which can list their paths.
+type Walker interface {
+	// Walk calls the function for each file and directory of the manifest.
+	Walk(context.Context, WalkFunc) error
+}
+
+// PrefixIterator is implemented by the manifests which can iterate over
+// the chunk addresses of a part of the manifest.
+type PrefixIterator interface {
+	// IteratePrefixAddresses iterates over the chunk addresses of the
+	// entries with the paths starting with the prefix and of the manifest
+	// chunks needed to look them up.
+	IteratePrefixAddresses(context.Context, string, swarm.AddressIterFunc) error
+}
+
 // Entry represents a single manifest entry.
 type Entry interface {
 	// Reference returns the address of the file.
@@ -5,6 +5,7 @@
 package manifest
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -134,8 +135,34 @@ func (m *mantarayManifest) IterateAddresses(ctx context.Context, fn swarm.Addres
 		return ErrMissingReference
 	}
 
+	err := m.trie.WalkNode(ctx, []byte{}, m.ls, addressWalker(nil, fn))
+	if err != nil {
+		return fmt.Errorf("manifest iterate addresses: %w", err)
+	}
+
+	return nil
+}
+
+func (m *mantarayManifest) IteratePrefixAddresses(ctx context.Context, prefix string, fn swarm.AddressIterFunc) error {
+	reference := swarm.NewAddress(m.trie.Reference())
+
+	if swarm.ZeroAddress.Equal(reference) {
+		return ErrMissingReference
+	}
+
+	err := m.trie.WalkNodePrefix(ctx, []byte(prefix), m.ls, addressWalker([]byte(prefix), fn))
+	if err != nil {
+		return fmt.Errorf("manifest iterate prefix addresses: %w", err)
+	}
+
+	return nil
+}
+
+// addressWalker returns the function reporting the references of the
+// visited nodes and the entries of the nodes with the path prefix.
+func addressWalker(prefix []byte, fn swarm.AddressIterFunc) mantaray.WalkNodeFunc {
 	emptyAddr := swarm.NewAddress([]byte{31: 0})
-	walker := func(path []byte, node *mantaray.Node, err error) error {
+	return func(path []byte, node *mantaray.Node, err error) error {
 		if err != nil {
 			return err
 		}
@@ -150,7 +177,7 @@ func (m *mantarayManifest) IterateAddresses(ctx context.Context, fn swarm.Addres
 				}
 			}
 
-			if node.IsValueType() && len(node.Entry()) > 0 {
+			if node.IsValueType() && len(node.Entry()) > 0 && bytes.HasPrefix(path, prefix) {
 				entry := swarm.NewAddress(node.Entry())
 				// The following comparison to the emptyAddr is
 				// a dirty hack which prevents the walker to
@@ -170,13 +197,15 @@ func (m *mantarayManifest) IterateAddresses(ctx context.Context, fn swarm.Addres
 
 		return nil
 	}
+}
 
-	err := m.trie.WalkNode(ctx, []byte{}, m.ls, walker)
-	if err != nil {
-		return fmt.Errorf("manifest iterate addresses: %w", err)
-	}
-
-	return nil
+func (m *mantarayManifest) Walk(ctx context.Context, fn WalkFunc) error {
+	return m.trie.Walk(ctx, []byte{}, m.ls, func(path []byte, isDir bool, err error) error {
+		if err != nil {
+			return err
+		}
+		return fn(string(path), isDir)
+	})
 }
 
 type mantarayLoadSaver struct {
@@ -246,6 +246,10 @@ func (n *Node) UnmarshalBinary(data []byte) error {
 
 		refBytesSize := int(data[nodeHeaderSize-1])
 
+		if n.refBytesSize == 0 {
+			// keep the size of the entries added before the node was loaded
+			n.refBytesSize = refBytesSize
+		}
 		n.entry = append([]byte{}, data[nodeHeaderSize:nodeHeaderSize+refBytesSize]...)
 		offset := nodeHeaderSize + refBytesSize // skip entry
 		n.forks = make(map[byte]*fork)
@@ -273,6 +277,10 @@ func (n *Node) UnmarshalBinary(data []byte) error {
 
 		refBytesSize := int(data[nodeHeaderSize-1])
 
+		if n.refBytesSize == 0 {
+			// keep the size of the entries added before the node was loaded
+			n.refBytesSize = refBytesSize
+		}
 		n.entry = append([]byte{}, data[nodeHeaderSize:nodeHeaderSize+refBytesSize]...)
 		offset := nodeHeaderSize + refBytesSize // skip entry
 		// Currently we don't persist the root nodeType when we marshal the manifest, as a result
@@ -221,8 +221,10 @@ func (n *Node) Add(ctx context.Context, path, entry []byte, metadata map[string]
 		if err := n.load(ctx, ls); err != nil {
 			return err
 		}
-		n.ref = nil
 	}
+	// the forks may have been loaded by a lookup already,
+	// the changed node must be saved again in any case
+	n.ref = nil
 	f := n.forks[path[0]]
 	if f == nil {
 		nn := New()
@@ -319,9 +321,14 @@ func (n *Node) Remove(ctx context.Context, path []byte, ls LoadSaver) error {
 	if len(rest) == 0 {
 		// full path matched
 		delete(n.forks, path[0])
+		n.ref = nil
 		return nil
 	}
-	return f.Node.Remove(ctx, rest, ls)
+	if err := f.Node.Remove(ctx, rest, ls); err != nil {
+		return err
+	}
+	n.ref = nil
+	return nil
 }
 
 func common(a, b []byte) (c []byte) {
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"errors"
 	"sync"
 	"testing"
 
@@ -62,6 +63,82 @@ func TestPersistIdempotence(t *testing.T) {
 	}
 }
 
+func TestPersistRemove(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ls := newMockLoadSaver()
+
+	n := mantaray.New()
+	for _, p := range []string{"index.html", "img/1.png", "img/2.png"} {
+		var v [32]byte
+		copy(v[:], p)
+		if err := n.Add(ctx, []byte(p), v[:], nil, ls); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := n.Save(ctx, ls); err != nil {
+		t.Fatal(err)
+	}
+
+	n = mantaray.NewNodeRef(n.Reference())
+	if err := n.Remove(ctx, []byte("img/2.png"), ls); err != nil {
+		t.Fatal(err)
+	}
+	// the entry without the reference is added to the loaded node
+	if err := n.Add(ctx, []byte("docs/"), nil, map[string]string{"name": "docs"}, ls); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Save(ctx, ls); err != nil {
+		t.Fatal(err)
+	}
+
+	n = mantaray.NewNodeRef(n.Reference())
+	if _, err := n.Lookup(ctx, []byte("img/2.png"), ls); !errors.Is(err, mantaray.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, mantaray.ErrNotFound)
+	}
+	for _, p := range []string{"index.html", "img/1.png", "docs/"} {
+		if _, err := n.LookupNode(ctx, []byte(p), ls); err != nil {
+			t.Fatalf("lookup %s: %v", p, err)
+		}
+	}
+}
+
+func TestPersistAddAfterLookup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ls := newMockLoadSaver()
+
+	var v [32]byte
+	n := mantaray.New()
+	if err := n.Add(ctx, []byte("img/1.png"), v[:], nil, ls); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Save(ctx, ls); err != nil {
+		t.Fatal(err)
+	}
+
+	n = mantaray.NewNodeRef(n.Reference())
+	// the failed lookup loads the forks of the node
+	if _, err := n.Lookup(ctx, []byte("img/2.png"), ls); !errors.Is(err, mantaray.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, mantaray.ErrNotFound)
+	}
+	if err := n.Add(ctx, []byte("img/2.png"), v[:], nil, ls); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Save(ctx, ls); err != nil {
+		t.Fatal(err)
+	}
+
+	n = mantaray.NewNodeRef(n.Reference())
+	for _, p := range []string{"img/1.png", "img/2.png"} {
+		if _, err := n.Lookup(ctx, []byte(p), ls); err != nil {
+			t.Fatalf("lookup %s: %v", p, err)
+		}
+	}
+}
+
 type addr [32]byte
 type mockLoadSaver struct {
 	mtx   sync.Mutex
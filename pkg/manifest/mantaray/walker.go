@@ -4,7 +4,10 @@
 
 package mantaray
 
-import "context"
+import (
+	"bytes"
+	"context"
+)
 
 // WalkNodeFunc is the type of the function called for each node visited
 // by WalkNode.
@@ -53,6 +56,47 @@ func (n *Node) WalkNode(ctx context.Context, root []byte, l Loader, walkFn WalkN
 	return err
 }
 
+// walkNodePrefix descends only the forks leading to the prefix and the
+// forks under it, calling walkFn.
+func walkNodePrefix(ctx context.Context, path, prefix []byte, l Loader, n *Node, walkFn WalkNodeFunc) error {
+	if n.forks == nil {
+		if err := n.load(ctx, l); err != nil {
+			return err
+		}
+	}
+
+	err := walkNodeFnCopyBytes(ctx, path, n, nil, walkFn)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range n.forks {
+		nextPath := append(path[:0:0], path...)
+		nextPath = append(nextPath, v.prefix...)
+
+		switch {
+		case bytes.HasPrefix(nextPath, prefix):
+			err = walkNode(ctx, nextPath, l, v.Node, walkFn)
+		case bytes.HasPrefix(prefix, nextPath):
+			err = walkNodePrefix(ctx, nextPath, prefix, l, v.Node, walkFn)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WalkNodePrefix walks the nodes of the tree structure with the paths
+// starting with the prefix and the nodes leading to them from the root,
+// calling walkFn for each of them. The other nodes are not loaded.
+func (n *Node) WalkNodePrefix(ctx context.Context, prefix []byte, l Loader, walkFn WalkNodeFunc) error {
+	return walkNodePrefix(ctx, []byte{}, prefix, l, n, walkFn)
+}
+
 // WalkFunc is the type of the function called for each file or directory
 // visited by Walk.
 type WalkFunc func(path []byte, isDir bool, err error) error
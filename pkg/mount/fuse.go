@@ -0,0 +1,155 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package mount
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+)
+
+// Mount mounts the collection with the root manifest
+// as the read-only FUSE file system on the directory.
+func Mount(ctx context.Context, dir string, getter storage.Getter, root swarm.Address, o Options) (Server, error) {
+	files, err := Index(ctx, getter, root)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &rootNode{getter: getter, files: files, prefetch: o.Prefetch}
+	return fs.Mount(dir, r, &fs.Options{
+		MountOptions: fuse.MountOptions{
+			FsName: root.String(),
+			Name:   "swarm",
+			Debug:  o.Debug,
+		},
+	})
+}
+
+// rootNode is the root directory, which builds the tree of the collection.
+type rootNode struct {
+	fs.Inode
+
+	getter   storage.Getter
+	files    []File
+	prefetch int64
+}
+
+var _ fs.NodeOnAdder = (*rootNode)(nil)
+
+func (r *rootNode) OnAdd(ctx context.Context) {
+	dir := func(p string) *fs.Inode {
+		n := &r.Inode
+		for _, name := range strings.Split(p, "/") {
+			if name == "" || name == "." {
+				continue
+			}
+			child := n.GetChild(name)
+			if child == nil {
+				child = n.NewPersistentInode(ctx, &fs.Inode{}, fs.StableAttr{Mode: fuse.S_IFDIR})
+				n.AddChild(name, child, true)
+			}
+			n = child
+		}
+		return n
+	}
+
+	for _, f := range r.files {
+		if f.Dir {
+			dir(f.Path)
+			continue
+		}
+		if f.Reference.IsZero() {
+			continue
+		}
+		parent := dir(path.Dir(f.Path))
+		file := &fileNode{getter: r.getter, file: f, prefetch: r.prefetch}
+		parent.AddChild(path.Base(f.Path), parent.NewPersistentInode(ctx, file, fs.StableAttr{}), true)
+	}
+}
+
+// fileNode is the file of the collection.
+type fileNode struct {
+	fs.Inode
+
+	getter   storage.Getter
+	file     File
+	prefetch int64
+
+	once sync.Once
+	size int64
+	err  error
+}
+
+var (
+	_ fs.NodeGetattrer = (*fileNode)(nil)
+	_ fs.NodeOpener    = (*fileNode)(nil)
+	_ fs.NodeReader    = (*fileNode)(nil)
+	_ fs.NodeReleaser  = (*fileNode)(nil)
+)
+
+// stat retrieves the size of the file on the first access.
+func (f *fileNode) stat(ctx context.Context) (int64, error) {
+	f.once.Do(func() {
+		var r *Reader
+		r, f.err = NewReader(ctx, f.getter, f.file.Reference, 0)
+		if f.err == nil {
+			f.size = r.Size()
+			f.err = r.Close()
+		}
+	})
+	return f.size, f.err
+}
+
+func (f *fileNode) Getattr(ctx context.Context, fh fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	size, err := f.stat(ctx)
+	if err != nil {
+		return syscall.EIO
+	}
+	out.Mode = 0444
+	out.Size = uint64(size)
+	return fs.OK
+}
+
+func (f *fileNode) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR|syscall.O_APPEND|syscall.O_TRUNC) != 0 {
+		return nil, 0, syscall.EROFS
+	}
+	// the reader outlives the open request
+	r, err := NewReader(context.Background(), f.getter, f.file.Reference, f.prefetch)
+	if err != nil {
+		return nil, 0, syscall.EIO
+	}
+	// the content of the file never changes
+	return r, fuse.FOPEN_KEEP_CACHE, fs.OK
+}
+
+func (f *fileNode) Read(ctx context.Context, fh fs.FileHandle, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	r, ok := fh.(*Reader)
+	if !ok {
+		return nil, syscall.EBADF
+	}
+	n, err := r.ReadAt(dest, off)
+	if err != nil && n == 0 && off < r.Size() {
+		return nil, syscall.EIO
+	}
+	return fuse.ReadResultData(dest[:n]), fs.OK
+}
+
+func (f *fileNode) Release(ctx context.Context, fh fs.FileHandle) syscall.Errno {
+	if r, ok := fh.(*Reader); ok {
+		_ = r.Close()
+	}
+	return fs.OK
+}
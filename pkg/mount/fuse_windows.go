@@ -0,0 +1,17 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mount
+
+import (
+	"context"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Mount is not supported on Windows.
+func Mount(context.Context, string, storage.Getter, swarm.Address, Options) (Server, error) {
+	return nil, ErrNotSupported
+}
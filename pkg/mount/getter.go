@@ -0,0 +1,118 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+var errInvalidChunk = errors.New("mount: invalid chunk")
+
+// Cache keeps the recently retrieved chunks in memory, so that the
+// prefetched chunks and the intermediate chunks of the files are not
+// retrieved again.
+type Cache struct {
+	getter storage.Getter
+	chunks *lru.Cache
+}
+
+// NewCache returns the getter which keeps up to size chunks retrieved by the getter.
+func NewCache(getter storage.Getter, size int) (*Cache, error) {
+	chunks, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{getter: getter, chunks: chunks}, nil
+}
+
+// Get implements the storage.Getter interface.
+func (c *Cache) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	if ch, ok := c.chunks.Get(addr.ByteString()); ok {
+		return ch.(swarm.Chunk), nil
+	}
+	ch, err := c.getter.Get(ctx, mode, addr)
+	if err != nil {
+		return nil, err
+	}
+	c.chunks.Add(addr.ByteString(), ch)
+	return ch, nil
+}
+
+// APIGetter retrieves the chunks from the chunks endpoint of the Bee API.
+type APIGetter struct {
+	client   *http.Client
+	endpoint *url.URL
+}
+
+// NewAPIGetter returns the getter of the chunks from the Bee API on the endpoint.
+func NewAPIGetter(client *http.Client, endpoint *url.URL) *APIGetter {
+	return &APIGetter{client: client, endpoint: endpoint}
+}
+
+// Get implements the storage.Getter interface.
+func (g *APIGetter) Get(ctx context.Context, _ storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	u := g.endpoint.JoinPath("chunks", addr.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, storage.ErrNotFound
+	default:
+		return nil, fmt.Errorf("get chunk %s: %s", addr, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, swarm.ChunkWithSpanSize+1))
+	if err != nil {
+		return nil, err
+	}
+	ch := swarm.NewChunk(addr, data)
+	if !cac.Valid(ch) {
+		return nil, fmt.Errorf("chunk %s: %w", addr, errInvalidChunk)
+	}
+	return ch, nil
+}
+
+// IsPinned reports whether the reference is pinned by the node of the Bee API on the endpoint.
+func (g *APIGetter) IsPinned(ctx context.Context, reference swarm.Address) (bool, error) {
+	u := g.endpoint.JoinPath("pins", reference.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("get pin %s: %s", reference, resp.Status)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mount exposes the Swarm collections as read-only file systems.
+// The files are read with the joiner on demand, so that only the parts
+// of the files which are accessed are retrieved, and the data ahead of
+// the sequential reads is prefetched.
+package mount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// DefaultPrefetch is the default size of the data prefetched ahead of the sequential reads.
+const DefaultPrefetch = 4 * 1024 * 1024
+
+var (
+	// ErrNotSupported is returned if the file systems can not be mounted on the platform.
+	ErrNotSupported = errors.New("mount: not supported on this platform")
+
+	errReadOnly = errors.New("mount: read-only file system")
+)
+
+// Options are the options of the mounted file system.
+type Options struct {
+	// Prefetch is the size of the data prefetched ahead of the sequential
+	// reads, the prefetching is disabled if it is zero.
+	Prefetch int64
+	// Debug logs the FUSE requests.
+	Debug bool
+}
+
+// Server is the mounted file system.
+type Server interface {
+	// Wait blocks until the file system is unmounted.
+	Wait()
+	// Unmount unmounts the file system.
+	Unmount() error
+}
+
+// File is the file or the directory of the collection.
+type File struct {
+	Path        string
+	Dir         bool
+	Reference   swarm.Address
+	ContentType string
+}
+
+// Index lists the files and the directories of the collection manifest.
+func Index(ctx context.Context, getter storage.Getter, root swarm.Address) ([]File, error) {
+	m, err := manifest.NewDefaultManifestReference(root, loadsave.NewReadonly(getterStorer{getter}))
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+	w, ok := m.(manifest.Walker)
+	if !ok {
+		return nil, fmt.Errorf("manifest type %s can not be listed", m.Type())
+	}
+
+	var files []File
+	err = w.Walk(ctx, func(p string, isDir bool) error {
+		if p == "" || p == manifest.RootPath {
+			return nil
+		}
+		if isDir {
+			files = append(files, File{Path: strings.TrimSuffix(p, "/"), Dir: true})
+			return nil
+		}
+		e, err := m.Lookup(ctx, p)
+		if err != nil {
+			return fmt.Errorf("lookup %s: %w", p, err)
+		}
+		files = append(files, File{
+			Path:        p,
+			Reference:   e.Reference(),
+			ContentType: e.Metadata()[manifest.EntryMetadataContentTypeKey],
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// getterStorer is the read-only storer used by the manifest load-saver.
+type getterStorer struct {
+	storage.Getter
+}
+
+func (getterStorer) Put(context.Context, storage.ModePut, ...swarm.Chunk) ([]bool, error) {
+	return nil, errReadOnly
+}
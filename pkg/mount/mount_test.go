@@ -0,0 +1,270 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mount_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/mount"
+	"github.com/ethersphere/bee/pkg/spinlock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+func store(t *testing.T, s storage.Storer, data []byte) swarm.Address {
+	t.Helper()
+
+	ctx := context.Background()
+	ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, s, storage.ModePutUpload, false), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+func TestIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := mock.NewStorer()
+
+	m, err := manifest.NewDefaultManifest(loadsave.New(s, func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, s, storage.ModePutUpload, false)
+	}), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"a.txt":        "alpha",
+		"docs/b.txt":   "beta",
+		"docs/x/c.txt": "gamma",
+	}
+	for p, content := range files {
+		if err := m.Add(ctx, p, manifest.NewEntry(store(t, s, []byte(content)), map[string]string{
+			manifest.EntryMetadataContentTypeKey: "text/plain",
+		})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Add(ctx, manifest.RootPath, manifest.NewEntry(swarm.ZeroAddress, map[string]string{
+		manifest.WebsiteIndexDocumentSuffixKey: "a.txt",
+	})); err != nil {
+		t.Fatal(err)
+	}
+	root, err := m.Store(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := mount.Index(ctx, s, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dirs := make(map[string]bool)
+	for _, f := range index {
+		if f.Dir {
+			dirs[f.Path] = true
+			continue
+		}
+		content, ok := files[f.Path]
+		if !ok {
+			t.Fatalf("unexpected file %s", f.Path)
+		}
+		delete(files, f.Path)
+		if f.ContentType != "text/plain" {
+			t.Fatalf("got content type %q, want text/plain", f.ContentType)
+		}
+
+		r, err := mount.NewReader(ctx, s, f.Reference, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(io.NewSectionReader(r, 0, r.Size()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Fatalf("%s: got %q, want %q", f.Path, got, content)
+		}
+		_ = r.Close()
+	}
+	if len(files) != 0 {
+		t.Fatalf("missing files %v", files)
+	}
+	for _, d := range []string{"docs", "docs/x"} {
+		if !dirs[d] {
+			t.Fatalf("missing directory %s in %v", d, dirs)
+		}
+	}
+}
+
+// countingGetter records the retrieved chunks.
+type countingGetter struct {
+	storage.Getter
+
+	mu  sync.Mutex
+	got map[string]int
+}
+
+func (g *countingGetter) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	g.mu.Lock()
+	g.got[addr.ByteString()]++
+	g.mu.Unlock()
+	return g.Getter.Get(ctx, mode, addr)
+}
+
+func (g *countingGetter) count() (chunks, gets int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, n := range g.got {
+		gets += n
+	}
+	return len(g.got), gets
+}
+
+func TestReaderPrefetch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := mock.NewStorer()
+	data := testutil.RandBytes(t, 64*swarm.ChunkSize)
+	ref := store(t, s, data)
+
+	counter := &countingGetter{Getter: s, got: make(map[string]int)}
+	cache, err := mount.NewCache(counter, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const prefetch = 16 * swarm.ChunkSize
+	r, err := mount.NewReader(ctx, cache, ref, prefetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if r.Size() != int64(len(data)) {
+		t.Fatalf("got size %d, want %d", r.Size(), len(data))
+	}
+
+	buf := make([]byte, swarm.ChunkSize)
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, data[:swarm.ChunkSize]) {
+		t.Fatal("data mismatch")
+	}
+
+	// the chunks ahead of the read are retrieved in the background
+	err = spinlock.Wait(5*time.Second, func() bool {
+		chunks, _ := counter.count()
+		// the root and the data chunks
+		return chunks >= 2+prefetch/swarm.ChunkSize
+	})
+	if err != nil {
+		t.Fatal("chunks ahead of the read were not prefetched")
+	}
+
+	// the prefetched data is read from the cache
+	_, before := counter.count()
+	if _, err := r.ReadAt(buf, swarm.ChunkSize); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, data[swarm.ChunkSize:2*swarm.ChunkSize]) {
+		t.Fatal("data mismatch")
+	}
+	if _, after := counter.count(); after != before {
+		t.Fatalf("got %d retrievals after reading the prefetched data, want %d", after, before)
+	}
+
+	// the read at the end of the file is short
+	n, err := r.ReadAt(buf, int64(len(data)-10))
+	if n != 10 || !errors.Is(err, io.EOF) {
+		t.Fatalf("got %d bytes with error %v, want 10 bytes with %v", n, err, io.EOF)
+	}
+	if !bytes.Equal(buf[:n], data[len(data)-10:]) {
+		t.Fatal("data mismatch")
+	}
+}
+
+func TestAPIGetter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := mock.NewStorer()
+	ref := store(t, s, []byte("swarm"))
+	invalid := swarm.MustParseHexAddress("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/pins/"+ref.String():
+			_, _ = io.WriteString(w, "{}")
+		case r.URL.Path == "/chunks/"+invalid.String():
+			_, _ = io.WriteString(w, "not a chunk")
+		case strings.HasPrefix(r.URL.Path, "/chunks/"):
+			addr, err := swarm.ParseHexAddress(strings.TrimPrefix(r.URL.Path, "/chunks/"))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			ch, err := s.Get(r.Context(), storage.ModeGetRequest, addr)
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(ch.Data())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	endpoint, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := mount.NewAPIGetter(srv.Client(), endpoint)
+
+	ch, err := g.Get(ctx, storage.ModeGetRequest, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ch.Data()[swarm.SpanSize:], []byte("swarm")) {
+		t.Fatalf("got chunk data %q", ch.Data())
+	}
+	if _, err := g.Get(ctx, storage.ModeGetRequest, swarm.RandAddress(t)); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+	if _, err := g.Get(ctx, storage.ModeGetRequest, invalid); err == nil {
+		t.Fatal("expected error for the invalid chunk")
+	}
+
+	for address, want := range map[string]bool{ref.String(): true, invalid.String(): false} {
+		pinned, err := g.IsPinned(ctx, swarm.MustParseHexAddress(address))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pinned != want {
+			t.Fatalf("%s: got pinned %v, want %v", address, pinned, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mount
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Reader reads the file with the joiner. When the file is read
+// sequentially, the data ahead of the last read is prefetched, so that
+// the chunks are retrieved before they are read. The prefetched chunks
+// are kept by the getter, which is expected to be the Cache.
+type Reader struct {
+	joiner   file.Joiner
+	size     int64
+	prefetch int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu         sync.Mutex
+	next       int64 // offset following the last read
+	prefetched int64 // offset up to which the data is prefetched
+	fetching   bool
+}
+
+// NewReader returns the reader of the file with the reference.
+func NewReader(ctx context.Context, getter storage.Getter, reference swarm.Address, prefetch int64) (*Reader, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	j, size, err := joiner.New(ctx, getter, reference)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &Reader{
+		joiner:   j,
+		size:     size,
+		prefetch: prefetch,
+		ctx:      ctx,
+		cancel:   cancel,
+	}, nil
+}
+
+// Size returns the size of the file.
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// ReadAt implements the io.ReaderAt interface.
+func (r *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	want := len(p)
+	// the joiner reads up to the capacity of the buffer
+	if rest := r.size - off; int64(len(p)) > rest {
+		p = p[:rest:rest]
+	} else {
+		p = p[:len(p):len(p)]
+	}
+	n, err := r.joiner.ReadAt(p, off)
+	if err != nil {
+		return n, err
+	}
+	r.advance(off, off+int64(n))
+	if n < want {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// advance records the read and starts the prefetching of the data ahead
+// of it if the file is read sequentially.
+func (r *Reader) advance(start, end int64) {
+	if r.prefetch <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sequential := start == r.next
+	r.next = end
+	if !sequential || r.fetching || r.ctx.Err() != nil {
+		return
+	}
+
+	from := end
+	if r.prefetched > from {
+		from = r.prefetched
+	}
+	to := end + r.prefetch
+	if to > r.size {
+		to = r.size
+	}
+	// the data is prefetched in the steps of the half of the window
+	if to-from < r.prefetch/2 && to < r.size {
+		return
+	}
+	if from >= to {
+		return
+	}
+
+	r.fetching = true
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		buf := make([]byte, to-from)
+		_, err := r.joiner.ReadAt(buf, from)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.fetching = false
+		if err == nil {
+			r.prefetched = to
+		}
+	}()
+}
+
+// Close stops the prefetching.
+func (r *Reader) Close() error {
+	r.cancel()
+	r.wg.Wait()
+	return nil
+}
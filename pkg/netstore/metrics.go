@@ -10,9 +10,10 @@ import (
 )
 
 type metrics struct {
-	LocalChunksCounter        prometheus.Counter
-	InvalidLocalChunksCounter prometheus.Counter
-	RetrievedChunksCounter    prometheus.Counter
+	LocalChunksCounter         prometheus.Counter
+	InvalidLocalChunksCounter  prometheus.Counter
+	RetrievedChunksCounter     prometheus.Counter
+	CoalescedRetrievalsCounter prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -37,6 +38,12 @@ func newMetrics() metrics {
 			Name:      "chunks_retrieved_from_network",
 			Help:      "Total no. of chunks retrieved from network.",
 		}),
+		CoalescedRetrievalsCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "coalesced_retrievals",
+			Help:      "Total no. of chunk requests that shared a network retrieval with concurrent requests.",
+		}),
 	}
 }
 
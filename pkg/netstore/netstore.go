@@ -16,12 +16,15 @@ import (
 	"time"
 
 	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/chunktrace"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/retrieval"
 	"github.com/ethersphere/bee/pkg/soc"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"resenje.org/singleflight"
 )
 
 // loggerName is the tree path name of the logger for this package.
@@ -41,6 +44,10 @@ type store struct {
 	sCancel    context.CancelFunc
 	wg         sync.WaitGroup
 	metrics    metrics
+
+	// retrievals coalesces the concurrent gets of the same chunk from the
+	// network, so that the retrieved chunk is put to the local store once
+	retrievals singleflight.Group
 }
 
 var (
@@ -66,6 +73,17 @@ func New(s storage.Storer, validStamp postage.ValidStampFn, r retrieval.Interfac
 // If the network path is taken, the method also stores the found chunk into the
 // local-store.
 func (s *store) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (ch swarm.Chunk, err error) {
+	if t := chunktrace.FromContext(ctx); t != nil {
+		r := &chunktrace.Record{Address: addr}
+		ctx = chunktrace.WithRecord(ctx, r)
+		start := time.Now()
+		defer func() {
+			r.Latency = time.Since(start)
+			r.Err = err
+			t.Add(r)
+		}()
+	}
+
 	ch, err = s.Storer.Get(ctx, mode, addr)
 	if err == nil {
 		s.metrics.LocalChunksCounter.Inc()
@@ -83,20 +101,77 @@ func (s *store) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Addres
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, errInvalidLocalChunk) {
 			// request from network
-			ch, err = s.retrieval.RetrieveChunk(ctx, addr, swarm.ZeroAddress)
-			if err != nil {
-				return nil, err
-			}
-			s.wg.Add(1)
-			s.put(ch, mode)
-			s.metrics.RetrievedChunksCounter.Inc()
-			return ch, nil
+			return s.retrieve(ctx, mode, addr)
 		}
 		return nil, fmt.Errorf("netstore get: %w", err)
 	}
+	if r := chunktrace.RecordFromContext(ctx); r != nil {
+		r.Local = true
+	}
 	return ch, nil
 }
 
+// GetMulti gets the chunks from the local store only, it fails with
+// storage.ErrNotFound if any of the chunks is not found or is invalid,
+// in which case the chunks should be retrieved one by one with Get.
+func (s *store) GetMulti(ctx context.Context, mode storage.ModeGet, addrs ...swarm.Address) ([]swarm.Chunk, error) {
+	start := time.Now()
+	chs, err := s.Storer.GetMulti(ctx, mode, addrs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range chs {
+		if !cac.Valid(ch) && !soc.Valid(ch) {
+			s.logger.Warning("netstore: got invalid chunk from localstore", "chunk_address", ch.Address())
+			s.metrics.InvalidLocalChunksCounter.Inc()
+			return nil, storage.ErrNotFound
+		}
+	}
+	s.metrics.LocalChunksCounter.Add(float64(len(chs)))
+	if t := chunktrace.FromContext(ctx); t != nil {
+		latency := time.Since(start)
+		for _, ch := range chs {
+			t.Add(&chunktrace.Record{Address: ch.Address(), Local: true, Latency: latency})
+		}
+	}
+	return chs, nil
+}
+
+// retrieve requests the chunk from the network. The retrieval service already
+// coalesces the identical requests in flight, but each of the callers would
+// put the returned chunk to the local store on its own. The concurrent
+// requests for the same chunk are therefore coalesced here too, so that the
+// retrieved chunk is stored and counted only once.
+func (s *store) retrieve(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	// the put mode depends on the get mode, so only the requests
+	// with the same mode are coalesced
+	key := fmt.Sprintf("%s_%d", addr.ByteString(), mode)
+
+	// topCtx is passing the tracing span to the shared retrieval
+	topCtx := ctx
+	v, shared, err := s.retrievals.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		ctx = tracing.WithContext(ctx, tracing.FromContext(topCtx))
+		if r := chunktrace.RecordFromContext(topCtx); r != nil {
+			ctx = chunktrace.WithRecord(ctx, r)
+		}
+		ch, err := s.retrieval.RetrieveChunk(ctx, addr, swarm.ZeroAddress)
+		if err != nil {
+			return nil, err
+		}
+		s.wg.Add(1)
+		s.put(ch, mode)
+		s.metrics.RetrievedChunksCounter.Inc()
+		return ch, nil
+	})
+	if shared {
+		s.metrics.CoalescedRetrievalsCounter.Inc()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.(swarm.Chunk), nil
+}
+
 // put will store the chunk into storage asynchronously
 func (s *store) put(ch swarm.Chunk, mode storage.ModeGet) {
 	go func() {
@@ -8,10 +8,12 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/chunktrace"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/netstore"
 	"github.com/ethersphere/bee/pkg/postage"
@@ -98,6 +100,45 @@ func TestNetstoreNoRetrieval(t *testing.T) {
 	}
 }
 
+// TestNetstoreChunkTrace verifies that the chunks are recorded
+// in the trace of the context.
+func TestNetstoreChunkTrace(t *testing.T) {
+	t.Parallel()
+
+	testChunk := chunktesting.GenerateTestRandomChunk()
+	retrieve, store, nstore := newRetrievingNetstore(t, noopValidStamp, testChunk)
+	addr := testChunk.Address()
+
+	trace := chunktrace.New()
+	ctx := chunktrace.WithTrace(context.Background(), trace)
+	if _, err := nstore.Get(ctx, storage.ModeGetRequest, addr); err != nil {
+		t.Fatal(err)
+	}
+	_ = waitAndGetChunk(t, store, addr, storage.ModeGetRequest)
+	if _, err := nstore.Get(ctx, storage.ModeGetRequest, addr); err != nil {
+		t.Fatal(err)
+	}
+	missing := chunktesting.GenerateTestRandomChunk().Address()
+	retrieve.failure = true
+	if _, err := nstore.Get(ctx, storage.ModeGetRequest, missing); err == nil {
+		t.Fatal("expected error")
+	}
+
+	records := trace.Records()
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	if r := records[0]; !r.Address.Equal(addr) || r.Local || r.Peers() != 1 || r.Err != nil || r.Latency <= 0 {
+		t.Fatalf("unexpected record of the retrieved chunk %+v", r)
+	}
+	if r := records[1]; !r.Address.Equal(addr) || !r.Local || r.Peers() != 0 || r.Err != nil {
+		t.Fatalf("unexpected record of the local chunk %+v", r)
+	}
+	if r := records[2]; !r.Address.Equal(missing) || r.Local || r.Err == nil {
+		t.Fatalf("unexpected record of the missing chunk %+v", r)
+	}
+}
+
 func TestInvalidChunkNetstoreRetrieval(t *testing.T) {
 	t.Parallel()
 
@@ -196,6 +237,82 @@ func TestInvalidPostageStamp(t *testing.T) {
 	}
 }
 
+// countingStorer counts the chunks put to the store.
+type countingStorer struct {
+	storage.Storer
+	puts atomic.Int32
+}
+
+func (s *countingStorer) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	s.puts.Add(int32(len(chs)))
+	return s.Storer.Put(ctx, mode, chs...)
+}
+
+// TestNetstoreRetrievalCoalescing verifies that the concurrent requests
+// for the same chunk result in a single retrieval from the network and
+// a single put of the retrieved chunk to the local store.
+func TestNetstoreRetrievalCoalescing(t *testing.T) {
+	t.Parallel()
+
+	testChunk := chunktesting.GenerateTestRandomChunk()
+	retrieve := &retrievalMock{
+		chunk:   testChunk,
+		release: make(chan struct{}),
+	}
+	store := &countingStorer{Storer: mock.NewStorer()}
+	nstore := netstore.New(store, noopValidStamp, retrieve, log.Noop)
+	testutil.CleanupCloser(t, nstore)
+	addr := testChunk.Address()
+
+	const requests = 10
+	var wg sync.WaitGroup
+	errC := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ch, err := nstore.Get(context.Background(), storage.ModeGetRequest, addr)
+			if err == nil && !bytes.Equal(ch.Data(), testChunk.Data()) {
+				err = errors.New("chunk data not equal to expected data")
+			}
+			errC <- err
+		}()
+	}
+
+	err := spinlock.Wait(time.Second, func() bool {
+		return atomic.LoadInt32(&retrieve.callCount) > 0
+	})
+	if err != nil {
+		t.Fatal("retrieve request not issued")
+	}
+	// let the rest of the requests join the retrieval in flight
+	time.Sleep(100 * time.Millisecond)
+	close(retrieve.release)
+
+	wg.Wait()
+	close(errC)
+	for err := range errC {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c := atomic.LoadInt32(&retrieve.callCount); c != 1 {
+		t.Fatalf("call count %d", c)
+	}
+
+	d := waitAndGetChunk(t, store, addr, storage.ModeGetRequest)
+	if !bytes.Equal(d.Data(), testChunk.Data()) {
+		t.Fatal("chunk data not equal to expected data")
+	}
+	// the background puts are done once the netstore is closed
+	if err := nstore.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if c := store.puts.Load(); c != 1 {
+		t.Fatalf("put count %d, want 1", c)
+	}
+}
+
 func waitAndGetChunk(t *testing.T, store storage.Storer, addr swarm.Address, mode storage.ModeGet) (chunk swarm.Chunk) {
 	t.Helper()
 
@@ -236,6 +353,7 @@ type retrievalMock struct {
 	failure   bool
 	addr      swarm.Address
 	chunk     swarm.Chunk
+	release   chan struct{}
 }
 
 func (r *retrievalMock) RetrieveChunk(ctx context.Context, addr, sourceAddr swarm.Address) (chunk swarm.Chunk, err error) {
@@ -244,7 +362,15 @@ func (r *retrievalMock) RetrieveChunk(ctx context.Context, addr, sourceAddr swar
 	}
 	r.called = true
 	atomic.AddInt32(&r.callCount, 1)
+	chunktrace.AddPeer(ctx)
 	r.addr = addr
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
 	return r.chunk.WithStamp(postagetesting.MustNewStamp()), nil
 }
 
@@ -199,6 +199,43 @@ func InitChequebookService(
 	return chequebookService, nil
 }
 
+// InitChequebookWithdrawal will initialize the scheduled withdrawals of the
+// chequebook balance above the threshold to the withdrawal address.
+func InitChequebookWithdrawal(
+	logger log.Logger,
+	chequebookService chequebook.Service,
+	erc20Service erc20.Service,
+	transactionService transaction.Service,
+	overlayEthAddress common.Address,
+	withdrawalAddress string,
+	withdrawalThreshold string,
+	withdrawalInterval time.Duration,
+) (*chequebook.WithdrawalScheduler, error) {
+	if !common.IsHexAddress(withdrawalAddress) {
+		return nil, fmt.Errorf("withdrawal address \"%s\" is not a valid address", withdrawalAddress)
+	}
+
+	threshold, ok := new(big.Int).SetString(withdrawalThreshold, 10)
+	if !ok || threshold.Sign() < 0 {
+		return nil, fmt.Errorf("withdrawal threshold \"%s\" cannot be parsed", withdrawalThreshold)
+	}
+
+	if withdrawalInterval <= 0 {
+		return nil, fmt.Errorf("withdrawal interval %s must be positive", withdrawalInterval)
+	}
+
+	return chequebook.NewWithdrawalScheduler(
+		logger,
+		chequebookService,
+		erc20Service,
+		transactionService,
+		overlayEthAddress,
+		common.HexToAddress(withdrawalAddress),
+		threshold,
+		withdrawalInterval,
+	), nil
+}
+
 func initChequeStoreCashout(
 	stateStore storage.StateStorer,
 	swapBackend transaction.Backend,
@@ -240,6 +277,8 @@ func InitSwap(
 	priceOracleAddress string,
 	chainID int64,
 	transactionService transaction.Service,
+	bounceThreshold int,
+	bounceBlocklistDuration time.Duration,
 ) (*swap.Service, priceoracle.Service, error) {
 
 	var currentPriceOracleAddress common.Address
@@ -274,8 +313,15 @@ func InitSwap(
 		cashoutService,
 		accounting,
 		cashoutAddress,
+		p2ps,
+		bounceThreshold,
+		bounceBlocklistDuration,
 	)
 
+	if err := swapService.Start(); err != nil {
+		return nil, nil, fmt.Errorf("swap start: %w", err)
+	}
+
 	swapProtocol.SetSwap(swapService)
 
 	err := p2ps.AddProtocol(swapProtocol.Protocol())
@@ -70,6 +70,7 @@ type DevBee struct {
 	apiCloser        io.Closer
 	pssCloser        io.Closer
 	tagsCloser       io.Closer
+	tagStoreCloser   io.Closer
 	errorLogWriter   io.Writer
 	apiServer        *http.Server
 	debugAPIServer   *http.Server
@@ -214,7 +215,7 @@ func NewDevBee(logger log.Logger, o *DevOptions) (b *DevBee, err error) {
 			ErrorLog:          stdlog.New(b.errorLogWriter, "", 0),
 		}
 
-		debugApiService.MountTechnicalDebug()
+		debugApiService.MountTechnicalDebug(false)
 		debugApiService.SetProbe(probe)
 
 		go func() {
@@ -241,13 +242,19 @@ func NewDevBee(logger log.Logger, o *DevOptions) (b *DevBee, err error) {
 		},
 	}
 
-	storer, err := localstore.New("", swarmAddress.Bytes(), stateStore, lo, logger)
+	storer, err := localstore.NewInmem(swarmAddress.Bytes(), stateStore, lo, logger)
 	if err != nil {
 		return nil, fmt.Errorf("localstore: %w", err)
 	}
 	b.localstoreCloser = storer
 
-	tagService := tags.NewTags(stateStore, logger)
+	tagStore, err := tags.NewStore("")
+	if err != nil {
+		return nil, fmt.Errorf("tags store: %w", err)
+	}
+	b.tagStoreCloser = tagStore
+
+	tagService := tags.NewTags(tagStore, logger)
 	b.tagsCloser = tagService
 
 	pssService := pss.New(mockKey, logger)
@@ -400,6 +407,7 @@ func NewDevBee(logger log.Logger, o *DevOptions) (b *DevBee, err error) {
 		Staking:          mockStaking,
 		Steward:          mockSteward,
 		SyncStatus:       syncStatusFn,
+		StateStorer:      stateStore,
 	}
 
 	var erc20 = erc20mock.New(
@@ -507,6 +515,7 @@ func (b *DevBee) Shutdown() error {
 	tryClose(b.pssCloser, "pss")
 	tryClose(b.tracerCloser, "tracer")
 	tryClose(b.tagsCloser, "tag persistence")
+	tryClose(b.tagStoreCloser, "tags store")
 	tryClose(b.stateStoreCloser, "statestore")
 	tryClose(b.localstoreCloser, "localstore")
 
@@ -10,6 +10,7 @@ package node
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -17,8 +18,10 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -32,13 +35,23 @@ import (
 	"github.com/ethersphere/bee/pkg/accounting"
 	"github.com/ethersphere/bee/pkg/addressbook"
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/audit"
 	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/availability"
+	"github.com/ethersphere/bee/pkg/batchgossip"
+	"github.com/ethersphere/bee/pkg/billing"
 	"github.com/ethersphere/bee/pkg/chainsync"
 	"github.com/ethersphere/bee/pkg/chainsyncer"
+	"github.com/ethersphere/bee/pkg/clockskew"
 	"github.com/ethersphere/bee/pkg/config"
+	"github.com/ethersphere/bee/pkg/contentstats"
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/denylist"
 	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/feeds/validation"
+	"github.com/ethersphere/bee/pkg/handoff"
 	"github.com/ethersphere/bee/pkg/hive"
+	"github.com/ethersphere/bee/pkg/ipfs"
 	"github.com/ethersphere/bee/pkg/localstore"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/metrics"
@@ -50,41 +63,55 @@ import (
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/postage/batchservice"
 	"github.com/ethersphere/bee/pkg/postage/batchstore"
+	"github.com/ethersphere/bee/pkg/postage/batchtable"
 	"github.com/ethersphere/bee/pkg/postage/listener"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
+	"github.com/ethersphere/bee/pkg/prewarm"
 	"github.com/ethersphere/bee/pkg/pricer"
 	"github.com/ethersphere/bee/pkg/pricing"
+	"github.com/ethersphere/bee/pkg/profiling"
+	"github.com/ethersphere/bee/pkg/profitability"
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/puller"
 	"github.com/ethersphere/bee/pkg/pullsync"
 	"github.com/ethersphere/bee/pkg/pullsync/pullstorage"
 	"github.com/ethersphere/bee/pkg/pusher"
 	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/receipts"
 	"github.com/ethersphere/bee/pkg/resolver/multiresolver"
 	"github.com/ethersphere/bee/pkg/retrieval"
+	"github.com/ethersphere/bee/pkg/salud"
+	"github.com/ethersphere/bee/pkg/selftest"
+	"github.com/ethersphere/bee/pkg/settlement"
 	"github.com/ethersphere/bee/pkg/settlement/pseudosettle"
 	"github.com/ethersphere/bee/pkg/settlement/swap"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
 	"github.com/ethersphere/bee/pkg/settlement/swap/erc20"
 	"github.com/ethersphere/bee/pkg/settlement/swap/priceoracle"
 	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/status"
 	"github.com/ethersphere/bee/pkg/steward"
 	"github.com/ethersphere/bee/pkg/storageincentives"
 	"github.com/ethersphere/bee/pkg/storageincentives/staking"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
 	"github.com/ethersphere/bee/pkg/topology"
-	"github.com/ethersphere/bee/pkg/topology/kademlia"
+	"github.com/ethersphere/bee/pkg/topology/driver"
+	// the kademlia registers the default topology driver
+	_ "github.com/ethersphere/bee/pkg/topology/kademlia"
 	"github.com/ethersphere/bee/pkg/topology/lightnode"
 	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/ethersphere/bee/pkg/transaction"
 	"github.com/ethersphere/bee/pkg/traversal"
 	"github.com/ethersphere/bee/pkg/util"
 	"github.com/ethersphere/bee/pkg/util/ioutil"
+	"github.com/ethersphere/bee/pkg/webhook"
+	"github.com/ethersphere/bee/pkg/workingset"
 	"github.com/hashicorp/go-multierror"
 	ma "github.com/multiformats/go-multiaddr"
 	"golang.org/x/crypto/sha3"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 )
 
 // LoggerName is the tree path name of the logger for this package.
@@ -96,11 +123,14 @@ type Bee struct {
 	ctxCancel                context.CancelFunc
 	apiCloser                io.Closer
 	apiServer                *http.Server
+	s3Server                 *http.Server
+	pssGRPCServer            *grpc.Server
 	debugAPIServer           *http.Server
 	resolverCloser           io.Closer
 	errorLogWriter           io.Writer
 	tracerCloser             io.Closer
 	tagsCloser               io.Closer
+	tagStoreCloser           io.Closer
 	stateStoreCloser         io.Closer
 	localstoreCloser         io.Closer
 	nsCloser                 io.Closer
@@ -108,6 +138,7 @@ type Bee struct {
 	topologyHalter           topology.Halter
 	pusherCloser             io.Closer
 	pullerCloser             io.Closer
+	saludCloser              io.Closer
 	accountingCloser         io.Closer
 	pullSyncCloser           io.Closer
 	pssCloser                io.Closer
@@ -117,10 +148,22 @@ type Bee struct {
 	listenerCloser           io.Closer
 	postageServiceCloser     io.Closer
 	priceOracleCloser        io.Closer
+	withdrawalCloser         io.Closer
 	hiveCloser               io.Closer
 	chainSyncerCloser        io.Closer
 	depthMonitorCloser       io.Closer
 	storageIncetivesCloser   io.Closer
+	auditLogCloser           io.Closer
+	pricerCloser             io.Closer
+	swapCloser               io.Closer
+	prewarmCloser            io.Closer
+	workingSetCloser         io.Closer
+	contentStatsCloser       io.Closer
+	billingCloser            io.Closer
+	availabilityCloser       io.Closer
+	batchGossipCloser        io.Closer
+	webhooksCloser           io.Closer
+	profilerCloser           io.Closer
 	shutdownInProgress       bool
 	shutdownMutex            sync.Mutex
 	syncingStopped           *util.Signaler
@@ -129,17 +172,28 @@ type Bee struct {
 type Options struct {
 	DataDir                       string
 	CacheCapacity                 uint64
+	CacheMinFreeDisk              float64
+	UploadCapacity                uint64
+	StateStoreEncryptionKey       []byte
+	ColdDataDir                   string
+	ColdAge                       time.Duration
+	DBBackend                     string
 	DBOpenFilesLimit              uint64
 	DBWriteBufferSize             uint64
 	DBBlockCacheCapacity          uint64
 	DBDisableSeeksCompaction      bool
+	DBGroupCommitLatency          time.Duration
 	APIAddr                       string
 	DebugAPIAddr                  string
 	Addr                          string
 	NATAddr                       string
 	EnableWS                      bool
+	EnableRelay                   bool
+	EnableRelayService            bool
+	StaticRelays                  []string
 	WelcomeMessage                string
 	Bootnodes                     []string
+	BootnodeDomains               []string
 	CORSAllowedOrigins            []string
 	Logger                        log.Logger
 	TracingEnabled                bool
@@ -165,6 +219,11 @@ type Options struct {
 	RedistributionContractAddress string
 	BlockTime                     time.Duration
 	DeployGasPrice                string
+	SwapBounceThreshold           int
+	SwapBounceBlocklistDuration   time.Duration
+	WithdrawalAddress             string
+	WithdrawalThreshold           string
+	WithdrawalInterval            time.Duration
 	WarmupTime                    time.Duration
 	ChainID                       int64
 	Resync                        bool
@@ -177,6 +236,42 @@ type Options struct {
 	AdminPasswordHash             string
 	UsePostageSnapshot            bool
 	EnableStorageIncentives       bool
+	MaxDirUploadFileSize          int64
+	CompressibleContentTypes      []string
+	TenantsPath                   string
+	ChainDisabled                 bool
+	StaticBatchesPath             string
+	StaticBatchesSigner           string
+	PostageExpiryGracePeriod      time.Duration
+	PostageExpiryWarning          time.Duration
+	AuditLogPath                  string
+	AuditLogMaxSize               int64
+	AuditLogMaxBackups            int
+	PushSyncTrace                 bool
+	PushSyncBatchWindow           time.Duration
+	DynamicPricing                bool
+	WebDAV                        bool
+	WebDAVPostageBatch            string
+	S3Addr                        string
+	S3PostageBatch                string
+	PssGRPCAddr                   string
+	AccountingSnapshotInterval    time.Duration
+	IPFSGateway                   string
+	ContentStats                  bool
+	TopologyDriver                string
+	Gateway                       bool
+	SourceURLMaxSize              int64
+	SourceURLSchemes              []string
+	SourceURLAllowPrivate         bool
+	WebhookURLs                   []string
+	WebhookSecret                 string
+	WebhookEvents                 []string
+	WebhookChequebookMinBalance   string
+	PyroscopeAddr                 string
+	PyroscopeAppName              string
+	HandoffAllowedPeers           []string
+	FeedValidationHooks           []string
+	FeedValidationTimeout         time.Duration
 }
 
 const (
@@ -238,7 +333,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		}
 	}(b)
 
-	stateStore, err := InitStateStore(logger, o.DataDir)
+	stateStore, err := InitStateStore(logger, o.DataDir, o.StateStoreEncryptionKey)
 	if err != nil {
 		return nil, err
 	}
@@ -299,14 +394,16 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		chequeStore        chequebook.ChequeStore
 		cashoutService     chequebook.CashoutService
 		erc20Service       erc20.Service
+		withdrawalPlanner  api.WithdrawalPlanner
 	)
 
 	chainEnabled := isChainEnabled(o, o.BlockchainRpcEndpoint, logger)
 
 	var batchStore postage.Storer = new(postage.NoOpBatchStore)
 	var evictFn func([]byte) error
+	batchEvents := postage.NewBatchEventFeed()
 
-	if chainEnabled {
+	if chainEnabled || o.ChainDisabled {
 		batchStore, err = batchstore.New(
 			stateStore,
 			func(id []byte) error {
@@ -314,6 +411,9 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			},
 			swarmAddress,
 			logger,
+			batchstore.WithExpiryGracePeriod(durationToBlocks(o.PostageExpiryGracePeriod, o.BlockTime)),
+			batchstore.WithExpiryWarning(durationToBlocks(o.PostageExpiryWarning, o.BlockTime)),
+			batchstore.WithEventPublisher(batchEvents),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("batchstore: %w", err)
@@ -334,13 +434,16 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	}
 	b.ethClientCloser = chainBackend.Close
 
+	profitabilityLedger := profitability.New(stateStore)
+	transactionService = profitability.NewTransactionService(logger, transactionService, profitabilityLedger)
+
 	logger.Info("using chain with network network", "chain_id", chainID, "network_id", networkID)
 
 	if o.ChainID != -1 && o.ChainID != chainID {
 		return nil, fmt.Errorf("connected to wrong ethereum network; network chainID %d; configured chainID %d", chainID, o.ChainID)
 	}
 
-	b.transactionCloser = tracerCloser
+	b.transactionCloser = transactionService
 	b.transactionMonitorCloser = transactionMonitor
 
 	var authenticator auth.Authenticator
@@ -356,7 +459,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	beeNodeMode := api.LightMode
 	if o.FullNodeMode {
 		beeNodeMode = api.FullMode
-	} else if !chainEnabled {
+	} else if !chainEnabled && !o.ChainDisabled {
 		beeNodeMode = api.UltraLightMode
 	}
 
@@ -388,7 +491,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		}
 
 		debugService = api.New(*publicKey, pssPrivateKey.PublicKey, overlayEthAddress, logger, transactionService, batchStore, beeNodeMode, o.ChequebookEnable, o.SwapEnable, chainBackend, o.CORSAllowedOrigins)
-		debugService.MountTechnicalDebug()
+		debugService.MountTechnicalDebug(false)
 		debugService.SetProbe(probe)
 
 		debugAPIServer := &http.Server{
@@ -414,7 +517,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 
 	if o.Restricted {
 		apiService = api.New(*publicKey, pssPrivateKey.PublicKey, overlayEthAddress, logger, transactionService, batchStore, beeNodeMode, o.ChequebookEnable, o.SwapEnable, chainBackend, o.CORSAllowedOrigins)
-		apiService.MountTechnicalDebug()
+		apiService.MountTechnicalDebug(o.Restricted)
 		apiService.SetProbe(probe)
 
 		apiServer := &http.Server{
@@ -498,6 +601,24 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			if err != nil {
 				return nil, err
 			}
+
+			if o.WithdrawalAddress != "" {
+				withdrawalScheduler, err := InitChequebookWithdrawal(
+					logger,
+					chequebookService,
+					erc20Service,
+					transactionService,
+					overlayEthAddress,
+					o.WithdrawalAddress,
+					o.WithdrawalThreshold,
+					o.WithdrawalInterval,
+				)
+				if err != nil {
+					return nil, err
+				}
+				b.withdrawalCloser = withdrawalScheduler
+				withdrawalPlanner = withdrawalScheduler
+			}
 		}
 
 		chequeStore, cashoutService = initChequeStoreCashout(
@@ -584,14 +705,18 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	}
 
 	p2ps, err := libp2p.New(ctx, signer, networkID, swarmAddress, addr, addressbook, stateStore, lightNodes, logger, tracer, libp2p.Options{
-		PrivateKey:      libp2pPrivateKey,
-		NATAddr:         o.NATAddr,
-		EnableWS:        o.EnableWS,
-		WelcomeMessage:  o.WelcomeMessage,
-		FullNode:        o.FullNodeMode,
-		Nonce:           nonce,
-		ValidateOverlay: chainEnabled,
-		Registry:        debugService.MetricsRegistry(),
+		PrivateKey:         libp2pPrivateKey,
+		NATAddr:            o.NATAddr,
+		EnableWS:           o.EnableWS,
+		EnableRelay:        o.EnableRelay,
+		EnableRelayService: o.EnableRelayService,
+		StaticRelays:       o.StaticRelays,
+		WelcomeMessage:     o.WelcomeMessage,
+		FullNode:           o.FullNodeMode,
+		Nonce:              nonce,
+		ValidateOverlay:    chainEnabled,
+		Registry:           debugService.MetricsRegistry(),
+		Features:           p2p.FeatureBatchedPushsync,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("p2p service: %w", err)
@@ -616,11 +741,20 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		Capacity:               o.CacheCapacity,
 		ReserveCapacity:        uint64(batchstore.Capacity),
 		UnreserveFunc:          batchStore.Unreserve,
+		Backend:                o.DBBackend,
 		OpenFilesLimit:         o.DBOpenFilesLimit,
 		BlockCacheCapacity:     o.DBBlockCacheCapacity,
 		WriteBufferSize:        o.DBWriteBufferSize,
 		DisableSeeksCompaction: o.DBDisableSeeksCompaction,
+		GroupCommitLatency:     o.DBGroupCommitLatency,
 		ValidStamp:             validStamp,
+		ColdAge:                o.ColdAge,
+		MinFreeDisk:            o.CacheMinFreeDisk,
+		UploadCapacity:         o.UploadCapacity,
+	}
+	if o.ColdDataDir != "" {
+		logger.Info("using cold datadir", "path", o.ColdDataDir)
+		lo.ColdPath = filepath.Join(o.ColdDataDir, "localstore")
 	}
 
 	storer, err := localstore.New(path, swarmAddress.Bytes(), stateStore, lo, logger)
@@ -635,7 +769,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		return nil, fmt.Errorf("postage service load: %w", err)
 	}
 	b.postageServiceCloser = post
-	batchStore.SetBatchExpiryHandler(post)
+	batchStore.SetBatchExpiryHandler(batchEvents.ExpiryHandler(post))
 
 	var (
 		postageStampContractService postagecontract.Interface
@@ -654,7 +788,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			return nil, errors.New("postage contract start block option not provided")
 		}
 		postageSyncStart = o.PostageContractStartBlock
-	} else if !found {
+	} else if !found && !o.ChainDisabled {
 		return nil, errors.New("no known postage stamp addresses for this network")
 	}
 
@@ -682,7 +816,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	eventListener = listener.New(b.syncingStopped, logger, chainBackend, postageStampContractAddress, postageStampContractABI, o.BlockTime, postageSyncingStallingTimeout, postageSyncingBackoffTimeout)
 	b.listenerCloser = eventListener
 
-	batchSvc, err = batchservice.New(stateStore, batchStore, logger, eventListener, overlayEthAddress.Bytes(), post, sha3.New256, o.Resync)
+	batchSvc, err = batchservice.New(stateStore, batchStore, logger, eventListener, overlayEthAddress.Bytes(), post, batchEvents, sha3.New256, o.Resync)
 	if err != nil {
 		return nil, err
 	}
@@ -728,13 +862,25 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 
 	metricsDB, err := shed.NewDBWrap(stateStore.DB())
 	if err != nil {
-		return nil, fmt.Errorf("unable to create metrics storage for kademlia: %w", err)
-	}
-
-	kad, err := kademlia.New(swarmAddress, addressbook, hive, p2ps, pingPong, metricsDB, logger,
-		kademlia.Options{Bootnodes: bootnodes, BootnodeMode: o.BootnodeMode, StaticNodes: o.StaticNodes, IgnoreRadius: !chainEnabled})
+		return nil, fmt.Errorf("unable to create metrics storage for topology driver: %w", err)
+	}
+
+	kad, err := driver.New(o.TopologyDriver, driver.Options{
+		Base:            swarmAddress,
+		AddressBook:     addressbook,
+		Discovery:       hive,
+		P2P:             p2ps,
+		Pinger:          pingPong,
+		MetricsDB:       metricsDB,
+		Logger:          logger,
+		Bootnodes:       bootnodes,
+		BootnodeDomains: o.BootnodeDomains,
+		BootnodeMode:    o.BootnodeMode,
+		StaticNodes:     o.StaticNodes,
+		IgnoreRadius:    !chainEnabled && !o.ChainDisabled,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("unable to create kademlia: %w", err)
+		return nil, fmt.Errorf("unable to create topology driver: %w", err)
 	}
 	b.topologyCloser = kad
 	b.topologyHalter = kad
@@ -788,6 +934,33 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		}
 	}
 
+	if o.ChainDisabled {
+		var signer *common.Address
+		if o.StaticBatchesSigner != "" {
+			if !common.IsHexAddress(o.StaticBatchesSigner) {
+				return nil, errors.New("malformed static batches signer address")
+			}
+			address := common.HexToAddress(o.StaticBatchesSigner)
+			signer = &address
+		}
+		data, err := os.ReadFile(o.StaticBatchesPath)
+		if err != nil {
+			return nil, fmt.Errorf("static batches: %w", err)
+		}
+		batches, err := batchtable.Parse(data, signer)
+		if err != nil {
+			return nil, fmt.Errorf("static batches: %w", err)
+		}
+		if err := batchtable.Load(batchStore, post, overlayEthAddress.Bytes(), batches); err != nil {
+			return nil, fmt.Errorf("static batches: %w", err)
+		}
+		if err := post.SetExpired(); err != nil {
+			return nil, fmt.Errorf("unable to set expirations: %w", err)
+		}
+		syncStatus.Store(true)
+		logger.Info("loaded static batch table", "batches", len(batches))
+	}
+
 	minThreshold := big.NewInt(2 * refreshRate)
 	maxThreshold := big.NewInt(24 * refreshRate)
 
@@ -797,8 +970,6 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 
 	lightPaymentThreshold := new(big.Int).Div(paymentThreshold, big.NewInt(lightFactor))
 
-	pricer := pricer.NewFixedPricer(swarmAddress, basePrice)
-
 	if paymentThreshold.Cmp(minThreshold) < 0 {
 		return nil, fmt.Errorf("payment threshold below minimum generally accepted value, need at least %s", minThreshold)
 	}
@@ -809,6 +980,20 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 
 	pricing := pricing.New(p2ps, logger, paymentThreshold, lightPaymentThreshold, minThreshold)
 
+	// the prices announced by the peers are always honoured, while our own
+	// price follows the pressure on the local resources only if enabled
+	pricerOptions := pricer.DynamicOptions{Announce: pricing.AnnouncePrice}
+	if o.DynamicPricing && o.FullNodeMode {
+		pressure := []pricer.PressureFunc{pricer.ReservePressure(storer.ReserveSize, storer.ReserveCapacity())}
+		if o.DataDir != "" {
+			pressure = append(pressure, pricer.DiskPressure(o.DataDir))
+		}
+		pricerOptions.Pressure = pricer.MaxPressure(pressure...)
+	}
+	pricer := pricer.NewDynamicPricer(swarmAddress, basePrice, logger, pricerOptions)
+	b.pricerCloser = pricer
+	pricing.SetPriceObserver(pricer)
+
 	if err = p2ps.AddProtocol(pricing.Protocol()); err != nil {
 		return nil, fmt.Errorf("pricing service: %w", err)
 	}
@@ -853,6 +1038,9 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 
 	acc.SetRefreshFunc(pseudosettleService.Pay)
 
+	clockSkew := clockskew.New(logger)
+	pseudosettleService.SetTimeRecorder(clockSkew)
+
 	if o.SwapEnable && chainEnabled {
 		var priceOracle priceoracle.Service
 		swapService, priceOracle, err = InitSwap(
@@ -868,11 +1056,16 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			o.PriceOracleAddress,
 			chainID,
 			transactionService,
+			o.SwapBounceThreshold,
+			o.SwapBounceBlocklistDuration,
 		)
 		if err != nil {
 			return nil, err
 		}
 		b.priceOracleCloser = priceOracle
+		b.swapCloser = swapService
+
+		swapService.SetProfitability(profitabilityLedger)
 
 		if o.ChequebookEnable {
 			acc.SetPayFunc(swapService.Pay)
@@ -881,26 +1074,66 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 
 	pricing.SetPaymentThresholdObserver(acc)
 
+	var billingSnapshotter api.BillingSnapshotter
+	if o.AccountingSnapshotInterval > 0 {
+		var settlements settlement.Interface
+		if swapService != nil {
+			settlements = swapService
+		}
+		billingService := billing.New(logger, stateStore, acc, settlements, pseudosettleService, o.AccountingSnapshotInterval)
+		b.billingCloser = billingService
+		billingSnapshotter = billingService
+	}
+
 	retrieve := retrieval.New(swarmAddress, storer, p2ps, kad, logger, acc, pricer, tracer, o.RetrievalCaching, validStamp)
-	tagService := tags.NewTags(stateStore, logger)
+	var tagStorePath string
+	if o.DataDir != "" {
+		tagStorePath = filepath.Join(o.DataDir, "tags")
+	}
+	tagStore, err := tags.NewStore(tagStorePath)
+	if err != nil {
+		return nil, fmt.Errorf("tags store: %w", err)
+	}
+	b.tagStoreCloser = tagStore
+	if n, err := tagStore.Migrate(stateStore); err != nil {
+		return nil, fmt.Errorf("tags migration: %w", err)
+	} else if n > 0 {
+		logger.Info("tags moved out of the statestore", "count", n)
+	}
+
+	tagService := tags.NewTags(tagStore, logger)
 	b.tagsCloser = tagService
 
 	pssService := pss.New(pssPrivateKey, logger)
 	b.pssCloser = pssService
 
-	ns := netstore.New(storer, validStamp, retrieve, logger)
+	availabilityService := availability.New(stateStore, logger)
+	b.availabilityCloser = availabilityService
+
+	ns := netstore.New(storer, validStamp, availabilityService.Retrieval(retrieve), logger)
 	b.nsCloser = ns
 
 	traversalService := traversal.New(ns)
 
 	pinningService := pinning.NewService(storer, stateStore, traversalService)
 
-	pushSyncProtocol := pushsync.New(swarmAddress, nonce, p2ps, storer, kad, batchStore, tagService, o.FullNodeMode, pssService.TryUnwrap, validStamp, logger, acc, pricer, signer, tracer, warmupTime)
+	pushSyncProtocol := pushsync.New(swarmAddress, nonce, p2ps, storer, kad, batchStore, tagService, o.FullNodeMode, pssService.TryUnwrap, validStamp, logger, acc, pricer, signer, tracer, warmupTime, o.PushSyncTrace, o.PushSyncBatchWindow)
 
 	// set the pushSyncer in the PSS
 	pssService.SetPushSyncer(pushSyncProtocol)
+	pssService.SetOutbox(stateStore, kad)
 
-	pusherService := pusher.New(networkID, storer, kad, pushSyncProtocol, validStamp, tagService, logger, tracer, warmupTime, pusher.DefaultRetryCount)
+	receiptStore, err := receipts.New(stateStore, receipts.DefaultRecentCapacity)
+	if err != nil {
+		return nil, fmt.Errorf("receipts: %w", err)
+	}
+
+	denyList, err := denylist.New(stateStore)
+	if err != nil {
+		return nil, fmt.Errorf("denylist: %w", err)
+	}
+
+	pusherService := pusher.New(networkID, storer, kad, pushSyncProtocol, validStamp, tagService, receiptStore, logger, tracer, warmupTime, pusher.DefaultRetryCount)
 	b.pusherCloser = pusherService
 
 	pullStorage := pullstorage.New(storer, logger)
@@ -931,6 +1164,11 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		return nil, fmt.Errorf("pullsync protocol: %w", err)
 	}
 
+	statusService := status.New(p2ps, batchStore, storer, pullSyncProtocol, kad, logger)
+	if err = p2ps.AddProtocol(statusService.Protocol()); err != nil {
+		return nil, fmt.Errorf("status service: %w", err)
+	}
+
 	stakingContractAddress := chainCfg.StakingAddress
 	if o.StakingContractAddress != "" {
 		if !common.IsHexAddress(o.StakingContractAddress) {
@@ -947,6 +1185,8 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 
 	var (
 		pullerService *puller.Puller
+		saludService  *salud.Service
+		batchGossip   *batchgossip.Service
 		agent         *storageincentives.Agent
 	)
 
@@ -954,10 +1194,20 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		pullerService = puller.New(stateStore, kad, batchStore, pullSyncProtocol, p2ps, logger, puller.Options{SyncSleepDur: puller.DefaultSyncErrorSleepDur}, warmupTime)
 		b.pullerCloser = pullerService
 
+		saludService = salud.New(statusService, kad, batchStore, logger, salud.DefaultInterval)
+		statusService.SetHealther(saludService)
+		b.saludCloser = saludService
+
+		batchGossip = batchgossip.New(p2ps, batchStore, kad, swarmAddress, batchEvents, logger, batchgossip.Options{})
+		if err = p2ps.AddProtocol(batchGossip.Protocol()); err != nil {
+			return nil, fmt.Errorf("batch gossip service: %w", err)
+		}
+		b.batchGossipCloser = batchGossip
+
 		depthMonitor := depthmonitor.New(kad, pullSyncProtocol, storer, batchStore, logger, warmupTime, depthmonitor.DefaultWakeupInterval, !batchStoreExists)
 		b.depthMonitorCloser = depthMonitor
 
-		if o.EnableStorageIncentives {
+		if o.EnableStorageIncentives && !o.ChainDisabled {
 
 			redistributionContractAddress := chainCfg.RedistributionAddress
 			if o.RedistributionContractAddress != "" {
@@ -972,7 +1222,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			}
 
 			redistributionContract := redistribution.New(swarmAddress, logger, transactionService, redistributionContractAddress, redistributionContractABI)
-			agent, err = storageincentives.New(swarmAddress, overlayEthAddress, chainBackend, logger, depthMonitor, redistributionContract, postageStampContractService, stakingContract, batchStore, storer, o.BlockTime, storageincentives.DefaultBlocksPerRound, storageincentives.DefaultBlocksPerPhase, stateStore, erc20Service, transactionService)
+			agent, err = storageincentives.New(swarmAddress, overlayEthAddress, chainBackend, logger, depthMonitor, redistributionContract, postageStampContractService, stakingContract, batchStore, storer, o.BlockTime, storageincentives.DefaultBlocksPerRound, storageincentives.DefaultBlocksPerPhase, stateStore, erc20Service, transactionService, profitabilityLedger)
 			if err != nil {
 				return nil, fmt.Errorf("storage incentives agent: %w", err)
 			}
@@ -988,7 +1238,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	b.resolverCloser = multiResolver
 	var chainSyncer *chainsyncer.ChainSyncer
 
-	if o.FullNodeMode {
+	if o.FullNodeMode && chainEnabled {
 		cs, err := chainsync.New(p2ps, chainBackend)
 		if err != nil {
 			return nil, fmt.Errorf("new chainsync: %w", err)
@@ -1007,6 +1257,142 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 	feedFactory := factory.New(ns)
 	steward := steward.New(storer, traversalService, retrieve, pushSyncProtocol)
 
+	handoffAllowedPeers := make([]swarm.Address, 0, len(o.HandoffAllowedPeers))
+	for _, p := range o.HandoffAllowedPeers {
+		addr, err := swarm.ParseHexAddress(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid handoff allowed peer %q: %w", p, err)
+		}
+		handoffAllowedPeers = append(handoffAllowedPeers, addr)
+	}
+	handoffService := handoff.New(p2ps, storer, traversal.New(storer), pinningService, validStamp, logger, handoff.Options{
+		AllowedPeers: handoffAllowedPeers,
+	})
+	if err = p2ps.AddProtocol(handoffService.Protocol()); err != nil {
+		return nil, fmt.Errorf("handoff service: %w", err)
+	}
+
+	selfTestService := selftest.New(pushSyncProtocol, retrieve, logger)
+
+	prewarmService := prewarm.New(ns, traversalService)
+	b.prewarmCloser = prewarmService
+
+	workingSetService := workingset.New(ns, stateStore, traversalService, logger)
+	b.workingSetCloser = workingSetService
+
+	var contentStatsService *contentstats.Service
+	if o.ContentStats {
+		if contentStatsService, err = contentstats.New(stateStore, logger); err != nil {
+			return nil, fmt.Errorf("content stats: %w", err)
+		}
+		b.contentStatsCloser = contentStatsService
+	}
+
+	var auditLog *audit.Log
+	if o.AuditLogPath != "" {
+		auditLog, err = audit.New(o.AuditLogPath, audit.Options{
+			MaxSize:    o.AuditLogMaxSize,
+			MaxBackups: o.AuditLogMaxBackups,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("audit log: %w", err)
+		}
+		b.auditLogCloser = auditLog
+	}
+
+	var tenants []api.Tenant
+	if o.TenantsPath != "" {
+		if !o.Restricted {
+			return nil, errors.New("tenants require the restricted mode")
+		}
+		data, err := os.ReadFile(o.TenantsPath)
+		if err != nil {
+			return nil, fmt.Errorf("tenants: %w", err)
+		}
+		if tenants, err = api.ParseTenants(data); err != nil {
+			return nil, fmt.Errorf("tenants: %w", err)
+		}
+	}
+
+	var webdavPostageBatch []byte
+	if o.WebDAVPostageBatch != "" {
+		if !o.WebDAV {
+			return nil, errors.New("webdav postage batch requires webdav")
+		}
+		if webdavPostageBatch, err = hex.DecodeString(o.WebDAVPostageBatch); err != nil || len(webdavPostageBatch) != 32 {
+			return nil, fmt.Errorf("invalid webdav postage batch %q", o.WebDAVPostageBatch)
+		}
+	}
+
+	var s3PostageBatch []byte
+	if o.S3PostageBatch != "" {
+		if o.S3Addr == "" {
+			return nil, errors.New("s3 postage batch requires s3 address")
+		}
+		if s3PostageBatch, err = hex.DecodeString(o.S3PostageBatch); err != nil || len(s3PostageBatch) != 32 {
+			return nil, fmt.Errorf("invalid s3 postage batch %q", o.S3PostageBatch)
+		}
+	}
+
+	var ipfsGateway ipfs.Fetcher
+	if o.IPFSGateway != "" {
+		if ipfsGateway, err = ipfs.NewGateway(o.IPFSGateway); err != nil {
+			return nil, fmt.Errorf("ipfs gateway: %w", err)
+		}
+	}
+
+	if o.PyroscopeAddr != "" {
+		profiler, err := profiling.NewProfiler(profiling.Options{
+			ServerAddress: o.PyroscopeAddr,
+			AppName:       o.PyroscopeAppName,
+			Tags: map[string]string{
+				"network": strconv.FormatUint(networkID, 10),
+				"overlay": swarmAddress.String(),
+			},
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("profiler: %w", err)
+		}
+		b.profilerCloser = profiler
+	}
+
+	var webhooks *webhook.Service
+	if len(o.WebhookURLs) > 0 {
+		chequebookMinimum, err := parseWebhookChequebookMinimum(o.WebhookChequebookMinBalance)
+		if err != nil {
+			return nil, err
+		}
+		sources := webhookSources{
+			batchEvents: batchEvents,
+			hooker:      p2ps,
+		}
+		if chainEnabled {
+			sources.chainBackend = chainBackend
+			if o.ChequebookEnable {
+				sources.chequebook = chequebookService
+				sources.chequebookMinimum = chequebookMinimum
+			}
+		}
+		if o.FullNodeMode {
+			sources.reserve = storer
+		}
+		if webhooks, err = initWebhooks(o, sources, logger); err != nil {
+			return nil, fmt.Errorf("webhooks: %w", err)
+		}
+		b.webhooksCloser = webhooks
+	}
+
+	var feedValidator validation.Validator
+	if len(o.FeedValidationHooks) > 0 {
+		feedValidator, err = validation.New(validation.Options{
+			URLs:    o.FeedValidationHooks,
+			Timeout: o.FeedValidationTimeout,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("feed validation: %w", err)
+		}
+	}
+
 	extraOpts := api.ExtraOptions{
 		Pingpong:         pingPong,
 		TopologyDriver:   kad,
@@ -1015,6 +1401,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		Pseudosettle:     pseudosettleService,
 		Swap:             swapService,
 		Chequebook:       chequebookService,
+		Withdrawal:       withdrawalPlanner,
 		BlockTime:        o.BlockTime,
 		Tags:             tagService,
 		Storer:           ns,
@@ -1027,8 +1414,34 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		PostageContract:  postageStampContractService,
 		Staking:          stakingContract,
 		Steward:          steward,
+		Handoff:          handoffService,
+		SelfTest:         selfTestService,
+		FeedValidator:    feedValidator,
 		SyncStatus:       syncStatusFn,
 		IndexDebugger:    storer,
+		Reserve:          storer,
+		ReserveChecker:   storer,
+		Syncer:           pullSyncProtocol,
+		Status:           statusService,
+		AuditLog:         auditLog,
+		BatchEvents:      batchEvents,
+		StateStorer:      stateStore,
+		Receipts:         receiptStore,
+		Denylist:         denyList,
+		Profitability:    profitabilityLedger,
+		Earnings:         retrieve,
+		Billing:          billingSnapshotter,
+		Prewarm:          prewarmService,
+		WorkingSet:       workingSetService,
+		ContentStats:     contentStatsService,
+		Availability:     availabilityService,
+		ClockSkew:        clockSkew,
+		IPFS:             ipfsGateway,
+		Webhooks:         webhooks,
+	}
+
+	if pullerService != nil {
+		extraOpts.ReserveRepairer = pullerService
 	}
 
 	if o.APIAddr != "" {
@@ -1039,9 +1452,19 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 		}
 
 		chunkC := apiService.Configure(signer, authenticator, tracer, api.Options{
-			CORSAllowedOrigins: o.CORSAllowedOrigins,
-			WsPingPeriod:       60 * time.Second,
-			Restricted:         o.Restricted,
+			CORSAllowedOrigins:       o.CORSAllowedOrigins,
+			WsPingPeriod:             60 * time.Second,
+			Restricted:               o.Restricted,
+			MaxDirUploadFileSize:     o.MaxDirUploadFileSize,
+			CompressibleContentTypes: o.CompressibleContentTypes,
+			Tenants:                  tenants,
+			WebDAV:                   o.WebDAV,
+			WebDAVPostageBatch:       webdavPostageBatch,
+			S3PostageBatch:           s3PostageBatch,
+			Gateway:                  o.Gateway,
+			SourceURLMaxSize:         o.SourceURLMaxSize,
+			SourceURLSchemes:         o.SourceURLSchemes,
+			SourceURLAllowPrivate:    o.SourceURLAllowPrivate,
 		}, extraOpts, chainID, erc20Service)
 
 		pusherService.AddFeed(chunkC)
@@ -1075,6 +1498,49 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			// in Restricted mode we mount debug endpoints
 			apiService.MountDebug(o.Restricted)
 		}
+
+		if o.S3Addr != "" {
+			s3Server := &http.Server{
+				IdleTimeout:       30 * time.Second,
+				ReadHeaderTimeout: 3 * time.Second,
+				Handler:           apiService.S3Handler(),
+				ErrorLog:          stdlog.New(b.errorLogWriter, "", 0),
+			}
+
+			s3Listener, err := net.Listen("tcp", o.S3Addr)
+			if err != nil {
+				return nil, fmt.Errorf("s3 listener: %w", err)
+			}
+
+			go func() {
+				logger.Info("starting s3 server", "address", s3Listener.Addr())
+				if err := s3Server.Serve(s3Listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Debug("s3 server failed to start", "error", err)
+					logger.Error(nil, "s3 server failed to start")
+				}
+			}()
+
+			b.s3Server = s3Server
+		}
+
+		if o.PssGRPCAddr != "" {
+			pssGRPCServer := apiService.PssGRPCServer()
+
+			pssGRPCListener, err := net.Listen("tcp", o.PssGRPCAddr)
+			if err != nil {
+				return nil, fmt.Errorf("pss grpc listener: %w", err)
+			}
+
+			go func() {
+				logger.Info("starting pss grpc server", "address", pssGRPCListener.Addr())
+				if err := pssGRPCServer.Serve(pssGRPCListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+					logger.Debug("pss grpc server failed to start", "error", err)
+					logger.Error(nil, "pss grpc server failed to start")
+				}
+			}()
+
+			b.pssGRPCServer = pssGRPCServer
+		}
 	}
 
 	if o.DebugAPIAddr != "" {
@@ -1089,11 +1555,20 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			debugService.MustRegisterMetrics(pullerService.Metrics()...)
 		}
 
+		if saludService != nil {
+			debugService.MustRegisterMetrics(saludService.Metrics()...)
+		}
+
+		if batchGossip != nil {
+			debugService.MustRegisterMetrics(batchGossip.Metrics()...)
+		}
+
 		if agent != nil {
 			debugService.MustRegisterMetrics(agent.Metrics()...)
 		}
 
 		debugService.MustRegisterMetrics(pushSyncProtocol.Metrics()...)
+		debugService.MustRegisterMetrics(handoffService.Metrics()...)
 		debugService.MustRegisterMetrics(pusherService.Metrics()...)
 		debugService.MustRegisterMetrics(pullSyncProtocol.Metrics()...)
 		debugService.MustRegisterMetrics(pullStorage.Metrics()...)
@@ -1123,6 +1598,7 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			debugService.MustRegisterMetrics(nsMetrics.Metrics()...)
 		}
 		debugService.MustRegisterMetrics(pseudosettleService.Metrics()...)
+		debugService.MustRegisterMetrics(clockSkew.Metrics()...)
 		if swapService != nil {
 			debugService.MustRegisterMetrics(swapService.Metrics()...)
 		}
@@ -1130,12 +1606,15 @@ func NewBee(ctx context.Context, addr string, publicKey *ecdsa.PublicKey, signer
 			debugService.MustRegisterMetrics(chainSyncer.Metrics()...)
 		}
 
-		debugService.Configure(signer, authenticator, tracer, api.Options{
+		debugChunkC := debugService.Configure(signer, authenticator, tracer, api.Options{
 			CORSAllowedOrigins: o.CORSAllowedOrigins,
 			WsPingPeriod:       60 * time.Second,
 			Restricted:         o.Restricted,
 		}, extraOpts, chainID, erc20Service)
 
+		// restamped chunks are pushed from the debug api
+		pusherService.AddFeed(debugChunkC)
+
 		debugService.SetP2P(p2ps)
 		debugService.SetSwarmAddress(&swarmAddress)
 		debugService.MountDebug(false)
@@ -1205,6 +1684,29 @@ func (b *Bee) Shutdown() error {
 			return nil
 		})
 	}
+	if b.s3Server != nil {
+		eg.Go(func() error {
+			if err := b.s3Server.Shutdown(ctx); err != nil {
+				return fmt.Errorf("s3 server: %w", err)
+			}
+			return nil
+		})
+	}
+	if b.pssGRPCServer != nil {
+		eg.Go(func() error {
+			stopped := make(chan struct{})
+			go func() {
+				b.pssGRPCServer.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				b.pssGRPCServer.Stop()
+			}
+			return nil
+		})
+	}
 	if b.debugAPIServer != nil {
 		eg.Go(func() error {
 			if err := b.debugAPIServer.Shutdown(ctx); err != nil {
@@ -1218,8 +1720,10 @@ func (b *Bee) Shutdown() error {
 		mErr = multierror.Append(mErr, err)
 	}
 
+	tryClose(b.auditLogCloser, "audit log")
+
 	var wg sync.WaitGroup
-	wg.Add(7)
+	wg.Add(9)
 	go func() {
 		defer wg.Done()
 		tryClose(b.chainSyncerCloser, "chain syncer")
@@ -1236,6 +1740,14 @@ func (b *Bee) Shutdown() error {
 		defer wg.Done()
 		tryClose(b.pullerCloser, "puller")
 	}()
+	go func() {
+		defer wg.Done()
+		tryClose(b.saludCloser, "salud")
+	}()
+	go func() {
+		defer wg.Done()
+		tryClose(b.batchGossipCloser, "batch gossip")
+	}()
 	go func() {
 		defer wg.Done()
 		tryClose(b.accountingCloser, "accounting")
@@ -1253,8 +1765,11 @@ func (b *Bee) Shutdown() error {
 
 	wg.Wait()
 
+	tryClose(b.pricerCloser, "pricer")
 	tryClose(b.p2pService, "p2p server")
 	tryClose(b.priceOracleCloser, "price oracle service")
+	tryClose(b.swapCloser, "swap")
+	tryClose(b.withdrawalCloser, "chequebook withdrawal")
 
 	wg.Add(3)
 	go func() {
@@ -1279,8 +1794,16 @@ func (b *Bee) Shutdown() error {
 
 	tryClose(b.tracerCloser, "tracer")
 	tryClose(b.tagsCloser, "tag persistence")
+	tryClose(b.tagStoreCloser, "tags store")
 	tryClose(b.topologyCloser, "topology driver")
+	tryClose(b.prewarmCloser, "prewarm")
+	tryClose(b.workingSetCloser, "working set")
+	tryClose(b.contentStatsCloser, "content stats")
+	tryClose(b.billingCloser, "billing")
 	tryClose(b.nsCloser, "netstore")
+	tryClose(b.availabilityCloser, "availability")
+	tryClose(b.webhooksCloser, "webhooks")
+	tryClose(b.profilerCloser, "profiler")
 	tryClose(b.depthMonitorCloser, "depthmonitor service")
 	tryClose(b.storageIncetivesCloser, "storage incentives agent")
 	tryClose(b.stateStoreCloser, "statestore")
@@ -1293,6 +1816,11 @@ func (b *Bee) Shutdown() error {
 var ErrShutdownInProgress error = errors.New("shutdown in progress")
 
 func isChainEnabled(o *Options, swapEndpoint string, logger log.Logger) bool {
+	if o.ChainDisabled {
+		logger.Info("starting without a chain backend, batches are loaded from the static batch table")
+		return false
+	}
+
 	chainDisabled := swapEndpoint == ""
 	lightMode := !o.FullNodeMode
 
@@ -1304,3 +1832,11 @@ func isChainEnabled(o *Options, swapEndpoint string, logger log.Logger) bool {
 	logger.Info("starting with an enabled chain backend")
 	return true // all other modes operate require chain enabled
 }
+
+// durationToBlocks returns the number of blocks which span at least the duration.
+func durationToBlocks(d, blockTime time.Duration) uint64 {
+	if d <= 0 || blockTime <= 0 {
+		return 0
+	}
+	return uint64((d + blockTime - 1) / blockTime)
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/statestore/encrypted"
 	"github.com/ethersphere/bee/pkg/statestore/leveldb"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -17,13 +18,41 @@ import (
 
 // InitStateStore will initialize the stateStore with the given path to the
 // data directory. When given an empty directory path, the function will instead
-// initialize an in-memory state store that will not be persisted.
-func InitStateStore(logger log.Logger, dataDir string) (storage.StateStorer, error) {
+// initialize an in-memory state store that will not be persisted. When given
+// the encryption key, the sensitive values are encrypted at rest, and the store
+// which was encrypted before is not opened without it.
+func InitStateStore(logger log.Logger, dataDir string, encryptionKey []byte) (storage.StateStorer, error) {
+	var (
+		store storage.StateStorer
+		err   error
+	)
 	if dataDir == "" {
 		logger.Warning("using in-mem state store, no node state will be persisted")
-		return leveldb.NewInMemoryStateStore(logger)
+		store, err = leveldb.NewInMemoryStateStore(logger)
+	} else {
+		store, err = leveldb.NewStateStore(filepath.Join(dataDir, "statestore"), logger)
 	}
-	return leveldb.NewStateStore(filepath.Join(dataDir, "statestore"), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if encryptionKey != nil {
+		s, err := encrypted.New(store, encryptionKey, encrypted.DefaultPrefixes, logger)
+		if err != nil {
+			_ = store.Close()
+			return nil, fmt.Errorf("statestore encryption: %w", err)
+		}
+		return s, nil
+	}
+	switch ok, err := encrypted.IsEncrypted(store); {
+	case err != nil:
+		_ = store.Close()
+		return nil, fmt.Errorf("statestore encryption: %w", err)
+	case ok:
+		_ = store.Close()
+		return nil, errors.New("statestore is encrypted, the encryption must be enabled")
+	}
+	return store, nil
 }
 
 const secureOverlayKey = "non-mineable-overlay"
@@ -0,0 +1,119 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
+	"github.com/ethersphere/bee/pkg/transaction"
+	"github.com/ethersphere/bee/pkg/webhook"
+)
+
+const (
+	webhookCheckInterval       = time.Minute
+	webhookChainTimeout        = 30 * time.Second
+	webhookDisconnectThreshold = 20               // disconnections by the peers within the window
+	webhookDisconnectWindow    = 10 * time.Minute // window of the counted disconnections
+)
+
+// webhookSources are the sources of the critical events notified to the
+// webhooks. The events of the nil sources are not watched.
+type webhookSources struct {
+	chainBackend      transaction.Backend
+	chequebook        chequebook.Service
+	chequebookMinimum *big.Int
+	reserve           interface {
+		ReserveSize() (uint64, error)
+		ReserveCapacity() uint64
+	}
+	batchEvents *postage.BatchEventFeed
+	hooker      p2p.Hooker
+}
+
+// initWebhooks returns the webhook service notifying the critical events
+// of the sources to the webhooks of the options.
+func initWebhooks(o *Options, sources webhookSources, logger log.Logger) (*webhook.Service, error) {
+	events := make([]webhook.EventType, 0, len(o.WebhookEvents))
+	for _, e := range o.WebhookEvents {
+		events = append(events, webhook.EventType(e))
+	}
+	w, err := webhook.New(webhook.Options{
+		URLs:   o.WebhookURLs,
+		Secret: o.WebhookSecret,
+		Events: events,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if b := sources.chainBackend; b != nil {
+		w.Watch(webhook.ChainDisconnected, webhookCheckInterval, func(ctx context.Context) (bool, map[string]interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, webhookChainTimeout)
+			defer cancel()
+			if _, err := b.BlockNumber(ctx); err != nil {
+				return true, map[string]interface{}{"error": err.Error()}, nil
+			}
+			return false, nil, nil
+		})
+	}
+
+	if c, min := sources.chequebook, sources.chequebookMinimum; c != nil && min != nil {
+		w.Watch(webhook.ChequebookLowBalance, webhookCheckInterval, func(ctx context.Context) (bool, map[string]interface{}, error) {
+			balance, err := c.AvailableBalance(ctx)
+			if err != nil {
+				return false, nil, err
+			}
+			return balance.Cmp(min) < 0, map[string]interface{}{
+				"availableBalance": balance.String(),
+				"threshold":        min.String(),
+			}, nil
+		})
+	}
+
+	if r := sources.reserve; r != nil {
+		w.Watch(webhook.ReserveFull, webhookCheckInterval, func(context.Context) (bool, map[string]interface{}, error) {
+			size, err := r.ReserveSize()
+			if err != nil {
+				return false, nil, err
+			}
+			capacity := r.ReserveCapacity()
+			return size >= capacity, map[string]interface{}{
+				"reserveSize":     size,
+				"reserveCapacity": capacity,
+			}, nil
+		})
+	}
+
+	if sources.batchEvents != nil {
+		w.WatchBatchEvents(sources.batchEvents)
+	}
+
+	if sources.hooker != nil {
+		// the hooks are kept for the lifetime of the p2p service
+		_ = w.WatchDisconnects(sources.hooker, webhookDisconnectThreshold, webhookDisconnectWindow)
+	}
+
+	return w, nil
+}
+
+// parseWebhookChequebookMinimum parses the chequebook balance
+// below which the webhooks are notified, nil if not set.
+func parseWebhookChequebookMinimum(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	min, ok := new(big.Int).SetString(s, 10)
+	if !ok || min.Sign() < 0 {
+		return nil, fmt.Errorf("invalid webhook chequebook minimum balance %q", s)
+	}
+	return min, nil
+}
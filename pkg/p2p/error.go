@@ -75,14 +75,17 @@ func (e *DisconnectError) Error() string {
 
 type BlockPeerError struct {
 	duration time.Duration
+	offense  Offense
 	err      error
 }
 
 // NewBlockPeerError wraps error and creates a special error that is treated specially
-// by p2p. It causes peer to be disconnected and blocks any new connection for this peer for the provided duration.
-func NewBlockPeerError(duration time.Duration, err error) error {
+// by p2p. It causes peer to be disconnected and blocks any new connection for this peer for the provided duration,
+// increased by the repeated offenses of the same class.
+func NewBlockPeerError(duration time.Duration, offense Offense, err error) error {
 	return &BlockPeerError{
 		duration: duration,
+		offense:  offense,
 		err:      err,
 	}
 }
@@ -101,6 +104,11 @@ func (e *BlockPeerError) Duration() time.Duration {
 	return e.duration
 }
 
+// Offense represents the class of the misbehavior the peer is blocked for.
+func (e *BlockPeerError) Offense() Offense {
+	return e.offense
+}
+
 // IncompatibleStreamError is the error that should be returned by p2p service
 // NewStream method when the stream or its version is not supported.
 type IncompatibleStreamError struct {
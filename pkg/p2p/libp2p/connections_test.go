@@ -84,6 +84,85 @@ func TestConnectDisconnect(t *testing.T) {
 	expectPeersEventually(t, s1)
 }
 
+func TestAnnounceUnderlay(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ab1 := addressbook.New(mock.NewStateStore())
+	s1, overlay1 := newService(t, 1, libp2pServiceOpts{Addressbook: ab1, libp2pOpts: libp2p.Options{
+		FullNode: true,
+	}})
+	s2, overlay2 := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode: true,
+	}})
+
+	if _, err := s2.Connect(ctx, serviceUnderlayAddress(t, s1)); err != nil {
+		t.Fatal(err)
+	}
+	expectPeers(t, s2, overlay1)
+	expectPeersEventually(t, s1, overlay2)
+
+	underlay, err := ma.NewMultiaddr("/ip4/1.2.3.4/tcp/1634")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2.AnnounceUnderlay(ctx, underlay)
+
+	err = spinlock.Wait(5*time.Second, func() bool {
+		addr, err := ab1.Get(overlay2)
+		if err != nil {
+			return false
+		}
+		return addr.Underlay.Equal(underlay)
+	})
+	if err != nil {
+		t.Fatal("timed out waiting for the announced underlay")
+	}
+}
+
+func TestConnectFeatures(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s1, overlay1 := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode: true,
+		Features: p2p.FeatureCompression | p2p.FeatureMultiReceiptPushsync,
+	}})
+	s2, overlay2 := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		Features: p2p.FeatureCompression,
+	}})
+
+	addr := serviceUnderlayAddress(t, s1)
+
+	bzzAddr, err := s2.Connect(ctx, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectPeers(t, s2, overlay1)
+	expectPeersEventually(t, s1, overlay2)
+
+	if f, ok := s2.PeerFeatures(overlay1); !ok || f != p2p.FeatureCompression|p2p.FeatureMultiReceiptPushsync {
+		t.Fatalf("got features %b (connected %v) of the inbound peer, want %b", f, ok, p2p.FeatureCompression|p2p.FeatureMultiReceiptPushsync)
+	}
+	if f, ok := s1.PeerFeatures(overlay2); !ok || f != p2p.FeatureCompression {
+		t.Fatalf("got features %b (connected %v) of the outbound peer, want %b", f, ok, p2p.FeatureCompression)
+	}
+
+	if err := s2.Disconnect(bzzAddr.Overlay, testDisconnectMsg); err != nil {
+		t.Fatal(err)
+	}
+	expectPeers(t, s2)
+
+	if _, ok := s2.PeerFeatures(overlay1); ok {
+		t.Fatal("got features of the disconnected peer")
+	}
+}
+
 func TestConnectToLightPeer(t *testing.T) {
 	t.Parallel()
 
@@ -574,7 +653,7 @@ func TestBlocklisting(t *testing.T) {
 	expectPeers(t, s2, overlay1)
 	expectPeersEventually(t, s1, overlay2)
 
-	if err := s2.Blocklist(overlay1, 0, testBlocklistMsg); err != nil {
+	if err := s2.Blocklist(overlay1, 0, p2p.OffenseProtocolError, testBlocklistMsg); err != nil {
 		t.Fatal(err)
 	}
 
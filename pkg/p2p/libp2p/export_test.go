@@ -12,6 +12,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/network"
 	libp2ppeer "github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
 )
 
 func (s *Service) HandshakeService() *handshake.Service {
@@ -42,3 +43,7 @@ func WithHostFactory(factory func(...libp2pm.Option) (host.Host, error)) Options
 		hostFactory: factory,
 	}
 }
+
+func (s *Service) AnnounceUnderlay(ctx context.Context, underlay ma.Multiaddr) {
+	s.announceUnderlay(ctx, underlay)
+}
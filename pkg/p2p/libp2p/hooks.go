@@ -0,0 +1,81 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package libp2p
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// hooks keeps the registered p2p.Hooks.
+type hooks struct {
+	mu    sync.RWMutex
+	hooks map[uint64]p2p.Hooks
+	next  uint64
+}
+
+func (h *hooks) add(hs p2p.Hooks) (remove func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.hooks == nil {
+		h.hooks = make(map[uint64]p2p.Hooks)
+	}
+	id := h.next
+	h.next++
+	h.hooks[id] = hs
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.hooks, id)
+		})
+	}
+}
+
+func (h *hooks) connected(info p2p.PeerInfo) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, hs := range h.hooks {
+		if hs.OnConnect != nil {
+			hs.OnConnect(info)
+		}
+	}
+}
+
+func (h *hooks) disconnected(peer p2p.Peer, reason string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, hs := range h.hooks {
+		if hs.OnDisconnect != nil {
+			hs.OnDisconnect(peer, reason)
+		}
+	}
+}
+
+func (h *hooks) blocklisted(overlay swarm.Address, duration time.Duration, reason string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, hs := range h.hooks {
+		if hs.OnBlocklist != nil {
+			hs.OnBlocklist(overlay, duration, reason)
+		}
+	}
+}
+
+var _ p2p.Hooker = (*Service)(nil)
+
+// AddHooks registers the hooks invoked on the peer connection events.
+func (s *Service) AddHooks(h p2p.Hooks) (remove func()) {
+	return s.hooks.add(h)
+}
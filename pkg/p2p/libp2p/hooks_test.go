@@ -0,0 +1,126 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package libp2p_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/libp2p"
+	"github.com/ethersphere/bee/pkg/spinlock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// hookRecorder records the events received through the p2p.Hooks.
+type hookRecorder struct {
+	mu           sync.Mutex
+	connected    []p2p.PeerInfo
+	disconnected []string
+	blocklisted  []swarm.Address
+}
+
+func (r *hookRecorder) hooks() p2p.Hooks {
+	return p2p.Hooks{
+		OnConnect: func(info p2p.PeerInfo) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.connected = append(r.connected, info)
+		},
+		OnDisconnect: func(_ p2p.Peer, reason string) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.disconnected = append(r.disconnected, reason)
+		},
+		OnBlocklist: func(overlay swarm.Address, _ time.Duration, _ string) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.blocklisted = append(r.blocklisted, overlay)
+		},
+	}
+}
+
+func (r *hookRecorder) waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	err := spinlock.Wait(5*time.Second, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return cond()
+	})
+	if err != nil {
+		t.Fatal("timed out waiting for hooks")
+	}
+}
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	s1, overlay1 := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode: true,
+	}})
+	s2, overlay2 := newService(t, 1, libp2pServiceOpts{})
+
+	var r1, r2 hookRecorder
+	s1.AddHooks(r1.hooks())
+	s2.AddHooks(r2.hooks())
+
+	if _, err := s2.Connect(context.Background(), serviceUnderlayAddress(t, s1)); err != nil {
+		t.Fatal(err)
+	}
+
+	r1.waitFor(t, func() bool { return len(r1.connected) == 1 })
+	r2.waitFor(t, func() bool { return len(r2.connected) == 1 })
+
+	if info := r1.connected[0]; !info.Address.Equal(overlay2) || !info.Inbound || info.FullNode {
+		t.Fatalf("got inbound peer info %+v", info)
+	}
+	if info := r2.connected[0]; !info.Address.Equal(overlay1) || info.Inbound || !info.FullNode || info.Underlay == nil {
+		t.Fatalf("got outbound peer info %+v", info)
+	}
+
+	if err := s2.Blocklist(overlay1, 0, p2p.OffenseProtocolError, testBlocklistMsg); err != nil {
+		t.Fatal(err)
+	}
+
+	r2.waitFor(t, func() bool { return len(r2.blocklisted) == 1 && len(r2.disconnected) == 1 })
+	if !r2.blocklisted[0].Equal(overlay1) {
+		t.Fatalf("got blocklisted %s, want %s", r2.blocklisted[0], overlay1)
+	}
+	if r2.disconnected[0] != testBlocklistMsg {
+		t.Fatalf("got disconnect reason %q, want %q", r2.disconnected[0], testBlocklistMsg)
+	}
+
+	r1.waitFor(t, func() bool { return len(r1.disconnected) == 1 })
+	if r1.disconnected[0] != "disconnected by peer" {
+		t.Fatalf("got disconnect reason %q", r1.disconnected[0])
+	}
+}
+
+func TestHooksRemove(t *testing.T) {
+	t.Parallel()
+
+	s1, _ := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode: true,
+	}})
+	s2, overlay2 := newService(t, 1, libp2pServiceOpts{})
+
+	var r hookRecorder
+	remove := s1.AddHooks(r.hooks())
+	remove()
+
+	if _, err := s2.Connect(context.Background(), serviceUnderlayAddress(t, s1)); err != nil {
+		t.Fatal(err)
+	}
+	expectPeersEventually(t, s1, overlay2)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.connected) != 0 {
+		t.Fatalf("got %d connect events after the hooks were removed", len(r.connected))
+	}
+}
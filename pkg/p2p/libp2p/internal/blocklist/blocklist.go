@@ -6,6 +6,7 @@ package blocklist
 
 import (
 	"errors"
+	"math"
 	"strings"
 	"time"
 
@@ -14,7 +15,27 @@ import (
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
-var keyPrefix = "blocklist-"
+var (
+	keyPrefix         = "blocklist-"
+	offensesKeyPrefix = "blocklist_offenses-"
+)
+
+// policy defines how the offenses of a class are penalized. Every offense
+// doubles the blocklist duration of the next offense of the same class,
+// while the penalty halves with every half-life of good behavior.
+type policy struct {
+	halfLife    time.Duration // period after which the penalty of the peer is halved
+	maxDuration time.Duration // maximal increased blocklist duration
+}
+
+var policies = map[p2p.Offense]policy{
+	p2p.OffenseProtocolError:       {halfLife: time.Hour, maxDuration: 24 * time.Hour},
+	p2p.OffenseAccountingViolation: {halfLife: 6 * time.Hour, maxDuration: 7 * 24 * time.Hour},
+	p2p.OffenseInvalidChunk:        {halfLife: 24 * time.Hour, maxDuration: 7 * 24 * time.Hour},
+}
+
+// defaultPolicy is the policy of the offenses without a defined policy.
+var defaultPolicy = policies[p2p.OffenseProtocolError]
 
 type currentTimeFn = func() time.Time
 
@@ -54,24 +75,78 @@ func (b *Blocklist) Exists(overlay swarm.Address) (bool, error) {
 	return true, nil
 }
 
-func (b *Blocklist) Add(overlay swarm.Address, duration time.Duration) (err error) {
+// offense is the record of the offenses of a class committed by the peer.
+type offense struct {
+	Penalty   float64   `json:"penalty"`   // number of the offenses decayed by the good behavior
+	Timestamp time.Time `json:"timestamp"` // time of the last offense
+}
+
+// Add blocklists the peer for the base duration of the offense increased
+// exponentially by the penalty of the former offenses of the same class.
+// It returns the duration for which the peer is blocklisted.
+func (b *Blocklist) Add(overlay swarm.Address, duration time.Duration, o p2p.Offense) (time.Duration, error) {
+	duration, err := b.penalize(overlay, duration, o)
+	if err != nil {
+		return 0, err
+	}
+
 	key := generateKey(overlay)
-	_, d, err := b.get(key)
+	t, d, err := b.get(key)
 	if err != nil {
 		if !errors.Is(err, storage.ErrNotFound) {
-			return err
+			return 0, err
 		}
 	}
 
 	// if peer is already blacklisted, blacklist it for the maximum amount of time
-	if duration < d && duration != 0 || d == 0 {
+	expired := b.currentTimeFn().Sub(t) > d && d != 0
+	if !expired && (duration < d && duration != 0 || d == 0) {
 		duration = d
 	}
 
-	return b.store.Put(key, &entry{
+	if err := b.store.Put(key, &entry{
 		Timestamp: b.currentTimeFn(),
 		Duration:  duration.String(),
-	})
+	}); err != nil {
+		return 0, err
+	}
+	return duration, nil
+}
+
+// penalize records the offense of the peer and returns the base duration
+// increased by the decayed penalty of the former offenses of the class.
+func (b *Blocklist) penalize(overlay swarm.Address, duration time.Duration, o p2p.Offense) (time.Duration, error) {
+	key := generateOffensesKey(overlay)
+	offenses := make(map[p2p.Offense]offense)
+	if err := b.store.Get(key, &offenses); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return 0, err
+	}
+
+	pol, ok := policies[o]
+	if !ok {
+		pol = defaultPolicy
+	}
+
+	now := b.currentTimeFn()
+	rec := offenses[o]
+	if elapsed := now.Sub(rec.Timestamp); rec.Penalty > 0 && elapsed > 0 {
+		rec.Penalty *= math.Pow(0.5, float64(elapsed)/float64(pol.halfLife))
+	}
+
+	// the infinite duration and the durations over the maximum are not increased
+	if duration != 0 && duration < pol.maxDuration {
+		increased := float64(duration) * math.Pow(2, rec.Penalty)
+		duration = time.Duration(math.Min(increased, float64(pol.maxDuration)))
+	}
+
+	offenses[o] = offense{
+		Penalty:   rec.Penalty + 1,
+		Timestamp: now,
+	}
+	if err := b.store.Put(key, offenses); err != nil {
+		return 0, err
+	}
+	return duration, nil
 }
 
 // Peers returns all currently blocklisted peers.
@@ -124,6 +199,10 @@ func generateKey(overlay swarm.Address) string {
 	return keyPrefix + overlay.String()
 }
 
+func generateOffensesKey(overlay swarm.Address) string {
+	return offensesKeyPrefix + overlay.String()
+}
+
 func unmarshalKey(s string) (swarm.Address, error) {
 	addr := s[len(keyPrefix):] // trim prefix
 	return swarm.ParseHexAddress(addr)
@@ -33,12 +33,12 @@ func TestExist(t *testing.T) {
 	}
 
 	// add forever
-	if err := bl.Add(addr1, 0); err != nil {
+	if _, err := bl.Add(addr1, 0, p2p.OffenseProtocolError); err != nil {
 		t.Fatal(err)
 	}
 
 	// add for 50 miliseconds
-	if err := bl.Add(addr2, time.Millisecond*50); err != nil {
+	if _, err := bl.Add(addr2, time.Millisecond*50, p2p.OffenseProtocolError); err != nil {
 		t.Fatal(err)
 	}
 
@@ -72,12 +72,12 @@ func TestPeers(t *testing.T) {
 	bl := blocklist.NewBlocklistWithCurrentTimeFn(mock.NewStateStore(), ctMock.Time)
 
 	// add forever
-	if err := bl.Add(addr1, 0); err != nil {
+	if _, err := bl.Add(addr1, 0, p2p.OffenseProtocolError); err != nil {
 		t.Fatal(err)
 	}
 
 	// add for 50 miliseconds
-	if err := bl.Add(addr2, time.Millisecond*50); err != nil {
+	if _, err := bl.Add(addr2, time.Millisecond*50, p2p.OffenseProtocolError); err != nil {
 		t.Fatal(err)
 	}
 
@@ -109,6 +109,75 @@ func TestPeers(t *testing.T) {
 	}
 }
 
+func TestOffenses(t *testing.T) {
+	t.Parallel()
+
+	addr1 := swarm.NewAddress([]byte{0, 1, 2, 3})
+	addr2 := swarm.NewAddress([]byte{4, 5, 6, 7})
+	store := mock.NewStateStore()
+	now := time.Now()
+	ctMock := &currentTimeMock{time: now}
+
+	add := func(t *testing.T, bl *blocklist.Blocklist, addr swarm.Address, duration time.Duration, offense p2p.Offense, want time.Duration) {
+		t.Helper()
+
+		got, err := bl.Add(addr, duration, offense)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("got duration %v, want %v", got, want)
+		}
+	}
+
+	bl := blocklist.NewBlocklistWithCurrentTimeFn(store, ctMock.Time)
+
+	// repeated offenses double the duration
+	add(t, bl, addr1, time.Minute, p2p.OffenseInvalidChunk, time.Minute)
+	add(t, bl, addr1, time.Minute, p2p.OffenseInvalidChunk, 2*time.Minute)
+	add(t, bl, addr1, time.Minute, p2p.OffenseInvalidChunk, 4*time.Minute)
+
+	// the offense classes are penalized independently,
+	// the peer stays blocklisted for the longest duration
+	add(t, bl, addr1, 10*time.Minute, p2p.OffenseProtocolError, 10*time.Minute)
+	add(t, bl, addr1, time.Second, p2p.OffenseProtocolError, 10*time.Minute)
+
+	exists, err := bl.Exists(addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Fatal("got not exists, expected exists")
+	}
+
+	// the duration is capped
+	for i := 0; i < 20; i++ {
+		if _, err := bl.Add(addr1, time.Hour, p2p.OffenseProtocolError); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctMock.SetTime(now.Add(24*time.Hour + time.Second))
+	exists, err = bl.Exists(addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Fatal("got exists, expected not exists")
+	}
+
+	// the penalty is persisted and halved by every half-life of good behavior
+	ctMock.SetTime(now)
+	add(t, bl, addr2, time.Minute, p2p.OffenseAccountingViolation, time.Minute)
+	add(t, bl, addr2, time.Minute, p2p.OffenseAccountingViolation, 2*time.Minute)
+	bl = blocklist.NewBlocklistWithCurrentTimeFn(store, ctMock.Time)
+	ctMock.SetTime(now.Add(6 * time.Hour))
+	add(t, bl, addr2, time.Minute, p2p.OffenseAccountingViolation, 2*time.Minute)
+
+	// the penalty decays to the base duration
+	ctMock.SetTime(now.Add(365 * 24 * time.Hour))
+	add(t, bl, addr2, time.Minute, p2p.OffenseAccountingViolation, time.Minute)
+}
+
 func isIn(p swarm.Address, peers []p2p.Peer) bool {
 	for _, v := range peers {
 		if v.Address.Equal(p) {
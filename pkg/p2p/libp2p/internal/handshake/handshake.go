@@ -33,6 +33,9 @@ const (
 	ProtocolVersion = "8.0.0"
 	// StreamName is the name of the stream used for handshake purposes.
 	StreamName = "handshake"
+	// AnnounceStreamName is the name of the stream used to announce
+	// the changed underlay to the connected peers.
+	AnnounceStreamName = "announce"
 	// MaxWelcomeMessageLength is maximum number of characters allowed in the welcome message.
 	MaxWelcomeMessageLength = 140
 	handshakeTimeout        = 15 * time.Second
@@ -66,6 +69,7 @@ type Service struct {
 	advertisableAddresser AdvertisableAddressResolver
 	overlay               swarm.Address
 	fullNode              bool
+	features              p2p.Features
 	nonce                 []byte
 	networkID             uint64
 	validateOverlay       bool
@@ -80,6 +84,7 @@ type Service struct {
 type Info struct {
 	BzzAddress *bzz.Address
 	FullNode   bool
+	Features   p2p.Features
 }
 
 func (i *Info) LightString() string {
@@ -117,6 +122,12 @@ func (s *Service) SetPicker(n p2p.Picker) {
 	s.picker = n
 }
 
+// SetFeatures sets the optional protocol features advertised to the peers.
+// It must be called before the first handshake.
+func (s *Service) SetFeatures(f p2p.Features) {
+	s.features = f
+}
+
 // Handshake initiates a handshake with a peer.
 func (s *Service) Handshake(ctx context.Context, stream p2p.Stream, peerMultiaddr ma.Multiaddr, peerID libp2ppeer.ID) (i *Info, err error) {
 	loggerV1 := s.logger.V(1).Register()
@@ -196,6 +207,7 @@ func (s *Service) Handshake(ctx context.Context, stream p2p.Stream, peerMultiadd
 		NetworkID:      s.networkID,
 		FullNode:       s.fullNode,
 		Nonce:          s.nonce,
+		Features:       uint64(s.features),
 		WelcomeMessage: welcomeMessage,
 	}
 
@@ -211,6 +223,7 @@ func (s *Service) Handshake(ctx context.Context, stream p2p.Stream, peerMultiadd
 	return &Info{
 		BzzAddress: remoteBzzAddress,
 		FullNode:   resp.Ack.FullNode,
+		Features:   p2p.Features(resp.Ack.Features),
 	}, nil
 }
 
@@ -274,6 +287,7 @@ func (s *Service) Handle(ctx context.Context, stream p2p.Stream, remoteMultiaddr
 			NetworkID:      s.networkID,
 			FullNode:       s.fullNode,
 			Nonce:          s.nonce,
+			Features:       uint64(s.features),
 			WelcomeMessage: welcomeMessage,
 		},
 	}); err != nil {
@@ -314,9 +328,68 @@ func (s *Service) Handle(ctx context.Context, stream p2p.Stream, remoteMultiaddr
 	return &Info{
 		BzzAddress: remoteBzzAddress,
 		FullNode:   ack.FullNode,
+		Features:   p2p.Features(ack.Features),
 	}, nil
 }
 
+// Announce sends the address of the node with the changed underlay
+// to the peer it is already connected to.
+func (s *Service) Announce(ctx context.Context, stream p2p.Stream, underlay ma.Multiaddr) error {
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+
+	bzzAddress, err := bzz.NewAddress(s.signer, underlay, s.overlay, s.networkID, s.nonce)
+	if err != nil {
+		return err
+	}
+	underlayBytes, err := bzzAddress.Underlay.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	w := protobuf.NewWriter(stream)
+	if err := w.WriteMsgWithContext(ctx, &pb.Ack{
+		Address: &pb.BzzAddress{
+			Underlay:  underlayBytes,
+			Overlay:   bzzAddress.Overlay.Bytes(),
+			Signature: bzzAddress.Signature,
+		},
+		NetworkID: s.networkID,
+		FullNode:  s.fullNode,
+		Nonce:     s.nonce,
+		Features:  uint64(s.features),
+	}); err != nil {
+		return fmt.Errorf("write announce message: %w", err)
+	}
+	return nil
+}
+
+// HandleAnnounce handles the changed underlay announced by the connected
+// peer with the overlay and returns its new address.
+func (s *Service) HandleAnnounce(ctx context.Context, stream p2p.Stream, overlay swarm.Address) (*bzz.Address, error) {
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+
+	r := protobuf.NewReader(stream)
+	var ack pb.Ack
+	if err := r.ReadMsgWithContext(ctx, &ack); err != nil {
+		return nil, fmt.Errorf("read announce message: %w", err)
+	}
+	if ack.NetworkID != s.networkID {
+		return nil, ErrNetworkIDIncompatible
+	}
+
+	bzzAddress, err := s.parseCheckAck(&ack)
+	if err != nil {
+		return nil, err
+	}
+	// the peer can announce only its own address
+	if !bzzAddress.Overlay.Equal(overlay) {
+		return nil, ErrInvalidAck
+	}
+	return bzzAddress, nil
+}
+
 // SetWelcomeMessage sets the new handshake welcome message.
 func (s *Service) SetWelcomeMessage(msg string) (err error) {
 	if len(msg) > MaxWelcomeMessageLength {
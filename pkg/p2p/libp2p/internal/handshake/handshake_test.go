@@ -175,6 +175,59 @@ func TestHandshake(t *testing.T) {
 		}
 	})
 
+	t.Run("Handshake - features", func(t *testing.T) {
+		handshakeService, err := handshake.New(signer1, aaddresser, node1Info.BzzAddress.Overlay, networkID, true, nonce, "", true, node1AddrInfo.ID, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handshakeService.SetFeatures(p2p.FeatureCompression)
+
+		var buffer1 bytes.Buffer
+		var buffer2 bytes.Buffer
+		stream1 := mock.NewStream(&buffer1, &buffer2)
+		stream2 := mock.NewStream(&buffer2, &buffer1)
+
+		w, r := protobuf.NewWriterAndReader(stream2)
+		if err := w.WriteMsg(&pb.SynAck{
+			Syn: &pb.Syn{
+				ObservedUnderlay: node1maBinary,
+			},
+			Ack: &pb.Ack{
+				Address: &pb.BzzAddress{
+					Underlay:  node2maBinary,
+					Overlay:   node2BzzAddress.Overlay.Bytes(),
+					Signature: node2BzzAddress.Signature,
+				},
+				NetworkID: networkID,
+				FullNode:  true,
+				Nonce:     nonce,
+				Features:  uint64(p2p.FeatureCompression | p2p.FeatureMultiReceiptPushsync),
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := handshakeService.Handshake(context.Background(), stream1, node2AddrInfo.Addrs[0], node2AddrInfo.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Features.Has(p2p.FeatureCompression | p2p.FeatureMultiReceiptPushsync) {
+			t.Fatalf("got features %b, want compression and multi receipt pushsync", res.Features)
+		}
+
+		var syn pb.Syn
+		if err := r.ReadMsg(&syn); err != nil {
+			t.Fatal(err)
+		}
+		var ack pb.Ack
+		if err := r.ReadMsg(&ack); err != nil {
+			t.Fatal(err)
+		}
+		if got := p2p.Features(ack.Features); got != p2p.FeatureCompression {
+			t.Fatalf("bad ack - features: got %b, want %b", got, p2p.FeatureCompression)
+		}
+	})
+
 	t.Run("Handshake - picker error", func(t *testing.T) {
 		handshakeService, err := handshake.New(signer1, aaddresser, node1Info.BzzAddress.Overlay, networkID, true, nonce, "", true, node1AddrInfo.ID, logger)
 		if err != nil {
@@ -669,6 +722,76 @@ func TestHandshake(t *testing.T) {
 			t.Fatal("expected nil res")
 		}
 	})
+
+	t.Run("Announce - OK", func(t *testing.T) {
+		var buffer1 bytes.Buffer
+		var buffer2 bytes.Buffer
+		stream1 := mock.NewStream(&buffer1, &buffer2)
+		stream2 := mock.NewStream(&buffer2, &buffer1)
+
+		handshakeService2, err := handshake.New(signer2, aaddresser, node2Info.BzzAddress.Overlay, networkID, true, nonce, "", true, node2AddrInfo.ID, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := handshakeService.Announce(context.Background(), stream1, node1ma); err != nil {
+			t.Fatal(err)
+		}
+		res, err := handshakeService2.HandleAnnounce(context.Background(), stream2, node1BzzAddress.Overlay)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(node1BzzAddress) {
+			t.Fatalf("got address %s, want %s", res, node1BzzAddress)
+		}
+	})
+
+	t.Run("Announce - address of another peer", func(t *testing.T) {
+		var buffer1 bytes.Buffer
+		var buffer2 bytes.Buffer
+		stream1 := mock.NewStream(&buffer1, &buffer2)
+		stream2 := mock.NewStream(&buffer2, &buffer1)
+
+		if err := handshakeService.Announce(context.Background(), stream1, node1ma); err != nil {
+			t.Fatal(err)
+		}
+		res, err := handshakeService.HandleAnnounce(context.Background(), stream2, node2BzzAddress.Overlay)
+		if !errors.Is(err, handshake.ErrInvalidAck) {
+			t.Fatalf("got error %v, want %v", err, handshake.ErrInvalidAck)
+		}
+		if res != nil {
+			t.Fatal("expected nil res")
+		}
+	})
+
+	t.Run("Announce - networkID mismatch", func(t *testing.T) {
+		var buffer1 bytes.Buffer
+		var buffer2 bytes.Buffer
+		stream1 := mock.NewStream(&buffer1, &buffer2)
+		stream2 := mock.NewStream(&buffer2, &buffer1)
+
+		w := protobuf.NewWriter(stream2)
+		if err := w.WriteMsg(&pb.Ack{
+			Address: &pb.BzzAddress{
+				Underlay:  node2maBinary,
+				Overlay:   node2BzzAddress.Overlay.Bytes(),
+				Signature: node2BzzAddress.Signature,
+			},
+			NetworkID: 5,
+			FullNode:  true,
+			Nonce:     nonce,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := handshakeService.HandleAnnounce(context.Background(), stream1, node2BzzAddress.Overlay)
+		if !errors.Is(err, handshake.ErrNetworkIDIncompatible) {
+			t.Fatalf("got error %v, want %v", err, handshake.ErrNetworkIDIncompatible)
+		}
+		if res != nil {
+			t.Fatal("expected nil res")
+		}
+	})
 }
 
 func mockPicker(f func(p2p.Peer) bool) p2p.Picker {
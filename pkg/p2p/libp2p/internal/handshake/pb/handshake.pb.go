@@ -71,6 +71,7 @@ type Ack struct {
 	NetworkID      uint64      `protobuf:"varint,2,opt,name=NetworkID,proto3" json:"NetworkID,omitempty"`
 	FullNode       bool        `protobuf:"varint,3,opt,name=FullNode,proto3" json:"FullNode,omitempty"`
 	Nonce          []byte      `protobuf:"bytes,4,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	Features       uint64      `protobuf:"varint,5,opt,name=Features,proto3" json:"Features,omitempty"`
 	WelcomeMessage string      `protobuf:"bytes,99,opt,name=WelcomeMessage,proto3" json:"WelcomeMessage,omitempty"`
 }
 
@@ -135,6 +136,13 @@ func (m *Ack) GetNonce() []byte {
 	return nil
 }
 
+func (m *Ack) GetFeatures() uint64 {
+	if m != nil {
+		return m.Features
+	}
+	return 0
+}
+
 func (m *Ack) GetWelcomeMessage() string {
 	if m != nil {
 		return m.WelcomeMessage
@@ -264,27 +272,28 @@ func init() {
 func init() { proto.RegisterFile("handshake.proto", fileDescriptor_a77305914d5d202f) }
 
 var fileDescriptor_a77305914d5d202f = []byte{
-	// 318 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xcd, 0x4a, 0xc3, 0x40,
-	0x14, 0x85, 0x3b, 0x4d, 0xed, 0xcf, 0xb5, 0x54, 0x19, 0x14, 0x82, 0x94, 0x10, 0xb2, 0x90, 0xe0,
-	0xa2, 0xa2, 0x3e, 0x41, 0x8b, 0x08, 0x82, 0xb6, 0x30, 0x41, 0x04, 0x57, 0xa6, 0x99, 0x4b, 0x2b,
-	0x89, 0x33, 0x65, 0xa6, 0xad, 0xa4, 0x4f, 0xe1, 0x93, 0xf8, 0x1c, 0x2e, 0xbb, 0x74, 0x29, 0xed,
-	0x8b, 0x48, 0xa6, 0x3f, 0xd1, 0xba, 0x3c, 0xe7, 0x9e, 0x99, 0xf9, 0xce, 0x1d, 0x38, 0x18, 0x86,
-	0x82, 0xeb, 0x61, 0x18, 0x63, 0x6b, 0xa4, 0xe4, 0x58, 0xd2, 0xda, 0xd6, 0xf0, 0x2e, 0xc0, 0x0a,
-	0x52, 0x41, 0xcf, 0xe0, 0xb0, 0xd7, 0xd7, 0xa8, 0xa6, 0xc8, 0x1f, 0x04, 0x47, 0x95, 0x84, 0xa9,
-	0x4d, 0x5c, 0xe2, 0xd7, 0xd9, 0x3f, 0xdf, 0xfb, 0x20, 0x60, 0xb5, 0xa3, 0x98, 0x9e, 0x43, 0xa5,
-	0xcd, 0xb9, 0x42, 0xad, 0x4d, 0x74, 0xff, 0xf2, 0xb8, 0x95, 0x3f, 0xd4, 0x99, 0xcd, 0xd6, 0x43,
-	0xb6, 0x49, 0xd1, 0x26, 0xd4, 0xba, 0x38, 0x7e, 0x93, 0x2a, 0xbe, 0xbd, 0xb6, 0x8b, 0x2e, 0xf1,
-	0x4b, 0x2c, 0x37, 0xe8, 0x09, 0x54, 0x6f, 0x26, 0x49, 0xd2, 0x95, 0x1c, 0x6d, 0xcb, 0x25, 0x7e,
-	0x95, 0x6d, 0x35, 0x3d, 0x82, 0xbd, 0xae, 0x14, 0x11, 0xda, 0x25, 0xc3, 0xb4, 0x12, 0xf4, 0x14,
-	0x1a, 0x8f, 0x98, 0x44, 0xf2, 0x15, 0xef, 0x51, 0xeb, 0x70, 0x80, 0x76, 0xe4, 0x12, 0xbf, 0xc6,
-	0x76, 0x5c, 0xef, 0x0e, 0xca, 0x41, 0x2a, 0x32, 0x64, 0xd7, 0xb4, 0x5d, 0xe3, 0x36, 0x7e, 0xe1,
-	0x06, 0xa9, 0x60, 0x66, 0x11, 0xae, 0xe9, 0x66, 0xe8, 0xfe, 0x26, 0xda, 0x51, 0xcc, 0xb2, 0x91,
-	0xf7, 0x0c, 0x90, 0x97, 0xcb, 0xa8, 0x77, 0x16, 0xb6, 0xd5, 0x59, 0xdf, 0xe0, 0x65, 0x20, 0xc2,
-	0xf1, 0x44, 0xa1, 0xb9, 0xb1, 0xce, 0x72, 0x83, 0xda, 0x50, 0xe9, 0x4d, 0x57, 0x07, 0x2d, 0x33,
-	0xdb, 0xc8, 0x4e, 0xf3, 0x73, 0xe1, 0x90, 0xf9, 0xc2, 0x21, 0xdf, 0x0b, 0x87, 0xbc, 0x2f, 0x9d,
-	0xc2, 0x7c, 0xe9, 0x14, 0xbe, 0x96, 0x4e, 0xe1, 0xa9, 0x38, 0xea, 0xf7, 0xcb, 0xe6, 0x0f, 0xaf,
-	0x7e, 0x02, 0x00, 0x00, 0xff, 0xff, 0x87, 0xa7, 0x49, 0x00, 0xd6, 0x01, 0x00, 0x00,
+	// 326 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x51, 0xcd, 0x4a, 0xf3, 0x40,
+	0x14, 0xed, 0x34, 0xfd, 0xbd, 0x5f, 0xe9, 0x27, 0x83, 0xc2, 0x20, 0x25, 0x84, 0x2c, 0x24, 0xb8,
+	0xa8, 0xa8, 0x4f, 0xd0, 0x22, 0x82, 0xa0, 0x2d, 0x4c, 0x10, 0xc1, 0x95, 0x69, 0x72, 0x69, 0x25,
+	0x71, 0x52, 0x66, 0xda, 0x4a, 0xfa, 0x14, 0x3e, 0x96, 0xb8, 0xea, 0xd2, 0xa5, 0x34, 0x2f, 0x22,
+	0x99, 0xb6, 0x89, 0xd6, 0xe5, 0x39, 0xf7, 0xdc, 0x3b, 0xe7, 0x9c, 0x81, 0xff, 0x13, 0x4f, 0x04,
+	0x6a, 0xe2, 0x85, 0xd8, 0x9d, 0xca, 0x78, 0x16, 0xd3, 0x66, 0x4e, 0xd8, 0xe7, 0x60, 0xb8, 0x89,
+	0xa0, 0xa7, 0x70, 0x30, 0x1c, 0x29, 0x94, 0x0b, 0x0c, 0xee, 0x45, 0x80, 0x32, 0xf2, 0x12, 0x46,
+	0x2c, 0xe2, 0xb4, 0xf8, 0x1f, 0xde, 0xfe, 0x20, 0x60, 0xf4, 0xfc, 0x90, 0x9e, 0x41, 0xbd, 0x17,
+	0x04, 0x12, 0x95, 0xd2, 0xd2, 0x7f, 0x17, 0x47, 0xdd, 0xe2, 0xa1, 0xfe, 0x72, 0xb9, 0x1d, 0xf2,
+	0x9d, 0x8a, 0x76, 0xa0, 0x39, 0xc0, 0xd9, 0x6b, 0x2c, 0xc3, 0x9b, 0x2b, 0x56, 0xb6, 0x88, 0x53,
+	0xe1, 0x05, 0x41, 0x8f, 0xa1, 0x71, 0x3d, 0x8f, 0xa2, 0x41, 0x1c, 0x20, 0x33, 0x2c, 0xe2, 0x34,
+	0x78, 0x8e, 0xe9, 0x21, 0x54, 0x07, 0xb1, 0xf0, 0x91, 0x55, 0xb4, 0xa7, 0x0d, 0xd0, 0x1b, 0xe8,
+	0xcd, 0xe6, 0x12, 0x15, 0xab, 0xea, 0x73, 0x39, 0xa6, 0x27, 0xd0, 0x7e, 0xc0, 0xc8, 0x8f, 0x5f,
+	0xf0, 0x0e, 0x95, 0xf2, 0xc6, 0xc8, 0x7c, 0x8b, 0x38, 0x4d, 0xbe, 0xc7, 0xda, 0xb7, 0x50, 0x73,
+	0x13, 0x91, 0xc5, 0xb1, 0x74, 0x13, 0xdb, 0x28, 0xed, 0x1f, 0x51, 0xdc, 0x44, 0x70, 0x5d, 0x92,
+	0xa5, 0x73, 0x6b, 0xe7, 0xbf, 0x15, 0x3d, 0x3f, 0xe4, 0xd9, 0xc8, 0x7e, 0x02, 0x28, 0x82, 0x67,
+	0xfe, 0xf6, 0xca, 0xcc, 0x71, 0xd6, 0x85, 0xfb, 0x3c, 0x16, 0xda, 0xad, 0xbe, 0xd8, 0xe2, 0x05,
+	0x41, 0x19, 0xd4, 0x87, 0x8b, 0xcd, 0xa2, 0xa1, 0x67, 0x3b, 0xd8, 0xef, 0xbc, 0xaf, 0x4d, 0xb2,
+	0x5a, 0x9b, 0xe4, 0x6b, 0x6d, 0x92, 0xb7, 0xd4, 0x2c, 0xad, 0x52, 0xb3, 0xf4, 0x99, 0x9a, 0xa5,
+	0xc7, 0xf2, 0x74, 0x34, 0xaa, 0xe9, 0xff, 0xbd, 0xfc, 0x0e, 0x00, 0x00, 0xff, 0xff, 0xbb, 0xa7,
+	0x7f, 0xf6, 0xf2, 0x01, 0x00, 0x00,
 }
 
 func (m *Syn) Marshal() (dAtA []byte, err error) {
@@ -346,6 +355,11 @@ func (m *Ack) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x9a
 	}
+	if m.Features != 0 {
+		i = encodeVarintHandshake(dAtA, i, uint64(m.Features))
+		i--
+		dAtA[i] = 0x28
+	}
 	if len(m.Nonce) > 0 {
 		i -= len(m.Nonce)
 		copy(dAtA[i:], m.Nonce)
@@ -518,6 +532,9 @@ func (m *Ack) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovHandshake(uint64(l))
 	}
+	if m.Features != 0 {
+		n += 1 + sovHandshake(uint64(m.Features))
+	}
 	l = len(m.WelcomeMessage)
 	if l > 0 {
 		n += 2 + l + sovHandshake(uint64(l))
@@ -791,6 +808,25 @@ func (m *Ack) Unmarshal(dAtA []byte) error {
 				m.Nonce = []byte{}
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Features", wireType)
+			}
+			m.Features = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandshake
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Features |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		case 99:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field WelcomeMessage", wireType)
@@ -64,8 +64,11 @@ import (
 const loggerName = "libp2p"
 
 var (
-	_ p2p.Service      = (*Service)(nil)
-	_ p2p.DebugService = (*Service)(nil)
+	_ p2p.Service         = (*Service)(nil)
+	_ p2p.DebugService    = (*Service)(nil)
+	_ p2p.NATStatuser     = (*Service)(nil)
+	_ p2p.FeatureQuerier  = (*Service)(nil)
+	_ p2p.TrafficReporter = (*Service)(nil)
 
 	// reachabilityOverridePublic overrides autonat to simply report
 	// public reachability status, it is set in the makefile.
@@ -110,6 +113,10 @@ type Service struct {
 	networkStatus     atomic.Int32
 	HeadersRWTimeout  time.Duration
 	autoNAT           autonat.AutoNAT
+	hooks             hooks
+	reachability      *atomic.Int32
+	relay             bool
+	relayService      bool
 }
 
 type lightnodes interface {
@@ -132,6 +139,17 @@ type Options struct {
 	hostFactory      func(...libp2p.Option) (host.Host, error)
 	HeadersRWTimeout time.Duration
 	Registry         *prometheus.Registry
+	// EnableRelay enables the reservations on the circuit relays and the
+	// hole punching when the node is not directly reachable.
+	EnableRelay bool
+	// EnableRelayService makes the full node relay the connections of the
+	// nodes which are not directly reachable.
+	EnableRelayService bool
+	// StaticRelays are the underlays of the relays used instead of the
+	// connected full nodes.
+	StaticRelays []string
+	// Features are the optional protocol features advertised to the peers.
+	Features p2p.Features
 }
 
 func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay swarm.Address, addr string, ab addressbook.Putter, storer storage.StateStorer, lightNodes *lightnode.Container, logger log.Logger, tracer *tracing.Tracer, o Options) (*Service, error) {
@@ -245,6 +263,24 @@ func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay
 
 	opts = append(opts, transports...)
 
+	peerRegistry := newPeerRegistry()
+
+	if o.EnableRelay {
+		opts = append(opts, libp2p.EnableRelay(), libp2p.EnableHolePunching())
+		if len(o.StaticRelays) > 0 {
+			relays, err := parseStaticRelays(o.StaticRelays)
+			if err != nil {
+				return nil, fmt.Errorf("static relays: %w", err)
+			}
+			opts = append(opts, libp2p.EnableAutoRelayWithStaticRelays(relays))
+		} else {
+			opts = append(opts, libp2p.EnableAutoRelayWithPeerSource(relayCandidates(peerRegistry)))
+		}
+	}
+	if o.EnableRelayService && o.FullNode {
+		opts = append(opts, libp2p.EnableRelayService())
+	}
+
 	if o.hostFactory == nil {
 		// Use the default libp2p host creation
 		o.hostFactory = libp2p.New
@@ -298,10 +334,20 @@ func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay
 		advertisableAddresser = natAddrResolver
 	}
 
+	reachability := atomic.NewInt32(int32(network.ReachabilityUnknown))
+	if o.EnableRelay {
+		advertisableAddresser = &relayAddressResolver{
+			AdvertisableAddressResolver: advertisableAddresser,
+			host:                        h,
+			reachability:                reachability,
+		}
+	}
+
 	handshakeService, err := handshake.New(signer, advertisableAddresser, overlay, networkID, o.FullNode, o.Nonce, o.WelcomeMessage, o.ValidateOverlay, h.ID(), logger)
 	if err != nil {
 		return nil, fmt.Errorf("handshake service: %w", err)
 	}
+	handshakeService.SetFeatures(o.Features)
 
 	// Create a new dialer for libp2p ping protocol. This ensures that the protocol
 	// uses a different set of keys to do ping. It prevents inconsistencies in peerstore as
@@ -313,7 +359,6 @@ func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay
 		return nil, err
 	}
 
-	peerRegistry := newPeerRegistry()
 	s := &Service{
 		ctx:               ctx,
 		host:              h,
@@ -336,6 +381,9 @@ func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay
 		lightNodes:        lightNodes,
 		HeadersRWTimeout:  o.HeadersRWTimeout,
 		autoNAT:           autoNAT,
+		reachability:      reachability,
+		relay:             o.EnableRelay,
+		relayService:      o.EnableRelayService && o.FullNode,
 	}
 
 	peerRegistry.setDisconnecter(s)
@@ -354,6 +402,14 @@ func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay
 
 	s.host.SetStreamHandlerMatch(id, matcher, s.handleIncoming)
 
+	announceID := protocol.ID(p2p.NewSwarmStreamName(handshake.ProtocolName, handshake.ProtocolVersion, handshake.AnnounceStreamName))
+	announceMatcher, err := s.protocolSemverMatcher(announceID)
+	if err != nil {
+		return nil, fmt.Errorf("protocol version match %s: %w", announceID, err)
+	}
+
+	s.host.SetStreamHandlerMatch(announceID, announceMatcher, s.handleAnnounce)
+
 	connMetricNotify := newConnMetricNotify(s.metrics)
 	h.Network().Notify(peerRegistry) // update peer registry on network events
 	h.Network().Notify(connMetricNotify)
@@ -375,6 +431,7 @@ func (s *Service) reachabilityWorker() error {
 				return
 			case e := <-sub.Out():
 				if r, ok := e.(event.EvtLocalReachabilityChanged); ok {
+					s.reachability.Store(int32(r.Reachability))
 					select {
 					case <-s.ready:
 					case <-s.halt:
@@ -431,7 +488,7 @@ func (s *Service) handleIncoming(stream network.Stream) {
 		return
 	}
 
-	if exists := s.peers.addIfNotExists(stream.Conn(), overlay, i.FullNode); exists {
+	if exists := s.peers.addIfNotExists(stream.Conn(), overlay, i.FullNode, i.Features); exists {
 		s.logger.Debug("stream handler: peer already exists", "peer_address", overlay)
 		if err = handshakeStream.FullClose(); err != nil {
 			s.logger.Debug("stream handler: could not close stream", "peer_address", overlay, "error", err)
@@ -535,7 +592,11 @@ func (s *Service) handleIncoming(stream network.Stream) {
 		s.reacher.Connected(overlay, i.BzzAddress.Underlay)
 	}
 
-	peerUserAgent := appendSpace(s.peerUserAgent(s.ctx, peerID))
+	peerUserAgent := s.peerUserAgent(s.ctx, peerID)
+
+	s.hooks.connected(p2p.PeerInfo{Peer: peer, Underlay: i.BzzAddress.Underlay, Inbound: true, UserAgent: peerUserAgent, Features: i.Features})
+
+	peerUserAgent = appendSpace(peerUserAgent)
 
 	loggerV1.Debug("stream handler: successfully connected to peer (inbound)", "addresses", i.BzzAddress.ShortString(), "light", i.LightString(), "user_agent", peerUserAgent)
 	s.logger.Debug("stream handler: successfully connected to peer (inbound)", "address", i.BzzAddress.Overlay, "light", i.LightString(), "user_agent", peerUserAgent)
@@ -573,6 +634,7 @@ func (s *Service) AddProtocol(p p2p.ProtocolSpec) (err error) {
 			}
 
 			stream := newStream(streamlibp2p, s.metrics)
+			stream.traffic = s.peers.trafficCounters(peerID, p.Name)
 
 			// exchange headers
 			ctx, cancel := context.WithTimeout(s.ctx, s.HeadersRWTimeout)
@@ -613,7 +675,7 @@ func (s *Service) AddProtocol(p p2p.ProtocolSpec) (err error) {
 				var bpe *p2p.BlockPeerError
 				if errors.As(err, &bpe) {
 					_ = stream.Reset()
-					if err := s.Blocklist(overlay, bpe.Duration(), bpe.Error()); err != nil {
+					if err := s.Blocklist(overlay, bpe.Duration(), bpe.Offense(), bpe.Error()); err != nil {
 						logger.Debug("blocklist: could not blocklist peer", "peer_id", peerID, "error", err)
 						logger.Error(nil, "unable to blocklist peer", "peer_id", peerID)
 					}
@@ -662,20 +724,23 @@ func (s *Service) NATManager() basichost.NATManager {
 	return s.natManager
 }
 
-func (s *Service) Blocklist(overlay swarm.Address, duration time.Duration, reason string) error {
+func (s *Service) Blocklist(overlay swarm.Address, duration time.Duration, offense p2p.Offense, reason string) error {
 	loggerV1 := s.logger.V(1).Register()
 
 	if s.NetworkStatus() != p2p.NetworkStatusAvailable {
 		return errors.New("blocklisting peer when network not available")
 	}
 
-	loggerV1.Debug("libp2p blocklisting peer", "peer_address", overlay.String(), "duration", duration, "reason", reason)
-	if err := s.blocklist.Add(overlay, duration); err != nil {
+	duration, err := s.blocklist.Add(overlay, duration, offense)
+	if err != nil {
 		s.metrics.BlocklistedPeerErrCount.Inc()
 		_ = s.Disconnect(overlay, "failed blocklisting peer")
 		return fmt.Errorf("blocklist peer %s: %w", overlay, err)
 	}
 	s.metrics.BlocklistedPeerCount.Inc()
+	loggerV1.Debug("libp2p blocklisted peer", "peer_address", overlay.String(), "duration", duration, "offense", offense, "reason", reason)
+
+	s.hooks.blocklisted(overlay, duration, reason)
 
 	_ = s.Disconnect(overlay, reason)
 	return nil
@@ -729,7 +794,10 @@ func (s *Service) Connect(ctx context.Context, addr ma.Multiaddr) (address *bzz.
 		return nil, err
 	}
 
-	stream, err := s.newStreamForPeerID(ctx, info.ID, handshake.ProtocolName, handshake.ProtocolVersion, handshake.StreamName)
+	// the handshake is allowed on the relayed connection,
+	// which is replaced with the direct one by the hole punching
+	streamCtx := network.WithUseTransient(ctx, "handshake")
+	stream, err := s.newStreamForPeerID(streamCtx, info.ID, handshake.ProtocolName, handshake.ProtocolVersion, handshake.StreamName)
 	if err != nil {
 		_ = s.host.Network().ClosePeer(info.ID)
 		return nil, fmt.Errorf("connect new stream: %w", err)
@@ -767,7 +835,7 @@ func (s *Service) Connect(ctx context.Context, addr ma.Multiaddr) (address *bzz.
 		return nil, p2p.ErrPeerBlocklisted
 	}
 
-	if exists := s.peers.addIfNotExists(stream.Conn(), overlay, i.FullNode); exists {
+	if exists := s.peers.addIfNotExists(stream.Conn(), overlay, i.FullNode, i.Features); exists {
 		if err := handshakeStream.FullClose(); err != nil {
 			_ = s.Disconnect(overlay, "failed closing handshake stream after connect")
 			return nil, fmt.Errorf("peer exists, full close: %w", err)
@@ -813,7 +881,16 @@ func (s *Service) Connect(ctx context.Context, addr ma.Multiaddr) (address *bzz.
 		s.reacher.Connected(overlay, i.BzzAddress.Underlay)
 	}
 
-	peerUserAgent := appendSpace(s.peerUserAgent(ctx, info.ID))
+	peerUserAgent := s.peerUserAgent(ctx, info.ID)
+
+	s.hooks.connected(p2p.PeerInfo{
+		Peer:      p2p.Peer{Address: overlay, FullNode: i.FullNode, EthereumAddress: i.BzzAddress.EthereumAddress},
+		Underlay:  i.BzzAddress.Underlay,
+		UserAgent: peerUserAgent,
+		Features:  i.Features,
+	})
+
+	peerUserAgent = appendSpace(peerUserAgent)
 
 	loggerV1.Debug("successfully connected to peer (outbound)", "addresses", i.BzzAddress.ShortString(), "light", i.LightString(), "user_agent", peerUserAgent)
 	s.logger.Debug("successfully connected to peer (outbound)", "address", i.BzzAddress.Overlay, "light", i.LightString(), "user_agent", peerUserAgent)
@@ -857,6 +934,8 @@ func (s *Service) Disconnect(overlay swarm.Address, reason string) (err error) {
 		return p2p.ErrPeerNotFound
 	}
 
+	s.hooks.disconnected(peer, reason)
+
 	return nil
 }
 
@@ -891,12 +970,26 @@ func (s *Service) disconnected(address swarm.Address) {
 	if s.reacher != nil {
 		s.reacher.Disconnected(address)
 	}
+
+	s.hooks.disconnected(peer, p2p.DisconnectedByPeerReason)
 }
 
 func (s *Service) Peers() []p2p.Peer {
 	return s.peers.peers()
 }
 
+// PeerFeatures returns the optional protocol features the
+// connected peer advertised in the handshake.
+func (s *Service) PeerFeatures(overlay swarm.Address) (p2p.Features, bool) {
+	return s.peers.peerFeatures(overlay)
+}
+
+// PeerTraffic returns the traffic of the protocol messages
+// exchanged with the connected peer per protocol name.
+func (s *Service) PeerTraffic(overlay swarm.Address) (map[string]p2p.Traffic, bool) {
+	return s.peers.peerTraffic(overlay)
+}
+
 func (s *Service) Blocklisted(overlay swarm.Address) (bool, error) {
 	return s.blocklist.Exists(overlay)
 }
@@ -923,6 +1016,7 @@ func (s *Service) NewStream(ctx context.Context, overlay swarm.Address, headers
 	}
 
 	stream := newStream(streamlibp2p, s.metrics)
+	stream.traffic = s.peers.trafficCounters(peerID, protocolName)
 
 	// tracing: add span context header
 	if headers == nil {
@@ -1008,6 +1102,9 @@ func (s *Service) Ready() error {
 	if err := s.reachabilityWorker(); err != nil {
 		return fmt.Errorf("reachability worker: %w", err)
 	}
+	if err := s.underlayWorker(); err != nil {
+		return fmt.Errorf("underlay worker: %w", err)
+	}
 
 	close(s.ready)
 	return nil
@@ -27,6 +27,9 @@ type metrics struct {
 	KickedOutPeersCount        prometheus.Counter
 	StreamHandlerErrResetCount prometheus.Counter
 	HeadersExchangeDuration    prometheus.Histogram
+	UnderlayChangeCount        prometheus.Counter
+	AnnouncedUnderlayCount     prometheus.Counter
+	HandledAnnounceCount       prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -117,6 +120,24 @@ func newMetrics() metrics {
 			Name:      "headers_exchange_duration",
 			Help:      "The duration spent exchanging the headers.",
 		}),
+		UnderlayChangeCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "underlay_change_count",
+			Help:      "Number of times the public underlay of the node changed.",
+		}),
+		AnnouncedUnderlayCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "announced_underlay_count",
+			Help:      "Number of peers the changed underlay was announced to.",
+		}),
+		HandledAnnounceCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "handled_announce_count",
+			Help:      "Number of changed underlays announced by the peers.",
+		}),
 	}
 }
 
@@ -21,8 +21,10 @@ type peerRegistry struct {
 	underlays   map[string]libp2ppeer.ID                    // map overlay address to underlay peer id
 	overlays    map[libp2ppeer.ID]swarm.Address             // map underlay peer id to overlay address
 	full        map[libp2ppeer.ID]bool                      // map to track whether a node is full or light node (true=full)
+	features    map[libp2ppeer.ID]p2p.Features              // optional protocol features advertised in the handshake
 	connections map[libp2ppeer.ID]map[network.Conn]struct{} // list of connections for safe removal on Disconnect notification
 	streams     map[libp2ppeer.ID]map[network.Stream]context.CancelFunc
+	traffic     map[libp2ppeer.ID]map[string]*trafficCounters // traffic with the peer per protocol name
 	mu          sync.RWMutex
 
 	//nolint:misspell
@@ -39,8 +41,10 @@ func newPeerRegistry() *peerRegistry {
 		underlays:   make(map[string]libp2ppeer.ID),
 		overlays:    make(map[libp2ppeer.ID]swarm.Address),
 		full:        make(map[libp2ppeer.ID]bool),
+		features:    make(map[libp2ppeer.ID]p2p.Features),
 		connections: make(map[libp2ppeer.ID]map[network.Conn]struct{}),
 		streams:     make(map[libp2ppeer.ID]map[network.Stream]context.CancelFunc),
+		traffic:     make(map[libp2ppeer.ID]map[string]*trafficCounters),
 
 		Notifiee: new(network.NoopNotifiee),
 	}
@@ -81,11 +85,65 @@ func (r *peerRegistry) Disconnected(_ network.Network, c network.Conn) {
 	}
 	delete(r.streams, peerID)
 	delete(r.full, peerID)
+	delete(r.features, peerID)
+	delete(r.traffic, peerID)
 	r.mu.Unlock()
 	r.disconnecter.disconnected(overlay)
 
 }
 
+// Connected tracks the direct connection to the peer which is connected
+// only through the circuit relay, so that the peer is not considered
+// disconnected when the relayed connection is closed after the hole punching.
+// peerRegistry has to be set by network.Network.Notify().
+func (r *peerRegistry) Connected(_ network.Network, c network.Conn) {
+	if c.Stat().Transient {
+		return
+	}
+	peerID := c.RemotePeer()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conns, ok := r.connections[peerID]
+	if !ok {
+		return
+	}
+	for conn := range conns {
+		if !conn.Stat().Transient {
+			return
+		}
+	}
+	conns[c] = struct{}{}
+}
+
+// relayCandidates returns up to num connected full nodes reachable on the direct connections.
+func (r *peerRegistry) relayCandidates(num int) []libp2ppeer.AddrInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var candidates []libp2ppeer.AddrInfo
+	for peerID, conns := range r.connections {
+		if len(candidates) >= num {
+			break
+		}
+		if !r.full[peerID] {
+			continue
+		}
+		for c := range conns {
+			if c.Stat().Transient {
+				continue
+			}
+			candidates = append(candidates, libp2ppeer.AddrInfo{
+				ID:    peerID,
+				Addrs: []ma.Multiaddr{c.RemoteMultiaddr()},
+			})
+			break
+		}
+	}
+	return candidates
+}
+
 func (r *peerRegistry) addStream(peerID libp2ppeer.ID, stream network.Stream, cancel context.CancelFunc) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -131,7 +189,7 @@ func (r *peerRegistry) peers() []p2p.Peer {
 	return peers
 }
 
-func (r *peerRegistry) addIfNotExists(c network.Conn, overlay swarm.Address, full bool) (exists bool) {
+func (r *peerRegistry) addIfNotExists(c network.Conn, overlay swarm.Address, full bool, features p2p.Features) (exists bool) {
 	peerID := c.RemotePeer()
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -151,6 +209,8 @@ func (r *peerRegistry) addIfNotExists(c network.Conn, overlay swarm.Address, ful
 	r.underlays[overlay.ByteString()] = peerID
 	r.overlays[peerID] = overlay
 	r.full[peerID] = full
+	r.features[peerID] = features
+	r.traffic[peerID] = make(map[string]*trafficCounters)
 	return false
 
 }
@@ -176,6 +236,50 @@ func (r *peerRegistry) fullnode(peerID libp2ppeer.ID) (bool, bool) {
 	return full, found
 }
 
+func (r *peerRegistry) peerFeatures(overlay swarm.Address) (p2p.Features, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	peerID, found := r.underlays[overlay.ByteString()]
+	if !found {
+		return 0, false
+	}
+	return r.features[peerID], true
+}
+
+// trafficCounters returns the counters of the traffic with the peer on the
+// protocol, or nil if the peer is not connected.
+func (r *peerRegistry) trafficCounters(peerID libp2ppeer.ID, protocolName string) *trafficCounters {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	protocols, ok := r.traffic[peerID]
+	if !ok {
+		return nil
+	}
+	c, ok := protocols[protocolName]
+	if !ok {
+		c = new(trafficCounters)
+		protocols[protocolName] = c
+	}
+	return c
+}
+
+func (r *peerRegistry) peerTraffic(overlay swarm.Address) (map[string]p2p.Traffic, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	peerID, found := r.underlays[overlay.ByteString()]
+	if !found {
+		return nil, false
+	}
+	traffic := make(map[string]p2p.Traffic, len(r.traffic[peerID]))
+	for protocolName, c := range r.traffic[peerID] {
+		traffic[protocolName] = c.traffic()
+	}
+	return traffic, true
+}
+
 func (r *peerRegistry) isConnected(peerID libp2ppeer.ID, remoteAddr ma.Multiaddr) (swarm.Address, bool) {
 	if remoteAddr == nil {
 		return swarm.ZeroAddress, false
@@ -217,6 +321,8 @@ func (r *peerRegistry) remove(overlay swarm.Address) (found, full bool, peerID l
 	delete(r.streams, peerID)
 	full = r.full[peerID]
 	delete(r.full, peerID)
+	delete(r.features, peerID)
+	delete(r.traffic, peerID)
 	r.mu.Unlock()
 
 	return found, full, peerID
@@ -56,6 +56,74 @@ func TestNewStream(t *testing.T) {
 	}
 }
 
+// TestPeerTraffic tests that the messages exchanged on the streams
+// are attributed to the peer and the protocol.
+func TestPeerTraffic(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s1, overlay1 := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode: true,
+	}})
+
+	s2, overlay2 := newService(t, 1, libp2pServiceOpts{})
+
+	if err := s1.AddProtocol(newTestProtocol(func(_ context.Context, p p2p.Peer, _ p2p.Stream) error {
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := serviceUnderlayAddress(t, s1)
+
+	if _, err := s2.Connect(ctx, addr); err != nil {
+		t.Fatal(err)
+	}
+
+	// the headers are exchanged on the new stream
+	stream, err := s2.NewStream(ctx, overlay1, nil, testProtocolName, testProtocolVersion, testStreamName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	traffic, ok := s2.PeerTraffic(overlay1)
+	if !ok {
+		t.Fatal("peer traffic not found")
+	}
+	out := traffic[testProtocolName]
+	if out.MessagesOut != 1 || out.MessagesIn != 1 || out.BytesOut == 0 || out.BytesIn == 0 {
+		t.Fatalf("got traffic %+v, want one message each way", out)
+	}
+
+	var in p2p.Traffic
+	err = spinlock.Wait(time.Second, func() bool {
+		traffic, ok := s1.PeerTraffic(overlay2)
+		if !ok {
+			return false
+		}
+		in = traffic[testProtocolName]
+		return in.MessagesOut == 1
+	})
+	if err != nil {
+		t.Fatalf("got traffic %+v, want one message each way", in)
+	}
+	if in.BytesIn != out.BytesOut || in.BytesOut != out.BytesIn {
+		t.Fatalf("got traffic %+v, want the reverse of %+v", in, out)
+	}
+
+	if err := s2.Disconnect(overlay1, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s2.PeerTraffic(overlay1); ok {
+		t.Fatal("traffic of the disconnected peer found")
+	}
+}
+
 // TestNewStream_OnlyFull tests that the handler gets the full
 // node information communicated correctly.
 func TestNewStream_OnlyFull(t *testing.T) {
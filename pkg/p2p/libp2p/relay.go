@@ -0,0 +1,95 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package libp2p
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/libp2p/internal/handshake"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	libp2ppeer "github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/host/autorelay"
+	ma "github.com/multiformats/go-multiaddr"
+	"go.uber.org/atomic"
+)
+
+// parseStaticRelays parses the underlays of the relays which are used
+// instead of the connected peers when the node is not directly reachable.
+func parseStaticRelays(addrs []string) ([]libp2ppeer.AddrInfo, error) {
+	relays := make([]libp2ppeer.AddrInfo, 0, len(addrs))
+	for _, a := range addrs {
+		info, err := libp2ppeer.AddrInfoFromString(a)
+		if err != nil {
+			return nil, fmt.Errorf("relay %q: %w", a, err)
+		}
+		relays = append(relays, *info)
+	}
+	return relays, nil
+}
+
+// relayCandidates returns the peer source which offers the connected full
+// nodes as the relays. The peers which do not run the relay service are
+// skipped by the autorelay.
+func relayCandidates(r *peerRegistry) autorelay.PeerSource {
+	return func(ctx context.Context, num int) <-chan libp2ppeer.AddrInfo {
+		candidates := r.relayCandidates(num)
+		c := make(chan libp2ppeer.AddrInfo, len(candidates))
+		for _, info := range candidates {
+			c <- info
+		}
+		close(c)
+		return c
+	}
+}
+
+// isRelayAddress reports whether the address goes through a circuit relay.
+func isRelayAddress(addr ma.Multiaddr) bool {
+	_, err := addr.ValueForProtocol(ma.P_CIRCUIT)
+	return err == nil
+}
+
+// relayAddressResolver advertises the relayed underlay when the node is not
+// directly reachable, as the observed address is then most likely not dialable.
+type relayAddressResolver struct {
+	handshake.AdvertisableAddressResolver
+
+	host         host.Host
+	reachability *atomic.Int32
+}
+
+// Resolve implements the handshake.AdvertisableAddressResolver interface.
+func (r *relayAddressResolver) Resolve(observedAddress ma.Multiaddr) (ma.Multiaddr, error) {
+	if isRelayAddress(observedAddress) || network.Reachability(r.reachability.Load()) != network.ReachabilityPrivate {
+		return r.AdvertisableAddressResolver.Resolve(observedAddress)
+	}
+	for _, a := range r.host.Addrs() {
+		if isRelayAddress(a) {
+			return buildUnderlayAddress(a, r.host.ID())
+		}
+	}
+	return r.AdvertisableAddressResolver.Resolve(observedAddress)
+}
+
+// NATStatus implements the p2p.NATStatuser interface.
+func (s *Service) NATStatus() p2p.NATStatus {
+	status := p2p.NATStatus{
+		Reachability:   p2p.ReachabilityStatus(s.reachability.Load()),
+		Relay:          s.relay,
+		RelayService:   s.relayService,
+		RelayAddresses: make([]ma.Multiaddr, 0),
+	}
+	for _, a := range s.host.Addrs() {
+		if !isRelayAddress(a) {
+			continue
+		}
+		if u, err := buildUnderlayAddress(a, s.host.ID()); err == nil {
+			status.RelayAddresses = append(status.RelayAddresses, u)
+		}
+	}
+	return status
+}
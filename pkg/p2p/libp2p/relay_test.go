@@ -0,0 +1,52 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package libp2p_test
+
+import (
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/libp2p"
+)
+
+func TestNATStatus(t *testing.T) {
+	t.Parallel()
+
+	relay, _ := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		FullNode:           true,
+		EnableRelayService: true,
+	}})
+
+	got := relay.NATStatus()
+	if got.Relay || !got.RelayService {
+		t.Fatalf("got relay %v and relay service %v, want false and true", got.Relay, got.RelayService)
+	}
+
+	addrs, err := relay.Addresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, _ := newService(t, 1, libp2pServiceOpts{libp2pOpts: libp2p.Options{
+		EnableRelay:        true,
+		EnableRelayService: true,
+		StaticRelays:       []string{addrs[0].String()},
+	}})
+
+	got = s.NATStatus()
+	if !got.Relay {
+		t.Fatal("relay not enabled")
+	}
+	// only the full nodes relay the connections
+	if got.RelayService {
+		t.Fatal("relay service enabled on the light node")
+	}
+	if got.Reachability != p2p.ReachabilityStatusUnknown {
+		t.Fatalf("got reachability %v, want %v", got.Reachability, p2p.ReachabilityStatusUnknown)
+	}
+	if len(got.RelayAddresses) != 0 {
+		t.Fatalf("got relay addresses %v before the reachability is known", got.RelayAddresses)
+	}
+}
@@ -7,6 +7,7 @@ package libp2p
 import (
 	"errors"
 	"io"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/p2p"
@@ -17,13 +18,17 @@ var (
 	closeDeadline  = 30 * time.Second
 	errExpectedEof = errors.New("read: expected eof")
 )
-var _ p2p.Stream = (*stream)(nil)
+var (
+	_ p2p.Stream          = (*stream)(nil)
+	_ p2p.TrafficRecorder = (*stream)(nil)
+)
 
 type stream struct {
 	network.Stream
 	headers         map[string][]byte
 	responseHeaders map[string][]byte
 	metrics         metrics
+	traffic         *trafficCounters // nil if the traffic is not attributed
 }
 
 func newStream(s network.Stream, metrics metrics) *stream {
@@ -37,6 +42,20 @@ func (s *stream) ResponseHeaders() p2p.Headers {
 	return s.responseHeaders
 }
 
+// RecordIn implements the p2p.TrafficRecorder interface.
+func (s *stream) RecordIn(size int) {
+	if s.traffic != nil {
+		s.traffic.recordIn(size)
+	}
+}
+
+// RecordOut implements the p2p.TrafficRecorder interface.
+func (s *stream) RecordOut(size int) {
+	if s.traffic != nil {
+		s.traffic.recordOut(size)
+	}
+}
+
 func (s *stream) Reset() error {
 	defer s.metrics.StreamResetCount.Inc()
 	return s.Stream.Reset()
@@ -72,3 +91,30 @@ func (s *stream) FullClose() error {
 	}
 	return nil
 }
+
+// trafficCounters counts the messages exchanged with a peer on a protocol.
+type trafficCounters struct {
+	bytesIn     atomic.Uint64
+	bytesOut    atomic.Uint64
+	messagesIn  atomic.Uint64
+	messagesOut atomic.Uint64
+}
+
+func (c *trafficCounters) recordIn(size int) {
+	c.bytesIn.Add(uint64(size))
+	c.messagesIn.Add(1)
+}
+
+func (c *trafficCounters) recordOut(size int) {
+	c.bytesOut.Add(uint64(size))
+	c.messagesOut.Add(1)
+}
+
+func (c *trafficCounters) traffic() p2p.Traffic {
+	return p2p.Traffic{
+		BytesIn:     c.bytesIn.Load(),
+		BytesOut:    c.bytesOut.Load(),
+		MessagesIn:  c.messagesIn.Load(),
+		MessagesOut: c.messagesOut.Load(),
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/libp2p/internal/handshake"
+	"github.com/libp2p/go-libp2p/core/event"
+	"github.com/libp2p/go-libp2p/core/network"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+// The underlay of the node is advertised to the peers in the handshake, so a
+// change of the public address at runtime, due to the NAT rebinding or the
+// renewed DHCP lease, leaves the peers with the address the node is not
+// reachable on anymore. The node watches the addresses of the host and
+// announces the changed underlay to its connected peers, which update their
+// address books and gossip the new address further with hive.
+
+const (
+	announceTimeout     = 10 * time.Second
+	announceConcurrency = 16
+)
+
+func (s *Service) underlayWorker() error {
+	sub, err := s.host.EventBus().Subscribe(new(event.EvtLocalAddressesUpdated))
+	if err != nil {
+		return fmt.Errorf("failed subscribing to local addresses event %w", err)
+	}
+
+	underlay := s.publicUnderlay(nil)
+
+	go func() {
+		defer sub.Close()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-sub.Out():
+				u := s.publicUnderlay(underlay)
+				if u == nil || (underlay != nil && u.Equal(underlay)) {
+					continue
+				}
+				s.logger.Info("underlay changed", "old_underlay", underlay, "new_underlay", u)
+				s.metrics.UnderlayChangeCount.Inc()
+				underlay = u
+				s.announceUnderlay(s.ctx, u)
+			}
+		}
+	}()
+	return nil
+}
+
+// publicUnderlay returns the public underlay of the node, preferring the
+// current one while it is one of the addresses of the host, or nil if
+// the node has no public address.
+func (s *Service) publicUnderlay(current ma.Multiaddr) ma.Multiaddr {
+	addrs, err := s.Addresses()
+	if err != nil {
+		s.logger.Debug("underlay: get addresses failed", "error", err)
+		return nil
+	}
+	if s.natAddrResolver != nil && len(addrs) > 0 {
+		// the resolved address of the nat is the last one
+		return addrs[len(addrs)-1]
+	}
+
+	var public ma.Multiaddr
+	for _, addr := range addrs {
+		if _, err := addr.ValueForProtocol(ma.P_CIRCUIT); err == nil {
+			continue // the relayed addresses are not the underlay
+		}
+		if !manet.IsPublicAddr(addr) {
+			continue
+		}
+		if current != nil && addr.Equal(current) {
+			return addr
+		}
+		if public == nil {
+			public = addr
+		}
+	}
+	return public
+}
+
+// announceUnderlay announces the underlay to all the connected peers.
+func (s *Service) announceUnderlay(ctx context.Context, underlay ma.Multiaddr) {
+	s.eachPeer(func(peer p2p.Peer) {
+		if err := s.announce(ctx, peer, underlay); err != nil {
+			s.logger.Debug("announce underlay failed", "peer_address", peer.Address, "error", err)
+			return
+		}
+		s.metrics.AnnouncedUnderlayCount.Inc()
+	})
+}
+
+func (s *Service) announce(ctx context.Context, peer p2p.Peer, underlay ma.Multiaddr) error {
+	peerID, found := s.peers.peerID(peer.Address)
+	if !found {
+		return p2p.ErrPeerNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, announceTimeout)
+	defer cancel()
+
+	streamlibp2p, err := s.newStreamForPeerID(ctx, peerID, handshake.ProtocolName, handshake.ProtocolVersion, handshake.AnnounceStreamName)
+	if err != nil {
+		return fmt.Errorf("new stream for peerid: %w", err)
+	}
+	stream := newStream(streamlibp2p, s.metrics)
+	if err := s.handshakeService.Announce(ctx, stream, underlay); err != nil {
+		_ = stream.Reset()
+		return err
+	}
+	return stream.FullClose()
+}
+
+// handleAnnounce updates the address of the connected peer which announced
+// its changed underlay and gossips it to the other connected peers.
+func (s *Service) handleAnnounce(streamlibp2p network.Stream) {
+	stream := newStream(streamlibp2p, s.metrics)
+
+	peerID := streamlibp2p.Conn().RemotePeer()
+	overlay, found := s.peers.overlay(peerID)
+	if !found {
+		_ = stream.Reset()
+		s.logger.Debug("announce handler: overlay address for peer not found", "peer_id", peerID)
+		return
+	}
+	full, found := s.peers.fullnode(peerID)
+	if !found {
+		_ = stream.Reset()
+		s.logger.Debug("announce handler: fullnode info for peer not found", "peer_id", peerID)
+		return
+	}
+
+	addr, err := s.handshakeService.HandleAnnounce(s.ctx, stream, overlay)
+	if err != nil {
+		_ = stream.Reset()
+		s.logger.Debug("announce handler: handle failed", "peer_address", overlay, "error", err)
+		return
+	}
+	if err := stream.FullClose(); err != nil {
+		s.logger.Debug("announce handler: could not close stream", "peer_address", overlay, "error", err)
+		return
+	}
+	s.metrics.HandledAnnounceCount.Inc()
+	s.logger.Debug("announce handler: peer underlay changed", "peer_address", overlay, "underlay", addr.Underlay)
+
+	// only the full nodes are kept in the address book and gossiped
+	if !full {
+		return
+	}
+	if err := s.addressbook.Put(overlay, *addr); err != nil {
+		s.logger.Debug("announce handler: addressbook put error", "peer_address", overlay, "error", err)
+		return
+	}
+	if s.reacher != nil {
+		s.reacher.Connected(overlay, addr.Underlay)
+	}
+	if s.notifier == nil {
+		return
+	}
+	s.eachPeer(func(addressee p2p.Peer) {
+		if addressee.Address.Equal(overlay) {
+			return
+		}
+		if err := s.notifier.AnnounceTo(s.ctx, addressee.Address, overlay, true); err != nil {
+			s.logger.Debug("announce handler: notifier.AnnounceTo failed", "addressee", addressee.Address, "peer_address", overlay, "error", err)
+		}
+	})
+}
+
+// eachPeer calls f for all the connected peers, up to announceConcurrency
+// at once, and waits for all of the calls to return.
+func (s *Service) eachPeer(f func(p2p.Peer)) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, announceConcurrency)
+	)
+	for _, peer := range s.peers.peers() {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(peer p2p.Peer) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			f(peer)
+		}(peer)
+	}
+	wg.Wait()
+}
@@ -26,7 +26,10 @@ type Service struct {
 	notifierFunc          p2p.PickyNotifier
 	setWelcomeMessageFunc func(string) error
 	getWelcomeMessageFunc func() string
-	blocklistFunc         func(swarm.Address, time.Duration, string) error
+	blocklistFunc         func(swarm.Address, time.Duration, p2p.Offense, string) error
+	natStatusFunc         func() p2p.NATStatus
+	peerFeaturesFunc      func(swarm.Address) (p2p.Features, bool)
+	peerTrafficFunc       func(swarm.Address) (map[string]p2p.Traffic, bool)
 	welcomeMessage        string
 }
 
@@ -86,12 +89,33 @@ func WithSetWelcomeMessageFunc(f func(string) error) Option {
 	})
 }
 
-func WithBlocklistFunc(f func(swarm.Address, time.Duration, string) error) Option {
+func WithBlocklistFunc(f func(swarm.Address, time.Duration, p2p.Offense, string) error) Option {
 	return optionFunc(func(s *Service) {
 		s.blocklistFunc = f
 	})
 }
 
+// WithNATStatusFunc sets the mock implementation of the NATStatus function
+func WithNATStatusFunc(f func() p2p.NATStatus) Option {
+	return optionFunc(func(s *Service) {
+		s.natStatusFunc = f
+	})
+}
+
+// WithPeerFeaturesFunc sets the mock implementation of the PeerFeatures function
+func WithPeerFeaturesFunc(f func(swarm.Address) (p2p.Features, bool)) Option {
+	return optionFunc(func(s *Service) {
+		s.peerFeaturesFunc = f
+	})
+}
+
+// WithPeerTrafficFunc sets the mock implementation of the PeerTraffic function
+func WithPeerTrafficFunc(f func(swarm.Address) (map[string]p2p.Traffic, bool)) Option {
+	return optionFunc(func(s *Service) {
+		s.peerTrafficFunc = f
+	})
+}
+
 // New will create a new mock P2P Service with the given options
 func New(opts ...Option) *Service {
 	s := new(Service)
@@ -168,13 +192,34 @@ func (s *Service) GetWelcomeMessage() string {
 	return s.welcomeMessage
 }
 
+func (s *Service) NATStatus() p2p.NATStatus {
+	if s.natStatusFunc == nil {
+		return p2p.NATStatus{RelayAddresses: make([]ma.Multiaddr, 0)}
+	}
+	return s.natStatusFunc()
+}
+
+func (s *Service) PeerFeatures(overlay swarm.Address) (p2p.Features, bool) {
+	if s.peerFeaturesFunc == nil {
+		return 0, false
+	}
+	return s.peerFeaturesFunc(overlay)
+}
+
+func (s *Service) PeerTraffic(overlay swarm.Address) (map[string]p2p.Traffic, bool) {
+	if s.peerTrafficFunc == nil {
+		return nil, false
+	}
+	return s.peerTrafficFunc(overlay)
+}
+
 func (s *Service) Halt() {}
 
-func (s *Service) Blocklist(overlay swarm.Address, duration time.Duration, reason string) error {
+func (s *Service) Blocklist(overlay swarm.Address, duration time.Duration, offense p2p.Offense, reason string) error {
 	if s.blocklistFunc == nil {
 		return errors.New("function blocklist not configured")
 	}
-	return s.blocklistFunc(overlay, duration, reason)
+	return s.blocklistFunc(overlay, duration, offense, reason)
 }
 
 func (s *Service) SetPickyNotifier(f p2p.PickyNotifier) {
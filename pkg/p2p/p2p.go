@@ -86,8 +86,36 @@ type Blocklister interface {
 	NetworkStatuser
 
 	// Blocklist will disconnect a peer and put it on a blocklist (blocking in & out connections) for provided duration
-	// Duration 0 is treated as an infinite duration.
-	Blocklist(overlay swarm.Address, duration time.Duration, reason string) error
+	// Duration 0 is treated as an infinite duration. The duration is the base duration of the offense class,
+	// it is increased exponentially by the repeated offenses of the same class.
+	Blocklist(overlay swarm.Address, duration time.Duration, offense Offense, reason string) error
+}
+
+// Offense is the class of the misbehavior of the peer which is blocklisted.
+// The repeated offenses of the same class increase the blocklist duration
+// exponentially, while the penalty of the peer decays with good behavior.
+type Offense int
+
+const (
+	// OffenseProtocolError is a violation of the protocol or an unresponsive peer.
+	OffenseProtocolError Offense = iota
+	// OffenseAccountingViolation is a violation of the accounting or settlement rules.
+	OffenseAccountingViolation
+	// OffenseInvalidChunk is a delivery of an invalid chunk.
+	OffenseInvalidChunk
+)
+
+func (o Offense) String() string {
+	switch o {
+	case OffenseProtocolError:
+		return "protocol error"
+	case OffenseAccountingViolation:
+		return "accounting violation"
+	case OffenseInvalidChunk:
+		return "invalid chunk"
+	default:
+		return "unknown offense"
+	}
 }
 
 type Halter interface {
@@ -128,6 +156,52 @@ type Notifier interface {
 	AnnounceTo(ctx context.Context, addressee, peer swarm.Address, fullnode bool) error
 }
 
+// PeerInfo describes a connected peer together
+// with the capabilities learned in the handshake.
+type PeerInfo struct {
+	Peer
+	Underlay  ma.Multiaddr
+	Inbound   bool     // whether the connection was initiated by the peer
+	UserAgent string   // libp2p user agent of the peer, may be empty
+	Features  Features // optional protocol features advertised by the peer
+}
+
+// DisconnectedByPeerReason is reported to the OnDisconnect
+// hooks when the connection was closed by the remote peer.
+const DisconnectedByPeerReason = "disconnected by peer"
+
+// Hooks are the callbacks invoked on the peer connection events. They let
+// the embedders of the bee packages follow the connections, for example to
+// drive their own topology or monitoring, without implementing the whole
+// PickyNotifier. Nil callbacks are skipped. The callbacks are called
+// synchronously from the connection handling and must not block.
+type Hooks struct {
+	OnConnect    func(PeerInfo)
+	OnDisconnect func(peer Peer, reason string)
+	OnBlocklist  func(overlay swarm.Address, duration time.Duration, reason string)
+}
+
+// Hooker registers the Hooks.
+type Hooker interface {
+	// AddHooks registers the hooks until the returned function is called.
+	AddHooks(Hooks) (remove func())
+}
+
+// NATStatus describes how the node traverses the NATs: whether it reserved
+// slots on the circuit relays, because it is not directly reachable, and
+// whether it relays the connections of the other nodes.
+type NATStatus struct {
+	Reachability   ReachabilityStatus
+	Relay          bool           // whether the relayed connections and hole punching are enabled
+	RelayService   bool           // whether the node relays the connections of the other nodes
+	RelayAddresses []ma.Multiaddr // the relayed underlays the node is reachable on
+}
+
+// NATStatuser reports the NAT traversal status.
+type NATStatuser interface {
+	NATStatus() NATStatus
+}
+
 // DebugService extends the Service with method used for debugging.
 type DebugService interface {
 	Service
@@ -192,6 +266,59 @@ type Peer struct {
 	EthereumAddress []byte
 }
 
+// Features is the bitmap of the optional protocol features the peer
+// advertised in the handshake. The protocols use a new feature only with
+// the peers which advertise it, so the features can be rolled out
+// incrementally without bumping the protocol versions.
+type Features uint64
+
+const (
+	// FeatureCompression marks the support of the compressed streams.
+	FeatureCompression Features = 1 << iota
+	// FeatureMultiReceiptPushsync marks the support of the pushsync
+	// returning the receipts of multiple storers.
+	FeatureMultiReceiptPushsync
+	// FeatureBatchedPushsync marks the support of the pushsync
+	// deliveries batched in a stream with the batched receipts.
+	FeatureBatchedPushsync
+)
+
+// Has reports whether all of the given features are set.
+func (f Features) Has(features Features) bool {
+	return f&features == features
+}
+
+// FeatureQuerier returns the features advertised by the connected peers.
+type FeatureQuerier interface {
+	// PeerFeatures returns the features of the peer and
+	// false if the peer is not connected.
+	PeerFeatures(overlay swarm.Address) (Features, bool)
+}
+
+// Traffic is the traffic of the protocol messages exchanged with a peer.
+type Traffic struct {
+	BytesIn     uint64
+	BytesOut    uint64
+	MessagesIn  uint64
+	MessagesOut uint64
+}
+
+// TrafficRecorder is implemented by the streams which attribute the
+// messages read and written on them to their peer and protocol.
+type TrafficRecorder interface {
+	// RecordIn records the message of size bytes read from the stream.
+	RecordIn(size int)
+	// RecordOut records the message of size bytes written to the stream.
+	RecordOut(size int)
+}
+
+// TrafficReporter reports the traffic with the connected peers.
+type TrafficReporter interface {
+	// PeerTraffic returns the traffic with the peer per protocol
+	// name and false if the peer is not connected.
+	PeerTraffic(overlay swarm.Address) (map[string]Traffic, bool)
+}
+
 // HandlerFunc handles a received Stream from a Peer.
 type HandlerFunc func(context.Context, Peer, Stream) error
 
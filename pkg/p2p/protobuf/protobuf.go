@@ -24,12 +24,24 @@ func NewWriterAndReader(s p2p.Stream) (Writer, Reader) {
 	return NewWriter(s), NewReader(s)
 }
 
+// NewReader returns the Reader of the delimited messages. The messages read
+// are recorded if r is the p2p.TrafficRecorder.
 func NewReader(r io.Reader) Reader {
-	return newReader(ggio.NewDelimitedReader(r, delimitedReaderMaxSize))
+	var reader ggio.Reader = ggio.NewDelimitedReader(r, delimitedReaderMaxSize)
+	if recorder, ok := r.(p2p.TrafficRecorder); ok {
+		reader = recordingReader{Reader: reader, recorder: recorder}
+	}
+	return newReader(reader)
 }
 
+// NewWriter returns the Writer of the delimited messages. The messages
+// written are recorded if w is the p2p.TrafficRecorder.
 func NewWriter(w io.Writer) Writer {
-	return newWriter(ggio.NewDelimitedWriter(w))
+	var writer ggio.Writer = ggio.NewDelimitedWriter(w)
+	if recorder, ok := w.(p2p.TrafficRecorder); ok {
+		writer = recordingWriter{Writer: writer, recorder: recorder}
+	}
+	return newWriter(writer)
 }
 
 func ReadMessages(r io.Reader, newMessage func() Message) (m []Message, err error) {
@@ -90,3 +102,38 @@ func (w Writer) WriteMsgWithContext(ctx context.Context, msg proto.Message) erro
 		return ctx.Err()
 	}
 }
+
+// recordingReader records the size of the messages read.
+type recordingReader struct {
+	ggio.Reader
+	recorder p2p.TrafficRecorder
+}
+
+func (r recordingReader) ReadMsg(msg proto.Message) error {
+	if err := r.Reader.ReadMsg(msg); err != nil {
+		return err
+	}
+	r.recorder.RecordIn(delimitedSize(msg))
+	return nil
+}
+
+// recordingWriter records the size of the messages written.
+type recordingWriter struct {
+	ggio.Writer
+	recorder p2p.TrafficRecorder
+}
+
+func (w recordingWriter) WriteMsg(msg proto.Message) error {
+	if err := w.Writer.WriteMsg(msg); err != nil {
+		return err
+	}
+	w.recorder.RecordOut(delimitedSize(msg))
+	return nil
+}
+
+// delimitedSize returns the size of the message on the
+// wire, including its varint length prefix.
+func delimitedSize(msg proto.Message) int {
+	size := proto.Size(msg)
+	return proto.SizeVarint(uint64(size)) + size
+}
@@ -5,6 +5,7 @@
 package protobuf_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -268,6 +269,55 @@ func TestReadMessages(t *testing.T) {
 	}
 }
 
+func TestTrafficRecorder(t *testing.T) {
+	t.Parallel()
+
+	messages := []string{"first", "second", "third"}
+	rw := new(recordingReadWriter)
+
+	w := protobuf.NewWriter(rw)
+	for _, m := range messages {
+		if err := w.WriteMsg(&pb.Message{Text: m}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(rw.out) != len(messages) {
+		t.Fatalf("got %d messages written, want %d", len(rw.out), len(messages))
+	}
+	written := 0
+	for _, size := range rw.out {
+		written += size
+	}
+	if written != rw.Len() {
+		t.Fatalf("got %d bytes written, want %d", written, rw.Len())
+	}
+
+	r := protobuf.NewReader(rw)
+	for range messages {
+		var msg pb.Message
+		if err := r.ReadMsg(&msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fmt.Sprint(rw.in) != fmt.Sprint(rw.out) {
+		t.Fatalf("got message sizes read %v, want %v", rw.in, rw.out)
+	}
+}
+
+// recordingReadWriter records the sizes of the messages read and written.
+type recordingReadWriter struct {
+	bytes.Buffer
+	in, out []int
+}
+
+func (rw *recordingReadWriter) RecordIn(size int) {
+	rw.in = append(rw.in, size)
+}
+
+func (rw *recordingReadWriter) RecordOut(size int) {
+	rw.out = append(rw.out, size)
+}
+
 func newMessageReader(messages []string, delay time.Duration) io.Reader {
 	r, pipe := io.Pipe()
 	w := protobuf.NewWriter(pipe)
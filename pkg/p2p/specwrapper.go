@@ -25,7 +25,7 @@ func WithDisconnectStreams(spec ProtocolSpec) {
 func WithBlocklistStreams(dur time.Duration, spec ProtocolSpec) {
 	for i := range spec.StreamSpecs {
 		spec.StreamSpecs[i].Handler = func(c context.Context, p Peer, s Stream) error {
-			return NewBlockPeerError(dur, ErrUnexpected)
+			return NewBlockPeerError(dur, OffenseProtocolError, ErrUnexpected)
 		}
 	}
 }
@@ -419,7 +419,7 @@ func (r *RecorderDisconnecter) Disconnect(overlay swarm.Address, _ string) error
 	return nil
 }
 
-func (r *RecorderDisconnecter) Blocklist(overlay swarm.Address, d time.Duration, _ string) error {
+func (r *RecorderDisconnecter) Blocklist(overlay swarm.Address, d time.Duration, _ p2p.Offense, _ string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
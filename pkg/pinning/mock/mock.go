@@ -15,7 +15,7 @@ var _ pinning.Interface = (*ServiceMock)(nil)
 
 // NewServiceMock is a convenient constructor for creating ServiceMock.
 func NewServiceMock() *ServiceMock {
-	return &ServiceMock{index: make(map[string]int)}
+	return &ServiceMock{index: make(map[string]int), pathIndex: make(map[string]int)}
 }
 
 // ServiceMock represents a simple mock of pinning.Interface.
@@ -23,6 +23,8 @@ func NewServiceMock() *ServiceMock {
 type ServiceMock struct {
 	index      map[string]int
 	references []swarm.Address
+	pathIndex  map[string]int
+	pathPins   []pinning.PathPin
 }
 
 // CreatePin implements pinning.Interface CreatePin method.
@@ -56,3 +58,46 @@ func (sm *ServiceMock) HasPin(ref swarm.Address) (bool, error) {
 func (sm *ServiceMock) Pins() ([]swarm.Address, error) {
 	return append([]swarm.Address(nil), sm.references...), nil
 }
+
+func pathPinKey(ref swarm.Address, path string) string {
+	return ref.String() + "-" + path
+}
+
+// CreatePathPin implements pinning.Interface CreatePathPin method.
+func (sm *ServiceMock) CreatePathPin(_ context.Context, ref swarm.Address, path string) error {
+	key := pathPinKey(ref, path)
+	if _, ok := sm.pathIndex[key]; ok {
+		return nil
+	}
+	sm.pathIndex[key] = len(sm.pathPins)
+	sm.pathPins = append(sm.pathPins, pinning.PathPin{Reference: ref, Path: path})
+	return nil
+}
+
+// DeletePathPin implements pinning.Interface DeletePathPin method.
+func (sm *ServiceMock) DeletePathPin(_ context.Context, ref swarm.Address, path string) error {
+	key := pathPinKey(ref, path)
+	i, ok := sm.pathIndex[key]
+	if !ok {
+		return nil
+	}
+	delete(sm.pathIndex, key)
+	sm.pathPins = append(sm.pathPins[:i], sm.pathPins[i+1:]...)
+	for k, j := range sm.pathIndex {
+		if j > i {
+			sm.pathIndex[k] = j - 1
+		}
+	}
+	return nil
+}
+
+// HasPathPin implements pinning.Interface HasPathPin method.
+func (sm *ServiceMock) HasPathPin(ref swarm.Address, path string) (bool, error) {
+	_, ok := sm.pathIndex[pathPinKey(ref, path)]
+	return ok, nil
+}
+
+// PathPins implements pinning.Interface PathPins method.
+func (sm *ServiceMock) PathPins() ([]pinning.PathPin, error) {
+	return append([]pinning.PathPin(nil), sm.pathPins...), nil
+}
@@ -35,14 +35,40 @@ type Interface interface {
 	HasPin(swarm.Address) (bool, error)
 	// Pins return all pinned references.
 	Pins() ([]swarm.Address, error)
+	// CreatePathPin creates a new pin for the entries of the manifest
+	// with the given reference under the path prefix. Only the nodes
+	// of the entries and the manifest nodes leading to them are
+	// traversed and pinned. Repeating calls of this method are idempotent.
+	CreatePathPin(context.Context, swarm.Address, string) error
+	// DeletePathPin deletes the pin of the manifest path prefix.
+	// The nodes pinned by the path pin will be un-pinned.
+	// Repeating calls of this method are idempotent.
+	DeletePathPin(context.Context, swarm.Address, string) error
+	// HasPathPin returns true if the given manifest path prefix is pinned.
+	HasPathPin(swarm.Address, string) (bool, error)
+	// PathPins return all pinned manifest path prefixes.
+	PathPins() ([]PathPin, error)
 }
 
-const storePrefix = "root-pin"
+// PathPin is the pin of the entries of a manifest under a path prefix.
+type PathPin struct {
+	Reference swarm.Address `json:"reference"`
+	Path      string        `json:"path"`
+}
+
+const (
+	storePrefix     = "root-pin"
+	pathStorePrefix = "path-pin"
+)
 
 func rootPinKey(ref swarm.Address) string {
 	return fmt.Sprintf("%s-%s", storePrefix, ref)
 }
 
+func pathPinKey(ref swarm.Address, path string) string {
+	return fmt.Sprintf("%s-%s-%s", pathStorePrefix, ref, path)
+}
+
 // NewService is a convenient constructor for Service.
 func NewService(
 	pinStorage storage.Storer,
@@ -63,17 +89,33 @@ type Service struct {
 	traverser  traversal.Traverser
 }
 
-// CreatePin implements Interface.CreatePin method.
-func (s *Service) CreatePin(ctx context.Context, ref swarm.Address, traverse bool) error {
-	// iterFn is a pinning iterator function over the leaves of the root.
-	iterFn := func(leaf swarm.Address) error {
-		switch err := s.pinStorage.Set(ctx, storage.ModeSetPin, leaf); {
+// leafAddress returns the address of the chunk of the leaf.
+func leafAddress(leaf swarm.Address) swarm.Address {
+	if len(leaf.Bytes()) == encryption.ReferenceSize {
+		// the traversal service might report back encrypted reference.
+		// this is not so trivial to mitigate inside the traversal service
+		// since it might introduce complexity with determining which entries
+		// should be treated with which address length, since the decryption keys
+		// on encrypted references are still needed for correct traversal.
+		// we therefore just make sure that localstore gets the correct reference size
+		// for pinning and un-pinning.
+		return swarm.NewAddress(leaf.Bytes()[:swarm.HashSize])
+	}
+	return leaf
+}
+
+// PinFn returns the pinning iterator function over the leaves of the root,
+// the leaves missing in the storer are retrieved and stored as pinned.
+func PinFn(ctx context.Context, storer storage.Storer, ref swarm.Address) swarm.AddressIterFunc {
+	return func(leaf swarm.Address) error {
+		leaf = leafAddress(leaf)
+		switch err := storer.Set(ctx, storage.ModeSetPin, leaf); {
 		case errors.Is(err, storage.ErrNotFound):
-			ch, err := s.pinStorage.Get(ctx, storage.ModeGetRequestPin, leaf)
+			ch, err := storer.Get(ctx, storage.ModeGetRequestPin, leaf)
 			if err != nil {
 				return fmt.Errorf("unable to get pin for leaf %q of root %q: %w", leaf, ref, err)
 			}
-			_, err = s.pinStorage.Put(ctx, storage.ModePutRequestPin, ch)
+			_, err = storer.Put(ctx, storage.ModePutRequestPin, ch)
 			if err != nil {
 				return fmt.Errorf("unable to put pin for leaf %q of root %q: %w", leaf, ref, err)
 			}
@@ -82,6 +124,26 @@ func (s *Service) CreatePin(ctx context.Context, ref swarm.Address, traverse boo
 		}
 		return nil
 	}
+}
+
+// UnpinFn returns the un-pinning iterator function over the leaves of the
+// root, the errors of which are collected in iterErr.
+func UnpinFn(ctx context.Context, storer storage.Storer, ref swarm.Address, iterErr *error) swarm.AddressIterFunc {
+	return func(leaf swarm.Address) error {
+		leaf = leafAddress(leaf)
+		err := storer.Set(ctx, storage.ModeSetUnpin, leaf)
+		if err != nil {
+			*iterErr = multierror.Append(err, fmt.Errorf("unable to unpin the chunk for leaf %q of root %q: %w", leaf, ref, err))
+			// Continue un-pinning all chunks.
+		}
+		return nil
+	}
+}
+
+// CreatePin implements Interface.CreatePin method.
+func (s *Service) CreatePin(ctx context.Context, ref swarm.Address, traverse bool) error {
+	// iterFn is a pinning iterator function over the leaves of the root.
+	iterFn := PinFn(ctx, s.pinStorage, ref)
 
 	if traverse {
 		if err := s.traverser.Traverse(ctx, ref, iterFn); err != nil {
@@ -103,24 +165,7 @@ func (s *Service) CreatePin(ctx context.Context, ref swarm.Address, traverse boo
 func (s *Service) DeletePin(ctx context.Context, ref swarm.Address) error {
 	var iterErr error
 	// iterFn is a unpinning iterator function over the leaves of the root.
-	iterFn := func(leaf swarm.Address) error {
-		if len(leaf.Bytes()) == encryption.ReferenceSize {
-			// the traversal service might report back encrypted reference.
-			// this is not so trivial to mitigate inside the traversal service
-			// since it might introduce complexity with determining which entries
-			// should be treated with which address length, since the decryption keys
-			// on encrypted references are still needed for correct traversal.
-			// we therefore just make sure that localstore gets the correct reference size
-			// for unpinning.
-			leaf = swarm.NewAddress(leaf.Bytes()[:swarm.HashSize])
-		}
-		err := s.pinStorage.Set(ctx, storage.ModeSetUnpin, leaf)
-		if err != nil {
-			iterErr = multierror.Append(err, fmt.Errorf("unable to unpin the chunk for leaf %q of root %q: %w", leaf, ref, err))
-			// Continue un-pinning all chunks.
-		}
-		return nil
-	}
+	iterFn := UnpinFn(ctx, s.pinStorage, ref, &iterErr)
 
 	if err := s.traverser.Traverse(ctx, ref, iterFn); err != nil {
 		return fmt.Errorf("traversal of %q failed: %w", ref, multierror.Append(err, iterErr))
@@ -164,3 +209,72 @@ func (s *Service) Pins() ([]swarm.Address, error) {
 	}
 	return refs, nil
 }
+
+// CreatePathPin implements Interface.CreatePathPin method.
+func (s *Service) CreatePathPin(ctx context.Context, ref swarm.Address, path string) error {
+	key := pathPinKey(ref, path)
+	switch err := s.rhStorage.Get(key, new(PathPin)); {
+	case err == nil:
+		return nil
+	case !errors.Is(err, storage.ErrNotFound):
+		return fmt.Errorf("unable to pin %q path %q: %w", ref, path, err)
+	}
+
+	if err := s.traverser.TraversePrefix(ctx, ref, path, PinFn(ctx, s.pinStorage, ref)); err != nil {
+		return fmt.Errorf("traversal of %q path %q failed: %w", ref, path, err)
+	}
+	return s.rhStorage.Put(key, PathPin{Reference: ref, Path: path})
+}
+
+// DeletePathPin implements Interface.DeletePathPin method.
+func (s *Service) DeletePathPin(ctx context.Context, ref swarm.Address, path string) error {
+	key := pathPinKey(ref, path)
+	switch err := s.rhStorage.Get(key, new(PathPin)); {
+	case errors.Is(err, storage.ErrNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("unable to get pin for key %q: %w", key, err)
+	}
+
+	var iterErr error
+	if err := s.traverser.TraversePrefix(ctx, ref, path, UnpinFn(ctx, s.pinStorage, ref, &iterErr)); err != nil {
+		return fmt.Errorf("traversal of %q path %q failed: %w", ref, path, multierror.Append(err, iterErr))
+	}
+	if iterErr != nil {
+		return multierror.Append(ErrTraversal, iterErr)
+	}
+
+	if err := s.rhStorage.Delete(key); err != nil {
+		return fmt.Errorf("unable to delete pin for key %q: %w", key, err)
+	}
+	return nil
+}
+
+// HasPathPin implements Interface.HasPathPin method.
+func (s *Service) HasPathPin(ref swarm.Address, path string) (bool, error) {
+	key, val := pathPinKey(ref, path), PathPin{}
+	switch err := s.rhStorage.Get(key, &val); {
+	case errors.Is(err, storage.ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("unable to get pin for key %q: %w", key, err)
+	}
+	return val.Reference.Equal(ref) && val.Path == path, nil
+}
+
+// PathPins implements Interface.PathPins method.
+func (s *Service) PathPins() ([]PathPin, error) {
+	var pins = make([]PathPin, 0)
+	err := s.rhStorage.Iterate(pathStorePrefix, func(key, val []byte) (stop bool, err error) {
+		var pin PathPin
+		if err := json.Unmarshal(val, &pin); err != nil {
+			return true, fmt.Errorf("invalid path pin value %q: %w", string(val), err)
+		}
+		pins = append(pins, pin)
+		return false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("iteration failed: %w", err)
+	}
+	return pins, nil
+}
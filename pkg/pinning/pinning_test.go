@@ -6,14 +6,20 @@ package pinning_test
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
 	"github.com/ethersphere/bee/pkg/pinning"
 	statestorem "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage"
 	storagem "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/traversal"
 )
 
@@ -97,3 +103,118 @@ func TestPinningService(t *testing.T) {
 		}
 	})
 }
+
+// pinRecorder records the pin counters of the chunks.
+type pinRecorder struct {
+	*storagem.MockStorer
+	pins map[string]int
+}
+
+func (r *pinRecorder) Set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Address) error {
+	if err := r.MockStorer.Set(ctx, mode, addrs...); err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		switch mode {
+		case storage.ModeSetPin:
+			r.pins[addr.String()]++
+		case storage.ModeSetUnpin:
+			if r.pins[addr.String()]--; r.pins[addr.String()] == 0 {
+				delete(r.pins, addr.String())
+			}
+		}
+	}
+	return nil
+}
+
+// nolint:paralleltest
+func TestPinningServicePath(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		storerMock = &pinRecorder{MockStorer: storagem.NewStorer(), pins: make(map[string]int)}
+		service    = pinning.NewService(
+			storerMock,
+			statestorem.NewStateStore(),
+			traversal.New(storerMock),
+		)
+	)
+
+	pipelineFn := func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, storerMock, storage.ModePutUpload, false)
+	}
+	m, err := manifest.NewDefaultManifest(loadsave.New(storerMock, pipelineFn), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := make(map[string]swarm.Address)
+	for _, p := range []string{"index.html", "images/a.png", "images/b.png"} {
+		ref, err := builder.FeedPipeline(ctx, pipelineFn(), strings.NewReader(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Add(ctx, p, manifest.NewEntry(ref, nil)); err != nil {
+			t.Fatal(err)
+		}
+		refs[p] = ref
+	}
+	root, err := m.Store(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("create and list", func(t *testing.T) {
+		if err := service.CreatePathPin(ctx, root, "images/"); err != nil {
+			t.Fatalf("CreatePathPin(...): unexpected error: %v", err)
+		}
+		for p, ref := range refs {
+			if have, want := storerMock.pins[ref.String()] == 1, strings.HasPrefix(p, "images/"); have != want {
+				t.Fatalf("%s pinned: have %t; want %t", p, have, want)
+			}
+		}
+		if storerMock.pins[root.String()] != 1 {
+			t.Fatal("root manifest chunk is not pinned")
+		}
+
+		pins, err := service.PathPins()
+		if err != nil {
+			t.Fatalf("PathPins(...): unexpected error: %v", err)
+		}
+		if have, want := pins, []pinning.PathPin{{Reference: root, Path: "images/"}}; !reflect.DeepEqual(have, want) {
+			t.Fatalf("PathPins(...): have %v; want %v", have, want)
+		}
+		if has, err := service.HasPin(root); err != nil || has {
+			t.Fatalf("HasPin(...): have %t, %v; want false", has, err)
+		}
+	})
+
+	t.Run("create idempotent", func(t *testing.T) {
+		if err := service.CreatePathPin(ctx, root, "images/"); err != nil {
+			t.Fatalf("CreatePathPin(...): unexpected error: %v", err)
+		}
+		if have, want := storerMock.pins[refs["images/a.png"].String()], 1; have != want {
+			t.Fatalf("pin counter: have %d; want %d", have, want)
+		}
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		if err := service.CreatePathPin(ctx, root, "docs/"); !errors.Is(err, traversal.ErrPrefixNotFound) {
+			t.Fatalf("CreatePathPin(...): have error %v; want %v", err, traversal.ErrPrefixNotFound)
+		}
+	})
+
+	t.Run("delete and has", func(t *testing.T) {
+		if err := service.DeletePathPin(ctx, root, "images/"); err != nil {
+			t.Fatalf("DeletePathPin(...): unexpected error: %v", err)
+		}
+		has, err := service.HasPathPin(root, "images/")
+		if err != nil {
+			t.Fatalf("HasPathPin(...): unexpected error: %v", err)
+		}
+		if has {
+			t.Fatalf("HasPathPin(...): have %t; want %t", has, !has)
+		}
+		if len(storerMock.pins) != 0 {
+			t.Fatalf("pinned chunks left: %v", storerMock.pins)
+		}
+	})
+}
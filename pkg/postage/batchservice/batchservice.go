@@ -37,6 +37,7 @@ type batchService struct {
 	listener      postage.Listener
 	owner         []byte
 	batchListener postage.BatchEventListener
+	publisher     postage.BatchEventPublisher
 
 	checksum hash.Hash // checksum hasher
 	resync   bool
@@ -54,6 +55,7 @@ func New(
 	listener postage.Listener,
 	owner []byte,
 	batchListener postage.BatchEventListener,
+	publisher postage.BatchEventPublisher,
 	checksumFunc func() hash.Hash,
 	resync bool,
 ) (Interface, error) {
@@ -95,7 +97,7 @@ func New(
 		}
 	}
 
-	return &batchService{stateStore, storer, logger.WithName(loggerName).Register(), listener, owner, batchListener, sum, resync}, nil
+	return &batchService{stateStore, storer, logger.WithName(loggerName).Register(), listener, owner, batchListener, publisher, sum, resync}, nil
 }
 
 // Create will create a new batch with the given ID, owner value and depth and
@@ -136,6 +138,17 @@ func (svc *batchService) Create(id, owner []byte, totalAmout, normalisedBalance
 		return fmt.Errorf("update checksum: %w", err)
 	}
 
+	svc.publish(postage.BatchEvent{
+		Type:        postage.BatchCreated,
+		BatchID:     batch.ID,
+		Owner:       batch.Owner,
+		Value:       batch.Value,
+		Depth:       batch.Depth,
+		BucketDepth: batch.BucketDepth,
+		Immutable:   batch.Immutable,
+		TxHash:      txHash,
+	})
+
 	svc.logger.Debug("batch created", "batch_id", hex.EncodeToString(batch.ID), "tx", txHash, "tx_checksum", cs)
 	return nil
 }
@@ -164,6 +177,14 @@ func (svc *batchService) TopUp(id []byte, totalAmout, normalisedBalance *big.Int
 		return fmt.Errorf("update checksum: %w", err)
 	}
 
+	svc.publish(postage.BatchEvent{
+		Type:    postage.BatchToppedUp,
+		BatchID: b.ID,
+		Value:   normalisedBalance,
+		Depth:   b.Depth,
+		TxHash:  txHash,
+	})
+
 	svc.logger.Debug("topped up batch", "batch_id", hex.EncodeToString(b.ID), "old_value", b.Value, "new_value", normalisedBalance, "tx", txHash, "tx_checksum", cs)
 	return nil
 }
@@ -189,6 +210,14 @@ func (svc *batchService) UpdateDepth(id []byte, depth uint8, normalisedBalance *
 		return fmt.Errorf("update checksum: %w", err)
 	}
 
+	svc.publish(postage.BatchEvent{
+		Type:    postage.BatchDepthIncreased,
+		BatchID: b.ID,
+		Value:   normalisedBalance,
+		Depth:   depth,
+		TxHash:  txHash,
+	})
+
 	svc.logger.Debug("updated depth of batch", "batch_id", hex.EncodeToString(b.ID), "old_depth", b.Depth, "new_depth", depth, "tx", txHash, "tx_checksum", cs)
 	return nil
 }
@@ -207,6 +236,12 @@ func (svc *batchService) UpdatePrice(price *big.Int, txHash common.Hash) error {
 		return fmt.Errorf("update checksum: %w", err)
 	}
 
+	svc.publish(postage.BatchEvent{
+		Type:   postage.PriceUpdated,
+		Price:  price,
+		TxHash: txHash,
+	})
+
 	svc.logger.Debug("updated chain price", "new_price", price, "tx_hash", txHash, "tx_checksum", sum)
 	return nil
 }
@@ -230,6 +265,14 @@ func (svc *batchService) UpdateBlockNumber(blockNumber uint64) error {
 	svc.logger.Debug("block height updated", "new_block", blockNumber)
 	return nil
 }
+
+// publish publishes the event if the publisher is set.
+func (svc *batchService) publish(e postage.BatchEvent) {
+	if svc.publisher != nil {
+		svc.publisher.Publish(e)
+	}
+}
+
 func (svc *batchService) TransactionStart() error {
 	return svc.stateStore.Put(dirtyDBKey, true)
 }
@@ -512,7 +512,7 @@ func TestTransactionOk(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	svc2, err := batchservice.New(s, store, testLog, newMockListener(), nil, nil, nil, false)
+	svc2, err := batchservice.New(s, store, testLog, newMockListener(), nil, nil, nil, nil, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -535,7 +535,7 @@ func TestTransactionError(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	svc2, err := batchservice.New(s, store, testLog, newMockListener(), nil, nil, nil, false)
+	svc2, err := batchservice.New(s, store, testLog, newMockListener(), nil, nil, nil, nil, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -552,7 +552,7 @@ func TestChecksum(t *testing.T) {
 	s := mocks.NewStateStore()
 	store := mock.New()
 	mockHash := &hs{}
-	svc, err := batchservice.New(s, store, testLog, newMockListener(), nil, nil, func() hash.Hash { return mockHash }, false)
+	svc, err := batchservice.New(s, store, testLog, newMockListener(), nil, nil, nil, func() hash.Hash { return mockHash }, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -573,7 +573,7 @@ func TestChecksumResync(t *testing.T) {
 	s := mocks.NewStateStore()
 	store := mock.New()
 	mockHash := &hs{}
-	svc, err := batchservice.New(s, store, testLog, newMockListener(), nil, nil, func() hash.Hash { return mockHash }, true)
+	svc, err := batchservice.New(s, store, testLog, newMockListener(), nil, nil, nil, func() hash.Hash { return mockHash }, true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -592,7 +592,7 @@ func TestChecksumResync(t *testing.T) {
 	// now start a new instance and check that the value gets read from statestore
 	store2 := mock.New()
 	mockHash2 := &hs{}
-	_, err = batchservice.New(s, store2, testLog, newMockListener(), nil, nil, func() hash.Hash { return mockHash2 }, false)
+	_, err = batchservice.New(s, store2, testLog, newMockListener(), nil, nil, nil, func() hash.Hash { return mockHash2 }, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -604,7 +604,7 @@ func TestChecksumResync(t *testing.T) {
 	// when resyncing
 	store3 := mock.New()
 	mockHash3 := &hs{}
-	_, err = batchservice.New(s, store3, testLog, newMockListener(), nil, nil, func() hash.Hash { return mockHash3 }, true)
+	_, err = batchservice.New(s, store3, testLog, newMockListener(), nil, nil, nil, func() hash.Hash { return mockHash3 }, true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -622,7 +622,7 @@ func newTestStoreAndServiceWithListener(
 	t.Helper()
 	s := mocks.NewStateStore()
 	store := mock.New(opts...)
-	svc, err := batchservice.New(s, store, testLog, newMockListener(), owner, batchListener, nil, false)
+	svc, err := batchservice.New(s, store, testLog, newMockListener(), owner, batchListener, nil, nil, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -658,3 +658,55 @@ func (h *hs) Sum(b []byte) []byte               { return []byte{h.ctr} }
 func (h *hs) Reset()                            {}
 func (h *hs) Size() int                         { panic("not implemented") }
 func (h *hs) BlockSize() int                    { panic("not implemented") }
+
+func TestBatchServicePublish(t *testing.T) {
+	t.Parallel()
+
+	feed := postage.NewBatchEventFeed()
+	events, unsubscribe := feed.Subscribe()
+	defer unsubscribe()
+
+	testBatch := postagetesting.MustNewBatch()
+	testChainState := postagetesting.NewChainState()
+	store := mock.New(mock.WithChainState(testChainState))
+	svc, err := batchservice.New(mocks.NewStateStore(), store, testLog, newMockListener(), nil, nil, feed, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := new(big.Int).Add(testChainState.TotalAmount, testChainState.CurrentPrice)
+	value.Add(value, big.NewInt(1000))
+	if err := svc.Create(testBatch.ID, testBatch.Owner, value, value, testBatch.Depth, testBatch.BucketDepth, testBatch.Immutable, testTxHash); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.TopUp(testBatch.ID, value, new(big.Int).Add(value, value), testTxHash); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UpdateDepth(testBatch.ID, testBatch.Depth+1, value, testTxHash); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UpdatePrice(big.NewInt(42), testTxHash); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []postage.BatchEventType{
+		postage.BatchCreated,
+		postage.BatchToppedUp,
+		postage.BatchDepthIncreased,
+		postage.PriceUpdated,
+	} {
+		e := <-events
+		if e.Type != want {
+			t.Fatalf("event type: want %s, got %s", want, e.Type)
+		}
+		if want == postage.PriceUpdated {
+			if e.Price.Cmp(big.NewInt(42)) != 0 {
+				t.Fatalf("price: want 42, got %v", e.Price)
+			}
+			continue
+		}
+		if !bytes.Equal(e.BatchID, testBatch.ID) {
+			t.Fatalf("batch id: want %x, got %x", testBatch.ID, e.BatchID)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package batchstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+const graceKeyPrefix = "batchstore_grace_"
+
+// Option is an option passed to New.
+type Option func(*store)
+
+// WithExpiryGracePeriod keeps the chunks of the expired batches readable for
+// the given number of blocks. The expired batches are removed from the store
+// right away, so that they are excluded from the reserve commitment, but their
+// chunks are evicted only at the end of the grace period.
+func WithExpiryGracePeriod(blocks uint64) Option {
+	return func(s *store) {
+		s.gracePeriod = blocks
+	}
+}
+
+// WithExpiryWarning publishes the BatchExpiring event once the batch is
+// going to expire within the given number of blocks.
+func WithExpiryWarning(blocks uint64) Option {
+	return func(s *store) {
+		s.warningPeriod = blocks
+	}
+}
+
+// WithEventPublisher sets the publisher of the expiry warnings and evictions.
+func WithEventPublisher(p postage.BatchEventPublisher) Option {
+	return func(s *store) {
+		s.publisher = p
+	}
+}
+
+// expiredBatch is the batch in its grace period.
+type expiredBatch struct {
+	Owner         []byte `json:"owner"`
+	EvictionBlock uint64 `json:"evictionBlock"`
+}
+
+// graceKey returns the index key of the expired batch in its grace period.
+func graceKey(batchID []byte) string {
+	return graceKeyPrefix + string(batchID)
+}
+
+// ExpiryGracePeriod is implementation of postage.Storer interface ExpiryGracePeriod method.
+func (s *store) ExpiryGracePeriod() uint64 {
+	return s.gracePeriod
+}
+
+// EvictionBlock is implementation of postage.Storer interface EvictionBlock method.
+func (s *store) EvictionBlock(id []byte) (uint64, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	b := new(expiredBatch)
+	if err := s.store.Get(graceKey(id), b); err != nil {
+		return 0, fmt.Errorf("get expired batch %x: %w", id, err)
+	}
+	return b.EvictionBlock, nil
+}
+
+// Expire is implementation of postage.Storer interface Expire method.
+func (s *store) Expire(id []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	b, err := s.get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := s.remove(b); err != nil {
+		return err
+	}
+	s.metrics.ExpiredEarly.Inc()
+	return s.computeRadius()
+}
+
+// expire evicts the chunks of the expired batch or keeps
+// them until the end of the grace period if it is set.
+// Must be called under lock.
+func (s *store) expire(b *postage.Batch) error {
+	if s.gracePeriod == 0 {
+		return s.evict(b.ID, b.Owner)
+	}
+	return s.store.Put(graceKey(b.ID), &expiredBatch{
+		Owner:         b.Owner,
+		EvictionBlock: s.cs.Block + s.gracePeriod,
+	})
+}
+
+// evict evicts the chunks of the batch.
+// Must be called under lock.
+func (s *store) evict(id, owner []byte) error {
+	if err := s.evictFn(id); err != nil {
+		return fmt.Errorf("evict batch %x: %w", id, err)
+	}
+	s.publish(postage.BatchEvent{Type: postage.BatchEvicted, BatchID: id, Owner: owner})
+	return nil
+}
+
+// evictExpired evicts the chunks of the expired batches
+// whose grace period ends at or before the given block.
+// Must be called under lock.
+func (s *store) evictExpired(block uint64) error {
+	type eviction struct {
+		id    []byte
+		owner []byte
+	}
+	var evictions []eviction
+
+	err := s.store.Iterate(graceKeyPrefix, func(key, value []byte) (bool, error) {
+		b := new(expiredBatch)
+		if err := json.Unmarshal(value, b); err != nil {
+			return false, err
+		}
+		if b.EvictionBlock <= block {
+			id := make([]byte, len(key)-len(graceKeyPrefix))
+			copy(id, key[len(graceKeyPrefix):])
+			evictions = append(evictions, eviction{id: id, owner: b.Owner})
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range evictions {
+		if err := s.evict(e.id, e.owner); err != nil {
+			return err
+		}
+		if err := s.store.Delete(graceKey(e.id)); err != nil {
+			return fmt.Errorf("delete expired batch %x: %w", e.id, err)
+		}
+	}
+	return nil
+}
+
+// warnExpiring publishes the BatchExpiring event for the batches which
+// are going to expire within the warning period. Every batch is reported
+// once until it is topped up above the warning period.
+// Must be called under lock.
+func (s *store) warnExpiring() error {
+	if s.publisher == nil || s.warningPeriod == 0 || s.cs.CurrentPrice == nil || s.cs.CurrentPrice.Sign() <= 0 {
+		return nil
+	}
+
+	threshold := new(big.Int).Mul(s.cs.CurrentPrice, new(big.Int).SetUint64(s.warningPeriod))
+	threshold.Add(threshold, s.cs.TotalAmount)
+
+	expiring := make(map[string]struct{})
+	err := s.store.Iterate(valueKeyPrefix, func(key, _ []byte) (bool, error) {
+		b, err := s.get(valueKeyToID(key))
+		if err != nil {
+			return false, err
+		}
+		if b.Value.Cmp(threshold) > 0 {
+			return true, nil // the batches are iterated in the order of their value
+		}
+
+		expiring[string(b.ID)] = struct{}{}
+		if _, ok := s.expiring[string(b.ID)]; ok {
+			return false, nil
+		}
+
+		blocks := new(big.Int).Sub(b.Value, s.cs.TotalAmount)
+		blocks.Div(blocks, s.cs.CurrentPrice)
+		s.publish(postage.BatchEvent{
+			Type:        postage.BatchExpiring,
+			BatchID:     b.ID,
+			Owner:       b.Owner,
+			Value:       b.Value,
+			Depth:       b.Depth,
+			BucketDepth: b.BucketDepth,
+			Immutable:   b.Immutable,
+			Blocks:      blocks.Uint64(),
+		})
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.expiring = expiring
+	return nil
+}
+
+// publish publishes the event if the publisher is set.
+func (s *store) publish(e postage.BatchEvent) {
+	if s.publisher != nil {
+		s.publisher.Publish(e)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package batchstore_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/postage/batchstore"
+	postagetest "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/statestore/leveldb"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+// eventRecorder records the published batch events.
+type eventRecorder struct {
+	events []postage.BatchEvent
+}
+
+func (r *eventRecorder) Publish(e postage.BatchEvent) {
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) take(typ postage.BatchEventType) []postage.BatchEvent {
+	var events []postage.BatchEvent
+	for _, e := range r.events {
+		if e.Type == typ {
+			events = append(events, e)
+		}
+	}
+	r.events = nil
+	return events
+}
+
+func setupExpiryBatchStore(t *testing.T, evicted *[][]byte, opts ...batchstore.Option) postage.Storer {
+	t.Helper()
+
+	stateStore, err := leveldb.NewStateStore(t.TempDir(), log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, stateStore)
+
+	evictFn := func(id []byte) error {
+		*evicted = append(*evicted, id)
+		return nil
+	}
+	bStore, err := batchstore.New(stateStore, evictFn, swarm.RandAddress(t), log.Noop, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bStore.PutChainState(&postage.ChainState{
+		Block:        0,
+		TotalAmount:  big.NewInt(0),
+		CurrentPrice: big.NewInt(1),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return bStore
+}
+
+func TestBatchExpiryGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	var evicted [][]byte
+	events := new(eventRecorder)
+	store := setupExpiryBatchStore(t, &evicted,
+		batchstore.WithExpiryGracePeriod(10),
+		batchstore.WithEventPublisher(events),
+	)
+
+	batch := postagetest.MustNewBatch(postagetest.WithValue(5), postagetest.WithDepth(0))
+	if err := store.Save(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	putChainState := func(block uint64) {
+		t.Helper()
+		if err := store.PutChainState(&postage.ChainState{
+			Block:        block,
+			TotalAmount:  big.NewInt(int64(block)),
+			CurrentPrice: big.NewInt(1),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the batch expires, but its chunks are kept
+	putChainState(5)
+	if exists, err := store.Exists(batch.ID); err != nil || exists {
+		t.Fatalf("got exists %v with error %v, want the batch removed", exists, err)
+	}
+	if len(evicted) != 0 {
+		t.Fatalf("got %d evictions in the grace period", len(evicted))
+	}
+	block, err := store.EvictionBlock(batch.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block != 15 {
+		t.Fatalf("got eviction block %d, want %d", block, 15)
+	}
+
+	putChainState(14)
+	if len(evicted) != 0 {
+		t.Fatalf("got %d evictions in the grace period", len(evicted))
+	}
+
+	// the chunks are evicted at the end of the grace period
+	putChainState(15)
+	if len(evicted) != 1 || !bytes.Equal(evicted[0], batch.ID) {
+		t.Fatalf("got evicted batches %x, want %x", evicted, batch.ID)
+	}
+	if got := events.take(postage.BatchEvicted); len(got) != 1 || !bytes.Equal(got[0].BatchID, batch.ID) {
+		t.Fatalf("got eviction events %v", got)
+	}
+	if _, err := store.EvictionBlock(batch.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+func TestBatchExpiryGracePeriodReset(t *testing.T) {
+	t.Parallel()
+
+	var evicted [][]byte
+	store := setupExpiryBatchStore(t, &evicted, batchstore.WithExpiryGracePeriod(10))
+
+	batch := postagetest.MustNewBatch(postagetest.WithValue(5), postagetest.WithDepth(0))
+	if err := store.Save(batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutChainState(&postage.ChainState{
+		Block:        5,
+		TotalAmount:  big.NewInt(5),
+		CurrentPrice: big.NewInt(1),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(evicted) != 0 {
+		t.Fatalf("got %d evictions in the grace period", len(evicted))
+	}
+
+	// the chunks of the expired batches are not left behind on reset
+	if err := store.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if len(evicted) != 1 || !bytes.Equal(evicted[0], batch.ID) {
+		t.Fatalf("got evicted batches %x, want %x", evicted, batch.ID)
+	}
+}
+
+func TestBatchExpiryWarning(t *testing.T) {
+	t.Parallel()
+
+	var evicted [][]byte
+	events := new(eventRecorder)
+	store := setupExpiryBatchStore(t, &evicted,
+		batchstore.WithExpiryWarning(10),
+		batchstore.WithEventPublisher(events),
+	)
+
+	soon := postagetest.MustNewBatch(postagetest.WithValue(20), postagetest.WithDepth(0))
+	later := postagetest.MustNewBatch(postagetest.WithValue(100), postagetest.WithDepth(0))
+	for _, b := range []*postage.Batch{soon, later} {
+		if err := store.Save(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	putChainState := func(block uint64) {
+		t.Helper()
+		if err := store.PutChainState(&postage.ChainState{
+			Block:        block,
+			TotalAmount:  big.NewInt(int64(block)),
+			CurrentPrice: big.NewInt(1),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	putChainState(12)
+	got := events.take(postage.BatchExpiring)
+	if len(got) != 1 || !bytes.Equal(got[0].BatchID, soon.ID) {
+		t.Fatalf("got expiry warnings %v, want one for %x", got, soon.ID)
+	}
+	if got[0].Blocks != 8 {
+		t.Fatalf("got %d blocks until the expiry, want %d", got[0].Blocks, 8)
+	}
+
+	// the batch is reported only once
+	putChainState(13)
+	if got := events.take(postage.BatchExpiring); len(got) != 0 {
+		t.Fatalf("got repeated expiry warnings %v", got)
+	}
+
+	// the topped up batch is reported again when it gets close to the expiry
+	if err := store.Update(soon, big.NewInt(40), soon.Depth); err != nil {
+		t.Fatal(err)
+	}
+	putChainState(14)
+	if got := events.take(postage.BatchExpiring); len(got) != 0 {
+		t.Fatalf("got expiry warnings %v for the topped up batch", got)
+	}
+	putChainState(31)
+	got = events.take(postage.BatchExpiring)
+	if len(got) != 1 || !bytes.Equal(got[0].BatchID, soon.ID) {
+		t.Fatalf("got expiry warnings %v, want one for %x", got, soon.ID)
+	}
+}
+
+func TestBatchExpire(t *testing.T) {
+	t.Parallel()
+
+	var evicted [][]byte
+	store := setupExpiryBatchStore(t, &evicted)
+
+	batch := postagetest.MustNewBatch(postagetest.WithValue(5), postagetest.WithDepth(0))
+	if err := store.Save(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	// the batch is expired before the chain state reflects the expiry
+	if err := store.Expire(batch.ID); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := store.Exists(batch.ID); err != nil || exists {
+		t.Fatalf("got exists %v with error %v, want the batch removed", exists, err)
+	}
+	if len(evicted) != 1 || !bytes.Equal(evicted[0], batch.ID) {
+		t.Fatalf("got evicted batches %x, want %x", evicted, batch.ID)
+	}
+
+	// expiring the removed batch again is a no-op
+	if err := store.Expire(batch.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(evicted) != 1 {
+		t.Fatalf("got %d evictions, want 1", len(evicted))
+	}
+}
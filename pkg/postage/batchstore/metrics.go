@@ -14,6 +14,7 @@ type metrics struct {
 	Radius            prometheus.Gauge
 	StorageRadius     prometheus.Gauge
 	UnreserveDuration prometheus.HistogramVec
+	ExpiredEarly      prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -44,6 +45,12 @@ func newMetrics() metrics {
 			Name:      "unreserve_duration",
 			Help:      "Duration in seconds for the Unreserve call.",
 		}, []string{"beforeLock"}),
+		ExpiredEarly: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "expired_early",
+			Help:      "Number of batches expired before the chain state reflected the expiry.",
+		}),
 	}
 }
 
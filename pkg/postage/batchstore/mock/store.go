@@ -35,6 +35,10 @@ type BatchStore struct {
 
 	existsFn func([]byte) (bool, error)
 
+	gracePeriod    uint64
+	evictionBlocks map[string]uint64
+	expired        [][]byte
+
 	mtx sync.Mutex
 }
 
@@ -110,6 +114,24 @@ func WithExistsFunc(f func([]byte) (bool, error)) Option {
 	}
 }
 
+// WithExpiryGracePeriod sets the grace period of the expired batches in blocks.
+func WithExpiryGracePeriod(blocks uint64) Option {
+	return func(bs *BatchStore) {
+		bs.gracePeriod = blocks
+	}
+}
+
+// WithEvictionBlock sets the block at which the chunks
+// of the expired batch with the given ID are evicted.
+func WithEvictionBlock(id []byte, block uint64) Option {
+	return func(bs *BatchStore) {
+		if bs.evictionBlocks == nil {
+			bs.evictionBlocks = make(map[string]uint64)
+		}
+		bs.evictionBlocks[string(id)] = block
+	}
+}
+
 func WithAcceptAllExistsFunc() Option {
 	return func(bs *BatchStore) {
 		bs.existsFn = func(_ []byte) (bool, error) {
@@ -248,6 +270,39 @@ func (bs *BatchStore) Reset() error {
 	return nil
 }
 
+func (bs *BatchStore) ExpiryGracePeriod() uint64 {
+	return bs.gracePeriod
+}
+
+func (bs *BatchStore) EvictionBlock(id []byte) (uint64, error) {
+	block, ok := bs.evictionBlocks[string(id)]
+	if !ok {
+		return 0, storage.ErrNotFound
+	}
+	return block, nil
+}
+
+// Expire records the expiry of the batch and drops it if it is the batch of the mock.
+func (bs *BatchStore) Expire(id []byte) error {
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+
+	bs.expired = append(bs.expired, id)
+	if bytes.Equal(bs.id, id) {
+		bs.batch = nil
+		bs.id = nil
+	}
+	return nil
+}
+
+// Expired returns the IDs of the batches passed to Expire.
+func (bs *BatchStore) Expired() [][]byte {
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+
+	return append([][]byte(nil), bs.expired...)
+}
+
 func (bs *BatchStore) ResetCalls() int {
 	return bs.resetCallCount
 }
@@ -104,23 +104,32 @@ func (s *store) cleanup() error {
 	}
 
 	for _, b := range evictions {
-		err := s.evictFn(b.ID)
-		if err != nil {
-			return fmt.Errorf("evict batch %x: %w", b.ID, err)
-		}
-		err = s.store.Delete(valueKey(b.Value, b.ID))
-		if err != nil {
-			return fmt.Errorf("delete value key for batch %x: %w", b.ID, err)
-		}
-		err = s.store.Delete(batchKey(b.ID))
-		if err != nil {
-			return fmt.Errorf("delete batch %x: %w", b.ID, err)
-		}
-		if s.batchExpiry != nil {
-			s.batchExpiry.HandleStampExpiry(b.ID)
+		if err := s.remove(b); err != nil {
+			return err
 		}
 	}
 
+	return s.evictExpired(s.cs.Block)
+}
+
+// remove expires and deletes the batch.
+// Must be called under lock.
+func (s *store) remove(b *postage.Batch) error {
+	err := s.expire(b)
+	if err != nil {
+		return err
+	}
+	err = s.store.Delete(valueKey(b.Value, b.ID))
+	if err != nil {
+		return fmt.Errorf("delete value key for batch %x: %w", b.ID, err)
+	}
+	err = s.store.Delete(batchKey(b.ID))
+	if err != nil {
+		return fmt.Errorf("delete batch %x: %w", b.ID, err)
+	}
+	if s.batchExpiry != nil {
+		s.batchExpiry.HandleStampExpiry(b.ID)
+	}
 	return nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"sync"
 
@@ -48,11 +49,16 @@ type store struct {
 
 	batchExpiry         postage.BatchExpiryHandler
 	storageRadiusSetter postage.StorageRadiusSetter // setter for radius notifications
+
+	gracePeriod   uint64                      // blocks for which the chunks of the expired batches are kept
+	warningPeriod uint64                      // blocks before the expiry at which the batches are reported
+	publisher     postage.BatchEventPublisher // publisher of the expiry warnings and evictions
+	expiring      map[string]struct{}         // batches reported to expire within the warning period
 }
 
 // New constructs a new postage batch store.
 // It initialises both chain state and reserve state from the persistent state store.
-func New(st storage.StateStorer, ev evictFn, addr swarm.Address, logger log.Logger) (postage.Storer, error) {
+func New(st storage.StateStorer, ev evictFn, addr swarm.Address, logger log.Logger, opts ...Option) (postage.Storer, error) {
 	cs := &postage.ChainState{}
 	err := st.Get(chainStateKey, cs)
 	if err != nil {
@@ -86,6 +92,9 @@ func New(st storage.StateStorer, ev evictFn, addr swarm.Address, logger log.Logg
 		metrics: newMetrics(),
 		logger:  logger.WithName(loggerName).Register(),
 	}
+	for _, o := range opts {
+		o(s)
+	}
 	return s, nil
 }
 
@@ -296,6 +305,11 @@ func (s *store) PutChainState(cs *postage.ChainState) error {
 		return fmt.Errorf("batchstore: put chain state clean up: %w", err)
 	}
 
+	err = s.warnExpiring()
+	if err != nil {
+		return fmt.Errorf("batchstore: put chain state expiry warning: %w", err)
+	}
+
 	err = s.computeRadius()
 	if err != nil {
 		return fmt.Errorf("batchstore: put chain state adjust radius: %w", err)
@@ -322,6 +336,11 @@ func (s *store) Reset() error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
+	// the chunks of the expired batches would not be evicted after the reset
+	if err := s.evictExpired(math.MaxUint64); err != nil {
+		return err
+	}
+
 	const prefix = "batchstore_"
 	if err := s.store.Iterate(prefix, func(k, _ []byte) (bool, error) {
 		return false, s.store.Delete(string(k))
@@ -0,0 +1,214 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package batchtable feeds the batchstore from a static table of the valid
+// batches instead of the postage contract events, which allows to run
+// private swarms without any blockchain. The stamps are still validated
+// against the owners and depths of the batches found in the table.
+package batchtable
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/postage"
+)
+
+var (
+	// ErrMissingSignature is returned when the signer of the
+	// table is configured but the table is not signed.
+	ErrMissingSignature = errors.New("batch table is not signed")
+	// ErrInvalidSignature is returned when the
+	// table is not signed by the configured signer.
+	ErrInvalidSignature = errors.New("invalid batch table signature")
+)
+
+// file is the JSON encoding of the table. The signature, if present, is the
+// ethereum signed message signature of the raw bytes of the batches field.
+type file struct {
+	Batches   json.RawMessage `json:"batches"`
+	Signature string          `json:"signature,omitempty"`
+}
+
+type entry struct {
+	BatchID     string `json:"batchID"`
+	Owner       string `json:"owner"`
+	Value       string `json:"value"` // normalised balance of the batch
+	Depth       uint8  `json:"depth"`
+	BucketDepth uint8  `json:"bucketDepth"`
+	Immutable   bool   `json:"immutable"`
+}
+
+// Parse parses the JSON encoded table of the batches. If the signer is
+// not nil, the table must be signed with the key of the signer.
+func Parse(data []byte, signer *common.Address) ([]*postage.Batch, error) {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("unmarshal batch table: %w", err)
+	}
+
+	if signer != nil {
+		if f.Signature == "" {
+			return nil, ErrMissingSignature
+		}
+		signature, err := hex.DecodeString(f.Signature)
+		if err != nil {
+			return nil, ErrInvalidSignature
+		}
+		publicKey, err := crypto.Recover(signature, f.Batches)
+		if err != nil {
+			return nil, ErrInvalidSignature
+		}
+		address, err := crypto.NewEthereumAddress(*publicKey)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(address, signer.Bytes()) {
+			return nil, ErrInvalidSignature
+		}
+	}
+
+	var entries []entry
+	if err := json.Unmarshal(f.Batches, &entries); err != nil {
+		return nil, fmt.Errorf("unmarshal batches: %w", err)
+	}
+
+	batches := make([]*postage.Batch, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		id, err := hex.DecodeString(e.BatchID)
+		if err != nil || len(id) != 32 {
+			return nil, fmt.Errorf("invalid batch id %q", e.BatchID)
+		}
+		if _, ok := seen[string(id)]; ok {
+			return nil, fmt.Errorf("duplicate batch %s", e.BatchID)
+		}
+		seen[string(id)] = struct{}{}
+
+		if !common.IsHexAddress(e.Owner) {
+			return nil, fmt.Errorf("invalid owner %q of batch %s", e.Owner, e.BatchID)
+		}
+		value, ok := new(big.Int).SetString(e.Value, 10)
+		if !ok || value.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid value %q of batch %s", e.Value, e.BatchID)
+		}
+		if e.BucketDepth == 0 || e.Depth <= e.BucketDepth {
+			return nil, fmt.Errorf("invalid depth %d and bucket depth %d of batch %s", e.Depth, e.BucketDepth, e.BatchID)
+		}
+
+		batches = append(batches, &postage.Batch{
+			ID:          id,
+			Value:       value,
+			Owner:       common.HexToAddress(e.Owner).Bytes(),
+			Depth:       e.Depth,
+			BucketDepth: e.BucketDepth,
+			Immutable:   e.Immutable,
+		})
+	}
+	return batches, nil
+}
+
+// Encode returns the JSON encoded table of the batches,
+// signed with the signer unless the signer is nil.
+func Encode(batches []*postage.Batch, signer crypto.Signer) ([]byte, error) {
+	entries := make([]entry, 0, len(batches))
+	for _, b := range batches {
+		entries = append(entries, entry{
+			BatchID:     hex.EncodeToString(b.ID),
+			Owner:       common.BytesToAddress(b.Owner).Hex(),
+			Value:       b.Value.String(),
+			Depth:       b.Depth,
+			BucketDepth: b.BucketDepth,
+			Immutable:   b.Immutable,
+		})
+	}
+
+	var (
+		f   file
+		err error
+	)
+	if f.Batches, err = json.Marshal(entries); err != nil {
+		return nil, err
+	}
+	if signer != nil {
+		signature, err := signer.Sign(f.Batches)
+		if err != nil {
+			return nil, err
+		}
+		f.Signature = hex.EncodeToString(signature)
+	}
+	return json.Marshal(f)
+}
+
+// Load makes the store hold exactly the batches of the table. The new batches
+// are saved and the changed ones updated, while the batches no longer found
+// in the table expire, as if their balance ran out. The chain state is kept
+// at zero price, so the batches of the table never expire on their own.
+// The stamp issuers of the batches owned by the owner are handed to the
+// listener, so that the node can stamp the chunks with them.
+func Load(store postage.Storer, listener postage.BatchEventListener, owner []byte, batches []*postage.Batch) error {
+	listed := make(map[string]struct{}, len(batches))
+	for _, b := range batches {
+		listed[string(b.ID)] = struct{}{}
+
+		exists, err := store.Exists(b.ID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if err := store.Save(b); err != nil {
+				return err
+			}
+		} else {
+			stored, err := store.Get(b.ID)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(stored.Owner, b.Owner) || stored.BucketDepth != b.BucketDepth || stored.Immutable != b.Immutable {
+				return fmt.Errorf("batch %x of the table does not match the stored one", b.ID)
+			}
+			if depth := stored.Depth; stored.Value.Cmp(b.Value) != 0 || depth != b.Depth {
+				if err := store.Update(stored, b.Value, b.Depth); err != nil {
+					return err
+				}
+				if bytes.Equal(b.Owner, owner) && b.Depth > depth {
+					listener.HandleDepthIncrease(b.ID, b.Depth)
+				}
+			}
+		}
+
+		if bytes.Equal(b.Owner, owner) {
+			if err := listener.HandleCreate(b, b.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	var unlisted []*postage.Batch
+	err := store.Iterate(func(b *postage.Batch) (bool, error) {
+		if _, ok := listed[string(b.ID)]; !ok {
+			unlisted = append(unlisted, b)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, b := range unlisted {
+		if err := store.Update(b, big.NewInt(0), b.Depth); err != nil {
+			return err
+		}
+	}
+
+	return store.PutChainState(&postage.ChainState{
+		TotalAmount:  big.NewInt(0),
+		CurrentPrice: big.NewInt(0),
+	})
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package batchtable_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/postage/batchstore"
+	"github.com/ethersphere/bee/pkg/postage/batchtable"
+	postagetest "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/statestore/leveldb"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+type listener struct {
+	created [][]byte
+}
+
+func (l *listener) HandleCreate(b *postage.Batch, _ *big.Int) error {
+	l.created = append(l.created, b.ID)
+	return nil
+}
+func (l *listener) HandleTopUp([]byte, *big.Int)      {}
+func (l *listener) HandleDepthIncrease([]byte, uint8) {}
+
+func newSigner(t *testing.T) (crypto.Signer, common.Address) {
+	t.Helper()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	address, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, address
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	var (
+		signer, address = newSigner(t)
+		_, otherAddress = newSigner(t)
+		batches         = []*postage.Batch{
+			postagetest.MustNewBatch(postagetest.WithValue(100)),
+			postagetest.MustNewBatch(postagetest.WithValue(200)),
+		}
+	)
+
+	signed, err := batchtable.Encode(batches, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := batchtable.Encode(batches, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		data    []byte
+		signer  *common.Address
+		wantErr error
+	}{
+		{name: "signed", data: signed, signer: &address},
+		{name: "signature not required", data: unsigned},
+		{name: "missing signature", data: unsigned, signer: &address, wantErr: batchtable.ErrMissingSignature},
+		{name: "other signer", data: signed, signer: &otherAddress, wantErr: batchtable.ErrInvalidSignature},
+		{name: "tampered", data: bytes.Replace(signed, []byte(`"100"`), []byte(`"999"`), 1), signer: &address, wantErr: batchtable.ErrInvalidSignature},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := batchtable.Parse(tc.data, tc.signer)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if len(got) != len(batches) {
+				t.Fatalf("got %d batches, want %d", len(got), len(batches))
+			}
+			for i, b := range got {
+				if !bytes.Equal(b.ID, batches[i].ID) || !bytes.Equal(b.Owner, batches[i].Owner) || b.Value.Cmp(batches[i].Value) != 0 ||
+					b.Depth != batches[i].Depth || b.BucketDepth != batches[i].BucketDepth || b.Immutable != batches[i].Immutable {
+					t.Fatalf("got batch %+v, want %+v", b, batches[i])
+				}
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, data := range []string{
+			`{"batches": [{"batchID": "ff", "owner": "0x0000000000000000000000000000000000000001", "value": "1", "depth": 17, "bucketDepth": 16}]}`,
+			`{"batches": [{"batchID": "` + swarm.RandAddress(t).String() + `", "owner": "0x01", "value": "1", "depth": 17, "bucketDepth": 16}]}`,
+			`{"batches": [{"batchID": "` + swarm.RandAddress(t).String() + `", "owner": "0x0000000000000000000000000000000000000001", "value": "0", "depth": 17, "bucketDepth": 16}]}`,
+			`{"batches": [{"batchID": "` + swarm.RandAddress(t).String() + `", "owner": "0x0000000000000000000000000000000000000001", "value": "1", "depth": 16, "bucketDepth": 16}]}`,
+		} {
+			if _, err := batchtable.Parse([]byte(data), nil); err == nil {
+				t.Fatalf("expected error parsing %s", data)
+			}
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	var (
+		owner   = postagetest.MustNewAddress()
+		owned   = postagetest.MustNewBatch(postagetest.WithOwner(owner), postagetest.WithValue(100))
+		foreign = postagetest.MustNewBatch(postagetest.WithValue(100))
+		evicted [][]byte
+		l       = new(listener)
+	)
+
+	// the leveldb state store iterates in the key order the expiry relies on
+	stateStore, err := leveldb.NewInMemoryStateStore(log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = stateStore.Close() })
+
+	store, err := batchstore.New(stateStore, func(id []byte) error {
+		evicted = append(evicted, id)
+		return nil
+	}, swarm.RandAddress(t), log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := batchtable.Load(store, l, owner, []*postage.Batch{owned, foreign}); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []*postage.Batch{owned, foreign} {
+		if exists, err := store.Exists(b.ID); err != nil || !exists {
+			t.Fatalf("batch %x not loaded: %v", b.ID, err)
+		}
+	}
+	if len(l.created) != 1 || !bytes.Equal(l.created[0], owned.ID) {
+		t.Fatalf("got issuers created for %x, want only for the owned batch", l.created)
+	}
+
+	// the foreign batch is dropped from the table and the owned one topped up
+	table := []*postage.Batch{postagetest.MustNewBatch(
+		postagetest.WithOwner(owner),
+		postagetest.WithValue(500),
+		func(b *postage.Batch) { b.ID = owned.ID },
+	)}
+	if err := batchtable.Load(store, l, owner, table); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Get(owned.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Value.Cmp(big.NewInt(500)) != 0 {
+		t.Fatalf("got value %v of the owned batch, want 500", got.Value)
+	}
+	if exists, err := store.Exists(foreign.ID); err != nil || exists {
+		t.Fatalf("batch dropped from the table still exists: %v", err)
+	}
+	if len(evicted) != 1 || !bytes.Equal(evicted[0], foreign.ID) {
+		t.Fatalf("got evicted batches %x, want only the foreign batch", evicted)
+	}
+}
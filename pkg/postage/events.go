@@ -0,0 +1,120 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postage
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BatchEventType is the type of the batch event.
+type BatchEventType string
+
+const (
+	BatchCreated        BatchEventType = "create"
+	BatchToppedUp       BatchEventType = "topup"
+	BatchDepthIncreased BatchEventType = "dilute"
+	BatchExpired        BatchEventType = "expire"
+	BatchExpiring       BatchEventType = "expiring"
+	BatchEvicted        BatchEventType = "evict"
+	PriceUpdated        BatchEventType = "price"
+)
+
+// BatchEvent is an update of a batch or of the price observed on the chain.
+// Fields which do not apply to the event type are left at their zero value.
+type BatchEvent struct {
+	Type        BatchEventType
+	BatchID     []byte
+	Owner       []byte
+	Value       *big.Int // normalised balance of the batch
+	Depth       uint8
+	BucketDepth uint8
+	Immutable   bool
+	Price       *big.Int
+	TxHash      common.Hash
+	Blocks      uint64 // blocks left until the batch expires or its chunks are evicted
+}
+
+// BatchEventPublisher publishes the batch events.
+type BatchEventPublisher interface {
+	Publish(BatchEvent)
+}
+
+// batchEventBufferSize is the number of the events buffered for a subscriber
+// after which the events are dropped until the subscriber catches up.
+const batchEventBufferSize = 64
+
+// BatchEventFeed delivers the published batch events to its subscribers.
+type BatchEventFeed struct {
+	mu   sync.Mutex
+	subs []chan BatchEvent
+}
+
+var _ BatchEventPublisher = (*BatchEventFeed)(nil)
+
+// NewBatchEventFeed constructs an empty feed.
+func NewBatchEventFeed() *BatchEventFeed {
+	return new(BatchEventFeed)
+}
+
+// Publish delivers the event to the subscribers without blocking.
+// The event is dropped for the subscribers which fell behind.
+func (f *BatchEventFeed) Publish(e BatchEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, c := range f.subs {
+		select {
+		case c <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the channel on which the published events are received.
+// Returned function is safe to be called multiple times.
+func (f *BatchEventFeed) Subscribe() (c <-chan BatchEvent, unsubscribe func()) {
+	channel := make(chan BatchEvent, batchEventBufferSize)
+	var closeOnce sync.Once
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.subs = append(f.subs, channel)
+
+	unsubscribe = func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		for i, c := range f.subs {
+			if c == channel {
+				f.subs = append(f.subs[:i], f.subs[i+1:]...)
+				break
+			}
+		}
+
+		closeOnce.Do(func() { close(channel) })
+	}
+
+	return channel, unsubscribe
+}
+
+// ExpiryHandler returns a BatchExpiryHandler which publishes
+// the expiry of the batches before passing it on to h.
+func (f *BatchEventFeed) ExpiryHandler(h BatchExpiryHandler) BatchExpiryHandler {
+	return &expiryPublisher{BatchExpiryHandler: h, feed: f}
+}
+
+type expiryPublisher struct {
+	BatchExpiryHandler
+	feed *BatchEventFeed
+}
+
+func (e *expiryPublisher) HandleStampExpiry(id []byte) {
+	e.feed.Publish(BatchEvent{Type: BatchExpired, BatchID: id})
+	e.BatchExpiryHandler.HandleStampExpiry(id)
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postage_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/postage"
+)
+
+type expiryHandler struct {
+	expired [][]byte
+}
+
+func (h *expiryHandler) HandleStampExpiry(id []byte) { h.expired = append(h.expired, id) }
+func (h *expiryHandler) SetExpired() error           { return nil }
+
+func TestBatchEventFeed(t *testing.T) {
+	t.Parallel()
+
+	feed := postage.NewBatchEventFeed()
+	c1, unsubscribe1 := feed.Subscribe()
+	c2, unsubscribe2 := feed.Subscribe()
+	defer unsubscribe2()
+
+	h := new(expiryHandler)
+	feed.ExpiryHandler(h).HandleStampExpiry([]byte{1})
+
+	for _, c := range []<-chan postage.BatchEvent{c1, c2} {
+		e := <-c
+		if e.Type != postage.BatchExpired || !bytes.Equal(e.BatchID, []byte{1}) {
+			t.Fatalf("unexpected event %+v", e)
+		}
+	}
+	if len(h.expired) != 1 {
+		t.Fatalf("expiry not passed on to the handler")
+	}
+
+	unsubscribe1()
+	unsubscribe1()
+	if _, ok := <-c1; ok {
+		t.Fatal("channel not closed after unsubscribe")
+	}
+
+	// a subscriber which does not read must not block the publisher
+	for i := 0; i < 1000; i++ {
+		feed.Publish(postage.BatchEvent{Type: postage.PriceUpdated})
+	}
+}
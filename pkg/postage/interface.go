@@ -98,6 +98,20 @@ type Storer interface {
 	Reset() error
 
 	SetBatchExpiryHandler(BatchExpiryHandler)
+
+	// ExpiryGracePeriod returns the number of blocks for which the chunks
+	// of the expired batches are kept readable before they are evicted.
+	ExpiryGracePeriod() uint64
+
+	// EvictionBlock returns the block at which the chunks of the expired
+	// batch with the given ID are evicted. It returns storage.ErrNotFound
+	// if the batch is not in its grace period.
+	EvictionBlock([]byte) (uint64, error)
+
+	// Expire removes the batch with the given ID as if its balance was
+	// depleted on the chain, before the chain state reflects the expiry.
+	// It is not an error if the batch does not exist.
+	Expire([]byte) error
 }
 
 // StorageRadiusSetter is used as a callback when the radius of a node changes.
@@ -39,9 +39,13 @@ func WithAcceptAll() Option {
 	return optionFunc(func(m *mockPostage) { m.acceptAll = true })
 }
 
+// WithIssuer adds the stamp issuer to the mock, can be given multiple times.
 func WithIssuer(s *postage.StampIssuer) Option {
 	return optionFunc(func(m *mockPostage) {
-		m.issuersMap = map[string]*postage.StampIssuer{string(s.ID()): s}
+		if m.issuersMap == nil {
+			m.issuersMap = make(map[string]*postage.StampIssuer)
+		}
+		m.issuersMap[string(s.ID())] = s
 	})
 }
 
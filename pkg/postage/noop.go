@@ -52,3 +52,9 @@ func (b *NoOpBatchStore) SetStorageRadiusSetter(StorageRadiusSetter) {}
 func (b *NoOpBatchStore) Unreserve(UnreserveIteratorFn) error { return nil }
 
 func (b *NoOpBatchStore) Reset() error { return nil }
+
+func (b *NoOpBatchStore) ExpiryGracePeriod() uint64 { return 0 }
+
+func (b *NoOpBatchStore) EvictionBlock([]byte) (uint64, error) { return 0, ErrChainDisabled }
+
+func (b *NoOpBatchStore) Expire([]byte) error { return nil }
@@ -57,6 +57,11 @@ func (s *Stamp) Index() []byte {
 	return s.index
 }
 
+// BucketIndex returns the bucket and the within-bucket index of the stamp.
+func (s *Stamp) BucketIndex() (bucket, index uint32) {
+	return bytesToIndex(s.index)
+}
+
 // Sig returns the signature of the stamp by the user
 func (s *Stamp) Sig() []byte {
 	return s.sig
@@ -143,22 +148,14 @@ func ValidStamp(batchStore Storer) ValidStampFn {
 // the validity  check is only meaningful in its association of a chunk
 // this chunk address needs to be given as argument
 func (s *Stamp) Valid(chunkAddr swarm.Address, ownerAddr []byte, depth, bucketDepth uint8, immutable bool) error {
-	toSign, err := toSignDigest(chunkAddr.Bytes(), s.batchID, s.index, s.timestamp)
-	if err != nil {
-		return err
-	}
-	signerPubkey, err := crypto.Recover(s.sig, toSign)
+	signerAddr, err := s.RecoverSigner(chunkAddr)
 	if err != nil {
 		return err
 	}
-	signerAddr, err := crypto.NewEthereumAddress(*signerPubkey)
-	if err != nil {
+	if err := s.ValidBucket(chunkAddr, bucketDepth); err != nil {
 		return err
 	}
-	bucket, index := bytesToIndex(s.index)
-	if toBucket(bucketDepth, chunkAddr) != bucket {
-		return ErrBucketMismatch
-	}
+	_, index := bytesToIndex(s.index)
 	if index >= 1<<int(depth-bucketDepth) {
 		return ErrInvalidIndex
 	}
@@ -167,3 +164,28 @@ func (s *Stamp) Valid(chunkAddr swarm.Address, ownerAddr []byte, depth, bucketDe
 	}
 	return nil
 }
+
+// RecoverSigner returns the ethereum address of the signer of the stamp
+// attached to the chunk with the address, which is the batch owner for
+// the valid stamps.
+func (s *Stamp) RecoverSigner(chunkAddr swarm.Address) ([]byte, error) {
+	toSign, err := toSignDigest(chunkAddr.Bytes(), s.batchID, s.index, s.timestamp)
+	if err != nil {
+		return nil, err
+	}
+	signerPubkey, err := crypto.Recover(s.sig, toSign)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.NewEthereumAddress(*signerPubkey)
+}
+
+// ValidBucket checks that the stamp index is in the
+// collision bucket of the chunk with the address.
+func (s *Stamp) ValidBucket(chunkAddr swarm.Address, bucketDepth uint8) error {
+	bucket, _ := bytesToIndex(s.index)
+	if toBucket(bucketDepth, chunkAddr) != bucket {
+		return ErrBucketMismatch
+	}
+	return nil
+}
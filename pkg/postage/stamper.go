@@ -7,6 +7,7 @@ package postage
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/crypto"
@@ -18,11 +19,52 @@ var (
 	ErrBucketFull = errors.New("bucket full")
 )
 
+// BucketFullError is returned when the chunk cannot be stamped because its
+// collision bucket of the batch is full. It wraps ErrBucketFull.
+type BucketFullError struct {
+	BatchID     []byte
+	Bucket      uint32
+	Depth       uint8  // depth of the batch
+	BucketDepth uint8  // depth of the collision buckets
+	Utilization uint32 // number of chunks stamped in the bucket
+	UpperBound  uint32 // maximal number of chunks in the bucket
+}
+
+// SuggestedDepth returns the depth the batch should be diluted to in order
+// to stamp the chunk; every increment of the depth doubles the capacity
+// of all buckets.
+func (e *BucketFullError) SuggestedDepth() uint8 {
+	return e.Depth + 1
+}
+
+func (e *BucketFullError) Error() string {
+	return fmt.Sprintf("%v: batch %x bucket %d utilization %d/%d", ErrBucketFull, e.BatchID, e.Bucket, e.Utilization, e.UpperBound)
+}
+
+func (e *BucketFullError) Unwrap() error {
+	return ErrBucketFull
+}
+
 // Stamper can issue stamps from the given address.
 type Stamper interface {
 	Stamp(swarm.Address) (*Stamp, error)
 }
 
+// BucketUtilization is the utilization of the collision bucket
+// of a chunk address in a batch.
+type BucketUtilization struct {
+	Count      uint32 // number of chunks stamped in the bucket
+	UpperBound uint32 // maximal number of chunks in the bucket
+	Buckets    uint32 // number of the buckets of the batch
+}
+
+// BucketUtilizer reports the utilization of the bucket of the chunk address,
+// so that the chunks whose address can be chosen, such as the encrypted
+// chunks with their random keys, are placed into the buckets with room.
+type BucketUtilizer interface {
+	BucketUtilization(swarm.Address) BucketUtilization
+}
+
 // stamper connects a stampissuer with a signer.
 // A stamper is created for each upload session.
 type stamper struct {
@@ -54,6 +96,42 @@ func (st *stamper) Stamp(addr swarm.Address) (*Stamp, error) {
 	return NewStamp(st.issuer.data.BatchID, index, ts, sig), nil
 }
 
+// BucketUtilization implements the BucketUtilizer.
+func (st *stamper) BucketUtilization(addr swarm.Address) BucketUtilization {
+	return st.issuer.BucketUtilization(addr)
+}
+
+// fallbackStamper stamps the chunks with the fallback
+// stamper if their bucket in the primary batch is full.
+type fallbackStamper struct {
+	primary  Stamper
+	fallback Stamper
+}
+
+// NewFallbackStamper constructs a Stamper which issues the stamps from the
+// primary stamper and switches to the fallback stamper only for the chunks
+// which overflow their bucket in the primary batch.
+func NewFallbackStamper(primary, fallback Stamper) Stamper {
+	return &fallbackStamper{primary: primary, fallback: fallback}
+}
+
+func (st *fallbackStamper) Stamp(addr swarm.Address) (*Stamp, error) {
+	stamp, err := st.primary.Stamp(addr)
+	if errors.Is(err, ErrBucketFull) {
+		return st.fallback.Stamp(addr)
+	}
+	return stamp, err
+}
+
+// BucketUtilization implements the BucketUtilizer with the utilization
+// of the primary batch, as the fallback batch is used only on overflow.
+func (st *fallbackStamper) BucketUtilization(addr swarm.Address) BucketUtilization {
+	if u, ok := st.primary.(BucketUtilizer); ok {
+		return u.BucketUtilization(addr)
+	}
+	return BucketUtilization{}
+}
+
 func timestamp() []byte {
 	ts := make([]byte, 8)
 	binary.BigEndian.PutUint64(ts, uint64(time.Now().UnixNano()))
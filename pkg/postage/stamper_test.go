@@ -5,6 +5,7 @@
 package postage_test
 
 import (
+	"bytes"
 	"errors"
 	"math/big"
 	"testing"
@@ -98,9 +99,39 @@ func TestStamperStamping(t *testing.T) {
 			}
 		}
 		// the bucket should now be full, not allowing a stamp for the  pivot chunk
-		if _, err = stamper.Stamp(chunkAddr); !errors.Is(err, postage.ErrBucketFull) {
+		_, err = stamper.Stamp(chunkAddr)
+		if !errors.Is(err, postage.ErrBucketFull) {
 			t.Fatalf("expected ErrBucketFull, got %v", err)
 		}
+		var bfe *postage.BucketFullError
+		if !errors.As(err, &bfe) {
+			t.Fatalf("expected BucketFullError, got %v", err)
+		}
+		if !bytes.Equal(bfe.BatchID, st.ID()) || bfe.Bucket != uint32(chunkAddr.Bytes()[0]) {
+			t.Fatalf("unexpected bucket full error %v", bfe)
+		}
+		if bfe.Depth != 12 || bfe.BucketDepth != 8 || bfe.Utilization != 16 || bfe.UpperBound != 16 {
+			t.Fatalf("unexpected bucket full error %+v", bfe)
+		}
+		if got := bfe.SuggestedDepth(); got != 13 {
+			t.Fatalf("got suggested depth %d, want 13", got)
+		}
+
+		// the utilization of the bucket is reported to the pipeline
+		want := postage.BucketUtilization{Count: 16, UpperBound: 16, Buckets: 256}
+		if got := stamper.(postage.BucketUtilizer).BucketUtilization(chunkAddr); got != want {
+			t.Fatalf("got bucket utilization %+v, want %+v", got, want)
+		}
+
+		// the fallback stamper issues the stamp from the fallback batch
+		fallback := newTestStampIssuer(t, 1000)
+		stamp, err := postage.NewFallbackStamper(stamper, postage.NewStamper(fallback, signer)).Stamp(chunkAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(stamp.BatchID(), fallback.ID()) {
+			t.Fatalf("got stamp from batch %x, want %x", stamp.BatchID(), fallback.ID())
+		}
 	})
 
 	// tests return with ErrOwnerMismatch
@@ -70,7 +70,14 @@ func (si *StampIssuer) inc(addr swarm.Address) ([]byte, error) {
 
 	if bucketCount == si.BucketUpperBound() {
 		if si.ImmutableFlag() {
-			return nil, ErrBucketFull
+			return nil, &BucketFullError{
+				BatchID:     si.data.BatchID,
+				Bucket:      b,
+				Depth:       si.Depth(),
+				BucketDepth: si.BucketDepth(),
+				Utilization: bucketCount,
+				UpperBound:  si.BucketUpperBound(),
+			}
 		}
 
 		bucketCount = 0
@@ -171,6 +178,17 @@ func (si *StampIssuer) ImmutableFlag() bool {
 	return si.data.ImmutableFlag
 }
 
+// BucketUtilization returns the utilization of the bucket of the address.
+func (si *StampIssuer) BucketUtilization(addr swarm.Address) BucketUtilization {
+	si.bucketMu.Lock()
+	defer si.bucketMu.Unlock()
+	return BucketUtilization{
+		Count:      si.data.Buckets[toBucket(si.BucketDepth(), addr)],
+		UpperBound: si.BucketUpperBound(),
+		Buckets:    uint32(len(si.data.Buckets)),
+	}
+}
+
 func (si *StampIssuer) Buckets() []uint32 {
 	si.bucketMu.Lock()
 	b := make([]uint32, len(si.data.Buckets))
@@ -0,0 +1,207 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package prewarm fetches all chunks of the content into the local store
+// in the background, so that the gateways can warm up the content ahead of
+// the expected traffic spike.
+package prewarm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+)
+
+const (
+	// DefaultConcurrency is the number of the chunks fetched in parallel if not requested.
+	DefaultConcurrency = 16
+	// MaxConcurrency is the maximal number of the chunks fetched in parallel.
+	MaxConcurrency = 64
+	// maxJobs is the number of the jobs kept, the oldest finished jobs are forgotten.
+	maxJobs = 1000
+)
+
+// ErrInvalidConcurrency is returned when the concurrency is out of the [1, MaxConcurrency] range.
+var ErrInvalidConcurrency = errors.New("invalid concurrency")
+
+// Progress is the progress of the prewarming of the content.
+type Progress struct {
+	Reference swarm.Address
+	// Chunks is the number of the chunks found by traversing the content so far.
+	Chunks int
+	// Fetched is the number of the chunks stored locally.
+	Fetched int
+	// Failed is the number of the chunks which could not be fetched.
+	Failed int
+	// Done reports whether the prewarming finished.
+	Done bool
+	// Err is the error which stopped the traversal of the content, if any.
+	Err        error
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+type job struct {
+	mu       sync.Mutex
+	progress Progress
+}
+
+func (j *job) get() Progress {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.progress
+}
+
+func (j *job) update(fn func(p *Progress)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn(&j.progress)
+}
+
+// Service prewarms the content and keeps the progress of the jobs.
+type Service struct {
+	getter    storage.Getter
+	traverser traversal.Traverser
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+// New returns a new Service which fetches the chunks with the getter,
+// which is expected to store the chunks retrieved from the network.
+func New(getter storage.Getter, traverser traversal.Traverser) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		getter:    getter,
+		traverser: traverser,
+		ctx:       ctx,
+		cancel:    cancel,
+		jobs:      make(map[string]*job),
+	}
+}
+
+// Start starts prewarming the content under the root reference with
+// at most concurrency chunks fetched in parallel. If the content is
+// being prewarmed already, the progress of the running job is returned.
+func (s *Service) Start(root swarm.Address, concurrency int) (Progress, error) {
+	if concurrency < 1 || concurrency > MaxConcurrency {
+		return Progress{}, ErrInvalidConcurrency
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if j, ok := s.jobs[root.ByteString()]; ok {
+		if p := j.get(); !p.Done {
+			return p, nil
+		}
+	}
+	s.evict()
+
+	j := &job{progress: Progress{
+		Reference: root,
+		StartedAt: time.Now(),
+	}}
+	s.jobs[root.ByteString()] = j
+
+	s.wg.Add(1)
+	go s.run(j, root, concurrency)
+
+	return j.get(), nil
+}
+
+// Progress returns the progress of the latest prewarming of the content.
+func (s *Service) Progress(root swarm.Address) (Progress, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[root.ByteString()]
+	if !ok {
+		return Progress{}, false
+	}
+	return j.get(), true
+}
+
+// evict forgets the oldest finished job when there are too many jobs.
+// It must be called with the lock held.
+func (s *Service) evict() {
+	if len(s.jobs) < maxJobs {
+		return
+	}
+	var (
+		oldest   string
+		finished time.Time
+	)
+	for key, j := range s.jobs {
+		p := j.get()
+		if p.Done && (finished.IsZero() || p.FinishedAt.Before(finished)) {
+			oldest, finished = key, p.FinishedAt
+		}
+	}
+	if !finished.IsZero() {
+		delete(s.jobs, oldest)
+	}
+}
+
+func (s *Service) run(j *job, root swarm.Address, concurrency int) {
+	defer s.wg.Done()
+
+	var (
+		ctx  = s.ctx
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, concurrency)
+		seen = make(map[string]struct{})
+	)
+	err := s.traverser.Traverse(ctx, root, func(addr swarm.Address) error {
+		if _, ok := seen[addr.ByteString()]; ok {
+			return nil
+		}
+		seen[addr.ByteString()] = struct{}{}
+		j.update(func(p *Progress) { p.Chunks++ })
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, err := s.getter.Get(ctx, storage.ModeGetRequest, addr)
+			j.update(func(p *Progress) {
+				if err != nil {
+					p.Failed++
+				} else {
+					p.Fetched++
+				}
+			})
+		}()
+		return nil
+	})
+	wg.Wait()
+
+	j.update(func(p *Progress) {
+		p.Done = true
+		p.Err = err
+		p.FinishedAt = time.Now()
+	})
+}
+
+// Close stops the running jobs and waits for them to finish.
+func (s *Service) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
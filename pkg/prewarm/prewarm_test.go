@@ -0,0 +1,191 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prewarm_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/prewarm"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+)
+
+// netGetter retrieves the chunks missing in the local store from the
+// remote store and stores them locally, except for the failing ones.
+type netGetter struct {
+	local, remote storage.Storer
+
+	mu      sync.Mutex
+	failing map[string]struct{}
+}
+
+func (g *netGetter) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	if ch, err := g.local.Get(ctx, mode, addr); err == nil {
+		return ch, nil
+	}
+	g.mu.Lock()
+	_, fail := g.failing[addr.ByteString()]
+	g.mu.Unlock()
+	if fail {
+		return nil, storage.ErrNotFound
+	}
+	ch, err := g.remote.Get(ctx, mode, addr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := g.local.Put(ctx, storage.ModePutRequest, ch); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (g *netGetter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	return g.local.Put(ctx, mode, chs...)
+}
+
+func waitDone(t *testing.T, s *prewarm.Service, root swarm.Address) prewarm.Progress {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		p, ok := s.Progress(root)
+		if !ok {
+			t.Fatalf("no progress of %s", root)
+		}
+		if p.Done {
+			return p
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("prewarming not done")
+	return prewarm.Progress{}
+}
+
+func TestPrewarm(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		remote = mock.NewStorer()
+		local  = mock.NewStorer()
+		data   = bytes.Repeat([]byte{1, 2, 3, 4}, 5*swarm.ChunkSize/4+7)
+	)
+	root, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, remote, storage.ModePutUpload, false), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the distinct chunks of the content: 5 identical full leaves,
+	// the leaf with the rest of the data and the root
+	const chunks = 3
+
+	getter := &netGetter{local: local, remote: remote, failing: make(map[string]struct{})}
+	s := prewarm.New(getter, traversal.New(getter))
+	t.Cleanup(func() { _ = s.Close() })
+
+	t.Run("invalid concurrency", func(t *testing.T) {
+		if _, err := s.Start(root, 0); !errors.Is(err, prewarm.ErrInvalidConcurrency) {
+			t.Fatalf("got error %v, want %v", err, prewarm.ErrInvalidConcurrency)
+		}
+		if _, ok := s.Progress(root); ok {
+			t.Fatal("unexpected progress")
+		}
+	})
+
+	t.Run("unknown content", func(t *testing.T) {
+		missing := swarm.MustParseHexAddress("aabbcc00000000000000000000000000000000000000000000000000000000ff")
+		if _, err := s.Start(missing, 1); err != nil {
+			t.Fatal(err)
+		}
+		p := waitDone(t, s, missing)
+		if !errors.Is(p.Err, storage.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", p.Err, storage.ErrNotFound)
+		}
+	})
+
+	t.Run("prewarm", func(t *testing.T) {
+		if _, err := s.Start(root, 2); err != nil {
+			t.Fatal(err)
+		}
+		p := waitDone(t, s, root)
+		if p.Err != nil {
+			t.Fatal(p.Err)
+		}
+		if p.Chunks != chunks || p.Fetched != chunks || p.Failed != 0 {
+			t.Fatalf("got %d chunks, %d fetched and %d failed, want %d fetched", p.Chunks, p.Fetched, p.Failed, chunks)
+		}
+
+		// the content is available without the network
+		if err := traversal.New(local).Traverse(ctx, root, func(addr swarm.Address) error {
+			_, err := local.Get(ctx, storage.ModeGetRequest, addr)
+			return err
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("failed chunks", func(t *testing.T) {
+		// the chunks of the files are not read by traversing the manifest
+		file, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, remote, storage.ModePutUpload, false), bytes.NewReader(bytes.Repeat([]byte{5}, 2*swarm.ChunkSize+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := manifest.NewDefaultManifest(loadsave.New(remote, func() pipeline.Interface {
+			return builder.NewPipelineBuilder(ctx, remote, storage.ModePutUpload, false)
+		}), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Add(ctx, "file", manifest.NewEntry(file, nil)); err != nil {
+			t.Fatal(err)
+		}
+		root, err := m.Store(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var (
+			leaf  swarm.Address
+			total = make(map[string]struct{})
+		)
+		if err := traversal.New(remote).Traverse(ctx, root, func(addr swarm.Address) error {
+			total[addr.ByteString()] = struct{}{}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := traversal.New(remote).Traverse(ctx, file, func(addr swarm.Address) error {
+			if !addr.Equal(file) {
+				leaf = addr
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		getter.mu.Lock()
+		getter.failing[leaf.ByteString()] = struct{}{}
+		getter.mu.Unlock()
+
+		if _, err := s.Start(root, prewarm.DefaultConcurrency); err != nil {
+			t.Fatal(err)
+		}
+		p := waitDone(t, s, root)
+		if p.Err != nil {
+			t.Fatal(p.Err)
+		}
+		if p.Chunks != len(total) || p.Fetched != len(total)-1 || p.Failed != 1 {
+			t.Fatalf("got %d chunks, %d fetched and %d failed, want %d chunks and 1 failed", p.Chunks, p.Fetched, p.Failed, len(total))
+		}
+	})
+}
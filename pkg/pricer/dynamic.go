@@ -0,0 +1,227 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pricer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "pricer"
+
+const (
+	// MaxPriceFactor bounds the price per proximity order the peers may
+	// announce to at most MaxPriceFactor times the base price.
+	MaxPriceFactor = 4
+	// PressureThreshold is the pressure above which the price rises
+	// linearly from the base price up to the maximum price.
+	PressureThreshold = 0.5
+	// priceSteps is the number of the steps between the base and the
+	// maximum price, so that the small changes in the pressure do not
+	// flood the peers with the price announcements.
+	priceSteps = 12
+
+	defaultUpdateInterval = time.Minute
+	announceTimeout       = time.Minute
+)
+
+// ErrPriceOutOfBounds is returned when the peer announces a price
+// outside of the bounds derived from the base price.
+var ErrPriceOutOfBounds = errors.New("price out of bounds")
+
+// PressureFunc returns the pressure on the local resources, between
+// 0 for an idle node and 1 for a node without any spare capacity.
+type PressureFunc func() (float64, error)
+
+// DynamicOptions are the options of the DynamicPricer.
+type DynamicOptions struct {
+	// Pressure drives the price we charge. The price stays at the base
+	// price if nil, while the prices announced by the peers are still used.
+	Pressure PressureFunc
+	// UpdateInterval is the interval of the price updates.
+	UpdateInterval time.Duration
+	// Announce is called after our price changed, to let the peers know.
+	// The peers are charged the new price once they acknowledge it.
+	Announce func(context.Context) error
+}
+
+var _ Interface = (*DynamicPricer)(nil)
+
+// DynamicPricer is a Pricer that adjusts the price we charge to the
+// pressure on the local resources, and uses the prices announced by
+// the peers for the chunks they serve. The peers are charged the price
+// they acknowledged, and the base price until they do, so that the peers
+// without the dynamic pricing keep accounting the chunks as we do.
+type DynamicPricer struct {
+	overlay   swarm.Address
+	basePrice uint64
+	maxPrice  uint64
+	logger    log.Logger
+	opts      DynamicOptions
+
+	poPrice atomic.Uint64
+
+	pricesMu       sync.RWMutex
+	peerPrices     map[string]uint64 // the prices announced by the peers
+	acceptedPrices map[string]uint64 // our prices acknowledged by the peers
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewDynamicPricer returns a new DynamicPricer with the given base price.
+// The price updates are started if the pressure function is set.
+func NewDynamicPricer(overlay swarm.Address, basePrice uint64, logger log.Logger, o DynamicOptions) *DynamicPricer {
+	if o.UpdateInterval == 0 {
+		o.UpdateInterval = defaultUpdateInterval
+	}
+	p := &DynamicPricer{
+		overlay:        overlay,
+		basePrice:      basePrice,
+		maxPrice:       basePrice * MaxPriceFactor,
+		logger:         logger.WithName(loggerName).Register(),
+		opts:           o,
+		peerPrices:     make(map[string]uint64),
+		acceptedPrices: make(map[string]uint64),
+		quit:           make(chan struct{}),
+	}
+	p.poPrice.Store(basePrice)
+
+	if o.Pressure != nil {
+		p.wg.Add(1)
+		go p.updateLoop()
+	}
+	return p
+}
+
+// PeerPrice implements Pricer.
+func (p *DynamicPricer) PeerPrice(peer, chunk swarm.Address) uint64 {
+	poPrice := p.basePrice
+	if peer.Equal(p.overlay) {
+		poPrice = p.poPrice.Load()
+	} else {
+		p.pricesMu.RLock()
+		if price, ok := p.peerPrices[peer.ByteString()]; ok {
+			poPrice = price
+		}
+		p.pricesMu.RUnlock()
+	}
+	return uint64(swarm.MaxPO-swarm.Proximity(peer.Bytes(), chunk.Bytes())+1) * poPrice
+}
+
+// Price implements Pricer.
+func (p *DynamicPricer) Price(peer, chunk swarm.Address) uint64 {
+	poPrice := p.basePrice
+	p.pricesMu.RLock()
+	if price, ok := p.acceptedPrices[peer.ByteString()]; ok {
+		poPrice = price
+	}
+	p.pricesMu.RUnlock()
+	return uint64(swarm.MaxPO-swarm.Proximity(p.overlay.Bytes(), chunk.Bytes())+1) * poPrice
+}
+
+// PoPrice returns the price per proximity order we announce to the peers.
+func (p *DynamicPricer) PoPrice() uint64 {
+	return p.poPrice.Load()
+}
+
+// NotifyPeerPrice sets the price per proximity order announced by the peer.
+func (p *DynamicPricer) NotifyPeerPrice(peer swarm.Address, poPrice uint64) error {
+	if poPrice < p.basePrice || poPrice > p.maxPrice {
+		return ErrPriceOutOfBounds
+	}
+	p.pricesMu.Lock()
+	p.peerPrices[peer.ByteString()] = poPrice
+	p.pricesMu.Unlock()
+	return nil
+}
+
+// NotifyPriceAccepted sets the price per proximity order we charge
+// the peer, after the peer acknowledged our announcement of it.
+func (p *DynamicPricer) NotifyPriceAccepted(peer swarm.Address, poPrice uint64) {
+	p.pricesMu.Lock()
+	p.acceptedPrices[peer.ByteString()] = poPrice
+	p.pricesMu.Unlock()
+}
+
+// ForgetPeerPrice drops the prices exchanged with the disconnected peer.
+func (p *DynamicPricer) ForgetPeerPrice(peer swarm.Address) {
+	p.pricesMu.Lock()
+	delete(p.peerPrices, peer.ByteString())
+	delete(p.acceptedPrices, peer.ByteString())
+	p.pricesMu.Unlock()
+}
+
+// Update recomputes our price from the current pressure
+// and announces it to the peers if it changed.
+func (p *DynamicPricer) Update(ctx context.Context) error {
+	if p.opts.Pressure == nil {
+		return nil
+	}
+
+	pressure, err := p.opts.Pressure()
+	if err != nil {
+		return err
+	}
+
+	price := p.priceAt(pressure)
+	if p.poPrice.Swap(price) == price {
+		return nil
+	}
+	p.logger.Debug("price updated", "pressure", pressure, "po_price", price)
+
+	if p.opts.Announce == nil {
+		return nil
+	}
+	return p.opts.Announce(ctx)
+}
+
+// priceAt returns the price per proximity order at the pressure,
+// rounded down to the nearest step between the base and the max price.
+func (p *DynamicPricer) priceAt(pressure float64) uint64 {
+	if pressure <= PressureThreshold {
+		return p.basePrice
+	}
+	if pressure > 1 {
+		pressure = 1
+	}
+	step := uint64((pressure - PressureThreshold) / (1 - PressureThreshold) * priceSteps)
+	return p.basePrice + (p.maxPrice-p.basePrice)*step/priceSteps
+}
+
+func (p *DynamicPricer) updateLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.opts.UpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), announceTimeout)
+		if err := p.Update(ctx); err != nil {
+			p.logger.Debug("price update failed", "error", err)
+		}
+		cancel()
+	}
+}
+
+// Close stops the price updates.
+func (p *DynamicPricer) Close() error {
+	close(p.quit)
+	p.wg.Wait()
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pricer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/pricer"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestDynamicPricer(t *testing.T) {
+	t.Parallel()
+
+	const basePrice = 10000
+
+	var (
+		overlay   = swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000")
+		peer      = swarm.MustParseHexAddress("8000000000000000000000000000000000000000000000000000000000000000")
+		chunk     = swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000001")
+		pressure  float64
+		announced int
+	)
+
+	p := pricer.NewDynamicPricer(overlay, basePrice, log.Noop, pricer.DynamicOptions{
+		Pressure: func() (float64, error) { return pressure, nil },
+		Announce: func(context.Context) error {
+			announced++
+			return nil
+		},
+	})
+	t.Cleanup(func() { _ = p.Close() })
+
+	// the chunk is at the maximum proximity order from the overlay
+	if got := p.Price(peer, chunk); got != basePrice {
+		t.Fatalf("got price %d, want %d", got, basePrice)
+	}
+
+	for _, tc := range []struct {
+		pressure  float64
+		poPrice   uint64
+		announced int
+	}{
+		{0.3, basePrice, 0},
+		{0.5, basePrice, 0},
+		{0.75, 2.5 * basePrice, 1},
+		{0.76, 2.5 * basePrice, 1}, // within the same price step
+		{1, pricer.MaxPriceFactor * basePrice, 2},
+		{1.5, pricer.MaxPriceFactor * basePrice, 2},
+		{0, basePrice, 3},
+	} {
+		pressure = tc.pressure
+		if err := p.Update(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := p.PoPrice(); got != tc.poPrice {
+			t.Fatalf("pressure %v: got po price %d, want %d", tc.pressure, got, tc.poPrice)
+		}
+		if announced != tc.announced {
+			t.Fatalf("pressure %v: got %d announcements, want %d", tc.pressure, announced, tc.announced)
+		}
+	}
+
+	// the peer is at proximity order 0 from the chunk
+	if got := p.PeerPrice(peer, chunk); got != uint64(swarm.MaxPO+1)*basePrice {
+		t.Fatalf("got peer price %d, want %d", got, uint64(swarm.MaxPO+1)*basePrice)
+	}
+	if err := p.NotifyPeerPrice(peer, 2*basePrice); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.PeerPrice(peer, chunk); got != uint64(swarm.MaxPO+1)*2*basePrice {
+		t.Fatalf("got peer price %d, want %d", got, uint64(swarm.MaxPO+1)*2*basePrice)
+	}
+
+	for _, price := range []uint64{basePrice - 1, pricer.MaxPriceFactor*basePrice + 1} {
+		if err := p.NotifyPeerPrice(peer, price); !errors.Is(err, pricer.ErrPriceOutOfBounds) {
+			t.Fatalf("price %d: got error %v, want %v", price, err, pricer.ErrPriceOutOfBounds)
+		}
+	}
+
+	// the peer is charged the new price only after it acknowledged it
+	pressure = 1
+	if err := p.Update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Price(peer, chunk); got != basePrice {
+		t.Fatalf("got price %d before the acknowledgement, want %d", got, basePrice)
+	}
+	p.NotifyPriceAccepted(peer, p.PoPrice())
+	if got := p.Price(peer, chunk); got != pricer.MaxPriceFactor*basePrice {
+		t.Fatalf("got price %d after the acknowledgement, want %d", got, pricer.MaxPriceFactor*basePrice)
+	}
+
+	p.ForgetPeerPrice(peer)
+	if got := p.PeerPrice(peer, chunk); got != uint64(swarm.MaxPO+1)*basePrice {
+		t.Fatalf("got peer price %d after forgetting the peer, want %d", got, uint64(swarm.MaxPO+1)*basePrice)
+	}
+	if got := p.Price(peer, chunk); got != basePrice {
+		t.Fatalf("got price %d after forgetting the peer, want %d", got, basePrice)
+	}
+}
+
+func TestMaxPressure(t *testing.T) {
+	t.Parallel()
+
+	size := uint64(60)
+	pressure := pricer.MaxPressure(
+		pricer.ReservePressure(func() (uint64, error) { return size, nil }, 100),
+		func() (float64, error) { return 0.4, nil },
+	)
+
+	got, err := pressure()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 0.6 {
+		t.Fatalf("got pressure %v, want 0.6", got)
+	}
+
+	size = 10
+	if got, _ = pressure(); got != 0.4 {
+		t.Fatalf("got pressure %v, want 0.4", got)
+	}
+}
@@ -24,6 +24,6 @@ func (pricer *MockPricer) PeerPrice(peer, chunk swarm.Address) uint64 {
 	return pricer.peerPrice
 }
 
-func (pricer *MockPricer) Price(chunk swarm.Address) uint64 {
+func (pricer *MockPricer) Price(peer, chunk swarm.Address) uint64 {
 	return pricer.price
 }
@@ -0,0 +1,53 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pricer
+
+import (
+	"github.com/shirou/gopsutil/disk"
+)
+
+// DiskPressure returns the PressureFunc of the used
+// space of the filesystem the path is located on.
+func DiskPressure(path string) PressureFunc {
+	return func() (float64, error) {
+		usage, err := disk.Usage(path)
+		if err != nil {
+			return 0, err
+		}
+		return usage.UsedPercent / 100, nil
+	}
+}
+
+// ReservePressure returns the PressureFunc of the
+// reserve utilization of the node.
+func ReservePressure(size func() (uint64, error), capacity uint64) PressureFunc {
+	return func() (float64, error) {
+		if capacity == 0 {
+			return 0, nil
+		}
+		s, err := size()
+		if err != nil {
+			return 0, err
+		}
+		return float64(s) / float64(capacity), nil
+	}
+}
+
+// MaxPressure returns the PressureFunc of the highest of the pressures.
+func MaxPressure(fns ...PressureFunc) PressureFunc {
+	return func() (float64, error) {
+		var max float64
+		for _, fn := range fns {
+			p, err := fn()
+			if err != nil {
+				return 0, err
+			}
+			if p > max {
+				max = p
+			}
+		}
+		return max, nil
+	}
+}
@@ -13,3 +13,7 @@ import (
 func (s *Service) Init(ctx context.Context, p p2p.Peer) error {
 	return s.init(ctx, p)
 }
+
+func (s *Service) Disconnect(p p2p.Peer) error {
+	return s.disconnect(p)
+}
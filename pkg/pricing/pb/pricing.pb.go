@@ -24,6 +24,8 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type AnnouncePaymentThreshold struct {
 	PaymentThreshold []byte `protobuf:"bytes,1,opt,name=PaymentThreshold,proto3" json:"PaymentThreshold,omitempty"`
+	PoPrice          uint64 `protobuf:"varint,2,opt,name=PoPrice,proto3" json:"PoPrice,omitempty"`
+	DynamicPricing   bool   `protobuf:"varint,3,opt,name=DynamicPricing,proto3" json:"DynamicPricing,omitempty"`
 }
 
 func (m *AnnouncePaymentThreshold) Reset()         { *m = AnnouncePaymentThreshold{} }
@@ -66,22 +68,85 @@ func (m *AnnouncePaymentThreshold) GetPaymentThreshold() []byte {
 	return nil
 }
 
+func (m *AnnouncePaymentThreshold) GetPoPrice() uint64 {
+	if m != nil {
+		return m.PoPrice
+	}
+	return 0
+}
+
+func (m *AnnouncePaymentThreshold) GetDynamicPricing() bool {
+	if m != nil {
+		return m.DynamicPricing
+	}
+	return false
+}
+
+type PriceAck struct {
+	PoPrice uint64 `protobuf:"varint,1,opt,name=PoPrice,proto3" json:"PoPrice,omitempty"`
+}
+
+func (m *PriceAck) Reset()         { *m = PriceAck{} }
+func (m *PriceAck) String() string { return proto.CompactTextString(m) }
+func (*PriceAck) ProtoMessage()    {}
+func (*PriceAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ec4cc93d045d43d0, []int{1}
+}
+func (m *PriceAck) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PriceAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PriceAck.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PriceAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PriceAck.Merge(m, src)
+}
+func (m *PriceAck) XXX_Size() int {
+	return m.Size()
+}
+func (m *PriceAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_PriceAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PriceAck proto.InternalMessageInfo
+
+func (m *PriceAck) GetPoPrice() uint64 {
+	if m != nil {
+		return m.PoPrice
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*AnnouncePaymentThreshold)(nil), "pricing.AnnouncePaymentThreshold")
+	proto.RegisterType((*PriceAck)(nil), "pricing.PriceAck")
 }
 
 func init() { proto.RegisterFile("pricing.proto", fileDescriptor_ec4cc93d045d43d0) }
 
 var fileDescriptor_ec4cc93d045d43d0 = []byte{
-	// 122 bytes of a gzipped FileDescriptorProto
+	// 181 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2d, 0x28, 0xca, 0x4c,
-	0xce, 0xcc, 0x4b, 0xd7, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x87, 0x72, 0x95, 0xdc, 0xb8,
-	0x24, 0x1c, 0xf3, 0xf2, 0xf2, 0x4b, 0xf3, 0x92, 0x53, 0x03, 0x12, 0x2b, 0x73, 0x53, 0xf3, 0x4a,
-	0x42, 0x32, 0x8a, 0x52, 0x8b, 0x33, 0xf2, 0x73, 0x52, 0x84, 0xb4, 0xb8, 0x04, 0xd0, 0xc5, 0x24,
-	0x18, 0x15, 0x18, 0x35, 0x78, 0x82, 0x30, 0xc4, 0x9d, 0x64, 0x4e, 0x3c, 0x92, 0x63, 0xbc, 0xf0,
-	0x48, 0x8e, 0xf1, 0xc1, 0x23, 0x39, 0xc6, 0x09, 0x8f, 0xe5, 0x18, 0x2e, 0x3c, 0x96, 0x63, 0xb8,
-	0xf1, 0x58, 0x8e, 0x21, 0x8a, 0xa9, 0x20, 0x29, 0x89, 0x0d, 0x6c, 0xab, 0x31, 0x20, 0x00, 0x00,
-	0xff, 0xff, 0x50, 0xca, 0x0e, 0x0a, 0x86, 0x00, 0x00, 0x00,
+	0xce, 0xcc, 0x4b, 0xd7, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x87, 0x72, 0x95, 0x3a, 0x18,
+	0xb9, 0x24, 0x1c, 0xf3, 0xf2, 0xf2, 0x4b, 0xf3, 0x92, 0x53, 0x03, 0x12, 0x2b, 0x73, 0x53, 0xf3,
+	0x4a, 0x42, 0x32, 0x8a, 0x52, 0x8b, 0x33, 0xf2, 0x73, 0x52, 0x84, 0xb4, 0xb8, 0x04, 0xd0, 0xc5,
+	0x24, 0x18, 0x15, 0x18, 0x35, 0x78, 0x82, 0x30, 0xc4, 0x85, 0x24, 0xb8, 0xd8, 0x03, 0xf2, 0x03,
+	0x8a, 0x32, 0x93, 0x53, 0x25, 0x98, 0x14, 0x18, 0x35, 0x58, 0x82, 0x60, 0x5c, 0x21, 0x35, 0x2e,
+	0x3e, 0x97, 0xca, 0xbc, 0xc4, 0xdc, 0xcc, 0xe4, 0x00, 0x88, 0xa5, 0x12, 0xcc, 0x0a, 0x8c, 0x1a,
+	0x1c, 0x41, 0x68, 0xa2, 0x4a, 0x2a, 0x5c, 0x1c, 0x60, 0x0d, 0x8e, 0xc9, 0xd9, 0xc8, 0xa6, 0x31,
+	0xa2, 0x98, 0xe6, 0x24, 0x73, 0xe2, 0x91, 0x1c, 0xe3, 0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9,
+	0x31, 0x4e, 0x78, 0x2c, 0xc7, 0x70, 0xe1, 0xb1, 0x1c, 0xc3, 0x8d, 0xc7, 0x72, 0x0c, 0x51, 0x4c,
+	0x05, 0x49, 0x49, 0x6c, 0x60, 0xef, 0x19, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0xfb, 0xc4, 0x62,
+	0x4c, 0xef, 0x00, 0x00, 0x00,
 }
 
 func (m *AnnouncePaymentThreshold) Marshal() (dAtA []byte, err error) {
@@ -104,6 +169,21 @@ func (m *AnnouncePaymentThreshold) MarshalToSizedBuffer(dAtA []byte) (int, error
 	_ = i
 	var l int
 	_ = l
+	if m.DynamicPricing {
+		i--
+		if m.DynamicPricing {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.PoPrice != 0 {
+		i = encodeVarintPricing(dAtA, i, uint64(m.PoPrice))
+		i--
+		dAtA[i] = 0x10
+	}
 	if len(m.PaymentThreshold) > 0 {
 		i -= len(m.PaymentThreshold)
 		copy(dAtA[i:], m.PaymentThreshold)
@@ -114,6 +194,34 @@ func (m *AnnouncePaymentThreshold) MarshalToSizedBuffer(dAtA []byte) (int, error
 	return len(dAtA) - i, nil
 }
 
+func (m *PriceAck) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PriceAck) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PriceAck) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.PoPrice != 0 {
+		i = encodeVarintPricing(dAtA, i, uint64(m.PoPrice))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintPricing(dAtA []byte, offset int, v uint64) int {
 	offset -= sovPricing(v)
 	base := offset
@@ -135,6 +243,24 @@ func (m *AnnouncePaymentThreshold) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPricing(uint64(l))
 	}
+	if m.PoPrice != 0 {
+		n += 1 + sovPricing(uint64(m.PoPrice))
+	}
+	if m.DynamicPricing {
+		n += 2
+	}
+	return n
+}
+
+func (m *PriceAck) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.PoPrice != 0 {
+		n += 1 + sovPricing(uint64(m.PoPrice))
+	}
 	return n
 }
 
@@ -207,16 +333,121 @@ func (m *AnnouncePaymentThreshold) Unmarshal(dAtA []byte) error {
 				m.PaymentThreshold = []byte{}
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PoPrice", wireType)
+			}
+			m.PoPrice = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPricing
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PoPrice |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DynamicPricing", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPricing
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DynamicPricing = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPricing(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthPricing
 			}
-			if (iNdEx + skippy) < 0 {
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PriceAck) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPricing
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PriceAck: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PriceAck: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PoPrice", wireType)
+			}
+			m.PoPrice = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPricing
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PoPrice |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPricing(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthPricing
 			}
 			if (iNdEx + skippy) > l {
@@ -8,7 +8,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/log"
@@ -44,6 +46,18 @@ type PaymentThresholdObserver interface {
 	NotifyPaymentThreshold(peer swarm.Address, paymentThreshold *big.Int) error
 }
 
+// PriceObserver provides the price per proximity order announced to the
+// peers and is notified of the prices announced by the peers. The prices are
+// only exchanged with the peers which support the dynamic pricing, and the
+// announced price is accepted once the peer acknowledges it, so that both
+// sides account the chunks with the same price.
+type PriceObserver interface {
+	PoPrice() uint64
+	NotifyPeerPrice(peer swarm.Address, poPrice uint64) error
+	NotifyPriceAccepted(peer swarm.Address, poPrice uint64)
+	ForgetPeerPrice(peer swarm.Address)
+}
+
 type Service struct {
 	streamer                 p2p.Streamer
 	logger                   log.Logger
@@ -51,6 +65,10 @@ type Service struct {
 	lightPaymentThreshold    *big.Int
 	minPaymentThreshold      *big.Int
 	paymentThresholdObserver PaymentThresholdObserver
+	priceObserver            PriceObserver
+
+	peersMu sync.Mutex
+	peers   map[string]p2p.Peer // connected peers, to announce the price updates to
 }
 
 func New(streamer p2p.Streamer, logger log.Logger, paymentThreshold, lightPaymentThreshold, minThreshold *big.Int) *Service {
@@ -60,6 +78,7 @@ func New(streamer p2p.Streamer, logger log.Logger, paymentThreshold, lightPaymen
 		paymentThreshold:      paymentThreshold,
 		lightPaymentThreshold: lightPaymentThreshold,
 		minPaymentThreshold:   minThreshold,
+		peers:                 make(map[string]p2p.Peer),
 	}
 }
 
@@ -73,8 +92,10 @@ func (s *Service) Protocol() p2p.ProtocolSpec {
 				Handler: s.handler,
 			},
 		},
-		ConnectIn:  s.init,
-		ConnectOut: s.init,
+		ConnectIn:     s.init,
+		ConnectOut:    s.init,
+		DisconnectIn:  s.disconnect,
+		DisconnectOut: s.disconnect,
 	}
 }
 
@@ -104,28 +125,103 @@ func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (e
 		return p2p.NewDisconnectError(ErrThresholdTooLow)
 	}
 
-	if paymentThreshold.Cmp(big.NewInt(0)) == 0 {
-		return err
+	// the price of the peers without the dynamic pricing is ignored,
+	// as they keep charging the base price regardless of it
+	dynamicPricing := req.DynamicPricing && s.priceObserver != nil
+	if dynamicPricing {
+		loggerV1.Debug("received price announcement from peer", "peer_address", p.Address, "po_price", req.PoPrice)
+		if err := s.priceObserver.NotifyPeerPrice(p.Address, req.PoPrice); err != nil {
+			loggerV1.Debug("price from peer rejected", "peer_address", p.Address, "po_price", req.PoPrice, "error", err)
+			return p2p.NewDisconnectError(err)
+		}
 	}
-	return s.paymentThresholdObserver.NotifyPaymentThreshold(p.Address, paymentThreshold)
-}
 
-func (s *Service) init(ctx context.Context, p p2p.Peer) error {
+	if paymentThreshold.Cmp(big.NewInt(0)) != 0 {
+		if err := s.paymentThresholdObserver.NotifyPaymentThreshold(p.Address, paymentThreshold); err != nil {
+			return err
+		}
+	}
 
-	threshold := s.paymentThreshold
-	if !p.FullNode {
-		threshold = s.lightPaymentThreshold
+	if !dynamicPricing {
+		return nil
 	}
+	// the peer starts charging the price once it is acknowledged
+	w := protobuf.NewWriter(stream)
+	if err := w.WriteMsgWithContext(ctx, &pb.PriceAck{PoPrice: req.PoPrice}); err != nil {
+		return fmt.Errorf("write price acknowledgement to peer %v: %w", p.Address, err)
+	}
+	return nil
+}
 
-	err := s.AnnouncePaymentThreshold(ctx, p.Address, threshold)
+func (s *Service) init(ctx context.Context, p p2p.Peer) error {
+	s.peersMu.Lock()
+	s.peers[p.Address.ByteString()] = p
+	s.peersMu.Unlock()
+
+	err := s.AnnouncePaymentThreshold(ctx, p.Address, s.threshold(p))
 	if err != nil {
 		s.logger.Warning("could not send payment threshold announcement to peer", "peer_address", p.Address)
 	}
 	return err
 }
 
+func (s *Service) disconnect(p p2p.Peer) error {
+	s.peersMu.Lock()
+	delete(s.peers, p.Address.ByteString())
+	s.peersMu.Unlock()
+
+	if s.priceObserver != nil {
+		s.priceObserver.ForgetPeerPrice(p.Address)
+	}
+	return nil
+}
+
+// threshold returns the payment threshold announced to the peer.
+func (s *Service) threshold(p p2p.Peer) *big.Int {
+	if !p.FullNode {
+		return s.lightPaymentThreshold
+	}
+	return s.paymentThreshold
+}
+
+// AnnouncePrice announces the payment threshold together with
+// the current price to all connected peers, after the price changed.
+func (s *Service) AnnouncePrice(ctx context.Context) error {
+	s.peersMu.Lock()
+	peers := make([]p2p.Peer, 0, len(s.peers))
+	for _, p := range s.peers {
+		peers = append(peers, p)
+	}
+	s.peersMu.Unlock()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+	)
+	for _, p := range peers {
+		p := p
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.AnnouncePaymentThreshold(ctx, p.Address, s.threshold(p)); err != nil {
+				s.logger.Debug("could not send price announcement to peer", "peer_address", p.Address, "error", err)
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("price announcement failed for %d of %d peers", failed, len(peers))
+	}
+	return nil
+}
+
 // AnnouncePaymentThreshold announces the payment threshold to per
-func (s *Service) AnnouncePaymentThreshold(ctx context.Context, peer swarm.Address, paymentThreshold *big.Int) error {
+func (s *Service) AnnouncePaymentThreshold(ctx context.Context, peer swarm.Address, paymentThreshold *big.Int) (err error) {
 	loggerV1 := s.logger.V(1).Register()
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -144,15 +240,43 @@ func (s *Service) AnnouncePaymentThreshold(ctx context.Context, peer swarm.Addre
 	}()
 
 	loggerV1.Debug("sending payment threshold announcement to peer", "peer_address", peer, "payment_threshold", paymentThreshold)
-	w := protobuf.NewWriter(stream)
-	err = w.WriteMsgWithContext(ctx, &pb.AnnouncePaymentThreshold{
+	w, r := protobuf.NewWriterAndReader(stream)
+	msg := &pb.AnnouncePaymentThreshold{
 		PaymentThreshold: paymentThreshold.Bytes(),
-	})
+	}
+	if s.priceObserver != nil {
+		msg.PoPrice = s.priceObserver.PoPrice()
+		msg.DynamicPricing = true
+	}
+	err = w.WriteMsgWithContext(ctx, msg)
+	if err != nil || s.priceObserver == nil {
+		return err
+	}
 
-	return err
+	// the peers without the dynamic pricing close the stream without
+	// the acknowledgement and keep being charged the base price
+	var ack pb.PriceAck
+	if err := r.ReadMsgWithContext(ctx, &ack); err != nil {
+		if errors.Is(err, io.EOF) {
+			loggerV1.Debug("peer does not support the dynamic pricing", "peer_address", peer)
+			return nil
+		}
+		return fmt.Errorf("read price acknowledgement from peer %v: %w", peer, err)
+	}
+	if ack.PoPrice != msg.PoPrice {
+		return fmt.Errorf("peer %v acknowledged price %d, announced %d", peer, ack.PoPrice, msg.PoPrice)
+	}
+	s.priceObserver.NotifyPriceAccepted(peer, ack.PoPrice)
+	return nil
 }
 
 // SetPaymentThresholdObserver sets the PaymentThresholdObserver to be used when receiving a new payment threshold
 func (s *Service) SetPaymentThresholdObserver(observer PaymentThresholdObserver) {
 	s.paymentThresholdObserver = observer
 }
+
+// SetPriceObserver sets the PriceObserver to be used when announcing the price
+// and when receiving a new price from the peers.
+func (s *Service) SetPriceObserver(observer PriceObserver) {
+	s.priceObserver = observer
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sync"
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/log"
@@ -296,3 +297,245 @@ func TestInitialPaymentThresholdLightNode(t *testing.T) {
 		t.Fatalf("observer called with wrong peer, got %v, want %v", observer.peer, peerID)
 	}
 }
+
+type testPriceObserver struct {
+	mu        sync.Mutex
+	poPrice   uint64
+	prices    map[string]uint64
+	accepted  map[string]uint64
+	forgotten []swarm.Address
+	err       error
+}
+
+func (t *testPriceObserver) PoPrice() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.poPrice
+}
+
+func (t *testPriceObserver) NotifyPeerPrice(peer swarm.Address, poPrice uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return t.err
+	}
+	t.prices[peer.ByteString()] = poPrice
+	return nil
+}
+
+func (t *testPriceObserver) NotifyPriceAccepted(peer swarm.Address, poPrice uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.accepted[peer.ByteString()] = poPrice
+}
+
+func (t *testPriceObserver) ForgetPeerPrice(peer swarm.Address) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.forgotten = append(t.forgotten, peer)
+}
+
+func (t *testPriceObserver) price(peer swarm.Address) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.prices[peer.ByteString()]
+}
+
+func (t *testPriceObserver) acceptedPrice(peer swarm.Address) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.accepted[peer.ByteString()]
+}
+
+func newTestPriceObserver(poPrice uint64) *testPriceObserver {
+	return &testPriceObserver{
+		poPrice:  poPrice,
+		prices:   make(map[string]uint64),
+		accepted: make(map[string]uint64),
+	}
+}
+
+func TestAnnouncePrice(t *testing.T) {
+	t.Parallel()
+
+	logger := log.Noop
+	testThreshold := big.NewInt(100000)
+	testLightThreshold := big.NewInt(10000)
+
+	recipientObserver := newTestPriceObserver(0)
+	recipient := pricing.New(nil, logger, testThreshold, testLightThreshold, big.NewInt(1000))
+	recipient.SetPaymentThresholdObserver(&testThresholdObserver{})
+	recipient.SetPriceObserver(recipientObserver)
+
+	peerID := swarm.MustParseHexAddress("9ee7add7")
+	peer := p2p.Peer{Address: peerID, FullNode: true}
+
+	recorder := streamtest.New(
+		streamtest.WithProtocols(recipient.Protocol()),
+		streamtest.WithBaseAddr(peerID),
+	)
+
+	payerObserver := newTestPriceObserver(10000)
+	payer := pricing.New(recorder, logger, testThreshold, testLightThreshold, big.NewInt(1000))
+	payer.SetPriceObserver(payerObserver)
+
+	if err := payer.Init(context.Background(), peer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.Records(peerID, "pricing", "1.0.0", "pricing"); err != nil {
+		t.Fatal(err)
+	}
+	if got := recipientObserver.price(peerID); got != 10000 {
+		t.Fatalf("got price %d, want %d", got, 10000)
+	}
+	if got := payerObserver.acceptedPrice(peerID); got != 10000 {
+		t.Fatalf("got accepted price %d, want %d", got, 10000)
+	}
+
+	// the price changed mid-session
+	payerObserver.mu.Lock()
+	payerObserver.poPrice = 20000
+	payerObserver.mu.Unlock()
+
+	if err := payer.AnnouncePrice(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	records, err := recorder.Records(peerID, "pricing", "1.0.0", "pricing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recipientObserver.price(peerID); got != 20000 {
+		t.Fatalf("got price %d, want %d", got, 20000)
+	}
+	if got := payerObserver.acceptedPrice(peerID); got != 20000 {
+		t.Fatalf("got accepted price %d, want %d", got, 20000)
+	}
+	if l := len(records); l != 2 {
+		t.Fatalf("got %v records, want %v", l, 2)
+	}
+	messages, err := protobuf.ReadMessages(
+		bytes.NewReader(records[1].In()),
+		func() protobuf.Message { return new(pb.AnnouncePaymentThreshold) },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := messages[0].(*pb.AnnouncePaymentThreshold)
+	if sent := big.NewInt(0).SetBytes(msg.PaymentThreshold); sent.Cmp(testThreshold) != 0 {
+		t.Fatalf("got message with amount %v, want %v", sent, testThreshold)
+	}
+
+	// the recipient rejects the price
+	errRejected := errors.New("rejected")
+	recipientObserver.mu.Lock()
+	recipientObserver.err = errRejected
+	recipientObserver.mu.Unlock()
+
+	if err := payer.AnnouncePrice(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	records, err = recorder.Records(peerID, "pricing", "1.0.0", "pricing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	disconnectErr := &p2p.DisconnectError{}
+	if err := records[2].Err(); !errors.As(err, &disconnectErr) || !errors.Is(err, errRejected) {
+		t.Fatalf("got error %v, want disconnect error %v", err, errRejected)
+	}
+
+	// the disconnected peer is no longer announced to
+	if err := payer.Disconnect(peer); err != nil {
+		t.Fatal(err)
+	}
+	if len(payerObserver.forgotten) != 1 || !payerObserver.forgotten[0].Equal(peerID) {
+		t.Fatalf("got forgotten peers %v, want %v", payerObserver.forgotten, peerID)
+	}
+	if err := payer.AnnouncePrice(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	records, err = recorder.Records(peerID, "pricing", "1.0.0", "pricing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := len(records); l != 3 {
+		t.Fatalf("got %v records, want %v", l, 3)
+	}
+}
+
+func TestAnnouncePriceWithoutDynamicPricing(t *testing.T) {
+	t.Parallel()
+
+	logger := log.Noop
+	testThreshold := big.NewInt(100000)
+	testLightThreshold := big.NewInt(10000)
+
+	peerID := swarm.MustParseHexAddress("9ee7add7")
+	peer := p2p.Peer{Address: peerID, FullNode: true}
+
+	t.Run("recipient", func(t *testing.T) {
+		t.Parallel()
+
+		recipient := pricing.New(nil, logger, testThreshold, testLightThreshold, big.NewInt(1000))
+		recipient.SetPaymentThresholdObserver(&testThresholdObserver{})
+
+		recorder := streamtest.New(
+			streamtest.WithProtocols(recipient.Protocol()),
+			streamtest.WithBaseAddr(peerID),
+		)
+
+		payerObserver := newTestPriceObserver(20000)
+		payer := pricing.New(recorder, logger, testThreshold, testLightThreshold, big.NewInt(1000))
+		payer.SetPriceObserver(payerObserver)
+
+		if err := payer.Init(context.Background(), peer); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := recorder.Records(peerID, "pricing", "1.0.0", "pricing"); err != nil {
+			t.Fatal(err)
+		}
+		// the peer keeps being charged the base price
+		if got := payerObserver.acceptedPrice(peerID); got != 0 {
+			t.Fatalf("got accepted price %d, want none", got)
+		}
+	})
+
+	t.Run("payer", func(t *testing.T) {
+		t.Parallel()
+
+		recipientObserver := newTestPriceObserver(0)
+		recipient := pricing.New(nil, logger, testThreshold, testLightThreshold, big.NewInt(1000))
+		recipient.SetPaymentThresholdObserver(&testThresholdObserver{})
+		recipient.SetPriceObserver(recipientObserver)
+
+		recorder := streamtest.New(
+			streamtest.WithProtocols(recipient.Protocol()),
+			streamtest.WithBaseAddr(peerID),
+		)
+
+		stream, err := recorder.NewStream(context.Background(), peerID, nil, "pricing", "1.0.0", "pricing")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := protobuf.NewWriter(stream)
+		if err := w.WriteMsg(&pb.AnnouncePaymentThreshold{
+			PaymentThreshold: testThreshold.Bytes(),
+			PoPrice:          20000,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		records, err := recorder.Records(peerID, "pricing", "1.0.0", "pricing")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := records[0].Err(); err != nil {
+			t.Fatal(err)
+		}
+		// the price is ignored and not acknowledged
+		if got := recipientObserver.price(peerID); got != 0 {
+			t.Fatalf("got price %d, want none", got)
+		}
+		if l := len(records[0].Out()); l != 0 {
+			t.Fatalf("got %d bytes of the response, want none", l)
+		}
+	})
+}
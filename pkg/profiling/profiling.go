@@ -0,0 +1,116 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package profiling captures the runtime profiles of the node on demand and
+// uploads them continuously to the pyroscope compatible profiling servers.
+// The CPU profiler of the runtime can be started only once at a time, so the
+// on-demand and the continuous CPU profiles are captured one after another.
+package profiling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/pprof"
+	"time"
+)
+
+// Kind is the kind of the profile.
+type Kind string
+
+const (
+	CPU       Kind = "cpu"
+	Heap      Kind = "heap"
+	Allocs    Kind = "allocs"
+	Block     Kind = "block"
+	Mutex     Kind = "mutex"
+	Goroutine Kind = "goroutine"
+)
+
+// Kinds are all the kinds of the profiles.
+var Kinds = []Kind{CPU, Heap, Allocs, Block, Mutex, Goroutine}
+
+const (
+	// DefaultCPUDuration is the default duration of the CPU profile.
+	DefaultCPUDuration = 30 * time.Second
+	// MaxCPUDuration is the maximal duration of the CPU profile.
+	MaxCPUDuration = 5 * time.Minute
+
+	// cpuWait is the time waited for the running CPU profile to stop. It is
+	// longer than the interval of the continuous profiles, so that they do
+	// not prevent the on-demand profiles.
+	cpuWait = 15 * time.Second
+)
+
+var (
+	// ErrUnknownKind is returned for the unknown kind of the profile.
+	ErrUnknownKind = errors.New("unknown profile kind")
+	// ErrInvalidDuration is returned if the duration
+	// of the CPU profile is not positive or too long.
+	ErrInvalidDuration = errors.New("invalid profile duration")
+	// ErrCaptureInProgress is returned if the other CPU profile
+	// is being captured for longer than the capture can wait.
+	ErrCaptureInProgress = errors.New("profile capture in progress")
+)
+
+// cpu is the semaphore of the CPU profiler of the runtime.
+var cpu = make(chan struct{}, 1)
+
+// Capture writes the profile of the kind in the pprof format to the writer.
+// The CPU profile is captured for the duration, the other profiles are the
+// snapshots of the runtime and the duration is ignored.
+func Capture(ctx context.Context, w io.Writer, kind Kind, duration time.Duration) error {
+	if !ValidKind(kind) {
+		return fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+	if kind != CPU {
+		return pprof.Lookup(string(kind)).WriteTo(w, 0)
+	}
+
+	if duration <= 0 || duration > MaxCPUDuration {
+		return fmt.Errorf("%w: %s", ErrInvalidDuration, duration)
+	}
+
+	timer := time.NewTimer(cpuWait)
+	defer timer.Stop()
+	select {
+	case cpu <- struct{}{}:
+	case <-timer.C:
+		return ErrCaptureInProgress
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-cpu }()
+
+	return captureCPU(ctx, w, duration)
+}
+
+// captureCPU writes the CPU profile of the duration to the
+// writer, it must be called with the CPU semaphore acquired.
+func captureCPU(ctx context.Context, w io.Writer, duration time.Duration) error {
+	if err := pprof.StartCPUProfile(w); err != nil {
+		return fmt.Errorf("start cpu profile: %w", err)
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	pprof.StopCPUProfile()
+
+	return ctx.Err()
+}
+
+// ValidKind reports whether the kind of the profile can be captured.
+func ValidKind(kind Kind) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profiling_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/profiling"
+)
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for _, kind := range []profiling.Kind{profiling.Heap, profiling.Goroutine, profiling.CPU} {
+		buf := new(bytes.Buffer)
+		if err := profiling.Capture(ctx, buf, kind, 100*time.Millisecond); err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		if buf.Len() == 0 {
+			t.Fatalf("%s: empty profile", kind)
+		}
+	}
+
+	if err := profiling.Capture(ctx, io.Discard, "threadcreate", 0); !errors.Is(err, profiling.ErrUnknownKind) {
+		t.Fatalf("got error %v, want %v", err, profiling.ErrUnknownKind)
+	}
+	if err := profiling.Capture(ctx, io.Discard, profiling.CPU, time.Hour); !errors.Is(err, profiling.ErrInvalidDuration) {
+		t.Fatalf("got error %v, want %v", err, profiling.ErrInvalidDuration)
+	}
+}
+
+func TestProfiler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		names = make(map[string]bool)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if r.URL.Path != "/ingest" || r.URL.Query().Get("format") != "pprof" || len(b) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		names[r.URL.Query().Get("name")] = true
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	p, err := profiling.NewProfiler(profiling.Options{
+		ServerAddress: server.URL,
+		AppName:       "bee",
+		Tags:          map[string]string{"network": "1", "overlay": "abcd"},
+		Interval:      100 * time.Millisecond,
+	}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"bee.cpu{network=1,overlay=abcd}", "bee.heap{network=1,overlay=abcd}"}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		done := names[want[0]] && names[want[1]]
+		mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("profiles %v not uploaded", want)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewProfilerInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	for _, o := range []profiling.Options{
+		{ServerAddress: "localhost", AppName: "bee"},
+		{ServerAddress: "http://localhost:4040"},
+	} {
+		if _, err := profiling.NewProfiler(o, log.Noop); err == nil {
+			t.Fatalf("options %+v: expected error", o)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "profiling"
+
+const (
+	// DefaultInterval is the default interval of the continuous profiles.
+	DefaultInterval = 10 * time.Second
+
+	uploadTimeout = 30 * time.Second
+	sampleRate    = 100 // Hz, the sampling rate of the CPU profiler of the runtime
+)
+
+// Options are the options of the continuous profiler.
+type Options struct {
+	// ServerAddress is the address of the pyroscope compatible server.
+	ServerAddress string
+	// AppName is the name of the application the profiles are uploaded for.
+	AppName string
+	// Tags are the tags of the uploaded profiles.
+	Tags map[string]string
+	// Interval is the interval of the profiles, DefaultInterval if not set.
+	Interval time.Duration
+	// Client is the HTTP client of the uploads, http.DefaultClient if not set.
+	Client *http.Client
+}
+
+// Profiler captures the CPU and the heap profiles of the node every
+// interval and uploads them to the ingest endpoint of the pyroscope
+// compatible server. The CPU profile of the interval is skipped if
+// the on-demand CPU profile is being captured.
+type Profiler struct {
+	logger   log.Logger
+	ingest   string
+	appName  string
+	tags     string
+	interval time.Duration
+	client   *http.Client
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewProfiler starts the continuous profiler of the options.
+func NewProfiler(o Options, logger log.Logger) (*Profiler, error) {
+	u, err := url.Parse(o.ServerAddress)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid profiling server address %q", o.ServerAddress)
+	}
+	if o.AppName == "" {
+		return nil, errors.New("profiling app name not set")
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	if o.Client == nil {
+		o.Client = http.DefaultClient
+	}
+
+	p := &Profiler{
+		logger:   logger.WithName(loggerName).Register(),
+		ingest:   strings.TrimSuffix(u.String(), "/") + "/ingest",
+		appName:  o.AppName,
+		tags:     formatTags(o.Tags),
+		interval: o.Interval,
+		client:   o.Client,
+		quit:     make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.run()
+
+	return p, nil
+}
+
+func (p *Profiler) run() {
+	defer p.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-p.quit
+		cancel()
+	}()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		from := time.Now()
+
+		cpuProfile := new(bytes.Buffer)
+		captured := false
+		select {
+		case cpu <- struct{}{}:
+			captured = captureCPU(ctx, cpuProfile, p.interval) == nil
+			<-cpu
+		default:
+			p.logger.Debug("cpu profile skipped, capture in progress")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		until := time.Now()
+
+		if captured {
+			if err := p.upload(ctx, "cpu", from, until, cpuProfile); err != nil {
+				p.logger.Debug("upload cpu profile failed", "error", err)
+			}
+		}
+
+		heapProfile := new(bytes.Buffer)
+		if err := Capture(ctx, heapProfile, Heap, 0); err != nil {
+			p.logger.Debug("capture heap profile failed", "error", err)
+			continue
+		}
+		if err := p.upload(ctx, "heap", from, until, heapProfile); err != nil {
+			p.logger.Debug("upload heap profile failed", "error", err)
+		}
+	}
+}
+
+// upload posts the profile of the time range to the ingest endpoint.
+func (p *Profiler) upload(ctx context.Context, name string, from, until time.Time, profile io.Reader) error {
+	q := url.Values{}
+	q.Set("name", p.appName+"."+name+p.tags)
+	q.Set("from", strconv.FormatInt(from.Unix(), 10))
+	q.Set("until", strconv.FormatInt(until.Unix(), 10))
+	q.Set("format", "pprof")
+	q.Set("spyName", "gospy")
+	q.Set("sampleRate", strconv.Itoa(sampleRate))
+
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.ingest+"?"+q.Encode(), profile)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// Close stops the profiler.
+func (p *Profiler) Close() error {
+	close(p.quit)
+	p.wg.Wait()
+	return nil
+}
+
+// formatTags formats the tags to the suffix of the
+// app name in the {key=value,...} form, sorted by the keys.
+func formatTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return "{}"
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + tags[k]
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
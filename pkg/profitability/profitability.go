@@ -0,0 +1,192 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package profitability keeps the daily ledger of the fees the node spends on
+// its blockchain transactions and of the income it earns from the settlements
+// and the redistribution rewards, so that the operators can tell whether their
+// node is net positive. The fees are in wei of the native token of the chain,
+// the income is in PLUR.
+package profitability
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// The categories of the transaction fees.
+const (
+	CostChequebook     = "chequebook"
+	CostCashout        = "cashout"
+	CostStaking        = "staking"
+	CostRedistribution = "redistribution"
+)
+
+// The categories of the income.
+const (
+	IncomeSettlements = "settlements"
+	IncomeRewards     = "rewards"
+)
+
+// plurPerBZZ is the number of PLUR in a single BZZ token.
+var plurPerBZZ = big.NewInt(10_000_000_000_000_000)
+
+const (
+	dayKeyPrefix = "profitability_day_"
+	txKeyPrefix  = "profitability_tx_"
+	dayLayout    = "2006-01-02"
+)
+
+// categories maps the descriptions of the transactions sent by the node to
+// the categories of their fees. The transactions with other descriptions,
+// like the postage batch purchases, are not accounted.
+var categories = map[string]string{
+	"chequebook deployment":                       CostChequebook,
+	"cheque cashout":                              CostCashout,
+	"Approve tokens for stake deposit operations": CostStaking,
+	"Deposit Stake":                               CostStaking,
+	"Withdraw stake":                              CostStaking,
+	"commit transaction":                          CostRedistribution,
+	"reveal transaction":                          CostRedistribution,
+	"claim win transaction":                       CostRedistribution,
+}
+
+// Category returns the category of the fee of the transaction
+// with the given description, if the transaction is accounted.
+func Category(description string) (string, bool) {
+	if strings.HasPrefix(description, "chequebook withdrawal") {
+		return CostChequebook, true
+	}
+	category, ok := categories[description]
+	return category, ok
+}
+
+// Day holds the fees and the income of a single day, by category.
+type Day struct {
+	Date   string              `json:"date"`
+	Costs  map[string]*big.Int `json:"costs"`
+	Income map[string]*big.Int `json:"income"`
+}
+
+func newDay(date string) *Day {
+	return &Day{
+		Date:   date,
+		Costs:  make(map[string]*big.Int),
+		Income: make(map[string]*big.Int),
+	}
+}
+
+func dayKey(date string) string {
+	return dayKeyPrefix + date
+}
+
+func txKey(txHash common.Hash) string {
+	return txKeyPrefix + txHash.String()
+}
+
+// Ledger persists the daily fees and income of the node.
+type Ledger struct {
+	mu    sync.Mutex
+	store storage.StateStorer
+	now   func() time.Time
+}
+
+// New creates a new Ledger which persists the records to the store.
+func New(store storage.StateStorer) *Ledger {
+	return &Ledger{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// AddCost records the fee of the transaction. The fee of
+// the same transaction is recorded only once.
+func (l *Ledger) AddCost(category string, txHash common.Hash, fee *big.Int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch err := l.store.Get(txKey(txHash), new(struct{})); {
+	case err == nil:
+		return nil
+	case !errors.Is(err, storage.ErrNotFound):
+		return err
+	}
+
+	if err := l.add(category, fee, func(d *Day) map[string]*big.Int { return d.Costs }); err != nil {
+		return err
+	}
+	return l.store.Put(txKey(txHash), struct{}{})
+}
+
+// AddIncome records the amount earned by the node.
+func (l *Ledger) AddIncome(category string, amount *big.Int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.add(category, amount, func(d *Day) map[string]*big.Int { return d.Income })
+}
+
+func (l *Ledger) add(category string, amount *big.Int, records func(*Day) map[string]*big.Int) error {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil
+	}
+
+	date := l.now().UTC().Format(dayLayout)
+	day, err := l.day(date)
+	if err != nil {
+		return err
+	}
+
+	m := records(day)
+	total, ok := m[category]
+	if !ok {
+		total = new(big.Int)
+	}
+	m[category] = total.Add(total, amount)
+
+	return l.store.Put(dayKey(date), day)
+}
+
+func (l *Ledger) day(date string) (*Day, error) {
+	day := newDay(date)
+	err := l.store.Get(dayKey(date), day)
+	if errors.Is(err, storage.ErrNotFound) {
+		return day, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get day %s: %w", date, err)
+	}
+	return day, nil
+}
+
+// Days returns the records of the last n days, including today and
+// the days without any records, starting with the oldest one.
+func (l *Ledger) Days(n int) ([]Day, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	today := l.now().UTC()
+	days := make([]Day, 0, n)
+	for i := n - 1; i >= 0; i-- {
+		day, err := l.day(today.AddDate(0, 0, -i).Format(dayLayout))
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, *day)
+	}
+	return days, nil
+}
+
+// IncomeInWei converts the income in PLUR to wei of the native token
+// of the chain at the given price of a single BZZ token in wei.
+func IncomeInWei(plur, weiPerBZZ *big.Int) *big.Int {
+	wei := new(big.Int).Mul(plur, weiPerBZZ)
+	return wei.Quo(wei, plurPerBZZ)
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profitability_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/profitability"
+	"github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/transaction"
+	transactionmock "github.com/ethersphere/bee/pkg/transaction/mock"
+)
+
+func TestLedger(t *testing.T) {
+	t.Parallel()
+
+	ledger := profitability.New(mock.NewStateStore())
+	now := time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC)
+	ledger.SetNow(func() time.Time { return now })
+
+	txHash := common.HexToHash("0x1")
+	for i := 0; i < 2; i++ {
+		// the fee of the same transaction is recorded once
+		if err := ledger.AddCost(profitability.CostCashout, txHash, big.NewInt(10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ledger.AddIncome(profitability.IncomeSettlements, big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.AddDate(0, 0, 1)
+	if err := ledger.AddCost(profitability.CostCashout, common.HexToHash("0x2"), big.NewInt(20)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.AddIncome(profitability.IncomeRewards, big.NewInt(200)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.AddIncome(profitability.IncomeRewards, big.NewInt(50)); err != nil {
+		t.Fatal(err)
+	}
+
+	days, err := ledger.Days(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 3 {
+		t.Fatalf("got %d days, want 3", len(days))
+	}
+
+	for i, want := range []struct {
+		date   string
+		cost   int64
+		income map[string]int64
+	}{
+		{date: "2023-05-01", income: map[string]int64{}},
+		{date: "2023-05-02", cost: 10, income: map[string]int64{profitability.IncomeSettlements: 100}},
+		{date: "2023-05-03", cost: 20, income: map[string]int64{profitability.IncomeRewards: 250}},
+	} {
+		day := days[i]
+		if day.Date != want.date {
+			t.Fatalf("day %d: got date %s, want %s", i, day.Date, want.date)
+		}
+		if want.cost == 0 && len(day.Costs) != 0 {
+			t.Fatalf("day %s: got costs %v, want none", day.Date, day.Costs)
+		}
+		if want.cost != 0 && day.Costs[profitability.CostCashout].Int64() != want.cost {
+			t.Fatalf("day %s: got cashout cost %v, want %d", day.Date, day.Costs[profitability.CostCashout], want.cost)
+		}
+		if len(day.Income) != len(want.income) {
+			t.Fatalf("day %s: got income %v, want %v", day.Date, day.Income, want.income)
+		}
+		for category, amount := range want.income {
+			if day.Income[category].Int64() != amount {
+				t.Fatalf("day %s: got %s income %v, want %d", day.Date, category, day.Income[category], amount)
+			}
+		}
+	}
+}
+
+func TestCategory(t *testing.T) {
+	t.Parallel()
+
+	for description, want := range map[string]string{
+		"chequebook deployment":                 profitability.CostChequebook,
+		"chequebook withdrawal of 10 BZZ":       profitability.CostChequebook,
+		"cheque cashout":                        profitability.CostCashout,
+		"Deposit Stake":                         profitability.CostStaking,
+		"commit transaction":                    profitability.CostRedistribution,
+		"claim win transaction":                 profitability.CostRedistribution,
+		"Approve tokens for postage operations": "",
+	} {
+		got, ok := profitability.Category(description)
+		if ok != (want != "") || got != want {
+			t.Fatalf("%q: got category %q, want %q", description, got, want)
+		}
+	}
+}
+
+func TestTransactionService(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ledger   = profitability.New(mock.NewStateStore())
+		txHashes = []common.Hash{common.HexToHash("0x1"), common.HexToHash("0x2")}
+		sent     int
+	)
+	service := profitability.NewTransactionService(log.Noop, transactionmock.New(
+		transactionmock.WithSendFunc(func(context.Context, *transaction.TxRequest, int) (common.Hash, error) {
+			txHash := txHashes[sent]
+			sent++
+			return txHash, nil
+		}),
+		transactionmock.WithWaitForReceiptFunc(func(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+			return &types.Receipt{TxHash: txHash, GasUsed: 21000}, nil
+		}),
+		transactionmock.WithStoredTransactionFunc(func(common.Hash) (*transaction.StoredTransaction, error) {
+			return &transaction.StoredTransaction{GasPrice: big.NewInt(2)}, nil
+		}),
+	), ledger)
+
+	if _, err := service.Send(context.Background(), &transaction.TxRequest{Description: "cheque cashout"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Send(context.Background(), &transaction.TxRequest{Description: "Approve tokens for postage operations"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	// closing waits for the receipts to be recorded
+	if err := service.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	days, err := ledger.Days(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days[0].Costs) != 1 || days[0].Costs[profitability.CostCashout].Int64() != 42000 {
+		t.Fatalf("got costs %v, want cashout cost 42000", days[0].Costs)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profitability
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/transaction"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "profitability"
+
+type transactionService struct {
+	transaction.Service
+
+	logger log.Logger
+	ledger *Ledger
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewTransactionService wraps the transaction service so that the fees of
+// the accounted transactions are recorded in the ledger once they are mined.
+// The fee is the gas used by the transaction at the gas price it was sent
+// with. The reverted transactions are recorded as well, as their fees are
+// spent.
+func NewTransactionService(logger log.Logger, service transaction.Service, ledger *Ledger) transaction.Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &transactionService{
+		Service: service,
+		logger:  logger.WithName(loggerName).Register(),
+		ledger:  ledger,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Send sends the transaction and starts watching for its receipt
+// if the fee of the transaction is accounted.
+func (s *transactionService) Send(ctx context.Context, request *transaction.TxRequest, tipCapBoostPercent int) (common.Hash, error) {
+	txHash, err := s.Service.Send(ctx, request, tipCapBoostPercent)
+	if err != nil {
+		return txHash, err
+	}
+
+	if category, ok := Category(request.Description); ok {
+		s.wg.Add(1)
+		go s.recordFee(category, txHash)
+	}
+	return txHash, nil
+}
+
+func (s *transactionService) recordFee(category string, txHash common.Hash) {
+	defer s.wg.Done()
+
+	receipt, err := s.Service.WaitForReceipt(s.ctx, txHash)
+	if err != nil {
+		s.logger.Debug("fee of the transaction not recorded", "tx", txHash, "error", err)
+		return
+	}
+
+	stored, err := s.Service.StoredTransaction(txHash)
+	if err != nil {
+		s.logger.Debug("fee of the transaction not recorded", "tx", txHash, "error", err)
+		return
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), stored.GasPrice)
+
+	if err := s.ledger.AddCost(category, txHash, fee); err != nil {
+		s.logger.Error(err, "record transaction fee failed", "tx", txHash)
+	}
+}
+
+// Close stops watching the transactions and closes the wrapped service.
+func (s *transactionService) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return s.Service.Close()
+}
@@ -12,6 +12,9 @@ import (
 type metrics struct {
 	TotalMessagesSentCounter prometheus.Counter
 	MessageMiningDuration    prometheus.Gauge
+	OutboxQueuedCounter      prometheus.Counter
+	OutboxSentCounter        prometheus.Counter
+	OutboxExpiredCounter     prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -30,6 +33,24 @@ func newMetrics() metrics {
 			Name:      "mining_duration",
 			Help:      "Time duration to mine a message.",
 		}),
+		OutboxQueuedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "outbox_queued",
+			Help:      "Total messages queued in the outbox while no peers were connected.",
+		}),
+		OutboxSentCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "outbox_sent",
+			Help:      "Total queued messages sent from the outbox.",
+		}),
+		OutboxExpiredCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "outbox_expired",
+			Help:      "Total queued messages dropped from the outbox after their expiry.",
+		}),
 	}
 }
 
@@ -0,0 +1,168 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pss
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// outboxKeyPrefix is the statestore key prefix of the queued messages.
+const outboxKeyPrefix = "pss_outbox_"
+
+// outboxFlushInterval is the interval in which the outbox is flushed
+// even without the topology change, so that the expired messages are
+// dropped and the messages with the failed pushes are retried.
+var outboxFlushInterval = time.Minute
+
+// ErrQueued is returned by Send if the message could not be sent because
+// there are no connected peers and it is queued in the outbox instead.
+var ErrQueued = errors.New("message queued until peers are connected")
+
+// TopologySubscriber notifies about the changes of the connected peers.
+type TopologySubscriber interface {
+	SubscribeTopologyChange() (c <-chan struct{}, unsubscribe func())
+}
+
+type expiryKey struct{}
+
+// WithExpiry returns the context with which Send queues the message in
+// the outbox if there are no connected peers. The queued message is sent
+// when the peers are connected again, unless the expiry elapses first.
+func WithExpiry(ctx context.Context, expiry time.Duration) context.Context {
+	return context.WithValue(ctx, expiryKey{}, expiry)
+}
+
+func expiryFromContext(ctx context.Context) (time.Duration, bool) {
+	expiry, ok := ctx.Value(expiryKey{}).(time.Duration)
+	return expiry, ok && expiry > 0
+}
+
+// outboxItem is the stamped trojan chunk of the queued message.
+type outboxItem struct {
+	Address swarm.Address `json:"address"`
+	Data    []byte        `json:"data"`
+	Stamp   []byte        `json:"stamp"`
+	Expiry  int64         `json:"expiry"`
+}
+
+func outboxKey(addr swarm.Address) string {
+	return outboxKeyPrefix + addr.String()
+}
+
+// SetOutbox enables the queueing of the messages sent with an expiry in
+// the store while there are no connected peers. The queued messages are
+// sent on the changes of the topology.
+func (p *pss) SetOutbox(store storage.StateStorer, topology TopologySubscriber) {
+	p.outbox = store
+	p.wg.Add(1)
+	go p.outboxLoop(topology)
+}
+
+// queue persists the chunk in the outbox until the expiry.
+func (p *pss) queue(ch swarm.Chunk, expiry time.Duration) error {
+	stamp, err := ch.Stamp().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	item := outboxItem{
+		Address: ch.Address(),
+		Data:    ch.Data(),
+		Stamp:   stamp,
+		Expiry:  time.Now().Add(expiry).UnixNano(),
+	}
+	if err := p.outbox.Put(outboxKey(ch.Address()), item); err != nil {
+		return fmt.Errorf("queue message: %w", err)
+	}
+	p.metrics.OutboxQueuedCounter.Inc()
+	return ErrQueued
+}
+
+func (p *pss) outboxLoop(topology TopologySubscriber) {
+	defer p.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-p.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	c, unsubscribe := topology.SubscribeTopologyChange()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(outboxFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-c:
+		case <-ticker.C:
+		}
+		if err := p.flushOutbox(ctx); err != nil {
+			p.logger.Error(err, "flush outbox failed")
+		}
+	}
+}
+
+// flushOutbox sends the queued messages, the expired messages are dropped.
+func (p *pss) flushOutbox(ctx context.Context) error {
+	var items []outboxItem
+	err := p.outbox.Iterate(outboxKeyPrefix, func(_, value []byte) (bool, error) {
+		var item outboxItem
+		if err := json.Unmarshal(value, &item); err != nil {
+			return true, err
+		}
+		items = append(items, item)
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixNano()
+	for _, item := range items {
+		if item.Expiry < now {
+			if err := p.outbox.Delete(outboxKey(item.Address)); err != nil {
+				return err
+			}
+			p.metrics.OutboxExpiredCounter.Inc()
+			p.logger.Debug("queued message expired", "address", item.Address)
+			continue
+		}
+
+		stamp := new(postage.Stamp)
+		if err := stamp.UnmarshalBinary(item.Stamp); err != nil {
+			return err
+		}
+		ch := swarm.NewChunk(item.Address, item.Data).WithStamp(stamp)
+		if _, err := p.pusher.PushChunkToClosest(ctx, ch); err != nil {
+			if errors.Is(err, topology.ErrNotFound) {
+				// still no connected peers
+				return nil
+			}
+			p.logger.Debug("send queued message failed", "address", item.Address, "error", err)
+			continue
+		}
+		if err := p.outbox.Delete(outboxKey(item.Address)); err != nil {
+			return err
+		}
+		p.metrics.OutboxSentCounter.Inc()
+	}
+	return nil
+}
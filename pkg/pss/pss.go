@@ -20,7 +20,9 @@ import (
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
 )
 
 // loggerName is the tree path name of the logger for this package.
@@ -44,6 +46,7 @@ type Interface interface {
 	TryUnwrap(swarm.Chunk)
 
 	SetPushSyncer(pushSyncer pushsync.PushSyncer)
+	SetOutbox(store storage.StateStorer, topology TopologySubscriber)
 	io.Closer
 }
 
@@ -54,6 +57,8 @@ type pss struct {
 	handlersMu sync.Mutex
 	metrics    metrics
 	logger     log.Logger
+	outbox     storage.StateStorer
+	wg         sync.WaitGroup
 	quit       chan struct{}
 }
 
@@ -70,6 +75,7 @@ func New(key *ecdsa.PrivateKey, logger log.Logger) Interface {
 
 func (ps *pss) Close() error {
 	close(ps.quit)
+	ps.wg.Wait()
 	ps.handlersMu.Lock()
 	defer ps.handlersMu.Unlock()
 
@@ -87,7 +93,9 @@ type Handler func(context.Context, []byte)
 
 // Send constructs a padded message with topic and payload,
 // wraps it in a trojan chunk such that one of the targets is a prefix of the chunk address.
-// Uses push-sync to deliver message.
+// Uses push-sync to deliver message. If there are no connected peers and the
+// context carries an expiry, see WithExpiry, the message is queued in the outbox
+// and ErrQueued is returned.
 func (p *pss) Send(ctx context.Context, topic Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets Targets) error {
 	p.metrics.TotalMessagesSentCounter.Inc()
 
@@ -108,6 +116,9 @@ func (p *pss) Send(ctx context.Context, topic Topic, payload []byte, stamper pos
 
 	// push the chunk using push sync so that it reaches it destination in network
 	if _, err = p.pusher.PushChunkToClosest(ctx, tc); err != nil {
+		if expiry, ok := expiryFromContext(ctx); ok && p.outbox != nil && errors.Is(err, topology.ErrNotFound) {
+			return p.queue(tc, expiry)
+		}
 		return err
 	}
 
@@ -7,6 +7,9 @@ package pss_test
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,7 +20,10 @@ import (
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/pushsync"
 	pushsyncmock "github.com/ethersphere/bee/pkg/pushsync/mock"
+	"github.com/ethersphere/bee/pkg/spinlock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
 )
 
 // TestSend creates a trojan chunk and sends it using push sync
@@ -70,6 +76,99 @@ func TestSend(t *testing.T) {
 	}
 }
 
+type topologySubscriber struct {
+	c chan struct{}
+}
+
+func (t *topologySubscriber) SubscribeTopologyChange() (<-chan struct{}, func()) {
+	return t.c, func() {}
+}
+
+// TestOutbox checks that the messages sent with an expiry are queued while
+// there are no connected peers and are sent when the topology changes.
+func TestOutbox(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mtx       sync.Mutex
+		connected bool
+		pushed    []swarm.Chunk
+	)
+	pushSyncService := pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if !connected {
+			return nil, fmt.Errorf("closest peer: %w", topology.ErrNotFound)
+		}
+		pushed = append(pushed, chunk)
+		return nil, nil
+	})
+	store := statestore.NewStateStore()
+	topo := &topologySubscriber{c: make(chan struct{})}
+
+	p := pss.New(nil, log.Noop)
+	p.SetPushSyncer(pushSyncService)
+	p.SetOutbox(store, topo)
+	t.Cleanup(func() { _ = p.Close() })
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		recipient = &privkey.PublicKey
+		targets   = pss.Targets([]pss.Target{{1}})
+		topic     = pss.NewTopic("topic")
+		ctx       = context.Background()
+	)
+
+	err = p.Send(ctx, topic, []byte("no expiry"), &stamper{}, recipient, targets)
+	if !errors.Is(err, topology.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, topology.ErrNotFound)
+	}
+	err = p.Send(pss.WithExpiry(ctx, time.Hour), topic, []byte("queued"), &stamper{}, recipient, targets)
+	if !errors.Is(err, pss.ErrQueued) {
+		t.Fatalf("got error %v, want %v", err, pss.ErrQueued)
+	}
+	err = p.Send(pss.WithExpiry(ctx, time.Nanosecond), topic, []byte("expired"), &stamper{}, recipient, targets)
+	if !errors.Is(err, pss.ErrQueued) {
+		t.Fatalf("got error %v, want %v", err, pss.ErrQueued)
+	}
+
+	mtx.Lock()
+	connected = true
+	mtx.Unlock()
+	topo.c <- struct{}{}
+
+	err = spinlock.Wait(time.Second, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(pushed) == 1
+	})
+	if err != nil {
+		t.Fatal("queued message not sent")
+	}
+	_, msg, err := pss.Unwrap(ctx, privkey, pushed[0], []pss.Topic{topic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg, []byte("queued")) {
+		t.Fatalf("got message %q, want %q", msg, "queued")
+	}
+
+	err = spinlock.Wait(time.Second, func() bool {
+		n := 0
+		_ = store.Iterate("pss_outbox_", func(_, _ []byte) (bool, error) {
+			n++
+			return false, nil
+		})
+		return n == 0
+	})
+	if err != nil {
+		t.Fatal("outbox not emptied")
+	}
+}
+
 type topicMessage struct {
 	topic pss.Topic
 	msg   []byte
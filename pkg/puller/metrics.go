@@ -16,6 +16,7 @@ type metrics struct {
 	LiveWorkerIterCounter prometheus.Counter // counts the number of live syncing iterations
 	LiveWorkerErrCounter  prometheus.Counter // count number of errors
 	MaxUintErrCounter     prometheus.Counter // how many times we got maxuint as topmost
+	RepairedBinsCounter   prometheus.Counter // how many times a bin was resynced to repair the reserve
 }
 
 func newMetrics() metrics {
@@ -58,6 +59,12 @@ func newMetrics() metrics {
 			Name:      "max_uint_errors",
 			Help:      "Total max uint errors.",
 		}),
+		RepairedBinsCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "repaired_bins",
+			Help:      "Total bins resynced to repair the reserve.",
+		}),
 	}
 }
 
@@ -65,6 +65,10 @@ type Puller struct {
 	bins uint8 // how many bins do we support
 
 	activeHistoricalSyncing *atomic.Uint64
+
+	repairBins map[uint8]struct{} // bins scheduled for the repair
+	repairMtx  sync.Mutex
+	repairC    chan struct{}
 }
 
 func New(stateStore storage.StateStorer, topology topology.Driver, reserveState postage.RadiusChecker, pullSync pullsync.Interface, blockLister p2p.Blocklister, logger log.Logger, o Options, warmupTime time.Duration) *Puller {
@@ -87,6 +91,8 @@ func New(stateStore storage.StateStorer, topology topology.Driver, reserveState
 		bins:                    bins,
 		activeHistoricalSyncing: atomic.NewUint64(0),
 		blockLister:             blockLister,
+		repairBins:              make(map[uint8]struct{}),
+		repairC:                 make(chan struct{}, 1),
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -145,7 +151,7 @@ func (p *Puller) manage(ctx context.Context, warmupTime time.Duration) {
 			}
 			delete(peersDisconnected, addr.ByteString())
 			return false, false, nil
-		}, topology.Filter{Reachable: true})
+		}, topology.Filter{Reachable: true, Healthy: true})
 
 		for _, peer := range peersDisconnected {
 			p.disconnectPeer(peer.address)
@@ -168,6 +174,8 @@ func (p *Puller) manage(ctx context.Context, warmupTime time.Duration) {
 		case <-c:
 			tick.Reset(recalcPeersDur)
 			onChange()
+		case <-p.repairC:
+			p.repair(ctx)
 		}
 	}
 }
@@ -313,7 +321,7 @@ func (p *Puller) histSyncWorker(ctx context.Context, peer swarm.Address, bin uin
 			loggerV2.Debug("histSyncWorker interval failed", "peer_address", peer, "bin", bin, "cursor", cur, "start", s, "topmost", top, "err", err)
 			if errors.Is(err, context.DeadlineExceeded) {
 				p.logger.Debug("histSyncWorker interval timeout, exiting", "total_duration", time.Since(loopStart), "peer_address", peer, "error", err)
-				err = p.blockLister.Blocklist(peer, histSyncTimeoutBlockList, "sync interval timeout")
+				err = p.blockLister.Blocklist(peer, histSyncTimeoutBlockList, p2p.OffenseProtocolError, "sync interval timeout")
 				if err != nil {
 					p.logger.Debug("histSyncWorker timeout disconnect error", "error", err)
 				}
@@ -417,10 +425,7 @@ func (p *Puller) addPeerInterval(peer swarm.Address, bin uint8, start, end uint6
 
 func (p *Puller) resetIntervals(upto uint8) error {
 	for bin := uint8(0); bin < upto; bin++ {
-		err := p.statestore.Iterate(binIntervalKey(bin), func(key, _ []byte) (stop bool, err error) {
-			return false, p.statestore.Delete(string(key))
-		})
-		if err != nil {
+		if err := p.resetBinIntervals(bin); err != nil {
 			return err
 		}
 	}
@@ -428,6 +433,23 @@ func (p *Puller) resetIntervals(upto uint8) error {
 	return nil
 }
 
+// resetBinIntervals deletes the synced intervals of the bin of all peers.
+func (p *Puller) resetBinIntervals(bin uint8) error {
+	var keys []string
+	if err := p.statestore.Iterate(binIntervalKey(bin), func(key, _ []byte) (stop bool, err error) {
+		keys = append(keys, string(key))
+		return false, nil
+	}); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := p.statestore.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *Puller) nextPeerInterval(peer swarm.Address, bin uint8) (start, end uint64, empty bool, err error) {
 
 	i, err := p.getOrCreateInterval(peer, bin)
@@ -373,6 +373,42 @@ func TestBinReset(t *testing.T) {
 	checkHistSyncingCount(t, puller, 0)
 }
 
+func TestRepair(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr    = swarm.RandAddress(t)
+		cursors = []uint64{0, 10}
+	)
+
+	puller, st, kad, pullsync := newPuller(t, opts{
+		kad: []kadMock.Option{
+			kadMock.WithEachPeerRevCalls(
+				kadMock.AddrTuple{Addr: addr, PO: 1},
+			),
+		},
+		pullSync: []mockps.Option{mockps.WithCursors(cursors), mockps.WithAutoReply(), mockps.WithLiveSyncBlock()},
+		bins:     2,
+		bs:       bsMock.WithReserveState(&postage.ReserveState{StorageRadius: 1}),
+	})
+
+	time.Sleep(100 * time.Millisecond)
+
+	kad.Trigger()
+
+	waitCursorsCalled(t, pullsync, addr, false)
+	waitSyncCalledTimes(t, pullsync, addr, 1)
+	checkIntervals(t, st, addr, "[[1 10]]", 1)
+
+	puller.Repair(1)
+
+	// the synced interval of the bin is replayed
+	waitCheckCalls(t, []c{call(1, 1, 10), call(1, 1, 10)}, pullsync.SyncCalls, addr)
+	checkIntervals(t, st, addr, "[[1 10]]", 1)
+
+	checkHistSyncingCount(t, puller, 0)
+}
+
 // TestDepthChange tests that puller reacts correctly to
 // depth changes signalled from kademlia.
 // Due to the fact that the component does goroutine termination
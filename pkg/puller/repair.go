@@ -0,0 +1,73 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package puller
+
+import (
+	"context"
+)
+
+// Repair schedules the repair of the reserve bins in which chunks were found
+// to be missing, for example by an integrity check of the local store. The
+// synced intervals of the bins are forgotten and the bins are synced again
+// from the neighbourhood peers, which replays the pullsync of only the
+// affected bins instead of a full resync. The missing chunks are fetched
+// again as the chunks already present in the reserve are not requested.
+// Repair does not block, the repair is done in the background.
+func (p *Puller) Repair(bins ...uint8) {
+	p.repairMtx.Lock()
+	for _, bin := range bins {
+		if bin < p.bins {
+			p.repairBins[bin] = struct{}{}
+		}
+	}
+	p.repairMtx.Unlock()
+
+	select {
+	case p.repairC <- struct{}{}:
+	default:
+	}
+}
+
+// repair resyncs the bins scheduled for the repair.
+func (p *Puller) repair(ctx context.Context) {
+	p.repairMtx.Lock()
+	bins := make([]uint8, 0, len(p.repairBins))
+	for bin := range p.repairBins {
+		bins = append(bins, bin)
+	}
+	p.repairBins = make(map[uint8]struct{})
+	p.repairMtx.Unlock()
+
+	if len(bins) == 0 {
+		return
+	}
+
+	p.syncPeersMtx.Lock()
+	defer p.syncPeersMtx.Unlock()
+
+	// stop the syncing of the bins before the intervals are
+	// forgotten, so that the workers do not persist them again
+	for _, peer := range p.syncPeers {
+		peer.Lock()
+		for _, bin := range bins {
+			peer.cancelBin(bin)
+		}
+		// the historical syncing of the restarted bins
+		// replays the intervals up to the fresh cursors
+		peer.cursors = nil
+		peer.Unlock()
+	}
+
+	for _, bin := range bins {
+		if err := p.resetBinIntervals(bin); err != nil {
+			p.logger.Error(err, "reset intervals of the repaired bin failed", "bin", bin)
+			continue
+		}
+		p.metrics.RepairedBinsCounter.Inc()
+	}
+	p.logger.Info("repairing the reserve bins", "bins", bins)
+
+	p.recalcPeers(ctx, p.radius.StorageRadius())
+}
@@ -577,7 +577,7 @@ func newTestDB(t *testing.T, o *localstore.Options) (baseKey []byte, db *localst
 
 	createLocalstoreLock.Lock()
 	defer createLocalstoreLock.Unlock()
-	db, err := localstore.New("", baseKey, nil, o, log.Noop)
+	db, err := localstore.NewInmem(baseKey, nil, o, log.Noop)
 	if err != nil {
 		t.Fatal(err)
 	}
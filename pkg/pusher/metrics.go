@@ -13,6 +13,7 @@ type metrics struct {
 	TotalToPush      prometheus.Counter
 	TotalSynced      prometheus.Counter
 	TotalErrors      prometheus.Counter
+	TotalThrottled   prometheus.Counter
 	MarkAndSweepTime prometheus.Histogram
 	SyncTime         prometheus.Histogram
 	ErrorTime        prometheus.Histogram
@@ -43,6 +44,12 @@ func newMetrics() metrics {
 			Name:      "total_errors",
 			Help:      "Total errors encountered.",
 		}),
+		TotalThrottled: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_throttled",
+			Help:      "Total chunks delayed by the bandwidth limit of their tag.",
+		}),
 		MarkAndSweepTime: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
@@ -21,6 +21,7 @@ import (
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/receipts"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
@@ -47,11 +48,13 @@ type Service struct {
 	depther           topology.NeighborhoodDepther
 	logger            log.Logger
 	tag               *tags.Tags
+	receipts          *receipts.Store
 	metrics           metrics
 	quit              chan struct{}
 	chunksWorkerQuitC chan struct{}
 	inflight          *inflight
 	attempts          *attempts
+	pushAttempts      *attempts // failed pushes per chunk, the tag fails once they are exhausted
 	smuggler          chan OpChan
 }
 
@@ -68,7 +71,7 @@ var (
 
 const chunkStoreTimeout = 2 * time.Second
 
-func New(networkID uint64, storer storage.Storer, depther topology.NeighborhoodDepther, pushSyncer pushsync.PushSyncer, validStamp postage.ValidStampFn, tagger *tags.Tags, logger log.Logger, tracer *tracing.Tracer, warmupTime time.Duration, retryCount int) *Service {
+func New(networkID uint64, storer storage.Storer, depther topology.NeighborhoodDepther, pushSyncer pushsync.PushSyncer, validStamp postage.ValidStampFn, tagger *tags.Tags, receiptStore *receipts.Store, logger log.Logger, tracer *tracing.Tracer, warmupTime time.Duration, retryCount int) *Service {
 	p := &Service{
 		networkID:         networkID,
 		storer:            storer,
@@ -76,12 +79,14 @@ func New(networkID uint64, storer storage.Storer, depther topology.NeighborhoodD
 		validStamp:        validStamp,
 		depther:           depther,
 		tag:               tagger,
+		receipts:          receiptStore,
 		logger:            logger.WithName(loggerName).Register(),
 		metrics:           newMetrics(),
 		quit:              make(chan struct{}),
 		chunksWorkerQuitC: make(chan struct{}),
 		inflight:          newInflight(),
 		attempts:          &attempts{retryCount: retryCount, attempts: make(map[string]int)},
+		pushAttempts:      &attempts{retryCount: retryCount, attempts: make(map[string]int)},
 		smuggler:          make(chan OpChan),
 	}
 	go p.chunksWorker(warmupTime, tracer)
@@ -136,6 +141,7 @@ func (s *Service) chunksWorker(warmupTime time.Duration, tracer *tracing.Tracer)
 
 		if err := s.valid(op.Chunk); err != nil {
 			logger.Warning("stamp with is no longer valid, skipping syncing for chunk", "batch_id", hex.EncodeToString(op.Chunk.Stamp().BatchID()), "direct_upload", op.Direct, "chunk_address", op.Chunk.Address(), "error", err)
+			s.failTag(op.Chunk, tags.PhaseStamp, err, logger)
 			if op.Direct {
 				if op.Err != nil {
 					op.Err <- err
@@ -159,6 +165,10 @@ func (s *Service) chunksWorker(warmupTime time.Duration, tracer *tracing.Tracer)
 			if op.Err != nil {
 				op.Err <- err
 			}
+			if !s.pushAttempts.try(op.Chunk.Address()) {
+				s.pushAttempts.delete(op.Chunk.Address())
+				s.failTag(op.Chunk, tags.PhasePush, err, logger)
+			}
 			repeat()
 			s.metrics.TotalErrors.Inc()
 			s.metrics.ErrorTime.Observe(time.Since(startTime).Seconds())
@@ -168,6 +178,7 @@ func (s *Service) chunksWorker(warmupTime time.Duration, tracer *tracing.Tracer)
 		if op.Err != nil {
 			op.Err <- nil
 		}
+		s.pushAttempts.delete(op.Chunk.Address())
 		s.metrics.TotalSynced.Inc()
 	}
 
@@ -226,6 +237,29 @@ func (s *Service) chunksWorker(warmupTime time.Duration, tracer *tracing.Tracer)
 	for {
 		select {
 		case op := <-cc:
+			// the chunks of the tags with a bandwidth cap wait aside,
+			// so that they do not hold back the other chunks
+			if delay := s.reserveBandwidth(op.Chunk); delay > 0 {
+				s.metrics.TotalThrottled.Inc()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					timer := time.NewTimer(delay)
+					defer timer.Stop()
+					select {
+					case <-timer.C:
+					case <-s.quit:
+						return
+					}
+					select {
+					case sem <- struct{}{}:
+						wg.Add(1)
+						go push(op)
+					case <-s.quit:
+					}
+				}()
+				continue
+			}
 			select {
 			case sem <- struct{}{}:
 				wg.Add(1)
@@ -263,8 +297,23 @@ func (s *Service) pushChunk(ctx context.Context, ch swarm.Chunk, logger log.Logg
 		// connected to other nodes, but is the closest one to the chunk.
 		wantSelf = true
 		loggerV1.Debug("chunk stays here, i'm the closest node", "chunk_address", ch.Address())
-	} else if err = s.checkReceipt(receipt); err != nil {
-		return err
+	} else {
+		storer, err := s.checkReceipt(receipt)
+		if err != nil {
+			return err
+		}
+		if s.receipts != nil {
+			err = s.receipts.Put(receipts.Receipt{
+				Address:   receipt.Address,
+				Storer:    storer,
+				Signature: receipt.Signature,
+				Nonce:     receipt.Nonce,
+				Timestamp: time.Now(),
+			})
+			if err != nil {
+				logger.Debug("store receipt failed", "chunk_address", ch.Address(), "error", err)
+			}
+		}
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
@@ -290,23 +339,55 @@ func (s *Service) pushChunk(ctx context.Context, ch swarm.Chunk, logger log.Logg
 					return nil // tag error is non-fatal
 				}
 			}
+			if receipt != nil && len(receipt.Path) > 0 {
+				t.AddTrace(len(receipt.Path), receipt.Path[0].String())
+			}
 		}
 	}
 	return nil
 }
 
-func (s *Service) checkReceipt(receipt *pushsync.Receipt) error {
+// failTag records the failed upload phase in the tag of the chunk, if any.
+func (s *Service) failTag(ch swarm.Chunk, phase tags.Phase, err error, logger log.Logger) {
+	if ch.TagID() == 0 {
+		return
+	}
+	t, tagErr := s.tag.Get(ch.TagID())
+	if tagErr != nil || t == nil {
+		return
+	}
+	if tagErr = t.Fail(phase, err); tagErr != nil {
+		logger.Debug("fail tag failed", "tag_uid", ch.TagID(), "error", tagErr)
+	}
+}
+
+// reserveBandwidth reserves the bandwidth to push the chunk if its
+// tag has a bandwidth cap and returns the duration to wait before
+// pushing it.
+func (s *Service) reserveBandwidth(ch swarm.Chunk) time.Duration {
+	if ch.TagID() == 0 {
+		return 0
+	}
+	t, err := s.tag.Get(ch.TagID())
+	if err != nil {
+		return 0
+	}
+	return t.ReserveBandwidth(len(ch.Data()))
+}
+
+// checkReceipt checks the depth of the storer of the chunk and returns its overlay address.
+func (s *Service) checkReceipt(receipt *pushsync.Receipt) (swarm.Address, error) {
 	loggerV1 := s.logger.V(1).Register()
 
 	addr := receipt.Address
 	publicKey, err := crypto.Recover(receipt.Signature, addr.Bytes())
 	if err != nil {
-		return fmt.Errorf("pusher: receipt recover: %w", err)
+		return swarm.ZeroAddress, fmt.Errorf("pusher: receipt recover: %w", err)
 	}
 
 	peer, err := crypto.NewOverlayAddress(*publicKey, s.networkID, receipt.Nonce)
 	if err != nil {
-		return fmt.Errorf("pusher: receipt storer address: %w", err)
+		return swarm.ZeroAddress, fmt.Errorf("pusher: receipt storer address: %w", err)
 	}
 
 	po := swarm.Proximity(addr.Bytes(), peer.Bytes())
@@ -318,12 +399,12 @@ func (s *Service) checkReceipt(receipt *pushsync.Receipt) error {
 	// if the receipt po is out of depth AND the receipt has not yet hit the maximum retry limit, reject the receipt.
 	if po < d && s.attempts.try(addr) {
 		s.metrics.ShallowReceiptDepth.WithLabelValues(strconv.Itoa(int(po))).Inc()
-		return fmt.Errorf("pusher: shallow receipt depth %d, want at least %d", po, d)
+		return swarm.ZeroAddress, fmt.Errorf("pusher: shallow receipt depth %d, want at least %d", po, d)
 	}
 	loggerV1.Debug("chunk pushed", "chunk_address", addr, "peer_address", peer, "proximity_order", po)
 	s.metrics.ReceiptDepth.WithLabelValues(strconv.Itoa(int(po))).Inc()
 	s.attempts.delete(addr)
-	return nil
+	return peer, nil
 }
 
 // valid checks whether the stamp for a chunk is valid before sending
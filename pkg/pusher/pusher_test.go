@@ -7,6 +7,7 @@ package pusher_test
 import (
 	"context"
 	"errors"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -22,7 +23,6 @@ import (
 	"github.com/ethersphere/bee/pkg/pushsync"
 	pushsyncmock "github.com/ethersphere/bee/pkg/pushsync/mock"
 	"github.com/ethersphere/bee/pkg/spinlock"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage"
 	testingc "github.com/ethersphere/bee/pkg/storage/testing"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -130,6 +130,71 @@ func TestSendChunkToSyncWithTag(t *testing.T) {
 	}
 }
 
+// TestPusherBandwidthLimit checks that the chunks of the tag with the bandwidth
+// cap are pushed at the limited rate without holding back the other chunks.
+func TestPusherBandwidthLimit(t *testing.T) {
+	t.Parallel()
+
+	triggerPeer := swarm.MustParseHexAddress("6000000000000000000000000000000000000000000000000000000000000000")
+	closestPeer := swarm.MustParseHexAddress("f000000000000000000000000000000000000000000000000000000000000000")
+
+	key, _ := crypto.GenerateSecp256k1Key()
+	signer := crypto.NewDefaultSigner(key)
+
+	pushSyncService := pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+		signature, _ := signer.Sign(chunk.Address().Bytes())
+		receipt := &pushsync.Receipt{
+			Address:   swarm.NewAddress(chunk.Address().Bytes()),
+			Signature: signature,
+			Nonce:     block,
+		}
+		return receipt, nil
+	})
+
+	mtags, _, storer := createPusher(t, triggerPeer, pushSyncService, defaultMockValidStamp, mock.WithClosestPeer(closestPeer), mock.WithNeighborhoodDepth(0))
+
+	ta, err := mtags.Create(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a single chunk per second
+	if err := ta.SetBandwidthLimit(swarm.ChunkWithSpanSize); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := testingc.GenerateTestRandomChunks(3)
+	for i := range chunks {
+		chunks[i] = chunks[i].WithTagID(ta.Uid)
+	}
+	unlimited := testingc.GenerateTestRandomChunk()
+
+	start := time.Now()
+	_, err = storer.Put(context.Background(), storage.ModePutUpload, append(chunks, unlimited)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = spinlock.Wait(spinTimeout, func() bool {
+		return checkIfModeSet(unlimited.Address(), storage.ModeSetSync, storer) == nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if synced := ta.Get(tags.StateSynced); synced == 3 {
+		t.Fatal("all chunks of the tag synced without waiting for the bandwidth")
+	}
+
+	err = spinlock.Wait(2*spinTimeout, func() bool {
+		return ta.Get(tags.StateSynced) == 3
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 2*time.Second {
+		t.Fatalf("chunks of the tag synced in %s, want at least 2s", elapsed)
+	}
+}
+
 // TestSendChunkToPushSyncWithoutTag is similar to TestSendChunkToPushSync, excep that the tags are not
 // present to simulate bzz api withotu splitter condition
 func TestSendChunkToPushSyncWithoutTag(t *testing.T) {
@@ -358,6 +423,46 @@ func TestPusherRetryShallow(t *testing.T) {
 	}
 }
 
+// TestPusherTagFailedPush checks that the tag of a chunk
+// fails once the pushes of the chunk fail repeatedly.
+func TestPusherTagFailedPush(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pivotPeer   = swarm.MustParseHexAddress("6000000000000000000000000000000000000000000000000000000000000000")
+		closestPeer = swarm.MustParseHexAddress("f000000000000000000000000000000000000000000000000000000000000000")
+		retryCount  = 3
+		errPush     = errors.New("push timeout")
+	)
+	pushSyncService := pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+		return nil, errPush
+	})
+
+	mtags, _, storer := createPusherWithRetryCount(t, pivotPeer, pushSyncService, defaultMockValidStamp, retryCount, mock.WithClosestPeer(closestPeer), mock.WithNeighborhoodDepth(0))
+
+	ta, err := mtags.Create(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunk := testingc.GenerateTestRandomChunk().WithTagID(ta.Uid)
+
+	_, err = storer.Put(context.Background(), storage.ModePutUpload, chunk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = spinlock.Wait(spinTimeout, ta.Failed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []tags.PhaseError{{Phase: tags.PhasePush, Message: errPush.Error()}}
+	if got := ta.Errors(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got tag errors %v, want %v", got, want)
+	}
+}
+
 // TestChunkWithInvalidStampSkipped tests that chunks with invalid stamps are skipped in pusher
 func TestChunkWithInvalidStampSkipped(t *testing.T) {
 	t.Parallel()
@@ -411,15 +516,14 @@ func createPusherWithRetryCount(t *testing.T, addr swarm.Address, pushSyncServic
 	logger := log.Noop
 
 	createLocalstoreLock.Lock()
-	storer, err := localstore.New("", addr.Bytes(), nil, nil, logger)
+	storer, err := localstore.NewInmem(addr.Bytes(), nil, nil, logger)
 	if err != nil {
 		createLocalstoreLock.Unlock()
 		t.Fatal(err)
 	}
 	createLocalstoreLock.Unlock()
 
-	mockStatestore := statestore.NewStateStore()
-	mtags := tags.NewTags(mockStatestore, logger)
+	mtags := tags.NewTags(nil, logger)
 	pusherStorer := &Store{
 		Storer:         storer,
 		internalStorer: storer,
@@ -428,7 +532,7 @@ func createPusherWithRetryCount(t *testing.T, addr swarm.Address, pushSyncServic
 	}
 	peerSuggester := mock.NewTopologyDriver(mockOpts...)
 
-	pusherService := pusher.New(1, pusherStorer, peerSuggester, pushSyncService, validStamp, mtags, logger, nil, 0, retryCount)
+	pusherService := pusher.New(1, pusherStorer, peerSuggester, pushSyncService, validStamp, mtags, nil, logger, nil, 0, retryCount)
 	testutil.CleanupCloser(t, pusherService, pusherStorer)
 
 	return mtags, pusherService, pusherStorer
@@ -0,0 +1,341 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pushsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/protobuf"
+	"github.com/ethersphere/bee/pkg/pushsync/pb"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// The deliveries of the chunks to the same peer within the batch window are
+// sent together in a single stream, and their receipts are returned together,
+// so that the cost of the stream setup, which dominates when many chunks are
+// pushed to the same neighbour, is shared. The batches are sent only to the
+// peers which advertise the FeatureBatchedPushsync in the handshake. The
+// receiver handles the deliveries of the batch concurrently, each as if it
+// was delivered alone, and marks the failed ones with the error in their
+// receipts, so that the failure of a delivery does not fail the batch. The
+// receipts are written as soon as they are ready, together with the ones
+// ready at the same time, so that a slow delivery does not hold back the
+// receipts of the others.
+
+// maxBatchSize is the maximal number of the deliveries in a batch,
+// which keeps the batch within the maximal protobuf message size.
+const maxBatchSize = 16
+
+var errMissingReceipt = errors.New("missing receipt")
+
+// batchResult is the result of the batched delivery.
+type batchResult struct {
+	receipt *pb.Receipt
+	sent    bool
+	err     error
+}
+
+// batchedDelivery is the delivery waiting in the batch for its result.
+type batchedDelivery struct {
+	delivery *pb.Delivery
+	result   chan batchResult
+}
+
+// deliveryBatch is the batch of the deliveries to a peer.
+type deliveryBatch struct {
+	deliveries []*batchedDelivery
+	full       chan struct{} // closed when the batch reached the maximal size
+	sending    bool          // the deliveries are taken by the flush
+}
+
+// sendBatchFunc sends the deliveries to the peer in a stream and returns
+// their receipts. It reports whether the deliveries were sent, even if the
+// receipts were not received.
+type sendBatchFunc func(ctx context.Context, peer swarm.Address, deliveries []*pb.Delivery) ([]*pb.Receipt, bool, error)
+
+// batcher collects the deliveries to the peers into the batches.
+type batcher struct {
+	window time.Duration
+	send   sendBatchFunc
+
+	mu      sync.Mutex
+	batches map[string]*deliveryBatch // open batches by the peer address
+}
+
+func newBatcher(window time.Duration, send sendBatchFunc) *batcher {
+	return &batcher{
+		window:  window,
+		send:    send,
+		batches: make(map[string]*deliveryBatch),
+	}
+}
+
+// deliver adds the delivery to the open batch to the peer, or opens a new one
+// which is sent after the window or as soon as it is full, and waits for the
+// receipt. It reports whether the delivery was sent, even if it failed.
+// If the context is cancelled before the batch is sent, the delivery is
+// removed from the batch. Once the batch is sent, the result is awaited
+// regardless, so that the delivery the peer is paid for is not reported as
+// not sent.
+func (b *batcher) deliver(ctx context.Context, peer swarm.Address, delivery *pb.Delivery) (*pb.Receipt, bool, error) {
+	d := &batchedDelivery{
+		delivery: delivery,
+		result:   make(chan batchResult, 1),
+	}
+
+	b.mu.Lock()
+	key := peer.ByteString()
+	batch, ok := b.batches[key]
+	if !ok {
+		batch = &deliveryBatch{full: make(chan struct{})}
+		b.batches[key] = batch
+		go b.flush(peer, batch)
+	}
+	batch.deliveries = append(batch.deliveries, d)
+	if len(batch.deliveries) == maxBatchSize {
+		delete(b.batches, key)
+		close(batch.full)
+	}
+	b.mu.Unlock()
+
+	select {
+	case r := <-d.result:
+		return r.receipt, r.sent, r.err
+	case <-ctx.Done():
+	}
+
+	b.mu.Lock()
+	if !batch.sending {
+		for i, bd := range batch.deliveries {
+			if bd == d {
+				batch.deliveries = append(batch.deliveries[:i], batch.deliveries[i+1:]...)
+				break
+			}
+		}
+		b.mu.Unlock()
+		return nil, false, ctx.Err()
+	}
+	b.mu.Unlock()
+
+	// the send is bounded by its own timeout
+	r := <-d.result
+	return r.receipt, r.sent, r.err
+}
+
+// flush sends the batch after the window, or when it is full,
+// and passes the receipts to the waiting deliveries.
+func (b *batcher) flush(peer swarm.Address, batch *deliveryBatch) {
+	timer := time.NewTimer(b.window)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-batch.full:
+	}
+
+	b.mu.Lock()
+	if b.batches[peer.ByteString()] == batch {
+		delete(b.batches, peer.ByteString())
+	}
+	// the batch is neither appended to nor removed from once it is sending
+	batch.sending = true
+	pending := batch.deliveries
+	b.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	deliveries := make([]*pb.Delivery, len(pending))
+	for i, d := range pending {
+		deliveries[i] = d.delivery
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTTL)
+	defer cancel()
+
+	receipts, sent, err := b.send(ctx, peer, deliveries)
+
+	byAddress := make(map[string]*pb.Receipt, len(receipts))
+	for _, r := range receipts {
+		if r != nil {
+			byAddress[string(r.Address)] = r
+		}
+	}
+	for _, d := range pending {
+		addr := swarm.NewAddress(d.delivery.Address)
+		result := batchResult{sent: sent}
+		switch r, ok := byAddress[string(d.delivery.Address)]; {
+		case err != nil:
+			result.err = fmt.Errorf("chunk %s batched delivery to peer %s: %w", addr, peer, err)
+		case !ok:
+			result.err = fmt.Errorf("chunk %s receive receipt from peer %s: %w", addr, peer, errMissingReceipt)
+		case r.Err != "":
+			result.err = fmt.Errorf("chunk %s delivery failed on peer %s: %s", addr, peer, r.Err)
+		default:
+			result.receipt = r
+		}
+		d.result <- result
+	}
+}
+
+// batchingTo reports whether the deliveries to the peer are batched.
+func (ps *PushSync) batchingTo(peer swarm.Address) bool {
+	if ps.batcher == nil {
+		return false
+	}
+	fq, ok := ps.streamer.(p2p.FeatureQuerier)
+	if !ok {
+		return false
+	}
+	features, ok := fq.PeerFeatures(peer)
+	return ok && features.Has(p2p.FeatureBatchedPushsync)
+}
+
+// sendBatch implements the sendBatchFunc with the batch stream.
+func (ps *PushSync) sendBatch(ctx context.Context, peer swarm.Address, deliveries []*pb.Delivery) ([]*pb.Receipt, bool, error) {
+	stream, err := ps.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamBatchName)
+	if err != nil {
+		return nil, false, fmt.Errorf("new batch stream: %w", err)
+	}
+	defer stream.Close()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+	if err := w.WriteMsgWithContext(ctx, &pb.Deliveries{Deliveries: deliveries}); err != nil {
+		_ = stream.Reset()
+		return nil, false, fmt.Errorf("write deliveries: %w", err)
+	}
+	ps.metrics.TotalBatchesSent.Inc()
+
+	// the receipts come in as many messages as they are ready on the peer
+	receipts := make([]*pb.Receipt, 0, len(deliveries))
+	for len(receipts) < len(deliveries) {
+		var msg pb.Receipts
+		if err := r.ReadMsgWithContext(ctx, &msg); err != nil {
+			_ = stream.Reset()
+			return receipts, true, fmt.Errorf("read receipts: %w", err)
+		}
+		if len(msg.Receipts) == 0 {
+			_ = stream.Reset()
+			return receipts, true, errors.New("read receipts: empty receipts")
+		}
+		receipts = append(receipts, msg.Receipts...)
+	}
+	return receipts, true, nil
+}
+
+// batchHandler handles the batch of the deliveries from another node, each as
+// the handler does, and returns their receipts as they are ready, in which the
+// failed deliveries are marked with the error. The peer is debited for each
+// delivered chunk only after its receipt is sent.
+func (ps *PushSync) batchHandler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (err error) {
+	now := time.Now()
+	w, r := protobuf.NewWriterAndReader(stream)
+	ctx, cancel := context.WithTimeout(ctx, defaultTTL)
+	defer cancel()
+	defer func() {
+		if err != nil {
+			ps.metrics.TotalHandlerTime.WithLabelValues("failure").Observe(time.Since(now).Seconds())
+			ps.metrics.TotalHandlerErrors.Inc()
+			_ = stream.Reset()
+		} else {
+			ps.metrics.TotalHandlerTime.WithLabelValues("success").Observe(time.Since(now).Seconds())
+			_ = stream.FullClose()
+		}
+	}()
+
+	var batch pb.Deliveries
+	if err = r.ReadMsgWithContext(ctx, &batch); err != nil {
+		return fmt.Errorf("pushsync read deliveries: %w", err)
+	}
+	n := len(batch.Deliveries)
+	if n == 0 || n > maxBatchSize {
+		return fmt.Errorf("pushsync batch of %d deliveries", n)
+	}
+	ps.metrics.TotalBatchesReceived.Inc()
+
+	// readyReceipt is the receipt of the delivery ready to be written,
+	// with the channel receiving the result of the write
+	type readyReceipt struct {
+		receipt *pb.Receipt
+		written chan error
+	}
+
+	var (
+		errs  = make([]error, n)
+		ready = make(chan readyReceipt, n)
+		done  sync.WaitGroup // the deliveries handled
+	)
+	done.Add(n)
+	for i, d := range batch.Deliveries {
+		i, d := i, d
+		go func() {
+			defer done.Done()
+
+			responded := false
+			err := ps.handleDelivery(ctx, p, d, func(_ context.Context, receipt *pb.Receipt) error {
+				responded = true
+				written := make(chan error, 1)
+				ready <- readyReceipt{receipt: receipt, written: written}
+				// the peer is debited only after the receipt is sent
+				return <-written
+			})
+			if err != nil {
+				errs[i] = err
+				if !responded {
+					ready <- readyReceipt{
+						receipt: &pb.Receipt{Address: d.Address, Err: err.Error()},
+						written: make(chan error, 1),
+					}
+				}
+			}
+		}()
+	}
+
+	// the receipts ready together are written in one message
+	var writeErr error
+	for pending := n; pending > 0; {
+		group := []readyReceipt{<-ready}
+	collect:
+		for len(group) < pending {
+			select {
+			case rr := <-ready:
+				group = append(group, rr)
+			default:
+				break collect
+			}
+		}
+		pending -= len(group)
+
+		if writeErr == nil {
+			receipts := make([]*pb.Receipt, len(group))
+			for i, rr := range group {
+				receipts[i] = rr.receipt
+			}
+			writeErr = w.WriteMsgWithContext(ctx, &pb.Receipts{Receipts: receipts})
+		}
+		for _, rr := range group {
+			rr.written <- writeErr
+		}
+	}
+	done.Wait()
+
+	if writeErr != nil {
+		return fmt.Errorf("send receipts to peer %s: %w", p.Address, writeErr)
+	}
+	// the peer pushing the invalid chunks is blocklisted as by the handler
+	for _, err := range errs {
+		var blockErr *p2p.BlockPeerError
+		if errors.As(err, &blockErr) {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,9 +4,28 @@
 
 package pushsync
 
+import (
+	"context"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/pushsync/pb"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
 var (
 	ProtocolName    = protocolName
 	ProtocolVersion = protocolVersion
 	StreamName      = streamName
+	StreamBatchName = streamBatchName
 	NewPeerSkipList = newPeerSkipList
 )
+
+type Batcher = batcher
+
+func NewBatcher(window time.Duration, send func(context.Context, swarm.Address, []*pb.Delivery) ([]*pb.Receipt, bool, error)) *Batcher {
+	return newBatcher(window, send)
+}
+
+func (b *Batcher) Deliver(ctx context.Context, peer swarm.Address, delivery *pb.Delivery) (*pb.Receipt, bool, error) {
+	return b.deliver(ctx, peer, delivery)
+}
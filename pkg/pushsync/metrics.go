@@ -32,6 +32,8 @@ type metrics struct {
 	TotalReplicationFromDistantPeer prometheus.Counter
 	TotalReplicationFromClosestPeer prometheus.Counter
 	DuplicateReceipt                prometheus.Counter
+	TotalBatchesSent                prometheus.Counter
+	TotalBatchesReceived            prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -178,6 +180,18 @@ func newMetrics() metrics {
 			Name:      "duplicate_receipts",
 			Help:      "Number of receipts received after first successful receipt.",
 		}),
+		TotalBatchesSent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_batches_sent",
+			Help:      "Total batches of chunk deliveries sent.",
+		}),
+		TotalBatchesReceived: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_batches_received",
+			Help:      "Total batches of chunk deliveries received.",
+		}),
 	}
 }
 
@@ -26,6 +26,7 @@ type Delivery struct {
 	Address []byte `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
 	Data    []byte `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
 	Stamp   []byte `protobuf:"bytes,3,opt,name=Stamp,proto3" json:"Stamp,omitempty"`
+	Trace   bool   `protobuf:"varint,4,opt,name=Trace,proto3" json:"Trace,omitempty"`
 }
 
 func (m *Delivery) Reset()         { *m = Delivery{} }
@@ -82,10 +83,19 @@ func (m *Delivery) GetStamp() []byte {
 	return nil
 }
 
+func (m *Delivery) GetTrace() bool {
+	if m != nil {
+		return m.Trace
+	}
+	return false
+}
+
 type Receipt struct {
 	Address   []byte `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
 	Signature []byte `protobuf:"bytes,2,opt,name=Signature,proto3" json:"Signature,omitempty"`
 	Nonce     []byte `protobuf:"bytes,3,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	Path      []*Hop `protobuf:"bytes,4,rep,name=Path,proto3" json:"Path,omitempty"`
+	Err       string `protobuf:"bytes,5,opt,name=Err,proto3" json:"Err,omitempty"`
 }
 
 func (m *Receipt) Reset()         { *m = Receipt{} }
@@ -142,27 +152,192 @@ func (m *Receipt) GetNonce() []byte {
 	return nil
 }
 
+func (m *Receipt) GetPath() []*Hop {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+func (m *Receipt) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+type Hop struct {
+	Prefix []byte `protobuf:"bytes,1,opt,name=Prefix,proto3" json:"Prefix,omitempty"`
+	Bits   uint32 `protobuf:"varint,2,opt,name=Bits,proto3" json:"Bits,omitempty"`
+}
+
+func (m *Hop) Reset()         { *m = Hop{} }
+func (m *Hop) String() string { return proto.CompactTextString(m) }
+func (*Hop) ProtoMessage()    {}
+func (*Hop) Descriptor() ([]byte, []int) {
+	return fileDescriptor_723cf31bfc02bfd6, []int{2}
+}
+func (m *Hop) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Hop) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Hop.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Hop) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Hop.Merge(m, src)
+}
+func (m *Hop) XXX_Size() int {
+	return m.Size()
+}
+func (m *Hop) XXX_DiscardUnknown() {
+	xxx_messageInfo_Hop.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Hop proto.InternalMessageInfo
+
+func (m *Hop) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+func (m *Hop) GetBits() uint32 {
+	if m != nil {
+		return m.Bits
+	}
+	return 0
+}
+
+type Deliveries struct {
+	Deliveries []*Delivery `protobuf:"bytes,1,rep,name=Deliveries,proto3" json:"Deliveries,omitempty"`
+}
+
+func (m *Deliveries) Reset()         { *m = Deliveries{} }
+func (m *Deliveries) String() string { return proto.CompactTextString(m) }
+func (*Deliveries) ProtoMessage()    {}
+func (*Deliveries) Descriptor() ([]byte, []int) {
+	return fileDescriptor_723cf31bfc02bfd6, []int{3}
+}
+func (m *Deliveries) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Deliveries) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Deliveries.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Deliveries) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Deliveries.Merge(m, src)
+}
+func (m *Deliveries) XXX_Size() int {
+	return m.Size()
+}
+func (m *Deliveries) XXX_DiscardUnknown() {
+	xxx_messageInfo_Deliveries.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Deliveries proto.InternalMessageInfo
+
+func (m *Deliveries) GetDeliveries() []*Delivery {
+	if m != nil {
+		return m.Deliveries
+	}
+	return nil
+}
+
+type Receipts struct {
+	Receipts []*Receipt `protobuf:"bytes,1,rep,name=Receipts,proto3" json:"Receipts,omitempty"`
+}
+
+func (m *Receipts) Reset()         { *m = Receipts{} }
+func (m *Receipts) String() string { return proto.CompactTextString(m) }
+func (*Receipts) ProtoMessage()    {}
+func (*Receipts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_723cf31bfc02bfd6, []int{4}
+}
+func (m *Receipts) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Receipts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Receipts.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Receipts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Receipts.Merge(m, src)
+}
+func (m *Receipts) XXX_Size() int {
+	return m.Size()
+}
+func (m *Receipts) XXX_DiscardUnknown() {
+	xxx_messageInfo_Receipts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Receipts proto.InternalMessageInfo
+
+func (m *Receipts) GetReceipts() []*Receipt {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Delivery)(nil), "pushsync.Delivery")
 	proto.RegisterType((*Receipt)(nil), "pushsync.Receipt")
+	proto.RegisterType((*Hop)(nil), "pushsync.Hop")
+	proto.RegisterType((*Deliveries)(nil), "pushsync.Deliveries")
+	proto.RegisterType((*Receipts)(nil), "pushsync.Receipts")
 }
 
 func init() { proto.RegisterFile("pushsync.proto", fileDescriptor_723cf31bfc02bfd6) }
 
 var fileDescriptor_723cf31bfc02bfd6 = []byte{
-	// 181 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2b, 0x28, 0x2d, 0xce,
-	0x28, 0xae, 0xcc, 0x4b, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x80, 0xf1, 0x95, 0xfc,
-	0xb8, 0x38, 0x5c, 0x52, 0x73, 0x32, 0xcb, 0x52, 0x8b, 0x2a, 0x85, 0x24, 0xb8, 0xd8, 0x1d, 0x53,
-	0x52, 0x8a, 0x52, 0x8b, 0x8b, 0x25, 0x18, 0x15, 0x18, 0x35, 0x78, 0x82, 0x60, 0x5c, 0x21, 0x21,
-	0x2e, 0x16, 0x97, 0xc4, 0x92, 0x44, 0x09, 0x26, 0xb0, 0x30, 0x98, 0x2d, 0x24, 0xc2, 0xc5, 0x1a,
-	0x5c, 0x92, 0x98, 0x5b, 0x20, 0xc1, 0x0c, 0x16, 0x84, 0x70, 0x94, 0xc2, 0xb9, 0xd8, 0x83, 0x52,
-	0x93, 0x53, 0x33, 0x0b, 0x4a, 0xf0, 0x18, 0x27, 0xc3, 0xc5, 0x19, 0x9c, 0x99, 0x9e, 0x97, 0x58,
-	0x52, 0x5a, 0x94, 0x0a, 0x35, 0x13, 0x21, 0x00, 0x32, 0xd8, 0x2f, 0x3f, 0x2f, 0x39, 0x15, 0x66,
-	0x30, 0x98, 0xe3, 0x24, 0x73, 0xe2, 0x91, 0x1c, 0xe3, 0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9,
-	0x31, 0x4e, 0x78, 0x2c, 0xc7, 0x70, 0xe1, 0xb1, 0x1c, 0xc3, 0x8d, 0xc7, 0x72, 0x0c, 0x51, 0x4c,
-	0x05, 0x49, 0x49, 0x6c, 0x60, 0x7f, 0x19, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0xf1, 0xe1, 0x1a,
-	0xeb, 0xe9, 0x00, 0x00, 0x00,
+	// 314 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0xc1, 0x4e, 0x3a, 0x31,
+	0x10, 0xc6, 0x29, 0xbb, 0xc0, 0x32, 0xff, 0x3f, 0x46, 0x1b, 0x63, 0x7a, 0x20, 0xcd, 0xba, 0xa7,
+	0xbd, 0x48, 0x22, 0x9e, 0xbc, 0x29, 0xc1, 0x84, 0x93, 0x21, 0xc5, 0x93, 0xb7, 0xb2, 0x54, 0x69,
+	0xa2, 0x6c, 0xd3, 0x16, 0x23, 0xef, 0xe0, 0xc1, 0xc7, 0xf2, 0xc8, 0xd1, 0xa3, 0x81, 0x17, 0x31,
+	0x5b, 0xba, 0x2c, 0x27, 0x6f, 0xdf, 0xef, 0xeb, 0xcc, 0x7e, 0x33, 0xb3, 0x70, 0xa4, 0x96, 0x66,
+	0x6e, 0x56, 0x8b, 0xac, 0xa7, 0x74, 0x6e, 0x73, 0x1c, 0x95, 0x9c, 0xcc, 0x20, 0x1a, 0x8a, 0x17,
+	0xf9, 0x26, 0xf4, 0x0a, 0x13, 0x68, 0xdd, 0xce, 0x66, 0x5a, 0x18, 0x43, 0x50, 0x8c, 0xd2, 0xff,
+	0xac, 0x44, 0x8c, 0x21, 0x1c, 0x72, 0xcb, 0x49, 0xdd, 0xd9, 0x4e, 0xe3, 0x53, 0x68, 0x4c, 0x2c,
+	0x7f, 0x55, 0x24, 0x70, 0xe6, 0x0e, 0x0a, 0xf7, 0x41, 0xf3, 0x4c, 0x90, 0x30, 0x46, 0x69, 0xc4,
+	0x76, 0x90, 0x7c, 0x20, 0x68, 0x31, 0x91, 0x09, 0xa9, 0xec, 0x1f, 0x29, 0x5d, 0x68, 0x4f, 0xe4,
+	0xf3, 0x82, 0xdb, 0xa5, 0x16, 0x3e, 0xaa, 0x32, 0x8a, 0x2f, 0xdf, 0xe7, 0x8b, 0x4c, 0x94, 0x79,
+	0x0e, 0xf0, 0x39, 0x84, 0x63, 0x6e, 0xe7, 0x24, 0x8c, 0x83, 0xf4, 0x5f, 0xbf, 0xd3, 0xdb, 0x2f,
+	0x3a, 0xca, 0x15, 0x73, 0x4f, 0xf8, 0x18, 0x82, 0x3b, 0xad, 0x49, 0x23, 0x46, 0x69, 0x9b, 0x15,
+	0x32, 0xb9, 0x84, 0x60, 0x94, 0x2b, 0x7c, 0x06, 0xcd, 0xb1, 0x16, 0x4f, 0xf2, 0xdd, 0x0f, 0xe2,
+	0xa9, 0xd8, 0x76, 0x20, 0xad, 0x71, 0x23, 0x74, 0x98, 0xd3, 0xc9, 0x0d, 0x80, 0xbf, 0x93, 0x14,
+	0x06, 0xf7, 0x0f, 0x89, 0x20, 0x97, 0x8d, 0xab, 0xec, 0xf2, 0xa2, 0xec, 0xa0, 0x2a, 0xb9, 0x86,
+	0xc8, 0x9f, 0xc0, 0xe0, 0x8b, 0x4a, 0xfb, 0xee, 0x93, 0xaa, 0xdb, 0xbf, 0xb0, 0x7d, 0xc9, 0xa0,
+	0xfb, 0xb5, 0xa1, 0x68, 0xbd, 0xa1, 0xe8, 0x67, 0x43, 0xd1, 0xe7, 0x96, 0xd6, 0xd6, 0x5b, 0x5a,
+	0xfb, 0xde, 0xd2, 0xda, 0x63, 0x5d, 0x4d, 0xa7, 0x4d, 0xf7, 0x4f, 0xaf, 0x7e, 0x03, 0x00, 0x00,
+	0xff, 0xff, 0x1d, 0x87, 0x70, 0x42, 0xe5, 0x01, 0x00, 0x00,
 }
 
 func (m *Delivery) Marshal() (dAtA []byte, err error) {
@@ -185,6 +360,16 @@ func (m *Delivery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Trace {
+		i--
+		if m.Trace {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if len(m.Stamp) > 0 {
 		i -= len(m.Stamp)
 		copy(dAtA[i:], m.Stamp)
@@ -229,6 +414,27 @@ func (m *Receipt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Err) > 0 {
+		i -= len(m.Err)
+		copy(dAtA[i:], m.Err)
+		i = encodeVarintPushsync(dAtA, i, uint64(len(m.Err)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Path) > 0 {
+		for iNdEx := len(m.Path) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Path[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPushsync(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.Nonce) > 0 {
 		i -= len(m.Nonce)
 		copy(dAtA[i:], m.Nonce)
@@ -253,6 +459,115 @@ func (m *Receipt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *Hop) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Hop) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Hop) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Bits != 0 {
+		i = encodeVarintPushsync(dAtA, i, uint64(m.Bits))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Prefix) > 0 {
+		i -= len(m.Prefix)
+		copy(dAtA[i:], m.Prefix)
+		i = encodeVarintPushsync(dAtA, i, uint64(len(m.Prefix)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Deliveries) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Deliveries) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Deliveries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Deliveries) > 0 {
+		for iNdEx := len(m.Deliveries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Deliveries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPushsync(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Receipts) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Receipts) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Receipts) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Receipts) > 0 {
+		for iNdEx := len(m.Receipts) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Receipts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPushsync(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintPushsync(dAtA []byte, offset int, v uint64) int {
 	offset -= sovPushsync(v)
 	base := offset
@@ -282,6 +597,9 @@ func (m *Delivery) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPushsync(uint64(l))
 	}
+	if m.Trace {
+		n += 2
+	}
 	return n
 }
 
@@ -303,6 +621,62 @@ func (m *Receipt) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPushsync(uint64(l))
 	}
+	if len(m.Path) > 0 {
+		for _, e := range m.Path {
+			l = e.Size()
+			n += 1 + l + sovPushsync(uint64(l))
+		}
+	}
+	l = len(m.Err)
+	if l > 0 {
+		n += 1 + l + sovPushsync(uint64(l))
+	}
+	return n
+}
+
+func (m *Hop) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Prefix)
+	if l > 0 {
+		n += 1 + l + sovPushsync(uint64(l))
+	}
+	if m.Bits != 0 {
+		n += 1 + sovPushsync(uint64(m.Bits))
+	}
+	return n
+}
+
+func (m *Deliveries) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Deliveries) > 0 {
+		for _, e := range m.Deliveries {
+			l = e.Size()
+			n += 1 + l + sovPushsync(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Receipts) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Receipts) > 0 {
+		for _, e := range m.Receipts {
+			l = e.Size()
+			n += 1 + l + sovPushsync(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -443,6 +817,26 @@ func (m *Delivery) Unmarshal(dAtA []byte) error {
 				m.Stamp = []byte{}
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPushsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Trace = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPushsync(dAtA[iNdEx:])
@@ -595,6 +989,343 @@ func (m *Receipt) Unmarshal(dAtA []byte) error {
 				m.Nonce = []byte{}
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPushsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = append(m.Path, &Hop{})
+			if err := m.Path[len(m.Path)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Err", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPushsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Err = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPushsync(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Hop) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPushsync
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Hop: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Hop: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPushsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = append(m.Prefix[:0], dAtA[iNdEx:postIndex]...)
+			if m.Prefix == nil {
+				m.Prefix = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bits", wireType)
+			}
+			m.Bits = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPushsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bits |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPushsync(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Deliveries) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPushsync
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Deliveries: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Deliveries: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deliveries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPushsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Deliveries = append(m.Deliveries, &Delivery{})
+			if err := m.Deliveries[len(m.Deliveries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPushsync(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Receipts) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPushsync
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Receipts: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Receipts: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Receipts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPushsync
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPushsync
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Receipts = append(m.Receipts, &Receipt{})
+			if err := m.Receipts[len(m.Receipts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPushsync(dAtA[iNdEx:])
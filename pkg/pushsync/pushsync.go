@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -39,14 +40,16 @@ const (
 	protocolName    = "pushsync"
 	protocolVersion = "1.1.0"
 	streamName      = "pushsync"
+	streamBatchName = "pushsync-batch"
 )
 
 const (
-	defaultTTL     = 30 * time.Second // request time to live
-	p90TTL         = 5 * time.Second  // P90 request time to live
-	sanctionWait   = 5 * time.Minute
-	replicationTTL = 5 * time.Second // time to live for neighborhood replication
-	waitRefresh    = 600 * time.Millisecond
+	defaultTTL                    = 30 * time.Second // request time to live
+	p90TTL                        = 5 * time.Second  // P90 request time to live
+	sanctionWait                  = 5 * time.Minute
+	replicationTTL                = 5 * time.Second // time to live for neighborhood replication
+	waitRefresh                   = 600 * time.Millisecond
+	invalidChunkBlocklistDuration = time.Minute // base blocklist duration of the peers pushing invalid chunks
 )
 
 const (
@@ -70,6 +73,28 @@ type Receipt struct {
 	Address   swarm.Address
 	Signature []byte
 	Nonce     []byte
+	Path      []Hop // forwarding path from the storer back to the origin, if traced
+}
+
+// Hop is the overlay address prefix of a node on the forwarding path of
+// a chunk. The prefix is cut to the storage radius of the node so that
+// it identifies only the neighbourhood and not the node itself.
+type Hop struct {
+	Prefix []byte
+	Bits   uint8
+}
+
+// String returns the prefix as a string of bits.
+func (h Hop) String() string {
+	var b strings.Builder
+	for i := 0; i < int(h.Bits) && i/8 < len(h.Prefix); i++ {
+		if h.Prefix[i/8]&(0x80>>(i%8)) != 0 {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
 }
 
 type PushSync struct {
@@ -91,6 +116,8 @@ type PushSync struct {
 	includeSelf    bool
 	warmupPeriod   time.Time
 	skipList       *peerSkipList
+	traceReceipts  bool
+	batcher        *batcher // nil if the deliveries are not batched
 }
 
 type receiptResult struct {
@@ -101,7 +128,10 @@ type receiptResult struct {
 	err      error
 }
 
-func New(address swarm.Address, nonce []byte, streamer p2p.StreamerDisconnecter, storer storage.Putter, topology topology.Driver, rs postage.RadiusChecker, tagger *tags.Tags, includeSelf bool, unwrap func(swarm.Chunk), validStamp postage.ValidStampFn, logger log.Logger, accounting accounting.Interface, pricer pricer.Interface, signer crypto.Signer, tracer *tracing.Tracer, warmupTime time.Duration) *PushSync {
+// New returns the PushSync. The deliveries to the same peer within the batch
+// window are batched in a stream, if the peer supports it, the deliveries are
+// not batched if the window is not positive.
+func New(address swarm.Address, nonce []byte, streamer p2p.StreamerDisconnecter, storer storage.Putter, topology topology.Driver, rs postage.RadiusChecker, tagger *tags.Tags, includeSelf bool, unwrap func(swarm.Chunk), validStamp postage.ValidStampFn, logger log.Logger, accounting accounting.Interface, pricer pricer.Interface, signer crypto.Signer, tracer *tracing.Tracer, warmupTime time.Duration, traceReceipts bool, batchWindow time.Duration) *PushSync {
 	ps := &PushSync{
 		address:        address,
 		nonce:          nonce,
@@ -120,9 +150,13 @@ func New(address swarm.Address, nonce []byte, streamer p2p.StreamerDisconnecter,
 		signer:         signer,
 		skipList:       newPeerSkipList(),
 		warmupPeriod:   time.Now().Add(warmupTime),
+		traceReceipts:  traceReceipts,
 	}
 
 	ps.validStamp = ps.validStampWrapper(validStamp)
+	if batchWindow > 0 {
+		ps.batcher = newBatcher(batchWindow, ps.sendBatch)
+	}
 	return ps
 }
 
@@ -135,6 +169,10 @@ func (s *PushSync) Protocol() p2p.ProtocolSpec {
 				Name:    streamName,
 				Handler: s.handler,
 			},
+			{
+				Name:    streamBatchName,
+				Handler: s.batchHandler,
+			},
 		},
 	}
 }
@@ -160,6 +198,15 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 	if err = r.ReadMsgWithContext(ctx, &ch); err != nil {
 		return fmt.Errorf("pushsync read delivery: %w", err)
 	}
+
+	return ps.handleDelivery(ctx, p, &ch, func(ctx context.Context, receipt *pb.Receipt) error {
+		return w.WriteMsgWithContext(ctx, receipt)
+	})
+}
+
+// handleDelivery stores or forwards the chunk of the delivery and passes
+// its receipt to the respond function, after which the peer is debited.
+func (ps *PushSync) handleDelivery(ctx context.Context, p p2p.Peer, ch *pb.Delivery, respond func(context.Context, *pb.Receipt) error) (err error) {
 	ps.metrics.TotalReceived.Inc()
 
 	chunk := swarm.NewChunk(swarm.NewAddress(ch.Address), ch.Data)
@@ -181,10 +228,10 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 			go ps.unwrap(chunk)
 		}
 	} else if !soc.Valid(chunk) {
-		return swarm.ErrInvalidChunk
+		return p2p.NewBlockPeerError(invalidChunkBlocklistDuration, p2p.OffenseInvalidChunk, swarm.ErrInvalidChunk)
 	}
 
-	price := ps.pricer.Price(chunkAddress)
+	price := ps.pricer.Price(p.Address, chunkAddress)
 
 	// if the peer is closer to the chunk, AND it's a full node, we were selected for replication. Return early.
 	if p.FullNode {
@@ -237,7 +284,10 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 			}
 
 			receipt := pb.Receipt{Address: chunkAddress.Bytes(), Signature: signature, Nonce: ps.nonce}
-			err = w.WriteMsgWithContext(ctxd, &receipt)
+			if ch.Trace {
+				receipt.Path = []*pb.Hop{ps.traceHop()}
+			}
+			err = respond(ctxd, &receipt)
 			if err != nil {
 				return fmt.Errorf("send receipt to peer %s: %w", p.Address.String(), err)
 			}
@@ -263,7 +313,7 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 		}
 	}()
 
-	receipt, err := ps.pushToClosest(ctx, chunk, false, p.Address)
+	receipt, err := ps.pushToClosest(ctx, chunk, false, p.Address, ch.Trace)
 	if err != nil {
 		if errors.Is(err, topology.ErrWantSelf) {
 			storerNode = true
@@ -291,7 +341,10 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 			defer debit.Cleanup()
 
 			receipt := pb.Receipt{Address: chunkAddress.Bytes(), Signature: signature, Nonce: ps.nonce}
-			if err := w.WriteMsgWithContext(ctx, &receipt); err != nil {
+			if ch.Trace {
+				receipt.Path = []*pb.Hop{ps.traceHop()}
+			}
+			if err := respond(ctx, &receipt); err != nil {
 				return fmt.Errorf("send receipt to peer %s: %w", p.Address.String(), err)
 			}
 
@@ -311,8 +364,12 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 	}
 	defer debit.Cleanup()
 
+	if ch.Trace {
+		receipt.Path = append(receipt.Path, ps.traceHop())
+	}
+
 	// pass back the receipt
-	if err := w.WriteMsgWithContext(ctx, receipt); err != nil {
+	if err := respond(ctx, receipt); err != nil {
 		return fmt.Errorf("send receipt to peer %s: %w", p.Address.String(), err)
 	}
 
@@ -324,19 +381,39 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 // the validity of the receipt.
 func (ps *PushSync) PushChunkToClosest(ctx context.Context, ch swarm.Chunk) (*Receipt, error) {
 	ps.metrics.TotalOutgoing.Inc()
-	r, err := ps.pushToClosest(ctx, ch, true, swarm.ZeroAddress)
+	r, err := ps.pushToClosest(ctx, ch, true, swarm.ZeroAddress, ps.traceReceipts)
 	if err != nil {
 		ps.metrics.TotalOutgoingErrors.Inc()
 		return nil, err
 	}
+	var path []Hop
+	for _, h := range r.Path {
+		if h == nil || h.Bits > uint32(swarm.MaxBins) || len(h.Prefix) > swarm.HashSize {
+			continue
+		}
+		path = append(path, Hop{Prefix: h.Prefix, Bits: uint8(h.Bits)})
+	}
 	return &Receipt{
 		Address:   swarm.NewAddress(r.Address),
 		Signature: r.Signature,
 		Nonce:     r.Nonce,
+		Path:      path,
 	}, nil
 }
 
-func (ps *PushSync) pushToClosest(ctx context.Context, ch swarm.Chunk, origin bool, originAddr swarm.Address) (*pb.Receipt, error) {
+// traceHop returns the overlay address prefix of the node
+// cut to its storage radius, to be added to traced receipts.
+func (ps *PushSync) traceHop() *pb.Hop {
+	bits := ps.radiusChecker.StorageRadius()
+	prefix := make([]byte, (int(bits)+7)/8)
+	copy(prefix, ps.address.Bytes())
+	if r := bits % 8; r != 0 {
+		prefix[len(prefix)-1] &= 0xff << (8 - r)
+	}
+	return &pb.Hop{Prefix: prefix, Bits: uint32(bits)}
+}
+
+func (ps *PushSync) pushToClosest(ctx context.Context, ch swarm.Chunk, origin bool, originAddr swarm.Address, trace bool) (*pb.Receipt, error) {
 	span, logger, ctx := ps.tracer.StartSpanFromContext(ctx, "push-closest", ps.logger, opentracing.Tag{Key: "address", Value: ch.Address().String()})
 	defer span.Finish()
 	defer ps.skipList.PruneExpired()
@@ -432,7 +509,7 @@ func (ps *PushSync) pushToClosest(ctx context.Context, ch swarm.Chunk, origin bo
 			go func() {
 				ctxd, cancel := context.WithTimeout(ctx, defaultTTL)
 				defer cancel()
-				ps.pushPeer(ctxd, resultChan, doneChan, peer, ch, origin)
+				ps.pushPeer(ctxd, resultChan, doneChan, peer, ch, origin, trace)
 			}()
 
 			// reached the limit, do not set timer to retry
@@ -493,18 +570,18 @@ func (ps *PushSync) measurePushPeer(t time.Time, err error, origin bool) {
 	ps.metrics.PushToPeerTime.WithLabelValues(status).Observe(time.Since(t).Seconds())
 }
 
-func (ps *PushSync) pushPeer(ctx context.Context, resultChan chan<- receiptResult, doneChan <-chan struct{}, peer swarm.Address, ch swarm.Chunk, origin bool) {
+func (ps *PushSync) pushPeer(ctx context.Context, resultChan chan<- receiptResult, doneChan <-chan struct{}, peer swarm.Address, ch swarm.Chunk, origin, trace bool) {
 
 	var (
 		err     error
-		receipt pb.Receipt
+		receipt *pb.Receipt
 		pushed  bool
 		now     = time.Now()
 	)
 
 	defer func() {
 		select {
-		case resultChan <- receiptResult{pushTime: now, peer: peer, err: err, pushed: pushed, receipt: &receipt}:
+		case resultChan <- receiptResult{pushTime: now, peer: peer, err: err, pushed: pushed, receipt: receipt}:
 		case <-doneChan:
 			ps.metrics.DuplicateReceipt.Inc()
 		}
@@ -533,43 +610,30 @@ func (ps *PushSync) pushPeer(ctx context.Context, resultChan chan<- receiptResul
 		return
 	}
 
-	streamer, err := ps.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
-	if err != nil {
-		err = fmt.Errorf("new stream for peer %s: %w", peer, err)
-		return
-	}
-	defer streamer.Close()
-
-	w, r := protobuf.NewWriterAndReader(streamer)
-	err = w.WriteMsgWithContext(ctx, &pb.Delivery{
+	delivery := &pb.Delivery{
 		Address: ch.Address().Bytes(),
 		Data:    ch.Data(),
 		Stamp:   stamp,
-	})
-	if err != nil {
-		_ = streamer.Reset()
-		err = fmt.Errorf("chunk %s deliver to peer %s: %w", ch.Address(), peer, err)
-		return
+		Trace:   trace,
+	}
+	if ps.batchingTo(peer) {
+		receipt, pushed, err = ps.batcher.deliver(ctx, peer, delivery)
+	} else {
+		receipt, pushed, err = ps.deliver(ctx, peer, delivery)
 	}
 
-	ps.metrics.TotalSent.Inc()
-
-	pushed = true
+	if pushed {
+		ps.metrics.TotalSent.Inc()
 
-	// if you manage to get a tag, just increment the respective counter
-	t, err := ps.tagger.Get(ch.TagID())
-	if err == nil && t != nil {
-		err = t.Inc(tags.StateSent)
-		if err != nil {
-			err = fmt.Errorf("tag %d increment: %w", ch.TagID(), err)
-			return
+		// if you manage to get a tag, just increment the respective counter
+		if t, terr := ps.tagger.Get(ch.TagID()); terr == nil && t != nil {
+			if terr := t.Inc(tags.StateSent); terr != nil {
+				err = fmt.Errorf("tag %d increment: %w", ch.TagID(), terr)
+				return
+			}
 		}
 	}
-
-	err = r.ReadMsgWithContext(ctx, &receipt)
 	if err != nil {
-		_ = streamer.Reset()
-		err = fmt.Errorf("chunk %s receive receipt from peer %s: %w", ch.Address(), peer, err)
 		return
 	}
 
@@ -582,6 +646,31 @@ func (ps *PushSync) pushPeer(ctx context.Context, resultChan chan<- receiptResul
 	err = creditAction.Apply()
 }
 
+// deliver sends the delivery to the peer in its own stream and waits for the
+// receipt. It reports whether the delivery was sent, even if it failed.
+func (ps *PushSync) deliver(ctx context.Context, peer swarm.Address, delivery *pb.Delivery) (*pb.Receipt, bool, error) {
+	addr := swarm.NewAddress(delivery.Address)
+
+	stream, err := ps.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
+	if err != nil {
+		return nil, false, fmt.Errorf("new stream for peer %s: %w", peer, err)
+	}
+	defer stream.Close()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+	if err := w.WriteMsgWithContext(ctx, delivery); err != nil {
+		_ = stream.Reset()
+		return nil, false, fmt.Errorf("chunk %s deliver to peer %s: %w", addr, peer, err)
+	}
+
+	var receipt pb.Receipt
+	if err := r.ReadMsgWithContext(ctx, &receipt); err != nil {
+		_ = stream.Reset()
+		return nil, true, fmt.Errorf("chunk %s receive receipt from peer %s: %w", addr, peer, err)
+	}
+	return &receipt, true, nil
+}
+
 func (ps *PushSync) pushToNeighbourhood(ctx context.Context, skiplist []swarm.Address, ch swarm.Chunk, origin bool, originAddr swarm.Address) {
 	count := 0
 	// Push the chunk to some peers in the neighborhood in parallel for replication.
@@ -21,11 +21,11 @@ import (
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/p2p/protobuf"
 	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/postage"
 	bsMock "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
 	pricermock "github.com/ethersphere/bee/pkg/pricer/mock"
 	"github.com/ethersphere/bee/pkg/pushsync"
 	"github.com/ethersphere/bee/pkg/pushsync/pb"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage"
 	mocks "github.com/ethersphere/bee/pkg/storage/mock"
 	testingc "github.com/ethersphere/bee/pkg/storage/testing"
@@ -295,6 +295,194 @@ func TestPushChunkToClosest(t *testing.T) {
 	}
 }
 
+// TestPushChunkToClosestBatched tests that the chunks pushed concurrently to the
+// peer advertising the batched pushsync are delivered in a single batch stream
+// and that each pushed chunk gets its receipt from the batch.
+func TestPushChunkToClosestBatched(t *testing.T) {
+	t.Parallel()
+
+	chunks := []swarm.Chunk{
+		testingc.FixtureChunk("7000"),
+		testingc.FixtureChunk("0025"),
+		testingc.FixtureChunk("02c2"),
+	}
+
+	pivotNode := swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000")
+	closestPeer := swarm.MustParseHexAddress("6000000000000000000000000000000000000000000000000000000000000000")
+
+	psPeer, peerStorer, _, peerAccounting := createPushSyncNode(t, closestPeer, defaultPrices, nil, nil, defaultSigner, mock.WithClosestPeerErr(topology.ErrWantSelf))
+
+	recorder := streamtest.New(streamtest.WithProtocols(psPeer.Protocol()), streamtest.WithBaseAddr(pivotNode))
+
+	storer := mocks.NewStorer()
+	testutil.CleanupCloser(t, storer)
+	validStamp := func(ch swarm.Chunk, stamp []byte) (swarm.Chunk, error) {
+		return ch, nil
+	}
+	streamer := batchingStreamer{streamtest.NewRecorderDisconnecter(recorder)}
+	psPivot := pushsync.New(pivotNode, blockHash.Bytes(), streamer, storer, mock.NewTopologyDriver(mock.WithClosestPeer(closestPeer)), bsMock.New(), tags.NewTags(nil, log.Noop), true, func(swarm.Chunk) {}, validStamp, log.Noop, accountingmock.NewAccounting(), pricermock.NewMockService(fixedPrice, fixedPrice), defaultSigner, nil, -1, false, 500*time.Millisecond)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(chunks))
+	for _, ch := range chunks {
+		ch := ch
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			receipt, err := psPivot.PushChunkToClosest(context.Background(), ch)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !ch.Address().Equal(receipt.Address) {
+				errs <- errors.New("invalid receipt")
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	records := recorder.WaitRecords(t, closestPeer, pushsync.ProtocolName, pushsync.ProtocolVersion, pushsync.StreamBatchName, 1, 5)
+
+	messages, err := protobuf.ReadMessages(
+		bytes.NewReader(records[0].In()),
+		func() protobuf.Message { return new(pb.Deliveries) },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("got %d deliveries messages, want 1", len(messages))
+	}
+	if got := len(messages[0].(*pb.Deliveries).Deliveries); got != len(chunks) {
+		t.Fatalf("got %d batched deliveries, want %d", got, len(chunks))
+	}
+
+	messages, err = protobuf.ReadMessages(
+		bytes.NewReader(records[0].Out()),
+		func() protobuf.Message { return new(pb.Receipts) },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the receipts are written as they are ready, in one or more messages
+	var got int
+	for _, m := range messages {
+		got += len(m.(*pb.Receipts).Receipts)
+	}
+	if got != len(chunks) {
+		t.Fatalf("got %d batched receipts, want %d", got, len(chunks))
+	}
+
+	if records := recorder.WaitRecords(t, closestPeer, pushsync.ProtocolName, pushsync.ProtocolVersion, pushsync.StreamName, 0, 1); len(records) != 0 {
+		t.Fatalf("got %d single deliveries, want none", len(records))
+	}
+
+	for _, ch := range chunks {
+		if _, err := peerStorer.Get(context.Background(), storage.ModeGetRequest, ch.Address()); err != nil {
+			t.Fatalf("chunk %s not stored: %v", ch.Address(), err)
+		}
+	}
+
+	balance, err := peerAccounting.Balance(pivotNode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(fixedPrice) * int64(len(chunks)); balance.Int64() != want {
+		t.Fatalf("unexpected balance on peer. want %d got %d", want, balance)
+	}
+}
+
+// TestBatcherCancel tests that the delivery cancelled before its batch is sent
+// is removed from the batch, and that the delivery cancelled while the batch
+// is sent waits for its receipt, as it is paid for.
+func TestBatcherCancel(t *testing.T) {
+	t.Parallel()
+
+	peer := swarm.MustParseHexAddress("6000000000000000000000000000000000000000000000000000000000000000")
+	receipts := func(deliveries []*pb.Delivery) []*pb.Receipt {
+		rs := make([]*pb.Receipt, len(deliveries))
+		for i, d := range deliveries {
+			rs[i] = &pb.Receipt{Address: d.Address}
+		}
+		return rs
+	}
+
+	t.Run("before send", func(t *testing.T) {
+		t.Parallel()
+
+		sent := make(chan []*pb.Delivery, 1)
+		b := pushsync.NewBatcher(100*time.Millisecond, func(_ context.Context, _ swarm.Address, deliveries []*pb.Delivery) ([]*pb.Receipt, bool, error) {
+			sent <- deliveries
+			return receipts(deliveries), true, nil
+		})
+
+		cancelled := &pb.Delivery{Address: testingc.FixtureChunk("7000").Address().Bytes()}
+		delivered := &pb.Delivery{Address: testingc.FixtureChunk("0025").Address().Bytes()}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		errc := make(chan error, 1)
+		go func() {
+			_, _, err := b.Deliver(context.Background(), peer, delivered)
+			errc <- err
+		}()
+
+		_, pushed, err := b.Deliver(ctx, peer, cancelled)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+		}
+		if pushed {
+			t.Fatal("cancelled delivery reported as sent")
+		}
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+
+		deliveries := <-sent
+		if len(deliveries) != 1 || !bytes.Equal(deliveries[0].Address, delivered.Address) {
+			t.Fatalf("got %d sent deliveries, want only the not cancelled one", len(deliveries))
+		}
+	})
+
+	t.Run("while sending", func(t *testing.T) {
+		t.Parallel()
+
+		sending := make(chan struct{})
+		release := make(chan struct{})
+		b := pushsync.NewBatcher(10*time.Millisecond, func(_ context.Context, _ swarm.Address, deliveries []*pb.Delivery) ([]*pb.Receipt, bool, error) {
+			close(sending)
+			<-release
+			return receipts(deliveries), true, nil
+		})
+
+		delivery := &pb.Delivery{Address: testingc.FixtureChunk("7000").Address().Bytes()}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-sending
+			cancel()
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+		}()
+
+		receipt, pushed, err := b.Deliver(ctx, peer, delivery)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pushed {
+			t.Fatal("sent delivery reported as not sent")
+		}
+		if !bytes.Equal(receipt.Address, delivery.Address) {
+			t.Fatal("invalid receipt")
+		}
+	})
+}
+
 func TestPushChunkToNextClosest(t *testing.T) {
 	t.Parallel()
 
@@ -675,6 +863,53 @@ func TestSignsReceipt(t *testing.T) {
 	}
 }
 
+// TestPushChunkToClosestTrace tests that a traced receipt carries the
+// storage radius prefixes of the storer and the forwarder in that order.
+func TestPushChunkToClosestTrace(t *testing.T) {
+	t.Parallel()
+
+	chunk := testingc.FixtureChunk("7000") // base 0111
+
+	pivotNode := swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000")  // base is 0000
+	forwarder := swarm.MustParseHexAddress("6000000000000000000000000000000000000000000000000000000000000000")  // binary 0110
+	storerNode := swarm.MustParseHexAddress("7f00000000000000000000000000000000000000000000000000000000000000") // binary 0111 1111
+
+	for _, tc := range []struct {
+		name  string
+		trace bool
+		want  []string
+	}{
+		{name: "disabled"},
+		{name: "enabled", trace: true, want: []string{"011111", "01"}},
+	} {
+		psStorer := createTracingPushSyncNode(t, storerNode, 6, nil, false, mock.WithClosestPeerErr(topology.ErrWantSelf))
+		storerRecorder := streamtest.New(streamtest.WithProtocols(psStorer.Protocol()), streamtest.WithBaseAddr(forwarder))
+
+		psForwarder := createTracingPushSyncNode(t, forwarder, 2, storerRecorder, false, mock.WithClosestPeer(storerNode))
+		forwarderRecorder := streamtest.New(streamtest.WithProtocols(psForwarder.Protocol()), streamtest.WithBaseAddr(pivotNode))
+
+		psPivot := createTracingPushSyncNode(t, pivotNode, 0, forwarderRecorder, tc.trace, mock.WithClosestPeer(forwarder))
+
+		receipt, err := psPivot.PushChunkToClosest(context.Background(), chunk)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		var got []string
+		for _, h := range receipt.Path {
+			got = append(got, h.String())
+		}
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: got path %v, want %v", tc.name, got, tc.want)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Fatalf("%s: got path %v, want %v", tc.name, got, tc.want)
+			}
+		}
+	}
+}
+
 func TestPeerSkipList(t *testing.T) {
 	t.Parallel()
 	skipList := pushsync.NewPeerSkipList()
@@ -797,8 +1032,7 @@ func createPushSyncNodeWithAccounting(t *testing.T, addr swarm.Address, prices p
 	testutil.CleanupCloser(t, storer)
 
 	mockTopology := mock.NewTopologyDriver(mockOpts...)
-	mockStatestore := statestore.NewStateStore()
-	mtag := tags.NewTags(mockStatestore, logger)
+	mtag := tags.NewTags(nil, logger)
 
 	mockPricer := pricermock.NewMockService(prices.price, prices.peerPrice)
 
@@ -813,7 +1047,20 @@ func createPushSyncNodeWithAccounting(t *testing.T, addr swarm.Address, prices p
 
 	bs := bsMock.New()
 
-	return pushsync.New(addr, blockHash.Bytes(), recorderDisconnecter, storer, mockTopology, bs, mtag, true, unwrap, validStamp, logger, acct, mockPricer, signer, nil, -1), storer, mtag
+	return pushsync.New(addr, blockHash.Bytes(), recorderDisconnecter, storer, mockTopology, bs, mtag, true, unwrap, validStamp, logger, acct, mockPricer, signer, nil, -1, false, 0), storer, mtag
+}
+
+func createTracingPushSyncNode(t *testing.T, addr swarm.Address, radius uint8, recorder *streamtest.Recorder, trace bool, mockOpts ...mock.Option) *pushsync.PushSync {
+	t.Helper()
+	storer := mocks.NewStorer()
+	testutil.CleanupCloser(t, storer)
+
+	validStamp := func(ch swarm.Chunk, stamp []byte) (swarm.Chunk, error) {
+		return ch, nil
+	}
+	bs := bsMock.New(bsMock.WithReserveState(&postage.ReserveState{StorageRadius: radius}))
+
+	return pushsync.New(addr, blockHash.Bytes(), streamtest.NewRecorderDisconnecter(recorder), storer, mock.NewTopologyDriver(mockOpts...), bs, tags.NewTags(nil, log.Noop), true, func(swarm.Chunk) {}, validStamp, log.Noop, accountingmock.NewAccounting(), pricermock.NewMockService(fixedPrice, fixedPrice), defaultSigner, nil, -1, trace, 0)
 }
 
 func waitOnRecordAndTest(t *testing.T, peer swarm.Address, recorder *streamtest.Recorder, add swarm.Address, data []byte) {
@@ -866,6 +1113,15 @@ func waitOnRecordAndTest(t *testing.T, peer swarm.Address, recorder *streamtest.
 	}
 }
 
+// batchingStreamer is the streamer whose peers advertise the batched pushsync.
+type batchingStreamer struct {
+	*streamtest.RecorderDisconnecter
+}
+
+func (batchingStreamer) PeerFeatures(swarm.Address) (p2p.Features, bool) {
+	return p2p.FeatureBatchedPushsync, true
+}
+
 func chanFunc(c chan<- struct{}) func(swarm.Chunk) {
 	return func(_ swarm.Chunk) {
 		c <- struct{}{}
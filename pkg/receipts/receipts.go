@@ -0,0 +1,132 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package receipts keeps the pushsync receipts of the uploaded content, so
+// that the publishers can later prove to third parties that the content was
+// accepted by the network. A receipt is the signature of the chunk address
+// by the storer node, from which the overlay address of the storer can be
+// recovered together with the nonce and the network id.
+package receipts
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	storePrefix = "receipts"
+
+	// DefaultRecentCapacity is the number of the receipts of the recently
+	// pushed chunks kept in memory until the content they belong to is known.
+	DefaultRecentCapacity = 10000
+	// maxReceipts is the maximum number of the receipts kept per reference.
+	maxReceipts = 16
+)
+
+// ErrNotFound is returned when there are no receipts kept for the reference.
+var ErrNotFound = errors.New("receipts not found")
+
+// Receipt is the proof that the storer node accepted the chunk.
+type Receipt struct {
+	Address   swarm.Address `json:"address"`
+	Storer    swarm.Address `json:"storer"`
+	Signature []byte        `json:"signature"`
+	Nonce     []byte        `json:"nonce"`
+	Timestamp time.Time     `json:"timestamp"` // local time the receipt was received at
+}
+
+func receiptsKey(addr swarm.Address) string {
+	return fmt.Sprintf("%s-%s", storePrefix, addr)
+}
+
+// Store persists the receipts of the watched references. The receipts of
+// the chunks which are not watched yet are kept in memory only, as the
+// root chunk of the content is usually pushed before the upload completes
+// and the reference of the content becomes known.
+type Store struct {
+	mu     sync.Mutex
+	store  storage.StateStorer
+	recent *lru.Cache
+}
+
+// New creates a new Store which keeps in memory at most
+// the given number of the receipts of the not watched chunks.
+func New(store storage.StateStorer, recentCapacity int) (*Store, error) {
+	recent, err := lru.New(recentCapacity)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{store: store, recent: recent}, nil
+}
+
+// Put records the receipt. It is persisted if the address of the
+// chunk is watched, otherwise it is kept with the recent receipts.
+func (s *Store) Put(r Receipt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receipts, err := s.get(r.Address)
+	if errors.Is(err, ErrNotFound) {
+		s.recent.Add(r.Address.ByteString(), r)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	receipts = append(receipts, r)
+	if len(receipts) > maxReceipts {
+		receipts = receipts[len(receipts)-maxReceipts:]
+	}
+	return s.store.Put(receiptsKey(r.Address), receipts)
+}
+
+// Watch persists the receipts of the reference from now on, including
+// the recent receipt of the reference if it was pushed already.
+// Repeating calls of this method are idempotent.
+func (s *Store) Watch(addr swarm.Address) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch _, err := s.get(addr); {
+	case err == nil:
+		return nil
+	case !errors.Is(err, ErrNotFound):
+		return err
+	}
+
+	receipts := []Receipt{}
+	if v, ok := s.recent.Get(addr.ByteString()); ok {
+		receipts = append(receipts, v.(Receipt))
+		s.recent.Remove(addr.ByteString())
+	}
+	return s.store.Put(receiptsKey(addr), receipts)
+}
+
+// Get returns the receipts kept for the reference, the
+// list is empty if the reference is watched but not pushed yet.
+func (s *Store) Get(addr swarm.Address) ([]Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.get(addr)
+}
+
+func (s *Store) get(addr swarm.Address) ([]Receipt, error) {
+	var receipts []Receipt
+	err := s.store.Get(receiptsKey(addr), &receipts)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
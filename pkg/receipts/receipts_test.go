@@ -0,0 +1,92 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package receipts_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/receipts"
+	"github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func newReceipt(t *testing.T, addr swarm.Address) receipts.Receipt {
+	t.Helper()
+
+	return receipts.Receipt{
+		Address:   addr,
+		Storer:    swarm.RandAddress(t),
+		Signature: []byte{1},
+		Nonce:     []byte{2},
+		Timestamp: time.Now().UTC().Round(0),
+	}
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := receipts.New(mock.NewStateStore(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		pushedFirst  = swarm.RandAddress(t)
+		pushedLater  = swarm.RandAddress(t)
+		evicted      = swarm.RandAddress(t)
+		notWatched   = swarm.RandAddress(t)
+		firstReceipt = newReceipt(t, pushedFirst)
+	)
+
+	// the receipt of the root chunk pushed before the upload completed
+	if err := store.Put(firstReceipt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(pushedFirst); !errors.Is(err, receipts.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, receipts.ErrNotFound)
+	}
+	if err := store.Watch(pushedFirst); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(pushedFirst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || !got[0].Storer.Equal(firstReceipt.Storer) || !got[0].Timestamp.Equal(firstReceipt.Timestamp) {
+		t.Fatalf("got receipts %+v, want %+v", got, firstReceipt)
+	}
+
+	// the receipt of the root chunk pushed after the upload completed
+	if err := store.Watch(pushedLater); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get(pushedLater); err != nil || len(got) != 0 {
+		t.Fatalf("got receipts %+v and error %v, want none", got, err)
+	}
+	for i := 0; i < 20; i++ {
+		if err := store.Put(newReceipt(t, pushedLater)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, err := store.Get(pushedLater); err != nil || len(got) != 16 {
+		t.Fatalf("got %d receipts and error %v, want 16", len(got), err)
+	}
+
+	// the recent receipts are bounded
+	if err := store.Put(newReceipt(t, evicted)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(newReceipt(t, notWatched)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Watch(evicted); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get(evicted); err != nil || len(got) != 0 {
+		t.Fatalf("got receipts %+v and error %v, want none", got, err)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retrieval
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// maxEarningChunks is the maximum number of the chunks whose earnings are
+// tracked, the chunks served least recently are dropped first.
+const maxEarningChunks = 10000
+
+// ChunkEarnings is the amount the node earned by serving the chunk
+// from its local store to the retrieval requests of the peers.
+type ChunkEarnings struct {
+	Address    swarm.Address
+	Earned     *big.Int
+	Retrievals uint64
+	LastServed time.Time
+	Proximity  uint8 // proximity order of the chunk to the node
+}
+
+// earnings keeps the earnings of the recently served chunks
+// and the total earned by serving the chunks from the local store.
+type earnings struct {
+	mu     sync.Mutex
+	total  *big.Int
+	chunks *lru.Cache
+	now    func() time.Time
+}
+
+func newEarnings() *earnings {
+	chunks, _ := lru.New(maxEarningChunks) // error only on non-positive size
+	return &earnings{
+		total:  new(big.Int),
+		chunks: chunks,
+		now:    time.Now,
+	}
+}
+
+// record adds the price debited to the peer for the chunk to its earnings.
+func (e *earnings) record(addr swarm.Address, po uint8, price uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	amount := new(big.Int).SetUint64(price)
+	e.total.Add(e.total, amount)
+
+	c, ok := e.chunks.Get(addr.ByteString())
+	if !ok {
+		_ = e.chunks.Add(addr.ByteString(), &ChunkEarnings{
+			Address:    addr,
+			Earned:     amount,
+			Retrievals: 1,
+			LastServed: e.now(),
+			Proximity:  po,
+		})
+		return
+	}
+	ce := c.(*ChunkEarnings)
+	ce.Earned.Add(ce.Earned, amount)
+	ce.Retrievals++
+	ce.LastServed = e.now()
+}
+
+// top returns the total earned and at most limit tracked chunks ranked by
+// their earnings, the more recently served first on ties.
+func (e *earnings) top(limit int) (*big.Int, []ChunkEarnings) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	keys := e.chunks.Keys()
+	chunks := make([]ChunkEarnings, 0, len(keys))
+	for i := len(keys) - 1; i >= 0; i-- {
+		c, ok := e.chunks.Peek(keys[i])
+		if !ok {
+			continue
+		}
+		ce := *c.(*ChunkEarnings)
+		ce.Earned = new(big.Int).Set(ce.Earned)
+		chunks = append(chunks, ce)
+	}
+	sort.SliceStable(chunks, func(i, j int) bool {
+		return chunks[i].Earned.Cmp(chunks[j].Earned) > 0
+	})
+	if limit >= 0 && len(chunks) > limit {
+		chunks = chunks[:limit]
+	}
+	return new(big.Int).Set(e.total), chunks
+}
+
+// Earnings returns the total amount the node earned by serving the chunks
+// from its local store and at most limit chunks which earned the most, of
+// the chunks served most recently. The negative limit returns all of them.
+func (s *Service) Earnings(limit int) (*big.Int, []ChunkEarnings) {
+	return s.earnings.top(limit)
+}
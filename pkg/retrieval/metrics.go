@@ -22,6 +22,9 @@ type metrics struct {
 	ChunkPrice            prometheus.Summary
 	TotalErrors           prometheus.Counter
 	ChunkRetrieveTime     prometheus.Histogram
+
+	NeighbourhoodFallbackCounter   prometheus.Counter
+	NeighbourhoodFallbackRetrieved prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -72,6 +75,18 @@ func newMetrics() metrics {
 				Help:      "Histogram for time taken to retrieve a chunk.",
 			},
 		),
+		NeighbourhoodFallbackCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "neighbourhood_fallback_count",
+			Help:      "Number of failed retrievals retried directly in the neighbourhood of the chunk.",
+		}),
+		NeighbourhoodFallbackRetrieved: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "neighbourhood_fallback_retrieved",
+			Help:      "Number of chunks retrieved directly from the neighbourhood of the chunk.",
+		}),
 	}
 }
 
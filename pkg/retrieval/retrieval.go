@@ -16,6 +16,7 @@ import (
 
 	"github.com/ethersphere/bee/pkg/accounting"
 	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/chunktrace"
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/p2p/protobuf"
@@ -59,10 +60,16 @@ type retrievalResult struct {
 	retrieveAttempted bool
 }
 
+// PeerSuggester suggests the peers to retrieve the chunks from.
+type PeerSuggester interface {
+	topology.ClosestPeerer
+	topology.NeighborhoodDepther
+}
+
 type Service struct {
 	addr          swarm.Address
 	streamer      p2p.Streamer
-	peerSuggester topology.ClosestPeerer
+	peerSuggester PeerSuggester
 	storer        storage.Storer
 	singleflight  singleflight.Group
 	logger        log.Logger
@@ -72,9 +79,11 @@ type Service struct {
 	tracer        *tracing.Tracer
 	caching       bool
 	validStamp    postage.ValidStampFn
+	throughput    *throughput
+	earnings      *earnings
 }
 
-func New(addr swarm.Address, storer storage.Storer, streamer p2p.Streamer, chunkPeerer topology.ClosestPeerer, logger log.Logger, accounting accounting.Interface, pricer pricer.Interface, tracer *tracing.Tracer, forwarderCaching bool, validStamp postage.ValidStampFn) *Service {
+func New(addr swarm.Address, storer storage.Storer, streamer p2p.Streamer, chunkPeerer PeerSuggester, logger log.Logger, accounting accounting.Interface, pricer pricer.Interface, tracer *tracing.Tracer, forwarderCaching bool, validStamp postage.ValidStampFn) *Service {
 	return &Service{
 		addr:          addr,
 		streamer:      streamer,
@@ -87,6 +96,8 @@ func New(addr swarm.Address, storer storage.Storer, streamer p2p.Streamer, chunk
 		tracer:        tracer,
 		caching:       forwarderCaching,
 		validStamp:    validStamp,
+		throughput:    newThroughput(),
+		earnings:      newEarnings(),
 	}
 }
 
@@ -109,6 +120,9 @@ const (
 	resetOverdraftDur    = time.Millisecond * 600
 	maxRetrievedErrors   = 32
 	originSuffix         = "_origin"
+	// maxNeighbourhoodPeers is the number of the peers in the neighbourhood
+	// of the chunk asked in parallel when the forwarding retrieval fails.
+	maxNeighbourhoodPeers = 4
 )
 
 func (s *Service) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.Address) (swarm.Chunk, error) {
@@ -126,6 +140,9 @@ func (s *Service) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.
 	// topCtx is passing the tracing span to the first singleflight call
 	topCtx := ctx
 	v, _, err := s.singleflight.Do(ctx, flightRoute, func(ctx context.Context) (interface{}, error) {
+		if r := chunktrace.RecordFromContext(topCtx); r != nil {
+			ctx = chunktrace.WithRecord(ctx, r)
+		}
 
 		sp := new(skippeers.List)
 
@@ -158,6 +175,17 @@ func (s *Service) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.
 
 		retry()
 
+		// giveUp asks the peers in the neighbourhood of the chunk directly
+		// before giving up the retrieval originated at this node
+		giveUp := func() (interface{}, error) {
+			if origin {
+				if chunk, err := s.retrieveFromNeighbourhood(ctx, addr); err == nil {
+					return chunk, nil
+				}
+			}
+			return nil, storage.ErrNotFound
+		}
+
 		inflight := 0
 
 		for retrievedErrorsLeft > 0 {
@@ -170,6 +198,7 @@ func (s *Service) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.
 			case <-retryC:
 
 				s.metrics.PeerRequestCounter.Inc()
+				chunktrace.AddPeer(ctx)
 
 				inflight++
 
@@ -190,7 +219,7 @@ func (s *Service) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.
 					if sp.OverdraftListEmpty() { // no peer is available, and none skipped due to overdraft errors
 						if inflight == 0 {
 							loggerV1.Debug("no peers left to retry", "chunk_address", addr)
-							return nil, storage.ErrNotFound
+							return giveUp()
 						}
 						continue
 					}
@@ -222,7 +251,7 @@ func (s *Service) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.
 		}
 
 		loggerV1.Debug("no peers left to retry", "chunk_address", addr)
-		return nil, storage.ErrNotFound
+		return giveUp()
 	})
 	if err != nil {
 		s.logger.Debug("retrieval failed", "chunk_address", addr, "error", err)
@@ -234,7 +263,6 @@ func (s *Service) RetrieveChunk(ctx context.Context, addr, sourcePeerAddr swarm.
 
 func (s *Service) retrieveChunk(ctx context.Context, done chan struct{}, result chan retrievalResult, addr swarm.Address, sp *skippeers.List, isOrigin bool) {
 
-	startTimer := time.Now()
 	// allow upstream requests if this node is the source of the request
 	// i.e. the request was not forwarded, to improve retrieval
 	// if this node is the closest to he chunk but still does not contain it
@@ -266,6 +294,15 @@ func (s *Service) retrieveChunk(ctx context.Context, done chan struct{}, result
 		return
 	}
 
+	chunk, retrieveAttempted, err = s.retrieveChunkFromPeer(ctx, addr, peer, sp, isOrigin)
+}
+
+// retrieveChunkFromPeer requests the chunk directly from the peer. The peer
+// is added to the skip list, or to its overdraft list if the chunk can not
+// be paid for.
+func (s *Service) retrieveChunkFromPeer(ctx context.Context, addr, peer swarm.Address, sp *skippeers.List, isOrigin bool) (chunk swarm.Chunk, retrieveAttempted bool, err error) {
+	startTimer := time.Now()
+
 	// compute the peer's price for this chunk for price header
 	chunkPrice := s.pricer.PeerPrice(peer, addr)
 
@@ -304,13 +341,16 @@ func (s *Service) retrieveChunk(ctx context.Context, done chan struct{}, result
 	}
 
 	retrieveAttempted = true
+	requestTime := time.Now()
 
 	var d pb.Delivery
 	err = r.ReadMsgWithContext(ctx, &d)
 	if err != nil {
+		s.throughput.record(peer, 0, time.Since(requestTime))
 		err = fmt.Errorf("read delivery: %w peer %s", err, peer.String())
 		return
 	}
+	s.throughput.record(peer, len(d.Data)+len(d.Stamp), time.Since(requestTime))
 	s.metrics.ChunkRetrieveTime.Observe(time.Since(startTimer).Seconds())
 	s.metrics.TotalRetrieved.Inc()
 
@@ -335,33 +375,126 @@ func (s *Service) retrieveChunk(ctx context.Context, done chan struct{}, result
 		return
 	}
 	s.metrics.ChunkPrice.Observe(float64(chunkPrice))
+	return
+}
+
+// retrieveFromNeighbourhood requests the chunk directly and in parallel from
+// the connected peers in the neighbourhood of the chunk, which are expected
+// to store it. It is the fallback for the failed forwarding retrieval, when
+// the forwarding route to the neighbourhood is broken.
+func (s *Service) retrieveFromNeighbourhood(ctx context.Context, addr swarm.Address) (swarm.Chunk, error) {
+	loggerV1 := s.logger.V(1).Register()
+
+	peers := s.neighbourhoodPeers(addr)
+	if len(peers) == 0 {
+		return nil, storage.ErrNotFound
+	}
+
+	s.metrics.NeighbourhoodFallbackCounter.Inc()
+	loggerV1.Debug("retrieving chunk from the neighbourhood", "chunk_address", addr, "peers", len(peers))
+
+	ctx, cancel := context.WithTimeout(ctx, retrieveChunkTimeout)
+	defer cancel()
+
+	resultC := make(chan retrievalResult, len(peers))
+	sp := new(skippeers.List)
+	for _, peer := range peers {
+		peer := peer
+		s.metrics.PeerRequestCounter.Inc()
+		chunktrace.AddPeer(ctx)
+		go func() {
+			chunk, retrieveAttempted, err := s.retrieveChunkFromPeer(ctx, addr, peer, sp, true)
+			if err != nil {
+				s.metrics.TotalErrors.Inc()
+			}
+			resultC <- retrievalResult{chunk: chunk, peer: peer, err: err, retrieveAttempted: retrieveAttempted}
+		}()
+	}
+
+	for range peers {
+		select {
+		case res := <-resultC:
+			if res.err != nil {
+				loggerV1.Debug("failed to get chunk from the neighbourhood", "chunk_address", addr, "peer_address", res.peer, "error", res.err)
+				continue
+			}
+			s.metrics.NeighbourhoodFallbackRetrieved.Inc()
+			loggerV1.Debug("retrieved chunk from the neighbourhood", "chunk_address", addr, "peer_address", res.peer)
+			return res.chunk, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+// neighbourhoodPeers returns at most maxNeighbourhoodPeers connected peers
+// closest to the chunk, which are within the neighbourhood depth of the chunk.
+func (s *Service) neighbourhoodPeers(addr swarm.Address) []swarm.Address {
+	depth := s.peerSuggester.NeighborhoodDepth()
+
+	var peers []swarm.Address
+	for len(peers) < maxNeighbourhoodPeers {
+		peer, err := s.peerSuggester.ClosestPeer(addr, false, topology.Filter{Reachable: true}, peers...)
+		if err != nil || swarm.Proximity(addr.Bytes(), peer.Bytes()) < depth {
+			break
+		}
+		peers = append(peers, peer)
+	}
+	return peers
 }
 
 // closestPeer returns address of the peer that is closest to the chunk with
-// provided address addr. This function will ignore peers with addresses
-// provided in skipPeers and if allowUpstream is true, peers that are further of
-// the chunk than this node is, could also be returned, allowing the upstream
-// retrieve request.
+// provided address addr. Out of the peers with the same proximity order to
+// the chunk, the one with the highest retrieval throughput is returned.
+// The peers marked as unhealthy are asked only if no healthy peer is found.
+// This function will ignore peers with addresses provided in skipPeers and
+// if allowUpstream is true, peers that are further of the chunk than this
+// node is, could also be returned, allowing the upstream retrieve request.
 func (s *Service) closestPeer(addr swarm.Address, skipPeers []swarm.Address, allowUpstream bool) (swarm.Address, error) {
+	peer, err := s.closestPeerWithFilter(addr, skipPeers, allowUpstream, topology.Filter{Reachable: true, Healthy: true})
+	if errors.Is(err, topology.ErrNotFound) {
+		return s.closestPeerWithFilter(addr, skipPeers, allowUpstream, topology.Filter{Reachable: true})
+	}
+	return peer, err
+}
 
-	closest, err := s.peerSuggester.ClosestPeer(addr, false, topology.Filter{Reachable: true}, skipPeers...)
+func (s *Service) closestPeerWithFilter(addr swarm.Address, skipPeers []swarm.Address, allowUpstream bool, filter topology.Filter) (swarm.Address, error) {
+	closest, err := s.peerSuggester.ClosestPeer(addr, false, filter, skipPeers...)
 	if err != nil {
 		return swarm.Address{}, err
 	}
 
-	if allowUpstream {
-		return closest, nil
+	candidates := []swarm.Address{closest}
+	po := swarm.Proximity(addr.Bytes(), closest.Bytes())
+	skip := append(append([]swarm.Address(nil), skipPeers...), closest)
+	for len(candidates) < maxCandidates {
+		peer, err := s.peerSuggester.ClosestPeer(addr, false, filter, skip...)
+		if err != nil || swarm.Proximity(addr.Bytes(), peer.Bytes()) != po {
+			break
+		}
+		candidates = append(candidates, peer)
+		skip = append(skip, peer)
 	}
 
-	closer, err := closest.Closer(addr, s.addr)
-	if err != nil {
-		return swarm.Address{}, fmt.Errorf("distance compare addr %s closest %s base address %s: %w", addr.String(), closest.String(), s.addr.String(), err)
-	}
-	if !closer {
-		return swarm.Address{}, topology.ErrNotFound
+	if !allowUpstream {
+		var eligible []swarm.Address
+		for _, c := range candidates {
+			closer, err := c.Closer(addr, s.addr)
+			if err != nil {
+				return swarm.Address{}, fmt.Errorf("distance compare addr %s closest %s base address %s: %w", addr.String(), c.String(), s.addr.String(), err)
+			}
+			if closer {
+				eligible = append(eligible, c)
+			}
+		}
+		if len(eligible) == 0 {
+			return swarm.Address{}, topology.ErrNotFound
+		}
+		candidates = eligible
 	}
 
-	return closest, nil
+	return s.throughput.best(candidates), nil
 }
 
 func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (err error) {
@@ -407,7 +540,7 @@ func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (e
 		return fmt.Errorf("stamp marshal: %w", err)
 	}
 
-	chunkPrice := s.pricer.Price(chunk.Address())
+	chunkPrice := s.pricer.Price(p.Address, chunk.Address())
 	debit, err := s.accounting.PrepareDebit(ctx, p.Address, chunkPrice)
 	if err != nil {
 		return fmt.Errorf("prepare debit to peer %s before writeback: %w", p.Address.String(), err)
@@ -427,6 +560,9 @@ func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (e
 	if err := debit.Apply(); err != nil {
 		return fmt.Errorf("apply debit: %w", err)
 	}
+	if !forwarded {
+		s.earnings.record(addr, swarm.Proximity(s.addr.Bytes(), addr.Bytes()), chunkPrice)
+	}
 
 	// cache the request last, so that putting to the localstore does not slow down the request flow
 	if s.caching && forwarded {
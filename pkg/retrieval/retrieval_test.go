@@ -150,6 +150,63 @@ func TestDelivery(t *testing.T) {
 	}
 }
 
+func TestEarnings(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chunks     = []swarm.Chunk{testingc.FixtureChunk("0033"), testingc.FixtureChunk("02c2")}
+		mockStorer = storemock.NewStorer()
+		clientAddr = swarm.MustParseHexAddress("9ee7add8")
+		serverAddr = swarm.MustParseHexAddress("9ee7add7")
+		pricerMock = pricermock.NewMockService(defaultPrice, defaultPrice)
+	)
+	for _, ch := range chunks {
+		if _, err := mockStorer.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := retrieval.New(swarm.MustParseHexAddress("0034"), mockStorer, nil, nil, log.Noop, accountingmock.NewAccounting(), pricerMock, nil, false, noopStampValidator)
+	recorder := streamtest.New(
+		streamtest.WithProtocols(server.Protocol()),
+		streamtest.WithBaseAddr(clientAddr),
+	)
+	mt := topologymock.NewTopologyDriver(topologymock.WithClosestPeer(serverAddr))
+	client := retrieval.New(clientAddr, storemock.NewStorer(), recorder, mt, log.Noop, accountingmock.NewAccounting(), pricerMock, nil, false, noopStampValidator)
+
+	// the second chunk is retrieved twice
+	for _, ch := range []swarm.Chunk{chunks[0], chunks[1], chunks[1]} {
+		if _, err := client.RetrieveChunk(context.Background(), ch.Address(), swarm.ZeroAddress); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	total, earnings := server.Earnings(-1)
+	if total.Uint64() != 3*defaultPrice {
+		t.Fatalf("got total %d, want %d", total, 3*defaultPrice)
+	}
+	if len(earnings) != 2 {
+		t.Fatalf("got %d chunks, want 2", len(earnings))
+	}
+	for i, want := range []struct {
+		addr       swarm.Address
+		earned     uint64
+		retrievals uint64
+	}{
+		{chunks[1].Address(), 2 * defaultPrice, 2},
+		{chunks[0].Address(), defaultPrice, 1},
+	} {
+		got := earnings[i]
+		if !got.Address.Equal(want.addr) || got.Earned.Uint64() != want.earned || got.Retrievals != want.retrievals {
+			t.Fatalf("got chunk %d earnings %s %d %d, want %s %d %d", i, got.Address, got.Earned, got.Retrievals, want.addr, want.earned, want.retrievals)
+		}
+	}
+
+	if _, earnings := server.Earnings(1); len(earnings) != 1 {
+		t.Fatalf("got %d chunks, want 1", len(earnings))
+	}
+}
+
 func TestWaitForInflight(t *testing.T) {
 	t.Parallel()
 
@@ -608,6 +665,89 @@ func TestRetrievePreemptiveRetry(t *testing.T) {
 	})
 }
 
+func TestRetrieveNeighbourhoodFallback(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger        = log.Noop
+		pricer        = pricermock.NewMockService(defaultPrice, defaultPrice)
+		chunk         = testingc.FixtureChunk("0025")
+		serverAddress = swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000")
+		clientAddress = swarm.MustParseHexAddress("8000000000000000000000000000000000000000000000000000000000000000")
+	)
+
+	serverStorer := storemock.NewStorer()
+	if _, err := serverStorer.Put(context.Background(), storage.ModePutUpload, chunk); err != nil {
+		t.Fatal(err)
+	}
+	server := retrieval.New(serverAddress, serverStorer, nil, nil, logger, accountingmock.NewAccounting(), pricer, nil, false, noopStampValidator)
+
+	// newRecorder fails the first request to the server,
+	// so the forwarding retrieval runs out of peers
+	newRecorder := func() (*streamtest.Recorder, func() int) {
+		var (
+			mu       sync.Mutex
+			requests int
+		)
+		recorder := streamtest.New(
+			streamtest.WithProtocols(server.Protocol()),
+			streamtest.WithMiddlewares(func(h p2p.HandlerFunc) p2p.HandlerFunc {
+				return func(ctx context.Context, p p2p.Peer, s p2p.Stream) error {
+					mu.Lock()
+					requests++
+					first := requests == 1
+					mu.Unlock()
+					if first {
+						_ = s.Close()
+						return errors.New("peer not reachable")
+					}
+					return h(ctx, p, s)
+				}
+			}),
+		)
+		return recorder, func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return requests
+		}
+	}
+
+	t.Run("peer in neighbourhood", func(t *testing.T) {
+		t.Parallel()
+
+		recorder, requests := newRecorder()
+		mt := topologymock.NewTopologyDriver(topologymock.WithPeers(serverAddress), topologymock.WithNeighborhoodDepth(1))
+		client := retrieval.New(clientAddress, nil, recorder, mt, logger, accountingmock.NewAccounting(), pricer, nil, false, noopStampValidator)
+
+		got, err := client.RetrieveChunk(context.Background(), chunk.Address(), swarm.ZeroAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Data(), chunk.Data()) {
+			t.Fatalf("got data %x, want %x", got.Data(), chunk.Data())
+		}
+		if n := requests(); n != 2 {
+			t.Fatalf("got %d requests, want 2", n)
+		}
+	})
+
+	t.Run("peer outside neighbourhood", func(t *testing.T) {
+		t.Parallel()
+
+		recorder, requests := newRecorder()
+		mt := topologymock.NewTopologyDriver(topologymock.WithPeers(serverAddress), topologymock.WithNeighborhoodDepth(swarm.MaxPO))
+		client := retrieval.New(clientAddress, nil, recorder, mt, logger, accountingmock.NewAccounting(), pricer, nil, false, noopStampValidator)
+
+		_, err := client.RetrieveChunk(context.Background(), chunk.Address(), swarm.ZeroAddress)
+		if !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+		}
+		if n := requests(); n != 1 {
+			t.Fatalf("got %d requests, want 1", n)
+		}
+	})
+}
+
 func TestClosestPeer(t *testing.T) {
 	t.Parallel()
 
@@ -653,6 +793,54 @@ func TestClosestPeer(t *testing.T) {
 	})
 }
 
+func TestClosestPeerThroughput(t *testing.T) {
+	t.Parallel()
+
+	var (
+		srvAd  = swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000")
+		chunk  = swarm.MustParseHexAddress("8000000000000000000000000000000000000000000000000000000000000000")
+		near   = swarm.MustParseHexAddress("8200000000000000000000000000000000000000000000000000000000000000")
+		fast   = swarm.MustParseHexAddress("8300000000000000000000000000000000000000000000000000000000000000")
+		faster = swarm.MustParseHexAddress("4000000000000000000000000000000000000000000000000000000000000000")
+	)
+
+	ret := retrieval.New(srvAd, nil, nil, topologymock.NewTopologyDriver(topologymock.WithPeers(near, fast, faster)), log.Noop, nil, nil, nil, false, nil)
+
+	addr, err := ret.ClosestPeer(chunk, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addr.Equal(near) {
+		t.Fatalf("want %s, got %s", near, addr)
+	}
+
+	// the peer with the higher throughput is preferred among the equally close
+	// peers, while the further peers are not considered regardless of throughput
+	ret.RecordThroughput(near, swarm.ChunkSize, time.Second)
+	ret.RecordThroughput(fast, 2*swarm.ChunkSize, time.Second)
+	ret.RecordThroughput(faster, 4*swarm.ChunkSize, time.Second)
+
+	addr, err = ret.ClosestPeer(chunk, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addr.Equal(fast) {
+		t.Fatalf("want %s, got %s", fast, addr)
+	}
+
+	// failed deliveries lower the throughput of the peer
+	for i := 0; i < 10; i++ {
+		ret.RecordThroughput(fast, 0, time.Second)
+	}
+	addr, err = ret.ClosestPeer(chunk, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addr.Equal(near) {
+		t.Fatalf("want %s, got %s", near, addr)
+	}
+}
+
 var noopStampValidator = func(chunk swarm.Chunk, stampBytes []byte) (swarm.Chunk, error) {
 	return chunk, nil
 }
@@ -0,0 +1,90 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retrieval
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	// throughputWeight is the weight of the latest sample
+	// in the exponentially weighted throughput of a peer.
+	throughputWeight = 0.2
+	// maxCandidates is the maximum number of the equally close peers
+	// considered when selecting the peer to retrieve the chunk from.
+	maxCandidates = 4
+)
+
+// throughput keeps the rolling throughput of the peers, in bytes per
+// second, measured on the deliveries of the chunks retrieved from them.
+type throughput struct {
+	mu    sync.Mutex
+	peers map[string]float64
+}
+
+func newThroughput() *throughput {
+	return &throughput{peers: make(map[string]float64)}
+}
+
+// record adds the sample of the bytes delivered by the peer in the
+// duration to its rolling throughput. Failed deliveries are recorded
+// with zero bytes, so that the unresponsive peers are avoided.
+func (t *throughput) record(peer swarm.Address, bytes int, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	sample := float64(bytes) / d.Seconds()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.peers[peer.ByteString()]
+	if !ok {
+		t.peers[peer.ByteString()] = sample
+		return
+	}
+	t.peers[peer.ByteString()] = v + throughputWeight*(sample-v)
+}
+
+// best returns the candidate with the highest throughput, preferring the
+// earlier candidates on ties. The peers without any recorded throughput
+// are assumed to perform as the average of the measured candidates.
+func (t *throughput) best(candidates []swarm.Address) swarm.Address {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var (
+		values = make([]float64, len(candidates))
+		known  = make([]bool, len(candidates))
+		sum    float64
+		n      int
+	)
+	for i, c := range candidates {
+		values[i], known[i] = t.peers[c.ByteString()]
+		if known[i] {
+			sum += values[i]
+			n++
+		}
+	}
+	if n == 0 {
+		return candidates[0]
+	}
+	for i := range candidates {
+		if !known[i] {
+			values[i] = sum / float64(n)
+		}
+	}
+
+	best := 0
+	for i := 1; i < len(candidates); i++ {
+		if values[i] > values[best] {
+			best = i
+		}
+	}
+	return candidates[best]
+}
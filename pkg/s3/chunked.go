@@ -0,0 +1,130 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package s3
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxChunkHeaderSize limits the length of the header line of a chunk.
+const maxChunkHeaderSize = 4096
+
+var errMalformedChunk = errors.New("malformed aws-chunked content")
+
+// IsChunked reports whether the content of the request with the given
+// headers is sent in the aws-chunked encoding, as done by the SDKs
+// which sign the content in the streaming mode.
+func IsChunked(contentEncoding, contentSHA256 string) bool {
+	return strings.HasPrefix(contentSHA256, "STREAMING-") || strings.Contains(contentEncoding, "aws-chunked")
+}
+
+// chunkedReader decodes the aws-chunked content. Every chunk is prefixed
+// with its hexadecimal size and optional extensions, like the signature of
+// the chunk, on the separate line, the content ends with the empty chunk
+// followed by the optional trailing headers. The signatures are not checked.
+type chunkedReader struct {
+	r    *bufio.Reader
+	left int64
+	done bool
+	err  error
+}
+
+// NewChunkedReader returns the reader of the content of
+// the aws-chunked encoded request body.
+func NewChunkedReader(r io.Reader) io.Reader {
+	return &chunkedReader{r: bufio.NewReader(r)}
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	for c.left == 0 {
+		if c.done {
+			c.err = io.EOF
+			return 0, c.err
+		}
+		if c.err = c.next(); c.err != nil {
+			return 0, c.err
+		}
+	}
+
+	if int64(len(p)) > c.left {
+		p = p[:c.left]
+	}
+	n, err := c.r.Read(p)
+	c.left -= int64(n)
+	if c.left == 0 && err == nil {
+		err = c.crlf()
+	}
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	c.err = err
+	return n, err
+}
+
+// next reads the header of the next chunk, or the trailing
+// headers after the header of the last, empty, chunk.
+func (c *chunkedReader) next() error {
+	line, err := c.line()
+	if err != nil {
+		return err
+	}
+	size, _, _ := strings.Cut(line, ";")
+	c.left, err = strconv.ParseInt(strings.TrimSpace(size), 16, 64)
+	if err != nil || c.left < 0 {
+		return fmt.Errorf("%w: chunk size %q", errMalformedChunk, size)
+	}
+	if c.left > 0 {
+		return nil
+	}
+
+	c.done = true
+	for {
+		line, err := c.line()
+		if errors.Is(err, io.EOF) || (err == nil && line == "") {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// line reads the line terminated with CRLF, without the terminator.
+func (c *chunkedReader) line() (string, error) {
+	var b strings.Builder
+	for {
+		part, isPrefix, err := c.r.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		b.Write(part)
+		if b.Len() > maxChunkHeaderSize {
+			return "", fmt.Errorf("%w: chunk header too long", errMalformedChunk)
+		}
+		if !isPrefix {
+			return b.String(), nil
+		}
+	}
+}
+
+// crlf reads the line terminator after the chunk data.
+func (c *chunkedReader) crlf() error {
+	line, err := c.line()
+	if err != nil {
+		return err
+	}
+	if line != "" {
+		return fmt.Errorf("%w: missing chunk terminator", errMalformedChunk)
+	}
+	return nil
+}
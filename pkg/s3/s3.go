@@ -0,0 +1,356 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package s3 exposes the mantaray manifests as the buckets of the S3
+// compatible object storage, so that the existing backup tools and SDKs
+// can store the objects on Swarm. The keys of the objects are the paths
+// of the manifest, every change produces a new version of the manifest.
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/manifest/feedwriter"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// The metadata of the manifest entries of the objects.
+const (
+	metadataETagKey         = "S3-ETag"
+	metadataSizeKey         = "S3-Size"
+	metadataLastModifiedKey = "S3-Last-Modified"
+)
+
+// DefaultMaxKeys is the maximal number of the listed keys if not requested.
+const DefaultMaxKeys = 1000
+
+var (
+	// ErrNoSuchKey is returned when the bucket has no object with the key.
+	ErrNoSuchKey = errors.New("no such key")
+	// ErrReadOnly is returned when the bucket can not be changed.
+	ErrReadOnly = errors.New("bucket is read-only")
+)
+
+// CommitFunc stores the root manifest of the changed bucket.
+type CommitFunc = feedwriter.CommitFunc
+
+// Options are the options of the Bucket. The bucket is read-only
+// if the Storer of the written objects is nil.
+type Options = feedwriter.Options
+
+// Object describes the object stored in the bucket.
+type Object struct {
+	Key          string
+	Reference    swarm.Address
+	Size         int64
+	ETag         string
+	ContentType  string
+	LastModified time.Time
+}
+
+// Bucket is the bucket of the objects kept in the manifest.
+// The bucket of the zero root reference is empty.
+type Bucket struct {
+	storer storage.Storer
+	w      *feedwriter.Writer
+}
+
+// New returns the bucket of the manifest with the root reference.
+func New(storer storage.Storer, root swarm.Address, o Options) *Bucket {
+	return &Bucket{
+		storer: storer,
+		w:      feedwriter.New(storer, root, o),
+	}
+}
+
+// Root returns the reference of the current root manifest.
+func (b *Bucket) Root() swarm.Address {
+	return b.w.Root()
+}
+
+// object returns the object described by the manifest entry.
+func (b *Bucket) object(ctx context.Context, key string, e manifest.Entry) (*Object, error) {
+	if e.Reference().IsZero() {
+		return nil, ErrNoSuchKey
+	}
+	md := e.Metadata()
+	o := &Object{
+		Key:         key,
+		Reference:   e.Reference(),
+		ETag:        md[metadataETagKey],
+		ContentType: md[manifest.EntryMetadataContentTypeKey],
+	}
+	if o.ETag == "" {
+		o.ETag = e.Reference().String()
+	}
+	if ts, err := strconv.ParseInt(md[metadataLastModifiedKey], 10, 64); err == nil {
+		o.LastModified = time.Unix(ts, 0).UTC()
+	}
+	size, err := strconv.ParseInt(md[metadataSizeKey], 10, 64)
+	if err != nil {
+		// not stored over S3
+		_, size, err = joiner.New(ctx, b.storer, o.Reference)
+		if err != nil {
+			return nil, err
+		}
+	}
+	o.Size = size
+	return o, nil
+}
+
+// Head returns the object with the key.
+func (b *Bucket) Head(ctx context.Context, key string) (*Object, error) {
+	if b.w.Root().IsZero() {
+		return nil, ErrNoSuchKey
+	}
+	m, err := b.w.Manifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e, err := m.Lookup(ctx, key)
+	if errors.Is(err, manifest.ErrNotFound) {
+		return nil, ErrNoSuchKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b.object(ctx, key, e)
+}
+
+// Get returns the object with the key and the reader of its content.
+func (b *Bucket) Get(ctx context.Context, key string) (*Object, file.Joiner, error) {
+	o, err := b.Head(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, _, err := joiner.New(ctx, b.storer, o.Reference)
+	if err != nil {
+		return nil, nil, err
+	}
+	return o, r, nil
+}
+
+// Put stores the content of the object and adds it to the bucket,
+// replacing the object with the same key. If the content type is
+// empty, it is derived from the extension of the key.
+func (b *Bucket) Put(ctx context.Context, key string, r io.Reader, contentType string) (*Object, error) {
+	if !b.w.Writable() {
+		return nil, ErrReadOnly
+	}
+
+	w := builder.NewWriter(ctx, b.w.Storer(), builder.Options{Mode: b.w.Mode()})
+	hash := md5.New()
+	size, err := io.Copy(io.MultiWriter(w, hash), r)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	o := &Object{
+		Key:          key,
+		Reference:    w.Reference(),
+		Size:         size,
+		ETag:         hex.EncodeToString(hash.Sum(nil)),
+		ContentType:  contentType,
+		LastModified: time.Now().UTC().Truncate(time.Second),
+	}
+	metadata := map[string]string{
+		manifest.EntryMetadataFilenameKey: path.Base(key),
+		metadataETagKey:                   o.ETag,
+		metadataSizeKey:                   strconv.FormatInt(o.Size, 10),
+		metadataLastModifiedKey:           strconv.FormatInt(o.LastModified.Unix(), 10),
+	}
+	if o.ContentType != "" {
+		metadata[manifest.EntryMetadataContentTypeKey] = o.ContentType
+	}
+
+	err = b.w.Update(ctx, func(m manifest.Interface) error {
+		return m.Add(ctx, key, manifest.NewEntry(o.Reference, metadata))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Delete removes the object with the key from the bucket,
+// deleting the object which does not exist is not an error.
+func (b *Bucket) Delete(ctx context.Context, key string) error {
+	if !b.w.Writable() {
+		return ErrReadOnly
+	}
+	if _, err := b.Head(ctx, key); errors.Is(err, ErrNoSuchKey) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return b.w.Update(ctx, func(m manifest.Interface) error {
+		// the manifest removes the whole subtree of the node matching
+		// the key, so the objects the key is the prefix of are restored
+		keys, err := b.keys(ctx, m, key)
+		if err != nil {
+			return err
+		}
+		kept := make(map[string]manifest.Entry, len(keys))
+		for _, k := range keys {
+			if k == key {
+				continue
+			}
+			e, err := m.Lookup(ctx, k)
+			if err != nil {
+				return err
+			}
+			kept[k] = e
+		}
+		if err := m.Remove(ctx, key); err != nil {
+			return err
+		}
+		for k, e := range kept {
+			switch _, err := m.Lookup(ctx, k); {
+			case errors.Is(err, manifest.ErrNotFound):
+				if err := m.Add(ctx, k, e); err != nil {
+					return err
+				}
+			case err != nil:
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// keys returns the sorted keys of the objects starting with the prefix.
+func (b *Bucket) keys(ctx context.Context, m manifest.Interface, prefix string) ([]string, error) {
+	w, ok := m.(manifest.Walker)
+	if !ok {
+		return nil, fmt.Errorf("manifest type %s can not be listed", m.Type())
+	}
+
+	var keys []string
+	err := w.Walk(ctx, func(p string, isDir bool) error {
+		if p == "" || p == manifest.RootPath || !strings.HasPrefix(p, prefix) {
+			return nil
+		}
+		if isDir {
+			// the key ending with the path separator
+			// is reported as the directory by the manifest
+			switch e, err := m.Lookup(ctx, p); {
+			case errors.Is(err, manifest.ErrNotFound):
+				return nil
+			case err != nil:
+				return err
+			case e.Reference().IsZero():
+				return nil
+			}
+		}
+		keys = append(keys, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ListOptions select the listed objects.
+type ListOptions struct {
+	// Prefix limits the listing to the keys starting with it.
+	Prefix string
+	// Delimiter groups the keys with the same part of the key
+	// between the prefix and the delimiter into the common prefix.
+	Delimiter string
+	// StartAfter lists the keys which come after it.
+	StartAfter string
+	// MaxKeys is the maximal number of the listed objects and common
+	// prefixes, DefaultMaxKeys if it is not positive.
+	MaxKeys int
+}
+
+// ListResult is the page of the listed objects.
+type ListResult struct {
+	Objects        []*Object
+	CommonPrefixes []string
+	// IsTruncated reports whether there are more objects to list,
+	// the listing continues after the NextMarker.
+	IsTruncated bool
+	NextMarker  string
+}
+
+// List lists the objects of the bucket in the order of their keys.
+func (b *Bucket) List(ctx context.Context, o ListOptions) (*ListResult, error) {
+	res := new(ListResult)
+	if b.w.Root().IsZero() {
+		return res, nil
+	}
+	if o.MaxKeys <= 0 {
+		o.MaxKeys = DefaultMaxKeys
+	}
+
+	m, err := b.w.Manifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := b.keys(ctx, m, o.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var last string
+	for _, key := range keys {
+		entry := key
+		if o.Delimiter != "" {
+			if i := strings.Index(key[len(o.Prefix):], o.Delimiter); i >= 0 {
+				entry = key[:len(o.Prefix)+i+len(o.Delimiter)]
+			}
+		}
+		if entry <= o.StartAfter || entry == last {
+			// listed already, possibly as the common prefix
+			continue
+		}
+		if len(res.Objects)+len(res.CommonPrefixes) == o.MaxKeys {
+			res.IsTruncated = true
+			res.NextMarker = last
+			break
+		}
+		last = entry
+
+		if entry != key {
+			res.CommonPrefixes = append(res.CommonPrefixes, entry)
+			continue
+		}
+		e, err := m.Lookup(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := b.object(ctx, key, e)
+		if err != nil {
+			return nil, err
+		}
+		res.Objects = append(res.Objects, obj)
+	}
+	return res, nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package s3_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/s3"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func listed(res *s3.ListResult) []string {
+	var keys []string
+	for _, o := range res.Objects {
+		keys = append(keys, o.Key)
+	}
+	return append(keys, res.CommonPrefixes...)
+}
+
+func TestBucket(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		storer  = mock.NewStorer()
+		commits int
+		root    swarm.Address
+		b       = s3.New(storer, swarm.ZeroAddress, s3.Options{
+			Storer: storer,
+			Mode:   storage.ModePutUpload,
+			Commit: func(_ context.Context, r swarm.Address) error {
+				commits++
+				root = r
+				return nil
+			},
+		})
+	)
+
+	for key, content := range map[string]string{
+		"a.txt":         "alpha",
+		"docs":          "beta",
+		"docs/c.txt":    "gamma",
+		"docs/d/e.html": "delta",
+	} {
+		if _, err := b.Put(ctx, key, strings.NewReader(content), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if commits != 4 || !b.Root().Equal(root) {
+		t.Fatalf("got %d commits of root %s, want 4 of root %s", commits, root, b.Root())
+	}
+
+	t.Run("get", func(t *testing.T) {
+		t.Parallel()
+
+		o, r, err := s3.New(storer, root, s3.Options{}).Get(ctx, "docs/d/e.html")
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "delta" || o.Size != 5 || o.ContentType != "text/html; charset=utf-8" {
+			t.Fatalf("got object %+v with content %q", o, data)
+		}
+
+		_, _, err = s3.New(storer, root, s3.Options{}).Get(ctx, "docs/")
+		if !errors.Is(err, s3.ErrNoSuchKey) {
+			t.Fatalf("got error %v, want %v", err, s3.ErrNoSuchKey)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+
+		bucket := s3.New(storer, root, s3.Options{})
+		for _, tc := range []struct {
+			opts s3.ListOptions
+			want []string
+		}{
+			{s3.ListOptions{}, []string{"a.txt", "docs", "docs/c.txt", "docs/d/e.html"}},
+			{s3.ListOptions{Delimiter: "/"}, []string{"a.txt", "docs", "docs/"}},
+			{s3.ListOptions{Prefix: "docs/", Delimiter: "/"}, []string{"docs/c.txt", "docs/d/"}},
+			{s3.ListOptions{StartAfter: "docs", MaxKeys: 1}, []string{"docs/c.txt"}},
+		} {
+			res, err := bucket.List(ctx, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := listed(res); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("options %+v: got %v, want %v", tc.opts, got, tc.want)
+			}
+		}
+
+		res, err := bucket.List(ctx, s3.ListOptions{MaxKeys: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.IsTruncated || res.NextMarker != "docs" {
+			t.Fatalf("got truncated %v after %q, want truncated after %q", res.IsTruncated, res.NextMarker, "docs")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+
+		bucket := s3.New(storer, root, s3.Options{
+			Storer: storer,
+			Commit: func(context.Context, swarm.Address) error { return nil },
+		})
+		if err := bucket.Delete(ctx, "docs"); err != nil {
+			t.Fatal(err)
+		}
+		res, err := bucket.List(ctx, s3.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := listed(res), []string{"a.txt", "docs/c.txt", "docs/d/e.html"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if err := bucket.Delete(ctx, "missing"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		t.Parallel()
+
+		bucket := s3.New(storer, root, s3.Options{})
+		if _, err := bucket.Put(ctx, "f.txt", strings.NewReader("epsilon"), ""); !errors.Is(err, s3.ErrReadOnly) {
+			t.Fatalf("got error %v, want %v", err, s3.ErrReadOnly)
+		}
+		if err := bucket.Delete(ctx, "a.txt"); !errors.Is(err, s3.ErrReadOnly) {
+			t.Fatalf("got error %v, want %v", err, s3.ErrReadOnly)
+		}
+	})
+}
+
+func TestChunkedReader(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name, body, want string
+		fail             bool
+	}{
+		{
+			name: "signed",
+			body: "5;chunk-signature=aa\r\nhello\r\n6;chunk-signature=bb\r\n world\r\n0;chunk-signature=cc\r\n\r\n",
+			want: "hello world",
+		},
+		{
+			name: "trailer",
+			body: "5\r\nhello\r\n0\r\nx-amz-checksum-crc32:AAAAAA==\r\n\r\n",
+			want: "hello",
+		},
+		{
+			name: "truncated",
+			body: "5\r\nhel",
+			fail: true,
+		},
+		{
+			name: "invalid size",
+			body: "z\r\nhello\r\n0\r\n\r\n",
+			fail: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := io.ReadAll(s3.NewChunkedReader(strings.NewReader(tc.body)))
+			if tc.fail {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package salud
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	ResponseDuration prometheus.Histogram
+	UnhealthyPeers   prometheus.Gauge
+	NetworkRadius    prometheus.Gauge
+}
+
+func newMetrics() metrics {
+	subsystem := "salud"
+
+	return metrics{
+		ResponseDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "response_duration_seconds",
+			Help:      "Duration of the peer status requests.",
+		}),
+		UnhealthyPeers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "unhealthy_peers",
+			Help:      "Number of the peers marked as unhealthy by the last health check.",
+		}),
+		NetworkRadius: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "network_radius",
+			Help:      "Most common storage radius of the peers.",
+		}),
+	}
+}
+
+func (s *Service) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
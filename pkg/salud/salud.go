@@ -0,0 +1,250 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package salud monitors the health of the connected peers by periodically
+// requesting their status. The peers which respond slowly, announce a storage
+// radius far from the rest of the network or disagree on the state of the
+// batchstore are marked as unhealthy, so that the syncing and the retrieval
+// prefer the healthy peers.
+package salud
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/status"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+	"go.uber.org/atomic"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "salud"
+
+const (
+	// DefaultInterval is the default interval between the health checks.
+	DefaultInterval = 5 * time.Minute
+
+	requestTimeout = 10 * time.Second
+	// durPercentile is the percentile of the response durations above
+	// which the peers are considered too slow.
+	durPercentile = 0.8
+	// minPeersPerBin is the number of peers per bin which are kept
+	// healthy regardless of the checks, so that every bin is served.
+	minPeersPerBin = 4
+)
+
+type topologyDriver interface {
+	topology.PeerHealthUpdater
+	topology.EachPeerer
+}
+
+type peerStatus interface {
+	PeerSnapshot(ctx context.Context, peer swarm.Address) (*status.Snapshot, error)
+}
+
+type Service struct {
+	logger   log.Logger
+	topology topologyDriver
+	status   peerStatus
+	radius   postage.RadiusChecker
+	metrics  metrics
+
+	isHealthy *atomic.Bool
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func New(status peerStatus, topology topologyDriver, radius postage.RadiusChecker, logger log.Logger, interval time.Duration) *Service {
+	s := &Service{
+		logger:    logger.WithName(loggerName).Register(),
+		topology:  topology,
+		status:    status,
+		radius:    radius,
+		metrics:   newMetrics(),
+		isHealthy: atomic.NewBool(true),
+		quit:      make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.worker(interval)
+
+	return s
+}
+
+func (s *Service) worker(interval time.Duration) {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.quit
+		cancel()
+	}()
+
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-time.After(interval):
+		}
+		s.salud(ctx)
+	}
+}
+
+// IsHealthy reports whether the storage radius of the
+// node agrees with the radius of the rest of the network.
+// It implements the status.Healther interface.
+func (s *Service) IsHealthy() bool {
+	return s.isHealthy.Load()
+}
+
+func (s *Service) Close() error {
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
+
+type peer struct {
+	address swarm.Address
+	po      uint8
+	status  *status.Snapshot
+	dur     time.Duration
+}
+
+// salud requests the status of all the connected peers and updates their
+// health in the topology.
+func (s *Service) salud(ctx context.Context) {
+	loggerV1 := s.logger.V(1).Register()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		peers   []peer
+		bins    = make(map[uint8]int)
+		failing []peer
+	)
+
+	_ = s.topology.EachPeer(func(addr swarm.Address, po uint8) (bool, bool, error) {
+		bins[po]++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+			defer cancel()
+
+			start := time.Now()
+			ss, err := s.status.PeerSnapshot(ctx, addr)
+			dur := time.Since(start)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				loggerV1.Debug("peer status request failed", "peer_address", addr, "error", err)
+				failing = append(failing, peer{address: addr, po: po})
+				return
+			}
+			s.metrics.ResponseDuration.Observe(dur.Seconds())
+			peers = append(peers, peer{address: addr, po: po, status: ss, dur: dur})
+		}()
+		return false, false, nil
+	}, topology.Filter{})
+
+	wg.Wait()
+
+	select {
+	case <-ctx.Done():
+		return
+	default:
+	}
+
+	unhealthy := 0
+	for _, p := range failing {
+		// the bin is too sparse to skip any of its peers
+		healthy := bins[p.po] <= minPeersPerBin
+		if !healthy {
+			unhealthy++
+		}
+		s.topology.UpdatePeerHealth(p.address, healthy)
+	}
+
+	if len(peers) == 0 {
+		s.metrics.UnhealthyPeers.Set(float64(unhealthy))
+		return
+	}
+
+	networkRadius := mostCommon(peers, func(p peer) uint64 { return uint64(p.status.StorageRadius) })
+	commitment := mostCommon(peers, func(p peer) uint64 { return p.status.BatchCommitment })
+	pDur := percentileDur(peers, durPercentile)
+	s.metrics.NetworkRadius.Set(float64(networkRadius))
+
+	for _, p := range peers {
+		healthy := true
+		switch {
+		case bins[p.po] <= minPeersPerBin:
+			// the bin is too sparse to skip any of its peers
+		case radiusDiff(p.status.StorageRadius, uint8(networkRadius)) > 1:
+			loggerV1.Debug("peer storage radius out of range", "peer_address", p.address, "radius", p.status.StorageRadius, "network_radius", networkRadius)
+			healthy = false
+		case p.dur > pDur:
+			loggerV1.Debug("peer response too slow", "peer_address", p.address, "duration", p.dur, "threshold", pDur)
+			healthy = false
+		case p.status.BatchCommitment != commitment:
+			loggerV1.Debug("peer batch commitment mismatch", "peer_address", p.address, "commitment", p.status.BatchCommitment, "network_commitment", commitment)
+			healthy = false
+		}
+		if !healthy {
+			unhealthy++
+		}
+		s.topology.UpdatePeerHealth(p.address, healthy)
+	}
+	s.metrics.UnhealthyPeers.Set(float64(unhealthy))
+
+	selfHealthy := radiusDiff(s.radius.StorageRadius(), uint8(networkRadius)) <= 1
+	if !selfHealthy {
+		s.logger.Warning("storage radius of the node is out of range of the network radius", "radius", s.radius.StorageRadius(), "network_radius", networkRadius)
+	}
+	s.isHealthy.Store(selfHealthy)
+}
+
+// mostCommon returns the most common value of the peers, preferring the
+// higher value on a tie.
+func mostCommon(peers []peer, value func(peer) uint64) uint64 {
+	counts := make(map[uint64]int)
+	var (
+		common uint64
+		max    int
+	)
+	for _, p := range peers {
+		v := value(p)
+		counts[v]++
+		if c := counts[v]; c > max || (c == max && v > common) {
+			common, max = v, c
+		}
+	}
+	return common
+}
+
+// percentileDur returns the response duration at the given percentile.
+func percentileDur(peers []peer, p float64) time.Duration {
+	durs := make([]time.Duration, 0, len(peers))
+	for _, peer := range peers {
+		durs = append(durs, peer.dur)
+	}
+	sort.Slice(durs, func(i, j int) bool { return durs[i] < durs[j] })
+	return durs[int(float64(len(durs)-1)*p)]
+}
+
+func radiusDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
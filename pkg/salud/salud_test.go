@@ -0,0 +1,126 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package salud_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	batchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	"github.com/ethersphere/bee/pkg/salud"
+	"github.com/ethersphere/bee/pkg/status"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+type testPeer struct {
+	addr   swarm.Address
+	po     uint8
+	status *status.Snapshot
+	delay  time.Duration
+	err    error
+}
+
+type mockStatus map[string]testPeer
+
+func (m mockStatus) PeerSnapshot(ctx context.Context, peer swarm.Address) (*status.Snapshot, error) {
+	p := m[peer.ByteString()]
+	select {
+	case <-time.After(p.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return p.status, p.err
+}
+
+type mockTopology struct {
+	peers []testPeer
+
+	mu     sync.Mutex
+	health map[string]bool
+}
+
+func (m *mockTopology) UpdatePeerHealth(peer swarm.Address, healthy bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.health[peer.ByteString()] = healthy
+}
+
+func (m *mockTopology) EachPeer(f topology.EachPeerFunc, _ topology.Filter) error {
+	for _, p := range m.peers {
+		if _, _, err := f(p.addr, p.po); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockTopology) EachPeerRev(f topology.EachPeerFunc, filter topology.Filter) error {
+	return m.EachPeer(f, filter)
+}
+
+func TestSalud(t *testing.T) {
+	t.Parallel()
+
+	healthy := &status.Snapshot{StorageRadius: 8, BatchCommitment: 100}
+	peers := []testPeer{
+		{status: healthy, err: errors.New("no response")},
+		{status: healthy, delay: 300 * time.Millisecond},
+		{status: healthy, delay: 300 * time.Millisecond},
+		{status: &status.Snapshot{StorageRadius: 4, BatchCommitment: 100}},
+		{status: &status.Snapshot{StorageRadius: 8, BatchCommitment: 99}},
+		{status: healthy},
+		{status: healthy},
+		{status: healthy},
+		{status: healthy},
+		{status: healthy},
+		// the only peer in its bin is kept healthy
+		{status: healthy, err: errors.New("no response"), po: 1},
+	}
+	want := []bool{false, false, false, false, false, true, true, true, true, true, true}
+
+	ms := make(mockStatus)
+	for i := range peers {
+		peers[i].addr = swarm.RandAddress(t)
+		ms[peers[i].addr.ByteString()] = peers[i]
+	}
+	topo := &mockTopology{peers: peers, health: make(map[string]bool)}
+
+	for _, tc := range []struct {
+		name   string
+		radius uint8
+		want   bool
+	}{
+		{name: "healthy node", radius: 7, want: true},
+		{name: "unhealthy node", radius: 3, want: false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			bs := batchstore.New(batchstore.WithReserveState(&postage.ReserveState{StorageRadius: tc.radius}))
+			s := salud.New(ms, topo, bs, log.Noop, time.Hour)
+			t.Cleanup(func() { _ = s.Close() })
+
+			salud.Salud(s, context.Background())
+
+			topo.mu.Lock()
+			defer topo.mu.Unlock()
+			for i, p := range peers {
+				if got := topo.health[p.addr.ByteString()]; got != want[i] {
+					t.Errorf("peer %d: got healthy %v, want %v", i, got, want[i])
+				}
+			}
+			if got := s.IsHealthy(); got != tc.want {
+				t.Fatalf("got node healthy %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
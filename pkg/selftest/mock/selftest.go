@@ -0,0 +1,20 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mock
+
+import (
+	"context"
+
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/selftest"
+)
+
+// SelfTest is the selftest.Interface mock calling the function.
+type SelfTest func(ctx context.Context, stamper postage.Stamper, size int) (*selftest.Report, error)
+
+// Run implements selftest.Interface Run method.
+func (f SelfTest) Run(ctx context.Context, stamper postage.Stamper, size int) (*selftest.Report, error) {
+	return f(ctx, stamper, size)
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package selftest provides the one-shot diagnostic of the node, which checks
+// that the content uploaded by the node is stored by the network and can be
+// retrieved from it. It uploads the small random payload, pushes its chunks
+// to the closest peers waiting for their receipts, and retrieves them back
+// with the retrieval protocol, so that they are not read from the local store.
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/retrieval"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"golang.org/x/sync/errgroup"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "selftest"
+
+const (
+	// DefaultSize is the default size of the payload in bytes.
+	DefaultSize = 16 * swarm.ChunkSize
+	// MaxSize is the maximal size of the payload in bytes.
+	MaxSize = 256 * swarm.ChunkSize
+
+	// how many chunks are pushed or retrieved in parallel
+	parallelChunks = 8
+)
+
+// Names of the stages of the self-test.
+const (
+	StageUpload    = "upload"
+	StagePushSync  = "pushsync"
+	StageRetrieval = "retrieval"
+)
+
+// ErrInvalidSize is returned if the size of the payload
+// is not positive or larger than MaxSize.
+var ErrInvalidSize = errors.New("invalid payload size")
+
+// Stage is the result of a stage of the self-test.
+type Stage struct {
+	Name     string
+	Duration time.Duration
+	Err      error // the cause of the failure of the stage, if it failed
+}
+
+// Report is the result of the self-test.
+type Report struct {
+	Reference swarm.Address // root address of the uploaded payload
+	Size      int           // size of the payload in bytes
+	Chunks    int           // number of the chunks of the payload
+	Receipts  int           // number of the chunks with the push-sync receipts
+	Retrieved int           // number of the chunks retrieved from the network
+	Stages    []Stage       // stages in the order they were run, up to the failed one
+}
+
+// Failed reports whether any stage of the self-test failed.
+func (r *Report) Failed() bool {
+	for _, s := range r.Stages {
+		if s.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Interface runs the self-test of the node.
+type Interface interface {
+	// Run uploads the random payload of the size with the chunks stamped by
+	// the stamper, pushes it to the network and retrieves it back. It returns
+	// the error only if the self-test could not be run, the failures of the
+	// stages are in the report.
+	Run(ctx context.Context, stamper postage.Stamper, size int) (*Report, error)
+}
+
+type Service struct {
+	pushSyncer pushsync.PushSyncer
+	retrieval  retrieval.Interface
+	logger     log.Logger
+}
+
+// New returns the Service which pushes the chunks with the push syncer
+// and retrieves them with the retrieval.
+func New(pushSyncer pushsync.PushSyncer, retrieval retrieval.Interface, logger log.Logger) *Service {
+	return &Service{
+		pushSyncer: pushSyncer,
+		retrieval:  retrieval,
+		logger:     logger.WithName(loggerName).Register(),
+	}
+}
+
+// Run implements the Interface.
+func (s *Service) Run(ctx context.Context, stamper postage.Stamper, size int) (*Report, error) {
+	if size <= 0 || size > MaxSize {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidSize, size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, payload); err != nil {
+		return nil, fmt.Errorf("random payload: %w", err)
+	}
+
+	report := &Report{Size: size}
+	stage := func(name string, fn func() error) bool {
+		start := time.Now()
+		err := fn()
+		report.Stages = append(report.Stages, Stage{Name: name, Duration: time.Since(start), Err: err})
+		if err != nil {
+			s.logger.Debug("self-test stage failed", "stage", name, "error", err)
+		}
+		return err == nil
+	}
+
+	var chunks []swarm.Chunk
+	upload := func() (err error) {
+		putter := &collectingPutter{stamper: stamper}
+		pipe := builder.NewPipelineBuilder(ctx, putter, storage.ModePutUpload, false)
+		report.Reference, err = builder.FeedPipeline(ctx, pipe, bytes.NewReader(payload))
+		chunks = putter.chunks
+		report.Chunks = len(chunks)
+		return err
+	}
+	pushSync := func() error {
+		var mu sync.Mutex
+		return forEach(ctx, chunks, func(ctx context.Context, ch swarm.Chunk) error {
+			if _, err := s.pushSyncer.PushChunkToClosest(ctx, ch); err != nil {
+				return fmt.Errorf("push chunk %s: %w", ch.Address(), err)
+			}
+			mu.Lock()
+			report.Receipts++
+			mu.Unlock()
+			return nil
+		})
+	}
+	retrieve := func() error {
+		var mu sync.Mutex
+		return forEach(ctx, chunks, func(ctx context.Context, ch swarm.Chunk) error {
+			got, err := s.retrieval.RetrieveChunk(ctx, ch.Address(), swarm.ZeroAddress)
+			if err != nil {
+				return fmt.Errorf("retrieve chunk %s: %w", ch.Address(), err)
+			}
+			if !bytes.Equal(got.Data(), ch.Data()) {
+				return fmt.Errorf("retrieve chunk %s: %w", ch.Address(), swarm.ErrInvalidChunk)
+			}
+			mu.Lock()
+			report.Retrieved++
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	_ = stage(StageUpload, upload) && stage(StagePushSync, pushSync) && stage(StageRetrieval, retrieve)
+	return report, nil
+}
+
+// forEach calls the function for all the chunks in parallel
+// and returns the first error, after which it stops.
+func forEach(ctx context.Context, chunks []swarm.Chunk, fn func(context.Context, swarm.Chunk) error) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, parallelChunks)
+	for _, ch := range chunks {
+		ch := ch
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return eg.Wait()
+		}
+		eg.Go(func() error {
+			defer func() { <-sem }()
+			return fn(ctx, ch)
+		})
+	}
+	return eg.Wait()
+}
+
+// collectingPutter stamps the chunks of the payload and keeps them in the
+// memory, so that they are neither stored nor pushed by the pusher.
+type collectingPutter struct {
+	stamper postage.Stamper
+	mu      sync.Mutex
+	chunks  []swarm.Chunk
+}
+
+func (p *collectingPutter) Put(_ context.Context, _ storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, ch := range chs {
+		stamp, err := p.stamper.Stamp(ch.Address())
+		if err != nil {
+			return nil, fmt.Errorf("stamp chunk %s: %w", ch.Address(), err)
+		}
+		p.chunks = append(p.chunks, ch.WithStamp(stamp))
+	}
+	return make([]bool, len(chs)), nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package selftest_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/log"
+	postagemock "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/pushsync"
+	pushsyncmock "github.com/ethersphere/bee/pkg/pushsync/mock"
+	"github.com/ethersphere/bee/pkg/selftest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// network stores the pushed chunks and retrieves them.
+type network struct {
+	mu     sync.Mutex
+	chunks map[string]swarm.Chunk
+}
+
+func (n *network) push(_ context.Context, ch swarm.Chunk) (*pushsync.Receipt, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.chunks[ch.Address().ByteString()] = ch
+	return &pushsync.Receipt{Address: ch.Address()}, nil
+}
+
+func (n *network) RetrieveChunk(_ context.Context, addr, _ swarm.Address) (swarm.Chunk, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	ch, ok := n.chunks[addr.ByteString()]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return ch, nil
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		net := &network{chunks: make(map[string]swarm.Chunk)}
+		s := selftest.New(pushsyncmock.New(net.push), net, log.Noop)
+
+		report, err := s.Run(ctx, postagemock.NewStamper(), 3*swarm.ChunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Failed() {
+			t.Fatalf("got failed report %+v", report)
+		}
+		if report.Chunks != 4 || report.Receipts != 4 || report.Retrieved != 4 {
+			t.Fatalf("got %d chunks, %d receipts and %d retrieved, want 4", report.Chunks, report.Receipts, report.Retrieved)
+		}
+		if _, ok := net.chunks[report.Reference.ByteString()]; !ok {
+			t.Fatalf("root chunk %s not pushed", report.Reference)
+		}
+		want := []string{selftest.StageUpload, selftest.StagePushSync, selftest.StageRetrieval}
+		if len(report.Stages) != len(want) {
+			t.Fatalf("got %d stages, want %d", len(report.Stages), len(want))
+		}
+		for i, stage := range report.Stages {
+			if stage.Name != want[i] {
+				t.Fatalf("got stage %q, want %q", stage.Name, want[i])
+			}
+		}
+	})
+
+	t.Run("push failed", func(t *testing.T) {
+		t.Parallel()
+
+		net := &network{chunks: make(map[string]swarm.Chunk)}
+		push := func(context.Context, swarm.Chunk) (*pushsync.Receipt, error) {
+			return nil, topology.ErrNotFound
+		}
+		s := selftest.New(pushsyncmock.New(push), net, log.Noop)
+
+		report, err := s.Run(ctx, postagemock.NewStamper(), swarm.ChunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.Failed() {
+			t.Fatal("want failed report")
+		}
+		if len(report.Stages) != 2 {
+			t.Fatalf("got %d stages, want 2", len(report.Stages))
+		}
+		if err := report.Stages[1].Err; !errors.Is(err, topology.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, topology.ErrNotFound)
+		}
+	})
+
+	t.Run("retrieval failed", func(t *testing.T) {
+		t.Parallel()
+
+		net := &network{chunks: make(map[string]swarm.Chunk)}
+		push := func(_ context.Context, ch swarm.Chunk) (*pushsync.Receipt, error) {
+			return &pushsync.Receipt{Address: ch.Address()}, nil
+		}
+		s := selftest.New(pushsyncmock.New(push), net, log.Noop)
+
+		report, err := s.Run(ctx, postagemock.NewStamper(), swarm.ChunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Stages) != 3 {
+			t.Fatalf("got %d stages, want 3", len(report.Stages))
+		}
+		if err := report.Stages[2].Err; !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+		}
+		if report.Receipts != 1 || report.Retrieved != 0 {
+			t.Fatalf("got %d receipts and %d retrieved, want 1 and 0", report.Receipts, report.Retrieved)
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		t.Parallel()
+
+		s := selftest.New(nil, nil, log.Noop)
+		if _, err := s.Run(ctx, postagemock.NewStamper(), selftest.MaxSize+1); !errors.Is(err, selftest.ErrInvalidSize) {
+			t.Fatalf("got error %v, want %v", err, selftest.ErrInvalidSize)
+		}
+	})
+}
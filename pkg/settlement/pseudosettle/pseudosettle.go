@@ -45,6 +45,11 @@ var (
 	ErrRefreshmentAboveExpected       = errors.New("refreshment above expected")
 )
 
+// TimeRecorder records the times reported by the peers.
+type TimeRecorder interface {
+	Record(peer swarm.Address, peerTime time.Time)
+}
+
 type Service struct {
 	streamer         p2p.Streamer
 	logger           log.Logger
@@ -55,6 +60,7 @@ type Service struct {
 	lightRefreshRate *big.Int
 	p2pService       p2p.Service
 	timeNow          func() time.Time
+	timeRecorder     TimeRecorder
 	peersMu          sync.Mutex
 	peers            map[string]*pseudoSettlePeer
 }
@@ -313,6 +319,10 @@ func (s *Service) Pay(ctx context.Context, peer swarm.Address, amount *big.Int)
 
 	checkTime := s.timeNow().UnixMilli()
 
+	if s.timeRecorder != nil {
+		s.timeRecorder.Record(peer, time.Unix(paymentAck.Timestamp, 0))
+	}
+
 	acceptedAmount := new(big.Int).SetBytes(paymentAck.Amount)
 	if acceptedAmount.Cmp(amount) > 0 {
 		err = fmt.Errorf("pseudosettle: peer %v: %w", peer, ErrRefreshmentAboveExpected)
@@ -362,6 +372,11 @@ func (s *Service) SetAccounting(accounting settlement.Accounting) {
 	s.accounting = accounting
 }
 
+// SetTimeRecorder sets the recorder of the times reported by the peers in the payment acknowledgements.
+func (s *Service) SetTimeRecorder(timeRecorder TimeRecorder) {
+	s.timeRecorder = timeRecorder
+}
+
 // TotalSent returns the total amount sent to a peer
 func (s *Service) TotalSent(peer swarm.Address) (totalSent *big.Int, err error) {
 	var lastTime lastPayment
@@ -291,8 +291,25 @@ func TestPayment(t *testing.T) {
 
 	payer := pseudosettle.New(recorder, logger, storePayer, payerObserver, big.NewInt(testRefreshRate), big.NewInt(testRefreshRateLight), mockp2p.New())
 	payer.SetAccounting(payerObserver)
+	timeRecorder := new(testTimeRecorder)
+	payer.SetTimeRecorder(timeRecorder)
 	// set time to non-zero, attempt payment based on debt, expect full amount to be accepted
 	testCaseAccepted(t, recorder, payerObserver, receiverObserver, payer, recipient, peerID, 30, 30, 1, 1, 1, big.NewInt(debt), big.NewInt(debt), big.NewInt(debt), big.NewInt(debt))
+
+	// the time of the recipient is recorded from the acknowledgement
+	if !timeRecorder.peer.Equal(peerID) || !timeRecorder.peerTime.Equal(time.Unix(30, 0)) {
+		t.Fatalf("got recorded time %v of peer %s, want %v of peer %s", timeRecorder.peerTime, timeRecorder.peer, time.Unix(30, 0), peerID)
+	}
+}
+
+type testTimeRecorder struct {
+	peer     swarm.Address
+	peerTime time.Time
+}
+
+func (r *testTimeRecorder) Record(peer swarm.Address, peerTime time.Time) {
+	r.peer = peer
+	r.peerTime = peerTime
 }
 
 func TestTimeLimitedPayment(t *testing.T) {
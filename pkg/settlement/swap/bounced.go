@@ -0,0 +1,209 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package swap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	bouncedChequePrefix  = "swap_bounced_cheque_"
+	pendingCashoutPrefix = "swap_pending_cashout_"
+)
+
+// cashoutConfirmationTimeout bounds the wait for the confirmation of a cashout.
+const cashoutConfirmationTimeout = time.Hour
+
+// BouncedCheque records a cashout of a peer's cheque which bounced,
+// meaning the peer's chequebook could not cover the full payout.
+type BouncedCheque struct {
+	Peer             swarm.Address
+	Chequebook       common.Address
+	TxHash           common.Hash
+	CumulativePayout *big.Int // cumulative payout of the bounced cheque
+	TotalPayout      *big.Int // amount which was actually paid out
+	Timestamp        int64    // unix time at which the bounce was detected
+}
+
+// pendingCashout is the cashout of a peer's cheque whose confirmation
+// is not yet checked for the bounce. It is persisted so that the check
+// is resumed if the node stops before the cashout is confirmed.
+type pendingCashout struct {
+	Peer       swarm.Address
+	Chequebook common.Address
+	TxHash     common.Hash
+}
+
+// bouncedChequeKey computes the store key for a bounced cashout of a peer.
+func bouncedChequeKey(peer swarm.Address, txHash common.Hash) string {
+	return fmt.Sprintf("%s%s_%x", bouncedChequePrefix, peer, txHash)
+}
+
+// pendingCashoutKey computes the store key for a pending cashout of a peer.
+func pendingCashoutKey(peer swarm.Address, txHash common.Hash) string {
+	return fmt.Sprintf("%s%s_%x", pendingCashoutPrefix, peer, txHash)
+}
+
+// Start resumes the checks for the bounce of the cashouts
+// which were not yet confirmed when the node was stopped.
+func (s *Service) Start() error {
+	var pending []pendingCashout
+	err := s.store.Iterate(pendingCashoutPrefix, func(key, val []byte) (stop bool, err error) {
+		var p pendingCashout
+		if err := json.Unmarshal(val, &p); err != nil {
+			return true, fmt.Errorf("pending cashout %s: %w", string(key), err)
+		}
+		pending = append(pending, p)
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, p := range pending {
+		s.watchCashout(p)
+	}
+	return nil
+}
+
+// addPendingCashout persists the cashout of the peer's cheque
+// and starts waiting for its confirmation.
+func (s *Service) addPendingCashout(peer swarm.Address, chequebookAddress common.Address, txHash common.Hash) error {
+	p := pendingCashout{Peer: peer, Chequebook: chequebookAddress, TxHash: txHash}
+	if err := s.store.Put(pendingCashoutKey(peer, txHash), &p); err != nil {
+		return err
+	}
+	s.watchCashout(p)
+	return nil
+}
+
+// watchCashout checks the pending cashout for the bounce in the background,
+// unless the service is closing, in which case the check is left to the
+// next start of the service.
+func (s *Service) watchCashout(p pendingCashout) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.quit:
+		return
+	default:
+	}
+	s.wg.Add(1)
+	go s.checkBounce(p)
+}
+
+// checkBounce waits for the confirmation of the cashout of the peer's cheque
+// and records the cheque as bounced if the chequebook could not cover it.
+// The pending cashout is removed once it is confirmed or it can not be,
+// it is kept if the wait is interrupted to be checked again on the next start.
+func (s *Service) checkBounce(p pendingCashout) {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithTimeout(s.ctx, cashoutConfirmationTimeout)
+	defer cancel()
+
+	last, err := s.cashout.WaitForCashout(ctx, p.Chequebook, p.TxHash)
+	if err != nil {
+		s.logger.Debug("wait for cashout failed", "peer_address", p.Peer, "transaction", p.TxHash, "error", err)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+	} else if last.Result != nil && last.Result.Bounced {
+		if err := s.recordBounce(p.Peer, p.Chequebook, last); err != nil {
+			s.logger.Error(err, "record bounced cheque failed", "peer_address", p.Peer, "transaction", p.TxHash)
+			return
+		}
+	}
+	if err := s.store.Delete(pendingCashoutKey(p.Peer, p.TxHash)); err != nil {
+		s.logger.Error(err, "remove pending cashout failed", "peer_address", p.Peer, "transaction", p.TxHash)
+	}
+}
+
+// recordBounce stores the bounced cashout for the peer if it was not yet
+// recorded and blocklists the peer once the number of bounced cheques
+// reaches the configured threshold.
+func (s *Service) recordBounce(peer swarm.Address, chequebookAddress common.Address, last *chequebook.LastCashout) error {
+	key := bouncedChequeKey(peer, last.TxHash)
+
+	err := s.store.Get(key, &BouncedCheque{})
+	if err == nil {
+		return nil // already recorded
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+
+	err = s.store.Put(key, &BouncedCheque{
+		Peer:             peer,
+		Chequebook:       chequebookAddress,
+		TxHash:           last.TxHash,
+		CumulativePayout: last.Result.CumulativePayout,
+		TotalPayout:      last.Result.TotalPayout,
+		Timestamp:        time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	s.metrics.ChequesBounced.Inc()
+
+	bounced, err := s.BouncedCheques(peer)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Warning("cheque bounced", "peer_address", peer, "transaction", last.TxHash, "bounced_count", len(bounced))
+
+	if s.blocklister == nil || s.bounceThreshold <= 0 || len(bounced) < s.bounceThreshold {
+		return nil
+	}
+
+	s.metrics.BouncedPeersBlocklisted.Inc()
+	return s.blocklister.Blocklist(peer, s.bounceBlocklistDuration, p2p.OffenseAccountingViolation, "swap: repeated bounced cheques")
+}
+
+// BouncedCheques returns the bounced cheques recorded for the peer.
+func (s *Service) BouncedCheques(peer swarm.Address) ([]BouncedCheque, error) {
+	var result []BouncedCheque
+	err := s.store.Iterate(bouncedChequePrefix+peer.String()+"_", func(_, val []byte) (stop bool, err error) {
+		var bounced BouncedCheque
+		if err := json.Unmarshal(val, &bounced); err != nil {
+			return true, err
+		}
+		result = append(result, bounced)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AllBouncedCheques returns the bounced cheques recorded for all peers.
+func (s *Service) AllBouncedCheques() (map[string][]BouncedCheque, error) {
+	result := make(map[string][]BouncedCheque)
+	err := s.store.Iterate(bouncedChequePrefix, func(key, val []byte) (stop bool, err error) {
+		var bounced BouncedCheque
+		if err := json.Unmarshal(val, &bounced); err != nil {
+			return true, fmt.Errorf("bounced cheque %s: %w", string(key), err)
+		}
+		result[bounced.Peer.String()] = append(result[bounced.Peer.String()], bounced)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
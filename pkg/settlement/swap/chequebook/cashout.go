@@ -30,6 +30,8 @@ type CashoutService interface {
 	CashCheque(ctx context.Context, chequebook common.Address, recipient common.Address) (common.Hash, error)
 	// CashoutStatus gets the status of the latest cashout transaction for the chequebook
 	CashoutStatus(ctx context.Context, chequebookAddress common.Address) (*CashoutStatus, error)
+	// WaitForCashout waits until the cashout transaction is confirmed and returns its outcome
+	WaitForCashout(ctx context.Context, chequebookAddress common.Address, txHash common.Hash) (*LastCashout, error)
 }
 
 type cashoutService struct {
@@ -247,6 +249,37 @@ func (s *cashoutService) CashoutStatus(ctx context.Context, chequebookAddress co
 	}, nil
 }
 
+// WaitForCashout waits until the cashout transaction is confirmed and returns its outcome.
+// The cheque of the outcome is only set while the transaction is the last cashout of the chequebook.
+func (s *cashoutService) WaitForCashout(ctx context.Context, chequebookAddress common.Address, txHash common.Hash) (*LastCashout, error) {
+	receipt, err := s.transactionService.WaitForReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	last := &LastCashout{TxHash: txHash}
+
+	var action cashoutAction
+	err = s.store.Get(cashoutActionKey(chequebookAddress), &action)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil && action.TxHash == txHash {
+		last.Cheque = action.Cheque
+	}
+
+	if receipt.Status == types.ReceiptStatusFailed {
+		last.Reverted = true
+		return last, nil
+	}
+
+	last.Result, err = s.parseCashChequeBeneficiaryReceipt(chequebookAddress, receipt)
+	if err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
 // parseCashChequeBeneficiaryReceipt processes the receipt from a CashChequeBeneficiary transaction
 func (s *cashoutService) parseCashChequeBeneficiaryReceipt(chequebookAddress common.Address, receipt *types.Receipt) (*CashChequeResult, error) {
 	result := &CashChequeResult{
@@ -365,6 +365,92 @@ func TestCashoutStatusPending(t *testing.T) {
 
 }
 
+func TestWaitForCashoutBounced(t *testing.T) {
+	t.Parallel()
+
+	chequebookAddress := common.HexToAddress("abcd")
+	recipientAddress := common.HexToAddress("efff")
+	txHash := common.HexToHash("dddd")
+	totalPayout := big.NewInt(100)
+	cumulativePayout := big.NewInt(500)
+
+	cheque := &chequebook.SignedCheque{
+		Cheque: chequebook.Cheque{
+			Beneficiary:      common.HexToAddress("aaaa"),
+			CumulativePayout: cumulativePayout,
+			Chequebook:       chequebookAddress,
+		},
+		Signature: []byte{},
+	}
+
+	store := storemock.NewStateStore()
+	cashoutService := chequebook.NewCashoutService(
+		store,
+		backendmock.New(),
+		transactionmock.New(
+			transactionmock.WithABISend(&chequebookABI, txHash, chequebookAddress, big.NewInt(0), "cashChequeBeneficiary", recipientAddress, cheque.CumulativePayout, cheque.Signature),
+			transactionmock.WithWaitForReceiptFunc(func(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+				if hash != txHash {
+					t.Fatalf("waiting for wrong transaction. wanted %v, got %v", txHash, hash)
+				}
+
+				chequeCashedLogData, err := chequeCashedEventType.Inputs.NonIndexed().Pack(totalPayout, cumulativePayout, big.NewInt(0))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return &types.Receipt{
+					Status: types.ReceiptStatusSuccessful,
+					Logs: []*types.Log{
+						{
+							Address: chequebookAddress,
+							Topics:  []common.Hash{chequeCashedEventType.ID, cheque.Beneficiary.Hash(), recipientAddress.Hash(), cheque.Beneficiary.Hash()},
+							Data:    chequeCashedLogData,
+						},
+						{
+							Address: chequebookAddress,
+							Topics:  []common.Hash{chequeBouncedEventType.ID},
+						},
+					},
+				}, nil
+			}),
+		),
+		chequestoremock.NewChequeStore(
+			chequestoremock.WithLastChequeFunc(func(c common.Address) (*chequebook.SignedCheque, error) {
+				return cheque, nil
+			}),
+		),
+	)
+
+	returnedTxHash, err := cashoutService.CashCheque(context.Background(), chequebookAddress, recipientAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last, err := cashoutService.WaitForCashout(context.Background(), chequebookAddress, returnedTxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifyStatus(t, &chequebook.CashoutStatus{Last: last, UncashedAmount: big.NewInt(0)}, chequebook.CashoutStatus{
+		Last: &chequebook.LastCashout{
+			TxHash: txHash,
+			Cheque: *cheque,
+			Result: &chequebook.CashChequeResult{
+				Beneficiary:      cheque.Beneficiary,
+				Recipient:        recipientAddress,
+				Caller:           cheque.Beneficiary,
+				TotalPayout:      totalPayout,
+				CumulativePayout: cumulativePayout,
+				CallerPayout:     big.NewInt(0),
+				Bounced:          true,
+			},
+			Reverted: false,
+		},
+		UncashedAmount: big.NewInt(0),
+	})
+}
+
 func verifyStatus(t *testing.T, status *chequebook.CashoutStatus, expected chequebook.CashoutStatus) {
 	t.Helper()
 
@@ -25,6 +25,12 @@ func newChequebookContract(address common.Address, transactionService transactio
 	}
 }
 
+// Issuer returns the issuer of the chequebook at the address,
+// which is the only one who can sign its cheques.
+func Issuer(ctx context.Context, transactionService transaction.Service, address common.Address) (common.Address, error) {
+	return newChequebookContract(address, transactionService).Issuer(ctx)
+}
+
 func (c *chequebookContract) Issuer(ctx context.Context) (common.Address, error) {
 	callData, err := chequebookABI.Pack("issuer")
 	if err != nil {
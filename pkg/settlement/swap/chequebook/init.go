@@ -230,3 +230,13 @@ func Init(
 
 	return chequebookService, nil
 }
+
+// StoredAddress returns the address of the chequebook of the node kept in
+// the state store, or storage.ErrNotFound if the chequebook is not deployed.
+func StoredAddress(stateStore storage.StateStorer) (common.Address, error) {
+	var chequebookAddress common.Address
+	if err := stateStore.Get(chequebookKey, &chequebookAddress); err != nil {
+		return common.Address{}, err
+	}
+	return chequebookAddress, nil
+}
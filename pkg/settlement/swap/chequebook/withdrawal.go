@@ -0,0 +1,191 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package chequebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/settlement/swap/erc20"
+	"github.com/ethersphere/bee/pkg/transaction"
+)
+
+// withdrawalLoggerName is the tree path name of the logger for the scheduled withdrawals.
+const withdrawalLoggerName = "chequebook_withdrawal"
+
+// DefaultWithdrawalInterval is the default interval between the scheduled withdrawals.
+const DefaultWithdrawalInterval = 24 * time.Hour
+
+// ErrWithdrawalReverted is returned if the withdrawal from the
+// chequebook to the node wallet reverted, so the transfer is not attempted.
+var ErrWithdrawalReverted = errors.New("chequebook withdrawal reverted")
+
+// WithdrawalPlan describes the transactions of the next scheduled withdrawal.
+type WithdrawalPlan struct {
+	AvailableBalance *big.Int       // available balance of the chequebook
+	Threshold        *big.Int       // balance kept in the chequebook
+	Amount           *big.Int       // amount to be withdrawn, zero if the balance does not exceed the threshold
+	Chequebook       common.Address // chequebook the amount is withdrawn from
+	Wallet           common.Address // node wallet receiving the withdrawal
+	Recipient        common.Address // address the amount is transferred to from the node wallet
+	Next             time.Time      // time of the next scheduled withdrawal
+}
+
+// WithdrawalScheduler periodically withdraws the available balance of the
+// chequebook above the threshold and transfers it from the node wallet to
+// the recipient address, so that the funds at risk on the node are limited.
+type WithdrawalScheduler struct {
+	logger             log.Logger
+	chequebook         Service
+	erc20              erc20.Service
+	transactionService transaction.Service
+	wallet             common.Address
+	recipient          common.Address
+	threshold          *big.Int
+	interval           time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewWithdrawalScheduler creates a new WithdrawalScheduler and starts
+// withdrawing at the given interval.
+func NewWithdrawalScheduler(
+	logger log.Logger,
+	chequebook Service,
+	erc20 erc20.Service,
+	transactionService transaction.Service,
+	wallet common.Address,
+	recipient common.Address,
+	threshold *big.Int,
+	interval time.Duration,
+) *WithdrawalScheduler {
+	s := &WithdrawalScheduler{
+		logger:             logger.WithName(withdrawalLoggerName).Register(),
+		chequebook:         chequebook,
+		erc20:              erc20,
+		transactionService: transactionService,
+		wallet:             wallet,
+		recipient:          recipient,
+		threshold:          threshold,
+		interval:           interval,
+		next:               time.Now().Add(interval),
+		quit:               make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.worker()
+
+	return s
+}
+
+func (s *WithdrawalScheduler) worker() {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.quit
+		cancel()
+	}()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		s.next = time.Now().Add(s.interval)
+		s.mu.Unlock()
+
+		if err := s.withdraw(ctx); err != nil {
+			s.logger.Error(err, "scheduled chequebook withdrawal failed")
+		}
+	}
+}
+
+// Plan returns the transactions the next scheduled withdrawal would make
+// with the current balance of the chequebook, without sending them.
+func (s *WithdrawalScheduler) Plan(ctx context.Context) (*WithdrawalPlan, error) {
+	available, err := s.chequebook.AvailableBalance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("available balance: %w", err)
+	}
+
+	amount := new(big.Int).Sub(available, s.threshold)
+	if amount.Sign() < 0 {
+		amount.SetInt64(0)
+	}
+
+	s.mu.Lock()
+	next := s.next
+	s.mu.Unlock()
+
+	return &WithdrawalPlan{
+		AvailableBalance: available,
+		Threshold:        new(big.Int).Set(s.threshold),
+		Amount:           amount,
+		Chequebook:       s.chequebook.Address(),
+		Wallet:           s.wallet,
+		Recipient:        s.recipient,
+		Next:             next,
+	}, nil
+}
+
+// withdraw executes the plan: the amount is withdrawn from the chequebook
+// and, once the withdrawal is confirmed, transferred to the recipient.
+func (s *WithdrawalScheduler) withdraw(ctx context.Context) error {
+	plan, err := s.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	if plan.Amount.Sign() == 0 {
+		s.logger.Debug("chequebook balance below withdrawal threshold", "available_balance", plan.AvailableBalance, "threshold", plan.Threshold)
+		return nil
+	}
+
+	txHash, err := s.chequebook.Withdraw(ctx, plan.Amount)
+	if err != nil {
+		return fmt.Errorf("withdraw: %w", err)
+	}
+	s.logger.Info("chequebook withdrawal sent", "amount", plan.Amount, "tx", txHash)
+
+	receipt, err := s.transactionService.WaitForReceipt(ctx, txHash)
+	if err != nil {
+		return fmt.Errorf("wait for withdrawal %x: %w", txHash, err)
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("%w: %x", ErrWithdrawalReverted, txHash)
+	}
+
+	txHash, err = s.erc20.Transfer(ctx, plan.Recipient, plan.Amount)
+	if err != nil {
+		return fmt.Errorf("transfer to %x: %w", plan.Recipient, err)
+	}
+	s.logger.Info("withdrawn amount transferred", "amount", plan.Amount, "recipient", plan.Recipient, "tx", txHash)
+
+	return nil
+}
+
+// Close stops the scheduled withdrawals.
+func (s *WithdrawalScheduler) Close() error {
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
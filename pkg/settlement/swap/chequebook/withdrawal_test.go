@@ -0,0 +1,157 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package chequebook_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
+	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook/mock"
+	erc20mock "github.com/ethersphere/bee/pkg/settlement/swap/erc20/mock"
+	transactionmock "github.com/ethersphere/bee/pkg/transaction/mock"
+)
+
+func TestWithdrawalPlan(t *testing.T) {
+	t.Parallel()
+
+	chequebookAddress := common.HexToAddress("0xabcd")
+	wallet := common.HexToAddress("0xbeef")
+	recipient := common.HexToAddress("0xc01d")
+
+	for _, tc := range []struct {
+		name      string
+		available *big.Int
+		amount    *big.Int
+	}{
+		{name: "above threshold", available: big.NewInt(1000), amount: big.NewInt(700)},
+		{name: "at threshold", available: big.NewInt(300), amount: big.NewInt(0)},
+		{name: "below threshold", available: big.NewInt(100), amount: big.NewInt(0)},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			chequebookService := mock.NewChequebook(
+				mock.WithChequebookAddressFunc(func() common.Address { return chequebookAddress }),
+				mock.WithChequebookAvailableBalanceFunc(func(context.Context) (*big.Int, error) {
+					return tc.available, nil
+				}),
+			)
+
+			scheduler := chequebook.NewWithdrawalScheduler(
+				log.Noop,
+				chequebookService,
+				erc20mock.New(),
+				transactionmock.New(),
+				wallet,
+				recipient,
+				big.NewInt(300),
+				time.Hour,
+			)
+			t.Cleanup(func() { _ = scheduler.Close() })
+
+			plan, err := scheduler.Plan(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if plan.Amount.Cmp(tc.amount) != 0 {
+				t.Fatalf("got amount %v, want %v", plan.Amount, tc.amount)
+			}
+			if plan.AvailableBalance.Cmp(tc.available) != 0 {
+				t.Fatalf("got available balance %v, want %v", plan.AvailableBalance, tc.available)
+			}
+			if plan.Chequebook != chequebookAddress {
+				t.Fatalf("got chequebook %x, want %x", plan.Chequebook, chequebookAddress)
+			}
+			if plan.Wallet != wallet {
+				t.Fatalf("got wallet %x, want %x", plan.Wallet, wallet)
+			}
+			if plan.Recipient != recipient {
+				t.Fatalf("got recipient %x, want %x", plan.Recipient, recipient)
+			}
+			if !plan.Next.After(time.Now()) {
+				t.Fatalf("next withdrawal %v not in the future", plan.Next)
+			}
+		})
+	}
+}
+
+func TestWithdrawalScheduled(t *testing.T) {
+	t.Parallel()
+
+	recipient := common.HexToAddress("0xc01d")
+	withdrawTx := common.HexToHash("0x1")
+	transferTx := common.HexToHash("0x2")
+
+	type transfer struct {
+		to    common.Address
+		value *big.Int
+	}
+	transferC := make(chan transfer, 1)
+
+	withdrawn := false
+	chequebookService := mock.NewChequebook(
+		mock.WithChequebookAvailableBalanceFunc(func(context.Context) (*big.Int, error) {
+			if withdrawn {
+				return big.NewInt(300), nil
+			}
+			return big.NewInt(1000), nil
+		}),
+		mock.WithChequebookWithdrawFunc(func(ctx context.Context, amount *big.Int) (common.Hash, error) {
+			if amount.Cmp(big.NewInt(700)) != 0 {
+				t.Errorf("got withdrawn amount %v, want %v", amount, 700)
+			}
+			withdrawn = true
+			return withdrawTx, nil
+		}),
+	)
+
+	transactionService := transactionmock.New(
+		transactionmock.WithWaitForReceiptFunc(func(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+			if txHash != withdrawTx {
+				t.Errorf("got receipt request for %x, want %x", txHash, withdrawTx)
+			}
+			return &types.Receipt{Status: 1}, nil
+		}),
+	)
+
+	erc20Service := erc20mock.New(
+		erc20mock.WithTransferFunc(func(ctx context.Context, address common.Address, value *big.Int) (common.Hash, error) {
+			transferC <- transfer{to: address, value: value}
+			return transferTx, nil
+		}),
+	)
+
+	scheduler := chequebook.NewWithdrawalScheduler(
+		log.Noop,
+		chequebookService,
+		erc20Service,
+		transactionService,
+		common.HexToAddress("0xbeef"),
+		recipient,
+		big.NewInt(300),
+		50*time.Millisecond,
+	)
+	defer scheduler.Close()
+
+	select {
+	case got := <-transferC:
+		if got.to != recipient {
+			t.Fatalf("got transfer to %x, want %x", got.to, recipient)
+		}
+		if got.value.Cmp(big.NewInt(700)) != 0 {
+			t.Fatalf("got transferred value %v, want %v", got.value, 700)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for transfer")
+	}
+}
@@ -7,4 +7,5 @@ var (
 	BeneficiaryPeerKey = beneficiaryPeerKey
 	PeerDeductedByKey  = peerDeductedByKey
 	PeerDeductedForKey = peerDeductedForKey
+	PendingCashoutKey  = pendingCashoutKey
 )
@@ -16,6 +16,9 @@ type metrics struct {
 	ChequesSent      prometheus.Counter
 	ChequesRejected  prometheus.Counter
 	AvailableBalance prometheus.Gauge
+
+	ChequesBounced          prometheus.Counter
+	BouncedPeersBlocklisted prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -58,6 +61,18 @@ func newMetrics() metrics {
 			Name:      "available_balance",
 			Help:      "Currently availeble chequebook balance.",
 		}),
+		ChequesBounced: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "cheques_bounced",
+			Help:      "Number of received cheques which bounced on cashout",
+		}),
+		BouncedPeersBlocklisted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "bounced_peers_blocklisted",
+			Help:      "Number of times a peer was blocklisted for bounced cheques",
+		}),
 	}
 }
 
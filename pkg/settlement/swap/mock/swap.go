@@ -40,6 +40,9 @@ type Service struct {
 
 	cashChequeFunc    func(ctx context.Context, peer swarm.Address) (common.Hash, error)
 	cashoutStatusFunc func(ctx context.Context, peer swarm.Address) (*chequebook.CashoutStatus, error)
+
+	bouncedChequesFunc    func(swarm.Address) ([]swap.BouncedCheque, error)
+	allBouncedChequesFunc func() (map[string][]swap.BouncedCheque, error)
 }
 
 // WithSettlementSentFunc sets the mock settlement function
@@ -122,6 +125,18 @@ func WithCashoutStatusFunc(f func(ctx context.Context, peer swarm.Address) (*che
 	})
 }
 
+func WithBouncedChequesFunc(f func(swarm.Address) ([]swap.BouncedCheque, error)) Option {
+	return optionFunc(func(s *Service) {
+		s.bouncedChequesFunc = f
+	})
+}
+
+func WithAllBouncedChequesFunc(f func() (map[string][]swap.BouncedCheque, error)) Option {
+	return optionFunc(func(s *Service) {
+		s.allBouncedChequesFunc = f
+	})
+}
+
 // New creates the mock swap implementation
 func New(opts ...Option) swap.Interface {
 	mock := new(Service)
@@ -247,6 +262,20 @@ func (s *Service) CashoutStatus(ctx context.Context, peer swarm.Address) (*chequ
 	return nil, nil
 }
 
+func (s *Service) BouncedCheques(peer swarm.Address) ([]swap.BouncedCheque, error) {
+	if s.bouncedChequesFunc != nil {
+		return s.bouncedChequesFunc(peer)
+	}
+	return nil, nil
+}
+
+func (s *Service) AllBouncedCheques() (map[string][]swap.BouncedCheque, error) {
+	if s.allBouncedChequesFunc != nil {
+		return s.allBouncedChequesFunc()
+	}
+	return nil, nil
+}
+
 func (s *Service) ReceiveCheque(ctx context.Context, peer swarm.Address, cheque *chequebook.SignedCheque, exchangeRate, deduction *big.Int) (err error) {
 	defer func() {
 		if err == nil {
@@ -9,10 +9,14 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
+	"github.com/ethersphere/bee/pkg/profitability"
 	"github.com/ethersphere/bee/pkg/settlement"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
 	"github.com/ethersphere/bee/pkg/settlement/swap/swapprotocol"
@@ -47,6 +51,10 @@ type Interface interface {
 	CashCheque(ctx context.Context, peer swarm.Address) (common.Hash, error)
 	// CashoutStatus gets the status of the latest cashout transaction for the peers chequebook
 	CashoutStatus(ctx context.Context, peer swarm.Address) (*chequebook.CashoutStatus, error)
+	// BouncedCheques returns the bounced cheques recorded for the peer
+	BouncedCheques(peer swarm.Address) ([]BouncedCheque, error)
+	// AllBouncedCheques returns the bounced cheques recorded for all peers
+	AllBouncedCheques() (map[string][]BouncedCheque, error)
 }
 
 // Service is the implementation of the swap settlement layer.
@@ -62,22 +70,42 @@ type Service struct {
 	addressbook    Addressbook
 	networkID      uint64
 	cashoutAddress common.Address
+	profitability  *profitability.Ledger
+
+	blocklister             p2p.Blocklister
+	bounceThreshold         int
+	bounceBlocklistDuration time.Duration
+
+	ctx    context.Context // cancelled on Close, to stop waiting for the cashouts
+	cancel context.CancelFunc
+	mu     sync.Mutex    // guards the start of the waits against quit
+	quit   chan struct{} // closed on Close, no new waits are started after
+	wg     sync.WaitGroup
 }
 
 // New creates a new swap Service.
-func New(proto swapprotocol.Interface, logger log.Logger, store storage.StateStorer, chequebook chequebook.Service, chequeStore chequebook.ChequeStore, addressbook Addressbook, networkID uint64, cashout chequebook.CashoutService, accounting settlement.Accounting, cashoutAddress common.Address) *Service {
+// Peers whose cheques bounced bounceThreshold times are blocklisted for bounceBlocklistDuration,
+// a non-positive threshold disables blocklisting.
+func New(proto swapprotocol.Interface, logger log.Logger, store storage.StateStorer, chequebook chequebook.Service, chequeStore chequebook.ChequeStore, addressbook Addressbook, networkID uint64, cashout chequebook.CashoutService, accounting settlement.Accounting, cashoutAddress common.Address, blocklister p2p.Blocklister, bounceThreshold int, bounceBlocklistDuration time.Duration) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Service{
-		proto:          proto,
-		logger:         logger.WithName(loggerName).Register(),
-		store:          store,
-		metrics:        newMetrics(),
-		chequebook:     chequebook,
-		chequeStore:    chequeStore,
-		addressbook:    addressbook,
-		networkID:      networkID,
-		cashout:        cashout,
-		accounting:     accounting,
-		cashoutAddress: cashoutAddress,
+		proto:                   proto,
+		logger:                  logger.WithName(loggerName).Register(),
+		store:                   store,
+		metrics:                 newMetrics(),
+		chequebook:              chequebook,
+		chequeStore:             chequeStore,
+		addressbook:             addressbook,
+		networkID:               networkID,
+		cashout:                 cashout,
+		accounting:              accounting,
+		cashoutAddress:          cashoutAddress,
+		blocklister:             blocklister,
+		bounceThreshold:         bounceThreshold,
+		bounceBlocklistDuration: bounceBlocklistDuration,
+		ctx:                     ctx,
+		cancel:                  cancel,
+		quit:                    make(chan struct{}),
 	}
 }
 
@@ -119,6 +147,12 @@ func (s *Service) ReceiveCheque(ctx context.Context, peer swarm.Address, cheque
 	s.metrics.TotalReceived.Add(tot)
 	s.metrics.ChequesReceived.Inc()
 
+	if s.profitability != nil {
+		if err := s.profitability.AddIncome(profitability.IncomeSettlements, receivedAmount); err != nil {
+			s.logger.Error(err, "record received cheque failed", "peer_address", peer)
+		}
+	}
+
 	return s.accounting.NotifyPaymentReceived(peer, amount)
 }
 
@@ -161,6 +195,11 @@ func (s *Service) SetAccounting(accounting settlement.Accounting) {
 	s.accounting = accounting
 }
 
+// SetProfitability sets the ledger where the received cheques are recorded.
+func (s *Service) SetProfitability(ledger *profitability.Ledger) {
+	s.profitability = ledger
+}
+
 // TotalSent returns the total amount sent to a peer
 func (s *Service) TotalSent(peer swarm.Address) (totalSent *big.Int, err error) {
 	beneficiary, known, err := s.addressbook.Beneficiary(peer)
@@ -352,6 +391,7 @@ func (s *Service) LastReceivedCheques() (map[string]*chequebook.SignedCheque, er
 }
 
 // CashCheque sends a cashing transaction for the last cheque of the peer
+// and records the cheque as bounced if it bounces once the transaction is confirmed.
 func (s *Service) CashCheque(ctx context.Context, peer swarm.Address) (common.Hash, error) {
 	chequebookAddress, known, err := s.addressbook.Chequebook(peer)
 	if err != nil {
@@ -360,7 +400,16 @@ func (s *Service) CashCheque(ctx context.Context, peer swarm.Address) (common.Ha
 	if !known {
 		return common.Hash{}, chequebook.ErrNoCheque
 	}
-	return s.cashout.CashCheque(ctx, chequebookAddress, s.cashoutAddress)
+	txHash, err := s.cashout.CashCheque(ctx, chequebookAddress, s.cashoutAddress)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if err := s.addPendingCashout(peer, chequebookAddress, txHash); err != nil {
+		return common.Hash{}, fmt.Errorf("add pending cashout: %w", err)
+	}
+
+	return txHash, nil
 }
 
 // CashoutStatus gets the status of the latest cashout transaction for the peers chequebook
@@ -372,6 +421,7 @@ func (s *Service) CashoutStatus(ctx context.Context, peer swarm.Address) (*chequ
 	if !known {
 		return nil, chequebook.ErrNoCheque
 	}
+
 	return s.cashout.CashoutStatus(ctx, chequebookAddress)
 }
 
@@ -387,6 +437,18 @@ func (s *Service) AddDeductionByPeer(peer swarm.Address) error {
 	return s.addressbook.AddDeductionBy(peer)
 }
 
+// Close stops waiting for the confirmations of the cashouts,
+// the pending ones are checked again on the next start.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	close(s.quit)
+	s.mu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
 type NoOpSwap struct {
 }
 
@@ -437,3 +499,13 @@ func (*NoOpSwap) CashCheque(ctx context.Context, peer swarm.Address) (common.Has
 func (*NoOpSwap) CashoutStatus(ctx context.Context, peer swarm.Address) (*chequebook.CashoutStatus, error) {
 	return nil, postagecontract.ErrChainDisabled
 }
+
+// BouncedCheques returns the bounced cheques recorded for the peer
+func (*NoOpSwap) BouncedCheques(peer swarm.Address) ([]BouncedCheque, error) {
+	return nil, postagecontract.ErrChainDisabled
+}
+
+// AllBouncedCheques returns the bounced cheques recorded for all peers
+func (*NoOpSwap) AllBouncedCheques() (map[string][]BouncedCheque, error) {
+	return nil, postagecontract.ErrChainDisabled
+}
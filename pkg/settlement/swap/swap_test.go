@@ -8,19 +8,25 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	p2pmock "github.com/ethersphere/bee/pkg/p2p/mock"
 	"github.com/ethersphere/bee/pkg/settlement/swap"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
 	mockchequebook "github.com/ethersphere/bee/pkg/settlement/swap/chequebook/mock"
 	mockchequestore "github.com/ethersphere/bee/pkg/settlement/swap/chequestore/mock"
 	"github.com/ethersphere/bee/pkg/settlement/swap/swapprotocol"
+	"github.com/ethersphere/bee/pkg/spinlock"
 	mockstore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/util/testutil"
 )
 
 type swapProtocolMock struct {
@@ -141,8 +147,9 @@ func (m *addressbookMock) GetDeductionBy(peer swarm.Address) (bool, error) {
 }
 
 type cashoutMock struct {
-	cashCheque    func(ctx context.Context, chequebook common.Address, recipient common.Address) (common.Hash, error)
-	cashoutStatus func(ctx context.Context, chequebookAddress common.Address) (*chequebook.CashoutStatus, error)
+	cashCheque     func(ctx context.Context, chequebook common.Address, recipient common.Address) (common.Hash, error)
+	cashoutStatus  func(ctx context.Context, chequebookAddress common.Address) (*chequebook.CashoutStatus, error)
+	waitForCashout func(ctx context.Context, chequebookAddress common.Address, txHash common.Hash) (*chequebook.LastCashout, error)
 }
 
 func (m *cashoutMock) CashCheque(ctx context.Context, chequebook, recipient common.Address) (common.Hash, error) {
@@ -151,6 +158,12 @@ func (m *cashoutMock) CashCheque(ctx context.Context, chequebook, recipient comm
 func (m *cashoutMock) CashoutStatus(ctx context.Context, chequebookAddress common.Address) (*chequebook.CashoutStatus, error) {
 	return m.cashoutStatus(ctx, chequebookAddress)
 }
+func (m *cashoutMock) WaitForCashout(ctx context.Context, chequebookAddress common.Address, txHash common.Hash) (*chequebook.LastCashout, error) {
+	if m.waitForCashout == nil {
+		return nil, chequebook.ErrNoCashout
+	}
+	return m.waitForCashout(ctx, chequebookAddress, txHash)
+}
 
 func TestReceiveCheque(t *testing.T) {
 	t.Parallel()
@@ -229,6 +242,9 @@ func TestReceiveCheque(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	err := swap.ReceiveCheque(context.Background(), peer, cheque, exchangeRate, deduction)
@@ -305,6 +321,9 @@ func TestReceiveChequeReject(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	err := swap.ReceiveCheque(context.Background(), peer, cheque, exchangeRate, deduction)
@@ -363,6 +382,9 @@ func TestReceiveChequeWrongChequebook(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	err := swapService.ReceiveCheque(context.Background(), peer, cheque, exchangeRate, deduction)
@@ -429,6 +451,9 @@ func TestPay(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	swap.Pay(context.Background(), peer, amount)
@@ -474,6 +499,9 @@ func TestPayIssueError(t *testing.T) {
 		&cashoutMock{},
 		nil,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	observer := newTestObserver()
@@ -527,6 +555,9 @@ func TestPayUnknownBeneficiary(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	swapService.Pay(context.Background(), peer, amount)
@@ -587,6 +618,9 @@ func TestHandshake(t *testing.T) {
 		&cashoutMock{},
 		nil,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	err = swapService.Handshake(peer, beneficiary)
@@ -640,6 +674,9 @@ func TestHandshakeNewPeer(t *testing.T) {
 		&cashoutMock{},
 		nil,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	err = swapService.Handshake(peer, beneficiary)
@@ -685,6 +722,9 @@ func TestMigratePeer(t *testing.T) {
 		&cashoutMock{},
 		nil,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	err = swapService.Handshake(peer, beneficiary)
@@ -733,7 +773,11 @@ func TestCashout(t *testing.T) {
 		},
 		nil,
 		ourChequebookAddress,
+		nil,
+		0,
+		0,
 	)
+	testutil.CleanupCloser(t, swapService)
 
 	returnedHash, err := swapService.CashCheque(context.Background(), peer)
 	if err != nil {
@@ -782,6 +826,9 @@ func TestCashoutStatus(t *testing.T) {
 		},
 		nil,
 		common.Address{},
+		nil,
+		0,
+		0,
 	)
 
 	returnedStatus, err := swapService.CashoutStatus(context.Background(), peer)
@@ -794,6 +841,228 @@ func TestCashoutStatus(t *testing.T) {
 	}
 }
 
+func TestCashChequeBounced(t *testing.T) {
+	t.Parallel()
+
+	logger := log.Noop
+	store := mockstore.NewStateStore()
+
+	theirChequebookAddress := common.HexToAddress("ffff")
+	peer := swarm.MustParseHexAddress("abcd")
+	addressbook := &addressbookMock{
+		chequebook: func(p swarm.Address) (common.Address, bool, error) {
+			return theirChequebookAddress, true, nil
+		},
+	}
+
+	var txHash atomic.Value
+	txHash.Store(common.HexToHash("0x1"))
+	last := func(hash common.Hash) *chequebook.LastCashout {
+		return &chequebook.LastCashout{
+			TxHash: hash,
+			Result: &chequebook.CashChequeResult{
+				TotalPayout:      big.NewInt(200),
+				CumulativePayout: big.NewInt(500),
+				Bounced:          true,
+			},
+		}
+	}
+
+	var blocklisted atomic.Int32
+	blocklistDuration := time.Hour
+	blocklister := p2pmock.New(p2pmock.WithBlocklistFunc(func(a swarm.Address, d time.Duration, o p2p.Offense, _ string) error {
+		if !a.Equal(peer) {
+			t.Errorf("blocklisted wrong peer. wanted %v, got %v", peer, a)
+		}
+		if d != blocklistDuration {
+			t.Errorf("wrong blocklist duration. wanted %v, got %v", blocklistDuration, d)
+		}
+		if o != p2p.OffenseAccountingViolation {
+			t.Errorf("wrong blocklist offense. wanted %v, got %v", p2p.OffenseAccountingViolation, o)
+		}
+		blocklisted.Add(1)
+		return nil
+	}))
+
+	swapService := swap.New(
+		&swapProtocolMock{},
+		logger,
+		store,
+		mockchequebook.NewChequebook(),
+		mockchequestore.NewChequeStore(),
+		addressbook,
+		uint64(1),
+		&cashoutMock{
+			cashCheque: func(context.Context, common.Address, common.Address) (common.Hash, error) {
+				return txHash.Load().(common.Hash), nil
+			},
+			cashoutStatus: func(context.Context, common.Address) (*chequebook.CashoutStatus, error) {
+				return &chequebook.CashoutStatus{
+					Last:           last(txHash.Load().(common.Hash)),
+					UncashedAmount: big.NewInt(0),
+				}, nil
+			},
+			waitForCashout: func(_ context.Context, c common.Address, hash common.Hash) (*chequebook.LastCashout, error) {
+				if c != theirChequebookAddress {
+					t.Errorf("waiting for wrong chequebook. wanted %v, got %v", theirChequebookAddress, c)
+				}
+				return last(hash), nil
+			},
+		},
+		nil,
+		common.Address{},
+		blocklister,
+		2,
+		blocklistDuration,
+	)
+	testutil.CleanupCloser(t, swapService)
+
+	// querying the status of the bounced cashout does not record it
+	if _, err := swapService.CashoutStatus(context.Background(), peer); err != nil {
+		t.Fatal(err)
+	}
+	bounced, err := swapService.BouncedCheques(peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bounced) != 0 {
+		t.Fatalf("got %d bounced cheques after the status query, want 0", len(bounced))
+	}
+
+	waitBounced := func(n int) []swap.BouncedCheque {
+		t.Helper()
+		var bounced []swap.BouncedCheque
+		err := spinlock.Wait(5*time.Second, func() bool {
+			bounced, _ = swapService.BouncedCheques(peer)
+			return len(bounced) == n
+		})
+		if err != nil {
+			t.Fatalf("got %d bounced cheques, want %d", len(bounced), n)
+		}
+		return bounced
+	}
+
+	// the bounce is recorded once the cashout is confirmed
+	if _, err := swapService.CashCheque(context.Background(), peer); err != nil {
+		t.Fatal(err)
+	}
+	bounced = waitBounced(1)
+	if bounced[0].TxHash != common.HexToHash("0x1") || bounced[0].TotalPayout.Cmp(big.NewInt(200)) != 0 || bounced[0].Chequebook != theirChequebookAddress {
+		t.Fatalf("unexpected bounced cheque %+v", bounced[0])
+	}
+	if blocklisted.Load() != 0 {
+		t.Fatal("peer blocklisted below the bounce threshold")
+	}
+
+	txHash.Store(common.HexToHash("0x2"))
+	if _, err := swapService.CashCheque(context.Background(), peer); err != nil {
+		t.Fatal(err)
+	}
+	waitBounced(2)
+	err = spinlock.Wait(5*time.Second, func() bool { return blocklisted.Load() == 1 })
+	if err != nil {
+		t.Fatalf("peer blocklisted %d times, want 1", blocklisted.Load())
+	}
+
+	all, err := swapService.AllBouncedCheques()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all[peer.String()]) != 2 {
+		t.Fatalf("got %d bounced cheques for peer, want 2", len(all[peer.String()]))
+	}
+}
+
+func TestCashChequeBouncedAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	store := mockstore.NewStateStore()
+
+	theirChequebookAddress := common.HexToAddress("ffff")
+	peer := swarm.MustParseHexAddress("abcd")
+	txHash := common.HexToHash("0x1")
+	addressbook := &addressbookMock{
+		chequebook: func(p swarm.Address) (common.Address, bool, error) {
+			return theirChequebookAddress, true, nil
+		},
+	}
+	newService := func(waitForCashout func(context.Context, common.Address, common.Hash) (*chequebook.LastCashout, error)) *swap.Service {
+		return swap.New(
+			&swapProtocolMock{},
+			log.Noop,
+			store,
+			mockchequebook.NewChequebook(),
+			mockchequestore.NewChequeStore(),
+			addressbook,
+			uint64(1),
+			&cashoutMock{
+				cashCheque: func(context.Context, common.Address, common.Address) (common.Hash, error) {
+					return txHash, nil
+				},
+				waitForCashout: waitForCashout,
+			},
+			nil,
+			common.Address{},
+			nil,
+			0,
+			0,
+		)
+	}
+
+	// the node stops before the cashout is confirmed
+	waiting := make(chan struct{})
+	swapService := newService(func(ctx context.Context, _ common.Address, _ common.Hash) (*chequebook.LastCashout, error) {
+		close(waiting)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err := swapService.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := swapService.CashCheque(context.Background(), peer); err != nil {
+		t.Fatal(err)
+	}
+	<-waiting
+	if err := swapService.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// no wait is started once the service is closed
+	if _, err := swapService.CashCheque(context.Background(), peer); err != nil {
+		t.Fatal(err)
+	}
+
+	// the cashout is checked again on the next start
+	swapService = newService(func(_ context.Context, _ common.Address, hash common.Hash) (*chequebook.LastCashout, error) {
+		return &chequebook.LastCashout{
+			TxHash: hash,
+			Result: &chequebook.CashChequeResult{
+				TotalPayout:      big.NewInt(200),
+				CumulativePayout: big.NewInt(500),
+				Bounced:          true,
+			},
+		}, nil
+	})
+	testutil.CleanupCloser(t, swapService)
+	if err := swapService.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := spinlock.Wait(5*time.Second, func() bool {
+		err := store.Get(swap.PendingCashoutKey(peer, txHash), new(struct{}))
+		return errors.Is(err, storage.ErrNotFound)
+	})
+	if err != nil {
+		t.Fatal("pending cashout not removed")
+	}
+	bounced, err := swapService.BouncedCheques(peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bounced) != 1 || bounced[0].TxHash != txHash {
+		t.Fatalf("got bounced cheques %+v, want the cheque of %s", bounced, txHash)
+	}
+}
+
 func TestStateStoreKeys(t *testing.T) {
 	t.Parallel()
 
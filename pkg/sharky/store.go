@@ -164,6 +164,9 @@ func (s *Store) Write(ctx context.Context, data []byte) (loc Location, err error
 		return loc, ctx.Err()
 	}
 
+	// once the write is taken by a shard, the slot is used, so the result
+	// is awaited regardless of the context, for the caller to be able to
+	// release the location it gets.
 	select {
 	case e := <-c:
 		if e.err == nil {
@@ -177,8 +180,6 @@ func (s *Store) Write(ctx context.Context, data []byte) (loc Location, err error
 		return e.loc, e.err
 	case <-s.quit:
 		return loc, ErrQuitting
-	case <-ctx.Done():
-		return loc, ctx.Err()
 	}
 }
 
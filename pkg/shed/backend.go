@@ -0,0 +1,190 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shed
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/util/registry"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// The fields and the indexes of the DB are kept in a Backend, the ordered
+// key-value store, so that the engines other than the goleveldb can be
+// compiled into the node and selected by the name in the Options, without
+// forking the packages built on the shed. A backend package registers its
+// BackendFactory on init, as the shed does with the goleveldb under the
+// DefaultBackend, and is compiled in by importing it.
+//
+// The goleveldb types are the common language of the backends: the batches
+// are replayed into the engine, the missing keys are reported with the
+// leveldb.ErrNotFound and the writes to the closed backend with the
+// leveldb.ErrClosed, and the engine iterators are adapted to the
+// iterator.Iterator, so the callers do not depend on the engine.
+
+// DefaultBackend is the name of the goleveldb backend,
+// which is used if none is selected.
+const DefaultBackend = "leveldb"
+
+// ErrUnknownBackend is returned if no backend is registered under the name.
+var ErrUnknownBackend = errors.New("unknown storage backend")
+
+// Reader reads the keys from a consistent view of the backend.
+type Reader interface {
+	// Get returns the value of the key or leveldb.ErrNotFound.
+	Get(key []byte) ([]byte, error)
+	// Has reports whether the key exists.
+	Has(key []byte) (bool, error)
+}
+
+// Snapshot is the consistent read-only view of the backend
+// at the time it is taken, it must be released after use.
+type Snapshot interface {
+	Reader
+	Release()
+}
+
+// Backend is the ordered key-value store of the DB.
+type Backend interface {
+	Reader
+	// Put sets the value of the key.
+	Put(key, value []byte) error
+	// Delete deletes the key, it is not an error if it does not exist.
+	Delete(key []byte) error
+	// Write applies the batch atomically, it is synced
+	// to the durable storage before it returns if sync is set.
+	Write(batch *leveldb.Batch, sync bool) error
+	// NewIterator returns the iterator over all the keys in their
+	// lexicographic order, it must be released after use.
+	NewIterator() iterator.Iterator
+	// Snapshot returns the consistent view of the backend.
+	Snapshot() (Snapshot, error)
+	// Compact compacts the key range [start, end), the whole
+	// backend if both are nil. It may be a no-op for some engines.
+	Compact(start, end []byte) error
+	// Close closes the backend.
+	Close() error
+}
+
+// BackendFactory opens the backend at the path with the options,
+// the backend is kept in memory if the path is empty.
+type BackendFactory func(path string, o *Options) (Backend, error)
+
+var backends = registry.New[BackendFactory]("storage backend")
+
+// nolint:gochecknoinits
+func init() {
+	RegisterBackend(DefaultBackend, openLevelDB)
+}
+
+// RegisterBackend registers the factory of the backend under the name.
+// It panics if the name is already registered, as that is a build error.
+func RegisterBackend(name string, f BackendFactory) {
+	backends.Register(name, f)
+}
+
+// openBackend opens the backend registered under the name,
+// or the DefaultBackend if the name is empty.
+func openBackend(name, path string, o *Options) (Backend, error) {
+	if name == "" {
+		name = DefaultBackend
+	}
+	f, ok := backends.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownBackend, name)
+	}
+	return f(path, o)
+}
+
+// Backends returns the sorted names of the registered backends.
+func Backends() []string {
+	return backends.Names()
+}
+
+// levelDB is the goleveldb backend.
+type levelDB struct {
+	ldb *leveldb.DB
+}
+
+func openLevelDB(path string, o *Options) (Backend, error) {
+	var (
+		ldb *leveldb.DB
+		err error
+	)
+	if path == "" {
+		ldb, err = leveldb.Open(storage.NewMemStorage(), nil)
+	} else {
+		ldb, err = leveldb.OpenFile(path, &opt.Options{
+			OpenFilesCacheCapacity: int(o.OpenFilesLimit),
+			BlockCacheCapacity:     int(o.BlockCacheCapacity),
+			WriteBuffer:            int(o.WriteBufferSize),
+			DisableSeeksCompaction: o.DisableSeeksCompaction,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &levelDB{ldb: ldb}, nil
+}
+
+func (l *levelDB) Get(key []byte) ([]byte, error) {
+	return l.ldb.Get(key, nil)
+}
+
+func (l *levelDB) Has(key []byte) (bool, error) {
+	return l.ldb.Has(key, nil)
+}
+
+func (l *levelDB) Put(key, value []byte) error {
+	return l.ldb.Put(key, value, nil)
+}
+
+func (l *levelDB) Delete(key []byte) error {
+	return l.ldb.Delete(key, nil)
+}
+
+func (l *levelDB) Write(batch *leveldb.Batch, sync bool) error {
+	if sync {
+		return l.ldb.Write(batch, &opt.WriteOptions{Sync: true})
+	}
+	return l.ldb.Write(batch, nil)
+}
+
+func (l *levelDB) NewIterator() iterator.Iterator {
+	return l.ldb.NewIterator(nil, nil)
+}
+
+func (l *levelDB) Snapshot() (Snapshot, error) {
+	s, err := l.ldb.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return levelDBSnapshot{s}, nil
+}
+
+func (l *levelDB) Compact(start, end []byte) error {
+	return l.ldb.CompactRange(util.Range{Start: start, Limit: end})
+}
+
+func (l *levelDB) Close() error {
+	return l.ldb.Close()
+}
+
+type levelDBSnapshot struct {
+	*leveldb.Snapshot
+}
+
+func (s levelDBSnapshot) Get(key []byte) ([]byte, error) {
+	return s.Snapshot.Get(key, nil)
+}
+
+func (s levelDBSnapshot) Has(key []byte) (bool, error) {
+	return s.Snapshot.Has(key, nil)
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shed
+
+import (
+	"errors"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const testBackend = "test"
+
+// testBackendWrites counts the batches written to the test backend.
+var testBackendWrites atomic.Int64
+
+// countingBackend is the goleveldb backend which counts the written batches.
+type countingBackend struct {
+	Backend
+}
+
+func (b countingBackend) Write(batch *leveldb.Batch, sync bool) error {
+	testBackendWrites.Add(1)
+	return b.Backend.Write(batch, sync)
+}
+
+// nolint:gochecknoinits
+func init() {
+	RegisterBackend(testBackend, func(path string, o *Options) (Backend, error) {
+		b, err := openLevelDB(path, o)
+		if err != nil {
+			return nil, err
+		}
+		return countingBackend{b}, nil
+	})
+}
+
+func TestBackend(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDB(t.TempDir(), &Options{Backend: testBackend})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	index, err := db.NewIndex("retrieval", retrievalIndexFuncs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Item{
+		Address: []byte("hash"),
+		Data:    []byte("DATA"),
+	}
+	writes := testBackendWrites.Load()
+	batch := new(leveldb.Batch)
+	if err := index.PutInBatch(batch, want); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.WriteBatch(batch); err != nil {
+		t.Fatal(err)
+	}
+	if testBackendWrites.Load() == writes {
+		t.Fatal("batch not written to the backend")
+	}
+
+	items := []Item{{Address: want.Address}}
+	if err := index.Fill(items); err != nil {
+		t.Fatal(err)
+	}
+	checkItem(t, items[0], want)
+}
+
+func TestBackendUnknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewDB("", &Options{Backend: "unknown"})
+	if !errors.Is(err, ErrUnknownBackend) {
+		t.Fatalf("got error %v, want %v", err, ErrUnknownBackend)
+	}
+}
+
+func TestBackends(t *testing.T) {
+	t.Parallel()
+
+	// other backends may be compiled in
+	names := Backends()
+	for _, want := range []string{DefaultBackend, testBackend} {
+		if i := sort.SearchStrings(names, want); i == len(names) || names[i] != want {
+			t.Fatalf("backend %q not in the backends %v", want, names)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on the duplicate backend")
+		}
+	}()
+	RegisterBackend(DefaultBackend, openLevelDB)
+}
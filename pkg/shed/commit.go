@@ -0,0 +1,137 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shed
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// maxGroupCommitSize is the size of the coalesced batches in bytes
+// above which they are committed without waiting for the latency budget.
+const maxGroupCommitSize = 4 * 1024 * 1024
+
+type commitRequest struct {
+	batch *leveldb.Batch
+	errC  chan error
+}
+
+// committer coalesces the batches written concurrently into a single
+// synced write. The group is committed as soon as no other writer is
+// queued, and waits at most for the latency budget for the queued batches
+// to join it. The batches are applied in the order they were received, so
+// the group has the same effect as the batches written one by one. If the
+// group fails, its batches are written one by one, so that a failing batch
+// does not fail the others.
+type committer struct {
+	backend  Backend
+	latency  time.Duration
+	metrics  metrics
+	requests chan commitRequest
+	queued   atomic.Int64 // the writers which are not yet collected in a group
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newCommitter(backend Backend, latency time.Duration, metrics metrics) *committer {
+	c := &committer{
+		backend:  backend,
+		latency:  latency,
+		metrics:  metrics,
+		requests: make(chan commitRequest),
+		quit:     make(chan struct{}),
+	}
+	c.wg.Add(1)
+	go c.run()
+	return c
+}
+
+// write commits the batch together with the concurrently written
+// batches and returns once the group is committed.
+func (c *committer) write(batch *leveldb.Batch) error {
+	r := commitRequest{batch: batch, errC: make(chan error, 1)}
+	c.queued.Add(1)
+	select {
+	case c.requests <- r:
+	case <-c.quit:
+		c.queued.Add(-1)
+		return leveldb.ErrClosed
+	}
+	return <-r.errC
+}
+
+func (c *committer) run() {
+	defer c.wg.Done()
+
+	for {
+		var group []commitRequest
+		select {
+		case r := <-c.requests:
+			c.queued.Add(-1)
+			group = append(group, r)
+		case <-c.quit:
+			return
+		}
+
+		size := len(group[0].batch.Dump())
+		timer := time.NewTimer(c.latency)
+	collect:
+		for size < maxGroupCommitSize && c.queued.Load() > 0 {
+			select {
+			case r := <-c.requests:
+				c.queued.Add(-1)
+				group = append(group, r)
+				size += len(r.batch.Dump())
+			case <-timer.C:
+				break collect
+			case <-c.quit:
+				break collect
+			}
+		}
+		timer.Stop()
+
+		c.commit(group)
+	}
+}
+
+func (c *committer) commit(group []commitRequest) {
+	err := c.writeGroup(group)
+	if err != nil && len(group) > 1 {
+		// the group is written atomically, so none of its batches
+		// are applied and each of them gets its own error
+		for _, r := range group {
+			r.errC <- c.backend.Write(r.batch, true)
+		}
+		return
+	}
+	for _, r := range group {
+		r.errC <- err
+	}
+}
+
+func (c *committer) writeGroup(group []commitRequest) error {
+	batch := group[0].batch
+	if len(group) > 1 {
+		batch = new(leveldb.Batch)
+		for _, r := range group {
+			if err := r.batch.Replay(batch); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.metrics.GroupCommitCounter.Inc()
+	c.metrics.GroupCommitBatches.Observe(float64(len(group)))
+	return c.backend.Write(batch, true)
+}
+
+// close stops the committer once the collected batches are committed.
+func (c *committer) close() {
+	close(c.quit)
+	c.wg.Wait()
+}
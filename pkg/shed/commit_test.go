@@ -0,0 +1,132 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shed
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var errBadBatch = errors.New("bad batch")
+
+// failingBackend fails the writes of the batches with the bad key,
+// the first write is blocked until the unblock channel is closed.
+type failingBackend struct {
+	Backend
+	bad     []byte
+	unblock chan struct{}
+
+	mu     sync.Mutex
+	writes int
+}
+
+func (b *failingBackend) Write(batch *leveldb.Batch, sync bool) error {
+	b.mu.Lock()
+	b.writes++
+	first := b.writes == 1
+	b.mu.Unlock()
+	if first {
+		<-b.unblock
+	}
+
+	r := new(keysReplay)
+	if err := batch.Replay(r); err != nil {
+		return err
+	}
+	for _, key := range r.keys {
+		if bytes.Equal(key, b.bad) {
+			return errBadBatch
+		}
+	}
+	return nil
+}
+
+func (b *failingBackend) writesCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.writes
+}
+
+// keysReplay collects the keys of the batch.
+type keysReplay struct {
+	keys [][]byte
+}
+
+func (r *keysReplay) Put(key, _ []byte) { r.keys = append(r.keys, key) }
+func (r *keysReplay) Delete(key []byte) { r.keys = append(r.keys, key) }
+
+func batchOf(key string) *leveldb.Batch {
+	batch := new(leveldb.Batch)
+	batch.Put([]byte(key), []byte(key))
+	return batch
+}
+
+// TestCommitter validates that the batch of the single writer is committed
+// without waiting for the latency, and that the batches of the failed group
+// are written one by one.
+func TestCommitter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("single writer", func(t *testing.T) {
+		t.Parallel()
+
+		unblock := make(chan struct{})
+		close(unblock)
+		c := newCommitter(&failingBackend{unblock: unblock}, time.Minute, newMetrics())
+		t.Cleanup(c.close)
+
+		errC := make(chan error, 1)
+		go func() { errC <- c.write(batchOf("single")) }()
+		select {
+		case err := <-errC:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("batch of the single writer waits for the latency")
+		}
+	})
+
+	t.Run("failed group", func(t *testing.T) {
+		t.Parallel()
+
+		backend := &failingBackend{bad: []byte("bad"), unblock: make(chan struct{})}
+		c := newCommitter(backend, time.Minute, newMetrics())
+		t.Cleanup(c.close)
+
+		errC := make(chan error, 1)
+		go func() { errC <- c.write(batchOf("first")) }()
+		// the good and the bad batches are queued while the first is written
+		for backend.writesCount() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		goodC, badC := make(chan error, 1), make(chan error, 1)
+		go func() { goodC <- c.write(batchOf("good")) }()
+		go func() { badC <- c.write(batchOf("bad")) }()
+		for c.queued.Load() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		close(backend.unblock)
+
+		if err := <-errC; err != nil {
+			t.Fatal(err)
+		}
+		if err := <-goodC; err != nil {
+			t.Fatalf("got error %v for the good batch", err)
+		}
+		if err := <-badC; !errors.Is(err, errBadBatch) {
+			t.Fatalf("got error %v, want %v", err, errBadBatch)
+		}
+		// the first batch, the failed group and its two batches one by one
+		if got := backend.writesCount(); got != 4 {
+			t.Fatalf("got %d writes, want 4", got)
+		}
+	})
+}
@@ -24,12 +24,10 @@ package shed
 
 import (
 	"errors"
+	"time"
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
-	"github.com/syndtr/goleveldb/leveldb/opt"
-	"github.com/syndtr/goleveldb/leveldb/storage"
-	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 var (
@@ -40,20 +38,29 @@ var (
 )
 
 type Options struct {
+	// Backend is the name of the registered backend the DB is
+	// kept in, the DefaultBackend if empty. The leveldb options
+	// below may be interpreted or ignored by the other backends.
+	Backend                string
 	BlockCacheCapacity     uint64
 	WriteBufferSize        uint64
 	OpenFilesLimit         uint64
 	DisableSeeksCompaction bool
+	// GroupCommitLatency is the longest time a written batch waits for
+	// the concurrently written batches to be synced together with it.
+	// Zero writes every batch on its own without the sync.
+	GroupCommitLatency time.Duration
 }
 
-// DB provides abstractions over LevelDB in order to
-// implement complex structures using fields and ordered indexes.
+// DB provides abstractions over the ordered key-value Backend in order
+// to implement complex structures using fields and ordered indexes.
 // It provides a schema functionality to store fields and indexes
 // information about naming and types.
 type DB struct {
-	ldb     *leveldb.DB
-	metrics metrics
-	quit    chan struct{} // Quit channel to stop the metrics collection before closing the database
+	backend   Backend
+	metrics   metrics
+	committer *committer    // nil if the batches are not group committed
+	quit      chan struct{} // Quit channel to stop the metrics collection before closing the database
 }
 
 // NewDB constructs a new DB and validates the schema
@@ -68,23 +75,20 @@ func NewDB(path string, o *Options) (db *DB, err error) {
 			DisableSeeksCompaction: defaultDisableSeeksCompaction,
 		}
 	}
-	var ldb *leveldb.DB
-	if path == "" {
-		ldb, err = leveldb.Open(storage.NewMemStorage(), nil)
-	} else {
-		ldb, err = leveldb.OpenFile(path, &opt.Options{
-			OpenFilesCacheCapacity: int(o.OpenFilesLimit),
-			BlockCacheCapacity:     int(o.BlockCacheCapacity),
-			WriteBuffer:            int(o.WriteBufferSize),
-			DisableSeeksCompaction: o.DisableSeeksCompaction,
-		})
+	backend, err := openBackend(o.Backend, path, o)
+	if err != nil {
+		return nil, err
 	}
 
+	db, err = newDB(backend)
 	if err != nil {
+		_ = backend.Close()
 		return nil, err
 	}
-
-	return NewDBWrap(ldb)
+	if o.GroupCommitLatency > 0 {
+		db.committer = newCommitter(backend, o.GroupCommitLatency, db.metrics)
+	}
+	return db, nil
 }
 
 // NewDBWrap returns new DB which uses the given ldb as its underlying storage.
@@ -93,9 +97,12 @@ func NewDBWrap(ldb *leveldb.DB) (db *DB, err error) {
 	if ldb == nil {
 		panic(errors.New("shed: NewDBWrap: nil ldb"))
 	}
+	return newDB(&levelDB{ldb: ldb})
+}
 
+func newDB(backend Backend) (db *DB, err error) {
 	db = &DB{
-		ldb:     ldb,
+		backend: backend,
 		metrics: newMetrics(),
 	}
 
@@ -119,9 +126,9 @@ func NewDBWrap(ldb *leveldb.DB) (db *DB, err error) {
 	return db, nil
 }
 
-// Put wraps LevelDB Put method to increment metrics counter.
+// Put wraps the backend Put method to increment metrics counter.
 func (db *DB) Put(key, value []byte) (err error) {
-	err = db.ldb.Put(key, value, nil)
+	err = db.backend.Put(key, value)
 	if err != nil {
 		db.metrics.PutFailCounter.Inc()
 		return err
@@ -130,9 +137,9 @@ func (db *DB) Put(key, value []byte) (err error) {
 	return nil
 }
 
-// Get wraps LevelDB Get method to increment metrics counter.
+// Get wraps the backend Get method to increment metrics counter.
 func (db *DB) Get(key []byte) (value []byte, err error) {
-	value, err = db.ldb.Get(key, nil)
+	value, err = db.backend.Get(key)
 	if err != nil {
 		if errors.Is(err, leveldb.ErrNotFound) {
 			db.metrics.GetNotFoundCounter.Inc()
@@ -145,9 +152,9 @@ func (db *DB) Get(key []byte) (value []byte, err error) {
 	return value, nil
 }
 
-// Has wraps LevelDB Has method to increment metrics counter.
+// Has wraps the backend Has method to increment metrics counter.
 func (db *DB) Has(key []byte) (yes bool, err error) {
-	yes, err = db.ldb.Has(key, nil)
+	yes, err = db.backend.Has(key)
 	if err != nil {
 		db.metrics.HasFailCounter.Inc()
 		return false, err
@@ -156,9 +163,9 @@ func (db *DB) Has(key []byte) (yes bool, err error) {
 	return yes, nil
 }
 
-// Delete wraps LevelDB Delete method to increment metrics counter.
+// Delete wraps the backend Delete method to increment metrics counter.
 func (db *DB) Delete(key []byte) (err error) {
-	err = db.ldb.Delete(key, nil)
+	err = db.backend.Delete(key)
 	if err != nil {
 		db.metrics.DeleteFailCounter.Inc()
 		return err
@@ -167,15 +174,21 @@ func (db *DB) Delete(key []byte) (err error) {
 	return nil
 }
 
-// NewIterator wraps LevelDB NewIterator method to increment metrics counter.
+// NewIterator wraps the backend NewIterator method to increment metrics counter.
 func (db *DB) NewIterator() iterator.Iterator {
 	db.metrics.IteratorCounter.Inc()
-	return db.ldb.NewIterator(nil, nil)
+	return db.backend.NewIterator()
 }
 
-// WriteBatch wraps LevelDB Write method to increment metrics counter.
+// WriteBatch wraps the backend Write method to increment metrics counter.
+// With the group commit, the batch is synced together with the batches
+// written concurrently.
 func (db *DB) WriteBatch(batch *leveldb.Batch) (err error) {
-	err = db.ldb.Write(batch, nil)
+	if db.committer != nil {
+		err = db.committer.write(batch)
+	} else {
+		err = db.backend.Write(batch, false)
+	}
 	if err != nil {
 		db.metrics.WriteBatchFailCounter.Inc()
 		return err
@@ -185,14 +198,16 @@ func (db *DB) WriteBatch(batch *leveldb.Batch) (err error) {
 }
 
 // Compact triggers a full database compaction on the underlying
-// LevelDB instance. Use with care! This can be very expensive!
+// backend instance. Use with care! This can be very expensive!
 func (db *DB) Compact(start, end []byte) error {
-	r := util.Range{Start: start, Limit: end}
-	return db.ldb.CompactRange(r)
+	return db.backend.Compact(start, end)
 }
 
-// Close closes LevelDB database.
+// Close closes the backend database.
 func (db *DB) Close() (err error) {
 	close(db.quit)
-	return db.ldb.Close()
+	if db.committer != nil {
+		db.committer.close()
+	}
+	return db.backend.Close()
 }
@@ -17,9 +17,14 @@
 package shed
 
 import (
+	"bytes"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/util/testutil"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 // TestNewDB constructs a new DB
@@ -106,3 +111,65 @@ func newTestDB(t *testing.T) *DB {
 
 	return db
 }
+
+// TestDB_groupCommit writes the batches concurrently with the group
+// commit and validates that all of them are committed and coalesced.
+func TestDB_groupCommit(t *testing.T) {
+	t.Parallel()
+
+	const (
+		count   = 16
+		latency = 100 * time.Millisecond
+	)
+
+	db, err := NewDB(t.TempDir(), &Options{
+		OpenFilesLimit:     defaultOpenFilesLimit,
+		BlockCacheCapacity: defaultBlockCacheCapacity,
+		WriteBufferSize:    defaultWriteBufferSize,
+		GroupCommitLatency: latency,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	errC := make(chan error, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			batch := new(leveldb.Batch)
+			batch.Put([]byte{byte(i)}, []byte{byte(i), byte(i)})
+			errC <- db.WriteBatch(batch)
+		}(i)
+	}
+	wg.Wait()
+	close(errC)
+	for err := range errC {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		got, err := db.Get([]byte{byte(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []byte{byte(i), byte(i)}; !bytes.Equal(got, want) {
+			t.Fatalf("got value %x, want %x", got, want)
+		}
+	}
+	// the batches committed one by one would wait for the latency each
+	if elapsed := time.Since(start); elapsed >= count/2*latency {
+		t.Fatalf("got %v to write the batches, want them coalesced", elapsed)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.WriteBatch(new(leveldb.Batch)); !errors.Is(err, leveldb.ErrClosed) {
+		t.Fatalf("got error %v, want %v", err, leveldb.ErrClosed)
+	}
+}
@@ -199,9 +199,9 @@ func (f Index) Get(keyFields Item) (out Item, err error) {
 // fields. Every item must have all fields needed for encoding the
 // key set. The passed slice items will be changed so that they
 // contain data from the index values. No new slice is allocated.
-// This function uses a single leveldb snapshot.
+// This function uses a single backend snapshot.
 func (f Index) Fill(items []Item) (err error) {
-	snapshot, err := f.db.ldb.GetSnapshot()
+	snapshot, err := f.db.backend.Snapshot()
 	if err != nil {
 		return fmt.Errorf("get snapshot: %w", err)
 	}
@@ -212,7 +212,7 @@ func (f Index) Fill(items []Item) (err error) {
 		if err != nil {
 			return fmt.Errorf("encode key: %w", err)
 		}
-		value, err := snapshot.Get(key, nil)
+		value, err := snapshot.Get(key)
 		if err != nil {
 			return fmt.Errorf("get value: %w", err)
 		}
@@ -240,7 +240,7 @@ func (f Index) Has(keyFields Item) (bool, error) {
 // there this Item's encoded key is stored in the index for each of them.
 func (f Index) HasMulti(items ...Item) ([]bool, error) {
 	have := make([]bool, len(items))
-	snapshot, err := f.db.ldb.GetSnapshot()
+	snapshot, err := f.db.backend.Snapshot()
 	if err != nil {
 		return nil, fmt.Errorf("get snapshot: %w", err)
 	}
@@ -250,7 +250,7 @@ func (f Index) HasMulti(items ...Item) ([]bool, error) {
 		if err != nil {
 			return nil, fmt.Errorf("encode key for address %x: %w", keyFields.Address, err)
 		}
-		have[i], err = snapshot.Has(key, nil)
+		have[i], err = snapshot.Has(key)
 		if err != nil {
 			return nil, fmt.Errorf("has key for address %x: %w", keyFields.Address, err)
 		}
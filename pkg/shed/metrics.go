@@ -25,6 +25,8 @@ type metrics struct {
 	IteratorCounter       prometheus.Counter
 	WriteBatchCounter     prometheus.Counter
 	WriteBatchFailCounter prometheus.Counter
+	GroupCommitCounter    prometheus.Counter
+	GroupCommitBatches    prometheus.Histogram
 }
 
 func newMetrics() metrics {
@@ -103,6 +105,19 @@ func newMetrics() metrics {
 			Name:      "write_batch_fail_count",
 			Help:      "Number of times the WRITE_BATCH operation failed.",
 		}),
+		GroupCommitCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "group_commit_count",
+			Help:      "Number of the synced writes of the coalesced batches.",
+		}),
+		GroupCommitBatches: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "group_commit_batches",
+			Help:      "Number of the batches coalesced into a single synced write.",
+			Buckets:   []float64{1, 2, 4, 8, 16, 32, 64, 128},
+		}),
 	}
 }
 
@@ -51,6 +51,21 @@ func NewSigned(id ID, ch swarm.Chunk, owner, sig []byte) (*SOC, error) {
 	return s, nil
 }
 
+// Signature returns the SOC signature.
+func (s *SOC) Signature() []byte {
+	return s.signature
+}
+
+// OwnerAddress returns the ethereum address of the SOC owner.
+func (s *SOC) OwnerAddress() []byte {
+	return s.owner
+}
+
+// ID returns the SOC id.
+func (s *SOC) ID() []byte {
+	return s.id
+}
+
 // address returns the SOC chunk address.
 func (s *SOC) address() (swarm.Address, error) {
 	if len(s.owner) != crypto.AddressSize {
@@ -0,0 +1,283 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package encrypted provides the statestore encrypting the values of the
+// sensitive keys at rest with AES-GCM, so that the payment channel state,
+// such as the cheques, the accounting balances and the postage issuers,
+// does not leak from a stolen disk.
+//
+// The values are bound to their keys as the additional data, so that they
+// can not be swapped between the keys. The values stored in plain before the
+// encryption was enabled are read as they are and are encrypted when the
+// store is opened.
+package encrypted
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "encryptedstatestore"
+
+// KeySize is the size of the encryption key, which selects AES-256.
+const KeySize = 32
+
+// checkKey is the key of the known value which verifies the encryption key.
+const checkKey = "statestore_encryption_check"
+
+var (
+	// header marks the encrypted values, followed by the nonce and the ciphertext.
+	header = []byte("\x00bee-enc-v1")
+
+	checkValue = []byte("statestore encryption check")
+)
+
+// DefaultPrefixes are the prefixes of the keys of the payment channel state
+// and the postage issuers.
+var DefaultPrefixes = []string{
+	"accounting_",
+	"pseudosettle_",
+	"swap_",
+	"postage",
+}
+
+var (
+	// ErrInvalidKey is returned if the store was encrypted with another key.
+	ErrInvalidKey = errors.New("invalid statestore encryption key")
+	// ErrMalformed is returned if the encrypted value can not be decrypted.
+	ErrMalformed = errors.New("malformed encrypted statestore value")
+)
+
+var _ storage.StateStorer = (*Store)(nil)
+
+// Store wraps the statestore and encrypts the values of the keys with the
+// configured prefixes. The values of the other keys are passed through.
+type Store struct {
+	store    storage.StateStorer
+	aead     cipher.AEAD
+	prefixes []string
+	logger   log.Logger
+}
+
+// New returns the Store encrypting the values of the keys with the prefixes
+// with the key. It fails with ErrInvalidKey if the store was encrypted with
+// another key, and encrypts the values of the keys stored in plain.
+func New(store storage.StateStorer, key []byte, prefixes []string, logger log.Logger) (*Store, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("statestore encryption key size %d, want %d", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{
+		store:    store,
+		aead:     aead,
+		prefixes: append([]string{checkKey}, prefixes...),
+		logger:   logger.WithName(loggerName).Register(),
+	}
+
+	var check rawValue
+	switch err := s.Get(checkKey, &check); {
+	case errors.Is(err, storage.ErrNotFound):
+		if err := s.Put(checkKey, rawValue(checkValue)); err != nil {
+			return nil, fmt.Errorf("put encryption check: %w", err)
+		}
+	case errors.Is(err, ErrMalformed), err == nil && !bytes.Equal(check, checkValue):
+		return nil, ErrInvalidKey
+	case err != nil:
+		return nil, fmt.Errorf("get encryption check: %w", err)
+	}
+
+	n, err := s.encryptPlain()
+	if err != nil {
+		return nil, fmt.Errorf("encrypt plain values: %w", err)
+	}
+	if n > 0 {
+		s.logger.Info("statestore values encrypted", "count", n)
+	}
+	return s, nil
+}
+
+// Get implements the storage.StateStorer.
+func (s *Store) Get(key string, i interface{}) error {
+	if !s.covers(key) {
+		return s.store.Get(key, i)
+	}
+	var v rawValue
+	if err := s.store.Get(key, &v); err != nil {
+		return err
+	}
+	data, err := s.decrypt(key, v)
+	if err != nil {
+		return err
+	}
+	if unmarshaler, ok := i.(encoding.BinaryUnmarshaler); ok {
+		return unmarshaler.UnmarshalBinary(data)
+	}
+	return json.Unmarshal(data, i)
+}
+
+// Put implements the storage.StateStorer.
+func (s *Store) Put(key string, i interface{}) (err error) {
+	if !s.covers(key) {
+		return s.store.Put(key, i)
+	}
+	var data []byte
+	if marshaler, ok := i.(encoding.BinaryMarshaler); ok {
+		if data, err = marshaler.MarshalBinary(); err != nil {
+			return err
+		}
+	} else if data, err = json.Marshal(i); err != nil {
+		return err
+	}
+	v, err := s.encrypt(key, data)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(key, rawValue(v))
+}
+
+// Delete implements the storage.StateStorer.
+func (s *Store) Delete(key string) error {
+	return s.store.Delete(key)
+}
+
+// Iterate implements the storage.StateStorer. The values of the covered
+// keys are decrypted and the encryption check is skipped.
+func (s *Store) Iterate(prefix string, iterFunc storage.StateIterFunc) error {
+	return s.store.Iterate(prefix, func(key, value []byte) (bool, error) {
+		if string(key) == checkKey {
+			return false, nil
+		}
+		if s.covers(string(key)) {
+			data, err := s.decrypt(string(key), value)
+			if err != nil {
+				return true, err
+			}
+			value = data
+		}
+		return iterFunc(key, value)
+	})
+}
+
+// DB implements the storage.StateStorer. The values written directly
+// to the DB are not encrypted.
+func (s *Store) DB() *leveldb.DB {
+	return s.store.DB()
+}
+
+// Close implements the storage.StateStorer.
+func (s *Store) Close() error {
+	return s.store.Close()
+}
+
+// covers reports whether the value of the key is encrypted.
+func (s *Store) covers(key string) bool {
+	for _, p := range s.prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// encrypt returns the header, the random nonce
+// and the value sealed with the key as the additional data.
+func (s *Store) encrypt(key string, data []byte) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	v := make([]byte, len(header)+nonceSize, len(header)+nonceSize+len(data)+s.aead.Overhead())
+	copy(v, header)
+	nonce := v[len(header):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("read nonce: %w", err)
+	}
+	return s.aead.Seal(v, nonce, data, []byte(key)), nil
+}
+
+// decrypt returns the value of the key,
+// which is returned as is if it is not encrypted.
+func (s *Store) decrypt(key string, v []byte) ([]byte, error) {
+	if !bytes.HasPrefix(v, header) {
+		return v, nil
+	}
+	v = v[len(header):]
+	nonceSize := s.aead.NonceSize()
+	if len(v) < nonceSize {
+		return nil, fmt.Errorf("key %q: %w", key, ErrMalformed)
+	}
+	data, err := s.aead.Open(nil, v[:nonceSize], v[nonceSize:], []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", key, ErrMalformed)
+	}
+	return data, nil
+}
+
+// encryptPlain encrypts the values of the covered keys stored in plain,
+// and returns their number.
+func (s *Store) encryptPlain() (int, error) {
+	plain := make(map[string][]byte)
+	for _, p := range s.prefixes {
+		err := s.store.Iterate(p, func(key, value []byte) (bool, error) {
+			if !bytes.HasPrefix(value, header) {
+				plain[string(key)] = value
+			}
+			return false, nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	for key, value := range plain {
+		v, err := s.encrypt(key, value)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.store.Put(key, rawValue(v)); err != nil {
+			return 0, err
+		}
+	}
+	return len(plain), nil
+}
+
+// rawValue is the value stored as it is.
+type rawValue []byte
+
+func (v rawValue) MarshalBinary() ([]byte, error) {
+	return v, nil
+}
+
+func (v *rawValue) UnmarshalBinary(data []byte) error {
+	*v = append((*v)[:0], data...)
+	return nil
+}
+
+// IsEncrypted reports whether the values of the store were encrypted, so that
+// the store is not opened without the encryption once it was enabled.
+func IsEncrypted(store storage.StateStorer) (bool, error) {
+	var v rawValue
+	switch err := store.Get(checkKey, &v); {
+	case errors.Is(err, storage.ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encrypted_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/statestore/encrypted"
+	"github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/statestore/test"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+var (
+	key      = bytes.Repeat([]byte{1}, encrypted.KeySize)
+	otherKey = bytes.Repeat([]byte{2}, encrypted.KeySize)
+)
+
+func TestEncryptedStateStore(t *testing.T) {
+	t.Parallel()
+
+	test.Run(t, func(t *testing.T) storage.StateStorer {
+		t.Helper()
+
+		s, err := encrypted.New(mock.NewStateStore(), key, []string{""}, log.Noop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
+
+func TestEncryptedAtRest(t *testing.T) {
+	t.Parallel()
+
+	underlying := mock.NewStateStore()
+	if err := underlying.Put("accounting_balance_plain", "secret-plain"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := encrypted.IsEncrypted(underlying); err != nil || ok {
+		t.Fatalf("got %v, %v, want the plain store", ok, err)
+	}
+
+	s, err := encrypted.New(underlying, key, encrypted.DefaultPrefixes, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Put("accounting_balance_peer", "secret-value"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("other_key", "public-value"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the covered values, including the ones stored in plain
+	// before, are not readable from the underlying store
+	err = underlying.Iterate("", func(k, v []byte) (bool, error) {
+		if bytes.Contains(v, []byte("secret")) {
+			t.Errorf("value of key %q not encrypted", k)
+		}
+		return false, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v string
+	if err := underlying.Get("other_key", &v); err != nil || v != "public-value" {
+		t.Fatalf("got %q, %v, want the plain value of the uncovered key", v, err)
+	}
+
+	for key, want := range map[string]string{
+		"accounting_balance_plain": "secret-plain",
+		"accounting_balance_peer":  "secret-value",
+		"other_key":                "public-value",
+	} {
+		var got string
+		if err := s.Get(key, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("key %q: got %q, want %q", key, got, want)
+		}
+	}
+
+	var n int
+	err = s.Iterate("accounting_balance_", func(_, v []byte) (bool, error) {
+		if !bytes.Contains(v, []byte("secret")) {
+			t.Errorf("got %q, want the decrypted value", v)
+		}
+		n++
+		return false, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("iterated %d values, want 2", n)
+	}
+
+	ok, err := encrypted.IsEncrypted(underlying)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("store not reported as encrypted")
+	}
+
+	if _, err := encrypted.New(underlying, otherKey, encrypted.DefaultPrefixes, log.Noop); !errors.Is(err, encrypted.ErrInvalidKey) {
+		t.Fatalf("got error %v, want %v", err, encrypted.ErrInvalidKey)
+	}
+	if _, err := encrypted.New(underlying, key, encrypted.DefaultPrefixes, log.Noop); err != nil {
+		t.Fatalf("reopen with the key: %v", err)
+	}
+}
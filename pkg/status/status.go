@@ -0,0 +1,221 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package status exposes the status protocol which exchanges
+// the state of the node that the peers use to judge its health
+// and to compare the state of the nodes in the neighbourhood.
+package status
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/p2p/protobuf"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/status/pb"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "status"
+
+const (
+	protocolName    = "status"
+	protocolVersion = "1.0.0"
+	streamName      = "status"
+)
+
+// Snapshot is the status of the node.
+type Snapshot struct {
+	ReserveSize     uint64  `json:"reserveSize"`
+	StorageRadius   uint8   `json:"storageRadius"`
+	ConnectedPeers  uint64  `json:"connectedPeers"`
+	PullsyncRate    float64 `json:"pullsyncRate"`
+	BatchCommitment uint64  `json:"batchCommitment"`
+	IsHealthy       bool    `json:"isHealthy"`
+}
+
+// Healther reports whether the node considers itself healthy.
+type Healther interface {
+	IsHealthy() bool
+}
+
+// Reserver reports the size of the reserve.
+type Reserver interface {
+	ReserveSize() (uint64, error)
+}
+
+// SyncReporter reports the rate of the chunks synced by the node in chunks per second.
+type SyncReporter interface {
+	Rate() float64
+}
+
+type Service struct {
+	streamer   p2p.Streamer
+	batchStore postage.Storer
+	reserve    Reserver
+	syncer     SyncReporter
+	topology   topology.PeersCounter
+	logger     log.Logger
+
+	mu              sync.Mutex
+	healther        Healther
+	commitmentBlock uint64
+	commitment      uint64
+}
+
+func New(streamer p2p.Streamer, batchStore postage.Storer, reserve Reserver, syncer SyncReporter, topology topology.PeersCounter, logger log.Logger) *Service {
+	return &Service{
+		streamer:   streamer,
+		batchStore: batchStore,
+		reserve:    reserve,
+		syncer:     syncer,
+		topology:   topology,
+		logger:     logger.WithName(loggerName).Register(),
+	}
+}
+
+func (s *Service) Protocol() p2p.ProtocolSpec {
+	return p2p.ProtocolSpec{
+		Name:    protocolName,
+		Version: protocolVersion,
+		StreamSpecs: []p2p.StreamSpec{
+			{
+				Name:    streamName,
+				Handler: s.handler,
+			},
+		},
+	}
+}
+
+// SetHealther sets the source of the health reported to the peers.
+func (s *Service) SetHealther(h Healther) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.healther = h
+}
+
+// PeerSnapshot requests the status of the peer.
+func (s *Service) PeerSnapshot(ctx context.Context, peer swarm.Address) (*Snapshot, error) {
+	stream, err := s.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
+	if err != nil {
+		return nil, fmt.Errorf("new stream: %w", err)
+	}
+	defer func() {
+		go stream.FullClose()
+	}()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+	if err := w.WriteMsgWithContext(ctx, &pb.Get{}); err != nil {
+		return nil, fmt.Errorf("write message: %w", err)
+	}
+
+	var ss pb.Snapshot
+	if err := r.ReadMsgWithContext(ctx, &ss); err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+	if ss.StorageRadius > uint32(swarm.MaxBins) {
+		return nil, fmt.Errorf("invalid storage radius %d", ss.StorageRadius)
+	}
+
+	return &Snapshot{
+		ReserveSize:     ss.ReserveSize,
+		StorageRadius:   uint8(ss.StorageRadius),
+		ConnectedPeers:  ss.ConnectedPeers,
+		PullsyncRate:    ss.PullsyncRate,
+		BatchCommitment: ss.BatchCommitment,
+		IsHealthy:       ss.IsHealthy,
+	}, nil
+}
+
+// LocalSnapshot returns the status of the node.
+func (s *Service) LocalSnapshot() (*Snapshot, error) {
+	commitment, err := s.batchCommitment()
+	if err != nil {
+		return nil, fmt.Errorf("batch commitment: %w", err)
+	}
+
+	reserveSize, err := s.reserve.ReserveSize()
+	if err != nil {
+		return nil, fmt.Errorf("reserve size: %w", err)
+	}
+
+	s.mu.Lock()
+	healthy := s.healther == nil || s.healther.IsHealthy()
+	s.mu.Unlock()
+
+	return &Snapshot{
+		ReserveSize:     reserveSize,
+		StorageRadius:   s.batchStore.StorageRadius(),
+		ConnectedPeers:  uint64(s.topology.PeersCount(topology.Filter{})),
+		PullsyncRate:    s.syncer.Rate(),
+		BatchCommitment: commitment,
+		IsHealthy:       healthy,
+	}, nil
+}
+
+func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) error {
+	loggerV2 := s.logger.V(2).Register()
+
+	w, r := protobuf.NewWriterAndReader(stream)
+	defer stream.FullClose()
+
+	var get pb.Get
+	if err := r.ReadMsgWithContext(ctx, &get); err != nil {
+		return fmt.Errorf("read message: %w", err)
+	}
+	loggerV2.Debug("status requested", "peer_address", p.Address)
+
+	ss, err := s.LocalSnapshot()
+	if err != nil {
+		return err
+	}
+
+	if err := w.WriteMsgWithContext(ctx, &pb.Snapshot{
+		ReserveSize:     ss.ReserveSize,
+		StorageRadius:   uint32(ss.StorageRadius),
+		ConnectedPeers:  ss.ConnectedPeers,
+		PullsyncRate:    ss.PullsyncRate,
+		BatchCommitment: ss.BatchCommitment,
+		IsHealthy:       ss.IsHealthy,
+	}); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	return nil
+}
+
+// batchCommitment returns the sum of the chunks the batches in the
+// batchstore can stamp. The nodes which agree on the state of the chain
+// have the same commitment. The value is cached until the next block.
+func (s *Service) batchCommitment() (uint64, error) {
+	var block uint64
+	if cs := s.batchStore.GetChainState(); cs != nil {
+		block = cs.Block
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if block != 0 && block == s.commitmentBlock {
+		return s.commitment, nil
+	}
+
+	var commitment uint64
+	err := s.batchStore.Iterate(func(b *postage.Batch) (bool, error) {
+		commitment += 1 << b.Depth
+		return false, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.commitmentBlock = block
+	s.commitment = commitment
+	return commitment, nil
+}
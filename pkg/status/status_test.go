@@ -0,0 +1,69 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package status_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p/streamtest"
+	"github.com/ethersphere/bee/pkg/postage"
+	batchstore "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+	"github.com/ethersphere/bee/pkg/status"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+type reserve uint64
+
+func (r reserve) ReserveSize() (uint64, error) { return uint64(r), nil }
+
+type syncer float64
+
+func (s syncer) Rate() float64 { return float64(s) }
+
+type peersCounter int
+
+func (c peersCounter) PeersCount(topology.Filter) int { return int(c) }
+
+type healther bool
+
+func (h healther) IsHealthy() bool { return bool(h) }
+
+func TestPeerSnapshot(t *testing.T) {
+	t.Parallel()
+
+	batch := postagetesting.MustNewBatch(postagetesting.WithDepth(10))
+	bs := batchstore.New(
+		batchstore.WithReserveState(&postage.ReserveState{StorageRadius: 8}),
+		batchstore.WithChainState(&postage.ChainState{Block: 100}),
+		batchstore.WithBatch(batch),
+	)
+
+	server := status.New(nil, bs, reserve(1000), syncer(2.5), peersCounter(2), log.Noop)
+	server.SetHealther(healther(false))
+
+	recorder := streamtest.New(streamtest.WithProtocols(server.Protocol()))
+	client := status.New(recorder, batchstore.New(), nil, nil, nil, log.Noop)
+
+	got, err := client.PeerSnapshot(context.Background(), swarm.RandAddress(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := status.Snapshot{
+		ReserveSize:     1000,
+		StorageRadius:   8,
+		ConnectedPeers:  2,
+		PullsyncRate:    2.5,
+		BatchCommitment: 1 << 10,
+		IsHealthy:       false,
+	}
+	if *got != want {
+		t.Fatalf("got snapshot %+v, want %+v", *got, want)
+	}
+}
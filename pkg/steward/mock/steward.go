@@ -7,6 +7,7 @@ package mock
 import (
 	"context"
 
+	"github.com/ethersphere/bee/pkg/steward"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
@@ -28,6 +29,16 @@ func (s *Steward) IsRetrievable(_ context.Context, addr swarm.Address) (bool, er
 	return addr.Equal(s.addr), nil
 }
 
+// CheckRetrievable implements steward.Interface CheckRetrievable method.
+// The content is reported as retrievable if the given address
+// was the last one given to the Reupload method call.
+func (s *Steward) CheckRetrievable(_ context.Context, addr swarm.Address, _ float64) (*steward.RetrievabilityReport, error) {
+	if addr.Equal(s.addr) {
+		return &steward.RetrievabilityReport{Chunks: 1, Checked: 1}, nil
+	}
+	return &steward.RetrievabilityReport{Chunks: 1, Checked: 1, Unretrievable: []swarm.Address{addr}}, nil
+}
+
 // LastAddress returns the last address given to the Reupload method call.
 func (s *Steward) LastAddress() swarm.Address {
 	return s.addr
@@ -10,6 +10,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"math/rand"
+	"sync"
 
 	"github.com/ethersphere/bee/pkg/pushsync"
 	"github.com/ethersphere/bee/pkg/retrieval"
@@ -23,6 +26,19 @@ import (
 // how many parallel push operations
 const parallelPush = 5
 
+// how many parallel retrievals of the sampled chunks
+const parallelRetrieve = 16
+
+// ErrInvalidSample is returned when the sample ratio is out of the (0, 1] range.
+var ErrInvalidSample = errors.New("invalid sample ratio")
+
+// RetrievabilityReport is the result of the per-chunk retrievability check.
+type RetrievabilityReport struct {
+	Chunks        int             // number of the chunks found by traversing the content
+	Checked       int             // number of the chunks which were retrieved or attempted to be
+	Unretrievable []swarm.Address // chunks which could not be retrieved
+}
+
 type Interface interface {
 	// Reupload root hash and all of its underlying
 	// associated chunks to the network.
@@ -31,6 +47,11 @@ type Interface interface {
 	// IsRetrievable checks whether the content
 	// on the given address is retrievable.
 	IsRetrievable(context.Context, swarm.Address) (bool, error)
+
+	// CheckRetrievable checks the retrievability of the given sample
+	// ratio of the chunks of the content on the given address and
+	// reports the chunks which could not be retrieved.
+	CheckRetrievable(ctx context.Context, root swarm.Address, sample float64) (*RetrievabilityReport, error)
 }
 
 type steward struct {
@@ -38,6 +59,7 @@ type steward struct {
 	push         pushsync.PushSyncer
 	traverser    traversal.Traverser
 	netTraverser traversal.Traverser
+	retrieval    retrieval.Interface
 }
 
 func New(getter storage.Getter, t traversal.Traverser, r retrieval.Interface, p pushsync.PushSyncer) Interface {
@@ -46,6 +68,7 @@ func New(getter storage.Getter, t traversal.Traverser, r retrieval.Interface, p
 		push:         p,
 		traverser:    t,
 		netTraverser: traversal.New(&netGetter{r}),
+		retrieval:    r,
 	}
 }
 
@@ -101,6 +124,102 @@ func (s *steward) IsRetrievable(ctx context.Context, root swarm.Address) (bool,
 	}
 }
 
+// CheckRetrievable implements Interface.CheckRetrievable method.
+// The intermediate chunks are always retrieved, as the content cannot be
+// traversed without them, while only the sample of the remaining chunks
+// is retrieved. The traversal stops at the first intermediate chunk which
+// cannot be retrieved, in which case the report covers only the chunks
+// found up to that point.
+func (s *steward) CheckRetrievable(ctx context.Context, root swarm.Address, sample float64) (*RetrievabilityReport, error) {
+	if sample <= 0 || sample > 1 {
+		return nil, ErrInvalidSample
+	}
+
+	var (
+		getter = &recordingGetter{netGetter: netGetter{s.retrieval}, retrieved: make(map[string]struct{})}
+		seen   = make(map[string]struct{})
+		addrs  []swarm.Address
+	)
+	fn := func(addr swarm.Address) error {
+		if _, ok := seen[addr.ByteString()]; !ok {
+			seen[addr.ByteString()] = struct{}{}
+			addrs = append(addrs, addr)
+		}
+		return nil
+	}
+	switch err := traversal.New(getter).Traverse(ctx, root, fn); {
+	case errors.Is(err, storage.ErrNotFound):
+		return &RetrievabilityReport{
+			Chunks:        len(addrs),
+			Checked:       len(getter.retrieved) + len(getter.missing),
+			Unretrievable: getter.missing,
+		}, nil
+	case err != nil:
+		return nil, fmt.Errorf("traversal of %q failed: %w", root, err)
+	}
+
+	var rest []swarm.Address
+	for _, addr := range addrs {
+		if _, ok := getter.retrieved[addr.ByteString()]; !ok {
+			rest = append(rest, addr)
+		}
+	}
+	rand.Shuffle(len(rest), func(i, j int) { rest[i], rest[j] = rest[j], rest[i] })
+	rest = rest[:int(math.Ceil(sample*float64(len(rest))))]
+
+	var (
+		mu            sync.Mutex
+		unretrievable []swarm.Address
+		sem           = make(chan struct{}, parallelRetrieve)
+		eg, egCtx     = errgroup.WithContext(ctx)
+	)
+	for _, addr := range rest {
+		addr := addr
+		sem <- struct{}{}
+		eg.Go(func() error {
+			defer func() { <-sem }()
+			_, err := s.retrieval.RetrieveChunk(egCtx, addr, swarm.ZeroAddress)
+			if err != nil {
+				if err := egCtx.Err(); err != nil {
+					return err
+				}
+				mu.Lock()
+				unretrievable = append(unretrievable, addr)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return &RetrievabilityReport{
+		Chunks:        len(addrs),
+		Checked:       len(getter.retrieved) + len(rest),
+		Unretrievable: unretrievable,
+	}, nil
+}
+
+// recordingGetter is the netGetter which records
+// the chunks it did and did not manage to retrieve.
+type recordingGetter struct {
+	netGetter
+	retrieved map[string]struct{}
+	missing   []swarm.Address
+}
+
+// Get implements the storage Getter.Get interface.
+func (rg *recordingGetter) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	ch, err := rg.netGetter.Get(ctx, mode, addr)
+	if err != nil {
+		rg.missing = append(rg.missing, addr)
+		return nil, err
+	}
+	rg.retrieved[addr.ByteString()] = struct{}{}
+	return ch, nil
+}
+
 // netGetter implements the storage Getter.Get method in a way
 // that it will try to retrieve the chunk only from the network.
 type netGetter struct {
@@ -7,10 +7,14 @@ package steward_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"sync"
 	"testing"
 
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
 	"github.com/ethersphere/bee/pkg/pushsync"
 	psmock "github.com/ethersphere/bee/pkg/pushsync/mock"
 	"github.com/ethersphere/bee/pkg/steward"
@@ -102,6 +106,84 @@ func TestSteward_ErrWantSelf(t *testing.T) {
 	}
 }
 
+func TestSteward_CheckRetrievable(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx           = context.Background()
+		chunks        = 10
+		data          = testutil.RandBytes(t, chunks*4096)
+		store         = mock.NewStorer()
+		loggingStorer = &loggingStore{Storer: store}
+	)
+
+	pipe := builder.NewPipelineBuilder(ctx, loggingStorer, storage.ModePutUpload, false)
+	fileAddr, err := builder.FeedPipeline(ctx, pipe, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := loadsave.New(store, func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, store, storage.ModePutUpload, false)
+	})
+	m, err := manifest.NewDefaultManifest(ls, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(ctx, "data", manifest.NewEntry(fileAddr, nil)); err != nil {
+		t.Fatal(err)
+	}
+	addr, err := m.Store(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the data chunks are stored before the intermediate one
+	// and only the intermediate ones are retrieved on traversal
+	missing := loggingStorer.addrs[0]
+	retrieval := &failingRetrieval{loggingStore: loggingStorer, missing: missing}
+	s := steward.New(store, traversal.New(store), retrieval, psmock.New(nil))
+
+	report, err := s.CheckRetrievable(ctx, addr, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Checked != report.Chunks {
+		t.Fatalf("got %d checked chunks, want %d", report.Checked, report.Chunks)
+	}
+	if len(report.Unretrievable) != 1 || !report.Unretrievable[0].Equal(missing) {
+		t.Fatalf("got unretrievable chunks %v, want %v", report.Unretrievable, []swarm.Address{missing})
+	}
+
+	sampled, err := s.CheckRetrievable(ctx, addr, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sampled.Chunks != report.Chunks {
+		t.Fatalf("got %d chunks, want %d", sampled.Chunks, report.Chunks)
+	}
+	if want := report.Chunks - chunks/2; sampled.Checked != want {
+		t.Fatalf("got %d checked chunks, want %d", sampled.Checked, want)
+	}
+
+	_, err = s.CheckRetrievable(ctx, addr, 0)
+	if !errors.Is(err, steward.ErrInvalidSample) {
+		t.Fatalf("got error %v, want %v", err, steward.ErrInvalidSample)
+	}
+}
+
+type failingRetrieval struct {
+	*loggingStore
+	missing swarm.Address
+}
+
+func (fr *failingRetrieval) RetrieveChunk(ctx context.Context, addr, sourceAddr swarm.Address) (chunk swarm.Chunk, err error) {
+	if addr.Equal(fr.missing) {
+		return nil, storage.ErrNotFound
+	}
+	return fr.loggingStore.RetrieveChunk(ctx, addr, sourceAddr)
+}
+
 type loggingStore struct {
 	storage.Storer
 	addrs []swarm.Address
@@ -50,8 +50,19 @@ func (s *Store) Put(_ context.Context, _ storage.ModePut, chs ...swarm.Chunk) (e
 	return exist, err
 }
 
-func (s *Store) GetMulti(_ context.Context, _ storage.ModeGet, _ ...swarm.Address) (ch []swarm.Chunk, err error) {
-	panic("not implemented")
+func (s *Store) GetMulti(_ context.Context, _ storage.ModeGet, addrs ...swarm.Address) (chs []swarm.Chunk, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	chs = make([]swarm.Chunk, len(addrs))
+	for i, addr := range addrs {
+		ch, ok := s.store[addr.ByteString()]
+		if !ok {
+			return nil, storage.ErrNotFound
+		}
+		chs[i] = ch
+	}
+	return chs, nil
 }
 
 func (s *Store) Has(_ context.Context, _ swarm.Address) (yes bool, err error) {
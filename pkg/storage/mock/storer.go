@@ -124,8 +124,19 @@ func (m *MockStorer) Put(ctx context.Context, mode storage.ModePut, chs ...swarm
 	return exist, nil
 }
 
-func (m *MockStorer) GetMulti(ctx context.Context, mode storage.ModeGet, addrs ...swarm.Address) (ch []swarm.Chunk, err error) {
-	panic("not implemented") // TODO: Implement
+func (m *MockStorer) GetMulti(_ context.Context, _ storage.ModeGet, addrs ...swarm.Address) (chs []swarm.Chunk, err error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	chs = make([]swarm.Chunk, len(addrs))
+	for i, addr := range addrs {
+		v, has := m.store[addr.String()]
+		if !has {
+			return nil, storage.ErrNotFound
+		}
+		chs[i] = v
+	}
+	return chs, nil
 }
 
 func (m *MockStorer) has(ctx context.Context, addr swarm.Address) (yes bool, err error) {
@@ -140,7 +151,15 @@ func (m *MockStorer) Has(ctx context.Context, addr swarm.Address) (yes bool, err
 }
 
 func (m *MockStorer) HasMulti(ctx context.Context, addrs ...swarm.Address) (yes []bool, err error) {
-	panic("not implemented") // TODO: Implement
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	yes = make([]bool, len(addrs))
+	for i, addr := range addrs {
+		if yes[i], err = m.has(ctx, addr); err != nil {
+			return nil, err
+		}
+	}
+	return yes, nil
 }
 
 func (m *MockStorer) Set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Address) (err error) {
@@ -191,6 +210,16 @@ func (m *MockStorer) Set(ctx context.Context, mode storage.ModeSet, addrs ...swa
 			}
 		case storage.ModeSetRemove:
 			delete(m.store, addr.String())
+		case storage.ModeSetPurge:
+			var pinned bool
+			for _, ad := range m.pinnedAddress {
+				if addr.Equal(ad) {
+					pinned = true
+				}
+			}
+			if !pinned {
+				delete(m.store, addr.String())
+			}
 		default:
 		}
 	}
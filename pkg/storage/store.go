@@ -104,6 +104,8 @@ func (m ModeSet) String() string {
 		return "ModeSetPin"
 	case ModeSetUnpin:
 		return "ModeSetUnpin"
+	case ModeSetPurge:
+		return "ModeSetPurge"
 	default:
 		return "Unknown"
 	}
@@ -119,6 +121,10 @@ const (
 	ModeSetPin
 	// ModeSetUnpin: when a chunk is unpinned using a command locally
 	ModeSetUnpin
+	// ModeSetPurge: when uploaded content is deleted locally; the chunk
+	// is not push synced anymore and it is removed unless it is pinned
+	// or it is in the reserve
+	ModeSetPurge
 )
 
 // Descriptor holds information required for Pull syncing. This struct
@@ -138,7 +144,7 @@ func (d *Descriptor) String() string {
 type Storer interface {
 	Getter
 	Putter
-	GetMulti(ctx context.Context, mode ModeGet, addrs ...swarm.Address) (ch []swarm.Chunk, err error)
+	MultiGetter
 	Hasser
 	Setter
 	LastPullSubscriptionBinID(bin uint8) (id uint64, err error)
@@ -156,6 +162,12 @@ type Getter interface {
 	Get(ctx context.Context, mode ModeGet, addr swarm.Address) (ch swarm.Chunk, err error)
 }
 
+// MultiGetter gets many chunks at once, it fails with
+// ErrNotFound if any of the chunks is not found.
+type MultiGetter interface {
+	GetMulti(ctx context.Context, mode ModeGet, addrs ...swarm.Address) (ch []swarm.Chunk, err error)
+}
+
 type Setter interface {
 	Set(ctx context.Context, mode ModeSet, addrs ...swarm.Address) (err error)
 }
@@ -174,8 +186,9 @@ type PullSubscriber interface {
 }
 
 type Sample struct {
-	Items []swarm.Address
-	Hash  swarm.Address
+	Items  []swarm.Address
+	Hash   swarm.Address
+	Chunks []swarm.Chunk `json:"-"` // stamped chunks of the items
 }
 
 func (s *Sample) String() string {
@@ -25,6 +25,7 @@ import (
 	"github.com/ethersphere/bee/pkg/log"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
+	"github.com/ethersphere/bee/pkg/profitability"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storageincentives/redistribution"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -71,7 +72,7 @@ type Agent struct {
 	state                  *RedistributionState
 }
 
-func New(overlay swarm.Address, ethAddress common.Address, backend ChainBackend, logger log.Logger, monitor Monitor, contract redistribution.Contract, batchExpirer postagecontract.PostageBatchExpirer, redistributionStatuser staking.RedistributionStatuser, radius postage.RadiusChecker, sampler storage.Sampler, blockTime time.Duration, blocksPerRound, blocksPerPhase uint64, stateStore storage.StateStorer, erc20Service erc20.Service, tranService transaction.Service) (*Agent, error) {
+func New(overlay swarm.Address, ethAddress common.Address, backend ChainBackend, logger log.Logger, monitor Monitor, contract redistribution.Contract, batchExpirer postagecontract.PostageBatchExpirer, redistributionStatuser staking.RedistributionStatuser, radius postage.RadiusChecker, sampler storage.Sampler, blockTime time.Duration, blocksPerRound, blocksPerPhase uint64, stateStore storage.StateStorer, erc20Service erc20.Service, tranService transaction.Service, ledger *profitability.Ledger) (*Agent, error) {
 	a := &Agent{
 		overlay:                overlay,
 		metrics:                newMetrics(),
@@ -92,6 +93,7 @@ func New(overlay swarm.Address, ethAddress common.Address, backend ChainBackend,
 		return nil, err
 	}
 
+	state.profitability = ledger
 	a.state = state
 
 	a.wg.Add(1)
@@ -136,6 +138,7 @@ func (a *Agent) start(blockTime time.Duration, blocksPerRound, blocksPerPhase ui
 
 		if round-1 == sampleRound { // the sample has to come from previous round to be able to commit it
 			obf, err := a.commit(ctx, storageRadius, reserveSample, round)
+			a.state.SetRoundPhase(round, commit, err)
 			if err != nil {
 				a.logger.Error(err, "commit")
 			} else {
@@ -176,6 +179,7 @@ func (a *Agent) start(blockTime time.Duration, blocksPerRound, blocksPerPhase ui
 
 		if round == commitRound { // reveal requires the obfuscationKey from the same round
 			err := a.reveal(ctx, storageRadius, reserveSample, obfuscationKey)
+			a.state.SetRoundPhase(round, reveal, err)
 			if err != nil {
 				a.logger.Error(err, "reveal")
 			} else {
@@ -198,6 +202,7 @@ func (a *Agent) start(blockTime time.Duration, blocksPerRound, blocksPerPhase ui
 
 		if round == revealRound { // to claim, previous reveal must've happened in the same round
 			err := a.claim(ctx, round)
+			a.state.SetRoundPhase(round, claim, err)
 			if err != nil {
 				a.logger.Error(err, "claim")
 			}
@@ -211,9 +216,14 @@ func (a *Agent) start(blockTime time.Duration, blocksPerRound, blocksPerPhase ui
 		mtx.Unlock()
 
 		sr, smpl, err := a.play(ctx, round)
+		if err != nil || smpl != nil {
+			// the sample is played in the next round
+			a.state.SetRoundPhase(round+1, sample, err)
+		}
 		if err != nil {
 			a.logger.Error(err, "make sample")
 		} else if smpl != nil {
+			a.state.SetRoundSample(round+1, smpl)
 			mtx.Lock()
 			sampleRound = round
 			reserveSample = smpl
@@ -393,6 +403,7 @@ func (a *Agent) play(ctx context.Context, round uint64) (uint8, []byte, error) {
 	}
 
 	a.state.SetLastPlayedRound(round)
+	a.state.SetRoundSelected(round + 1)
 	a.logger.Info("neighbourhood chosen", "round", round)
 	a.metrics.NeighborhoodSelected.Inc()
 
@@ -494,6 +505,12 @@ func (a *Agent) Status() (*Status, error) {
 	return a.state.Status()
 }
 
+// Rounds returns the data of at most the limit latest
+// rounds the node took part in, starting with the latest one.
+func (a *Agent) Rounds(limit int) ([]RoundData, error) {
+	return a.state.Rounds(limit)
+}
+
 func (a *Agent) HasEnoughFundsToPlay(ctx context.Context) (bool, error) {
 	balance, err := a.backend.BalanceAt(ctx, a.state.ethAddress, nil)
 	if err != nil {
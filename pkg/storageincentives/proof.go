@@ -0,0 +1,146 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storageincentives
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/ethersphere/bee/pkg/bmt"
+	"github.com/ethersphere/bee/pkg/bmtpool"
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// ErrInvalidProof is returned if the reserve proof does not verify.
+var ErrInvalidProof = errors.New("invalid reserve proof")
+
+// inclusionProofLength is the number of the sister
+// nodes in the inclusion proof of the sample hash.
+var inclusionProofLength = bits.Len(swarm.BmtBranches/2) - 1
+
+// ReserveProof proves that the node of the overlay stores the reserve
+// committed to by the hash of its sample. The sample is made of the lowest
+// transformed addresses of the reserve chunks, the transformed address being
+// the HMAC of the chunk data keyed with the anchor. Every item of the sample
+// comes with the chunk it was transformed from and with its inclusion proof
+// in the sample hash.
+type ReserveProof struct {
+	Overlay    swarm.Address
+	Anchor     []byte
+	Depth      uint8
+	SampleHash swarm.Address
+	Items      []ReserveProofItem
+}
+
+// ReserveProofItem is the item of the sample with its proofs.
+type ReserveProofItem struct {
+	TransformedAddress swarm.Address
+	Inclusion          bmt.Proof   // inclusion of the transformed address in the sample hash
+	Chunk              swarm.Chunk // stamped chunk of the transformed address
+}
+
+// NewReserveProof returns the proof of the sample made with the
+// anchor of the reserve of the overlay of the storage depth.
+func NewReserveProof(overlay swarm.Address, anchor []byte, depth uint8, sample storage.Sample) (ReserveProof, error) {
+	if len(sample.Chunks) != len(sample.Items) {
+		return ReserveProof{}, errors.New("sample without chunks")
+	}
+
+	hasher := bmtpool.Get()
+	defer bmtpool.Put(hasher)
+
+	for _, item := range sample.Items {
+		if _, err := hasher.Write(item.Bytes()); err != nil {
+			return ReserveProof{}, err
+		}
+	}
+	hash, err := hasher.Hash(nil)
+	if err != nil {
+		return ReserveProof{}, err
+	}
+	if !bytes.Equal(hash, sample.Hash.Bytes()) {
+		return ReserveProof{}, errors.New("sample hash mismatch")
+	}
+
+	prover := bmt.Prover{Hasher: hasher}
+	items := make([]ReserveProofItem, len(sample.Items))
+	for i, item := range sample.Items {
+		p := prover.Proof(i)
+		items[i] = ReserveProofItem{
+			TransformedAddress: item,
+			// the section and the span are the buffers of the pooled hasher
+			Inclusion: bmt.Proof{
+				Section: append([]byte(nil), p.Section...),
+				Sisters: p.Sisters,
+				Span:    append([]byte(nil), p.Span...),
+			},
+			Chunk: sample.Chunks[i],
+		}
+	}
+
+	return ReserveProof{
+		Overlay:    overlay,
+		Anchor:     anchor,
+		Depth:      depth,
+		SampleHash: sample.Hash,
+		Items:      items,
+	}, nil
+}
+
+// VerifyReserveProof verifies that the items of the proof are included in
+// the sample hash in the ascending order of their transformed addresses and
+// that they are transformed from the valid chunks. The stamps of the chunks
+// are not verified, since it requires the state of the batches on the chain.
+func VerifyReserveProof(p ReserveProof) error {
+	hasher := bmtpool.Get()
+	defer bmtpool.Put(hasher)
+
+	prover := bmt.Prover{Hasher: hasher}
+	for i, item := range p.Items {
+		if i > 0 && !le(p.Items[i-1].TransformedAddress.Bytes(), item.TransformedAddress.Bytes()) {
+			return fmt.Errorf("%w: item %d not in order", ErrInvalidProof, i)
+		}
+
+		section := item.Inclusion.Section
+		offset := (i % 2) * swarm.HashSize
+		if len(item.Inclusion.Sisters) != inclusionProofLength {
+			return fmt.Errorf("%w: item %d with %d sister nodes", ErrInvalidProof, i, len(item.Inclusion.Sisters))
+		}
+		if len(section) != 2*swarm.HashSize || !bytes.Equal(section[offset:offset+swarm.HashSize], item.TransformedAddress.Bytes()) {
+			return fmt.Errorf("%w: item %d not in the proven section", ErrInvalidProof, i)
+		}
+		root, err := prover.Verify(i, item.Inclusion)
+		if err != nil {
+			return fmt.Errorf("%w: item %d: %v", ErrInvalidProof, i, err)
+		}
+		if !bytes.Equal(root, p.SampleHash.Bytes()) {
+			return fmt.Errorf("%w: item %d not included in the sample hash", ErrInvalidProof, i)
+		}
+
+		if item.Chunk == nil || (!cac.Valid(item.Chunk) && !soc.Valid(item.Chunk)) {
+			return fmt.Errorf("%w: item %d of invalid chunk", ErrInvalidProof, i)
+		}
+		if swarm.Proximity(p.Overlay.Bytes(), item.Chunk.Address().Bytes()) < p.Depth {
+			return fmt.Errorf("%w: item %d of chunk out of the reserve", ErrInvalidProof, i)
+		}
+		mac := hmac.New(swarm.NewHasher, p.Anchor)
+		_, _ = mac.Write(item.Chunk.Data())
+		if !bytes.Equal(mac.Sum(nil), item.TransformedAddress.Bytes()) {
+			return fmt.Errorf("%w: item %d not transformed from the chunk", ErrInvalidProof, i)
+		}
+	}
+	return nil
+}
+
+// le reports whether a is lexicographically less than b.
+func le(a, b []byte) bool {
+	return bytes.Compare(a, b) == -1
+}
@@ -0,0 +1,155 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storageincentives_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/bmtpool"
+	"github.com/ethersphere/bee/pkg/storage"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/storageincentives"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// makeSample makes the sample of the chunks the way the sampler does.
+func makeSample(t *testing.T, anchor []byte, chunks []swarm.Chunk) storage.Sample {
+	t.Helper()
+
+	type entry struct {
+		taddr swarm.Address
+		chunk swarm.Chunk
+	}
+	entries := make([]entry, len(chunks))
+	for i, ch := range chunks {
+		mac := hmac.New(swarm.NewHasher, anchor)
+		_, _ = mac.Write(ch.Data())
+		entries[i] = entry{swarm.NewAddress(mac.Sum(nil)), ch}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].taddr.Bytes(), entries[j].taddr.Bytes()) < 0
+	})
+	entries = entries[:8]
+
+	hasher := bmtpool.Get()
+	defer bmtpool.Put(hasher)
+
+	var sample storage.Sample
+	for _, e := range entries {
+		if _, err := hasher.Write(e.taddr.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		sample.Items = append(sample.Items, e.taddr)
+		sample.Chunks = append(sample.Chunks, e.chunk)
+	}
+	sample.Hash = swarm.NewAddress(hasher.Sum(nil))
+	return sample
+}
+
+func TestReserveProof(t *testing.T) {
+	t.Parallel()
+
+	var (
+		overlay = swarm.RandAddress(t)
+		anchor  = []byte("anchor")
+		chunks  = make([]swarm.Chunk, 20)
+	)
+	for i := range chunks {
+		chunks[i] = testingc.GenerateTestRandomChunk()
+	}
+	sample := makeSample(t, anchor, chunks)
+
+	newProof := func(t *testing.T) storageincentives.ReserveProof {
+		t.Helper()
+
+		p, err := storageincentives.NewReserveProof(overlay, anchor, 0, sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		p := newProof(t)
+		if len(p.Items) != 8 || !p.SampleHash.Equal(sample.Hash) {
+			t.Fatalf("got proof of %d items of hash %s", len(p.Items), p.SampleHash)
+		}
+		if err := storageincentives.VerifyReserveProof(p); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("sample hash mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		s := sample
+		s.Hash = swarm.RandAddress(t)
+		if _, err := storageincentives.NewReserveProof(overlay, anchor, 0, s); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	for _, tc := range []struct {
+		name   string
+		tamper func(p *storageincentives.ReserveProof)
+	}{
+		{
+			name:   "other sample hash",
+			tamper: func(p *storageincentives.ReserveProof) { p.SampleHash = swarm.RandAddress(t) },
+		},
+		{
+			name:   "other anchor",
+			tamper: func(p *storageincentives.ReserveProof) { p.Anchor = []byte("other") },
+		},
+		{
+			name: "other chunk",
+			tamper: func(p *storageincentives.ReserveProof) {
+				p.Items[3].Chunk = testingc.GenerateTestRandomChunk()
+			},
+		},
+		{
+			name: "invalid chunk",
+			tamper: func(p *storageincentives.ReserveProof) {
+				ch := p.Items[3].Chunk
+				p.Items[3].Chunk = swarm.NewChunk(swarm.RandAddress(t), ch.Data())
+			},
+		},
+		{
+			name: "swapped items",
+			tamper: func(p *storageincentives.ReserveProof) {
+				p.Items[2], p.Items[3] = p.Items[3], p.Items[2]
+			},
+		},
+		{
+			name: "out of the reserve",
+			tamper: func(p *storageincentives.ReserveProof) {
+				p.Depth = swarm.MaxPO
+			},
+		},
+		{
+			name: "missing sister",
+			tamper: func(p *storageincentives.ReserveProof) {
+				p.Items[5].Inclusion.Sisters = p.Items[5].Inclusion.Sisters[1:]
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := newProof(t)
+			tc.tamper(&p)
+			if err := storageincentives.VerifyReserveProof(p); !errors.Is(err, storageincentives.ErrInvalidProof) {
+				t.Fatalf("got error %v, want %v", err, storageincentives.ErrInvalidProof)
+			}
+		})
+	}
+}
@@ -6,13 +6,17 @@ package storageincentives
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/profitability"
 	"github.com/ethersphere/bee/pkg/settlement/swap/erc20"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/transaction"
@@ -23,8 +27,17 @@ const loggerNameNode = "nodestatus"
 const (
 	redistributionStatusKey = "redistribution_state"
 	saveStatusInterval      = time.Second
+
+	redistributionRoundKeyPrefix = "redistribution_round_"
+	// maxRounds is the number of the latest rounds whose data is kept.
+	maxRounds = 1000
 )
 
+func roundKey(round uint64) string {
+	// zero padded so that the rounds are iterated in the order
+	return fmt.Sprintf("%s%020d", redistributionRoundKeyPrefix, round)
+}
+
 type RedistributionState struct {
 	mtx sync.Mutex
 
@@ -35,6 +48,7 @@ type RedistributionState struct {
 	status         *Status
 	currentBalance *big.Int
 	txService      transaction.Service
+	profitability  *profitability.Ledger
 }
 
 // Status provide internal status of the nodes in the redistribution game
@@ -51,6 +65,28 @@ type Status struct {
 	Fees            *big.Int
 }
 
+// PhaseOutcome is the outcome of a phase of the round.
+type PhaseOutcome struct {
+	Done  bool
+	Error string
+}
+
+// RoundData is the record of the participation of the node in a round.
+// The neighbourhood is selected and the sample is made in the claim
+// phase of the previous round, which is recorded under this round,
+// as the sample is committed, revealed and claimed in this round.
+type RoundData struct {
+	Round      uint64
+	Selected   bool
+	SampleHash []byte
+	Sample     PhaseOutcome
+	Commit     PhaseOutcome
+	Reveal     PhaseOutcome
+	Claim      PhaseOutcome
+	Winner     bool
+	Reward     *big.Int
+}
+
 func NewRedistributionState(logger log.Logger, ethAddress common.Address, stateStore storage.StateStorer, erc20Service erc20.Service, contract transaction.Service) (*RedistributionState, error) {
 	s := &RedistributionState{
 		ethAddress:     ethAddress,
@@ -105,6 +141,9 @@ func (r *RedistributionState) SetLastWonRound(round uint64) {
 	defer r.mtx.Unlock()
 	r.status.LastWonRound = round
 	r.save()
+	r.updateRound(round, func(d *RoundData) {
+		d.Winner = true
+	})
 }
 
 func (r *RedistributionState) IsFullySynced(isSynced bool) {
@@ -146,8 +185,18 @@ func (r *RedistributionState) CalculateWinnerReward(ctx context.Context) error {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
-	r.status.Reward.Add(r.status.Reward, currentBalance.Sub(currentBalance, r.currentBalance))
+	reward := currentBalance.Sub(currentBalance, r.currentBalance)
+	r.status.Reward.Add(r.status.Reward, reward)
 	r.save()
+	r.updateRound(r.status.Round, func(d *RoundData) {
+		d.Reward.Add(d.Reward, reward)
+	})
+
+	if r.profitability != nil {
+		if err := r.profitability.AddIncome(profitability.IncomeRewards, reward); err != nil {
+			r.logger.Error(err, "record winner reward failed")
+		}
+	}
 
 	return nil
 }
@@ -161,6 +210,92 @@ func (r *RedistributionState) Status() (*Status, error) {
 	return status, nil
 }
 
+// SetRoundSelected records that the neighbourhood of the node was selected to play in the round.
+func (r *RedistributionState) SetRoundSelected(round uint64) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.updateRound(round, func(d *RoundData) {
+		d.Selected = true
+	})
+}
+
+// SetRoundSample records the hash of the reserve sample played in the round.
+func (r *RedistributionState) SetRoundSample(round uint64, sampleHash []byte) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.updateRound(round, func(d *RoundData) {
+		d.SampleHash = sampleHash
+	})
+}
+
+// SetRoundPhase records the outcome of the phase of the round.
+func (r *RedistributionState) SetRoundPhase(round uint64, phase PhaseType, err error) {
+	o := PhaseOutcome{Done: err == nil}
+	if err != nil {
+		o.Error = err.Error()
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.updateRound(round, func(d *RoundData) {
+		switch phase {
+		case sample:
+			d.Sample = o
+		case commit:
+			d.Commit = o
+		case reveal:
+			d.Reveal = o
+		case claim:
+			d.Claim = o
+		}
+	})
+}
+
+// Rounds returns the data of at most the limit latest rounds
+// the node took part in, starting with the latest one.
+func (r *RedistributionState) Rounds(limit int) ([]RoundData, error) {
+	var rounds []RoundData
+	if err := r.stateStore.Iterate(redistributionRoundKeyPrefix, func(_, value []byte) (bool, error) {
+		var d RoundData
+		if err := json.Unmarshal(value, &d); err != nil {
+			return true, fmt.Errorf("invalid round data: %w", err)
+		}
+		rounds = append(rounds, d)
+		return false, nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i].Round > rounds[j].Round
+	})
+	if len(rounds) > limit {
+		rounds = rounds[:limit]
+	}
+	return rounds, nil
+}
+
+// updateRound applies the update to the data of the round and
+// saves it. It must be called with the mutex held.
+func (r *RedistributionState) updateRound(round uint64, update func(*RoundData)) {
+	d := RoundData{Round: round, Reward: big.NewInt(0)}
+	switch err := r.stateStore.Get(roundKey(round), &d); {
+	case errors.Is(err, storage.ErrNotFound):
+		// only the latest rounds are kept
+		if round >= maxRounds {
+			if err := r.stateStore.Delete(roundKey(round - maxRounds)); err != nil {
+				r.logger.Error(err, "deleting redistribution round", "round", round-maxRounds)
+			}
+		}
+	case err != nil:
+		r.logger.Error(err, "getting redistribution round", "round", round)
+		return
+	}
+	update(&d)
+	if err := r.stateStore.Put(roundKey(round), d); err != nil {
+		r.logger.Error(err, "saving redistribution round", "round", round)
+	}
+}
+
 func (r *RedistributionState) SetBalance(ctx context.Context) error {
 	// get current balance
 	currentBalance, err := r.erc20Service.BalanceOf(ctx, r.ethAddress)
@@ -6,6 +6,7 @@ package storageincentives
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -183,3 +184,70 @@ func TestFee(t *testing.T) {
 		t.Fatalf("expected fee %d got %d", expectedResult, gotSecondResult.Fees)
 	}
 }
+
+func TestRounds(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	balance := big.NewInt(1000)
+	state := createRedistribution(t, []erc20mock.Option{
+		erc20mock.WithBalanceOfFunc(func(ctx context.Context, address common.Address) (*big.Int, error) {
+			return new(big.Int).Set(balance), nil
+		}),
+	}, nil)
+
+	state.SetRoundSelected(1)
+	state.SetRoundPhase(1, sample, errors.New("sample failed"))
+
+	state.SetRoundSelected(2)
+	state.SetRoundPhase(2, sample, nil)
+	state.SetRoundSample(2, []byte{1, 2, 3})
+	state.SetCurrentEvent(claim, 2, 300)
+	state.SetRoundPhase(2, commit, nil)
+	state.SetRoundPhase(2, reveal, nil)
+	if err := state.SetBalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	state.SetLastWonRound(2)
+	balance.SetInt64(1500)
+	if err := state.CalculateWinnerReward(ctx); err != nil {
+		t.Fatal(err)
+	}
+	state.SetRoundPhase(2, claim, nil)
+
+	want := []RoundData{
+		{
+			Round:      2,
+			Selected:   true,
+			SampleHash: []byte{1, 2, 3},
+			Sample:     PhaseOutcome{Done: true},
+			Commit:     PhaseOutcome{Done: true},
+			Reveal:     PhaseOutcome{Done: true},
+			Claim:      PhaseOutcome{Done: true},
+			Winner:     true,
+			Reward:     big.NewInt(500),
+		},
+		{
+			Round:    1,
+			Selected: true,
+			Sample:   PhaseOutcome{Error: "sample failed"},
+			Reward:   big.NewInt(0),
+		},
+	}
+
+	got, err := state.Rounds(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(big.Int{})); diff != "" {
+		t.Errorf("result mismatch (-want +have):\n%s", diff)
+	}
+
+	got, err = state.Rounds(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want[:1], got, cmp.AllowUnexported(big.Int{})); diff != "" {
+		t.Errorf("result mismatch (-want +have):\n%s", diff)
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/syndtr/goleveldb/leveldb"
+	ldbstorage "github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Progress is the progress of the tag, by which the tags are indexed.
+type Progress string
+
+const (
+	ProgressIncomplete Progress = "incomplete"
+	ProgressComplete   Progress = "complete"
+	ProgressFailed     Progress = "failed"
+)
+
+// progressIDs are the key bytes of the progress values.
+var progressIDs = map[Progress]byte{
+	ProgressIncomplete: 0,
+	ProgressComplete:   1,
+	ProgressFailed:     2,
+}
+
+// Sort is the order of the queried tags.
+type Sort string
+
+const (
+	SortUid Sort = "uid" // by the ascending uid
+	SortAge Sort = "age" // from the oldest to the newest
+)
+
+// Query selects the tags by their progress, age and completeness.
+type Query struct {
+	Progress        Progress      // progress of the tags, any if empty
+	MinAge          time.Duration // minimal age of the tags
+	MaxAge          time.Duration // maximal age of the tags, if positive
+	MinCompleteness float64       // minimal completeness of the tags
+	MaxCompleteness float64       // maximal completeness of the tags, if positive
+	Sort            Sort          // order of the tags, SortUid if empty
+	Offset          int
+	Limit           int
+}
+
+// Match reports whether the tag is selected by the query at the time.
+func (q Query) Match(t *Tag, now time.Time) bool {
+	if q.Progress != "" && progressOf(t) != q.Progress {
+		return false
+	}
+	if !q.matchStartedAt(t.StartedAt, now) {
+		return false
+	}
+	c := t.Completeness()
+	return c >= q.MinCompleteness && (q.MaxCompleteness <= 0 || c <= q.MaxCompleteness)
+}
+
+// SortTags sorts the tags in the order of the query.
+func (q Query) SortTags(tags []*Tag) {
+	sort.Slice(tags, func(i, j int) bool { return q.less(tags[i], tags[j]) })
+}
+
+func (q Query) less(a, b *Tag) bool {
+	if q.Sort == SortAge && !a.StartedAt.Equal(b.StartedAt) {
+		return a.StartedAt.Before(b.StartedAt)
+	}
+	return a.Uid < b.Uid
+}
+
+// startedAtRange returns the range of the start times of the tags of the
+// query at the time in the unix seconds, the upper bound is inclusive.
+func (q Query) startedAtRange(now time.Time) (from, until uint64) {
+	until = startedAtKey(now.Add(-q.MinAge))
+	if q.MaxAge > 0 {
+		from = startedAtKey(now.Add(-q.MaxAge))
+	}
+	return from, until
+}
+
+func (q Query) matchStartedAt(startedAt, now time.Time) bool {
+	from, until := q.startedAtRange(now)
+	s := startedAtKey(startedAt)
+	return s >= from && s <= until
+}
+
+// progressOf returns the progress of the tag.
+func progressOf(t *Tag) Progress {
+	switch {
+	case t.Failed():
+		return ProgressFailed
+	case t.Done(StateSynced):
+		return ProgressComplete
+	default:
+		return ProgressIncomplete
+	}
+}
+
+const (
+	tagPrefix      = 't' // uid -> tag
+	agePrefix      = 'a' // startedAt|uid -> nil
+	progressPrefix = 'p' // progress|startedAt|uid -> nil
+)
+
+// Store is the store of the persisted tags, separate from the state store.
+// Besides the tags by their uid, it indexes them by their start time, and
+// by their progress and start time, so that the tags can be queried without
+// reading all of them. A nil Store does not persist the tags.
+type Store struct {
+	db *leveldb.DB
+	mu sync.Mutex // serialises the updates of the tags with their index entries
+}
+
+// NewStore opens the store of the tags at the path, or in the memory if the
+// path is empty.
+func NewStore(path string) (*Store, error) {
+	var (
+		db  *leveldb.DB
+		err error
+	)
+	if path == "" {
+		db, err = leveldb.Open(ldbstorage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(path, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open tags store: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the store.
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Migrate moves the tags persisted in the state store before the
+// Store was introduced to the store and returns their number.
+func (s *Store) Migrate(stateStore storage.StateStorer) (int, error) {
+	var (
+		keys []string
+		tags []*Tag
+	)
+	err := stateStore.Iterate(tagKeyPrefix, func(key, value []byte) (bool, error) {
+		if !strings.HasPrefix(string(key), tagKeyPrefix) {
+			return true, nil
+		}
+		t, err := decodeTagValueFromStore(value)
+		if err != nil {
+			return true, fmt.Errorf("decode tag %s: %w", key, err)
+		}
+		keys = append(keys, string(key))
+		tags = append(tags, t)
+		return false, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i, t := range tags {
+		if err := s.put(t); err != nil {
+			return i, err
+		}
+		if err := stateStore.Delete(keys[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(tags), nil
+}
+
+// put stores the tag and moves its index entries.
+func (s *Store) put(t *Tag) error {
+	if s == nil {
+		return nil
+	}
+	value, err := t.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch := new(leveldb.Batch)
+	if prev, err := s.get(t.Uid); err == nil {
+		batch.Delete(ageKey(prev))
+		batch.Delete(progressKey(progressOf(prev), prev))
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	batch.Put(tagKey(t.Uid), value)
+	batch.Put(ageKey(t), nil)
+	batch.Put(progressKey(progressOf(t), t), nil)
+	return s.db.Write(batch, nil)
+}
+
+// get returns the stored tag of the uid.
+func (s *Store) get(uid uint32) (*Tag, error) {
+	if s == nil {
+		return nil, ErrNotFound
+	}
+	value, err := s.db.Get(tagKey(uid), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	t := new(Tag)
+	if err := t.UnmarshalBinary(value); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// delete removes the tag of the uid with its index entries.
+func (s *Store) delete(uid uint32) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.get(uid)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	batch := new(leveldb.Batch)
+	batch.Delete(tagKey(uid))
+	batch.Delete(ageKey(t))
+	batch.Delete(progressKey(progressOf(t), t))
+	return s.db.Write(batch, nil)
+}
+
+// query returns the first n stored tags of the query, in the order of the
+// query and ignoring its offset and limit, skipping the tags of the skip
+// function. The tags are read by the index which matches the query best.
+func (s *Store) query(q Query, now time.Time, n int, skip func(uid uint32) bool) ([]*Tag, error) {
+	if s == nil || n <= 0 {
+		return nil, nil
+	}
+
+	var (
+		prefix   []byte
+		ordered  = q.Sort == SortAge // the index is in the order of the query
+		from, to = q.startedAtRange(now)
+	)
+	switch {
+	case q.Progress != "":
+		prefix = []byte{progressPrefix, progressIDs[q.Progress]}
+	case q.Sort == SortAge:
+		prefix = []byte{agePrefix}
+	default:
+		// the tags are in the order of the uid
+		return s.scanTags(q, now, n, skip)
+	}
+
+	start := append(append([]byte(nil), prefix...), encodeUint64(from)...)
+	limit := append(append([]byte(nil), prefix...), encodeUint64(to+1)...)
+	it := s.db.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
+	defer it.Release()
+
+	var tags []*Tag
+	for it.Next() {
+		uid := binary.BigEndian.Uint32(it.Key()[len(it.Key())-4:])
+		if skip(uid) {
+			continue
+		}
+		t, err := s.get(uid)
+		if err != nil {
+			return nil, fmt.Errorf("get tag %d: %w", uid, err)
+		}
+		if !q.Match(t, now) {
+			continue
+		}
+		tags = append(tags, t)
+		if ordered && len(tags) == n {
+			break
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	if !ordered {
+		q.SortTags(tags)
+		if len(tags) > n {
+			tags = tags[:n]
+		}
+	}
+	return tags, nil
+}
+
+// scanTags returns the first n stored tags of the query in the order of the uid.
+func (s *Store) scanTags(q Query, now time.Time, n int, skip func(uid uint32) bool) ([]*Tag, error) {
+	it := s.db.NewIterator(util.BytesPrefix([]byte{tagPrefix}), nil)
+	defer it.Release()
+
+	var tags []*Tag
+	for it.Next() && len(tags) < n {
+		t := new(Tag)
+		if err := t.UnmarshalBinary(it.Value()); err != nil {
+			return nil, fmt.Errorf("decode tag: %w", err)
+		}
+		if skip(t.Uid) || !q.Match(t, now) {
+			continue
+		}
+		tags = append(tags, t)
+	}
+	return tags, it.Error()
+}
+
+func tagKey(uid uint32) []byte {
+	key := make([]byte, 5)
+	key[0] = tagPrefix
+	binary.BigEndian.PutUint32(key[1:], uid)
+	return key
+}
+
+func ageKey(t *Tag) []byte {
+	return indexKey([]byte{agePrefix}, t)
+}
+
+func progressKey(p Progress, t *Tag) []byte {
+	return indexKey([]byte{progressPrefix, progressIDs[p]}, t)
+}
+
+// indexKey returns the key of the index of the prefix ordered
+// by the start time of the tag, and then by its uid.
+func indexKey(prefix []byte, t *Tag) []byte {
+	var key bytes.Buffer
+	key.Write(prefix)
+	key.Write(encodeUint64(startedAtKey(t.StartedAt)))
+	_ = binary.Write(&key, binary.BigEndian, t.Uid)
+	return key.Bytes()
+}
+
+// startedAtKey returns the start time in the unix seconds,
+// in which the tags are persisted, zero before the epoch.
+func startedAtKey(t time.Time) uint64 {
+	if s := t.Unix(); s > 0 {
+		return uint64(s)
+	}
+	return 0
+}
+
+func encodeUint64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
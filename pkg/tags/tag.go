@@ -21,21 +21,28 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/log"
-	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/opentracing/opentracing-go"
+	"golang.org/x/time/rate"
 )
 
 var (
 	errExists = errors.New("already exists")
 	errNA     = errors.New("not available yet")
 	errNoETA  = errors.New("unable to calculate ETA")
+
+	// ErrInvalidBandwidthLimit is returned when the bandwidth limit is negative.
+	ErrInvalidBandwidthLimit = errors.New("invalid bandwidth limit")
+
+	// ErrFailed is returned when waiting for a tag whose upload failed.
+	ErrFailed = errors.New("tag failed")
 )
 
 // State is the enum type for chunk states
@@ -50,6 +57,34 @@ const (
 	StateSynced              // proof is received; chunk removed from sync db; chunk is available everywhere
 )
 
+// Phase is the enum type for the upload phases which can fail
+type Phase = uint8
+
+const (
+	PhaseSplit Phase = iota + 1 // content split into chunks
+	PhaseStamp                  // chunks stamped with the postage batch
+	PhasePush                   // chunks push synced to the neighbourhood
+)
+
+// PhaseName returns the name of the upload phase.
+func PhaseName(p Phase) string {
+	switch p {
+	case PhaseSplit:
+		return "split"
+	case PhaseStamp:
+		return "stamp"
+	case PhasePush:
+		return "push"
+	}
+	return "unknown"
+}
+
+// PhaseError is the error which failed an upload phase.
+type PhaseError struct {
+	Phase   Phase
+	Message string
+}
+
 // Tag represents info on the status of new chunks
 type Tag struct {
 	Total  int64 // total chunks belonging to a tag
@@ -64,21 +99,180 @@ type Tag struct {
 	StartedAt time.Time     // tag started to calculate ETA
 
 	// end-to-end tag tracing
-	ctx        context.Context     // tracing context
-	span       opentracing.Span    // tracing root span
-	spanOnce   sync.Once           // make sure we close root span only once
-	stateStore storage.StateStorer // to persist the tag
-	logger     log.Logger          // logger instance for logging
+	ctx      context.Context  // tracing context
+	span     opentracing.Span // tracing root span
+	spanOnce sync.Once        // make sure we close root span only once
+	store    *Store           // to persist the tag
+	logger   log.Logger       // logger instance for logging
+
+	traceMu sync.Mutex // guards trace
+	trace   *Trace     // forwarding paths of traced receipts, not persisted
+
+	limitMu        sync.Mutex    // guards bandwidthLimit and limiter
+	bandwidthLimit int64         // push sync bandwidth cap in bytes per second, zero means unlimited
+	limiter        *rate.Limiter // enforces the bandwidth cap, created on the first reservation
+
+	errMu       sync.Mutex   // guards phaseErrors
+	phaseErrors []PhaseError // first error of each failed phase, the tag is failed if any
+}
+
+// Trace summarises the forwarding paths reported
+// in the traced push sync receipts of the tag chunks.
+type Trace struct {
+	Receipts       int64            // number of traced receipts
+	MaxHops        int              // longest forwarding path
+	TotalHops      int64            // sum of the lengths of all forwarding paths
+	Neighbourhoods map[string]int64 // number of chunks stored per neighbourhood prefix
+}
+
+// AddTrace records the forwarding path length of a chunk
+// and the neighbourhood prefix of the node which stored it.
+func (t *Tag) AddTrace(hops int, neighbourhood string) {
+	t.traceMu.Lock()
+	defer t.traceMu.Unlock()
+
+	if t.trace == nil {
+		t.trace = &Trace{Neighbourhoods: make(map[string]int64)}
+	}
+	t.trace.Receipts++
+	t.trace.TotalHops += int64(hops)
+	if hops > t.trace.MaxHops {
+		t.trace.MaxHops = hops
+	}
+	t.trace.Neighbourhoods[neighbourhood]++
+}
+
+// Trace returns a copy of the trace summary
+// or false if no traced receipts were recorded.
+func (t *Tag) Trace() (Trace, bool) {
+	t.traceMu.Lock()
+	defer t.traceMu.Unlock()
+
+	if t.trace == nil {
+		return Trace{}, false
+	}
+	tr := *t.trace
+	tr.Neighbourhoods = make(map[string]int64, len(t.trace.Neighbourhoods))
+	for k, v := range t.trace.Neighbourhoods {
+		tr.Neighbourhoods[k] = v
+	}
+	return tr, true
+}
+
+// SetBandwidthLimit caps the bandwidth used to push sync the chunks
+// of the tag to the given number of bytes per second. Zero removes
+// the cap. The limit is persisted with the tag.
+func (t *Tag) SetBandwidthLimit(bytesPerSecond int64) error {
+	if bytesPerSecond < 0 {
+		return ErrInvalidBandwidthLimit
+	}
+
+	t.limitMu.Lock()
+	t.bandwidthLimit = bytesPerSecond
+	if t.limiter != nil {
+		t.limiter.SetLimit(bandwidthRate(bytesPerSecond))
+		t.limiter.SetBurst(bandwidthBurst(bytesPerSecond))
+	}
+	t.limitMu.Unlock()
+
+	return t.saveTag()
+}
+
+// BandwidthLimit returns the push sync bandwidth cap
+// of the tag in bytes per second, or zero if there is none.
+func (t *Tag) BandwidthLimit() int64 {
+	t.limitMu.Lock()
+	defer t.limitMu.Unlock()
+	return t.bandwidthLimit
+}
+
+// ReserveBandwidth reserves the bandwidth to push sync n bytes of
+// the tag chunks and returns the duration the caller has to wait
+// before pushing them so that the bandwidth cap is respected.
+func (t *Tag) ReserveBandwidth(n int) time.Duration {
+	t.limitMu.Lock()
+	defer t.limitMu.Unlock()
+
+	if t.bandwidthLimit == 0 {
+		return 0
+	}
+	if t.limiter == nil {
+		t.limiter = rate.NewLimiter(bandwidthRate(t.bandwidthLimit), bandwidthBurst(t.bandwidthLimit))
+	}
+	if burst := t.limiter.Burst(); n > burst {
+		n = burst
+	}
+	return t.limiter.ReserveN(time.Now(), n).Delay()
+}
+
+// bandwidthRate returns the rate of the limiter
+// for the bandwidth limit in bytes per second.
+func bandwidthRate(bytesPerSecond int64) rate.Limit {
+	if bytesPerSecond == 0 {
+		return rate.Inf
+	}
+	return rate.Limit(bytesPerSecond)
+}
+
+// bandwidthBurst returns the burst of the limiter for the bandwidth
+// limit in bytes per second, which fits at least one whole chunk.
+func bandwidthBurst(bytesPerSecond int64) int {
+	if bytesPerSecond < swarm.ChunkWithSpanSize {
+		return swarm.ChunkWithSpanSize
+	}
+	return int(bytesPerSecond)
+}
+
+// Fail records the error of the upload phase and puts the tag into the
+// terminal failed state. Only the first error of each phase is kept.
+// The failure is persisted with the tag.
+func (t *Tag) Fail(phase Phase, err error) error {
+	t.errMu.Lock()
+	for _, e := range t.phaseErrors {
+		if e.Phase == phase {
+			t.errMu.Unlock()
+			return nil
+		}
+	}
+	t.phaseErrors = append(t.phaseErrors, PhaseError{Phase: phase, Message: err.Error()})
+	t.errMu.Unlock()
+
+	return t.saveTag()
+}
+
+// Failed reports whether an upload phase of the tag failed.
+func (t *Tag) Failed() bool {
+	t.errMu.Lock()
+	defer t.errMu.Unlock()
+	return len(t.phaseErrors) > 0
+}
+
+// Errors returns the errors of the failed upload phases in the order they failed.
+func (t *Tag) Errors() []PhaseError {
+	t.errMu.Lock()
+	defer t.errMu.Unlock()
+	return append([]PhaseError(nil), t.phaseErrors...)
+}
+
+// failedError returns the error wrapping ErrFailed
+// with the errors of the failed upload phases.
+func (t *Tag) failedError() error {
+	errs := t.Errors()
+	msg := make([]string, len(errs))
+	for i, e := range errs {
+		msg[i] = fmt.Sprintf("%s: %s", PhaseName(e.Phase), e.Message)
+	}
+	return fmt.Errorf("%w: %s", ErrFailed, strings.Join(msg, "; "))
 }
 
 // NewTag creates a new tag, and returns it
-func NewTag(ctx context.Context, uid uint32, total int64, tracer *tracing.Tracer, stateStore storage.StateStorer, logger log.Logger) *Tag {
+func NewTag(ctx context.Context, uid uint32, total int64, tracer *tracing.Tracer, store *Store, logger log.Logger) *Tag {
 	t := &Tag{
-		Uid:        uid,
-		StartedAt:  time.Now(),
-		Total:      total,
-		stateStore: stateStore,
-		logger:     logger,
+		Uid:       uid,
+		StartedAt: time.Now(),
+		Total:     total,
+		store:     store,
+		logger:    logger,
 	}
 
 	// context here is used only to store the root span `new.upload.tag` within Tag,
@@ -163,8 +357,11 @@ func (t *Tag) TotalCounter() int64 {
 
 // WaitTillDone returns without error once the tag is complete
 // wrt the state given as argument
-// it returns an error if the context is done
+// it returns an error if the context is done or the tag failed
 func (t *Tag) WaitTillDone(ctx context.Context, s State) error {
+	if t.Failed() {
+		return t.failedError()
+	}
 	if t.Done(s) {
 		return nil
 	}
@@ -174,6 +371,9 @@ func (t *Tag) WaitTillDone(ctx context.Context, s State) error {
 	for {
 		select {
 		case <-ticker.C:
+			if t.Failed() {
+				return t.failedError()
+			}
 			if t.Done(s) {
 				return nil
 			}
@@ -189,6 +389,20 @@ func (t *Tag) Done(s State) bool {
 	return err == nil && n == total
 }
 
+// Completeness returns the fraction of the chunks of the tag
+// which are synced, zero if the total count is not known yet.
+func (t *Tag) Completeness() float64 {
+	total := atomic.LoadInt64(&t.Total)
+	if total <= 0 {
+		return 0
+	}
+	c := float64(atomic.LoadInt64(&t.Seen)+atomic.LoadInt64(&t.Synced)) / float64(total)
+	if c > 1 {
+		return 1
+	}
+	return c
+}
+
 // DoneSplit sets total count to SPLIT count and sets the associated swarm hash for this tag
 // is meant to be called when splitter finishes for input streams of unknown size
 func (t *Tag) DoneSplit(address swarm.Address) (int64, error) {
@@ -260,6 +474,16 @@ func (tag *Tag) MarshalBinary() (data []byte, err error) {
 	buffer = append(buffer, intBuffer[:n]...)
 	buffer = append(buffer, tag.Address.Bytes()...)
 
+	encodeInt64Append(&buffer, tag.BandwidthLimit())
+
+	errs := tag.Errors()
+	encodeInt64Append(&buffer, int64(len(errs)))
+	for _, e := range errs {
+		buffer = append(buffer, e.Phase)
+		encodeInt64Append(&buffer, int64(len(e.Message)))
+		buffer = append(buffer, e.Message...)
+	}
+
 	return buffer, nil
 }
 
@@ -286,6 +510,38 @@ func (tag *Tag) UnmarshalBinary(buffer []byte) error {
 	buffer = buffer[n:]
 	if t > 0 {
 		tag.Address = swarm.NewAddress(buffer[:t])
+		buffer = buffer[t:]
+	}
+
+	// the tags persisted before the bandwidth limit
+	// was introduced end with the address
+	if len(buffer) > 0 {
+		tag.limitMu.Lock()
+		tag.bandwidthLimit = decodeInt64Splice(&buffer)
+		tag.limitMu.Unlock()
+	}
+
+	// the tags persisted before the phase errors
+	// were introduced end with the bandwidth limit
+	if len(buffer) > 0 {
+		n := decodeInt64Splice(&buffer)
+		errs := make([]PhaseError, 0, n)
+		for i := int64(0); i < n; i++ {
+			if len(buffer) < 1 {
+				return errors.New("buffer too short")
+			}
+			phase := buffer[0]
+			buffer = buffer[1:]
+			l := decodeInt64Splice(&buffer)
+			if l < 0 || int64(len(buffer)) < l {
+				return errors.New("buffer too short")
+			}
+			errs = append(errs, PhaseError{Phase: phase, Message: string(buffer[:l])})
+			buffer = buffer[l:]
+		}
+		tag.errMu.Lock()
+		tag.phaseErrors = errs
+		tag.errMu.Unlock()
 	}
 
 	return nil
@@ -303,23 +559,7 @@ func decodeInt64Splice(buffer *[]byte) int64 {
 	return val
 }
 
-// saveTag update the tag in the state store
+// saveTag updates the tag in the store
 func (tag *Tag) saveTag() error {
-	key := getKey(tag.Uid)
-	value, err := tag.MarshalBinary()
-	if err != nil {
-		return err
-	}
-
-	if tag.stateStore != nil {
-		err = tag.stateStore.Put(key, value)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func getKey(uid uint32) string {
-	return fmt.Sprintf("tags_%d", uid)
+	return tag.store.put(tag)
 }
@@ -18,12 +18,13 @@ package tags
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/log"
-	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
@@ -35,9 +36,9 @@ var (
 func TestTagSingleIncrements(t *testing.T) {
 	t.Parallel()
 
-	mockStatestore := statestore.NewStateStore()
+	store := newStore(t)
 	logger := log.Noop
-	tg := &Tag{Total: 10, stateStore: mockStatestore, logger: logger}
+	tg := &Tag{Total: 10, store: store, logger: logger}
 
 	tc := []struct {
 		state    uint32
@@ -142,13 +143,142 @@ func TestTagETA(t *testing.T) {
 	}
 }
 
+func TestTagTrace(t *testing.T) {
+	t.Parallel()
+
+	tg := &Tag{}
+	if _, ok := tg.Trace(); ok {
+		t.Fatal("expected no trace")
+	}
+
+	tg.AddTrace(2, "01")
+	tg.AddTrace(4, "01")
+	tg.AddTrace(1, "10")
+
+	trace, ok := tg.Trace()
+	if !ok {
+		t.Fatal("expected trace")
+	}
+	if trace.Receipts != 3 || trace.MaxHops != 4 || trace.TotalHops != 7 {
+		t.Fatalf("unexpected trace %+v", trace)
+	}
+	if trace.Neighbourhoods["01"] != 2 || trace.Neighbourhoods["10"] != 1 {
+		t.Fatalf("unexpected neighbourhoods %v", trace.Neighbourhoods)
+	}
+
+	// the returned trace is a copy
+	trace.Neighbourhoods["01"] = 0
+	if again, _ := tg.Trace(); again.Neighbourhoods["01"] != 2 {
+		t.Fatal("trace modified through the copy")
+	}
+}
+
+func TestTagBandwidthLimit(t *testing.T) {
+	t.Parallel()
+
+	tg := NewTag(context.Background(), 1, 0, nil, newStore(t), log.Noop)
+	if d := tg.ReserveBandwidth(swarm.ChunkWithSpanSize); d != 0 {
+		t.Fatalf("got delay %s without the bandwidth limit", d)
+	}
+	if err := tg.SetBandwidthLimit(-1); !errors.Is(err, ErrInvalidBandwidthLimit) {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidBandwidthLimit)
+	}
+
+	// a single chunk per second
+	if err := tg.SetBandwidthLimit(swarm.ChunkWithSpanSize); err != nil {
+		t.Fatal(err)
+	}
+	if d := tg.ReserveBandwidth(swarm.ChunkWithSpanSize); d != 0 {
+		t.Fatalf("got delay %s for the first chunk", d)
+	}
+	if d := tg.ReserveBandwidth(swarm.ChunkWithSpanSize); d < 900*time.Millisecond || d > time.Second {
+		t.Fatalf("got delay %s for the second chunk, want about 1s", d)
+	}
+
+	// the limit survives the persistence of the tag
+	b, err := tg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshalledTag := &Tag{}
+	if err := unmarshalledTag.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := unmarshalledTag.BandwidthLimit(); got != swarm.ChunkWithSpanSize {
+		t.Fatalf("got bandwidth limit %d, want %d", got, swarm.ChunkWithSpanSize)
+	}
+
+	// lifting the limit applies to the reservations made before
+	if err := tg.SetBandwidthLimit(0); err != nil {
+		t.Fatal(err)
+	}
+	if d := tg.ReserveBandwidth(swarm.ChunkWithSpanSize); d != 0 {
+		t.Fatalf("got delay %s after the bandwidth limit was lifted", d)
+	}
+}
+
+func TestTagFail(t *testing.T) {
+	t.Parallel()
+
+	tg := NewTag(context.Background(), 1, 10, nil, newStore(t), log.Noop)
+	if tg.Failed() {
+		t.Fatal("new tag failed")
+	}
+
+	if err := tg.Fail(PhaseStamp, errors.New("bucket full")); err != nil {
+		t.Fatal(err)
+	}
+	// only the first error of a phase is kept
+	if err := tg.Fail(PhaseStamp, errors.New("other")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tg.Fail(PhasePush, errors.New("push timeout")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []PhaseError{
+		{Phase: PhaseStamp, Message: "bucket full"},
+		{Phase: PhasePush, Message: "push timeout"},
+	}
+	if !tg.Failed() {
+		t.Fatal("tag not failed")
+	}
+	if got := tg.Errors(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got errors %v, want %v", got, want)
+	}
+
+	// the failed tag is never done
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := tg.WaitTillDone(ctx, StateSynced)
+	if !errors.Is(err, ErrFailed) {
+		t.Fatalf("got error %v, want %v", err, ErrFailed)
+	}
+	if got, want := err.Error(), "tag failed: stamp: bucket full; push: push timeout"; got != want {
+		t.Fatalf("got error message %q, want %q", got, want)
+	}
+
+	// the errors survive the persistence of the tag
+	b, err := tg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshalledTag := &Tag{}
+	if err := unmarshalledTag.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := unmarshalledTag.Errors(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got unmarshalled errors %v, want %v", got, want)
+	}
+}
+
 // TestTagConcurrentIncrements tests Inc calls concurrently
 func TestTagConcurrentIncrements(t *testing.T) {
 	t.Parallel()
 
-	mockStatestore := statestore.NewStateStore()
+	store := newStore(t)
 	logger := log.Noop
-	tg := &Tag{stateStore: mockStatestore, logger: logger}
+	tg := &Tag{store: store, logger: logger}
 	n := 10
 	wg := sync.WaitGroup{}
 	wg.Add(5 * n)
@@ -178,9 +308,9 @@ func TestTagConcurrentIncrements(t *testing.T) {
 func TestTagsMultipleConcurrentIncrementsSyncMap(t *testing.T) {
 	t.Parallel()
 
-	mockStatestore := statestore.NewStateStore()
+	store := newStore(t)
 	logger := log.Noop
-	ts := NewTags(mockStatestore, logger)
+	ts := NewTags(store, logger)
 	n := 100
 	wg := sync.WaitGroup{}
 	wg.Add(10 * 5 * n)
@@ -231,9 +361,9 @@ func TestTagsMultipleConcurrentIncrementsSyncMap(t *testing.T) {
 func TestMarshallingWithAddr(t *testing.T) {
 	t.Parallel()
 
-	mockStatestore := statestore.NewStateStore()
+	store := newStore(t)
 	logger := log.Noop
-	tg := NewTag(context.Background(), 111, 10, nil, mockStatestore, logger)
+	tg := NewTag(context.Background(), 111, 10, nil, store, logger)
 	tg.Address = swarm.NewAddress([]byte{0, 1, 2, 3, 4, 5, 6})
 
 	for _, f := range allStates {
@@ -282,9 +412,9 @@ func TestMarshallingWithAddr(t *testing.T) {
 func TestMarshallingNoAddr(t *testing.T) {
 	t.Parallel()
 
-	mockStatestore := statestore.NewStateStore()
+	store := newStore(t)
 	logger := log.Noop
-	tg := NewTag(context.Background(), 111, 10, nil, mockStatestore, logger)
+	tg := NewTag(context.Background(), 111, 10, nil, store, logger)
 	for _, f := range allStates {
 		err := tg.Inc(f)
 		if err != nil {
@@ -23,13 +23,11 @@ import (
 	"encoding/json"
 	"errors"
 	"math/rand"
-	"sort"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/log"
-	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
@@ -37,7 +35,10 @@ import (
 const loggerName = "tags"
 
 const (
-	maxPage      = 1000 // hard limit of page size
+	maxPage = 1000 // hard limit of page size
+
+	// tagKeyPrefix is the prefix of the keys of the tags
+	// persisted in the state store before the Store.
 	tagKeyPrefix = "tags_"
 )
 
@@ -48,21 +49,22 @@ var (
 
 // Tags hold tag information indexed by a unique random uint32
 type Tags struct {
-	tags       *sync.Map
-	stateStore storage.StateStorer
-	logger     log.Logger
-	rand       *rand.Rand
-	randM      sync.Mutex
+	tags   *sync.Map
+	store  *Store
+	logger log.Logger
+	rand   *rand.Rand
+	randM  sync.Mutex
 }
 
-// NewTags creates a tags object
-func NewTags(stateStore storage.StateStorer, logger log.Logger) *Tags {
+// NewTags creates a tags object persisting the tags in the store,
+// the tags are not persisted if the store is nil.
+func NewTags(store *Store, logger log.Logger) *Tags {
 
 	return &Tags{
-		tags:       &sync.Map{},
-		stateStore: stateStore,
-		logger:     logger.WithName(loggerName).Register(),
-		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		tags:   &sync.Map{},
+		store:  store,
+		logger: logger.WithName(loggerName).Register(),
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -86,7 +88,7 @@ func (ts *Tags) Create(total int64) (*Tag, error) {
 		}
 	}
 
-	t := NewTag(context.Background(), uid, total, nil, ts.stateStore, ts.logger)
+	t := NewTag(context.Background(), uid, total, nil, ts.store, ts.logger)
 
 	if _, loaded := ts.tags.LoadOrStore(t.Uid, t); loaded {
 		return nil, errExists
@@ -113,12 +115,11 @@ func (ts *Tags) Get(uid uint32) (*Tag, error) {
 	if !ok {
 		// see if the tag is present in the store
 		// if yes, load it in to the memory
-		ta, err := ts.getTagFromStore(uid)
+		ta, err := ts.store.get(uid)
 		if err != nil {
 			return nil, ErrNotFound
 		}
-		ts.tags.LoadOrStore(ta.Uid, ta)
-		return ta, nil
+		t, _ = ts.tags.LoadOrStore(ta.Uid, ta)
 	}
 	return t.(*Tag), nil
 }
@@ -150,11 +151,9 @@ func (ts *Tags) Range(fn func(k, v interface{}) bool) {
 func (ts *Tags) Delete(k interface{}) {
 	ts.tags.Delete(k)
 
-	// k is a uint32, try to create the tag key and remove
-	// from statestore
+	// k is a uint32, remove the tag from the store
 	if uid, ok := k.(uint32); ok && uid != 0 {
-		key := tagKey(uid)
-		_ = ts.stateStore.Delete(key)
+		_ = ts.store.delete(uid)
 	}
 }
 
@@ -195,84 +194,57 @@ func (ts *Tags) UnmarshalJSON(value []byte) error {
 	return err
 }
 
-func (ts *Tags) ListAll(ctx context.Context, offset, limit int) (t []*Tag, err error) {
-	if limit > maxPage {
-		limit = maxPage
-	}
-
-	// range sync.Map first
-	allTags := ts.All()
-	sort.Slice(allTags, func(i, j int) bool { return allTags[i].Uid < allTags[j].Uid })
-	for _, tag := range allTags {
-		if offset > 0 {
-			offset--
-			continue
-		}
-
-		t = append(t, tag)
-
-		limit--
+// ListAll returns the page of the tags in the order of their uid.
+func (ts *Tags) ListAll(ctx context.Context, offset, limit int) ([]*Tag, error) {
+	return ts.Query(ctx, Query{Offset: offset, Limit: limit})
+}
 
-		if limit == 0 {
-			break
-		}
+// Query returns the page of the tags selected by the query, in the order
+// of the query. The tags in the memory take precedence over the stored ones,
+// whose persisted state may be behind.
+func (ts *Tags) Query(_ context.Context, q Query) ([]*Tag, error) {
+	if q.Limit <= 0 || q.Limit > maxPage {
+		q.Limit = maxPage
 	}
-
-	if limit == 0 {
-		return
+	if q.Offset < 0 {
+		q.Offset = 0
 	}
+	now := time.Now()
 
-	// and then from statestore
-	err = ts.stateStore.Iterate(tagKeyPrefix, func(key, value []byte) (stop bool, err error) {
-		if offset > 0 {
-			offset--
-			return false, nil
-		}
-
-		var ta *Tag
-		ta, err = decodeTagValueFromStore(value)
-		if err != nil {
-			return true, err
-		}
-
-		if _, ok := ts.tags.Load(ta.Uid); ok {
-			// tag was already returned from sync.Map
-			return false, nil
-		}
-
-		t = append(t, ta)
-
-		limit--
-
-		if limit == 0 {
-			return true, nil
+	var t []*Tag
+	inMemory := make(map[uint32]struct{})
+	ts.tags.Range(func(k, v interface{}) bool {
+		tag := v.(*Tag)
+		inMemory[tag.Uid] = struct{}{}
+		if q.Match(tag, now) {
+			t = append(t, tag)
 		}
-
-		return false, nil
+		return true
 	})
 
-	return t, err
-}
-
-func decodeTagValueFromStore(value []byte) (*Tag, error) {
-	var data []byte
-	err := json.Unmarshal(value, &data)
+	stored, err := ts.store.query(q, now, q.Offset+q.Limit, func(uid uint32) bool {
+		_, ok := inMemory[uid]
+		return ok
+	})
 	if err != nil {
 		return nil, err
 	}
-	var ta Tag
-	err = ta.UnmarshalBinary(data)
-	if err != nil {
-		return nil, err
+	t = append(t, stored...)
+	q.SortTags(t)
+
+	if q.Offset >= len(t) {
+		return nil, nil
 	}
-	return &ta, nil
+	t = t[q.Offset:]
+	if len(t) > q.Limit {
+		t = t[:q.Limit]
+	}
+	return t, nil
 }
 
-// getTagFromStore get a given tag from the state store.
-func (ts *Tags) getTagFromStore(uid uint32) (*Tag, error) {
-	key := tagKey(uid)
+func decodeTagValueFromStore(value []byte) (*Tag, error) {
 	var data []byte
-	err := ts.stateStore.Get(key, &data)
+	err := json.Unmarshal(value, &data)
 	if err != nil {
 		return nil, err
 	}
@@ -298,7 +270,3 @@ func (ts *Tags) Close() (err error) {
 	}
 	return nil
 }
-
-func tagKey(uid uint32) string {
-	return tagKeyPrefix + strconv.FormatInt(int64(uid), 10)
-}
@@ -19,21 +19,35 @@ package tags
 import (
 	"context"
 	"errors"
+	"reflect"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/log"
 	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
+// newStore returns the in-memory tags store closed with the test.
+func newStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
 func TestAll(t *testing.T) {
 	t.Parallel()
 
-	mockStatestore := statestore.NewStateStore()
+	store := newStore(t)
 	logger := log.Noop
-	ts := NewTags(mockStatestore, logger)
+	ts := NewTags(store, logger)
 	if _, err := ts.Create(1); err != nil {
 		t.Fatal(err)
 	}
@@ -68,10 +82,10 @@ func TestAll(t *testing.T) {
 func TestListAll(t *testing.T) {
 	t.Parallel()
 
-	mockStatestore := statestore.NewStateStore()
+	store := newStore(t)
 	logger := log.Noop
 
-	ts1 := NewTags(mockStatestore, logger)
+	ts1 := NewTags(store, logger)
 
 	// create few tags
 	for i := 0; i < 5; i++ {
@@ -90,7 +104,7 @@ func TestListAll(t *testing.T) {
 		t.Fatalf("want %d tags but got %d", 5, len(tagList1))
 	}
 
-	// save all returned tags to statestore
+	// save all returned tags to the store
 	for _, tag := range tagList1 {
 		err = tag.saveTag()
 		if err != nil {
@@ -99,13 +113,13 @@ func TestListAll(t *testing.T) {
 	}
 
 	// This sleep is needed because otherwise in test the ts2 newtags gets the same seed as ts1 (happening in the same second in the test),
-	// which in the test results in the same uids already existing in statestore that the "create few more tags" creates below in sync.Map
-	// this highlights that upon tags.Create(), already existing values are only checked in sync.Map but not in statestore
+	// which in the test results in the same uids already existing in the store that the "create few more tags" creates below in sync.Map
+	// this highlights that upon tags.Create(), already existing values are only checked in sync.Map but not in the store
 
 	time.Sleep(1 * time.Nanosecond)
 
 	// use new tags object
-	ts2 := NewTags(mockStatestore, logger)
+	ts2 := NewTags(store, logger)
 
 	// create few more tags in new tags object
 	for i := 0; i < 5; i++ {
@@ -114,7 +128,7 @@ func TestListAll(t *testing.T) {
 		}
 	}
 
-	// first tags are from sync.Map
+	// the tags of sync.Map and the store are listed together by their uid
 	tagList2, err := ts2.ListAll(context.Background(), 0, 5)
 	if err != nil {
 		t.Fatal(err)
@@ -124,7 +138,6 @@ func TestListAll(t *testing.T) {
 		t.Fatalf("want %d tags but got %d", 5, len(tagList2))
 	}
 
-	// now tags are returned from statestore
 	tagList3, err := ts2.ListAll(context.Background(), 5, 5)
 	if err != nil {
 		t.Fatal(err)
@@ -134,23 +147,156 @@ func TestListAll(t *testing.T) {
 		t.Fatalf("want %d tags but got %d", 5, len(tagList3))
 	}
 
-	// where they are not sorted
-	sort.Slice(tagList3, func(i, j int) bool { return tagList3[i].Uid < tagList3[j].Uid })
+	got := append(tagList2, tagList3...)
+	if !sort.SliceIsSorted(got, func(i, j int) bool { return got[i].Uid < got[j].Uid }) {
+		t.Fatal("tags are not sorted by uid")
+	}
 
-	// and are the same as ones returned from first tags object
-	for i := range tagList3 {
-		if tagList1[i].Uid != tagList3[i].Uid {
-			t.Fatalf("expected tag %d, but got %d", tagList1[i].Uid, tagList3[i].Uid)
+	// and include the ones saved by the first tags object
+	uids := make(map[uint32]bool)
+	for _, tag := range got {
+		uids[tag.Uid] = true
+	}
+	for _, tag := range tagList1 {
+		if !uids[tag.Uid] {
+			t.Fatalf("tag %d not listed", tag.Uid)
 		}
 	}
 }
 
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	store := newStore(t)
+	ts := NewTags(store, log.Noop)
+
+	now := time.Now()
+	newTag := func(uid uint32, age time.Duration, total, synced int64) *Tag {
+		tag := &Tag{Uid: uid, Total: total, Split: total, Stored: total, Synced: synced, StartedAt: now.Add(-age), store: store, logger: log.Noop}
+		if err := tag.saveTag(); err != nil {
+			t.Fatal(err)
+		}
+		return tag
+	}
+	newTag(1, time.Minute, 10, 10)   // complete
+	newTag(2, 3*time.Hour, 10, 2)    // incomplete
+	newTag(3, time.Hour, 10, 5)      // incomplete
+	newTag(4, 2*time.Hour, 10, 10)   // complete
+	newTag(5, 30*time.Minute, 10, 0) // incomplete
+
+	// the tag of sync.Map takes precedence over the stored one
+	ta, err := ts.Get(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ta.Synced = 10
+
+	for _, tc := range []struct {
+		name  string
+		query Query
+		want  []uint32
+	}{
+		{
+			name: "all",
+			want: []uint32{1, 2, 3, 4, 5},
+		},
+		{
+			name:  "by age",
+			query: Query{Sort: SortAge},
+			want:  []uint32{2, 4, 3, 5, 1},
+		},
+		{
+			name:  "incomplete by age",
+			query: Query{Progress: ProgressIncomplete, Sort: SortAge},
+			want:  []uint32{2, 5},
+		},
+		{
+			name:  "complete",
+			query: Query{Progress: ProgressComplete},
+			want:  []uint32{1, 3, 4},
+		},
+		{
+			name:  "age bounds",
+			query: Query{MinAge: 10 * time.Minute, MaxAge: 150 * time.Minute},
+			want:  []uint32{3, 4, 5},
+		},
+		{
+			name:  "completeness bounds",
+			query: Query{MinCompleteness: 0.1, MaxCompleteness: 0.5},
+			want:  []uint32{2},
+		},
+		{
+			name:  "offset and limit",
+			query: Query{Sort: SortAge, Offset: 1, Limit: 2},
+			want:  []uint32{4, 3},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tags, err := ts.Query(context.Background(), tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]uint32, len(tags))
+			for i, tag := range tags {
+				got[i] = tag.Uid
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got tags %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	stateStore := statestore.NewStateStore()
+	ta := &Tag{Uid: 42, Total: 10, Synced: 3, StartedAt: time.Unix(1600000000, 0)}
+	value, err := ta.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stateStore.Put(tagKeyPrefix+"42", value); err != nil {
+		t.Fatal(err)
+	}
+	if err := stateStore.Put("other", "value"); err != nil {
+		t.Fatal(err)
+	}
+
+	store := newStore(t)
+	n, err := store.Migrate(stateStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("got %d migrated tags, want 1", n)
+	}
+
+	got, err := NewTags(store, log.Noop).Get(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != ta.Total || got.Synced != ta.Synced || !got.StartedAt.Equal(ta.StartedAt) {
+		t.Fatalf("got tag %+v, want %+v", got, ta)
+	}
+	if err := stateStore.Get(tagKeyPrefix+"42", new(Tag)); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+	var other string
+	if err := stateStore.Get("other", &other); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestPersistence(t *testing.T) {
 	t.Parallel()
 
-	mockStatestore := statestore.NewStateStore()
+	store := newStore(t)
 	logger := log.Noop
-	ts := NewTags(mockStatestore, logger)
+	ts := NewTags(store, logger)
 	ta, err := ts.Create(1)
 	if err != nil {
 		t.Fatal(err)
@@ -169,7 +315,7 @@ func TestPersistence(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	ts = NewTags(mockStatestore, logger)
+	ts = NewTags(store, logger)
 
 	// Get the tag after the node bootup
 	rcvd1, err := ts.Get(ta.Uid)
@@ -202,7 +348,7 @@ func TestPersistence(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	ts = NewTags(mockStatestore, logger)
+	ts = NewTags(store, logger)
 
 	// get the tag after the node boot up
 	rcvd2, err := ts.Get(ta.Uid)
@@ -225,7 +371,7 @@ func TestPersistence(t *testing.T) {
 	}
 
 	// regression test case: make sure that a persisted tag
-	// is flushed from statestore on Delete
+	// is flushed from the store on Delete
 	ts.Delete(ta.Uid)
 
 	// simulate node closing down and booting up
@@ -233,7 +379,7 @@ func TestPersistence(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	ts = NewTags(mockStatestore, logger)
+	ts = NewTags(store, logger)
 
 	// get the tag after the node boot up
 	_, err = ts.Get(ta.Uid)
@@ -0,0 +1,95 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package driver lets the alternative topology strategies, such as the
+// latency-optimized, the data-center-aware or the static full mesh of
+// a private swarm, be compiled into the node and selected by the name in
+// the configuration, without forking the kademlia. A driver package
+// registers its Factory on init, as the kademlia does under DefaultName,
+// and is compiled in by importing it.
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/addressbook"
+	"github.com/ethersphere/bee/pkg/discovery"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/pingpong"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology"
+	"github.com/ethersphere/bee/pkg/util/registry"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultName is the name of the kademlia driver, which is used if none is selected.
+const DefaultName = "kademlia"
+
+// ErrUnknown is returned if no driver is registered under the name.
+var ErrUnknown = errors.New("unknown topology driver")
+
+// Driver is the topology driver of the node.
+type Driver interface {
+	topology.Driver
+	p2p.PickyNotifier
+	topology.SetStorageRadiuser
+	topology.PeerHealthUpdater
+	topology.PeersCounter
+
+	// Start starts connecting to the peers.
+	Start(ctx context.Context) error
+	// Metrics returns the metrics collectors of the driver.
+	Metrics() []prometheus.Collector
+}
+
+// Options are the dependencies and the configuration of the driver.
+type Options struct {
+	Base        swarm.Address
+	AddressBook addressbook.Interface
+	Discovery   discovery.Driver
+	P2P         p2p.Service
+	Pinger      pingpong.Interface
+	MetricsDB   *shed.DB
+	Logger      log.Logger
+
+	Bootnodes       []ma.Multiaddr
+	BootnodeDomains []string // domains whose TXT records list the underlays of the bootnodes
+	BootnodeMode    bool
+	StaticNodes     []swarm.Address
+	IgnoreRadius    bool
+}
+
+// Factory returns the driver configured with the options.
+type Factory func(o Options) (Driver, error)
+
+var factories = registry.New[Factory]("topology driver")
+
+// Register registers the factory of the driver under the name.
+// It panics if the name is already registered, as that is a build error.
+func Register(name string, f Factory) {
+	factories.Register(name, f)
+}
+
+// New returns the driver registered under the name,
+// or the DefaultName driver if the name is empty.
+func New(name string, o Options) (Driver, error) {
+	if name == "" {
+		name = DefaultName
+	}
+	f, ok := factories.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknown, name)
+	}
+	return f(o)
+}
+
+// Names returns the sorted names of the registered drivers.
+func Names() []string {
+	return factories.Names()
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package driver_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/topology/driver"
+	_ "github.com/ethersphere/bee/pkg/topology/kademlia"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	errCreated := errors.New("created")
+	base := swarm.RandAddress(t)
+
+	var got driver.Options
+	driver.Register("test-full-mesh", func(o driver.Options) (driver.Driver, error) {
+		got = o
+		return nil, errCreated
+	})
+
+	if _, err := driver.New("test-full-mesh", driver.Options{Base: base, BootnodeMode: true}); !errors.Is(err, errCreated) {
+		t.Fatalf("got error %v, want %v", err, errCreated)
+	}
+	if !got.Base.Equal(base) || !got.BootnodeMode {
+		t.Fatalf("got options %+v, want the passed options", got)
+	}
+
+	if _, err := driver.New("unknown", driver.Options{}); !errors.Is(err, driver.ErrUnknown) {
+		t.Fatalf("got error %v, want %v", err, driver.ErrUnknown)
+	}
+
+	// other drivers may be compiled in
+	names := driver.Names()
+	for _, want := range []string{driver.DefaultName, "test-full-mesh"} {
+		if i := sort.SearchStrings(names, want); i == len(names) || names[i] != want {
+			t.Fatalf("driver %q not in the drivers %v", want, names)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("duplicate registration did not panic")
+		}
+	}()
+	driver.Register(driver.DefaultName, nil)
+}
@@ -0,0 +1,164 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kademlia
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+const (
+	defaultBootnodeBackoff    = 30 * time.Second // backoff after the first failed connection to a bootnode
+	defaultBootnodeMaxBackoff = 30 * time.Minute // maximal backoff of the failing bootnode
+)
+
+// lookupTXTFunc returns the TXT records of the domain name.
+type lookupTXTFunc func(ctx context.Context, name string) ([]string, error)
+
+// bootnodeHealth is the health of a bootnode.
+type bootnodeHealth struct {
+	failures int       // number of the consecutive failed connections
+	retryAt  time.Time // time before which the bootnode is not dialed
+}
+
+// bootnodes rotates across the configured bootnodes and the bootnodes
+// discovered from the TXT records of the domains, each record listing an
+// underlay address. The bootnodes which fail are not dialed for the backoff
+// which doubles with every consecutive failure, so that the dead bootnodes
+// are not dialed in the tight retry loop while there are no connected peers.
+type bootnodes struct {
+	static     []ma.Multiaddr
+	domains    []string
+	lookupTXT  lookupTXTFunc
+	backoff    time.Duration
+	maxBackoff time.Duration
+	logger     log.Logger
+
+	mu       sync.Mutex
+	health   map[string]*bootnodeHealth // by the bootnode address
+	rotation int                        // offset of the first dialed bootnode
+}
+
+func newBootnodes(static []ma.Multiaddr, domains []string, lookupTXT lookupTXTFunc, backoff, maxBackoff time.Duration, logger log.Logger) *bootnodes {
+	return &bootnodes{
+		static:     static,
+		domains:    domains,
+		lookupTXT:  lookupTXT,
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+		logger:     logger,
+		health:     make(map[string]*bootnodeHealth),
+	}
+}
+
+// candidates returns the bootnodes which are not backing off at the time,
+// the healthiest first and rotated among the equally healthy ones, so that
+// the connections are spread across the bootnodes.
+func (b *bootnodes) candidates(ctx context.Context, now time.Time) []ma.Multiaddr {
+	addrs := append([]ma.Multiaddr(nil), b.static...)
+	for _, domain := range b.domains {
+		records, err := b.lookupTXT(ctx, domain)
+		if err != nil {
+			b.logger.Debug("lookup bootnodes txt records failed", "domain", domain, "error", err)
+			continue
+		}
+		for _, r := range records {
+			addr, err := ma.NewMultiaddr(strings.TrimSpace(r))
+			if err != nil {
+				// the domain may have the other txt records
+				continue
+			}
+			addrs = append(addrs, addr)
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(addrs))
+	available := make([]ma.Multiaddr, 0, len(addrs))
+	for _, addr := range addrs {
+		key := addr.String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if h, ok := b.health[key]; ok && now.Before(h.retryAt) {
+			continue
+		}
+		available = append(available, addr)
+	}
+
+	if n := len(available); n > 0 {
+		offset := b.rotation % n
+		available = append(available[offset:], available[:offset]...)
+		b.rotation++
+	}
+	sort.SliceStable(available, func(i, j int) bool {
+		return b.failures(available[i]) < b.failures(available[j])
+	})
+	return available
+}
+
+// backingOff returns the number of the bootnodes backing off at the time.
+func (b *bootnodes) backingOff(now time.Time) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var n int
+	for _, h := range b.health {
+		if now.Before(h.retryAt) {
+			n++
+		}
+	}
+	return n
+}
+
+// failures returns the number of the consecutive failures of the bootnode,
+// it must be called with the mutex locked.
+func (b *bootnodes) failures(addr ma.Multiaddr) int {
+	if h, ok := b.health[addr.String()]; ok {
+		return h.failures
+	}
+	return 0
+}
+
+// succeeded records the connection to the bootnode.
+func (b *bootnodes) succeeded(addr ma.Multiaddr) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.health, addr.String())
+}
+
+// failed records the failed connection to the bootnode at the time
+// and returns the time before which the bootnode is not dialed.
+func (b *bootnodes) failed(addr ma.Multiaddr, now time.Time) time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h, ok := b.health[addr.String()]
+	if !ok {
+		h = new(bootnodeHealth)
+		b.health[addr.String()] = h
+	}
+	h.failures++
+
+	backoff := b.backoff
+	for i := 1; i < h.failures && backoff < b.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > b.maxBackoff {
+		backoff = b.maxBackoff
+	}
+	h.retryAt = now.Add(backoff)
+	return h.retryAt
+}
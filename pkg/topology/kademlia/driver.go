@@ -0,0 +1,24 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kademlia
+
+import (
+	"github.com/ethersphere/bee/pkg/topology/driver"
+)
+
+var _ driver.Driver = (*Kad)(nil)
+
+// nolint:gochecknoinits
+func init() {
+	driver.Register(driver.DefaultName, func(o driver.Options) (driver.Driver, error) {
+		return New(o.Base, o.AddressBook, o.Discovery, o.P2P, o.Pinger, o.MetricsDB, o.Logger, Options{
+			Bootnodes:       o.Bootnodes,
+			BootnodeDomains: o.BootnodeDomains,
+			BootnodeMode:    o.BootnodeMode,
+			StaticNodes:     o.StaticNodes,
+			IgnoreRadius:    o.IgnoreRadius,
+		})
+	})
+}
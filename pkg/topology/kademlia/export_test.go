@@ -4,7 +4,14 @@
 
 package kademlia
 
-import "github.com/ethersphere/bee/pkg/swarm"
+import (
+	"context"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/swarm"
+	ma "github.com/multiformats/go-multiaddr"
+)
 
 var (
 	PruneOversaturatedBinsFunc = func(k *Kad) func(uint8) {
@@ -24,3 +31,21 @@ type PeerFilterFunc = peerFilterFunc
 func (k *Kad) IsWithinDepth(addr swarm.Address) bool {
 	return swarm.Proximity(k.base.Bytes(), addr.Bytes()) >= k.NeighborhoodDepth()
 }
+
+type Bootnodes = bootnodes
+
+func NewBootnodes(static []ma.Multiaddr, domains []string, lookupTXT func(context.Context, string) ([]string, error), backoff, maxBackoff time.Duration) *Bootnodes {
+	return newBootnodes(static, domains, lookupTXT, backoff, maxBackoff, log.Noop)
+}
+
+func (b *bootnodes) Candidates(ctx context.Context, now time.Time) []ma.Multiaddr {
+	return b.candidates(ctx, now)
+}
+
+func (b *bootnodes) Succeeded(addr ma.Multiaddr) {
+	b.succeeded(addr)
+}
+
+func (b *bootnodes) Failed(addr ma.Multiaddr, now time.Time) time.Time {
+	return b.failed(addr, now)
+}
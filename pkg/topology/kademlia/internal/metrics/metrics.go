@@ -54,6 +54,7 @@ func PeerLogIn(t time.Time, dir PeerConnectionDirection) RecordOp {
 		}
 		cs.sessionConnDirection = dir
 		cs.lastSeenTimestamp = ls
+		cs.unhealthy = false
 	}
 }
 
@@ -120,6 +121,16 @@ func PeerReachability(s p2p.ReachabilityStatus) RecordOp {
 	}
 }
 
+// PeerHealth updates the last health status.
+func PeerHealth(healthy bool) RecordOp {
+	return func(cs *Counters) {
+		cs.Lock()
+		defer cs.Unlock()
+
+		cs.unhealthy = !healthy
+	}
+}
+
 // Snapshot represents a snapshot of peers' metrics counters.
 type Snapshot struct {
 	LastSeenTimestamp          int64
@@ -129,6 +140,7 @@ type Snapshot struct {
 	SessionConnectionDirection PeerConnectionDirection
 	LatencyEWMA                time.Duration
 	Reachability               p2p.ReachabilityStatus
+	Healthy                    bool
 }
 
 // HasAtMaxOneConnectionAttempt returns true if the snapshot represents a new
@@ -161,6 +173,7 @@ type Counters struct {
 	sessionConnDuration  time.Duration
 	sessionConnDirection PeerConnectionDirection
 	latencyEWMA          time.Duration
+	unhealthy            bool
 	ReachabilityStatus   p2p.ReachabilityStatus
 }
 
@@ -210,6 +223,7 @@ func (cs *Counters) snapshot(t time.Time) *Snapshot {
 		SessionConnectionDirection: cs.sessionConnDirection,
 		LatencyEWMA:                cs.latencyEWMA,
 		Reachability:               cs.ReachabilityStatus,
+		Healthy:                    !cs.unhealthy,
 	}
 }
 
@@ -301,6 +315,20 @@ func (c *Collector) IsUnreachable(addr swarm.Address) bool {
 	return cs.ReachabilityStatus != p2p.ReachabilityStatusPublic
 }
 
+// IsUnhealthy returns true if the peer was marked as unhealthy.
+func (c *Collector) IsUnhealthy(addr swarm.Address) bool {
+	val, ok := c.counters.Load(addr.ByteString())
+	if !ok {
+		return false
+	}
+	cs := val.(*Counters)
+
+	cs.Lock()
+	defer cs.Unlock()
+
+	return cs.unhealthy
+}
+
 // Inspect allows inspecting current snapshot for the given
 // peer address by executing the inspection function.
 func (c *Collector) Inspect(addr swarm.Address) *Snapshot {
@@ -139,6 +139,19 @@ func TestPeerMetricsCollector(t *testing.T) {
 		t.Fatalf("Snapshot(%q, ...): has reachability status mismatch: have %q; want %q", addr, have, want)
 	}
 
+	// Health.
+	if have, want := ss.Healthy, true; have != want {
+		t.Fatalf("Snapshot(%q, ...): health mismatch: have %t; want %t", addr, have, want)
+	}
+	mc.Record(addr, metrics.PeerHealth(false))
+	ss = snapshot(t, mc, t2, addr)
+	if have, want := ss.Healthy, false; have != want {
+		t.Fatalf("Snapshot(%q, ...): health mismatch: have %t; want %t", addr, have, want)
+	}
+	if have, want := mc.IsUnhealthy(addr), true; have != want {
+		t.Fatalf("IsUnhealthy(%q): mismatch: have %t; want %t", addr, have, want)
+	}
+
 	// Inspect.
 	have := mc.Inspect(addr)
 	want := ss
@@ -172,6 +185,7 @@ func TestPeerMetricsCollector(t *testing.T) {
 	want = &metrics.Snapshot{
 		LastSeenTimestamp:       ss.LastSeenTimestamp,
 		ConnectionTotalDuration: 2 * ss.ConnectionTotalDuration, // 2x because we've already logout with t3 and login with t1 again.
+		Healthy:                 true,
 	}
 	if diff := cmp.Diff(have, want); diff != "" {
 		t.Fatalf("unexpected snapshot diffrence:\n%s", diff)
@@ -87,13 +87,17 @@ var noopSanctionedPeerFn = func(_ swarm.Address) bool { return false }
 type Options struct {
 	SaturationFunc   binSaturationFunc
 	Bootnodes        []ma.Multiaddr
+	BootnodeDomains  []string // domains whose TXT records list the underlays of the bootnodes
 	BootnodeMode     bool
 	PruneFunc        pruneFunc
 	StaticNodes      []swarm.Address
 	ReachabilityFunc peerFilterFunc
 	IgnoreRadius     bool
+	LookupTXT        lookupTXTFunc
 
 	BitSuffixLength             *int
+	BootnodeBackoff             *time.Duration
+	BootnodeMaxBackoff          *time.Duration
 	TimeToRetry                 *time.Duration
 	ShortRetry                  *time.Duration
 	SaturationPeers             *int
@@ -108,13 +112,17 @@ type Options struct {
 type kadOptions struct {
 	SaturationFunc   binSaturationFunc
 	Bootnodes        []ma.Multiaddr
+	BootnodeDomains  []string
 	BootnodeMode     bool
 	PruneFunc        pruneFunc
 	StaticNodes      []swarm.Address
 	ReachabilityFunc peerFilterFunc
 	IgnoreRadius     bool
+	LookupTXT        lookupTXTFunc
 
 	TimeToRetry                 time.Duration
+	BootnodeBackoff             time.Duration
+	BootnodeMaxBackoff          time.Duration
 	ShortRetry                  time.Duration
 	PeerPingPollTime            time.Duration
 	BitSuffixLength             int // additional depth of common prefix for bin
@@ -130,13 +138,17 @@ func newKadOptions(o Options) kadOptions {
 		// copy values
 		SaturationFunc:   o.SaturationFunc,
 		Bootnodes:        o.Bootnodes,
+		BootnodeDomains:  o.BootnodeDomains,
 		BootnodeMode:     o.BootnodeMode,
 		PruneFunc:        o.PruneFunc,
 		StaticNodes:      o.StaticNodes,
 		ReachabilityFunc: o.ReachabilityFunc,
 		IgnoreRadius:     o.IgnoreRadius,
+		LookupTXT:        o.LookupTXT,
 		// copy or use default
 		TimeToRetry:                 defaultValDuration(o.TimeToRetry, defaultTimeToRetry),
+		BootnodeBackoff:             defaultValDuration(o.BootnodeBackoff, defaultBootnodeBackoff),
+		BootnodeMaxBackoff:          defaultValDuration(o.BootnodeMaxBackoff, defaultBootnodeMaxBackoff),
 		ShortRetry:                  defaultValDuration(o.ShortRetry, defaultShortRetry),
 		PeerPingPollTime:            defaultValDuration(o.PeerPingPollTime, defaultPeerPingPollTime),
 		BitSuffixLength:             defaultValInt(o.BitSuffixLength, defaultBitSuffixLength),
@@ -151,6 +163,10 @@ func newKadOptions(o Options) kadOptions {
 		ko.SaturationFunc = makeSaturationFunc(ko)
 	}
 
+	if ko.LookupTXT == nil {
+		ko.LookupTXT = net.DefaultResolver.LookupTXT
+	}
+
 	return ko
 }
 
@@ -194,6 +210,7 @@ type Kad struct {
 	peerSigMtx        sync.Mutex
 	logger            log.Logger // logger
 	bootnode          bool       // indicates whether the node is working in bootnode mode
+	bootnodes         *bootnodes // bootnodes dialed while there are no connected peers
 	collector         *im.Collector
 	quit              chan struct{} // quit channel
 	halt              chan struct{} // halt channel
@@ -253,6 +270,7 @@ func New(
 		peerFilter:        opt.ReachabilityFunc,
 		storageRadius:     swarm.MaxPO,
 	}
+	k.bootnodes = newBootnodes(opt.Bootnodes, opt.BootnodeDomains, opt.LookupTXT, opt.BootnodeBackoff, opt.BootnodeMaxBackoff, k.logger)
 
 	blocklistCallback := func(a swarm.Address) {
 		k.logger.Debug("disconnecting peer for ping failure", "peer_address", a)
@@ -840,21 +858,33 @@ func (k *Kad) previouslyConnected() []swarm.Address {
 	return peers
 }
 
+// connectBootNodes connects to up to 3 bootnodes which are not backing off,
+// rotating across them. The bootnodes to which no connection could be made
+// are backing off, so they are not dialed on every round of the manage loop.
 func (k *Kad) connectBootNodes(ctx context.Context) {
 	loggerV1 := k.logger.V(1).Register()
 
-	var attempts, connected int
-	totalAttempts := maxBootNodeAttempts * len(k.opt.Bootnodes)
-
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	for _, addr := range k.opt.Bootnodes {
-		if attempts >= totalAttempts || connected >= 3 {
+	candidates := k.bootnodes.candidates(ctx, time.Now())
+	defer func() {
+		k.metrics.BootNodesBackingOff.Set(float64(k.bootnodes.backingOff(time.Now())))
+	}()
+	if len(candidates) == 0 {
+		loggerV1.Debug("no bootnodes to connect to")
+		return
+	}
+
+	var connected int
+	for _, bootnode := range candidates {
+		if connected >= 3 || ctx.Err() != nil {
 			return
 		}
 
-		if _, err := p2p.Discover(ctx, addr, func(addr ma.Multiaddr) (stop bool, err error) {
+		var attempts int
+		reached := false
+		_, err := p2p.Discover(ctx, bootnode, func(addr ma.Multiaddr) (stop bool, err error) {
 			loggerV1.Debug("connecting to bootnode", "bootnode_address", addr)
 			if attempts >= maxBootNodeAttempts {
 				return true, nil
@@ -871,8 +901,10 @@ func (k *Kad) connectBootNodes(ctx context.Context) {
 					return false, err
 				}
 				k.logger.Debug("connect to bootnode failed", "bootnode_address", addr, "error", err)
+				reached = true
 				return false, nil
 			}
+			reached = true
 
 			if err := k.onConnected(ctx, bzzAddress.Overlay); err != nil {
 				return false, err
@@ -885,11 +917,22 @@ func (k *Kad) connectBootNodes(ctx context.Context) {
 
 			// connect to max 3 bootnodes
 			return connected >= 3, nil
-		}); err != nil && !errors.Is(err, context.Canceled) {
-			k.logger.Debug("discover to bootnode failed", "bootnode_address", addr, "error", err)
-			k.logger.Warning("discover to bootnode failed", "bootnode_address", addr)
-			return
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			k.logger.Debug("discover to bootnode failed", "bootnode_address", bootnode, "error", err)
+			k.logger.Warning("discover to bootnode failed", "bootnode_address", bootnode)
 		}
+
+		if reached {
+			k.bootnodes.succeeded(bootnode)
+			continue
+		}
+		if errors.Is(err, context.Canceled) {
+			continue
+		}
+		k.metrics.TotalBootNodesConnectionFailures.Inc()
+		retryAt := k.bootnodes.failed(bootnode, time.Now())
+		k.logger.Debug("bootnode backing off", "bootnode_address", bootnode, "retry_at", retryAt)
 	}
 }
 
@@ -1391,7 +1434,7 @@ func (k *Kad) EachNeighborRev(f topology.EachPeerFunc) error {
 // EachPeer iterates from closest bin to farthest.
 func (k *Kad) EachPeer(f topology.EachPeerFunc, filter topology.Filter) error {
 	return k.connectedPeers.EachBin(func(addr swarm.Address, po uint8) (bool, bool, error) {
-		if filter.Reachable && k.peerFilter(addr) {
+		if k.filtered(addr, filter) {
 			return false, false, nil
 		}
 		return f(addr, po)
@@ -1401,12 +1444,20 @@ func (k *Kad) EachPeer(f topology.EachPeerFunc, filter topology.Filter) error {
 // EachPeerRev iterates from farthest bin to closest.
 func (k *Kad) EachPeerRev(f topology.EachPeerFunc, filter topology.Filter) error {
 	return k.connectedPeers.EachBinRev(func(addr swarm.Address, po uint8) (bool, bool, error) {
-		if filter.Reachable && k.peerFilter(addr) {
+		if k.filtered(addr, filter) {
 			return false, false, nil
 		}
 		return f(addr, po)
 	})
 }
+
+// filtered returns true if the peer is skipped by the given filter.
+func (k *Kad) filtered(addr swarm.Address, filter topology.Filter) bool {
+	if filter.Reachable && k.peerFilter(addr) {
+		return true
+	}
+	return filter.Healthy && k.collector.IsUnhealthy(addr)
+}
 func (k *Kad) PeersCount(filter topology.Filter) int {
 	return k.connectedPeers.Length()
 }
@@ -1426,6 +1477,12 @@ func (k *Kad) Reachable(addr swarm.Address, status p2p.ReachabilityStatus) {
 	}
 }
 
+// UpdatePeerHealth implements the topology.PeerHealthUpdater interface.
+func (k *Kad) UpdatePeerHealth(peer swarm.Address, healthy bool) {
+	k.collector.Record(peer, im.PeerHealth(healthy))
+	k.logger.V(1).Register().Debug("health of peer updated", "peer_address", peer, "healthy", healthy)
+}
+
 // UpdateReachability updates node reachability status.
 // The status will be updated only once. Updates to status
 // p2p.ReachabilityStatusUnknown are ignored.
@@ -1731,6 +1788,7 @@ func createMetricsSnapshotView(ss *im.Snapshot) *topology.MetricSnapshotView {
 		SessionConnectionDirection: string(ss.SessionConnectionDirection),
 		LatencyEWMA:                ss.LatencyEWMA.Milliseconds(),
 		Reachability:               ss.Reachability.String(),
+		Healthy:                    ss.Healthy,
 	}
 }
 
@@ -1328,6 +1328,98 @@ func TestStart(t *testing.T) {
 	})
 }
 
+func TestStartBootnodeDomains(t *testing.T) {
+	t.Parallel()
+
+	var records []string
+	for i := 0; i < 5; i++ {
+		records = append(records, underlayBase+swarm.RandAddress(t).String())
+	}
+	records = append(records, "v=spf1 -all") // not a bootnode record
+
+	var lookups int32
+	lookupTXT := func(_ context.Context, name string) ([]string, error) {
+		if name != "bootnodes.example.org" {
+			return nil, fmt.Errorf("unknown domain %s", name)
+		}
+		atomic.AddInt32(&lookups, 1)
+		return records, nil
+	}
+
+	var conns, failedConns int32 // how many connect calls were made to the p2p mock
+	_, kad, _, _, _ := newTestKademlia(t, &conns, &failedConns, kademlia.Options{
+		Bootnodes:       []ma.Multiaddr{nonConnectableAddress},
+		BootnodeDomains: []string{"bootnodes.example.org"},
+		LookupTXT:       lookupTXT,
+	})
+
+	if err := kad.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, kad)
+
+	waitCounter(t, &conns, 3)
+	if n := atomic.LoadInt32(&lookups); n == 0 {
+		t.Fatal("bootnode domain not looked up")
+	}
+	if n := atomic.LoadInt32(&failedConns); n > 1 {
+		t.Fatalf("got %d failed connections, want at most 1", n)
+	}
+}
+
+func TestBootnodesBackoff(t *testing.T) {
+	t.Parallel()
+
+	var addrs []ma.Multiaddr
+	for i := 0; i < 3; i++ {
+		addr, err := ma.NewMultiaddr(underlayBase + swarm.RandAddress(t).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, addr)
+	}
+
+	var (
+		ctx        = context.Background()
+		now        = time.Now()
+		backoff    = time.Minute
+		maxBackoff = 3 * time.Minute
+		bootnodes  = kademlia.NewBootnodes(addrs, nil, nil, backoff, maxBackoff)
+	)
+
+	// the bootnodes are rotated
+	if got := bootnodes.Candidates(ctx, now); !got[0].Equal(addrs[0]) || len(got) != 3 {
+		t.Fatalf("got candidates %v, want starting with %s", got, addrs[0])
+	}
+	if got := bootnodes.Candidates(ctx, now); !got[0].Equal(addrs[1]) || len(got) != 3 {
+		t.Fatalf("got candidates %v, want starting with %s", got, addrs[1])
+	}
+
+	// the failed bootnode is backing off for the doubled backoff up to the maximal one
+	for i, want := range []time.Duration{backoff, 2 * backoff, maxBackoff, maxBackoff} {
+		if got := bootnodes.Failed(addrs[0], now); !got.Equal(now.Add(want)) {
+			t.Fatalf("failure %d: got retry at %s, want %s", i+1, got, now.Add(want))
+		}
+	}
+	for _, addr := range bootnodes.Candidates(ctx, now) {
+		if addr.Equal(addrs[0]) {
+			t.Fatalf("got backing off bootnode %s", addr)
+		}
+	}
+
+	// the bootnode is dialed after the backoff, after the healthy ones
+	got := bootnodes.Candidates(ctx, now.Add(maxBackoff))
+	if len(got) != 3 || !got[2].Equal(addrs[0]) {
+		t.Fatalf("got candidates %v, want ending with %s", got, addrs[0])
+	}
+
+	// the connected bootnode is healthy again
+	bootnodes.Succeeded(addrs[0])
+	if got := bootnodes.Failed(addrs[0], now); !got.Equal(now.Add(backoff)) {
+		t.Fatalf("got retry at %s, want %s", got, now.Add(backoff))
+	}
+}
+
 func TestOutofDepthPrune(t *testing.T) {
 	t.Parallel()
 
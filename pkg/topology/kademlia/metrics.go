@@ -26,6 +26,8 @@ type metrics struct {
 	TotalOutboundConnectionAttempts       prometheus.Counter
 	TotalOutboundConnectionFailedAttempts prometheus.Counter
 	TotalBootNodesConnectionAttempts      prometheus.Counter
+	TotalBootNodesConnectionFailures      prometheus.Counter
+	BootNodesBackingOff                   prometheus.Gauge
 	StartAddAddressBookOverlaysTime       prometheus.Histogram
 	PeerLatencyEWMA                       prometheus.Histogram
 	Flag                                  prometheus.Counter
@@ -130,6 +132,18 @@ func newMetrics() metrics {
 			Name:      "total_bootnodes_connection_attempts",
 			Help:      "Total boot-nodes connection attempts made.",
 		}),
+		TotalBootNodesConnectionFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_bootnodes_connection_failures",
+			Help:      "Total boot-nodes which could not be connected to.",
+		}),
+		BootNodesBackingOff: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "bootnodes_backing_off",
+			Help:      "Number of the boot-nodes not dialed after the failed connections.",
+		}),
 		StartAddAddressBookOverlaysTime: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Namespace: m.Namespace,
 			Subsystem: subsystem,
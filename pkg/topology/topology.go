@@ -64,6 +64,7 @@ type EachNeighbor interface {
 // Filter defines the different filters that can be used with the Peer iterators
 type Filter struct {
 	Reachable bool
+	Healthy   bool
 }
 
 // EachPeerFunc is a callback that is called with a peer and its PO
@@ -84,6 +85,7 @@ type MetricSnapshotView struct {
 	SessionConnectionDirection string  `json:"sessionConnectionDirection"`
 	LatencyEWMA                int64   `json:"latencyEWMA"`
 	Reachability               string  `json:"reachability"`
+	Healthy                    bool    `json:"healthy"`
 }
 
 type BinInfo struct {
@@ -128,6 +130,44 @@ type KadBins struct {
 	Bin31 BinInfo `json:"bin_31"`
 }
 
+// List returns the bins ordered by their proximity order.
+func (kb *KadBins) List() []BinInfo {
+	return []BinInfo{
+		kb.Bin0,
+		kb.Bin1,
+		kb.Bin2,
+		kb.Bin3,
+		kb.Bin4,
+		kb.Bin5,
+		kb.Bin6,
+		kb.Bin7,
+		kb.Bin8,
+		kb.Bin9,
+		kb.Bin10,
+		kb.Bin11,
+		kb.Bin12,
+		kb.Bin13,
+		kb.Bin14,
+		kb.Bin15,
+		kb.Bin16,
+		kb.Bin17,
+		kb.Bin18,
+		kb.Bin19,
+		kb.Bin20,
+		kb.Bin21,
+		kb.Bin22,
+		kb.Bin23,
+		kb.Bin24,
+		kb.Bin25,
+		kb.Bin26,
+		kb.Bin27,
+		kb.Bin28,
+		kb.Bin29,
+		kb.Bin30,
+		kb.Bin31,
+	}
+}
+
 type KadParams struct {
 	Base                string    `json:"baseAddr"`            // base address string
 	Population          int       `json:"population"`          // known
@@ -155,6 +195,13 @@ type SetStorageRadiuser interface {
 	SetStorageRadius(uint8)
 }
 
+type PeerHealthUpdater interface {
+	// UpdatePeerHealth marks the peer as healthy or unhealthy.
+	// The unhealthy peers are skipped by the iterators with
+	// the Healthy filter.
+	UpdatePeerHealth(peer swarm.Address, healthy bool)
+}
+
 type PeersCounter interface {
 	PeersCount(Filter) int
 }
@@ -244,6 +244,16 @@ func NewLoggerWithTraceID(ctx context.Context, l log.Logger) log.Logger {
 	return loggerWithTraceID(FromContext(ctx), l)
 }
 
+// TraceID returns the ID of the trace of the tracing span context
+// stored in the go context, or the empty string if there is none.
+func TraceID(ctx context.Context) string {
+	jsc, ok := FromContext(ctx).(jaeger.SpanContext)
+	if !ok || !jsc.TraceID().IsValid() {
+		return ""
+	}
+	return jsc.TraceID().String()
+}
+
 func loggerWithTraceID(sc opentracing.SpanContext, l log.Logger) log.Logger {
 	if l == nil {
 		return nil
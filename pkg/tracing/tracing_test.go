@@ -227,6 +227,24 @@ func TestNewLoggerWithTraceID_nilLogger(t *testing.T) {
 	}
 }
 
+func TestTraceID(t *testing.T) {
+	t.Parallel()
+
+	tracer := newTracer(t)
+
+	if id := tracing.TraceID(context.Background()); id != "" {
+		t.Errorf("got trace id %q, want empty", id)
+	}
+
+	span, _, ctx := tracer.StartSpanFromContext(context.Background(), "some-operation", nil)
+	defer span.Finish()
+
+	want := span.Context().(jaeger.SpanContext).TraceID().String()
+	if got := tracing.TraceID(ctx); got != want {
+		t.Errorf("got trace id %q, want %q", got, want)
+	}
+}
+
 func newTracer(t *testing.T) *tracing.Tracer {
 	t.Helper()
 
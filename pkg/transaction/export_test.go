@@ -5,5 +5,6 @@
 package transaction
 
 var (
-	StoredTransactionKey = storedTransactionKey
+	StoredTransactionKey  = storedTransactionKey
+	PendingTransactionKey = pendingTransactionKey
 )
@@ -27,6 +27,8 @@ type transactionServiceMock struct {
 	storedTransaction    func(txHash common.Hash) (*transaction.StoredTransaction, error)
 	cancelTransaction    func(ctx context.Context, originalTxHash common.Hash) (common.Hash, error)
 	transactionFee       func(ctx context.Context, txHash common.Hash) (*big.Int, error)
+	nonceStatus          func(ctx context.Context) (*transaction.NonceStatus, error)
+	resolveStuckNonce    func(ctx context.Context) (common.Hash, error)
 }
 
 func (m *transactionServiceMock) Send(ctx context.Context, request *transaction.TxRequest, boostPercent int) (txHash common.Hash, err error) {
@@ -97,6 +99,20 @@ func (m *transactionServiceMock) TransactionFee(ctx context.Context, txHash comm
 	return big.NewInt(0), nil
 }
 
+func (m *transactionServiceMock) NonceStatus(ctx context.Context) (*transaction.NonceStatus, error) {
+	if m.nonceStatus != nil {
+		return m.nonceStatus(ctx)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *transactionServiceMock) ResolveStuckNonce(ctx context.Context) (common.Hash, error) {
+	if m.resolveStuckNonce != nil {
+		return m.resolveStuckNonce(ctx)
+	}
+	return common.Hash{}, errors.New("not implemented")
+}
+
 // Option is the option passed to the mock Chequebook service
 type Option interface {
 	apply(*transactionServiceMock)
@@ -154,6 +170,18 @@ func WithTransactionFeeFunc(f func(ctx context.Context, txHash common.Hash) (*bi
 	})
 }
 
+func WithNonceStatusFunc(f func(ctx context.Context) (*transaction.NonceStatus, error)) Option {
+	return optionFunc(func(s *transactionServiceMock) {
+		s.nonceStatus = f
+	})
+}
+
+func WithResolveStuckNonceFunc(f func(ctx context.Context) (common.Hash, error)) Option {
+	return optionFunc(func(s *transactionServiceMock) {
+		s.resolveStuckNonce = f
+	})
+}
+
 func New(opts ...Option) transaction.Service {
 	mock := new(transactionServiceMock)
 	for _, o := range opts {
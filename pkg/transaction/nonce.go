@@ -0,0 +1,205 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// StuckNonceTimeout is the duration after which the pending transaction
+// with the lowest unconfirmed nonce is considered to be stuck.
+const StuckNonceTimeout = 15 * time.Minute
+
+// ErrNoStuckNonce denotes that there is no stuck nonce to be resolved.
+var ErrNoStuckNonce = errors.New("no stuck nonce")
+
+// NonceManager hands out the nonces of the sender. As the chequebook, postage
+// and staking services all send their transactions from the same account,
+// the nonces are allocated one at a time so that concurrent transactions of
+// the different subsystems never reuse or skip a nonce.
+type NonceManager struct {
+	mu      sync.Mutex
+	backend Backend
+	store   storage.StateStorer
+	sender  common.Address
+}
+
+// NewNonceManager creates a new nonce manager for the sender.
+func NewNonceManager(backend Backend, store storage.StateStorer, sender common.Address) *NonceManager {
+	return &NonceManager{
+		backend: backend,
+		store:   store,
+		sender:  sender,
+	}
+}
+
+// Acquire locks the manager and returns the next nonce to be used.
+// Commit must be called once the transaction with the nonce was sent
+// and Release must always be called to unlock the manager.
+func (m *NonceManager) Acquire(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+
+	nonce, err := m.next(ctx)
+	if err != nil {
+		m.mu.Unlock()
+		return 0, err
+	}
+	return nonce, nil
+}
+
+// Commit records that the nonce was used.
+func (m *NonceManager) Commit(nonce uint64) error {
+	return m.store.Put(m.key(), nonce+1)
+}
+
+// Release unlocks the manager.
+func (m *NonceManager) Release() {
+	m.mu.Unlock()
+}
+
+// Next returns the next nonce which is going to be handed out.
+func (m *NonceManager) Next(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.next(ctx)
+}
+
+func (m *NonceManager) key() string {
+	return fmt.Sprintf("%s%x", noncePrefix, m.sender)
+}
+
+func (m *NonceManager) next(ctx context.Context) (uint64, error) {
+	onchainNonce, err := m.backend.PendingNonceAt(ctx, m.sender)
+	if err != nil {
+		return 0, err
+	}
+
+	var nonce uint64
+	err = m.store.Get(m.key(), &nonce)
+	if err != nil {
+		// If no nonce was found locally used whatever we get from the backend.
+		if errors.Is(err, storage.ErrNotFound) {
+			return onchainNonce, nil
+		}
+		return 0, err
+	}
+
+	// If the nonce onchain is larger than what we have there were external
+	// transactions and we need to update our nonce.
+	if onchainNonce > nonce {
+		return onchainNonce, nil
+	}
+	return nonce, nil
+}
+
+// StuckNonce describes the nonce which prevents the
+// transactions with the higher nonces from being mined.
+type StuckNonce struct {
+	Nonce  uint64
+	TxHash common.Hash // pending transaction with the nonce, zero if the nonce was never sent
+	Since  time.Time   // creation time of the pending transaction
+}
+
+// NonceStatus is the state of the nonces of the sender.
+type NonceStatus struct {
+	Confirmed uint64      // nonce of the next transaction to be mined
+	Pending   uint64      // next nonce according to the pending pool of the backend
+	Next      uint64      // next nonce to be handed out
+	Stuck     *StuckNonce // nil if no nonce is stuck
+}
+
+// NonceStatus reports the nonces of the sender and detects the stuck one.
+// A nonce is stuck if it is the lowest one not yet confirmed while the
+// higher nonces were already handed out and either there is no transaction
+// with it in the pending pool, which leaves a gap, or its pending transaction
+// has not been mined for longer than the StuckNonceTimeout.
+func (t *transactionService) NonceStatus(ctx context.Context) (*NonceStatus, error) {
+	confirmed, err := t.backend.NonceAt(ctx, t.sender, nil)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := t.backend.PendingNonceAt(ctx, t.sender)
+	if err != nil {
+		return nil, err
+	}
+	next, err := t.nonces.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &NonceStatus{
+		Confirmed: confirmed,
+		Pending:   pending,
+		Next:      next,
+	}
+	if next <= confirmed {
+		return status, nil
+	}
+
+	txHashes, err := t.PendingTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		txHash  common.Hash
+		created int64
+	)
+	for _, hash := range txHashes {
+		storedTransaction, err := t.StoredTransaction(hash)
+		if err != nil {
+			return nil, err
+		}
+		// Cancellations share the nonce of the original
+		// transaction, the most recent one is reported.
+		if storedTransaction.Nonce == confirmed && storedTransaction.Created >= created {
+			txHash, created = hash, storedTransaction.Created
+		}
+	}
+
+	switch {
+	case txHash == (common.Hash{}) && pending <= confirmed:
+		status.Stuck = &StuckNonce{Nonce: confirmed}
+	case txHash != (common.Hash{}) && time.Since(time.Unix(created, 0)) > StuckNonceTimeout:
+		status.Stuck = &StuckNonce{Nonce: confirmed, TxHash: txHash, Since: time.Unix(created, 0)}
+	}
+
+	return status, nil
+}
+
+// ResolveStuckNonce unblocks the stuck nonce by sending a zero-transfer
+// transaction to the sender with it. The pending transaction with the nonce
+// is replaced by paying the higher fee, as with the CancelTransaction.
+func (t *transactionService) ResolveStuckNonce(ctx context.Context) (common.Hash, error) {
+	status, err := t.NonceStatus(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if status.Stuck == nil {
+		return common.Hash{}, ErrNoStuckNonce
+	}
+
+	if status.Stuck.TxHash != (common.Hash{}) {
+		return t.CancelTransaction(ctx, status.Stuck.TxHash)
+	}
+
+	gasFeeCap, gasTipCap, err := t.suggestedFeeAndTip(ctx, sctx.GetGasPrice(ctx), DefaultTipBoostPercent)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	t.logger.Warning("filling nonce gap", "nonce", status.Stuck.Nonce)
+
+	return t.sendZeroTransfer(status.Stuck.Nonce, gasFeeCap, gasTipCap, DefaultTipBoostPercent, "nonce gap fill")
+}
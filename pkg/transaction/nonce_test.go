@@ -0,0 +1,258 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/sctx"
+	storemock "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/transaction"
+	"github.com/ethersphere/bee/pkg/transaction/backendmock"
+	"github.com/ethersphere/bee/pkg/transaction/monitormock"
+	"github.com/ethersphere/bee/pkg/util/testutil"
+)
+
+func newNonceTestService(t *testing.T, store storage.StateStorer, sender common.Address, confirmed, pending uint64, opts ...backendmock.Option) transaction.Service {
+	t.Helper()
+
+	opts = append(opts,
+		backendmock.WithNonceAtFunc(func(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+			if account != sender {
+				t.Fatalf("nonce requested for wrong account. wanted %x, got %x", sender, account)
+			}
+			if blockNumber != nil {
+				t.Fatalf("nonce requested at block %d, wanted latest", blockNumber)
+			}
+			return confirmed, nil
+		}),
+		backendmock.WithPendingNonceAtFunc(func(ctx context.Context, account common.Address) (uint64, error) {
+			return pending, nil
+		}),
+	)
+
+	signedTx := types.NewTx(&types.DynamicFeeTx{})
+	transactionService, err := transaction.NewService(log.Noop,
+		backendmock.New(opts...),
+		signerMockForTransaction(t, signedTx, sender, big.NewInt(5)),
+		store,
+		big.NewInt(5),
+		monitormock.New(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CleanupCloser(t, transactionService)
+	return transactionService
+}
+
+func putPendingTransaction(t *testing.T, store storage.StateStorer, txHash common.Hash, nonce uint64, created time.Time) {
+	t.Helper()
+
+	err := store.Put(transaction.StoredTransactionKey(txHash), transaction.StoredTransaction{
+		Nonce:     nonce,
+		GasFeeCap: big.NewInt(1100),
+		GasTipCap: big.NewInt(100),
+		Created:   created.Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Put(transaction.PendingTransactionKey(txHash), struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNonceStatus(t *testing.T) {
+	t.Parallel()
+
+	sender := common.HexToAddress("0xddff")
+	txHash := common.HexToHash("0xabcd")
+
+	for _, tc := range []struct {
+		name      string
+		confirmed uint64
+		pending   uint64
+		stored    uint64
+		txNonce   uint64
+		txCreated time.Time
+		want      *transaction.StuckNonce
+	}{
+		{
+			name:      "all confirmed",
+			confirmed: 5,
+			pending:   5,
+			stored:    5,
+		},
+		{
+			name:      "nonce gap",
+			confirmed: 5,
+			pending:   5,
+			stored:    7,
+			want:      &transaction.StuckNonce{Nonce: 5},
+		},
+		{
+			name:      "recent pending transaction",
+			confirmed: 5,
+			pending:   6,
+			stored:    6,
+			txNonce:   5,
+			txCreated: time.Now(),
+		},
+		{
+			name:      "stuck pending transaction",
+			confirmed: 5,
+			pending:   6,
+			stored:    6,
+			txNonce:   5,
+			txCreated: time.Now().Add(-2 * transaction.StuckNonceTimeout),
+			want:      &transaction.StuckNonce{Nonce: 5, TxHash: txHash},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := storemock.NewStateStore()
+			testutil.CleanupCloser(t, store)
+
+			err := store.Put(nonceKey(sender), tc.stored)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			transactionService := newNonceTestService(t, store, sender, tc.confirmed, tc.pending)
+
+			if !tc.txCreated.IsZero() {
+				putPendingTransaction(t, store, txHash, tc.txNonce, tc.txCreated)
+			}
+
+			status, err := transactionService.NonceStatus(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if status.Confirmed != tc.confirmed || status.Pending != tc.pending || status.Next != tc.stored {
+				t.Fatalf("got nonces %d/%d/%d, want %d/%d/%d", status.Confirmed, status.Pending, status.Next, tc.confirmed, tc.pending, tc.stored)
+			}
+			switch {
+			case tc.want == nil && status.Stuck != nil:
+				t.Fatalf("got stuck nonce %+v, want none", status.Stuck)
+			case tc.want != nil && status.Stuck == nil:
+				t.Fatalf("got no stuck nonce, want %+v", tc.want)
+			case tc.want != nil && (status.Stuck.Nonce != tc.want.Nonce || status.Stuck.TxHash != tc.want.TxHash):
+				t.Fatalf("got stuck nonce %+v, want %+v", status.Stuck, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveStuckNonce(t *testing.T) {
+	t.Parallel()
+
+	sender := common.HexToAddress("0xddff")
+	chainID := big.NewInt(5)
+	gasPrice := big.NewInt(1000)
+	gasTip := big.NewInt(100)
+
+	t.Run("nonce gap", func(t *testing.T) {
+		t.Parallel()
+
+		store := storemock.NewStateStore()
+		testutil.CleanupCloser(t, store)
+
+		err := store.Put(nonceKey(sender), uint64(7))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gasTipCap := new(big.Int).Div(new(big.Int).Mul(big.NewInt(int64(transaction.DefaultTipBoostPercent)+100), gasTip), big.NewInt(100))
+		fillTx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     5,
+			To:        &sender,
+			Value:     big.NewInt(0),
+			Gas:       21000,
+			GasTipCap: gasTipCap,
+			GasFeeCap: new(big.Int).Add(gasPrice, gasTipCap),
+			Data:      []byte{},
+		})
+
+		sent := false
+		transactionService, err := transaction.NewService(log.Noop,
+			backendmock.New(
+				backendmock.WithNonceAtFunc(func(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+					return 5, nil
+				}),
+				backendmock.WithPendingNonceAtFunc(func(ctx context.Context, account common.Address) (uint64, error) {
+					return 5, nil
+				}),
+				backendmock.WithSuggestGasPriceFunc(func(ctx context.Context) (*big.Int, error) {
+					return gasPrice, nil
+				}),
+				backendmock.WithSuggestGasTipCapFunc(func(ctx context.Context) (*big.Int, error) {
+					return gasTip, nil
+				}),
+				backendmock.WithSendTransactionFunc(func(ctx context.Context, tx *types.Transaction) error {
+					if tx != fillTx {
+						t.Fatal("not sending signed transaction")
+					}
+					sent = true
+					return nil
+				}),
+			),
+			signerMockForTransaction(t, fillTx, sender, chainID),
+			store,
+			chainID,
+			monitormock.New(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.CleanupCloser(t, transactionService)
+
+		txHash, err := transactionService.ResolveStuckNonce(sctx.SetGasPrice(context.Background(), gasPrice))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sent {
+			t.Fatal("gap filling transaction not sent")
+		}
+		if txHash != fillTx.Hash() {
+			t.Fatalf("returned wrong hash. wanted %v, got %v", fillTx.Hash(), txHash)
+		}
+
+		storedTransaction, err := transactionService.StoredTransaction(txHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if storedTransaction.Nonce != 5 {
+			t.Fatalf("got stored nonce %d, want 5", storedTransaction.Nonce)
+		}
+	})
+
+	t.Run("nothing stuck", func(t *testing.T) {
+		t.Parallel()
+
+		store := storemock.NewStateStore()
+		testutil.CleanupCloser(t, store)
+
+		transactionService := newNonceTestService(t, store, sender, 5, 5)
+
+		_, err := transactionService.ResolveStuckNonce(context.Background())
+		if !errors.Is(err, transaction.ErrNoStuckNonce) {
+			t.Fatalf("got error %v, want %v", err, transaction.ErrNoStuckNonce)
+		}
+	})
+}
@@ -94,6 +94,10 @@ type Service interface {
 	CancelTransaction(ctx context.Context, originalTxHash common.Hash) (common.Hash, error)
 	// TransactionFee retrieves the transaction fee
 	TransactionFee(ctx context.Context, txHash common.Hash) (*big.Int, error)
+	// NonceStatus reports the nonces of the sender and the stuck one, if any
+	NonceStatus(ctx context.Context) (*NonceStatus, error)
+	// ResolveStuckNonce unblocks the stuck nonce with a zero-transfer transaction
+	ResolveStuckNonce(ctx context.Context) (common.Hash, error)
 }
 
 type transactionService struct {
@@ -109,6 +113,7 @@ type transactionService struct {
 	store   storage.StateStorer
 	chainID *big.Int
 	monitor Monitor
+	nonces  *NonceManager
 }
 
 // NewService creates a new transaction service.
@@ -130,6 +135,7 @@ func NewService(logger log.Logger, backend Backend, signer crypto.Signer, store
 		store:   store,
 		chainID: chainID,
 		monitor: monitor,
+		nonces:  NewNonceManager(backend, store, senderAddress),
 	}
 
 	err = t.waitForAllPendingTx()
@@ -162,10 +168,11 @@ func (t *transactionService) Send(ctx context.Context, request *TxRequest, boost
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	nonce, err := t.nextNonce(ctx)
+	nonce, err := t.nonces.Acquire(ctx)
 	if err != nil {
 		return common.Hash{}, err
 	}
+	defer t.nonces.Release()
 
 	tx, err := t.prepareTransaction(ctx, request, nonce, boostPercent)
 	if err != nil {
@@ -184,7 +191,7 @@ func (t *transactionService) Send(ctx context.Context, request *TxRequest, boost
 		return common.Hash{}, err
 	}
 
-	err = t.putNonce(nonce + 1)
+	err = t.nonces.Commit(nonce)
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -347,10 +354,6 @@ func (t *transactionService) suggestedFeeAndTip(ctx context.Context, gasPrice *b
 
 }
 
-func (t *transactionService) nonceKey() string {
-	return fmt.Sprintf("%s%x", noncePrefix, t.sender)
-}
-
 func storedTransactionKey(txHash common.Hash) string {
 	return fmt.Sprintf("%s%x", storedTransactionPrefix, txHash)
 }
@@ -359,34 +362,6 @@ func pendingTransactionKey(txHash common.Hash) string {
 	return fmt.Sprintf("%s%x", pendingTransactionPrefix, txHash)
 }
 
-func (t *transactionService) nextNonce(ctx context.Context) (uint64, error) {
-	onchainNonce, err := t.backend.PendingNonceAt(ctx, t.sender)
-	if err != nil {
-		return 0, err
-	}
-
-	var nonce uint64
-	err = t.store.Get(t.nonceKey(), &nonce)
-	if err != nil {
-		// If no nonce was found locally used whatever we get from the backend.
-		if errors.Is(err, storage.ErrNotFound) {
-			return onchainNonce, nil
-		}
-		return 0, err
-	}
-
-	// If the nonce onchain is larger than what we have there were external
-	// transactions and we need to update our nonce.
-	if onchainNonce > nonce {
-		return onchainNonce, nil
-	}
-	return nonce, nil
-}
-
-func (t *transactionService) putNonce(nonce uint64) error {
-	return t.store.Put(t.nonceKey(), nonce)
-}
-
 // WaitForReceipt waits until either the transaction with the given hash has
 // been mined or the context is cancelled.
 func (t *transactionService) WaitForReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
@@ -528,8 +503,14 @@ func (t *transactionService) CancelTransaction(ctx context.Context, originalTxHa
 
 	gasFeeCap.Add(gasFeeCap, gasTipCap)
 
+	return t.sendZeroTransfer(storedTransaction.Nonce, gasFeeCap, gasTipCap, storedTransaction.GasTipBoost, fmt.Sprintf("%s (cancellation)", storedTransaction.Description))
+}
+
+// sendZeroTransfer sends a zero-transfer transaction to the sender with
+// the given nonce and registers it as pending.
+func (t *transactionService) sendZeroTransfer(nonce uint64, gasFeeCap, gasTipCap *big.Int, boostPercent int, description string) (common.Hash, error) {
 	signedTx, err := t.signer.SignTx(types.NewTx(&types.DynamicFeeTx{
-		Nonce:     storedTransaction.Nonce,
+		Nonce:     nonce,
 		ChainID:   t.chainID,
 		To:        &t.sender,
 		Value:     big.NewInt(0),
@@ -554,12 +535,12 @@ func (t *transactionService) CancelTransaction(ctx context.Context, originalTxHa
 		GasPrice:    signedTx.GasPrice(),
 		GasLimit:    signedTx.Gas(),
 		GasFeeCap:   signedTx.GasFeeCap(),
-		GasTipBoost: storedTransaction.GasTipBoost,
+		GasTipBoost: boostPercent,
 		GasTipCap:   signedTx.GasTipCap(),
 		Value:       signedTx.Value(),
 		Nonce:       signedTx.Nonce(),
 		Created:     time.Now().Unix(),
-		Description: fmt.Sprintf("%s (cancellation)", storedTransaction.Description),
+		Description: description,
 	})
 	if err != nil {
 		return common.Hash{}, err
@@ -22,10 +22,23 @@ import (
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
+var (
+	// ErrNotManifest is returned if the part of the manifest
+	// is traversed, but the address is not a manifest.
+	ErrNotManifest = errors.New("traversal: not a manifest")
+	// ErrPrefixNotFound is returned if the manifest
+	// has no entries with the traversed prefix.
+	ErrPrefixNotFound = errors.New("traversal: prefix not found")
+)
+
 // Traverser represents service which traverse through address dependent chunks.
 type Traverser interface {
 	// Traverse iterates through each address related to the supplied one, if possible.
 	Traverse(context.Context, swarm.Address, swarm.AddressIterFunc) error
+	// TraversePrefix iterates through each address related to the entries
+	// of the manifest with the paths starting with the prefix, including
+	// the addresses of the manifest chunks needed to reach the entries.
+	TraversePrefix(context.Context, swarm.Address, string, swarm.AddressIterFunc) error
 }
 
 type PutGetter interface {
@@ -46,15 +59,7 @@ type service struct {
 // Traverse implements Traverser.Traverse method.
 func (s *service) Traverse(ctx context.Context, addr swarm.Address, iterFn swarm.AddressIterFunc) error {
 	processBytes := func(ref swarm.Address) error {
-		j, _, err := joiner.New(ctx, s.store, ref)
-		if err != nil {
-			return fmt.Errorf("traversal: joiner error on %q: %w", ref, err)
-		}
-		err = j.IterateChunkAddresses(iterFn)
-		if err != nil {
-			return fmt.Errorf("traversal: iterate chunk address error for %q: %w", ref, err)
-		}
-		return nil
+		return s.processBytes(ctx, ref, iterFn)
 	}
 
 	ch, err := s.store.Get(ctx, storage.ModeGetRequest, addr)
@@ -91,3 +96,46 @@ func (s *service) Traverse(ctx context.Context, addr swarm.Address, iterFn swarm
 	}
 	return nil
 }
+
+// TraversePrefix implements Traverser.TraversePrefix method.
+func (s *service) TraversePrefix(ctx context.Context, addr swarm.Address, prefix string, iterFn swarm.AddressIterFunc) error {
+	processBytes := func(ref swarm.Address) error {
+		return s.processBytes(ctx, ref, iterFn)
+	}
+
+	ls := loadsave.NewReadonly(s.store)
+	mf, err := manifest.NewDefaultManifestReference(addr, ls)
+	if err != nil {
+		return fmt.Errorf("traversal: unable to create manifest reference for %q: %w", addr, err)
+	}
+	pi, ok := mf.(manifest.PrefixIterator)
+	if !ok {
+		return ErrNotManifest
+	}
+	switch has, err := mf.HasPrefix(ctx, prefix); {
+	case errors.Is(err, mantaray.ErrTooShort) || errors.Is(err, mantaray.ErrInvalidVersionHash):
+		return ErrNotManifest
+	case err != nil:
+		return fmt.Errorf("traversal: unable to look up prefix %q of %q: %w", prefix, addr, err)
+	case !has:
+		return ErrPrefixNotFound
+	}
+
+	if err := pi.IteratePrefixAddresses(ctx, prefix, processBytes); err != nil {
+		return fmt.Errorf("traversal: unable to process prefix %q of %q: %w", prefix, addr, err)
+	}
+	return nil
+}
+
+// processBytes iterates through the addresses of the chunks of the file.
+func (s *service) processBytes(ctx context.Context, ref swarm.Address, iterFn swarm.AddressIterFunc) error {
+	j, _, err := joiner.New(ctx, s.store, ref)
+	if err != nil {
+		return fmt.Errorf("traversal: joiner error on %q: %w", ref, err)
+	}
+	err = j.IterateChunkAddresses(iterFn)
+	if err != nil {
+		return fmt.Errorf("traversal: iterate chunk address error for %q: %w", ref, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package registry keeps the factories of the components which are compiled
+// into the node and selected by the name in the configuration, such as the
+// storage backends of the shed and the topology drivers. The packages of the
+// components register their factories on init.
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry is the set of the factories registered under their names.
+type Registry[F any] struct {
+	kind string // the kind of the components, in the panic messages
+
+	mu        sync.RWMutex
+	factories map[string]F
+}
+
+// New returns the empty registry of the kind of the components.
+func New[F any](kind string) *Registry[F] {
+	return &Registry[F]{
+		kind:      kind,
+		factories: make(map[string]F),
+	}
+}
+
+// Register registers the factory under the name.
+// It panics if the name is already registered, as that is a build error.
+func (r *Registry[F]) Register(name string, f F) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.factories[name]; ok {
+		panic(fmt.Sprintf("%s %q already registered", r.kind, name))
+	}
+	r.factories[name] = f
+}
+
+// Get returns the factory registered under the name.
+func (r *Registry[F]) Get(name string) (F, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, ok := r.factories[name]
+	return f, ok
+}
+
+// Names returns the sorted names of the registered factories.
+func (r *Registry[F]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
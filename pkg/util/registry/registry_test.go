@@ -0,0 +1,38 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/util/registry"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := registry.New[func() int]("test factory")
+	r.Register("b", func() int { return 2 })
+	r.Register("a", func() int { return 1 })
+
+	f, ok := r.Get("b")
+	if !ok || f() != 2 {
+		t.Fatal("registered factory not found")
+	}
+	if _, ok := r.Get("c"); ok {
+		t.Fatal("unregistered factory found")
+	}
+	if got, want := r.Names(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got names %v, want %v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("duplicate registration did not panic")
+		}
+	}()
+	r.Register("a", nil)
+}
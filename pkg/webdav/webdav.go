@@ -0,0 +1,570 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package webdav exposes the mantaray manifests as WebDAV file systems,
+// so that the Swarm collections can be mounted in the file managers.
+// The file system is read-only unless the changes can be committed,
+// in which case every change produces a new version of the manifest.
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/manifest/feedwriter"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	dav "golang.org/x/net/webdav"
+)
+
+const separator = "/"
+
+// CommitFunc stores the root manifest of the changed file system.
+type CommitFunc = feedwriter.CommitFunc
+
+// Options are the options of the FileSystem.
+type Options struct {
+	// Options of the manifest, the file system
+	// is read-only if the Storer is nil.
+	feedwriter.Options
+	// ModTime is the modification time reported for all files.
+	ModTime time.Time
+}
+
+var _ dav.FileSystem = (*FileSystem)(nil)
+
+// FileSystem is the WebDAV file system of the manifest.
+// The directories of the manifest are the path prefixes ending with the
+// path separator, the empty directories are kept as the entries with the
+// zero reference on the path of the directory.
+type FileSystem struct {
+	storer  storage.Storer
+	w       *feedwriter.Writer
+	modTime time.Time
+
+	dirs map[string]map[string]bool // children of the directories, true for subdirectories
+}
+
+// New returns the file system of the manifest with the root reference.
+func New(storer storage.Storer, root swarm.Address, o Options) *FileSystem {
+	return &FileSystem{
+		storer:  storer,
+		w:       feedwriter.New(storer, root, o.Options),
+		modTime: o.ModTime,
+	}
+}
+
+// Root returns the reference of the current root manifest.
+func (fs *FileSystem) Root() swarm.Address {
+	return fs.w.Root()
+}
+
+// manifestPath converts the WebDAV name into the manifest path.
+func manifestPath(name string) string {
+	return strings.TrimPrefix(path.Clean(separator+name), separator)
+}
+
+// index lists the directories of the manifest.
+func (fs *FileSystem) index(ctx context.Context) (map[string]map[string]bool, error) {
+	if fs.dirs != nil {
+		return fs.dirs, nil
+	}
+
+	m, err := fs.w.Manifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w, ok := m.(manifest.Walker)
+	if !ok {
+		return nil, fmt.Errorf("manifest type %s can not be listed", m.Type())
+	}
+
+	dirs := map[string]map[string]bool{"": {}}
+	err = w.Walk(ctx, func(p string, isDir bool) error {
+		if p == "" || p == manifest.RootPath {
+			return nil
+		}
+		p = strings.TrimSuffix(p, separator)
+		if isDir && dirs[p] == nil {
+			dirs[p] = make(map[string]bool)
+		}
+		parent := path.Dir(p)
+		if parent == "." {
+			parent = ""
+		}
+		if dirs[parent] == nil {
+			dirs[parent] = make(map[string]bool)
+		}
+		dirs[parent][path.Base(p)] = isDir
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	fs.dirs = dirs
+	return dirs, nil
+}
+
+// stat returns the info of the file or the directory on the manifest path.
+func (fs *FileSystem) stat(ctx context.Context, p string) (*fileInfo, error) {
+	dirs, err := fs.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := dirs[p]; ok {
+		return &fileInfo{name: path.Base(separator + p), dir: true, modTime: fs.modTime, writable: fs.w.Writable()}, nil
+	}
+
+	m, err := fs.w.Manifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e, err := m.Lookup(ctx, p)
+	if errors.Is(err, manifest.ErrNotFound) {
+		return nil, os.ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, size, err := joiner.New(ctx, fs.storer, e.Reference())
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{
+		name:        path.Base(p),
+		size:        size,
+		modTime:     fs.modTime,
+		writable:    fs.w.Writable(),
+		reference:   e.Reference(),
+		contentType: e.Metadata()[manifest.EntryMetadataContentTypeKey],
+	}, nil
+}
+
+// Stat implements the webdav.FileSystem interface.
+func (fs *FileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	return fs.stat(ctx, manifestPath(name))
+}
+
+// OpenFile implements the webdav.FileSystem interface.
+func (fs *FileSystem) OpenFile(ctx context.Context, name string, flag int, _ os.FileMode) (dav.File, error) {
+	p := manifestPath(name)
+
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		if !fs.w.Writable() {
+			return nil, os.ErrPermission
+		}
+		info, err := fs.stat(ctx, p)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			if flag&os.O_CREATE == 0 {
+				return nil, os.ErrNotExist
+			}
+			if err := fs.checkParent(ctx, p); err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, err
+		case info.dir:
+			return nil, os.ErrInvalid
+		case flag&os.O_EXCL != 0:
+			return nil, os.ErrExist
+		case flag&os.O_TRUNC == 0:
+			// the files are rewritten as a whole
+			return nil, os.ErrPermission
+		}
+		return &writeFile{ctx: ctx, fs: fs, path: p}, nil
+	}
+
+	info, err := fs.stat(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	f := &readFile{ctx: ctx, fs: fs, path: p, info: info}
+	if !info.dir {
+		if f.reader, _, err = joiner.New(ctx, fs.storer, info.reference); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// checkParent returns an error if the parent directory of the path does not exist.
+func (fs *FileSystem) checkParent(ctx context.Context, p string) error {
+	parent := path.Dir(p)
+	if parent == "." {
+		return nil
+	}
+	info, err := fs.stat(ctx, parent)
+	if err != nil {
+		return err
+	}
+	if !info.dir {
+		return os.ErrInvalid
+	}
+	return nil
+}
+
+// Mkdir implements the webdav.FileSystem interface.
+func (fs *FileSystem) Mkdir(ctx context.Context, name string, _ os.FileMode) error {
+	if !fs.w.Writable() {
+		return os.ErrPermission
+	}
+	p := manifestPath(name)
+	if _, err := fs.stat(ctx, p); err == nil {
+		return os.ErrExist
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := fs.checkParent(ctx, p); err != nil {
+		return err
+	}
+	return fs.update(ctx, func(m manifest.Interface) error {
+		return m.Add(ctx, p+separator, manifest.NewEntry(swarm.ZeroAddress, map[string]string{
+			manifest.EntryMetadataFilenameKey: path.Base(p),
+		}))
+	})
+}
+
+// RemoveAll implements the webdav.FileSystem interface.
+func (fs *FileSystem) RemoveAll(ctx context.Context, name string) error {
+	if !fs.w.Writable() {
+		return os.ErrPermission
+	}
+	p := manifestPath(name)
+	if p == "" {
+		return os.ErrPermission
+	}
+	paths, err := fs.paths(ctx, p)
+	if err != nil {
+		return err
+	}
+	return fs.update(ctx, func(m manifest.Interface) error {
+		return fs.remove(ctx, m, paths)
+	})
+}
+
+// Rename implements the webdav.FileSystem interface.
+func (fs *FileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	if !fs.w.Writable() {
+		return os.ErrPermission
+	}
+	oldPath, newPath := manifestPath(oldName), manifestPath(newName)
+	if oldPath == "" || newPath == "" || strings.HasPrefix(newPath+separator, oldPath+separator) {
+		return os.ErrInvalid
+	}
+	if _, err := fs.stat(ctx, newPath); err == nil {
+		return os.ErrExist
+	}
+	if err := fs.checkParent(ctx, newPath); err != nil {
+		return err
+	}
+	paths, err := fs.paths(ctx, oldPath)
+	if err != nil {
+		return err
+	}
+	return fs.update(ctx, func(m manifest.Interface) error {
+		for _, p := range paths {
+			e, err := m.Lookup(ctx, p)
+			if errors.Is(err, manifest.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := m.Add(ctx, newPath+strings.TrimPrefix(p, oldPath), e); err != nil {
+				return err
+			}
+		}
+		return fs.remove(ctx, m, paths)
+	})
+}
+
+// remove removes the paths from the manifest. The manifest removes the
+// whole subtree of the node matching the path, so the other entries
+// the removed paths are the prefixes of are restored afterwards.
+func (fs *FileSystem) remove(ctx context.Context, m manifest.Interface, paths []string) error {
+	dirs, err := fs.index(ctx)
+	if err != nil {
+		return err
+	}
+	removed := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		removed[p] = true
+	}
+
+	kept := make(map[string]manifest.Entry)
+	for dir, children := range dirs {
+		for name, isDir := range children {
+			q := strings.TrimPrefix(dir+separator+name, separator)
+			if isDir {
+				q += separator
+			}
+			if removed[q] {
+				continue
+			}
+			for _, p := range paths {
+				if !strings.HasPrefix(q, p) {
+					continue
+				}
+				switch e, err := m.Lookup(ctx, q); {
+				case err == nil:
+					kept[q] = e
+				case !errors.Is(err, manifest.ErrNotFound):
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	for _, p := range paths {
+		if err := m.Remove(ctx, p); err != nil && !errors.Is(err, manifest.ErrNotFound) {
+			return err
+		}
+	}
+	for q, e := range kept {
+		switch _, err := m.Lookup(ctx, q); {
+		case errors.Is(err, manifest.ErrNotFound):
+			if err := m.Add(ctx, q, e); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+	}
+	return nil
+}
+
+// paths returns the manifest paths of the file or of the directory with
+// all its descendants, including the entries of the empty directories.
+func (fs *FileSystem) paths(ctx context.Context, p string) ([]string, error) {
+	info, err := fs.stat(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if !info.dir {
+		return []string{p}, nil
+	}
+
+	dirs, err := fs.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		paths []string
+		walk  func(dir string)
+	)
+	walk = func(dir string) {
+		paths = append(paths, dir+separator)
+		for name, isDir := range dirs[dir] {
+			if isDir {
+				walk(dir + separator + name)
+			} else {
+				paths = append(paths, dir+separator+name)
+			}
+		}
+	}
+	walk(p)
+	return paths, nil
+}
+
+// update changes the manifest and commits its new root.
+func (fs *FileSystem) update(ctx context.Context, fn func(manifest.Interface) error) error {
+	if err := fs.w.Update(ctx, fn); err != nil {
+		return err
+	}
+	fs.dirs = nil
+	return nil
+}
+
+// put stores the file and adds it to the manifest.
+func (fs *FileSystem) put(ctx context.Context, p string, r io.Reader) error {
+	pipe := builder.NewPipelineBuilder(ctx, fs.w.Storer(), fs.w.Mode(), false)
+	ref, err := builder.FeedPipeline(ctx, pipe, r)
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{
+		manifest.EntryMetadataFilenameKey: path.Base(p),
+	}
+	if ct := mime.TypeByExtension(path.Ext(p)); ct != "" {
+		metadata[manifest.EntryMetadataContentTypeKey] = ct
+	}
+	return fs.update(ctx, func(m manifest.Interface) error {
+		return m.Add(ctx, p, manifest.NewEntry(ref, metadata))
+	})
+}
+
+var (
+	_ dav.ContentTyper = (*fileInfo)(nil)
+	_ dav.ETager       = (*fileInfo)(nil)
+)
+
+type fileInfo struct {
+	name        string
+	size        int64
+	dir         bool
+	writable    bool
+	modTime     time.Time
+	reference   swarm.Address
+	contentType string
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.dir }
+func (fi *fileInfo) Sys() interface{}   { return nil }
+
+func (fi *fileInfo) Mode() os.FileMode {
+	mode := os.FileMode(0444)
+	if fi.writable {
+		mode |= 0200
+	}
+	if fi.dir {
+		mode |= os.ModeDir | 0111
+	}
+	return mode
+}
+
+// ContentType returns the content type of the file from the manifest metadata.
+func (fi *fileInfo) ContentType(context.Context) (string, error) {
+	if fi.contentType != "" {
+		return fi.contentType, nil
+	}
+	if ct := mime.TypeByExtension(path.Ext(fi.name)); ct != "" {
+		return ct, nil
+	}
+	return "", dav.ErrNotImplemented
+}
+
+// ETag returns the reference of the file, which changes with its content.
+func (fi *fileInfo) ETag(context.Context) (string, error) {
+	if fi.reference.IsZero() {
+		return "", dav.ErrNotImplemented
+	}
+	return fmt.Sprintf("%q", fi.reference), nil
+}
+
+// readFile is the opened file or directory of the manifest.
+type readFile struct {
+	ctx    context.Context
+	fs     *FileSystem
+	path   string
+	info   *fileInfo
+	reader file.Joiner
+	listed bool
+}
+
+func (f *readFile) Read(p []byte) (int, error) {
+	if f.info.dir {
+		return 0, os.ErrInvalid
+	}
+	return f.reader.Read(p)
+}
+
+func (f *readFile) Seek(offset int64, whence int) (int64, error) {
+	if f.info.dir {
+		return 0, os.ErrInvalid
+	}
+	return f.reader.Seek(offset, whence)
+}
+
+func (f *readFile) Write([]byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+func (f *readFile) Stat() (os.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *readFile) Close() error {
+	return nil
+}
+
+// Readdir returns the whole directory listing on the first call.
+func (f *readFile) Readdir(int) ([]os.FileInfo, error) {
+	if !f.info.dir {
+		return nil, os.ErrInvalid
+	}
+	if f.listed {
+		return nil, io.EOF
+	}
+	f.listed = true
+
+	dirs, err := f.fs.index(f.ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(dirs[f.path]))
+	for name := range dirs[f.path] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	infos := make([]os.FileInfo, 0, len(names))
+	for _, name := range names {
+		info, err := f.fs.stat(f.ctx, strings.TrimPrefix(f.path+separator+name, separator))
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// writeFile buffers the written content and stores
+// the file in the manifest when it is closed.
+type writeFile struct {
+	ctx  context.Context
+	fs   *FileSystem
+	path string
+	buf  bytes.Buffer
+}
+
+func (f *writeFile) Write(p []byte) (int, error) {
+	return f.buf.Write(p)
+}
+
+func (f *writeFile) Read([]byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+func (f *writeFile) Seek(offset int64, whence int) (int64, error) {
+	if offset == 0 && (whence == io.SeekCurrent || whence == io.SeekEnd) {
+		return int64(f.buf.Len()), nil
+	}
+	return 0, os.ErrInvalid
+}
+
+func (f *writeFile) Readdir(int) ([]os.FileInfo, error) {
+	return nil, os.ErrInvalid
+}
+
+func (f *writeFile) Stat() (os.FileInfo, error) {
+	return &fileInfo{
+		name:     path.Base(f.path),
+		size:     int64(f.buf.Len()),
+		writable: true,
+		modTime:  f.fs.modTime,
+	}, nil
+}
+
+func (f *writeFile) Close() error {
+	return f.fs.put(f.ctx, f.path, &f.buf)
+}
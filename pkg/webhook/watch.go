@@ -0,0 +1,132 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/postage"
+)
+
+// CheckFunc reports whether the condition of the event holds,
+// together with the data of the event.
+type CheckFunc func(ctx context.Context) (bool, map[string]interface{}, error)
+
+// Watch calls the check in the interval and notifies the event once its
+// condition starts to hold. The event is notified again only after the
+// condition stops holding in between. The failed checks are skipped.
+func (s *Service) Watch(typ EventType, interval time.Duration, check CheckFunc) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-s.quit
+			cancel()
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var holds bool
+		for {
+			select {
+			case <-s.quit:
+				return
+			case <-ticker.C:
+			}
+
+			ok, data, err := check(ctx)
+			if err != nil {
+				s.logger.Debug("webhook check failed", "event", typ, "error", err)
+				continue
+			}
+			if ok && !holds {
+				s.Notify(typ, data)
+			}
+			holds = ok
+		}
+	}()
+}
+
+// BatchEventSubscriber delivers the batch events.
+type BatchEventSubscriber interface {
+	Subscribe() (c <-chan postage.BatchEvent, unsubscribe func())
+}
+
+// WatchBatchEvents notifies the BatchExpiring events of the batches.
+func (s *Service) WatchBatchEvents(sub BatchEventSubscriber) {
+	c, unsubscribe := sub.Subscribe()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-s.quit:
+				return
+			case e, ok := <-c:
+				if !ok {
+					return
+				}
+				if e.Type != postage.BatchExpiring {
+					continue
+				}
+				s.Notify(BatchExpiring, map[string]interface{}{
+					"batchID": hex.EncodeToString(e.BatchID),
+					"blocks":  e.Blocks,
+				})
+			}
+		}
+	}()
+}
+
+// WatchDisconnects notifies the BlocklistedByPeers event once the peers
+// close the connections to the node at least threshold times within the
+// window. The peers disconnect from the nodes they blocklist, so the
+// disconnections by many peers hint that the node is blocklisted.
+// The returned function stops the watching.
+func (s *Service) WatchDisconnects(hooker p2p.Hooker, threshold int, window time.Duration) (stop func()) {
+	var (
+		mu       sync.Mutex
+		times    []time.Time
+		notified time.Time
+	)
+	return hooker.AddHooks(p2p.Hooks{
+		OnDisconnect: func(peer p2p.Peer, reason string) {
+			if reason != p2p.DisconnectedByPeerReason {
+				return
+			}
+
+			mu.Lock()
+			now := time.Now()
+			i := 0
+			for i < len(times) && now.Sub(times[i]) > window {
+				i++
+			}
+			times = append(times[i:], now)
+			notify := len(times) >= threshold && now.Sub(notified) > window
+			if notify {
+				notified = now
+			}
+			count := len(times)
+			mu.Unlock()
+
+			if notify {
+				s.Notify(BlocklistedByPeers, map[string]interface{}{
+					"disconnects": count,
+					"window":      window.String(),
+				})
+			}
+		},
+	})
+}
@@ -0,0 +1,343 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package webhook notifies the operator about the critical events of the
+// node by posting them to the configured webhooks. The requests are signed
+// with the HMAC of the shared secret, so that the receivers can verify them,
+// and the failed deliveries are retried with an increasing delay.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "webhook"
+
+// EventType is the type of the critical event.
+type EventType string
+
+const (
+	ChequebookLowBalance EventType = "chequebook_low_balance"
+	BatchExpiring        EventType = "batch_expiring"
+	ReserveFull          EventType = "reserve_full"
+	ChainDisconnected    EventType = "chain_disconnected"
+	BlocklistedByPeers   EventType = "blocklisted_by_peers"
+)
+
+// EventTypes are the types of all the events.
+var EventTypes = []EventType{
+	ChequebookLowBalance,
+	BatchExpiring,
+	ReserveFull,
+	ChainDisconnected,
+	BlocklistedByPeers,
+}
+
+const (
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of the
+	// request body keyed with the secret, prefixed with "sha256=".
+	SignatureHeader = "Swarm-Webhook-Signature"
+	// EventHeader carries the type of the event.
+	EventHeader = "Swarm-Webhook-Event"
+	// DeliveryHeader carries the ID of the event, which is
+	// the same for all the delivery attempts of the event.
+	DeliveryHeader = "Swarm-Webhook-Delivery"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of the delivery attempts.
+	DefaultMaxAttempts = 5
+	// DefaultRetryDelay is the default delay after the first failed
+	// delivery attempt, it is doubled after every next failed attempt.
+	DefaultRetryDelay = 10 * time.Second
+
+	maxDeliveries  = 100 // number of the latest deliveries kept for the status
+	requestTimeout = 30 * time.Second
+)
+
+// Event is the payload posted to the webhooks.
+type Event struct {
+	ID   string                 `json:"id"`
+	Type EventType              `json:"type"`
+	Time time.Time              `json:"time"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// DeliveryStatus is the status of the delivery of an event.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is the delivery of an event to a webhook.
+type Delivery struct {
+	Event      Event          `json:"event"`
+	URL        string         `json:"url"`
+	Status     DeliveryStatus `json:"status"`
+	Attempts   int            `json:"attempts"`
+	StatusCode int            `json:"statusCode,omitempty"` // status code of the last attempt
+	Error      string         `json:"error,omitempty"`      // error of the last failed attempt
+	Updated    time.Time      `json:"updated"`
+}
+
+// Options are the options of the Service.
+type Options struct {
+	URLs        []string      // webhooks the events are posted to
+	Secret      string        // key of the request signatures, the requests are not signed if empty
+	Events      []EventType   // types of the notified events, all events are notified if empty
+	MaxAttempts int           // delivery attempts of an event
+	RetryDelay  time.Duration // delay after the first failed attempt
+	Client      *http.Client
+}
+
+// Service posts the events to the webhooks.
+type Service struct {
+	urls        []string
+	secret      []byte
+	events      map[EventType]struct{}
+	maxAttempts int
+	retryDelay  time.Duration
+	client      *http.Client
+	logger      log.Logger
+
+	mu         sync.Mutex
+	deliveries []*Delivery // latest deliveries, oldest first
+	closed     bool
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New returns the Service posting the events to the webhooks of the options.
+func New(o Options, logger log.Logger) (*Service, error) {
+	for _, u := range o.URLs {
+		pu, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("parse webhook url: %w", err)
+		}
+		if pu.Scheme != "http" && pu.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported webhook url scheme: %q", pu.Scheme)
+		}
+	}
+
+	events := make(map[EventType]struct{})
+	for _, t := range o.Events {
+		if !validEventType(t) {
+			return nil, fmt.Errorf("unknown webhook event %q", t)
+		}
+		events[t] = struct{}{}
+	}
+	if len(events) == 0 {
+		for _, t := range EventTypes {
+			events[t] = struct{}{}
+		}
+	}
+
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.RetryDelay <= 0 {
+		o.RetryDelay = DefaultRetryDelay
+	}
+	if o.Client == nil {
+		o.Client = &http.Client{Timeout: requestTimeout}
+	}
+
+	return &Service{
+		urls:        o.URLs,
+		secret:      []byte(o.Secret),
+		events:      events,
+		maxAttempts: o.MaxAttempts,
+		retryDelay:  o.RetryDelay,
+		client:      o.Client,
+		logger:      logger.WithName(loggerName).Register(),
+		quit:        make(chan struct{}),
+	}, nil
+}
+
+func validEventType(t EventType) bool {
+	for _, v := range EventTypes {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify posts the event of the type with the data to the webhooks in the
+// background, unless the type is not configured to be notified.
+func (s *Service) Notify(typ EventType, data map[string]interface{}) {
+	if _, ok := s.events[typ]; !ok {
+		return
+	}
+
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	e := Event{
+		ID:   hex.EncodeToString(id),
+		Type: typ,
+		Time: time.Now().UTC(),
+		Data: data,
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		s.logger.Error(err, "marshal webhook event failed", "event", typ)
+		return
+	}
+
+	for _, u := range s.urls {
+		if d := s.add(e, u); d != nil {
+			go s.deliver(d, body)
+		}
+	}
+}
+
+// Deliveries returns the latest deliveries, the newest first.
+func (s *Service) Deliveries() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries := make([]Delivery, 0, len(s.deliveries))
+	for i := len(s.deliveries) - 1; i >= 0; i-- {
+		deliveries = append(deliveries, *s.deliveries[i])
+	}
+	return deliveries
+}
+
+func (s *Service) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
+
+// add records the pending delivery of the event to the webhook.
+// It returns nil if the service is closed.
+func (s *Service) add(e Event, u string) *Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.wg.Add(1)
+
+	d := &Delivery{
+		Event:   e,
+		URL:     u,
+		Status:  DeliveryPending,
+		Updated: time.Now().UTC(),
+	}
+	s.deliveries = append(s.deliveries, d)
+	if len(s.deliveries) > maxDeliveries {
+		s.deliveries = s.deliveries[len(s.deliveries)-maxDeliveries:]
+	}
+	return d
+}
+
+// deliver posts the body to the webhook of the delivery
+// until it succeeds or the attempts are exhausted.
+func (s *Service) deliver(d *Delivery, body []byte) {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	delay := s.retryDelay
+	for attempt := 1; ; attempt++ {
+		code, err := s.post(ctx, d, body)
+
+		s.mu.Lock()
+		d.Attempts = attempt
+		d.StatusCode = code
+		d.Updated = time.Now().UTC()
+		switch {
+		case err == nil:
+			d.Status = DeliveryDelivered
+			d.Error = ""
+		case attempt >= s.maxAttempts || ctx.Err() != nil:
+			d.Status = DeliveryFailed
+			d.Error = err.Error()
+		default:
+			d.Error = err.Error()
+		}
+		status := d.Status
+		s.mu.Unlock()
+
+		if status != DeliveryPending {
+			if status == DeliveryFailed {
+				s.logger.Error(err, "webhook delivery failed", "event", d.Event.Type, "url", d.URL, "attempts", attempt)
+			}
+			return
+		}
+
+		s.logger.Debug("webhook delivery attempt failed", "event", d.Event.Type, "url", d.URL, "attempt", attempt, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+		delay *= 2
+	}
+}
+
+// post posts the body once, it returns the status code of the response.
+func (s *Service) post(ctx context.Context, d *Delivery, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(d.Event.Type))
+	req.Header.Set(DeliveryHeader, d.Event.ID)
+	if len(s.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the value of the SignatureHeader of the body signed with the secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
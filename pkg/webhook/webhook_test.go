@@ -0,0 +1,213 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/spinlock"
+	"github.com/ethersphere/bee/pkg/webhook"
+)
+
+const secret = "secret"
+
+// receiver records the events posted to the webhook. It responds
+// with the status codes in turn, the last one is repeated.
+type receiver struct {
+	t      *testing.T
+	mu     sync.Mutex
+	codes  []int
+	events []webhook.Event
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		rc.t.Error(err)
+		return
+	}
+	if got, want := r.Header.Get(webhook.SignatureHeader), webhook.Sign([]byte(secret), body); got != want {
+		rc.t.Errorf("got signature %q, want %q", got, want)
+	}
+	var e webhook.Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		rc.t.Error(err)
+		return
+	}
+	if got := r.Header.Get(webhook.EventHeader); got != string(e.Type) {
+		rc.t.Errorf("got event header %q, want %q", got, e.Type)
+	}
+	if got := r.Header.Get(webhook.DeliveryHeader); got != e.ID {
+		rc.t.Errorf("got delivery header %q, want %q", got, e.ID)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.events = append(rc.events, e)
+	code := rc.codes[0]
+	if len(rc.codes) > 1 {
+		rc.codes = rc.codes[1:]
+	}
+	w.WriteHeader(code)
+}
+
+func (rc *receiver) received() []webhook.Event {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return append([]webhook.Event(nil), rc.events...)
+}
+
+func newService(t *testing.T, rc *receiver, events ...webhook.EventType) *webhook.Service {
+	t.Helper()
+
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	s, err := webhook.New(webhook.Options{
+		URLs:        []string{server.URL},
+		Secret:      secret,
+		Events:      events,
+		MaxAttempts: 3,
+		RetryDelay:  time.Millisecond,
+	}, log.Noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func waitDelivery(t *testing.T, s *webhook.Service, status webhook.DeliveryStatus) webhook.Delivery {
+	t.Helper()
+
+	var d webhook.Delivery
+	err := spinlock.Wait(5*time.Second, func() bool {
+		deliveries := s.Deliveries()
+		if len(deliveries) == 0 {
+			return false
+		}
+		d = deliveries[0]
+		return d.Status == status
+	})
+	if err != nil {
+		t.Fatalf("delivery not %s: %+v", status, d)
+	}
+	return d
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("delivered", func(t *testing.T) {
+		t.Parallel()
+
+		rc := &receiver{t: t, codes: []int{http.StatusOK}}
+		s := newService(t, rc)
+
+		s.Notify(webhook.ReserveFull, map[string]interface{}{"size": 10})
+
+		d := waitDelivery(t, s, webhook.DeliveryDelivered)
+		if d.Attempts != 1 || d.StatusCode != http.StatusOK {
+			t.Fatalf("got %d attempts with status %d, want 1 with %d", d.Attempts, d.StatusCode, http.StatusOK)
+		}
+		events := rc.received()
+		if len(events) != 1 || events[0].Type != webhook.ReserveFull || events[0].Data["size"] != float64(10) {
+			t.Fatalf("got events %+v", events)
+		}
+	})
+
+	t.Run("retried", func(t *testing.T) {
+		t.Parallel()
+
+		rc := &receiver{t: t, codes: []int{http.StatusInternalServerError, http.StatusOK}}
+		s := newService(t, rc)
+
+		s.Notify(webhook.ChainDisconnected, nil)
+
+		d := waitDelivery(t, s, webhook.DeliveryDelivered)
+		if d.Attempts != 2 {
+			t.Fatalf("got %d attempts, want 2", d.Attempts)
+		}
+		if events := rc.received(); len(events) != 2 || events[0].ID != events[1].ID {
+			t.Fatalf("got events %+v, want the same event twice", events)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		rc := &receiver{t: t, codes: []int{http.StatusInternalServerError}}
+		s := newService(t, rc)
+
+		s.Notify(webhook.ChainDisconnected, nil)
+
+		d := waitDelivery(t, s, webhook.DeliveryFailed)
+		if d.Attempts != 3 || d.StatusCode != http.StatusInternalServerError || d.Error == "" {
+			t.Fatalf("got delivery %+v, want failed after 3 attempts", d)
+		}
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		t.Parallel()
+
+		rc := &receiver{t: t, codes: []int{http.StatusOK}}
+		s := newService(t, rc, webhook.ReserveFull)
+
+		s.Notify(webhook.ChainDisconnected, nil)
+
+		if d := s.Deliveries(); len(d) != 0 {
+			t.Fatalf("got %d deliveries of the filtered event", len(d))
+		}
+	})
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := webhook.New(webhook.Options{URLs: []string{"ftp://example.com"}}, log.Noop); err == nil {
+		t.Fatal("expected error for the unsupported url scheme")
+	}
+	if _, err := webhook.New(webhook.Options{Events: []webhook.EventType{"unknown"}}, log.Noop); err == nil {
+		t.Fatal("expected error for the unknown event")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	rc := &receiver{t: t, codes: []int{http.StatusOK}}
+	s := newService(t, rc)
+
+	var (
+		mu     sync.Mutex
+		checks int
+	)
+	// the condition holds on the checks 1, 2 and 4
+	s.Watch(webhook.ReserveFull, time.Millisecond, func(context.Context) (bool, map[string]interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		checks++
+		return checks == 1 || checks == 2 || checks == 4, nil, nil
+	})
+
+	err := spinlock.Wait(5*time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return checks > 5 && len(rc.received()) == 2
+	})
+	if err != nil {
+		t.Fatalf("got %d events, want 2", len(rc.received()))
+	}
+}
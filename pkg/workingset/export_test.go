@@ -0,0 +1,21 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workingset
+
+import (
+	"context"
+	"time"
+)
+
+func (s *Service) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
+
+func (s *Service) Expire(ctx context.Context) error {
+	return s.expire(ctx)
+}
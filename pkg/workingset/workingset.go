@@ -0,0 +1,314 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package workingset keeps the chunks of the content in the local store
+// exempt from the garbage collection for a limited time. The applications
+// lease their hot dataset and renew the leases while they need it, without
+// the full pinning semantics: the exemption ends when the lease expires.
+package workingset
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/log"
+	"github.com/ethersphere/bee/pkg/pinning"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/hashicorp/go-multierror"
+)
+
+// loggerName is the tree path name of the logger for this package.
+const loggerName = "workingset"
+
+const (
+	// MaxTTL is the maximal time to live of a lease.
+	MaxTTL = 7 * 24 * time.Hour
+	// expireInterval is the period of the checks for the expired leases.
+	expireInterval = time.Minute
+	leaseKeyPrefix = "workingset-lease-"
+)
+
+var (
+	// ErrNotFound is returned when the lease does not exist or has expired.
+	ErrNotFound = errors.New("lease not found")
+	// ErrInvalidTTL is returned when the time to live is out of the (0, MaxTTL] range.
+	ErrInvalidTTL = errors.New("invalid ttl")
+	// ErrNoReferences is returned when the lease has no references.
+	ErrNoReferences = errors.New("no references")
+)
+
+// Lease exempts the chunks of the content under the references from the
+// garbage collection until it expires.
+type Lease struct {
+	ID         string          `json:"id"`
+	References []swarm.Address `json:"references"`
+	ExpiresAt  time.Time       `json:"expiresAt"`
+}
+
+func leaseKey(id string) string {
+	return leaseKeyPrefix + id
+}
+
+// Service leases the chunks and releases them when the leases expire.
+type Service struct {
+	storer     storage.Storer
+	stateStore storage.StateStorer
+	traverser  traversal.Traverser
+	logger     log.Logger
+	now        func() time.Time
+
+	mu   sync.Mutex // serializes the changes of the leases
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New returns a new Service which releases the expired leases in the background.
+func New(storer storage.Storer, stateStore storage.StateStorer, traverser traversal.Traverser, logger log.Logger) *Service {
+	s := &Service{
+		storer:     storer,
+		stateStore: stateStore,
+		traverser:  traverser,
+		logger:     logger.WithName(loggerName).Register(),
+		now:        time.Now,
+		quit:       make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.expireLoop()
+
+	return s
+}
+
+// Lease exempts the chunks of the content under the references from the
+// garbage collection for the ttl. The chunks missing in the local store are
+// retrieved. If any of the content can not be leased, nothing is leased.
+// The chunks are pinned as by the pinning, without the lock, as the
+// retrievals may take long and the pins of the localstore are counted
+// per lease anyway.
+func (s *Service) Lease(ctx context.Context, refs []swarm.Address, ttl time.Duration) (Lease, error) {
+	if len(refs) == 0 {
+		return Lease{}, ErrNoReferences
+	}
+	if ttl <= 0 || ttl > MaxTTL {
+		return Lease{}, ErrInvalidTTL
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Lease{}, fmt.Errorf("lease id: %w", err)
+	}
+
+	pinned := make(map[string][]swarm.Address) // the pinned leaves by the reference
+	for _, ref := range refs {
+		pin := pinning.PinFn(ctx, s.storer, ref)
+		if err := s.traverser.Traverse(ctx, ref, func(leaf swarm.Address) error {
+			if err := pin(leaf); err != nil {
+				return err
+			}
+			pinned[ref.ByteString()] = append(pinned[ref.ByteString()], leaf)
+			return nil
+		}); err != nil {
+			s.unpin(pinned)
+			return Lease{}, fmt.Errorf("lease %s: %w", ref, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := Lease{
+		ID:         hex.EncodeToString(id),
+		References: refs,
+		ExpiresAt:  s.now().Add(ttl),
+	}
+	if err := s.stateStore.Put(leaseKey(l.ID), l); err != nil {
+		s.unpin(pinned)
+		return Lease{}, fmt.Errorf("store lease: %w", err)
+	}
+	return l, nil
+}
+
+// unpin rolls back the pins of the leaves of the lease which failed.
+func (s *Service) unpin(pinned map[string][]swarm.Address) {
+	var unpinErr error
+	for ref, leaves := range pinned {
+		unpin := pinning.UnpinFn(context.Background(), s.storer, swarm.NewAddress([]byte(ref)), &unpinErr)
+		for _, leaf := range leaves {
+			_ = unpin(leaf)
+		}
+	}
+	if unpinErr != nil {
+		s.logger.Error(unpinErr, "unpin chunks of failed lease")
+	}
+}
+
+// Renew extends the lease to expire after the ttl from now.
+func (s *Service) Renew(id string, ttl time.Duration) (Lease, error) {
+	if ttl <= 0 || ttl > MaxTTL {
+		return Lease{}, ErrInvalidTTL
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.get(id)
+	if err != nil {
+		return Lease{}, err
+	}
+	l.ExpiresAt = s.now().Add(ttl)
+	if err := s.stateStore.Put(leaseKey(l.ID), l); err != nil {
+		return Lease{}, fmt.Errorf("store lease: %w", err)
+	}
+	return l, nil
+}
+
+// Release ends the lease before it expires.
+func (s *Service) Release(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	return s.release(ctx, l)
+}
+
+// release unpins the chunks of the lease and deletes it.
+// It must be called with the lock held.
+func (s *Service) release(ctx context.Context, l Lease) error {
+	var unpinErr error
+	for _, ref := range l.References {
+		if err := s.traverser.Traverse(ctx, ref, pinning.UnpinFn(ctx, s.storer, ref, &unpinErr)); err != nil {
+			unpinErr = multierror.Append(unpinErr, fmt.Errorf("traverse %s: %w", ref, err))
+		}
+	}
+	if err := s.stateStore.Delete(leaseKey(l.ID)); err != nil {
+		return fmt.Errorf("delete lease: %w", err)
+	}
+	return unpinErr
+}
+
+// Get returns the lease if it did not expire.
+func (s *Service) Get(id string) (Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.get(id)
+}
+
+func (s *Service) get(id string) (Lease, error) {
+	var l Lease
+	switch err := s.stateStore.Get(leaseKey(id), &l); {
+	case errors.Is(err, storage.ErrNotFound):
+		return Lease{}, ErrNotFound
+	case err != nil:
+		return Lease{}, fmt.Errorf("get lease: %w", err)
+	}
+	if !s.now().Before(l.ExpiresAt) {
+		return Lease{}, ErrNotFound
+	}
+	return l, nil
+}
+
+// Leases returns the leases which did not expire, ordered by their expiry.
+func (s *Service) Leases() ([]Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	leases, err := s.leases()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	active := make([]Lease, 0, len(leases))
+	for _, l := range leases {
+		if now.Before(l.ExpiresAt) {
+			active = append(active, l)
+		}
+	}
+	return active, nil
+}
+
+func (s *Service) leases() ([]Lease, error) {
+	var leases []Lease
+	if err := s.stateStore.Iterate(leaseKeyPrefix, func(_, value []byte) (bool, error) {
+		var l Lease
+		if err := json.Unmarshal(value, &l); err != nil {
+			return true, fmt.Errorf("invalid lease: %w", err)
+		}
+		leases = append(leases, l)
+		return false, nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].ExpiresAt.Before(leases[j].ExpiresAt)
+	})
+	return leases, nil
+}
+
+// expire releases the expired leases, the lease which fails
+// to be released does not hold back the release of the others.
+func (s *Service) expire(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	leases, err := s.leases()
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	for _, l := range leases {
+		if now.Before(l.ExpiresAt) {
+			break
+		}
+		if err := s.release(ctx, l); err != nil {
+			s.logger.Error(err, "release expired lease failed", "lease_id", l.ID)
+		}
+	}
+	return nil
+}
+
+func (s *Service) expireLoop() {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.quit
+		cancel()
+	}()
+
+	ticker := time.NewTicker(expireInterval)
+	defer ticker.Stop()
+
+	for {
+		// the leases which expired while the node was down are released too
+		if err := s.expire(ctx); err != nil {
+			s.logger.Error(err, "release expired leases failed")
+		}
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// Close stops releasing the expired leases.
+func (s *Service) Close() error {
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
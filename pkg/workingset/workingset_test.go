@@ -0,0 +1,382 @@
+// Copyright 2023 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workingset_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/log"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/traversal"
+	"github.com/ethersphere/bee/pkg/workingset"
+)
+
+// hookTraverser calls the hook before each traversal.
+type hookTraverser struct {
+	traversal.Traverser
+	hook func(ref swarm.Address) error
+}
+
+func (t hookTraverser) Traverse(ctx context.Context, ref swarm.Address, fn swarm.AddressIterFunc) error {
+	if err := t.hook(ref); err != nil {
+		return err
+	}
+	return t.Traverser.Traverse(ctx, ref, fn)
+}
+
+// encryptedStorer gets the chunks of the encrypted references, which are
+// passed by the traversal of the encrypted content, by their addresses.
+type encryptedStorer struct {
+	*mock.MockStorer
+}
+
+func (s encryptedStorer) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	if len(addr.Bytes()) == encryption.ReferenceSize {
+		addr = swarm.NewAddress(addr.Bytes()[:swarm.HashSize])
+	}
+	return s.MockStorer.Get(ctx, mode, addr)
+}
+
+// encryptedTraverser reports the leaves as the encrypted references,
+// as the traversal of the encrypted content might.
+type encryptedTraverser struct {
+	traversal.Traverser
+}
+
+func (t encryptedTraverser) Traverse(ctx context.Context, ref swarm.Address, fn swarm.AddressIterFunc) error {
+	return t.Traverser.Traverse(ctx, ref, func(leaf swarm.Address) error {
+		if len(leaf.Bytes()) == swarm.HashSize {
+			ref := make([]byte, encryption.ReferenceSize)
+			copy(ref, leaf.Bytes())
+			leaf = swarm.NewAddress(ref)
+		}
+		return fn(leaf)
+	})
+}
+
+func TestWorkingSet(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		storer  = mock.NewStorer()
+		now     = time.Now()
+		service = workingset.New(storer, statestore.NewStateStore(), traversal.New(storer), log.Noop)
+	)
+	t.Cleanup(func() { _ = service.Close() })
+	service.SetNow(func() time.Time { return now })
+
+	ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, storer, storage.ModePutUpload, false), bytes.NewReader(bytes.Repeat([]byte{1, 2}, swarm.ChunkSize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks []swarm.Address
+	if err := traversal.New(storer).Traverse(ctx, ref, func(addr swarm.Address) error {
+		chunks = append(chunks, addr)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// purge removes the chunks which are not exempt from the garbage collection
+	purge := func(t *testing.T, wantKept bool) {
+		t.Helper()
+
+		if err := storer.Set(ctx, storage.ModeSetPurge, chunks...); err != nil {
+			t.Fatal(err)
+		}
+		for _, addr := range chunks {
+			has, err := storer.Has(ctx, addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if has != wantKept {
+				t.Fatalf("chunk %s kept %t, want %t", addr, has, wantKept)
+			}
+		}
+	}
+
+	t.Run("invalid lease", func(t *testing.T) {
+		if _, err := service.Lease(ctx, nil, time.Hour); !errors.Is(err, workingset.ErrNoReferences) {
+			t.Fatalf("got error %v, want %v", err, workingset.ErrNoReferences)
+		}
+		if _, err := service.Lease(ctx, []swarm.Address{ref}, workingset.MaxTTL+time.Second); !errors.Is(err, workingset.ErrInvalidTTL) {
+			t.Fatalf("got error %v, want %v", err, workingset.ErrInvalidTTL)
+		}
+		missing := swarm.MustParseHexAddress("aabbcc00000000000000000000000000000000000000000000000000000000ff")
+		if _, err := service.Lease(ctx, []swarm.Address{ref, missing}, time.Hour); !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+		}
+		leases, err := service.Leases()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(leases) != 0 {
+			t.Fatalf("got %d leases, want none", len(leases))
+		}
+	})
+
+	t.Run("lease, renew and expire", func(t *testing.T) {
+		lease, err := service.Lease(ctx, []swarm.Address{ref}, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !lease.ExpiresAt.Equal(now.Add(time.Hour)) {
+			t.Fatalf("got expiry %v, want %v", lease.ExpiresAt, now.Add(time.Hour))
+		}
+		leases, err := service.Leases()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(leases) != 1 || leases[0].ID != lease.ID {
+			t.Fatalf("got leases %v, want %v", leases, lease)
+		}
+
+		renewed, err := service.Renew(lease.ID, 2*time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !renewed.ExpiresAt.Equal(now.Add(2 * time.Hour)) {
+			t.Fatalf("got expiry %v, want %v", renewed.ExpiresAt, now.Add(2*time.Hour))
+		}
+
+		service.SetNow(func() time.Time { return now.Add(time.Hour + time.Minute) })
+		if err := service.Expire(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.Get(lease.ID); err != nil {
+			t.Fatal(err)
+		}
+		purge(t, true)
+
+		service.SetNow(func() time.Time { return now.Add(2*time.Hour + time.Minute) })
+		if _, err := service.Get(lease.ID); !errors.Is(err, workingset.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, workingset.ErrNotFound)
+		}
+		if _, err := service.Renew(lease.ID, time.Hour); !errors.Is(err, workingset.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, workingset.ErrNotFound)
+		}
+		if err := service.Expire(ctx); err != nil {
+			t.Fatal(err)
+		}
+		purge(t, false)
+	})
+}
+
+func TestRelease(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		storer  = mock.NewStorer()
+		service = workingset.New(storer, statestore.NewStateStore(), traversal.New(storer), log.Noop)
+	)
+	t.Cleanup(func() { _ = service.Close() })
+
+	ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, storer, storage.ModePutUpload, false), bytes.NewReader([]byte("working set")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := service.Lease(ctx, []swarm.Address{ref}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.Lease(ctx, []swarm.Address{ref}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the chunk stays exempt while any of the leases holds it
+	for i, lease := range []workingset.Lease{first, second} {
+		if err := service.Release(ctx, lease.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := storer.Set(ctx, storage.ModeSetPurge, ref); err != nil {
+			t.Fatal(err)
+		}
+		has, err := storer.Has(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := i == 0; has != want {
+			t.Fatalf("release %d: chunk kept %t, want %t", i, has, want)
+		}
+	}
+
+	if err := service.Release(ctx, first.ID); !errors.Is(err, workingset.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, workingset.ErrNotFound)
+	}
+}
+
+func TestLeaseUnlocked(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		storer  = mock.NewStorer()
+		slow    = swarm.MustParseHexAddress("aabbcc00000000000000000000000000000000000000000000000000000000ff")
+		started = make(chan struct{})
+		unblock = make(chan struct{})
+	)
+	traverser := hookTraverser{
+		Traverser: traversal.New(storer),
+		hook: func(ref swarm.Address) error {
+			if ref.Equal(slow) {
+				close(started)
+				<-unblock
+				return storage.ErrNotFound
+			}
+			return nil
+		},
+	}
+	service := workingset.New(storer, statestore.NewStateStore(), traverser, log.Noop)
+	t.Cleanup(func() { _ = service.Close() })
+
+	ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, storer, storage.ModePutUpload, false), bytes.NewReader([]byte("working set")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lease, err := service.Lease(ctx, []swarm.Address{ref}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slowErr := make(chan error, 1)
+	go func() {
+		_, err := service.Lease(ctx, []swarm.Address{slow}, time.Hour)
+		slowErr <- err
+	}()
+	<-started
+
+	// the other leases are served while the slow lease is retrieving
+	if _, err := service.Renew(lease.ID, 2*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Leases(); err != nil {
+		t.Fatal(err)
+	}
+
+	close(unblock)
+	if err := <-slowErr; !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+func TestExpireFailed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		storer = mock.NewStorer()
+		now    = time.Now()
+		failed swarm.Address
+	)
+	traverser := hookTraverser{
+		Traverser: traversal.New(storer),
+		hook: func(ref swarm.Address) error {
+			if ref.Equal(failed) {
+				return errors.New("traversal failed")
+			}
+			return nil
+		},
+	}
+	service := workingset.New(storer, statestore.NewStateStore(), traverser, log.Noop)
+	t.Cleanup(func() { _ = service.Close() })
+	service.SetNow(func() time.Time { return now })
+
+	var refs []swarm.Address
+	for _, data := range []string{"first", "second"} {
+		ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, storer, storage.ModePutUpload, false), bytes.NewReader([]byte(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs = append(refs, ref)
+	}
+	for i, ref := range refs {
+		if _, err := service.Lease(ctx, []swarm.Address{ref}, time.Duration(i+1)*time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the release of the first lease fails, the second one is released still
+	failed = refs[0]
+	service.SetNow(func() time.Time { return now.Add(3 * time.Hour) })
+	if err := service.Expire(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := storer.Set(ctx, storage.ModeSetPurge, refs[1]); err != nil {
+		t.Fatal(err)
+	}
+	has, err := storer.Has(ctx, refs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has {
+		t.Fatal("chunk of the expired lease kept")
+	}
+}
+
+// TestLeaseEncrypted tests that the chunks of the encrypted content are
+// leased, and that they are unpinned when the lease fails.
+func TestLeaseEncrypted(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		storer  = encryptedStorer{mock.NewStorer()}
+		service = workingset.New(storer, statestore.NewStateStore(), encryptedTraverser{traversal.New(storer)}, log.Noop)
+		missing = swarm.MustParseHexAddress("aabbcc00000000000000000000000000000000000000000000000000000000ff")
+	)
+	t.Cleanup(func() { _ = service.Close() })
+
+	ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, storer, storage.ModePutUpload, true), bytes.NewReader(bytes.Repeat([]byte{1, 2}, swarm.ChunkSize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks []swarm.Address
+	if err := traversal.New(storer).Traverse(ctx, ref, func(addr swarm.Address) error {
+		chunks = append(chunks, swarm.NewAddress(addr.Bytes()[:swarm.HashSize]))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// pinned reports whether the chunks are exempt from the garbage collection
+	pinned := func(t *testing.T, want bool) {
+		t.Helper()
+
+		for _, addr := range chunks {
+			if got := storer.GetModeSet(addr); (got == storage.ModeSetPin) != want {
+				t.Fatalf("chunk %s last set with mode %v, want pinned %t", addr, got, want)
+			}
+		}
+	}
+
+	if _, err := service.Lease(ctx, []swarm.Address{ref, missing}, time.Hour); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+	pinned(t, false)
+
+	lease, err := service.Lease(ctx, []swarm.Address{ref}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinned(t, true)
+
+	if err := service.Release(ctx, lease.ID); err != nil {
+		t.Fatal(err)
+	}
+	pinned(t, false)
+}